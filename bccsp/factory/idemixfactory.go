@@ -0,0 +1,59 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package factory
+
+import (
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/idemix"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/pkg/errors"
+)
+
+const (
+	// IdemixFactoryName is the name of the factory of the Idemix BCCSP implementation
+	IdemixFactoryName = "IDEMIX"
+)
+
+// IdemixOpts contains options for the IdemixFactory
+type IdemixOpts struct {
+	// Keystore Options
+	Ephemeral    bool              `mapstructure:"tempkeys,omitempty" json:"tempkeys,omitempty"`
+	FileKeystore *FileKeystoreOpts `mapstructure:"filekeystore,omitempty" json:"filekeystore,omitempty" yaml:"FileKeyStore"`
+}
+
+// IdemixFactory is the factory of the Idemix BCCSP.
+type IdemixFactory struct{}
+
+// Name returns the name of this factory
+func (f *IdemixFactory) Name() string {
+	return IdemixFactoryName
+}
+
+// Get returns an instance of BCCSP using Opts.
+func (f *IdemixFactory) Get(config *FactoryOpts) (bccsp.BCCSP, error) {
+	// Validate arguments
+	if config == nil || config.IdemixOpts == nil {
+		return nil, errors.New("Invalid config. It must not be nil.")
+	}
+
+	idemixOpts := config.IdemixOpts
+
+	var ks bccsp.KeyStore
+	if idemixOpts.Ephemeral {
+		ks = sw.NewDummyKeyStore()
+	} else if idemixOpts.FileKeystore != nil {
+		fks, err := sw.NewFileBasedKeyStore(nil, idemixOpts.FileKeystore.KeyStorePath, false)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to initialize software key store")
+		}
+		ks = fks
+	} else {
+		// Default to DummyKeystore
+		ks = sw.NewDummyKeyStore()
+	}
+
+	return idemix.New(ks)
+}
@@ -0,0 +1,66 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package factory
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdemixFactoryName(t *testing.T) {
+	f := &IdemixFactory{}
+	assert.Equal(t, f.Name(), IdemixFactoryName)
+}
+
+func TestIdemixFactoryGetInvalidArgs(t *testing.T) {
+	f := &IdemixFactory{}
+
+	_, err := f.Get(nil)
+	assert.Error(t, err, "Invalid config. It must not be nil.")
+
+	_, err = f.Get(&FactoryOpts{})
+	assert.Error(t, err, "Invalid config. It must not be nil.")
+}
+
+func TestIdemixFactoryGet(t *testing.T) {
+	f := &IdemixFactory{}
+
+	opts := &FactoryOpts{
+		IdemixOpts: &IdemixOpts{Ephemeral: true},
+	}
+	csp, err := f.Get(opts)
+	assert.NoError(t, err)
+	assert.NotNil(t, csp)
+
+	// the returned BCCSP supports the Idemix primitives
+	isk, err := csp.KeyGen(&bccsp.IdemixIssuerKeyGenOpts{Temporary: true, AttributeNames: []string{"A"}})
+	assert.NoError(t, err)
+	assert.NotNil(t, isk)
+
+	opts = &FactoryOpts{
+		IdemixOpts: &IdemixOpts{
+			FileKeystore: &FileKeystoreOpts{KeyStorePath: os.TempDir()},
+		},
+	}
+	csp, err = f.Get(opts)
+	assert.NoError(t, err)
+	assert.NotNil(t, csp)
+}
+
+func TestGetBCCSPFromOptsIdemix(t *testing.T) {
+	csp, err := GetBCCSPFromOpts(&FactoryOpts{
+		ProviderName: IdemixFactoryName,
+		IdemixOpts:   &IdemixOpts{Ephemeral: true},
+	})
+	assert.NoError(t, err)
+	assert.NotNil(t, csp)
+
+	_, err = GetBCCSPFromOpts(&FactoryOpts{ProviderName: IdemixFactoryName})
+	assert.Error(t, err)
+}
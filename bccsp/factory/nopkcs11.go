@@ -27,6 +27,7 @@ type FactoryOpts struct {
 	ProviderName string      `mapstructure:"default" json:"default" yaml:"Default"`
 	SwOpts       *SwOpts     `mapstructure:"SW,omitempty" json:"SW,omitempty" yaml:"SwOpts"`
 	PluginOpts   *PluginOpts `mapstructure:"PLUGIN,omitempty" json:"PLUGIN,omitempty" yaml:"PluginOpts"`
+	IdemixOpts   *IdemixOpts `mapstructure:"IDEMIX,omitempty" json:"IDEMIX,omitempty" yaml:"IdemixOpts"`
 }
 
 // InitFactories must be called before using factory interfaces
@@ -87,6 +88,8 @@ func GetBCCSPFromOpts(config *FactoryOpts) (bccsp.BCCSP, error) {
 		f = &SWFactory{}
 	case "PLUGIN":
 		f = &PluginFactory{}
+	case "IDEMIX":
+		f = &IdemixFactory{}
 	default:
 		return nil, errors.Errorf("Could not find BCCSP, no '%s' provider", config.ProviderName)
 	}
@@ -0,0 +1,125 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package idemix
+
+import (
+	"reflect"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/pkg/errors"
+)
+
+// csp is a BCCSP implementing the Idemix primitives.
+// Key generation, derivation and import are delegated to the wrappers
+// registered in the embedded sw.CSP. Signing and verification are
+// dispatched here, because Idemix signatures are not computed over a digest:
+// depending on the opts, the digest can be empty (e.g. for credential requests).
+type csp struct {
+	*sw.CSP
+
+	signers   map[reflect.Type]sw.Signer
+	verifiers map[reflect.Type]sw.Verifier
+}
+
+// New returns a new instance of the Idemix BCCSP.
+// Keys that are not ephemeral are stored in the passed key store.
+func New(keyStore bccsp.KeyStore) (bccsp.BCCSP, error) {
+	base, err := sw.New(keyStore)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed instantiating base bccsp")
+	}
+
+	csp := &csp{
+		CSP:       base,
+		signers:   make(map[reflect.Type]sw.Signer),
+		verifiers: make(map[reflect.Type]sw.Verifier),
+	}
+
+	// key generators
+	base.AddWrapper(reflect.TypeOf(&bccsp.IdemixIssuerKeyGenOpts{}), &issuerKeyGen{})
+	base.AddWrapper(reflect.TypeOf(&bccsp.IdemixUserSecretKeyGenOpts{}), &userSecretKeyGen{})
+	base.AddWrapper(reflect.TypeOf(&bccsp.IdemixRevocationKeyGenOpts{}), &revocationKeyGen{})
+
+	// key derivers
+	base.AddWrapper(reflect.TypeOf(&userSecretKey{}), &nymKeyDerivation{})
+
+	// key importers
+	base.AddWrapper(reflect.TypeOf(&bccsp.IdemixIssuerPublicKeyImportOpts{}), &issuerPublicKeyImporter{})
+	base.AddWrapper(reflect.TypeOf(&bccsp.IdemixUserSecretKeyImportOpts{}), &userSecretKeyImporter{})
+	base.AddWrapper(reflect.TypeOf(&bccsp.IdemixNymPublicKeyImportOpts{}), &nymPublicKeyImporter{})
+	base.AddWrapper(reflect.TypeOf(&bccsp.IdemixRevocationPublicKeyImportOpts{}), &revocationPublicKeyImporter{})
+
+	// signers
+	csp.signers[reflect.TypeOf(&userSecretKey{})] = &userSigner{}
+	csp.signers[reflect.TypeOf(&issuerSecretKey{})] = &credentialSigner{}
+	csp.signers[reflect.TypeOf(&revocationSecretKey{})] = &criSigner{}
+
+	// verifiers
+	csp.verifiers[reflect.TypeOf(&issuerPublicKey{})] = &issuerVerifier{}
+	csp.verifiers[reflect.TypeOf(&userSecretKey{})] = &credentialVerifier{}
+	csp.verifiers[reflect.TypeOf(&nymPublicKey{})] = &nymSignatureVerifier{}
+	csp.verifiers[reflect.TypeOf(&revocationPublicKey{})] = &criVerifier{}
+
+	return csp, nil
+}
+
+// Sign signs digest using key k.
+// The opts argument determines which Idemix object is produced:
+// a credential request, a credential, a signature, a pseudonym signature
+// or a credential revocation information.
+// Notice that the digest might be empty.
+func (csp *csp) Sign(k bccsp.Key, digest []byte, opts bccsp.SignerOpts) (signature []byte, err error) {
+	// Validate arguments
+	if k == nil {
+		return nil, errors.New("Invalid Key. It must not be nil.")
+	}
+	if opts == nil {
+		return nil, errors.New("Invalid opts. It must not be nil.")
+	}
+
+	keyType := reflect.TypeOf(k)
+	signer, found := csp.signers[keyType]
+	if !found {
+		return nil, errors.Errorf("Unsupported 'SignKey' provided [%s]", keyType)
+	}
+
+	signature, err = signer.Sign(k, digest, opts)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed signing")
+	}
+
+	return
+}
+
+// Verify verifies signature against key k and digest.
+// Notice that the digest might be empty.
+func (csp *csp) Verify(k bccsp.Key, signature, digest []byte, opts bccsp.SignerOpts) (valid bool, err error) {
+	// Validate arguments
+	if k == nil {
+		return false, errors.New("Invalid Key. It must not be nil.")
+	}
+	if len(signature) == 0 {
+		return false, errors.New("Invalid signature. Cannot be empty.")
+	}
+	if opts == nil {
+		return false, errors.New("Invalid opts. It must not be nil.")
+	}
+
+	keyType := reflect.TypeOf(k)
+	verifier, found := csp.verifiers[keyType]
+	if !found {
+		return false, errors.Errorf("Unsupported 'VerifyKey' provided [%s]", keyType)
+	}
+
+	valid, err = verifier.Verify(k, signature, digest, opts)
+	if err != nil {
+		return false, errors.WithMessage(err, "failed verifying")
+	}
+
+	return
+}
@@ -0,0 +1,324 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package idemix
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	cryptolib "github.com/hyperledger/fabric/idemix"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var attributeNames = []string{"OU", "Role", "EnrollmentID", "RevocationHandle"}
+
+func newIssuer(t *testing.T) (bccsp.BCCSP, bccsp.Key, bccsp.Key) {
+	csp, err := New(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+
+	issuerKey, err := csp.KeyGen(&bccsp.IdemixIssuerKeyGenOpts{Temporary: true, AttributeNames: attributeNames})
+	require.NoError(t, err)
+	assert.True(t, issuerKey.Private())
+	issuerPk, err := issuerKey.PublicKey()
+	require.NoError(t, err)
+	assert.False(t, issuerPk.Private())
+	assert.Equal(t, issuerKey.SKI(), issuerPk.SKI())
+
+	return csp, issuerKey, issuerPk
+}
+
+func credentialAttributes(rh int) []bccsp.IdemixAttribute {
+	return []bccsp.IdemixAttribute{
+		{Type: bccsp.IdemixBytesAttribute, Value: []byte("ou")},
+		{Type: bccsp.IdemixIntAttribute, Value: 1},
+		{Type: bccsp.IdemixBytesAttribute, Value: []byte("alice")},
+		{Type: bccsp.IdemixIntAttribute, Value: rh},
+	}
+}
+
+// issueCredential runs the issuance protocol for a fresh user secret key
+func issueCredential(t *testing.T, csp bccsp.BCCSP, issuerKey, issuerPk bccsp.Key, rh int) (bccsp.Key, []byte) {
+	userKey, err := csp.KeyGen(&bccsp.IdemixUserSecretKeyGenOpts{Temporary: true})
+	require.NoError(t, err)
+
+	nonce := make([]byte, cryptolib.FieldBytes)
+	nonce[0] = 1
+	credRequest, err := csp.Sign(userKey, nil, &bccsp.IdemixCredentialRequestSignerOpts{IssuerPK: issuerPk, IssuerNonce: nonce})
+	require.NoError(t, err)
+
+	valid, err := csp.Verify(issuerPk, credRequest, nil, &bccsp.IdemixCredentialRequestSignerOpts{IssuerNonce: nonce})
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	cred, err := csp.Sign(issuerKey, credRequest, &bccsp.IdemixCredentialSignerOpts{Attributes: credentialAttributes(rh)})
+	require.NoError(t, err)
+
+	return userKey, cred
+}
+
+func TestIssuerKeyImport(t *testing.T) {
+	csp, _, issuerPk := newIssuer(t)
+
+	raw, err := issuerPk.Bytes()
+	require.NoError(t, err)
+
+	imported, err := csp.KeyImport(raw, &bccsp.IdemixIssuerPublicKeyImportOpts{Temporary: true, AttributeNames: attributeNames})
+	assert.NoError(t, err)
+	assert.Equal(t, issuerPk.SKI(), imported.SKI())
+
+	_, err = csp.KeyImport(raw, &bccsp.IdemixIssuerPublicKeyImportOpts{Temporary: true, AttributeNames: []string{"A", "B", "C", "D"}})
+	assert.EqualError(t, err, "Failed importing key with opts [&{true [A B C D]}]: invalid attribute name at position [0], expected [A], got [OU]")
+
+	_, err = csp.KeyImport([]byte{1, 2, 3}, &bccsp.IdemixIssuerPublicKeyImportOpts{Temporary: true})
+	assert.Error(t, err)
+
+	_, err = csp.KeyImport("not bytes", &bccsp.IdemixIssuerPublicKeyImportOpts{Temporary: true})
+	assert.Contains(t, err.Error(), "invalid raw, expected byte array")
+}
+
+func TestCredentialIssuance(t *testing.T) {
+	csp, issuerKey, issuerPk := newIssuer(t)
+	userKey, cred := issueCredential(t, csp, issuerKey, issuerPk, 42)
+
+	valid, err := csp.Verify(userKey, cred, nil, &bccsp.IdemixCredentialSignerOpts{IssuerPK: issuerPk, Attributes: credentialAttributes(42)})
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	// the role and the revocation handle are not checked if hidden
+	attrs := credentialAttributes(0)
+	attrs[1] = bccsp.IdemixAttribute{Type: bccsp.IdemixHiddenAttribute}
+	attrs[3] = bccsp.IdemixAttribute{Type: bccsp.IdemixHiddenAttribute}
+	valid, err = csp.Verify(userKey, cred, nil, &bccsp.IdemixCredentialSignerOpts{IssuerPK: issuerPk, Attributes: attrs})
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	attrs[0] = bccsp.IdemixAttribute{Type: bccsp.IdemixBytesAttribute, Value: []byte("another ou")}
+	_, err = csp.Verify(userKey, cred, nil, &bccsp.IdemixCredentialSignerOpts{IssuerPK: issuerPk, Attributes: attrs})
+	assert.Contains(t, err.Error(), "credential does not contain the correct value for attribute OU")
+
+	// a credential does not verify with another user secret key
+	otherKey, err := csp.KeyGen(&bccsp.IdemixUserSecretKeyGenOpts{Temporary: true})
+	require.NoError(t, err)
+	valid, err = csp.Verify(otherKey, cred, nil, &bccsp.IdemixCredentialSignerOpts{IssuerPK: issuerPk})
+	assert.Error(t, err)
+	assert.False(t, valid)
+
+	// the user secret key can be exported and imported
+	raw, err := userKey.Bytes()
+	require.NoError(t, err)
+	imported, err := csp.KeyImport(raw, &bccsp.IdemixUserSecretKeyImportOpts{Temporary: true})
+	require.NoError(t, err)
+	assert.Equal(t, userKey.SKI(), imported.SKI())
+	valid, err = csp.Verify(imported, cred, nil, &bccsp.IdemixCredentialSignerOpts{IssuerPK: issuerPk})
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	// hidden attributes cannot be issued
+	attrs = credentialAttributes(0)
+	attrs[2] = bccsp.IdemixAttribute{Type: bccsp.IdemixHiddenAttribute}
+	nonce := make([]byte, cryptolib.FieldBytes)
+	credRequest, err := csp.Sign(userKey, nil, &bccsp.IdemixCredentialRequestSignerOpts{IssuerPK: issuerPk, IssuerNonce: nonce})
+	require.NoError(t, err)
+	_, err = csp.Sign(issuerKey, credRequest, &bccsp.IdemixCredentialSignerOpts{Attributes: attrs})
+	assert.Contains(t, err.Error(), "attribute type 0 not allowed")
+
+	// a credential request is bound to the nonce of the issuer
+	_, err = csp.Verify(issuerPk, credRequest, nil, &bccsp.IdemixCredentialRequestSignerOpts{IssuerNonce: []byte("another nonce")})
+	assert.Contains(t, err.Error(), "the issuer nonce does not match")
+	_, err = csp.Sign(userKey, nil, &bccsp.IdemixCredentialRequestSignerOpts{IssuerPK: issuerPk})
+	assert.Contains(t, err.Error(), "invalid issuer nonce")
+}
+
+func TestSignatures(t *testing.T) {
+	csp, issuerKey, issuerPk := newIssuer(t)
+	userKey, cred := issueCredential(t, csp, issuerKey, issuerPk, 42)
+
+	nym, err := csp.KeyDeriv(userKey, &bccsp.IdemixNymKeyDerivationOpts{Temporary: true, IssuerPK: issuerPk})
+	require.NoError(t, err)
+	nymPk, err := nym.PublicKey()
+	require.NoError(t, err)
+
+	// the public part of a pseudonym can be imported from its byte representation
+	raw, err := nymPk.Bytes()
+	require.NoError(t, err)
+	nymPk, err = csp.KeyImport(raw, &bccsp.IdemixNymPublicKeyImportOpts{Temporary: true})
+	require.NoError(t, err)
+	assert.Equal(t, nym.SKI(), nymPk.SKI())
+
+	msg := []byte("hello world")
+
+	// signature disclosing the OU and the role
+	attrs := credentialAttributes(42)
+	attrs[2] = bccsp.IdemixAttribute{Type: bccsp.IdemixHiddenAttribute}
+	attrs[3] = bccsp.IdemixAttribute{Type: bccsp.IdemixHiddenAttribute}
+	signerOpts := &bccsp.IdemixSignerOpts{
+		Nym:        nym,
+		IssuerPK:   issuerPk,
+		Credential: cred,
+		Attributes: attrs,
+		RhIndex:    3,
+	}
+	sig, err := csp.Sign(userKey, msg, signerOpts)
+	require.NoError(t, err)
+
+	valid, err := csp.Verify(issuerPk, sig, msg, signerOpts)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	_, err = csp.Verify(issuerPk, sig, []byte("another message"), signerOpts)
+	assert.Error(t, err)
+
+	attrs[0] = bccsp.IdemixAttribute{Type: bccsp.IdemixBytesAttribute, Value: []byte("another ou")}
+	_, err = csp.Verify(issuerPk, sig, msg, signerOpts)
+	assert.Error(t, err)
+
+	// pseudonym signatures
+	nymOpts := &bccsp.IdemixNymSignerOpts{Nym: nym, IssuerPK: issuerPk}
+	nymSig, err := csp.Sign(userKey, msg, nymOpts)
+	require.NoError(t, err)
+
+	valid, err = csp.Verify(nymPk, nymSig, msg, nymOpts)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	_, err = csp.Verify(nymPk, nymSig, []byte("another message"), nymOpts)
+	assert.Error(t, err)
+
+	_, err = csp.Sign(userKey, msg, &bccsp.IdemixCRISignerOpts{})
+	assert.Contains(t, err.Error(), "invalid options, unsupported type")
+	_, err = csp.Verify(nymPk, nil, msg, nymOpts)
+	assert.EqualError(t, err, "Invalid signature. Cannot be empty.")
+}
+
+func TestMembershipSignatures(t *testing.T) {
+	csp, issuerKey, issuerPk := newIssuer(t)
+	userKey, cred := issueCredential(t, csp, issuerKey, issuerPk, 42)
+	nym, err := csp.KeyDeriv(userKey, &bccsp.IdemixNymKeyDerivationOpts{Temporary: true, IssuerPK: issuerPk})
+	require.NoError(t, err)
+
+	msg := []byte("hello world")
+
+	// signature proving that the hidden enrollment id is one of alice and bob
+	attrs := credentialAttributes(42)
+	attrs[2] = bccsp.IdemixAttribute{
+		Type: bccsp.IdemixMembershipAttribute,
+		Value: []bccsp.IdemixAttribute{
+			{Type: bccsp.IdemixBytesAttribute, Value: []byte("bob")},
+			{Type: bccsp.IdemixBytesAttribute, Value: []byte("alice")},
+		},
+	}
+	attrs[3] = bccsp.IdemixAttribute{Type: bccsp.IdemixHiddenAttribute}
+	signerOpts := &bccsp.IdemixSignerOpts{
+		Nym:        nym,
+		IssuerPK:   issuerPk,
+		Credential: cred,
+		Attributes: attrs,
+		RhIndex:    3,
+	}
+	sig, err := csp.Sign(userKey, msg, signerOpts)
+	require.NoError(t, err)
+
+	valid, err := csp.Verify(issuerPk, sig, msg, signerOpts)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	// the verifier must expect the same set
+	attrs[2] = bccsp.IdemixAttribute{
+		Type:  bccsp.IdemixMembershipAttribute,
+		Value: []bccsp.IdemixAttribute{{Type: bccsp.IdemixBytesAttribute, Value: []byte("alice")}},
+	}
+	_, err = csp.Verify(issuerPk, sig, msg, signerOpts)
+	assert.Error(t, err)
+	attrs[2] = bccsp.IdemixAttribute{Type: bccsp.IdemixHiddenAttribute}
+	_, err = csp.Verify(issuerPk, sig, msg, signerOpts)
+	assert.Error(t, err)
+
+	// the hidden attribute must be in the set
+	attrs[2] = bccsp.IdemixAttribute{
+		Type:  bccsp.IdemixMembershipAttribute,
+		Value: []bccsp.IdemixAttribute{{Type: bccsp.IdemixBytesAttribute, Value: []byte("bob")}},
+	}
+	_, err = csp.Sign(userKey, msg, signerOpts)
+	assert.Error(t, err)
+
+	attrs[2] = bccsp.IdemixAttribute{Type: bccsp.IdemixMembershipAttribute, Value: []byte("alice")}
+	_, err = csp.Sign(userKey, msg, signerOpts)
+	assert.EqualError(t, err, "failed signing: invalid attribute at position [2], expected []bccsp.IdemixAttribute as membership attribute value")
+	attrs[2] = bccsp.IdemixAttribute{Type: bccsp.IdemixMembershipAttribute, Value: []bccsp.IdemixAttribute{}}
+	_, err = csp.Sign(userKey, msg, signerOpts)
+	assert.EqualError(t, err, "failed signing: invalid attribute at position [2], the set of values must not be empty")
+}
+
+func TestRevocation(t *testing.T) {
+	csp, issuerKey, issuerPk := newIssuer(t)
+	userKey, cred := issueCredential(t, csp, issuerKey, issuerPk, 42)
+	nym, err := csp.KeyDeriv(userKey, &bccsp.IdemixNymKeyDerivationOpts{Temporary: true, IssuerPK: issuerPk})
+	require.NoError(t, err)
+
+	revocationKey, err := csp.KeyGen(&bccsp.IdemixRevocationKeyGenOpts{Temporary: true})
+	require.NoError(t, err)
+	revocationPk, err := revocationKey.PublicKey()
+	require.NoError(t, err)
+
+	// the revocation public key is imported from its PEM encoding
+	raw, err := revocationPk.Bytes()
+	require.NoError(t, err)
+	revocationPk, err = csp.KeyImport(raw, &bccsp.IdemixRevocationPublicKeyImportOpts{Temporary: true})
+	require.NoError(t, err)
+	assert.Equal(t, revocationKey.SKI(), revocationPk.SKI())
+
+	unrevoked := cryptolib.BigToBytes(cryptolib.HashModOrder(nil))
+	rh := make([]byte, cryptolib.FieldBytes)
+	rh[len(rh)-1] = 42
+
+	criOpts := &bccsp.IdemixCRISignerOpts{Epoch: 1, RevocationAlgorithm: bccsp.AlgWeakBB, UnrevokedHandles: [][]byte{unrevoked, rh}}
+	cri, err := csp.Sign(revocationKey, nil, criOpts)
+	require.NoError(t, err)
+
+	valid, err := csp.Verify(revocationPk, cri, nil, criOpts)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+	_, err = csp.Verify(revocationPk, cri, nil, &bccsp.IdemixCRISignerOpts{Epoch: 2, RevocationAlgorithm: bccsp.AlgWeakBB})
+	assert.EqualError(t, err, "failed verifying: credential revocation information is for epoch 1, expected 2")
+
+	attrs := credentialAttributes(42)
+	for i := 1; i < len(attrs); i++ {
+		attrs[i] = bccsp.IdemixAttribute{Type: bccsp.IdemixHiddenAttribute}
+	}
+	signerOpts := &bccsp.IdemixSignerOpts{
+		Nym:        nym,
+		IssuerPK:   issuerPk,
+		Credential: cred,
+		Attributes: attrs,
+		RhIndex:    3,
+		CRI:        cri,
+	}
+	sig, err := csp.Sign(userKey, nil, signerOpts)
+	require.NoError(t, err)
+	valid, err = csp.Verify(issuerPk, sig, nil, signerOpts)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	// the signature does not verify against the CRI of another epoch
+	criOpts = &bccsp.IdemixCRISignerOpts{Epoch: 2, RevocationAlgorithm: bccsp.AlgWeakBB, UnrevokedHandles: [][]byte{unrevoked}}
+	cri2, err := csp.Sign(revocationKey, nil, criOpts)
+	require.NoError(t, err)
+	signerOpts.CRI = cri2
+	_, err = csp.Verify(issuerPk, sig, nil, signerOpts)
+	assert.Contains(t, err.Error(), "signature is for epoch 1, but the current epoch is 2")
+
+	// the credential is revoked in epoch 2
+	_, err = csp.Sign(userKey, nil, signerOpts)
+	assert.Contains(t, err.Error(), "the revocation handle is revoked in epoch 2")
+
+	c := &cryptolib.CredentialRevocationInformation{}
+	require.NoError(t, proto.Unmarshal(cri2, c))
+	assert.Len(t, c.NonRevokedHandles, 1)
+}
@@ -0,0 +1,148 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package idemix
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/bccsp"
+	cryptolib "github.com/hyperledger/fabric/idemix"
+	"github.com/pkg/errors"
+)
+
+// issuerSecretKey contains the issuer secret key
+// and implements the bccsp.Key interface
+type issuerSecretKey struct {
+	sk *cryptolib.IssuerKey
+}
+
+// Bytes converts this key to its byte representation,
+// if this operation is allowed.
+func (k *issuerSecretKey) Bytes() ([]byte, error) {
+	return proto.Marshal(k.sk)
+}
+
+// SKI returns the subject key identifier of this key.
+func (k *issuerSecretKey) SKI() []byte {
+	return k.sk.IPk.Hash
+}
+
+// Symmetric returns true if this key is a symmetric key,
+// false if this key is asymmetric
+func (k *issuerSecretKey) Symmetric() bool {
+	return false
+}
+
+// Private returns true if this key is a private key,
+// false otherwise.
+func (k *issuerSecretKey) Private() bool {
+	return true
+}
+
+// PublicKey returns the corresponding public key part of an asymmetric public/private key pair.
+func (k *issuerSecretKey) PublicKey() (bccsp.Key, error) {
+	return &issuerPublicKey{k.sk.IPk}, nil
+}
+
+// issuerPublicKey contains the issuer public key
+// and implements the bccsp.Key interface
+type issuerPublicKey struct {
+	pk *cryptolib.IssuerPublicKey
+}
+
+// Bytes converts this key to its byte representation,
+// if this operation is allowed.
+func (k *issuerPublicKey) Bytes() ([]byte, error) {
+	return proto.Marshal(k.pk)
+}
+
+// SKI returns the subject key identifier of this key.
+func (k *issuerPublicKey) SKI() []byte {
+	return k.pk.Hash
+}
+
+// Symmetric returns true if this key is a symmetric key,
+// false if this key is asymmetric
+func (k *issuerPublicKey) Symmetric() bool {
+	return false
+}
+
+// Private returns true if this key is a private key,
+// false otherwise.
+func (k *issuerPublicKey) Private() bool {
+	return false
+}
+
+// PublicKey returns the corresponding public key part of an asymmetric public/private key pair.
+func (k *issuerPublicKey) PublicKey() (bccsp.Key, error) {
+	return k, nil
+}
+
+// issuerKeyGen generates issuer key pairs
+type issuerKeyGen struct{}
+
+func (*issuerKeyGen) KeyGen(opts bccsp.KeyGenOpts) (bccsp.Key, error) {
+	o, ok := opts.(*bccsp.IdemixIssuerKeyGenOpts)
+	if !ok {
+		return nil, errors.New("invalid options, expected *bccsp.IdemixIssuerKeyGenOpts")
+	}
+
+	rng, err := cryptolib.GetRand()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed initializing PRNG")
+	}
+
+	sk, err := cryptolib.NewIssuerKey(o.AttributeNames, rng)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed generating issuer key")
+	}
+
+	return &issuerSecretKey{sk}, nil
+}
+
+// issuerPublicKeyImporter imports serialized issuer public keys
+type issuerPublicKeyImporter struct{}
+
+func (*issuerPublicKeyImporter) KeyImport(raw interface{}, opts bccsp.KeyImportOpts) (bccsp.Key, error) {
+	der, ok := raw.([]byte)
+	if !ok {
+		return nil, errors.New("invalid raw, expected byte array")
+	}
+	if len(der) == 0 {
+		return nil, errors.New("invalid raw, it must not be nil")
+	}
+	o, ok := opts.(*bccsp.IdemixIssuerPublicKeyImportOpts)
+	if !ok {
+		return nil, errors.New("invalid options, expected *bccsp.IdemixIssuerPublicKeyImportOpts")
+	}
+
+	pk := &cryptolib.IssuerPublicKey{}
+	err := proto.Unmarshal(der, pk)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal issuer public key")
+	}
+	err = pk.SetHash()
+	if err != nil {
+		return nil, errors.WithMessage(err, "setting the hash of the issuer public key failed")
+	}
+	err = pk.Check()
+	if err != nil {
+		return nil, errors.WithMessage(err, "invalid issuer public key")
+	}
+
+	if len(o.AttributeNames) != 0 {
+		if len(pk.AttributeNames) != len(o.AttributeNames) {
+			return nil, errors.Errorf("invalid number of attributes, expected [%d], got [%d]", len(o.AttributeNames), len(pk.AttributeNames))
+		}
+		for i, name := range o.AttributeNames {
+			if pk.AttributeNames[i] != name {
+				return nil, errors.Errorf("invalid attribute name at position [%d], expected [%s], got [%s]", i, name, pk.AttributeNames[i])
+			}
+		}
+	}
+
+	return &issuerPublicKey{pk}, nil
+}
@@ -0,0 +1,135 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package idemix
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+
+	"github.com/hyperledger/fabric/bccsp"
+	cryptolib "github.com/hyperledger/fabric/idemix"
+	"github.com/pkg/errors"
+)
+
+// revocationSecretKey contains the long term key of the revocation authority
+// and implements the bccsp.Key interface
+type revocationSecretKey struct {
+	sk *ecdsa.PrivateKey
+}
+
+// Bytes converts this key to its byte representation,
+// if this operation is allowed.
+func (k *revocationSecretKey) Bytes() ([]byte, error) {
+	return nil, errors.New("not supported")
+}
+
+// SKI returns the subject key identifier of this key.
+func (k *revocationSecretKey) SKI() []byte {
+	return revocationSKI(&k.sk.PublicKey)
+}
+
+// Symmetric returns true if this key is a symmetric key,
+// false if this key is asymmetric
+func (k *revocationSecretKey) Symmetric() bool {
+	return false
+}
+
+// Private returns true if this key is a private key,
+// false otherwise.
+func (k *revocationSecretKey) Private() bool {
+	return true
+}
+
+// PublicKey returns the corresponding public key part of an asymmetric public/private key pair.
+func (k *revocationSecretKey) PublicKey() (bccsp.Key, error) {
+	return &revocationPublicKey{&k.sk.PublicKey}, nil
+}
+
+// revocationPublicKey contains the long term public key of the revocation authority
+// and implements the bccsp.Key interface
+type revocationPublicKey struct {
+	pk *ecdsa.PublicKey
+}
+
+// Bytes returns the PEM encoding of the PKIX representation of this key
+func (k *revocationPublicKey) Bytes() ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(k.pk)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed marshalling revocation public key")
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// SKI returns the subject key identifier of this key.
+func (k *revocationPublicKey) SKI() []byte {
+	return revocationSKI(k.pk)
+}
+
+// Symmetric returns true if this key is a symmetric key,
+// false if this key is asymmetric
+func (k *revocationPublicKey) Symmetric() bool {
+	return false
+}
+
+// Private returns true if this key is a private key,
+// false otherwise.
+func (k *revocationPublicKey) Private() bool {
+	return false
+}
+
+// PublicKey returns the corresponding public key part of an asymmetric public/private key pair.
+func (k *revocationPublicKey) PublicKey() (bccsp.Key, error) {
+	return k, nil
+}
+
+func revocationSKI(pk *ecdsa.PublicKey) []byte {
+	hash := sha256.Sum256(elliptic.Marshal(pk.Curve, pk.X, pk.Y))
+	return hash[:]
+}
+
+// revocationKeyGen generates long term revocation keys
+type revocationKeyGen struct{}
+
+func (*revocationKeyGen) KeyGen(opts bccsp.KeyGenOpts) (bccsp.Key, error) {
+	sk, err := cryptolib.GenerateLongTermRevocationKey()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed generating revocation key")
+	}
+
+	return &revocationSecretKey{sk}, nil
+}
+
+// revocationPublicKeyImporter imports PEM encoded revocation public keys
+type revocationPublicKeyImporter struct{}
+
+func (*revocationPublicKeyImporter) KeyImport(raw interface{}, opts bccsp.KeyImportOpts) (bccsp.Key, error) {
+	der, ok := raw.([]byte)
+	if !ok {
+		return nil, errors.New("invalid raw, expected byte array")
+	}
+	if len(der) == 0 {
+		return nil, errors.New("invalid raw, it must not be nil")
+	}
+
+	block, _ := pem.Decode(der)
+	if block == nil {
+		return nil, errors.New("failed to decode revocation public key: no pem content")
+	}
+	pk, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse revocation public key")
+	}
+	ecdsaPk, ok := pk.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.Errorf("revocation public key is of type %T, expected an ECDSA key", pk)
+	}
+
+	return &revocationPublicKey{ecdsaPk}, nil
+}
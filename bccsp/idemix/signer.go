@@ -0,0 +1,425 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package idemix
+
+import (
+	"bytes"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-amcl/amcl/FP256BN"
+	"github.com/hyperledger/fabric/bccsp"
+	cryptolib "github.com/hyperledger/fabric/idemix"
+	"github.com/pkg/errors"
+)
+
+// userSigner produces, with a user secret key, credential requests,
+// signatures and pseudonym signatures, depending on the opts
+type userSigner struct{}
+
+func (*userSigner) Sign(k bccsp.Key, digest []byte, opts bccsp.SignerOpts) ([]byte, error) {
+	userKey, ok := k.(*userSecretKey)
+	if !ok {
+		return nil, errors.New("invalid key, expected *userSecretKey")
+	}
+
+	rng, err := cryptolib.GetRand()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed initializing PRNG")
+	}
+
+	switch o := opts.(type) {
+	case *bccsp.IdemixCredentialRequestSignerOpts:
+		ipk, err := toIssuerPublicKey(o.IssuerPK)
+		if err != nil {
+			return nil, err
+		}
+		if len(o.IssuerNonce) != cryptolib.FieldBytes {
+			return nil, errors.Errorf("invalid issuer nonce, expected length %d, got %d", cryptolib.FieldBytes, len(o.IssuerNonce))
+		}
+
+		credRequest := cryptolib.NewCredRequest(userKey.sk, FP256BN.FromBytes(o.IssuerNonce), ipk, rng)
+		return proto.Marshal(credRequest)
+
+	case *bccsp.IdemixSignerOpts:
+		ipk, err := toIssuerPublicKey(o.IssuerPK)
+		if err != nil {
+			return nil, err
+		}
+		nym, ok := o.Nym.(*nymSecretKey)
+		if !ok {
+			return nil, errors.New("invalid options, expected Nym as *nymSecretKey")
+		}
+		cred := &cryptolib.Credential{}
+		err = proto.Unmarshal(o.Credential, cred)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed unmarshalling credential")
+		}
+		cri, err := unmarshalCRI(o.CRI)
+		if err != nil {
+			return nil, err
+		}
+
+		disclosure, memberships, err := proofSpec(o.Attributes)
+		if err != nil {
+			return nil, err
+		}
+
+		sig, err := cryptolib.NewSignature(cred, userKey.sk, nym.nym, nym.rNym, ipk, disclosure, digest, o.RhIndex, cri, memberships, rng)
+		if err != nil {
+			return nil, err
+		}
+		return proto.Marshal(sig)
+
+	case *bccsp.IdemixNymSignerOpts:
+		ipk, err := toIssuerPublicKey(o.IssuerPK)
+		if err != nil {
+			return nil, err
+		}
+		nym, ok := o.Nym.(*nymSecretKey)
+		if !ok {
+			return nil, errors.New("invalid options, expected Nym as *nymSecretKey")
+		}
+
+		sig, err := cryptolib.NewNymSignature(userKey.sk, nym.nym, nym.rNym, ipk, digest, rng)
+		if err != nil {
+			return nil, err
+		}
+		return proto.Marshal(sig)
+
+	default:
+		return nil, errors.Errorf("invalid options, unsupported type %T", opts)
+	}
+}
+
+// credentialSigner issues, with an issuer secret key, a credential
+// on the credential request passed as digest
+type credentialSigner struct{}
+
+func (*credentialSigner) Sign(k bccsp.Key, digest []byte, opts bccsp.SignerOpts) ([]byte, error) {
+	issuerKey, ok := k.(*issuerSecretKey)
+	if !ok {
+		return nil, errors.New("invalid key, expected *issuerSecretKey")
+	}
+	o, ok := opts.(*bccsp.IdemixCredentialSignerOpts)
+	if !ok {
+		return nil, errors.New("invalid options, expected *bccsp.IdemixCredentialSignerOpts")
+	}
+
+	credRequest := &cryptolib.CredRequest{}
+	err := proto.Unmarshal(digest, credRequest)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed unmarshalling credential request")
+	}
+
+	attrs := make([]*FP256BN.BIG, len(o.Attributes))
+	for i, attr := range o.Attributes {
+		attrs[i], err = attributeValue(attr)
+		if err != nil {
+			return nil, errors.WithMessage(err, "invalid attribute")
+		}
+	}
+
+	rng, err := cryptolib.GetRand()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed initializing PRNG")
+	}
+
+	cred, err := cryptolib.NewCredential(issuerKey.sk, credRequest, attrs, rng)
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(cred)
+}
+
+// criSigner creates, with a revocation secret key, the
+// credential revocation information of an epoch
+type criSigner struct{}
+
+func (*criSigner) Sign(k bccsp.Key, digest []byte, opts bccsp.SignerOpts) ([]byte, error) {
+	revocationKey, ok := k.(*revocationSecretKey)
+	if !ok {
+		return nil, errors.New("invalid key, expected *revocationSecretKey")
+	}
+	o, ok := opts.(*bccsp.IdemixCRISignerOpts)
+	if !ok {
+		return nil, errors.New("invalid options, expected *bccsp.IdemixCRISignerOpts")
+	}
+
+	handles := make([]*FP256BN.BIG, len(o.UnrevokedHandles))
+	for i, handle := range o.UnrevokedHandles {
+		if len(handle) != cryptolib.FieldBytes {
+			return nil, errors.Errorf("invalid revocation handle at position [%d], expected length %d, got %d", i, cryptolib.FieldBytes, len(handle))
+		}
+		handles[i] = FP256BN.FromBytes(handle)
+	}
+
+	rng, err := cryptolib.GetRand()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed initializing PRNG")
+	}
+
+	cri, err := cryptolib.CreateCRI(revocationKey.sk, handles, o.Epoch, cryptolib.RevocationAlgorithm(o.RevocationAlgorithm), rng)
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(cri)
+}
+
+// issuerVerifier verifies, with an issuer public key,
+// credential requests and signatures, depending on the opts
+type issuerVerifier struct{}
+
+func (*issuerVerifier) Verify(k bccsp.Key, signature, digest []byte, opts bccsp.SignerOpts) (bool, error) {
+	ipk, ok := k.(*issuerPublicKey)
+	if !ok {
+		return false, errors.New("invalid key, expected *issuerPublicKey")
+	}
+
+	switch o := opts.(type) {
+	case *bccsp.IdemixCredentialRequestSignerOpts:
+		credRequest := &cryptolib.CredRequest{}
+		err := proto.Unmarshal(signature, credRequest)
+		if err != nil {
+			return false, errors.Wrap(err, "failed unmarshalling credential request")
+		}
+		if len(o.IssuerNonce) != 0 && !bytes.Equal(o.IssuerNonce, credRequest.IssuerNonce) {
+			return false, errors.New("invalid credential request, the issuer nonce does not match")
+		}
+
+		err = credRequest.Check(ipk.pk)
+		if err != nil {
+			return false, err
+		}
+		return true, nil
+
+	case *bccsp.IdemixSignerOpts:
+		sig := &cryptolib.Signature{}
+		err := proto.Unmarshal(signature, sig)
+		if err != nil {
+			return false, errors.Wrap(err, "failed unmarshalling signature")
+		}
+		cri, err := unmarshalCRI(o.CRI)
+		if err != nil {
+			return false, err
+		}
+
+		disclosure, memberships, err := proofSpec(o.Attributes)
+		if err != nil {
+			return false, err
+		}
+		values := make([]*FP256BN.BIG, len(o.Attributes))
+		for i, attr := range o.Attributes {
+			if disclosure[i] == 0 {
+				continue
+			}
+			values[i], err = attributeValue(attr)
+			if err != nil {
+				return false, errors.WithMessage(err, "invalid attribute")
+			}
+		}
+
+		err = sig.Ver(disclosure, ipk.pk, digest, values, o.RhIndex, cri, memberships)
+		if err != nil {
+			return false, err
+		}
+		return true, nil
+
+	default:
+		return false, errors.Errorf("invalid options, unsupported type %T", opts)
+	}
+}
+
+// credentialVerifier verifies, with a user secret key, that a credential
+// was issued on that key and carries the expected attribute values
+type credentialVerifier struct{}
+
+func (*credentialVerifier) Verify(k bccsp.Key, signature, digest []byte, opts bccsp.SignerOpts) (bool, error) {
+	userKey, ok := k.(*userSecretKey)
+	if !ok {
+		return false, errors.New("invalid key, expected *userSecretKey")
+	}
+	o, ok := opts.(*bccsp.IdemixCredentialSignerOpts)
+	if !ok {
+		return false, errors.New("invalid options, expected *bccsp.IdemixCredentialSignerOpts")
+	}
+	ipk, err := toIssuerPublicKey(o.IssuerPK)
+	if err != nil {
+		return false, err
+	}
+
+	cred := &cryptolib.Credential{}
+	err = proto.Unmarshal(signature, cred)
+	if err != nil {
+		return false, errors.Wrap(err, "failed unmarshalling credential")
+	}
+
+	if len(o.Attributes) != 0 {
+		if len(cred.Attrs) != len(o.Attributes) {
+			return false, errors.Errorf("credential contains %d attribute values, but expected %d", len(cred.Attrs), len(o.Attributes))
+		}
+		for i, attr := range o.Attributes {
+			if attr.Type == bccsp.IdemixHiddenAttribute {
+				continue
+			}
+			value, err := attributeValue(attr)
+			if err != nil {
+				return false, errors.WithMessage(err, "invalid attribute")
+			}
+			if !bytes.Equal(cryptolib.BigToBytes(value), cred.Attrs[i]) {
+				return false, errors.Errorf("credential does not contain the correct value for attribute %s", ipk.AttributeNames[i])
+			}
+		}
+	}
+
+	err = cred.Ver(userKey.sk, ipk)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// nymSignatureVerifier verifies pseudonym signatures with the public part of the pseudonym
+type nymSignatureVerifier struct{}
+
+func (*nymSignatureVerifier) Verify(k bccsp.Key, signature, digest []byte, opts bccsp.SignerOpts) (bool, error) {
+	nym, ok := k.(*nymPublicKey)
+	if !ok {
+		return false, errors.New("invalid key, expected *nymPublicKey")
+	}
+	o, ok := opts.(*bccsp.IdemixNymSignerOpts)
+	if !ok {
+		return false, errors.New("invalid options, expected *bccsp.IdemixNymSignerOpts")
+	}
+	ipk, err := toIssuerPublicKey(o.IssuerPK)
+	if err != nil {
+		return false, err
+	}
+
+	sig := &cryptolib.NymSignature{}
+	err = proto.Unmarshal(signature, sig)
+	if err != nil {
+		return false, errors.Wrap(err, "failed unmarshalling signature")
+	}
+
+	err = sig.Ver(nym.nym, ipk, digest)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// criVerifier verifies, with a revocation public key, that a credential
+// revocation information was issued by the revocation authority
+// for the epoch and revocation algorithm in the opts
+type criVerifier struct{}
+
+func (*criVerifier) Verify(k bccsp.Key, signature, digest []byte, opts bccsp.SignerOpts) (bool, error) {
+	revocationKey, ok := k.(*revocationPublicKey)
+	if !ok {
+		return false, errors.New("invalid key, expected *revocationPublicKey")
+	}
+	o, ok := opts.(*bccsp.IdemixCRISignerOpts)
+	if !ok {
+		return false, errors.New("invalid options, expected *bccsp.IdemixCRISignerOpts")
+	}
+
+	cri, err := unmarshalCRI(signature)
+	if err != nil {
+		return false, err
+	}
+	if cri.Epoch != o.Epoch {
+		return false, errors.Errorf("credential revocation information is for epoch %d, expected %d", cri.Epoch, o.Epoch)
+	}
+	if bccsp.RevocationAlgorithm(cri.RevocationAlg) != o.RevocationAlgorithm {
+		return false, errors.Errorf("credential revocation information uses revocation algorithm %d, expected %d", cri.RevocationAlg, o.RevocationAlgorithm)
+	}
+
+	err = cryptolib.VerifyCRI(revocationKey.pk, cri)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// toIssuerPublicKey extracts the issuer public key from the passed bccsp key,
+// which can be either an issuer public or secret key
+func toIssuerPublicKey(k bccsp.Key) (*cryptolib.IssuerPublicKey, error) {
+	switch key := k.(type) {
+	case *issuerPublicKey:
+		return key.pk, nil
+	case *issuerSecretKey:
+		return key.sk.IPk, nil
+	default:
+		return nil, errors.Errorf("invalid options, expected IssuerPK as *issuerPublicKey, got %T", k)
+	}
+}
+
+// attributeValue maps a disclosed attribute to its value in the credential
+func attributeValue(attr bccsp.IdemixAttribute) (*FP256BN.BIG, error) {
+	switch attr.Type {
+	case bccsp.IdemixBytesAttribute:
+		value, ok := attr.Value.([]byte)
+		if !ok {
+			return nil, errors.New("expected byte array as attribute value")
+		}
+		return cryptolib.HashModOrder(value), nil
+	case bccsp.IdemixIntAttribute:
+		value, ok := attr.Value.(int)
+		if !ok {
+			return nil, errors.New("expected int as attribute value")
+		}
+		return FP256BN.NewBIGint(value), nil
+	default:
+		return nil, errors.Errorf("attribute type %d not allowed", attr.Type)
+	}
+}
+
+// proofSpec returns which attributes a signature discloses and, for the
+// membership attributes, the sets of values they are proven to be in
+func proofSpec(attributes []bccsp.IdemixAttribute) ([]byte, map[int][]*FP256BN.BIG, error) {
+	disclosure := make([]byte, len(attributes))
+	memberships := map[int][]*FP256BN.BIG{}
+	for i, attr := range attributes {
+		switch attr.Type {
+		case bccsp.IdemixHiddenAttribute:
+		case bccsp.IdemixMembershipAttribute:
+			set, ok := attr.Value.([]bccsp.IdemixAttribute)
+			if !ok {
+				return nil, nil, errors.Errorf("invalid attribute at position [%d], expected []bccsp.IdemixAttribute as membership attribute value", i)
+			}
+			if len(set) == 0 {
+				return nil, nil, errors.Errorf("invalid attribute at position [%d], the set of values must not be empty", i)
+			}
+			values := make([]*FP256BN.BIG, len(set))
+			for j, member := range set {
+				value, err := attributeValue(member)
+				if err != nil {
+					return nil, nil, errors.WithMessage(err, "invalid membership attribute value")
+				}
+				values[j] = value
+			}
+			memberships[i] = values
+		default:
+			disclosure[i] = 1
+		}
+	}
+	return disclosure, memberships, nil
+}
+
+// unmarshalCRI unmarshals a credential revocation information,
+// returning nil if raw is empty
+func unmarshalCRI(raw []byte) (*cryptolib.CredentialRevocationInformation, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	cri := &cryptolib.CredentialRevocationInformation{}
+	err := proto.Unmarshal(raw, cri)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed unmarshalling credential revocation information")
+	}
+	return cri, nil
+}
@@ -0,0 +1,210 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package idemix
+
+import (
+	"crypto/sha256"
+
+	"github.com/hyperledger/fabric-amcl/amcl/FP256BN"
+	"github.com/hyperledger/fabric/bccsp"
+	cryptolib "github.com/hyperledger/fabric/idemix"
+	"github.com/pkg/errors"
+)
+
+// userSecretKey contains the secret key a user's credential is issued on
+// and implements the bccsp.Key interface
+type userSecretKey struct {
+	sk *FP256BN.BIG
+}
+
+// Bytes converts this key to its byte representation,
+// if this operation is allowed.
+func (k *userSecretKey) Bytes() ([]byte, error) {
+	return cryptolib.BigToBytes(k.sk), nil
+}
+
+// SKI returns the subject key identifier of this key.
+func (k *userSecretKey) SKI() []byte {
+	hash := sha256.Sum256(cryptolib.BigToBytes(k.sk))
+	return hash[:]
+}
+
+// Symmetric returns true if this key is a symmetric key,
+// false if this key is asymmetric
+func (k *userSecretKey) Symmetric() bool {
+	return true
+}
+
+// Private returns true if this key is a private key,
+// false otherwise.
+func (k *userSecretKey) Private() bool {
+	return true
+}
+
+// PublicKey returns the corresponding public key part of an asymmetric public/private key pair.
+// This method returns an error in symmetric key schemes.
+func (k *userSecretKey) PublicKey() (bccsp.Key, error) {
+	return nil, errors.New("cannot call this method on a symmetric key")
+}
+
+// userSecretKeyGen generates user secret keys
+type userSecretKeyGen struct{}
+
+func (*userSecretKeyGen) KeyGen(opts bccsp.KeyGenOpts) (bccsp.Key, error) {
+	rng, err := cryptolib.GetRand()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed initializing PRNG")
+	}
+
+	return &userSecretKey{cryptolib.RandModOrder(rng)}, nil
+}
+
+// userSecretKeyImporter imports the byte representation of user secret keys
+type userSecretKeyImporter struct{}
+
+func (*userSecretKeyImporter) KeyImport(raw interface{}, opts bccsp.KeyImportOpts) (bccsp.Key, error) {
+	der, ok := raw.([]byte)
+	if !ok {
+		return nil, errors.New("invalid raw, expected byte array")
+	}
+	if len(der) != cryptolib.FieldBytes {
+		return nil, errors.Errorf("invalid raw, expected length %d, got %d", cryptolib.FieldBytes, len(der))
+	}
+
+	return &userSecretKey{FP256BN.FromBytes(der)}, nil
+}
+
+// nymSecretKey contains an unlinkable pseudonym together with the
+// randomness used to derive it from a user secret key
+// and implements the bccsp.Key interface
+type nymSecretKey struct {
+	// sk is the user secret key the pseudonym was derived from
+	sk *FP256BN.BIG
+	// rNym is the randomness of the pseudonym
+	rNym *FP256BN.BIG
+	// nym is the pseudonym
+	nym *FP256BN.ECP
+}
+
+// Bytes converts this key to its byte representation,
+// if this operation is allowed.
+func (k *nymSecretKey) Bytes() ([]byte, error) {
+	return nil, errors.New("not supported")
+}
+
+// SKI returns the subject key identifier of this key.
+func (k *nymSecretKey) SKI() []byte {
+	return nymSKI(k.nym)
+}
+
+// Symmetric returns true if this key is a symmetric key,
+// false if this key is asymmetric
+func (k *nymSecretKey) Symmetric() bool {
+	return false
+}
+
+// Private returns true if this key is a private key,
+// false otherwise.
+func (k *nymSecretKey) Private() bool {
+	return true
+}
+
+// PublicKey returns the corresponding public key part of an asymmetric public/private key pair.
+func (k *nymSecretKey) PublicKey() (bccsp.Key, error) {
+	return &nymPublicKey{k.nym}, nil
+}
+
+// nymPublicKey contains the public part of a pseudonym
+// and implements the bccsp.Key interface
+type nymPublicKey struct {
+	nym *FP256BN.ECP
+}
+
+// Bytes returns the concatenation of the coordinates of the pseudonym
+func (k *nymPublicKey) Bytes() ([]byte, error) {
+	return nymBytes(k.nym), nil
+}
+
+// SKI returns the subject key identifier of this key.
+func (k *nymPublicKey) SKI() []byte {
+	return nymSKI(k.nym)
+}
+
+// Symmetric returns true if this key is a symmetric key,
+// false if this key is asymmetric
+func (k *nymPublicKey) Symmetric() bool {
+	return false
+}
+
+// Private returns true if this key is a private key,
+// false otherwise.
+func (k *nymPublicKey) Private() bool {
+	return false
+}
+
+// PublicKey returns the corresponding public key part of an asymmetric public/private key pair.
+func (k *nymPublicKey) PublicKey() (bccsp.Key, error) {
+	return k, nil
+}
+
+func nymBytes(nym *FP256BN.ECP) []byte {
+	return append(cryptolib.BigToBytes(nym.GetX()), cryptolib.BigToBytes(nym.GetY())...)
+}
+
+func nymSKI(nym *FP256BN.ECP) []byte {
+	hash := sha256.Sum256(nymBytes(nym))
+	return hash[:]
+}
+
+// nymKeyDerivation derives pseudonyms from user secret keys
+type nymKeyDerivation struct{}
+
+func (*nymKeyDerivation) KeyDeriv(k bccsp.Key, opts bccsp.KeyDerivOpts) (bccsp.Key, error) {
+	userKey, ok := k.(*userSecretKey)
+	if !ok {
+		return nil, errors.New("invalid key, expected *userSecretKey")
+	}
+	o, ok := opts.(*bccsp.IdemixNymKeyDerivationOpts)
+	if !ok {
+		return nil, errors.New("invalid options, expected *bccsp.IdemixNymKeyDerivationOpts")
+	}
+	ipk, ok := o.IssuerPublicKey().(*issuerPublicKey)
+	if !ok {
+		return nil, errors.New("invalid options, expected IssuerPK as *issuerPublicKey")
+	}
+
+	rng, err := cryptolib.GetRand()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed initializing PRNG")
+	}
+
+	nym, rNym := cryptolib.MakeNym(userKey.sk, ipk.pk, rng)
+	return &nymSecretKey{sk: userKey.sk, rNym: rNym, nym: nym}, nil
+}
+
+// nymPublicKeyImporter imports the byte representation of pseudonyms,
+// that is the concatenation of their coordinates
+type nymPublicKeyImporter struct{}
+
+func (*nymPublicKeyImporter) KeyImport(raw interface{}, opts bccsp.KeyImportOpts) (bccsp.Key, error) {
+	der, ok := raw.([]byte)
+	if !ok {
+		return nil, errors.New("invalid raw, expected byte array")
+	}
+	if len(der) != 2*cryptolib.FieldBytes {
+		return nil, errors.Errorf("invalid raw, expected length %d, got %d", 2*cryptolib.FieldBytes, len(der))
+	}
+
+	nym := FP256BN.NewECPbigs(
+		FP256BN.FromBytes(der[:cryptolib.FieldBytes]),
+		FP256BN.FromBytes(der[cryptolib.FieldBytes:]))
+	if nym.Is_infinity() {
+		return nil, errors.New("invalid raw, the pseudonym is not a valid point")
+	}
+
+	return &nymPublicKey{nym}, nil
+}
@@ -12,6 +12,43 @@ const (
 	IDEMIX = "IDEMIX"
 )
 
+// RevocationAlgorithm identifies the revocation algorithm used for Idemix credentials
+type RevocationAlgorithm int32
+
+const (
+	// AlgNoRevocation means that credentials are never revoked
+	AlgNoRevocation RevocationAlgorithm = iota
+	// AlgWeakBB means that credentials are revoked per epoch with
+	// weak Boneh-Boyen signatures on the unrevoked revocation handles
+	AlgWeakBB
+)
+
+// IdemixAttributeType represents the type of an idemix attribute
+type IdemixAttributeType int
+
+const (
+	// IdemixHiddenAttribute represents an hidden attribute
+	IdemixHiddenAttribute IdemixAttributeType = iota
+	// IdemixBytesAttribute represents a sequence of bytes
+	IdemixBytesAttribute
+	// IdemixIntAttribute represents an int
+	IdemixIntAttribute
+	// IdemixMembershipAttribute represents an hidden attribute
+	// that is proven to be equal to one of a set of values
+	IdemixMembershipAttribute
+)
+
+// IdemixAttribute is an attribute of an idemix credential
+type IdemixAttribute struct {
+	// Type is the attribute's type
+	Type IdemixAttributeType
+	// Value is the attribute's value: a []byte for IdemixBytesAttribute,
+	// an int for IdemixIntAttribute, nil for IdemixHiddenAttribute, and
+	// for IdemixMembershipAttribute a []IdemixAttribute of bytes or int
+	// attributes containing the set of values
+	Value interface{}
+}
+
 // IdemixIssuerKeyGenOpts contains the options for the Idemix Issuer key-generation.
 // A list of attribytes may be optionally passed
 type IdemixIssuerKeyGenOpts struct {
@@ -32,6 +69,24 @@ func (o *IdemixIssuerKeyGenOpts) Ephemeral() bool {
 	return o.Temporary
 }
 
+// IdemixIssuerPublicKeyImportOpts contains the options for importing of an Idemix issuer public key.
+type IdemixIssuerPublicKeyImportOpts struct {
+	Temporary bool
+	// AttributeNames is a list of attributes to ensure the import public key has
+	AttributeNames []string
+}
+
+// Algorithm returns the key generation algorithm identifier (to be used).
+func (*IdemixIssuerPublicKeyImportOpts) Algorithm() string {
+	return IDEMIX
+}
+
+// Ephemeral returns true if the key to generate has to be ephemeral,
+// false otherwise.
+func (o *IdemixIssuerPublicKeyImportOpts) Ephemeral() bool {
+	return o.Temporary
+}
+
 // IdemixUserSecretKeyGenOpts contains the options for the generation of an Idemix credential secret key.
 type IdemixUserSecretKeyGenOpts struct {
 	Temporary bool
@@ -48,6 +103,22 @@ func (o *IdemixUserSecretKeyGenOpts) Ephemeral() bool {
 	return o.Temporary
 }
 
+// IdemixUserSecretKeyImportOpts contains the options for importing of an Idemix credential secret key.
+type IdemixUserSecretKeyImportOpts struct {
+	Temporary bool
+}
+
+// Algorithm returns the key generation algorithm identifier (to be used).
+func (*IdemixUserSecretKeyImportOpts) Algorithm() string {
+	return IDEMIX
+}
+
+// Ephemeral returns true if the key to generate has to be ephemeral,
+// false otherwise.
+func (o *IdemixUserSecretKeyImportOpts) Ephemeral() bool {
+	return o.Temporary
+}
+
 // IdemixNymKeyDerivationOpts contains the options to create a new unlinkable pseudonym from a
 // credential secret key with the respect to the specified issuer public key
 type IdemixNymKeyDerivationOpts struct {
@@ -74,11 +145,33 @@ func (o *IdemixNymKeyDerivationOpts) IssuerPublicKey() Key {
 	return o.IssuerPK
 }
 
+// IdemixNymPublicKeyImportOpts contains the options to import the public part of a pseudonym
+type IdemixNymPublicKeyImportOpts struct {
+	// Temporary tells if the key is ephemeral
+	Temporary bool
+}
+
+// Algorithm returns the key derivation algorithm identifier (to be used).
+func (*IdemixNymPublicKeyImportOpts) Algorithm() string {
+	return IDEMIX
+}
+
+// Ephemeral returns true if the key to derive has to be ephemeral,
+// false otherwise.
+func (o *IdemixNymPublicKeyImportOpts) Ephemeral() bool {
+	return o.Temporary
+}
+
 // IdemixCredentialRequestSignerOpts contains the option to create a Idemix credential request.
 type IdemixCredentialRequestSignerOpts struct {
 	// Attributes contains a list of indices of the attributes to be included in the
 	// credential. The indices are with the respect to IdemixIssuerKeyGenOpts#AttributeNames.
 	Attributes []int
+	// IssuerPK is the public-key of the issuer
+	IssuerPK Key
+	// IssuerNonce is generated by the issuer and used by the client to generate the credential request.
+	// Once the issuer gets the credential requests, it checks that the nonce is the same.
+	IssuerNonce []byte
 	// HashFun is the hash function to be used
 	H crypto.Hash
 }
@@ -87,9 +180,18 @@ func (o *IdemixCredentialRequestSignerOpts) HashFunc() crypto.Hash {
 	return o.H
 }
 
+// IssuerPublicKey returns the issuer public key used to derive
+// a new unlinkable pseudonym from a credential secret key
+func (o *IdemixCredentialRequestSignerOpts) IssuerPublicKey() Key {
+	return o.IssuerPK
+}
+
 // IdemixCredentialSignerOpts contains the options to produce a credential starting from a credential request
 type IdemixCredentialSignerOpts struct {
-	Attributes []int
+	// Attributes to include in the credentials. IdemixHiddenAttribute is not allowed here
+	Attributes []IdemixAttribute
+	// IssuerPK is the public-key of the issuer
+	IssuerPK Key
 	// HashFun is the hash function to be used
 	H crypto.Hash
 }
@@ -101,6 +203,11 @@ func (o *IdemixCredentialSignerOpts) HashFunc() crypto.Hash {
 	return o.H
 }
 
+// IssuerPublicKey returns the issuer public key the credential is verified against
+func (o *IdemixCredentialSignerOpts) IssuerPublicKey() Key {
+	return o.IssuerPK
+}
+
 // IdemixSignerOpts contains the options to generate an Idemix signature
 type IdemixSignerOpts struct {
 	// Nym is the pseudonym to be used
@@ -109,10 +216,19 @@ type IdemixSignerOpts struct {
 	IssuerPK Key
 	// Credential is the byte representation of the credential signed by the issuer
 	Credential []byte
-	// Disclosure specifies which attribute should be disclosed. If Disclosure[i] = 0
+	// Attributes specifies which attribute should be disclosed and which not.
+	// If Attributes[i].Type = IdemixHiddenAttribute
 	// then the i-th credential attribute should not be disclosed, otherwise the i-th
 	// credential attribute will be disclosed.
-	Disclosure []byte
+	// At verification time, if the i-th attribute is disclosed (Attributes[i].Type != IdemixHiddenAttribute),
+	// then Attributes[i].Value must be set accordingly.
+	Attributes []IdemixAttribute
+	// RhIndex is the index of attribute containing the revocation handler.
+	// Notice that this attributed cannot be discloused
+	RhIndex int
+	// CRI contains the (serialized) credential revocation information of the current
+	// epoch. If nil, no non-revocation proof is produced or expected.
+	CRI []byte
 	// H is the hash function to be used
 	H crypto.Hash
 }
@@ -137,3 +253,57 @@ type IdemixNymSignerOpts struct {
 func (o *IdemixNymSignerOpts) HashFunc() crypto.Hash {
 	return o.H
 }
+
+// IdemixRevocationKeyGenOpts contains the options for the Idemix revocation key-generation.
+type IdemixRevocationKeyGenOpts struct {
+	// Temporary tells if the key is ephemeral
+	Temporary bool
+}
+
+// Algorithm returns the key generation algorithm identifier (to be used).
+func (*IdemixRevocationKeyGenOpts) Algorithm() string {
+	return IDEMIX
+}
+
+// Ephemeral returns true if the key to generate has to be ephemeral,
+// false otherwise.
+func (o *IdemixRevocationKeyGenOpts) Ephemeral() bool {
+	return o.Temporary
+}
+
+// IdemixRevocationPublicKeyImportOpts contains the options for importing of an Idemix revocation public key.
+type IdemixRevocationPublicKeyImportOpts struct {
+	Temporary bool
+}
+
+// Algorithm returns the key generation algorithm identifier (to be used).
+func (*IdemixRevocationPublicKeyImportOpts) Algorithm() string {
+	return IDEMIX
+}
+
+// Ephemeral returns true if the key to generate has to be ephemeral,
+// false otherwise.
+func (o *IdemixRevocationPublicKeyImportOpts) Ephemeral() bool {
+	return o.Temporary
+}
+
+// IdemixCRISignerOpts contains the options to generate an Idemix CRI.
+// The CRI is supposed to be generated by the Issuing authority and
+// can be verified publicly by using the revocation public key.
+type IdemixCRISignerOpts struct {
+	// Epoch is the epoch the CRI is valid in
+	Epoch int64
+	// RevocationAlgorithm is the revocation algorithm of the CRI
+	RevocationAlgorithm RevocationAlgorithm
+	// UnrevokedHandles contains the revocation handles that are not revoked in Epoch
+	UnrevokedHandles [][]byte
+	// H is the hash function to be used
+	H crypto.Hash
+}
+
+// HashFunc returns an identifier for the hash function used to produce
+// the message passed to Signer.Sign, or else zero to indicate that no
+// hashing was done.
+func (o *IdemixCRISignerOpts) HashFunc() crypto.Hash {
+	return o.H
+}
@@ -0,0 +1,138 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/hyperledger/fabric/cmd/common/comm"
+	"github.com/hyperledger/fabric/cmd/common/signer"
+	"github.com/pkg/errors"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+const (
+	saveConfigCommand = "saveConfig"
+)
+
+var (
+	// function used to terminate the CLI
+	terminate = os.Exit
+	// function used to redirect output to
+	outWriter io.Writer = os.Stderr
+)
+
+// CLICommand defines a command that is added to the CLI
+// via an external consumer.
+type CLICommand func(Config) error
+
+// CLI defines a command line interpreter
+type CLI struct {
+	app         *kingpin.Application
+	dispatchers map[string]CLICommand
+
+	// CLI arguments
+	mspID, mspDir          *string
+	tlsCA, tlsCert, tlsKey *string
+	configFile             *string
+}
+
+// NewCLI creates a new CLI with the given name and help message
+func NewCLI(name, help string) *CLI {
+	cli := &CLI{
+		app:         kingpin.New(name, help),
+		dispatchers: make(map[string]CLICommand),
+	}
+	cli.configFile = cli.app.Flag("configFile", "Specifies the config file to load the configuration from").String()
+	cli.mspID = cli.app.Flag("MSP", "The MSP ID of the user, which is used to sign requests").String()
+	cli.tlsCA = cli.app.Flag("peerTLSCA", "The path of the TLS root CA certificate of the peer. If specified, TLS is used").String()
+	cli.tlsCert = cli.app.Flag("tlsCert", "The path of the client TLS certificate, used for mutual TLS").String()
+	cli.tlsKey = cli.app.Flag("tlsKey", "The path of the client TLS private key, used for mutual TLS").String()
+	cli.mspDir = cli.app.Flag("mspPath", "The path of the local MSP directory of the user that signs the requests").String()
+	cli.app.Command(saveConfigCommand, "Save the config passed by flags into the file specified by --configFile")
+	return cli
+}
+
+// Command adds a new top-level command to the CLI
+func (cli *CLI) Command(name, help string, onCommand CLICommand) *kingpin.CmdClause {
+	cmd := cli.app.Command(name, help)
+	cli.dispatchers[name] = onCommand
+	return cmd
+}
+
+// Run makes the CLI process the arguments and executes the command(s) with the flag(s)
+func (cli *CLI) Run(args []string) {
+	command, err := cli.app.Parse(args)
+	if err != nil {
+		cli.fail(err)
+		return
+	}
+	if command == saveConfigCommand {
+		if err := cli.saveConfig(); err != nil {
+			cli.fail(err)
+		}
+		return
+	}
+
+	f, exists := cli.dispatchers[command]
+	if !exists {
+		cli.fail(errors.Errorf("command %s doesn't exist, shouldn't happen", command))
+		return
+	}
+
+	conf, err := cli.loadConfig()
+	if err != nil {
+		cli.fail(err)
+		return
+	}
+
+	if err := f(conf); err != nil {
+		cli.fail(err)
+	}
+}
+
+func (cli *CLI) fail(err error) {
+	out(err.Error())
+	terminate(1)
+}
+
+func (cli *CLI) saveConfig() error {
+	if *cli.configFile == "" {
+		return errors.New("--configFile must be used to specify the configuration file")
+	}
+	return cli.configFromFlags().ToFile(*cli.configFile)
+}
+
+// loadConfig loads the configuration from the file specified by --configFile,
+// or out of the flags if no configuration file was specified
+func (cli *CLI) loadConfig() (Config, error) {
+	if *cli.configFile != "" {
+		return ConfigFromFile(*cli.configFile)
+	}
+	conf := cli.configFromFlags()
+	return conf, validateConfig(conf)
+}
+
+func (cli *CLI) configFromFlags() Config {
+	return Config{
+		TLSConfig: comm.Config{
+			PeerCACertPath: *cli.tlsCA,
+			CertPath:       *cli.tlsCert,
+			KeyPath:        *cli.tlsKey,
+		},
+		SignerConfig: signer.Config{
+			MSPID:  *cli.mspID,
+			MSPDir: *cli.mspDir,
+		},
+	}
+}
+
+func out(s string) {
+	fmt.Fprintln(outWriter, s)
+}
@@ -0,0 +1,128 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric/cmd/common/comm"
+	"github.com/hyperledger/fabric/cmd/common/signer"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCLI(t *testing.T) {
+	var exitCode int
+	terminate = func(code int) {
+		exitCode = code
+	}
+	defer func() {
+		terminate = os.Exit
+		outWriter = os.Stderr
+	}()
+
+	dir, err := ioutil.TempDir("", "cli")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	configFile := filepath.Join(dir, "config.yaml")
+
+	var receivedConf *Config
+	var returnedErr error
+	testCmd := func(conf Config) error {
+		receivedConf = &conf
+		return returnedErr
+	}
+
+	run := func(args ...string) string {
+		exitCode = 0
+		receivedConf = nil
+		buff := &bytes.Buffer{}
+		outWriter = buff
+		cli := NewCLI("cli", "cli help")
+		cli.Command("test", "test help", testCmd)
+		cli.Run(args)
+		return buff.String()
+	}
+
+	expectedConf := Config{
+		TLSConfig: comm.Config{
+			PeerCACertPath: "ca.pem",
+			CertPath:       "cert.pem",
+			KeyPath:        "key.pem",
+		},
+		SignerConfig: signer.Config{
+			MSPID:  "Org1MSP",
+			MSPDir: "msp",
+		},
+	}
+	flags := []string{
+		"--peerTLSCA", "ca.pem",
+		"--tlsCert", "cert.pem",
+		"--tlsKey", "key.pem",
+		"--MSP", "Org1MSP",
+		"--mspPath", "msp",
+	}
+
+	t.Run("command with flags", func(t *testing.T) {
+		output := run(append([]string{"test"}, flags...)...)
+		assert.Empty(t, output)
+		assert.Equal(t, 0, exitCode)
+		assert.Equal(t, &expectedConf, receivedConf)
+	})
+
+	t.Run("command fails", func(t *testing.T) {
+		returnedErr = errors.New("something went wrong")
+		defer func() {
+			returnedErr = nil
+		}()
+		output := run(append([]string{"test"}, flags...)...)
+		assert.Equal(t, "something went wrong\n", output)
+		assert.Equal(t, 1, exitCode)
+	})
+
+	t.Run("command with missing flags", func(t *testing.T) {
+		output := run("test", "--MSP", "Org1MSP")
+		assert.Equal(t, "MSP directory is not specified\n", output)
+		assert.Equal(t, 1, exitCode)
+		assert.Nil(t, receivedConf)
+	})
+
+	t.Run("unknown command", func(t *testing.T) {
+		output := run("foo")
+		assert.Contains(t, output, "expected command but got \"foo\"")
+		assert.Equal(t, 1, exitCode)
+	})
+
+	t.Run("saveConfig without a config file", func(t *testing.T) {
+		output := run(append([]string{"saveConfig"}, flags...)...)
+		assert.Equal(t, "--configFile must be used to specify the configuration file\n", output)
+		assert.Equal(t, 1, exitCode)
+	})
+
+	t.Run("saveConfig and then use the config file", func(t *testing.T) {
+		output := run(append([]string{"saveConfig", "--configFile", configFile}, flags...)...)
+		assert.Empty(t, output)
+		assert.Equal(t, 0, exitCode)
+		assert.Nil(t, receivedConf)
+
+		output = run("test", "--configFile", configFile)
+		assert.Empty(t, output)
+		assert.Equal(t, 0, exitCode)
+		assert.Equal(t, &expectedConf, receivedConf)
+	})
+
+	t.Run("non existent config file", func(t *testing.T) {
+		output := run("test", "--configFile", filepath.Join(dir, "non_existent.yaml"))
+		assert.Contains(t, output, "no such file or directory")
+		assert.Equal(t, 1, exitCode)
+		assert.Nil(t, receivedConf)
+	})
+}
@@ -0,0 +1,59 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"time"
+
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/comm"
+	"google.golang.org/grpc"
+)
+
+const defaultTimeout = time.Second * 5
+
+// Client deals with TLS connections
+// to the discovery server
+type Client struct {
+	TLSCertHash []byte
+	*comm.GRPCClient
+}
+
+// NewClient creates a new comm client out of the given configuration
+func NewClient(conf Config) (*Client, error) {
+	if conf.Timeout == time.Duration(0) {
+		conf.Timeout = defaultTimeout
+	}
+	sop, err := conf.ToSecureOptions()
+	if err != nil {
+		return nil, err
+	}
+	cl, err := comm.NewGRPCClient(comm.ClientConfig{
+		SecOpts: sop,
+		Timeout: conf.Timeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var tlsCertHash []byte
+	// If TLS is enabled, and we use mutual TLS, we need to compute the hash of the client certificate
+	if len(cl.Certificate().Certificate) > 0 {
+		tlsCertHash = util.ComputeSHA256(cl.Certificate().Certificate[0])
+	}
+	return &Client{GRPCClient: cl, TLSCertHash: tlsCertHash}, nil
+}
+
+// NewDialer creates a new dialer from the given endpoint
+func (c *Client) NewDialer(endpoint string) func() (*grpc.ClientConn, error) {
+	return func() (*grpc.ClientConn, error) {
+		conn, err := c.NewConnection(endpoint, "")
+		if err != nil {
+			return nil, err
+		}
+		return conn, nil
+	}
+}
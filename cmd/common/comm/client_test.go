@@ -0,0 +1,136 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/crypto/tlsgen"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/comm"
+	"github.com/hyperledger/fabric/protos/discovery"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+func TestTLSClient(t *testing.T) {
+	ca, err := tlsgen.NewCA()
+	assert.NoError(t, err)
+	serverPair, err := ca.NewServerCertKeyPair("127.0.0.1")
+	assert.NoError(t, err)
+	dir, err := ioutil.TempDir("", "tls")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	srv, err := comm.NewGRPCServer("127.0.0.1:", comm.ServerConfig{
+		SecOpts: &comm.SecureOptions{
+			UseTLS:      true,
+			Key:         serverPair.Key,
+			Certificate: serverPair.Cert,
+		},
+	})
+	assert.NoError(t, err)
+	go srv.Start()
+	defer srv.Stop()
+	conf := Config{
+		PeerCACertPath: writeFile(t, dir, "ca.pem", ca.CertBytes()),
+	}
+	cl, err := NewClient(conf)
+	assert.NoError(t, err)
+	assert.Nil(t, cl.TLSCertHash)
+	conn, err := cl.NewDialer(srv.Address())()
+	assert.NoError(t, err)
+	conn.Close()
+}
+
+func TestDialBadEndpoint(t *testing.T) {
+	ca, err := tlsgen.NewCA()
+	assert.NoError(t, err)
+	dir, err := ioutil.TempDir("", "tls")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	conf := Config{
+		PeerCACertPath: writeFile(t, dir, "ca.pem", ca.CertBytes()),
+		Timeout:        100 * time.Millisecond,
+	}
+	cl, err := NewClient(conf)
+	assert.NoError(t, err)
+	_, err = cl.NewDialer("non_existent_host.xyz.blabla:9999")()
+	assert.Error(t, err)
+}
+
+func TestNonTLSClient(t *testing.T) {
+	srv, err := comm.NewGRPCServer("127.0.0.1:", comm.ServerConfig{
+		SecOpts: &comm.SecureOptions{},
+	})
+	assert.NoError(t, err)
+	go srv.Start()
+	defer srv.Stop()
+	conf := Config{}
+	cl, err := NewClient(conf)
+	assert.NoError(t, err)
+	conn, err := cl.NewDialer(srv.Address())()
+	assert.NoError(t, err)
+	conn.Close()
+}
+
+func TestMutualTLSClient(t *testing.T) {
+	ca, err := tlsgen.NewCA()
+	assert.NoError(t, err)
+	serverPair, err := ca.NewServerCertKeyPair("127.0.0.1")
+	assert.NoError(t, err)
+	clientPair, err := ca.NewClientCertKeyPair()
+	assert.NoError(t, err)
+
+	dir, err := ioutil.TempDir("", "mutualtls")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	srv, err := comm.NewGRPCServer("127.0.0.1:", comm.ServerConfig{
+		SecOpts: &comm.SecureOptions{
+			UseTLS:            true,
+			RequireClientCert: true,
+			Key:               serverPair.Key,
+			Certificate:       serverPair.Cert,
+			ClientRootCAs:     [][]byte{ca.CertBytes()},
+		},
+	})
+	assert.NoError(t, err)
+	discovery.RegisterDiscoveryServer(srv.Server(), &discoveryService{})
+	go srv.Start()
+	defer srv.Stop()
+
+	conf := Config{
+		PeerCACertPath: writeFile(t, dir, "ca.pem", ca.CertBytes()),
+		CertPath:       writeFile(t, dir, "cert.pem", clientPair.Cert),
+		KeyPath:        writeFile(t, dir, "key.pem", clientPair.Key),
+	}
+	cl, err := NewClient(conf)
+	assert.NoError(t, err)
+	assert.Equal(t, util.ComputeSHA256(clientPair.TLSCert.Raw), cl.TLSCertHash)
+
+	conn, err := cl.NewDialer(srv.Address())()
+	assert.NoError(t, err)
+	defer conn.Close()
+	_, err = discovery.NewDiscoveryClient(conn).Discover(context.Background(), &discovery.SignedRequest{})
+	assert.NoError(t, err)
+}
+
+type discoveryService struct{}
+
+func (*discoveryService) Discover(context.Context, *discovery.SignedRequest) (*discovery.Response, error) {
+	return &discovery.Response{}, nil
+}
+
+func writeFile(t *testing.T, dir, name string, content []byte) string {
+	path := filepath.Join(dir, name)
+	assert.NoError(t, ioutil.WriteFile(path, content, 0600))
+	return path
+}
@@ -0,0 +1,66 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"io/ioutil"
+	"time"
+
+	"github.com/hyperledger/fabric/core/comm"
+	"github.com/pkg/errors"
+)
+
+// Config defines configuration of a Client
+type Config struct {
+	CertPath       string
+	KeyPath        string
+	PeerCACertPath string
+	Timeout        time.Duration
+}
+
+// ToSecureOptions converts this Config to SecureOptions.
+// TLS is enabled if a root CA certificate of the peers is configured,
+// and mutual TLS is used if a client certificate and key are configured as well
+func (conf Config) ToSecureOptions() (*comm.SecureOptions, error) {
+	if conf.PeerCACertPath == "" {
+		return &comm.SecureOptions{}, nil
+	}
+	caBytes, err := loadFile(conf.PeerCACertPath)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	secOpts := &comm.SecureOptions{
+		UseTLS:        true,
+		ServerRootCAs: [][]byte{caBytes},
+	}
+	if conf.CertPath == "" && conf.KeyPath == "" {
+		return secOpts, nil
+	}
+	if conf.CertPath == "" || conf.KeyPath == "" {
+		return nil, errors.New("both the TLS certificate and the TLS key must be specified for mutual TLS")
+	}
+	certBytes, err := loadFile(conf.CertPath)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	keyBytes, err := loadFile(conf.KeyPath)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	secOpts.RequireClientCert = true
+	secOpts.Certificate = certBytes
+	secOpts.Key = keyBytes
+	return secOpts, nil
+}
+
+func loadFile(path string) ([]byte, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Errorf("Failed opening file %s: %v", path, err)
+	}
+	return b, nil
+}
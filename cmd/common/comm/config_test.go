@@ -0,0 +1,67 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/crypto/tlsgen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig(t *testing.T) {
+	ca, err := tlsgen.NewCA()
+	assert.NoError(t, err)
+	clientPair, err := ca.NewClientCertKeyPair()
+	assert.NoError(t, err)
+	dir, err := ioutil.TempDir("", "config")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	caPath := writeFile(t, dir, "ca.pem", ca.CertBytes())
+	certPath := writeFile(t, dir, "cert.pem", clientPair.Cert)
+	keyPath := writeFile(t, dir, "key.pem", clientPair.Key)
+
+	// No TLS
+	secOpts, err := Config{}.ToSecureOptions()
+	assert.NoError(t, err)
+	assert.False(t, secOpts.UseTLS)
+
+	// Server side TLS only
+	secOpts, err = Config{PeerCACertPath: caPath}.ToSecureOptions()
+	assert.NoError(t, err)
+	assert.True(t, secOpts.UseTLS)
+	assert.False(t, secOpts.RequireClientCert)
+	assert.Equal(t, [][]byte{ca.CertBytes()}, secOpts.ServerRootCAs)
+
+	// Mutual TLS
+	secOpts, err = Config{PeerCACertPath: caPath, CertPath: certPath, KeyPath: keyPath}.ToSecureOptions()
+	assert.NoError(t, err)
+	assert.True(t, secOpts.UseTLS)
+	assert.True(t, secOpts.RequireClientCert)
+	assert.Equal(t, clientPair.Cert, secOpts.Certificate)
+	assert.Equal(t, clientPair.Key, secOpts.Key)
+
+	// Only the certificate is specified
+	_, err = Config{PeerCACertPath: caPath, CertPath: certPath}.ToSecureOptions()
+	assert.EqualError(t, err, "both the TLS certificate and the TLS key must be specified for mutual TLS")
+
+	// Missing files
+	nonExistent := filepath.Join(dir, "non_existent")
+	for _, conf := range []Config{
+		{PeerCACertPath: nonExistent},
+		{PeerCACertPath: caPath, CertPath: nonExistent, KeyPath: keyPath},
+		{PeerCACertPath: caPath, CertPath: certPath, KeyPath: nonExistent},
+	} {
+		_, err = conf.ToSecureOptions()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "Failed opening file "+nonExistent)
+	}
+}
@@ -0,0 +1,83 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/cmd/common/comm"
+	"github.com/hyperledger/fabric/cmd/common/signer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	configFilePath := filepath.Join(dir, "config.yaml")
+
+	t.Run("save and load a config", func(t *testing.T) {
+		c := Config{
+			TLSConfig: comm.Config{
+				CertPath:       "foo",
+				KeyPath:        "foo",
+				PeerCACertPath: "foo",
+				Timeout:        time.Second * 3,
+			},
+			SignerConfig: signer.Config{
+				MSPDir: "foo",
+				MSPID:  "foo",
+			},
+		}
+
+		err := c.ToFile(configFilePath)
+		assert.NoError(t, err)
+
+		c2, err := ConfigFromFile(configFilePath)
+		assert.NoError(t, err)
+		assert.Equal(t, c, c2)
+	})
+
+	t.Run("bad config isn't saved", func(t *testing.T) {
+		c := Config{}
+		err := c.ToFile(filepath.Join(dir, "bad.yaml"))
+		assert.EqualError(t, err, "config isn't valid: MSP ID is not specified")
+		_, err = os.Stat(filepath.Join(dir, "bad.yaml"))
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("invalid config is rejected when loaded", func(t *testing.T) {
+		for _, tc := range []struct {
+			content string
+			errMsg  string
+		}{
+			{content: "signerconfig:\n  mspdir: foo\n", errMsg: "MSP ID is not specified"},
+			{content: "signerconfig:\n  mspid: foo\n", errMsg: "MSP directory is not specified"},
+		} {
+			path := filepath.Join(dir, "invalid.yaml")
+			assert.NoError(t, ioutil.WriteFile(path, []byte(tc.content), 0600))
+			_, err := ConfigFromFile(path)
+			assert.EqualError(t, err, tc.errMsg)
+		}
+	})
+
+	t.Run("bad config file", func(t *testing.T) {
+		path := filepath.Join(dir, "garbage.yaml")
+		assert.NoError(t, ioutil.WriteFile(path, []byte("{{{"), 0600))
+		_, err := ConfigFromFile(path)
+		assert.Contains(t, err.Error(), "error unmarshaling YAML file "+path)
+	})
+
+	t.Run("non existent config file", func(t *testing.T) {
+		_, err := ConfigFromFile(filepath.Join(dir, "non_existent.yaml"))
+		assert.Contains(t, err.Error(), "no such file or directory")
+	})
+}
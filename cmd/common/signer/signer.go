@@ -0,0 +1,55 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package signer
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/msp"
+	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
+	"github.com/pkg/errors"
+)
+
+// Config holds the configuration for
+// creation of a Signer
+type Config struct {
+	MSPID  string
+	MSPDir string
+}
+
+// Signer signs messages with the default
+// signing identity of the local MSP
+type Signer struct {
+	identity msp.SigningIdentity
+	Creator  []byte
+}
+
+// NewSigner loads the local MSP from the directory in the
+// given configuration and creates a Signer out of it
+func NewSigner(conf Config) (*Signer, error) {
+	if err := mspmgmt.LoadLocalMsp(conf.MSPDir, nil, conf.MSPID); err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("failed loading local MSP from %s", conf.MSPDir))
+	}
+	identity, err := mspmgmt.GetLocalMSP().GetDefaultSigningIdentity()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed obtaining the default signing identity")
+	}
+	creator, err := identity.Serialize()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed serializing the signing identity")
+	}
+	return &Signer{
+		identity: identity,
+		Creator:  creator,
+	}, nil
+}
+
+// Sign signs the given message with the signing
+// identity of the signer, and returns the signature
+func (si *Signer) Sign(msg []byte) ([]byte, error) {
+	return si.identity.Sign(msg)
+}
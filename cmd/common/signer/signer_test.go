@@ -0,0 +1,72 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package signer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/config/configtest"
+	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
+	"github.com/hyperledger/fabric/protos/msp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSigner(t *testing.T) {
+	mspDir, err := configtest.GetDevMspDir()
+	assert.NoError(t, err)
+	conf := Config{
+		MSPID:  "SampleOrg",
+		MSPDir: mspDir,
+	}
+
+	signer, err := NewSigner(conf)
+	assert.NoError(t, err)
+
+	sId := &msp.SerializedIdentity{}
+	assert.NoError(t, proto.Unmarshal(signer.Creator, sId))
+	assert.Equal(t, "SampleOrg", sId.Mspid)
+
+	// the signature verifies with the identity the signer serializes
+	identity, err := mspmgmt.GetLocalMSP().DeserializeIdentity(signer.Creator)
+	assert.NoError(t, err)
+	msg := []byte("foo")
+	sig, err := signer.Sign(msg)
+	assert.NoError(t, err)
+	assert.NoError(t, identity.Verify(msg, sig))
+	assert.Error(t, identity.Verify([]byte("bar"), sig))
+}
+
+func TestSignerBadConfig(t *testing.T) {
+	mspDir, err := configtest.GetDevMspDir()
+	assert.NoError(t, err)
+
+	for _, tc := range []struct {
+		name   string
+		conf   Config
+		errMsg string
+	}{
+		{
+			name:   "no MSP ID",
+			conf:   Config{MSPDir: mspDir},
+			errMsg: "the local MSP must have an ID",
+		},
+		{
+			name:   "non existent MSP directory",
+			conf:   Config{MSPID: "SampleOrg", MSPDir: filepath.Join("testdata", "non_existent_msp")},
+			errMsg: "could not load a valid signer certificate from directory",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			signer, err := NewSigner(tc.conf)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tc.errMsg)
+			assert.Nil(t, signer)
+		})
+	}
+}
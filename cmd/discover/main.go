@@ -0,0 +1,20 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"os"
+
+	"github.com/hyperledger/fabric/cmd/common"
+	"github.com/hyperledger/fabric/discovery/cmd"
+)
+
+func main() {
+	cli := common.NewCLI("discover", "Command line client for fabric discovery service")
+	discovery.AddCommands(cli)
+	cli.Run(os.Args[1:])
+}
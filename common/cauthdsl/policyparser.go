@@ -51,8 +51,62 @@ var (
 			RoleAdmin, RoleMember, RoleClient, RolePeer),
 	)
 	regexErr = regexp.MustCompile("^No parameter '([^']+)' found[.]$")
+
+	// regexIdemix matches principals over the attributes of Idemix
+	// credentials, formed as <MSP_ID>.idemix(<PREDICATES>)
+	regexIdemix       = regexp.MustCompile(`^([[:alnum:].-]+)[.]idemix\(([^()']+)\)$`)
+	regexIdemixEqual  = regexp.MustCompile(`^\s*([[:alnum:]_-]+)\s*==\s*([^|]+)$`)
+	regexIdemixMember = regexp.MustCompile(`^\s*([[:alnum:]_-]+)\s+in\s+(.+)$`)
 )
 
+// isPrincipal returns true if s is either a role or an Idemix principal
+func isPrincipal(s string) bool {
+	return regex.MatchString(s) || regexIdemix.MatchString(s)
+}
+
+// idemixPrincipal builds the principal for a string formed as
+// <MSP_ID>.idemix(<PREDICATE>[; <PREDICATE>]), where each predicate is
+// either <ATTRIBUTE> == <VALUE> or <ATTRIBUTE> in <VALUE>[|<VALUE>]
+func idemixPrincipal(principal string) (*msp.MSPPrincipal, error) {
+	subm := regexIdemix.FindStringSubmatch(principal)
+	if subm == nil {
+		return nil, fmt.Errorf("Error parsing principal %s", principal)
+	}
+
+	attributes := &msp.IdemixAttributes{MspIdentifier: subm[1]}
+	for _, predicate := range strings.Split(subm[2], ";") {
+		if equal := regexIdemixEqual.FindStringSubmatch(predicate); equal != nil {
+			attributes.Predicates = append(attributes.Predicates, &msp.IdemixAttributePredicate{
+				AttributeName: equal[1],
+				Type:          msp.IdemixAttributePredicate_EQUAL,
+				Values:        []string{strings.TrimSpace(equal[2])},
+			})
+			continue
+		}
+
+		member := regexIdemixMember.FindStringSubmatch(predicate)
+		if member == nil {
+			return nil, fmt.Errorf("Error parsing predicate '%s' of principal %s", strings.TrimSpace(predicate), principal)
+		}
+		values := strings.Split(member[2], "|")
+		for i := range values {
+			values[i] = strings.TrimSpace(values[i])
+			if values[i] == "" {
+				return nil, fmt.Errorf("Error parsing predicate '%s' of principal %s, empty value", strings.TrimSpace(predicate), principal)
+			}
+		}
+		attributes.Predicates = append(attributes.Predicates, &msp.IdemixAttributePredicate{
+			AttributeName: member[1],
+			Type:          msp.IdemixAttributePredicate_MEMBER,
+			Values:        values,
+		})
+	}
+
+	return &msp.MSPPrincipal{
+		PrincipalClassification: msp.MSPPrincipal_IDEMIX_ATTRIBUTES,
+		Principal:               utils.MarshalOrPanic(attributes)}, nil
+}
+
 // a stub function - it returns the same string as it's passed.
 // This will be evaluated by second/third passes to convert to a proto policy
 func outof(args ...interface{}) (interface{}, error) {
@@ -77,7 +131,7 @@ func outof(args ...interface{}) (interface{}, error) {
 		toret += ", "
 		switch t := arg.(type) {
 		case string:
-			if regex.MatchString(t) {
+			if isPrincipal(t) {
 				toret += "'" + t + "'"
 			} else {
 				toret += t
@@ -105,7 +159,7 @@ func firstPass(args ...interface{}) (interface{}, error) {
 		toret += ", "
 		switch t := arg.(type) {
 		case string:
-			if regex.MatchString(t) {
+			if isPrincipal(t) {
 				toret += "'" + t + "'"
 			} else {
 				toret += t
@@ -163,6 +217,19 @@ func secondPass(args ...interface{}) (interface{}, error) {
 		   <MSP_ID> . <ROLE>, where MSP_ID is the MSP identifier
 		   and ROLE is either a member, an admin, a client, a peer or an orderer*/
 		case string:
+			/* if it's formed as <MSP_ID> . idemix ( <PREDICATES> ),
+			   we build a principal over Idemix attributes */
+			if regexIdemix.MatchString(t) {
+				p, err := idemixPrincipal(t)
+				if err != nil {
+					return nil, err
+				}
+				ctx.principals = append(ctx.principals, p)
+				policies = append(policies, SignedBy(int32(ctx.IDNum)))
+				ctx.IDNum++
+				break
+			}
+
 			/* split the string */
 			subm := regex.FindAllStringSubmatch(t, -1)
 			if subm == nil || len(subm) != 1 || len(subm[0]) != 4 {
@@ -241,6 +308,15 @@ func newContext() *context {
 //	- ORG is a string (representing the MSP identifier)
 //	- ROLE takes the value of any of the RoleXXX constants representing
 //    the required role
+//
+// or, for holders of Idemix credentials, as:
+//
+// ORG.idemix(PRED[; PRED])
+//
+// where PRED is either
+//	- NAME == VALUE, requiring attribute NAME to be disclosed with value VALUE
+//	- NAME in VALUE[|VALUE], requiring attribute NAME to be one of the
+//    VALUEs, which the credential holder can prove without disclosing it
 func FromString(policy string) (*common.SignaturePolicyEnvelope, error) {
 	// first we translate the and/or business into outof gates
 	intermediate, err := govaluate.NewEvaluableExpressionWithFunctions(
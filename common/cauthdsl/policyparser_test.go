@@ -233,3 +233,53 @@ func TestBadStringsNoPanic(t *testing.T) {
 	_, err = FromString("OR('A.member', Bmember)")
 	assert.Error(t, err)
 }
+
+func TestIdemixAttributes(t *testing.T) {
+	p1, err := FromString("AND('A.member', OR('B.idemix(OU == ou1; EnrollmentID in alice | bob)', 'C.idemix(Role==admin)'))")
+	assert.NoError(t, err)
+
+	principals := make([]*msp.MSPPrincipal, 0)
+
+	principals = append(principals, &msp.MSPPrincipal{
+		PrincipalClassification: msp.MSPPrincipal_IDEMIX_ATTRIBUTES,
+		Principal: utils.MarshalOrPanic(&msp.IdemixAttributes{
+			MspIdentifier: "B",
+			Predicates: []*msp.IdemixAttributePredicate{
+				{AttributeName: "OU", Type: msp.IdemixAttributePredicate_EQUAL, Values: []string{"ou1"}},
+				{AttributeName: "EnrollmentID", Type: msp.IdemixAttributePredicate_MEMBER, Values: []string{"alice", "bob"}},
+			},
+		})})
+
+	principals = append(principals, &msp.MSPPrincipal{
+		PrincipalClassification: msp.MSPPrincipal_IDEMIX_ATTRIBUTES,
+		Principal: utils.MarshalOrPanic(&msp.IdemixAttributes{
+			MspIdentifier: "C",
+			Predicates: []*msp.IdemixAttributePredicate{
+				{AttributeName: "Role", Type: msp.IdemixAttributePredicate_EQUAL, Values: []string{"admin"}},
+			},
+		})})
+
+	principals = append(principals, &msp.MSPPrincipal{
+		PrincipalClassification: msp.MSPPrincipal_ROLE,
+		Principal:               utils.MarshalOrPanic(&msp.MSPRole{Role: msp.MSPRole_MEMBER, MspIdentifier: "A"})})
+
+	// the nested gate is evaluated first
+	p2 := &common.SignaturePolicyEnvelope{
+		Version:    0,
+		Rule:       And(SignedBy(2), Or(SignedBy(0), SignedBy(1))),
+		Identities: principals,
+	}
+
+	assert.Equal(t, p1, p2)
+}
+
+func TestIdemixAttributesBad(t *testing.T) {
+	_, err := FromString("OR('A.idemix(OU = ou1)')")
+	assert.EqualError(t, err, "Error parsing predicate 'OU = ou1' of principal A.idemix(OU = ou1)")
+	_, err = FromString("OR('A.idemix(EnrollmentID in alice||bob)')")
+	assert.EqualError(t, err, "Error parsing predicate 'EnrollmentID in alice||bob' of principal A.idemix(EnrollmentID in alice||bob), empty value")
+	_, err = FromString("OR('A.idemix(OU == ou1;)')")
+	assert.Error(t, err)
+	_, err = FromString("OR('A.idemix()')")
+	assert.Error(t, err)
+}
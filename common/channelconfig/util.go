@@ -104,11 +104,12 @@ func OrdererAddressesValue(addresses []string) *StandardConfigValue {
 
 // ConsensusTypeValue returns the config definition for the orderer consensus type.
 // It is a value for the /Channel/Orderer group.
-func ConsensusTypeValue(consensusType string) *StandardConfigValue {
+func ConsensusTypeValue(consensusType string, consensusMetadata []byte) *StandardConfigValue {
 	return &StandardConfigValue{
 		key: ConsensusTypeKey,
 		value: &ab.ConsensusType{
-			Type: consensusType,
+			Type:     consensusType,
+			Metadata: consensusMetadata,
 		},
 	}
 }
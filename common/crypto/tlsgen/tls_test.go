@@ -0,0 +1,92 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tlsgen
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createTLSService(t *testing.T, ca CA, host string) *tls.Config {
+	keyPair, err := ca.NewServerCertKeyPair(host)
+	assert.NoError(t, err)
+	cert, err := tls.X509KeyPair(keyPair.Cert, keyPair.Key)
+	assert.NoError(t, err)
+	tlsConf := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    x509.NewCertPool(),
+	}
+	tlsConf.ClientCAs.AppendCertsFromPEM(ca.CertBytes())
+	return tlsConf
+}
+
+func TestTLSCA(t *testing.T) {
+	// This test checks that the CA can create certificates
+	// and corresponding keys that are signed by itself
+
+	ca, err := NewCA()
+	assert.NoError(t, err)
+	assert.NotNil(t, ca)
+
+	srv := createTLSService(t, ca, "127.0.0.1")
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", srv)
+	assert.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			// Complete the handshake before closing the connection
+			conn.(*tls.Conn).Handshake()
+			conn.Close()
+		}
+	}()
+
+	probeTLS := func(kp *CertKeyPair) error {
+		cert, err := tls.X509KeyPair(kp.Cert, kp.Key)
+		assert.NoError(t, err)
+		tlsCfg := &tls.Config{
+			RootCAs:      x509.NewCertPool(),
+			Certificates: []tls.Certificate{cert},
+		}
+		tlsCfg.RootCAs.AppendCertsFromPEM(ca.CertBytes())
+		conn, err := tls.DialWithDialer(&net.Dialer{Timeout: time.Second}, "tcp", listener.Addr().String(), tlsCfg)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		// Read from the connection, in order to find out whether
+		// the server rejected our certificate
+		_, err = conn.Read(make([]byte, 1))
+		if err != nil && err.Error() == "EOF" {
+			return nil
+		}
+		return err
+	}
+
+	// Good path - use a cert key pair generated from the CA
+	// that the TLS server started with
+	kp, err := ca.NewClientCertKeyPair()
+	assert.NoError(t, err)
+	err = probeTLS(kp)
+	assert.NoError(t, err)
+
+	// Bad path - use a cert key pair generated from a foreign CA
+	foreignCA, _ := NewCA()
+	kp, err = foreignCA.NewClientCertKeyPair()
+	assert.NoError(t, err)
+	err = probeTLS(kp)
+	assert.Error(t, err)
+}
@@ -0,0 +1,81 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpadmin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/hyperledger/fabric/common/flogging"
+)
+
+var logger = flogging.MustGetLogger("flogging.httpadmin")
+
+// LogSpec is the JSON document exchanged with the SpecHandler
+type LogSpec struct {
+	Spec string `json:"spec,omitempty"`
+}
+
+// ErrorResponse is the JSON document returned by the SpecHandler on failures
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// SpecHandler is an http.Handler that serves the current logging specification
+// on GET requests and activates the logging specification supplied in the body
+// of PUT requests
+type SpecHandler struct {
+	// ActivateSpec activates the supplied logging specification
+	ActivateSpec func(spec string) error
+	// Spec returns the active logging specification
+	Spec func() string
+}
+
+// NewSpecHandler returns a SpecHandler backed by the flogging package
+func NewSpecHandler() *SpecHandler {
+	return &SpecHandler{
+		ActivateSpec: flogging.ActivateSpec,
+		Spec:         flogging.Spec,
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (h *SpecHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+		h.sendResponse(resp, http.StatusOK, &LogSpec{Spec: h.Spec()})
+
+	case http.MethodPut:
+		var logSpec LogSpec
+		decoder := json.NewDecoder(req.Body)
+		if err := decoder.Decode(&logSpec); err != nil {
+			h.sendResponse(resp, http.StatusBadRequest, &ErrorResponse{Error: err.Error()})
+			return
+		}
+		req.Body.Close()
+
+		if err := h.ActivateSpec(logSpec.Spec); err != nil {
+			h.sendResponse(resp, http.StatusBadRequest, &ErrorResponse{Error: err.Error()})
+			return
+		}
+		logger.Infof("Logging specification changed to '%s'", logSpec.Spec)
+		resp.WriteHeader(http.StatusNoContent)
+
+	default:
+		err := fmt.Errorf("invalid request method: %s", req.Method)
+		h.sendResponse(resp, http.StatusBadRequest, &ErrorResponse{Error: err.Error()})
+	}
+}
+
+func (h *SpecHandler) sendResponse(resp http.ResponseWriter, code int, payload interface{}) {
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(code)
+	if err := json.NewEncoder(resp).Encode(payload); err != nil {
+		logger.Errorf("Failed encoding response: %s", err)
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpadmin
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestSpecHandler() (*SpecHandler, *string) {
+	spec := "info"
+	return &SpecHandler{
+		ActivateSpec: func(s string) error {
+			if s == "bad-spec" {
+				return errors.New("invalid logging specification")
+			}
+			spec = s
+			return nil
+		},
+		Spec: func() string { return spec },
+	}, &spec
+}
+
+func TestGetSpec(t *testing.T) {
+	h, _ := newTestSpecHandler()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/logspec", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"spec": "info"}`, rec.Body.String())
+}
+
+func TestPutSpec(t *testing.T) {
+	h, spec := newTestSpecHandler()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/logspec", strings.NewReader(`{"spec": "gossip=debug:warning"}`)))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "gossip=debug:warning", *spec)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/logspec", strings.NewReader(`{"spec": "bad-spec"}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.JSONEq(t, `{"error": "invalid logging specification"}`, rec.Body.String())
+	assert.Equal(t, "gossip=debug:warning", *spec)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/logspec", strings.NewReader(`{not-json`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "invalid character")
+}
+
+func TestInvalidMethod(t *testing.T) {
+	h, _ := newTestSpecHandler()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/logspec", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.JSONEq(t, `{"error": "invalid request method: POST"}`, rec.Body.String())
+}
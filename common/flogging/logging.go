@@ -24,6 +24,7 @@ import (
 	"sync"
 
 	"github.com/op/go-logging"
+	"github.com/pkg/errors"
 )
 
 const (
@@ -39,6 +40,7 @@ var (
 
 	modules          map[string]string // Holds the map of all modules and their respective log level
 	peerStartModules map[string]string
+	activeSpec       string // Holds the logging specification that was last activated
 
 	lock sync.RWMutex
 	once sync.Once
@@ -179,6 +181,10 @@ func InitFromSpec(spec string) string {
 
 	logging.SetLevel(levelAll, "") // set the logging level for all modules
 
+	lock.Lock()
+	activeSpec = spec
+	lock.Unlock()
+
 	// iterate through modules to reload their level in the modules map based on
 	// the new default level
 	for k := range modules {
@@ -190,6 +196,65 @@ func InitFromSpec(spec string) string {
 	return levelAll.String()
 }
 
+// Spec returns the logging specification that was last activated. An empty
+// string denotes that the default logging level applies to all modules.
+func Spec() string {
+	lock.RLock()
+	defer lock.RUnlock()
+	return activeSpec
+}
+
+// ActivateSpec validates the supplied logging specification and, if valid,
+// replaces the current logging configuration with it. Unlike InitFromSpec, the
+// levels of the modules that are not mentioned in the specification are reset
+// to the default level of the specification, and an invalid specification is
+// rejected as a whole.
+func ActivateSpec(spec string) error {
+	levelAll, err := validateSpec(spec)
+	if err != nil {
+		return err
+	}
+
+	lock.RLock()
+	for module := range modules {
+		logging.SetLevel(levelAll, module)
+	}
+	lock.RUnlock()
+
+	InitFromSpec(spec)
+	return nil
+}
+
+// validateSpec checks that the supplied logging specification is well formed
+// and returns the default level that it defines
+func validateSpec(spec string) (logging.Level, error) {
+	levelAll := defaultLevel
+	if spec == "" {
+		return levelAll, nil
+	}
+	for _, field := range strings.Split(spec, ":") {
+		split := strings.Split(field, "=")
+		switch len(split) {
+		case 1:
+			level, err := logging.LogLevel(field)
+			if err != nil {
+				return levelAll, errors.Errorf("invalid logging specification '%s': bad level '%s'", spec, field)
+			}
+			levelAll = level
+		case 2:
+			if split[0] == "" {
+				return levelAll, errors.Errorf("invalid logging specification '%s': no module specified in '%s'", spec, field)
+			}
+			if _, err := logging.LogLevel(split[1]); err != nil {
+				return levelAll, errors.Errorf("invalid logging specification '%s': bad level '%s'", spec, split[1])
+			}
+		default:
+			return levelAll, errors.Errorf("invalid logging specification '%s': bad segment '%s'", spec, field)
+		}
+	}
+	return levelAll, nil
+}
+
 // SetPeerStartupModulesMap saves the modules and their log levels.
 // this function should only be called at the end of peer startup.
 func SetPeerStartupModulesMap() {
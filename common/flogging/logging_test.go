@@ -175,3 +175,32 @@ func ExampleInitBackend() {
 	// Output:
 	// 1970-01-01 00:00:00.000 UTC [testModule] ExampleInitBackend -> INFO 001 test output
 }
+
+func TestActivateSpec(t *testing.T) {
+	defer flogging.Reset()
+
+	flogging.MustGetLogger("activatespec1")
+	flogging.MustGetLogger("activatespec2")
+
+	assert.NoError(t, flogging.ActivateSpec("activatespec1=debug:error"))
+	assert.Equal(t, "activatespec1=debug:error", flogging.Spec())
+	assert.Equal(t, "DEBUG", flogging.GetModuleLevel("activatespec1"))
+	assert.Equal(t, "ERROR", flogging.GetModuleLevel("activatespec2"))
+
+	// the modules that are not mentioned in the spec fall back to its default level
+	assert.NoError(t, flogging.ActivateSpec("activatespec2=warning:info"))
+	assert.Equal(t, "activatespec2=warning:info", flogging.Spec())
+	assert.Equal(t, "INFO", flogging.GetModuleLevel("activatespec1"))
+	assert.Equal(t, "WARNING", flogging.GetModuleLevel("activatespec2"))
+
+	// an invalid spec is rejected as a whole
+	for _, spec := range []string{"foo", "activatespec1=foo", "=debug", "activatespec1=debug=info"} {
+		assert.Error(t, flogging.ActivateSpec(spec), "spec '%s' should be rejected", spec)
+		assert.Equal(t, "activatespec2=warning:info", flogging.Spec())
+		assert.Equal(t, "INFO", flogging.GetModuleLevel("activatespec1"))
+	}
+
+	assert.NoError(t, flogging.ActivateSpec(""))
+	assert.Equal(t, "", flogging.Spec())
+	assert.Equal(t, flogging.DefaultLevel(), flogging.GetModuleLevel("activatespec2"))
+}
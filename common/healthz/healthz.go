@@ -0,0 +1,161 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package healthz
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// StatusOK is reported when all the registered checkers succeed
+	StatusOK = "OK"
+	// StatusUnavailable is reported when at least one of the registered checkers fails
+	StatusUnavailable = "Service Unavailable"
+
+	defaultTimeout = 30 * time.Second
+)
+
+// HealthChecker is implemented by the components that want to take part in the
+// health checks of the process. HealthCheck should return nil if the component
+// is healthy and an error describing the problem otherwise. The supplied context
+// is canceled when the timeout of the health check elapses.
+type HealthChecker interface {
+	HealthCheck(context.Context) error
+}
+
+// FailedCheck represents a failed health check of a component
+type FailedCheck struct {
+	Component string `json:"component"`
+	Reason    string `json:"reason"`
+}
+
+// HealthStatus is the JSON document returned by the health handler
+type HealthStatus struct {
+	Status       string        `json:"status"`
+	Time         time.Time     `json:"time"`
+	FailedChecks []FailedCheck `json:"failed_checks,omitempty"`
+}
+
+// HealthHandler is an http.Handler that runs the health checks of all the
+// registered components and reports the aggregated result
+type HealthHandler struct {
+	mutex    sync.RWMutex
+	checkers map[string]HealthChecker
+	now      func() time.Time
+	timeout  time.Duration
+}
+
+// NewHealthHandler returns a new HealthHandler without any registered checker
+func NewHealthHandler() *HealthHandler {
+	return &HealthHandler{
+		checkers: make(map[string]HealthChecker),
+		now:      time.Now,
+		timeout:  defaultTimeout,
+	}
+}
+
+// RegisterChecker registers the HealthChecker of the given component.
+// An error is returned if a checker is already registered for the component
+func (h *HealthHandler) RegisterChecker(component string, checker HealthChecker) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if _, exists := h.checkers[component]; exists {
+		return errors.Errorf("health checker for component [%s] is already registered", component)
+	}
+	h.checkers[component] = checker
+	return nil
+}
+
+// DeregisterChecker removes the HealthChecker of the given component, if any
+func (h *HealthHandler) DeregisterChecker(component string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	delete(h.checkers, component)
+}
+
+// SetTimeout sets the duration after which a health check that has not
+// returned is considered as failed
+func (h *HealthHandler) SetTimeout(timeout time.Duration) {
+	h.timeout = timeout
+}
+
+// ServeHTTP implements http.Handler. Only GET requests are accepted. The response
+// status code is 200 if all the checks succeed and 503 otherwise
+func (h *HealthHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), h.timeout)
+	defer cancel()
+
+	status := HealthStatus{Status: StatusOK, Time: h.now()}
+	status.FailedChecks = h.RunChecks(ctx)
+	statusCode := http.StatusOK
+	if len(status.FailedChecks) > 0 {
+		status.Status = StatusUnavailable
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(statusCode)
+	json.NewEncoder(rw).Encode(status)
+}
+
+// RunChecks runs the health checks of all the registered components concurrently
+// and returns the failed ones, sorted by component. A check that does not return
+// before the context is done is reported as failed
+func (h *HealthHandler) RunChecks(ctx context.Context) []FailedCheck {
+	h.mutex.RLock()
+	checkers := make(map[string]HealthChecker, len(h.checkers))
+	for component, checker := range h.checkers {
+		checkers[component] = checker
+	}
+	h.mutex.RUnlock()
+
+	type result struct {
+		component string
+		err       error
+	}
+	results := make(chan result, len(checkers))
+	for component, checker := range checkers {
+		go func(component string, checker HealthChecker) {
+			results <- result{component: component, err: checker.HealthCheck(ctx)}
+		}(component, checker)
+	}
+
+	var failedChecks []FailedCheck
+	pending := make(map[string]struct{}, len(checkers))
+	for component := range checkers {
+		pending[component] = struct{}{}
+	}
+	for len(pending) > 0 {
+		select {
+		case res := <-results:
+			delete(pending, res.component)
+			if res.err != nil {
+				failedChecks = append(failedChecks, FailedCheck{Component: res.component, Reason: res.err.Error()})
+			}
+		case <-ctx.Done():
+			for component := range pending {
+				failedChecks = append(failedChecks, FailedCheck{Component: component, Reason: "health check timed out"})
+			}
+			pending = nil
+		}
+	}
+
+	sort.Slice(failedChecks, func(i, j int) bool { return failedChecks[i].Component < failedChecks[j].Component })
+	return failedChecks
+}
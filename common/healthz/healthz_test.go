@@ -0,0 +1,99 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package healthz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type checkerFunc func(context.Context) error
+
+func (f checkerFunc) HealthCheck(ctx context.Context) error {
+	return f(ctx)
+}
+
+func healthy() HealthChecker {
+	return checkerFunc(func(context.Context) error { return nil })
+}
+
+func failing(reason string) HealthChecker {
+	return checkerFunc(func(context.Context) error { return errors.New(reason) })
+}
+
+func TestRegisterChecker(t *testing.T) {
+	h := NewHealthHandler()
+	assert.NoError(t, h.RegisterChecker("component1", healthy()))
+	err := h.RegisterChecker("component1", healthy())
+	assert.EqualError(t, err, "health checker for component [component1] is already registered")
+
+	h.DeregisterChecker("component1")
+	assert.NoError(t, h.RegisterChecker("component1", healthy()))
+}
+
+func TestServeHTTP(t *testing.T) {
+	now := time.Date(2018, 9, 1, 10, 0, 0, 0, time.UTC)
+	h := NewHealthHandler()
+	h.now = func() time.Time { return now }
+
+	// without any checker the process is healthy
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	status := &HealthStatus{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), status))
+	assert.Equal(t, &HealthStatus{Status: StatusOK, Time: now}, status)
+
+	assert.NoError(t, h.RegisterChecker("component1", healthy()))
+	assert.NoError(t, h.RegisterChecker("component3", failing("component3 is down")))
+	assert.NoError(t, h.RegisterChecker("component2", failing("component2 is down")))
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	status = &HealthStatus{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), status))
+	assert.Equal(t, &HealthStatus{
+		Status: StatusUnavailable,
+		Time:   now,
+		FailedChecks: []FailedCheck{
+			{Component: "component2", Reason: "component2 is down"},
+			{Component: "component3", Reason: "component3 is down"},
+		},
+	}, status)
+
+	// only GET is allowed
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/healthz", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestHealthCheckTimeout(t *testing.T) {
+	h := NewHealthHandler()
+	h.SetTimeout(50 * time.Millisecond)
+	stuck := make(chan struct{})
+	defer close(stuck)
+	assert.NoError(t, h.RegisterChecker("stuck", checkerFunc(func(context.Context) error {
+		<-stuck
+		return nil
+	})))
+	assert.NoError(t, h.RegisterChecker("healthy", healthy()))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	status := &HealthStatus{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), status))
+	assert.Equal(t, []FailedCheck{{Component: "stuck", Reason: "health check timed out"}}, status.FailedChecks)
+}
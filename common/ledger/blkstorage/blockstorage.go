@@ -47,14 +47,26 @@ var (
 	ErrNotFoundInIndex = errors.New("Entry not found in index")
 	// ErrAttrNotIndexed is used to indicate that an attribute is not indexed
 	ErrAttrNotIndexed = errors.New("Attribute not indexed")
+	// ErrPruned is used to indicate that the requested data was removed from the block store by pruning
+	ErrPruned = errors.New("Requested data has been pruned")
 )
 
+// TxValidationInfo carries the validation code of a transaction. This is used for carrying
+// the validation codes of the transactions of the blocks that are not included in a ledger snapshot
+type TxValidationInfo struct {
+	TxID           string
+	ValidationCode peer.TxValidationCode
+}
+
 // BlockStoreProvider provides an handle to a BlockStore
 type BlockStoreProvider interface {
 	CreateBlockStore(ledgerid string) (BlockStore, error)
 	OpenBlockStore(ledgerid string) (BlockStore, error)
 	Exists(ledgerid string) (bool, error)
 	List() ([]string, error)
+	// Drop removes the blocks and the index of the BlockStore with the given id.
+	// The BlockStore is expected to be shut down before invoking this function
+	Drop(ledgerid string) error
 	Close()
 }
 
@@ -71,5 +83,18 @@ type BlockStore interface {
 	RetrieveTxByBlockNumTranNum(blockNum uint64, tranNum uint64) (*common.Envelope, error)
 	RetrieveBlockByTxID(txID string) (*common.Block, error)
 	RetrieveTxValidationCodeByTxID(txID string) (peer.TxValidationCode, error)
+	// GetFirstAvailableBlockNum returns the number of the first block that has not been pruned
+	GetFirstAvailableBlockNum() (uint64, error)
+	// Prune removes the blocks that precede the block with number `retainFromBlockNum`.
+	// Blocks are removed at the granularity of block files, hence some of these blocks may be retained
+	Prune(retainFromBlockNum uint64) error
+	// Rollback removes the blocks that follow the block with number `targetBlockNum`
+	Rollback(targetBlockNum uint64) error
+	// ExportTxValidationInfo invokes the given function for the validation code of each indexed transaction
+	ExportTxValidationInfo(fn func(*TxValidationInfo) error) error
+	// BootstrapFromSnapshot prepares an empty block store for receiving the blocks starting from the
+	// block with number `firstBlockNum`. The transactions returned by `nextTxInfo` (until it returns nil)
+	// are indexed as the transactions of the preceding blocks, which are treated as pruned
+	BootstrapFromSnapshot(firstBlockNum uint64, nextTxInfo func() (*TxValidationInfo, error)) error
 	Shutdown()
 }
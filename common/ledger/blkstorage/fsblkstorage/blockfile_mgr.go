@@ -19,6 +19,7 @@ package fsblkstorage
 import (
 	"fmt"
 	"math"
+	"os"
 	"sync"
 	"sync/atomic"
 
@@ -53,6 +54,8 @@ type blockfileMgr struct {
 	cpInfoCond        *sync.Cond
 	currentFileWriter *blockfileWriter
 	bcInfo            atomic.Value
+	pruningInfo       atomic.Value
+	pruneLock         sync.Mutex
 }
 
 /*
@@ -144,6 +147,11 @@ func newBlockfileMgr(id string, conf *Conf, indexConfig *blkstorage.IndexConfig,
 	// Create a new KeyValue store database handler for the blocks index in the keyvalue database
 	mgr.index = newBlockIndex(indexConfig, indexStore)
 
+	// Determine the first block file (and block) that has not been pruned
+	if err = mgr.initPruningInfo(); err != nil {
+		panic(fmt.Sprintf("Could not load pruning info: %s", err))
+	}
+
 	// Update the manager with the checkpoint info and the file writer
 	mgr.cpInfo = cpInfo
 	mgr.currentFileWriter = currentFileWriter
@@ -354,6 +362,10 @@ func (mgr *blockfileMgr) syncIndex() error {
 		startingBlockNum = lastBlockIndexed + 1
 	} else {
 		logger.Debugf("No block indexed, Last block present in block files=[%d]", mgr.cpInfo.lastBlockNumber)
+		// start from the first block file that has not been pruned
+		pi := mgr.getPruningInfo()
+		startFileNum = pi.firstFileSuffixNum
+		startingBlockNum = pi.firstBlockNum
 	}
 
 	logger.Infof("Start building index from block [%d] to last block [%d]", startingBlockNum, mgr.cpInfo.lastBlockNumber)
@@ -458,9 +470,12 @@ func (mgr *blockfileMgr) retrieveBlockByNumber(blockNum uint64) (*common.Block,
 		blockNum = mgr.getBlockchainInfo().Height - 1
 	}
 
+	if err := mgr.checkBlockNotPruned(blockNum); err != nil {
+		return nil, err
+	}
 	loc, err := mgr.index.getBlockLocByBlockNum(blockNum)
 	if err != nil {
-		return nil, err
+		return nil, mgr.blockPrunedOr(blockNum, err)
 	}
 	return mgr.fetchBlock(loc)
 }
@@ -483,9 +498,12 @@ func (mgr *blockfileMgr) retrieveTxValidationCodeByTxID(txID string) (peer.TxVal
 
 func (mgr *blockfileMgr) retrieveBlockHeaderByNumber(blockNum uint64) (*common.BlockHeader, error) {
 	logger.Debugf("retrieveBlockHeaderByNumber() - blockNum = [%d]", blockNum)
+	if err := mgr.checkBlockNotPruned(blockNum); err != nil {
+		return nil, err
+	}
 	loc, err := mgr.index.getBlockLocByBlockNum(blockNum)
 	if err != nil {
-		return nil, err
+		return nil, mgr.blockPrunedOr(blockNum, err)
 	}
 	blockBytes, err := mgr.fetchBlockBytes(loc)
 	if err != nil {
@@ -513,9 +531,12 @@ func (mgr *blockfileMgr) retrieveTransactionByID(txID string) (*common.Envelope,
 
 func (mgr *blockfileMgr) retrieveTransactionByBlockNumTranNum(blockNum uint64, tranNum uint64) (*common.Envelope, error) {
 	logger.Debugf("retrieveTransactionByBlockNumTranNum() - blockNum = [%d], tranNum = [%d]", blockNum, tranNum)
+	if err := mgr.checkBlockNotPruned(blockNum); err != nil {
+		return nil, err
+	}
 	loc, err := mgr.index.getTXLocByBlockNumTranNum(blockNum, tranNum)
 	if err != nil {
-		return nil, err
+		return nil, mgr.blockPrunedOr(blockNum, err)
 	}
 	return mgr.fetchTransactionEnvelope(loc)
 }
@@ -544,9 +565,12 @@ func (mgr *blockfileMgr) fetchTransactionEnvelope(lp *fileLocPointer) (*common.E
 }
 
 func (mgr *blockfileMgr) fetchBlockBytes(lp *fileLocPointer) ([]byte, error) {
+	if err := mgr.checkFileNotPruned(lp.fileSuffixNum); err != nil {
+		return nil, err
+	}
 	stream, err := newBlockfileStream(mgr.rootDir, lp.fileSuffixNum, int64(lp.offset))
 	if err != nil {
-		return nil, err
+		return nil, mgr.filePrunedOr(lp.fileSuffixNum, err)
 	}
 	defer stream.close()
 	b, err := stream.nextBlockBytes()
@@ -557,10 +581,13 @@ func (mgr *blockfileMgr) fetchBlockBytes(lp *fileLocPointer) ([]byte, error) {
 }
 
 func (mgr *blockfileMgr) fetchRawBytes(lp *fileLocPointer) ([]byte, error) {
+	if err := mgr.checkFileNotPruned(lp.fileSuffixNum); err != nil {
+		return nil, err
+	}
 	filePath := deriveBlockfilePath(mgr.rootDir, lp.fileSuffixNum)
 	reader, err := newBlockfileReader(filePath)
 	if err != nil {
-		return nil, err
+		return nil, mgr.filePrunedOr(lp.fileSuffixNum, err)
 	}
 	defer reader.close()
 	b, err := reader.read(lp.offset, lp.bytesLength)
@@ -570,6 +597,44 @@ func (mgr *blockfileMgr) fetchRawBytes(lp *fileLocPointer) ([]byte, error) {
 	return b, nil
 }
 
+// checkBlockNotPruned returns blkstorage.ErrPruned if the given block has been pruned
+func (mgr *blockfileMgr) checkBlockNotPruned(blockNum uint64) error {
+	if pi := mgr.getPruningInfo(); blockNum < pi.firstBlockNum {
+		logger.Debugf("Block [%d] has been pruned, first available block is [%d]", blockNum, pi.firstBlockNum)
+		return blkstorage.ErrPruned
+	}
+	return nil
+}
+
+// checkFileNotPruned returns blkstorage.ErrPruned if the given block file has been pruned
+func (mgr *blockfileMgr) checkFileNotPruned(fileNum int) error {
+	if pi := mgr.getPruningInfo(); fileNum < pi.firstFileSuffixNum {
+		logger.Debugf("Block file [%d] has been pruned, first available block file is [%d]", fileNum, pi.firstFileSuffixNum)
+		return blkstorage.ErrPruned
+	}
+	return nil
+}
+
+// blockPrunedOr returns blkstorage.ErrPruned in place of the error of a lookup of the given
+// block if the block has been pruned after the lookup started, and the error itself otherwise
+func (mgr *blockfileMgr) blockPrunedOr(blockNum uint64, err error) error {
+	if prunedErr := mgr.checkBlockNotPruned(blockNum); prunedErr != nil {
+		return prunedErr
+	}
+	return err
+}
+
+// filePrunedOr returns blkstorage.ErrPruned in place of the error of opening the given block
+// file if the file has been removed by a pruning that ran concurrently with the read
+func (mgr *blockfileMgr) filePrunedOr(fileNum int, err error) error {
+	if os.IsNotExist(err) {
+		if prunedErr := mgr.checkFileNotPruned(fileNum); prunedErr != nil {
+			return prunedErr
+		}
+	}
+	return err
+}
+
 //Get the current checkpoint information that is stored in the database
 func (mgr *blockfileMgr) loadCurrentInfo() (*checkpointInfo, error) {
 	var b []byte
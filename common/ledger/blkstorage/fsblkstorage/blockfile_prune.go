@@ -0,0 +1,239 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+)
+
+var (
+	blkMgrPruningInfoKey = []byte("blkMgrPruningInfo")
+)
+
+// pruningInfo tracks the first block file (and the first block in it)
+// that is still available after the older block files have been pruned
+type pruningInfo struct {
+	firstFileSuffixNum int
+	firstBlockNum      uint64
+}
+
+func (i *pruningInfo) marshal() ([]byte, error) {
+	buffer := proto.NewBuffer([]byte{})
+	var err error
+	if err = buffer.EncodeVarint(uint64(i.firstFileSuffixNum)); err != nil {
+		return nil, err
+	}
+	if err = buffer.EncodeVarint(i.firstBlockNum); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func (i *pruningInfo) unmarshal(b []byte) error {
+	buffer := proto.NewBuffer(b)
+	var val uint64
+	var err error
+
+	if val, err = buffer.DecodeVarint(); err != nil {
+		return err
+	}
+	i.firstFileSuffixNum = int(val)
+
+	if val, err = buffer.DecodeVarint(); err != nil {
+		return err
+	}
+	i.firstBlockNum = val
+	return nil
+}
+
+func (i *pruningInfo) String() string {
+	return fmt.Sprintf("firstFileSuffixNum=[%d], firstBlockNum=[%d]", i.firstFileSuffixNum, i.firstBlockNum)
+}
+
+// initPruningInfo loads the pruning info from the db. If the db does not carry it
+// (i.e., the block store has never been pruned or the db has been lost), the pruning
+// info is constructed from the oldest block file present on the file system.
+// Block files that precede the first available file, left over by a crash
+// in the middle of pruning, are removed.
+func (mgr *blockfileMgr) initPruningInfo() error {
+	pi, err := mgr.loadPruningInfo()
+	if err != nil {
+		return err
+	}
+	if pi == nil {
+		if pi, err = constructPruningInfoFromBlockFiles(mgr.rootDir); err != nil {
+			return err
+		}
+	}
+	if err := removeBlockfilesBefore(mgr.rootDir, pi.firstFileSuffixNum); err != nil {
+		return err
+	}
+	logger.Debugf("Pruning info = %s", pi)
+	mgr.pruningInfo.Store(pi)
+	return nil
+}
+
+func (mgr *blockfileMgr) getPruningInfo() *pruningInfo {
+	return mgr.pruningInfo.Load().(*pruningInfo)
+}
+
+func (mgr *blockfileMgr) loadPruningInfo() (*pruningInfo, error) {
+	var b []byte
+	var err error
+	if b, err = mgr.db.Get(blkMgrPruningInfoKey); b == nil || err != nil {
+		return nil, err
+	}
+	i := &pruningInfo{}
+	if err = i.unmarshal(b); err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+// prune removes all the block files that contain only blocks preceding the block
+// `retainFromBlockNum`. The file that contains `retainFromBlockNum` is retained
+// and, hence, the current file is never removed. Files are removed oldest first;
+// for each file, the index entries of its blocks and the pruning info are updated
+// atomically before the file is deleted, so that a crash leaves the store consistent.
+// The index entries keyed by transaction ID are retained so that the validation
+// code of pruned transactions remains available for duplicate detection.
+func (mgr *blockfileMgr) prune(retainFromBlockNum uint64) error {
+	mgr.pruneLock.Lock()
+	defer mgr.pruneLock.Unlock()
+
+	if height := mgr.getBlockchainInfo().Height; retainFromBlockNum >= height {
+		return fmt.Errorf("Cannot prune up to block [%d], the blockchain height is [%d]", retainFromBlockNum, height)
+	}
+	pi := mgr.getPruningInfo()
+	if retainFromBlockNum <= pi.firstBlockNum {
+		logger.Debugf("Nothing to prune, block [%d] precedes the first available block [%d]", retainFromBlockNum, pi.firstBlockNum)
+		return nil
+	}
+	loc, err := mgr.index.getBlockLocByBlockNum(retainFromBlockNum)
+	if err != nil {
+		return err
+	}
+	logger.Infof("Pruning block files [%d] to [%d] in order to retain blocks from block [%d]",
+		pi.firstFileSuffixNum, loc.fileSuffixNum-1, retainFromBlockNum)
+	for fileNum := pi.firstFileSuffixNum; fileNum < loc.fileSuffixNum; fileNum++ {
+		if err := mgr.pruneBlockfile(fileNum); err != nil {
+			return err
+		}
+	}
+	logger.Infof("Finished pruning. Pruning info = %s", mgr.getPruningInfo())
+	return nil
+}
+
+func (mgr *blockfileMgr) pruneBlockfile(fileNum int) error {
+	stream, err := newBlockfileStream(mgr.rootDir, fileNum, 0)
+	if err != nil {
+		return err
+	}
+	defer stream.close()
+
+	batch := leveldbhelper.NewUpdateBatch()
+	newPruningInfo := &pruningInfo{firstFileSuffixNum: fileNum + 1, firstBlockNum: mgr.getPruningInfo().firstBlockNum}
+	for {
+		blockBytes, err := stream.nextBlockBytes()
+		if err != nil {
+			return err
+		}
+		if blockBytes == nil {
+			break
+		}
+		info, err := extractSerializedBlockInfo(blockBytes)
+		if err != nil {
+			return err
+		}
+		mgr.index.removeBlockIndex(info.blockHeader.Number, info.blockHeader.Hash(), len(info.txOffsets), batch)
+		newPruningInfo.firstBlockNum = info.blockHeader.Number + 1
+	}
+
+	b, err := newPruningInfo.marshal()
+	if err != nil {
+		return err
+	}
+	batch.Put(blkMgrPruningInfoKey, b)
+	if err := mgr.db.WriteBatch(batch, true); err != nil {
+		return err
+	}
+	mgr.pruningInfo.Store(newPruningInfo)
+	logger.Debugf("Removing block file [%d], pruning info = %s", fileNum, newPruningInfo)
+	return os.Remove(deriveBlockfilePath(mgr.rootDir, fileNum))
+}
+
+// constructPruningInfoFromBlockFiles derives the pruning info from the oldest block file
+// present in the root dir
+func constructPruningInfoFromBlockFiles(rootDir string) (*pruningInfo, error) {
+	firstFileNum, err := retrieveFirstFileSuffix(rootDir)
+	if err != nil {
+		return nil, err
+	}
+	if firstFileNum <= 0 {
+		return &pruningInfo{}, nil
+	}
+	stream, err := newBlockfileStream(rootDir, firstFileNum, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.close()
+	blockBytes, err := stream.nextBlockBytes()
+	if err != nil {
+		return nil, err
+	}
+	if blockBytes == nil {
+		return nil, fmt.Errorf("No block found in the first block file [%d]", firstFileNum)
+	}
+	info, err := extractSerializedBlockInfo(blockBytes)
+	if err != nil {
+		return nil, err
+	}
+	return &pruningInfo{firstFileSuffixNum: firstFileNum, firstBlockNum: info.blockHeader.Number}, nil
+}
+
+func retrieveFirstFileSuffix(rootDir string) (int, error) {
+	smallestFileNum := -1
+	filesInfo, err := ioutil.ReadDir(rootDir)
+	if err != nil {
+		return -1, err
+	}
+	for _, fileInfo := range filesInfo {
+		name := fileInfo.Name()
+		if fileInfo.IsDir() || !isBlockFileName(name) {
+			continue
+		}
+		fileNum, err := strconv.Atoi(strings.TrimPrefix(name, blockfilePrefix))
+		if err != nil {
+			return -1, err
+		}
+		if smallestFileNum == -1 || fileNum < smallestFileNum {
+			smallestFileNum = fileNum
+		}
+	}
+	return smallestFileNum, nil
+}
+
+func removeBlockfilesBefore(rootDir string, fileNum int) error {
+	firstFileNum, err := retrieveFirstFileSuffix(rootDir)
+	if err != nil || firstFileNum < 0 {
+		return err
+	}
+	for i := firstFileNum; i < fileNum; i++ {
+		logger.Infof("Removing block file [%d] left over by an interrupted pruning", i)
+		if err := os.Remove(deriveBlockfilePath(rootDir, i)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"os"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/hyperledger/fabric/protos/common"
+)
+
+func TestBlockfileMgrPrune(t *testing.T) {
+	allBlocks := testutil.ConstructTestBlocks(t, 110)
+	blocks := allBlocks[:100]
+	env := newTestEnv(t, NewConf(testPath(), blockfileSizeFor(t, blocks[:20])))
+	defer env.Cleanup()
+	ledgerid := "testLedger"
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, ledgerid)
+	blkfileMgr := blkfileMgrWrapper.blockfileMgr
+	blkfileMgrWrapper.addBlocks(blocks)
+	testutil.AssertEquals(t, blkfileMgr.getPruningInfo(), &pruningInfo{})
+
+	// pruning beyond the last block is not allowed
+	testutil.AssertError(t, blkfileMgr.prune(100), "Expected an error while pruning beyond the last block")
+
+	testutil.AssertNoError(t, blkfileMgr.prune(50), "Error while pruning")
+	pi := blkfileMgr.getPruningInfo()
+	testutil.AssertEquals(t, pi.firstBlockNum > 0 && pi.firstBlockNum <= 50, true)
+	testutil.AssertEquals(t, pi.firstFileSuffixNum > 0, true)
+	for fileNum := 0; fileNum < pi.firstFileSuffixNum; fileNum++ {
+		exists, _, err := util.FileExists(deriveBlockfilePath(blkfileMgr.rootDir, fileNum))
+		testutil.AssertNoError(t, err, "")
+		testutil.AssertEquals(t, exists, false)
+	}
+	checkPrunedBlocks(t, blkfileMgr, blocks[:pi.firstBlockNum])
+	blkfileMgrWrapper.testGetBlockByNumber(blocks[pi.firstBlockNum:], pi.firstBlockNum)
+	blkfileMgrWrapper.testGetBlockByHash(blocks[pi.firstBlockNum:])
+
+	// pruning up to a block that is already pruned is a no-op
+	testutil.AssertNoError(t, blkfileMgr.prune(10), "Error while pruning")
+	testutil.AssertEquals(t, blkfileMgr.getPruningInfo(), pi)
+	blkfileMgrWrapper.close()
+
+	// the pruning info survives a restart and the store keeps accepting blocks
+	blkfileMgrWrapper = newTestBlockfileWrapper(env, ledgerid)
+	defer blkfileMgrWrapper.close()
+	blkfileMgr = blkfileMgrWrapper.blockfileMgr
+	testutil.AssertEquals(t, blkfileMgr.getPruningInfo(), pi)
+	checkPrunedBlocks(t, blkfileMgr, blocks[:pi.firstBlockNum])
+	blkfileMgrWrapper.addBlocks(allBlocks[100:])
+	blkfileMgrWrapper.testGetBlockByNumber(allBlocks[pi.firstBlockNum:], pi.firstBlockNum)
+}
+
+func TestBlockfileMgrSyncIndexAfterPrune(t *testing.T) {
+	blocks := testutil.ConstructTestBlocks(t, 100)
+	env := newTestEnv(t, NewConf(testPath(), blockfileSizeFor(t, blocks[:20])))
+	defer env.Cleanup()
+	ledgerid := "testLedger"
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, ledgerid)
+	blkfileMgrWrapper.addBlocks(blocks)
+	testutil.AssertNoError(t, blkfileMgrWrapper.blockfileMgr.prune(70), "Error while pruning")
+	pi := blkfileMgrWrapper.blockfileMgr.getPruningInfo()
+	// remove the index checkpoint so that the index gets rebuilt from the block files on restart
+	testutil.AssertNoError(t, blkfileMgrWrapper.blockfileMgr.db.Delete(indexCheckpointKey, true), "")
+	blkfileMgrWrapper.close()
+
+	blkfileMgrWrapper = newTestBlockfileWrapper(env, ledgerid)
+	defer blkfileMgrWrapper.close()
+	testutil.AssertEquals(t, blkfileMgrWrapper.blockfileMgr.getPruningInfo(), pi)
+	lastBlockIndexed, err := blkfileMgrWrapper.blockfileMgr.index.getLastBlockIndexed()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, lastBlockIndexed, uint64(99))
+	blkfileMgrWrapper.testGetBlockByNumber(blocks[pi.firstBlockNum:], pi.firstBlockNum)
+}
+
+func TestConstructPruningInfoFromBlockFiles(t *testing.T) {
+	blocks := testutil.ConstructTestBlocks(t, 100)
+	env := newTestEnv(t, NewConf(testPath(), blockfileSizeFor(t, blocks[:20])))
+	defer env.Cleanup()
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	defer blkfileMgrWrapper.close()
+	blkfileMgr := blkfileMgrWrapper.blockfileMgr
+
+	pi, err := constructPruningInfoFromBlockFiles(blkfileMgr.rootDir)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, pi, &pruningInfo{})
+
+	blkfileMgrWrapper.addBlocks(blocks)
+	testutil.AssertNoError(t, blkfileMgr.prune(50), "Error while pruning")
+	pi, err = constructPruningInfoFromBlockFiles(blkfileMgr.rootDir)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, pi, blkfileMgr.getPruningInfo())
+}
+
+func TestBlockfileMgrReadRacingPrune(t *testing.T) {
+	blocks := testutil.ConstructTestBlocks(t, 100)
+	env := newTestEnv(t, NewConf(testPath(), blockfileSizeFor(t, blocks[:20])))
+	defer env.Cleanup()
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	defer blkfileMgrWrapper.close()
+	blkfileMgr := blkfileMgrWrapper.blockfileMgr
+	blkfileMgrWrapper.addBlocks(blocks)
+
+	// a reader that looked up the location of block 0 before the pruning
+	// fails to open the removed block file
+	loc, err := blkfileMgr.index.getBlockLocByBlockNum(0)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNoError(t, blkfileMgr.prune(50), "Error while pruning")
+	_, openErr := newBlockfileStream(blkfileMgr.rootDir, loc.fileSuffixNum, int64(loc.offset))
+	testutil.AssertEquals(t, os.IsNotExist(openErr), true)
+
+	// such errors are reported as blkstorage.ErrPruned
+	testutil.AssertEquals(t, blkfileMgr.filePrunedOr(loc.fileSuffixNum, openErr), blkstorage.ErrPruned)
+	testutil.AssertEquals(t, blkfileMgr.blockPrunedOr(0, blkstorage.ErrNotFoundInIndex), blkstorage.ErrPruned)
+
+	// errors on block files and blocks that have not been pruned are returned as they are
+	lastFileNum := blkfileMgr.cpInfo.latestFileChunkSuffixNum
+	testutil.AssertEquals(t, blkfileMgr.filePrunedOr(lastFileNum, openErr), openErr)
+	testutil.AssertEquals(t, blkfileMgr.blockPrunedOr(99, blkstorage.ErrNotFoundInIndex), blkstorage.ErrNotFoundInIndex)
+}
+
+func checkPrunedBlocks(t *testing.T, blkfileMgr *blockfileMgr, prunedBlocks []*common.Block) {
+	for _, block := range prunedBlocks {
+		_, err := blkfileMgr.retrieveBlockByNumber(block.Header.Number)
+		testutil.AssertEquals(t, err, blkstorage.ErrPruned)
+		_, err = blkfileMgr.retrieveBlockByHash(block.Header.Hash())
+		testutil.AssertEquals(t, err, blkstorage.ErrNotFoundInIndex)
+		_, err = blkfileMgr.retrieveTransactionByBlockNumTranNum(block.Header.Number, 0)
+		testutil.AssertEquals(t, err, blkstorage.ErrPruned)
+
+		txID, err := extractTxID(block.Data.Data[0])
+		testutil.AssertNoError(t, err, "")
+		_, err = blkfileMgr.retrieveTransactionByID(txID)
+		testutil.AssertEquals(t, err, blkstorage.ErrPruned)
+		_, err = blkfileMgr.retrieveBlockByTxID(txID)
+		testutil.AssertEquals(t, err, blkstorage.ErrPruned)
+		// the validation code remains available for duplicate detection
+		_, err = blkfileMgr.retrieveTxValidationCodeByTxID(txID)
+		testutil.AssertNoError(t, err, "")
+	}
+
+	itr, err := blkfileMgr.retrieveBlocks(0)
+	testutil.AssertNoError(t, err, "")
+	defer itr.Close()
+	_, err = itr.Next()
+	testutil.AssertEquals(t, err, blkstorage.ErrPruned)
+}
+
+func blockfileSizeFor(t *testing.T, blocks []*common.Block) int {
+	size := 0
+	for _, block := range blocks {
+		by, _, err := serializeBlock(block)
+		testutil.AssertNoError(t, err, "Error while serializing block")
+		size += len(by) + len(proto.EncodeVarint(uint64(len(by))))
+	}
+	return size
+}
@@ -0,0 +1,116 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/protos/common"
+)
+
+// rollback removes all the blocks that follow the block `targetBlockNum`. The index entries
+// of the removed blocks, the index checkpoint, and the checkpoint info are updated atomically
+// before the block files are truncated. If a crash happens before the block files are truncated,
+// the blocks left beyond the checkpoint are picked up again on restart as if the rollback never
+// happened and the rollback can simply be retried.
+// This function is not expected to be invoked concurrently with `addBlock`
+func (mgr *blockfileMgr) rollback(targetBlockNum uint64) error {
+	mgr.pruneLock.Lock()
+	defer mgr.pruneLock.Unlock()
+
+	height := mgr.getBlockchainInfo().Height
+	if height == 0 || targetBlockNum >= height-1 {
+		return fmt.Errorf("Cannot rollback to block [%d], the blockchain height is [%d]", targetBlockNum, height)
+	}
+	if pi := mgr.getPruningInfo(); targetBlockNum < pi.firstBlockNum {
+		return fmt.Errorf("Cannot rollback to block [%d], the first available block is [%d]", targetBlockNum, pi.firstBlockNum)
+	}
+	// the location of the first block to be removed is the point where the block files get truncated
+	loc, err := mgr.index.getBlockLocByBlockNum(targetBlockNum + 1)
+	if err != nil {
+		return err
+	}
+	currentCPInfo := mgr.cpInfo
+	logger.Infof("Rolling back blocks [%d] to [%d], truncating block file [%d] at offset [%d]",
+		targetBlockNum+1, height-1, loc.fileSuffixNum, loc.offset)
+
+	batch := leveldbhelper.NewUpdateBatch()
+	if err := mgr.removeIndexEntriesFrom(loc, currentCPInfo.latestFileChunkSuffixNum, batch); err != nil {
+		return err
+	}
+	newCPInfo := &checkpointInfo{
+		latestFileChunkSuffixNum: loc.fileSuffixNum,
+		latestFileChunksize:      loc.offset,
+		isChainEmpty:             false,
+		lastBlockNumber:          targetBlockNum}
+	b, err := newCPInfo.marshal()
+	if err != nil {
+		return err
+	}
+	batch.Put(blkMgrInfoKey, b)
+	batch.Put(indexCheckpointKey, encodeBlockNum(targetBlockNum))
+	if err := mgr.db.WriteBatch(batch, true); err != nil {
+		return err
+	}
+
+	if err := mgr.currentFileWriter.close(); err != nil {
+		return err
+	}
+	for fileNum := currentCPInfo.latestFileChunkSuffixNum; fileNum > loc.fileSuffixNum; fileNum-- {
+		logger.Debugf("Removing block file [%d]", fileNum)
+		if err := os.Remove(deriveBlockfilePath(mgr.rootDir, fileNum)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	currentFileWriter, err := newBlockfileWriter(deriveBlockfilePath(mgr.rootDir, loc.fileSuffixNum))
+	if err != nil {
+		return err
+	}
+	if err := currentFileWriter.truncateFile(loc.offset); err != nil {
+		return err
+	}
+	mgr.currentFileWriter = currentFileWriter
+	mgr.updateCheckpoint(newCPInfo)
+
+	lastBlockHeader, err := mgr.retrieveBlockHeaderByNumber(targetBlockNum)
+	if err != nil {
+		return err
+	}
+	mgr.bcInfo.Store(&common.BlockchainInfo{
+		Height:            targetBlockNum + 1,
+		CurrentBlockHash:  lastBlockHeader.Hash(),
+		PreviousBlockHash: lastBlockHeader.PreviousHash})
+	logger.Infof("Finished rollback. Checkpoint info = %s", newCPInfo)
+	return nil
+}
+
+// removeIndexEntriesFrom adds to the batch the removal of the index entries of all the blocks
+// stored from the given location up to the end of the block file `lastFileNum`
+func (mgr *blockfileMgr) removeIndexEntriesFrom(loc *fileLocPointer, lastFileNum int, batch *leveldbhelper.UpdateBatch) error {
+	stream, err := newBlockStream(mgr.rootDir, loc.fileSuffixNum, int64(loc.offset), lastFileNum)
+	if err != nil {
+		return err
+	}
+	defer stream.close()
+	for {
+		blockBytes, err := stream.nextBlockBytes()
+		if err != nil {
+			return err
+		}
+		if blockBytes == nil {
+			return nil
+		}
+		info, err := extractSerializedBlockInfo(blockBytes)
+		if err != nil {
+			return err
+		}
+		mgr.index.removeBlockIndex(info.blockHeader.Number, info.blockHeader.Hash(), len(info.txOffsets), batch)
+		mgr.index.removeTxIndex(info.txOffsets, batch)
+	}
+}
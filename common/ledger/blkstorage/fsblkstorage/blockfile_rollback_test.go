@@ -0,0 +1,89 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/hyperledger/fabric/protos/common"
+)
+
+func TestBlockfileMgrRollback(t *testing.T) {
+	blocks := testutil.ConstructTestBlocks(t, 100)
+	env := newTestEnv(t, NewConf(testPath(), blockfileSizeFor(t, blocks[:20])))
+	defer env.Cleanup()
+	ledgerid := "testLedger"
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, ledgerid)
+	blkfileMgr := blkfileMgrWrapper.blockfileMgr
+	blkfileMgrWrapper.addBlocks(blocks)
+	lastFileNum := blkfileMgr.cpInfo.latestFileChunkSuffixNum
+
+	// rolling back to the last block (or beyond) is not allowed
+	testutil.AssertError(t, blkfileMgr.rollback(99), "Expected an error while rolling back to the last block")
+	testutil.AssertError(t, blkfileMgr.rollback(150), "Expected an error while rolling back beyond the last block")
+
+	testutil.AssertNoError(t, blkfileMgr.rollback(45), "Error while rolling back")
+	testutil.AssertEquals(t, blkfileMgr.getBlockchainInfo().Height, uint64(46))
+	testutil.AssertEquals(t, blkfileMgr.getBlockchainInfo().CurrentBlockHash, blocks[45].Header.Hash())
+	lastBlockIndexed, err := blkfileMgr.index.getLastBlockIndexed()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, lastBlockIndexed, uint64(45))
+	for fileNum := blkfileMgr.cpInfo.latestFileChunkSuffixNum + 1; fileNum <= lastFileNum; fileNum++ {
+		exists, _, err := util.FileExists(deriveBlockfilePath(blkfileMgr.rootDir, fileNum))
+		testutil.AssertNoError(t, err, "")
+		testutil.AssertEquals(t, exists, false)
+	}
+	blkfileMgrWrapper.testGetBlockByNumber(blocks[:46], 0)
+	checkRolledBackBlocks(t, blkfileMgr, blocks[46:])
+	blkfileMgrWrapper.close()
+
+	// the rollback survives a restart and the store accepts the blocks after the target block again
+	blkfileMgrWrapper = newTestBlockfileWrapper(env, ledgerid)
+	defer blkfileMgrWrapper.close()
+	blkfileMgr = blkfileMgrWrapper.blockfileMgr
+	testutil.AssertEquals(t, blkfileMgr.getBlockchainInfo().Height, uint64(46))
+	checkRolledBackBlocks(t, blkfileMgr, blocks[46:])
+	blkfileMgrWrapper.addBlocks(blocks[46:])
+	blkfileMgrWrapper.testGetBlockByNumber(blocks, 0)
+	blkfileMgrWrapper.testGetBlockByHash(blocks)
+}
+
+func TestBlockfileMgrRollbackToPrunedBlock(t *testing.T) {
+	blocks := testutil.ConstructTestBlocks(t, 100)
+	env := newTestEnv(t, NewConf(testPath(), blockfileSizeFor(t, blocks[:20])))
+	defer env.Cleanup()
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	defer blkfileMgrWrapper.close()
+	blkfileMgr := blkfileMgrWrapper.blockfileMgr
+	blkfileMgrWrapper.addBlocks(blocks)
+	testutil.AssertNoError(t, blkfileMgr.prune(50), "Error while pruning")
+	pi := blkfileMgr.getPruningInfo()
+
+	testutil.AssertError(t, blkfileMgr.rollback(pi.firstBlockNum-1), "Expected an error while rolling back to a pruned block")
+	testutil.AssertNoError(t, blkfileMgr.rollback(pi.firstBlockNum), "Error while rolling back")
+	testutil.AssertEquals(t, blkfileMgr.getBlockchainInfo().Height, pi.firstBlockNum+1)
+	blkfileMgrWrapper.testGetBlockByNumber(blocks[pi.firstBlockNum:pi.firstBlockNum+1], pi.firstBlockNum)
+}
+
+func checkRolledBackBlocks(t *testing.T, blkfileMgr *blockfileMgr, rolledBackBlocks []*common.Block) {
+	for _, block := range rolledBackBlocks {
+		_, err := blkfileMgr.retrieveBlockByNumber(block.Header.Number)
+		testutil.AssertEquals(t, err, blkstorage.ErrNotFoundInIndex)
+		_, err = blkfileMgr.retrieveBlockByHash(block.Header.Hash())
+		testutil.AssertEquals(t, err, blkstorage.ErrNotFoundInIndex)
+
+		txID, err := extractTxID(block.Data.Data[0])
+		testutil.AssertNoError(t, err, "")
+		_, err = blkfileMgr.retrieveTransactionByID(txID)
+		testutil.AssertEquals(t, err, blkstorage.ErrNotFoundInIndex)
+		_, err = blkfileMgr.retrieveTxValidationCodeByTxID(txID)
+		testutil.AssertEquals(t, err, blkstorage.ErrNotFoundInIndex)
+	}
+}
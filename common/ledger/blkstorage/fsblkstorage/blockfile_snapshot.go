@@ -0,0 +1,96 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/protos/common"
+)
+
+// maxTxIndexBatchSize is the number of transactions that are indexed in a single db batch
+// while bootstrapping the block store from a snapshot
+const maxTxIndexBatchSize = 10000
+
+// exportTxValidationInfo invokes the given function for the validation code of each indexed transaction
+func (mgr *blockfileMgr) exportTxValidationInfo(fn func(*blkstorage.TxValidationInfo) error) error {
+	return mgr.index.exportTxValidationInfo(fn)
+}
+
+// bootstrapFromSnapshot prepares an empty block store for receiving the blocks starting from the block
+// `firstBlockNum`. The block store is made to look like a block store whose blocks preceding `firstBlockNum`
+// have been pruned, i.e., the pruning info points to the block `firstBlockNum` in the block file 1 and the
+// transactions returned by `nextTxInfo` are indexed as the transactions of the block file 0, which is removed.
+// As a result, these transactions are reported as pruned, which is sufficient for detecting duplicate transactions.
+// The pruning info and the checkpoint info are updated atomically, after the transactions have been indexed,
+// so that a crash in between leaves the blockchain empty. If `firstBlockNum` is 0, there is nothing to prepare
+func (mgr *blockfileMgr) bootstrapFromSnapshot(firstBlockNum uint64, nextTxInfo func() (*blkstorage.TxValidationInfo, error)) error {
+	if height := mgr.getBlockchainInfo().Height; height != 0 {
+		return fmt.Errorf("Cannot bootstrap the block store from a snapshot, the blockchain height is [%d]", height)
+	}
+	if firstBlockNum == 0 {
+		// all the transactions are indexed when the blocks are added
+		return nil
+	}
+	logger.Infof("Bootstrapping the block store from a snapshot, first block = [%d]", firstBlockNum)
+	prunedFileLoc := &fileLocPointer{fileSuffixNum: 0}
+	batch := leveldbhelper.NewUpdateBatch()
+	numTxs := 0
+	for {
+		txInfo, err := nextTxInfo()
+		if err != nil {
+			return err
+		}
+		if txInfo == nil {
+			break
+		}
+		if err := mgr.index.indexTxOfPrunedBlock(txInfo, prunedFileLoc, batch); err != nil {
+			return err
+		}
+		numTxs++
+		if numTxs%maxTxIndexBatchSize == 0 {
+			if err := mgr.db.WriteBatch(batch, true); err != nil {
+				return err
+			}
+			batch = leveldbhelper.NewUpdateBatch()
+		}
+	}
+	logger.Infof("Indexed [%d] transactions of the blocks preceding block [%d]", numTxs, firstBlockNum)
+
+	newPruningInfo := &pruningInfo{firstFileSuffixNum: 1, firstBlockNum: firstBlockNum}
+	newCPInfo := &checkpointInfo{latestFileChunkSuffixNum: 1, isChainEmpty: true}
+	pruningInfoBytes, err := newPruningInfo.marshal()
+	if err != nil {
+		return err
+	}
+	cpInfoBytes, err := newCPInfo.marshal()
+	if err != nil {
+		return err
+	}
+	batch.Put(blkMgrPruningInfoKey, pruningInfoBytes)
+	batch.Put(blkMgrInfoKey, cpInfoBytes)
+	nextFileWriter, err := newBlockfileWriter(deriveBlockfilePath(mgr.rootDir, newCPInfo.latestFileChunkSuffixNum))
+	if err != nil {
+		return err
+	}
+	if err := mgr.db.WriteBatch(batch, true); err != nil {
+		nextFileWriter.close()
+		return err
+	}
+	mgr.currentFileWriter.close()
+	mgr.currentFileWriter = nextFileWriter
+	mgr.pruningInfo.Store(newPruningInfo)
+	mgr.updateCheckpoint(newCPInfo)
+	mgr.bcInfo.Store(&common.BlockchainInfo{Height: firstBlockNum})
+	if err := os.Remove(deriveBlockfilePath(mgr.rootDir, 0)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
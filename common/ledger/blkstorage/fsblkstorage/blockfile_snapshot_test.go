@@ -0,0 +1,94 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/ledger/util"
+)
+
+func TestBlockfileMgrBootstrapFromSnapshot(t *testing.T) {
+	blocks := testutil.ConstructTestBlocks(t, 20)
+	env := newTestEnv(t, NewConf(testPath(), 0))
+	defer env.Cleanup()
+
+	sourceWrapper := newTestBlockfileWrapper(env, "sourceLedger")
+	sourceWrapper.addBlocks(blocks[:15])
+	var txInfos []*blkstorage.TxValidationInfo
+	testutil.AssertNoError(t, sourceWrapper.blockfileMgr.exportTxValidationInfo(func(txInfo *blkstorage.TxValidationInfo) error {
+		txInfos = append(txInfos, txInfo)
+		return nil
+	}), "")
+	sourceWrapper.close()
+	// the genesis block carries a single transaction and each of the other blocks carries ten transactions
+	testutil.AssertEquals(t, len(txInfos), 141)
+	for i := 1; i < len(txInfos); i++ {
+		testutil.AssertEquals(t, txInfos[i-1].TxID < txInfos[i].TxID, true)
+	}
+
+	targetWrapper := newTestBlockfileWrapper(env, "targetLedger")
+	targetMgr := targetWrapper.blockfileMgr
+	toImport := txInfos
+	nextTxInfo := func() (*blkstorage.TxValidationInfo, error) {
+		if len(toImport) == 0 {
+			return nil, nil
+		}
+		txInfo := toImport[0]
+		toImport = toImport[1:]
+		return txInfo, nil
+	}
+	testutil.AssertNoError(t, targetMgr.bootstrapFromSnapshot(10, nextTxInfo), "")
+	testutil.AssertEquals(t, targetMgr.getBlockchainInfo().Height, uint64(10))
+	testutil.AssertEquals(t, targetMgr.getPruningInfo(), &pruningInfo{firstFileSuffixNum: 1, firstBlockNum: 10})
+	exists, _, err := util.FileExists(deriveBlockfilePath(targetMgr.rootDir, 0))
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, exists, false)
+
+	// the blocks are expected to continue from the first block of the snapshot
+	testutil.AssertError(t, targetMgr.addBlock(blocks[11]), "Expected an error while adding a block out of order")
+	targetWrapper.addBlocks(blocks[10:15])
+	checkPrunedBlocks(t, targetMgr, blocks[:10])
+	targetWrapper.testGetBlockByNumber(blocks[10:15], 10)
+	targetWrapper.testGetBlockByHash(blocks[10:15])
+
+	// bootstrapping a non-empty block store is not allowed
+	testutil.AssertError(t, targetMgr.bootstrapFromSnapshot(10, nextTxInfo), "Expected an error while bootstrapping a non-empty block store")
+	targetWrapper.close()
+
+	// the bootstrapped block store survives a restart and keeps accepting blocks
+	targetWrapper = newTestBlockfileWrapper(env, "targetLedger")
+	defer targetWrapper.close()
+	targetMgr = targetWrapper.blockfileMgr
+	testutil.AssertEquals(t, targetMgr.getBlockchainInfo().Height, uint64(15))
+	checkPrunedBlocks(t, targetMgr, blocks[:10])
+	targetWrapper.addBlocks(blocks[15:])
+	targetWrapper.testGetBlockByNumber(blocks[10:], 10)
+}
+
+func TestBlockStoreProviderDrop(t *testing.T) {
+	env := newTestEnv(t, NewConf(testPath(), 0))
+	defer env.Cleanup()
+	provider := env.provider
+	store, err := provider.OpenBlockStore("testLedger")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNoError(t, store.AddBlock(testutil.ConstructTestBlocks(t, 1)[0]), "")
+	store.Shutdown()
+
+	testutil.AssertNoError(t, provider.Drop("testLedger"), "")
+	exists, err := provider.Exists("testLedger")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, exists, false)
+	store, err = provider.OpenBlockStore("testLedger")
+	testutil.AssertNoError(t, err, "")
+	defer store.Shutdown()
+	bcInfo, err := store.GetBlockchainInfo()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, bcInfo.Height, uint64(0))
+}
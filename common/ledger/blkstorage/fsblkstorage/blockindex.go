@@ -46,12 +46,16 @@ var errIndexEmpty = errors.New("NoBlockIndexed")
 type index interface {
 	getLastBlockIndexed() (uint64, error)
 	indexBlock(blockIdxInfo *blockIdxInfo) error
+	removeBlockIndex(blockNum uint64, blockHash []byte, numTxs int, batch *leveldbhelper.UpdateBatch)
+	removeTxIndex(txOffsets []*txindexInfo, batch *leveldbhelper.UpdateBatch)
 	getBlockLocByHash(blockHash []byte) (*fileLocPointer, error)
 	getBlockLocByBlockNum(blockNum uint64) (*fileLocPointer, error)
 	getTxLoc(txID string) (*fileLocPointer, error)
 	getTXLocByBlockNumTranNum(blockNum uint64, tranNum uint64) (*fileLocPointer, error)
 	getBlockLocByTxID(txID string) (*fileLocPointer, error)
 	getTxValidationCodeByTxID(txID string) (peer.TxValidationCode, error)
+	exportTxValidationInfo(fn func(*blkstorage.TxValidationInfo) error) error
+	indexTxOfPrunedBlock(txInfo *blkstorage.TxValidationInfo, flp *fileLocPointer, batch *leveldbhelper.UpdateBatch) error
 }
 
 type blockIdxInfo struct {
@@ -163,6 +167,40 @@ func (index *blockIndex) indexBlock(blockIdxInfo *blockIdxInfo) error {
 	return nil
 }
 
+// removeBlockIndex adds to the batch the removal of the index entries of a pruned block that
+// are keyed by block hash or block number. The entries keyed by transaction ID are retained
+// for detecting duplicate transactions
+func (index *blockIndex) removeBlockIndex(blockNum uint64, blockHash []byte, numTxs int, batch *leveldbhelper.UpdateBatch) {
+	logger.Debugf("Removing index entries of block [%d]", blockNum)
+	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrBlockHash]; ok {
+		batch.Delete(constructBlockHashKey(blockHash))
+	}
+	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrBlockNum]; ok {
+		batch.Delete(constructBlockNumKey(blockNum))
+	}
+	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrBlockNumTranNum]; ok {
+		for txNum := 0; txNum < numTxs; txNum++ {
+			batch.Delete(constructBlockNumTranNumKey(blockNum, uint64(txNum)))
+		}
+	}
+}
+
+// removeTxIndex adds to the batch the removal of the index entries keyed by the IDs of the
+// given transactions. This is used when the block containing the transactions is rolled back
+func (index *blockIndex) removeTxIndex(txOffsets []*txindexInfo, batch *leveldbhelper.UpdateBatch) {
+	for _, txoffset := range txOffsets {
+		if _, ok := index.indexItemsMap[blkstorage.IndexableAttrTxID]; ok {
+			batch.Delete(constructTxIDKey(txoffset.txID))
+		}
+		if _, ok := index.indexItemsMap[blkstorage.IndexableAttrBlockTxID]; ok {
+			batch.Delete(constructBlockTxIDKey(txoffset.txID))
+		}
+		if _, ok := index.indexItemsMap[blkstorage.IndexableAttrTxValidationCode]; ok {
+			batch.Delete(constructTxValidationCodeIDKey(txoffset.txID))
+		}
+	}
+}
+
 func (index *blockIndex) getBlockLocByHash(blockHash []byte) (*fileLocPointer, error) {
 	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrBlockHash]; !ok {
 		return nil, blkstorage.ErrAttrNotIndexed
@@ -263,6 +301,51 @@ func (index *blockIndex) getTxValidationCodeByTxID(txID string) (peer.TxValidati
 	return result, nil
 }
 
+// exportTxValidationInfo invokes the given function for the validation code of each transaction
+// present in the index, in the order of the transaction IDs
+func (index *blockIndex) exportTxValidationInfo(fn func(*blkstorage.TxValidationInfo) error) error {
+	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrTxValidationCode]; !ok {
+		return blkstorage.ErrAttrNotIndexed
+	}
+	itr := index.db.GetIterator([]byte{txValidationResultIdxKeyPrefix}, []byte{txValidationResultIdxKeyPrefix + 1})
+	defer itr.Release()
+	for itr.Next() {
+		raw := itr.Value()
+		if len(raw) != 1 {
+			return errors.New("Invalid value in indexItems")
+		}
+		txInfo := &blkstorage.TxValidationInfo{
+			TxID:           string(itr.Key()[1:]),
+			ValidationCode: peer.TxValidationCode(int32(raw[0])),
+		}
+		if err := fn(txInfo); err != nil {
+			return err
+		}
+	}
+	return itr.Error()
+}
+
+// indexTxOfPrunedBlock adds to the batch the index entries keyed by the ID of a transaction whose block
+// is not present in the block store. The location of the transaction and its block is the given
+// location pointer that is expected to point to a pruned block file, so that the transaction is
+// reported as pruned while its validation code remains available for detecting duplicate transactions
+func (index *blockIndex) indexTxOfPrunedBlock(txInfo *blkstorage.TxValidationInfo, flp *fileLocPointer, batch *leveldbhelper.UpdateBatch) error {
+	flpBytes, err := flp.marshal()
+	if err != nil {
+		return err
+	}
+	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrTxID]; ok {
+		batch.Put(constructTxIDKey(txInfo.TxID), flpBytes)
+	}
+	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrBlockTxID]; ok {
+		batch.Put(constructBlockTxIDKey(txInfo.TxID), flpBytes)
+	}
+	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrTxValidationCode]; ok {
+		batch.Put(constructTxValidationCodeIDKey(txInfo.TxID), []byte{byte(txInfo.ValidationCode)})
+	}
+	return nil
+}
+
 func constructBlockNumKey(blockNum uint64) []byte {
 	blkNumBytes := util.EncodeOrderPreservingVarUint64(blockNum)
 	return append([]byte{blockNumIdxKeyPrefix}, blkNumBytes...)
@@ -22,6 +22,7 @@ import (
 
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
 	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/peer"
@@ -37,6 +38,11 @@ func (i *noopIndex) getLastBlockIndexed() (uint64, error) {
 func (i *noopIndex) indexBlock(blockIdxInfo *blockIdxInfo) error {
 	return nil
 }
+func (i *noopIndex) removeBlockIndex(blockNum uint64, blockHash []byte, numTxs int, batch *leveldbhelper.UpdateBatch) {
+}
+func (i *noopIndex) removeTxIndex(txOffsets []*txindexInfo, batch *leveldbhelper.UpdateBatch) {
+}
+
 func (i *noopIndex) getBlockLocByHash(blockHash []byte) (*fileLocPointer, error) {
 	return nil, nil
 }
@@ -58,6 +64,14 @@ func (i *noopIndex) getTxValidationCodeByTxID(txID string) (peer.TxValidationCod
 	return peer.TxValidationCode(-1), nil
 }
 
+func (i *noopIndex) exportTxValidationInfo(fn func(*blkstorage.TxValidationInfo) error) error {
+	return nil
+}
+
+func (i *noopIndex) indexTxOfPrunedBlock(txInfo *blkstorage.TxValidationInfo, flp *fileLocPointer, batch *leveldbhelper.UpdateBatch) error {
+	return nil
+}
+
 func TestBlockIndexSync(t *testing.T) {
 	testBlockIndexSync(t, 10, 5, false)
 	testBlockIndexSync(t, 10, 5, true)
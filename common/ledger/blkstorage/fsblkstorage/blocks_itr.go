@@ -51,11 +51,14 @@ func (itr *blocksItr) waitForBlock(blockNum uint64) uint64 {
 func (itr *blocksItr) initStream() error {
 	var lp *fileLocPointer
 	var err error
-	if lp, err = itr.mgr.index.getBlockLocByBlockNum(itr.blockNumToRetrieve); err != nil {
+	if err = itr.mgr.checkBlockNotPruned(itr.blockNumToRetrieve); err != nil {
 		return err
 	}
+	if lp, err = itr.mgr.index.getBlockLocByBlockNum(itr.blockNumToRetrieve); err != nil {
+		return itr.mgr.blockPrunedOr(itr.blockNumToRetrieve, err)
+	}
 	if itr.stream, err = newBlockStream(itr.mgr.rootDir, lp.fileSuffixNum, int64(lp.offset), -1); err != nil {
-		return err
+		return itr.mgr.filePrunedOr(lp.fileSuffixNum, err)
 	}
 	return nil
 }
@@ -86,6 +86,31 @@ func (store *fsBlockStore) RetrieveTxValidationCodeByTxID(txID string) (peer.TxV
 	return store.fileMgr.retrieveTxValidationCodeByTxID(txID)
 }
 
+// GetFirstAvailableBlockNum returns the number of the first block that has not been pruned
+func (store *fsBlockStore) GetFirstAvailableBlockNum() (uint64, error) {
+	return store.fileMgr.getPruningInfo().firstBlockNum, nil
+}
+
+// Prune removes the block files that contain only blocks preceding `retainFromBlockNum`
+func (store *fsBlockStore) Prune(retainFromBlockNum uint64) error {
+	return store.fileMgr.prune(retainFromBlockNum)
+}
+
+// Rollback removes the blocks that follow `targetBlockNum` from the block files and the index
+func (store *fsBlockStore) Rollback(targetBlockNum uint64) error {
+	return store.fileMgr.rollback(targetBlockNum)
+}
+
+// ExportTxValidationInfo invokes the given function for the validation code of each indexed transaction
+func (store *fsBlockStore) ExportTxValidationInfo(fn func(*blkstorage.TxValidationInfo) error) error {
+	return store.fileMgr.exportTxValidationInfo(fn)
+}
+
+// BootstrapFromSnapshot prepares the empty block store for receiving the blocks starting from `firstBlockNum`
+func (store *fsBlockStore) BootstrapFromSnapshot(firstBlockNum uint64, nextTxInfo func() (*blkstorage.TxValidationInfo, error)) error {
+	return store.fileMgr.bootstrapFromSnapshot(firstBlockNum, nextTxInfo)
+}
+
 // Shutdown shuts down the block store
 func (store *fsBlockStore) Shutdown() {
 	logger.Debugf("closing fs blockStore:%s", store.id)
@@ -17,6 +17,8 @@ limitations under the License.
 package fsblkstorage
 
 import (
+	"os"
+
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/util"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
@@ -59,6 +61,14 @@ func (p *FsBlockstoreProvider) List() ([]string, error) {
 	return util.ListSubdirs(p.conf.getChainsDir())
 }
 
+// Drop removes the block files and the index of the block store with the given id
+func (p *FsBlockstoreProvider) Drop(ledgerid string) error {
+	if err := p.leveldbProvider.GetDBHandle(ledgerid).DeleteAll(); err != nil {
+		return err
+	}
+	return os.RemoveAll(p.conf.getLedgerBlockDir(ledgerid))
+}
+
 // Close closes the FsBlockstoreProvider
 func (p *FsBlockstoreProvider) Close() {
 	p.leveldbProvider.Close()
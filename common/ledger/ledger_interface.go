@@ -17,6 +17,8 @@ limitations under the License.
 package ledger
 
 import (
+	"time"
+
 	"github.com/hyperledger/fabric/protos/common"
 )
 
@@ -49,3 +51,17 @@ type QueryResult interface{}
 
 // PrunePolicy - a general interface for supporting different pruning policies
 type PrunePolicy interface{}
+
+// RetainLastNBlocksPolicy is a PrunePolicy that retains the last `NumBlocks` blocks of the chain
+type RetainLastNBlocksPolicy struct {
+	NumBlocks uint64
+}
+
+// RetainSinceTimestampPolicy is a PrunePolicy that retains the blocks starting from the first block
+// whose transactions were created at or after `Timestamp`
+type RetainSinceTimestampPolicy struct {
+	Timestamp time.Time
+}
+
+// RetainSinceLastConfigBlockPolicy is a PrunePolicy that retains the blocks starting from the last config block
+type RetainSinceLastConfigBlockPolicy struct{}
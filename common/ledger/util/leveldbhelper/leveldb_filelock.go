@@ -0,0 +1,58 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package leveldbhelper
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+)
+
+// FileLock is an exclusive lock on a directory that is held by a single process.
+// The lock is backed by a leveldb opened on the directory - leveldb acquires a file
+// lock while opening a db and releases it when the db is closed or the process exits.
+// A FileLock is not safe for concurrent use by multiple goroutines
+type FileLock struct {
+	dirPath string
+	db      *leveldb.DB
+}
+
+// NewFileLock constructs a FileLock on the given directory
+func NewFileLock(dirPath string) *FileLock {
+	return &FileLock{dirPath: dirPath}
+}
+
+// Lock acquires the lock. An error is returned if the lock is held by another
+// process, or by another FileLock in the same process
+func (f *FileLock) Lock() error {
+	if f.db != nil {
+		return nil
+	}
+	dirEmpty, err := util.CreateDirIfMissing(f.dirPath)
+	if err != nil {
+		return fmt.Errorf("Error while trying to create dir [%s]: %s", f.dirPath, err)
+	}
+	db, err := leveldb.OpenFile(f.dirPath, &opt.Options{ErrorIfMissing: !dirEmpty})
+	if err != nil {
+		return fmt.Errorf("Error while acquiring the lock on [%s]: %s", f.dirPath, err)
+	}
+	f.db = db
+	return nil
+}
+
+// Unlock releases the lock. Unlocking a lock that is not held is a no-op
+func (f *FileLock) Unlock() {
+	if f.db == nil {
+		return
+	}
+	if err := f.db.Close(); err != nil {
+		logger.Warningf("Error while releasing the lock on [%s]: %s", f.dirPath, err)
+	}
+	f.db = nil
+}
@@ -0,0 +1,33 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package leveldbhelper
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+)
+
+func TestFileLock(t *testing.T) {
+	lockPath := testDBPath + "/fileLock"
+	defer os.RemoveAll(testDBPath)
+
+	fileLock := NewFileLock(lockPath)
+	testutil.AssertNoError(t, fileLock.Lock(), "")
+	// locking again with the same FileLock is a no-op
+	testutil.AssertNoError(t, fileLock.Lock(), "")
+
+	// the lock cannot be acquired by another FileLock while it is held
+	anotherFileLock := NewFileLock(lockPath)
+	testutil.AssertError(t, anotherFileLock.Lock(), "Expected an error while acquiring a held lock")
+
+	fileLock.Unlock()
+	fileLock.Unlock()
+	testutil.AssertNoError(t, anotherFileLock.Lock(), "")
+	anotherFileLock.Unlock()
+}
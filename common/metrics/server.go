@@ -8,11 +8,13 @@ package metrics
 
 import (
 	"fmt"
+	"net/http"
 	"sync"
 	"time"
 
 	"github.com/spf13/viper"
 	"github.com/uber-go/tally"
+	promreporter "github.com/uber-go/tally/prometheus"
 )
 
 const (
@@ -82,6 +84,18 @@ func Init(opts Opts) (err error) {
 	return
 }
 
+// GetRootScope returns the root scope if it is initialized, or a scope that
+// discards all the metrics otherwise. Components should call it when they are
+// created and derive their sub scopes from the returned one
+func GetRootScope() Scope {
+	rootScopeMutex.Lock()
+	defer rootScopeMutex.Unlock()
+	if RootScope == nil {
+		return newNoOpScope()
+	}
+	return RootScope
+}
+
 //Start starts metrics server
 func Start() error {
 	rootScopeMutex.Lock()
@@ -89,6 +103,9 @@ func Start() error {
 	if running {
 		return nil
 	}
+	if RootScope == nil {
+		return fmt.Errorf("metrics root scope is not initialized")
+	}
 	running = true
 	return RootScope.Start()
 }
@@ -107,6 +124,26 @@ func Shutdown() error {
 	return err
 }
 
+// PrometheusHandler returns the http.Handler that exposes the metrics of the
+// root scope in the prometheus exposition format. It returns nil if the root
+// scope is not initialized or does not report to prometheus
+func PrometheusHandler() http.Handler {
+	rootScopeMutex.Lock()
+	defer rootScopeMutex.Unlock()
+	return prometheusHandler(RootScope)
+}
+
+func prometheusHandler(rootScope Scope) http.Handler {
+	s, ok := rootScope.(*scope)
+	if !ok {
+		return nil
+	}
+	if reporter, ok := s.baseReporter.(*promReporter); ok {
+		return reporter.HTTPHandler()
+	}
+	return nil
+}
+
 func isRunning() bool {
 	rootScopeMutex.Lock()
 	defer rootScopeMutex.Unlock()
@@ -145,9 +182,17 @@ func (g *noOpGauge) Update(v float64) {
 
 }
 
+type noOpHistogram struct {
+}
+
+func (h *noOpHistogram) RecordDuration(v time.Duration) {
+
+}
+
 type noOpScope struct {
-	counter *noOpCounter
-	gauge   *noOpGauge
+	counter   *noOpCounter
+	gauge     *noOpGauge
+	histogram *noOpHistogram
 }
 
 func (s *noOpScope) Counter(name string) Counter {
@@ -158,6 +203,10 @@ func (s *noOpScope) Gauge(name string) Gauge {
 	return s.gauge
 }
 
+func (s *noOpScope) Histogram(name string) Histogram {
+	return s.histogram
+}
+
 func (s *noOpScope) Tagged(tags map[string]string) Scope {
 	return s
 }
@@ -176,8 +225,9 @@ func (s *noOpScope) Start() error {
 
 func newNoOpScope() Scope {
 	return &noOpScope{
-		counter: &noOpCounter{},
-		gauge:   &noOpGauge{},
+		counter:   &noOpCounter{},
+		gauge:     &noOpGauge{},
+		histogram: &noOpHistogram{},
 	}
 }
 
@@ -210,12 +260,16 @@ func create(opts Opts) (rootScope Scope, e error) {
 			return
 		}
 
-		rootScope = newRootScope(
-			tally.ScopeOptions{
-				Prefix:         namespace,
-				Reporter:       reporter,
-				CachedReporter: cachedReporter,
-			}, opts.Interval)
+		scopeOpts := tally.ScopeOptions{
+			Prefix:         namespace,
+			Reporter:       reporter,
+			CachedReporter: cachedReporter,
+		}
+		if opts.Reporter == promReporterType {
+			// prometheus doesn't allow '.' in the metric names
+			scopeOpts.Separator = promreporter.DefaultSeparator
+		}
+		rootScope = newRootScope(scopeOpts, opts.Interval)
 		return
 	}
 }
@@ -8,6 +8,8 @@ package metrics
 
 import (
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
@@ -187,9 +189,56 @@ func TestNoOpScopeMetrics(t *testing.T) {
 	subScope := s.SubScope("test")
 	subScope.Counter("foo").Inc(2)
 	subScope.Gauge("bar").Update(1.33)
+	subScope.Histogram("baz").RecordDuration(time.Second)
 	tagSubScope := subScope.Tagged(map[string]string{"env": "test"})
 	tagSubScope.Counter("foo").Inc(2)
 	tagSubScope.Gauge("bar").Update(1.33)
+	tagSubScope.Histogram("baz").RecordDuration(time.Second)
+}
+
+func TestGetRootScope(t *testing.T) {
+	rootScopeMutex.Lock()
+	origRootScope := RootScope
+	RootScope = nil
+	rootScopeMutex.Unlock()
+	defer func() {
+		rootScopeMutex.Lock()
+		RootScope = origRootScope
+		rootScopeMutex.Unlock()
+	}()
+
+	assert.IsType(t, &noOpScope{}, GetRootScope())
+
+	s, err := create(Opts{Enabled: true, Reporter: promReporterType, Interval: time.Second})
+	assert.NoError(t, err)
+	rootScopeMutex.Lock()
+	RootScope = s
+	rootScopeMutex.Unlock()
+	assert.Equal(t, s, GetRootScope())
+}
+
+func TestPrometheusHandler(t *testing.T) {
+	t.Parallel()
+	s, err := create(Opts{Enabled: true, Reporter: promReporterType, Interval: 100 * time.Millisecond})
+	assert.NoError(t, err)
+	defer s.Close()
+	handler := prometheusHandler(s)
+	assert.NotNil(t, handler)
+
+	s.SubScope("peer").Counter("blocks_total").Inc(2)
+	scrape := func() string {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		return rec.Body.String()
+	}
+	gt := NewGomegaWithT(t)
+	gt.Eventually(scrape).Should(ContainSubstring("hyperledger_fabric_peer_blocks_total 2"))
+
+	// no handler for the scopes that don't report to prometheus
+	s, err = create(Opts{Enabled: false})
+	assert.NoError(t, err)
+	assert.Nil(t, prometheusHandler(s))
+	assert.Nil(t, prometheusHandler(nil))
 }
 
 func TestNewOpts(t *testing.T) {
@@ -211,7 +260,7 @@ func TestNewOpts(t *testing.T) {
 	assert.False(t, opts1.Enabled)
 	assert.Equal(t, 1*time.Second, opts1.Interval)
 	assert.Equal(t, promReporterType, opts1.Reporter)
-	assert.Equal(t, "0.0.0.0:8080", opts1.PromReporterOpts.ListenAddress)
+	assert.Equal(t, "", opts1.PromReporterOpts.ListenAddress)
 }
 
 func TestNewOptsDefaultVar(t *testing.T) {
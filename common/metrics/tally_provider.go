@@ -29,6 +29,10 @@ var logger = logging.MustGetLogger("common/metrics/tally")
 
 var scopeRegistryKey = tally.KeyForPrefixedStringMap
 
+// durationBuckets are the buckets of the duration histograms,
+// ranging from 1ms to about 65s
+var durationBuckets = tally.MustMakeExponentialDurationBuckets(time.Millisecond, 2, 17)
+
 type counter struct {
 	tallyCounter tally.Counter
 }
@@ -53,6 +57,18 @@ func (g *gauge) Update(v float64) {
 	g.tallyGauge.Update(v)
 }
 
+type histogram struct {
+	tallyHistogram tally.Histogram
+}
+
+func newHistogram(tallyHistogram tally.Histogram) *histogram {
+	return &histogram{tallyHistogram: tallyHistogram}
+}
+
+func (h *histogram) RecordDuration(v time.Duration) {
+	h.tallyHistogram.RecordDuration(v)
+}
+
 type scopeRegistry struct {
 	sync.RWMutex
 	subScopes map[string]*scope
@@ -68,9 +84,11 @@ type scope struct {
 
 	cm sync.RWMutex
 	gm sync.RWMutex
+	hm sync.RWMutex
 
-	counters map[string]*counter
-	gauges   map[string]*gauge
+	counters   map[string]*counter
+	gauges     map[string]*gauge
+	histograms map[string]*histogram
 }
 
 func newRootScope(opts tally.ScopeOptions, interval time.Duration) Scope {
@@ -92,7 +110,8 @@ func newRootScope(opts tally.ScopeOptions, interval time.Duration) Scope {
 		},
 		baseReporter: baseReporter,
 		counters:     make(map[string]*counter),
-		gauges:       make(map[string]*gauge)}
+		gauges:       make(map[string]*gauge),
+		histograms:   make(map[string]*histogram)}
 }
 
 func newStatsdReporter(statsdReporterOpts StatsdReporterOpts) (tally.StatsReporter, error) {
@@ -120,20 +139,19 @@ func newStatsdReporter(statsdReporterOpts StatsdReporterOpts) (tally.StatsReport
 }
 
 func newPromReporter(promReporterOpts PromReporterOpts) (promreporter.Reporter, error) {
-	if promReporterOpts.ListenAddress == "" {
-		return nil, errors.New("missing prometheus listenAddress option")
-	}
-
 	opts := promreporter.Options{Registerer: prometheus.NewRegistry()}
 	reporter := promreporter.NewReporter(opts)
-	mux := http.NewServeMux()
-	handler := promReporterHttpHandler(opts.Registerer.(*prometheus.Registry))
-	mux.Handle("/metrics", handler)
-	server := &http.Server{Addr: promReporterOpts.ListenAddress, Handler: mux}
 	promReporter := &promReporter{
 		reporter: reporter,
-		server:   server,
 		registry: opts.Registerer.(*prometheus.Registry)}
+
+	// Without a listen address, the metrics are only exposed through the handler
+	// returned by HTTPHandler (e.g., by the operations endpoint)
+	if promReporterOpts.ListenAddress != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promReporter.HTTPHandler())
+		promReporter.server = &http.Server{Addr: promReporterOpts.ListenAddress, Handler: mux}
+	}
 	return promReporter, nil
 }
 
@@ -171,6 +189,23 @@ func (s *scope) Gauge(name string) Gauge {
 	return val
 }
 
+func (s *scope) Histogram(name string) Histogram {
+	s.hm.RLock()
+	val, ok := s.histograms[name]
+	s.hm.RUnlock()
+	if !ok {
+		s.hm.Lock()
+		val, ok = s.histograms[name]
+		if !ok {
+			histogram := s.tallyScope.Histogram(name, durationBuckets)
+			val = newHistogram(histogram)
+			s.histograms[name] = val
+		}
+		s.hm.Unlock()
+	}
+	return val
+}
+
 func (s *scope) Tagged(tags map[string]string) Scope {
 	originTags := tags
 	tags = mergeRightTags(s.tags, tags)
@@ -201,8 +236,9 @@ func (s *scope) Tagged(tags map[string]string) Scope {
 		tallyScope: s.tallyScope.Tagged(originTags),
 		registry:   s.registry,
 
-		counters: make(map[string]*counter),
-		gauges:   make(map[string]*gauge),
+		counters:   make(map[string]*counter),
+		gauges:     make(map[string]*gauge),
+		histograms: make(map[string]*histogram),
 	}
 
 	s.registry.subScopes[key] = subScope
@@ -237,8 +273,9 @@ func (s *scope) SubScope(prefix string) Scope {
 		tallyScope: s.tallyScope.SubScope(prefix),
 		registry:   s.registry,
 
-		counters: make(map[string]*counter),
-		gauges:   make(map[string]*gauge),
+		counters:   make(map[string]*counter),
+		gauges:     make(map[string]*gauge),
+		histograms: make(map[string]*histogram),
 	}
 
 	s.registry.subScopes[key] = subScope
@@ -390,11 +427,17 @@ func (r *promReporter) Flush() {
 }
 
 func (r *promReporter) Close() error {
+	if r.server == nil {
+		return nil
+	}
 	//TODO: Timeout here?
 	return r.server.Shutdown(context.Background())
 }
 
 func (r *promReporter) Start() error {
+	if r.server == nil {
+		return nil
+	}
 	return r.server.ListenAndServe()
 }
 
@@ -50,14 +50,21 @@ func (m *testFloatValue) ReportGauge(value float64) {
 	m.reporter.gg.Done()
 }
 
+type testHistogramValue struct {
+	samples map[time.Duration]int64
+	tags    map[string]string
+}
+
 type testStatsReporter struct {
 	cg sync.WaitGroup
 	gg sync.WaitGroup
+	hg sync.WaitGroup
 
 	scope Scope
 
-	counters map[string]*testIntValue
-	gauges   map[string]*testFloatValue
+	counters   map[string]*testIntValue
+	gauges     map[string]*testFloatValue
+	histograms map[string]*testHistogramValue
 
 	flushes int32
 }
@@ -65,8 +72,9 @@ type testStatsReporter struct {
 // newTestStatsReporter returns a new TestStatsReporter
 func newTestStatsReporter() *testStatsReporter {
 	return &testStatsReporter{
-		counters: make(map[string]*testIntValue),
-		gauges:   make(map[string]*testFloatValue)}
+		counters:   make(map[string]*testIntValue),
+		gauges:     make(map[string]*testFloatValue),
+		histograms: make(map[string]*testHistogramValue)}
 }
 
 func (r *testStatsReporter) WaitAll() {
@@ -151,7 +159,13 @@ func (r *testStatsReporter) ReportHistogramDurationSamples(
 	bucketUpperBound time.Duration,
 	samples int64,
 ) {
-
+	h, ok := r.histograms[name]
+	if !ok {
+		h = &testHistogramValue{samples: make(map[time.Duration]int64), tags: tags}
+		r.histograms[name] = h
+	}
+	h.samples[bucketUpperBound] += samples
+	r.hg.Done()
 }
 
 func (r *testStatsReporter) Capabilities() tally.Capabilities {
@@ -245,6 +259,31 @@ func TestMultiGaugeReport(t *testing.T) {
 	assert.Equal(t, float64(3.33), r.gauges[namespace+".foo"].val)
 }
 
+func TestHistogram(t *testing.T) {
+	t.Parallel()
+	r := newTestStatsReporter()
+	opts := tally.ScopeOptions{
+		Prefix:    namespace,
+		Separator: tally.DefaultSeparator,
+		Reporter:  r}
+
+	s := newRootScope(opts, 1*time.Second)
+	go s.Start()
+	defer s.Close()
+
+	// the two samples fall in two distinct buckets
+	r.hg.Add(2)
+	s.Histogram("foo").RecordDuration(3 * time.Millisecond)
+	s.Histogram("foo").RecordDuration(3 * time.Millisecond)
+	s.Histogram("foo").RecordDuration(100 * time.Millisecond)
+	r.hg.Wait()
+
+	assert.Equal(t, map[time.Duration]int64{
+		4 * time.Millisecond:   2,
+		128 * time.Millisecond: 1,
+	}, r.histograms[namespace+".foo"].samples)
+}
+
 func TestSubScope(t *testing.T) {
 	t.Parallel()
 	r := newTestStatsReporter()
@@ -0,0 +1,192 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TestScope is a Scope that keeps the values of its metrics in memory
+// so that tests can assert on what the components they exercise report
+type TestScope struct {
+	prefix string
+	tags   map[string]string
+	values *testValues
+}
+
+type testValues struct {
+	lock       sync.Mutex
+	counters   map[string]int64
+	gauges     map[string][]float64
+	histograms map[string][]time.Duration
+}
+
+// NewTestScope creates an empty TestScope
+func NewTestScope() *TestScope {
+	return &TestScope{
+		tags: map[string]string{},
+		values: &testValues{
+			counters:   map[string]int64{},
+			gauges:     map[string][]float64{},
+			histograms: map[string][]time.Duration{},
+		},
+	}
+}
+
+// UseTestScope installs a new TestScope as the root scope and returns it,
+// along with a function that restores the previous root scope. Components
+// pick up the root scope when they are created, so they must be created
+// after UseTestScope is called
+func UseTestScope() (*TestScope, func()) {
+	ts := NewTestScope()
+	rootScopeMutex.Lock()
+	previous := RootScope
+	RootScope = ts
+	rootScopeMutex.Unlock()
+	return ts, func() {
+		rootScopeMutex.Lock()
+		RootScope = previous
+		rootScopeMutex.Unlock()
+	}
+}
+
+// Counter returns the Counter object corresponding to the name
+func (s *TestScope) Counter(name string) Counter {
+	return &testCounter{values: s.values, key: s.key(name, nil)}
+}
+
+// Gauge returns the Gauge object corresponding to the name
+func (s *TestScope) Gauge(name string) Gauge {
+	return &testGauge{values: s.values, key: s.key(name, nil)}
+}
+
+// Histogram returns the Histogram object corresponding to the name
+func (s *TestScope) Histogram(name string) Histogram {
+	return &testHistogram{values: s.values, key: s.key(name, nil)}
+}
+
+// Tagged returns a new child Scope with the given tags and current tags
+func (s *TestScope) Tagged(tags map[string]string) Scope {
+	merged := make(map[string]string, len(s.tags)+len(tags))
+	for k, v := range s.tags {
+		merged[k] = v
+	}
+	for k, v := range tags {
+		merged[k] = v
+	}
+	return &TestScope{prefix: s.prefix, tags: merged, values: s.values}
+}
+
+// SubScope returns a new child Scope appending a further name prefix
+func (s *TestScope) SubScope(name string) Scope {
+	return &TestScope{prefix: s.fullName(name), tags: s.tags, values: s.values}
+}
+
+// Start does nothing, as a TestScope does not report anywhere
+func (s *TestScope) Start() error {
+	return nil
+}
+
+// Close does nothing, as a TestScope does not report anywhere
+func (s *TestScope) Close() error {
+	return nil
+}
+
+// CounterValue returns the value of the counter with the given name, relative
+// to this scope, and the given tags in addition to the tags of this scope
+func (s *TestScope) CounterValue(name string, tags map[string]string) int64 {
+	s.values.lock.Lock()
+	defer s.values.lock.Unlock()
+	return s.values.counters[s.key(name, tags)]
+}
+
+// GaugeValue returns the last value of the gauge with the given name, relative
+// to this scope, and the given tags in addition to the tags of this scope
+func (s *TestScope) GaugeValue(name string, tags map[string]string) float64 {
+	values := s.GaugeValues(name, tags)
+	if len(values) == 0 {
+		return 0
+	}
+	return values[len(values)-1]
+}
+
+// GaugeValues returns all the values, in order, the gauge with the given name was updated with,
+// relative to this scope, and the given tags in addition to the tags of this scope
+func (s *TestScope) GaugeValues(name string, tags map[string]string) []float64 {
+	s.values.lock.Lock()
+	defer s.values.lock.Unlock()
+	return append([]float64(nil), s.values.gauges[s.key(name, tags)]...)
+}
+
+// HistogramValues returns the durations recorded by the histogram with the given name,
+// relative to this scope, and the given tags in addition to the tags of this scope
+func (s *TestScope) HistogramValues(name string, tags map[string]string) []time.Duration {
+	s.values.lock.Lock()
+	defer s.values.lock.Unlock()
+	return append([]time.Duration(nil), s.values.histograms[s.key(name, tags)]...)
+}
+
+func (s *TestScope) fullName(name string) string {
+	if s.prefix == "" {
+		return name
+	}
+	return s.prefix + "." + name
+}
+
+// key identifies a metric by its full name and its sorted tags
+func (s *TestScope) key(name string, tags map[string]string) string {
+	merged := make(map[string]string, len(s.tags)+len(tags))
+	for k, v := range s.tags {
+		merged[k] = v
+	}
+	for k, v := range tags {
+		merged[k] = v
+	}
+	pairs := make([]string, 0, len(merged))
+	for k, v := range merged {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(pairs)
+	return fmt.Sprintf("%s{%s}", s.fullName(name), strings.Join(pairs, ","))
+}
+
+type testCounter struct {
+	values *testValues
+	key    string
+}
+
+func (c *testCounter) Inc(delta int64) {
+	c.values.lock.Lock()
+	defer c.values.lock.Unlock()
+	c.values.counters[c.key] += delta
+}
+
+type testGauge struct {
+	values *testValues
+	key    string
+}
+
+func (g *testGauge) Update(value float64) {
+	g.values.lock.Lock()
+	defer g.values.lock.Unlock()
+	g.values.gauges[g.key] = append(g.values.gauges[g.key], value)
+}
+
+type testHistogram struct {
+	values *testValues
+	key    string
+}
+
+func (h *testHistogram) RecordDuration(value time.Duration) {
+	h.values.lock.Lock()
+	defer h.values.lock.Unlock()
+	h.values.histograms[h.key] = append(h.values.histograms[h.key], value)
+}
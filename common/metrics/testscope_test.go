@@ -0,0 +1,39 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTestScope(t *testing.T) {
+	t.Parallel()
+	ts := NewTestScope()
+
+	sub := ts.SubScope("component").Tagged(map[string]string{"channel": "mychannel"})
+	sub.Counter("requests").Inc(1)
+	sub.Tagged(map[string]string{"status": "ok"}).Counter("requests").Inc(2)
+	sub.Tagged(map[string]string{"status": "ok"}).Counter("requests").Inc(3)
+	sub.Gauge("height").Update(4)
+	sub.Gauge("height").Update(5)
+	sub.Histogram("duration").RecordDuration(time.Second)
+
+	assert.Equal(t, int64(1), ts.CounterValue("component.requests", map[string]string{"channel": "mychannel"}))
+	assert.Equal(t, int64(5), ts.CounterValue("component.requests", map[string]string{"channel": "mychannel", "status": "ok"}))
+	assert.Equal(t, int64(0), ts.CounterValue("component.requests", map[string]string{"channel": "otherchannel"}))
+	assert.Equal(t, float64(5), ts.GaugeValue("component.height", map[string]string{"channel": "mychannel"}))
+	assert.Equal(t, []float64{4, 5}, ts.GaugeValues("component.height", map[string]string{"channel": "mychannel"}))
+	assert.Equal(t, []time.Duration{time.Second}, ts.HistogramValues("component.duration", map[string]string{"channel": "mychannel"}))
+
+	// values can also be read relative to a child scope
+	child := sub.(*TestScope)
+	assert.Equal(t, int64(5), child.CounterValue("requests", map[string]string{"status": "ok"}))
+	assert.Equal(t, float64(5), child.GaugeValue("height", nil))
+}
@@ -17,6 +17,8 @@ import (
 type Orderer struct {
 	// ConsensusTypeVal is returned as the result of ConsensusType()
 	ConsensusTypeVal string
+	// ConsensusMetadataVal is returned as the result of ConsensusMetadata()
+	ConsensusMetadataVal []byte
 	// BatchSizeVal is returned as the result of BatchSize()
 	BatchSizeVal *ab.BatchSize
 	// BatchTimeoutVal is returned as the result of BatchTimeout()
@@ -36,6 +38,11 @@ func (scm *Orderer) ConsensusType() string {
 	return scm.ConsensusTypeVal
 }
 
+// ConsensusMetadata returns the ConsensusMetadataVal
+func (scm *Orderer) ConsensusMetadata() []byte {
+	return scm.ConsensusMetadataVal
+}
+
 // BatchSize returns the BatchSizeVal
 func (scm *Orderer) BatchSize() *ab.BatchSize {
 	return scm.BatchSizeVal
@@ -20,6 +20,7 @@ import (
 	"fmt"
 
 	"github.com/hyperledger/fabric/common/ledger"
+	coreledger "github.com/hyperledger/fabric/core/ledger"
 )
 
 type MockQueryExecutor struct {
@@ -56,6 +57,14 @@ func (m *MockQueryExecutor) ExecuteQuery(namespace, query string) (ledger.Result
 	return nil, nil
 }
 
+func (m *MockQueryExecutor) GetStateRangeScanIteratorWithPagination(namespace, startKey, endKey string, pageSize int32, bookmark string) (coreledger.QueryResultsIterator, error) {
+	return nil, nil
+}
+
+func (m *MockQueryExecutor) ExecuteQueryWithPagination(namespace, query string, pageSize int32, bookmark string) (coreledger.QueryResultsIterator, error) {
+	return nil, nil
+}
+
 func (m *MockQueryExecutor) GetPrivateData(namespace, collection, key string) ([]byte, error) {
 	return nil, nil
 }
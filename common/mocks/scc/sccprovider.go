@@ -21,6 +21,7 @@ import (
 	lm "github.com/hyperledger/fabric/common/mocks/ledger"
 	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
+	"github.com/hyperledger/fabric/core/common/validation/statebased"
 	"github.com/hyperledger/fabric/core/ledger"
 )
 
@@ -52,6 +53,7 @@ type MocksccProviderImpl struct {
 	PolicyManagerRv       policies.Manager
 	PolicyManagerBool     bool
 	SysCCMap              map[string]bool
+	VPMgr                 statebased.KeyLevelValidationParameterManager
 }
 
 func (c *MocksccProviderImpl) IsSysCC(name string) bool {
@@ -81,3 +83,7 @@ func (c *MocksccProviderImpl) GetApplicationConfig(cid string) (channelconfig.Ap
 func (c *MocksccProviderImpl) PolicyManager(channelID string) (policies.Manager, bool) {
 	return c.PolicyManagerRv, c.PolicyManagerBool
 }
+
+func (c *MocksccProviderImpl) GetValidationParameterManager() statebased.KeyLevelValidationParameterManager {
+	return c.VPMgr
+}
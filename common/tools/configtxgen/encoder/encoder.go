@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package encoder
 
 import (
+	"io/ioutil"
+
 	"github.com/hyperledger/fabric/common/cauthdsl"
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/crypto"
@@ -18,6 +20,7 @@ import (
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/msp"
 	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/orderer/etcdraft"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
 
@@ -44,6 +47,8 @@ const (
 	ConsensusTypeSolo = "solo"
 	// ConsensusTypeKafka identifies the Kafka-based consensus implementation.
 	ConsensusTypeKafka = "kafka"
+	// ConsensusTypeEtcdRaft identifies the etcd/raft-based consensus implementation.
+	ConsensusTypeEtcdRaft = "etcdraft"
 
 	// BlockValidationPolicyKey TODO
 	BlockValidationPolicyKey = "BlockValidation"
@@ -200,7 +205,6 @@ func NewOrdererGroup(conf *genesisconfig.Orderer) (*cb.ConfigGroup, error) {
 		Policy:    policies.ImplicitMetaAnyPolicy(channelconfig.WritersPolicyKey).Value(),
 		ModPolicy: channelconfig.AdminsPolicyKey,
 	}
-	addValue(ordererGroup, channelconfig.ConsensusTypeValue(conf.OrdererType), channelconfig.AdminsPolicyKey)
 	addValue(ordererGroup, channelconfig.BatchSizeValue(
 		conf.BatchSize.MaxMessageCount,
 		conf.BatchSize.AbsoluteMaxBytes,
@@ -213,13 +217,20 @@ func NewOrdererGroup(conf *genesisconfig.Orderer) (*cb.ConfigGroup, error) {
 		addValue(ordererGroup, channelconfig.CapabilitiesValue(conf.Capabilities), channelconfig.AdminsPolicyKey)
 	}
 
+	var consensusMetadata []byte
 	switch conf.OrdererType {
 	case ConsensusTypeSolo:
 	case ConsensusTypeKafka:
 		addValue(ordererGroup, channelconfig.KafkaBrokersValue(conf.Kafka.Brokers), channelconfig.AdminsPolicyKey)
+	case ConsensusTypeEtcdRaft:
+		var err error
+		if consensusMetadata, err = marshalEtcdRaftMetadata(conf.EtcdRaft); err != nil {
+			return nil, errors.Errorf("cannot marshal metadata for orderer type %s: %s", ConsensusTypeEtcdRaft, err)
+		}
 	default:
 		return nil, errors.Errorf("unknown orderer type: %s", conf.OrdererType)
 	}
+	addValue(ordererGroup, channelconfig.ConsensusTypeValue(conf.OrdererType, consensusMetadata), channelconfig.AdminsPolicyKey)
 
 	for _, org := range conf.Organizations {
 		var err error
@@ -233,6 +244,41 @@ func NewOrdererGroup(conf *genesisconfig.Orderer) (*cb.ConfigGroup, error) {
 	return ordererGroup, nil
 }
 
+// marshalEtcdRaftMetadata serializes the etcd/raft consenter set and options,
+// embedding the contents of the TLS certificate files referenced by the consenters.
+func marshalEtcdRaftMetadata(conf *genesisconfig.EtcdRaft) ([]byte, error) {
+	if conf == nil {
+		return nil, errors.New("missing EtcdRaft configuration")
+	}
+	md := &etcdraft.Metadata{
+		Options: &etcdraft.Options{
+			TickInterval:         conf.Options.TickInterval,
+			ElectionTick:         conf.Options.ElectionTick,
+			HeartbeatTick:        conf.Options.HeartbeatTick,
+			MaxInflightMsgs:      conf.Options.MaxInflightMsgs,
+			MaxSizePerMsg:        conf.Options.MaxSizePerMsg,
+			SnapshotIntervalSize: conf.Options.SnapshotIntervalSize,
+		},
+	}
+	for _, c := range conf.Consenters {
+		clientCert, err := ioutil.ReadFile(c.ClientTLSCert)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot load client cert for consenter %s:%d", c.Host, c.Port)
+		}
+		serverCert, err := ioutil.ReadFile(c.ServerTLSCert)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot load server cert for consenter %s:%d", c.Host, c.Port)
+		}
+		md.Consenters = append(md.Consenters, &etcdraft.Consenter{
+			Host:          c.Host,
+			Port:          c.Port,
+			ClientTlsCert: clientCert,
+			ServerTlsCert: serverCert,
+		})
+	}
+	return proto.Marshal(md)
+}
+
 // NewOrdererOrgGroup returns an orderer org component of the channel configuration.  It defines the crypto material for the
 // organization (its MSP).  It sets the mod_policy of all elements to "Admins".
 func NewOrdererOrgGroup(conf *genesisconfig.Organization) (*cb.ConfigGroup, error) {
@@ -172,6 +172,7 @@ type Orderer struct {
 	BatchTimeout  time.Duration      `yaml:"BatchTimeout"`
 	BatchSize     BatchSize          `yaml:"BatchSize"`
 	Kafka         Kafka              `yaml:"Kafka"`
+	EtcdRaft      *EtcdRaft          `yaml:"EtcdRaft"`
 	Organizations []*Organization    `yaml:"Organizations"`
 	MaxChannels   uint64             `yaml:"MaxChannels"`
 	Capabilities  map[string]bool    `yaml:"Capabilities"`
@@ -190,6 +191,31 @@ type Kafka struct {
 	Brokers []string `yaml:"Brokers"`
 }
 
+// EtcdRaft contains configuration for the etcd/raft-based orderer.
+type EtcdRaft struct {
+	Consenters []*Consenter    `yaml:"Consenters"`
+	Options    EtcdRaftOptions `yaml:"Options"`
+}
+
+// Consenter identifies a member of the etcd/raft consenter set. The TLS
+// certificates are given as paths to PEM files.
+type Consenter struct {
+	Host          string `yaml:"Host"`
+	Port          uint32 `yaml:"Port"`
+	ClientTLSCert string `yaml:"ClientTLSCert"`
+	ServerTLSCert string `yaml:"ServerTLSCert"`
+}
+
+// EtcdRaftOptions contains the tuning parameters shared by all etcd/raft nodes of a channel.
+type EtcdRaftOptions struct {
+	TickInterval         string `yaml:"TickInterval"`
+	ElectionTick         uint32 `yaml:"ElectionTick"`
+	HeartbeatTick        uint32 `yaml:"HeartbeatTick"`
+	MaxInflightMsgs      uint32 `yaml:"MaxInflightMsgs"`
+	MaxSizePerMsg        uint64 `yaml:"MaxSizePerMsg"`
+	SnapshotIntervalSize uint64 `yaml:"SnapshotIntervalSize"`
+}
+
 var genesisDefaults = TopLevel{
 	Orderer: &Orderer{
 		OrdererType:  "solo",
@@ -203,6 +229,16 @@ var genesisDefaults = TopLevel{
 		Kafka: Kafka{
 			Brokers: []string{"127.0.0.1:9092"},
 		},
+		EtcdRaft: &EtcdRaft{
+			Options: EtcdRaftOptions{
+				TickInterval:         "500ms",
+				ElectionTick:         10,
+				HeartbeatTick:        1,
+				MaxInflightMsgs:      256,
+				MaxSizePerMsg:        1024 * 1024,
+				SnapshotIntervalSize: 20 * 1024 * 1024,
+			},
+		},
 	},
 }
 
@@ -302,7 +338,7 @@ func (t *TopLevel) completeInitialization(configDir string) {
 	}
 
 	if t.Orderer != nil {
-		t.Orderer.completeInitialization()
+		t.Orderer.completeInitialization(configDir)
 	}
 }
 
@@ -332,7 +368,7 @@ func (p *Profile) completeInitialization(configDir string) {
 
 	// Some profiles will not define orderer parameters
 	if p.Orderer != nil {
-		p.Orderer.completeInitialization()
+		p.Orderer.completeInitialization(configDir)
 	}
 }
 
@@ -359,7 +395,7 @@ func (org *Organization) completeInitialization(configDir string) {
 	translatePaths(configDir, org)
 }
 
-func (oc *Orderer) completeInitialization() {
+func (oc *Orderer) completeInitialization(configDir string) {
 	for {
 		switch {
 		case oc.OrdererType == "":
@@ -383,7 +419,46 @@ func (oc *Orderer) completeInitialization() {
 		case oc.Kafka.Brokers == nil:
 			logger.Infof("Orderer.Kafka.Brokers unset, setting to %v", genesisDefaults.Orderer.Kafka.Brokers)
 			oc.Kafka.Brokers = genesisDefaults.Orderer.Kafka.Brokers
+		case oc.OrdererType == "etcdraft" && oc.EtcdRaft == nil:
+			logger.Panicf("Orderer.EtcdRaft must be set if Orderer.OrdererType is set to etcdraft")
+		case oc.OrdererType == "etcdraft" && len(oc.EtcdRaft.Consenters) == 0:
+			logger.Panicf("Orderer.EtcdRaft.Consenters must be set if Orderer.OrdererType is set to etcdraft")
 		default:
+			if oc.EtcdRaft != nil {
+				oc.EtcdRaft.completeInitialization(configDir)
+			}
+			return
+		}
+	}
+}
+
+func (er *EtcdRaft) completeInitialization(configDir string) {
+	defaults := genesisDefaults.Orderer.EtcdRaft.Options
+	for {
+		switch {
+		case er.Options.TickInterval == "":
+			logger.Infof("Orderer.EtcdRaft.Options.TickInterval unset, setting to %v", defaults.TickInterval)
+			er.Options.TickInterval = defaults.TickInterval
+		case er.Options.ElectionTick == 0:
+			logger.Infof("Orderer.EtcdRaft.Options.ElectionTick unset, setting to %v", defaults.ElectionTick)
+			er.Options.ElectionTick = defaults.ElectionTick
+		case er.Options.HeartbeatTick == 0:
+			logger.Infof("Orderer.EtcdRaft.Options.HeartbeatTick unset, setting to %v", defaults.HeartbeatTick)
+			er.Options.HeartbeatTick = defaults.HeartbeatTick
+		case er.Options.MaxInflightMsgs == 0:
+			logger.Infof("Orderer.EtcdRaft.Options.MaxInflightMsgs unset, setting to %v", defaults.MaxInflightMsgs)
+			er.Options.MaxInflightMsgs = defaults.MaxInflightMsgs
+		case er.Options.MaxSizePerMsg == 0:
+			logger.Infof("Orderer.EtcdRaft.Options.MaxSizePerMsg unset, setting to %v", defaults.MaxSizePerMsg)
+			er.Options.MaxSizePerMsg = defaults.MaxSizePerMsg
+		case er.Options.SnapshotIntervalSize == 0:
+			logger.Infof("Orderer.EtcdRaft.Options.SnapshotIntervalSize unset, setting to %v", defaults.SnapshotIntervalSize)
+			er.Options.SnapshotIntervalSize = defaults.SnapshotIntervalSize
+		default:
+			for _, c := range er.Consenters {
+				cf.TranslatePathInPlace(configDir, &c.ClientTLSCert)
+				cf.TranslatePathInPlace(configDir, &c.ServerTLSCert)
+			}
 			return
 		}
 	}
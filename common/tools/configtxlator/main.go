@@ -18,14 +18,17 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"os"
 	"reflect"
+	"strings"
 
 	"github.com/hyperledger/fabric/common/tools/configtxlator/metadata"
 	"github.com/hyperledger/fabric/common/tools/configtxlator/rest"
 	"github.com/hyperledger/fabric/common/tools/configtxlator/update"
+	"github.com/hyperledger/fabric/common/tools/configtxlator/workflow"
 	"github.com/hyperledger/fabric/common/tools/protolator"
 	cb "github.com/hyperledger/fabric/protos/common"
 
@@ -61,6 +64,20 @@ var (
 	computeUpdateChannelID = computeUpdate.Flag("channel_id", "The name of the channel for this update.").Required().String()
 	computeUpdateDest      = computeUpdate.Flag("output", "A file to write the JSON document to.").Default(os.Stdout.Name()).OpenFile(os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
 
+	patchUpdate      = app.Command("patch_update", "Applies a declarative patch to the config of a config block and computes the CONFIG_UPDATE envelope which transitions to it.")
+	patchUpdateBlock = patchUpdate.Flag("block", "The config block, as fetched with 'peer channel fetch config'.").Required().File()
+	patchUpdatePatch = patchUpdate.Flag("patch", "A YAML file describing the changes to the config.").Required().String()
+	patchUpdateDest  = patchUpdate.Flag("output", "A file to write the unsigned envelope to.").Default(os.Stdout.Name()).OpenFile(os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+
+	evaluateUpdate       = app.Command("evaluate_update", "Evaluates the signatures on a CONFIG_UPDATE envelope against the config of a config block, without submitting it.")
+	evaluateUpdateBlock  = evaluateUpdate.Flag("block", "The latest config block of the channel, as fetched with 'peer channel fetch config'.").Required().File()
+	evaluateUpdateUpdate = evaluateUpdate.Flag("update", "The CONFIG_UPDATE envelope.").Required().File()
+	evaluateUpdateDest   = evaluateUpdate.Flag("output", "A file to write the report to.").Default(os.Stdout.Name()).OpenFile(os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+
+	mergeSignatures       = app.Command("merge_signatures", "Merges the signatures of several CONFIG_UPDATE envelopes carrying the same config update.")
+	mergeSignaturesSource = mergeSignatures.Flag("input", "A CONFIG_UPDATE envelope. May be repeated.").Required().ExistingFiles()
+	mergeSignaturesDest   = mergeSignatures.Flag("output", "A file to write the merged envelope to.").Default(os.Stdout.Name()).OpenFile(os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+
 	version = app.Command("version", "Show version information")
 )
 
@@ -93,6 +110,27 @@ func main() {
 		if err != nil {
 			app.Fatalf("Error computing update: %s", err)
 		}
+	case patchUpdate.FullCommand():
+		defer (*patchUpdateBlock).Close()
+		defer (*patchUpdateDest).Close()
+		err := patchUpdt(*patchUpdateBlock, *patchUpdatePatch, *patchUpdateDest, os.Stderr)
+		if err != nil {
+			app.Fatalf("Error computing update: %s", err)
+		}
+	case evaluateUpdate.FullCommand():
+		defer (*evaluateUpdateBlock).Close()
+		defer (*evaluateUpdateUpdate).Close()
+		defer (*evaluateUpdateDest).Close()
+		err := evaluateUpdt(*evaluateUpdateBlock, *evaluateUpdateUpdate, *evaluateUpdateDest)
+		if err != nil {
+			app.Fatalf("Error evaluating update: %s", err)
+		}
+	case mergeSignatures.FullCommand():
+		defer (*mergeSignaturesDest).Close()
+		err := mergeSigs(*mergeSignaturesSource, *mergeSignaturesDest)
+		if err != nil {
+			app.Fatalf("Error merging signatures: %s", err)
+		}
 	// "version" command
 	case version.FullCommand():
 		printVersion()
@@ -203,3 +241,162 @@ func computeUpdt(original, updated, output *os.File, channelID string) error {
 
 	return nil
 }
+
+func readConfigBlock(block *os.File) (string, *cb.Config, error) {
+	blockIn, err := ioutil.ReadAll(block)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "error reading config block")
+	}
+
+	configBlock := &cb.Block{}
+	err = proto.Unmarshal(blockIn, configBlock)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "error unmarshaling config block")
+	}
+
+	return workflow.ConfigFromBlock(configBlock)
+}
+
+func readEnvelope(input *os.File) (*cb.Envelope, error) {
+	envIn, err := ioutil.ReadAll(input)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading envelope %s", input.Name())
+	}
+
+	env := &cb.Envelope{}
+	err = proto.Unmarshal(envIn, env)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error unmarshaling envelope %s", input.Name())
+	}
+
+	return env, nil
+}
+
+func patchUpdt(block *os.File, patchFile string, output *os.File, report io.Writer) error {
+	channelID, origConf, err := readConfigBlock(block)
+	if err != nil {
+		return err
+	}
+
+	patch, err := workflow.LoadPatch(patchFile)
+	if err != nil {
+		return err
+	}
+
+	updtConf, err := patch.Apply(origConf)
+	if err != nil {
+		return errors.WithMessage(err, "error applying patch")
+	}
+
+	cu, err := workflow.ComputeUpdate(channelID, origConf, updtConf)
+	if err != nil {
+		return errors.Wrapf(err, "error computing config update")
+	}
+
+	requirements, err := workflow.RequiredPolicies(origConf, cu)
+	if err != nil {
+		return errors.WithMessage(err, "error computing required policies")
+	}
+
+	env, err := workflow.NewConfigUpdateEnvelope(cu)
+	if err != nil {
+		return errors.WithMessage(err, "error creating config update envelope")
+	}
+
+	outBytes, err := proto.Marshal(env)
+	if err != nil {
+		return errors.Wrapf(err, "error marshaling config update envelope")
+	}
+
+	_, err = output.Write(outBytes)
+	if err != nil {
+		return errors.Wrapf(err, "error writing config update envelope to output")
+	}
+
+	fmt.Fprintf(report, "Config update for channel %s requires signatures satisfying the following mod_policies:\n", channelID)
+	for _, requirement := range requirements {
+		fmt.Fprintf(report, "  %s\n", requirement.Policy)
+		for _, element := range requirement.Elements {
+			fmt.Fprintf(report, "      %s\n", element)
+		}
+	}
+
+	return nil
+}
+
+func evaluateUpdt(block, update *os.File, output io.Writer) error {
+	channelID, conf, err := readConfigBlock(block)
+	if err != nil {
+		return err
+	}
+
+	env, err := readEnvelope(update)
+	if err != nil {
+		return err
+	}
+
+	evaluation, err := workflow.EvaluateUpdate(channelID, conf, env)
+	if err != nil {
+		return err
+	}
+
+	for _, pe := range evaluation.Policies {
+		status := "NOT SATISFIED"
+		if pe.Satisfied {
+			status = "satisfied"
+		}
+		fmt.Fprintf(output, "%s: %s\n", pe.Policy, status)
+		if pe.Rule != "" {
+			fmt.Fprintf(output, "  rule:       %s, %d of %d sub-groups required\n", pe.Rule, pe.Threshold, len(pe.Signed)+len(pe.Unsigned))
+		}
+		if len(pe.Signed) > 0 {
+			fmt.Fprintf(output, "  signed:     %s\n", strings.Join(pe.Signed, ", "))
+		}
+		if !pe.Satisfied && len(pe.Unsigned) > 0 {
+			fmt.Fprintf(output, "  to sign:    %s\n", strings.Join(pe.Unsigned, ", "))
+		}
+		for _, element := range pe.Elements {
+			fmt.Fprintf(output, "  modifies:   %s\n", element)
+		}
+	}
+
+	if evaluation.ValidationError != nil {
+		return errors.WithMessage(evaluation.ValidationError, "the update would be rejected")
+	}
+	fmt.Fprintf(output, "The update would be accepted for channel %s\n", channelID)
+
+	return nil
+}
+
+func mergeSigs(inputs []string, output *os.File) error {
+	var envs []*cb.Envelope
+	for _, input := range inputs {
+		f, err := os.Open(input)
+		if err != nil {
+			return errors.Wrapf(err, "error opening %s", input)
+		}
+		env, err := readEnvelope(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+		envs = append(envs, env)
+	}
+
+	merged, err := workflow.MergeSignatures(envs...)
+	if err != nil {
+		return err
+	}
+
+	outBytes, err := proto.Marshal(merged)
+	if err != nil {
+		return errors.Wrapf(err, "error marshaling merged envelope")
+	}
+
+	_, err = output.Write(outBytes)
+	if err != nil {
+		return errors.Wrapf(err, "error writing merged envelope to output")
+	}
+
+	return nil
+}
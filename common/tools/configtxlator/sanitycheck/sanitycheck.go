@@ -111,6 +111,14 @@ func checkPolicyPrincipals(group *cb.ConfigGroup, basePath string, mspMap map[st
 					continue
 				}
 				mspID = ou.MspIdentifier
+			case mspprotos.MSPPrincipal_IDEMIX_ATTRIBUTES:
+				attributes := &mspprotos.IdemixAttributes{}
+				err = proto.Unmarshal(identity.Principal, attributes)
+				if err != nil {
+					appendError(fmt.Sprintf("value of identities array at index %d is of type IDEMIX_ATTRIBUTES, but could not be unmarshaled to msp.IdemixAttributes: %s", i, err))
+					continue
+				}
+				mspID = attributes.MspIdentifier
 			default:
 				continue
 			}
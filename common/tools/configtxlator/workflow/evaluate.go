@@ -0,0 +1,267 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package workflow
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/msp"
+	cb "github.com/hyperledger/fabric/protos/common"
+	mspprotos "github.com/hyperledger/fabric/protos/msp"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// PolicyEvaluation is the outcome of evaluating a mod_policy required by a config update
+// against the signatures the update carries.
+type PolicyEvaluation struct {
+	PolicyRequirement
+
+	// Satisfied is true if the signatures satisfy the policy
+	Satisfied bool
+
+	// Rule describes implicit meta policies, e.g. MAJORITY Admins. It is empty for
+	// signature policies.
+	Rule string
+
+	// Threshold is the number of sub-groups whose sub-policy must be satisfied for
+	// implicit meta policies. It is zero for signature policies.
+	Threshold int
+
+	// Signed lists, for implicit meta policies, the sub-groups (usually organizations)
+	// whose sub-policy is satisfied. For signature policies it lists the principals of
+	// the policy, e.g. Org1MSP.admin, satisfied by an identity which validly signed the update.
+	Signed []string
+
+	// Unsigned lists, for implicit meta policies, the sub-groups whose sub-policy is not
+	// satisfied. For signature policies it lists the principals of the policy not
+	// satisfied by any identity which validly signed the update.
+	Unsigned []string
+}
+
+// Evaluation is the outcome of evaluating a config update against a channel config.
+type Evaluation struct {
+	// Policies holds the evaluation of every mod_policy the update requires, sorted by policy
+	Policies []*PolicyEvaluation
+
+	// ValidationError is the reason why the update would be rejected by the config
+	// validator of the channel, or nil if the update would be accepted
+	ValidationError error
+}
+
+// EvaluateUpdate evaluates the signatures on an envelope of type CONFIG_UPDATE against the
+// channel config of the given channel, without submitting it. It reports for every
+// modified element whether its mod_policy is satisfied and who still needs to sign, and
+// whether the config validator of the channel would accept the update as a whole.
+func EvaluateUpdate(channelID string, config *cb.Config, env *cb.Envelope) (*Evaluation, error) {
+	updateChannelID, configUpdateEnv, err := UnmarshalConfigUpdateEnvelope(env)
+	if err != nil {
+		return nil, err
+	}
+	if updateChannelID != channelID {
+		return nil, errors.Errorf("config update is for channel %s, but the config is of channel %s", updateChannelID, channelID)
+	}
+
+	configUpdate, err := configtx.UnmarshalConfigUpdate(configUpdateEnv.ConfigUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle, err := channelconfig.NewBundle(channelID, config)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error creating channel config")
+	}
+
+	requirements, err := RequiredPolicies(config, configUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	signedData, err := configUpdateEnv.AsSignedData()
+	if err != nil {
+		return nil, err
+	}
+	signers := validSigners(bundle.MSPManager(), signedData)
+
+	evaluation := &Evaluation{}
+	for _, requirement := range requirements {
+		policyEvaluation, err := evaluatePolicy(bundle, config, requirement, signedData, signers)
+		if err != nil {
+			return nil, err
+		}
+		evaluation.Policies = append(evaluation.Policies, policyEvaluation)
+	}
+
+	evaluation.ValidationError = validateUpdate(channelID, bundle, env)
+	return evaluation, nil
+}
+
+// validateUpdate returns the error the orderer would reject the update with
+func validateUpdate(channelID string, bundle *channelconfig.Bundle, env *cb.Envelope) error {
+	configEnv, err := bundle.ConfigtxValidator().ProposeConfigUpdate(env)
+	if err != nil {
+		return err
+	}
+
+	newBundle, err := channelconfig.NewBundle(channelID, configEnv.Config)
+	if err != nil {
+		return errors.WithMessage(err, "the updated config is invalid")
+	}
+
+	return bundle.ValidateNew(newBundle)
+}
+
+func evaluatePolicy(bundle *channelconfig.Bundle, config *cb.Config, requirement *PolicyRequirement, signedData []*cb.SignedData, signers []msp.Identity) (*PolicyEvaluation, error) {
+	evaluation := &PolicyEvaluation{PolicyRequirement: *requirement}
+
+	policy, ok := bundle.PolicyManager().GetPolicy(requirement.Policy)
+	if !ok {
+		return nil, errors.Errorf("policy %s does not exist", requirement.Policy)
+	}
+	evaluation.Satisfied = policy.Evaluate(signedData) == nil
+
+	path := strings.Split(strings.TrimPrefix(requirement.Policy, pathSeparator), pathSeparator)
+	group, configPolicy := lookupPolicy(config.ChannelGroup, path)
+	if configPolicy == nil || configPolicy.Policy == nil {
+		return nil, errors.Errorf("policy %s does not exist", requirement.Policy)
+	}
+
+	switch cb.Policy_PolicyType(configPolicy.Policy.Type) {
+	case cb.Policy_IMPLICIT_META:
+		imp := &cb.ImplicitMetaPolicy{}
+		if err := proto.Unmarshal(configPolicy.Policy.Value, imp); err != nil {
+			return nil, errors.Wrapf(err, "error unmarshaling implicit meta policy %s", requirement.Policy)
+		}
+		evaluation.Rule = imp.Rule.String() + " " + imp.SubPolicy
+
+		groupPath := strings.Join(path[:len(path)-1], pathSeparator)
+		subGroups := make([]string, 0, len(group.Groups))
+		for subGroup := range group.Groups {
+			subGroups = append(subGroups, subGroup)
+		}
+		sort.Strings(subGroups)
+
+		switch imp.Rule {
+		case cb.ImplicitMetaPolicy_ANY:
+			evaluation.Threshold = 1
+		case cb.ImplicitMetaPolicy_ALL:
+			evaluation.Threshold = len(subGroups)
+		case cb.ImplicitMetaPolicy_MAJORITY:
+			evaluation.Threshold = len(subGroups)/2 + 1
+		}
+		if len(subGroups) == 0 {
+			evaluation.Threshold = 0
+		}
+
+		for _, subGroup := range subGroups {
+			subPolicy, ok := bundle.PolicyManager().GetPolicy(pathSeparator + groupPath + pathSeparator + subGroup + pathSeparator + imp.SubPolicy)
+			if ok && subPolicy.Evaluate(signedData) == nil {
+				evaluation.Signed = append(evaluation.Signed, subGroup)
+			} else {
+				evaluation.Unsigned = append(evaluation.Unsigned, subGroup)
+			}
+		}
+	case cb.Policy_SIGNATURE:
+		spe := &cb.SignaturePolicyEnvelope{}
+		if err := proto.Unmarshal(configPolicy.Policy.Value, spe); err != nil {
+			return nil, errors.Wrapf(err, "error unmarshaling signature policy %s", requirement.Policy)
+		}
+
+		reported := make(map[string]struct{})
+		for _, principal := range spe.Identities {
+			name := principalName(principal)
+			if _, ok := reported[name]; ok {
+				continue
+			}
+			reported[name] = struct{}{}
+			if satisfiesPrincipal(signers, principal) {
+				evaluation.Signed = append(evaluation.Signed, name)
+			} else {
+				evaluation.Unsigned = append(evaluation.Unsigned, name)
+			}
+		}
+	}
+
+	return evaluation, nil
+}
+
+// lookupPolicy returns the policy at the absolute path, given as its elements, and the group
+// which contains it
+func lookupPolicy(root *cb.ConfigGroup, path []string) (*cb.ConfigGroup, *cb.ConfigPolicy) {
+	if len(path) < 2 || path[0] != channelconfig.RootGroupKey {
+		return nil, nil
+	}
+
+	group := root
+	for _, key := range path[1 : len(path)-1] {
+		group = group.Groups[key]
+		if group == nil {
+			return nil, nil
+		}
+	}
+
+	return group, group.Policies[path[len(path)-1]]
+}
+
+// principalName returns the name the principal is reported with, e.g. Org1MSP.admin
+func principalName(principal *mspprotos.MSPPrincipal) string {
+	switch principal.PrincipalClassification {
+	case mspprotos.MSPPrincipal_ROLE:
+		role := &mspprotos.MSPRole{}
+		if proto.Unmarshal(principal.Principal, role) == nil {
+			return role.MspIdentifier + "." + strings.ToLower(role.Role.String())
+		}
+	case mspprotos.MSPPrincipal_ORGANIZATION_UNIT:
+		ou := &mspprotos.OrganizationUnit{}
+		if proto.Unmarshal(principal.Principal, ou) == nil {
+			return ou.MspIdentifier + ".OU=" + ou.OrganizationalUnitIdentifier
+		}
+	case mspprotos.MSPPrincipal_IDENTITY:
+		id := &mspprotos.SerializedIdentity{}
+		if proto.Unmarshal(principal.Principal, id) == nil {
+			return id.Mspid + ".identity"
+		}
+	case mspprotos.MSPPrincipal_IDEMIX_ATTRIBUTES:
+		attributes := &mspprotos.IdemixAttributes{}
+		if proto.Unmarshal(principal.Principal, attributes) == nil {
+			return attributes.MspIdentifier + ".idemix_attributes"
+		}
+	}
+	return principal.PrincipalClassification.String()
+}
+
+// validSigners returns the identities, deserialized with the MSPs of the channel, whose
+// signature in signedData is valid. Signatures that cannot be verified are ignored, as
+// they are when the policies of the channel are evaluated.
+func validSigners(deserializer msp.IdentityDeserializer, signedData []*cb.SignedData) []msp.Identity {
+	var signers []msp.Identity
+	for _, sd := range signedData {
+		identity, err := deserializer.DeserializeIdentity(sd.Identity)
+		if err != nil {
+			continue
+		}
+		if identity.Verify(sd.Data, sd.Signature) != nil {
+			continue
+		}
+		signers = append(signers, identity)
+	}
+	return signers
+}
+
+// satisfiesPrincipal returns whether one of the signers satisfies the principal
+func satisfiesPrincipal(signers []msp.Identity, principal *mspprotos.MSPPrincipal) bool {
+	for _, signer := range signers {
+		if signer.SatisfiesPrincipal(principal) == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,240 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package workflow
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/crypto"
+	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/config/configtest"
+	"github.com/hyperledger/fabric/msp"
+	cb "github.com/hyperledger/fabric/protos/common"
+	mspprotos "github.com/hyperledger/fabric/protos/msp"
+	"github.com/hyperledger/fabric/protos/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSigningIdentity returns the signing identity of the development MSP, set up with the given ID
+func newSigningIdentity(t *testing.T, mspID string) msp.SigningIdentity {
+	mspDir, err := configtest.GetDevMspDir()
+	require.NoError(t, err)
+	conf, err := msp.GetLocalMspConfig(mspDir, nil, mspID)
+	require.NoError(t, err)
+
+	thisMSP, err := msp.New(&msp.BCCSPNewOpts{NewBaseOpts: msp.NewBaseOpts{Version: msp.MSPv1_0}})
+	require.NoError(t, err)
+	require.NoError(t, thisMSP.Setup(conf))
+
+	signer, err := thisMSP.GetDefaultSigningIdentity()
+	require.NoError(t, err)
+	return signer
+}
+
+// signConfigUpdate adds a signature of signer to the config update carried by env, as
+// 'peer channel signconfigtx' does
+func signConfigUpdate(t *testing.T, env *cb.Envelope, signer msp.SigningIdentity) *cb.Envelope {
+	channelID, configUpdateEnv, err := UnmarshalConfigUpdateEnvelope(env)
+	require.NoError(t, err)
+
+	creator, err := signer.Serialize()
+	require.NoError(t, err)
+	nonce, err := crypto.GetRandomNonce()
+	require.NoError(t, err)
+
+	configSig := &cb.ConfigSignature{
+		SignatureHeader: utils.MarshalOrPanic(&cb.SignatureHeader{Creator: creator, Nonce: nonce}),
+	}
+	configSig.Signature, err = signer.Sign(util.ConcatenateBytes(configSig.SignatureHeader, configUpdateEnv.ConfigUpdate))
+	require.NoError(t, err)
+	configUpdateEnv.Signatures = append(configUpdateEnv.Signatures, configSig)
+
+	signed, err := utils.CreateSignedEnvelope(cb.HeaderType_CONFIG_UPDATE, channelID, nil, configUpdateEnv, 0, 0)
+	require.NoError(t, err)
+	return signed
+}
+
+// twoOrgConfig returns a channel config whose application group contains SampleOrg and Org2
+func twoOrgConfig(t *testing.T) *cb.Config {
+	mspDir, err := configtest.GetDevMspDir()
+	require.NoError(t, err)
+
+	patch := &Patch{
+		Application: &ApplicationPatch{
+			AddOrganizations: []*genesisconfig.Organization{orgWithMemberPolicies("Org2", "Org2MSP", mspDir)},
+		},
+	}
+	config, err := patch.Apply(testConfig(t))
+	require.NoError(t, err)
+	return config
+}
+
+func orgWithMemberPolicies(name, mspID, mspDir string) *genesisconfig.Organization {
+	policy := &genesisconfig.Policy{Type: "Signature", Rule: "OR('" + mspID + ".member')"}
+	return &genesisconfig.Organization{
+		Name:           name,
+		ID:             mspID,
+		MSPDir:         mspDir,
+		MSPType:        "bccsp",
+		AdminPrincipal: genesisconfig.AdminRoleAdminPrincipal,
+		Policies: map[string]*genesisconfig.Policy{
+			"Readers": policy,
+			"Writers": policy,
+			"Admins":  policy,
+		},
+	}
+}
+
+func TestEvaluateUpdate(t *testing.T) {
+	mspDir, err := configtest.GetDevMspDir()
+	require.NoError(t, err)
+
+	config := twoOrgConfig(t)
+	patch := &Patch{
+		Application: &ApplicationPatch{
+			AddOrganizations: []*genesisconfig.Organization{orgWithMemberPolicies("Org3", "Org3MSP", mspDir)},
+			AnchorPeers: map[string][]*genesisconfig.AnchorPeer{
+				"Org2": {{Host: "peer0.org2.example.com", Port: 7051}},
+			},
+		},
+		Orderer: &OrdererPatch{
+			BatchSize: &genesisconfig.BatchSize{MaxMessageCount: 50},
+		},
+	}
+	updated, err := patch.Apply(config)
+	require.NoError(t, err)
+	configUpdate, err := ComputeUpdate("testchannel", config, updated)
+	require.NoError(t, err)
+	unsigned, err := NewConfigUpdateEnvelope(configUpdate)
+	require.NoError(t, err)
+
+	evaluation, err := EvaluateUpdate("testchannel", config, unsigned)
+	require.NoError(t, err)
+	require.Len(t, evaluation.Policies, 3)
+	for _, policyEvaluation := range evaluation.Policies {
+		assert.False(t, policyEvaluation.Satisfied, "policy %s", policyEvaluation.Policy)
+	}
+	assert.Error(t, evaluation.ValidationError)
+
+	sampleOrgSigned := signConfigUpdate(t, unsigned, newSigningIdentity(t, genesisconfig.SampleOrgName))
+	evaluation, err = EvaluateUpdate("testchannel", config, sampleOrgSigned)
+	require.NoError(t, err)
+	assert.Equal(t, []*PolicyEvaluation{
+		{
+			PolicyRequirement: PolicyRequirement{
+				Policy:   "/Channel/Application/Admins",
+				Elements: []string{"[Group]  /Channel/Application"},
+			},
+			Satisfied: false,
+			Rule:      "MAJORITY Admins",
+			Threshold: 2,
+			Signed:    []string{"SampleOrg"},
+			Unsigned:  []string{"Org2"},
+		},
+		{
+			PolicyRequirement: PolicyRequirement{
+				Policy:   "/Channel/Application/Org2/Admins",
+				Elements: []string{"[Value]  /Channel/Application/Org2/AnchorPeers"},
+			},
+			Satisfied: false,
+			Unsigned:  []string{"Org2MSP.member"},
+		},
+		{
+			PolicyRequirement: PolicyRequirement{
+				Policy:   "/Channel/Orderer/Admins",
+				Elements: []string{"[Value]  /Channel/Orderer/BatchSize"},
+			},
+			Satisfied: true,
+			Rule:      "MAJORITY Admins",
+			Threshold: 1,
+			Signed:    []string{"SampleOrg"},
+		},
+	}, evaluation.Policies)
+	assert.Error(t, evaluation.ValidationError)
+
+	org2Signed := signConfigUpdate(t, unsigned, newSigningIdentity(t, "Org2MSP"))
+
+	// A signature that does not verify does not satisfy the principals of its signer
+	channelID, forgedEnv, err := UnmarshalConfigUpdateEnvelope(org2Signed)
+	require.NoError(t, err)
+	forgedEnv.Signatures[0].Signature = []byte("forged")
+	forged, err := utils.CreateSignedEnvelope(cb.HeaderType_CONFIG_UPDATE, channelID, nil, forgedEnv, 0, 0)
+	require.NoError(t, err)
+	evaluation, err = EvaluateUpdate("testchannel", config, forged)
+	require.NoError(t, err)
+	require.Len(t, evaluation.Policies, 3)
+	assert.Equal(t, "/Channel/Application/Org2/Admins", evaluation.Policies[1].Policy)
+	assert.False(t, evaluation.Policies[1].Satisfied)
+	assert.Empty(t, evaluation.Policies[1].Signed)
+	assert.Equal(t, []string{"Org2MSP.member"}, evaluation.Policies[1].Unsigned)
+
+	merged, err := MergeSignatures(sampleOrgSigned, org2Signed)
+	require.NoError(t, err)
+
+	evaluation, err = EvaluateUpdate("testchannel", config, merged)
+	require.NoError(t, err)
+	for _, policyEvaluation := range evaluation.Policies {
+		assert.True(t, policyEvaluation.Satisfied, "policy %s", policyEvaluation.Policy)
+		assert.Empty(t, policyEvaluation.Unsigned)
+	}
+	assert.Equal(t, []string{"Org2MSP.member"}, evaluation.Policies[1].Signed)
+	assert.NoError(t, evaluation.ValidationError)
+}
+
+func TestPrincipalName(t *testing.T) {
+	role := &mspprotos.MSPPrincipal{
+		PrincipalClassification: mspprotos.MSPPrincipal_ROLE,
+		Principal:               utils.MarshalOrPanic(&mspprotos.MSPRole{MspIdentifier: "Org1MSP", Role: mspprotos.MSPRole_ADMIN}),
+	}
+	assert.Equal(t, "Org1MSP.admin", principalName(role))
+
+	ou := &mspprotos.MSPPrincipal{
+		PrincipalClassification: mspprotos.MSPPrincipal_ORGANIZATION_UNIT,
+		Principal:               utils.MarshalOrPanic(&mspprotos.OrganizationUnit{MspIdentifier: "Org1MSP", OrganizationalUnitIdentifier: "finance"}),
+	}
+	assert.Equal(t, "Org1MSP.OU=finance", principalName(ou))
+
+	identity := &mspprotos.MSPPrincipal{
+		PrincipalClassification: mspprotos.MSPPrincipal_IDENTITY,
+		Principal:               utils.MarshalOrPanic(&mspprotos.SerializedIdentity{Mspid: "Org1MSP"}),
+	}
+	assert.Equal(t, "Org1MSP.identity", principalName(identity))
+
+	bad := &mspprotos.MSPPrincipal{PrincipalClassification: mspprotos.MSPPrincipal_ROLE, Principal: []byte("garbage")}
+	assert.Equal(t, "ROLE", principalName(bad))
+}
+
+func TestEvaluateUpdateBad(t *testing.T) {
+	config := testConfig(t)
+	patch := &Patch{Orderer: &OrdererPatch{BatchSize: &genesisconfig.BatchSize{MaxMessageCount: 50}}}
+	updated, err := patch.Apply(config)
+	require.NoError(t, err)
+	configUpdate, err := ComputeUpdate("testchannel", config, updated)
+	require.NoError(t, err)
+	env, err := NewConfigUpdateEnvelope(configUpdate)
+	require.NoError(t, err)
+
+	_, err = EvaluateUpdate("otherchannel", config, env)
+	assert.EqualError(t, err, "config update is for channel testchannel, but the config is of channel otherchannel")
+
+	_, err = EvaluateUpdate("testchannel", config, &cb.Envelope{Payload: []byte("garbage")})
+	assert.Error(t, err)
+
+	_, err = EvaluateUpdate("testchannel", &cb.Config{ChannelGroup: cb.NewConfigGroup()}, env)
+	assert.Error(t, err)
+
+	// An update computed against an outdated config is rejected by the validator
+	updated.ChannelGroup.Groups[channelconfig.OrdererGroupKey].Values[channelconfig.BatchSizeKey].Version = 1
+	stale := signConfigUpdate(t, env, newSigningIdentity(t, genesisconfig.SampleOrgName))
+	evaluation, err := EvaluateUpdate("testchannel", updated, stale)
+	require.NoError(t, err)
+	assert.Error(t, evaluation.ValidationError)
+}
@@ -0,0 +1,297 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package workflow
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/tools/configtxgen/encoder"
+	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
+	cf "github.com/hyperledger/fabric/core/config"
+	"github.com/hyperledger/fabric/msp"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/orderer/etcdraft"
+	pb "github.com/hyperledger/fabric/protos/peer"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// Patch is a declarative description of the changes to apply to a channel configuration.
+// Sections which are left empty leave the corresponding part of the configuration untouched.
+type Patch struct {
+	Application *ApplicationPatch `yaml:"Application"`
+	Orderer     *OrdererPatch     `yaml:"Orderer"`
+}
+
+// ApplicationPatch describes the changes to the Application group of a channel.
+type ApplicationPatch struct {
+	// AddOrganizations lists the organizations to add to the channel. Their MSP
+	// definitions are loaded from their MSPDir, as configtxgen does.
+	AddOrganizations []*genesisconfig.Organization `yaml:"AddOrganizations"`
+	// AnchorPeers maps the name of an existing organization to the anchor peers
+	// to add to it.
+	AnchorPeers map[string][]*genesisconfig.AnchorPeer `yaml:"AnchorPeers"`
+}
+
+// OrdererPatch describes the changes to the Orderer group of a channel.
+type OrdererPatch struct {
+	// AddOrganizations lists the orderer organizations to add to the channel.
+	AddOrganizations []*genesisconfig.Organization `yaml:"AddOrganizations"`
+	// BatchSize overrides the fields of the batch size which are not zero.
+	BatchSize *genesisconfig.BatchSize `yaml:"BatchSize"`
+	// Consenters replaces the TLS certificates of the etcd/raft consenters
+	// with the same host and port.
+	Consenters []*genesisconfig.Consenter `yaml:"Consenters"`
+}
+
+// LoadPatch reads a patch from a YAML file. Relative paths in the patch are
+// resolved against the directory of the file.
+func LoadPatch(path string) (*Patch, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading patch file %s", path)
+	}
+
+	patch := &Patch{}
+	if err := yaml.Unmarshal(raw, patch); err != nil {
+		return nil, errors.Wrapf(err, "error unmarshaling patch file %s", path)
+	}
+
+	patch.completeInitialization(filepath.Dir(path))
+	return patch, nil
+}
+
+func (p *Patch) completeInitialization(configDir string) {
+	if p.Application != nil {
+		for _, org := range p.Application.AddOrganizations {
+			completeOrganization(configDir, org)
+		}
+	}
+	if p.Orderer != nil {
+		for _, org := range p.Orderer.AddOrganizations {
+			completeOrganization(configDir, org)
+		}
+		for _, c := range p.Orderer.Consenters {
+			cf.TranslatePathInPlace(configDir, &c.ClientTLSCert)
+			cf.TranslatePathInPlace(configDir, &c.ServerTLSCert)
+		}
+	}
+}
+
+func completeOrganization(configDir string, org *genesisconfig.Organization) {
+	if org.MSPType == "" {
+		org.MSPType = msp.ProviderTypeToString(msp.FABRIC)
+	}
+	if org.AdminPrincipal == "" {
+		org.AdminPrincipal = genesisconfig.AdminRoleAdminPrincipal
+	}
+	cf.TranslatePathInPlace(configDir, &org.MSPDir)
+}
+
+// Apply returns a copy of config with the patch applied. The config passed in
+// is not modified.
+func (p *Patch) Apply(config *cb.Config) (*cb.Config, error) {
+	if config == nil || config.ChannelGroup == nil {
+		return nil, errors.New("config has no channel group")
+	}
+
+	updated := proto.Clone(config).(*cb.Config)
+
+	if p.Application != nil {
+		group, ok := updated.ChannelGroup.Groups[channelconfig.ApplicationGroupKey]
+		if !ok {
+			return nil, errors.New("config has no Application group")
+		}
+		if err := p.Application.apply(group); err != nil {
+			return nil, errors.WithMessage(err, "error patching Application group")
+		}
+	}
+
+	if p.Orderer != nil {
+		group, ok := updated.ChannelGroup.Groups[channelconfig.OrdererGroupKey]
+		if !ok {
+			return nil, errors.New("config has no Orderer group")
+		}
+		if err := p.Orderer.apply(group); err != nil {
+			return nil, errors.WithMessage(err, "error patching Orderer group")
+		}
+	}
+
+	return updated, nil
+}
+
+func (ap *ApplicationPatch) apply(group *cb.ConfigGroup) error {
+	for _, org := range ap.AddOrganizations {
+		if _, ok := group.Groups[org.Name]; ok {
+			return errors.Errorf("organization %s already exists", org.Name)
+		}
+		orgGroup, err := encoder.NewApplicationOrgGroup(org)
+		if err != nil {
+			return errors.Wrapf(err, "error creating organization %s", org.Name)
+		}
+		group.Groups[org.Name] = orgGroup
+	}
+
+	for orgName, anchorPeers := range ap.AnchorPeers {
+		orgGroup, ok := group.Groups[orgName]
+		if !ok {
+			return errors.Errorf("organization %s does not exist", orgName)
+		}
+
+		current := &pb.AnchorPeers{}
+		if err := unmarshalValue(orgGroup, channelconfig.AnchorPeersKey, current); err != nil {
+			return errors.WithMessage(err, "error reading anchor peers of organization "+orgName)
+		}
+
+	NextAnchorPeer:
+		for _, anchorPeer := range anchorPeers {
+			for _, existing := range current.AnchorPeers {
+				if existing.Host == anchorPeer.Host && existing.Port == int32(anchorPeer.Port) {
+					continue NextAnchorPeer
+				}
+			}
+			current.AnchorPeers = append(current.AnchorPeers, &pb.AnchorPeer{
+				Host: anchorPeer.Host,
+				Port: int32(anchorPeer.Port),
+			})
+		}
+
+		if err := setValue(orgGroup, channelconfig.AnchorPeersValue(current.AnchorPeers)); err != nil {
+			return errors.WithMessage(err, "error writing anchor peers of organization "+orgName)
+		}
+	}
+
+	return nil
+}
+
+func (op *OrdererPatch) apply(group *cb.ConfigGroup) error {
+	for _, org := range op.AddOrganizations {
+		if _, ok := group.Groups[org.Name]; ok {
+			return errors.Errorf("organization %s already exists", org.Name)
+		}
+		orgGroup, err := encoder.NewOrdererOrgGroup(org)
+		if err != nil {
+			return errors.Wrapf(err, "error creating organization %s", org.Name)
+		}
+		group.Groups[org.Name] = orgGroup
+	}
+
+	if op.BatchSize != nil {
+		batchSize := &ab.BatchSize{}
+		if err := unmarshalValue(group, channelconfig.BatchSizeKey, batchSize); err != nil {
+			return err
+		}
+		if op.BatchSize.MaxMessageCount != 0 {
+			batchSize.MaxMessageCount = op.BatchSize.MaxMessageCount
+		}
+		if op.BatchSize.AbsoluteMaxBytes != 0 {
+			batchSize.AbsoluteMaxBytes = op.BatchSize.AbsoluteMaxBytes
+		}
+		if op.BatchSize.PreferredMaxBytes != 0 {
+			batchSize.PreferredMaxBytes = op.BatchSize.PreferredMaxBytes
+		}
+		value := channelconfig.BatchSizeValue(batchSize.MaxMessageCount, batchSize.AbsoluteMaxBytes, batchSize.PreferredMaxBytes)
+		if err := setValue(group, value); err != nil {
+			return err
+		}
+	}
+
+	if len(op.Consenters) > 0 {
+		if err := rotateConsenterCerts(group, op.Consenters); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func rotateConsenterCerts(group *cb.ConfigGroup, consenters []*genesisconfig.Consenter) error {
+	consensusType := &ab.ConsensusType{}
+	if err := unmarshalValue(group, channelconfig.ConsensusTypeKey, consensusType); err != nil {
+		return err
+	}
+	if consensusType.Type != encoder.ConsensusTypeEtcdRaft {
+		return errors.Errorf("cannot rotate consenter certificates for consensus type %s", consensusType.Type)
+	}
+
+	metadata := &etcdraft.Metadata{}
+	if err := proto.Unmarshal(consensusType.Metadata, metadata); err != nil {
+		return errors.Wrap(err, "error unmarshaling etcdraft metadata")
+	}
+
+	for _, c := range consenters {
+		var existing *etcdraft.Consenter
+		for _, consenter := range metadata.Consenters {
+			if consenter.Host == c.Host && consenter.Port == c.Port {
+				existing = consenter
+				break
+			}
+		}
+		if existing == nil {
+			return errors.Errorf("consenter %s:%d does not exist", c.Host, c.Port)
+		}
+
+		if c.ClientTLSCert != "" {
+			clientCert, err := ioutil.ReadFile(c.ClientTLSCert)
+			if err != nil {
+				return errors.Wrapf(err, "cannot load client cert for consenter %s:%d", c.Host, c.Port)
+			}
+			existing.ClientTlsCert = clientCert
+		}
+		if c.ServerTLSCert != "" {
+			serverCert, err := ioutil.ReadFile(c.ServerTLSCert)
+			if err != nil {
+				return errors.Wrapf(err, "cannot load server cert for consenter %s:%d", c.Host, c.Port)
+			}
+			existing.ServerTlsCert = serverCert
+		}
+	}
+
+	rawMetadata, err := proto.Marshal(metadata)
+	if err != nil {
+		return errors.Wrap(err, "error marshaling etcdraft metadata")
+	}
+	return setValue(group, channelconfig.ConsensusTypeValue(consensusType.Type, rawMetadata))
+}
+
+// unmarshalValue unmarshals the value with the given key of group into msg.
+// A missing value leaves msg untouched.
+func unmarshalValue(group *cb.ConfigGroup, key string, msg proto.Message) error {
+	value, ok := group.Values[key]
+	if !ok {
+		return nil
+	}
+	if err := proto.Unmarshal(value.Value, msg); err != nil {
+		return errors.Wrapf(err, "error unmarshaling value %s", key)
+	}
+	return nil
+}
+
+// setValue replaces the value of group with the given one, keeping the
+// mod_policy of the existing value
+func setValue(group *cb.ConfigGroup, value *channelconfig.StandardConfigValue) error {
+	modPolicy := channelconfig.AdminsPolicyKey
+	if existing, ok := group.Values[value.Key()]; ok {
+		modPolicy = existing.ModPolicy
+	}
+
+	raw, err := proto.Marshal(value.Value())
+	if err != nil {
+		return errors.Wrapf(err, "error marshaling value %s", value.Key())
+	}
+
+	group.Values[value.Key()] = &cb.ConfigValue{
+		Value:     raw,
+		ModPolicy: modPolicy,
+	}
+	return nil
+}
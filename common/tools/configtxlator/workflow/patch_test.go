@@ -0,0 +1,302 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package workflow
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/tools/configtxgen/configtxgentest"
+	"github.com/hyperledger/fabric/common/tools/configtxgen/encoder"
+	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
+	"github.com/hyperledger/fabric/core/config/configtest"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/orderer/etcdraft"
+	pb "github.com/hyperledger/fabric/protos/peer"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testConfig(t *testing.T) *cb.Config {
+	block := encoder.New(configtxgentest.Load(genesisconfig.SampleDevModeSoloProfile)).GenesisBlockForChannel("testchannel")
+	channelID, config, err := ConfigFromBlock(block)
+	require.NoError(t, err)
+	require.Equal(t, "testchannel", channelID)
+	return config
+}
+
+func requiredPoliciesFor(t *testing.T, original, updated *cb.Config) []*PolicyRequirement {
+	configUpdate, err := ComputeUpdate("testchannel", original, updated)
+	require.NoError(t, err)
+	requirements, err := RequiredPolicies(original, configUpdate)
+	require.NoError(t, err)
+	return requirements
+}
+
+func TestLoadPatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "workflow")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	patchFile := filepath.Join(dir, "patch.yaml")
+	err = ioutil.WriteFile(patchFile, []byte(`
+Application:
+    AddOrganizations:
+        - Name: Org2
+          ID: Org2MSP
+          MSPDir: org2/msp
+    AnchorPeers:
+        Org1:
+            - Host: peer0.org1.example.com
+              Port: 7051
+Orderer:
+    BatchSize:
+        MaxMessageCount: 20
+    Consenters:
+        - Host: raft0.example.com
+          Port: 7050
+          ClientTLSCert: /tls/client.pem
+          ServerTLSCert: tls/server.pem
+`), 0600)
+	require.NoError(t, err)
+
+	patch, err := LoadPatch(patchFile)
+	require.NoError(t, err)
+
+	require.Len(t, patch.Application.AddOrganizations, 1)
+	org := patch.Application.AddOrganizations[0]
+	assert.Equal(t, "Org2", org.Name)
+	assert.Equal(t, "Org2MSP", org.ID)
+	assert.Equal(t, filepath.Join(dir, "org2/msp"), org.MSPDir)
+	assert.Equal(t, "bccsp", org.MSPType)
+	assert.Equal(t, genesisconfig.AdminRoleAdminPrincipal, org.AdminPrincipal)
+	assert.Equal(t, []*genesisconfig.AnchorPeer{{Host: "peer0.org1.example.com", Port: 7051}}, patch.Application.AnchorPeers["Org1"])
+
+	assert.Equal(t, &genesisconfig.BatchSize{MaxMessageCount: 20}, patch.Orderer.BatchSize)
+	require.Len(t, patch.Orderer.Consenters, 1)
+	assert.Equal(t, "/tls/client.pem", patch.Orderer.Consenters[0].ClientTLSCert)
+	assert.Equal(t, filepath.Join(dir, "tls/server.pem"), patch.Orderer.Consenters[0].ServerTLSCert)
+
+	_, err = LoadPatch(filepath.Join(dir, "missing.yaml"))
+	assert.Error(t, err)
+
+	err = ioutil.WriteFile(patchFile, []byte("Application: [ unterminated"), 0600)
+	require.NoError(t, err)
+	_, err = LoadPatch(patchFile)
+	assert.Error(t, err)
+}
+
+func TestAddOrganization(t *testing.T) {
+	mspDir, err := configtest.GetDevMspDir()
+	require.NoError(t, err)
+
+	original := testConfig(t)
+	patch := &Patch{
+		Application: &ApplicationPatch{
+			AddOrganizations: []*genesisconfig.Organization{{
+				Name:           "Org2",
+				ID:             "Org2MSP",
+				MSPDir:         mspDir,
+				MSPType:        "bccsp",
+				AdminPrincipal: genesisconfig.AdminRoleAdminPrincipal,
+				AnchorPeers:    []*genesisconfig.AnchorPeer{{Host: "peer0.org2.example.com", Port: 7051}},
+			}},
+		},
+	}
+
+	updated, err := patch.Apply(original)
+	require.NoError(t, err)
+
+	_, ok := original.ChannelGroup.Groups[channelconfig.ApplicationGroupKey].Groups["Org2"]
+	assert.False(t, ok, "the original config must not be modified")
+	org := updated.ChannelGroup.Groups[channelconfig.ApplicationGroupKey].Groups["Org2"]
+	require.NotNil(t, org)
+	assert.Contains(t, org.Values, channelconfig.MSPKey)
+	assert.Contains(t, org.Values, channelconfig.AnchorPeersKey)
+
+	assert.Equal(t, []*PolicyRequirement{{
+		Policy:   "/Channel/Application/Admins",
+		Elements: []string{"[Group]  /Channel/Application"},
+	}}, requiredPoliciesFor(t, original, updated))
+
+	_, err = patch.Apply(updated)
+	assert.EqualError(t, err, "error patching Application group: organization Org2 already exists")
+
+	patch = &Patch{
+		Orderer: &OrdererPatch{
+			AddOrganizations: []*genesisconfig.Organization{{
+				Name:           "OrdererOrg2",
+				ID:             "OrdererOrg2MSP",
+				MSPDir:         mspDir,
+				MSPType:        "bccsp",
+				AdminPrincipal: genesisconfig.AdminRoleAdminPrincipal,
+			}},
+		},
+	}
+	updated, err = patch.Apply(original)
+	require.NoError(t, err)
+	assert.Contains(t, updated.ChannelGroup.Groups[channelconfig.OrdererGroupKey].Groups, "OrdererOrg2")
+	assert.Equal(t, []*PolicyRequirement{{
+		Policy:   "/Channel/Orderer/Admins",
+		Elements: []string{"[Group]  /Channel/Orderer"},
+	}}, requiredPoliciesFor(t, original, updated))
+
+	patch.Orderer.AddOrganizations[0].MSPDir = filepath.Join(mspDir, "missing")
+	_, err = patch.Apply(original)
+	assert.Error(t, err)
+}
+
+func TestAddAnchorPeers(t *testing.T) {
+	original := testConfig(t)
+	patch := &Patch{
+		Application: &ApplicationPatch{
+			AnchorPeers: map[string][]*genesisconfig.AnchorPeer{
+				genesisconfig.SampleOrgName: {
+					{Host: "peer0.example.com", Port: 7051},
+					{Host: "peer1.example.com", Port: 7051},
+				},
+			},
+		},
+	}
+
+	updated, err := patch.Apply(original)
+	require.NoError(t, err)
+
+	org := updated.ChannelGroup.Groups[channelconfig.ApplicationGroupKey].Groups[genesisconfig.SampleOrgName]
+	anchorPeers := &pb.AnchorPeers{}
+	require.NoError(t, proto.Unmarshal(org.Values[channelconfig.AnchorPeersKey].Value, anchorPeers))
+	assert.Equal(t, []*pb.AnchorPeer{
+		{Host: "127.0.0.1", Port: 7051},
+		{Host: "peer0.example.com", Port: 7051},
+		{Host: "peer1.example.com", Port: 7051},
+	}, anchorPeers.AnchorPeers)
+
+	assert.Equal(t, []*PolicyRequirement{{
+		Policy:   "/Channel/Application/SampleOrg/Admins",
+		Elements: []string{"[Value]  /Channel/Application/SampleOrg/AnchorPeers"},
+	}}, requiredPoliciesFor(t, original, updated))
+
+	// Anchor peers which are already defined are not added twice
+	again, err := patch.Apply(updated)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(updated, again))
+
+	patch.Application.AnchorPeers = map[string][]*genesisconfig.AnchorPeer{
+		"UnknownOrg": {{Host: "peer0.example.com", Port: 7051}},
+	}
+	_, err = patch.Apply(original)
+	assert.EqualError(t, err, "error patching Application group: organization UnknownOrg does not exist")
+}
+
+func TestChangeBatchSize(t *testing.T) {
+	original := testConfig(t)
+	patch := &Patch{
+		Orderer: &OrdererPatch{
+			BatchSize: &genesisconfig.BatchSize{MaxMessageCount: 500},
+		},
+	}
+
+	updated, err := patch.Apply(original)
+	require.NoError(t, err)
+
+	originalBatchSize := &ab.BatchSize{}
+	require.NoError(t, proto.Unmarshal(original.ChannelGroup.Groups[channelconfig.OrdererGroupKey].Values[channelconfig.BatchSizeKey].Value, originalBatchSize))
+	batchSize := &ab.BatchSize{}
+	require.NoError(t, proto.Unmarshal(updated.ChannelGroup.Groups[channelconfig.OrdererGroupKey].Values[channelconfig.BatchSizeKey].Value, batchSize))
+	assert.Equal(t, uint32(500), batchSize.MaxMessageCount)
+	assert.Equal(t, originalBatchSize.AbsoluteMaxBytes, batchSize.AbsoluteMaxBytes)
+	assert.Equal(t, originalBatchSize.PreferredMaxBytes, batchSize.PreferredMaxBytes)
+
+	assert.Equal(t, []*PolicyRequirement{{
+		Policy:   "/Channel/Orderer/Admins",
+		Elements: []string{"[Value]  /Channel/Orderer/BatchSize"},
+	}}, requiredPoliciesFor(t, original, updated))
+}
+
+func TestRotateConsenterCerts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "workflow")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	clientCert := filepath.Join(dir, "client.pem")
+	serverCert := filepath.Join(dir, "server.pem")
+	require.NoError(t, ioutil.WriteFile(clientCert, []byte("new client cert"), 0600))
+	require.NoError(t, ioutil.WriteFile(serverCert, []byte("new server cert"), 0600))
+
+	original := testConfig(t)
+	patch := &Patch{
+		Orderer: &OrdererPatch{
+			Consenters: []*genesisconfig.Consenter{{
+				Host:          "raft1.example.com",
+				Port:          7050,
+				ClientTLSCert: clientCert,
+				ServerTLSCert: serverCert,
+			}},
+		},
+	}
+
+	_, err = patch.Apply(original)
+	assert.EqualError(t, err, "error patching Orderer group: cannot rotate consenter certificates for consensus type solo")
+
+	metadata := &etcdraft.Metadata{
+		Consenters: []*etcdraft.Consenter{
+			{Host: "raft0.example.com", Port: 7050, ClientTlsCert: []byte("client0"), ServerTlsCert: []byte("server0")},
+			{Host: "raft1.example.com", Port: 7050, ClientTlsCert: []byte("client1"), ServerTlsCert: []byte("server1")},
+		},
+	}
+	rawMetadata, err := proto.Marshal(metadata)
+	require.NoError(t, err)
+	ordererGroup := original.ChannelGroup.Groups[channelconfig.OrdererGroupKey]
+	require.NoError(t, setValue(ordererGroup, channelconfig.ConsensusTypeValue(encoder.ConsensusTypeEtcdRaft, rawMetadata)))
+
+	updated, err := patch.Apply(original)
+	require.NoError(t, err)
+
+	consensusType := &ab.ConsensusType{}
+	require.NoError(t, proto.Unmarshal(updated.ChannelGroup.Groups[channelconfig.OrdererGroupKey].Values[channelconfig.ConsensusTypeKey].Value, consensusType))
+	assert.Equal(t, encoder.ConsensusTypeEtcdRaft, consensusType.Type)
+	updatedMetadata := &etcdraft.Metadata{}
+	require.NoError(t, proto.Unmarshal(consensusType.Metadata, updatedMetadata))
+	assert.Equal(t, metadata.Consenters[0], updatedMetadata.Consenters[0])
+	assert.Equal(t, []byte("new client cert"), updatedMetadata.Consenters[1].ClientTlsCert)
+	assert.Equal(t, []byte("new server cert"), updatedMetadata.Consenters[1].ServerTlsCert)
+
+	assert.Equal(t, []*PolicyRequirement{{
+		Policy:   "/Channel/Orderer/Admins",
+		Elements: []string{"[Value]  /Channel/Orderer/ConsensusType"},
+	}}, requiredPoliciesFor(t, original, updated))
+
+	patch.Orderer.Consenters[0].Port = 7051
+	_, err = patch.Apply(original)
+	assert.EqualError(t, err, "error patching Orderer group: consenter raft1.example.com:7051 does not exist")
+
+	patch.Orderer.Consenters[0].Port = 7050
+	patch.Orderer.Consenters[0].ClientTLSCert = filepath.Join(dir, "missing.pem")
+	_, err = patch.Apply(original)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot load client cert for consenter raft1.example.com:7050")
+}
+
+func TestApplyBadConfig(t *testing.T) {
+	patch := &Patch{Application: &ApplicationPatch{}}
+
+	_, err := patch.Apply(&cb.Config{})
+	assert.EqualError(t, err, "config has no channel group")
+
+	_, err = patch.Apply(&cb.Config{ChannelGroup: cb.NewConfigGroup()})
+	assert.EqualError(t, err, "config has no Application group")
+
+	patch = &Patch{Orderer: &OrdererPatch{}}
+	_, err = patch.Apply(&cb.Config{ChannelGroup: cb.NewConfigGroup()})
+	assert.EqualError(t, err, "config has no Orderer group")
+}
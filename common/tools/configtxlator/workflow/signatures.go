@@ -0,0 +1,87 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package workflow
+
+import (
+	"bytes"
+
+	"github.com/hyperledger/fabric/common/configtx"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+
+	"github.com/pkg/errors"
+)
+
+// UnmarshalConfigUpdateEnvelope extracts the channel ID and the ConfigUpdateEnvelope from
+// an envelope of type CONFIG_UPDATE.
+func UnmarshalConfigUpdateEnvelope(env *cb.Envelope) (string, *cb.ConfigUpdateEnvelope, error) {
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return "", nil, errors.WithMessage(err, "error extracting payload from envelope")
+	}
+	if payload.Header == nil {
+		return "", nil, errors.New("payload has no header")
+	}
+
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return "", nil, errors.WithMessage(err, "error extracting channel header")
+	}
+	if chdr.Type != int32(cb.HeaderType_CONFIG_UPDATE) {
+		return "", nil, errors.Errorf("envelope is not a config update, it is of type %s", cb.HeaderType(chdr.Type))
+	}
+
+	configUpdateEnv, err := configtx.UnmarshalConfigUpdateEnvelope(payload.Data)
+	if err != nil {
+		return "", nil, errors.WithMessage(err, "error extracting config update envelope")
+	}
+
+	return chdr.ChannelId, configUpdateEnv, nil
+}
+
+// MergeSignatures combines the signatures of several envelopes carrying the same config
+// update, e.g. copies of the same update signed separately by the admins of different
+// organizations with 'peer channel signconfigtx'. Signatures which appear in more than one
+// envelope are kept once. The result is an unsigned CONFIG_UPDATE envelope.
+func MergeSignatures(envs ...*cb.Envelope) (*cb.Envelope, error) {
+	if len(envs) == 0 {
+		return nil, errors.New("no envelopes to merge")
+	}
+
+	var channelID string
+	merged := &cb.ConfigUpdateEnvelope{}
+	for i, env := range envs {
+		envChannelID, configUpdateEnv, err := UnmarshalConfigUpdateEnvelope(env)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid envelope at position %d", i)
+		}
+
+		if i == 0 {
+			channelID = envChannelID
+			merged.ConfigUpdate = configUpdateEnv.ConfigUpdate
+		} else {
+			if envChannelID != channelID {
+				return nil, errors.Errorf("envelope at position %d is for channel %s, expected %s", i, envChannelID, channelID)
+			}
+			if !bytes.Equal(configUpdateEnv.ConfigUpdate, merged.ConfigUpdate) {
+				return nil, errors.Errorf("envelope at position %d carries a different config update", i)
+			}
+		}
+
+	NextSignature:
+		for _, sig := range configUpdateEnv.Signatures {
+			for _, existing := range merged.Signatures {
+				if bytes.Equal(existing.SignatureHeader, sig.SignatureHeader) && bytes.Equal(existing.Signature, sig.Signature) {
+					continue NextSignature
+				}
+			}
+			merged.Signatures = append(merged.Signatures, sig)
+		}
+	}
+
+	return utils.CreateSignedEnvelope(cb.HeaderType_CONFIG_UPDATE, channelID, nil, merged, 0, 0)
+}
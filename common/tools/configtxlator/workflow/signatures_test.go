@@ -0,0 +1,82 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package workflow
+
+import (
+	"testing"
+
+	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testConfigUpdateEnvelope(t *testing.T, channelID string, maxMessageCount uint32) *cb.Envelope {
+	config := testConfig(t)
+	patch := &Patch{Orderer: &OrdererPatch{BatchSize: &genesisconfig.BatchSize{MaxMessageCount: maxMessageCount}}}
+	updated, err := patch.Apply(config)
+	require.NoError(t, err)
+	configUpdate, err := ComputeUpdate(channelID, config, updated)
+	require.NoError(t, err)
+	env, err := NewConfigUpdateEnvelope(configUpdate)
+	require.NoError(t, err)
+	return env
+}
+
+func TestMergeSignatures(t *testing.T) {
+	unsigned := testConfigUpdateEnvelope(t, "testchannel", 50)
+	first := signConfigUpdate(t, unsigned, newSigningIdentity(t, genesisconfig.SampleOrgName))
+	second := signConfigUpdate(t, unsigned, newSigningIdentity(t, "Org2MSP"))
+	both := signConfigUpdate(t, first, newSigningIdentity(t, "Org3MSP"))
+
+	merged, err := MergeSignatures(unsigned, first, second, both, first)
+	require.NoError(t, err)
+	assert.Empty(t, merged.Signature)
+
+	channelID, configUpdateEnv, err := UnmarshalConfigUpdateEnvelope(merged)
+	require.NoError(t, err)
+	assert.Equal(t, "testchannel", channelID)
+
+	_, unsignedEnv, err := UnmarshalConfigUpdateEnvelope(unsigned)
+	require.NoError(t, err)
+	assert.Equal(t, unsignedEnv.ConfigUpdate, configUpdateEnv.ConfigUpdate)
+
+	_, firstEnv, err := UnmarshalConfigUpdateEnvelope(first)
+	require.NoError(t, err)
+	_, secondEnv, err := UnmarshalConfigUpdateEnvelope(second)
+	require.NoError(t, err)
+	_, bothEnv, err := UnmarshalConfigUpdateEnvelope(both)
+	require.NoError(t, err)
+	assert.Equal(t, []*cb.ConfigSignature{
+		firstEnv.Signatures[0],
+		secondEnv.Signatures[0],
+		bothEnv.Signatures[1],
+	}, configUpdateEnv.Signatures)
+}
+
+func TestMergeSignaturesBad(t *testing.T) {
+	_, err := MergeSignatures()
+	assert.EqualError(t, err, "no envelopes to merge")
+
+	env := testConfigUpdateEnvelope(t, "testchannel", 50)
+
+	_, err = MergeSignatures(env, testConfigUpdateEnvelope(t, "otherchannel", 50))
+	assert.EqualError(t, err, "envelope at position 1 is for channel otherchannel, expected testchannel")
+
+	_, err = MergeSignatures(env, testConfigUpdateEnvelope(t, "testchannel", 60))
+	assert.EqualError(t, err, "envelope at position 1 carries a different config update")
+
+	notAnUpdate, err := utils.CreateSignedEnvelope(cb.HeaderType_CONFIG, "testchannel", nil, &cb.ConfigEnvelope{}, 0, 0)
+	require.NoError(t, err)
+	_, err = MergeSignatures(env, notAnUpdate)
+	assert.EqualError(t, err, "invalid envelope at position 1: envelope is not a config update, it is of type CONFIG")
+
+	_, err = MergeSignatures(&cb.Envelope{Payload: []byte("garbage")})
+	assert.Error(t, err)
+}
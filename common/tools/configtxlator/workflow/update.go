@@ -0,0 +1,208 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package workflow
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/common/tools/configtxlator/update"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	groupPrefix  = "[Group]  "
+	valuePrefix  = "[Value]  "
+	policyPrefix = "[Policy] "
+
+	pathSeparator = "/"
+)
+
+// ConfigFromBlock extracts the channel ID and the channel configuration from a config block,
+// as fetched with 'peer channel fetch config'.
+func ConfigFromBlock(block *cb.Block) (string, *cb.Config, error) {
+	if block == nil || block.Data == nil || len(block.Data.Data) == 0 {
+		return "", nil, errors.New("block has no data")
+	}
+
+	env, err := utils.ExtractEnvelope(block, 0)
+	if err != nil {
+		return "", nil, errors.WithMessage(err, "error extracting envelope from block")
+	}
+
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return "", nil, errors.WithMessage(err, "error extracting payload from envelope")
+	}
+	if payload.Header == nil {
+		return "", nil, errors.New("payload has no header")
+	}
+
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return "", nil, errors.WithMessage(err, "error extracting channel header")
+	}
+	if chdr.Type != int32(cb.HeaderType_CONFIG) {
+		return "", nil, errors.Errorf("block is not a config block, its transaction is of type %s", cb.HeaderType(chdr.Type))
+	}
+
+	configEnv, err := configtx.UnmarshalConfigEnvelope(payload.Data)
+	if err != nil {
+		return "", nil, errors.WithMessage(err, "error extracting config envelope")
+	}
+	if configEnv.Config == nil || configEnv.Config.ChannelGroup == nil {
+		return "", nil, errors.New("config envelope has no channel group")
+	}
+
+	return chdr.ChannelId, configEnv.Config, nil
+}
+
+// ComputeUpdate computes the config update for channelID which transitions from the
+// original to the updated config.
+func ComputeUpdate(channelID string, original, updated *cb.Config) (*cb.ConfigUpdate, error) {
+	configUpdate, err := update.Compute(original, updated)
+	if err != nil {
+		return nil, err
+	}
+	configUpdate.ChannelId = channelID
+	return configUpdate, nil
+}
+
+// NewConfigUpdateEnvelope wraps configUpdate into an unsigned CONFIG_UPDATE envelope, which
+// can be signed with 'peer channel signconfigtx' and submitted with 'peer channel update'.
+func NewConfigUpdateEnvelope(configUpdate *cb.ConfigUpdate) (*cb.Envelope, error) {
+	configUpdateEnv := &cb.ConfigUpdateEnvelope{
+		ConfigUpdate: utils.MarshalOrPanic(configUpdate),
+	}
+	return utils.CreateSignedEnvelope(cb.HeaderType_CONFIG_UPDATE, configUpdate.ChannelId, nil, configUpdateEnv, 0, 0)
+}
+
+// PolicyRequirement is a mod_policy which must be satisfied by the signatures on a config
+// update, together with the config elements whose modification requires it.
+type PolicyRequirement struct {
+	// Policy is the fully qualified path of the policy, e.g. /Channel/Application/Admins
+	Policy string
+	// Elements are the modified elements, in the notation of the config validator,
+	// e.g. [Value]  /Channel/Orderer/BatchSize
+	Elements []string
+}
+
+// RequiredPolicies returns the mod_policies which must be satisfied for configUpdate to be
+// applied to the original config, sorted by policy. Just as the config validator does, it
+// considers every element of the write set whose version differs from the read set, and
+// requires the mod_policy the element has in the original config. New elements require no
+// policy of their own, as they are authorized by the modification of their parent group.
+func RequiredPolicies(original *cb.Config, configUpdate *cb.ConfigUpdate) ([]*PolicyRequirement, error) {
+	if original == nil || original.ChannelGroup == nil {
+		return nil, errors.New("original config has no channel group")
+	}
+	if configUpdate == nil || configUpdate.WriteSet == nil {
+		return nil, errors.New("config update has no write set")
+	}
+
+	elements := make(map[string][]string)
+	err := requiredPolicies(elements, []string{channelconfig.RootGroupKey}, original.ChannelGroup, configUpdate.ReadSet, configUpdate.WriteSet)
+	if err != nil {
+		return nil, err
+	}
+
+	requirements := make([]*PolicyRequirement, 0, len(elements))
+	for policy, modified := range elements {
+		sort.Strings(modified)
+		requirements = append(requirements, &PolicyRequirement{
+			Policy:   policy,
+			Elements: modified,
+		})
+	}
+	sort.Slice(requirements, func(i, j int) bool {
+		return requirements[i].Policy < requirements[j].Policy
+	})
+
+	return requirements, nil
+}
+
+// requiredPolicies adds the policies required by the modifications of the group at path
+// (which includes the key of the group) and of its elements to result
+func requiredPolicies(result map[string][]string, path []string, original, readSet, writeSet *cb.ConfigGroup) error {
+	groupPath := pathSeparator + strings.Join(path, pathSeparator)
+
+	if readSet == nil || readSet.Version != writeSet.Version {
+		if err := addRequirement(result, path, original.ModPolicy, groupPrefix+groupPath); err != nil {
+			return err
+		}
+	}
+
+	for key, value := range writeSet.Values {
+		existing, ok := original.Values[key]
+		if !ok {
+			continue
+		}
+		if readSet != nil {
+			if read, ok := readSet.Values[key]; ok && read.Version == value.Version {
+				continue
+			}
+		}
+		if err := addRequirement(result, path, existing.ModPolicy, valuePrefix+groupPath+pathSeparator+key); err != nil {
+			return err
+		}
+	}
+
+	for key, policy := range writeSet.Policies {
+		existing, ok := original.Policies[key]
+		if !ok {
+			continue
+		}
+		if readSet != nil {
+			if read, ok := readSet.Policies[key]; ok && read.Version == policy.Version {
+				continue
+			}
+		}
+		if err := addRequirement(result, path, existing.ModPolicy, policyPrefix+groupPath+pathSeparator+key); err != nil {
+			return err
+		}
+	}
+
+	for key, group := range writeSet.Groups {
+		existing, ok := original.Groups[key]
+		if !ok {
+			continue
+		}
+		var readGroup *cb.ConfigGroup
+		if readSet != nil {
+			readGroup = readSet.Groups[key]
+		}
+		subPath := make([]string, len(path)+1)
+		copy(subPath, path)
+		subPath[len(path)] = key
+		if err := requiredPolicies(result, subPath, existing, readGroup, group); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addRequirement resolves modPolicy relative to the group at path and records that the
+// element requires it
+func addRequirement(result map[string][]string, path []string, modPolicy, element string) error {
+	if modPolicy == "" {
+		return errors.Errorf("element %s has no mod_policy", element)
+	}
+
+	policy := modPolicy
+	if !strings.HasPrefix(modPolicy, pathSeparator) {
+		policy = pathSeparator + strings.Join(path, pathSeparator) + pathSeparator + modPolicy
+	}
+
+	result[policy] = append(result[policy], element)
+	return nil
+}
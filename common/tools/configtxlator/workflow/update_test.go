@@ -0,0 +1,157 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package workflow
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/common/configtx"
+	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigFromBlockBad(t *testing.T) {
+	_, _, err := ConfigFromBlock(nil)
+	assert.EqualError(t, err, "block has no data")
+
+	_, _, err = ConfigFromBlock(&cb.Block{Data: &cb.BlockData{Data: [][]byte{[]byte("garbage")}}})
+	assert.Error(t, err)
+
+	env, err := utils.CreateSignedEnvelope(cb.HeaderType_ENDORSER_TRANSACTION, "testchannel", nil, &cb.ConfigEnvelope{}, 0, 0)
+	require.NoError(t, err)
+	block := &cb.Block{Data: &cb.BlockData{Data: [][]byte{utils.MarshalOrPanic(env)}}}
+	_, _, err = ConfigFromBlock(block)
+	assert.EqualError(t, err, "block is not a config block, its transaction is of type ENDORSER_TRANSACTION")
+
+	env, err = utils.CreateSignedEnvelope(cb.HeaderType_CONFIG, "testchannel", nil, &cb.ConfigEnvelope{}, 0, 0)
+	require.NoError(t, err)
+	block = &cb.Block{Data: &cb.BlockData{Data: [][]byte{utils.MarshalOrPanic(env)}}}
+	_, _, err = ConfigFromBlock(block)
+	assert.EqualError(t, err, "config envelope has no channel group")
+}
+
+func TestNewConfigUpdateEnvelope(t *testing.T) {
+	original := testConfig(t)
+	patch := &Patch{Orderer: &OrdererPatch{BatchSize: &genesisconfig.BatchSize{PreferredMaxBytes: 1024}}}
+	updated, err := patch.Apply(original)
+	require.NoError(t, err)
+
+	configUpdate, err := ComputeUpdate("testchannel", original, updated)
+	require.NoError(t, err)
+	assert.Equal(t, "testchannel", configUpdate.ChannelId)
+
+	env, err := NewConfigUpdateEnvelope(configUpdate)
+	require.NoError(t, err)
+	assert.Empty(t, env.Signature)
+
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	require.NoError(t, err)
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	require.NoError(t, err)
+	assert.Equal(t, int32(cb.HeaderType_CONFIG_UPDATE), chdr.Type)
+	assert.Equal(t, "testchannel", chdr.ChannelId)
+
+	configUpdateEnv, err := configtx.UnmarshalConfigUpdateEnvelope(payload.Data)
+	require.NoError(t, err)
+	assert.Empty(t, configUpdateEnv.Signatures)
+	decoded, err := configtx.UnmarshalConfigUpdate(configUpdateEnv.ConfigUpdate)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(configUpdate, decoded))
+
+	_, err = ComputeUpdate("testchannel", original, original)
+	assert.Error(t, err)
+}
+
+func TestRequiredPolicies(t *testing.T) {
+	original := &cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Version:   1,
+			ModPolicy: "Admins",
+			Values: map[string]*cb.ConfigValue{
+				"Foo": {Version: 2, ModPolicy: "/Channel/Orderer/Admins"},
+				"Bar": {Version: 0, ModPolicy: "Admins"},
+			},
+			Policies: map[string]*cb.ConfigPolicy{
+				"Admins": {Version: 3, ModPolicy: "Admins"},
+			},
+			Groups: map[string]*cb.ConfigGroup{
+				"Orderer": {
+					Version:   4,
+					ModPolicy: "Admins",
+					Policies: map[string]*cb.ConfigPolicy{
+						"Writers": {Version: 0, ModPolicy: "Org1/Admins"},
+					},
+				},
+			},
+		},
+	}
+
+	configUpdate := &cb.ConfigUpdate{
+		ReadSet: &cb.ConfigGroup{
+			Version: 1,
+			Values: map[string]*cb.ConfigValue{
+				"Bar": {Version: 0},
+			},
+			Groups: map[string]*cb.ConfigGroup{
+				"Orderer": {Version: 4},
+			},
+		},
+		WriteSet: &cb.ConfigGroup{
+			Version: 2,
+			Values: map[string]*cb.ConfigValue{
+				"Foo": {Version: 3, ModPolicy: "/Channel/Orderer/Admins"},
+				"Bar": {Version: 0, ModPolicy: "Admins"},
+				"New": {Version: 0, ModPolicy: "Admins"},
+			},
+			Policies: map[string]*cb.ConfigPolicy{
+				"Admins": {Version: 4, ModPolicy: "Admins"},
+			},
+			Groups: map[string]*cb.ConfigGroup{
+				"Orderer": {
+					Version:   4,
+					ModPolicy: "Admins",
+					Policies: map[string]*cb.ConfigPolicy{
+						"Writers": {Version: 1, ModPolicy: "Org1/Admins"},
+					},
+				},
+				"New": {Version: 0, ModPolicy: "Admins"},
+			},
+		},
+	}
+
+	requirements, err := RequiredPolicies(original, configUpdate)
+	require.NoError(t, err)
+	assert.Equal(t, []*PolicyRequirement{
+		{
+			Policy:   "/Channel/Admins",
+			Elements: []string{"[Group]  /Channel", "[Policy] /Channel/Admins"},
+		},
+		{
+			Policy:   "/Channel/Orderer/Admins",
+			Elements: []string{"[Value]  /Channel/Foo"},
+		},
+		{
+			Policy:   "/Channel/Orderer/Org1/Admins",
+			Elements: []string{"[Policy] /Channel/Orderer/Writers"},
+		},
+	}, requirements)
+
+	original.ChannelGroup.Values["Foo"].ModPolicy = ""
+	_, err = RequiredPolicies(original, configUpdate)
+	assert.EqualError(t, err, "element [Value]  /Channel/Foo has no mod_policy")
+
+	_, err = RequiredPolicies(&cb.Config{}, configUpdate)
+	assert.EqualError(t, err, "original config has no channel group")
+
+	_, err = RequiredPolicies(original, &cb.ConfigUpdate{})
+	assert.EqualError(t, err, "config update has no write set")
+}
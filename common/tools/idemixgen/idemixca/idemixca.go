@@ -7,6 +7,10 @@ SPDX-License-Identifier: Apache-2.0
 package idemixca
 
 import (
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/pem"
+
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-amcl/amcl/FP256BN"
 	"github.com/hyperledger/fabric/idemix"
@@ -37,6 +41,49 @@ func GenerateIssuerKey() ([]byte, []byte, error) {
 	return key.ISk, ipkSerialized, err
 }
 
+// GenerateRevocationKey generates the long term key pair of the revocation authority,
+// which certifies the credential revocation information of every epoch.
+// Generated keys are PEM encoded.
+func GenerateRevocationKey() ([]byte, []byte, error) {
+	key, err := idemix.GenerateLongTermRevocationKey()
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "cannot generate revocation key")
+	}
+	skBytes, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to marshal revocation secret key")
+	}
+	pkBytes, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to marshal revocation public key")
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: skBytes}),
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pkBytes}),
+		nil
+}
+
+// GenerateCRI creates the credential revocation information for the given epoch.
+// Every revocation handle that is not in unrevokedHandles is revoked in this epoch.
+// The credential revocation information is serialized to bytes.
+func GenerateCRI(key *ecdsa.PrivateKey, epoch int64, unrevokedHandles []int) ([]byte, error) {
+	rng, err := idemix.GetRand()
+	if err != nil {
+		return nil, errors.WithMessage(err, "Error getting PRNG")
+	}
+
+	handles := make([]*FP256BN.BIG, len(unrevokedHandles))
+	for i, rh := range unrevokedHandles {
+		handles[i] = FP256BN.NewBIGint(rh)
+	}
+	cri, err := idemix.CreateCRI(key, handles, epoch, idemix.ALG_WEAK_BB, rng)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create credential revocation information")
+	}
+
+	return proto.Marshal(cri)
+}
+
 // GenerateMSPConfig creates a new MSP config.
 // If the new MSP config contains a signer then
 // it generates a fresh user secret and issues a credential
@@ -85,11 +132,11 @@ func GenerateSignerConfig(isAdmin bool, ouString string, enrollmentId string, re
 	}
 
 	signer := &m.IdemixMSPSignerConfig{
-		credBytes,
-		idemix.BigToBytes(sk),
-		ouString,
-		isAdmin,
-		enrollmentId,
+		Cred:                         credBytes,
+		Sk:                           idemix.BigToBytes(sk),
+		OrganizationalUnitIdentifier: ouString,
+		IsAdmin:                      isAdmin,
+		EnrollmentId:                 enrollmentId,
 	}
 	return proto.Marshal(signer)
 }
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package idemixca
 
 import (
+	"crypto/x509"
+	"encoding/pem"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -48,6 +50,25 @@ func TestIdemixCa(t *testing.T) {
 	assert.NoError(t, writeSignerToFile(conf))
 	assert.NoError(t, setupMSP())
 
+	// Enable revocation with a signer that is not revoked in epoch 1
+	rskBytes, rpkBytes, err := GenerateRevocationKey()
+	assert.NoError(t, err)
+	block, _ := pem.Decode(rskBytes)
+	rsk, err := x509.ParseECPrivateKey(block.Bytes)
+	assert.NoError(t, err)
+	assert.NoError(t, writeVerifierFile(m.IdemixConfigFileRevocationPublicKey, rpkBytes))
+
+	cri, err := GenerateCRI(rsk, 1, []int{1, 1234})
+	assert.NoError(t, err)
+	assert.NoError(t, writeVerifierFile(m.IdemixConfigFileRevocationInformation, cri))
+	assert.NoError(t, setupMSP())
+
+	// The signer is revoked in epoch 2
+	cri, err = GenerateCRI(rsk, 2, []int{1})
+	assert.NoError(t, err)
+	assert.NoError(t, writeVerifierFile(m.IdemixConfigFileRevocationInformation, cri))
+	assert.Error(t, setupMSP())
+
 	// Without the verifier dir present, setup should give an error
 	cleanupVerifier()
 	assert.Error(t, setupMSP())
@@ -84,6 +105,10 @@ func writeVerifierToFile(ipkBytes []byte) error {
 	return ioutil.WriteFile(filepath.Join(testDir, m.IdemixConfigDirMsp, m.IdemixConfigFileIssuerPublicKey), ipkBytes, 0644)
 }
 
+func writeVerifierFile(name string, contents []byte) error {
+	return ioutil.WriteFile(filepath.Join(testDir, m.IdemixConfigDirMsp, name), contents, 0644)
+}
+
 func writeSignerToFile(signerBytes []byte) error {
 	err := os.Mkdir(filepath.Join(testDir, m.IdemixConfigDirUser), os.ModePerm)
 	if err != nil {
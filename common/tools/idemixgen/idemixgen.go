@@ -12,6 +12,9 @@ package main
 // the Identity Mixer MSP
 
 import (
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -27,8 +30,9 @@ import (
 )
 
 const (
-	IdemixDirIssuer             = "ca"
-	IdemixConfigIssuerSecretKey = "IssuerSecretKey"
+	IdemixDirIssuer                 = "ca"
+	IdemixConfigIssuerSecretKey     = "IssuerSecretKey"
+	IdemixConfigRevocationSecretKey = "RevocationSecretKey"
 )
 
 // command line flags
@@ -41,6 +45,9 @@ var (
 	genCredIsAdmin          = genSignerConfig.Flag("admin", "Make the default signer admin").Short('a').Bool()
 	genCredEnrollmentId     = genSignerConfig.Flag("enrollmentId", "The enrollment id of the default signer").Short('e').String()
 	genCredRevocationHandle = genSignerConfig.Flag("revocationHandle", "The handle used to revoke this signer").Short('r').Int()
+	genCRI                  = app.Command("cri", "Generate the credential revocation information of an epoch for this Idemix MSP")
+	genCRIEpoch             = genCRI.Flag("epoch", "The epoch the credential revocation information is valid in").Short('e').Required().Int64()
+	genCRIHandles           = genCRI.Flag("unrevoked", "A revocation handle that is not revoked in this epoch (can be repeated)").Short('r').Ints()
 
 	version = app.Command("version", "Show version information")
 )
@@ -53,6 +60,8 @@ func main() {
 	case genIssuerKey.FullCommand():
 		isk, ipk, err := idemixca.GenerateIssuerKey()
 		handleError(err)
+		rsk, rpk, err := idemixca.GenerateRevocationKey()
+		handleError(err)
 
 		// Prevent overwriting the existing key
 		path := filepath.Join(IdemixDirIssuer)
@@ -67,6 +76,9 @@ func main() {
 		writeFile(filepath.Join(IdemixDirIssuer, IdemixConfigIssuerSecretKey), isk)
 		writeFile(filepath.Join(IdemixDirIssuer, msp.IdemixConfigFileIssuerPublicKey), ipk)
 		writeFile(filepath.Join(msp.IdemixConfigDirMsp, msp.IdemixConfigFileIssuerPublicKey), ipk)
+		writeFile(filepath.Join(IdemixDirIssuer, IdemixConfigRevocationSecretKey), rsk)
+		writeFile(filepath.Join(IdemixDirIssuer, msp.IdemixConfigFileRevocationPublicKey), rpk)
+		writeFile(filepath.Join(msp.IdemixConfigDirMsp, msp.IdemixConfigFileRevocationPublicKey), rpk)
 
 	case genSignerConfig.FullCommand():
 		config, err := idemixca.GenerateSignerConfig(*genCredIsAdmin, *genCredOU, *genCredEnrollmentId, *genCredRevocationHandle, readIssuerKey())
@@ -79,6 +91,13 @@ func main() {
 		handleError(os.Mkdir(msp.IdemixConfigDirUser, 0770))
 		writeFile(filepath.Join(msp.IdemixConfigDirUser, msp.IdemixConfigFileSigner), config)
 
+	case genCRI.FullCommand():
+		cri, err := idemixca.GenerateCRI(readRevocationKey(), *genCRIEpoch, *genCRIHandles)
+		handleError(err)
+
+		// The credential revocation information of the previous epoch is replaced
+		writeFile(filepath.Join(msp.IdemixConfigDirMsp, msp.IdemixConfigFileRevocationInformation), cri)
+
 	case version.FullCommand():
 		printVersion()
 	}
@@ -112,6 +131,23 @@ func readIssuerKey() *idemix.IssuerKey {
 	return key
 }
 
+// readRevocationKey reads the revocation secret key from the current directory
+func readRevocationKey() *ecdsa.PrivateKey {
+	path := filepath.Join(IdemixDirIssuer, IdemixConfigRevocationSecretKey)
+	keyBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		handleError(errors.Wrapf(err, "failed to open revocation secret key file: %s", path))
+	}
+	block, _ := pem.Decode(keyBytes)
+	if block == nil {
+		handleError(errors.Errorf("failed to decode revocation secret key file: %s", path))
+	}
+	key, err := x509.ParseECPrivateKey(block.Bytes)
+	handleError(err)
+
+	return key
+}
+
 // checkDirectoryNotExists checks whether a directory with the given path already exists and exits if this is the case
 func checkDirectoryNotExists(path string, errorMessage string) {
 	_, err := os.Stat(path)
@@ -93,6 +93,7 @@ func (d *defaultACLProvider) initialize() {
 	//Event resources
 	d.cResourcePolicyMap[resources.Event_Block] = CHANNELREADERS
 	d.cResourcePolicyMap[resources.Event_FilteredBlock] = CHANNELREADERS
+	d.cResourcePolicyMap[resources.Event_FilteredBlockAndPrivateData] = CHANNELREADERS
 }
 
 //this should cover an exhaustive list of everything called from the peer
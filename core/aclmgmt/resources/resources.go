@@ -40,6 +40,7 @@ const (
 	Peer_ChaincodeToChaincode = "peer/ChaincodeToChaincode"
 
 	//Events
-	Event_Block         = "event/Block"
-	Event_FilteredBlock = "event/FilteredBlock"
+	Event_Block                       = "event/Block"
+	Event_FilteredBlock               = "event/FilteredBlock"
+	Event_FilteredBlockAndPrivateData = "event/FilteredBlockAndPrivateData"
 )
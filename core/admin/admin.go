@@ -62,6 +62,8 @@ func (s *ServerAdmin) StartServer(ctx context.Context, env *common.Envelope) (*p
 	return status, nil
 }
 
+// GetModuleLogLevel returns the logging level of the requested module.
+// Deprecated: the logging specification is served by the /logspec resource of the operations endpoint
 func (s *ServerAdmin) GetModuleLogLevel(ctx context.Context, env *common.Envelope) (*pb.LogLevelResponse, error) {
 	op, err := s.v.validate(ctx, env)
 	if err != nil {
@@ -76,6 +78,8 @@ func (s *ServerAdmin) GetModuleLogLevel(ctx context.Context, env *common.Envelop
 	return logResponse, nil
 }
 
+// SetModuleLogLevel sets the logging level of the modules that match the requested regular expression.
+// Deprecated: the logging specification is updated through the /logspec resource of the operations endpoint
 func (s *ServerAdmin) SetModuleLogLevel(ctx context.Context, env *common.Envelope) (*pb.LogLevelResponse, error) {
 	op, err := s.v.validate(ctx, env)
 	if err != nil {
@@ -90,6 +94,8 @@ func (s *ServerAdmin) SetModuleLogLevel(ctx context.Context, env *common.Envelop
 	return logResponse, err
 }
 
+// RevertLogLevels reverts the logging levels to the levels at the end of the peer startup.
+// Deprecated: the logging specification is updated through the /logspec resource of the operations endpoint
 func (s *ServerAdmin) RevertLogLevels(ctx context.Context, env *common.Envelope) (*empty.Empty, error) {
 	if _, err := s.v.validate(ctx, env); err != nil {
 		return nil, err
@@ -19,6 +19,7 @@ import (
 	"github.com/hyperledger/fabric/core/cclifecycle"
 	"github.com/hyperledger/fabric/core/cclifecycle/mocks"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/common/privdata"
 	"github.com/hyperledger/fabric/core/ledger/cceventmgmt"
 	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/op/go-logging"
@@ -31,6 +32,9 @@ func init() {
 	logging.SetLevel(logging.DEBUG, "discovery/lifecycle")
 }
 
+// collectionsKey matches the keys of the collections of chaincodes in lscc
+var collectionsKey = mock.MatchedBy(privdata.IsCollectionConfigKey)
+
 func TestNewQuery(t *testing.T) {
 	// This tests that the QueryCreatorFunc can cast the below function to the interface type
 	var q cc.Query
@@ -92,6 +96,7 @@ func TestHandleChaincodeDeployGreenPath(t *testing.T) {
 	})
 
 	query := &mocks.Query{}
+	query.On("GetState", "lscc", collectionsKey).Return(nil, nil)
 	query.On("GetState", "lscc", "cc1").Return(cc1Bytes, nil)
 	query.On("GetState", "lscc", "cc2").Return(cc2Bytes, nil)
 	query.On("GetState", "lscc", "cc3").Return(cc3Bytes, nil).Once()
@@ -203,6 +208,7 @@ func TestHandleChaincodeDeployFailures(t *testing.T) {
 	})
 
 	query := &mocks.Query{}
+	query.On("GetState", "lscc", collectionsKey).Return(nil, nil)
 	query.On("Done")
 	queryCreator := &mocks.QueryCreator{}
 	enum := &mocks.Enumerator{}
@@ -287,6 +293,7 @@ func TestMetadata(t *testing.T) {
 	})
 
 	query := &mocks.Query{}
+	query.On("GetState", "lscc", collectionsKey).Return(nil, nil)
 	query.On("GetState", "lscc", "cc3").Return(cc1Bytes, nil)
 	query.On("Done")
 	queryCreator := &mocks.QueryCreator{}
@@ -13,6 +13,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/chaincode"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/common/privdata"
 	"github.com/pkg/errors"
 )
 
@@ -100,11 +101,18 @@ func DeployedChaincodes(q Query, filter ChaincodePredicate, chaincodes ...string
 			continue
 		}
 
+		collConfig, err := q.GetState("lscc", privdata.BuildCollectionKVSKey(cc))
+		if err != nil {
+			Logger.Error("Failed querying lscc namespace for the collections of", cc, ":", err)
+			return nil, errors.WithStack(err)
+		}
+
 		instCC := chaincode.Metadata{
-			Name:    ccInfo.Name,
-			Version: ccInfo.Version,
-			Id:      ccInfo.Id,
-			Policy:  ccInfo.Policy,
+			Name:              ccInfo.Name,
+			Version:           ccInfo.Version,
+			Id:                ccInfo.Id,
+			Policy:            ccInfo.Policy,
+			CollectionsConfig: collConfig,
 		}
 
 		if !filter(instCC) {
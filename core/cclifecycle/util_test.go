@@ -251,6 +251,7 @@ func TestChaincodeInspection(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			query := &mocks.Query{}
 			query.On("Done")
+			query.On("GetState", "lscc", collectionsKey).Return(nil, nil)
 			query.On("GetState", mock.Anything, mock.Anything).Return(test.returnedCCBytes, test.queryErr).Once()
 			query.On("GetState", mock.Anything, mock.Anything).Return(cc2Bytes, nil).Once()
 			ccInfo, err := cc.DeployedChaincodes(query, test.filter, test.queriedChaincodes...)
@@ -263,3 +264,32 @@ func TestChaincodeInspection(t *testing.T) {
 		})
 	}
 }
+
+func TestDeployedChaincodesCollectionsConfig(t *testing.T) {
+	cc1Bytes, _ := proto.Marshal(&ccprovider.ChaincodeData{Name: "cc1", Version: "1.0", Id: []byte{42}})
+	collConfigBytes, _ := proto.Marshal(&common.CollectionConfigPackage{
+		Config: []*common.CollectionConfig{
+			{
+				Payload: &common.CollectionConfig_StaticCollectionConfig{
+					StaticCollectionConfig: &common.StaticCollectionConfig{Name: "collection1"},
+				},
+			},
+		},
+	})
+
+	query := &mocks.Query{}
+	query.On("Done")
+	query.On("GetState", "lscc", "cc1").Return(cc1Bytes, nil)
+	query.On("GetState", "lscc", "cc1~collection").Return(collConfigBytes, nil).Once()
+	ccInfo, err := cc.DeployedChaincodes(query, cc.AcceptAll, "cc1")
+	assert.NoError(t, err)
+	assert.Equal(t, chaincode.MetadataSet{
+		{Name: "cc1", Version: "1.0", Id: []byte{42}, CollectionsConfig: collConfigBytes},
+	}, ccInfo)
+
+	// failure in querying the collections of the chaincode
+	query.On("GetState", "lscc", "cc1~collection").Return(nil, errors.New("failed querying ledger")).Once()
+	ccInfo, err = cc.DeployedChaincodes(query, cc.AcceptAll, "cc1")
+	assert.EqualError(t, err, "failed querying ledger")
+	assert.Nil(t, ccInfo)
+}
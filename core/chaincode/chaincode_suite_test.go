@@ -36,6 +36,11 @@ type resultsIterator interface {
 	commonledger.ResultsIterator
 }
 
+//go:generate counterfeiter -o mock/query_results_iterator.go --fake-name QueryResultsIterator . queryResultsIterator
+type queryResultsIterator interface {
+	ledger.QueryResultsIterator
+}
+
 //go:generate counterfeiter -o mock/runtime.go --fake-name Runtime . chaincodeRuntime
 type chaincodeRuntime interface {
 	Runtime
@@ -68,6 +68,9 @@ func NewChaincodeSupport(
 		PeerAddress:   peerAddress,
 		PeerID:        config.PeerID,
 		PeerNetworkID: config.PeerNetworkID,
+		// the processor must have a provider for the External VM type
+		// registered when external builders are configured
+		UseExternalBuilders: len(config.ExternalBuilders) > 0,
 		CommonEnv: []string{
 			"CORE_CHAINCODE_LOGGING_LEVEL=" + config.LogLevel,
 			"CORE_CHAINCODE_LOGGING_SHIM=" + config.ShimLogLevel,
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/container/externalbuilder"
 	logging "github.com/op/go-logging"
 	"github.com/spf13/viper"
 )
@@ -31,6 +32,9 @@ type Config struct {
 	LogFormat      string
 	LogLevel       string
 	ShimLogLevel   string
+	// ExternalBuilders are the builders used to launch user chaincode
+	// instead of Docker, if any
+	ExternalBuilders []externalbuilder.Builder
 }
 
 func GlobalConfig() *Config {
@@ -62,6 +66,11 @@ func (c *Config) load() {
 	c.LogFormat = viper.GetString("chaincode.logging.format")
 	c.LogLevel = getLogLevelFromViper("chaincode.logging.level")
 	c.ShimLogLevel = getLogLevelFromViper("chaincode.logging.shim")
+
+	if err := viper.UnmarshalKey("chaincode.externalBuilders", &c.ExternalBuilders); err != nil {
+		chaincodeLogger.Warningf("chaincode.externalBuilders is malformed, ignoring it: %s", err)
+		c.ExternalBuilders = nil
+	}
 }
 
 func toSeconds(s string, def int) time.Duration {
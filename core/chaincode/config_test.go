@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/hyperledger/fabric/core/chaincode"
+	"github.com/hyperledger/fabric/core/container/externalbuilder"
 	"github.com/spf13/viper"
 )
 
@@ -84,6 +85,27 @@ func TestGlobalConfigInvalidLogLevel(t *testing.T) {
 	assert.Equal(t, "INFO", config.ShimLogLevel)
 }
 
+func TestGlobalConfigExternalBuilders(t *testing.T) {
+	defer viper.Set("chaincode.externalBuilders", nil)
+
+	config := chaincode.GlobalConfig()
+	assert.Empty(t, config.ExternalBuilders)
+
+	viper.Set("chaincode.externalBuilders", []map[string]interface{}{
+		{"name": "builder1", "path": "/path/to/builder1"},
+		{"name": "builder2", "path": "/path/to/builder2", "environmentWhitelist": []string{"GOPROXY", "GOCACHE"}},
+	})
+	config = chaincode.GlobalConfig()
+	assert.Equal(t, []externalbuilder.Builder{
+		{Name: "builder1", Path: "/path/to/builder1"},
+		{Name: "builder2", Path: "/path/to/builder2", EnvironmentWhitelist: []string{"GOPROXY", "GOCACHE"}},
+	}, config.ExternalBuilders)
+
+	viper.Set("chaincode.externalBuilders", "not-a-list")
+	config = chaincode.GlobalConfig()
+	assert.Empty(t, config.ExternalBuilders)
+}
+
 func TestIsDevMode(t *testing.T) {
 	cleanup := capture()
 	defer cleanup()
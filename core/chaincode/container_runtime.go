@@ -12,12 +12,14 @@ import (
 	"io"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/hyperledger/fabric/core/chaincode/accesscontrol"
 	"github.com/hyperledger/fabric/core/chaincode/platforms"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/container"
 	"github.com/hyperledger/fabric/core/container/ccintf"
+	"github.com/hyperledger/fabric/core/container/externalbuilder"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/pkg/errors"
 	"golang.org/x/net/context"
@@ -44,6 +46,15 @@ type ContainerRuntime struct {
 	PeerAddress   string
 	PeerID        string
 	PeerNetworkID string
+	// UseExternalBuilders selects the external builders configured in
+	// core.yaml rather than Docker to launch user chaincode. The chaincode
+	// that none of the external builders detects is launched with Docker
+	UseExternalBuilders bool
+
+	mutex sync.Mutex
+	// dockerFallbacks holds the canonical names of the chaincodes that
+	// were not detected by any external builder
+	dockerFallbacks map[string]struct{}
 }
 
 // Start launches chaincode in a runtime environment.
@@ -59,11 +70,40 @@ func (c *ContainerRuntime) Start(ctxt context.Context, cccid *ccprovider.CCConte
 	chaincodeLogger.Debugf("start container with args: %s", strings.Join(lc.Args, " "))
 	chaincodeLogger.Debugf("start container with env:\n\t%s", strings.Join(lc.Envs, "\n\t"))
 
+	vmtype := c.getVMType(cname, cds)
+	err = c.start(ctxt, vmtype, cds, cccid, lc)
+	if _, notDetected := errors.Cause(err).(*externalbuilder.ErrNotDetected); notDetected && vmtype == container.EXTERNAL {
+		chaincodeLogger.Infof("no external builder detected chaincode %s, launching it with Docker", cname)
+		c.mutex.Lock()
+		if c.dockerFallbacks == nil {
+			c.dockerFallbacks = make(map[string]struct{})
+		}
+		c.dockerFallbacks[cname] = struct{}{}
+		c.mutex.Unlock()
+		err = c.start(ctxt, container.DOCKER, cds, cccid, lc)
+	}
+	if err != nil {
+		return errors.WithMessage(err, "error starting container")
+	}
+
+	return nil
+}
+
+// start launches chaincode with the given VM type
+func (c *ContainerRuntime) start(ctxt context.Context, vmtype string, cds *pb.ChaincodeDeploymentSpec, cccid *ccprovider.CCContext, lc *LaunchConfig) error {
 	builder := func() (io.Reader, error) { return platforms.GenerateDockerBuild(cds) }
+	envs := lc.Envs
+	if vmtype == container.EXTERNAL {
+		// external builders are handed the chaincode package as is and the
+		// chaincode runs on the host, so it needs the peer address from the environment
+		builder = func() (io.Reader, error) { return bytes.NewReader(cds.CodePackage), nil }
+		envs = append(append([]string{}, lc.Envs...), "CORE_PEER_ADDRESS="+c.PeerAddress)
+	}
+
 	scr := container.StartContainerReq{
 		Builder:       builder,
 		Args:          lc.Args,
-		Env:           lc.Envs,
+		Env:           envs,
 		FilesToUpload: lc.Files,
 		CCID: ccintf.CCID{
 			ChaincodeSpec: cds.ChaincodeSpec,
@@ -73,14 +113,8 @@ func (c *ContainerRuntime) Start(ctxt context.Context, cccid *ccprovider.CCConte
 		},
 	}
 
-	vmtype := getVMType(cds)
 	resp, err := c.Processor.Process(ctxt, vmtype, scr)
-	err = selectError(&resp, err)
-	if err != nil {
-		return errors.WithMessage(err, "error starting container")
-	}
-
-	return nil
+	return selectError(&resp, err)
 }
 
 // Stop terminates chaincode and its container runtime environment.
@@ -96,7 +130,7 @@ func (c *ContainerRuntime) Stop(ctxt context.Context, cccid *ccprovider.CCContex
 		Dontremove: false,
 	}
 
-	resp, err := c.Processor.Process(ctxt, getVMType(cds), scr)
+	resp, err := c.Processor.Process(ctxt, c.getVMType(cccid.GetCanonicalName(), cds), scr)
 	err = selectError(&resp, err)
 	if err != nil {
 		return errors.WithMessage(err, "error stopping container")
@@ -105,10 +139,18 @@ func (c *ContainerRuntime) Stop(ctxt context.Context, cccid *ccprovider.CCContex
 	return nil
 }
 
-func getVMType(cds *pb.ChaincodeDeploymentSpec) string {
+func (c *ContainerRuntime) getVMType(cname string, cds *pb.ChaincodeDeploymentSpec) string {
 	if cds.ExecEnv == pb.ChaincodeDeploymentSpec_SYSTEM {
 		return container.SYSTEM
 	}
+	if c.UseExternalBuilders {
+		c.mutex.Lock()
+		_, fallback := c.dockerFallbacks[cname]
+		c.mutex.Unlock()
+		if !fallback {
+			return container.EXTERNAL
+		}
+	}
 	return container.DOCKER
 }
 
@@ -8,6 +8,7 @@ package chaincode_test
 
 import (
 	"context"
+	"io/ioutil"
 	"testing"
 
 	"github.com/hyperledger/fabric/core/chaincode"
@@ -16,6 +17,7 @@ import (
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/container"
 	"github.com/hyperledger/fabric/core/container/ccintf"
+	"github.com/hyperledger/fabric/core/container/externalbuilder"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
@@ -206,6 +208,98 @@ func TestContainerRuntimeStart(t *testing.T) {
 	}
 }
 
+func TestContainerRuntimeStartExternalBuilders(t *testing.T) {
+	fakeProcessor := &mock.Processor{}
+	cr := &chaincode.ContainerRuntime{
+		Processor:           fakeProcessor,
+		PeerAddress:         "peer.example.com",
+		PeerID:              "peer-id",
+		PeerNetworkID:       "peer-network-id",
+		UseExternalBuilders: true,
+	}
+
+	ccctx := ccprovider.NewCCContext("context-chain-id", "context-name", "context-version", "context-tx-id", false, nil, nil)
+	cds := &pb.ChaincodeDeploymentSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			Type: pb.ChaincodeSpec_GOLANG,
+		},
+		CodePackage: []byte("code-package"),
+	}
+
+	err := cr.Start(context.Background(), ccctx, cds)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, fakeProcessor.ProcessCallCount())
+	_, vmType, req := fakeProcessor.ProcessArgsForCall(0)
+	assert.Equal(t, container.EXTERNAL, vmType)
+	startReq, ok := req.(container.StartContainerReq)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"CORE_CHAINCODE_ID_NAME=context-name:context-version", "CORE_PEER_TLS_ENABLED=false", "CORE_PEER_ADDRESS=peer.example.com"}, startReq.Env)
+
+	// the code package is handed to the external builders as is
+	reader, err := startReq.Builder()
+	assert.NoError(t, err)
+	codePackage, err := ioutil.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("code-package"), codePackage)
+
+	// system chaincode still runs in process
+	cds.ExecEnv = pb.ChaincodeDeploymentSpec_SYSTEM
+	err = cr.Stop(context.Background(), ccctx, cds)
+	assert.NoError(t, err)
+	_, vmType, _ = fakeProcessor.ProcessArgsForCall(1)
+	assert.Equal(t, container.SYSTEM, vmType)
+}
+
+func TestContainerRuntimeExternalBuildersDockerFallback(t *testing.T) {
+	fakeProcessor := &mock.Processor{}
+	fakeProcessor.ProcessReturnsOnCall(0, container.VMCResp{Err: &externalbuilder.ErrNotDetected{Chaincode: "context-name-context-version"}}, nil)
+	cr := &chaincode.ContainerRuntime{
+		Processor:           fakeProcessor,
+		PeerAddress:         "peer.example.com",
+		PeerID:              "peer-id",
+		PeerNetworkID:       "peer-network-id",
+		UseExternalBuilders: true,
+	}
+
+	ccctx := ccprovider.NewCCContext("context-chain-id", "context-name", "context-version", "context-tx-id", false, nil, nil)
+	cds := &pb.ChaincodeDeploymentSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			Type: pb.ChaincodeSpec_GOLANG,
+		},
+	}
+
+	// the chaincode that no external builder detects is launched with Docker
+	err := cr.Start(context.Background(), ccctx, cds)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, fakeProcessor.ProcessCallCount())
+	_, vmType, _ := fakeProcessor.ProcessArgsForCall(0)
+	assert.Equal(t, container.EXTERNAL, vmType)
+	_, vmType, req := fakeProcessor.ProcessArgsForCall(1)
+	assert.Equal(t, container.DOCKER, vmType)
+	startReq, ok := req.(container.StartContainerReq)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"CORE_CHAINCODE_ID_NAME=context-name:context-version", "CORE_PEER_TLS_ENABLED=false"}, startReq.Env)
+
+	// it is stopped and started again with Docker
+	err = cr.Stop(context.Background(), ccctx, cds)
+	assert.NoError(t, err)
+	_, vmType, _ = fakeProcessor.ProcessArgsForCall(2)
+	assert.Equal(t, container.DOCKER, vmType)
+	err = cr.Start(context.Background(), ccctx, cds)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, fakeProcessor.ProcessCallCount())
+	_, vmType, _ = fakeProcessor.ProcessArgsForCall(3)
+	assert.Equal(t, container.DOCKER, vmType)
+
+	// other failures of the external builders are not recovered with Docker
+	fakeProcessor.ProcessReturnsOnCall(4, container.VMCResp{}, errors.New("build failed"))
+	otherctx := ccprovider.NewCCContext("context-chain-id", "other-name", "context-version", "context-tx-id", false, nil, nil)
+	err = cr.Start(context.Background(), otherctx, cds)
+	assert.EqualError(t, err, "error starting container: build failed")
+	assert.Equal(t, 5, fakeProcessor.ProcessCallCount())
+}
+
 func TestContainerRuntimeStartErrors(t *testing.T) {
 	tests := []struct {
 		chaincodeType pb.ChaincodeSpec_Type
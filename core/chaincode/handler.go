@@ -135,11 +135,15 @@ func (h *Handler) handleMessageReadyState(msg *pb.ChaincodeMessage) error {
 		go h.handleModState(msg)
 	case pb.ChaincodeMessage_DEL_STATE:
 		go h.handleModState(msg)
+	case pb.ChaincodeMessage_PUT_STATE_METADATA:
+		go h.handleModState(msg)
 	case pb.ChaincodeMessage_INVOKE_CHAINCODE:
 		go h.handleModState(msg)
 
 	case pb.ChaincodeMessage_GET_STATE:
 		go h.handleGetState(msg)
+	case pb.ChaincodeMessage_GET_STATE_METADATA:
+		go h.handleGetStateMetadata(msg)
 	case pb.ChaincodeMessage_GET_STATE_BY_RANGE:
 		go h.handleGetStateByRange(msg)
 	case pb.ChaincodeMessage_GET_QUERY_RESULT:
@@ -540,6 +544,85 @@ func (h *Handler) handleGetState(msg *pb.ChaincodeMessage) {
 	}
 }
 
+// checkMetadataCapability returns an error if the channel does not enable
+// key-level endorsement, which state metadata is required for
+func (h *Handler) checkMetadataCapability(channelID string) error {
+	ac, exists := h.sccp.GetApplicationConfig(channelID)
+	if !exists {
+		return errors.Errorf("application config does not exist for %s", channelID)
+	}
+	if !ac.Capabilities().KeyLevelEndorsement() {
+		return errors.New("key level endorsement is not enabled")
+	}
+	return nil
+}
+
+// Handles query to ledger to get state metadata
+func (h *Handler) handleGetStateMetadata(msg *pb.ChaincodeMessage) {
+	chaincodeLogger.Debugf("[%s]handling %s from chaincode", shorttxid(msg.Txid), pb.ChaincodeMessage_GET_STATE_METADATA)
+	if !h.registerTxid(msg) {
+		return
+	}
+
+	var serialSendMsg *pb.ChaincodeMessage
+	var txContext *TransactionContext
+	txContext, serialSendMsg = h.isValidTxSim(msg.ChannelId, msg.Txid,
+		"[%s]No ledger context for GetStateMetadata. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_ERROR)
+
+	defer func() {
+		h.deRegisterTxid(msg, serialSendMsg, false)
+	}()
+
+	if txContext == nil {
+		return
+	}
+
+	errHandler := func(err error, errFmt string, errArgs ...interface{}) {
+		chaincodeLogger.Errorf(errFmt, errArgs...)
+		serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: []byte(err.Error()), Txid: msg.Txid, ChannelId: msg.ChannelId}
+	}
+
+	if err := h.checkMetadataCapability(txContext.chainID); err != nil {
+		errHandler(err, "[%s]Cannot get state metadata: %s. Sending %s", shorttxid(msg.Txid), err, pb.ChaincodeMessage_ERROR)
+		return
+	}
+
+	getStateMetadata := &pb.GetStateMetadata{}
+	if err := proto.Unmarshal(msg.Payload, getStateMetadata); err != nil {
+		errHandler(err, "[%s]Unable to decipher payload. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_ERROR)
+		return
+	}
+	chaincodeID := h.getCCRootName()
+	chaincodeLogger.Debugf("[%s] getting state metadata for chaincode %s, key %s, channel %s",
+		shorttxid(msg.Txid), chaincodeID, getStateMetadata.Key, txContext.chainID)
+
+	var metadata map[string][]byte
+	var err error
+	if isCollectionSet(getStateMetadata.Collection) {
+		metadata, err = txContext.txsimulator.GetPrivateMetadata(chaincodeID, getStateMetadata.Collection, getStateMetadata.Key)
+	} else {
+		metadata, err = txContext.txsimulator.GetStateMetadata(chaincodeID, getStateMetadata.Key)
+	}
+	if err != nil {
+		errHandler(err, "[%s]Failed to get chaincode state metadata(%s). Sending %s", shorttxid(msg.Txid), err, pb.ChaincodeMessage_ERROR)
+		return
+	}
+
+	var metadataResult pb.StateMetadataResult
+	for metakey := range metadata {
+		md := &pb.StateMetadata{Metakey: metakey, Value: metadata[metakey]}
+		metadataResult.Entries = append(metadataResult.Entries, md)
+	}
+	res, err := proto.Marshal(&metadataResult)
+	if err != nil {
+		errHandler(err, "[%s]Failed to marshal state metadata(%s). Sending %s", shorttxid(msg.Txid), err, pb.ChaincodeMessage_ERROR)
+		return
+	}
+
+	chaincodeLogger.Debugf("[%s]Got state metadata. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_RESPONSE)
+	serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: res, Txid: msg.Txid, ChannelId: msg.ChannelId}
+}
+
 // Handles query to ledger to rage query state
 func (h *Handler) handleGetStateByRange(msg *pb.ChaincodeMessage) {
 	chaincodeLogger.Debugf("[%s]handling %s from chaincode", shorttxid(msg.Txid), pb.ChaincodeMessage_GET_STATE_BY_RANGE)
@@ -585,8 +668,20 @@ func (h *Handler) handleGetStateByRange(msg *pb.ChaincodeMessage) {
 	var rangeIter commonledger.ResultsIterator
 	var err error
 
+	isPaginated := len(getStateByRange.Metadata) > 0
 	if isCollectionSet(getStateByRange.Collection) {
+		if isPaginated {
+			errHandler(errors.New("pagination is not supported for private data queries"), nil, "Failed to get ledger scan iterator. Sending %s", pb.ChaincodeMessage_ERROR)
+			return
+		}
 		rangeIter, err = txContext.txsimulator.GetPrivateDataRangeScanIterator(chaincodeID, getStateByRange.Collection, getStateByRange.StartKey, getStateByRange.EndKey)
+	} else if isPaginated {
+		var metadata *pb.QueryMetadata
+		if metadata, err = getQueryMetadataFromBytes(getStateByRange.Metadata); err != nil {
+			errHandler(err, nil, "Failed to unmarshal query metadata. Sending %s", pb.ChaincodeMessage_ERROR)
+			return
+		}
+		rangeIter, err = txContext.txsimulator.GetStateRangeScanIteratorWithPagination(chaincodeID, getStateByRange.StartKey, getStateByRange.EndKey, metadata.PageSize, metadata.Bookmark)
 	} else {
 		rangeIter, err = txContext.txsimulator.GetStateRangeScanIterator(chaincodeID, getStateByRange.StartKey, getStateByRange.EndKey)
 	}
@@ -598,7 +693,7 @@ func (h *Handler) handleGetStateByRange(msg *pb.ChaincodeMessage) {
 	txContext.InitializeQueryContext(iterID, rangeIter)
 
 	var payload *pb.QueryResponse
-	payload, err = getQueryResponse(txContext, rangeIter, iterID)
+	payload, err = getQueryResponse(txContext, rangeIter, iterID, isPaginated)
 	if err != nil {
 		errHandler(err, rangeIter, "Failed to get query result. Sending %s", pb.ChaincodeMessage_ERROR)
 		return
@@ -616,8 +711,11 @@ func (h *Handler) handleGetStateByRange(msg *pb.ChaincodeMessage) {
 
 const maxResultLimit = 100
 
-// getQueryResponse takes an iterator and fetch state to construct QueryResponse
-func getQueryResponse(txContext *TransactionContext, iter commonledger.ResultsIterator, iterID string) (*pb.QueryResponse, error) {
+// getQueryResponse takes an iterator and fetch state to construct QueryResponse.
+// The results of a paginated query are not split into batches; the whole page is
+// returned in a single response along with the metadata that carries the bookmark
+// for retrieving the next page
+func getQueryResponse(txContext *TransactionContext, iter commonledger.ResultsIterator, iterID string, isPaginated bool) (*pb.QueryResponse, error) {
 	pendingQueryResults := txContext.pendingQueryResults[iterID]
 	for {
 		queryResult, err := iter.Next()
@@ -629,9 +727,18 @@ func getQueryResponse(txContext *TransactionContext, iter commonledger.ResultsIt
 		case queryResult == nil:
 			// nil response from iterator indicates end of query results
 			batch := pendingQueryResults.Cut()
+			if isPaginated {
+				bookmark := txContext.CleanupQueryContextWithBookmark(iterID)
+				responseMetadata := &pb.QueryResponseMetadata{FetchedRecordsCount: int32(len(batch)), Bookmark: bookmark}
+				responseMetadataBytes, err := proto.Marshal(responseMetadata)
+				if err != nil {
+					return nil, err
+				}
+				return &pb.QueryResponse{Results: batch, HasMore: false, Id: iterID, Metadata: responseMetadataBytes}, nil
+			}
 			txContext.CleanupQueryContext(iterID)
 			return &pb.QueryResponse{Results: batch, HasMore: false, Id: iterID}, nil
-		case pendingQueryResults.Size() == maxResultLimit:
+		case !isPaginated && pendingQueryResults.Size() == maxResultLimit:
 			// max number of results queued up, cut batch, then add current result to pending batch
 			batch := pendingQueryResults.Cut()
 			if err := pendingQueryResults.Add(queryResult); err != nil {
@@ -648,6 +755,17 @@ func getQueryResponse(txContext *TransactionContext, iter commonledger.ResultsIt
 	}
 }
 
+func getQueryMetadataFromBytes(metadataBytes []byte) (*pb.QueryMetadata, error) {
+	metadata := &pb.QueryMetadata{}
+	if err := proto.Unmarshal(metadataBytes, metadata); err != nil {
+		return nil, err
+	}
+	if metadata.PageSize <= 0 {
+		return nil, errors.Errorf("invalid page size %d, the page size must be greater than zero", metadata.PageSize)
+	}
+	return metadata, nil
+}
+
 // Handles query to ledger for query state next
 func (h *Handler) handleQueryStateNext(msg *pb.ChaincodeMessage) {
 	chaincodeLogger.Debugf("[%s]handling %s from chaincode", shorttxid(msg.Txid), pb.ChaincodeMessage_QUERY_STATE_NEXT)
@@ -694,7 +812,7 @@ func (h *Handler) handleQueryStateNext(msg *pb.ChaincodeMessage) {
 		return
 	}
 
-	payload, err := getQueryResponse(txContext, queryIter, queryStateNext.Id)
+	payload, err := getQueryResponse(txContext, queryIter, queryStateNext.Id, false)
 	if err != nil {
 		errHandler([]byte(err.Error()), queryIter, "Failed to get query result. Sending %s", pb.ChaincodeMessage_ERROR)
 		return
@@ -799,8 +917,20 @@ func (h *Handler) handleGetQueryResult(msg *pb.ChaincodeMessage) {
 
 	var err error
 	var executeIter commonledger.ResultsIterator
+	isPaginated := len(getQueryResult.Metadata) > 0
 	if isCollectionSet(getQueryResult.Collection) {
+		if isPaginated {
+			errHandler([]byte("pagination is not supported for private data queries"), nil, "Failed to get ledger query iterator. Sending %s", pb.ChaincodeMessage_ERROR)
+			return
+		}
 		executeIter, err = txContext.txsimulator.ExecuteQueryOnPrivateData(chaincodeID, getQueryResult.Collection, getQueryResult.Query)
+	} else if isPaginated {
+		var metadata *pb.QueryMetadata
+		if metadata, err = getQueryMetadataFromBytes(getQueryResult.Metadata); err != nil {
+			errHandler([]byte(err.Error()), nil, "Failed to unmarshal query metadata. Sending %s", pb.ChaincodeMessage_ERROR)
+			return
+		}
+		executeIter, err = txContext.txsimulator.ExecuteQueryWithPagination(chaincodeID, getQueryResult.Query, metadata.PageSize, metadata.Bookmark)
 	} else {
 		executeIter, err = txContext.txsimulator.ExecuteQuery(chaincodeID, getQueryResult.Query)
 	}
@@ -813,7 +943,7 @@ func (h *Handler) handleGetQueryResult(msg *pb.ChaincodeMessage) {
 	txContext.InitializeQueryContext(iterID, executeIter)
 
 	var payload *pb.QueryResponse
-	payload, err = getQueryResponse(txContext, executeIter, iterID)
+	payload, err = getQueryResponse(txContext, executeIter, iterID, isPaginated)
 	if err != nil {
 		errHandler([]byte(err.Error()), executeIter, "Failed to get query result. Sending %s", pb.ChaincodeMessage_ERROR)
 		return
@@ -879,7 +1009,7 @@ func (h *Handler) handleGetHistoryForKey(msg *pb.ChaincodeMessage) {
 	txContext.InitializeQueryContext(iterID, historyIter)
 
 	var payload *pb.QueryResponse
-	payload, err = getQueryResponse(txContext, historyIter, iterID)
+	payload, err = getQueryResponse(txContext, historyIter, iterID, false)
 
 	if err != nil {
 		errHandler([]byte(err.Error()), historyIter, "Failed to get query result. Sending %s", pb.ChaincodeMessage_ERROR)
@@ -996,6 +1126,30 @@ func (h *Handler) handleModState(msg *pb.ChaincodeMessage) {
 			err = txContext.txsimulator.SetState(chaincodeID, putState.Key, putState.Value)
 		}
 
+	case pb.ChaincodeMessage_PUT_STATE_METADATA:
+		if err := h.checkMetadataCapability(txContext.chainID); err != nil {
+			errHandler([]byte(err.Error()), "[%s]Cannot put state metadata: %s. Sending %s", shorttxid(msg.Txid), err, pb.ChaincodeMessage_ERROR)
+			return
+		}
+
+		putStateMetadata := &pb.PutStateMetadata{}
+		unmarshalErr := proto.Unmarshal(msg.Payload, putStateMetadata)
+		if unmarshalErr != nil {
+			errHandler([]byte(unmarshalErr.Error()), "[%s]Unable to decipher payload. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_ERROR)
+			return
+		}
+		if putStateMetadata.Metadata == nil {
+			errHandler([]byte("no metadata entry provided"), "[%s]Missing metadata entry. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_ERROR)
+			return
+		}
+
+		metadata := putStateMetadata.Metadata
+		if isCollectionSet(putStateMetadata.Collection) {
+			err = txContext.txsimulator.SetPrivateMetadataEntry(chaincodeID, putStateMetadata.Collection, putStateMetadata.Key, metadata.Metakey, metadata.Value)
+		} else {
+			err = txContext.txsimulator.SetStateMetadataEntry(chaincodeID, putStateMetadata.Key, metadata.Metakey, metadata.Value)
+		}
+
 	case pb.ChaincodeMessage_DEL_STATE:
 		// Invoke ledger to delete state
 		delState := &pb.DelState{}
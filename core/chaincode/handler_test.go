@@ -11,8 +11,10 @@ import (
 	"math"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/protos/ledger/queryresult"
+	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -62,7 +64,7 @@ func TestGetQueryResponse(t *testing.T) {
 			resultsIterator.On("Close").Return().Once()
 			totalResultCount := 0
 			for hasMoreCount := 0; hasMoreCount <= tc.expectedHasMoreCount; hasMoreCount++ {
-				queryResponse, _ := getQueryResponse(transactionContext, resultsIterator, queryID)
+				queryResponse, _ := getQueryResponse(transactionContext, resultsIterator, queryID, false)
 				if queryResponse.GetHasMore() {
 					t.Logf("Got %d results and more are expected.", len(queryResponse.GetResults()))
 				} else {
@@ -89,6 +91,60 @@ func TestGetQueryResponse(t *testing.T) {
 
 }
 
+func TestGetQueryResponseWithPagination(t *testing.T) {
+	queryResult := &queryresult.KV{
+		Key:       "key",
+		Namespace: "namespace",
+		Value:     []byte("value"),
+	}
+
+	// the results of a paginated query are returned in a single response
+	for _, resultCount := range []int{0, 1, maxResultLimit, maxResultLimit + 1, 3 * maxResultLimit} {
+		transactionContext := &TransactionContext{
+			queryIteratorMap:    make(map[string]ledger.ResultsIterator),
+			pendingQueryResults: make(map[string]*PendingQueryResult),
+		}
+		queryID := "test"
+		t.Run(fmt.Sprintf("%d", resultCount), func(t *testing.T) {
+			resultsIterator := &MockQueryResultsIterator{}
+			transactionContext.InitializeQueryContext(queryID, resultsIterator)
+			if resultCount > 0 {
+				resultsIterator.On("Next").Return(queryResult, nil).Times(resultCount)
+			}
+			resultsIterator.On("Next").Return(nil, nil).Once()
+			resultsIterator.On("GetBookmarkAndClose").Return("nextKey").Once()
+
+			queryResponse, err := getQueryResponse(transactionContext, resultsIterator, queryID, true)
+			assert.NoError(t, err)
+			assert.False(t, queryResponse.GetHasMore())
+			assert.Len(t, queryResponse.GetResults(), resultCount)
+			responseMetadata := &pb.QueryResponseMetadata{}
+			assert.NoError(t, proto.Unmarshal(queryResponse.GetMetadata(), responseMetadata))
+			assert.Equal(t, int32(resultCount), responseMetadata.FetchedRecordsCount)
+			assert.Equal(t, "nextKey", responseMetadata.Bookmark)
+			assert.Nil(t, transactionContext.GetQueryIterator(queryID))
+			resultsIterator.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetQueryMetadataFromBytes(t *testing.T) {
+	metadataBytes, err := proto.Marshal(&pb.QueryMetadata{PageSize: 10, Bookmark: "key1"})
+	assert.NoError(t, err)
+	metadata, err := getQueryMetadataFromBytes(metadataBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(10), metadata.PageSize)
+	assert.Equal(t, "key1", metadata.Bookmark)
+
+	metadataBytes, err = proto.Marshal(&pb.QueryMetadata{PageSize: -1})
+	assert.NoError(t, err)
+	_, err = getQueryMetadataFromBytes(metadataBytes)
+	assert.EqualError(t, err, "invalid page size -1, the page size must be greater than zero")
+
+	_, err = getQueryMetadataFromBytes([]byte("garbage"))
+	assert.Error(t, err)
+}
+
 type MockResultsIterator struct {
 	mock.Mock
 }
@@ -104,3 +160,12 @@ func (m *MockResultsIterator) Next() (ledger.QueryResult, error) {
 func (m *MockResultsIterator) Close() {
 	m.Called()
 }
+
+type MockQueryResultsIterator struct {
+	MockResultsIterator
+}
+
+func (m *MockQueryResultsIterator) GetBookmarkAndClose() string {
+	args := m.Called()
+	return args.String(0)
+}
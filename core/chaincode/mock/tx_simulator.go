@@ -339,6 +339,39 @@ type TxSimulator struct {
 		result1 *ledger.TxSimulationResults
 		result2 error
 	}
+	GetStateRangeScanIteratorWithPaginationStub        func(namespace string, startKey string, endKey string, pageSize int32, bookmark string) (ledger.QueryResultsIterator, error)
+	getStateRangeScanIteratorWithPaginationMutex       sync.RWMutex
+	getStateRangeScanIteratorWithPaginationArgsForCall []struct {
+		namespace string
+		startKey  string
+		endKey    string
+		pageSize  int32
+		bookmark  string
+	}
+	getStateRangeScanIteratorWithPaginationReturns struct {
+		result1 ledger.QueryResultsIterator
+		result2 error
+	}
+	getStateRangeScanIteratorWithPaginationReturnsOnCall map[int]struct {
+		result1 ledger.QueryResultsIterator
+		result2 error
+	}
+	ExecuteQueryWithPaginationStub        func(namespace string, query string, pageSize int32, bookmark string) (ledger.QueryResultsIterator, error)
+	executeQueryWithPaginationMutex       sync.RWMutex
+	executeQueryWithPaginationArgsForCall []struct {
+		namespace string
+		query     string
+		pageSize  int32
+		bookmark  string
+	}
+	executeQueryWithPaginationReturns struct {
+		result1 ledger.QueryResultsIterator
+		result2 error
+	}
+	executeQueryWithPaginationReturnsOnCall map[int]struct {
+		result1 ledger.QueryResultsIterator
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -1609,6 +1642,115 @@ func (fake *TxSimulator) GetTxSimulationResultsReturnsOnCall(i int, result1 *led
 	}{result1, result2}
 }
 
+func (fake *TxSimulator) GetStateRangeScanIteratorWithPagination(namespace string, startKey string, endKey string, pageSize int32, bookmark string) (ledger.QueryResultsIterator, error) {
+	fake.getStateRangeScanIteratorWithPaginationMutex.Lock()
+	ret, specificReturn := fake.getStateRangeScanIteratorWithPaginationReturnsOnCall[len(fake.getStateRangeScanIteratorWithPaginationArgsForCall)]
+	fake.getStateRangeScanIteratorWithPaginationArgsForCall = append(fake.getStateRangeScanIteratorWithPaginationArgsForCall, struct {
+		namespace string
+		startKey  string
+		endKey    string
+		pageSize  int32
+		bookmark  string
+	}{namespace, startKey, endKey, pageSize, bookmark})
+	fake.recordInvocation("GetStateRangeScanIteratorWithPagination", []interface{}{namespace, startKey, endKey, pageSize, bookmark})
+	fake.getStateRangeScanIteratorWithPaginationMutex.Unlock()
+	if fake.GetStateRangeScanIteratorWithPaginationStub != nil {
+		return fake.GetStateRangeScanIteratorWithPaginationStub(namespace, startKey, endKey, pageSize, bookmark)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getStateRangeScanIteratorWithPaginationReturns.result1, fake.getStateRangeScanIteratorWithPaginationReturns.result2
+}
+
+func (fake *TxSimulator) GetStateRangeScanIteratorWithPaginationCallCount() int {
+	fake.getStateRangeScanIteratorWithPaginationMutex.RLock()
+	defer fake.getStateRangeScanIteratorWithPaginationMutex.RUnlock()
+	return len(fake.getStateRangeScanIteratorWithPaginationArgsForCall)
+}
+
+func (fake *TxSimulator) GetStateRangeScanIteratorWithPaginationArgsForCall(i int) (string, string, string, int32, string) {
+	fake.getStateRangeScanIteratorWithPaginationMutex.RLock()
+	defer fake.getStateRangeScanIteratorWithPaginationMutex.RUnlock()
+	return fake.getStateRangeScanIteratorWithPaginationArgsForCall[i].namespace, fake.getStateRangeScanIteratorWithPaginationArgsForCall[i].startKey, fake.getStateRangeScanIteratorWithPaginationArgsForCall[i].endKey, fake.getStateRangeScanIteratorWithPaginationArgsForCall[i].pageSize, fake.getStateRangeScanIteratorWithPaginationArgsForCall[i].bookmark
+}
+
+func (fake *TxSimulator) GetStateRangeScanIteratorWithPaginationReturns(result1 ledger.QueryResultsIterator, result2 error) {
+	fake.GetStateRangeScanIteratorWithPaginationStub = nil
+	fake.getStateRangeScanIteratorWithPaginationReturns = struct {
+		result1 ledger.QueryResultsIterator
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *TxSimulator) GetStateRangeScanIteratorWithPaginationReturnsOnCall(i int, result1 ledger.QueryResultsIterator, result2 error) {
+	fake.GetStateRangeScanIteratorWithPaginationStub = nil
+	if fake.getStateRangeScanIteratorWithPaginationReturnsOnCall == nil {
+		fake.getStateRangeScanIteratorWithPaginationReturnsOnCall = make(map[int]struct {
+			result1 ledger.QueryResultsIterator
+			result2 error
+		})
+	}
+	fake.getStateRangeScanIteratorWithPaginationReturnsOnCall[i] = struct {
+		result1 ledger.QueryResultsIterator
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *TxSimulator) ExecuteQueryWithPagination(namespace string, query string, pageSize int32, bookmark string) (ledger.QueryResultsIterator, error) {
+	fake.executeQueryWithPaginationMutex.Lock()
+	ret, specificReturn := fake.executeQueryWithPaginationReturnsOnCall[len(fake.executeQueryWithPaginationArgsForCall)]
+	fake.executeQueryWithPaginationArgsForCall = append(fake.executeQueryWithPaginationArgsForCall, struct {
+		namespace string
+		query     string
+		pageSize  int32
+		bookmark  string
+	}{namespace, query, pageSize, bookmark})
+	fake.recordInvocation("ExecuteQueryWithPagination", []interface{}{namespace, query, pageSize, bookmark})
+	fake.executeQueryWithPaginationMutex.Unlock()
+	if fake.ExecuteQueryWithPaginationStub != nil {
+		return fake.ExecuteQueryWithPaginationStub(namespace, query, pageSize, bookmark)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.executeQueryWithPaginationReturns.result1, fake.executeQueryWithPaginationReturns.result2
+}
+
+func (fake *TxSimulator) ExecuteQueryWithPaginationCallCount() int {
+	fake.executeQueryWithPaginationMutex.RLock()
+	defer fake.executeQueryWithPaginationMutex.RUnlock()
+	return len(fake.executeQueryWithPaginationArgsForCall)
+}
+
+func (fake *TxSimulator) ExecuteQueryWithPaginationArgsForCall(i int) (string, string, int32, string) {
+	fake.executeQueryWithPaginationMutex.RLock()
+	defer fake.executeQueryWithPaginationMutex.RUnlock()
+	return fake.executeQueryWithPaginationArgsForCall[i].namespace, fake.executeQueryWithPaginationArgsForCall[i].query, fake.executeQueryWithPaginationArgsForCall[i].pageSize, fake.executeQueryWithPaginationArgsForCall[i].bookmark
+}
+
+func (fake *TxSimulator) ExecuteQueryWithPaginationReturns(result1 ledger.QueryResultsIterator, result2 error) {
+	fake.ExecuteQueryWithPaginationStub = nil
+	fake.executeQueryWithPaginationReturns = struct {
+		result1 ledger.QueryResultsIterator
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *TxSimulator) ExecuteQueryWithPaginationReturnsOnCall(i int, result1 ledger.QueryResultsIterator, result2 error) {
+	fake.ExecuteQueryWithPaginationStub = nil
+	if fake.executeQueryWithPaginationReturnsOnCall == nil {
+		fake.executeQueryWithPaginationReturnsOnCall = make(map[int]struct {
+			result1 ledger.QueryResultsIterator
+			result2 error
+		})
+	}
+	fake.executeQueryWithPaginationReturnsOnCall[i] = struct {
+		result1 ledger.QueryResultsIterator
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *TxSimulator) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -1662,6 +1804,10 @@ func (fake *TxSimulator) Invocations() map[string][][]interface{} {
 	defer fake.deletePrivateMetadataMutex.RUnlock()
 	fake.getTxSimulationResultsMutex.RLock()
 	defer fake.getTxSimulationResultsMutex.RUnlock()
+	fake.getStateRangeScanIteratorWithPaginationMutex.RLock()
+	defer fake.getStateRangeScanIteratorWithPaginationMutex.RUnlock()
+	fake.executeQueryWithPaginationMutex.RLock()
+	defer fake.executeQueryWithPaginationMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
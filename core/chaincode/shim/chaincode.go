@@ -426,11 +426,20 @@ func (stub *ChaincodeStub) PutState(key string, value []byte) error {
 func (stub *ChaincodeStub) GetQueryResult(query string) (StateQueryIteratorInterface, error) {
 	// Access public data by setting the collection to empty string
 	collection := ""
-	response, err := stub.handler.handleGetQueryResult(collection, query, stub.ChannelId, stub.TxID)
+	iterator, _, err := stub.handleGetQueryResult(collection, query, nil)
+	return iterator, err
+}
+
+// GetQueryResultWithPagination documentation can be found in interfaces.go
+func (stub *ChaincodeStub) GetQueryResultWithPagination(query string, pageSize int32,
+	bookmark string) (StateQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+	// Access public data by setting the collection to empty string
+	collection := ""
+	metadata, err := createQueryMetadata(pageSize, bookmark)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return &StateQueryIterator{CommonIterator: &CommonIterator{stub.handler, stub.ChannelId, stub.TxID, response, 0}}, nil
+	return stub.handleGetQueryResult(collection, query, metadata)
 }
 
 // DelState documentation can be found in interfaces.go
@@ -440,6 +449,23 @@ func (stub *ChaincodeStub) DelState(key string) error {
 	return stub.handler.handleDelState(collection, key, stub.ChannelId, stub.TxID)
 }
 
+// SetStateValidationParameter documentation can be found in interfaces.go
+func (stub *ChaincodeStub) SetStateValidationParameter(key string, ep []byte) error {
+	return stub.handler.handlePutStateMetadataEntry("", key, pb.MetaDataKeys_VALIDATION_PARAMETER.String(), ep, stub.ChannelId, stub.TxID)
+}
+
+// GetStateValidationParameter documentation can be found in interfaces.go
+func (stub *ChaincodeStub) GetStateValidationParameter(key string) ([]byte, error) {
+	md, err := stub.handler.handleGetStateMetadata("", key, stub.ChannelId, stub.TxID)
+	if err != nil {
+		return nil, err
+	}
+	if ep, ok := md[pb.MetaDataKeys_VALIDATION_PARAMETER.String()]; ok {
+		return ep, nil
+	}
+	return nil, nil
+}
+
 // CommonIterator documentation can be found in interfaces.go
 type CommonIterator struct {
 	handler    *Handler
@@ -466,12 +492,43 @@ const (
 	HISTORY_QUERY_RESULT
 )
 
-func (stub *ChaincodeStub) handleGetStateByRange(collection, startKey, endKey string) (StateQueryIteratorInterface, error) {
-	response, err := stub.handler.handleGetStateByRange(collection, startKey, endKey, stub.ChannelId, stub.TxID)
+func (stub *ChaincodeStub) handleGetStateByRange(collection, startKey, endKey string,
+	metadata []byte) (StateQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+	response, err := stub.handler.handleGetStateByRange(collection, startKey, endKey, metadata, stub.ChannelId, stub.TxID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	return stub.createStateQueryIterator(response)
+}
+
+func (stub *ChaincodeStub) handleGetQueryResult(collection, query string,
+	metadata []byte) (StateQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+	response, err := stub.handler.handleGetQueryResult(collection, query, metadata, stub.ChannelId, stub.TxID)
+	if err != nil {
+		return nil, nil, err
 	}
-	return &StateQueryIterator{CommonIterator: &CommonIterator{stub.handler, stub.ChannelId, stub.TxID, response, 0}}, nil
+	return stub.createStateQueryIterator(response)
+}
+
+// createStateQueryIterator returns an iterator over the results of the query response and,
+// for a paginated query, the metadata of the response
+func (stub *ChaincodeStub) createStateQueryIterator(response *pb.QueryResponse) (StateQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+	iterator := &StateQueryIterator{CommonIterator: &CommonIterator{stub.handler, stub.ChannelId, stub.TxID, response, 0}}
+	if len(response.Metadata) == 0 {
+		return iterator, nil, nil
+	}
+	responseMetadata := &pb.QueryResponseMetadata{}
+	if err := proto.Unmarshal(response.Metadata, responseMetadata); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to unmarshal query response metadata")
+	}
+	return iterator, responseMetadata, nil
+}
+
+func createQueryMetadata(pageSize int32, bookmark string) ([]byte, error) {
+	if pageSize <= 0 {
+		return nil, errors.New("pageSize must be greater than zero")
+	}
+	return proto.Marshal(&pb.QueryMetadata{PageSize: pageSize, Bookmark: bookmark})
 }
 
 // GetStateByRange documentation can be found in interfaces.go
@@ -483,7 +540,25 @@ func (stub *ChaincodeStub) GetStateByRange(startKey, endKey string) (StateQueryI
 		return nil, err
 	}
 	collection := ""
-	return stub.handleGetStateByRange(collection, startKey, endKey)
+	iterator, _, err := stub.handleGetStateByRange(collection, startKey, endKey, nil)
+	return iterator, err
+}
+
+// GetStateByRangeWithPagination documentation can be found in interfaces.go
+func (stub *ChaincodeStub) GetStateByRangeWithPagination(startKey, endKey string, pageSize int32,
+	bookmark string) (StateQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+	if startKey == "" {
+		startKey = emptyKeySubstitute
+	}
+	if err := validateSimpleKeys(startKey, endKey); err != nil {
+		return nil, nil, err
+	}
+	metadata, err := createQueryMetadata(pageSize, bookmark)
+	if err != nil {
+		return nil, nil, err
+	}
+	collection := ""
+	return stub.handleGetStateByRange(collection, startKey, endKey, metadata)
 }
 
 // GetHistoryForKey documentation can be found in interfaces.go
@@ -566,7 +641,8 @@ func validateSimpleKeys(simpleKeys ...string) error {
 func (stub *ChaincodeStub) GetStateByPartialCompositeKey(objectType string, attributes []string) (StateQueryIteratorInterface, error) {
 	collection := ""
 	if partialCompositeKey, err := stub.CreateCompositeKey(objectType, attributes); err == nil {
-		return stub.handleGetStateByRange(collection, partialCompositeKey, partialCompositeKey+string(maxUnicodeRuneValue))
+		iterator, _, err := stub.handleGetStateByRange(collection, partialCompositeKey, partialCompositeKey+string(maxUnicodeRuneValue), nil)
+		return iterator, err
 	} else {
 		return nil, err
 	}
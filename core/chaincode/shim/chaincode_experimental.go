@@ -52,7 +52,8 @@ func (stub *ChaincodeStub) GetPrivateDataByRange(collection, startKey, endKey st
 	if err := validateSimpleKeys(startKey, endKey); err != nil {
 		return nil, err
 	}
-	return stub.handleGetStateByRange(collection, startKey, endKey)
+	iterator, _, err := stub.handleGetStateByRange(collection, startKey, endKey, nil)
+	return iterator, err
 }
 
 // GetPrivateDataByPartialCompositeKey documentation can be found in interfaces.go
@@ -61,7 +62,8 @@ func (stub *ChaincodeStub) GetPrivateDataByPartialCompositeKey(collection, objec
 		return nil, fmt.Errorf("collection must not be an empty string")
 	}
 	if partialCompositeKey, err := stub.CreateCompositeKey(objectType, attributes); err == nil {
-		return stub.handleGetStateByRange(collection, partialCompositeKey, partialCompositeKey+string(maxUnicodeRuneValue))
+		iterator, _, err := stub.handleGetStateByRange(collection, partialCompositeKey, partialCompositeKey+string(maxUnicodeRuneValue), nil)
+		return iterator, err
 	} else {
 		return nil, err
 	}
@@ -72,7 +74,7 @@ func (stub *ChaincodeStub) GetPrivateDataQueryResult(collection, query string) (
 	if collection == "" {
 		return nil, fmt.Errorf("collection must not be an empty string")
 	}
-	response, err := stub.handler.handleGetQueryResult(collection, query, stub.ChannelId, stub.TxID)
+	response, err := stub.handler.handleGetQueryResult(collection, query, nil, stub.ChannelId, stub.TxID)
 	if err != nil {
 		return nil, err
 	}
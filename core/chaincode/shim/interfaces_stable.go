@@ -99,6 +99,17 @@ type ChaincodeStubInterface interface {
 	// the ledger when the transaction is validated and successfully committed.
 	DelState(key string) error
 
+	// SetStateValidationParameter sets the key-level endorsement policy for `key`.
+	// The policy overrides the chaincode-level endorsement policy for
+	// transactions that modify the key or its metadata once the transaction
+	// is validated and successfully committed.
+	SetStateValidationParameter(key string, ep []byte) error
+
+	// GetStateValidationParameter retrieves the key-level endorsement policy
+	// for `key`. Note that this will introduce a read dependency on `key` in
+	// the transaction's readset.
+	GetStateValidationParameter(key string) ([]byte, error)
+
 	// GetStateByRange returns a range iterator over a set of keys in the
 	// ledger. The iterator can be used to iterate over all keys
 	// between the startKey (inclusive) and endKey (exclusive).
@@ -110,6 +121,22 @@ type ChaincodeStubInterface interface {
 	// has not changed since transaction endorsement (phantom reads detected).
 	GetStateByRange(startKey, endKey string) (StateQueryIteratorInterface, error)
 
+	// GetStateByRangeWithPagination returns a range iterator over a set of keys in the
+	// ledger, limited to a page of at most `pageSize` keys. When an empty string is
+	// passed as the bookmark, the iterator can be used to fetch the first `pageSize`
+	// keys between the startKey (inclusive) and endKey (exclusive). When the bookmark
+	// is a non-empty string, the iterator can be used to fetch the first `pageSize`
+	// keys between the bookmark (inclusive) and endKey (exclusive). Only the bookmark
+	// returned in the QueryResponseMetadata of a prior page can be used as the bookmark;
+	// an empty bookmark in the QueryResponseMetadata indicates that there are no more
+	// keys in the range. The keys are returned by the iterator in lexical order. Note
+	// that startKey and endKey can be empty string, which implies unbounded range
+	// query on start or end.
+	// Call Close() on the returned StateQueryIteratorInterface object when done.
+	// This call is only supported in a read-only transaction.
+	GetStateByRangeWithPagination(startKey, endKey string, pageSize int32,
+		bookmark string) (StateQueryIteratorInterface, *pb.QueryResponseMetadata, error)
+
 	// GetStateByPartialCompositeKey queries the state in the ledger based on
 	// a given partial composite key. This function returns an iterator
 	// which can be used to iterate over all composite keys whose prefix matches
@@ -148,6 +175,18 @@ type ChaincodeStubInterface interface {
 	// ledger, and should limit use to read-only chaincode operations.
 	GetQueryResult(query string) (StateQueryIteratorInterface, error)
 
+	// GetQueryResultWithPagination performs a "rich" query against a state database,
+	// like GetQueryResult, and returns an iterator over a page of at most `pageSize`
+	// results. When an empty string is passed as the bookmark, the iterator can be
+	// used to fetch the first page of results. Only the bookmark returned in the
+	// QueryResponseMetadata of a prior page can be used for fetching the next page;
+	// an empty bookmark in the QueryResponseMetadata indicates that there are no
+	// more results. It is only supported for state databases that support rich
+	// query, e.g. CouchDB.
+	// This call is only supported in a read-only transaction.
+	GetQueryResultWithPagination(query string, pageSize int32,
+		bookmark string) (StateQueryIteratorInterface, *pb.QueryResponseMetadata, error)
+
 	// GetHistoryForKey returns a history of key values across time.
 	// For each historic key update, the historic value and associated
 	// transaction id and timestamp are returned. The timestamp is the
@@ -11,12 +11,14 @@ package shim
 import (
 	"container/list"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/protos/ledger/queryresult"
 	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/op/go-logging"
 	"github.com/pkg/errors"
 )
@@ -26,6 +28,14 @@ var mockLogger = logging.MustGetLogger("mock")
 
 // MockStub is an implementation of ChaincodeStubInterface for unit testing chaincode.
 // Use this instead of ChaincodeStub in your chaincode's unit test calls to Init or Invoke.
+//
+// As on a peer, the writes of a transaction are not visible to the reads of the same
+// transaction. They are kept in the write set of the transaction and applied to the
+// state when the transaction ends, unless Init or Invoke returns a response with a
+// status greater than or equal to ERRORTHRESHOLD, in which case they are discarded.
+// Writes outside of a transaction, except PutState, are applied right away.
+// The writes of a chaincode invoked with InvokeChaincode are part of the transaction
+// of the caller, and are applied or discarded along with the writes of the caller.
 type MockStub struct {
 	// arguments the stub was called with
 	args [][]byte
@@ -60,8 +70,67 @@ type MockStub struct {
 
 	PvtState map[string]map[string][]byte
 
+	// stores per-key endorsement policy
+	EndorsementPolicies map[string][]byte
+
 	// channel to store ChaincodeEvents
 	ChaincodeEventsChannel chan *pb.ChaincodeEvent
+
+	// Creator is returned by GetCreator, e.g. a marshaled msp.SerializedIdentity.
+	// Transactions invoked with a signed proposal use the creator of the proposal instead.
+	Creator []byte
+
+	// TransientMap is returned by GetTransient. Transactions invoked with a signed
+	// proposal use the transient map of the proposal instead.
+	TransientMap map[string][]byte
+
+	// timestamp of the transactions, set by SetTxTimestamp. The current time is used if nil
+	fixedTxTimestamp *timestamp.Timestamp
+
+	// creator, transient map and binding of the signed proposal of the transaction in progress
+	proposalContext *mockProposalContext
+
+	// writes of the transaction in progress
+	txWrites *mockTxWrites
+
+	// committed modifications of the keys in State, in commit order
+	history map[string][]*queryresult.KeyModification
+}
+
+// mockProposalContext holds the fields of a signed proposal exposed to the chaincode
+type mockProposalContext struct {
+	creator   []byte
+	transient map[string][]byte
+	binding   []byte
+}
+
+// mockWrite is a write of a transaction to a key
+type mockWrite struct {
+	value    []byte
+	isDelete bool
+}
+
+// mockTxWrites is the write set of a transaction
+type mockTxWrites struct {
+	state               map[string]*mockWrite
+	pvtState            map[string]map[string]*mockWrite
+	endorsementPolicies map[string][]byte
+	// write sets of the chaincodes invoked by the transaction, in invocation order
+	invoked []*mockInvokedWrites
+}
+
+// mockInvokedWrites is the write set of a chaincode invoked by a transaction
+type mockInvokedWrites struct {
+	stub   *MockStub
+	writes *mockTxWrites
+}
+
+func newMockTxWrites() *mockTxWrites {
+	return &mockTxWrites{
+		state:               make(map[string]*mockWrite),
+		pvtState:            make(map[string]map[string]*mockWrite),
+		endorsementPolicies: make(map[string][]byte),
+	}
 }
 
 func (stub *MockStub) GetTxID() string {
@@ -102,15 +171,39 @@ func (stub *MockStub) GetFunctionAndParameters() (function string, params []stri
 func (stub *MockStub) MockTransactionStart(txid string) {
 	stub.TxID = txid
 	stub.setSignedProposal(&pb.SignedProposal{})
-	stub.setTxTimestamp(util.CreateUtcTimestamp())
+	if stub.fixedTxTimestamp != nil {
+		stub.TxTimestamp = stub.fixedTxTimestamp
+	} else {
+		stub.TxTimestamp = util.CreateUtcTimestamp()
+	}
+	stub.proposalContext = nil
+	stub.txWrites = newMockTxWrites()
 }
 
-// End a mocked transaction, clearing the UUID.
+// End a mocked transaction, clearing the UUID. The writes of the transaction are
+// applied to the state.
 func (stub *MockStub) MockTransactionEnd(uuid string) {
+	stub.commitTxWrites()
 	stub.signedProposal = nil
+	stub.proposalContext = nil
 	stub.TxID = ""
 }
 
+// MockTransactionRollback discards the writes of the transaction in progress.
+// MockInit and MockInvoke call it when the chaincode returns an error response.
+func (stub *MockStub) MockTransactionRollback() {
+	if stub.txWrites != nil {
+		mockLogger.Debug("MockStub", stub.Name, "Discarding the writes of transaction", stub.TxID)
+		stub.txWrites = newMockTxWrites()
+	}
+}
+
+// SetTxTimestamp sets the timestamp of the transactions started afterwards.
+// A nil timestamp restores the use of the current time.
+func (stub *MockStub) SetTxTimestamp(ts *timestamp.Timestamp) {
+	stub.fixedTxTimestamp = ts
+}
+
 // Register a peer chaincode with this MockStub
 // invokableChaincodeName is the name or hash of the peer
 // otherStub is a MockStub of the peer, already intialised
@@ -123,7 +216,7 @@ func (stub *MockStub) MockInit(uuid string, args [][]byte) pb.Response {
 	stub.args = args
 	stub.MockTransactionStart(uuid)
 	res := stub.cc.Init(stub)
-	stub.MockTransactionEnd(uuid)
+	stub.endTransaction(uuid, res)
 	return res
 }
 
@@ -132,25 +225,77 @@ func (stub *MockStub) MockInvoke(uuid string, args [][]byte) pb.Response {
 	stub.args = args
 	stub.MockTransactionStart(uuid)
 	res := stub.cc.Invoke(stub)
-	stub.MockTransactionEnd(uuid)
+	stub.endTransaction(uuid, res)
 	return res
 }
 
+// endTransaction ends the transaction, discarding its writes if the response is an error
+func (stub *MockStub) endTransaction(uuid string, res pb.Response) {
+	if res.Status >= ERRORTHRESHOLD {
+		stub.MockTransactionRollback()
+	}
+	stub.MockTransactionEnd(uuid)
+}
+
 func (stub *MockStub) GetDecorations() map[string][]byte {
 	return nil
 }
 
 // Invoke this chaincode, also starts and ends a transaction.
+// The creator, transient map, binding and timestamp of the transaction are taken
+// from the proposal carried by sp, if any.
 func (stub *MockStub) MockInvokeWithSignedProposal(uuid string, args [][]byte, sp *pb.SignedProposal) pb.Response {
 	stub.args = args
 	stub.MockTransactionStart(uuid)
 	stub.signedProposal = sp
+	if err := stub.setProposalContext(sp); err != nil {
+		stub.MockTransactionRollback()
+		stub.MockTransactionEnd(uuid)
+		return Error(err.Error())
+	}
 	res := stub.cc.Invoke(stub)
-	stub.MockTransactionEnd(uuid)
+	stub.endTransaction(uuid, res)
 	return res
 }
 
+// setProposalContext extracts the fields exposed to the chaincode from the proposal carried by sp
+func (stub *MockStub) setProposalContext(sp *pb.SignedProposal) error {
+	if sp == nil || len(sp.ProposalBytes) == 0 {
+		return nil
+	}
+	proposal, err := utils.GetProposal(sp.ProposalBytes)
+	if err != nil {
+		return errors.WithMessage(err, "failed extracting proposal from signed proposal")
+	}
+	creator, transient, err := utils.GetChaincodeProposalContext(proposal)
+	if err != nil {
+		return errors.WithMessage(err, "failed extracting signed proposal fields")
+	}
+	binding, err := utils.ComputeProposalBinding(proposal)
+	if err != nil {
+		return errors.WithMessage(err, "failed computing binding from signed proposal")
+	}
+	hdr, err := utils.GetHeader(proposal.Header)
+	if err != nil {
+		return err
+	}
+	chdr, err := utils.UnmarshalChannelHeader(hdr.ChannelHeader)
+	if err != nil {
+		return err
+	}
+	if chdr.Timestamp != nil {
+		stub.TxTimestamp = chdr.Timestamp
+	}
+	stub.proposalContext = &mockProposalContext{creator: creator, transient: transient, binding: binding}
+	return nil
+}
+
+// GetPrivateData retrieves the value for a given key from the committed state
+// of a collection
 func (stub *MockStub) GetPrivateData(collection string, key string) ([]byte, error) {
+	if collection == "" {
+		return nil, errors.New("collection must not be an empty string")
+	}
 	m, in := stub.PvtState[collection]
 
 	if !in {
@@ -160,35 +305,66 @@ func (stub *MockStub) GetPrivateData(collection string, key string) ([]byte, err
 	return m[key], nil
 }
 
+// PutPrivateData writes the specified `value` and `key` into a collection
 func (stub *MockStub) PutPrivateData(collection string, key string, value []byte) error {
-	m, in := stub.PvtState[collection]
-	if !in {
-		stub.PvtState[collection] = make(map[string][]byte)
-		m, in = stub.PvtState[collection]
+	if collection == "" {
+		return errors.New("collection must not be an empty string")
 	}
-
-	m[key] = value
-
+	if key == "" {
+		return errors.New("key must not be an empty string")
+	}
+	stub.writePvtState(collection, key, &mockWrite{value: value})
 	return nil
 }
 
+// DelPrivateData removes the specified `key` and its value from a collection
 func (stub *MockStub) DelPrivateData(collection string, key string) error {
-	return errors.New("Not Implemented")
+	if collection == "" {
+		return errors.New("collection must not be an empty string")
+	}
+	stub.writePvtState(collection, key, &mockWrite{isDelete: true})
+	return nil
 }
 
+// GetPrivateDataByRange returns an iterator over the keys of a collection from
+// startKey (inclusive) to endKey (exclusive)
 func (stub *MockStub) GetPrivateDataByRange(collection, startKey, endKey string) (StateQueryIteratorInterface, error) {
-	return nil, errors.New("Not Implemented")
+	if collection == "" {
+		return nil, errors.New("collection must not be an empty string")
+	}
+	if startKey == "" {
+		startKey = emptyKeySubstitute
+	}
+	if err := validateSimpleKeys(startKey, endKey); err != nil {
+		return nil, err
+	}
+	return newMockStateQueryIterator(rangeOfState(stub.PvtState[collection], startKey, endKey)), nil
 }
 
+// GetPrivateDataByPartialCompositeKey returns an iterator over the keys of a
+// collection that have the given partial composite key as prefix
 func (stub *MockStub) GetPrivateDataByPartialCompositeKey(collection, objectType string, attributes []string) (StateQueryIteratorInterface, error) {
-	return nil, errors.New("Not Implemented")
+	if collection == "" {
+		return nil, errors.New("collection must not be an empty string")
+	}
+	partialCompositeKey, err := stub.CreateCompositeKey(objectType, attributes)
+	if err != nil {
+		return nil, err
+	}
+	return newMockStateQueryIterator(rangeOfState(stub.PvtState[collection], partialCompositeKey, partialCompositeKey+string(maxUnicodeRuneValue))), nil
 }
 
+// GetPrivateDataQueryResult performs a rich query against a collection. The query is
+// a CouchDB selector query, see GetQueryResult for the supported subset
 func (stub *MockStub) GetPrivateDataQueryResult(collection, query string) (StateQueryIteratorInterface, error) {
-	// Not implemented since the mock engine does not have a query engine.
-	// However, a very simple query engine that supports string matching
-	// could be implemented to test that the framework supports queries
-	return nil, errors.New("Not Implemented")
+	if collection == "" {
+		return nil, errors.New("collection must not be an empty string")
+	}
+	results, err := executeMockQuery(stub.PvtState[collection], query)
+	if err != nil {
+		return nil, err
+	}
+	return newMockStateQueryIterator(results), nil
 }
 
 // GetState retrieves the value for a given key from the ledger
@@ -205,8 +381,17 @@ func (stub *MockStub) PutState(key string, value []byte) error {
 		mockLogger.Errorf("%+v", err)
 		return err
 	}
+	if key == "" {
+		return errors.New("key must not be an empty string")
+	}
 
 	mockLogger.Debug("MockStub", stub.Name, "Putting", key, value)
+	stub.writeState(key, &mockWrite{value: value})
+	return nil
+}
+
+// putState applies a write of `key` to the state
+func (stub *MockStub) putState(key string, value []byte) {
 	stub.State[key] = value
 
 	// insert key into ordered list of keys
@@ -238,29 +423,140 @@ func (stub *MockStub) PutState(key string, value []byte) error {
 		stub.Keys.PushFront(key)
 		mockLogger.Debug("MockStub", stub.Name, "Key", key, "is first element in list")
 	}
+}
 
+// SetStateValidationParameter sets the key-level endorsement policy for `key`.
+func (stub *MockStub) SetStateValidationParameter(key string, ep []byte) error {
+	if stub.txWrites == nil {
+		stub.EndorsementPolicies[key] = ep
+		return nil
+	}
+	stub.txWrites.endorsementPolicies[key] = ep
 	return nil
 }
 
+// GetStateValidationParameter retrieves the key-level endorsement policy for `key`.
+func (stub *MockStub) GetStateValidationParameter(key string) ([]byte, error) {
+	return stub.EndorsementPolicies[key], nil
+}
+
 // DelState removes the specified `key` and its value from the ledger.
 func (stub *MockStub) DelState(key string) error {
 	mockLogger.Debug("MockStub", stub.Name, "Deleting", key, stub.State[key])
+	stub.writeState(key, &mockWrite{isDelete: true})
+	return nil
+}
+
+// delState applies a delete of `key` to the state
+func (stub *MockStub) delState(key string) {
 	delete(stub.State, key)
 
 	for elem := stub.Keys.Front(); elem != nil; elem = elem.Next() {
 		if strings.Compare(key, elem.Value.(string)) == 0 {
 			stub.Keys.Remove(elem)
+			break
 		}
 	}
+}
 
-	return nil
+// writeState adds a write of the public state to the write set of the transaction
+// in progress, or applies it right away outside of a transaction
+func (stub *MockStub) writeState(key string, write *mockWrite) {
+	if stub.txWrites == nil {
+		stub.applyStateWrite(key, write)
+		return
+	}
+	stub.txWrites.state[key] = write
+}
+
+// writePvtState adds a write of a collection to the write set of the transaction
+// in progress, or applies it right away outside of a transaction
+func (stub *MockStub) writePvtState(collection, key string, write *mockWrite) {
+	if stub.txWrites == nil {
+		stub.applyPvtStateWrite(collection, key, write)
+		return
+	}
+	if _, ok := stub.txWrites.pvtState[collection]; !ok {
+		stub.txWrites.pvtState[collection] = make(map[string]*mockWrite)
+	}
+	stub.txWrites.pvtState[collection][key] = write
+}
+
+func (stub *MockStub) applyStateWrite(key string, write *mockWrite) {
+	if write.isDelete {
+		stub.delState(key)
+		return
+	}
+	stub.putState(key, write.value)
+}
+
+func (stub *MockStub) applyPvtStateWrite(collection, key string, write *mockWrite) {
+	if write.isDelete {
+		delete(stub.PvtState[collection], key)
+		return
+	}
+	if _, ok := stub.PvtState[collection]; !ok {
+		stub.PvtState[collection] = make(map[string][]byte)
+	}
+	stub.PvtState[collection][key] = write.value
+}
+
+// commitTxWrites applies the write set of the transaction in progress to the state
+// and records the modifications of the public state in the history
+func (stub *MockStub) commitTxWrites() {
+	if stub.txWrites == nil {
+		return
+	}
+	writes := stub.txWrites
+	stub.txWrites = nil
+	stub.applyTxWrites(writes, stub.TxID, stub.TxTimestamp)
+}
+
+// applyTxWrites applies the write set of the given transaction to the state, as well
+// as the write sets of the chaincodes it invoked to their own states
+func (stub *MockStub) applyTxWrites(writes *mockTxWrites, txID string, txTimestamp *timestamp.Timestamp) {
+	for _, key := range sortedWriteKeys(writes.state) {
+		write := writes.state[key]
+		stub.applyStateWrite(key, write)
+		stub.history[key] = append(stub.history[key], &queryresult.KeyModification{
+			TxId:      txID,
+			Value:     write.value,
+			Timestamp: txTimestamp,
+			IsDelete:  write.isDelete,
+		})
+	}
+	for collection, collWrites := range writes.pvtState {
+		for _, key := range sortedWriteKeys(collWrites) {
+			stub.applyPvtStateWrite(collection, key, collWrites[key])
+		}
+	}
+	for key, ep := range writes.endorsementPolicies {
+		stub.EndorsementPolicies[key] = ep
+	}
+	for _, invoked := range writes.invoked {
+		invoked.stub.applyTxWrites(invoked.writes, txID, txTimestamp)
+	}
+}
+
+func sortedWriteKeys(writes map[string]*mockWrite) []string {
+	keys := make([]string, 0, len(writes))
+	for key := range writes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
 }
 
+// GetStateByRange returns an iterator over the keys of the state from
+// startKey (inclusive) to endKey (exclusive)
 func (stub *MockStub) GetStateByRange(startKey, endKey string) (StateQueryIteratorInterface, error) {
+	if startKey == "" {
+		startKey = emptyKeySubstitute
+	}
 	if err := validateSimpleKeys(startKey, endKey); err != nil {
 		return nil, err
 	}
-	return NewMockStateRangeQueryIterator(stub, startKey, endKey), nil
+	return newMockStateQueryIterator(rangeOfState(stub.State, startKey, endKey)), nil
 }
 
 // GetQueryResult function can be invoked by a chaincode to perform a
@@ -268,17 +564,59 @@ func (stub *MockStub) GetStateByRange(startKey, endKey string) (StateQueryIterat
 // that support rich query.  The query string is in the syntax of the underlying
 // state database. An iterator is returned which can be used to iterate (next) over
 // the query result set
+//
+// The mock stub evaluates CouchDB selector queries. The selector supports the
+// combination operators $and, $or, $nor and $not and the condition operators
+// $eq, $ne, $lt, $lte, $gt, $gte, $exists, $type, $in, $nin, $size, $mod, $regex,
+// $all and $elemMatch. The sort and fields options are applied, while limit, skip
+// and bookmark are ignored, as they are by the peer. Strings are compared by their
+// bytes rather than with the ICU collation of CouchDB.
 func (stub *MockStub) GetQueryResult(query string) (StateQueryIteratorInterface, error) {
-	// Not implemented since the mock engine does not have a query engine.
-	// However, a very simple query engine that supports string matching
-	// could be implemented to test that the framework supports queries
-	return nil, errors.New("not implemented")
+	results, err := executeMockQuery(stub.State, query)
+	if err != nil {
+		return nil, err
+	}
+	return newMockStateQueryIterator(results), nil
+}
+
+// GetStateByRangeWithPagination returns an iterator over a page of at most pageSize
+// keys of the state from startKey (inclusive) to endKey (exclusive). The page starts
+// at the bookmark, which is the key returned in the metadata of the previous page,
+// or at startKey if the bookmark is empty
+func (stub *MockStub) GetStateByRangeWithPagination(startKey, endKey string, pageSize int32,
+	bookmark string) (StateQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+	if startKey == "" {
+		startKey = emptyKeySubstitute
+	}
+	if err := validateSimpleKeys(startKey, endKey); err != nil {
+		return nil, nil, err
+	}
+	if bookmark != "" && bookmark > startKey {
+		startKey = bookmark
+	}
+	return paginateResults(rangeOfState(stub.State, startKey, endKey), pageSize, "")
+}
+
+// GetQueryResultWithPagination performs a rich query against the state, see GetQueryResult,
+// and returns an iterator over a page of at most pageSize results. The page starts at the
+// bookmark, which is the key returned in the metadata of the previous page, or at the first
+// result if the bookmark is empty
+func (stub *MockStub) GetQueryResultWithPagination(query string, pageSize int32,
+	bookmark string) (StateQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+	results, err := executeMockQuery(stub.State, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	return paginateResults(results, pageSize, bookmark)
 }
 
 // GetHistoryForKey function can be invoked by a chaincode to return a history of
 // key values across time. GetHistoryForKey is intended to be used for read-only queries.
+// The mock stub returns the modifications committed by its transactions, oldest first.
 func (stub *MockStub) GetHistoryForKey(key string) (HistoryQueryIteratorInterface, error) {
-	return nil, errors.New("not implemented")
+	history := make([]*queryresult.KeyModification, len(stub.history[key]))
+	copy(history, stub.history[key])
+	return &mockHistoryQueryIterator{results: history}, nil
 }
 
 //GetStateByPartialCompositeKey function can be invoked by a chaincode to query the
@@ -292,7 +630,7 @@ func (stub *MockStub) GetStateByPartialCompositeKey(objectType string, attribute
 	if err != nil {
 		return nil, err
 	}
-	return NewMockStateRangeQueryIterator(stub, partialCompositeKey, partialCompositeKey+string(maxUnicodeRuneValue)), nil
+	return newMockStateQueryIterator(rangeOfState(stub.State, partialCompositeKey, partialCompositeKey+string(maxUnicodeRuneValue))), nil
 }
 
 // CreateCompositeKey combines the list of attributes
@@ -311,36 +649,85 @@ func (stub *MockStub) SplitCompositeKey(compositeKey string) (string, []string,
 // E.g. stub1.InvokeChaincode("stub2Hash", funcArgs, channel)
 // Before calling this make sure to create another MockStub stub2, call stub2.MockInit(uuid, func, args)
 // and register it with stub1 by calling stub1.MockPeerChaincode("stub2Hash", stub2)
+// The writes of the peered chaincode are committed or discarded along with the
+// transaction of this stub, they are only applied right away outside of a transaction.
 func (stub *MockStub) InvokeChaincode(chaincodeName string, args [][]byte, channel string) pb.Response {
 	// Internally we use chaincode name as a composite name
 	if channel != "" {
 		chaincodeName = chaincodeName + "/" + channel
 	}
 	// TODO "args" here should possibly be a serialized pb.ChaincodeInput
-	otherStub := stub.Invokables[chaincodeName]
+	otherStub, ok := stub.Invokables[chaincodeName]
+	if !ok {
+		return Error(fmt.Sprintf("chaincode %s is not registered with MockPeerChaincode", chaincodeName))
+	}
 	mockLogger.Debug("MockStub", stub.Name, "Invoking peer chaincode", otherStub.Name, args)
 	//	function, strings := getFuncArgs(args)
-	res := otherStub.MockInvoke(stub.TxID, args)
+	if stub.txWrites == nil {
+		res := otherStub.MockInvoke(stub.TxID, args)
+		mockLogger.Debug("MockStub", stub.Name, "Invoked peer chaincode", otherStub.Name, "got", fmt.Sprintf("%+v", res))
+		return res
+	}
+	res, writes := otherStub.invokeInTransaction(stub, args)
+	if writes != nil {
+		stub.txWrites.invoked = append(stub.txWrites.invoked, &mockInvokedWrites{stub: otherStub, writes: writes})
+	}
 	mockLogger.Debug("MockStub", stub.Name, "Invoked peer chaincode", otherStub.Name, "got", fmt.Sprintf("%+v", res))
 	return res
 }
 
-// Not implemented
+// invokeInTransaction invokes the chaincode as part of the transaction of the caller.
+// Instead of being applied, the writes of the chaincode are returned for the caller to
+// commit or discard along with its own, or nil if the chaincode returned an error.
+func (stub *MockStub) invokeInTransaction(caller *MockStub, args [][]byte) (pb.Response, *mockTxWrites) {
+	stub.args = args
+	stub.TxID = caller.TxID
+	stub.TxTimestamp = caller.TxTimestamp
+	stub.signedProposal = caller.signedProposal
+	stub.proposalContext = caller.proposalContext
+	stub.txWrites = newMockTxWrites()
+
+	res := stub.cc.Invoke(stub)
+
+	writes := stub.txWrites
+	stub.txWrites = nil
+	stub.signedProposal = nil
+	stub.proposalContext = nil
+	stub.TxID = ""
+	if res.Status >= ERRORTHRESHOLD {
+		return res, nil
+	}
+	return res, writes
+}
+
+// GetCreator returns the creator of the signed proposal of the transaction, or
+// Creator if the transaction was not invoked with a signed proposal
 func (stub *MockStub) GetCreator() ([]byte, error) {
-	return nil, nil
+	if stub.proposalContext != nil {
+		return stub.proposalContext.creator, nil
+	}
+	return stub.Creator, nil
 }
 
-// Not implemented
+// GetTransient returns the transient map of the signed proposal of the transaction,
+// or TransientMap if the transaction was not invoked with a signed proposal
 func (stub *MockStub) GetTransient() (map[string][]byte, error) {
-	return nil, nil
+	if stub.proposalContext != nil {
+		return stub.proposalContext.transient, nil
+	}
+	return stub.TransientMap, nil
 }
 
-// Not implemented
+// GetBinding returns the binding of the signed proposal of the transaction, or nil
+// if the transaction was not invoked with a signed proposal
 func (stub *MockStub) GetBinding() ([]byte, error) {
+	if stub.proposalContext != nil {
+		return stub.proposalContext.binding, nil
+	}
 	return nil, nil
 }
 
-// Not implemented
+// GetSignedProposal returns the signed proposal the transaction was invoked with
 func (stub *MockStub) GetSignedProposal() (*pb.SignedProposal, error) {
 	return stub.signedProposal, nil
 }
@@ -349,13 +736,14 @@ func (stub *MockStub) setSignedProposal(sp *pb.SignedProposal) {
 	stub.signedProposal = sp
 }
 
-// Not implemented
+// GetArgsSlice returns the arguments of the transaction concatenated
 func (stub *MockStub) GetArgsSlice() ([]byte, error) {
-	return nil, nil
-}
-
-func (stub *MockStub) setTxTimestamp(time *timestamp.Timestamp) {
-	stub.TxTimestamp = time
+	args := stub.GetArgs()
+	res := []byte{}
+	for _, barg := range args {
+		res = append(res, barg...)
+	}
+	return res, nil
 }
 
 func (stub *MockStub) GetTxTimestamp() (*timestamp.Timestamp, error) {
@@ -378,8 +766,10 @@ func NewMockStub(name string, cc Chaincode) *MockStub {
 	s.cc = cc
 	s.State = make(map[string][]byte)
 	s.PvtState = make(map[string]map[string][]byte)
+	s.EndorsementPolicies = make(map[string][]byte)
 	s.Invokables = make(map[string]*MockStub)
 	s.Keys = list.New()
+	s.history = make(map[string][]*queryresult.KeyModification)
 	s.ChaincodeEventsChannel = make(chan *pb.ChaincodeEvent, 100) //define large capacity for non-blocking setEvent calls.
 
 	return s
@@ -507,6 +897,126 @@ func NewMockStateRangeQueryIterator(stub *MockStub, startKey string, endKey stri
 	return iter
 }
 
+/*****************************
+ Query Result Iterators
+*****************************/
+
+// mockStateQueryIterator iterates over a snapshot of the results of a query
+type mockStateQueryIterator struct {
+	results []*queryresult.KV
+	current int
+	closed  bool
+}
+
+func newMockStateQueryIterator(results []*queryresult.KV) *mockStateQueryIterator {
+	return &mockStateQueryIterator{results: results}
+}
+
+// HasNext returns true if the iterator contains additional results
+func (iter *mockStateQueryIterator) HasNext() bool {
+	return !iter.closed && iter.current < len(iter.results)
+}
+
+// Next returns the next key and value of the iterator
+func (iter *mockStateQueryIterator) Next() (*queryresult.KV, error) {
+	if iter.closed {
+		return nil, errors.New("Next() called after Close()")
+	}
+	if !iter.HasNext() {
+		return nil, errors.New("Next() called when it does not HaveNext()")
+	}
+	result := iter.results[iter.current]
+	iter.current++
+	return result, nil
+}
+
+// Close closes the iterator
+func (iter *mockStateQueryIterator) Close() error {
+	iter.closed = true
+	return nil
+}
+
+// mockHistoryQueryIterator iterates over a snapshot of the history of a key
+type mockHistoryQueryIterator struct {
+	results []*queryresult.KeyModification
+	current int
+	closed  bool
+}
+
+// HasNext returns true if the iterator contains additional modifications
+func (iter *mockHistoryQueryIterator) HasNext() bool {
+	return !iter.closed && iter.current < len(iter.results)
+}
+
+// Next returns the next modification of the key
+func (iter *mockHistoryQueryIterator) Next() (*queryresult.KeyModification, error) {
+	if iter.closed {
+		return nil, errors.New("Next() called after Close()")
+	}
+	if !iter.HasNext() {
+		return nil, errors.New("Next() called when it does not HaveNext()")
+	}
+	result := iter.results[iter.current]
+	iter.current++
+	return result, nil
+}
+
+// Close closes the iterator
+func (iter *mockHistoryQueryIterator) Close() error {
+	iter.closed = true
+	return nil
+}
+
+// rangeOfState returns the keys and values of state from startKey (inclusive) to
+// endKey (exclusive), sorted by key. An empty endKey denotes the end of the state
+func rangeOfState(state map[string][]byte, startKey, endKey string) []*queryresult.KV {
+	var keys []string
+	for key := range state {
+		if key >= startKey && (endKey == "" || key < endKey) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	results := make([]*queryresult.KV, 0, len(keys))
+	for _, key := range keys {
+		results = append(results, &queryresult.KV{Key: key, Value: state[key]})
+	}
+	return results
+}
+
+// paginateResults returns an iterator over the page of at most pageSize results starting
+// at the result with the bookmark as key, or at the first result if the bookmark is empty.
+// The bookmark of the returned metadata is the key of the result following the page, or
+// empty if there is none
+func paginateResults(results []*queryresult.KV, pageSize int32, bookmark string) (StateQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+	if pageSize <= 0 {
+		return nil, nil, errors.New("pageSize must be greater than zero")
+	}
+	start := 0
+	if bookmark != "" {
+		start = -1
+		for i, result := range results {
+			if result.Key == bookmark {
+				start = i
+				break
+			}
+		}
+		if start < 0 {
+			return nil, nil, errors.Errorf("invalid bookmark %s", bookmark)
+		}
+	}
+	end := start + int(pageSize)
+	nextBookmark := ""
+	if end < len(results) {
+		nextBookmark = results[end].Key
+	} else {
+		end = len(results)
+	}
+	page := results[start:end]
+	metadata := &pb.QueryResponseMetadata{FetchedRecordsCount: int32(len(page)), Bookmark: nextBookmark}
+	return newMockStateQueryIterator(page), metadata, nil
+}
+
 func getBytes(function string, args []string) [][]byte {
 	bytes := make([][]byte, 0, len(args)+1)
 	bytes = append(bytes, []byte(function))
@@ -0,0 +1,547 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shim
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/fabric/protos/ledger/queryresult"
+	"github.com/pkg/errors"
+)
+
+// mockQuery is a CouchDB query as evaluated by the mock stub
+type mockQuery struct {
+	selector map[string]interface{}
+	sort     []mockSortField
+	fields   []string
+}
+
+type mockSortField struct {
+	field      string
+	descending bool
+}
+
+// executeMockQuery evaluates a CouchDB query against the given state and returns the
+// matching keys and values, sorted by key unless the query specifies a sort order.
+// Values that are not JSON objects are stored as attachments by CouchDB and never match.
+func executeMockQuery(state map[string][]byte, queryString string) ([]*queryresult.KV, error) {
+	query, err := parseMockQuery(queryString)
+	if err != nil {
+		return nil, err
+	}
+
+	type match struct {
+		key string
+		doc map[string]interface{}
+	}
+	var matches []*match
+	for key, value := range state {
+		doc, ok := decodeJSONObject(value)
+		if !ok {
+			continue
+		}
+		// the key of a document is its _id
+		doc["_id"] = key
+		matched, err := matchSelector(query.selector, doc)
+		if err != nil {
+			return nil, err
+		}
+		if matched {
+			matches = append(matches, &match{key: key, doc: doc})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		for _, sortField := range query.sort {
+			v1, _ := lookupField(matches[i].doc, sortField.field)
+			v2, _ := lookupField(matches[j].doc, sortField.field)
+			c := collate(v1, v2)
+			if c == 0 {
+				continue
+			}
+			if sortField.descending {
+				return c > 0
+			}
+			return c < 0
+		}
+		return matches[i].key < matches[j].key
+	})
+
+	results := make([]*queryresult.KV, 0, len(matches))
+	for _, m := range matches {
+		delete(m.doc, "_id")
+		doc := m.doc
+		if len(query.fields) > 0 {
+			doc = projectFields(doc, query.fields)
+		}
+		// as the peer, return the document re-encoded rather than the stored bytes
+		value, err := json.Marshal(doc)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error encoding the value of key %s", m.key)
+		}
+		results = append(results, &queryresult.KV{Key: m.key, Value: value})
+	}
+	return results, nil
+}
+
+func parseMockQuery(queryString string) (*mockQuery, error) {
+	var raw map[string]interface{}
+	decoder := json.NewDecoder(strings.NewReader(queryString))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, errors.Wrap(err, "the query is not a valid JSON object")
+	}
+
+	query := &mockQuery{}
+	selector, ok := raw["selector"].(map[string]interface{})
+	if !ok {
+		return nil, errors.New("the query must contain a selector object")
+	}
+	query.selector = selector
+
+	if rawSort, ok := raw["sort"]; ok {
+		sortFields, ok := rawSort.([]interface{})
+		if !ok {
+			return nil, errors.New("sort must be an array")
+		}
+		for _, rawField := range sortFields {
+			switch field := rawField.(type) {
+			case string:
+				query.sort = append(query.sort, mockSortField{field: field})
+			case map[string]interface{}:
+				if len(field) != 1 {
+					return nil, errors.New("each sort object must contain a single field")
+				}
+				for name, direction := range field {
+					switch direction {
+					case "asc":
+						query.sort = append(query.sort, mockSortField{field: name})
+					case "desc":
+						query.sort = append(query.sort, mockSortField{field: name, descending: true})
+					default:
+						return nil, errors.Errorf("invalid sort direction %v for field %s", direction, name)
+					}
+				}
+			default:
+				return nil, errors.New("sort must contain field names or objects")
+			}
+		}
+	}
+
+	if rawFields, ok := raw["fields"]; ok {
+		fields, ok := rawFields.([]interface{})
+		if !ok {
+			return nil, errors.New("fields must be an array")
+		}
+		for _, rawField := range fields {
+			field, ok := rawField.(string)
+			if !ok {
+				return nil, errors.New("fields must contain field names")
+			}
+			query.fields = append(query.fields, field)
+		}
+	}
+
+	return query, nil
+}
+
+func decodeJSONObject(value []byte) (map[string]interface{}, bool) {
+	var doc map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(value))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil || doc == nil {
+		return nil, false
+	}
+	return doc, true
+}
+
+// matchSelector returns whether doc matches all the conditions of the selector
+func matchSelector(selector map[string]interface{}, doc map[string]interface{}) (bool, error) {
+	for field, condition := range selector {
+		var matched bool
+		var err error
+		switch field {
+		case "$and", "$or", "$nor":
+			matched, err = matchCombination(field, condition, doc)
+		case "$not":
+			subSelector, ok := condition.(map[string]interface{})
+			if !ok {
+				return false, errors.New("$not requires a selector object")
+			}
+			matched, err = matchSelector(subSelector, doc)
+			matched = !matched
+		default:
+			if strings.HasPrefix(field, "$") {
+				return false, errors.Errorf("operator %s is not supported by the mock stub", field)
+			}
+			value, exists := lookupField(doc, field)
+			matched, err = matchCondition(value, exists, condition)
+		}
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func matchCombination(operator string, condition interface{}, doc map[string]interface{}) (bool, error) {
+	subSelectors, ok := condition.([]interface{})
+	if !ok {
+		return false, errors.Errorf("%s requires an array of selectors", operator)
+	}
+	matchCount := 0
+	for _, rawSubSelector := range subSelectors {
+		subSelector, ok := rawSubSelector.(map[string]interface{})
+		if !ok {
+			return false, errors.Errorf("%s requires an array of selectors", operator)
+		}
+		matched, err := matchSelector(subSelector, doc)
+		if err != nil {
+			return false, err
+		}
+		if matched {
+			matchCount++
+		}
+	}
+	switch operator {
+	case "$and":
+		return matchCount == len(subSelectors), nil
+	case "$or":
+		return matchCount > 0, nil
+	default:
+		return matchCount == 0, nil
+	}
+}
+
+// matchCondition returns whether the value of a field satisfies a condition. A condition is
+// either an object of operators, a selector of the fields of an object value, or a value that
+// the field must be equal to
+func matchCondition(value interface{}, exists bool, condition interface{}) (bool, error) {
+	operators, ok := condition.(map[string]interface{})
+	if !ok {
+		return exists && collate(value, condition) == 0, nil
+	}
+	if !isOperatorObject(operators) {
+		object, ok := value.(map[string]interface{})
+		if !exists || !ok {
+			return false, nil
+		}
+		return matchSelector(operators, object)
+	}
+
+	for operator, argument := range operators {
+		matched, err := matchOperator(operator, argument, value, exists)
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func isOperatorObject(object map[string]interface{}) bool {
+	if len(object) == 0 {
+		return false
+	}
+	for key := range object {
+		if !strings.HasPrefix(key, "$") {
+			return false
+		}
+	}
+	return true
+}
+
+func matchOperator(operator string, argument, value interface{}, exists bool) (bool, error) {
+	switch operator {
+	case "$exists":
+		expected, ok := argument.(bool)
+		if !ok {
+			return false, errors.New("$exists requires a boolean")
+		}
+		return exists == expected, nil
+	case "$not":
+		matched, err := matchCondition(value, exists, argument)
+		return !matched, err
+	}
+
+	// all the other operators require the field to exist
+	if !exists {
+		return false, nil
+	}
+
+	switch operator {
+	case "$eq":
+		return collate(value, argument) == 0, nil
+	case "$ne":
+		return collate(value, argument) != 0, nil
+	case "$lt":
+		return collate(value, argument) < 0, nil
+	case "$lte":
+		return collate(value, argument) <= 0, nil
+	case "$gt":
+		return collate(value, argument) > 0, nil
+	case "$gte":
+		return collate(value, argument) >= 0, nil
+	case "$type":
+		expected, ok := argument.(string)
+		if !ok {
+			return false, errors.New("$type requires a string")
+		}
+		return jsonTypeName(value) == expected, nil
+	case "$in", "$nin":
+		candidates, ok := argument.([]interface{})
+		if !ok {
+			return false, errors.Errorf("%s requires an array", operator)
+		}
+		found := containsAny(value, candidates)
+		if operator == "$in" {
+			return found, nil
+		}
+		return !found, nil
+	case "$size":
+		array, ok := value.([]interface{})
+		if !ok {
+			return false, nil
+		}
+		size, ok := toFloat(argument)
+		if !ok {
+			return false, errors.New("$size requires a number")
+		}
+		return float64(len(array)) == size, nil
+	case "$mod":
+		args, ok := argument.([]interface{})
+		if !ok || len(args) != 2 {
+			return false, errors.New("$mod requires an array of a divisor and a remainder")
+		}
+		divisor, ok1 := toFloat(args[0])
+		remainder, ok2 := toFloat(args[1])
+		if !ok1 || !ok2 || divisor == 0 {
+			return false, errors.New("$mod requires an array of a non-zero divisor and a remainder")
+		}
+		number, ok := toFloat(value)
+		if !ok || number != math.Trunc(number) {
+			return false, nil
+		}
+		return math.Mod(number, divisor) == remainder, nil
+	case "$regex":
+		pattern, ok := argument.(string)
+		if !ok {
+			return false, errors.New("$regex requires a string")
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return false, errors.Wrapf(err, "invalid $regex %s", pattern)
+		}
+		str, ok := value.(string)
+		return ok && re.MatchString(str), nil
+	case "$all":
+		required, ok := argument.([]interface{})
+		if !ok {
+			return false, errors.New("$all requires an array")
+		}
+		array, ok := value.([]interface{})
+		if !ok {
+			return false, nil
+		}
+		for _, r := range required {
+			if !containsAny(r, array) {
+				return false, nil
+			}
+		}
+		return true, nil
+	case "$elemMatch":
+		array, ok := value.([]interface{})
+		if !ok {
+			return false, nil
+		}
+		for _, element := range array {
+			matched, err := matchCondition(element, true, argument)
+			if err != nil {
+				return false, err
+			}
+			if matched {
+				return true, nil
+			}
+		}
+		return false, nil
+	default:
+		return false, errors.Errorf("operator %s is not supported by the mock stub", operator)
+	}
+}
+
+// containsAny returns whether value, or any element of value if it is an array, is equal
+// to one of the candidates
+func containsAny(value interface{}, candidates []interface{}) bool {
+	values := []interface{}{value}
+	if array, ok := value.([]interface{}); ok {
+		values = array
+	}
+	for _, v := range values {
+		for _, candidate := range candidates {
+			if collate(v, candidate) == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// lookupField returns the value of a field of doc, given in dot notation for nested fields
+func lookupField(doc map[string]interface{}, field string) (interface{}, bool) {
+	var value interface{} = doc
+	for _, name := range strings.Split(field, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		value, ok = object[name]
+		if !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// projectFields returns a document with only the given fields of doc
+func projectFields(doc map[string]interface{}, fields []string) map[string]interface{} {
+	projection := make(map[string]interface{})
+	for _, field := range fields {
+		value, ok := lookupField(doc, field)
+		if !ok {
+			continue
+		}
+		names := strings.Split(field, ".")
+		object := projection
+		for _, name := range names[:len(names)-1] {
+			next, ok := object[name].(map[string]interface{})
+			if !ok {
+				next = make(map[string]interface{})
+				object[name] = next
+			}
+			object = next
+		}
+		object[names[len(names)-1]] = value
+	}
+	return projection
+}
+
+// collate compares two JSON values following the CouchDB collation order:
+// null, false, true, numbers, strings, arrays, objects
+func collate(v1, v2 interface{}) int {
+	r1, r2 := collationRank(v1), collationRank(v2)
+	if r1 != r2 {
+		return compareInts(r1, r2)
+	}
+
+	switch v1 := v1.(type) {
+	case json.Number, float64:
+		f1, _ := toFloat(v1)
+		f2, _ := toFloat(v2)
+		switch {
+		case f1 < f2:
+			return -1
+		case f1 > f2:
+			return 1
+		}
+		return 0
+	case string:
+		return strings.Compare(v1, v2.(string))
+	case []interface{}:
+		a2 := v2.([]interface{})
+		for i := 0; i < len(v1) && i < len(a2); i++ {
+			if c := collate(v1[i], a2[i]); c != 0 {
+				return c
+			}
+		}
+		return compareInts(len(v1), len(a2))
+	case map[string]interface{}:
+		o2 := v2.(map[string]interface{})
+		k1, k2 := sortedFieldNames(v1), sortedFieldNames(o2)
+		for i := 0; i < len(k1) && i < len(k2); i++ {
+			if c := strings.Compare(k1[i], k2[i]); c != 0 {
+				return c
+			}
+			if c := collate(v1[k1[i]], o2[k2[i]]); c != 0 {
+				return c
+			}
+		}
+		return compareInts(len(k1), len(k2))
+	}
+	// null and booleans are fully ordered by their rank
+	return 0
+}
+
+func collationRank(v interface{}) int {
+	switch v := v.(type) {
+	case nil:
+		return 0
+	case bool:
+		if v {
+			return 2
+		}
+		return 1
+	case json.Number, float64:
+		return 3
+	case string:
+		return 4
+	case []interface{}:
+		return 5
+	default:
+		return 6
+	}
+}
+
+func jsonTypeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number, float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+func compareInts(i1, i2 int) int {
+	switch {
+	case i1 < i2:
+		return -1
+	case i1 > i2:
+		return 1
+	}
+	return 0
+}
+
+func sortedFieldNames(object map[string]interface{}) []string {
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
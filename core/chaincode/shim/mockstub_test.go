@@ -17,13 +17,20 @@ limitations under the License.
 package shim
 
 import (
+	"container/list"
 	"encoding/json"
 	"fmt"
 	"reflect"
 	"testing"
 
+	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/queryresult"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestMockStateRangeQueryIterator(t *testing.T) {
@@ -271,3 +278,545 @@ func TestMockMock(t *testing.T) {
 	getBytes("f", []string{"a", "b"})
 	getFuncArgs([][]byte{[]byte("a")})
 }
+
+func TestMockStateValidationParameter(t *testing.T) {
+	stub := NewMockStub("validationParameterTest", nil)
+
+	ep, err := stub.GetStateValidationParameter("key")
+	assert.NoError(t, err)
+	assert.Nil(t, ep)
+
+	err = stub.SetStateValidationParameter("key", []byte("policy"))
+	assert.NoError(t, err)
+	ep, err = stub.GetStateValidationParameter("key")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("policy"), ep)
+}
+
+// mockStubTestCC is a chaincode whose invoke is provided by the test
+type mockStubTestCC struct {
+	invoke func(stub ChaincodeStubInterface) pb.Response
+}
+
+func (cc *mockStubTestCC) Init(stub ChaincodeStubInterface) pb.Response {
+	return Success(nil)
+}
+
+func (cc *mockStubTestCC) Invoke(stub ChaincodeStubInterface) pb.Response {
+	return cc.invoke(stub)
+}
+
+func TestMockStubTransactionWrites(t *testing.T) {
+	cc := &mockStubTestCC{}
+	stub := NewMockStub("txWritesTest", cc)
+
+	cc.invoke = func(stub ChaincodeStubInterface) pb.Response {
+		assert.NoError(t, stub.PutState("key1", []byte("value1")))
+		assert.NoError(t, stub.(*MockStub).PutPrivateData("coll", "pkey1", []byte("pvalue1")))
+		assert.NoError(t, stub.SetStateValidationParameter("key1", []byte("policy")))
+		// the writes of a transaction are not visible to its own reads
+		value, err := stub.GetState("key1")
+		assert.NoError(t, err)
+		assert.Nil(t, value)
+		value, err = stub.(*MockStub).GetPrivateData("coll", "pkey1")
+		assert.NoError(t, err)
+		assert.Nil(t, value)
+		return Success(nil)
+	}
+	res := stub.MockInvoke("tx1", nil)
+	assert.Equal(t, int32(OK), res.Status)
+	assert.Equal(t, []byte("value1"), stub.State["key1"])
+	assert.Equal(t, []byte("pvalue1"), stub.PvtState["coll"]["pkey1"])
+	assert.Equal(t, []byte("policy"), stub.EndorsementPolicies["key1"])
+
+	// the writes of a transaction returning an error are discarded
+	cc.invoke = func(stub ChaincodeStubInterface) pb.Response {
+		assert.NoError(t, stub.PutState("key1", []byte("value2")))
+		assert.NoError(t, stub.PutState("key2", []byte("value2")))
+		assert.NoError(t, stub.(*MockStub).DelPrivateData("coll", "pkey1"))
+		assert.NoError(t, stub.SetStateValidationParameter("key1", []byte("policy2")))
+		return Error("failed")
+	}
+	res = stub.MockInvoke("tx2", nil)
+	assert.Equal(t, int32(ERROR), res.Status)
+	assert.Equal(t, []byte("value1"), stub.State["key1"])
+	assert.NotContains(t, stub.State, "key2")
+	assert.Equal(t, []string{"key1"}, toStringSlice(stub.Keys))
+	assert.Equal(t, []byte("pvalue1"), stub.PvtState["coll"]["pkey1"])
+	assert.Equal(t, []byte("policy"), stub.EndorsementPolicies["key1"])
+
+	// the last write of a transaction to a key wins
+	cc.invoke = func(stub ChaincodeStubInterface) pb.Response {
+		assert.NoError(t, stub.PutState("key2", []byte("value2")))
+		assert.NoError(t, stub.DelState("key2"))
+		assert.NoError(t, stub.DelState("key1"))
+		assert.NoError(t, stub.PutState("key1", []byte("value3")))
+		assert.NoError(t, stub.(*MockStub).DelPrivateData("coll", "pkey1"))
+		return Success(nil)
+	}
+	res = stub.MockInvoke("tx3", nil)
+	assert.Equal(t, int32(OK), res.Status)
+	assert.Equal(t, []byte("value3"), stub.State["key1"])
+	assert.NotContains(t, stub.State, "key2")
+	assert.NotContains(t, stub.PvtState["coll"], "pkey1")
+
+	// a transaction can be rolled back explicitly
+	stub.MockTransactionStart("tx4")
+	assert.NoError(t, stub.PutState("key4", []byte("value4")))
+	stub.MockTransactionRollback()
+	stub.MockTransactionEnd("tx4")
+	assert.NotContains(t, stub.State, "key4")
+
+	assert.EqualError(t, stub.PutState("key5", []byte("value5")), "cannot PutState without a transactions - call stub.MockTransactionStart()?")
+	stub.MockTransactionStart("tx5")
+	assert.EqualError(t, stub.PutState("", []byte("value5")), "key must not be an empty string")
+	stub.MockTransactionEnd("tx5")
+}
+
+func TestMockStubInvokeChaincodeWrites(t *testing.T) {
+	calleeCC := &mockStubTestCC{}
+	callee := NewMockStub("callee", calleeCC)
+	callerCC := &mockStubTestCC{}
+	caller := NewMockStub("caller", callerCC)
+	caller.MockPeerChaincode("callee/mychan", callee)
+
+	calleeCC.invoke = func(stub ChaincodeStubInterface) pb.Response {
+		assert.Equal(t, "tx1", stub.GetTxID())
+		assert.NoError(t, stub.PutState("calleekey", []byte("calleevalue")))
+		return Success(nil)
+	}
+	callerCC.invoke = func(stub ChaincodeStubInterface) pb.Response {
+		assert.NoError(t, stub.PutState("callerkey", []byte("callervalue")))
+		res := stub.InvokeChaincode("callee", nil, "mychan")
+		assert.Equal(t, int32(OK), res.Status)
+		// the writes of the callee are staged in the transaction of the caller
+		assert.NotContains(t, callee.State, "calleekey")
+		return Success(nil)
+	}
+	res := caller.MockInvoke("tx1", nil)
+	assert.Equal(t, int32(OK), res.Status)
+	assert.Equal(t, []byte("callervalue"), caller.State["callerkey"])
+	assert.Equal(t, []byte("calleevalue"), callee.State["calleekey"])
+	assert.Equal(t, []string{"calleekey"}, toStringSlice(callee.Keys))
+	iter, err := callee.GetHistoryForKey("calleekey")
+	assert.NoError(t, err)
+	modification, err := iter.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, "tx1", modification.TxId)
+	assert.Equal(t, caller.TxTimestamp, modification.Timestamp)
+
+	// the writes of the callee are discarded along with those of the caller
+	calleeCC.invoke = func(stub ChaincodeStubInterface) pb.Response {
+		assert.NoError(t, stub.PutState("calleekey", []byte("calleevalue2")))
+		return Success(nil)
+	}
+	callerCC.invoke = func(stub ChaincodeStubInterface) pb.Response {
+		stub.InvokeChaincode("callee", nil, "mychan")
+		return Error("failed")
+	}
+	res = caller.MockInvoke("tx2", nil)
+	assert.Equal(t, int32(ERROR), res.Status)
+	assert.Equal(t, []byte("calleevalue"), callee.State["calleekey"])
+
+	// the writes of a callee returning an error are discarded
+	calleeCC.invoke = func(stub ChaincodeStubInterface) pb.Response {
+		assert.NoError(t, stub.PutState("calleekey", []byte("calleevalue3")))
+		return Error("failed")
+	}
+	callerCC.invoke = func(stub ChaincodeStubInterface) pb.Response {
+		res := stub.InvokeChaincode("callee", nil, "mychan")
+		assert.Equal(t, int32(ERROR), res.Status)
+		assert.NoError(t, stub.PutState("callerkey", []byte("callervalue3")))
+		return Success(nil)
+	}
+	res = caller.MockInvoke("tx3", nil)
+	assert.Equal(t, int32(OK), res.Status)
+	assert.Equal(t, []byte("callervalue3"), caller.State["callerkey"])
+	assert.Equal(t, []byte("calleevalue"), callee.State["calleekey"])
+
+	// the writes of a chaincode invoked by the callee are part of the same transaction
+	nestedCC := &mockStubTestCC{invoke: func(stub ChaincodeStubInterface) pb.Response {
+		assert.NoError(t, stub.PutState("nestedkey", []byte("nestedvalue")))
+		return Success(nil)
+	}}
+	nested := NewMockStub("nested", nestedCC)
+	callee.MockPeerChaincode("nested", nested)
+	calleeCC.invoke = func(stub ChaincodeStubInterface) pb.Response {
+		return stub.InvokeChaincode("nested", nil, "")
+	}
+	callerCC.invoke = func(stub ChaincodeStubInterface) pb.Response {
+		stub.InvokeChaincode("callee", nil, "mychan")
+		assert.NotContains(t, nested.State, "nestedkey")
+		return Error("failed")
+	}
+	caller.MockInvoke("tx4", nil)
+	assert.NotContains(t, nested.State, "nestedkey")
+	callerCC.invoke = func(stub ChaincodeStubInterface) pb.Response {
+		return stub.InvokeChaincode("callee", nil, "mychan")
+	}
+	caller.MockInvoke("tx5", nil)
+	assert.Equal(t, []byte("nestedvalue"), nested.State["nestedkey"])
+
+	res = caller.InvokeChaincode("unknown", nil, "")
+	assert.Equal(t, int32(ERROR), res.Status)
+	assert.Equal(t, "chaincode unknown is not registered with MockPeerChaincode", res.Message)
+}
+
+func TestMockStubGetStateByRange(t *testing.T) {
+	stub := NewMockStub("rangeTest", nil)
+	stub.MockTransactionStart("init")
+	for _, key := range []string{"key1", "key2", "key3"} {
+		assert.NoError(t, stub.PutState(key, []byte("value")))
+	}
+	stub.MockTransactionEnd("init")
+
+	keysOf := func(iter StateQueryIteratorInterface) []string {
+		var keys []string
+		for iter.HasNext() {
+			kv, err := iter.Next()
+			assert.NoError(t, err)
+			keys = append(keys, kv.Key)
+		}
+		assert.NoError(t, iter.Close())
+		return keys
+	}
+
+	// the end key is excluded, as it is by the peer
+	iter, err := stub.GetStateByRange("key1", "key3")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"key1", "key2"}, keysOf(iter))
+	iter, err = stub.GetStateByRange("key2", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"key2", "key3"}, keysOf(iter))
+	iter, err = stub.GetStateByRange("", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"key1", "key2", "key3"}, keysOf(iter))
+}
+
+func toStringSlice(l *list.List) []string {
+	var s []string
+	for e := l.Front(); e != nil; e = e.Next() {
+		s = append(s, e.Value.(string))
+	}
+	return s
+}
+
+func TestMockStubProposalFields(t *testing.T) {
+	cc := &mockStubTestCC{}
+	stub := NewMockStub("proposalFieldsTest", cc)
+	stub.Creator = []byte("creator")
+	stub.TransientMap = map[string][]byte{"secret": []byte("value")}
+	ts := &timestamp.Timestamp{Seconds: 1500000000}
+	stub.SetTxTimestamp(ts)
+
+	cc.invoke = func(stub ChaincodeStubInterface) pb.Response {
+		creator, err := stub.GetCreator()
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("creator"), creator)
+		transient, err := stub.GetTransient()
+		assert.NoError(t, err)
+		assert.Equal(t, map[string][]byte{"secret": []byte("value")}, transient)
+		txTimestamp, err := stub.GetTxTimestamp()
+		assert.NoError(t, err)
+		assert.Equal(t, ts, txTimestamp)
+		return Success(nil)
+	}
+	res := stub.MockInvoke("tx1", nil)
+	assert.Equal(t, int32(OK), res.Status, res.Message)
+
+	// the fields of a signed proposal override the ones set on the stub
+	cis := &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			ChaincodeId: &pb.ChaincodeID{Name: "proposalFieldsTest"},
+			Input:       &pb.ChaincodeInput{Args: [][]byte{[]byte("invoke")}},
+		},
+	}
+	proposal, _, err := utils.CreateChaincodeProposalWithTransient(common.HeaderType_ENDORSER_TRANSACTION, "mychannel", cis,
+		[]byte("proposal creator"), map[string][]byte{"secret": []byte("proposal value")})
+	assert.NoError(t, err)
+	expectedBinding, err := utils.ComputeProposalBinding(proposal)
+	assert.NoError(t, err)
+	hdr, err := utils.GetHeader(proposal.Header)
+	assert.NoError(t, err)
+	chdr, err := utils.UnmarshalChannelHeader(hdr.ChannelHeader)
+	assert.NoError(t, err)
+
+	cc.invoke = func(stub ChaincodeStubInterface) pb.Response {
+		creator, err := stub.GetCreator()
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("proposal creator"), creator)
+		transient, err := stub.GetTransient()
+		assert.NoError(t, err)
+		assert.Equal(t, map[string][]byte{"secret": []byte("proposal value")}, transient)
+		binding, err := stub.GetBinding()
+		assert.NoError(t, err)
+		assert.Equal(t, expectedBinding, binding)
+		txTimestamp, err := stub.GetTxTimestamp()
+		assert.NoError(t, err)
+		assert.Equal(t, chdr.Timestamp, txTimestamp)
+		return Success(nil)
+	}
+	sp := &pb.SignedProposal{ProposalBytes: utils.MarshalOrPanic(proposal)}
+	res = stub.MockInvokeWithSignedProposal("tx2", nil, sp)
+	assert.Equal(t, int32(OK), res.Status, res.Message)
+
+	// the proposal fields do not leak into the following transactions
+	cc.invoke = func(stub ChaincodeStubInterface) pb.Response {
+		creator, err := stub.GetCreator()
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("creator"), creator)
+		return Success(nil)
+	}
+	res = stub.MockInvoke("tx3", nil)
+	assert.Equal(t, int32(OK), res.Status, res.Message)
+
+	res = stub.MockInvokeWithSignedProposal("tx4", nil, &pb.SignedProposal{ProposalBytes: []byte("garbage")})
+	assert.Equal(t, int32(ERROR), res.Status)
+	assert.Contains(t, res.Message, "failed extracting proposal from signed proposal")
+}
+
+func TestMockStubGetArgsSlice(t *testing.T) {
+	stub := NewMockStub("argsSliceTest", nil)
+	stub.args = [][]byte{[]byte("func"), []byte("a"), []byte("bc")}
+	argsSlice, err := stub.GetArgsSlice()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("funcabc"), argsSlice)
+}
+
+func TestMockStubGetHistoryForKey(t *testing.T) {
+	stub := NewMockStub("historyTest", nil)
+	for i, write := range []string{"value1", "", "value3"} {
+		txID := fmt.Sprintf("tx%d", i+1)
+		stub.SetTxTimestamp(&timestamp.Timestamp{Seconds: int64(i + 1)})
+		stub.MockTransactionStart(txID)
+		if write == "" {
+			stub.DelState("key")
+		} else {
+			stub.PutState("key", []byte(write))
+		}
+		stub.MockTransactionEnd(txID)
+	}
+	// a rolled back transaction leaves no history
+	stub.MockTransactionStart("tx4")
+	stub.PutState("key", []byte("value4"))
+	stub.MockTransactionRollback()
+	stub.MockTransactionEnd("tx4")
+
+	iter, err := stub.GetHistoryForKey("key")
+	assert.NoError(t, err)
+	var history []*queryresult.KeyModification
+	for iter.HasNext() {
+		km, err := iter.Next()
+		assert.NoError(t, err)
+		history = append(history, km)
+	}
+	assert.NoError(t, iter.Close())
+	assert.Equal(t, []*queryresult.KeyModification{
+		{TxId: "tx1", Value: []byte("value1"), Timestamp: &timestamp.Timestamp{Seconds: 1}},
+		{TxId: "tx2", Timestamp: &timestamp.Timestamp{Seconds: 2}, IsDelete: true},
+		{TxId: "tx3", Value: []byte("value3"), Timestamp: &timestamp.Timestamp{Seconds: 3}},
+	}, history)
+	_, err = iter.Next()
+	assert.Error(t, err)
+
+	iter, err = stub.GetHistoryForKey("unknown")
+	assert.NoError(t, err)
+	assert.False(t, iter.HasNext())
+}
+
+func TestMockStubPrivateDataQueries(t *testing.T) {
+	stub := NewMockStub("pvtDataTest", nil)
+	stub.MockTransactionStart("init")
+	for _, marble := range []Marble{
+		{ObjectType: "marble", Name: "marble1", Color: "blue", Size: 35, Owner: "tom"},
+		{ObjectType: "marble", Name: "marble2", Color: "red", Size: 50, Owner: "tom"},
+		{ObjectType: "marble", Name: "marble3", Color: "blue", Size: 70, Owner: "jerry"},
+	} {
+		marbleJSON, err := json.Marshal(marble)
+		assert.NoError(t, err)
+		assert.NoError(t, stub.PutPrivateData("marbles", marble.Name, marbleJSON))
+		indexKey, err := stub.CreateCompositeKey("color~name", []string{marble.Color, marble.Name})
+		assert.NoError(t, err)
+		assert.NoError(t, stub.PutPrivateData("marbles", indexKey, []byte{0x00}))
+	}
+	stub.MockTransactionEnd("init")
+
+	keysOf := func(iter StateQueryIteratorInterface) []string {
+		var keys []string
+		for iter.HasNext() {
+			kv, err := iter.Next()
+			assert.NoError(t, err)
+			keys = append(keys, kv.Key)
+		}
+		assert.NoError(t, iter.Close())
+		return keys
+	}
+
+	iter, err := stub.GetPrivateDataByRange("marbles", "marble1", "marble3")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"marble1", "marble2"}, keysOf(iter))
+	iter, err = stub.GetPrivateDataByRange("marbles", "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"marble1", "marble2", "marble3"}, keysOf(iter))
+	iter, err = stub.GetPrivateDataByRange("unknown", "", "")
+	assert.NoError(t, err)
+	assert.Empty(t, keysOf(iter))
+
+	iter, err = stub.GetPrivateDataByPartialCompositeKey("marbles", "color~name", []string{"blue"})
+	assert.NoError(t, err)
+	blueKey1, _ := stub.CreateCompositeKey("color~name", []string{"blue", "marble1"})
+	blueKey3, _ := stub.CreateCompositeKey("color~name", []string{"blue", "marble3"})
+	assert.Equal(t, []string{blueKey1, blueKey3}, keysOf(iter))
+
+	iter, err = stub.GetPrivateDataQueryResult("marbles", `{"selector":{"owner":"tom","size":{"$gt":40}}}`)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"marble2"}, keysOf(iter))
+
+	_, err = stub.GetPrivateData("", "marble1")
+	assert.EqualError(t, err, "collection must not be an empty string")
+	_, err = stub.GetPrivateDataByRange("", "", "")
+	assert.EqualError(t, err, "collection must not be an empty string")
+	_, err = stub.GetPrivateDataByPartialCompositeKey("", "color~name", nil)
+	assert.EqualError(t, err, "collection must not be an empty string")
+	_, err = stub.GetPrivateDataQueryResult("", `{"selector":{}}`)
+	assert.EqualError(t, err, "collection must not be an empty string")
+}
+
+func TestMockStubGetQueryResult(t *testing.T) {
+	stub := NewMockStub("queryTest", nil)
+	stub.MockTransactionStart("init")
+	stub.PutState("marble1", []byte(`{"docType":"marble","name":"marble1","color":"blue","size":35,"owner":{"name":"tom","age":30},"tags":["shiny","round"]}`))
+	stub.PutState("marble2", []byte(`{"docType":"marble","name":"marble2","color":"red","size":50,"owner":{"name":"tom","age":30},"tags":["matte"]}`))
+	stub.PutState("marble3", []byte(`{"docType":"marble","name":"marble3","color":"blue","size":70,"owner":{"name":"jerry","age":25}}`))
+	stub.PutState("owner1", []byte(`{"docType":"owner","name":"tom"}`))
+	stub.PutState("binary", []byte{0x00, 0x01})
+	stub.MockTransactionEnd("init")
+
+	query := func(query string) []string {
+		iter, err := stub.GetQueryResult(query)
+		assert.NoError(t, err, query)
+		if err != nil {
+			return nil
+		}
+		var keys []string
+		for iter.HasNext() {
+			kv, err := iter.Next()
+			assert.NoError(t, err)
+			keys = append(keys, kv.Key)
+		}
+		assert.NoError(t, iter.Close())
+		return keys
+	}
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{`{"selector":{"docType":"marble"}}`, []string{"marble1", "marble2", "marble3"}},
+		{`{"selector":{"docType":"marble","color":"blue"}}`, []string{"marble1", "marble3"}},
+		{`{"selector":{"owner.name":"tom"}}`, []string{"marble1", "marble2"}},
+		{`{"selector":{"owner":{"age":{"$lt":30}}}}`, []string{"marble3"}},
+		{`{"selector":{"size":{"$gte":50,"$lte":70}}}`, []string{"marble2", "marble3"}},
+		{`{"selector":{"color":{"$in":["red","green"]}}}`, []string{"marble2"}},
+		{`{"selector":{"docType":"marble","color":{"$nin":["red"]}}}`, []string{"marble1", "marble3"}},
+		{`{"selector":{"$or":[{"color":"red"},{"size":70}]}}`, []string{"marble2", "marble3"}},
+		{`{"selector":{"docType":"marble","$not":{"color":"blue"}}}`, []string{"marble2"}},
+		{`{"selector":{"tags":{"$exists":true}}}`, []string{"marble1", "marble2"}},
+		{`{"selector":{"tags":{"$all":["round","shiny"]}}}`, []string{"marble1"}},
+		{`{"selector":{"tags":{"$elemMatch":{"$eq":"matte"}}}}`, []string{"marble2"}},
+		{`{"selector":{"tags":{"$size":1}}}`, []string{"marble2"}},
+		{`{"selector":{"name":{"$regex":"^marble[12]$"}}}`, []string{"marble1", "marble2"}},
+		{`{"selector":{"size":{"$mod":[7,0]}}}`, []string{"marble1", "marble3"}},
+		{`{"selector":{"_id":"owner1"}}`, []string{"owner1"}},
+		{`{"selector":{"docType":"marble"},"sort":[{"size":"desc"}]}`, []string{"marble3", "marble2", "marble1"}},
+		{`{"selector":{"docType":"marble"},"sort":["color",{"size":"desc"}]}`, []string{"marble3", "marble1", "marble2"}},
+		{`{"selector":{"docType":"marble"},"limit":1}`, []string{"marble1", "marble2", "marble3"}},
+		{`{"selector":{"color":"green"}}`, nil},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, query(test.query), test.query)
+	}
+
+	iter, err := stub.GetQueryResult(`{"selector":{"name":"marble3"},"fields":["name","owner.name"]}`)
+	assert.NoError(t, err)
+	kv, err := iter.Next()
+	assert.NoError(t, err)
+	assert.True(t, jsonBytesEqual([]byte(`{"name":"marble3","owner":{"name":"jerry"}}`), kv.Value), string(kv.Value))
+	assert.False(t, iter.HasNext())
+
+	for _, badQuery := range []string{
+		`not json`,
+		`{"sort":["size"]}`,
+		`{"selector":{"size":{"$foo":1}}}`,
+		`{"selector":{"docType":"marble"},"sort":[{"size":"up"}]}`,
+	} {
+		_, err := stub.GetQueryResult(badQuery)
+		assert.Error(t, err, badQuery)
+	}
+	_, err = stub.GetQueryResult(`{"selector":{"size":{"$foo":1}}}`)
+	assert.EqualError(t, err, "operator $foo is not supported by the mock stub")
+}
+
+func TestMockStubPagination(t *testing.T) {
+	stub := NewMockStub("paginationTest", nil)
+	stub.MockTransactionStart("init")
+	for i := 1; i <= 5; i++ {
+		stub.PutState(fmt.Sprintf("marble%d", i), []byte(fmt.Sprintf(`{"docType":"marble","size":%d}`, 10*i)))
+	}
+	stub.PutState("owner1", []byte(`{"docType":"owner"}`))
+	stub.MockTransactionEnd("init")
+
+	keysOf := func(iter StateQueryIteratorInterface) []string {
+		var keys []string
+		for iter.HasNext() {
+			kv, err := iter.Next()
+			assert.NoError(t, err)
+			keys = append(keys, kv.Key)
+		}
+		assert.NoError(t, iter.Close())
+		return keys
+	}
+
+	t.Run("Range", func(t *testing.T) {
+		iter, metadata, err := stub.GetStateByRangeWithPagination("marble1", "marble5", 2, "")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"marble1", "marble2"}, keysOf(iter))
+		assert.Equal(t, &pb.QueryResponseMetadata{FetchedRecordsCount: 2, Bookmark: "marble3"}, metadata)
+
+		iter, metadata, err = stub.GetStateByRangeWithPagination("marble1", "marble5", 2, metadata.Bookmark)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"marble3", "marble4"}, keysOf(iter))
+		assert.Equal(t, &pb.QueryResponseMetadata{FetchedRecordsCount: 2, Bookmark: ""}, metadata)
+
+		iter, metadata, err = stub.GetStateByRangeWithPagination("", "", 4, "marble4")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"marble4", "marble5", "owner1"}, keysOf(iter))
+		assert.Equal(t, &pb.QueryResponseMetadata{FetchedRecordsCount: 3, Bookmark: ""}, metadata)
+
+		_, _, err = stub.GetStateByRangeWithPagination("marble1", "marble5", 0, "")
+		assert.EqualError(t, err, "pageSize must be greater than zero")
+	})
+
+	t.Run("Query", func(t *testing.T) {
+		query := `{"selector":{"docType":"marble"},"sort":[{"size":"desc"}]}`
+		iter, metadata, err := stub.GetQueryResultWithPagination(query, 2, "")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"marble5", "marble4"}, keysOf(iter))
+		assert.Equal(t, &pb.QueryResponseMetadata{FetchedRecordsCount: 2, Bookmark: "marble3"}, metadata)
+
+		iter, metadata, err = stub.GetQueryResultWithPagination(query, 2, metadata.Bookmark)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"marble3", "marble2"}, keysOf(iter))
+		assert.Equal(t, &pb.QueryResponseMetadata{FetchedRecordsCount: 2, Bookmark: "marble1"}, metadata)
+
+		iter, metadata, err = stub.GetQueryResultWithPagination(query, 2, metadata.Bookmark)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"marble1"}, keysOf(iter))
+		assert.Equal(t, &pb.QueryResponseMetadata{FetchedRecordsCount: 1, Bookmark: ""}, metadata)
+
+		_, _, err = stub.GetQueryResultWithPagination(query, 2, "owner1")
+		assert.EqualError(t, err, "invalid bookmark owner1")
+		_, _, err = stub.GetQueryResultWithPagination(query, -1, "")
+		assert.EqualError(t, err, "pageSize must be greater than zero")
+	})
+}
@@ -0,0 +1,111 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shim
+
+import (
+	"github.com/hyperledger/fabric/bccsp/factory"
+	"github.com/hyperledger/fabric/core/comm"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+)
+
+// TLSProperties are the TLS properties of a ChaincodeServer
+type TLSProperties struct {
+	// Disabled disables TLS, in which case the other properties are ignored
+	Disabled bool
+	// Key and Cert are the PEM encoded private key and certificate of the server
+	Key  []byte
+	Cert []byte
+	// ClientCACerts are the PEM encoded certificates of the CAs of the peers.
+	// If set, the peers are required to authenticate with a client certificate
+	ClientCACerts []byte
+}
+
+// ChaincodeServer runs a chaincode as a server that the peer connects to
+// (chaincode-as-a-service), rather than having the chaincode connect to the peer.
+// The peer learns the address of the server from the connection.json file
+// produced by an external builder
+type ChaincodeServer struct {
+	// CCID is the canonical name (name:version) of the chaincode
+	CCID string
+	// Address is the listen address of the server
+	Address string
+	// CC is the chaincode that is served
+	CC Chaincode
+	// TLSProps are the TLS properties of the server
+	TLSProps TLSProperties
+}
+
+// Start starts the server and serves the peers connecting to it. It returns
+// when the server fails
+func (cs *ChaincodeServer) Start() error {
+	if cs.CCID == "" {
+		return errors.New("ccid must be specified")
+	}
+	if cs.Address == "" {
+		return errors.New("address must be specified")
+	}
+	if cs.CC == nil {
+		return errors.New("chaincode must be specified")
+	}
+
+	SetupChaincodeLogging()
+
+	err := factory.InitFactories(factory.GetDefaultOpts())
+	if err != nil {
+		return errors.WithMessage(err, "internal error, BCCSP could not be initialized with default options")
+	}
+
+	serverConfig := comm.ServerConfig{
+		KaOpts:  comm.DefaultKeepaliveOptions,
+		SecOpts: &comm.SecureOptions{},
+	}
+	if !cs.TLSProps.Disabled {
+		if cs.TLSProps.Key == nil || cs.TLSProps.Cert == nil {
+			return errors.New("key and cert must be specified when TLS is enabled")
+		}
+		serverConfig.SecOpts.UseTLS = true
+		serverConfig.SecOpts.Key = cs.TLSProps.Key
+		serverConfig.SecOpts.Certificate = cs.TLSProps.Cert
+		if cs.TLSProps.ClientCACerts != nil {
+			serverConfig.SecOpts.RequireClientCert = true
+			serverConfig.SecOpts.ClientRootCAs = [][]byte{cs.TLSProps.ClientCACerts}
+		}
+	}
+
+	server, err := comm.NewGRPCServer(cs.Address, serverConfig)
+	if err != nil {
+		return errors.WithMessage(err, "failed creating chaincode server")
+	}
+	pb.RegisterChaincodeSupportServer(server.Server(), &chaincodeServer{ccid: cs.CCID, cc: cs.CC})
+
+	chaincodeLogger.Infof("Chaincode %s listening on %s", cs.CCID, server.Address())
+	return server.Start()
+}
+
+// chaincodeServer serves the chaincode to the peers, which open a stream
+// by calling Register
+type chaincodeServer struct {
+	ccid string
+	cc   Chaincode
+}
+
+// Register chats with the peer on the stream opened by the peer
+func (s *chaincodeServer) Register(stream pb.ChaincodeSupport_RegisterServer) error {
+	chaincodeLogger.Debugf("Peer connected, starting chat using name=%s", s.ccid)
+	return chatWithPeer(s.ccid, &serverStream{ChaincodeSupport_RegisterServer: stream}, s.cc)
+}
+
+// serverStream adapts the server side of the stream to PeerChaincodeStream.
+// The stream is closed when Register returns
+type serverStream struct {
+	pb.ChaincodeSupport_RegisterServer
+}
+
+func (s *serverStream) CloseSend() error {
+	return nil
+}
@@ -0,0 +1,71 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shim
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+func TestChaincodeServerMissingProperties(t *testing.T) {
+	cc := &shimTestCC{}
+	tests := []struct {
+		server *ChaincodeServer
+		errMsg string
+	}{
+		{&ChaincodeServer{Address: "127.0.0.1:0", CC: cc}, "ccid must be specified"},
+		{&ChaincodeServer{CCID: "mycc:1.0", CC: cc}, "address must be specified"},
+		{&ChaincodeServer{CCID: "mycc:1.0", Address: "127.0.0.1:0"}, "chaincode must be specified"},
+		{&ChaincodeServer{CCID: "mycc:1.0", Address: "127.0.0.1:0", CC: cc}, "key and cert must be specified when TLS is enabled"},
+	}
+	for _, tc := range tests {
+		assert.EqualError(t, tc.server.Start(), tc.errMsg)
+	}
+}
+
+func TestChaincodeServer(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	address := lis.Addr().String()
+	lis.Close()
+
+	server := &ChaincodeServer{
+		CCID:     "mycc:1.0",
+		Address:  address,
+		CC:       &shimTestCC{},
+		TLSProps: TLSProperties{Disabled: true},
+	}
+	go server.Start()
+
+	var conn *grpc.ClientConn
+	for i := 0; i < 50 && conn == nil; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		conn, _ = grpc.DialContext(ctx, address, grpc.WithInsecure(), grpc.WithBlock())
+		cancel()
+	}
+	if conn == nil {
+		t.Fatal("failed connecting to the chaincode server")
+	}
+	defer conn.Close()
+
+	// the peer opens the stream and the chaincode registers on it
+	stream, err := pb.NewChaincodeSupportClient(conn).Register(context.Background())
+	assert.NoError(t, err)
+	msg, err := stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, pb.ChaincodeMessage_REGISTER, msg.Type)
+	chaincodeID := &pb.ChaincodeID{}
+	assert.NoError(t, proto.Unmarshal(msg.Payload, chaincodeID))
+	assert.Equal(t, "mycc:1.0", chaincodeID.Name)
+}
@@ -24,6 +24,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/flogging"
 	mockpeer "github.com/hyperledger/fabric/common/mocks/peer"
 	"github.com/hyperledger/fabric/common/util"
@@ -90,6 +91,8 @@ func (t *shimTestCC) Invoke(stub ChaincodeStubInterface) pb.Response {
 		return t.cc2cc(stub, args)
 	} else if function == "rangeq" {
 		return t.rangeq(stub, args)
+	} else if function == "rangeqpaginated" {
+		return t.rangeqpaginated(stub, args)
 	} else if function == "historyq" {
 		return t.historyq(stub, args)
 	} else if function == "richq" {
@@ -252,6 +255,29 @@ func (t *shimTestCC) rangeq(stub ChaincodeStubInterface, args []string) pb.Respo
 	return Success(buffer.Bytes())
 }
 
+// rangeqpaginated calls range query with pagination and returns the bookmark of the next page
+func (t *shimTestCC) rangeqpaginated(stub ChaincodeStubInterface, args []string) pb.Response {
+	if len(args) != 2 {
+		return Error("Incorrect number of arguments. Expecting keys for range query")
+	}
+
+	resultsIterator, metadata, err := stub.GetStateByRangeWithPagination(args[0], args[1], 2, "")
+	if err != nil {
+		return Error(err.Error())
+	}
+	defer resultsIterator.Close()
+
+	for resultsIterator.HasNext() {
+		if _, err := resultsIterator.Next(); err != nil {
+			return Error(err.Error())
+		}
+	}
+	if metadata == nil {
+		return Error("Expected query response metadata")
+	}
+	return Success([]byte(metadata.Bookmark))
+}
+
 // richq calls tichq query
 func (t *shimTestCC) richq(stub ChaincodeStubInterface, args []string) pb.Response {
 	if len(args) != 1 {
@@ -755,6 +781,36 @@ func TestInvoke(t *testing.T) {
 	//wait for done
 	processDone(t, done, false)
 
+	//range query with pagination
+
+	//create the response, carrying the metadata of the page
+	rangeQueryPaginatedResponse := &pb.QueryResponse{Results: []*pb.QueryResultBytes{
+		{ResultBytes: utils.MarshalOrPanic(&lproto.KV{Namespace: "getputcc", Key: "A", Value: []byte("100")})},
+		{ResultBytes: utils.MarshalOrPanic(&lproto.KV{Namespace: "getputcc", Key: "B", Value: []byte("200")})}},
+		HasMore:  false,
+		Metadata: utils.MarshalOrPanic(&pb.QueryResponseMetadata{FetchedRecordsCount: 2, Bookmark: "C"})}
+	rangeQueryPaginatedResponder := func(msg *pb.ChaincodeMessage) *pb.ChaincodeMessage {
+		getStateByRange := &pb.GetStateByRange{}
+		assert.NoError(t, proto.Unmarshal(msg.Payload, getStateByRange))
+		queryMetadata := &pb.QueryMetadata{}
+		assert.NoError(t, proto.Unmarshal(getStateByRange.Metadata, queryMetadata))
+		assert.Equal(t, int32(2), queryMetadata.PageSize)
+		return &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: utils.MarshalOrPanic(rangeQueryPaginatedResponse), Txid: "6d", ChannelId: channelId}
+	}
+
+	respSet = &mockpeer.MockResponseSet{errorFunc, errorFunc, []*mockpeer.MockResponse{
+		{&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_GET_STATE_BY_RANGE, Txid: "6d", ChannelId: channelId}, rangeQueryPaginatedResponder},
+		{&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_QUERY_STATE_CLOSE, Txid: "6d", ChannelId: channelId}, &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Txid: "6d", ChannelId: channelId}},
+		{&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_COMPLETED, Txid: "6d", ChannelId: channelId}, nil}}}
+	peerSide.SetResponses(respSet)
+
+	ci = &pb.ChaincodeInput{Args: [][]byte{[]byte("rangeqpaginated"), []byte("A"), []byte("D")}, Decorations: nil}
+	payload = utils.MarshalOrPanic(ci)
+	peerSide.Send(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_TRANSACTION, Payload: payload, Txid: "6d", ChannelId: channelId})
+
+	//wait for done
+	processDone(t, done, false)
+
 	//history query
 
 	//create the response
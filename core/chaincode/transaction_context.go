@@ -67,6 +67,25 @@ func (t *TransactionContext) CleanupQueryContext(queryID string) {
 	delete(t.pendingQueryResults, queryID)
 }
 
+// CleanupQueryContextWithBookmark closes the iterator of a paginated query and returns
+// the bookmark for retrieving the next page of results
+func (t *TransactionContext) CleanupQueryContextWithBookmark(queryID string) string {
+	t.queryMutex.Lock()
+	defer t.queryMutex.Unlock()
+	iter := t.queryIteratorMap[queryID]
+	bookmark := ""
+	if iter != nil {
+		if queryResultIterator, ok := iter.(ledger.QueryResultsIterator); ok {
+			bookmark = queryResultIterator.GetBookmarkAndClose()
+		} else {
+			iter.Close()
+		}
+	}
+	delete(t.queryIteratorMap, queryID)
+	delete(t.pendingQueryResults, queryID)
+	return bookmark
+}
+
 func (t *TransactionContext) CloseQueryIterators() {
 	t.queryMutex.Lock()
 	defer t.queryMutex.Unlock()
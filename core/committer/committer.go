@@ -42,6 +42,13 @@ type Committer interface {
 	// collections and namespaces of private data to retrieve
 	GetPvtDataByNum(blockNum uint64, filter ledger.PvtNsCollFilter) ([]*ledger.TxPvtData, error)
 
+	// CommitPvtDataOfOldBlocks commits the private data corresponding to already committed blocks
+	// and returns the private data whose hashes do not match with the hashes present in the blocks
+	CommitPvtDataOfOldBlocks(blockPvtData []*ledger.BlockPvtData) ([]*ledger.PvtdataHashMismatch, error)
+
+	// GetMissingPvtDataTracker returns the tracker of the private data that is missing in the ledger
+	GetMissingPvtDataTracker() (ledger.MissingPvtDataTracker, error)
+
 	// Get recent block sequence number
 	LedgerHeight() (uint64, error)
 
@@ -108,6 +108,16 @@ func (m *mockLedger) CommitWithPvtData(blockAndPvtdata *ledger2.BlockAndPvtData)
 	return args.Error(0)
 }
 
+func (m *mockLedger) CommitPvtDataOfOldBlocks(blockPvtData []*ledger2.BlockPvtData) ([]*ledger2.PvtdataHashMismatch, error) {
+	args := m.Called(blockPvtData)
+	return args.Get(0).([]*ledger2.PvtdataHashMismatch), args.Error(1)
+}
+
+func (m *mockLedger) GetMissingPvtDataTracker() (ledger2.MissingPvtDataTracker, error) {
+	args := m.Called()
+	return args.Get(0).(ledger2.MissingPvtDataTracker), args.Error(1)
+}
+
 func (m *mockLedger) PurgePrivateData(maxBlockNumToRetain uint64) error {
 	args := m.Called(maxBlockNumToRetain)
 	return args.Error(0)
@@ -0,0 +1,33 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txvalidator
+
+import (
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/protos/peer"
+)
+
+// validatorMetrics holds the metrics of the validation of the blocks of a channel
+type validatorMetrics struct {
+	scope                   metrics.Scope
+	blockValidationDuration metrics.Histogram
+	vsccValidationDuration  metrics.Histogram
+}
+
+func newValidatorMetrics(chainID string) *validatorMetrics {
+	scope := metrics.GetRootScope().SubScope("validator").Tagged(map[string]string{"channel": chainID})
+	return &validatorMetrics{
+		scope:                   scope,
+		blockValidationDuration: scope.Histogram("block_validation_duration"),
+		vsccValidationDuration:  scope.Histogram("vscc_validation_duration"),
+	}
+}
+
+// invalidTransactions returns the counter of the transactions invalidated with the given code
+func (m *validatorMetrics) invalidTransactions(code peer.TxValidationCode) metrics.Counter {
+	return m.scope.Tagged(map[string]string{"validation_code": code.String()}).Counter("invalid_transactions")
+}
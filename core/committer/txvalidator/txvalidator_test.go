@@ -52,7 +52,7 @@ func testValidationWithNTXes(t *testing.T, ledger ledger2.PeerLedger, gbHash []b
 		*mocktxvalidator.Support
 		*semaphore.Weighted
 	}{&mocktxvalidator.Support{LedgerVal: ledger, ACVal: &config.MockApplicationCapabilities{}}, semaphore.NewWeighted(10)}
-	tValidator := &txValidator{vcs, mockVsccValidator}
+	tValidator := &txValidator{support: vcs, vscc: mockVsccValidator, metrics: newValidatorMetrics("TestLedger")}
 
 	bcInfo, _ := ledger.GetBlockchainInfo()
 	testutil.AssertEquals(t, bcInfo, &common.BlockchainInfo{
@@ -127,7 +127,7 @@ func TestBlockValidationDuplicateTXId(t *testing.T) {
 		*mocktxvalidator.Support
 		*semaphore.Weighted
 	}{&mocktxvalidator.Support{LedgerVal: ledger, ACVal: acv}, semaphore.NewWeighted(10)}
-	tValidator := &txValidator{vcs, mockVsccValidator}
+	tValidator := &txValidator{support: vcs, vscc: mockVsccValidator, metrics: newValidatorMetrics("TestLedger")}
 
 	bcInfo, _ := ledger.GetBlockchainInfo()
 	testutil.AssertEquals(t, bcInfo, &common.BlockchainInfo{
@@ -214,7 +214,7 @@ func TestNewTxValidator_DuplicateTransactions(t *testing.T) {
 		*mocktxvalidator.Support
 		*semaphore.Weighted
 	}{&mocktxvalidator.Support{LedgerVal: ledger, ACVal: &config.MockApplicationCapabilities{}}, semaphore.NewWeighted(10)}
-	tValidator := &txValidator{vcs, &validator.MockVsccValidator{}}
+	tValidator := &txValidator{support: vcs, vscc: &validator.MockVsccValidator{}, metrics: newValidatorMetrics("TestLedger")}
 
 	// Create simple endorsement transaction
 	payload := &common.Payload{
@@ -8,15 +8,19 @@ package txvalidator
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/configtx"
 	commonerrors "github.com/hyperledger/fabric/common/errors"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
 	"github.com/hyperledger/fabric/core/common/validation"
+	"github.com/hyperledger/fabric/core/common/validation/statebased"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	ledgerUtil "github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/protos/common"
@@ -65,6 +69,8 @@ type Validator interface {
 type txValidator struct {
 	support Support
 	vscc    vsccValidator
+	vpmgr   statebased.KeyLevelValidationParameterManager
+	metrics *validatorMetrics
 }
 
 var logger *logging.Logger // package-level logger
@@ -89,12 +95,14 @@ type blockValidationResult struct {
 	txid                 string
 }
 
-// NewTxValidator creates new transactions validator
-func NewTxValidator(support Support, sccp sysccprovider.SystemChaincodeProvider) Validator {
+// NewTxValidator creates new transactions validator for the given channel
+func NewTxValidator(chainID string, support Support, sccp sysccprovider.SystemChaincodeProvider) Validator {
 	// Encapsulates interface implementation
 	return &txValidator{
 		support: support,
-		vscc:    newVSCCValidator(support, sccp)}
+		vscc:    newVSCCValidator(support, sccp),
+		vpmgr:   sccp.GetValidationParameterManager(),
+		metrics: newValidatorMetrics(chainID)}
 }
 
 func (v *txValidator) chainExists(chain string) bool {
@@ -126,6 +134,7 @@ func (v *txValidator) Validate(block *common.Block) error {
 	var err error
 	var errPos int
 
+	startValidation := time.Now()
 	logger.Debug("START Block Validation")
 	defer logger.Debug("END Block Validation")
 	// Initialize trans as valid here, then set invalidation reason code upon invalidation below
@@ -137,6 +146,13 @@ func (v *txValidator) Validate(block *common.Block) error {
 	// array of txids
 	txidArray := make([]string, len(block.Data.Data))
 
+	// the validation parameter dependencies of all transactions
+	// must be known before their parallel validation starts
+	var txsNamespaces []*txNamespaces
+	if v.support.Capabilities().KeyLevelEndorsement() {
+		txsNamespaces = v.extractValidationParameterDependencies(block)
+	}
+
 	results := make(chan *blockValidationResult)
 	go func() {
 		for tIdx, d := range block.Data.Data {
@@ -161,6 +177,10 @@ func (v *txValidator) Validate(block *common.Block) error {
 	for i := 0; i < len(block.Data.Data); i++ {
 		res := <-results
 
+		if txsNamespaces != nil {
+			v.setTxValidationCode(block, res, txsNamespaces[res.tIdx])
+		}
+
 		if res.err != nil {
 			// if there is an error, we buffer its value, wait for
 			// all workers to complete validation and then return
@@ -219,6 +239,13 @@ func (v *txValidator) Validate(block *common.Block) error {
 
 	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = txsfltr
 
+	v.metrics.blockValidationDuration.RecordDuration(time.Since(startValidation))
+	for _, f := range txsfltr {
+		if code := peer.TxValidationCode(f); code != peer.TxValidationCode_VALID {
+			v.metrics.invalidTransactions(code).Inc(1)
+		}
+	}
+
 	return nil
 }
 
@@ -320,7 +347,8 @@ func (v *txValidator) validateTx(req *blockValidationRequest, results chan<- *bl
 		if common.HeaderType(chdr.Type) == common.HeaderType_ENDORSER_TRANSACTION {
 			// Check duplicate transactions
 			txID = chdr.TxId
-			if _, err := v.support.Ledger().GetTransactionByID(txID); err == nil {
+			// a transaction whose block has been pruned is still a duplicate
+			if _, err := v.support.Ledger().GetTransactionByID(txID); err == nil || errors.Cause(err) == blkstorage.ErrPruned {
 				logger.Error("Duplicate transaction found, ", txID, ", skipping")
 				results <- &blockValidationResult{
 					tIdx:           tIdx,
@@ -331,7 +359,9 @@ func (v *txValidator) validateTx(req *blockValidationRequest, results chan<- *bl
 
 			// Validate tx with vscc and policy
 			logger.Debug("Validating transaction vscc tx validate")
-			err, cde := v.vscc.VSCCValidateTx(payload, d)
+			startVSCC := time.Now()
+			err, cde := v.vscc.VSCCValidateTx(tIdx, payload, d, block)
+			v.metrics.vsccValidationDuration.RecordDuration(time.Since(startVSCC))
 			if err != nil {
 				logger.Errorf("VSCCValidateTx for transaction txId = %s returned error: %s", txID, err)
 				switch err.(type) {
@@ -429,6 +459,66 @@ func (v *txValidator) validateTx(req *blockValidationRequest, results chan<- *bl
 	}
 }
 
+// txNamespaces lists the namespaces written
+// by an endorser transaction of a block
+type txNamespaces struct {
+	channel    string
+	namespaces []string
+}
+
+// extractValidationParameterDependencies informs the validation parameter
+// manager about the keys whose validation parameters are changed by the
+// transactions of the block, and returns the namespaces written by each
+// transaction. Transactions that cannot be parsed are skipped here; they
+// are invalidated by their validation.
+func (v *txValidator) extractValidationParameterDependencies(block *common.Block) []*txNamespaces {
+	txsNamespaces := make([]*txNamespaces, len(block.Data.Data))
+	for tIdx, d := range block.Data.Data {
+		txsNamespaces[tIdx] = &txNamespaces{}
+
+		env, err := utils.GetEnvelopeFromBlock(d)
+		if err != nil {
+			continue
+		}
+		payload, err := utils.GetPayload(env)
+		if err != nil || payload.Header == nil {
+			continue
+		}
+		chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+		if err != nil || common.HeaderType(chdr.Type) != common.HeaderType_ENDORSER_TRANSACTION {
+			continue
+		}
+		respPayload, err := utils.GetActionFromEnvelope(d)
+		if err != nil {
+			continue
+		}
+		txRWSet := &rwsetutil.TxRwSet{}
+		if err := txRWSet.FromProtoBytes(respPayload.Results); err != nil {
+			continue
+		}
+
+		txsNamespaces[tIdx].channel = chdr.ChannelId
+		for _, ns := range txRWSet.NsRwSets {
+			v.vpmgr.ExtractValidationParameterDependency(chdr.ChannelId, ns.NameSpace, block.Header.Number, uint64(tIdx), respPayload.Results)
+			txsNamespaces[tIdx].namespaces = append(txsNamespaces[tIdx].namespaces, ns.NameSpace)
+		}
+	}
+	return txsNamespaces
+}
+
+// setTxValidationCode informs the validation parameter manager about the
+// validation code of a transaction, so that the validation of subsequent
+// transactions that depend on it may proceed
+func (v *txValidator) setTxValidationCode(block *common.Block, res *blockValidationResult, txNs *txNamespaces) {
+	vc := res.validationCode
+	if res.err != nil {
+		vc = peer.TxValidationCode_INVALID_OTHER_REASON
+	}
+	for _, ns := range txNs.namespaces {
+		v.vpmgr.SetTxValidationCode(txNs.channel, ns, block.Header.Number, uint64(res.tIdx), vc)
+	}
+}
+
 // generateCCKey generates a unique identifier for chaincode in specific channel
 func (v *txValidator) generateCCKey(ccName, chainID string) string {
 	return fmt.Sprintf("%s/%s", ccName, chainID)
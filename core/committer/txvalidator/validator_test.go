@@ -10,13 +10,16 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sync"
 	"testing"
 
 	"github.com/hyperledger/fabric/common/cauthdsl"
 	ctxt "github.com/hyperledger/fabric/common/configtx/test"
 	commonerrors "github.com/hyperledger/fabric/common/errors"
 	ledger2 "github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/metrics"
 	mockconfig "github.com/hyperledger/fabric/common/mocks/config"
 	"github.com/hyperledger/fabric/common/mocks/scc"
 	"github.com/hyperledger/fabric/common/util"
@@ -34,6 +37,7 @@ import (
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
+	pkgerrors "github.com/pkg/errors"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -61,7 +65,7 @@ func setupLedgerAndValidatorExplicit(t *testing.T, cpb *mockconfig.MockApplicati
 		*semaphore.Weighted
 	}{&mocktxvalidator.Support{LedgerVal: theLedger, ACVal: cpb}, semaphore.NewWeighted(10)}
 	mp := (&scc.MocksccProviderFactory{}).NewSystemChaincodeProvider()
-	theValidator := NewTxValidator(vcs, mp)
+	theValidator := NewTxValidator("TestLedger", vcs, mp)
 
 	return theLedger, theValidator
 }
@@ -203,6 +207,76 @@ func TestInvokeOK(t *testing.T) {
 	assertValid(b, t)
 }
 
+// mockVPManager records the validation parameter
+// dependencies and validation codes it is told about
+type mockVPManager struct {
+	sync.Mutex
+	dependencies []string
+	codes        map[string]peer.TxValidationCode
+}
+
+func (m *mockVPManager) GetValidationParameterForKey(ch, cc, coll, key string, blockNum, txNum uint64) ([]byte, error) {
+	return nil, nil
+}
+
+func (m *mockVPManager) ExtractValidationParameterDependency(ch, cc string, blockNum, txNum uint64, rwset []byte) {
+	m.Lock()
+	defer m.Unlock()
+	m.dependencies = append(m.dependencies, fmt.Sprintf("%s/%s/%d", ch, cc, txNum))
+}
+
+func (m *mockVPManager) SetTxValidationCode(ch, cc string, blockNum, txNum uint64, vc peer.TxValidationCode) {
+	m.Lock()
+	defer m.Unlock()
+	m.codes[fmt.Sprintf("%s/%s/%d", ch, cc, txNum)] = vc
+}
+
+func TestInvokeMetadataWrites(t *testing.T) {
+	ccID := "mycc"
+
+	rwsetBuilder := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder.AddToMetadataWriteSet(ccID, "key", map[string][]byte{"metakey": []byte("metavalue")})
+	rwset, err := rwsetBuilder.GetTxSimulationResults()
+	assert.NoError(t, err)
+	rwsetBytes, err := rwset.GetPubSimulationBytes()
+	assert.NoError(t, err)
+
+	t.Run("Without key-level endorsement capability", func(t *testing.T) {
+		l, v := setupLedgerAndValidator(t)
+		defer ledgermgmt.CleanupTestEnv()
+		defer l.Close()
+
+		putCCInfo(l, ccID, signedByAnyMember([]string{"SampleOrg"}), t)
+
+		tx := getEnv(ccID, nil, rwsetBytes, t)
+		b := &common.Block{Data: &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(tx)}}, Header: &common.BlockHeader{}}
+
+		err := v.Validate(b)
+		assert.NoError(t, err)
+		assertInvalid(b, t, peer.TxValidationCode_ILLEGAL_WRITESET)
+	})
+
+	t.Run("With key-level endorsement capability", func(t *testing.T) {
+		l, v := setupLedgerAndValidatorExplicit(t, &mockconfig.MockApplicationCapabilities{KeyLevelEndorsementRv: true})
+		defer ledgermgmt.CleanupTestEnv()
+		defer l.Close()
+
+		vpmgr := &mockVPManager{codes: map[string]peer.TxValidationCode{}}
+		v.(*txValidator).vpmgr = vpmgr
+
+		putCCInfo(l, ccID, signedByAnyMember([]string{"SampleOrg"}), t)
+
+		tx := getEnv(ccID, nil, rwsetBytes, t)
+		b := &common.Block{Data: &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(tx)}}, Header: &common.BlockHeader{}}
+
+		err := v.Validate(b)
+		assert.NoError(t, err)
+		assertValid(b, t)
+		assert.Equal(t, []string{"testchainid/mycc/0"}, vpmgr.dependencies)
+		assert.Equal(t, map[string]peer.TxValidationCode{"testchainid/mycc/0": peer.TxValidationCode_VALID}, vpmgr.codes)
+	})
+}
+
 func TestInvokeNoRWSet(t *testing.T) {
 	t.Run("Pre-1.2Capability", func(t *testing.T) {
 		l, v := setupLedgerAndValidator(t)
@@ -627,6 +701,14 @@ func (m *mockLedger) CommitWithPvtData(pvtDataAndBlock *ledger.BlockAndPvtData)
 	return nil
 }
 
+func (m *mockLedger) CommitPvtDataOfOldBlocks(blockPvtData []*ledger.BlockPvtData) ([]*ledger.PvtdataHashMismatch, error) {
+	return nil, nil
+}
+
+func (m *mockLedger) GetMissingPvtDataTracker() (ledger.MissingPvtDataTracker, error) {
+	return nil, nil
+}
+
 // PurgePrivateData purges the private data
 func (m *mockLedger) PurgePrivateData(maxBlockNumToRetain uint64) error {
 	return nil
@@ -702,6 +784,16 @@ func (exec *mockQueryExecutor) ExecuteQuery(namespace, query string) (ledger2.Re
 	return args.Get(0).(ledger2.ResultsIterator), args.Error(1)
 }
 
+func (exec *mockQueryExecutor) GetStateRangeScanIteratorWithPagination(namespace, startKey, endKey string, pageSize int32, bookmark string) (ledger.QueryResultsIterator, error) {
+	args := exec.Called(namespace, startKey, endKey, pageSize, bookmark)
+	return args.Get(0).(ledger.QueryResultsIterator), args.Error(1)
+}
+
+func (exec *mockQueryExecutor) ExecuteQueryWithPagination(namespace, query string, pageSize int32, bookmark string) (ledger.QueryResultsIterator, error) {
+	args := exec.Called(namespace, query, pageSize, bookmark)
+	return args.Get(0).(ledger.QueryResultsIterator), args.Error(1)
+}
+
 func (exec *mockQueryExecutor) GetPrivateData(namespace, collection, key string) ([]byte, error) {
 	args := exec.Called(namespace, collection, key)
 	return args.Get(0).([]byte), args.Error(1)
@@ -747,7 +839,7 @@ func TestLedgerIsNoAvailable(t *testing.T) {
 		*semaphore.Weighted
 	}{&mocktxvalidator.Support{LedgerVal: theLedger, ACVal: &mockconfig.MockApplicationCapabilities{}}, semaphore.NewWeighted(10)}
 	mp := (&scc.MocksccProviderFactory{}).NewSystemChaincodeProvider()
-	validator := NewTxValidator(vcs, mp)
+	validator := NewTxValidator("TestLedger", vcs, mp)
 
 	ccID := "mycc"
 	tx := getEnv(ccID, nil, createRWset(t, ccID), t)
@@ -769,6 +861,30 @@ func TestLedgerIsNoAvailable(t *testing.T) {
 	assertion.NotNil(err.(*commonerrors.VSCCInfoLookupFailureError))
 }
 
+// TestDuplicateTxInPrunedBlock checks that a transaction whose ID belongs to
+// a block that has been pruned is still flagged as a duplicate, even when the
+// ledger wraps the pruned error
+func TestDuplicateTxInPrunedBlock(t *testing.T) {
+	theLedger := new(mockLedger)
+	vcs := struct {
+		*mocktxvalidator.Support
+		*semaphore.Weighted
+	}{&mocktxvalidator.Support{LedgerVal: theLedger, ACVal: &mockconfig.MockApplicationCapabilities{}}, semaphore.NewWeighted(10)}
+	mp := (&scc.MocksccProviderFactory{}).NewSystemChaincodeProvider()
+	validator := NewTxValidator("TestLedger", vcs, mp)
+
+	ccID := "mycc"
+	tx := getEnv(ccID, nil, createRWset(t, ccID), t)
+
+	theLedger.On("GetTransactionByID", mock.Anything).Return((*peer.ProcessedTransaction)(nil), pkgerrors.Wrap(blkstorage.ErrPruned, "could not retrieve transaction"))
+
+	b := &common.Block{Data: &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(tx)}}}
+
+	err := validator.Validate(b)
+	assert.NoError(t, err)
+	assertInvalid(b, t, peer.TxValidationCode_DUPLICATE_TXID)
+}
+
 func TestValidationInvalidEndorsing(t *testing.T) {
 	theLedger := new(mockLedger)
 	vcs := struct {
@@ -776,7 +892,7 @@ func TestValidationInvalidEndorsing(t *testing.T) {
 		*semaphore.Weighted
 	}{&mocktxvalidator.Support{LedgerVal: theLedger, ACVal: &mockconfig.MockApplicationCapabilities{}}, semaphore.NewWeighted(10)}
 	mp := (&scc.MocksccProviderFactory{}).NewSystemChaincodeProvider()
-	validator := NewTxValidator(vcs, mp)
+	validator := NewTxValidator("TestLedger", vcs, mp)
 
 	ccID := "mycc"
 	tx := getEnv(ccID, nil, createRWset(t, ccID), t)
@@ -810,6 +926,58 @@ func TestValidationInvalidEndorsing(t *testing.T) {
 	assertInvalid(b, t, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE)
 }
 
+func TestValidationMetrics(t *testing.T) {
+	ts, restore := metrics.UseTestScope()
+	defer restore()
+
+	theLedger := new(mockLedger)
+	vcs := struct {
+		*mocktxvalidator.Support
+		*semaphore.Weighted
+	}{&mocktxvalidator.Support{LedgerVal: theLedger, ACVal: &mockconfig.MockApplicationCapabilities{}}, semaphore.NewWeighted(10)}
+	mp := (&scc.MocksccProviderFactory{}).NewSystemChaincodeProvider()
+	validator := NewTxValidator("TestLedger", vcs, mp)
+
+	ccID := "mycc"
+	tx := getEnv(ccID, nil, createRWset(t, ccID), t)
+
+	theLedger.On("GetTransactionByID", mock.Anything).Return(&peer.ProcessedTransaction{}, errors.New("Cannot find the transaction"))
+
+	cd := &ccp.ChaincodeData{
+		Name:    ccID,
+		Version: ccVersion,
+		Vscc:    "vscc",
+		Policy:  signedByAnyMember([]string{"SampleOrg"}),
+	}
+
+	queryExecutor := new(mockQueryExecutor)
+	queryExecutor.On("GetState", "lscc", ccID).Return(utils.MarshalOrPanic(cd), nil)
+	theLedger.On("NewQueryExecutor", mock.Anything).Return(queryExecutor, nil)
+
+	b := &common.Block{Data: &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(tx)}}}
+
+	c := executeChaincodeProvider.getCallback()
+	executeChaincodeProvider.setCallback(func() (*peer.Response, *peer.ChaincodeEvent, error) {
+		return &peer.Response{Status: shim.ERROR}, nil, nil
+	})
+	err := validator.Validate(b)
+	executeChaincodeProvider.setCallback(c)
+	assert.NoError(t, err)
+	assertInvalid(b, t, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE)
+
+	tags := map[string]string{"channel": "TestLedger"}
+	assert.Len(t, ts.HistogramValues("validator.block_validation_duration", tags), 1)
+	assert.Len(t, ts.HistogramValues("validator.vscc_validation_duration", tags), 1)
+	assert.Equal(t, int64(1), ts.CounterValue("validator.invalid_transactions", map[string]string{
+		"channel":         "TestLedger",
+		"validation_code": peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE.String(),
+	}))
+	assert.Equal(t, int64(0), ts.CounterValue("validator.invalid_transactions", map[string]string{
+		"channel":         "TestLedger",
+		"validation_code": peer.TxValidationCode_DUPLICATE_TXID.String(),
+	}))
+}
+
 type ccResultCallback func() (*peer.Response, *peer.ChaincodeEvent, error)
 
 type ccExecuteChaincode struct {
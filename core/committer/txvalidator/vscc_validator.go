@@ -11,6 +11,7 @@ package txvalidator
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/cauthdsl"
@@ -30,7 +31,7 @@ import (
 // and vscc execution, in order to increase
 // testability of txValidator
 type vsccValidator interface {
-	VSCCValidateTx(payload *common.Payload, envBytes []byte) (error, peer.TxValidationCode)
+	VSCCValidateTx(seq int, payload *common.Payload, envBytes []byte, block *common.Block) (error, peer.TxValidationCode)
 }
 
 // vsccValidator implementation which used to call
@@ -51,7 +52,7 @@ func newVSCCValidator(support Support, sccp sysccprovider.SystemChaincodeProvide
 }
 
 // VSCCValidateTx executes vscc validation for transaction
-func (v *vsccValidatorImpl) VSCCValidateTx(payload *common.Payload, envBytes []byte) (error, peer.TxValidationCode) {
+func (v *vsccValidatorImpl) VSCCValidateTx(seq int, payload *common.Payload, envBytes []byte, block *common.Block) (error, peer.TxValidationCode) {
 	logger.Debugf("VSCCValidateTx starts for bytes %p", envBytes)
 	defer logger.Debugf("VSCCValidateTx completes env bytes %p", envBytes)
 
@@ -114,6 +115,13 @@ func (v *vsccValidatorImpl) VSCCValidateTx(payload *common.Payload, envBytes []b
 		return err, peer.TxValidationCode_INVALID_OTHER_REASON
 	}
 
+	// metadata writes are only understood by peers that
+	// support key-level endorsement policies
+	if !v.support.Capabilities().KeyLevelEndorsement() && txWritesMetadata(txRWSet) {
+		return errors.New("metadata writes are not supported without the key-level endorsement capability"),
+			peer.TxValidationCode_ILLEGAL_WRITESET
+	}
+
 	var wrNamespace []string
 	alwaysEnforceOriginalNamespace := v.support.Capabilities().V1_2Validation()
 	if alwaysEnforceOriginalNamespace {
@@ -200,7 +208,7 @@ func (v *vsccValidatorImpl) VSCCValidateTx(payload *common.Payload, envBytes []b
 			}
 
 			// do VSCC validation
-			if err = v.VSCCValidateTxForCC(envBytes, chdr.TxId, chdr.ChannelId, vscc.ChaincodeName, vscc.ChaincodeVersion, policy); err != nil {
+			if err = v.VSCCValidateTxForCC(envBytes, chdr.TxId, chdr.ChannelId, vscc.ChaincodeName, vscc.ChaincodeVersion, ns, block.GetHeader().GetNumber(), uint64(seq), policy); err != nil {
 				switch err.(type) {
 				case *commonerrors.VSCCEndorsementPolicyError:
 					return err, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE
@@ -231,7 +239,7 @@ func (v *vsccValidatorImpl) VSCCValidateTx(payload *common.Payload, envBytes []b
 		// currently, VSCC does custom validation for LSCC only; if an hlf
 		// user creates a new system chaincode which is invokable from the outside
 		// they have to modify VSCC to provide appropriate validation
-		if err = v.VSCCValidateTxForCC(envBytes, chdr.TxId, vscc.ChainID, vscc.ChaincodeName, vscc.ChaincodeVersion, policy); err != nil {
+		if err = v.VSCCValidateTxForCC(envBytes, chdr.TxId, vscc.ChainID, vscc.ChaincodeName, vscc.ChaincodeVersion, ccID, block.GetHeader().GetNumber(), uint64(seq), policy); err != nil {
 			switch err.(type) {
 			case *commonerrors.VSCCEndorsementPolicyError:
 				return err, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE
@@ -244,7 +252,7 @@ func (v *vsccValidatorImpl) VSCCValidateTx(payload *common.Payload, envBytes []b
 	return nil, peer.TxValidationCode_VALID
 }
 
-func (v *vsccValidatorImpl) VSCCValidateTxForCC(envBytes []byte, txid, chid, vsccName, vsccVer string, policy []byte) error {
+func (v *vsccValidatorImpl) VSCCValidateTxForCC(envBytes []byte, txid, chid, vsccName, vsccVer, ns string, blockNum, txNum uint64, policy []byte) error {
 	logger.Debugf("VSCCValidateTxForCC starts for envbytes %p", envBytes)
 	defer logger.Debugf("VSCCValidateTxForCC completes for envbytes %p", envBytes)
 	ctxt, txsim, err := v.ccprovider.GetContext(v.support.Ledger(), txid)
@@ -260,6 +268,12 @@ func (v *vsccValidatorImpl) VSCCValidateTxForCC(envBytes []byte, txid, chid, vsc
 	// args[1] - serialized Envelope
 	// args[2] - serialized policy
 	args := [][]byte{[]byte(""), envBytes, policy}
+	if v.support.Capabilities().KeyLevelEndorsement() {
+		// args[3] - namespace being validated
+		// args[4] - block number
+		// args[5] - position of the transaction in the block
+		args = append(args, []byte(ns), []byte(strconv.FormatUint(blockNum, 10)), []byte(strconv.FormatUint(txNum, 10)))
+	}
 
 	// get context to invoke VSCC
 	vscctxid := coreUtil.GenerateUUID()
@@ -365,7 +379,7 @@ func (v *vsccValidatorImpl) GetInfoForValidate(chdr *common.ChannelHeader, ccID
 // performs a ledger write
 func (v *vsccValidatorImpl) txWritesToNamespace(ns *rwsetutil.NsRwSet) bool {
 	// check for public writes first
-	if ns.KvRwSet != nil && len(ns.KvRwSet.Writes) > 0 {
+	if ns.KvRwSet != nil && (len(ns.KvRwSet.Writes) > 0 || len(ns.KvRwSet.MetadataWrites) > 0) {
 		return true
 	}
 
@@ -383,3 +397,14 @@ func (v *vsccValidatorImpl) txWritesToNamespace(ns *rwsetutil.NsRwSet) bool {
 
 	return false
 }
+
+// txWritesMetadata returns true if the supplied TxRwSet
+// writes the metadata of any key
+func txWritesMetadata(txRWSet *rwsetutil.TxRwSet) bool {
+	for _, ns := range txRWSet.NsRwSets {
+		if ns.KvRwSet != nil && len(ns.KvRwSet.MetadataWrites) > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -116,6 +116,13 @@ func (sc *SimpleCollection) Setup(collectionConfig *common.StaticCollectionConfi
 				return errors.Wrap(err, "Could not unmarshal OrganizationUnit from principal")
 			}
 			sc.memberOrgs = append(sc.memberOrgs, OU.MspIdentifier)
+		case m.MSPPrincipal_IDEMIX_ATTRIBUTES:
+			attributes := &m.IdemixAttributes{}
+			err := proto.Unmarshal(principal.Principal, attributes)
+			if err != nil {
+				return errors.Wrap(err, "Could not unmarshal IdemixAttributes from principal")
+			}
+			sc.memberOrgs = append(sc.memberOrgs, attributes.MspIdentifier)
 		default:
 			return errors.New(fmt.Sprintf("Invalid principal type %d", int32(principal.PrincipalClassification)))
 		}
@@ -19,6 +19,7 @@ package sysccprovider
 import (
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/core/common/validation/statebased"
 	"github.com/hyperledger/fabric/core/ledger"
 )
 
@@ -51,6 +52,11 @@ type SystemChaincodeProvider interface {
 	// Returns the policy manager associated to the passed channel
 	// and whether the policy manager exists
 	PolicyManager(channelID string) (policies.Manager, bool)
+
+	// GetValidationParameterManager returns the manager of the
+	// key-level validation parameters, which is shared between
+	// the committer and the validation system chaincode
+	GetValidationParameterManager() statebased.KeyLevelValidationParameterManager
 }
 
 // ChaincodeInstance is unique identifier of chaincode instance
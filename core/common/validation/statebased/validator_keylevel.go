@@ -0,0 +1,114 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statebased
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+)
+
+// PolicyEvaluator evaluates policies
+type PolicyEvaluator interface {
+	// Evaluate takes a set of SignedData and evaluates whether this set of
+	// signatures satisfies the policy supplied in its marshalled form
+	Evaluate(policyBytes []byte, signatureSet []*common.SignedData) error
+}
+
+// KeyLevelValidator implements the StateBasedValidator interface: the
+// writes of a transaction to keys that carry a validation parameter are
+// validated against it, while the chaincode-level endorsement policy is
+// used for all other writes
+type KeyLevelValidator struct {
+	vpmgr KeyLevelValidationParameterManager
+	pe    PolicyEvaluator
+}
+
+// NewKeyLevelValidator returns a new key-level validator that retrieves
+// validation parameters from the supplied manager and evaluates them
+// with the supplied policy evaluator
+func NewKeyLevelValidator(pe PolicyEvaluator, vpmgr KeyLevelValidationParameterManager) *KeyLevelValidator {
+	return &KeyLevelValidator{
+		vpmgr: vpmgr,
+		pe:    pe,
+	}
+}
+
+// PreValidate records the validation parameter dependencies of the rwset
+func (klv *KeyLevelValidator) PreValidate(ch, cc string, blockNum, txNum uint64, rwset []byte) error {
+	klv.vpmgr.ExtractValidationParameterDependency(ch, cc, blockNum, txNum, rwset)
+	return nil
+}
+
+// Validate checks that the endorsements satisfy the validation parameter
+// of every key of namespace cc written by the rwset. The chaincode-level
+// endorsement policy ep must be satisfied if the rwset writes a key that
+// has no validation parameter, or if it writes no key at all.
+func (klv *KeyLevelValidator) Validate(ch, cc string, blockNum, txNum uint64, rwsetBytes, prp, ep []byte, endorsements []*peer.Endorsement) error {
+	rwset := &rwsetutil.TxRwSet{}
+	if err := rwset.FromProtoBytes(rwsetBytes); err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("txRWSet.FromProtoBytes failed on tx (%d,%d)", blockNum, txNum))
+	}
+
+	signatureSet := []*common.SignedData{}
+	for _, endorsement := range endorsements {
+		signatureSet = append(signatureSet, &common.SignedData{
+			// set the data that is signed; concatenation of proposal response bytes and endorser ID
+			Data: append(append([]byte{}, prp...), endorsement.Endorser...),
+			// set the identity that signs the message: it's the endorser
+			Identity: endorsement.Endorser,
+			// set the signature
+			Signature: endorsement.Signature,
+		})
+	}
+
+	keys := map[string]struct{}{}
+	for _, nsRWSet := range rwset.NsRwSets {
+		if nsRWSet.NameSpace != cc {
+			continue
+		}
+		for _, w := range nsRWSet.KvRwSet.Writes {
+			keys[w.Key] = struct{}{}
+		}
+		for _, mw := range nsRWSet.KvRwSet.MetadataWrites {
+			keys[mw.Key] = struct{}{}
+		}
+	}
+
+	ccPolicyRequired := len(keys) == 0
+	for key := range keys {
+		vp, err := klv.vpmgr.GetValidationParameterForKey(ch, cc, "", key, blockNum, txNum)
+		if err != nil {
+			return err
+		}
+		if vp == nil {
+			ccPolicyRequired = true
+			continue
+		}
+
+		if err := klv.pe.Evaluate(vp, signatureSet); err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("validation of endorsement policy for key %s in namespace %s failed", key, cc))
+		}
+	}
+
+	if ccPolicyRequired {
+		if err := klv.pe.Evaluate(ep, signatureSet); err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("validation of endorsement policy for chaincode %s failed", cc))
+		}
+	}
+
+	return nil
+}
+
+// PostValidate records the validation code of the transaction
+func (klv *KeyLevelValidator) PostValidate(ch, cc string, blockNum, txNum uint64, vc peer.TxValidationCode) error {
+	klv.vpmgr.SetTxValidationCode(ch, cc, blockNum, txNum, vc)
+	return nil
+}
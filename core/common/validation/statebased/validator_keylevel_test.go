@@ -0,0 +1,109 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statebased
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockPolicyEvaluator accepts the signature set only for the policies
+// that it has been told are satisfied, and records the policies evaluated
+type mockPolicyEvaluator struct {
+	satisfied [][]byte
+	evaluated [][]byte
+}
+
+func (m *mockPolicyEvaluator) Evaluate(policyBytes []byte, signatureSet []*common.SignedData) error {
+	m.evaluated = append(m.evaluated, policyBytes)
+	for _, p := range m.satisfied {
+		if bytes.Equal(p, policyBytes) {
+			return nil
+		}
+	}
+	return errors.Errorf("policy %s not satisfied", policyBytes)
+}
+
+func newTestKeyLevelValidator(satisfied ...string) (*KeyLevelValidator, *mockPolicyEvaluator, *mockQEProvider) {
+	qep := newMockQEProvider()
+	pe := &mockPolicyEvaluator{}
+	for _, p := range satisfied {
+		pe.satisfied = append(pe.satisfied, []byte(p))
+	}
+	return NewKeyLevelValidator(pe, NewKeyLevelValidationParameterManager(qep)), pe, qep
+}
+
+func TestKeyLevelValidationWithoutValidationParameters(t *testing.T) {
+	v, pe, _ := newTestKeyLevelValidator("CCEP")
+	endorsements := []*peer.Endorsement{{Endorser: []byte("endorser"), Signature: []byte("signature")}}
+
+	rws := rwsetBytes(t, "cc", map[string][]byte{"key": []byte("value")}, nil)
+	err := v.Validate("ch", "cc", 1, 0, rws, []byte("prp"), []byte("CCEP"), endorsements)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("CCEP")}, pe.evaluated)
+
+	err = v.Validate("ch", "cc", 1, 0, rws, []byte("prp"), []byte("otherEP"), endorsements)
+	assert.EqualError(t, err, "validation of endorsement policy for chaincode cc failed: policy otherEP not satisfied")
+
+	// a transaction that writes nothing in the namespace is checked against the chaincode-level policy
+	rws = rwsetBytes(t, "othercc", map[string][]byte{"key": []byte("value")}, nil)
+	err = v.Validate("ch", "cc", 1, 0, rws, []byte("prp"), []byte("CCEP"), endorsements)
+	assert.NoError(t, err)
+
+	err = v.Validate("ch", "cc", 1, 0, []byte("barf"), []byte("prp"), []byte("CCEP"), endorsements)
+	assert.Error(t, err)
+}
+
+func TestKeyLevelValidationWithValidationParameters(t *testing.T) {
+	v, pe, qep := newTestKeyLevelValidator("SBEP")
+	qep.setValidationParameter("cc", "key", []byte("SBEP"))
+	endorsements := []*peer.Endorsement{{Endorser: []byte("endorser"), Signature: []byte("signature")}}
+
+	// the key-level policy overrides the chaincode-level policy
+	rws := rwsetBytes(t, "cc", map[string][]byte{"key": []byte("value")}, nil)
+	err := v.Validate("ch", "cc", 1, 0, rws, []byte("prp"), []byte("CCEP"), endorsements)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("SBEP")}, pe.evaluated)
+
+	// metadata writes are validated against the key-level policy as well
+	rws = rwsetBytes(t, "cc", nil, map[string]map[string][]byte{"key": {peer.MetaDataKeys_VALIDATION_PARAMETER.String(): []byte("newEP")}})
+	err = v.Validate("ch", "cc", 1, 0, rws, []byte("prp"), []byte("CCEP"), endorsements)
+	assert.NoError(t, err)
+
+	// writing a key without a key-level policy requires the chaincode-level policy
+	rws = rwsetBytes(t, "cc", map[string][]byte{"key": []byte("value"), "otherkey": []byte("value")}, nil)
+	err = v.Validate("ch", "cc", 1, 0, rws, []byte("prp"), []byte("CCEP"), endorsements)
+	assert.EqualError(t, err, "validation of endorsement policy for chaincode cc failed: policy CCEP not satisfied")
+
+	qep.setValidationParameter("cc", "key", []byte("otherEP"))
+	rws = rwsetBytes(t, "cc", map[string][]byte{"key": []byte("value")}, nil)
+	err = v.Validate("ch", "cc", 1, 0, rws, []byte("prp"), []byte("SBEP"), endorsements)
+	assert.EqualError(t, err, "validation of endorsement policy for key key in namespace cc failed: policy otherEP not satisfied")
+}
+
+func TestKeyLevelValidationParameterUpdated(t *testing.T) {
+	v, _, qep := newTestKeyLevelValidator("SBEP")
+	qep.setValidationParameter("cc", "key", []byte("SBEP"))
+	endorsements := []*peer.Endorsement{{Endorser: []byte("endorser"), Signature: []byte("signature")}}
+
+	rws0 := rwsetBytes(t, "cc", nil, map[string]map[string][]byte{"key": {peer.MetaDataKeys_VALIDATION_PARAMETER.String(): []byte("newEP")}})
+	rws1 := rwsetBytes(t, "cc", map[string][]byte{"key": []byte("value")}, nil)
+	assert.NoError(t, v.PreValidate("ch", "cc", 1, 0, rws0))
+	assert.NoError(t, v.PreValidate("ch", "cc", 1, 1, rws1))
+
+	err := v.Validate("ch", "cc", 1, 0, rws0, []byte("prp"), []byte("CCEP"), endorsements)
+	assert.NoError(t, err)
+	assert.NoError(t, v.PostValidate("ch", "cc", 1, 0, peer.TxValidationCode_VALID))
+
+	err = v.Validate("ch", "cc", 1, 1, rws1, []byte("prp"), []byte("CCEP"), endorsements)
+	assert.Equal(t, &ValidationParameterUpdatedErr{Key: "key", Height: 1}, err)
+}
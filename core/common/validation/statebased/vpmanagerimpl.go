@@ -0,0 +1,202 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statebased
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("statebased")
+
+// QueryExecutorProvider returns query executors
+// on the committed state of a channel's ledger
+type QueryExecutorProvider interface {
+	// GetQueryExecutorForLedger returns a query executor for the
+	// ledger of the supplied channel
+	GetQueryExecutorForLedger(cid string) (ledger.QueryExecutor, error)
+}
+
+// ledgerKeyID identifies a key in the KVS
+type ledgerKeyID struct {
+	cc   string
+	coll string
+	key  string
+}
+
+// txID identifies the validation of a transaction for a chaincode
+type txID struct {
+	cc    string
+	txNum uint64
+}
+
+// blockContext tracks the dependencies on validation
+// parameters for the transactions of a single block
+type blockContext struct {
+	mutex    sync.Mutex
+	cond     *sync.Cond
+	blockNum uint64
+	// writers lists, for each key, the transactions
+	// that change the validation parameters of the key
+	writers map[ledgerKeyID]map[uint64]struct{}
+	// results stores the validation code of the transactions
+	results map[txID]peer.TxValidationCode
+}
+
+func newBlockContext(blockNum uint64) *blockContext {
+	bc := &blockContext{
+		blockNum: blockNum,
+		writers:  make(map[ledgerKeyID]map[uint64]struct{}),
+		results:  make(map[txID]peer.TxValidationCode),
+	}
+	bc.cond = sync.NewCond(&bc.mutex)
+	return bc
+}
+
+// KeyLevelValidationParameterManagerImpl implements the
+// KeyLevelValidationParameterManager interface. Validation
+// parameters are read from the metadata of the keys in the
+// committed state of the ledger; transactions in the block
+// being validated that change the validation parameters of a
+// key make the validation of subsequent transactions that
+// depend on that key wait until their validation code is known.
+type KeyLevelValidationParameterManagerImpl struct {
+	qeProvider QueryExecutorProvider
+
+	mutex         sync.Mutex
+	blockContexts map[string]*blockContext
+}
+
+// NewKeyLevelValidationParameterManager returns a new instance of the
+// validation parameter manager that reads the committed state
+// through the supplied query executor provider
+func NewKeyLevelValidationParameterManager(qeProvider QueryExecutorProvider) *KeyLevelValidationParameterManagerImpl {
+	return &KeyLevelValidationParameterManagerImpl{
+		qeProvider:    qeProvider,
+		blockContexts: make(map[string]*blockContext),
+	}
+}
+
+// getBlockContext returns the dependency tracking context for the
+// supplied block of the supplied channel. Blocks on a channel are
+// validated one after the other, hence the context of the previous
+// block is discarded as soon as a new block number is seen.
+func (m *KeyLevelValidationParameterManagerImpl) getBlockContext(ch string, blockNum uint64) *blockContext {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	bc, ok := m.blockContexts[ch]
+	if !ok || bc.blockNum != blockNum {
+		bc = newBlockContext(blockNum)
+		m.blockContexts[ch] = bc
+	}
+	return bc
+}
+
+// ExtractValidationParameterDependency records the keys of namespace
+// cc whose validation parameters are changed by the supplied rwset, that
+// is, keys whose metadata is written and keys that are deleted
+func (m *KeyLevelValidationParameterManagerImpl) ExtractValidationParameterDependency(ch, cc string, blockNum, txNum uint64, rwsetBytes []byte) {
+	rwset := &rwsetutil.TxRwSet{}
+	if err := rwset.FromProtoBytes(rwsetBytes); err != nil {
+		// the transaction will be invalidated during its validation
+		logger.Warningf("could not unmarshal rwset for tx %d in block %d: %s", txNum, blockNum, err)
+		return
+	}
+
+	bc := m.getBlockContext(ch, blockNum)
+	bc.mutex.Lock()
+	defer bc.mutex.Unlock()
+
+	addDependency := func(key string) {
+		kid := ledgerKeyID{cc: cc, key: key}
+		if bc.writers[kid] == nil {
+			bc.writers[kid] = make(map[uint64]struct{})
+		}
+		bc.writers[kid][txNum] = struct{}{}
+	}
+
+	for _, nsRWSet := range rwset.NsRwSets {
+		if nsRWSet.NameSpace != cc {
+			continue
+		}
+		for _, mw := range nsRWSet.KvRwSet.MetadataWrites {
+			addDependency(mw.Key)
+		}
+		for _, w := range nsRWSet.KvRwSet.Writes {
+			if w.IsDelete {
+				addDependency(w.Key)
+			}
+		}
+	}
+}
+
+// GetValidationParameterForKey returns the validation parameter of the
+// supplied key as committed in the ledger, or nil if it has none. It blocks
+// until the validation codes of all transactions preceding txNum in the block
+// that change the validation parameters of the key are known, and returns
+// ValidationParameterUpdatedErr if any of them is valid.
+func (m *KeyLevelValidationParameterManagerImpl) GetValidationParameterForKey(ch, cc, coll, key string, blockNum, txNum uint64) ([]byte, error) {
+	bc := m.getBlockContext(ch, blockNum)
+
+	bc.mutex.Lock()
+	var precedingWriters []uint64
+	for writer := range bc.writers[ledgerKeyID{cc: cc, coll: coll, key: key}] {
+		if writer < txNum {
+			precedingWriters = append(precedingWriters, writer)
+		}
+	}
+
+	for _, writer := range precedingWriters {
+		vc, ok := bc.results[txID{cc: cc, txNum: writer}]
+		for !ok {
+			bc.cond.Wait()
+			vc, ok = bc.results[txID{cc: cc, txNum: writer}]
+		}
+
+		if vc == peer.TxValidationCode_VALID {
+			bc.mutex.Unlock()
+			return nil, &ValidationParameterUpdatedErr{Key: key, Height: blockNum}
+		}
+	}
+	bc.mutex.Unlock()
+
+	qe, err := m.qeProvider.GetQueryExecutorForLedger(ch)
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not retrieve ledger query executor")
+	}
+	defer qe.Done()
+
+	var metadata map[string][]byte
+	if coll == "" {
+		metadata, err = qe.GetStateMetadata(cc, key)
+	} else {
+		metadata, err = qe.GetPrivateMetadata(cc, coll, key)
+	}
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not retrieve metadata for "+key)
+	}
+
+	return metadata[peer.MetaDataKeys_VALIDATION_PARAMETER.String()], nil
+}
+
+// SetTxValidationCode records the validation code of the supplied
+// transaction and wakes up the validations waiting for it
+func (m *KeyLevelValidationParameterManagerImpl) SetTxValidationCode(ch, cc string, blockNum, txNum uint64, vc peer.TxValidationCode) {
+	bc := m.getBlockContext(ch, blockNum)
+
+	bc.mutex.Lock()
+	defer bc.mutex.Unlock()
+
+	bc.results[txID{cc: cc, txNum: txNum}] = vc
+	bc.cond.Broadcast()
+}
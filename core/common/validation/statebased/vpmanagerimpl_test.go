@@ -0,0 +1,150 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statebased
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	lm "github.com/hyperledger/fabric/common/mocks/ledger"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockQueryExecutor struct {
+	*lm.MockQueryExecutor
+	metadata map[string]map[string][]byte
+}
+
+func (m *mockQueryExecutor) GetStateMetadata(namespace, key string) (map[string][]byte, error) {
+	return m.metadata[namespace+"/"+key], nil
+}
+
+type mockQEProvider struct {
+	qe  *mockQueryExecutor
+	err error
+}
+
+func (m *mockQEProvider) GetQueryExecutorForLedger(cid string) (ledger.QueryExecutor, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.qe, nil
+}
+
+func newMockQEProvider() *mockQEProvider {
+	return &mockQEProvider{
+		qe: &mockQueryExecutor{
+			MockQueryExecutor: lm.NewMockQueryExecutor(nil),
+			metadata:          map[string]map[string][]byte{},
+		},
+	}
+}
+
+func (m *mockQEProvider) setValidationParameter(cc, key string, vp []byte) {
+	m.qe.metadata[cc+"/"+key] = map[string][]byte{peer.MetaDataKeys_VALIDATION_PARAMETER.String(): vp}
+}
+
+func rwsetBytes(t *testing.T, cc string, writes map[string][]byte, metadataWrites map[string]map[string][]byte) []byte {
+	rwsb := rwsetutil.NewRWSetBuilder()
+	for key, value := range writes {
+		rwsb.AddToWriteSet(cc, key, value)
+	}
+	for key, metadata := range metadataWrites {
+		rwsb.AddToMetadataWriteSet(cc, key, metadata)
+	}
+	rws, err := rwsb.GetTxSimulationResults()
+	assert.NoError(t, err)
+	b, err := proto.Marshal(rws.PubSimulationResults)
+	assert.NoError(t, err)
+	return b
+}
+
+func TestValidationParameterFromLedger(t *testing.T) {
+	qep := newMockQEProvider()
+	qep.setValidationParameter("cc", "key", []byte("EP"))
+	vpmgr := NewKeyLevelValidationParameterManager(qep)
+
+	vp, err := vpmgr.GetValidationParameterForKey("ch", "cc", "", "key", 1, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("EP"), vp)
+
+	vp, err = vpmgr.GetValidationParameterForKey("ch", "cc", "", "otherkey", 1, 0)
+	assert.NoError(t, err)
+	assert.Nil(t, vp)
+
+	qep.err = errors.New("ledger unavailable")
+	_, err = vpmgr.GetValidationParameterForKey("ch", "cc", "", "key", 1, 0)
+	assert.EqualError(t, err, "could not retrieve ledger query executor: ledger unavailable")
+}
+
+func TestValidationParameterDependency(t *testing.T) {
+	qep := newMockQEProvider()
+	qep.setValidationParameter("cc", "key", []byte("EP"))
+	vpmgr := NewKeyLevelValidationParameterManager(qep)
+
+	// tx 0 updates the validation parameter of the key, tx 2 deletes the key
+	rws := rwsetBytes(t, "cc", nil, map[string]map[string][]byte{"key": {peer.MetaDataKeys_VALIDATION_PARAMETER.String(): []byte("newEP")}})
+	vpmgr.ExtractValidationParameterDependency("ch", "cc", 1, 0, rws)
+	rws = rwsetBytes(t, "cc", map[string][]byte{"key": nil}, nil)
+	vpmgr.ExtractValidationParameterDependency("ch", "cc", 1, 2, rws)
+	// a value-only write does not change the validation parameter
+	rws = rwsetBytes(t, "cc", map[string][]byte{"key": []byte("value")}, nil)
+	vpmgr.ExtractValidationParameterDependency("ch", "cc", 1, 3, rws)
+
+	// tx 0 does not depend on itself
+	vp, err := vpmgr.GetValidationParameterForKey("ch", "cc", "", "key", 1, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("EP"), vp)
+
+	// tx 1 waits for the validation of tx 0
+	done := make(chan error)
+	go func() {
+		_, err := vpmgr.GetValidationParameterForKey("ch", "cc", "", "key", 1, 1)
+		done <- err
+	}()
+	select {
+	case <-done:
+		t.Fatal("validation parameter returned before the validation of tx 0")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	vpmgr.SetTxValidationCode("ch", "cc", 1, 0, peer.TxValidationCode_VALID)
+	err = <-done
+	assert.Equal(t, &ValidationParameterUpdatedErr{Key: "key", Height: 1}, err)
+
+	// the same holds for the next block if tx 0 turns out to be invalid
+	rws = rwsetBytes(t, "cc", nil, map[string]map[string][]byte{"key": {peer.MetaDataKeys_VALIDATION_PARAMETER.String(): []byte("newEP")}})
+	vpmgr.ExtractValidationParameterDependency("ch", "cc", 2, 0, rws)
+	go func() {
+		_, err := vpmgr.GetValidationParameterForKey("ch", "cc", "", "key", 2, 1)
+		done <- err
+	}()
+	vpmgr.SetTxValidationCode("ch", "cc", 2, 0, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE)
+	assert.NoError(t, <-done)
+
+	// keys of other namespaces are unaffected
+	vp, err = vpmgr.GetValidationParameterForKey("ch", "othercc", "", "key", 2, 1)
+	assert.NoError(t, err)
+	assert.Nil(t, vp)
+}
+
+func TestValidationParameterDependencyBadRWSet(t *testing.T) {
+	qep := newMockQEProvider()
+	qep.setValidationParameter("cc", "key", []byte("EP"))
+	vpmgr := NewKeyLevelValidationParameterManager(qep)
+
+	// an unparsable rwset introduces no dependency
+	vpmgr.ExtractValidationParameterDependency("ch", "cc", 1, 0, []byte("barf"))
+	vp, err := vpmgr.GetValidationParameterForKey("ch", "cc", "", "key", 1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("EP"), vp)
+}
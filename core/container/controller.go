@@ -56,12 +56,23 @@ func NewVMController() *VMController {
 
 //constants for supported containers
 const (
-	DOCKER = "Docker"
-	SYSTEM = "System"
+	DOCKER   = "Docker"
+	SYSTEM   = "System"
+	EXTERNAL = "External"
 )
 
+// RegisterVMProvider registers the provider of the VMs of the given type,
+// replacing the provider previously registered for that type, if any
+func (vmc *VMController) RegisterVMProvider(typ string, provider VMProvider) {
+	vmc.Lock()
+	defer vmc.Unlock()
+	vmc.vmProviders[typ] = provider
+}
+
 func (vmc *VMController) newVM(typ string) api.VM {
+	vmc.RLock()
 	v, ok := vmc.vmProviders[typ]
+	vmc.RUnlock()
 	if !ok {
 		vmLogger.Panicf("Programming error: unsupported VM type: %s", typ)
 	}
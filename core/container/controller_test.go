@@ -20,6 +20,7 @@ import (
 	"github.com/hyperledger/fabric/core/container/api"
 	"github.com/hyperledger/fabric/core/container/ccintf"
 	"github.com/hyperledger/fabric/core/container/dockercontroller"
+	"github.com/hyperledger/fabric/core/container/externalbuilder"
 	"github.com/hyperledger/fabric/core/container/inproccontroller"
 	"github.com/hyperledger/fabric/core/testutil"
 	pb "github.com/hyperledger/fabric/protos/peer"
@@ -166,6 +167,12 @@ func TestNewVM(t *testing.T) {
 	assert.NotNil(t, ivm, "Requested System VM but newVM did not return inproccontroller.InprocVM")
 
 	assert.Panics(t, func() { vmc.newVM("") }, "Requested unknown VM but did not panic")
+
+	assert.Panics(t, func() { vmc.newVM("External") }, "Requested unregistered VM but did not panic")
+	vmc.RegisterVMProvider(EXTERNAL, externalbuilder.NewProvider(nil))
+	vm = vmc.newVM("External")
+	evm := vm.(*externalbuilder.ExternalVM)
+	assert.NotNil(t, evm, "Requested External VM but newVM did not return externalbuilder.ExternalVM")
 }
 
 func TestVM_GetChaincodePackageBytes(t *testing.T) {
@@ -83,6 +83,9 @@ type dockerClient interface {
 	KillContainer(opts docker.KillContainerOptions) error
 	// RemoveContainer removes a docker container, returns an error in case of failure
 	RemoveContainer(opts docker.RemoveContainerOptions) error
+	// PingWithContext pings the docker daemon. The context object can be used
+	// to cancel the ping request.
+	PingWithContext(context.Context) error
 }
 
 // Controller implements container.VMProvider
@@ -450,6 +453,19 @@ func (vm *DockerVM) stopInternal(ctxt context.Context, client dockerClient,
 	return err
 }
 
+// HealthCheck checks if the DockerVM is able to communicate with the Docker
+// daemon.
+func (vm *DockerVM) HealthCheck(ctx context.Context) error {
+	client, err := vm.getClientFnc()
+	if err != nil {
+		return fmt.Errorf("failed to connect to Docker daemon: %s", err)
+	}
+	if err := client.PingWithContext(ctx); err != nil {
+		return fmt.Errorf("failed to ping to Docker daemon: %s", err)
+	}
+	return nil
+}
+
 //Destroy destroys an image
 func (vm *DockerVM) Destroy(ctxt context.Context, ccid ccintf.CCID, force bool, noprune bool) error {
 	id, err := vm.GetVMName(ccid, formatImageName)
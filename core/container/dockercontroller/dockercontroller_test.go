@@ -236,6 +236,28 @@ func Test_Destroy(t *testing.T) {
 	testerr(t, err, true)
 }
 
+func Test_HealthCheck(t *testing.T) {
+	dvm := DockerVM{}
+	ctx := context.Background()
+
+	// Failure case: getMockClient returns error
+	getClientErr = true
+	dvm.getClientFnc = getMockClient
+	err := dvm.HealthCheck(ctx)
+	testerr(t, err, false)
+	getClientErr = false
+
+	// Failure case: the daemon can't be pinged
+	pingErr = true
+	err = dvm.HealthCheck(ctx)
+	testerr(t, err, false)
+	pingErr = false
+
+	// Success case
+	err = dvm.HealthCheck(ctx)
+	testerr(t, err, true)
+}
+
 type testCase struct {
 	name           string
 	ccid           ccintf.CCID
@@ -306,7 +328,7 @@ type mockClient struct {
 }
 
 var getClientErr, createErr, uploadErr, noSuchImgErr, buildErr, removeImgErr,
-	startErr, stopErr, killErr, removeErr bool
+	startErr, stopErr, killErr, removeErr, pingErr bool
 
 func (c *mockClient) CreateContainer(options docker.CreateContainerOptions) (*docker.Container, error) {
 	if createErr {
@@ -374,6 +396,13 @@ func (c *mockClient) RemoveContainer(opts docker.RemoveContainerOptions) error {
 	return nil
 }
 
+func (c *mockClient) PingWithContext(ctx context.Context) error {
+	if pingErr {
+		return errors.New("Error pinging the daemon")
+	}
+	return nil
+}
+
 func formatInvalidChars(name string) (string, error) {
 	return "inv@lid*character$/", nil
 }
@@ -0,0 +1,133 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package externalbuilder
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultEnvironmentWhitelist lists the environment variables of the peer that
+// are always propagated to the builder executables
+var DefaultEnvironmentWhitelist = []string{"LD_LIBRARY_PATH", "LIBPATH", "PATH", "TMPDIR"}
+
+// Builder is an external builder as configured under chaincode.externalBuilders
+// in core.yaml. The bin directory of Path must contain the detect, build and
+// launch executables
+type Builder struct {
+	Name                 string   `mapstructure:"name"`
+	Path                 string   `mapstructure:"path"`
+	EnvironmentWhitelist []string `mapstructure:"environmentWhitelist"`
+}
+
+// Detect runs bin/detect with the source and the metadata directories of the
+// chaincode, and reports whether the builder handles the chaincode
+func (b *Builder) Detect(srcDir, metadataDir string) bool {
+	cmd := b.command("detect", srcDir, metadataDir)
+	out, err := cmd.CombinedOutput()
+	logger.Debugf("external builder [%s] detect output: %s", b.Name, out)
+	if err != nil {
+		logger.Debugf("external builder [%s] did not detect the chaincode: %s", b.Name, err)
+		return false
+	}
+	return true
+}
+
+// Build runs bin/build, which is expected to place the build output of the
+// chaincode into outputDir
+func (b *Builder) Build(srcDir, metadataDir, outputDir string) error {
+	cmd := b.command("build", srcDir, metadataDir, outputDir)
+	out, err := cmd.CombinedOutput()
+	logger.Debugf("external builder [%s] build output: %s", b.Name, out)
+	if err != nil {
+		return errors.Wrapf(err, "external builder [%s] failed to build the chaincode: %s", b.Name, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// Launch starts bin/launch with the build output directory and the run directory
+// of the chaincode. The given environment is appended to the whitelisted one.
+// The returned command has been started and must be waited for by the caller
+func (b *Builder) Launch(outputDir, runDir string, env []string) (*exec.Cmd, error) {
+	cmd := b.command("launch", outputDir, runDir)
+	cmd.Env = append(cmd.Env, env...)
+	cmd.Stdout = newLogWriter(fmt.Sprintf("[%s] ", b.Name))
+	cmd.Stderr = cmd.Stdout
+	if err := cmd.Start(); err != nil {
+		return nil, errors.Wrapf(err, "external builder [%s] failed to launch the chaincode", b.Name)
+	}
+	return cmd, nil
+}
+
+func (b *Builder) command(name string, args ...string) *exec.Cmd {
+	cmd := exec.Command(filepath.Join(b.Path, "bin", name), args...)
+	cmd.Env = b.environment()
+	return cmd
+}
+
+// environment returns the variables of the peer's environment that are
+// whitelisted for the builder
+func (b *Builder) environment() []string {
+	whitelist := make(map[string]struct{})
+	for _, name := range DefaultEnvironmentWhitelist {
+		whitelist[name] = struct{}{}
+	}
+	for _, name := range b.EnvironmentWhitelist {
+		whitelist[name] = struct{}{}
+	}
+
+	var env []string
+	for _, kv := range os.Environ() {
+		name := strings.SplitN(kv, "=", 2)[0]
+		if _, ok := whitelist[name]; ok {
+			env = append(env, kv)
+		}
+	}
+	return env
+}
+
+// maxLogLineLength is the length at which an incomplete line written by a
+// launched chaincode is logged without waiting for the rest of it
+const maxLogLineLength = 64 * 1024
+
+// logWriter logs the lines written by a launched chaincode
+type logWriter struct {
+	prefix string
+	mutex  sync.Mutex
+	buf    bytes.Buffer
+}
+
+func newLogWriter(prefix string) *logWriter {
+	return &logWriter{prefix: prefix}
+}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.buf.Write(p)
+	for {
+		line, err := w.buf.ReadString('\n')
+		if err != nil {
+			// keep the incomplete line until the rest of it is written, unless
+			// it is too long to be kept
+			if len(line) < maxLogLineLength {
+				w.buf.WriteString(line)
+			} else {
+				logger.Info(w.prefix + line)
+			}
+			return len(p), nil
+		}
+		logger.Info(w.prefix + strings.TrimRight(line, "\r\n"))
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package externalbuilder
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hyperledger/fabric/core/comm"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+// ConnectionFile is the name of the file that a builder places into its build
+// output when the chaincode runs as a server that the peer connects to
+const ConnectionFile = "connection.json"
+
+const defaultDialTimeout = 3 * time.Second
+
+// ChaincodeServerInfo holds the connection information of a chaincode that runs
+// as a service, as found in the connection.json file of the build output
+type ChaincodeServerInfo struct {
+	Address            string `json:"address"`
+	DialTimeout        string `json:"dial_timeout"`
+	TLSRequired        bool   `json:"tls_required"`
+	ClientAuthRequired bool   `json:"client_auth_required"`
+	RootCert           string `json:"root_cert"`
+	ClientKey          string `json:"client_key"`
+	ClientCert         string `json:"client_cert"`
+}
+
+// readChaincodeServerInfo reads the connection.json file of the build output.
+// It returns nil if the build output doesn't contain such a file
+func readChaincodeServerInfo(outputDir string) (*ChaincodeServerInfo, error) {
+	raw, err := ioutil.ReadFile(filepath.Join(outputDir, ConnectionFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed reading %s", ConnectionFile)
+	}
+
+	info := &ChaincodeServerInfo{}
+	if err := json.Unmarshal(raw, info); err != nil {
+		return nil, errors.Wrapf(err, "malformed %s", ConnectionFile)
+	}
+	if info.Address == "" {
+		return nil, errors.Errorf("%s does not contain the chaincode address", ConnectionFile)
+	}
+	return info, nil
+}
+
+// clientConfig returns the configuration of the gRPC client connecting to the chaincode
+func (info *ChaincodeServerInfo) clientConfig() (comm.ClientConfig, error) {
+	config := comm.ClientConfig{
+		KaOpts:  comm.DefaultKeepaliveOptions,
+		Timeout: defaultDialTimeout,
+		SecOpts: &comm.SecureOptions{},
+	}
+	if info.DialTimeout != "" {
+		timeout, err := time.ParseDuration(info.DialTimeout)
+		if err != nil {
+			return comm.ClientConfig{}, errors.Wrapf(err, "invalid dial_timeout")
+		}
+		config.Timeout = timeout
+	}
+
+	if !info.TLSRequired {
+		return config, nil
+	}
+	if info.RootCert == "" {
+		return comm.ClientConfig{}, errors.New("root_cert is required when tls_required is set")
+	}
+	config.SecOpts.UseTLS = true
+	config.SecOpts.ServerRootCAs = [][]byte{[]byte(info.RootCert)}
+
+	if info.ClientAuthRequired {
+		if info.ClientKey == "" || info.ClientCert == "" {
+			return comm.ClientConfig{}, errors.New("client_key and client_cert are required when client_auth_required is set")
+		}
+		config.SecOpts.RequireClientCert = true
+		config.SecOpts.Key = []byte(info.ClientKey)
+		config.SecOpts.Certificate = []byte(info.ClientCert)
+	}
+	return config, nil
+}
+
+// dial connects to the chaincode server
+func (info *ChaincodeServerInfo) dial() (*grpc.ClientConn, error) {
+	config, err := info.clientConfig()
+	if err != nil {
+		return nil, err
+	}
+	client, err := comm.NewGRPCClient(config)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed creating gRPC client")
+	}
+	conn, err := client.NewConnection(info.Address, "")
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed connecting to chaincode at "+info.Address)
+	}
+	return conn, nil
+}
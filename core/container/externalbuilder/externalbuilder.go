@@ -0,0 +1,424 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package externalbuilder
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	container "github.com/hyperledger/fabric/core/container/api"
+	"github.com/hyperledger/fabric/core/container/ccintf"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// MetadataFile is the name of the file describing the chaincode that is passed
+// to the detect and build executables
+const MetadataFile = "metadata.json"
+
+const defaultStopTimeout = 5 * time.Second
+
+var logger = flogging.MustGetLogger("externalbuilder")
+
+// ChaincodeMetadata is the content of the metadata.json file
+type ChaincodeMetadata struct {
+	Type    string `json:"type"`
+	Path    string `json:"path"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// ErrNotDetected is returned when starting a chaincode that none of the external builders detects
+type ErrNotDetected struct {
+	Chaincode string
+}
+
+func (e *ErrNotDetected) Error() string {
+	return fmt.Sprintf("no external builder detected chaincode %s", e.Chaincode)
+}
+
+// Provider implements container.VMProvider. It keeps track of the chaincodes
+// launched by its VMs. A chaincode being started is recorded with a nil instance
+type Provider struct {
+	builders  []Builder
+	mutex     sync.Mutex
+	instances map[string]*instance
+}
+
+// NewProvider creates a new Provider that launches chaincode with the given builders.
+// When launching a chaincode, the builders are tried in order and the first one
+// that detects the chaincode builds and launches it
+func NewProvider(builders []Builder) *Provider {
+	return &Provider{
+		builders:  builders,
+		instances: make(map[string]*instance),
+	}
+}
+
+// NewVM creates a new ExternalVM
+func (p *Provider) NewVM() container.VM {
+	return &ExternalVM{provider: p}
+}
+
+// instance is a chaincode launched by an external builder. It is either a
+// process started by the launch executable, or a connection to a chaincode server
+type instance struct {
+	workDir string
+	cmd     *exec.Cmd
+	conn    *grpc.ClientConn
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// stop terminates the chaincode and removes its working directory. A launched
+// process is sent SIGTERM and killed if it doesn't exit within the timeout
+func (i *instance) stop(timeout time.Duration) error {
+	defer os.RemoveAll(i.workDir)
+
+	if i.conn != nil {
+		i.cancel()
+		return i.conn.Close()
+	}
+
+	i.cmd.Process.Signal(syscall.SIGTERM)
+	select {
+	case <-i.done:
+		return nil
+	case <-time.After(timeout):
+	}
+	if err := i.cmd.Process.Kill(); err != nil {
+		return errors.Wrap(err, "failed killing chaincode process")
+	}
+	<-i.done
+	return nil
+}
+
+// ExternalVM is a VM that builds and launches chaincode with external builders
+type ExternalVM struct {
+	provider *Provider
+}
+
+// Deploy is not supported, as the chaincode is built when it is started
+func (vm *ExternalVM) Deploy(ctxt context.Context, ccid ccintf.CCID, args []string, env []string, reader io.Reader) error {
+	return errors.New("deploy is not supported by external builders")
+}
+
+// Start builds the chaincode package returned by the builder with the first
+// external builder that detects it, then either launches it or, if the build
+// output contains a connection.json file, connects to the chaincode server
+func (vm *ExternalVM) Start(ctxt context.Context, ccid ccintf.CCID, args []string, env []string, filesToUpload map[string][]byte, builder container.BuildSpecFactory, prelaunchFunc container.PrelaunchFunc) error {
+	name, err := vm.GetVMName(ccid, nil)
+	if err != nil {
+		return err
+	}
+
+	// record the chaincode as being started, so that concurrent starts fail
+	vm.provider.mutex.Lock()
+	if _, running := vm.provider.instances[name]; running {
+		vm.provider.mutex.Unlock()
+		return errors.Errorf("chaincode %s is already running", name)
+	}
+	vm.provider.instances[name] = nil
+	vm.provider.mutex.Unlock()
+
+	inst, err := vm.start(ctxt, name, ccid, env, filesToUpload, builder, prelaunchFunc)
+
+	vm.provider.mutex.Lock()
+	if err != nil {
+		delete(vm.provider.instances, name)
+	} else {
+		vm.provider.instances[name] = inst
+	}
+	vm.provider.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		<-inst.done
+		vm.provider.mutex.Lock()
+		if vm.provider.instances[name] == inst {
+			delete(vm.provider.instances, name)
+		}
+		vm.provider.mutex.Unlock()
+		logger.Infof("chaincode %s exited", name)
+	}()
+
+	return nil
+}
+
+// start builds and launches the chaincode in a new working directory
+func (vm *ExternalVM) start(ctxt context.Context, name string, ccid ccintf.CCID, env []string, filesToUpload map[string][]byte, builder container.BuildSpecFactory, prelaunchFunc container.PrelaunchFunc) (*instance, error) {
+	if builder == nil {
+		return nil, errors.Errorf("no chaincode package provided for %s", name)
+	}
+	codePackage, err := builder()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get chaincode package")
+	}
+
+	workDir, err := ioutil.TempDir("", "externalbuilder-")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating working directory")
+	}
+	inst, err := vm.buildAndRun(ctxt, ccid, workDir, codePackage, env, filesToUpload, prelaunchFunc)
+	if err != nil {
+		os.RemoveAll(workDir)
+		return nil, err
+	}
+	return inst, nil
+}
+
+func (vm *ExternalVM) buildAndRun(ctxt context.Context, ccid ccintf.CCID, workDir string, codePackage io.Reader, env []string, filesToUpload map[string][]byte, prelaunchFunc container.PrelaunchFunc) (*instance, error) {
+	srcDir := filepath.Join(workDir, "src")
+	metadataDir := filepath.Join(workDir, "metadata")
+	outputDir := filepath.Join(workDir, "output")
+	runDir := filepath.Join(workDir, "run")
+	for _, dir := range []string{srcDir, metadataDir, outputDir, runDir} {
+		if err := os.Mkdir(dir, 0700); err != nil {
+			return nil, errors.Wrap(err, "failed creating working directory")
+		}
+	}
+
+	if err := untar(codePackage, srcDir); err != nil {
+		return nil, errors.WithMessage(err, "failed extracting chaincode package")
+	}
+	if err := writeMetadata(metadataDir, ccid); err != nil {
+		return nil, err
+	}
+
+	b := vm.detect(srcDir, metadataDir)
+	if b == nil {
+		return nil, &ErrNotDetected{Chaincode: ccid.GetName()}
+	}
+	if err := b.Build(srcDir, metadataDir, outputDir); err != nil {
+		return nil, err
+	}
+
+	serverInfo, err := readChaincodeServerInfo(outputDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if prelaunchFunc != nil {
+		if err := prelaunchFunc(); err != nil {
+			return nil, err
+		}
+	}
+
+	if serverInfo != nil {
+		return connect(ctxt, workDir, serverInfo)
+	}
+
+	env, err = writeFiles(runDir, filesToUpload, env)
+	if err != nil {
+		return nil, err
+	}
+	cmd, err := b.Launch(outputDir, runDir, env)
+	if err != nil {
+		return nil, err
+	}
+	inst := &instance{workDir: workDir, cmd: cmd, done: make(chan struct{})}
+	go func() {
+		defer close(inst.done)
+		if err := cmd.Wait(); err != nil {
+			logger.Warningf("chaincode %s launched by external builder [%s] ended with error: %s", ccid.GetName(), b.Name, err)
+		}
+	}()
+	return inst, nil
+}
+
+// detect returns the first builder that detects the chaincode, or nil if none does
+func (vm *ExternalVM) detect(srcDir, metadataDir string) *Builder {
+	for i := range vm.provider.builders {
+		b := &vm.provider.builders[i]
+		if b.Detect(srcDir, metadataDir) {
+			logger.Debugf("chaincode detected by external builder [%s]", b.Name)
+			return b
+		}
+	}
+	return nil
+}
+
+// connect establishes a chaincode stream with a chaincode server, and hands it
+// over to the chaincode support of the peer supplied through the context
+func connect(ctxt context.Context, workDir string, serverInfo *ChaincodeServerInfo) (*instance, error) {
+	ccSupport, ok := ctxt.Value(ccintf.GetCCHandlerKey()).(ccintf.CCSupport)
+	if !ok || ccSupport == nil {
+		return nil, errors.New("chaincode support not supplied")
+	}
+
+	conn, err := serverInfo.dial()
+	if err != nil {
+		return nil, err
+	}
+	streamCtx, cancel := context.WithCancel(context.Background())
+	stream, err := pb.NewChaincodeSupportClient(conn).Register(streamCtx)
+	if err != nil {
+		cancel()
+		conn.Close()
+		return nil, errors.Wrapf(err, "failed registering with chaincode at %s", serverInfo.Address)
+	}
+
+	inst := &instance{workDir: workDir, conn: conn, cancel: cancel, done: make(chan struct{})}
+	go func() {
+		defer close(inst.done)
+		if err := ccSupport.HandleChaincodeStream(stream.Context(), stream); err != nil {
+			logger.Warningf("chaincode stream with %s ended with error: %s", serverInfo.Address, err)
+		}
+	}()
+	return inst, nil
+}
+
+// Stop stops the chaincode
+func (vm *ExternalVM) Stop(ctxt context.Context, ccid ccintf.CCID, timeout uint, dontkill bool, dontremove bool) error {
+	name, err := vm.GetVMName(ccid, nil)
+	if err != nil {
+		return err
+	}
+
+	vm.provider.mutex.Lock()
+	inst, ok := vm.provider.instances[name]
+	if ok && inst == nil {
+		vm.provider.mutex.Unlock()
+		return errors.Errorf("chaincode %s is being started", name)
+	}
+	delete(vm.provider.instances, name)
+	vm.provider.mutex.Unlock()
+	if !ok {
+		return errors.Errorf("chaincode %s is not running", name)
+	}
+
+	stopTimeout := defaultStopTimeout
+	if timeout > 0 {
+		stopTimeout = time.Duration(timeout) * time.Second
+	}
+	return inst.stop(stopTimeout)
+}
+
+// Destroy is a no-op as the working directory of the chaincode is removed when it is stopped
+func (vm *ExternalVM) Destroy(ctxt context.Context, ccid ccintf.CCID, force bool, noprune bool) error {
+	return nil
+}
+
+// GetVMName returns the name of the chaincode prefixed with the network and the peer IDs.
+// It accepts a format function parameter to allow different formatting based on
+// the desired use of the name.
+func (vm *ExternalVM) GetVMName(ccid ccintf.CCID, format func(string) (string, error)) (string, error) {
+	var parts []string
+	for _, part := range []string{ccid.NetworkID, ccid.PeerID, ccid.GetName()} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	name := strings.Join(parts, "-")
+	if format != nil {
+		return format(name)
+	}
+	return name, nil
+}
+
+// writeMetadata writes the metadata.json file describing the chaincode
+func writeMetadata(metadataDir string, ccid ccintf.CCID) error {
+	metadata := ChaincodeMetadata{
+		Type:    strings.ToLower(ccid.ChaincodeSpec.Type.String()),
+		Path:    ccid.ChaincodeSpec.ChaincodeId.Path,
+		Name:    ccid.ChaincodeSpec.ChaincodeId.Name,
+		Version: ccid.Version,
+	}
+	raw, err := json.Marshal(metadata)
+	if err != nil {
+		return errors.Wrap(err, "failed marshaling chaincode metadata")
+	}
+	if err := ioutil.WriteFile(filepath.Join(metadataDir, MetadataFile), raw, 0600); err != nil {
+		return errors.Wrap(err, "failed writing chaincode metadata")
+	}
+	return nil
+}
+
+// writeFiles writes the files meant to be uploaded into a chaincode container
+// under runDir, and updates the environment variables that refer to them
+func writeFiles(runDir string, files map[string][]byte, env []string) ([]string, error) {
+	paths := make(map[string]string)
+	for name, content := range files {
+		path := filepath.Join(runDir, filepath.Clean("/"+name))
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return nil, errors.Wrapf(err, "failed creating directory of %s", name)
+		}
+		if err := ioutil.WriteFile(path, content, 0600); err != nil {
+			return nil, errors.Wrapf(err, "failed writing %s", name)
+		}
+		paths[name] = path
+	}
+
+	updated := make([]string, 0, len(env))
+	for _, kv := range env {
+		nameAndValue := strings.SplitN(kv, "=", 2)
+		if len(nameAndValue) == 2 {
+			if path, ok := paths[nameAndValue[1]]; ok {
+				kv = fmt.Sprintf("%s=%s", nameAndValue[0], path)
+			}
+		}
+		updated = append(updated, kv)
+	}
+	return updated, nil
+}
+
+// untar extracts the gzipped tar chaincode package into dir
+func untar(codePackage io.Reader, dir string) error {
+	gr, err := gzip.NewReader(codePackage)
+	if err != nil {
+		return errors.Wrap(err, "failed opening gzip stream")
+	}
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed reading tar entry")
+		}
+
+		path := filepath.Join(dir, filepath.Clean("/"+header.Name))
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, 0700); err != nil {
+				return errors.Wrapf(err, "failed creating directory %s", header.Name)
+			}
+		case tar.TypeReg, tar.TypeRegA:
+			if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+				return errors.Wrapf(err, "failed creating directory of %s", header.Name)
+			}
+			f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, os.FileMode(header.Mode)&0700|0600)
+			if err != nil {
+				return errors.Wrapf(err, "failed creating %s", header.Name)
+			}
+			_, err = io.Copy(f, tr)
+			f.Close()
+			if err != nil {
+				return errors.Wrapf(err, "failed writing %s", header.Name)
+			}
+		default:
+			logger.Debugf("skipping tar entry %s of type %c", header.Name, header.Typeflag)
+		}
+	}
+}
@@ -0,0 +1,338 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package externalbuilder
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	container "github.com/hyperledger/fabric/core/container/api"
+	"github.com/hyperledger/fabric/core/container/ccintf"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+const chaincodeScript = `#!/bin/sh
+# records the environment and the TLS client certificate, then runs until terminated
+env > "$OUTPUT_FILE.tmp"
+cat "$CORE_TLS_CLIENT_CERT_PATH" > "$OUTPUT_FILE.cert"
+mv "$OUTPUT_FILE.tmp" "$OUTPUT_FILE"
+exec sleep 60
+`
+
+func codePackage(t *testing.T, files map[string]string) container.BuildSpecFactory {
+	buf := &bytes.Buffer{}
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+	for name, content := range files {
+		err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		assert.NoError(t, err)
+		_, err = tw.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, tw.Close())
+	assert.NoError(t, gw.Close())
+	return func() (io.Reader, error) { return bytes.NewReader(buf.Bytes()), nil }
+}
+
+func testCCID() ccintf.CCID {
+	return ccintf.CCID{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			Type:        pb.ChaincodeSpec_GOLANG,
+			ChaincodeId: &pb.ChaincodeID{Name: "mycc", Path: "github.com/example/mycc"},
+		},
+		NetworkID: "dev",
+		PeerID:    "peer0",
+		Version:   "1.0",
+	}
+}
+
+func builders(names ...string) []Builder {
+	var res []Builder
+	for _, name := range names {
+		path, _ := filepath.Abs(filepath.Join("testdata", name))
+		res = append(res, Builder{Name: name, Path: path, EnvironmentWhitelist: []string{"EXTERNALBUILDER_WHITELISTED"}})
+	}
+	return res
+}
+
+func TestLaunchChaincode(t *testing.T) {
+	gt := NewGomegaWithT(t)
+	tempDir, err := ioutil.TempDir("", "externalbuilder-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+	outputFile := filepath.Join(tempDir, "output")
+
+	os.Setenv("EXTERNALBUILDER_WHITELISTED", "visible")
+	os.Setenv("EXTERNALBUILDER_NOT_WHITELISTED", "hidden")
+	defer os.Unsetenv("EXTERNALBUILDER_WHITELISTED")
+	defer os.Unsetenv("EXTERNALBUILDER_NOT_WHITELISTED")
+
+	vm := NewProvider(builders("undetectingbuilder", "goodbuilder")).NewVM()
+	ccid := testCCID()
+	env := []string{"CORE_CHAINCODE_ID_NAME=mycc:1.0", "CORE_TLS_CLIENT_CERT_PATH=/etc/hyperledger/fabric/client.crt", "OUTPUT_FILE=" + outputFile}
+	files := map[string][]byte{"/etc/hyperledger/fabric/client.crt": []byte("client-cert")}
+	preLaunched := false
+	err = vm.Start(context.Background(), ccid, nil, env, files, codePackage(t, map[string]string{"chaincode.sh": chaincodeScript}), func() error {
+		preLaunched = true
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.True(t, preLaunched)
+
+	gt.Eventually(func() bool {
+		_, err := os.Stat(outputFile)
+		return err == nil
+	}, 5*time.Second).Should(BeTrue())
+	output, err := ioutil.ReadFile(outputFile)
+	assert.NoError(t, err)
+	chaincodeEnv := strings.Split(string(output), "\n")
+	assert.Contains(t, chaincodeEnv, "CORE_CHAINCODE_ID_NAME=mycc:1.0")
+	assert.Contains(t, chaincodeEnv, "EXTERNALBUILDER_WHITELISTED=visible")
+	assert.NotContains(t, chaincodeEnv, "EXTERNALBUILDER_NOT_WHITELISTED=hidden")
+	assert.NotContains(t, chaincodeEnv, "CORE_TLS_CLIENT_CERT_PATH=/etc/hyperledger/fabric/client.crt")
+	cert, err := ioutil.ReadFile(outputFile + ".cert")
+	assert.NoError(t, err)
+	assert.Equal(t, "client-cert", string(cert))
+
+	// the chaincode can only be started once
+	err = vm.Start(context.Background(), ccid, nil, env, files, codePackage(t, map[string]string{"chaincode.sh": chaincodeScript}), nil)
+	assert.EqualError(t, err, "chaincode dev-peer0-mycc-1.0 is already running")
+
+	provider := vm.(*ExternalVM).provider
+	inst := provider.instances["dev-peer0-mycc-1.0"]
+	assert.NoError(t, vm.Stop(context.Background(), ccid, 0, false, false))
+	select {
+	case <-inst.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("chaincode process didn't exit")
+	}
+	_, err = os.Stat(inst.workDir)
+	assert.True(t, os.IsNotExist(err))
+
+	err = vm.Stop(context.Background(), ccid, 0, false, false)
+	assert.EqualError(t, err, "chaincode dev-peer0-mycc-1.0 is not running")
+}
+
+func TestStartFailures(t *testing.T) {
+	pkg := codePackage(t, map[string]string{"chaincode.sh": chaincodeScript})
+	tests := []struct {
+		name      string
+		builders  []Builder
+		pkg       container.BuildSpecFactory
+		prelaunch container.PrelaunchFunc
+		errMsg    string
+	}{
+		{"no builder", nil, pkg, nil, "no external builder detected chaincode mycc-1.0"},
+		{"not detected", builders("undetectingbuilder"), pkg, nil, "no external builder detected chaincode mycc-1.0"},
+		{"not golang", builders("goodbuilder"), codePackage(t, map[string]string{"main.go": "package main"}), nil, "no external builder detected chaincode mycc-1.0"},
+		{"build failure", builders("failingbuilder"), pkg, nil, "external builder [failingbuilder] failed to build the chaincode: compilation failed: exit status 1"},
+		{"no package", builders("goodbuilder"), nil, nil, "no chaincode package provided for dev-peer0-mycc-1.0"},
+		{"bad package", builders("goodbuilder"), func() (io.Reader, error) { return strings.NewReader("not a tarball"), nil }, nil, "failed extracting chaincode package: failed opening gzip stream: gzip: invalid header"},
+		{"prelaunch failure", builders("goodbuilder"), pkg, func() error { return fmt.Errorf("prelaunch failed") }, "prelaunch failed"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			vm := NewProvider(tc.builders).NewVM()
+			err := vm.Start(context.Background(), testCCID(), nil, nil, nil, tc.pkg, tc.prelaunch)
+			assert.EqualError(t, err, tc.errMsg)
+			assert.Empty(t, vm.(*ExternalVM).provider.instances)
+		})
+	}
+}
+
+func TestConcurrentStart(t *testing.T) {
+	vm := NewProvider(builders("goodbuilder")).NewVM()
+	ccid := testCCID()
+
+	// the first start waits for the chaincode package while the chaincode is started again
+	building := make(chan struct{})
+	release := make(chan struct{})
+	errCh := make(chan error)
+	go func() {
+		errCh <- vm.Start(context.Background(), ccid, nil, nil, nil, func() (io.Reader, error) {
+			close(building)
+			<-release
+			return nil, fmt.Errorf("package unavailable")
+		}, nil)
+	}()
+	<-building
+
+	err := vm.Start(context.Background(), ccid, nil, nil, nil, codePackage(t, map[string]string{"chaincode.sh": chaincodeScript}), nil)
+	assert.EqualError(t, err, "chaincode dev-peer0-mycc-1.0 is already running")
+	err = vm.Stop(context.Background(), ccid, 0, false, false)
+	assert.EqualError(t, err, "chaincode dev-peer0-mycc-1.0 is being started")
+
+	close(release)
+	assert.EqualError(t, <-errCh, "failed to get chaincode package: package unavailable")
+	assert.Empty(t, vm.(*ExternalVM).provider.instances)
+}
+
+type ccSupport struct {
+	received chan *pb.ChaincodeMessage
+}
+
+func (cs *ccSupport) HandleChaincodeStream(ctx context.Context, stream ccintf.ChaincodeStream) error {
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		cs.received <- msg
+		if err := stream.Send(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_REGISTERED}); err != nil {
+			return err
+		}
+	}
+}
+
+type chaincodeServer struct {
+	received chan *pb.ChaincodeMessage
+}
+
+func (s *chaincodeServer) Register(stream pb.ChaincodeSupport_RegisterServer) error {
+	if err := stream.Send(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_REGISTER, Payload: []byte("mycc:1.0")}); err != nil {
+		return err
+	}
+	msg, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	s.received <- msg
+	<-stream.Context().Done()
+	return nil
+}
+
+func TestChaincodeAsAService(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	server := grpc.NewServer()
+	ccServer := &chaincodeServer{received: make(chan *pb.ChaincodeMessage, 1)}
+	pb.RegisterChaincodeSupportServer(server, ccServer)
+	go server.Serve(lis)
+	defer server.Stop()
+
+	vm := NewProvider(builders("goodbuilder")).NewVM()
+	pkg := codePackage(t, map[string]string{
+		"chaincode.sh":    chaincodeScript,
+		"connection.json": fmt.Sprintf(`{"address": "%s", "dial_timeout": "5s"}`, lis.Addr()),
+	})
+
+	// the chaincode support of the peer is required
+	err = vm.Start(context.Background(), testCCID(), nil, nil, nil, pkg, nil)
+	assert.EqualError(t, err, "chaincode support not supplied")
+
+	support := &ccSupport{received: make(chan *pb.ChaincodeMessage, 1)}
+	ctx := context.WithValue(context.Background(), ccintf.GetCCHandlerKey(), support)
+	err = vm.Start(ctx, testCCID(), nil, nil, nil, pkg, nil)
+	assert.NoError(t, err)
+
+	select {
+	case msg := <-support.received:
+		assert.Equal(t, pb.ChaincodeMessage_REGISTER, msg.Type)
+		assert.Equal(t, []byte("mycc:1.0"), msg.Payload)
+	case <-time.After(5 * time.Second):
+		t.Fatal("peer didn't receive the REGISTER message")
+	}
+	select {
+	case msg := <-ccServer.received:
+		assert.Equal(t, pb.ChaincodeMessage_REGISTERED, msg.Type)
+	case <-time.After(5 * time.Second):
+		t.Fatal("chaincode didn't receive the REGISTERED message")
+	}
+
+	inst := vm.(*ExternalVM).provider.instances["dev-peer0-mycc-1.0"]
+	assert.NoError(t, vm.Stop(context.Background(), testCCID(), 0, false, false))
+	select {
+	case <-inst.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("chaincode stream wasn't closed")
+	}
+}
+
+func TestChaincodeServerInfo(t *testing.T) {
+	tests := []struct {
+		name       string
+		connection string
+		errMsg     string
+	}{
+		{"malformed", `{"address":`, "malformed connection.json: unexpected end of JSON input"},
+		{"no address", `{"dial_timeout": "1s"}`, "connection.json does not contain the chaincode address"},
+		{"bad timeout", `{"address": "cc:7052", "dial_timeout": "soon"}`, "invalid dial_timeout: time: invalid duration"},
+		{"no root cert", `{"address": "cc:7052", "tls_required": true}`, "root_cert is required when tls_required is set"},
+		{"no client cert", `{"address": "cc:7052", "tls_required": true, "root_cert": "ca", "client_auth_required": true}`, "client_key and client_cert are required when client_auth_required is set"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "externalbuilder-test")
+			assert.NoError(t, err)
+			defer os.RemoveAll(dir)
+			assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, ConnectionFile), []byte(tc.connection), 0600))
+
+			info, err := readChaincodeServerInfo(dir)
+			if err == nil {
+				_, err = info.clientConfig()
+			}
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tc.errMsg)
+		})
+	}
+
+	// no connection.json
+	dir, err := ioutil.TempDir("", "externalbuilder-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	info, err := readChaincodeServerInfo(dir)
+	assert.NoError(t, err)
+	assert.Nil(t, info)
+
+	// TLS with client authentication
+	info = &ChaincodeServerInfo{Address: "cc:7052", TLSRequired: true, ClientAuthRequired: true, RootCert: "ca", ClientKey: "key", ClientCert: "cert"}
+	config, err := info.clientConfig()
+	assert.NoError(t, err)
+	assert.True(t, config.SecOpts.UseTLS)
+	assert.True(t, config.SecOpts.RequireClientCert)
+	assert.Equal(t, [][]byte{[]byte("ca")}, config.SecOpts.ServerRootCAs)
+	assert.Equal(t, []byte("key"), config.SecOpts.Key)
+	assert.Equal(t, []byte("cert"), config.SecOpts.Certificate)
+	assert.Equal(t, defaultDialTimeout, config.Timeout)
+}
+
+func TestLogWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	flogging.InitBackend(flogging.SetFormat("%{message}"), buf)
+	defer flogging.Reset()
+
+	w := newLogWriter("[cc] ")
+	w.Write([]byte("first line\nsecond"))
+	w.Write([]byte(" line\r\nthird"))
+	assert.Equal(t, "[cc] first line\n[cc] second line\n", buf.String())
+
+	// an incomplete line is logged once it reaches the maximum length
+	buf.Reset()
+	long := strings.Repeat("x", maxLogLineLength)
+	w.Write([]byte(long[:maxLogLineLength-len("third")-1]))
+	assert.Equal(t, "", buf.String())
+	w.Write([]byte("xy"))
+	assert.Equal(t, "[cc] third"+long[:maxLogLineLength-len("third")]+"y\n", buf.String())
+	assert.Equal(t, 0, w.buf.Len())
+}
@@ -95,6 +95,21 @@ func (d *db) entryAt(blockNum uint64, ns, key string) (*compositeKV, error) {
 	return &compositeKV{k, v}, nil
 }
 
+// exportEntries passes to the given function all the entries of the db in the order of the keys
+func (d *db) exportEntries(fn func(*compositeKV) error) error {
+	itr := d.GetIterator(nil, nil)
+	defer itr.Release()
+	for itr.Next() {
+		k := decodeCompositeKey(itr.Key())
+		v := make([]byte, len(itr.Value()))
+		copy(v, itr.Value())
+		if err := fn(&compositeKV{k, v}); err != nil {
+			return err
+		}
+	}
+	return itr.Error()
+}
+
 func encodeCompositeKey(ns, key string, blockNum uint64) []byte {
 	b := []byte(keyPrefix + ns)
 	b = append(b, separatorByte)
@@ -28,9 +28,23 @@ const (
 type Mgr interface {
 	ledger.StateListener
 	GetRetriever(ledgerID string, ledgerInfoRetriever LedgerInfoRetriever) ledger.ConfigHistoryRetriever
+	// ExportHistory passes to the given function all the entries of the history of the given ledger
+	ExportHistory(ledgerID string, fn func(*HistoryEntry) error) error
+	// ImportHistory adds the given entries, as exported by function `ExportHistory`, to the history of the given ledger
+	ImportHistory(ledgerID string, entries []*HistoryEntry) error
+	Drop(ledgerID string) error
 	Close()
 }
 
+// HistoryEntry is an entry of the history, i.e., the value of a key in a namespace
+// as committed by the block with number BlockNum
+type HistoryEntry struct {
+	Namespace string
+	Key       string
+	BlockNum  uint64
+	Value     []byte
+}
+
 type mgr struct {
 	dbProvider *dbProvider
 }
@@ -69,6 +83,27 @@ func (m *mgr) GetRetriever(ledgerID string, ledgerInfoRetriever LedgerInfoRetrie
 	return &retriever{dbHandle: m.dbProvider.getDB(ledgerID), ledgerInfoRetriever: ledgerInfoRetriever}
 }
 
+// ExportHistory implements the function in the interface 'Mgr'
+func (m *mgr) ExportHistory(ledgerID string, fn func(*HistoryEntry) error) error {
+	return m.dbProvider.getDB(ledgerID).exportEntries(func(kv *compositeKV) error {
+		return fn(&HistoryEntry{Namespace: kv.ns, Key: kv.key, BlockNum: kv.blockNum, Value: kv.value})
+	})
+}
+
+// ImportHistory implements the function in the interface 'Mgr'
+func (m *mgr) ImportHistory(ledgerID string, entries []*HistoryEntry) error {
+	batch := newBatch()
+	for _, entry := range entries {
+		batch.add(entry.Namespace, entry.Key, entry.BlockNum, entry.Value)
+	}
+	return m.dbProvider.getDB(ledgerID).writeBatch(batch, true)
+}
+
+// Drop implements the function in the interface 'Mgr'
+func (m *mgr) Drop(ledgerID string) error {
+	return m.dbProvider.getDB(ledgerID).DeleteAll()
+}
+
 // Close implements the function in the interface 'Mgr'
 func (m *mgr) Close() {
 	m.dbProvider.Close()
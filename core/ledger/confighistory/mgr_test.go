@@ -94,6 +94,41 @@ func TestMgr(t *testing.T) {
 	})
 }
 
+func TestExportImportHistory(t *testing.T) {
+	dbPath := "/tmp/fabric/core/ledger/confighistory"
+	env := newTestEnv(t, dbPath)
+	mgr := env.mgr
+	defer env.cleanup()
+	chaincodeName := "chaincode1"
+	dummyLedgerInfoRetriever := &dummyLedgerInfoRetriever{info: &common.BlockchainInfo{Height: 101}}
+	for _, committingBlockNum := range []uint64{5, 10} {
+		collConfigPackage := sampleCollectionConfigPackage("source", committingBlockNum)
+		assert.NoError(t, mgr.HandleStateUpdates("source", sampleStateUpdate(t, chaincodeName, collConfigPackage), committingBlockNum))
+	}
+
+	var entries []*HistoryEntry
+	assert.NoError(t, mgr.ExportHistory("source", func(entry *HistoryEntry) error {
+		entries = append(entries, entry)
+		return nil
+	}))
+	assert.Len(t, entries, 2)
+	// the more recent entries come first
+	assert.Equal(t, uint64(10), entries[0].BlockNum)
+	assert.Equal(t, "lscc", entries[0].Namespace)
+	assert.Equal(t, constructCollectionConfigKey(chaincodeName), entries[0].Key)
+
+	assert.NoError(t, mgr.ImportHistory("target", entries))
+	retriever := mgr.GetRetriever("target", dummyLedgerInfoRetriever)
+	for _, committingBlockNum := range []uint64{5, 10} {
+		retrievedConfig, err := retriever.CollectionConfigAt(committingBlockNum, chaincodeName)
+		assert.NoError(t, err)
+		assert.Equal(t, sampleCollectionConfigPackage("source", committingBlockNum), retrievedConfig.CollectionConfig)
+	}
+	retrievedConfig, err := retriever.MostRecentCollectionConfigBelow(100, chaincodeName)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10), retrievedConfig.CommittingBlockNum)
+}
+
 type testEnv struct {
 	dbPath string
 	mgr    Mgr
@@ -27,6 +27,8 @@ import (
 type HistoryDBProvider interface {
 	// GetDBHandle returns a handle to a HistoryDB
 	GetDBHandle(id string) (HistoryDB, error)
+	// Drop drops all the data of the HistoryDB with the given id
+	Drop(id string) error
 	// Close closes all the HistoryDB instances and releases any resources held by HistoryDBProvider
 	Close()
 }
@@ -36,6 +38,9 @@ type HistoryDB interface {
 	NewHistoryQueryExecutor(blockStore blkstorage.BlockStore) (ledger.HistoryQueryExecutor, error)
 	Commit(block *common.Block) error
 	GetLastSavepoint() (*version.Height, error)
+	// InitSavepoint sets the savepoint of an empty history database. This is used when a ledger is bootstrapped
+	// from a snapshot, in which case the history of the keys is recorded only for the blocks following the snapshot
+	InitSavepoint(height *version.Height) error
 	ShouldRecover(lastAvailableBlock uint64) (bool, uint64, error)
 	CommitLostBlock(blockAndPvtdata *ledger.BlockAndPvtData) error
 }
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package historyleveldb
 
 import (
+	"fmt"
+
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
@@ -43,6 +45,11 @@ func (provider *HistoryDBProvider) GetDBHandle(dbName string) (historydb.History
 	return newHistoryDB(provider.dbProvider.GetDBHandle(dbName), dbName), nil
 }
 
+// Drop deletes all the data of the named database
+func (provider *HistoryDBProvider) Drop(dbName string) error {
+	return provider.dbProvider.GetDBHandle(dbName).DeleteAll()
+}
+
 // Close closes the underlying db
 func (provider *HistoryDBProvider) Close() {
 	provider.dbProvider.Close()
@@ -178,6 +185,18 @@ func (historyDB *historyDB) GetLastSavepoint() (*version.Height, error) {
 	return height, nil
 }
 
+// InitSavepoint implements method in HistoryDB interface
+func (historyDB *historyDB) InitSavepoint(height *version.Height) error {
+	savepoint, err := historyDB.GetLastSavepoint()
+	if err != nil {
+		return err
+	}
+	if savepoint != nil {
+		return fmt.Errorf("the history database is not empty, savepoint = %#v", savepoint)
+	}
+	return historyDB.db.Put(savePointKey, height.ToBytes(), true)
+}
+
 // ShouldRecover implements method in interface kvledger.Recoverer
 func (historyDB *historyDB) ShouldRecover(lastAvailableBlock uint64) (bool, uint64, error) {
 	if !ledgerconfig.IsHistoryDBEnabled() {
@@ -25,6 +25,7 @@ import (
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	util2 "github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
 	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/ledger/queryresult"
@@ -99,6 +100,21 @@ func TestSavepoint(t *testing.T) {
 	testutil.AssertNoError(t, err, "Error upon historyDatabase.ShouldRecover()")
 	testutil.AssertEquals(t, status, true)
 	testutil.AssertEquals(t, blockNum, uint64(3))
+
+	// the savepoint can be initialized only for an empty history database
+	testutil.AssertError(t, env.testHistoryDB.InitSavepoint(version.NewHeight(5, 0)), "Expected an error for a non-empty history database")
+}
+
+func TestInitSavepoint(t *testing.T) {
+	env := newTestHistoryEnv(t)
+	defer env.cleanup()
+	testutil.AssertNoError(t, env.testHistoryDB.InitSavepoint(version.NewHeight(5, 2)), "")
+	savepoint, err := env.testHistoryDB.GetLastSavepoint()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, savepoint, version.NewHeight(5, 2))
+	status, _, err := env.testHistoryDB.ShouldRecover(5)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, status, false)
 }
 
 func TestHistory(t *testing.T) {
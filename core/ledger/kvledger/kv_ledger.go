@@ -7,9 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package kvledger
 
 import (
-	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/hyperledger/fabric/core/ledger/pvtdatapolicy"
 
@@ -32,6 +32,10 @@ import (
 
 var logger = flogging.MustGetLogger("kvledger")
 
+// recommitProgressInterval is the number of blocks after which the progress of recommitting
+// the blocks to the state and history databases is logged
+const recommitProgressInterval = 1000
+
 // KVLedger provides an implementation of `ledger.PeerLedger`.
 // This implementation provides a key-value based data model
 type kvLedger struct {
@@ -41,6 +45,7 @@ type kvLedger struct {
 	historyDB              historydb.HistoryDB
 	configHistoryRetriever ledger.ConfigHistoryRetriever
 	blockAPIsRWLock        *sync.RWMutex
+	metrics                *ledgerMetrics
 }
 
 // NewKVLedger constructs new `KVLedger`
@@ -57,7 +62,8 @@ func newKVLedger(
 	stateListeners = append(stateListeners, configHistoryMgr)
 	// Create a kvLedger for this chain/ledger, which encasulates the underlying
 	// id store, blockstore, txmgr (state database), history database
-	l := &kvLedger{ledgerID: ledgerID, blockStore: blockStore, historyDB: historyDB, blockAPIsRWLock: &sync.RWMutex{}}
+	l := &kvLedger{ledgerID: ledgerID, blockStore: blockStore, historyDB: historyDB, blockAPIsRWLock: &sync.RWMutex{},
+		metrics: newLedgerMetrics(ledgerID)}
 
 	// TODO Move the function `GetChaincodeEventListener` to ledger interface and
 	// this functionality of regiserting for events to ledgermgmt package so that this
@@ -138,8 +144,9 @@ func (l *kvLedger) recoverDBs() error {
 }
 
 //recommitLostBlocks retrieves blocks in specified range and commit the write set to either
-//state DB or history DB or both
+//state DB or history DB or both. The progress is logged every `recommitProgressInterval` blocks
 func (l *kvLedger) recommitLostBlocks(firstBlockNum uint64, lastBlockNum uint64, recoverables ...recoverable) error {
+	logger.Infof("Channel [%s]: Recommitting blocks [%d] to [%d] to rebuild the databases", l.ledgerID, firstBlockNum, lastBlockNum)
 	var err error
 	var blockAndPvtdata *ledger.BlockAndPvtData
 	for blockNumber := firstBlockNum; blockNumber <= lastBlockNum; blockNumber++ {
@@ -151,7 +158,12 @@ func (l *kvLedger) recommitLostBlocks(firstBlockNum uint64, lastBlockNum uint64,
 				return err
 			}
 		}
+		if numRecommitted := blockNumber - firstBlockNum + 1; numRecommitted%recommitProgressInterval == 0 && blockNumber != lastBlockNum {
+			logger.Infof("Channel [%s]: Recommitted block [%d], %d of %d blocks done", l.ledgerID, blockNumber,
+				numRecommitted, lastBlockNum-firstBlockNum+1)
+		}
 	}
+	logger.Infof("Channel [%s]: Recommitted blocks [%d] to [%d]", l.ledgerID, firstBlockNum, lastBlockNum)
 	return nil
 }
 
@@ -222,9 +234,17 @@ func (l *kvLedger) GetTxValidationCodeByTxID(txID string) (peer.TxValidationCode
 	return txValidationCode, err
 }
 
-//Prune prunes the blocks/transactions that satisfy the given policy
+//Prune prunes the blocks/transactions that satisfy the given policy.
+//Blocks are removed at the granularity of the block files, hence some of the blocks
+//selected by the policy may be retained. The last config block and the blocks that
+//are needed for recovering the state and history databases are never pruned
 func (l *kvLedger) Prune(policy commonledger.PrunePolicy) error {
-	return errors.New("Not yet implemented")
+	retainFromBlockNum, err := l.computeRetainFromBlockNum(policy)
+	if err != nil {
+		return err
+	}
+	logger.Infof("Channel [%s]: pruning blocks preceding block [%d]", l.ledgerID, retainFromBlockNum)
+	return l.blockStore.Prune(retainFromBlockNum)
 }
 
 // NewTxSimulator returns new `ledger.TxSimulator`
@@ -254,32 +274,42 @@ func (l *kvLedger) CommitWithPvtData(pvtdataAndBlock *ledger.BlockAndPvtData) er
 	blockNo := pvtdataAndBlock.Block.Header.Number
 
 	logger.Debugf("Channel [%s]: Validating state for block [%d]", l.ledgerID, blockNo)
+	startStateValidation := time.Now()
 	err = l.txtmgmt.ValidateAndPrepare(pvtdataAndBlock, true)
 	if err != nil {
 		return err
 	}
+	l.metrics.stateValidationDuration.RecordDuration(time.Since(startStateValidation))
 
 	logger.Debugf("Channel [%s]: Committing block [%d] to storage", l.ledgerID, blockNo)
 
 	l.blockAPIsRWLock.Lock()
 	defer l.blockAPIsRWLock.Unlock()
+	startBlockStoreCommit := time.Now()
 	if err = l.blockStore.CommitWithPvtData(pvtdataAndBlock); err != nil {
 		return err
 	}
+	l.metrics.blockStoreCommitDuration.RecordDuration(time.Since(startBlockStoreCommit))
 	logger.Infof("Channel [%s]: Committed block [%d] with %d transaction(s)", l.ledgerID, block.Header.Number, len(block.Data.Data))
 
 	logger.Debugf("Channel [%s]: Committing block [%d] transactions to state database", l.ledgerID, blockNo)
+	startStateCommit := time.Now()
 	if err = l.txtmgmt.Commit(); err != nil {
 		panic(fmt.Errorf(`Error during commit to txmgr:%s`, err))
 	}
+	l.metrics.stateCommitDuration.RecordDuration(time.Since(startStateCommit))
 
 	// History database could be written in parallel with state and/or async as a future optimization
 	if ledgerconfig.IsHistoryDBEnabled() {
 		logger.Debugf("Channel [%s]: Committing block [%d] transactions to history database", l.ledgerID, blockNo)
+		startHistoryCommit := time.Now()
 		if err := l.historyDB.Commit(block); err != nil {
 			panic(fmt.Errorf(`Error during commit to history db:%s`, err))
 		}
+		l.metrics.historyCommitDuration.RecordDuration(time.Since(startHistoryCommit))
 	}
+	l.metrics.committedBlocks.Inc(1)
+	l.metrics.committedTransactions.Inc(int64(len(block.Data.Data)))
 	return nil
 }
 
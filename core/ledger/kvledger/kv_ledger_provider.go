@@ -35,8 +35,9 @@ var (
 	// ErrLedgerNotOpened is thrown by a CloseLedger call if a ledger with the given id has not been opened
 	ErrLedgerNotOpened = errors.New("Ledger is not opened yet")
 
-	underConstructionLedgerKey = []byte("underConstructionLedgerKey")
-	ledgerKeyPrefix            = []byte("l")
+	underConstructionLedgerKey             = []byte("underConstructionLedgerKey")
+	underConstructionFromSnapshotLedgerKey = []byte("underConstructionFromSnapshotLedgerKey")
+	ledgerKeyPrefix                        = []byte("l")
 )
 
 // Provider implements interface ledger.PeerLedgerProvider
@@ -48,6 +49,7 @@ type Provider struct {
 	configHistoryMgr    confighistory.Mgr
 	stateListeners      []ledger.StateListener
 	bookkeepingProvider bookkeeping.Provider
+	fileLock            *leveldbhelper.FileLock
 }
 
 // NewProvider instantiates a new Provider.
@@ -56,6 +58,13 @@ func NewProvider() (ledger.PeerLedgerProvider, error) {
 
 	logger.Info("Initializing ledger provider")
 
+	// Acquire the lock that prevents other processes (e.g., the peer node commands
+	// that rollback or reset the ledgers) from using the ledgers at the same time
+	fileLock := leveldbhelper.NewFileLock(ledgerconfig.GetFileLockPath())
+	if err := fileLock.Lock(); err != nil {
+		return nil, fmt.Errorf("the ledgers are in use by another process, such as a running peer: %s", err)
+	}
+
 	// Initialize the ID store (inventory of chainIds/ledgerIds)
 	idStore := openIDStore(ledgerconfig.GetLedgerProviderPath())
 
@@ -73,8 +82,9 @@ func NewProvider() (ledger.PeerLedgerProvider, error) {
 	// Initialize config history mgr
 	configHistoryMgr := confighistory.NewMgr()
 	logger.Info("ledger provider Initialized")
-	provider := &Provider{idStore, ledgerStoreProvider, vdbProvider, historydbProvider, configHistoryMgr, nil, bookkeepingProvider}
+	provider := &Provider{idStore, ledgerStoreProvider, vdbProvider, historydbProvider, configHistoryMgr, nil, bookkeepingProvider, fileLock}
 	provider.recoverUnderConstructionLedger()
+	provider.recoverUnderConstructionFromSnapshotLedger()
 	return provider, nil
 }
 
@@ -180,6 +190,7 @@ func (provider *Provider) Close() {
 	provider.historydbProvider.Close()
 	provider.bookkeepingProvider.Close()
 	provider.configHistoryMgr.Close()
+	provider.fileLock.Unlock()
 }
 
 // recoverUnderConstructionLedger checks whether the under construction flag is set - this would be the case
@@ -219,6 +230,29 @@ func (provider *Provider) recoverUnderConstructionLedger() {
 	return
 }
 
+// recoverUnderConstructionFromSnapshotLedger checks whether the flag that is set while creating a ledger from a
+// snapshot is set - this would be the case if a crash had happened during the creation of the ledger from the
+// snapshot. As the ledger is marked as created atomically with unsetting the flag, the ledger is known to be
+// incomplete. Hence, the recovery removes all the data of the ledger and clears the flag
+func (provider *Provider) recoverUnderConstructionFromSnapshotLedger() {
+	ledgerID, err := provider.idStore.getUnderConstructionFromSnapshotFlag()
+	panicOnErr(err, "Error while checking whether the under construction from snapshot flag is set")
+	if ledgerID == "" {
+		return
+	}
+	logger.Infof("ledger [%s] found as under construction from a snapshot. Removing the partially created ledger", ledgerID)
+	panicOnErr(provider.removeLedgerData(ledgerID), "Error while removing the data of ledger [%s]", ledgerID)
+	panicOnErr(provider.idStore.unsetUnderConstructionFromSnapshotFlag(), "Error while unsetting under construction flag")
+}
+
+// removeLedgerData removes the block store, the pvt data store, and the databases of the given ledger
+func (provider *Provider) removeLedgerData(ledgerID string) error {
+	if err := provider.dropDBs(ledgerID); err != nil {
+		return err
+	}
+	return provider.ledgerStoreProvider.Drop(ledgerID)
+}
+
 // runCleanup cleans up blockstorage, statedb, and historydb for what
 // may have got created during in-complete ledger creation
 func (provider *Provider) runCleanup(ledgerID string) error {
@@ -267,6 +301,22 @@ func (s *idStore) getUnderConstructionFlag() (string, error) {
 	return string(val), nil
 }
 
+func (s *idStore) setUnderConstructionFromSnapshotFlag(ledgerID string) error {
+	return s.db.Put(underConstructionFromSnapshotLedgerKey, []byte(ledgerID), true)
+}
+
+func (s *idStore) unsetUnderConstructionFromSnapshotFlag() error {
+	return s.db.Delete(underConstructionFromSnapshotLedgerKey, true)
+}
+
+func (s *idStore) getUnderConstructionFromSnapshotFlag() (string, error) {
+	val, err := s.db.Get(underConstructionFromSnapshotLedgerKey)
+	if err != nil {
+		return "", err
+	}
+	return string(val), nil
+}
+
 func (s *idStore) createLedgerID(ledgerID string, gb *common.Block) error {
 	key := s.encodeLedgerKey(ledgerID)
 	var val []byte
@@ -283,6 +333,7 @@ func (s *idStore) createLedgerID(ledgerID string, gb *common.Block) error {
 	batch := &leveldb.Batch{}
 	batch.Put(key, val)
 	batch.Delete(underConstructionLedgerKey)
+	batch.Delete(underConstructionFromSnapshotLedgerKey)
 	return s.db.WriteBatch(batch, true)
 }
 
@@ -302,7 +353,8 @@ func (s *idStore) getAllLedgerIds() ([]string, error) {
 	defer itr.Release()
 	itr.First()
 	for itr.Valid() {
-		if bytes.Equal(itr.Key(), underConstructionLedgerKey) {
+		if !bytes.HasPrefix(itr.Key(), ledgerKeyPrefix) {
+			itr.Next()
 			continue
 		}
 		id := string(s.decodeLedgerID(itr.Key()))
@@ -14,6 +14,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/common/privdata"
 	lgr "github.com/hyperledger/fabric/core/ledger"
@@ -120,6 +121,42 @@ func TestKVLedgerBlockStorage(t *testing.T) {
 	testutil.AssertEquals(t, validCode, peer.TxValidationCode_VALID)
 }
 
+func TestKVLedgerMetrics(t *testing.T) {
+	ts, restore := metrics.UseTestScope()
+	defer restore()
+
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider, _ := NewProvider()
+	defer provider.Close()
+
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	ledger, err := provider.Create(gb)
+	assert.NoError(t, err)
+	defer ledger.Close()
+
+	simulator, _ := ledger.NewTxSimulator(util.GenerateUUID())
+	simulator.SetState("ns1", "key1", []byte("value1"))
+	simulator.Done()
+	simRes, _ := simulator.GetTxSimulationResults()
+	pubSimBytes, _ := simRes.GetPubSimulationBytes()
+	block1 := bg.NextBlock([][]byte{pubSimBytes, pubSimBytes})
+	assert.NoError(t, ledger.CommitWithPvtData(&lgr.BlockAndPvtData{Block: block1}))
+
+	// the genesis block and block1 have been committed
+	tags := map[string]string{"channel": "testLedger"}
+	for _, stage := range []string{
+		"ledger.state_validation_duration",
+		"ledger.block_store_commit_duration",
+		"ledger.state_commit_duration",
+		"ledger.history_commit_duration",
+	} {
+		assert.Len(t, ts.HistogramValues(stage, tags), 2, stage)
+	}
+	assert.Equal(t, int64(2), ts.CounterValue("ledger.committed_blocks", tags))
+	assert.Equal(t, int64(3), ts.CounterValue("ledger.committed_transactions", tags))
+}
+
 func TestKVLedgerBlockStorageWithPvtdata(t *testing.T) {
 	t.Skip()
 	env := newTestEnv(t)
@@ -0,0 +1,34 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"github.com/hyperledger/fabric/common/metrics"
+)
+
+// ledgerMetrics holds the durations of the stages of the commit of the blocks of a channel,
+// along with the number of blocks and transactions committed
+type ledgerMetrics struct {
+	stateValidationDuration  metrics.Histogram
+	blockStoreCommitDuration metrics.Histogram
+	stateCommitDuration      metrics.Histogram
+	historyCommitDuration    metrics.Histogram
+	committedBlocks          metrics.Counter
+	committedTransactions    metrics.Counter
+}
+
+func newLedgerMetrics(ledgerID string) *ledgerMetrics {
+	scope := metrics.GetRootScope().SubScope("ledger").Tagged(map[string]string{"channel": ledgerID})
+	return &ledgerMetrics{
+		stateValidationDuration:  scope.Histogram("state_validation_duration"),
+		blockStoreCommitDuration: scope.Histogram("block_store_commit_duration"),
+		stateCommitDuration:      scope.Histogram("state_commit_duration"),
+		historyCommitDuration:    scope.Histogram("history_commit_duration"),
+		committedBlocks:          scope.Counter("committed_blocks"),
+		committedTransactions:    scope.Counter("committed_transactions"),
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	commonledger "github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// computeRetainFromBlockNum returns the number of the first block to be retained by the given policy.
+// The returned number is lowered, if required, so that the last config block (needed for
+// initializing the channel on peer startup) and the blocks that the state and history
+// databases may need for recovery are retained
+func (l *kvLedger) computeRetainFromBlockNum(policy commonledger.PrunePolicy) (uint64, error) {
+	bcInfo, err := l.blockStore.GetBlockchainInfo()
+	if err != nil {
+		return 0, err
+	}
+	if bcInfo.Height == 0 {
+		return 0, nil
+	}
+	lastBlockNum := bcInfo.Height - 1
+	lastBlock, err := l.blockStore.RetrieveBlockByNumber(lastBlockNum)
+	if err != nil {
+		return 0, err
+	}
+	lastConfigBlockNum, err := utils.GetLastConfigIndexFromBlock(lastBlock)
+	if err != nil {
+		return 0, errors.WithMessage(err, "could not retrieve the last config block index")
+	}
+
+	var retainFromBlockNum uint64
+	switch p := policy.(type) {
+	case *commonledger.RetainLastNBlocksPolicy:
+		if p.NumBlocks == 0 {
+			return 0, errors.New("the number of blocks to retain must be greater than zero")
+		}
+		if p.NumBlocks < bcInfo.Height {
+			retainFromBlockNum = bcInfo.Height - p.NumBlocks
+		}
+	case *commonledger.RetainSinceTimestampPolicy:
+		if retainFromBlockNum, err = l.firstBlockSince(p.Timestamp, lastBlockNum); err != nil {
+			return 0, err
+		}
+	case *commonledger.RetainSinceLastConfigBlockPolicy:
+		retainFromBlockNum = lastConfigBlockNum
+	default:
+		return 0, errors.Errorf("unsupported prune policy type %T", policy)
+	}
+
+	if retainFromBlockNum > lastConfigBlockNum {
+		logger.Infof("Channel [%s]: retaining blocks from the last config block [%d] instead of block [%d]",
+			l.ledgerID, lastConfigBlockNum, retainFromBlockNum)
+		retainFromBlockNum = lastConfigBlockNum
+	}
+	for _, r := range []recoverable{l.txtmgmt, l.historyDB} {
+		recoverFlag, firstBlockNum, err := r.ShouldRecover(lastBlockNum)
+		if err != nil {
+			return 0, err
+		}
+		if recoverFlag && firstBlockNum < retainFromBlockNum {
+			retainFromBlockNum = firstBlockNum
+		}
+	}
+	return retainFromBlockNum, nil
+}
+
+// firstBlockSince scans the available blocks and returns the number of the first
+// block whose first transaction was created at or after the given timestamp. If no
+// such block exists, the last block number is returned
+func (l *kvLedger) firstBlockSince(timestamp time.Time, lastBlockNum uint64) (uint64, error) {
+	firstBlockNum, err := l.blockStore.GetFirstAvailableBlockNum()
+	if err != nil {
+		return 0, err
+	}
+	itr, err := l.blockStore.RetrieveBlocks(firstBlockNum)
+	if err != nil {
+		return 0, err
+	}
+	defer itr.Close()
+	for blockNum := firstBlockNum; blockNum < lastBlockNum; blockNum++ {
+		res, err := itr.Next()
+		if err != nil {
+			return 0, err
+		}
+		blockTimestamp, err := getBlockTimestamp(res.(*common.Block))
+		if err != nil {
+			return 0, errors.WithMessage(err, fmt.Sprintf("could not retrieve the timestamp of block %d", blockNum))
+		}
+		if !blockTimestamp.Before(timestamp) {
+			return blockNum, nil
+		}
+	}
+	return lastBlockNum, nil
+}
+
+// getBlockTimestamp returns the timestamp of the channel header of the first transaction in the block
+func getBlockTimestamp(block *common.Block) (time.Time, error) {
+	env, err := utils.ExtractEnvelope(block, 0)
+	if err != nil {
+		return time.Time{}, err
+	}
+	chdr, err := utils.ChannelHeader(env)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return ptypes.Timestamp(chdr.Timestamp)
+}
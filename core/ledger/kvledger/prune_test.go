@@ -0,0 +1,163 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	commonledger "github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/util"
+	lgr "github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/protos/common"
+	putils "github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKVLedgerPrune(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider, _ := NewProvider()
+	defer provider.Close()
+
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	l, err := provider.Create(gb)
+	assert.NoError(t, err)
+	defer l.Close()
+	kvl := l.(*kvLedger)
+
+	// blocks 1 to 10 are committed; block 8 is treated as a config block
+	var ts time.Time
+	for i := uint64(1); i <= 10; i++ {
+		if i == 6 {
+			ts = time.Now()
+		}
+		lastConfigBlockNum := uint64(0)
+		if i >= 8 {
+			lastConfigBlockNum = 8
+		}
+		commitBlockWithLastConfig(t, l, bg, lastConfigBlockNum)
+	}
+
+	testCases := []struct {
+		policy             commonledger.PrunePolicy
+		retainFromBlockNum uint64
+	}{
+		{&commonledger.RetainLastNBlocksPolicy{NumBlocks: 5}, 6},
+		{&commonledger.RetainLastNBlocksPolicy{NumBlocks: 20}, 0},
+		// the last config block is always retained
+		{&commonledger.RetainLastNBlocksPolicy{NumBlocks: 1}, 8},
+		{&commonledger.RetainSinceTimestampPolicy{Timestamp: ts}, 6},
+		{&commonledger.RetainSinceTimestampPolicy{Timestamp: time.Now().Add(time.Hour)}, 8},
+		{&commonledger.RetainSinceLastConfigBlockPolicy{}, 8},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%T%v", tc.policy, tc.policy), func(t *testing.T) {
+			retainFromBlockNum, err := kvl.computeRetainFromBlockNum(tc.policy)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.retainFromBlockNum, retainFromBlockNum)
+		})
+	}
+
+	_, err = kvl.computeRetainFromBlockNum(&commonledger.RetainLastNBlocksPolicy{})
+	assert.EqualError(t, err, "the number of blocks to retain must be greater than zero")
+	err = l.Prune("unsupported policy")
+	assert.EqualError(t, err, "unsupported prune policy type string")
+
+	// all the blocks are in the same block file, hence no block gets pruned
+	assert.NoError(t, l.Prune(&commonledger.RetainSinceLastConfigBlockPolicy{}))
+	block, err := l.GetBlockByNumber(0)
+	assert.NoError(t, err)
+	assert.Equal(t, gb, block)
+	bcInfo, err := l.GetBlockchainInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(11), bcInfo.Height)
+}
+
+func TestKVLedgerPruneBlockFiles(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	// the first block file only fits the genesis block, so that it can be pruned
+	viper.Set("ledger.blockchain.maxBlockfileSize", len(putils.MarshalOrPanic(gb))+16)
+	defer viper.Set("ledger.blockchain.maxBlockfileSize", 0)
+	provider, _ := NewProvider()
+	defer provider.Close()
+
+	l, err := provider.Create(gb)
+	assert.NoError(t, err)
+	defer l.Close()
+	kvl := l.(*kvLedger)
+
+	// blocks 1 to 20 are committed; block 15 is treated as a config block
+	for i := uint64(1); i <= 20; i++ {
+		lastConfigBlockNum := uint64(0)
+		if i >= 15 {
+			lastConfigBlockNum = 15
+		}
+		commitBlockWithLastConfig(t, l, bg, lastConfigBlockNum)
+	}
+	bcInfo, err := l.GetBlockchainInfo()
+	assert.NoError(t, err)
+
+	assert.NoError(t, l.Prune(&commonledger.RetainSinceLastConfigBlockPolicy{}))
+
+	// at least the block file holding the genesis block has been removed,
+	// and the last config block has been retained
+	firstBlockNum, err := kvl.blockStore.GetFirstAvailableBlockNum()
+	assert.NoError(t, err)
+	assert.True(t, firstBlockNum > 0 && firstBlockNum <= 15, "first available block is %d", firstBlockNum)
+	for blockNum := uint64(0); blockNum < firstBlockNum; blockNum++ {
+		_, err := l.GetBlockByNumber(blockNum)
+		assert.Equal(t, blkstorage.ErrPruned, errors.Cause(err))
+	}
+	itr, err := l.GetBlocksIterator(0)
+	assert.NoError(t, err)
+	_, err = itr.Next()
+	itr.Close()
+	assert.Equal(t, blkstorage.ErrPruned, errors.Cause(err))
+	for blockNum := firstBlockNum; blockNum <= 20; blockNum++ {
+		block, err := l.GetBlockByNumber(blockNum)
+		assert.NoError(t, err)
+		assert.Equal(t, blockNum, block.Header.Number)
+	}
+
+	// the blockchain info reflects the last block, which is not affected by the pruning
+	prunedBcInfo, err := l.GetBlockchainInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, bcInfo, prunedBcInfo)
+	assert.Equal(t, uint64(21), prunedBcInfo.Height)
+
+	// pruning again with the same policy is a no-op, and the ledger keeps accepting blocks
+	assert.NoError(t, l.Prune(&commonledger.RetainSinceLastConfigBlockPolicy{}))
+	commitBlockWithLastConfig(t, l, bg, 15)
+	prunedBcInfo, err = l.GetBlockchainInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(22), prunedBcInfo.Height)
+	assert.Equal(t, bcInfo.CurrentBlockHash, prunedBcInfo.PreviousBlockHash)
+}
+
+func commitBlockWithLastConfig(t *testing.T, l lgr.PeerLedger, bg *testutil.BlockGenerator, lastConfigBlockNum uint64) {
+	simulator, err := l.NewTxSimulator(util.GenerateUUID())
+	assert.NoError(t, err)
+	simulator.SetState("ns1", "key1", []byte("value1"))
+	simulator.Done()
+	simRes, err := simulator.GetTxSimulationResults()
+	assert.NoError(t, err)
+	pubSimBytes, err := simRes.GetPubSimulationBytes()
+	assert.NoError(t, err)
+	block := bg.NextBlock([][]byte{pubSimBytes})
+	block.Metadata.Metadata[common.BlockMetadataIndex_LAST_CONFIG] = putils.MarshalOrPanic(&common.Metadata{
+		Value: putils.MarshalOrPanic(&common.LastConfig{Index: lastConfigBlockNum}),
+	})
+	assert.NoError(t, l.CommitWithPvtData(&lgr.BlockAndPvtData{Block: block}))
+}
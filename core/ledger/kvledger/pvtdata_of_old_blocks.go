@@ -0,0 +1,163 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"bytes"
+
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
+	putils "github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// CommitPvtDataOfOldBlocks commits the private data corresponding to already committed blocks.
+// The private data is first committed to the state database and then to the pvt data store so that,
+// if a crash happens in between, the missing data entries are retained in the pvt data store and
+// the private data is simply fetched and committed again. The private data of the blocks that have
+// been pruned is ignored and their missing data entries are removed, as it cannot be verified anymore
+func (l *kvLedger) CommitPvtDataOfOldBlocks(blocksPvtData []*ledger.BlockPvtData) ([]*ledger.PvtdataHashMismatch, error) {
+	validPvtData, hashMismatches, prunedBlkNums, err := l.validatePvtDataOfOldBlocks(blocksPvtData)
+	if err != nil {
+		return nil, err
+	}
+	if len(prunedBlkNums) > 0 {
+		logger.Infof("Channel [%s]: Removing the missing pvt data entries of the pruned blocks %v", l.ledgerID, prunedBlkNums)
+		if err := l.blockStore.RemoveMissingPvtDataOfBlocks(prunedBlkNums); err != nil {
+			return nil, err
+		}
+	}
+	if len(validPvtData) == 0 {
+		return hashMismatches, nil
+	}
+	logger.Debugf("Channel [%s]: Committing pvt data of [%d] old blocks to state database", l.ledgerID, len(validPvtData))
+	if err := l.txtmgmt.CommitPvtDataOfOldBlocks(validPvtData); err != nil {
+		return nil, err
+	}
+	logger.Debugf("Channel [%s]: Committing pvt data of [%d] old blocks to pvt data store", l.ledgerID, len(validPvtData))
+	if err := l.blockStore.CommitPvtDataOfOldBlocks(validPvtData); err != nil {
+		return nil, err
+	}
+	return hashMismatches, nil
+}
+
+// GetMissingPvtDataTracker returns the MissingPvtDataTracker
+func (l *kvLedger) GetMissingPvtDataTracker() (ledger.MissingPvtDataTracker, error) {
+	return l.blockStore, nil
+}
+
+// validatePvtDataOfOldBlocks verifies the hashes of the supplied collection write sets against the hashes
+// present in the corresponding blocks. It returns the write sets that match, grouped by block number, along
+// with the information about the write sets that do not match and the numbers of the blocks that have been
+// pruned. The write sets of the transactions that have been invalidated are ignored
+func (l *kvLedger) validatePvtDataOfOldBlocks(blocksPvtData []*ledger.BlockPvtData) (map[uint64][]*ledger.TxPvtData, []*ledger.PvtdataHashMismatch, []uint64, error) {
+	validPvtData := make(map[uint64][]*ledger.TxPvtData)
+	var hashMismatches []*ledger.PvtdataHashMismatch
+	var prunedBlkNums []uint64
+	for _, blockPvtData := range blocksPvtData {
+		block, err := l.blockStore.RetrieveBlockByNumber(blockPvtData.BlockNum)
+		if errors.Cause(err) == blkstorage.ErrPruned {
+			logger.Warningf("Channel [%s]: Ignoring the pvt data of block [%d] as the block has been pruned",
+				l.ledgerID, blockPvtData.BlockNum)
+			prunedBlkNums = append(prunedBlkNums, blockPvtData.BlockNum)
+			continue
+		}
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		txsFilter := util.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+		for seqInBlock, txPvtData := range blockPvtData.WriteSets {
+			if txPvtData.WriteSet == nil {
+				continue
+			}
+			if seqInBlock >= uint64(len(block.Data.Data)) {
+				logger.Warningf("Channel [%s]: Ignoring the pvt data of non-existing tx [%d] in block [%d]",
+					l.ledgerID, seqInBlock, blockPvtData.BlockNum)
+				continue
+			}
+			if txsFilter.IsInvalid(int(seqInBlock)) {
+				logger.Debugf("Channel [%s]: Ignoring the pvt data of invalid tx [%d] in block [%d]",
+					l.ledgerID, seqInBlock, blockPvtData.BlockNum)
+				continue
+			}
+			collHashes, err := retrievePvtRwSetHashes(block.Data.Data[seqInBlock])
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			validWriteSet := &rwset.TxPvtReadWriteSet{DataModel: txPvtData.WriteSet.DataModel}
+			for _, nsPvtRwSet := range txPvtData.WriteSet.NsPvtRwset {
+				validNsPvtRwSet := &rwset.NsPvtReadWriteSet{Namespace: nsPvtRwSet.Namespace}
+				for _, collPvtRwSet := range nsPvtRwSet.CollectionPvtRwset {
+					expectedHash := collHashes[nsPvtRwSet.Namespace][collPvtRwSet.CollectionName]
+					if !bytes.Equal(util.ComputeHash(collPvtRwSet.Rwset), expectedHash) {
+						logger.Warningf("Channel [%s]: Hash of pvt data for collection [%s:%s] of tx [%d] in block [%d] does not match with the corresponding hash in the block",
+							l.ledgerID, nsPvtRwSet.Namespace, collPvtRwSet.CollectionName, seqInBlock, blockPvtData.BlockNum)
+						hashMismatches = append(hashMismatches, &ledger.PvtdataHashMismatch{
+							BlockNum:     blockPvtData.BlockNum,
+							TxNum:        seqInBlock,
+							Namespace:    nsPvtRwSet.Namespace,
+							Collection:   collPvtRwSet.CollectionName,
+							ExpectedHash: expectedHash,
+						})
+						continue
+					}
+					validNsPvtRwSet.CollectionPvtRwset = append(validNsPvtRwSet.CollectionPvtRwset, collPvtRwSet)
+				}
+				if len(validNsPvtRwSet.CollectionPvtRwset) > 0 {
+					validWriteSet.NsPvtRwset = append(validWriteSet.NsPvtRwset, validNsPvtRwSet)
+				}
+			}
+			if len(validWriteSet.NsPvtRwset) > 0 {
+				validPvtData[blockPvtData.BlockNum] = append(validPvtData[blockPvtData.BlockNum],
+					&ledger.TxPvtData{SeqInBlock: seqInBlock, WriteSet: validWriteSet})
+			}
+		}
+	}
+	return validPvtData, hashMismatches, prunedBlkNums, nil
+}
+
+// retrievePvtRwSetHashes returns the hashes of the collection pvt write sets present in the given transaction
+// as a map of namespace to a map of collection to hash. An empty map is returned for a non-endorser transaction
+func retrievePvtRwSetHashes(envBytes []byte) (map[string]map[string][]byte, error) {
+	collHashes := make(map[string]map[string][]byte)
+	env, err := putils.GetEnvelopeFromBlock(envBytes)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := putils.GetPayload(env)
+	if err != nil {
+		return nil, err
+	}
+	chdr, err := putils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return nil, err
+	}
+	if common.HeaderType(chdr.Type) != common.HeaderType_ENDORSER_TRANSACTION {
+		return collHashes, nil
+	}
+	respPayload, err := putils.GetActionFromEnvelope(envBytes)
+	if err != nil {
+		return nil, err
+	}
+	txRWSet := &rwsetutil.TxRwSet{}
+	if err := txRWSet.FromProtoBytes(respPayload.Results); err != nil {
+		return nil, err
+	}
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		for _, collHashedRWSet := range nsRWSet.CollHashedRwSets {
+			if collHashes[nsRWSet.NameSpace] == nil {
+				collHashes[nsRWSet.NameSpace] = make(map[string][]byte)
+			}
+			collHashes[nsRWSet.NameSpace][collHashedRWSet.CollectionName] = collHashedRWSet.PvtRwSetHash
+		}
+	}
+	return collHashes, nil
+}
@@ -0,0 +1,179 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	lgr "github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommitPvtDataOfOldBlocks(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider, _ := NewProvider()
+	defer provider.Close()
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	ledger, err := provider.Create(gb)
+	assert.NoError(t, err)
+	defer ledger.Close()
+
+	collConfigBlk := prepareNextBlockForTestCollectionConfigs(t, ledger, bg, "simulationForCollConfig", "ns", map[string]uint64{"coll": 0})
+	assert.NoError(t, ledger.CommitWithPvtData(collConfigBlk))
+
+	// block 2 is committed without its pvt data
+	blk2 := prepareNextBlockForTest(t, ledger, bg, "SimulateForBlk2",
+		map[string]string{"key1": "value1.2", "key2": "value2.2"},
+		map[string]string{"key1": "pvtValue1.2", "key2": "pvtValue2.2"})
+	blk2PvtData := blk2.BlockPvtData
+	blk2.BlockPvtData = nil
+	blk2.Missing = []lgr.MissingPrivateData{{SeqInBlock: 0, Namespace: "ns", Collection: "coll"}}
+	assert.NoError(t, ledger.CommitWithPvtData(blk2))
+
+	// block 3 overwrites key1 along with its pvt data
+	blk3 := prepareNextBlockForTest(t, ledger, bg, "SimulateForBlk3",
+		map[string]string{"key1": "value1.3"},
+		map[string]string{"key1": "pvtValue1.3"})
+	assert.NoError(t, ledger.CommitWithPvtData(blk3))
+
+	tracker, err := ledger.GetMissingPvtDataTracker()
+	assert.NoError(t, err)
+	expectedMissingPvtDataInfo := make(lgr.MissingPvtDataInfo)
+	expectedMissingPvtDataInfo.Add(2, 0, "ns", "coll")
+	missingPvtDataInfo, err := tracker.GetMissingPvtDataInfoForMostRecentBlocks(10)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedMissingPvtDataInfo, missingPvtDataInfo)
+
+	// the pvt data that does not match the hash in the block is not committed
+	tamperedWriteSet := proto.Clone(blk2PvtData[0].WriteSet).(*rwset.TxPvtReadWriteSet)
+	tamperedWriteSet.NsPvtRwset[0].CollectionPvtRwset[0].Rwset = []byte("tampered-rwset")
+	hashMismatches, err := ledger.CommitPvtDataOfOldBlocks([]*lgr.BlockPvtData{
+		{BlockNum: 2, WriteSets: map[uint64]*lgr.TxPvtData{0: {SeqInBlock: 0, WriteSet: tamperedWriteSet}}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []*lgr.PvtdataHashMismatch{
+		{
+			BlockNum:     2,
+			TxNum:        0,
+			Namespace:    "ns",
+			Collection:   "coll",
+			ExpectedHash: util.ComputeHash(blk2PvtData[0].WriteSet.NsPvtRwset[0].CollectionPvtRwset[0].Rwset),
+		},
+	}, hashMismatches)
+	missingPvtDataInfo, err = tracker.GetMissingPvtDataInfoForMostRecentBlocks(10)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedMissingPvtDataInfo, missingPvtDataInfo)
+	checkStateDBForTest(t, ledger, nil, map[string]string{"key1": "pvtValue1.3"})
+	qe, err := ledger.NewQueryExecutor()
+	assert.NoError(t, err)
+	_, err = qe.GetPrivateData("ns", "coll", "key2")
+	qe.Done()
+	assert.Contains(t, err.Error(), "Private data matching public hash version is not available")
+
+	// the pvt data that matches the hash in the block is committed, except the stale write of key1
+	hashMismatches, err = ledger.CommitPvtDataOfOldBlocks([]*lgr.BlockPvtData{
+		{BlockNum: 2, WriteSets: blk2PvtData},
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, hashMismatches)
+	missingPvtDataInfo, err = tracker.GetMissingPvtDataInfoForMostRecentBlocks(10)
+	assert.NoError(t, err)
+	assert.Empty(t, missingPvtDataInfo)
+	checkStateDBForTest(t, ledger, nil, map[string]string{"key1": "pvtValue1.3", "key2": "pvtValue2.2"})
+
+	pvtdata, err := ledger.GetPvtDataByNum(2, nil)
+	assert.NoError(t, err)
+	assert.Len(t, pvtdata, 1)
+	assert.True(t, pvtdata[0].Has("ns", "coll"))
+
+	// the savepoint of the state database is not affected by the commit of the pvt data of old blocks
+	checkBCSummaryForTest(t, ledger, &bcSummary{stateDBSavePoint: 3})
+}
+
+func TestCommitPvtDataOfInvalidTxsAndPrunedBlocks(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	// every block is stored in a block file of its own, so that blocks can be pruned individually
+	viper.Set("ledger.blockchain.maxBlockfileSize", 1)
+	defer viper.Set("ledger.blockchain.maxBlockfileSize", 0)
+	provider, _ := NewProvider()
+	defer provider.Close()
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	ledger, err := provider.Create(gb)
+	assert.NoError(t, err)
+	defer ledger.Close()
+
+	collConfigBlk := prepareNextBlockForTestCollectionConfigs(t, ledger, bg, "simulationForCollConfig", "ns", map[string]uint64{"coll": 0})
+	assert.NoError(t, ledger.CommitWithPvtData(collConfigBlk))
+
+	// block 2 is committed without its pvt data
+	blk2 := prepareNextBlockForTest(t, ledger, bg, "SimulateForBlk2",
+		map[string]string{"key2": "value2"},
+		map[string]string{"key2": "pvtValue2"})
+	blk2PvtData := blk2.BlockPvtData
+	blk2.BlockPvtData = nil
+	blk2.Missing = []lgr.MissingPrivateData{{SeqInBlock: 0, Namespace: "ns", Collection: "coll"}}
+	assert.NoError(t, ledger.CommitWithPvtData(blk2))
+
+	// the transaction of block 3 is invalid
+	blk3 := prepareNextBlockForTest(t, ledger, bg, "SimulateForBlk3",
+		map[string]string{"key3": "value3"},
+		map[string]string{"key3": "pvtValue3"})
+	blk3PvtData := blk3.BlockPvtData
+	blk3.BlockPvtData = nil
+	blk3.Block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] =
+		util.NewTxValidationFlagsSetValue(1, peer.TxValidationCode_MVCC_READ_CONFLICT)
+	assert.NoError(t, ledger.CommitWithPvtData(blk3))
+
+	blk4 := prepareNextBlockForTest(t, ledger, bg, "SimulateForBlk4",
+		map[string]string{"key4": "value4"}, nil)
+	blk4.BlockPvtData = nil
+	assert.NoError(t, ledger.CommitWithPvtData(blk4))
+
+	// the pvt data of an invalid transaction is not committed
+	hashMismatches, err := ledger.CommitPvtDataOfOldBlocks([]*lgr.BlockPvtData{
+		{BlockNum: 3, WriteSets: blk3PvtData},
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, hashMismatches)
+	pvtdata, err := ledger.GetPvtDataByNum(3, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, pvtdata)
+	qe, err := ledger.NewQueryExecutor()
+	assert.NoError(t, err)
+	val, err := qe.GetPrivateData("ns", "coll", "key3")
+	qe.Done()
+	assert.NoError(t, err)
+	assert.Nil(t, val)
+
+	// once block 2 is pruned, its pvt data is ignored and it is not reported as missing anymore
+	assert.NoError(t, ledger.(*kvLedger).blockStore.Prune(3))
+	_, err = ledger.GetBlockByNumber(2)
+	assert.Equal(t, blkstorage.ErrPruned, err)
+	hashMismatches, err = ledger.CommitPvtDataOfOldBlocks([]*lgr.BlockPvtData{
+		{BlockNum: 2, WriteSets: blk2PvtData},
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, hashMismatches)
+	tracker, err := ledger.GetMissingPvtDataTracker()
+	assert.NoError(t, err)
+	missingPvtDataInfo, err := tracker.GetMissingPvtDataInfoForMostRecentBlocks(10)
+	assert.NoError(t, err)
+	assert.Empty(t, missingPvtDataInfo)
+	pvtdata, err = ledger.GetPvtDataByNum(2, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, pvtdata)
+}
@@ -0,0 +1,75 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"github.com/hyperledger/fabric/core/ledger/ledgerstorage"
+)
+
+// RebuildDBs drops the state database, the history database, and the other databases derived from
+// the blocks of the ledger with the given id, without modifying the block store and the pvt data store.
+// The dropped databases are rebuilt by replaying the blocks and the pvt data of the ledger when the ledger
+// is opened next, i.e., on the next peer start. This is useful after switching the state database or
+// enabling the history database. Like `RollbackKVLedger`, this function returns an error if the ledgers
+// are in use by another process such as a running peer
+func RebuildDBs(ledgerID string) error {
+	p, err := NewProvider()
+	if err != nil {
+		return err
+	}
+	defer p.Close()
+	provider := p.(*Provider)
+
+	store, err := provider.openStoreForRollback(ledgerID)
+	if err != nil {
+		return err
+	}
+	defer store.Shutdown()
+	if err := provider.dropDBs(ledgerID); err != nil {
+		return err
+	}
+	logger.Infof("The databases of ledger [%s] have been dropped and are rebuilt on the next peer start", ledgerID)
+	return nil
+}
+
+// RebuildAllDBs drops the databases derived from the blocks for all the ledgers, see `RebuildDBs`.
+// All the ledgers are checked before any of them is modified so that either all or none of the
+// ledgers are rebuilt
+func RebuildAllDBs() error {
+	p, err := NewProvider()
+	if err != nil {
+		return err
+	}
+	defer p.Close()
+	provider := p.(*Provider)
+
+	ledgerIDs, err := provider.List()
+	if err != nil {
+		return err
+	}
+	var stores []*ledgerstorage.Store
+	defer func() {
+		for _, store := range stores {
+			store.Shutdown()
+		}
+	}()
+	for _, ledgerID := range ledgerIDs {
+		store, err := provider.openStoreForRollback(ledgerID)
+		if err != nil {
+			return err
+		}
+		stores = append(stores, store)
+	}
+
+	for _, ledgerID := range ledgerIDs {
+		if err := provider.dropDBs(ledgerID); err != nil {
+			return err
+		}
+	}
+	logger.Infof("The databases of all the ledgers %s have been dropped and are rebuilt on the next peer start", ledgerIDs)
+	return nil
+}
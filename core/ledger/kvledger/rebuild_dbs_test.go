@@ -0,0 +1,113 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	lgr "github.com/hyperledger/fabric/core/ledger"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRebuildDBs(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	defer viper.Set("ledger.history.enableHistoryDatabase", viper.GetBool("ledger.history.enableHistoryDatabase"))
+	viper.Set("ledger.history.enableHistoryDatabase", false)
+	provider, _ := NewProvider()
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	l, err := provider.Create(gb)
+	assert.NoError(t, err)
+	for i := 1; i <= 10; i++ {
+		commitBlockWithValue(t, l, bg, fmt.Sprintf("value%d", i))
+	}
+
+	// the rebuild is refused while the ledgers are in use
+	err = RebuildDBs("testLedger")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the ledgers are in use by another process")
+	l.Close()
+	provider.Close()
+
+	assert.Equal(t, ErrNonExistingLedgerID, RebuildDBs("nonExistingLedger"))
+	// enable the history database after the fact, the history of all the blocks is expected after the rebuild
+	viper.Set("ledger.history.enableHistoryDatabase", true)
+	assert.NoError(t, RebuildDBs("testLedger"))
+
+	provider, _ = NewProvider()
+	defer provider.Close()
+	vdb, err := provider.(*Provider).vdbProvider.GetDBHandle("testLedger")
+	assert.NoError(t, err)
+	savepoint, err := vdb.GetLatestSavePoint()
+	assert.NoError(t, err)
+	assert.Nil(t, savepoint)
+
+	l, err = provider.Open("testLedger")
+	assert.NoError(t, err)
+	defer l.Close()
+	bcInfo, err := l.GetBlockchainInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(11), bcInfo.Height)
+	checkValueOfKey1(t, l, "value10")
+	assert.Equal(t, 10, countHistoryOfKey1(t, l))
+}
+
+func TestRebuildAllDBs(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider, _ := NewProvider()
+	for _, ledgerID := range []string{"ledger1", "ledger2"} {
+		bg, gb := testutil.NewBlockGenerator(t, ledgerID, false)
+		l, err := provider.Create(gb)
+		assert.NoError(t, err)
+		for i := 1; i <= 3; i++ {
+			commitBlockWithValue(t, l, bg, fmt.Sprintf("value%d", i))
+		}
+		l.Close()
+	}
+	provider.Close()
+
+	assert.NoError(t, RebuildAllDBs())
+
+	provider, _ = NewProvider()
+	defer provider.Close()
+	for _, ledgerID := range []string{"ledger1", "ledger2"} {
+		vdb, err := provider.(*Provider).vdbProvider.GetDBHandle(ledgerID)
+		assert.NoError(t, err)
+		savepoint, err := vdb.GetLatestSavePoint()
+		assert.NoError(t, err)
+		assert.Nil(t, savepoint)
+
+		l, err := provider.Open(ledgerID)
+		assert.NoError(t, err)
+		bcInfo, err := l.GetBlockchainInfo()
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(4), bcInfo.Height)
+		checkValueOfKey1(t, l, "value3")
+		l.Close()
+	}
+}
+
+func countHistoryOfKey1(t *testing.T, l lgr.PeerLedger) int {
+	qe, err := l.NewHistoryQueryExecutor()
+	assert.NoError(t, err)
+	itr, err := qe.GetHistoryForKey("ns1", "key1")
+	assert.NoError(t, err)
+	defer itr.Close()
+	count := 0
+	for {
+		res, err := itr.Next()
+		assert.NoError(t, err)
+		if res == nil {
+			return count
+		}
+		count++
+	}
+}
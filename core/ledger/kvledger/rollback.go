@@ -0,0 +1,150 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/ledger/ledgerstorage"
+)
+
+// RollbackKVLedger rolls back the ledger with the given id to the given block number.
+// The block store and the pvt data store are truncated to the given block whereas the
+// state database, the history database, and the other databases derived from the blocks
+// are dropped for the ledger. These databases are rebuilt from the block store when the
+// ledger is opened next, i.e., on the next peer start.
+// As the ledger provider is instantiated for performing the rollback, this function
+// returns an error if the ledgers are in use by another process such as a running peer
+func RollbackKVLedger(ledgerID string, blockNum uint64) error {
+	p, err := NewProvider()
+	if err != nil {
+		return err
+	}
+	defer p.Close()
+	provider := p.(*Provider)
+
+	store, err := provider.openStoreForRollback(ledgerID)
+	if err != nil {
+		return err
+	}
+	defer store.Shutdown()
+	bcInfo, err := store.GetBlockchainInfo()
+	if err != nil {
+		return err
+	}
+	if blockNum >= bcInfo.Height-1 {
+		return fmt.Errorf("target block number [%d] should be less than the last block number [%d] of ledger [%s]",
+			blockNum, bcInfo.Height-1, ledgerID)
+	}
+
+	// the databases are dropped before rolling back the block store so that, if a crash happens in between,
+	// the databases are simply rebuilt up to the last block and the rollback can be retried
+	if err := provider.dropDBs(ledgerID); err != nil {
+		return err
+	}
+	logger.Infof("Rolling back the block store of ledger [%s] to block [%d]", ledgerID, blockNum)
+	if err := store.Rollback(blockNum); err != nil {
+		return err
+	}
+	logger.Infof("Ledger [%s] has been rolled back to block [%d]", ledgerID, blockNum)
+	return nil
+}
+
+// ResetAllKVLedgers resets all the ledgers to their genesis blocks. The databases derived from the
+// blocks are dropped for all the ledgers and are rebuilt when the ledgers are opened next.
+// All the ledgers are checked before any of them is modified so that either all or none of the
+// ledgers are reset. Like `RollbackKVLedger`, this function returns an error if the ledgers are in
+// use by another process such as a running peer
+func ResetAllKVLedgers() error {
+	p, err := NewProvider()
+	if err != nil {
+		return err
+	}
+	defer p.Close()
+	provider := p.(*Provider)
+
+	ledgerIDs, err := provider.List()
+	if err != nil {
+		return err
+	}
+	var stores []*ledgerstorage.Store
+	defer func() {
+		for _, store := range stores {
+			store.Shutdown()
+		}
+	}()
+	for _, ledgerID := range ledgerIDs {
+		store, err := provider.openStoreForRollback(ledgerID)
+		if err != nil {
+			return err
+		}
+		stores = append(stores, store)
+	}
+
+	for i, ledgerID := range ledgerIDs {
+		logger.Infof("Resetting ledger [%s] to the genesis block", ledgerID)
+		if err := provider.dropDBs(ledgerID); err != nil {
+			return err
+		}
+		bcInfo, err := stores[i].GetBlockchainInfo()
+		if err != nil {
+			return err
+		}
+		if bcInfo.Height <= 1 {
+			continue
+		}
+		if err := stores[i].Rollback(0); err != nil {
+			return err
+		}
+	}
+	logger.Infof("All the ledgers %s have been reset to the genesis block", ledgerIDs)
+	return nil
+}
+
+// openStoreForRollback opens the block store of the given ledger and makes sure that
+// the ledger can be rebuilt from its blocks, i.e., none of its blocks has been pruned
+func (provider *Provider) openStoreForRollback(ledgerID string) (*ledgerstorage.Store, error) {
+	exists, err := provider.idStore.ledgerIDExists(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrNonExistingLedgerID
+	}
+	store, err := provider.ledgerStoreProvider.Open(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	firstBlockNum, err := store.GetFirstAvailableBlockNum()
+	if err != nil {
+		store.Shutdown()
+		return nil, err
+	}
+	if firstBlockNum > 0 {
+		store.Shutdown()
+		return nil, fmt.Errorf("the blocks before block [%d] of ledger [%s] have been pruned, the databases of the ledger cannot be rebuilt",
+			firstBlockNum, ledgerID)
+	}
+	return store, nil
+}
+
+// dropDBs drops the state database, the history database, the pvt data expiry bookkeeping, and
+// the collection config history of the given ledger. All of these are rebuilt from the block
+// store when the ledger is opened next
+func (provider *Provider) dropDBs(ledgerID string) error {
+	logger.Infof("Dropping the databases of ledger [%s]", ledgerID)
+	if err := provider.vdbProvider.Drop(ledgerID); err != nil {
+		return err
+	}
+	if err := provider.historydbProvider.Drop(ledgerID); err != nil {
+		return err
+	}
+	if err := provider.bookkeepingProvider.Drop(ledgerID); err != nil {
+		return err
+	}
+	return provider.configHistoryMgr.Drop(ledgerID)
+}
@@ -0,0 +1,115 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/util"
+	lgr "github.com/hyperledger/fabric/core/ledger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRollbackKVLedger(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider, _ := NewProvider()
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	l, err := provider.Create(gb)
+	assert.NoError(t, err)
+	for i := 1; i <= 10; i++ {
+		commitBlockWithValue(t, l, bg, fmt.Sprintf("value%d", i))
+	}
+
+	// the rollback is refused while the ledgers are in use
+	err = RollbackKVLedger("testLedger", 5)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the ledgers are in use by another process")
+	l.Close()
+	provider.Close()
+
+	assert.Equal(t, ErrNonExistingLedgerID, RollbackKVLedger("nonExistingLedger", 5))
+	assert.EqualError(t, RollbackKVLedger("testLedger", 10),
+		"target block number [10] should be less than the last block number [10] of ledger [testLedger]")
+	assert.NoError(t, RollbackKVLedger("testLedger", 5))
+
+	provider, _ = NewProvider()
+	defer provider.Close()
+	l, err = provider.Open("testLedger")
+	assert.NoError(t, err)
+	defer l.Close()
+	bcInfo, err := l.GetBlockchainInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(6), bcInfo.Height)
+	checkValueOfKey1(t, l, "value5")
+
+	// the ledger accepts new blocks after the rollback
+	bg, _ = testutil.NewBlockGenerator(t, "testLedger", false)
+	for i := 1; i <= 5; i++ {
+		bg.NextBlock(nil)
+	}
+	commitBlockWithValue(t, l, bg, "value6-new")
+	checkValueOfKey1(t, l, "value6-new")
+}
+
+func TestResetAllKVLedgers(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider, _ := NewProvider()
+	for _, ledgerID := range []string{"ledger1", "ledger2"} {
+		bg, gb := testutil.NewBlockGenerator(t, ledgerID, false)
+		l, err := provider.Create(gb)
+		assert.NoError(t, err)
+		for i := 1; i <= 3; i++ {
+			commitBlockWithValue(t, l, bg, fmt.Sprintf("value%d", i))
+		}
+		l.Close()
+	}
+	_, gb := testutil.NewBlockGenerator(t, "ledger3", false)
+	l, err := provider.Create(gb)
+	assert.NoError(t, err)
+	l.Close()
+	provider.Close()
+
+	assert.NoError(t, ResetAllKVLedgers())
+
+	provider, _ = NewProvider()
+	defer provider.Close()
+	for _, ledgerID := range []string{"ledger1", "ledger2", "ledger3"} {
+		l, err := provider.Open(ledgerID)
+		assert.NoError(t, err)
+		bcInfo, err := l.GetBlockchainInfo()
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1), bcInfo.Height)
+		checkValueOfKey1(t, l, "")
+		l.Close()
+	}
+}
+
+func commitBlockWithValue(t *testing.T, l lgr.PeerLedger, bg *testutil.BlockGenerator, value string) {
+	simulator, err := l.NewTxSimulator(util.GenerateUUID())
+	assert.NoError(t, err)
+	simulator.SetState("ns1", "key1", []byte(value))
+	simulator.Done()
+	simRes, err := simulator.GetTxSimulationResults()
+	assert.NoError(t, err)
+	pubSimBytes, err := simRes.GetPubSimulationBytes()
+	assert.NoError(t, err)
+	block := bg.NextBlock([][]byte{pubSimBytes})
+	assert.NoError(t, l.CommitWithPvtData(&lgr.BlockAndPvtData{Block: block}))
+}
+
+func checkValueOfKey1(t *testing.T, l lgr.PeerLedger, expectedValue string) {
+	qe, err := l.NewQueryExecutor()
+	assert.NoError(t, err)
+	defer qe.Done()
+	value, err := qe.GetState("ns1", "key1")
+	assert.NoError(t, err)
+	assert.Equal(t, expectedValue, string(value))
+}
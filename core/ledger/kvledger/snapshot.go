@@ -0,0 +1,629 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/confighistory"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/pvtstatepurgemgmt"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/core/ledger/ledgerstorage"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+// A ledger snapshot is a directory that contains the following files. Each of the data files is a
+// sequence of records, each of which is prefixed with its length encoded as a varint
+const (
+	// snapshotStateFileName contains the public state and the hashes of the pvt data
+	snapshotStateFileName = "state.data"
+	// snapshotTxIDsFileName contains the validation codes of the transactions of the blocks not included in the snapshot
+	snapshotTxIDsFileName = "txids.data"
+	// snapshotConfigHistoryFileName contains the history of the collection configs
+	snapshotConfigHistoryFileName = "config_history.data"
+	// snapshotPvtdataExpiryFileName contains the expiry schedule of the pvt data
+	snapshotPvtdataExpiryFileName = "pvtdata_expiry.data"
+	// snapshotBlocksFileName contains the blocks from the last config block to the last block
+	snapshotBlocksFileName = "blocks.data"
+	// snapshotMetadataFileName contains the snapshotMetadata, including the hashes of the data files
+	snapshotMetadataFileName = "metadata.json"
+	// snapshotHashFileName contains the hash of the metadata file, which is the hash of the snapshot
+	snapshotHashFileName = "metadata.json.sha256"
+)
+
+// snapshotMetadata describes a ledger snapshot
+type snapshotMetadata struct {
+	ChannelName       string            `json:"channel_name"`
+	FirstBlockNumber  uint64            `json:"first_block_number"`
+	LastBlockNumber   uint64            `json:"last_block_number"`
+	LastBlockHash     string            `json:"last_block_hash"`
+	PreviousBlockHash string            `json:"previous_block_hash"`
+	FileHashes        map[string]string `json:"file_hashes"`
+}
+
+// GenerateSnapshot exports the ledger with the given id to a snapshot in the given directory, which
+// should not exist. The snapshot is taken at the height of the block store, after the databases
+// of the ledger have been brought up to date with the block store. The snapshot contains the state
+// (including the hashes of the pvt data but not the pvt data), the collection config history, the
+// pvt data expiry schedule, the IDs of the transactions committed so far, and the blocks from the last
+// config block onwards. This function returns the hash of the snapshot, i.e., the hash of its metadata file.
+// As the ledger provider is instantiated for generating the snapshot, this function returns an error
+// if the ledgers are in use by another process such as a running peer
+func GenerateSnapshot(ledgerID string, snapshotDir string) (string, error) {
+	p, err := NewProvider()
+	if err != nil {
+		return "", err
+	}
+	defer p.Close()
+	provider := p.(*Provider)
+
+	exists, err := provider.idStore.ledgerIDExists(ledgerID)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return "", ErrNonExistingLedgerID
+	}
+	if _, err := os.Stat(snapshotDir); !os.IsNotExist(err) {
+		return "", fmt.Errorf("the snapshot directory [%s] already exists", snapshotDir)
+	}
+	// opening the ledger brings the databases up to date with the block store
+	lgr, err := provider.openInternal(ledgerID)
+	if err != nil {
+		return "", err
+	}
+	defer lgr.Close()
+	l := lgr.(*kvLedger)
+
+	if err := os.MkdirAll(snapshotDir, 0755); err != nil {
+		return "", err
+	}
+	snapshotHash, err := provider.generateSnapshot(l, snapshotDir)
+	if err != nil {
+		os.RemoveAll(snapshotDir)
+		return "", err
+	}
+	return snapshotHash, nil
+}
+
+func (provider *Provider) generateSnapshot(l *kvLedger, snapshotDir string) (string, error) {
+	bcInfo, err := l.blockStore.GetBlockchainInfo()
+	if err != nil {
+		return "", err
+	}
+	if bcInfo.Height == 0 {
+		return "", fmt.Errorf("the ledger [%s] has no blocks", l.ledgerID)
+	}
+	lastBlockNum := bcInfo.Height - 1
+	lastBlock, err := l.blockStore.RetrieveBlockByNumber(lastBlockNum)
+	if err != nil {
+		return "", err
+	}
+	firstBlockNum, err := utils.GetLastConfigIndexFromBlock(lastBlock)
+	if err != nil {
+		return "", err
+	}
+	vdb, err := provider.vdbProvider.GetDBHandle(l.ledgerID)
+	if err != nil {
+		return "", err
+	}
+	savepoint, err := vdb.GetLatestSavePoint()
+	if err != nil {
+		return "", err
+	}
+	if savepoint == nil || savepoint.BlockNum != lastBlockNum {
+		return "", fmt.Errorf("the state database of ledger [%s] is not in sync with the block store, savepoint = %#v, last block = [%d]",
+			l.ledgerID, savepoint, lastBlockNum)
+	}
+	logger.Infof("Generating a snapshot of ledger [%s] at block [%d], the snapshot includes the blocks from block [%d]",
+		l.ledgerID, lastBlockNum, firstBlockNum)
+
+	metadata := &snapshotMetadata{
+		ChannelName:       l.ledgerID,
+		FirstBlockNumber:  firstBlockNum,
+		LastBlockNumber:   lastBlockNum,
+		LastBlockHash:     hex.EncodeToString(bcInfo.CurrentBlockHash),
+		PreviousBlockHash: hex.EncodeToString(bcInfo.PreviousBlockHash),
+		FileHashes:        map[string]string{},
+	}
+	exporters := map[string]func(*snapshotFileWriter) error{
+		snapshotStateFileName: func(w *snapshotFileWriter) error {
+			return vdb.ExportPubAndHashedState(func(r *privacyenabledstate.SnapshotRecord) error {
+				buf := proto.NewBuffer(nil)
+				buf.EncodeStringBytes(r.Namespace)
+				buf.EncodeStringBytes(r.Collection)
+				buf.EncodeRawBytes(r.Key)
+				buf.EncodeRawBytes(r.Value.Value)
+				buf.EncodeRawBytes(r.Value.Metadata)
+				buf.EncodeVarint(r.Value.Version.BlockNum)
+				buf.EncodeVarint(r.Value.Version.TxNum)
+				return w.addRecord(buf)
+			})
+		},
+		snapshotTxIDsFileName: func(w *snapshotFileWriter) error {
+			return l.blockStore.ExportTxValidationInfo(func(txInfo *blkstorage.TxValidationInfo) error {
+				buf := proto.NewBuffer(nil)
+				buf.EncodeStringBytes(txInfo.TxID)
+				buf.EncodeVarint(uint64(txInfo.ValidationCode))
+				return w.addRecord(buf)
+			})
+		},
+		snapshotConfigHistoryFileName: func(w *snapshotFileWriter) error {
+			return provider.configHistoryMgr.ExportHistory(l.ledgerID, func(entry *confighistory.HistoryEntry) error {
+				buf := proto.NewBuffer(nil)
+				buf.EncodeStringBytes(entry.Namespace)
+				buf.EncodeStringBytes(entry.Key)
+				buf.EncodeVarint(entry.BlockNum)
+				buf.EncodeRawBytes(entry.Value)
+				return w.addRecord(buf)
+			})
+		},
+		snapshotPvtdataExpiryFileName: func(w *snapshotFileWriter) error {
+			return pvtstatepurgemgmt.ExportExpirySchedule(l.ledgerID, provider.bookkeepingProvider,
+				func(entry *pvtstatepurgemgmt.ExpiryScheduleEntry) error {
+					buf := proto.NewBuffer(nil)
+					buf.EncodeVarint(entry.CommittingBlk)
+					buf.EncodeVarint(entry.ExpiryBlk)
+					buf.EncodeStringBytes(entry.Namespace)
+					buf.EncodeStringBytes(entry.Collection)
+					buf.EncodeRawBytes(entry.KeyHash)
+					return w.addRecord(buf)
+				})
+		},
+		snapshotBlocksFileName: func(w *snapshotFileWriter) error {
+			for blockNum := firstBlockNum; blockNum <= lastBlockNum; blockNum++ {
+				block, err := l.blockStore.RetrieveBlockByNumber(blockNum)
+				if err != nil {
+					return fmt.Errorf("error while retrieving block [%d]: %s", blockNum, err)
+				}
+				blockBytes, err := proto.Marshal(block)
+				if err != nil {
+					return err
+				}
+				buf := proto.NewBuffer(nil)
+				buf.EncodeRawBytes(blockBytes)
+				if err := w.addRecord(buf); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	for fileName, export := range exporters {
+		fileHash, err := writeSnapshotFile(snapshotDir, fileName, export)
+		if err != nil {
+			return "", err
+		}
+		metadata.FileHashes[fileName] = fileHash
+	}
+
+	metadataBytes, err := json.MarshalIndent(metadata, "", "    ")
+	if err != nil {
+		return "", err
+	}
+	if err := ioutil.WriteFile(filepath.Join(snapshotDir, snapshotMetadataFileName), metadataBytes, 0644); err != nil {
+		return "", err
+	}
+	snapshotHash := computeHash(metadataBytes)
+	if err := ioutil.WriteFile(filepath.Join(snapshotDir, snapshotHashFileName), []byte(snapshotHash), 0644); err != nil {
+		return "", err
+	}
+	logger.Infof("Generated the snapshot of ledger [%s] in directory [%s], snapshot hash = [%s]", l.ledgerID, snapshotDir, snapshotHash)
+	return snapshotHash, nil
+}
+
+// CreateLedgerFromSnapshot creates a ledger from the snapshot in the given directory. The snapshot is
+// verified against the hashes recorded in it and, if `expectedHash` is not empty, against the given hash,
+// which is expected to be obtained from a trusted source. The created ledger starts with the blocks
+// included in the snapshot and continues from the block that follows the last block of the snapshot.
+// This function returns the id of the created ledger. As the ledger provider is instantiated for
+// creating the ledger, this function returns an error if the ledgers are in use by another process
+// such as a running peer
+func CreateLedgerFromSnapshot(snapshotDir string, expectedHash string) (string, error) {
+	metadata, err := loadAndVerifySnapshot(snapshotDir, expectedHash)
+	if err != nil {
+		return "", err
+	}
+	ledgerID := metadata.ChannelName
+
+	p, err := NewProvider()
+	if err != nil {
+		return "", err
+	}
+	defer p.Close()
+	provider := p.(*Provider)
+
+	exists, err := provider.idStore.ledgerIDExists(ledgerID)
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		return "", ErrLedgerIDExists
+	}
+	if err := provider.idStore.setUnderConstructionFromSnapshotFlag(ledgerID); err != nil {
+		return "", err
+	}
+	configBlock, err := provider.importSnapshot(snapshotDir, metadata)
+	if err != nil {
+		logger.Errorf("Error while creating ledger [%s] from the snapshot. Removing the partially created ledger. Err: %s", ledgerID, err)
+		panicOnErr(provider.removeLedgerData(ledgerID), "Error while removing the data of ledger [%s]", ledgerID)
+		panicOnErr(provider.idStore.unsetUnderConstructionFromSnapshotFlag(), "Error while unsetting under construction flag")
+		return "", err
+	}
+	panicOnErr(provider.idStore.createLedgerID(ledgerID, configBlock), "Error while marking ledger as created")
+	logger.Infof("Created ledger [%s] from the snapshot in directory [%s] at block [%d]", ledgerID, snapshotDir, metadata.LastBlockNumber)
+	return ledgerID, nil
+}
+
+// importSnapshot bootstraps the block store with the blocks of the snapshot and imports the rest of the
+// snapshot into the databases of the ledger. The history database only records the history of the keys
+// for the blocks that follow the snapshot. This function returns the first block of the snapshot, i.e.,
+// the last config block
+func (provider *Provider) importSnapshot(snapshotDir string, metadata *snapshotMetadata) (*common.Block, error) {
+	ledgerID := metadata.ChannelName
+	store, err := provider.ledgerStoreProvider.Open(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	defer store.Shutdown()
+	configBlock, lastBlock, err := importBlocks(snapshotDir, metadata.FirstBlockNumber, store)
+	if err != nil {
+		return nil, err
+	}
+	savepoint := version.NewHeight(lastBlock.Header.Number, uint64(len(lastBlock.Data.Data)-1))
+
+	vdb, err := provider.vdbProvider.GetDBHandle(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	err = readSnapshotFile(snapshotDir, snapshotStateFileName, func(r *snapshotFileReader) error {
+		return vdb.ImportPubAndHashedState(func() (*privacyenabledstate.SnapshotRecord, error) {
+			buf, err := r.nextRecord()
+			if buf == nil || err != nil {
+				return nil, err
+			}
+			record := &privacyenabledstate.SnapshotRecord{Value: &statedb.VersionedValue{}}
+			var blockNum, txNum uint64
+			if record.Namespace, err = buf.DecodeStringBytes(); err != nil {
+				return nil, err
+			}
+			if record.Collection, err = buf.DecodeStringBytes(); err != nil {
+				return nil, err
+			}
+			if record.Key, err = buf.DecodeRawBytes(true); err != nil {
+				return nil, err
+			}
+			if record.Value.Value, err = buf.DecodeRawBytes(true); err != nil {
+				return nil, err
+			}
+			if record.Value.Metadata, err = buf.DecodeRawBytes(true); err != nil {
+				return nil, err
+			}
+			if len(record.Value.Metadata) == 0 {
+				record.Value.Metadata = nil
+			}
+			if blockNum, err = buf.DecodeVarint(); err != nil {
+				return nil, err
+			}
+			if txNum, err = buf.DecodeVarint(); err != nil {
+				return nil, err
+			}
+			record.Value.Version = version.NewHeight(blockNum, txNum)
+			return record, nil
+		}, savepoint)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var configHistory []*confighistory.HistoryEntry
+	err = readSnapshotFile(snapshotDir, snapshotConfigHistoryFileName, func(r *snapshotFileReader) error {
+		for {
+			buf, err := r.nextRecord()
+			if buf == nil || err != nil {
+				return err
+			}
+			entry := &confighistory.HistoryEntry{}
+			if entry.Namespace, err = buf.DecodeStringBytes(); err != nil {
+				return err
+			}
+			if entry.Key, err = buf.DecodeStringBytes(); err != nil {
+				return err
+			}
+			if entry.BlockNum, err = buf.DecodeVarint(); err != nil {
+				return err
+			}
+			if entry.Value, err = buf.DecodeRawBytes(true); err != nil {
+				return err
+			}
+			configHistory = append(configHistory, entry)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := provider.configHistoryMgr.ImportHistory(ledgerID, configHistory); err != nil {
+		return nil, err
+	}
+
+	err = readSnapshotFile(snapshotDir, snapshotPvtdataExpiryFileName, func(r *snapshotFileReader) error {
+		return pvtstatepurgemgmt.ImportExpirySchedule(ledgerID, provider.bookkeepingProvider,
+			func() (*pvtstatepurgemgmt.ExpiryScheduleEntry, error) {
+				buf, err := r.nextRecord()
+				if buf == nil || err != nil {
+					return nil, err
+				}
+				entry := &pvtstatepurgemgmt.ExpiryScheduleEntry{}
+				if entry.CommittingBlk, err = buf.DecodeVarint(); err != nil {
+					return nil, err
+				}
+				if entry.ExpiryBlk, err = buf.DecodeVarint(); err != nil {
+					return nil, err
+				}
+				if entry.Namespace, err = buf.DecodeStringBytes(); err != nil {
+					return nil, err
+				}
+				if entry.Collection, err = buf.DecodeStringBytes(); err != nil {
+					return nil, err
+				}
+				if entry.KeyHash, err = buf.DecodeRawBytes(true); err != nil {
+					return nil, err
+				}
+				return entry, nil
+			})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	historyDB, err := provider.historydbProvider.GetDBHandle(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := historyDB.InitSavepoint(savepoint); err != nil {
+		return nil, err
+	}
+	return configBlock, nil
+}
+
+// importBlocks bootstraps the given store with the transaction IDs and the blocks of the snapshot.
+// This function returns the first and the last block of the snapshot
+func importBlocks(snapshotDir string, firstBlockNum uint64, store *ledgerstorage.Store) (*common.Block, *common.Block, error) {
+	err := readSnapshotFile(snapshotDir, snapshotTxIDsFileName, func(r *snapshotFileReader) error {
+		return store.BootstrapFromSnapshot(firstBlockNum, func() (*blkstorage.TxValidationInfo, error) {
+			buf, err := r.nextRecord()
+			if buf == nil || err != nil {
+				return nil, err
+			}
+			txInfo := &blkstorage.TxValidationInfo{}
+			if txInfo.TxID, err = buf.DecodeStringBytes(); err != nil {
+				return nil, err
+			}
+			code, err := buf.DecodeVarint()
+			if err != nil {
+				return nil, err
+			}
+			txInfo.ValidationCode = peer.TxValidationCode(int32(code))
+			return txInfo, nil
+		})
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	var firstBlock, lastBlock *common.Block
+	err = readSnapshotBlocks(snapshotDir, func(block *common.Block) error {
+		if firstBlock == nil {
+			firstBlock = block
+		}
+		lastBlock = block
+		return store.CommitWithPvtData(&ledger.BlockAndPvtData{Block: block})
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return firstBlock, lastBlock, nil
+}
+
+// loadAndVerifySnapshot loads the metadata of the snapshot in the given directory and verifies the hashes
+// of the files and the hash chain of the blocks of the snapshot
+func loadAndVerifySnapshot(snapshotDir string, expectedHash string) (*snapshotMetadata, error) {
+	metadataBytes, err := ioutil.ReadFile(filepath.Join(snapshotDir, snapshotMetadataFileName))
+	if err != nil {
+		return nil, err
+	}
+	recordedHash, err := ioutil.ReadFile(filepath.Join(snapshotDir, snapshotHashFileName))
+	if err != nil {
+		return nil, err
+	}
+	snapshotHash := computeHash(metadataBytes)
+	if snapshotHash != strings.TrimSpace(string(recordedHash)) {
+		return nil, fmt.Errorf("the hash of the snapshot metadata [%s] does not match the recorded hash [%s]",
+			snapshotHash, strings.TrimSpace(string(recordedHash)))
+	}
+	if expectedHash != "" && snapshotHash != expectedHash {
+		return nil, fmt.Errorf("the hash of the snapshot [%s] does not match the expected hash [%s]", snapshotHash, expectedHash)
+	}
+	metadata := &snapshotMetadata{}
+	if err := json.Unmarshal(metadataBytes, metadata); err != nil {
+		return nil, fmt.Errorf("error while unmarshaling the snapshot metadata: %s", err)
+	}
+	if metadata.ChannelName == "" {
+		return nil, fmt.Errorf("the snapshot metadata does not carry the channel name")
+	}
+	if metadata.FirstBlockNumber > metadata.LastBlockNumber {
+		return nil, fmt.Errorf("the first block [%d] of the snapshot follows the last block [%d]",
+			metadata.FirstBlockNumber, metadata.LastBlockNumber)
+	}
+
+	for _, fileName := range []string{snapshotStateFileName, snapshotTxIDsFileName, snapshotConfigHistoryFileName,
+		snapshotPvtdataExpiryFileName, snapshotBlocksFileName} {
+		expectedFileHash, ok := metadata.FileHashes[fileName]
+		if !ok {
+			return nil, fmt.Errorf("the snapshot metadata does not carry the hash of file [%s]", fileName)
+		}
+		fileHash, err := computeFileHash(filepath.Join(snapshotDir, fileName))
+		if err != nil {
+			return nil, err
+		}
+		if fileHash != expectedFileHash {
+			return nil, fmt.Errorf("the hash of file [%s] of the snapshot [%s] does not match the recorded hash [%s]",
+				fileName, fileHash, expectedFileHash)
+		}
+	}
+
+	expectedBlockNum := metadata.FirstBlockNumber
+	var previousBlockHash []byte
+	err = readSnapshotBlocks(snapshotDir, func(block *common.Block) error {
+		if block.Header.Number != expectedBlockNum {
+			return fmt.Errorf("expected block [%d] in the snapshot, found block [%d]", expectedBlockNum, block.Header.Number)
+		}
+		if !bytes.Equal(block.Header.DataHash, block.Data.Hash()) {
+			return fmt.Errorf("the data hash of block [%d] in the snapshot does not match its data", block.Header.Number)
+		}
+		if previousBlockHash != nil && !bytes.Equal(block.Header.PreviousHash, previousBlockHash) {
+			return fmt.Errorf("the previous hash of block [%d] in the snapshot does not match the hash of the preceding block", block.Header.Number)
+		}
+		previousBlockHash = block.Header.Hash()
+		expectedBlockNum++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if expectedBlockNum != metadata.LastBlockNumber+1 {
+		return nil, fmt.Errorf("the snapshot contains the blocks up to block [%d], expected up to block [%d]",
+			expectedBlockNum-1, metadata.LastBlockNumber)
+	}
+	if hex.EncodeToString(previousBlockHash) != metadata.LastBlockHash {
+		return nil, fmt.Errorf("the hash of the last block of the snapshot does not match the recorded hash [%s]", metadata.LastBlockHash)
+	}
+	return metadata, nil
+}
+
+func readSnapshotBlocks(snapshotDir string, fn func(*common.Block) error) error {
+	return readSnapshotFile(snapshotDir, snapshotBlocksFileName, func(r *snapshotFileReader) error {
+		for {
+			buf, err := r.nextRecord()
+			if buf == nil || err != nil {
+				return err
+			}
+			blockBytes, err := buf.DecodeRawBytes(false)
+			if err != nil {
+				return err
+			}
+			block, err := utils.GetBlockFromBlockBytes(blockBytes)
+			if err != nil {
+				return err
+			}
+			if err := fn(block); err != nil {
+				return err
+			}
+		}
+	})
+}
+
+// snapshotFileWriter writes the length prefixed records to a snapshot file and computes the hash of the file
+type snapshotFileWriter struct {
+	file   *os.File
+	writer *bufio.Writer
+	hasher hash.Hash
+}
+
+func writeSnapshotFile(snapshotDir, fileName string, export func(*snapshotFileWriter) error) (string, error) {
+	file, err := os.OpenFile(filepath.Join(snapshotDir, fileName), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	w := &snapshotFileWriter{file: file, writer: bufio.NewWriter(file), hasher: sha256.New()}
+	if err := export(w); err != nil {
+		return "", err
+	}
+	if err := w.writer.Flush(); err != nil {
+		return "", err
+	}
+	if err := file.Sync(); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(w.hasher.Sum(nil)), nil
+}
+
+func (w *snapshotFileWriter) addRecord(buf *proto.Buffer) error {
+	record := append(proto.EncodeVarint(uint64(len(buf.Bytes()))), buf.Bytes()...)
+	if _, err := w.writer.Write(record); err != nil {
+		return err
+	}
+	_, err := w.hasher.Write(record)
+	return err
+}
+
+// snapshotFileReader reads the length prefixed records from a snapshot file
+type snapshotFileReader struct {
+	reader *bufio.Reader
+}
+
+func readSnapshotFile(snapshotDir, fileName string, read func(*snapshotFileReader) error) error {
+	file, err := os.Open(filepath.Join(snapshotDir, fileName))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return read(&snapshotFileReader{bufio.NewReader(file)})
+}
+
+// nextRecord returns the next record of the file and a nil record once the file is exhausted
+func (r *snapshotFileReader) nextRecord() (*proto.Buffer, error) {
+	length, err := binary.ReadUvarint(r.reader)
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	record := make([]byte, length)
+	if _, err := io.ReadFull(r.reader, record); err != nil {
+		return nil, fmt.Errorf("error while reading a record of the snapshot: %s", err)
+	}
+	return proto.NewBuffer(record), nil
+}
+
+func computeHash(b []byte) string {
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:])
+}
+
+func computeFileHash(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
@@ -0,0 +1,203 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/util"
+	lgr "github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/protos/common"
+	putils "github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateSnapshotAndCreateLedger(t *testing.T) {
+	snapshotsDir, err := ioutil.TempDir("", "snapshots")
+	assert.NoError(t, err)
+	defer os.RemoveAll(snapshotsDir)
+	snapshotDir := filepath.Join(snapshotsDir, "snapshot1")
+
+	sourceEnv := newTestEnv(t)
+	defer sourceEnv.cleanup()
+	provider, _ := NewProvider()
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	l, err := provider.Create(gb)
+	assert.NoError(t, err)
+	var txIDs []string
+	for i := 1; i <= 9; i++ {
+		txIDs = append(txIDs, commitSnapshotTestBlock(t, l, bg, fmt.Sprintf("value%d", i), 5))
+	}
+
+	// a snapshot cannot be generated while the ledgers are in use
+	_, err = GenerateSnapshot("testLedger", snapshotDir)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the ledgers are in use by another process")
+	l.Close()
+	provider.Close()
+
+	_, err = GenerateSnapshot("nonExistingLedger", snapshotDir)
+	assert.Equal(t, ErrNonExistingLedgerID, err)
+	snapshotHash, err := GenerateSnapshot("testLedger", snapshotDir)
+	assert.NoError(t, err)
+	_, err = GenerateSnapshot("testLedger", snapshotDir)
+	assert.EqualError(t, err, fmt.Sprintf("the snapshot directory [%s] already exists", snapshotDir))
+
+	// create the ledger from the snapshot on another peer
+	targetEnv := newTestEnv(t)
+	defer targetEnv.cleanup()
+	_, err = CreateLedgerFromSnapshot(snapshotDir, "wrongHash")
+	assert.EqualError(t, err, fmt.Sprintf("the hash of the snapshot [%s] does not match the expected hash [wrongHash]", snapshotHash))
+	ledgerID, err := CreateLedgerFromSnapshot(snapshotDir, snapshotHash)
+	assert.NoError(t, err)
+	assert.Equal(t, "testLedger", ledgerID)
+	_, err = CreateLedgerFromSnapshot(snapshotDir, snapshotHash)
+	assert.Equal(t, ErrLedgerIDExists, err)
+
+	// a snapshot of the created ledger is identical to the original snapshot
+	reSnapshotHash, err := GenerateSnapshot("testLedger", filepath.Join(snapshotsDir, "snapshot2"))
+	assert.NoError(t, err)
+	assert.Equal(t, snapshotHash, reSnapshotHash)
+
+	provider, _ = NewProvider()
+	defer provider.Close()
+	ledgerIDs, err := provider.List()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"testLedger"}, ledgerIDs)
+	l, err = provider.Open("testLedger")
+	assert.NoError(t, err)
+	defer l.Close()
+	bcInfo, err := l.GetBlockchainInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10), bcInfo.Height)
+	checkValueOfKey1(t, l, "value9")
+
+	// the blocks preceding the last config block are not present while their transactions are known
+	_, err = l.GetBlockByNumber(4)
+	assert.Equal(t, blkstorage.ErrPruned, err)
+	_, err = l.GetBlockByNumber(5)
+	assert.NoError(t, err)
+	_, err = l.GetTransactionByID(txIDs[1])
+	assert.Equal(t, blkstorage.ErrPruned, err)
+	_, err = l.GetTransactionByID(txIDs[7])
+	assert.NoError(t, err)
+
+	// the ledger continues from the block that follows the snapshot
+	commitSnapshotTestBlock(t, l, bg, "value10", 5)
+	checkValueOfKey1(t, l, "value10")
+	qe, err := l.NewHistoryQueryExecutor()
+	assert.NoError(t, err)
+	itr, err := qe.GetHistoryForKey("ns1", "key1")
+	assert.NoError(t, err)
+	defer itr.Close()
+	numHistoryEntries := 0
+	for {
+		res, err := itr.Next()
+		assert.NoError(t, err)
+		if res == nil {
+			break
+		}
+		numHistoryEntries++
+	}
+	assert.Equal(t, 1, numHistoryEntries)
+}
+
+func TestCreateLedgerFromTamperedSnapshot(t *testing.T) {
+	snapshotDir, err := ioutil.TempDir("", "snapshot")
+	assert.NoError(t, err)
+	defer os.RemoveAll(snapshotDir)
+	snapshotDir = filepath.Join(snapshotDir, "snapshot")
+
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider, _ := NewProvider()
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	l, err := provider.Create(gb)
+	assert.NoError(t, err)
+	for i := 1; i <= 3; i++ {
+		commitBlockWithValue(t, l, bg, fmt.Sprintf("value%d", i))
+	}
+	l.Close()
+	provider.Close()
+	snapshotHash, err := GenerateSnapshot("testLedger", snapshotDir)
+	assert.NoError(t, err)
+
+	stateFilePath := filepath.Join(snapshotDir, snapshotStateFileName)
+	stateFileBytes, err := ioutil.ReadFile(stateFilePath)
+	assert.NoError(t, err)
+	tamperedBytes := append([]byte{}, stateFileBytes...)
+	tamperedBytes[len(tamperedBytes)-1]++
+	assert.NoError(t, ioutil.WriteFile(stateFilePath, tamperedBytes, 0644))
+
+	targetEnv := newTestEnv(t)
+	defer targetEnv.cleanup()
+	_, err = CreateLedgerFromSnapshot(snapshotDir, snapshotHash)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the hash of file [state.data] of the snapshot")
+	assert.NoError(t, ioutil.WriteFile(stateFilePath, stateFileBytes, 0644))
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(snapshotDir, snapshotHashFileName), []byte("wrongHash"), 0644))
+	_, err = CreateLedgerFromSnapshot(snapshotDir, "")
+	assert.EqualError(t, err, fmt.Sprintf("the hash of the snapshot metadata [%s] does not match the recorded hash [wrongHash]", snapshotHash))
+}
+
+func TestRecoverUnderConstructionFromSnapshotLedger(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider, _ := NewProvider()
+	p := provider.(*Provider)
+	store, err := p.ledgerStoreProvider.Open("testLedger")
+	assert.NoError(t, err)
+	_, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	assert.NoError(t, store.CommitWithPvtData(&lgr.BlockAndPvtData{Block: gb}))
+	store.Shutdown()
+	// simulate a crash while creating the ledger from a snapshot
+	assert.NoError(t, p.idStore.setUnderConstructionFromSnapshotFlag("testLedger"))
+	provider.Close()
+
+	provider, _ = NewProvider()
+	defer provider.Close()
+	p = provider.(*Provider)
+	flag, err := p.idStore.getUnderConstructionFromSnapshotFlag()
+	assert.NoError(t, err)
+	assert.Equal(t, "", flag)
+	exists, err := provider.Exists("testLedger")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+	store, err = p.ledgerStoreProvider.Open("testLedger")
+	assert.NoError(t, err)
+	defer store.Shutdown()
+	bcInfo, err := store.GetBlockchainInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), bcInfo.Height)
+}
+
+// commitSnapshotTestBlock commits a block that sets the value of key1 and points to the
+// given block as the last config block. This function returns the ID of the transaction
+func commitSnapshotTestBlock(t *testing.T, l lgr.PeerLedger, bg *testutil.BlockGenerator, value string, lastConfigBlockNum uint64) string {
+	txID := util.GenerateUUID()
+	simulator, err := l.NewTxSimulator(txID)
+	assert.NoError(t, err)
+	simulator.SetState("ns1", "key1", []byte(value))
+	simulator.Done()
+	simRes, err := simulator.GetTxSimulationResults()
+	assert.NoError(t, err)
+	pubSimBytes, err := simRes.GetPubSimulationBytes()
+	assert.NoError(t, err)
+	block := bg.NextBlockWithTxid([][]byte{pubSimBytes}, []string{txID})
+	block.Metadata.Metadata[common.BlockMetadataIndex_LAST_CONFIG] = putils.MarshalOrPanic(&common.Metadata{
+		Value: putils.MarshalOrPanic(&common.LastConfig{Index: lastConfigBlockNum}),
+	})
+	assert.NoError(t, l.CommitWithPvtData(&lgr.BlockAndPvtData{Block: block}))
+	return txID
+}
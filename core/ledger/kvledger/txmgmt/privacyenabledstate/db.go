@@ -18,6 +18,8 @@ import (
 type DBProvider interface {
 	// GetDBHandle returns a handle to a PvtVersionedDB
 	GetDBHandle(id string) (DB, error)
+	// Drop drops all the data of the PvtVersionedDB with the given id
+	Drop(id string) error
 	// Close closes all the PvtVersionedDB instances and releases any resources held by VersionedDBProvider
 	Close()
 }
@@ -37,6 +39,12 @@ type DB interface {
 	GetPrivateDataRangeScanIterator(namespace, collection, startKey, endKey string) (statedb.ResultsIterator, error)
 	ExecuteQueryOnPrivateData(namespace, collection, query string) (statedb.ResultsIterator, error)
 	ApplyPrivacyAwareUpdates(updates *UpdateBatch, height *version.Height) error
+	// ExportPubAndHashedState passes to the given function, in the order of namespaces and keys, all the
+	// entries of the public state and of the hashed state. The pvt data itself is not exported
+	ExportPubAndHashedState(fn func(*SnapshotRecord) error) error
+	// ImportPubAndHashedState applies to an empty db the entries supplied by the given function, which
+	// returns nil once the entries are exhausted, and records the given height as the save point
+	ImportPubAndHashedState(next func() (*SnapshotRecord, error), height *version.Height) error
 }
 
 // PvtdataCompositeKey encloses Namespace, CollectionName and Key components
@@ -0,0 +1,123 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privacyenabledstate
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+)
+
+// maxSnapshotImportBatchSize is the number of snapshot records that are applied to the db in one batch
+const maxSnapshotImportBatchSize = 10000
+
+// SnapshotRecord is an entry of the public state or of the hashed state (i.e., the hashes of the pvt data)
+// as exported to a ledger snapshot. For an entry of the hashed state, the Collection is set and the Key
+// carries the key hash
+type SnapshotRecord struct {
+	Namespace  string
+	Collection string
+	Key        []byte
+	Value      *statedb.VersionedValue
+}
+
+// ExportPubAndHashedState implements corresponding function in interface DB
+func (s *CommonStorageDB) ExportPubAndHashedState(fn func(*SnapshotRecord) error) error {
+	itr, err := s.VersionedDB.GetFullScanIterator(isPvtDataNs)
+	if err != nil {
+		return err
+	}
+	defer itr.Close()
+	for {
+		res, err := itr.Next()
+		if err != nil {
+			return err
+		}
+		if res == nil {
+			return nil
+		}
+		kv := res.(*statedb.VersionedKV)
+		vv := kv.VersionedValue
+		record := &SnapshotRecord{Namespace: kv.Namespace, Key: []byte(kv.Key), Value: &vv}
+		if ns, coll, ok := splitHashedDataNs(kv.Namespace); ok {
+			record.Namespace, record.Collection = ns, coll
+			if !s.BytesKeySuppoted() {
+				if record.Key, err = base64.StdEncoding.DecodeString(kv.Key); err != nil {
+					return err
+				}
+			}
+		}
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+}
+
+// ImportPubAndHashedState implements corresponding function in interface DB
+func (s *CommonStorageDB) ImportPubAndHashedState(next func() (*SnapshotRecord, error), height *version.Height) error {
+	savepoint, err := s.GetLatestSavePoint()
+	if err != nil {
+		return err
+	}
+	if savepoint != nil {
+		return fmt.Errorf("the state db is not empty, save point = %#v", savepoint)
+	}
+	batch := NewPubUpdateBatch()
+	numRecords := 0
+	for {
+		record, err := next()
+		if err != nil {
+			return err
+		}
+		if record == nil {
+			break
+		}
+		if err := s.addSnapshotRecord(batch, record); err != nil {
+			return err
+		}
+		if numRecords++; numRecords%maxSnapshotImportBatchSize != 0 {
+			continue
+		}
+		if err := s.VersionedDB.ApplyUpdates(batch.UpdateBatch, nil); err != nil {
+			return err
+		}
+		batch = NewPubUpdateBatch()
+	}
+	return s.VersionedDB.ApplyUpdates(batch.UpdateBatch, height)
+}
+
+func (s *CommonStorageDB) addSnapshotRecord(batch *PubUpdateBatch, record *SnapshotRecord) error {
+	if record.Value == nil || record.Value.Value == nil {
+		return fmt.Errorf("no value present for the key [%s] of the namespace [%s]", record.Key, record.Namespace)
+	}
+	if record.Collection == "" {
+		batch.Update(record.Namespace, string(record.Key), record.Value)
+		return nil
+	}
+	key := string(record.Key)
+	if !s.BytesKeySuppoted() {
+		key = base64.StdEncoding.EncodeToString(record.Key)
+	}
+	batch.Update(deriveHashedDataNs(record.Namespace, record.Collection), key, record.Value)
+	return nil
+}
+
+func isPvtDataNs(namespace string) bool {
+	return strings.Contains(namespace, nsJoiner+pvtDataPrefix)
+}
+
+// splitHashedDataNs splits a namespace derived by function `deriveHashedDataNs` into the namespace and the collection
+func splitHashedDataNs(derivedNs string) (string, string, bool) {
+	idx := strings.Index(derivedNs, nsJoiner+hashDataPrefix)
+	if idx < 0 {
+		return "", "", false
+	}
+	return derivedNs[:idx], derivedNs[idx+len(nsJoiner+hashDataPrefix):], true
+}
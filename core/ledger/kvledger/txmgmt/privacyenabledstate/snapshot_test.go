@@ -0,0 +1,70 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privacyenabledstate
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportImportPubAndHashedState(t *testing.T) {
+	env := &LevelDBCommonStorageTestEnv{}
+	env.Init(t)
+	defer env.Cleanup()
+
+	db := env.GetDBHandle("source")
+	updates := NewUpdateBatch()
+	updates.PubUpdates.PutValAndMetadata("ns1", "key1", []byte("value1"), []byte("metadata1"), version.NewHeight(1, 1))
+	updates.PubUpdates.Put("ns2", "key1", []byte("value2"), version.NewHeight(1, 2))
+	putPvtUpdates(t, updates, "ns1", "coll1", "key1", []byte("pvtvalue1"), version.NewHeight(1, 3))
+	assert.NoError(t, db.ApplyPrivacyAwareUpdates(updates, version.NewHeight(1, 3)))
+
+	var records []*SnapshotRecord
+	assert.NoError(t, db.ExportPubAndHashedState(func(record *SnapshotRecord) error {
+		records = append(records, record)
+		return nil
+	}))
+	assert.Equal(t, []*SnapshotRecord{
+		{Namespace: "ns1", Key: []byte("key1"),
+			Value: &statedb.VersionedValue{Value: []byte("value1"), Metadata: []byte("metadata1"), Version: version.NewHeight(1, 1)}},
+		{Namespace: "ns1", Collection: "coll1", Key: util.ComputeStringHash("key1"),
+			Value: &statedb.VersionedValue{Value: util.ComputeHash([]byte("pvtvalue1")), Version: version.NewHeight(1, 3)}},
+		{Namespace: "ns2", Key: []byte("key1"),
+			Value: &statedb.VersionedValue{Value: []byte("value2"), Version: version.NewHeight(1, 2)}},
+	}, records)
+
+	target := env.GetDBHandle("target")
+	next := func() (*SnapshotRecord, error) {
+		if len(records) == 0 {
+			return nil, nil
+		}
+		record := records[0]
+		records = records[1:]
+		return record, nil
+	}
+	assert.NoError(t, target.ImportPubAndHashedState(next, version.NewHeight(5, 2)))
+
+	vv, err := target.GetState("ns1", "key1")
+	assert.NoError(t, err)
+	assert.Equal(t, &statedb.VersionedValue{Value: []byte("value1"), Metadata: []byte("metadata1"), Version: version.NewHeight(1, 1)}, vv)
+	vv, err = target.GetValueHash("ns1", "coll1", util.ComputeStringHash("key1"))
+	assert.NoError(t, err)
+	assert.Equal(t, &statedb.VersionedValue{Value: util.ComputeHash([]byte("pvtvalue1")), Version: version.NewHeight(1, 3)}, vv)
+	vv, err = target.GetPrivateData("ns1", "coll1", "key1")
+	assert.NoError(t, err)
+	assert.Nil(t, vv)
+	savepoint, err := target.GetLatestSavePoint()
+	assert.NoError(t, err)
+	assert.Equal(t, version.NewHeight(5, 2), savepoint)
+
+	err = target.ImportPubAndHashedState(next, version.NewHeight(6, 0))
+	assert.EqualError(t, err, "the state db is not empty, save point = &version.Height{BlockNum:0x5, TxNum:0x2}")
+}
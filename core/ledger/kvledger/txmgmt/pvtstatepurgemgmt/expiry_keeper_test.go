@@ -53,6 +53,12 @@ func TestExpiryKeeper(t *testing.T) {
 	listExpinfo3, _ := expiryKeeper.retrieve(17)
 	assert.Equal(t, []*expiryInfo{expinfo4}, listExpinfo3)
 
+	// Retrieve entries by expiry key
+	expinfo, _ := expiryKeeper.retrieveByExpiryKey(&expiryInfoKey{committingBlk: 3, expiryBlk: 15})
+	assert.Equal(t, expinfo2, expinfo)
+	expinfo, _ = expiryKeeper.retrieveByExpiryKey(&expiryInfoKey{committingBlk: 3, expiryBlk: 17})
+	assert.Equal(t, &expiryInfo{&expiryInfoKey{committingBlk: 3, expiryBlk: 17}, newPvtdataKeys()}, expinfo)
+
 	// Clear entries for keys expiring at block 13 and 15 and again retrieve by expiring block 13, 15, and 17
 	expiryKeeper.updateBookkeeping(nil, []*expiryInfoKey{expinfo1.expiryInfoKey, expinfo2.expiryInfoKey, expinfo3.expiryInfoKey})
 	listExpinfo4, _ := expiryKeeper.retrieve(13)
@@ -0,0 +1,103 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pvtstatepurgemgmt
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger/bookkeeping"
+)
+
+// ExpiryScheduleEntry is an entry of the expiry schedule as exported to a ledger snapshot, i.e., the hash of
+// a pvt data key that is committed by the block 'CommittingBlk' and is to be purged with the commit of the
+// block 'ExpiryBlk'. The pvt data keys are not exported as the pvt data itself is not part of a snapshot
+type ExpiryScheduleEntry struct {
+	CommittingBlk uint64
+	ExpiryBlk     uint64
+	Namespace     string
+	Collection    string
+	KeyHash       []byte
+}
+
+// ExportExpirySchedule passes to the given function the expiry schedule of the given ledger. The entries are
+// passed in the order of the expiry block, the committing block, the namespace, the collection, and the key hash,
+// so that the exported schedule does not depend on the pvt data present on the peer
+func ExportExpirySchedule(ledgerID string, bookkeepingProvider bookkeeping.Provider, fn func(*ExpiryScheduleEntry) error) error {
+	itr := bookkeepingProvider.GetDBHandle(ledgerID, bookkeeping.PvtdataExpiry).GetIterator(nil, nil)
+	defer itr.Release()
+	for itr.Next() {
+		expinfo, err := decodeExpiryInfo(itr.Key(), itr.Value())
+		if err != nil {
+			return err
+		}
+		var entries []*ExpiryScheduleEntry
+		for ns, colls := range expinfo.pvtdataKeys.Map {
+			for coll, keysAndHashes := range colls.Map {
+				for _, keyAndHash := range keysAndHashes.List {
+					entries = append(entries, &ExpiryScheduleEntry{
+						CommittingBlk: expinfo.expiryInfoKey.committingBlk,
+						ExpiryBlk:     expinfo.expiryInfoKey.expiryBlk,
+						Namespace:     ns,
+						Collection:    coll,
+						KeyHash:       keyAndHash.Hash,
+					})
+				}
+			}
+		}
+		sort.Slice(entries, func(i, j int) bool {
+			if entries[i].Namespace != entries[j].Namespace {
+				return entries[i].Namespace < entries[j].Namespace
+			}
+			if entries[i].Collection != entries[j].Collection {
+				return entries[i].Collection < entries[j].Collection
+			}
+			return bytes.Compare(entries[i].KeyHash, entries[j].KeyHash) < 0
+		})
+		for _, entry := range entries {
+			if err := fn(entry); err != nil {
+				return err
+			}
+		}
+	}
+	return itr.Error()
+}
+
+// ImportExpirySchedule adds to the expiry schedule of the given ledger the entries supplied by the given
+// function, which returns nil once the entries are exhausted
+func ImportExpirySchedule(ledgerID string, bookkeepingProvider bookkeeping.Provider, next func() (*ExpiryScheduleEntry, error)) error {
+	ek := newExpiryKeeper(ledgerID, bookkeepingProvider)
+	var current *expiryInfo
+	flush := func() error {
+		if current == nil {
+			return nil
+		}
+		existing, err := ek.retrieveByExpiryKey(current.expiryInfoKey)
+		if err != nil {
+			return err
+		}
+		existing.pvtdataKeys.merge(current.pvtdataKeys)
+		return ek.updateBookkeeping([]*expiryInfo{existing}, nil)
+	}
+	for {
+		entry, err := next()
+		if err != nil {
+			return err
+		}
+		if entry == nil {
+			return flush()
+		}
+		key := expiryInfoKey{committingBlk: entry.CommittingBlk, expiryBlk: entry.ExpiryBlk}
+		if current == nil || *current.expiryInfoKey != key {
+			if err := flush(); err != nil {
+				return err
+			}
+			current = &expiryInfo{expiryInfoKey: &key, pvtdataKeys: newPvtdataKeys()}
+		}
+		current.pvtdataKeys.add(entry.Namespace, entry.Collection, "", entry.KeyHash)
+	}
+}
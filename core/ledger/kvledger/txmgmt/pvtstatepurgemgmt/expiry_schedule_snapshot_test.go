@@ -0,0 +1,68 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pvtstatepurgemgmt
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger/bookkeeping"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportImportExpirySchedule(t *testing.T) {
+	testenv := bookkeeping.NewTestEnv(t)
+	defer testenv.Cleanup()
+	sourceKeeper := newExpiryKeeper("source", testenv.TestProvider)
+
+	pvtdataKeys := newPvtdataKeys()
+	pvtdataKeys.add("ns-2", "coll-1", "key-2", []byte("key-2-hash"))
+	pvtdataKeys.add("ns-1", "coll-1", "key-1", []byte("key-1-hash"))
+	// the hash of a key whose pvt data is missing on this peer
+	pvtdataKeys.add("ns-1", "coll-1", "", []byte("key-0-hash"))
+	expinfo1 := &expiryInfo{&expiryInfoKey{committingBlk: 3, expiryBlk: 13}, pvtdataKeys}
+	expinfo2 := &expiryInfo{&expiryInfoKey{committingBlk: 4, expiryBlk: 12}, buildPvtdataKeysForTest(3, 0)}
+	assert.NoError(t, sourceKeeper.updateBookkeeping([]*expiryInfo{expinfo1, expinfo2}, nil))
+
+	var entries []*ExpiryScheduleEntry
+	assert.NoError(t, ExportExpirySchedule("source", testenv.TestProvider, func(entry *ExpiryScheduleEntry) error {
+		entries = append(entries, entry)
+		return nil
+	}))
+	assert.Equal(t, []*ExpiryScheduleEntry{
+		{CommittingBlk: 4, ExpiryBlk: 12, Namespace: "ns-3", Collection: "coll-3", KeyHash: []byte("key-3-hash")},
+		{CommittingBlk: 3, ExpiryBlk: 13, Namespace: "ns-1", Collection: "coll-1", KeyHash: []byte("key-0-hash")},
+		{CommittingBlk: 3, ExpiryBlk: 13, Namespace: "ns-1", Collection: "coll-1", KeyHash: []byte("key-1-hash")},
+		{CommittingBlk: 3, ExpiryBlk: 13, Namespace: "ns-2", Collection: "coll-1", KeyHash: []byte("key-2-hash")},
+	}, entries)
+
+	toImport := entries
+	next := func() (*ExpiryScheduleEntry, error) {
+		if len(toImport) == 0 {
+			return nil, nil
+		}
+		entry := toImport[0]
+		toImport = toImport[1:]
+		return entry, nil
+	}
+	assert.NoError(t, ImportExpirySchedule("target", testenv.TestProvider, next))
+	targetKeeper := newExpiryKeeper("target", testenv.TestProvider)
+	expected := newPvtdataKeys()
+	expected.add("ns-1", "coll-1", "", []byte("key-0-hash"))
+	expected.add("ns-1", "coll-1", "", []byte("key-1-hash"))
+	expected.add("ns-2", "coll-1", "", []byte("key-2-hash"))
+	listExpinfo, err := targetKeeper.retrieve(13)
+	assert.NoError(t, err)
+	assert.Equal(t, []*expiryInfo{{&expiryInfoKey{committingBlk: 3, expiryBlk: 13}, expected}}, listExpinfo)
+
+	// re-exporting the imported schedule yields the same entries
+	var reexported []*ExpiryScheduleEntry
+	assert.NoError(t, ExportExpirySchedule("target", testenv.TestProvider, func(entry *ExpiryScheduleEntry) error {
+		reexported = append(reexported, entry)
+		return nil
+	}))
+	assert.Equal(t, entries, reexported)
+}
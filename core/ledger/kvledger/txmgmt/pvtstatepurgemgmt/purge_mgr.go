@@ -14,6 +14,7 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
 	"github.com/hyperledger/fabric/core/ledger/pvtdatapolicy"
+	"github.com/hyperledger/fabric/core/ledger/util"
 )
 
 // PurgeMgr manages purging of the expired pvtdata
@@ -24,6 +25,9 @@ type PurgeMgr interface {
 	DeleteExpiredAndUpdateBookkeeping(
 		pvtUpdates *privacyenabledstate.PvtUpdateBatch,
 		hashedUpdates *privacyenabledstate.HashedUpdateBatch) error
+	// UpdateBookkeepingForPvtDataOfOldBlocks updates the bookkeeping for the pvtdata of the already committed blocks
+	// so that the pvtdata keys, whose hashes were committed earlier without the pvtdata, are also purged on expiry
+	UpdateBookkeepingForPvtDataOfOldBlocks(pvtUpdates *privacyenabledstate.PvtUpdateBatch) error
 	// BlockCommitDone is a callback to the PurgeMgr when the block is committed to the ledger
 	BlockCommitDone()
 }
@@ -111,6 +115,47 @@ func (p *purgeMgr) DeleteExpiredAndUpdateBookkeeping(
 	return p.expKeeper.updateBookkeeping(listExpiryInfo, nil)
 }
 
+// UpdateBookkeepingForPvtDataOfOldBlocks implements function in the interface 'PurgeMgr'
+func (p *purgeMgr) UpdateBookkeepingForPvtDataOfOldBlocks(pvtUpdates *privacyenabledstate.PvtUpdateBatch) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	builder := newExpiryScheduleBuilder(p.btlPolicy)
+	for pvtUpdateKey, vv := range pvtUpdates.ToCompositeKeyMap() {
+		keyHash := util.ComputeStringHash(pvtUpdateKey.Key)
+		if err := builder.add(pvtUpdateKey.Namespace, pvtUpdateKey.CollectionName, pvtUpdateKey.Key, keyHash, vv); err != nil {
+			return err
+		}
+	}
+	var listExpiryInfo []*expiryInfo
+	for _, toAdd := range builder.getExpiryInfo() {
+		// the entry that was added at the time of the block commit carries only the key hashes
+		existing, err := p.expKeeper.retrieveByExpiryKey(toAdd.expiryInfoKey)
+		if err != nil {
+			return err
+		}
+		existing.pvtdataKeys.merge(toAdd.pvtdataKeys)
+		listExpiryInfo = append(listExpiryInfo, existing)
+		p.addKeysToWorkingset(toAdd)
+	}
+	return p.expKeeper.updateBookkeeping(listExpiryInfo, nil)
+}
+
+// addKeysToWorkingset adds the pvtdata keys to the working set if the working set has already been
+// prepared for the block at which these keys expire (with only the key hashes loaded from the bookkeeper)
+func (p *purgeMgr) addKeysToWorkingset(expinfo *expiryInfo) {
+	if p.workingset == nil || p.workingset.expiringBlk != expinfo.expiryInfoKey.expiryBlk {
+		return
+	}
+	toAdd := transformToExpiryInfoMap([]*expiryInfo{expinfo})
+	for hashedKey, keyAndVersion := range p.workingset.toPurge {
+		for hashedKeyToAdd, keyAndVersionToAdd := range toAdd {
+			if *hashedKey == *hashedKeyToAdd {
+				keyAndVersion.key = keyAndVersionToAdd.key
+			}
+		}
+	}
+}
+
 // BlockCommitDone implements function in the interface 'PurgeMgr'
 // These orphan entries for purge-schedule can be cleared off in bulk in a separate background routine as well
 // If we maintian the following logic (i.e., clear off entries just after block commit), we need a TODO -
@@ -99,6 +99,50 @@ func testPergeMgr(t *testing.T, dbEnv privacyenabledstate.TestEnv) {
 	testHelper.checkPvtdataDoesNotExist("ns1", "coll4", "pvtkey4")
 }
 
+func TestPurgeMgrForPvtDataOfOldBlocks(t *testing.T) {
+	dbEnvs := []privacyenabledstate.TestEnv{
+		&privacyenabledstate.LevelDBCommonStorageTestEnv{},
+		&privacyenabledstate.CouchDBCommonStorageTestEnv{},
+	}
+	for _, dbEnv := range dbEnvs {
+		t.Run(dbEnv.GetName(), func(t *testing.T) { testPurgeMgrForPvtDataOfOldBlocks(t, dbEnv) })
+	}
+}
+
+func testPurgeMgrForPvtDataOfOldBlocks(t *testing.T, dbEnv privacyenabledstate.TestEnv) {
+	ledgerid := "testledger-purge-mgr-pvtdata-oldblocks"
+	cs := btltestutil.NewMockCollectionStore()
+	cs.SetBTL("ns1", "coll1", 1)
+	btlPolicy := pvtdatapolicy.ConstructBTLPolicy(cs)
+
+	testHelper := &testHelper{}
+	testHelper.init(t, ledgerid, btlPolicy, dbEnv)
+	defer testHelper.cleanup()
+
+	// block 1 is committed with the hashes but without the pvtdata
+	block1Updates := privacyenabledstate.NewUpdateBatch()
+	block1Updates.HashUpdates.Put("ns1", "coll1", util.ComputeStringHash("pvtkey1"), util.ComputeHash([]byte("pvtvalue1-1")), version.NewHeight(1, 1))
+	testHelper.commitUpdatesForTesting(1, block1Updates)
+	vv, hashVersion := testHelper.fetchPvtdataFronDB("ns1", "coll1", "pvtkey1")
+	assert.Nil(t, vv)
+	assert.Equal(t, version.NewHeight(1, 1), hashVersion)
+
+	// the missing pvtdata of block 1 is committed later
+	oldBlockUpdates := privacyenabledstate.NewUpdateBatch()
+	oldBlockUpdates.PvtUpdates.Put("ns1", "coll1", "pvtkey1", []byte("pvtvalue1-1"), version.NewHeight(1, 1))
+	assert.NoError(t, testHelper.purgeMgr.UpdateBookkeepingForPvtDataOfOldBlocks(oldBlockUpdates.PvtUpdates))
+	assert.NoError(t, testHelper.db.ApplyPrivacyAwareUpdates(oldBlockUpdates, nil))
+	testHelper.checkPvtdataExists("ns1", "coll1", "pvtkey1", []byte("pvtvalue1-1"))
+
+	noPvtdataUpdates := privacyenabledstate.NewUpdateBatch()
+	testHelper.commitUpdatesForTesting(2, noPvtdataUpdates)
+	testHelper.checkPvtdataExists("ns1", "coll1", "pvtkey1", []byte("pvtvalue1-1"))
+
+	// the pvtdata committed later is purged along with its hash
+	testHelper.commitUpdatesForTesting(3, noPvtdataUpdates)
+	testHelper.checkPvtdataDoesNotExist("ns1", "coll1", "pvtkey1")
+}
+
 type testHelper struct {
 	t              *testing.T
 	bookkeepingEnv *bookkeeping.TestEnv
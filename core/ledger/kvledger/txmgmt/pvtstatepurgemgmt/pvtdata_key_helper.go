@@ -6,12 +6,37 @@ SPDX-License-Identifier: Apache-2.0
 
 package pvtstatepurgemgmt
 
+import "bytes"
+
 func (pvtdataKeys *PvtdataKeys) add(ns string, coll string, key string, keyhash []byte) {
 	colls := pvtdataKeys.getOrCreateCollections(ns)
 	keysAndHashes := colls.getOrCreateKeysAndHashes(coll)
 	keysAndHashes.List = append(keysAndHashes.List, &KeyAndHash{Key: key, Hash: keyhash})
 }
 
+// merge adds the keys of 'toMerge' to 'pvtdataKeys'. If an entry for a key hash is already present
+// without the key (i.e., the pvtdata was missing at the time of the block commit), the key is filled in
+func (pvtdataKeys *PvtdataKeys) merge(toMerge *PvtdataKeys) {
+	for ns, colls := range toMerge.Map {
+		for coll, keysAndHashes := range colls.Map {
+			existing := pvtdataKeys.getOrCreateCollections(ns).getOrCreateKeysAndHashes(coll)
+			for _, keyAndHash := range keysAndHashes.List {
+				existing.addOrUpdate(keyAndHash)
+			}
+		}
+	}
+}
+
+func (keysAndHashes *KeysAndHashes) addOrUpdate(keyAndHash *KeyAndHash) {
+	for _, existing := range keysAndHashes.List {
+		if bytes.Equal(existing.Hash, keyAndHash.Hash) {
+			existing.Key = keyAndHash.Key
+			return
+		}
+	}
+	keysAndHashes.List = append(keysAndHashes.List, keyAndHash)
+}
+
 func (pvtdataKeys *PvtdataKeys) getOrCreateCollections(ns string) *Collections {
 	colls, ok := pvtdataKeys.Map[ns]
 	if !ok {
@@ -241,3 +241,37 @@ func serializeTestProtoMsg(t *testing.T, protoMsg proto.Message) []byte {
 	testutil.AssertNoError(t, err, "")
 	return msgBytes
 }
+
+func TestTxSimulationResultWithMetadata(t *testing.T) {
+	rwSetBuilder := NewRWSetBuilder()
+	rwSetBuilder.AddToReadSet("ns1", "key1", version.NewHeight(1, 1))
+	rwSetBuilder.AddToWriteSet("ns1", "key1", []byte("value1"))
+	rwSetBuilder.AddToMetadataWriteSet("ns1", "key2", map[string][]byte{"metakey2": []byte("metavalue2"), "metakey1": []byte("metavalue1")})
+	rwSetBuilder.AddToMetadataWriteSet("ns1", "key1", map[string][]byte{"metakey1": []byte("metavalue1")})
+	// the latest metadata write for a key overrides the previous ones
+	rwSetBuilder.AddToMetadataWriteSet("ns1", "key1", nil)
+
+	txSimulationResults, err := rwSetBuilder.GetTxSimulationResults()
+	assert.NoError(t, err)
+
+	ns1KVRWSet := &kvrwset.KVRWSet{
+		Reads:  []*kvrwset.KVRead{NewKVRead("key1", version.NewHeight(1, 1))},
+		Writes: []*kvrwset.KVWrite{newKVWrite("key1", []byte("value1"))},
+		MetadataWrites: []*kvrwset.KVMetadataWrite{
+			{Key: "key1", Entries: []*kvrwset.KVMetadataEntry{}},
+			{Key: "key2", Entries: []*kvrwset.KVMetadataEntry{
+				{Name: "metakey1", Value: []byte("metavalue1")},
+				{Name: "metakey2", Value: []byte("metavalue2")},
+			}},
+		},
+	}
+	expectedTxRWSet := &rwset.TxReadWriteSet{NsRwset: []*rwset.NsReadWriteSet{
+		{Namespace: "ns1", Rwset: serializeTestProtoMsg(t, ns1KVRWSet)},
+	}}
+	assert.Equal(t, expectedTxRWSet, txSimulationResults.PubSimulationResults)
+
+	txRWSet, err := TxRwSetFromProtoMsg(txSimulationResults.PubSimulationResults)
+	assert.NoError(t, err)
+	assert.Len(t, txRWSet.NsRwSets[0].KvRwSet.MetadataWrites, 2)
+	assert.Equal(t, "key2", txRWSet.NsRwSets[0].KvRwSet.MetadataWrites[1].Key)
+}
@@ -303,6 +303,15 @@ func newKVWrite(key string, value []byte) *kvrwset.KVWrite {
 	return &kvrwset.KVWrite{Key: key, IsDelete: value == nil, Value: value}
 }
 
+func newKVMetadataWrite(key string, metadata map[string][]byte) *kvrwset.KVMetadataWrite {
+	names := util.GetSortedKeys(metadata)
+	entries := make([]*kvrwset.KVMetadataEntry, 0, len(names))
+	for _, name := range names {
+		entries = append(entries, &kvrwset.KVMetadataEntry{Name: name, Value: metadata[name]})
+	}
+	return &kvrwset.KVMetadataWrite{Key: key, Entries: entries}
+}
+
 func newPvtKVReadHash(key string, version *version.Height) *kvrwset.KVReadHash {
 	return &kvrwset.KVReadHash{KeyHash: util.ComputeStringHash(key), Version: newProtoVersion(version)}
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package commontests
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
@@ -198,6 +199,65 @@ func TestIterator(t *testing.T, dbProvider statedb.VersionedDBProvider) {
 	testItr(t, itr4, []string{"key5", "key6"})
 }
 
+// TestPaginatedRangeQuery tests range queries with pagination
+func TestPaginatedRangeQuery(t *testing.T, dbProvider statedb.VersionedDBProvider) {
+	db, err := dbProvider.GetDBHandle("testpaginatedrangequery")
+	testutil.AssertNoError(t, err, "")
+	db.Open()
+	defer db.Close()
+	batch := statedb.NewUpdateBatch()
+	for i := 1; i <= 7; i++ {
+		batch.Put("ns1", fmt.Sprintf("key%d", i), []byte(fmt.Sprintf("value%d", i)), version.NewHeight(1, uint64(i)))
+	}
+	batch.Put("ns2", "key8", []byte("value8"), version.NewHeight(1, 8))
+	db.ApplyUpdates(batch, version.NewHeight(2, 1))
+
+	// the pages are retrieved by passing the bookmark of the previous page
+	itr, err := db.GetStateRangeScanIteratorWithPagination("ns1", "key1", "", 3, "")
+	testutil.AssertNoError(t, err, "")
+	testItrWithoutClose(t, itr, []string{"key1", "key2", "key3"})
+	bookmark := itr.GetBookmarkAndClose()
+	testutil.AssertEquals(t, bookmark, "key4")
+
+	itr, err = db.GetStateRangeScanIteratorWithPagination("ns1", "key1", "", 3, bookmark)
+	testutil.AssertNoError(t, err, "")
+	testItrWithoutClose(t, itr, []string{"key4", "key5", "key6"})
+	bookmark = itr.GetBookmarkAndClose()
+	testutil.AssertEquals(t, bookmark, "key7")
+
+	itr, err = db.GetStateRangeScanIteratorWithPagination("ns1", "key1", "", 3, bookmark)
+	testutil.AssertNoError(t, err, "")
+	testItrWithoutClose(t, itr, []string{"key7"})
+	testutil.AssertEquals(t, itr.GetBookmarkAndClose(), "")
+
+	// a page that ends exactly at the end of the range
+	itr, err = db.GetStateRangeScanIteratorWithPagination("ns1", "key2", "key4", 2, "")
+	testutil.AssertNoError(t, err, "")
+	testItrWithoutClose(t, itr, []string{"key2", "key3"})
+	testutil.AssertEquals(t, itr.GetBookmarkAndClose(), "")
+
+	// a pageSize of zero returns all the results
+	itr, err = db.GetStateRangeScanIteratorWithPagination("ns1", "", "", 0, "key5")
+	testutil.AssertNoError(t, err, "")
+	testItrWithoutClose(t, itr, []string{"key5", "key6", "key7"})
+	testutil.AssertEquals(t, itr.GetBookmarkAndClose(), "")
+
+	_, err = db.GetStateRangeScanIteratorWithPagination("ns1", "key2", "key4", 2, "key5")
+	testutil.AssertError(t, err, "Expected an error for a bookmark outside the requested key range")
+}
+
+func testItrWithoutClose(t *testing.T, itr statedb.ResultsIterator, expectedKeys []string) {
+	for _, expectedKey := range expectedKeys {
+		queryResult, err := itr.Next()
+		testutil.AssertNoError(t, err, "")
+		testutil.AssertNotNil(t, queryResult)
+		testutil.AssertEquals(t, queryResult.(*statedb.VersionedKV).Key, expectedKey)
+	}
+	queryResult, err := itr.Next()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNil(t, queryResult)
+}
+
 func testItr(t *testing.T, itr statedb.ResultsIterator, expectedKeys []string) {
 	defer itr.Close()
 	for _, expectedKey := range expectedKeys {
@@ -725,3 +785,40 @@ func TestBatchWithIndividualRetry(t *testing.T, dbProvider statedb.VersionedDBPr
 	testutil.AssertNoError(t, err, "")
 
 }
+
+// TestValueAndMetadataWrites tests statedb for value and metadata read-writes
+func TestValueAndMetadataWrites(t *testing.T, dbProvider statedb.VersionedDBProvider) {
+	db, err := dbProvider.GetDBHandle("testvalueandmetadata")
+	testutil.AssertNoError(t, err, "")
+	batch := statedb.NewUpdateBatch()
+
+	vv1 := statedb.VersionedValue{Value: []byte("value1"), Metadata: []byte("metadata1"), Version: version.NewHeight(1, 1)}
+	vv2 := statedb.VersionedValue{Value: []byte("value2"), Metadata: []byte("metadata2"), Version: version.NewHeight(1, 2)}
+	vv3 := statedb.VersionedValue{Value: []byte(`{"asset_name":"marble1","color":"blue"}`), Metadata: []byte("metadata3"), Version: version.NewHeight(1, 3)}
+	vv4 := statedb.VersionedValue{Value: []byte("value4"), Version: version.NewHeight(1, 4)}
+
+	batch.PutValAndMetadata("ns1", "key1", vv1.Value, vv1.Metadata, vv1.Version)
+	batch.PutValAndMetadata("ns1", "key2", vv2.Value, vv2.Metadata, vv2.Version)
+	batch.PutValAndMetadata("ns2", "key3", vv3.Value, vv3.Metadata, vv3.Version)
+	batch.PutValAndMetadata("ns2", "key4", vv4.Value, vv4.Metadata, vv4.Version)
+	db.ApplyUpdates(batch, version.NewHeight(2, 5))
+
+	vv, _ := db.GetState("ns1", "key1")
+	testutil.AssertEquals(t, vv, &vv1)
+
+	vv, _ = db.GetState("ns2", "key3")
+	testutil.AssertEquals(t, vv, &vv3)
+
+	vv, _ = db.GetState("ns2", "key4")
+	testutil.AssertEquals(t, vv, &vv4)
+
+	itr, err := db.GetStateRangeScanIterator("ns1", "", "")
+	testutil.AssertNoError(t, err, "")
+	defer itr.Close()
+	res, err := itr.Next()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, res.(*statedb.VersionedKV).VersionedValue, vv1)
+	res, err = itr.Next()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, res.(*statedb.VersionedKV).VersionedValue, vv2)
+}
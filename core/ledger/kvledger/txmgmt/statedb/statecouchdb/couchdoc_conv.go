@@ -7,6 +7,7 @@ package statecouchdb
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"strconv"
@@ -22,10 +23,11 @@ const (
 	idField       = "_id"
 	revField      = "_rev"
 	versionField  = "~version"
+	metadataField = "~metadata"
 	deletedField  = "_deleted"
 )
 
-var reservedFields = [4]string{idField, revField, versionField, deletedField}
+var reservedFields = [5]string{idField, revField, versionField, metadataField, deletedField}
 
 type keyValue struct {
 	key string
@@ -81,10 +83,18 @@ func couchDocToKeyValue(doc *couchdb.CouchDoc) (*keyValue, error) {
 	key := jsonResult[idField].(string)
 	// create the return version from the version field in the JSON
 	returnVersion := createVersionHeightFromVersionString(jsonResult[versionField].(string))
-	// remove the _id, _rev and version fields
+	// the metadata, if present, is stored as a base64 encoded string
+	var returnMetadata []byte
+	if encodedMetadata, fieldFound := jsonResult[metadataField]; fieldFound {
+		if returnMetadata, err = base64.StdEncoding.DecodeString(encodedMetadata.(string)); err != nil {
+			return nil, err
+		}
+	}
+	// remove the _id, _rev, version and metadata fields
 	delete(jsonResult, idField)
 	delete(jsonResult, revField)
 	delete(jsonResult, versionField)
+	delete(jsonResult, metadataField)
 
 	// handle binary or json data
 	if doc.Attachments != nil { // binary attachment
@@ -100,7 +110,11 @@ func couchDocToKeyValue(doc *couchdb.CouchDoc) (*keyValue, error) {
 			return nil, err
 		}
 	}
-	return &keyValue{key, &statedb.VersionedValue{Value: returnValue, Version: returnVersion}}, nil
+	return &keyValue{key, &statedb.VersionedValue{
+		Value:    returnValue,
+		Metadata: returnMetadata,
+		Version:  returnVersion},
+	}, nil
 }
 
 func keyValToCouchDoc(kv *keyValue, revision string) (*couchdb.CouchDoc, error) {
@@ -110,7 +124,7 @@ func keyValToCouchDoc(kv *keyValue, revision string) (*couchdb.CouchDoc, error)
 		kvTypeJSON
 		kvTypeAttachment
 	)
-	key, value, version := kv.key, kv.VersionedValue.Value, kv.VersionedValue.Version
+	key, value, metadata, version := kv.key, kv.VersionedValue.Value, kv.VersionedValue.Metadata, kv.VersionedValue.Version
 	jsonMap := make(jsonValue)
 
 	var kvtype kvType
@@ -128,6 +142,9 @@ func keyValToCouchDoc(kv *keyValue, revision string) (*couchdb.CouchDoc, error)
 
 	// add the version, id, revision, and delete marker (if needed)
 	jsonMap[versionField] = fmt.Sprintf("%v:%v", version.BlockNum, version.TxNum)
+	if metadata != nil {
+		jsonMap[metadataField] = base64.StdEncoding.EncodeToString(metadata)
+	}
 	jsonMap[idField] = key
 	if revision != "" {
 		jsonMap[revField] = revision
@@ -0,0 +1,68 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statecouchdb
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/core/ledger/util/couchdb"
+)
+
+// subNsDocsRetriever implements `batch` interface and wraps the function `couchdb.BatchRetrieveDocuments`
+// for allowing parallel execution of this function for different sets of keys within a namespace.
+// Different sets of keys is expected to be created based on configuration `ledgerconfig.GetMaxBatchUpdateSize()`
+type subNsDocsRetriever struct {
+	db              *couchdb.CouchDatabase
+	keys            []string
+	executionResult []*couchdb.QueryResult
+}
+
+// retrieveNsValues retrieves the values of the given keys within a namespace using bulk reads.
+// The keys that do not exist in the db are not present in the returned map
+func retrieveNsValues(db *couchdb.CouchDatabase, keys []string) (map[string]*statedb.VersionedValue, error) {
+	// consturct one batch per group of keys based on maxBacthSize
+	maxBacthSize := ledgerconfig.GetMaxBatchUpdateSize()
+	batches := []batch{}
+	remainingKeys := keys
+	for {
+		numKeys := minimum(maxBacthSize, len(remainingKeys))
+		if numKeys == 0 {
+			break
+		}
+		batch := &subNsDocsRetriever{db: db, keys: remainingKeys[:numKeys]}
+		batches = append(batches, batch)
+		remainingKeys = remainingKeys[numKeys:]
+	}
+	if err := executeBatches(batches); err != nil {
+		return nil, err
+	}
+	// accumulate results from each batch
+	values := make(map[string]*statedb.VersionedValue)
+	for _, b := range batches {
+		for _, doc := range b.(*subNsDocsRetriever).executionResult {
+			kv, err := couchDocToKeyValue(&couchdb.CouchDoc{JSONValue: doc.Value, Attachments: doc.Attachments})
+			if err != nil {
+				return nil, err
+			}
+			values[kv.key] = kv.VersionedValue
+		}
+	}
+	return values, nil
+}
+
+func (b *subNsDocsRetriever) execute() error {
+	var err error
+	if b.executionResult, err = b.db.BatchRetrieveDocuments(b.keys); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (b *subNsDocsRetriever) String() string {
+	return fmt.Sprintf("subNsDocsRetriever:db=%s, num keys=%d", b.db.DBName, len(b.keys))
+}
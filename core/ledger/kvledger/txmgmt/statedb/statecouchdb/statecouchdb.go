@@ -9,6 +9,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"sync"
 	"unicode/utf8"
 
@@ -23,8 +24,7 @@ import (
 
 var logger = flogging.MustGetLogger("statecouchdb")
 
-// querySkip is implemented for future use by query paging
-// currently defaulted to 0 and is not used
+// querySkip is defaulted to 0 as query paging is implemented using bookmarks
 const querySkip = 0
 
 var dbArtifactsDirFilter = map[string]bool{"META-INF/statedb/couchdb/indexes": true}
@@ -70,6 +70,30 @@ func (provider *VersionedDBProvider) GetDBHandle(dbName string) (statedb.Version
 	return vdb, nil
 }
 
+// Drop drops the metadata database and all the namespace databases of the named database (i.e., channel)
+func (provider *VersionedDBProvider) Drop(dbName string) error {
+	provider.mux.Lock()
+	defer provider.mux.Unlock()
+	couchDBNames, err := provider.couchInstance.RetrieveDatabaseNames()
+	if err != nil {
+		return err
+	}
+	metadataDBName := couchdb.ConstructMetadataDBName(dbName)
+	namespaceDBNamePrefix := dbName + "_"
+	for _, couchDBName := range couchDBNames {
+		if couchDBName != metadataDBName && !strings.HasPrefix(couchDBName, namespaceDBNamePrefix) {
+			continue
+		}
+		logger.Infof("Dropping database [%s] of channel [%s]", couchDBName, dbName)
+		db := &couchdb.CouchDatabase{CouchInstance: *provider.couchInstance, DBName: couchDBName}
+		if _, err := db.DropDatabase(); err != nil {
+			return fmt.Errorf("error while dropping database [%s]: %s", couchDBName, err)
+		}
+	}
+	delete(provider.databases, dbName)
+	return nil
+}
+
 // Close closes the underlying db instance
 func (provider *VersionedDBProvider) Close() {
 	// No close needed on Couch
@@ -82,6 +106,7 @@ type VersionedDB struct {
 	chainName          string                            // The name of the chain/channel.
 	namespaceDBs       map[string]*couchdb.CouchDatabase // One database per deployed chaincode.
 	committedDataCache *versionsCache                    // Used as a local cache during bulk processing of a block.
+	valueCache         *valueCache                       // Used as a cache of the committed values during simulation.
 	mux                sync.RWMutex
 }
 
@@ -96,7 +121,7 @@ func newVersionedDB(couchInstance *couchdb.CouchInstance, dbName string) (*Versi
 	}
 	namespaceDBMap := make(map[string]*couchdb.CouchDatabase)
 	return &VersionedDB{couchInstance: couchInstance, metadataDB: metadataDB, chainName: chainName, namespaceDBs: namespaceDBMap,
-		committedDataCache: newVersionCache(), mux: sync.RWMutex{}}, nil
+		committedDataCache: newVersionCache(), valueCache: newValueCache(ledgerconfig.GetValueCacheSize()), mux: sync.RWMutex{}}, nil
 }
 
 // getNamespaceDBHandle gets the handle to a named chaincode database
@@ -223,33 +248,64 @@ func (vdb *VersionedDB) BytesKeySuppoted() bool {
 // GetState implements method in VersionedDB interface
 func (vdb *VersionedDB) GetState(namespace string, key string) (*statedb.VersionedValue, error) {
 	logger.Debugf("GetState(). ns=%s, key=%s", namespace, key)
+	if vv, found := vdb.valueCache.get(namespace, key); found {
+		return vv, nil
+	}
 	db, err := vdb.getNamespaceDBHandle(namespace)
 	if err != nil {
 		return nil, err
 	}
+	cacheGeneration := vdb.valueCache.generation(namespace)
 	couchDoc, _, err := db.ReadDoc(key)
 	if err != nil {
 		return nil, err
 	}
 	if couchDoc == nil {
+		vdb.valueCache.add(namespace, key, nil, cacheGeneration)
 		return nil, nil
 	}
 	kv, err := couchDocToKeyValue(couchDoc)
 	if err != nil {
 		return nil, err
 	}
+	vdb.valueCache.add(namespace, key, kv.VersionedValue, cacheGeneration)
 	return kv.VersionedValue, nil
 }
 
 // GetStateMultipleKeys implements method in VersionedDB interface
+// The keys that are not found in the value cache are retrieved from CouchDB using bulk reads
 func (vdb *VersionedDB) GetStateMultipleKeys(namespace string, keys []string) ([]*statedb.VersionedValue, error) {
 	vals := make([]*statedb.VersionedValue, len(keys))
+	var missingKeys []string
+	missingKeyIndexes := make(map[string][]int)
 	for i, key := range keys {
-		val, err := vdb.GetState(namespace, key)
-		if err != nil {
-			return nil, err
+		if vv, found := vdb.valueCache.get(namespace, key); found {
+			vals[i] = vv
+			continue
+		}
+		if _, ok := missingKeyIndexes[key]; !ok {
+			missingKeys = append(missingKeys, key)
+		}
+		missingKeyIndexes[key] = append(missingKeyIndexes[key], i)
+	}
+	if len(missingKeys) == 0 {
+		return vals, nil
+	}
+	db, err := vdb.getNamespaceDBHandle(namespace)
+	if err != nil {
+		return nil, err
+	}
+	cacheGeneration := vdb.valueCache.generation(namespace)
+	retrievedVals, err := retrieveNsValues(db, missingKeys)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range missingKeys {
+		vv := retrievedVals[key]
+		vdb.valueCache.add(namespace, key, vv, cacheGeneration)
+		for _, i := range missingKeyIndexes[key] {
+			vals[i] = copyVersionedValue(vv)
 		}
-		vals[i] = val
 	}
 	return vals, nil
 }
@@ -258,8 +314,30 @@ func (vdb *VersionedDB) GetStateMultipleKeys(namespace string, keys []string) ([
 // startKey is inclusive
 // endKey is exclusive
 func (vdb *VersionedDB) GetStateRangeScanIterator(namespace string, startKey string, endKey string) (statedb.ResultsIterator, error) {
+	return vdb.GetStateRangeScanIteratorWithPagination(namespace, startKey, endKey, 0, "")
+}
+
+// GetStateRangeScanIteratorWithPagination implements method in VersionedDB interface
+// The bookmark is the key of the first result of the next page. A pageSize of 0 limits the results
+// to the querylimit from core.yaml, and a pageSize larger than the querylimit is reduced to it
+func (vdb *VersionedDB) GetStateRangeScanIteratorWithPagination(namespace string, startKey string, endKey string,
+	pageSize int32, bookmark string) (statedb.QueryResultsIterator, error) {
+	if pageSize < 0 {
+		return nil, fmt.Errorf("pageSize must not be negative")
+	}
+	if bookmark != "" {
+		if bookmark < startKey || (endKey != "" && bookmark >= endKey) {
+			return nil, fmt.Errorf("bookmark is outside the requested key range")
+		}
+		startKey = bookmark
+	}
 	// Get the querylimit from core.yaml
 	queryLimit := ledgerconfig.GetQueryLimit()
+	pageSize = limitPageSize(pageSize, queryLimit)
+	if pageSize > 0 {
+		// one additional record is read for determining the bookmark of the next page
+		queryLimit = int(pageSize) + 1
+	}
 	db, err := vdb.getNamespaceDBHandle(namespace)
 	if err != nil {
 		return nil, err
@@ -269,17 +347,35 @@ func (vdb *VersionedDB) GetStateRangeScanIterator(namespace string, startKey str
 		logger.Debugf("Error calling ReadDocRange(): %s\n", err.Error())
 		return nil, err
 	}
-	logger.Debugf("Exiting GetStateRangeScanIterator")
-	return newQueryScanner(namespace, *queryResult), nil
+	results := *queryResult
+	nextBookmark := ""
+	if pageSize > 0 && len(results) > int(pageSize) {
+		nextBookmark = results[pageSize].ID
+		results = results[:pageSize]
+	}
+	logger.Debugf("Exiting GetStateRangeScanIteratorWithPagination")
+	return newQueryScanner(namespace, results, nextBookmark), nil
 }
 
 // ExecuteQuery implements method in VersionedDB interface
 func (vdb *VersionedDB) ExecuteQuery(namespace, query string) (statedb.ResultsIterator, error) {
+	return vdb.ExecuteQueryWithPagination(namespace, query, 0, "")
+}
+
+// ExecuteQueryWithPagination implements method in VersionedDB interface
+// The bookmark is the one returned by CouchDB along with the previous page. A pageSize of 0 limits
+// the results to the querylimit from core.yaml, and a pageSize larger than the querylimit is reduced to it
+func (vdb *VersionedDB) ExecuteQueryWithPagination(namespace, query string, pageSize int32, bookmark string) (statedb.QueryResultsIterator, error) {
+	if pageSize < 0 {
+		return nil, fmt.Errorf("pageSize must not be negative")
+	}
 	// Get the querylimit from core.yaml
 	queryLimit := ledgerconfig.GetQueryLimit()
-	// Explicit paging not yet supported.
-	// Use queryLimit from config and 0 skip.
-	queryString, err := applyAdditionalQueryOptions(query, queryLimit, 0)
+	pageSize = limitPageSize(pageSize, queryLimit)
+	if pageSize > 0 {
+		queryLimit = int(pageSize)
+	}
+	queryString, err := applyAdditionalQueryOptions(query, queryLimit, querySkip, bookmark)
 	if err != nil {
 		logger.Debugf("Error calling applyAdditionalQueryOptions(): %s\n", err.Error())
 		return nil, err
@@ -288,13 +384,33 @@ func (vdb *VersionedDB) ExecuteQuery(namespace, query string) (statedb.ResultsIt
 	if err != nil {
 		return nil, err
 	}
-	queryResult, err := db.QueryDocuments(queryString)
+	queryResult, nextBookmark, err := db.QueryDocuments(queryString)
 	if err != nil {
 		logger.Debugf("Error calling QueryDocuments(): %s\n", err.Error())
 		return nil, err
 	}
-	logger.Debugf("Exiting ExecuteQuery")
-	return newQueryScanner(namespace, *queryResult), nil
+	// CouchDB returns a bookmark even if the results are exhausted; a page that is
+	// not full indicates that there are no more results
+	if pageSize == 0 || len(*queryResult) < int(pageSize) {
+		nextBookmark = ""
+	}
+	logger.Debugf("Exiting ExecuteQueryWithPagination")
+	return newQueryScanner(namespace, *queryResult, nextBookmark), nil
+}
+
+// limitPageSize reduces a pageSize that exceeds the querylimit from core.yaml to the querylimit
+func limitPageSize(pageSize int32, queryLimit int) int32 {
+	if int(pageSize) > queryLimit {
+		return int32(queryLimit)
+	}
+	return pageSize
+}
+
+// GetFullScanIterator implements method in VersionedDB interface. A full scan is not supported
+// because the namespaces cannot be derived back from the names of the namespace databases, which
+// are escaped and may be truncated
+func (vdb *VersionedDB) GetFullScanIterator(skipNamespace func(namespace string) bool) (statedb.ResultsIterator, error) {
+	return nil, fmt.Errorf("full scan of the state is not supported for CouchDB")
 }
 
 // ApplyUpdates implements method in VersionedDB interface
@@ -311,7 +427,12 @@ func (vdb *VersionedDB) ApplyUpdates(updates *statedb.UpdateBatch, height *versi
 		return err
 	}
 	// stage 2 - ApplyUpdates push the changes to the DB
+	// the cached values of the updated keys are dropped first so that the simulation reads
+	// the db while the changes are being pushed. If the push fails, the values that were read
+	// meanwhile are dropped again as the db may contain a part of the changes
+	vdb.valueCache.invalidate(updates)
 	if err = executeBatches(updateBatches); err != nil {
+		vdb.valueCache.invalidate(updates)
 		return err
 	}
 
@@ -320,8 +441,11 @@ func (vdb *VersionedDB) ApplyUpdates(updates *statedb.UpdateBatch, height *versi
 	// Record a savepoint at a given height
 	if err = vdb.ensureFullCommitAndRecordSavepoint(height, namespaces); err != nil {
 		logger.Errorf("Error during recordSavepoint: %s\n", err.Error())
+		vdb.valueCache.invalidate(updates)
 		return err
 	}
+	// the changes are in the db, cache the committed values
+	vdb.valueCache.update(updates)
 	return nil
 }
 
@@ -365,6 +489,10 @@ func (vdb *VersionedDB) ensureFullCommitAndRecordSavepoint(height *version.Heigh
 	if err := vdb.ensureFullCommit(dbs); err != nil {
 		return err
 	}
+	// a nil height denotes the updates that do not correspond to a new block, e.g., the pvt data of old blocks
+	if height == nil {
+		return nil
+	}
 	// construct savepoint document and save
 	savepointCouchDoc, err := encodeSavepoint(height)
 	if err != nil {
@@ -398,10 +526,11 @@ func (vdb *VersionedDB) GetLatestSavePoint() (*version.Height, error) {
 }
 
 // applyAdditionalQueryOptions will add additional fields to the query required for query processing
-func applyAdditionalQueryOptions(queryString string, queryLimit, querySkip int) (string, error) {
+func applyAdditionalQueryOptions(queryString string, queryLimit, querySkip int, bookmark string) (string, error) {
 	const jsonQueryFields = "fields"
 	const jsonQueryLimit = "limit"
 	const jsonQuerySkip = "skip"
+	const jsonQueryBookmark = "bookmark"
 	//create a generic map for the query json
 	jsonQueryMap := make(map[string]interface{})
 	//unmarshal the selector json into the generic map
@@ -414,21 +543,26 @@ func applyAdditionalQueryOptions(queryString string, queryLimit, querySkip int)
 	if fieldsJSONArray, ok := jsonQueryMap[jsonQueryFields]; ok {
 		switch fieldsJSONArray.(type) {
 		case []interface{}:
-			//Add the "_id", and "version" fields,  these are needed by default
+			//Add the "_id", "version" and "metadata" fields,  these are needed by default
 			jsonQueryMap[jsonQueryFields] = append(fieldsJSONArray.([]interface{}),
-				idField, versionField)
+				idField, versionField, metadataField)
 		default:
 			return "", fmt.Errorf("fields definition must be an array")
 		}
 	}
 	// Add limit
 	// This will override any limit passed in the query.
-	// Explicit paging not yet supported.
 	jsonQueryMap[jsonQueryLimit] = queryLimit
-	// Add skip of 0.
+	// Add skip
 	// This will override any skip passed in the query.
-	// Explicit paging not yet supported.
 	jsonQueryMap[jsonQuerySkip] = querySkip
+	// Add bookmark, if any
+	// This will override any bookmark passed in the query.
+	if bookmark != "" {
+		jsonQueryMap[jsonQueryBookmark] = bookmark
+	} else {
+		delete(jsonQueryMap, jsonQueryBookmark)
+	}
 	//Marshal the updated json query
 	editedQuery, err := json.Marshal(jsonQueryMap)
 	if err != nil {
@@ -442,10 +576,11 @@ type queryScanner struct {
 	cursor    int
 	namespace string
 	results   []couchdb.QueryResult
+	bookmark  string
 }
 
-func newQueryScanner(namespace string, queryResults []couchdb.QueryResult) *queryScanner {
-	return &queryScanner{-1, namespace, queryResults}
+func newQueryScanner(namespace string, queryResults []couchdb.QueryResult, bookmark string) *queryScanner {
+	return &queryScanner{-1, namespace, queryResults, bookmark}
 }
 
 func (scanner *queryScanner) Next() (statedb.QueryResult, error) {
@@ -469,3 +604,10 @@ func (scanner *queryScanner) Next() (statedb.QueryResult, error) {
 func (scanner *queryScanner) Close() {
 	scanner = nil
 }
+
+// GetBookmarkAndClose returns the bookmark for the next page of results
+func (scanner *queryScanner) GetBookmarkAndClose() string {
+	retval := scanner.bookmark
+	scanner.Close()
+	return retval
+}
@@ -118,6 +118,17 @@ func TestIterator(t *testing.T) {
 	commontests.TestIterator(t, env.DBProvider)
 }
 
+func TestPaginatedRangeQuery(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	env.Cleanup("testpaginatedrangequery_")
+	env.Cleanup("testpaginatedrangequery_ns1")
+	env.Cleanup("testpaginatedrangequery_ns2")
+	defer env.Cleanup("testpaginatedrangequery_")
+	defer env.Cleanup("testpaginatedrangequery_ns1")
+	defer env.Cleanup("testpaginatedrangequery_ns2")
+	commontests.TestPaginatedRangeQuery(t, env.DBProvider)
+}
+
 // The following tests are unique to couchdb, they are not used in leveldb
 //  query test
 func TestQuery(t *testing.T) {
@@ -470,3 +481,242 @@ func printCompositeKeys(keys []*statedb.CompositeKey) string {
 	}
 	return strings.Join(compositeKeyString, ",")
 }
+
+func TestValueAndMetadataWrites(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	env.Cleanup("testvalueandmetadata_")
+	env.Cleanup("testvalueandmetadata_ns1")
+	env.Cleanup("testvalueandmetadata_ns2")
+	defer env.Cleanup("testvalueandmetadata_")
+	defer env.Cleanup("testvalueandmetadata_ns1")
+	defer env.Cleanup("testvalueandmetadata_ns2")
+	commontests.TestValueAndMetadataWrites(t, env.DBProvider)
+}
+
+func TestCouchDocConversionWithMetadata(t *testing.T) {
+	for _, kv := range []*keyValue{
+		{"key1", &statedb.VersionedValue{Value: []byte(`{"color":"blue"}`), Metadata: []byte("metadata1"), Version: version.NewHeight(1, 1)}},
+		{"key2", &statedb.VersionedValue{Value: []byte("binary"), Metadata: []byte("metadata2"), Version: version.NewHeight(1, 2)}},
+		{"key3", &statedb.VersionedValue{Value: []byte("binary"), Version: version.NewHeight(1, 3)}},
+	} {
+		doc, err := keyValToCouchDoc(kv, "")
+		testutil.AssertNoError(t, err, "")
+		convertedKV, err := couchDocToKeyValue(doc)
+		testutil.AssertNoError(t, err, "")
+		testutil.AssertEquals(t, convertedKV, kv)
+	}
+
+	// the metadata field is reserved
+	_, err := keyValToCouchDoc(&keyValue{"key1", &statedb.VersionedValue{
+		Value:   []byte(`{"~metadata":"foo"}`),
+		Version: version.NewHeight(1, 1)}}, "")
+	testutil.AssertError(t, err, "The field ~metadata should not be allowed")
+}
+
+func TestValueCacheConsistency(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	env.Cleanup("testvaluecache_")
+	env.Cleanup("testvaluecache_ns")
+	defer env.Cleanup("testvaluecache_")
+	defer env.Cleanup("testvaluecache_ns")
+
+	db, err := env.DBProvider.GetDBHandle("testvaluecache")
+	testutil.AssertNoError(t, err, "")
+	vdb := db.(*VersionedDB)
+
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns", "key1", []byte("value1"), version.NewHeight(1, 1))
+	batch.Put("ns", "key2", []byte(`{"color":"blue", "size":1}`), version.NewHeight(1, 2))
+	testutil.AssertNoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 2)), "")
+
+	// reads populate the cache, including the keys that do not exist
+	for _, key := range []string{"key1", "key2", "key3"} {
+		_, err := db.GetState("ns", key)
+		testutil.AssertNoError(t, err, "")
+		_, found := vdb.valueCache.get("ns", key)
+		testutil.AssertEquals(t, found, true)
+	}
+	vv, err := db.GetState("ns", "key2")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, vv, &statedb.VersionedValue{Value: []byte(`{"color":"blue","size":1}`), Version: version.NewHeight(1, 2)})
+
+	// the cache reflects the committed updates
+	batch = statedb.NewUpdateBatch()
+	batch.Put("ns", "key1", []byte("value1-updated"), version.NewHeight(2, 1))
+	batch.Put("ns", "key2", []byte(`{"color":"red", "size":2}`), version.NewHeight(2, 2))
+	batch.Put("ns", "key3", []byte("value3"), version.NewHeight(2, 3))
+	testutil.AssertNoError(t, db.ApplyUpdates(batch, version.NewHeight(2, 3)), "")
+
+	vv, err = db.GetState("ns", "key1")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, vv, &statedb.VersionedValue{Value: []byte("value1-updated"), Version: version.NewHeight(2, 1)})
+	vv, err = db.GetState("ns", "key2")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, vv, &statedb.VersionedValue{Value: []byte(`{"color":"red","size":2}`), Version: version.NewHeight(2, 2)})
+	vals, err := db.GetStateMultipleKeys("ns", []string{"key3", "key1"})
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, vals, []*statedb.VersionedValue{
+		{Value: []byte("value3"), Version: version.NewHeight(2, 3)},
+		{Value: []byte("value1-updated"), Version: version.NewHeight(2, 1)},
+	})
+
+	batch = statedb.NewUpdateBatch()
+	batch.Delete("ns", "key1", version.NewHeight(3, 1))
+	testutil.AssertNoError(t, db.ApplyUpdates(batch, version.NewHeight(3, 1)), "")
+	vv, err = db.GetState("ns", "key1")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNil(t, vv)
+
+	// the values served by the cache are the ones served by the db
+	uncachedDB, err := newVersionedDB(vdb.couchInstance, "testvaluecache")
+	testutil.AssertNoError(t, err, "")
+	uncachedDB.valueCache = newValueCache(0)
+	keys := []string{"key1", "key2", "key3", "key4"}
+	cachedVals, err := db.GetStateMultipleKeys("ns", keys)
+	testutil.AssertNoError(t, err, "")
+	uncachedVals, err := uncachedDB.GetStateMultipleKeys("ns", keys)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, cachedVals, uncachedVals)
+}
+
+func TestGetStateMultipleKeysBulkRead(t *testing.T) {
+	viper.Set("ledger.state.couchDBConfig.maxBatchUpdateSize", 2)
+	defer viper.Set("ledger.state.couchDBConfig.maxBatchUpdateSize", 1000)
+	viper.Set("ledger.state.couchDBConfig.valueCacheSize", 0)
+	defer viper.Set("ledger.state.couchDBConfig.valueCacheSize", 1000)
+	env := NewTestVDBEnv(t)
+	env.Cleanup("testbulkread_")
+	env.Cleanup("testbulkread_ns")
+	defer env.Cleanup("testbulkread_")
+	defer env.Cleanup("testbulkread_ns")
+
+	db, err := env.DBProvider.GetDBHandle("testbulkread")
+	testutil.AssertNoError(t, err, "")
+
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns", "key1", []byte("value1"), version.NewHeight(1, 1))
+	batch.Put("ns", "key2", []byte(`{"color":"blue"}`), version.NewHeight(1, 2))
+	batch.Put("ns", "key3", []byte("value3"), version.NewHeight(1, 3))
+	batch.Put("ns", "key4", []byte("value4"), version.NewHeight(1, 4))
+	testutil.AssertNoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 4)), "")
+	batch = statedb.NewUpdateBatch()
+	batch.Delete("ns", "key4", version.NewHeight(2, 1))
+	testutil.AssertNoError(t, db.ApplyUpdates(batch, version.NewHeight(2, 1)), "")
+
+	keys := []string{"key3", "key5", "key1", "key4", "key2", "key1"}
+	vals, err := db.GetStateMultipleKeys("ns", keys)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, len(vals), len(keys))
+	for i, key := range keys {
+		vv, err := db.GetState("ns", key)
+		testutil.AssertNoError(t, err, "")
+		testutil.AssertEquals(t, vals[i], vv)
+	}
+	testutil.AssertNil(t, vals[1])
+	testutil.AssertNil(t, vals[3])
+}
+
+func TestPaginationPageSizeLimitedToQueryLimit(t *testing.T) {
+	viper.Set("ledger.state.couchDBConfig.queryLimit", 2)
+	defer viper.Set("ledger.state.couchDBConfig.queryLimit", 10000)
+	env := NewTestVDBEnv(t)
+	env.Cleanup("testpagesizelimit_")
+	env.Cleanup("testpagesizelimit_ns")
+	defer env.Cleanup("testpagesizelimit_")
+	defer env.Cleanup("testpagesizelimit_ns")
+
+	db, err := env.DBProvider.GetDBHandle("testpagesizelimit")
+	testutil.AssertNoError(t, err, "")
+
+	batch := statedb.NewUpdateBatch()
+	for i := 1; i <= 4; i++ {
+		batch.Put("ns", fmt.Sprintf("key%d", i), []byte(fmt.Sprintf(`{"asset":"asset%d","owner":"tom"}`, i)), version.NewHeight(1, uint64(i)))
+	}
+	testutil.AssertNoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 4)), "")
+
+	// a pageSize larger than the querylimit returns a page of querylimit results and a bookmark
+	itr, err := db.GetStateRangeScanIteratorWithPagination("ns", "", "", 10, "")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, countResults(t, itr), 2)
+	testutil.AssertEquals(t, itr.GetBookmarkAndClose(), "key3")
+
+	queryItr, err := db.ExecuteQueryWithPagination("ns", `{"selector":{"owner":"tom"}}`, 10, "")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, countResults(t, queryItr), 2)
+	testutil.AssertNotEquals(t, queryItr.GetBookmarkAndClose(), "")
+}
+
+func countResults(t *testing.T, itr statedb.ResultsIterator) int {
+	count := 0
+	for {
+		queryResult, err := itr.Next()
+		testutil.AssertNoError(t, err, "")
+		if queryResult == nil {
+			return count
+		}
+		count++
+	}
+}
+
+// benchmarkSimulationReads measures the reads of a simulation that reads `keysPerTx` keys out
+// of `numKeys` keys, either one by one or with a single GetStateMultipleKeys
+func benchmarkSimulationReads(b *testing.B, valueCacheSize int, bulkRead bool) {
+	const numKeys = 100
+	const keysPerTx = 10
+	viper.Set("ledger.state.couchDBConfig.valueCacheSize", valueCacheSize)
+	defer viper.Set("ledger.state.couchDBConfig.valueCacheSize", 1000)
+	flogging.SetModuleLevel("statecouchdb", "error")
+	defer flogging.SetModuleLevel("statecouchdb", "debug")
+	env := NewTestVDBEnv(b)
+	env.Cleanup("benchsimulation_")
+	env.Cleanup("benchsimulation_ns")
+	defer env.Cleanup("benchsimulation_")
+	defer env.Cleanup("benchsimulation_ns")
+
+	db, err := env.DBProvider.GetDBHandle("benchsimulation")
+	if err != nil {
+		b.Fatal(err)
+	}
+	batch := statedb.NewUpdateBatch()
+	for i := 0; i < numKeys; i++ {
+		batch.Put("ns", fmt.Sprintf("key%d", i), []byte(fmt.Sprintf(`{"asset":"asset%d","owner":"owner%d"}`, i, i)), version.NewHeight(1, uint64(i)))
+	}
+	if err := db.ApplyUpdates(batch, version.NewHeight(1, numKeys)); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		keys := make([]string, keysPerTx)
+		for i := range keys {
+			keys[i] = fmt.Sprintf("key%d", (n*keysPerTx+i)%numKeys)
+		}
+		if bulkRead {
+			if _, err := db.GetStateMultipleKeys("ns", keys); err != nil {
+				b.Fatal(err)
+			}
+			continue
+		}
+		for _, key := range keys {
+			if _, err := db.GetState("ns", key); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkSimulationReadsNoCache(b *testing.B) {
+	benchmarkSimulationReads(b, 0, false)
+}
+
+func BenchmarkSimulationReadsWithCache(b *testing.B) {
+	benchmarkSimulationReads(b, 1000, false)
+}
+
+func BenchmarkSimulationBulkReadsNoCache(b *testing.B) {
+	benchmarkSimulationReads(b, 0, true)
+}
+
+func BenchmarkSimulationBulkReadsWithCache(b *testing.B) {
+	benchmarkSimulationReads(b, 1000, true)
+}
@@ -0,0 +1,178 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statecouchdb
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+
+	"github.com/golang/groupcache/lru"
+)
+
+// valueCache is an in-memory LRU cache of the committed values, with one cache per namespace.
+// It serves the reads of the simulation phase so that repeated reads of the same keys do not
+// require a round-trip to CouchDB. A key that does not exist in the db is cached as a nil value.
+//
+// The cache is kept consistent with the db by `ApplyUpdates`. The keys of a block are invalidated
+// before the block is written to the db and, where possible, the committed values are set after the
+// block is written. A value read from the db is added to the cache only if no block touched the
+// namespace since the read started, which is detected by a per-namespace generation that every
+// invalidation and update increments. This avoids that a value read before a commit overwrites the
+// value set by the commit.
+type valueCache struct {
+	size        int
+	caches      map[string]*lru.Cache
+	generations map[string]uint64
+	mux         sync.Mutex
+}
+
+// newValueCache constructs a valueCache that holds at most `size` values per namespace.
+// A size of 0 disables the cache.
+func newValueCache(size int) *valueCache {
+	return &valueCache{
+		size:        size,
+		caches:      make(map[string]*lru.Cache),
+		generations: make(map[string]uint64),
+	}
+}
+
+func (c *valueCache) enabled() bool {
+	return c.size > 0
+}
+
+// get returns a copy of the cached value of the given key and whether the key was found in the cache.
+// A nil value with found equal to true means that the key does not exist in the db
+func (c *valueCache) get(ns, key string) (*statedb.VersionedValue, bool) {
+	if !c.enabled() {
+		return nil, false
+	}
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	nsCache, ok := c.caches[ns]
+	if !ok {
+		return nil, false
+	}
+	cached, ok := nsCache.Get(key)
+	if !ok {
+		return nil, false
+	}
+	return copyVersionedValue(cached.(*statedb.VersionedValue)), true
+}
+
+// generation returns the current generation of the namespace. It is expected to be captured
+// before reading from the db and passed to `add` along with the value read
+func (c *valueCache) generation(ns string) uint64 {
+	if !c.enabled() {
+		return 0
+	}
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.generations[ns]
+}
+
+// add adds a value read from the db to the cache, unless the namespace has been updated since
+// the generation `gen` was captured
+func (c *valueCache) add(ns, key string, vv *statedb.VersionedValue, gen uint64) {
+	if !c.enabled() {
+		return
+	}
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if c.generations[ns] != gen {
+		logger.Debugf("Not caching the value of %s~%s as the namespace was updated during the read", ns, key)
+		return
+	}
+	c.nsCache(ns).Add(key, copyVersionedValue(vv))
+}
+
+// invalidate removes the keys of the given updates from the cache. It is invoked before the
+// updates are written to the db
+func (c *valueCache) invalidate(updates *statedb.UpdateBatch) {
+	if !c.enabled() {
+		return
+	}
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	for _, ns := range updates.GetUpdatedNamespaces() {
+		c.generations[ns]++
+		nsCache, ok := c.caches[ns]
+		if !ok {
+			continue
+		}
+		for key := range updates.GetUpdates(ns) {
+			nsCache.Remove(key)
+		}
+	}
+}
+
+// update sets the values of the given updates in the cache. It is invoked after the updates
+// have been written to the db. A delete is cached as a nil value. The keys whose value is not
+// cached remain invalidated, see `committedValue`
+func (c *valueCache) update(updates *statedb.UpdateBatch) {
+	if !c.enabled() {
+		return
+	}
+	// select the values outside of the lock, as this requires parsing them
+	namespaces := updates.GetUpdatedNamespaces()
+	nsValues := make(map[string]map[string]*statedb.VersionedValue)
+	for _, ns := range namespaces {
+		nsValues[ns] = make(map[string]*statedb.VersionedValue)
+		for key, vv := range updates.GetUpdates(ns) {
+			committedVV, ok := committedValue(vv)
+			if !ok {
+				continue
+			}
+			nsValues[ns][key] = committedVV
+		}
+	}
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	for _, ns := range namespaces {
+		c.generations[ns]++
+		nsCache := c.nsCache(ns)
+		for key, vv := range nsValues[ns] {
+			nsCache.Add(key, vv)
+		}
+	}
+}
+
+// committedValue returns the value to cache for the given committed value. ok is false if the
+// value is to be read from the db instead. This is the case for JSON values, as these are not
+// stored verbatim in CouchDB; caching them verbatim would hand to the simulation a value that
+// differs from the one read by the peers that do not have it cached. Binary values are stored
+// verbatim as attachments, except for empty values which are left to the db as well
+func committedValue(vv *statedb.VersionedValue) (committedVV *statedb.VersionedValue, ok bool) {
+	if vv.Value == nil {
+		return nil, true
+	}
+	if len(vv.Value) == 0 || json.Unmarshal(vv.Value, &jsonValue{}) == nil {
+		return nil, false
+	}
+	return copyVersionedValue(vv), true
+}
+
+// nsCache returns the cache of the given namespace, creating it if needed. The caller is expected
+// to hold the lock
+func (c *valueCache) nsCache(ns string) *lru.Cache {
+	nsCache, ok := c.caches[ns]
+	if !ok {
+		nsCache = lru.New(c.size)
+		c.caches[ns] = nsCache
+	}
+	return nsCache
+}
+
+// copyVersionedValue returns a shallow copy of vv so that the cached entries cannot be modified
+// through the values handed out to the callers
+func copyVersionedValue(vv *statedb.VersionedValue) *statedb.VersionedValue {
+	if vv == nil {
+		return nil
+	}
+	vvCopy := *vv
+	return &vvCopy
+}
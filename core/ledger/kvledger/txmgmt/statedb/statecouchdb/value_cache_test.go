@@ -0,0 +1,119 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statecouchdb
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+)
+
+func TestValueCacheDisabled(t *testing.T) {
+	cache := newValueCache(0)
+	cache.add("ns", "key1", &statedb.VersionedValue{Value: []byte("value1"), Version: version.NewHeight(1, 1)}, cache.generation("ns"))
+	_, found := cache.get("ns", "key1")
+	testutil.AssertEquals(t, found, false)
+
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns", "key1", []byte("value1"), version.NewHeight(1, 1))
+	cache.invalidate(batch)
+	cache.update(batch)
+	_, found = cache.get("ns", "key1")
+	testutil.AssertEquals(t, found, false)
+}
+
+func TestValueCacheAddAndGet(t *testing.T) {
+	cache := newValueCache(2)
+	vv1 := &statedb.VersionedValue{Value: []byte("value1"), Metadata: []byte("metadata1"), Version: version.NewHeight(1, 1)}
+	cache.add("ns1", "key1", vv1, cache.generation("ns1"))
+	cache.add("ns1", "key2", nil, cache.generation("ns1"))
+
+	vv, found := cache.get("ns1", "key1")
+	testutil.AssertEquals(t, found, true)
+	testutil.AssertEquals(t, vv, vv1)
+	// the caller gets a copy of the cached entry
+	vv.Version = version.NewHeight(5, 5)
+	vv, _ = cache.get("ns1", "key1")
+	testutil.AssertEquals(t, vv.Version, version.NewHeight(1, 1))
+
+	// a key that does not exist in the db is cached as nil
+	vv, found = cache.get("ns1", "key2")
+	testutil.AssertEquals(t, found, true)
+	testutil.AssertNil(t, vv)
+
+	// the caches of the namespaces are independent
+	_, found = cache.get("ns2", "key1")
+	testutil.AssertEquals(t, found, false)
+
+	// the least recently used key is evicted
+	cache.get("ns1", "key1")
+	cache.add("ns1", "key3", vv1, cache.generation("ns1"))
+	_, found = cache.get("ns1", "key2")
+	testutil.AssertEquals(t, found, false)
+	_, found = cache.get("ns1", "key1")
+	testutil.AssertEquals(t, found, true)
+	_, found = cache.get("ns1", "key3")
+	testutil.AssertEquals(t, found, true)
+}
+
+func TestValueCacheUpdates(t *testing.T) {
+	cache := newValueCache(10)
+	for _, key := range []string{"key1", "key2", "key3", "key4", "key5"} {
+		cache.add("ns1", key, &statedb.VersionedValue{Value: []byte("old"), Version: version.NewHeight(1, 1)}, cache.generation("ns1"))
+	}
+	cache.add("ns2", "key1", &statedb.VersionedValue{Value: []byte("old"), Version: version.NewHeight(1, 1)}, cache.generation("ns2"))
+
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte("binary"), version.NewHeight(2, 1))
+	batch.Put("ns1", "key2", []byte(`{"color":"blue"}`), version.NewHeight(2, 2))
+	batch.Delete("ns1", "key3", version.NewHeight(2, 3))
+	batch.Put("ns1", "key4", []byte{}, version.NewHeight(2, 4))
+	batch.Put("ns1", "key6", []byte("new"), version.NewHeight(2, 5))
+
+	// a read that starts before the commit cannot populate the cache once the commit started
+	staleReadGeneration := cache.generation("ns1")
+	cache.invalidate(batch)
+	for _, key := range []string{"key1", "key2", "key3", "key4"} {
+		_, found := cache.get("ns1", key)
+		testutil.AssertEquals(t, found, false)
+	}
+	cache.add("ns1", "key1", &statedb.VersionedValue{Value: []byte("old"), Version: version.NewHeight(1, 1)}, staleReadGeneration)
+	_, found := cache.get("ns1", "key1")
+	testutil.AssertEquals(t, found, false)
+
+	// a read that starts during the commit cannot populate the cache once the commit completed
+	duringCommitGeneration := cache.generation("ns1")
+	cache.update(batch)
+	cache.add("ns1", "key2", &statedb.VersionedValue{Value: []byte("old"), Version: version.NewHeight(1, 1)}, duringCommitGeneration)
+
+	vv, found := cache.get("ns1", "key1")
+	testutil.AssertEquals(t, found, true)
+	testutil.AssertEquals(t, vv, &statedb.VersionedValue{Value: []byte("binary"), Version: version.NewHeight(2, 1)})
+	vv, found = cache.get("ns1", "key6")
+	testutil.AssertEquals(t, found, true)
+	testutil.AssertEquals(t, vv, &statedb.VersionedValue{Value: []byte("new"), Version: version.NewHeight(2, 5)})
+	// deletes are cached as nil
+	vv, found = cache.get("ns1", "key3")
+	testutil.AssertEquals(t, found, true)
+	testutil.AssertNil(t, vv)
+	// JSON and empty values are left to the db
+	_, found = cache.get("ns1", "key2")
+	testutil.AssertEquals(t, found, false)
+	_, found = cache.get("ns1", "key4")
+	testutil.AssertEquals(t, found, false)
+	// the keys that are not updated stay cached
+	_, found = cache.get("ns1", "key5")
+	testutil.AssertEquals(t, found, true)
+	_, found = cache.get("ns2", "key1")
+	testutil.AssertEquals(t, found, true)
+
+	// a read that starts after the commit populates the cache
+	cache.add("ns1", "key2", &statedb.VersionedValue{Value: []byte(`{"color":"blue"}`), Version: version.NewHeight(2, 2)}, cache.generation("ns1"))
+	_, found = cache.get("ns1", "key2")
+	testutil.AssertEquals(t, found, true)
+}
@@ -16,6 +16,8 @@ import (
 type VersionedDBProvider interface {
 	// GetDBHandle returns a handle to a VersionedDB
 	GetDBHandle(id string) (VersionedDB, error)
+	// Drop drops all the data of the VersionedDB with the given id
+	Drop(id string) error
 	// Close closes all the VersionedDB instances and releases any resources held by VersionedDBProvider
 	Close()
 }
@@ -35,9 +37,24 @@ type VersionedDB interface {
 	GetStateRangeScanIterator(namespace string, startKey string, endKey string) (ResultsIterator, error)
 	// ExecuteQuery executes the given query and returns an iterator that contains results of type *VersionedKV.
 	ExecuteQuery(namespace, query string) (ResultsIterator, error)
+	// GetStateRangeScanIteratorWithPagination returns an iterator that contains a page of at most pageSize
+	// key-values between given key ranges. A non-empty bookmark, as returned by the iterator of the
+	// previous page, is the key from which the scan resumes.
+	// The returned QueryResultsIterator contains results of type *VersionedKV
+	GetStateRangeScanIteratorWithPagination(namespace string, startKey string, endKey string, pageSize int32, bookmark string) (QueryResultsIterator, error)
+	// ExecuteQueryWithPagination executes the given query and returns an iterator that contains a page of
+	// at most pageSize results of type *VersionedKV. A non-empty bookmark, as returned by the iterator
+	// of the previous page, identifies the position from which the query resumes.
+	ExecuteQueryWithPagination(namespace, query string, pageSize int32, bookmark string) (QueryResultsIterator, error)
+	// GetFullScanIterator returns an iterator that contains all the key-values of the db, across the namespaces,
+	// ordered by namespace and then by key. The key-values of the namespaces for which skipNamespace returns true
+	// are not included. The returned ResultsIterator contains results of type *VersionedKV
+	GetFullScanIterator(skipNamespace func(namespace string) bool) (ResultsIterator, error)
 	// ApplyUpdates applies the batch to the underlying db.
 	// height is the height of the highest transaction in the Batch that
-	// a state db implementation is expected to ues as a save point
+	// a state db implementation is expected to ues as a save point.
+	// A nil height denotes that the batch does not correspond to a new block (e.g., the batch
+	// carries the pvt data of already committed blocks) and hence the save point is not updated
 	ApplyUpdates(batch *UpdateBatch, height *version.Height) error
 	// GetLatestSavePoint returns the height of the highest transaction upto which
 	// the state db is consistent
@@ -70,8 +87,9 @@ type CompositeKey struct {
 
 // VersionedValue encloses value and corresponding version
 type VersionedValue struct {
-	Value   []byte
-	Version *version.Height
+	Value    []byte
+	Metadata []byte
+	Version  *version.Height
 }
 
 // VersionedKV encloses key and corresponding VersionedValue
@@ -86,6 +104,14 @@ type ResultsIterator interface {
 	Close()
 }
 
+// QueryResultsIterator adds GetBookmarkAndClose method to ResultsIterator
+type QueryResultsIterator interface {
+	ResultsIterator
+	// GetBookmarkAndClose returns the bookmark for retrieving the next page of results and closes the iterator.
+	// An empty bookmark is returned if no more results are available
+	GetBookmarkAndClose() string
+}
+
 // QueryResult - a general interface for supporting different types of query results. Actual types differ for different queries
 type QueryResult interface{}
 
@@ -120,17 +146,22 @@ func (batch *UpdateBatch) Get(ns string, key string) *VersionedValue {
 	return vv
 }
 
-// Put adds a VersionedKV
+// Put adds a key with value only. The metadata is assumed to be nil
 func (batch *UpdateBatch) Put(ns string, key string, value []byte, version *version.Height) {
+	batch.PutValAndMetadata(ns, key, value, nil, version)
+}
+
+// PutValAndMetadata adds a key with value and metadata
+func (batch *UpdateBatch) PutValAndMetadata(ns string, key string, value []byte, metadata []byte, version *version.Height) {
 	if value == nil {
 		panic("Nil value not allowed")
 	}
-	batch.Update(ns, key, &VersionedValue{value, version})
+	batch.Update(ns, key, &VersionedValue{value, metadata, version})
 }
 
 // Delete deletes a Key and associated value
 func (batch *UpdateBatch) Delete(ns string, key string, version *version.Height) {
-	batch.Update(ns, key, &VersionedValue{nil, version})
+	batch.Update(ns, key, &VersionedValue{nil, nil, version})
 }
 
 // Exists checks whether the given key exists in the batch
@@ -225,7 +256,7 @@ func (itr *nsIterator) Next() (QueryResult, error) {
 	key := itr.sortedKeys[itr.nextIndex]
 	vv := itr.nsUpdates.m[key]
 	itr.nextIndex++
-	return &VersionedKV{CompositeKey{itr.ns, key}, VersionedValue{vv.Value, vv.Version}}, nil
+	return &VersionedKV{CompositeKey{itr.ns, key}, VersionedValue{vv.Value, vv.Metadata, vv.Version}}, nil
 }
 
 // Close implements the method from QueryResult interface
@@ -41,6 +41,11 @@ func (provider *VersionedDBProvider) GetDBHandle(dbName string) (statedb.Version
 	return newVersionedDB(provider.dbProvider.GetDBHandle(dbName), dbName), nil
 }
 
+// Drop deletes all the data of the named database
+func (provider *VersionedDBProvider) Drop(dbName string) error {
+	return provider.dbProvider.GetDBHandle(dbName).DeleteAll()
+}
+
 // Close closes the underlying db
 func (provider *VersionedDBProvider) Close() {
 	provider.dbProvider.Close()
@@ -89,8 +94,7 @@ func (vdb *versionedDB) GetState(namespace string, key string) (*statedb.Version
 	if dbVal == nil {
 		return nil, nil
 	}
-	val, ver := DecodeValue(dbVal)
-	return &statedb.VersionedValue{Value: val, Version: ver}, nil
+	return decodeVersionedValue(dbVal), nil
 }
 
 // GetVersion implements method in VersionedDB interface
@@ -122,13 +126,29 @@ func (vdb *versionedDB) GetStateMultipleKeys(namespace string, keys []string) ([
 // startKey is inclusive
 // endKey is exclusive
 func (vdb *versionedDB) GetStateRangeScanIterator(namespace string, startKey string, endKey string) (statedb.ResultsIterator, error) {
+	return vdb.GetStateRangeScanIteratorWithPagination(namespace, startKey, endKey, 0, "")
+}
+
+// GetStateRangeScanIteratorWithPagination implements method in VersionedDB interface
+// The bookmark is the key of the first result of the next page. A pageSize of 0 does not limit the results
+func (vdb *versionedDB) GetStateRangeScanIteratorWithPagination(namespace string, startKey string, endKey string,
+	pageSize int32, bookmark string) (statedb.QueryResultsIterator, error) {
+	if pageSize < 0 {
+		return nil, errors.New("pageSize must not be negative")
+	}
+	if bookmark != "" {
+		if bookmark < startKey || (endKey != "" && bookmark >= endKey) {
+			return nil, errors.New("bookmark is outside the requested key range")
+		}
+		startKey = bookmark
+	}
 	compositeStartKey := constructCompositeKey(namespace, startKey)
 	compositeEndKey := constructCompositeKey(namespace, endKey)
 	if endKey == "" {
 		compositeEndKey[len(compositeEndKey)-1] = lastKeyIndicator
 	}
 	dbItr := vdb.db.GetIterator(compositeStartKey, compositeEndKey)
-	return newKVScanner(namespace, dbItr), nil
+	return newKVScanner(namespace, dbItr, pageSize), nil
 }
 
 // ExecuteQuery implements method in VersionedDB interface
@@ -136,6 +156,16 @@ func (vdb *versionedDB) ExecuteQuery(namespace, query string) (statedb.ResultsIt
 	return nil, errors.New("ExecuteQuery not supported for leveldb")
 }
 
+// ExecuteQueryWithPagination implements method in VersionedDB interface
+func (vdb *versionedDB) ExecuteQueryWithPagination(namespace, query string, pageSize int32, bookmark string) (statedb.QueryResultsIterator, error) {
+	return nil, errors.New("ExecuteQueryWithPagination not supported for leveldb")
+}
+
+// GetFullScanIterator implements method in VersionedDB interface
+func (vdb *versionedDB) GetFullScanIterator(skipNamespace func(namespace string) bool) (statedb.ResultsIterator, error) {
+	return &fullScanner{vdb.db.GetIterator(nil, nil), skipNamespace}, nil
+}
+
 // ApplyUpdates implements method in VersionedDB interface
 func (vdb *versionedDB) ApplyUpdates(batch *statedb.UpdateBatch, height *version.Height) error {
 	dbBatch := leveldbhelper.NewUpdateBatch()
@@ -149,11 +179,14 @@ func (vdb *versionedDB) ApplyUpdates(batch *statedb.UpdateBatch, height *version
 			if vv.Value == nil {
 				dbBatch.Delete(compositeKey)
 			} else {
-				dbBatch.Put(compositeKey, EncodeValue(vv.Value, vv.Version))
+				dbBatch.Put(compositeKey, encodeVersionedValue(vv))
 			}
 		}
 	}
-	dbBatch.Put(savePointKey, height.ToBytes())
+	// a nil height denotes the updates that do not correspond to a new block, e.g., the pvt data of old blocks
+	if height != nil {
+		dbBatch.Put(savePointKey, height.ToBytes())
+	}
 	// Setting snyc to true as a precaution, false may be an ok optimization after further testing.
 	if err := vdb.db.WriteBatch(dbBatch, true); err != nil {
 		return err
@@ -184,29 +217,79 @@ func splitCompositeKey(compositeKey []byte) (string, string) {
 }
 
 type kvScanner struct {
-	namespace string
-	dbItr     iterator.Iterator
+	namespace            string
+	dbItr                iterator.Iterator
+	requestedLimit       int32
+	totalRecordsReturned int32
 }
 
-func newKVScanner(namespace string, dbItr iterator.Iterator) *kvScanner {
-	return &kvScanner{namespace, dbItr}
+func newKVScanner(namespace string, dbItr iterator.Iterator, requestedLimit int32) *kvScanner {
+	return &kvScanner{namespace, dbItr, requestedLimit, 0}
 }
 
 func (scanner *kvScanner) Next() (statedb.QueryResult, error) {
+	if scanner.requestedLimit > 0 && scanner.totalRecordsReturned >= scanner.requestedLimit {
+		return nil, nil
+	}
 	if !scanner.dbItr.Next() {
 		return nil, nil
 	}
+	scanner.totalRecordsReturned++
 	dbKey := scanner.dbItr.Key()
 	dbVal := scanner.dbItr.Value()
 	dbValCopy := make([]byte, len(dbVal))
 	copy(dbValCopy, dbVal)
 	_, key := splitCompositeKey(dbKey)
-	value, version := DecodeValue(dbValCopy)
+	vv := decodeVersionedValue(dbValCopy)
 	return &statedb.VersionedKV{
 		CompositeKey:   statedb.CompositeKey{Namespace: scanner.namespace, Key: key},
-		VersionedValue: statedb.VersionedValue{Value: value, Version: version}}, nil
+		VersionedValue: *vv}, nil
 }
 
 func (scanner *kvScanner) Close() {
 	scanner.dbItr.Release()
 }
+
+// GetBookmarkAndClose returns the key that follows the last returned result
+// or an empty string if the range has been exhausted
+func (scanner *kvScanner) GetBookmarkAndClose() string {
+	retval := ""
+	if scanner.dbItr.Next() {
+		_, key := splitCompositeKey(scanner.dbItr.Key())
+		retval = key
+	}
+	scanner.Close()
+	return retval
+}
+
+// fullScanner iterates over the key-values of all the namespaces. As the namespace and the key are
+// separated by a zero byte in the composite key, the db order is the order of namespaces and then keys
+type fullScanner struct {
+	dbItr         iterator.Iterator
+	skipNamespace func(namespace string) bool
+}
+
+func (scanner *fullScanner) Next() (statedb.QueryResult, error) {
+	for scanner.dbItr.Next() {
+		dbKey := scanner.dbItr.Key()
+		if bytes.Equal(dbKey, savePointKey) {
+			continue
+		}
+		ns, key := splitCompositeKey(dbKey)
+		if scanner.skipNamespace != nil && scanner.skipNamespace(ns) {
+			continue
+		}
+		dbVal := scanner.dbItr.Value()
+		dbValCopy := make([]byte, len(dbVal))
+		copy(dbValCopy, dbVal)
+		vv := decodeVersionedValue(dbValCopy)
+		return &statedb.VersionedKV{
+			CompositeKey:   statedb.CompositeKey{Namespace: ns, Key: key},
+			VersionedValue: *vv}, nil
+	}
+	return nil, scanner.dbItr.Error()
+}
+
+func (scanner *fullScanner) Close() {
+	scanner.dbItr.Release()
+}
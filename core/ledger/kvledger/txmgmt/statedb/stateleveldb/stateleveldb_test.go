@@ -56,6 +56,12 @@ func TestIterator(t *testing.T) {
 	commontests.TestIterator(t, env.DBProvider)
 }
 
+func TestPaginatedRangeQuery(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestPaginatedRangeQuery(t, env.DBProvider)
+}
+
 func TestEncodeDecodeValueAndVersion(t *testing.T) {
 	testValueAndVersionEncoding(t, []byte("value1"), version.NewHeight(1, 2))
 	testValueAndVersionEncoding(t, []byte{}, version.NewHeight(50, 50))
@@ -130,3 +136,47 @@ func TestUtilityFunctions(t *testing.T) {
 	// ValidateKeyValue should return nil for a valid key and value
 	testutil.AssertNoError(t, db.ValidateKeyValue("testKey", []byte("testValue")), "leveldb should accept all key-values")
 }
+
+func TestValueAndMetadataWrites(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestValueAndMetadataWrites(t, env.DBProvider)
+}
+
+func TestFullScanIterator(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+
+	db, err := env.DBProvider.GetDBHandle("testfullscaniterator")
+	testutil.AssertNoError(t, err, "")
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns2", "key1", []byte("value1"), version.NewHeight(1, 1))
+	batch.PutValAndMetadata("ns1", "key2", []byte("value2"), []byte("metadata"), version.NewHeight(1, 2))
+	batch.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 3))
+	batch.Put("ns1$$pcoll", "key1", []byte("pvtvalue"), version.NewHeight(1, 4))
+	batch.Put("", "key1", []byte("value1"), version.NewHeight(1, 5))
+	testutil.AssertNoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 5)), "")
+
+	itr, err := db.GetFullScanIterator(func(ns string) bool { return ns == "ns1$$pcoll" })
+	testutil.AssertNoError(t, err, "")
+	defer itr.Close()
+	var results []*statedb.VersionedKV
+	for {
+		res, err := itr.Next()
+		testutil.AssertNoError(t, err, "")
+		if res == nil {
+			break
+		}
+		results = append(results, res.(*statedb.VersionedKV))
+	}
+	testutil.AssertEquals(t, results, []*statedb.VersionedKV{
+		{CompositeKey: statedb.CompositeKey{Namespace: "", Key: "key1"},
+			VersionedValue: statedb.VersionedValue{Value: []byte("value1"), Version: version.NewHeight(1, 5)}},
+		{CompositeKey: statedb.CompositeKey{Namespace: "ns1", Key: "key1"},
+			VersionedValue: statedb.VersionedValue{Value: []byte("value1"), Version: version.NewHeight(1, 3)}},
+		{CompositeKey: statedb.CompositeKey{Namespace: "ns1", Key: "key2"},
+			VersionedValue: statedb.VersionedValue{Value: []byte("value2"), Metadata: []byte("metadata"), Version: version.NewHeight(1, 2)}},
+		{CompositeKey: statedb.CompositeKey{Namespace: "ns2", Key: "key1"},
+			VersionedValue: statedb.VersionedValue{Value: []byte("value1"), Version: version.NewHeight(1, 1)}},
+	})
+}
@@ -16,7 +16,16 @@ limitations under the License.
 
 package stateleveldb
 
-import "github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+)
+
+// metadataMarker is the first byte of a value that is stored along with its metadata.
+// A value without metadata starts with the order-preserving encoding of the block number,
+// whose first byte never exceeds 8, and hence the two encodings can be told apart
+const metadataMarker = byte(0xff)
 
 //EncodeValue appends the value to the version, allows storage of version and value in binary form
 func EncodeValue(value []byte, version *version.Height) []byte {
@@ -33,3 +42,32 @@ func DecodeValue(encodedValue []byte) ([]byte, *version.Height) {
 	value := encodedValue[n:]
 	return value, height
 }
+
+// encodeVersionedValue encodes the version, the value and the metadata in binary form.
+// When the metadata is nil, the encoding is the same as the one produced by EncodeValue
+func encodeVersionedValue(vv *statedb.VersionedValue) []byte {
+	if vv.Metadata == nil {
+		return EncodeValue(vv.Value, vv.Version)
+	}
+	encodedValue := append([]byte{metadataMarker}, vv.Version.ToBytes()...)
+	encodedValue = append(encodedValue, proto.EncodeVarint(uint64(len(vv.Metadata)))...)
+	encodedValue = append(encodedValue, vv.Metadata...)
+	return append(encodedValue, vv.Value...)
+}
+
+// decodeVersionedValue decodes a value encoded by encodeVersionedValue
+func decodeVersionedValue(encodedValue []byte) *statedb.VersionedValue {
+	if len(encodedValue) == 0 || encodedValue[0] != metadataMarker {
+		value, height := DecodeValue(encodedValue)
+		return &statedb.VersionedValue{Value: value, Version: height}
+	}
+	height, n := version.NewHeightFromBytes(encodedValue[1:])
+	remaining := encodedValue[1+n:]
+	metadataLen, n := proto.DecodeVarint(remaining)
+	remaining = remaining[n:]
+	return &statedb.VersionedValue{
+		Value:    remaining[metadataLen:],
+		Metadata: remaining[:metadataLen],
+		Version:  height,
+	}
+}
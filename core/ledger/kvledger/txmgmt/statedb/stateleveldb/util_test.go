@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
 )
 
@@ -52,3 +53,19 @@ func TestEncodeDecodeJSON(t *testing.T) {
 	testutil.AssertEquals(t, decodedVersion, version2)
 
 }
+
+// TestEncodeDecodeVersionedValue tests encoding and decoding a value along with its metadata
+func TestEncodeDecodeVersionedValue(t *testing.T) {
+	for _, vv := range []*statedb.VersionedValue{
+		{Value: []byte("value1"), Version: version.NewHeight(1, 1)},
+		{Value: []byte("value1"), Metadata: []byte("metadata1"), Version: version.NewHeight(1, 1)},
+		{Value: []byte{}, Metadata: []byte("metadata1"), Version: version.NewHeight(0, 0)},
+		{Value: []byte("value1"), Metadata: []byte{}, Version: version.NewHeight(1000, 1)},
+	} {
+		testutil.AssertEquals(t, decodeVersionedValue(encodeVersionedValue(vv)), vv)
+	}
+
+	// values without metadata are encoded as before
+	vv := &statedb.VersionedValue{Value: []byte("value1"), Version: version.NewHeight(5, 6)}
+	testutil.AssertEquals(t, encodeVersionedValue(vv), EncodeValue(vv.Value, vv.Version))
+}
@@ -23,8 +23,10 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/txmgr"
 
 	commonledger "github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/storageutil"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/core/ledger/util"
@@ -55,6 +57,25 @@ func (h *queryHelper) getState(ns string, key string) ([]byte, error) {
 	return val, nil
 }
 
+func (h *queryHelper) getStateMetadata(ns string, key string) (map[string][]byte, error) {
+	if err := h.checkDone(); err != nil {
+		return nil, err
+	}
+	versionedValue, err := h.txmgr.db.GetState(ns, key)
+	if err != nil {
+		return nil, err
+	}
+	var metadataBytes []byte
+	var ver *version.Height
+	if versionedValue != nil {
+		metadataBytes, ver = versionedValue.Metadata, versionedValue.Version
+	}
+	if h.rwsetBuilder != nil {
+		h.rwsetBuilder.AddToReadSet(ns, key, ver)
+	}
+	return storageutil.DeserializeMetadata(metadataBytes)
+}
+
 func (h *queryHelper) getStateMultipleKeys(namespace string, keys []string) ([][]byte, error) {
 	if err := h.checkDone(); err != nil {
 		return nil, err
@@ -78,7 +99,21 @@ func (h *queryHelper) getStateRangeScanIterator(namespace string, startKey strin
 	if err := h.checkDone(); err != nil {
 		return nil, err
 	}
-	itr, err := newResultsItr(namespace, startKey, endKey, h.txmgr.db, h.rwsetBuilder,
+	itr, err := newResultsItr(namespace, startKey, endKey, 0, "", h.txmgr.db, h.rwsetBuilder,
+		ledgerconfig.IsQueryReadsHashingEnabled(), ledgerconfig.GetMaxDegreeQueryReadsHashing())
+	if err != nil {
+		return nil, err
+	}
+	h.itrs = append(h.itrs, itr)
+	return itr, nil
+}
+
+func (h *queryHelper) getStateRangeScanIteratorWithPagination(namespace string, startKey string, endKey string,
+	pageSize int32, bookmark string) (ledger.QueryResultsIterator, error) {
+	if err := h.checkDone(); err != nil {
+		return nil, err
+	}
+	itr, err := newResultsItr(namespace, startKey, endKey, pageSize, bookmark, h.txmgr.db, h.rwsetBuilder,
 		ledgerconfig.IsQueryReadsHashingEnabled(), ledgerconfig.GetMaxDegreeQueryReadsHashing())
 	if err != nil {
 		return nil, err
@@ -98,6 +133,17 @@ func (h *queryHelper) executeQuery(namespace, query string) (commonledger.Result
 	return &queryResultsItr{DBItr: dbItr, RWSetBuilder: h.rwsetBuilder}, nil
 }
 
+func (h *queryHelper) executeQueryWithPagination(namespace, query string, pageSize int32, bookmark string) (ledger.QueryResultsIterator, error) {
+	if err := h.checkDone(); err != nil {
+		return nil, err
+	}
+	dbItr, err := h.txmgr.db.ExecuteQueryWithPagination(namespace, query, pageSize, bookmark)
+	if err != nil {
+		return nil, err
+	}
+	return &queryResultsItr{DBItr: dbItr, RWSetBuilder: h.rwsetBuilder}, nil
+}
+
 func (h *queryHelper) getPrivateData(ns, coll, key string) ([]byte, error) {
 	if err := h.checkDone(); err != nil {
 		return nil, err
@@ -214,15 +260,17 @@ func (h *queryHelper) checkDone() error {
 type resultsItr struct {
 	ns                      string
 	endKey                  string
-	dbItr                   statedb.ResultsIterator
+	dbItr                   statedb.QueryResultsIterator
 	rwSetBuilder            *rwsetutil.RWSetBuilder
 	rangeQueryInfo          *kvrwset.RangeQueryInfo
 	rangeQueryResultsHelper *rwsetutil.RangeQueryResultsHelper
 }
 
-func newResultsItr(ns string, startKey string, endKey string,
+// A pageSize of 0 and an empty bookmark result in a non-paginated range scan. For a paginated
+// scan, the captured range query info covers only the page that is returned
+func newResultsItr(ns string, startKey string, endKey string, pageSize int32, bookmark string,
 	db statedb.VersionedDB, rwsetBuilder *rwsetutil.RWSetBuilder, enableHashing bool, maxDegree uint32) (*resultsItr, error) {
-	dbItr, err := db.GetStateRangeScanIterator(ns, startKey, endKey)
+	dbItr, err := db.GetStateRangeScanIteratorWithPagination(ns, startKey, endKey, pageSize, bookmark)
 	if err != nil {
 		return nil, err
 	}
@@ -231,6 +279,9 @@ func newResultsItr(ns string, startKey string, endKey string,
 	if rwsetBuilder != nil {
 		itr.rwSetBuilder = rwsetBuilder
 		itr.endKey = endKey
+		if bookmark != "" {
+			startKey = bookmark
+		}
 		// just set the StartKey... set the EndKey later below in the Next() method.
 		itr.rangeQueryInfo = &kvrwset.RangeQueryInfo{StartKey: startKey}
 		resultsHelper, err := rwsetutil.NewRangeQueryResultsHelper(enableHashing, maxDegree)
@@ -291,6 +342,11 @@ func (itr *resultsItr) Close() {
 	itr.dbItr.Close()
 }
 
+// GetBookmarkAndClose implements method in interface ledger.QueryResultsIterator
+func (itr *resultsItr) GetBookmarkAndClose() string {
+	return itr.dbItr.GetBookmarkAndClose()
+}
+
 type queryResultsItr struct {
 	DBItr        statedb.ResultsIterator
 	RWSetBuilder *rwsetutil.RWSetBuilder
@@ -320,6 +376,15 @@ func (itr *queryResultsItr) Close() {
 	itr.DBItr.Close()
 }
 
+// GetBookmarkAndClose implements method in interface ledger.QueryResultsIterator
+func (itr *queryResultsItr) GetBookmarkAndClose() string {
+	if queryResultsItr, ok := itr.DBItr.(statedb.QueryResultsIterator); ok {
+		return queryResultsItr.GetBookmarkAndClose()
+	}
+	itr.DBItr.Close()
+	return ""
+}
+
 func decomposeVersionedValue(versionedValue *statedb.VersionedValue) ([]byte, *version.Height) {
 	var value []byte
 	var ver *version.Height
@@ -20,6 +20,7 @@ import (
 	"errors"
 
 	"github.com/hyperledger/fabric/common/ledger"
+	coreledger "github.com/hyperledger/fabric/core/ledger"
 )
 
 // LockBasedQueryExecutor is a query executor used in `LockBasedTxMgr`
@@ -41,7 +42,7 @@ func (q *lockBasedQueryExecutor) GetState(ns string, key string) ([]byte, error)
 
 // GetStateMetadata implements method in interface `ledger.QueryExecutor`
 func (q *lockBasedQueryExecutor) GetStateMetadata(namespace, key string) (map[string][]byte, error) {
-	return nil, errors.New("not implemented")
+	return q.helper.getStateMetadata(namespace, key)
 }
 
 // GetStateMultipleKeys implements method in interface `ledger.QueryExecutor`
@@ -62,6 +63,17 @@ func (q *lockBasedQueryExecutor) ExecuteQuery(namespace, query string) (ledger.R
 	return q.helper.executeQuery(namespace, query)
 }
 
+// GetStateRangeScanIteratorWithPagination implements method in interface `ledger.QueryExecutor`
+func (q *lockBasedQueryExecutor) GetStateRangeScanIteratorWithPagination(namespace string, startKey string, endKey string,
+	pageSize int32, bookmark string) (coreledger.QueryResultsIterator, error) {
+	return q.helper.getStateRangeScanIteratorWithPagination(namespace, startKey, endKey, pageSize, bookmark)
+}
+
+// ExecuteQueryWithPagination implements method in interface `ledger.QueryExecutor`
+func (q *lockBasedQueryExecutor) ExecuteQueryWithPagination(namespace, query string, pageSize int32, bookmark string) (coreledger.QueryResultsIterator, error) {
+	return q.helper.executeQueryWithPagination(namespace, query, pageSize, bookmark)
+}
+
 // GetPrivateData implements method in interface `ledger.QueryExecutor`
 func (q *lockBasedQueryExecutor) GetPrivateData(namespace, collection, key string) ([]byte, error) {
 	return q.helper.getPrivateData(namespace, collection, key)
@@ -23,22 +23,31 @@ import (
 	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/txmgr"
 )
 
 // LockBasedTxSimulator is a transaction simulator used in `LockBasedTxMgr`
 type lockBasedTxSimulator struct {
 	lockBasedQueryExecutor
-	rwsetBuilder            *rwsetutil.RWSetBuilder
-	writePerformed          bool
-	pvtdataQueriesPerformed bool
+	rwsetBuilder              *rwsetutil.RWSetBuilder
+	writePerformed            bool
+	pvtdataQueriesPerformed   bool
+	paginatedQueriesPerformed bool
+	// metadataWrites holds the metadata written by this transaction so far, so that
+	// successive updates to the entries of the metadata of a key are accumulated
+	metadataWrites map[statedb.CompositeKey]map[string][]byte
 }
 
 func newLockBasedTxSimulator(txmgr *LockBasedTxMgr, txid string) (*lockBasedTxSimulator, error) {
 	rwsetBuilder := rwsetutil.NewRWSetBuilder()
 	helper := &queryHelper{txmgr: txmgr, rwsetBuilder: rwsetBuilder}
 	logger.Debugf("constructing new tx simulator txid = [%s]", txid)
-	return &lockBasedTxSimulator{lockBasedQueryExecutor{helper, txid}, rwsetBuilder, false, false}, nil
+	return &lockBasedTxSimulator{
+		lockBasedQueryExecutor: lockBasedQueryExecutor{helper, txid},
+		rwsetBuilder:           rwsetBuilder,
+		metadataWrites:         make(map[statedb.CompositeKey]map[string][]byte),
+	}, nil
 }
 
 // SetState implements method in interface `ledger.TxSimulator`
@@ -73,17 +82,61 @@ func (s *lockBasedTxSimulator) SetStateMultipleKeys(namespace string, kvs map[st
 
 // SetStateMetadataEntry implements method in interface `ledger.TxSimulator`
 func (s *lockBasedTxSimulator) SetStateMetadataEntry(namespace, key, metakey string, metadata []byte) error {
-	return errors.New("not implemented")
+	return s.updateStateMetadata(namespace, key, func(m map[string][]byte) {
+		m[metakey] = metadata
+	})
 }
 
 // DeleteStateMetadataEntry implements method in interface `ledger.TxSimulator`
 func (s *lockBasedTxSimulator) DeleteStateMetadataEntry(namespace, key, metakey string) error {
-	return errors.New("not implemented")
+	return s.updateStateMetadata(namespace, key, func(m map[string][]byte) {
+		delete(m, metakey)
+	})
 }
 
 // DeleteStateMetadata implements method in interface `ledger.TxSimulator`
 func (s *lockBasedTxSimulator) DeleteStateMetadata(namespace, key string) error {
-	return errors.New("not implemented")
+	if err := s.checkBeforeMetadataWrite(key); err != nil {
+		return err
+	}
+	s.metadataWrites[statedb.CompositeKey{Namespace: namespace, Key: key}] = make(map[string][]byte)
+	s.rwsetBuilder.AddToMetadataWriteSet(namespace, key, nil)
+	return nil
+}
+
+// updateStateMetadata applies the supplied update to the latest metadata of the key, that is,
+// the metadata written earlier by this transaction or else the committed metadata, and adds
+// the resulting metadata to the write-set
+func (s *lockBasedTxSimulator) updateStateMetadata(namespace, key string, update func(map[string][]byte)) error {
+	if err := s.checkBeforeMetadataWrite(key); err != nil {
+		return err
+	}
+	compositeKey := statedb.CompositeKey{Namespace: namespace, Key: key}
+	metadata, ok := s.metadataWrites[compositeKey]
+	if !ok {
+		committedMetadata, err := s.helper.getStateMetadata(namespace, key)
+		if err != nil {
+			return err
+		}
+		metadata = make(map[string][]byte, len(committedMetadata))
+		for metakey, value := range committedMetadata {
+			metadata[metakey] = value
+		}
+	}
+	update(metadata)
+	s.metadataWrites[compositeKey] = metadata
+	s.rwsetBuilder.AddToMetadataWriteSet(namespace, key, metadata)
+	return nil
+}
+
+func (s *lockBasedTxSimulator) checkBeforeMetadataWrite(key string) error {
+	if err := s.helper.checkDone(); err != nil {
+		return err
+	}
+	if err := s.checkBeforeWrite(); err != nil {
+		return err
+	}
+	return s.helper.txmgr.db.ValidateKeyValue(key, nil)
 }
 
 // SetPrivateData implements method in interface `ledger.TxSimulator`
@@ -148,6 +201,23 @@ func (s *lockBasedTxSimulator) ExecuteQueryOnPrivateData(namespace, collection,
 	return s.lockBasedQueryExecutor.ExecuteQueryOnPrivateData(namespace, collection, query)
 }
 
+// GetStateRangeScanIteratorWithPagination implements method in interface `ledger.QueryExecutor`
+func (s *lockBasedTxSimulator) GetStateRangeScanIteratorWithPagination(namespace string, startKey string, endKey string,
+	pageSize int32, bookmark string) (ledger.QueryResultsIterator, error) {
+	if err := s.checkBeforePaginatedQueries(); err != nil {
+		return nil, err
+	}
+	return s.lockBasedQueryExecutor.GetStateRangeScanIteratorWithPagination(namespace, startKey, endKey, pageSize, bookmark)
+}
+
+// ExecuteQueryWithPagination implements method in interface `ledger.QueryExecutor`
+func (s *lockBasedTxSimulator) ExecuteQueryWithPagination(namespace, query string, pageSize int32, bookmark string) (ledger.QueryResultsIterator, error) {
+	if err := s.checkBeforePaginatedQueries(); err != nil {
+		return nil, err
+	}
+	return s.lockBasedQueryExecutor.ExecuteQueryWithPagination(namespace, query, pageSize, bookmark)
+}
+
 // GetTxSimulationResults implements method in interface `ledger.TxSimulator`
 func (s *lockBasedTxSimulator) GetTxSimulationResults() (*ledger.TxSimulationResults, error) {
 	logger.Debugf("Simulation completed, getting simulation results")
@@ -168,6 +238,11 @@ func (s *lockBasedTxSimulator) checkBeforeWrite() error {
 			Msg: fmt.Sprintf("Tx [%s]: Transaction has already performed queries on pvt data. Writes are not allowed", s.txid),
 		}
 	}
+	if s.paginatedQueriesPerformed {
+		return &txmgr.ErrUnsupportedTransaction{
+			Msg: fmt.Sprintf("Tx [%s]: Transaction has already performed a paginated query. Writes are not allowed", s.txid),
+		}
+	}
 	s.writePerformed = true
 	return nil
 }
@@ -181,3 +256,15 @@ func (s *lockBasedTxSimulator) checkBeforePvtdataQueries() error {
 	s.pvtdataQueriesPerformed = true
 	return nil
 }
+
+// checkBeforePaginatedQueries restricts paginated queries to read-only transactions, as the
+// range query info captured for a page cannot be used for phantom read validation at commit time
+func (s *lockBasedTxSimulator) checkBeforePaginatedQueries() error {
+	if s.writePerformed {
+		return &txmgr.ErrUnsupportedTransaction{
+			Msg: fmt.Sprintf("Tx [%s]: Paginated queries are supported only in a read-only transaction", s.txid),
+		}
+	}
+	s.paginatedQueriesPerformed = true
+	return nil
+}
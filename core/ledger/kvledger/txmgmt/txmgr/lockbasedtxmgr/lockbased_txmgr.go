@@ -6,6 +6,7 @@ SPDX-License-Identifier: Apache-2.0
 package lockbasedtxmgr
 
 import (
+	"bytes"
 	"sync"
 
 	"github.com/hyperledger/fabric/core/ledger/pvtdatapolicy"
@@ -15,9 +16,11 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/kvledger/bookkeeping"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/pvtstatepurgemgmt"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/validator"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/validator/valimpl"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
 )
@@ -33,7 +36,10 @@ type LockBasedTxMgr struct {
 	validator       validator.Validator
 	stateListeners  []ledger.StateListener
 	commitRWLock    sync.RWMutex
-	current         *current
+	// oldBlockCommit serializes the commit of the pvt data of old blocks with the commit of a new block
+	// so that the purging of the expired pvt data is not interleaved with the commit of the pvt data of old blocks
+	oldBlockCommit sync.Mutex
+	current        *current
 }
 
 type current struct {
@@ -127,6 +133,8 @@ func (txmgr *LockBasedTxMgr) Shutdown() {
 
 // Commit implements method in interface `txmgmt.TxMgr`
 func (txmgr *LockBasedTxMgr) Commit() error {
+	txmgr.oldBlockCommit.Lock()
+	defer txmgr.oldBlockCommit.Unlock()
 	// When using the purge manager for the first block commit after peer start, the asynchronous function
 	// 'PrepareForExpiringKeys' is invoked in-line. However, for the subsequent blocks commits, this function is invoked
 	// in advance for the next block
@@ -200,6 +208,64 @@ func (txmgr *LockBasedTxMgr) CommitLostBlock(blockAndPvtdata *ledger.BlockAndPvt
 	return txmgr.Commit()
 }
 
+// CommitPvtDataOfOldBlocks implements method in interface `txmgmt.TxMgr`
+// A pvt write is committed to the state only if the hash of the key is present in the state with the version
+// of the transaction that wrote it and with the hash of the value. Otherwise, the key has been overwritten,
+// deleted, or purged by a later transaction and the pvt write is stale
+func (txmgr *LockBasedTxMgr) CommitPvtDataOfOldBlocks(blocksPvtData map[uint64][]*ledger.TxPvtData) error {
+	txmgr.oldBlockCommit.Lock()
+	defer txmgr.oldBlockCommit.Unlock()
+
+	updates := privacyenabledstate.NewUpdateBatch()
+	for blkNum, txsPvtData := range blocksPvtData {
+		for _, txPvtData := range txsPvtData {
+			if err := txmgr.addPvtDataOfOldTx(updates.PvtUpdates, blkNum, txPvtData); err != nil {
+				return err
+			}
+		}
+	}
+	if updates.PvtUpdates.IsEmpty() {
+		return nil
+	}
+	if err := txmgr.pvtdataPurgeMgr.UpdateBookkeepingForPvtDataOfOldBlocks(updates.PvtUpdates); err != nil {
+		return err
+	}
+	txmgr.commitRWLock.Lock()
+	defer txmgr.commitRWLock.Unlock()
+	logger.Debugf("Committing the pvt data of old blocks to state database")
+	return txmgr.db.ApplyPrivacyAwareUpdates(updates, nil)
+}
+
+func (txmgr *LockBasedTxMgr) addPvtDataOfOldTx(pvtUpdates *privacyenabledstate.PvtUpdateBatch, blkNum uint64, txPvtData *ledger.TxPvtData) error {
+	txPvtRWSet, err := rwsetutil.TxPvtRwSetFromProtoMsg(txPvtData.WriteSet)
+	if err != nil {
+		return err
+	}
+	ver := version.NewHeight(blkNum, txPvtData.SeqInBlock)
+	for _, nsPvtRWSet := range txPvtRWSet.NsPvtRwSet {
+		for _, collPvtRWSet := range nsPvtRWSet.CollPvtRwSets {
+			ns, coll := nsPvtRWSet.NameSpace, collPvtRWSet.CollectionName
+			for _, kvWrite := range collPvtRWSet.KvRwSet.Writes {
+				if kvWrite.IsDelete {
+					// the hash of a deleted key is not present in the state and hence there is nothing to delete
+					continue
+				}
+				committedValueHash, err := txmgr.db.GetValueHash(ns, coll, util.ComputeStringHash(kvWrite.Key))
+				if err != nil {
+					return err
+				}
+				if committedValueHash == nil || !version.AreSame(committedValueHash.Version, ver) ||
+					!bytes.Equal(committedValueHash.Value, util.ComputeHash(kvWrite.Value)) {
+					logger.Debugf("Skipping the stale pvt write of key [%s] in [%s:%s] committed by tx [%d:%d]", kvWrite.Key, ns, coll, blkNum, txPvtData.SeqInBlock)
+					continue
+				}
+				pvtUpdates.Put(ns, coll, kvWrite.Key, kvWrite.Value, ver)
+			}
+		}
+	}
+	return nil
+}
+
 func extractStateUpdates(batch *privacyenabledstate.UpdateBatch, namespaces []string) ledger.StateUpdates {
 	stateupdates := make(ledger.StateUpdates)
 	for _, namespace := range namespaces {
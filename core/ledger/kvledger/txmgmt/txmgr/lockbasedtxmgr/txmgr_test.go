@@ -710,6 +710,68 @@ func TestTxSimulatorUnsupportedTx(t *testing.T) {
 	err = simulator.SetState("ns", "key", []byte("value"))
 	_, ok = err.(*txmgr.ErrUnsupportedTransaction)
 	testutil.AssertEquals(t, ok, true)
+
+	// paginated queries are supported only in a read-only transaction
+	simulator, _ = txMgr.NewTxSimulator("txid3")
+	err = simulator.SetState("ns", "key", []byte("value"))
+	testutil.AssertNoError(t, err, "")
+	_, err = simulator.GetStateRangeScanIteratorWithPagination("ns1", "startKey", "endKey", 2, "")
+	_, ok = err.(*txmgr.ErrUnsupportedTransaction)
+	testutil.AssertEquals(t, ok, true)
+
+	simulator, _ = txMgr.NewTxSimulator("txid4")
+	itr, err := simulator.GetStateRangeScanIteratorWithPagination("ns1", "startKey", "endKey", 2, "")
+	testutil.AssertNoError(t, err, "")
+	itr.Close()
+	err = simulator.SetState("ns", "key", []byte("value"))
+	_, ok = err.(*txmgr.ErrUnsupportedTransaction)
+	testutil.AssertEquals(t, ok, true)
+}
+
+func TestPaginatedRangeQuery(t *testing.T) {
+	testEnv := testEnvs[0]
+	testEnv.init(t, "TestPaginatedRangeQuery", nil)
+	defer testEnv.cleanup()
+	txMgr := testEnv.getTxMgr()
+	txMgrHelper := newTxMgrTestHelper(t, txMgr)
+	s, _ := txMgr.NewTxSimulator("test_tx1")
+	for i := 1; i <= 5; i++ {
+		s.SetState("ns1", createTestKey(i), createTestValue(i))
+	}
+	s.Done()
+	txRWSet, _ := s.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet.PubSimulationResults)
+
+	var retrievedKeys []string
+	bookmark := ""
+	for numPages := 1; ; numPages++ {
+		qe, err := txMgr.NewQueryExecutor(fmt.Sprintf("test_tx_page%d", numPages))
+		testutil.AssertNoError(t, err, "")
+		itr, err := qe.GetStateRangeScanIteratorWithPagination("ns1", createTestKey(1), "", 2, bookmark)
+		testutil.AssertNoError(t, err, "")
+		for {
+			kv, err := itr.Next()
+			testutil.AssertNoError(t, err, "")
+			if kv == nil {
+				break
+			}
+			retrievedKeys = append(retrievedKeys, kv.(*queryresult.KV).Key)
+		}
+		bookmark = itr.GetBookmarkAndClose()
+		qe.Done()
+		if bookmark == "" {
+			testutil.AssertEquals(t, numPages, 3)
+			break
+		}
+	}
+	testutil.AssertEquals(t, retrievedKeys,
+		[]string{createTestKey(1), createTestKey(2), createTestKey(3), createTestKey(4), createTestKey(5)})
+
+	// rich queries are not supported on leveldb
+	qe, _ := txMgr.NewQueryExecutor("test_tx_query")
+	defer qe.Done()
+	_, err := qe.ExecuteQueryWithPagination("ns1", `{"selector":{"owner":"bob"}}`, 2, "")
+	testutil.AssertError(t, err, "Expected an error for a rich query on leveldb")
 }
 
 func TestTxSimulatorMissingPvtdata(t *testing.T) {
@@ -864,3 +926,85 @@ func prepareNextBlockForTest(t *testing.T, txMgr txmgr.TxMgr, bg *testutil.Block
 		BlockPvtData: map[uint64]*ledger.TxPvtData{0: {SeqInBlock: 0, WriteSet: simRes.PvtSimulationResults}},
 	}
 }
+
+func TestTxSimulatorWithStateMetadata(t *testing.T) {
+	for _, testEnv := range testEnvs {
+		t.Run(testEnv.getName(), func(t *testing.T) {
+			testLedgerID := "testtxsimulatorwithstatemetadata"
+			testEnv.init(t, testLedgerID, nil)
+			testTxSimulatorWithStateMetadata(t, testEnv)
+			testEnv.cleanup()
+		})
+	}
+}
+
+func testTxSimulatorWithStateMetadata(t *testing.T, env testEnv) {
+	txMgr := env.getTxMgr()
+	txMgrHelper := newTxMgrTestHelper(t, txMgr)
+
+	// simulate tx1 that writes a value and the metadata of a key
+	s1, _ := txMgr.NewTxSimulator("test_tx1")
+	assert.NoError(t, s1.SetState("ns1", "key1", []byte("value1")))
+	assert.NoError(t, s1.SetStateMetadataEntry("ns1", "key1", "metakey1", []byte("metavalue1")))
+	assert.NoError(t, s1.SetStateMetadataEntry("ns1", "key1", "metakey2", []byte("metavalue2")))
+	assert.NoError(t, s1.SetState("ns1", "key2", []byte("value2")))
+	s1.Done()
+	txRWSet1, _ := s1.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet1.PubSimulationResults)
+
+	// the metadata entries accumulate within a transaction
+	qe, _ := txMgr.NewQueryExecutor("test_tx2")
+	metadata, err := qe.GetStateMetadata("ns1", "key1")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"metakey1": []byte("metavalue1"), "metakey2": []byte("metavalue2")}, metadata)
+	metadata, err = qe.GetStateMetadata("ns1", "key2")
+	assert.NoError(t, err)
+	assert.Nil(t, metadata)
+	metadata, err = qe.GetStateMetadata("ns1", "non-existing-key")
+	assert.NoError(t, err)
+	assert.Nil(t, metadata)
+	qe.Done()
+
+	// simulate tx3 that deletes one of the entries without touching the value
+	s3, _ := txMgr.NewTxSimulator("test_tx3")
+	assert.NoError(t, s3.DeleteStateMetadataEntry("ns1", "key1", "metakey1"))
+	assert.NoError(t, s3.SetStateMetadataEntry("ns1", "key2", "metakey1", []byte("metavalue1")))
+	s3.Done()
+	txRWSet3, _ := s3.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet3.PubSimulationResults)
+
+	qe, _ = txMgr.NewQueryExecutor("test_tx4")
+	value, _ := qe.GetState("ns1", "key1")
+	assert.Equal(t, []byte("value1"), value)
+	metadata, _ = qe.GetStateMetadata("ns1", "key1")
+	assert.Equal(t, map[string][]byte{"metakey2": []byte("metavalue2")}, metadata)
+	metadata, _ = qe.GetStateMetadata("ns1", "key2")
+	assert.Equal(t, map[string][]byte{"metakey1": []byte("metavalue1")}, metadata)
+	qe.Done()
+
+	// a metadata update changes the version of the key
+	vv, _ := env.getVDB().GetState("ns1", "key1")
+	assert.Equal(t, version.NewHeight(2, 0), vv.Version)
+
+	// simulate tx5 that deletes the metadata and tx6 that read the metadata concurrently
+	s5, _ := txMgr.NewTxSimulator("test_tx5")
+	assert.NoError(t, s5.DeleteStateMetadata("ns1", "key1"))
+	s5.Done()
+	s6, _ := txMgr.NewTxSimulator("test_tx6")
+	_, err = s6.GetStateMetadata("ns1", "key1")
+	assert.NoError(t, err)
+	assert.NoError(t, s6.SetState("ns1", "key3", []byte("value3")))
+	s6.Done()
+
+	txRWSet5, _ := s5.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet5.PubSimulationResults)
+	txRWSet6, _ := s6.GetTxSimulationResults()
+	txMgrHelper.checkRWsetInvalid(txRWSet6.PubSimulationResults)
+
+	qe, _ = txMgr.NewQueryExecutor("test_tx7")
+	defer qe.Done()
+	value, _ = qe.GetState("ns1", "key1")
+	assert.Equal(t, []byte("value1"), value)
+	metadata, _ = qe.GetStateMetadata("ns1", "key1")
+	assert.Nil(t, metadata)
+}
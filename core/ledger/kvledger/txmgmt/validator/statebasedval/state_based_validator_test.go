@@ -26,6 +26,7 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/storageutil"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/validator/valinternal"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
 	"github.com/hyperledger/fabric/core/ledger/util"
@@ -221,6 +222,71 @@ func TestValidator(t *testing.T) {
 	checkValidation(t, validator, getTestPubSimulationRWSet(t, rwsetBuilder4, rwsetBuilder5), []int{1})
 }
 
+func TestValidatorWithMetadataWrites(t *testing.T) {
+	testDBEnv := privacyenabledstate.LevelDBCommonStorageTestEnv{}
+	testDBEnv.Init(t)
+	defer testDBEnv.Cleanup()
+	db := testDBEnv.GetDBHandle("TestDB")
+
+	//populate db with initial data
+	batch := privacyenabledstate.NewUpdateBatch()
+	metadata, err := storageutil.SerializeMetadata([]*kvrwset.KVMetadataEntry{{Name: "metakey", Value: []byte("metavalue")}})
+	testutil.AssertNoError(t, err, "")
+	batch.PubUpdates.PutValAndMetadata("ns1", "key1", []byte("value1"), metadata, version.NewHeight(1, 0))
+	batch.PubUpdates.Put("ns1", "key2", []byte("value2"), version.NewHeight(1, 1))
+	db.ApplyPrivacyAwareUpdates(batch, version.NewHeight(1, 1))
+
+	validator := NewValidator(db)
+
+	// tx0 updates the value of key1 and retains its metadata
+	rwsetBuilder0 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder0.AddToWriteSet("ns1", "key1", []byte("value1_new"))
+	// tx1 updates the metadata of key2 and retains its value
+	rwsetBuilder1 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder1.AddToMetadataWriteSet("ns1", "key2", map[string][]byte{"metakey": []byte("metavalue2")})
+	// tx2 sets the metadata of a non-existing key, which is ignored
+	rwsetBuilder2 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder2.AddToMetadataWriteSet("ns1", "key3", map[string][]byte{"metakey": []byte("metavalue3")})
+	// tx3 deletes the metadata of key1 as updated by tx0
+	rwsetBuilder3 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder3.AddToMetadataWriteSet("ns1", "key1", nil)
+
+	var txs []*valinternal.Transaction
+	for i, txRWSet := range getTestPubSimulationRWSet(t, rwsetBuilder0, rwsetBuilder1, rwsetBuilder2, rwsetBuilder3) {
+		txs = append(txs, &valinternal.Transaction{
+			ID:             fmt.Sprintf("txid-%d", i),
+			IndexInBlock:   i,
+			ValidationCode: peer.TxValidationCode_VALID,
+			RWSet:          txRWSet,
+		})
+	}
+	updates, err := validator.ValidateAndPrepareBatch(&valinternal.Block{Num: 2, Txs: txs}, true)
+	testutil.AssertNoError(t, err, "")
+
+	testutil.AssertEquals(t, updates.PubUpdates.Get("ns1", "key1"),
+		&statedb.VersionedValue{Value: []byte("value1_new"), Version: version.NewHeight(2, 3)})
+
+	metadata2, err := storageutil.SerializeMetadata([]*kvrwset.KVMetadataEntry{{Name: "metakey", Value: []byte("metavalue2")}})
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, updates.PubUpdates.Get("ns1", "key2"),
+		&statedb.VersionedValue{Value: []byte("value2"), Metadata: metadata2, Version: version.NewHeight(2, 1)})
+
+	testutil.AssertEquals(t, updates.PubUpdates.Exists("ns1", "key3"), false)
+
+	// a value write alone retains the committed metadata
+	rwsetBuilder4 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder4.AddToWriteSet("ns1", "key1", []byte("value1_latest"))
+	txs = []*valinternal.Transaction{{
+		ID:             "txid-4",
+		ValidationCode: peer.TxValidationCode_VALID,
+		RWSet:          getTestPubSimulationRWSet(t, rwsetBuilder4)[0],
+	}}
+	updates, err = validator.ValidateAndPrepareBatch(&valinternal.Block{Num: 3, Txs: txs}, true)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, updates.PubUpdates.Get("ns1", "key1"),
+		&statedb.VersionedValue{Value: []byte("value1_latest"), Metadata: metadata, Version: version.NewHeight(3, 0)})
+}
+
 func TestPhantomValidation(t *testing.T) {
 	testDBEnv := privacyenabledstate.LevelDBCommonStorageTestEnv{}
 	testDBEnv.Init(t)
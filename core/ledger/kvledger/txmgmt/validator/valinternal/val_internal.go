@@ -7,12 +7,18 @@ SPDX-License-Identifier: Apache-2.0
 package valinternal
 
 import (
+	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/storageutil"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
 	"github.com/hyperledger/fabric/protos/peer"
 )
 
+var logger = flogging.MustGetLogger("valinternal")
+
 // InternalValidator is supposed to validate the transactions based on public data and hashes present in a block
 // and returns a batch that should be used to update the state
 type InternalValidator interface {
@@ -78,16 +84,59 @@ func (t *Transaction) RetrieveHash(ns string, coll string) []byte {
 	return nil
 }
 
-// ApplyWriteSet adds (or deletes) the key/values present in the write set to the PubAndHashUpdates
-func (u *PubAndHashUpdates) ApplyWriteSet(txRWSet *rwsetutil.TxRwSet, txHeight *version.Height) {
+// ApplyWriteSet adds (or deletes) the key/values present in the write set to the PubAndHashUpdates.
+// A value write that is not accompanied by a metadata write in the same transaction retains the
+// latest metadata of the key. Similarly, a metadata write retains the latest value of the key; a
+// metadata write for a key that does not exist is ignored. The latest state of a key is looked up
+// in the updates of the preceding transactions and then in the statedb
+func (u *PubAndHashUpdates) ApplyWriteSet(txRWSet *rwsetutil.TxRwSet, txHeight *version.Height, db privacyenabledstate.DB) error {
 	for _, nsRWSet := range txRWSet.NsRwSets {
 		ns := nsRWSet.NameSpace
+		metadataWrites := make(map[string]*kvrwset.KVMetadataWrite)
+		for _, metadataWrite := range nsRWSet.KvRwSet.MetadataWrites {
+			metadataWrites[metadataWrite.Key] = metadataWrite
+		}
+
 		for _, kvWrite := range nsRWSet.KvRwSet.Writes {
+			key := kvWrite.Key
 			if kvWrite.IsDelete {
-				u.PubUpdates.Delete(ns, kvWrite.Key, txHeight)
+				u.PubUpdates.Delete(ns, key, txHeight)
+				delete(metadataWrites, key)
+				continue
+			}
+			var metadata []byte
+			var err error
+			if metadataWrite, ok := metadataWrites[key]; ok {
+				metadata, err = serializeMetadata(metadataWrite.Entries)
+				delete(metadataWrites, key)
 			} else {
-				u.PubUpdates.Put(ns, kvWrite.Key, kvWrite.Value, txHeight)
+				metadata, err = u.retrieveLatestMetadata(ns, key, db)
+			}
+			if err != nil {
+				return err
+			}
+			u.PubUpdates.PutValAndMetadata(ns, key, kvWrite.Value, metadata, txHeight)
+		}
+
+		for _, metadataWrite := range nsRWSet.KvRwSet.MetadataWrites {
+			key := metadataWrite.Key
+			if _, ok := metadataWrites[key]; !ok {
+				// already applied along with the value write
+				continue
+			}
+			latestVal, err := u.retrieveLatestState(ns, key, db)
+			if err != nil {
+				return err
+			}
+			if latestVal == nil || latestVal.Value == nil {
+				logger.Debugf("Ignoring metadata write for non-existing key [%s] in namespace [%s]", key, ns)
+				continue
+			}
+			metadata, err := serializeMetadata(metadataWrite.Entries)
+			if err != nil {
+				return err
 			}
+			u.PubUpdates.PutValAndMetadata(ns, key, latestVal.Value, metadata, txHeight)
 		}
 
 		for _, collHashRWset := range nsRWSet.CollHashedRwSets {
@@ -101,4 +150,29 @@ func (u *PubAndHashUpdates) ApplyWriteSet(txRWSet *rwsetutil.TxRwSet, txHeight *
 			}
 		}
 	}
+	return nil
+}
+
+// retrieveLatestState returns the value of the key from the updates of the preceding
+// transactions, if present, or else from the statedb
+func (u *PubAndHashUpdates) retrieveLatestState(ns, key string, db privacyenabledstate.DB) (*statedb.VersionedValue, error) {
+	if u.PubUpdates.Exists(ns, key) {
+		return u.PubUpdates.Get(ns, key), nil
+	}
+	return db.GetState(ns, key)
+}
+
+func (u *PubAndHashUpdates) retrieveLatestMetadata(ns, key string, db privacyenabledstate.DB) ([]byte, error) {
+	vv, err := u.retrieveLatestState(ns, key, db)
+	if err != nil || vv == nil {
+		return nil, err
+	}
+	return vv.Metadata, nil
+}
+
+func serializeMetadata(entries []*kvrwset.KVMetadataEntry) ([]byte, error) {
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	return storageutil.SerializeMetadata(entries)
 }
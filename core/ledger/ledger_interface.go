@@ -75,6 +75,13 @@ type PeerLedger interface {
 	Prune(policy commonledger.PrunePolicy) error
 	// GetConfigHistoryRetriever returns the ConfigHistoryRetriever
 	GetConfigHistoryRetriever() (ConfigHistoryRetriever, error)
+	// CommitPvtDataOfOldBlocks commits the private data corresponding to already committed blocks.
+	// The hashes of the supplied private write sets are verified against the hashes present in the
+	// corresponding blocks. The private write sets that do not match are not committed and are
+	// returned as `PvtdataHashMismatch` so that the caller can fetch them again from another source
+	CommitPvtDataOfOldBlocks(blockPvtData []*BlockPvtData) ([]*PvtdataHashMismatch, error)
+	// GetMissingPvtDataTracker return the MissingPvtDataTracker
+	GetMissingPvtDataTracker() (MissingPvtDataTracker, error)
 }
 
 // ValidatedLedger represents the 'final ledger' after filtering out invalid transactions from PeerLedger.
@@ -106,6 +113,16 @@ type QueryExecutor interface {
 	// For a chaincode, the namespace corresponds to the chaincodeId
 	// The returned ResultsIterator contains results of type *KV which is defined in protos/ledger/queryresult.
 	ExecuteQuery(namespace, query string) (commonledger.ResultsIterator, error)
+	// GetStateRangeScanIteratorWithPagination returns an iterator that contains a page of at most pageSize
+	// key-values between given key ranges. The bookmark returned by the iterator of a page can be supplied
+	// for retrieving the next page; an empty bookmark starts the scan from the startKey.
+	// The returned QueryResultsIterator contains results of type *KV which is defined in protos/ledger/queryresult.
+	GetStateRangeScanIteratorWithPagination(namespace string, startKey string, endKey string, pageSize int32, bookmark string) (QueryResultsIterator, error)
+	// ExecuteQueryWithPagination executes the given query and returns an iterator that contains a page of at most
+	// pageSize results. The bookmark returned by the iterator of a page can be supplied for retrieving the next page.
+	// Only used for state databases that support query
+	// The returned QueryResultsIterator contains results of type *KV which is defined in protos/ledger/queryresult.
+	ExecuteQueryWithPagination(namespace, query string, pageSize int32, bookmark string) (QueryResultsIterator, error)
 	// GetPrivateData gets the value of a private data item identified by a tuple <namespace, collection, key>
 	GetPrivateData(namespace, collection, key string) ([]byte, error)
 	// GetPrivateMetadata gets the metadata of a private data item identified by a tuple <namespace, collection, key>
@@ -127,6 +144,14 @@ type QueryExecutor interface {
 	Done()
 }
 
+// QueryResultsIterator adds GetBookmarkAndClose method to ResultsIterator
+type QueryResultsIterator interface {
+	commonledger.ResultsIterator
+	// GetBookmarkAndClose returns the bookmark for retrieving the next page of results and releases the
+	// resources held by the iterator. An empty bookmark is returned if no more results are available
+	GetBookmarkAndClose() string
+}
+
 // HistoryQueryExecutor executes the history queries
 type HistoryQueryExecutor interface {
 	// GetHistoryForKey retrieves the history of values for a key.
@@ -201,6 +226,53 @@ type BlockAndPvtData struct {
 	Missing      []MissingPrivateData
 }
 
+// BlockPvtData contains the private data of the transactions of an already committed block
+// The map contains the tuples <seqInBlock, *TxPvtData>
+type BlockPvtData struct {
+	BlockNum  uint64
+	WriteSets map[uint64]*TxPvtData
+}
+
+// MissingPvtDataTracker allows getting information about the private data that is missing on the peer
+type MissingPvtDataTracker interface {
+	// GetMissingPvtDataInfoForMostRecentBlocks returns the information about the missing private data
+	// of at most `maxBlocks` most recent blocks that have missing private data
+	GetMissingPvtDataInfoForMostRecentBlocks(maxBlocks int) (MissingPvtDataInfo, error)
+	// GetMissingPvtDataInfoForMostRecentBlocksBelow returns the information about the missing private data
+	// of at most `maxBlocks` most recent blocks below the block `blockNum` that have missing private data
+	GetMissingPvtDataInfoForMostRecentBlocksBelow(blockNum uint64, maxBlocks int) (MissingPvtDataInfo, error)
+}
+
+// MissingPvtDataInfo is a map of block number to MissingBlockPvtdataInfo
+type MissingPvtDataInfo map[uint64]MissingBlockPvtdataInfo
+
+// MissingBlockPvtdataInfo is a map of transaction number (within the block) to MissingCollectionPvtDataInfo
+type MissingBlockPvtdataInfo map[uint64][]*MissingCollectionPvtDataInfo
+
+// MissingCollectionPvtDataInfo includes the name of the chaincode and collection for which private data is missing
+type MissingCollectionPvtDataInfo struct {
+	Namespace, Collection string
+}
+
+// Add adds a missing data entry to the MissingPvtDataInfo
+func (missingPvtDataInfo MissingPvtDataInfo) Add(blkNum, txNum uint64, ns, coll string) {
+	missingBlockPvtDataInfo, ok := missingPvtDataInfo[blkNum]
+	if !ok {
+		missingBlockPvtDataInfo = make(MissingBlockPvtdataInfo)
+		missingPvtDataInfo[blkNum] = missingBlockPvtDataInfo
+	}
+	missingBlockPvtDataInfo[txNum] = append(missingBlockPvtDataInfo[txNum],
+		&MissingCollectionPvtDataInfo{Namespace: ns, Collection: coll})
+}
+
+// PvtdataHashMismatch is used when the hash of a private write set does not match
+// the corresponding hash present in the block
+type PvtdataHashMismatch struct {
+	BlockNum, TxNum       uint64
+	Namespace, Collection string
+	ExpectedHash          []byte
+}
+
 // PvtCollFilter represents the set of the collection names (as keys of the map with value 'true')
 type PvtCollFilter map[string]bool
 
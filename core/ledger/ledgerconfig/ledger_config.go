@@ -31,11 +31,14 @@ const confBookkeeper = "bookkeeper"
 const confConfigHistory = "configHistory"
 const confChains = "chains"
 const confPvtdataStore = "pvtdataStore"
+const confFileLock = "fileLock"
 const confQueryLimit = "ledger.state.couchDBConfig.queryLimit"
 const confEnableHistoryDatabase = "ledger.history.enableHistoryDatabase"
 const confMaxBatchSize = "ledger.state.couchDBConfig.maxBatchUpdateSize"
 const confAutoWarmIndexes = "ledger.state.couchDBConfig.autoWarmIndexes"
 const confWarmIndexesAfterNBlocks = "ledger.state.couchDBConfig.warmIndexesAfterNBlocks"
+const confValueCacheSize = "ledger.state.couchDBConfig.valueCacheSize"
+const confMaxBlockfileSize = "ledger.blockchain.maxBlockfileSize"
 
 // GetRootPath returns the filesystem path.
 // All ledger related contents are expected to be stored under this path
@@ -79,9 +82,19 @@ func GetConfigHistoryPath() string {
 	return filepath.Join(GetRootPath(), confConfigHistory)
 }
 
+// GetFileLockPath returns the filesystem path of the lock that is held by the process using the ledgers
+func GetFileLockPath() string {
+	return filepath.Join(GetRootPath(), confFileLock)
+}
+
 // GetMaxBlockfileSize returns maximum size of the block file
 func GetMaxBlockfileSize() int {
-	return 64 * 1024 * 1024
+	maxBlockfileSize := viper.GetInt(confMaxBlockfileSize)
+	// if maxBlockfileSize was unset, default to 64 MB
+	if maxBlockfileSize <= 0 {
+		maxBlockfileSize = 64 * 1024 * 1024
+	}
+	return maxBlockfileSize
 }
 
 //GetQueryLimit exposes the queryLimit variable
@@ -104,6 +117,20 @@ func GetMaxBatchUpdateSize() int {
 	return maxBatchUpdateSize
 }
 
+//GetValueCacheSize exposes the valueCacheSize variable, i.e., the maximum number of
+//values per namespace that the CouchDB state database keeps in memory. 0 disables the cache
+func GetValueCacheSize() int {
+	valueCacheSize := viper.GetInt(confValueCacheSize)
+	// if valueCacheSize was unset, default to 1000
+	if !viper.IsSet(confValueCacheSize) {
+		valueCacheSize = 1000
+	}
+	if valueCacheSize < 0 {
+		valueCacheSize = 0
+	}
+	return valueCacheSize
+}
+
 // GetPvtdataStorePurgeInterval returns the interval in the terms of number of blocks
 // when the purge for the expired data would be performed
 func GetPvtdataStorePurgeInterval() uint64 {
@@ -164,6 +164,43 @@ func TestGetWarmIndexesAfterNBlocks(t *testing.T) {
 	testutil.AssertEquals(t, updatedValue, 10)
 }
 
+func TestGetValueCacheSizeDefault(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defaultValue := GetValueCacheSize()
+	testutil.AssertEquals(t, defaultValue, 1000) //test default config is 1000
+}
+
+func TestGetValueCacheSizeUnset(t *testing.T) {
+	viper.Reset()
+	defaultValue := GetValueCacheSize()
+	testutil.AssertEquals(t, defaultValue, 1000) //test default config is 1000
+}
+
+func TestGetValueCacheSize(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	viper.Set("ledger.state.couchDBConfig.valueCacheSize", 0)
+	updatedValue := GetValueCacheSize()
+	testutil.AssertEquals(t, updatedValue, 0) //test config returns 0
+	viper.Set("ledger.state.couchDBConfig.valueCacheSize", -1)
+	updatedValue = GetValueCacheSize()
+	testutil.AssertEquals(t, updatedValue, 0) //negative values disable the cache
+}
+
+func TestGetMaxBlockfileSizeUnset(t *testing.T) {
+	viper.Reset()
+	defaultValue := GetMaxBlockfileSize()
+	testutil.AssertEquals(t, defaultValue, 64*1024*1024) //test default config is 64 MB
+}
+
+func TestGetMaxBlockfileSize(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	viper.Set("ledger.blockchain.maxBlockfileSize", 1024)
+	updatedValue := GetMaxBlockfileSize()
+	testutil.AssertEquals(t, updatedValue, 1024) //test config returns 1024
+}
+
 func setUpCoreYAMLConfig() {
 	//call a helper method to load the core.yaml
 	ledgertestutil.SetupCoreYAMLConfig()
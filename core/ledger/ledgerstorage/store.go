@@ -82,6 +82,15 @@ func (p *Provider) Open(ledgerid string) (*Store, error) {
 	return store, nil
 }
 
+// Drop removes the block store and the pvt data store of the given ledger.
+// The store of the ledger is expected to be shut down before invoking this function
+func (p *Provider) Drop(ledgerid string) error {
+	if err := p.pvtdataStoreProvider.Drop(ledgerid); err != nil {
+		return err
+	}
+	return p.blkStoreProvider.Drop(ledgerid)
+}
+
 // Close closes the provider
 func (p *Provider) Close() {
 	p.blkStoreProvider.Close()
@@ -101,7 +110,13 @@ func (s *Store) CommitWithPvtData(blockAndPvtdata *ledger.BlockAndPvtData) error
 	for _, v := range blockAndPvtdata.BlockPvtData {
 		pvtdata = append(pvtdata, v)
 	}
-	if err := s.pvtdataStore.Prepare(blockAndPvtdata.Block.Header.Number, pvtdata); err != nil {
+	missingPvtData := make(ledger.MissingBlockPvtdataInfo)
+	for _, missing := range blockAndPvtdata.Missing {
+		txNum := uint64(missing.SeqInBlock)
+		missingPvtData[txNum] = append(missingPvtData[txNum],
+			&ledger.MissingCollectionPvtDataInfo{Namespace: missing.Namespace, Collection: missing.Collection})
+	}
+	if err := s.pvtdataStore.Prepare(blockAndPvtdata.Block.Header.Number, pvtdata, missingPvtData); err != nil {
 		return err
 	}
 	if err := s.AddBlock(blockAndPvtdata.Block); err != nil {
@@ -111,6 +126,64 @@ func (s *Store) CommitWithPvtData(blockAndPvtdata *ledger.BlockAndPvtData) error
 	return s.pvtdataStore.Commit()
 }
 
+// BootstrapFromSnapshot prepares the empty block store and pvt data store for receiving the blocks
+// starting from the block `firstBlockNum`. The transactions returned by `nextTxInfo` are indexed as
+// the transactions of the preceding blocks, which are not present in the store
+func (s *Store) BootstrapFromSnapshot(firstBlockNum uint64, nextTxInfo func() (*blkstorage.TxValidationInfo, error)) error {
+	s.rwlock.Lock()
+	defer s.rwlock.Unlock()
+	if err := s.BlockStore.BootstrapFromSnapshot(firstBlockNum, nextTxInfo); err != nil {
+		return err
+	}
+	if firstBlockNum == 0 {
+		return nil
+	}
+	return s.pvtdataStore.InitLastCommittedBlock(firstBlockNum - 1)
+}
+
+// Rollback rolls back both the block store and the pvt data store to the given block number.
+// The pvt data store is rolled back first, as rolling it back again is a no-op. Hence, if a crash
+// happens in between, retrying the rollback brings both the stores in sync
+func (s *Store) Rollback(targetBlockNum uint64) error {
+	s.rwlock.Lock()
+	defer s.rwlock.Unlock()
+	if err := s.pvtdataStore.RollbackToBlock(targetBlockNum); err != nil {
+		return err
+	}
+	return s.BlockStore.Rollback(targetBlockNum)
+}
+
+// CommitPvtDataOfOldBlocks commits the pvt data of the already committed blocks.
+// The supplied pvt data is expected to have been verified against the blocks by the caller
+func (s *Store) CommitPvtDataOfOldBlocks(blocksPvtData map[uint64][]*ledger.TxPvtData) error {
+	s.rwlock.Lock()
+	defer s.rwlock.Unlock()
+	return s.pvtdataStore.CommitPvtDataOfOldBlocks(blocksPvtData)
+}
+
+// RemoveMissingPvtDataOfBlocks removes the missing pvt data entries of the given blocks
+func (s *Store) RemoveMissingPvtDataOfBlocks(blkNums []uint64) error {
+	s.rwlock.Lock()
+	defer s.rwlock.Unlock()
+	return s.pvtdataStore.RemoveMissingPvtDataOfBlocks(blkNums)
+}
+
+// GetMissingPvtDataInfoForMostRecentBlocks returns the information about the missing pvt data
+// of at most `maxBlocks` most recent blocks that have missing pvt data
+func (s *Store) GetMissingPvtDataInfoForMostRecentBlocks(maxBlocks int) (ledger.MissingPvtDataInfo, error) {
+	s.rwlock.RLock()
+	defer s.rwlock.RUnlock()
+	return s.pvtdataStore.GetMissingPvtDataInfoForMostRecentBlocks(maxBlocks)
+}
+
+// GetMissingPvtDataInfoForMostRecentBlocksBelow returns the information about the missing pvt data
+// of at most `maxBlocks` most recent blocks below the block `blockNum` that have missing pvt data
+func (s *Store) GetMissingPvtDataInfoForMostRecentBlocksBelow(blockNum uint64, maxBlocks int) (ledger.MissingPvtDataInfo, error) {
+	s.rwlock.RLock()
+	defer s.rwlock.RUnlock()
+	return s.pvtdataStore.GetMissingPvtDataInfoForMostRecentBlocksBelow(blockNum, maxBlocks)
+}
+
 // GetPvtDataAndBlockByNum returns the block and the corresponding pvt data.
 // The pvt data is filtered by the list of 'collections' supplied
 func (s *Store) GetPvtDataAndBlockByNum(blockNum uint64, filter ledger.PvtNsCollFilter) (*ledger.BlockAndPvtData, error) {
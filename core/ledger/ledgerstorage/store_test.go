@@ -29,6 +29,7 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/pvtdatapolicy"
 	btltestutil "github.com/hyperledger/fabric/core/ledger/pvtdatapolicy/testutil"
 	"github.com/hyperledger/fabric/protos/ledger/rwset"
+	putils "github.com/hyperledger/fabric/protos/utils"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
@@ -102,6 +103,144 @@ func TestStore(t *testing.T) {
 	assert.Nil(t, blockAndPvtdata.BlockPvtData[2])
 }
 
+func TestStoreRollback(t *testing.T) {
+	testEnv := newTestEnv(t)
+	defer testEnv.cleanup()
+	provider := NewProvider()
+	defer provider.Close()
+	store, err := provider.Open("testLedger")
+	assert.NoError(t, err)
+	store.Init(btlPolicyForSampleData())
+	defer store.Shutdown()
+
+	sampleData := sampleData(t)
+	for _, sampleDatum := range sampleData {
+		assert.NoError(t, store.CommitWithPvtData(sampleDatum))
+	}
+	assert.Error(t, store.Rollback(uint64(len(sampleData)-1)))
+	assert.NoError(t, store.Rollback(2))
+
+	bcInfo, err := store.GetBlockchainInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), bcInfo.Height)
+	blockAndPvtdata, err := store.GetPvtDataAndBlockByNum(2, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, sampleData[2], blockAndPvtdata)
+	_, err = store.GetPvtDataByNum(3, nil)
+	assert.Error(t, err)
+
+	// the blocks following the target block can be committed again
+	for _, sampleDatum := range sampleData[3:] {
+		assert.NoError(t, store.CommitWithPvtData(sampleDatum))
+	}
+	blockAndPvtdata, err = store.GetPvtDataAndBlockByNum(3, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, sampleData[3], blockAndPvtdata)
+}
+
+func TestStoreBootstrapFromSnapshot(t *testing.T) {
+	testEnv := newTestEnv(t)
+	defer testEnv.cleanup()
+	provider := NewProvider()
+	defer provider.Close()
+	sampleData := sampleData(t)
+
+	sourceStore, err := provider.Open("sourceLedger")
+	assert.NoError(t, err)
+	sourceStore.Init(btlPolicyForSampleData())
+	for _, sampleDatum := range sampleData {
+		assert.NoError(t, sourceStore.CommitWithPvtData(sampleDatum))
+	}
+	var txInfos []*blkstorage.TxValidationInfo
+	assert.NoError(t, sourceStore.ExportTxValidationInfo(func(txInfo *blkstorage.TxValidationInfo) error {
+		txInfos = append(txInfos, txInfo)
+		return nil
+	}))
+	sourceStore.Shutdown()
+
+	targetStore, err := provider.Open("targetLedger")
+	assert.NoError(t, err)
+	targetStore.Init(btlPolicyForSampleData())
+	nextTxInfo := func() (*blkstorage.TxValidationInfo, error) {
+		if len(txInfos) == 0 {
+			return nil, nil
+		}
+		txInfo := txInfos[0]
+		txInfos = txInfos[1:]
+		return txInfo, nil
+	}
+	assert.NoError(t, targetStore.BootstrapFromSnapshot(3, nextTxInfo))
+	// the blocks following the snapshot are committed with their pvt data
+	for _, sampleDatum := range sampleData[3:] {
+		assert.NoError(t, targetStore.CommitWithPvtData(sampleDatum))
+	}
+	bcInfo, err := targetStore.GetBlockchainInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(len(sampleData)), bcInfo.Height)
+	blockAndPvtdata, err := targetStore.GetPvtDataAndBlockByNum(3, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, sampleData[3], blockAndPvtdata)
+	_, err = targetStore.RetrieveBlockByNumber(2)
+	assert.Equal(t, blkstorage.ErrPruned, err)
+	env, err := putils.GetEnvelopeFromBlock(sampleData[1].Block.Data.Data[0])
+	assert.NoError(t, err)
+	chdr, err := putils.ChannelHeader(env)
+	assert.NoError(t, err)
+	firstTxID := chdr.TxId
+	_, err = targetStore.RetrieveTxByID(firstTxID)
+	assert.Equal(t, blkstorage.ErrPruned, err)
+	_, err = targetStore.RetrieveTxValidationCodeByTxID(firstTxID)
+	assert.NoError(t, err)
+	targetStore.Shutdown()
+
+	// dropping the stores brings the ledger back to an empty state
+	assert.NoError(t, provider.Drop("targetLedger"))
+	targetStore, err = provider.Open("targetLedger")
+	assert.NoError(t, err)
+	defer targetStore.Shutdown()
+	bcInfo, err = targetStore.GetBlockchainInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), bcInfo.Height)
+}
+
+func TestStoreMissingPvtData(t *testing.T) {
+	testEnv := newTestEnv(t)
+	defer testEnv.cleanup()
+	provider := NewProvider()
+	defer provider.Close()
+	store, err := provider.Open("testLedger")
+	assert.NoError(t, err)
+	store.Init(btlPolicyForSampleData())
+	defer store.Shutdown()
+
+	// block 2 misses the pvt data of tx 1 and block 3 misses the pvt data of tx 2
+	sampleData := sampleData(t)
+	sampleData[2].Missing = []ledger.MissingPrivateData{{TxId: "tx1", SeqInBlock: 1, Namespace: "ns-1", Collection: "coll-1"}}
+	sampleData[3].Missing = []ledger.MissingPrivateData{{TxId: "tx2", SeqInBlock: 2, Namespace: "ns-1", Collection: "coll-2"}}
+	for _, sampleDatum := range sampleData {
+		assert.NoError(t, store.CommitWithPvtData(sampleDatum))
+	}
+	expectedMissingPvtData := make(ledger.MissingPvtDataInfo)
+	expectedMissingPvtData.Add(2, 1, "ns-1", "coll-1")
+	expectedMissingPvtData.Add(3, 2, "ns-1", "coll-2")
+	missingPvtData, err := store.GetMissingPvtDataInfoForMostRecentBlocks(10)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedMissingPvtData, missingPvtData)
+
+	// commit the missing pvt data of block 3
+	pvtData := samplePvtData(t, []uint64{2})
+	pvtData[2].WriteSet.NsPvtRwset[0].CollectionPvtRwset = pvtData[2].WriteSet.NsPvtRwset[0].CollectionPvtRwset[1:]
+	assert.NoError(t, store.CommitPvtDataOfOldBlocks(map[uint64][]*ledger.TxPvtData{3: {pvtData[2]}}))
+	delete(expectedMissingPvtData, 3)
+	missingPvtData, err = store.GetMissingPvtDataInfoForMostRecentBlocks(10)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedMissingPvtData, missingPvtData)
+	blockPvtData, err := store.GetPvtDataByNum(3, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{2, 4, 6}, []uint64{blockPvtData[0].SeqInBlock, blockPvtData[1].SeqInBlock, blockPvtData[2].SeqInBlock})
+	assert.Equal(t, pvtData[2], blockPvtData[0])
+}
+
 func TestStoreWithExistingBlockchain(t *testing.T) {
 	testLedgerid := "test-ledger"
 	testEnv := newTestEnv(t)
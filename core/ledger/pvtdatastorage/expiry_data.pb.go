@@ -46,7 +46,8 @@ func (m *ExpiryData) GetMap() map[string]*Collections {
 }
 
 type Collections struct {
-	Map map[string]*TxNums `protobuf:"bytes,1,rep,name=map" json:"map,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Map            map[string]*TxNums `protobuf:"bytes,1,rep,name=map" json:"map,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	MissingDataMap map[string]*TxNums `protobuf:"bytes,2,rep,name=missingDataMap" json:"missingDataMap,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 }
 
 func (m *Collections) Reset()                    { *m = Collections{} }
@@ -61,6 +62,13 @@ func (m *Collections) GetMap() map[string]*TxNums {
 	return nil
 }
 
+func (m *Collections) GetMissingDataMap() map[string]*TxNums {
+	if m != nil {
+		return m.MissingDataMap
+	}
+	return nil
+}
+
 type TxNums struct {
 	List []uint64 `protobuf:"varint,1,rep,packed,name=list" json:"list,omitempty"`
 }
@@ -86,22 +94,24 @@ func init() {
 func init() { proto.RegisterFile("expiry_data.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 263 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x4c, 0xad, 0x28, 0xc8,
-	0x2c, 0xaa, 0x8c, 0x4f, 0x49, 0x2c, 0x49, 0xd4, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0xe2, 0x2b,
-	0x28, 0x2b, 0x01, 0x71, 0x8b, 0x4b, 0xf2, 0x8b, 0x12, 0xd3, 0x53, 0x95, 0x66, 0x30, 0x72, 0x71,
-	0xb9, 0x82, 0x55, 0xb9, 0x24, 0x96, 0x24, 0x0a, 0x99, 0x72, 0x31, 0xe7, 0x26, 0x16, 0x48, 0x30,
-	0x2a, 0x30, 0x6b, 0x70, 0x1b, 0x29, 0xeb, 0xa1, 0x2a, 0xd6, 0x43, 0x28, 0xd4, 0xf3, 0x4d, 0x2c,
-	0x70, 0xcd, 0x2b, 0x29, 0xaa, 0x0c, 0x02, 0xa9, 0x97, 0x0a, 0xe6, 0xe2, 0x80, 0x09, 0x08, 0x09,
-	0x70, 0x31, 0x67, 0xa7, 0x56, 0x4a, 0x30, 0x2a, 0x30, 0x6a, 0x70, 0x06, 0x81, 0x98, 0x42, 0x86,
-	0x5c, 0xac, 0x65, 0x89, 0x39, 0xa5, 0xa9, 0x12, 0x4c, 0x0a, 0x8c, 0x1a, 0xdc, 0x46, 0xd2, 0xe8,
-	0xc6, 0x3a, 0xe7, 0xe7, 0xe4, 0xa4, 0x26, 0x97, 0x64, 0xe6, 0xe7, 0x15, 0x07, 0x41, 0x54, 0x5a,
-	0x31, 0x59, 0x30, 0x2a, 0x4d, 0x65, 0xe4, 0xe2, 0x46, 0x92, 0x12, 0x32, 0x43, 0x76, 0x9b, 0x0a,
-	0x1e, 0x43, 0xd0, 0x1c, 0xe7, 0x87, 0xd7, 0x71, 0x3a, 0xa8, 0x8e, 0x13, 0x43, 0x37, 0x37, 0xa4,
-	0xc2, 0xaf, 0x34, 0x17, 0xc5, 0x5d, 0x32, 0x5c, 0x6c, 0x10, 0x41, 0x21, 0x21, 0x2e, 0x96, 0x9c,
-	0xcc, 0xe2, 0x12, 0xb0, 0x93, 0x58, 0x82, 0xc0, 0x6c, 0x27, 0xab, 0x28, 0x8b, 0xf4, 0xcc, 0x92,
-	0x8c, 0xd2, 0x24, 0xbd, 0xe4, 0xfc, 0x5c, 0xfd, 0x8c, 0xca, 0x82, 0xd4, 0xa2, 0x9c, 0xd4, 0x94,
-	0xf4, 0xd4, 0x22, 0xfd, 0xb4, 0xc4, 0xa4, 0xa2, 0xcc, 0x64, 0xfd, 0xe4, 0xfc, 0xa2, 0x54, 0x7d,
-	0xa8, 0x10, 0xaa, 0x5d, 0x49, 0x6c, 0xe0, 0x38, 0x32, 0x06, 0x04, 0x00, 0x00, 0xff, 0xff, 0xb7,
-	0xd4, 0xf8, 0x9d, 0xb8, 0x01, 0x00, 0x00,
+	// 301 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x92, 0x41, 0x4b, 0xbc, 0x40,
+	0x18, 0xc6, 0x19, 0xdd, 0xff, 0xf2, 0xef, 0x15, 0x96, 0x9a, 0x20, 0xc4, 0x3a, 0x88, 0x75, 0xf0,
+	0x10, 0x4a, 0x1b, 0xc5, 0xb2, 0xc7, 0x6a, 0x8f, 0xbb, 0x07, 0x0b, 0xa2, 0x2e, 0x31, 0xba, 0x93,
+	0x3b, 0xa4, 0xce, 0x30, 0x8e, 0xcb, 0xfa, 0x4d, 0xfa, 0x4a, 0x7d, 0xab, 0x50, 0x8b, 0x54, 0xc2,
+	0x53, 0xb7, 0xd7, 0xd7, 0x67, 0x7e, 0xcf, 0xf3, 0x0c, 0x03, 0x07, 0x74, 0x27, 0x98, 0x2c, 0x5f,
+	0xd6, 0x44, 0x11, 0x4f, 0x48, 0xae, 0x38, 0x9e, 0x88, 0xad, 0xaa, 0x3e, 0x73, 0xc5, 0x25, 0x89,
+	0xa9, 0xf3, 0x8e, 0x00, 0x16, 0xb5, 0xea, 0x8e, 0x28, 0x82, 0xaf, 0x40, 0x4f, 0x89, 0x30, 0x91,
+	0xad, 0xbb, 0xc6, 0xf4, 0xd4, 0xeb, 0x8a, 0xbd, 0x1f, 0xa1, 0xb7, 0x24, 0x62, 0x91, 0x29, 0x59,
+	0x06, 0x95, 0xde, 0xba, 0x87, 0xff, 0xdf, 0x0b, 0xbc, 0x0f, 0xfa, 0x1b, 0x2d, 0x4d, 0x64, 0x23,
+	0x77, 0x2f, 0xa8, 0x46, 0x7c, 0x01, 0xff, 0xb6, 0x24, 0x29, 0xa8, 0xa9, 0xd9, 0xc8, 0x35, 0xa6,
+	0xc7, 0x7d, 0xec, 0x2d, 0x4f, 0x12, 0x1a, 0x29, 0xc6, 0xb3, 0x3c, 0x68, 0x94, 0x73, 0x6d, 0x86,
+	0x9c, 0x0f, 0x0d, 0x8c, 0xd6, 0x2f, 0x7c, 0xdd, 0xce, 0x76, 0x36, 0x00, 0xe9, 0x86, 0xc3, 0x8f,
+	0x30, 0x49, 0x59, 0x9e, 0xb3, 0x2c, 0xae, 0x92, 0x2f, 0x89, 0x30, 0xb5, 0x1a, 0xe1, 0x0f, 0x22,
+	0x3a, 0x27, 0x1a, 0x5a, 0x0f, 0x63, 0xad, 0x06, 0x5b, 0x9f, 0x77, 0x5b, 0x1f, 0xf5, 0xdd, 0x1e,
+	0x76, 0xab, 0x22, 0x6d, 0x17, 0xb6, 0x9e, 0xe0, 0xf0, 0x17, 0xdb, 0xbf, 0x40, 0x3b, 0x27, 0x30,
+	0x6e, 0x96, 0x18, 0xc3, 0x28, 0x61, 0xb9, 0xaa, 0xaf, 0x71, 0x14, 0xd4, 0xf3, 0xcd, 0xfc, 0x79,
+	0x16, 0x33, 0xb5, 0x29, 0x42, 0x2f, 0xe2, 0xa9, 0xbf, 0x29, 0x05, 0x95, 0x09, 0x5d, 0xc7, 0x54,
+	0xfa, 0xaf, 0x24, 0x94, 0x2c, 0xf2, 0x23, 0x2e, 0xa9, 0xff, 0xb5, 0xea, 0x7a, 0x85, 0xe3, 0xfa,
+	0x5d, 0x5d, 0x7e, 0x0e, 0x00, 0xd6, 0x32, 0x33, 0x02, 0x6c, 0x02, 0x00, 0x00,
 }
@@ -11,19 +11,39 @@ func newExpiryData() *ExpiryData {
 }
 
 func newCollections() *Collections {
-	return &Collections{make(map[string]*TxNums)}
+	return &Collections{make(map[string]*TxNums), make(map[string]*TxNums)}
 }
 
 func (e *ExpiryData) add(ns, coll string, txNum uint64) {
+	collections := e.getOrCreateCollections(ns)
+	if collections.Map == nil {
+		collections.Map = make(map[string]*TxNums)
+	}
+	addTxNum(collections.Map, coll, txNum)
+}
+
+func (e *ExpiryData) addMissingData(ns, coll string, txNum uint64) {
+	collections := e.getOrCreateCollections(ns)
+	if collections.MissingDataMap == nil {
+		collections.MissingDataMap = make(map[string]*TxNums)
+	}
+	addTxNum(collections.MissingDataMap, coll, txNum)
+}
+
+func (e *ExpiryData) getOrCreateCollections(ns string) *Collections {
 	collections, ok := e.Map[ns]
 	if !ok {
 		collections = newCollections()
 		e.Map[ns] = collections
 	}
-	txNums, ok := collections.Map[coll]
+	return collections
+}
+
+func addTxNum(txNumsByColl map[string]*TxNums, coll string, txNum uint64) {
+	txNums, ok := txNumsByColl[coll]
 	if !ok {
 		txNums = &TxNums{}
-		collections.Map[coll] = txNums
+		txNumsByColl[coll] = txNums
 	}
 	txNums.List = append(txNums.List, txNum)
 }
@@ -14,13 +14,15 @@ import (
 	"github.com/hyperledger/fabric/protos/ledger/rwset"
 )
 
-func prepareStoreEntries(blockNum uint64, pvtdata []*ledger.TxPvtData, btlPolicy pvtdatapolicy.BTLPolicy) ([]*dataEntry, []*expiryEntry, error) {
+func prepareStoreEntries(blockNum uint64, pvtdata []*ledger.TxPvtData, btlPolicy pvtdatapolicy.BTLPolicy,
+	missingPvtData ledger.MissingBlockPvtdataInfo) ([]*dataEntry, []*expiryEntry, []*missingDataKey, error) {
 	dataEntries := prepareDataEntries(blockNum, pvtdata)
-	expiryEntries, err := prepareExpiryEntries(blockNum, dataEntries, btlPolicy)
+	missingDataKeys := prepareMissingDataKeys(blockNum, missingPvtData)
+	expiryEntries, err := prepareExpiryEntries(blockNum, dataEntries, missingDataKeys, btlPolicy)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
-	return dataEntries, expiryEntries, nil
+	return dataEntries, expiryEntries, missingDataKeys, nil
 }
 
 func prepareDataEntries(blockNum uint64, pvtData []*ledger.TxPvtData) []*dataEntry {
@@ -39,22 +41,48 @@ func prepareDataEntries(blockNum uint64, pvtData []*ledger.TxPvtData) []*dataEnt
 	return dataEntries
 }
 
-func prepareExpiryEntries(committingBlk uint64, dataEntries []*dataEntry, btlPolicy pvtdatapolicy.BTLPolicy) ([]*expiryEntry, error) {
+func prepareMissingDataKeys(blockNum uint64, missingPvtData ledger.MissingBlockPvtdataInfo) []*missingDataKey {
+	var missingDataKeys []*missingDataKey
+	for txNum, missingColls := range missingPvtData {
+		for _, missingColl := range missingColls {
+			missingDataKeys = append(missingDataKeys, &missingDataKey{blockNum, txNum, missingColl.Namespace, missingColl.Collection})
+		}
+	}
+	return missingDataKeys
+}
+
+func prepareExpiryEntries(committingBlk uint64, dataEntries []*dataEntry, missingDataKeys []*missingDataKey,
+	btlPolicy pvtdatapolicy.BTLPolicy) ([]*expiryEntry, error) {
 	mapByExpiringBlk := make(map[uint64]*ExpiryData)
-	for _, dataEntry := range dataEntries {
-		expiringBlk, err := btlPolicy.GetExpiringBlock(dataEntry.key.ns, dataEntry.key.coll, dataEntry.key.blkNum)
-		if err != nil {
+	getExpiryData := func(ns, coll string) (*ExpiryData, error) {
+		expiringBlk, err := btlPolicy.GetExpiringBlock(ns, coll, committingBlk)
+		if err != nil || neverExpires(expiringBlk) {
 			return nil, err
 		}
-		if neverExpires(expiringBlk) {
-			continue
-		}
 		expiryData, ok := mapByExpiringBlk[expiringBlk]
 		if !ok {
 			expiryData = newExpiryData()
 			mapByExpiringBlk[expiringBlk] = expiryData
 		}
-		expiryData.add(dataEntry.key.ns, dataEntry.key.coll, dataEntry.key.txNum)
+		return expiryData, nil
+	}
+	for _, dataEntry := range dataEntries {
+		expiryData, err := getExpiryData(dataEntry.key.ns, dataEntry.key.coll)
+		if err != nil {
+			return nil, err
+		}
+		if expiryData != nil {
+			expiryData.add(dataEntry.key.ns, dataEntry.key.coll, dataEntry.key.txNum)
+		}
+	}
+	for _, missingDataKey := range missingDataKeys {
+		expiryData, err := getExpiryData(missingDataKey.ns, missingDataKey.coll)
+		if err != nil {
+			return nil, err
+		}
+		if expiryData != nil {
+			expiryData.addMissingData(missingDataKey.ns, missingDataKey.coll, missingDataKey.txNum)
+		}
 	}
 	var expiryEntries []*expiryEntry
 	for expiryBlk, expiryData := range mapByExpiringBlk {
@@ -76,12 +104,28 @@ func deriveDataKeys(expiryEntry *expiryEntry) []*dataKey {
 	return dataKeys
 }
 
+func deriveMissingDataKeys(expiryEntry *expiryEntry) []*missingDataKey {
+	var missingDataKeys []*missingDataKey
+	for ns, colls := range expiryEntry.value.Map {
+		for coll, txNums := range colls.MissingDataMap {
+			for _, txNum := range txNums.List {
+				missingDataKeys = append(missingDataKeys, &missingDataKey{expiryEntry.key.committingBlk, txNum, ns, coll})
+			}
+		}
+	}
+	return missingDataKeys
+}
+
 func passesFilter(dataKey *dataKey, filter ledger.PvtNsCollFilter) bool {
 	return filter == nil || filter.Has(dataKey.ns, dataKey.coll)
 }
 
 func isExpired(dataKey *dataKey, btl pvtdatapolicy.BTLPolicy, latestBlkNum uint64) (bool, error) {
-	expiringBlk, err := btl.GetExpiringBlock(dataKey.ns, dataKey.coll, dataKey.blkNum)
+	return isExpiredCollData(dataKey.ns, dataKey.coll, dataKey.blkNum, btl, latestBlkNum)
+}
+
+func isExpiredCollData(ns, coll string, blkNum uint64, btl pvtdatapolicy.BTLPolicy, latestBlkNum uint64) (bool, error) {
+	expiringBlk, err := btl.GetExpiringBlock(ns, coll, blkNum)
 	if err != nil {
 		return false, err
 	}
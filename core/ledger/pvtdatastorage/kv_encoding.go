@@ -8,6 +8,7 @@ package pvtdatastorage
 
 import (
 	"bytes"
+	"math"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
@@ -15,10 +16,11 @@ import (
 )
 
 var (
-	pendingCommitKey    = []byte{0}
-	lastCommittedBlkkey = []byte{1}
-	pvtDataKeyPrefix    = []byte{2}
-	expiryKeyPrefix     = []byte{3}
+	pendingCommitKey     = []byte{0}
+	lastCommittedBlkkey  = []byte{1}
+	pvtDataKeyPrefix     = []byte{2}
+	expiryKeyPrefix      = []byte{3}
+	missingDataKeyPrefix = []byte{4}
 
 	nilByte    = byte(0)
 	emptyValue = []byte{}
@@ -30,12 +32,58 @@ func getDataKeysForRangeScanByBlockNum(blockNum uint64) (startKey, endKey []byte
 	return
 }
 
+func getDataKeysForRangeScanFromBlockNum(blockNum uint64) (startKey, endKey []byte) {
+	startKey = append(pvtDataKeyPrefix, version.NewHeight(blockNum, 0).ToBytes()...)
+	endKey = []byte{pvtDataKeyPrefix[0] + 1}
+	return
+}
+
+func getAllExpiryKeysForRangeScan() (startKey, endKey []byte) {
+	startKey = expiryKeyPrefix
+	endKey = []byte{expiryKeyPrefix[0] + 1}
+	return
+}
+
 func getExpiryKeysForRangeScan(minBlkNum, maxBlkNum uint64) (startKey, endKey []byte) {
 	startKey = append(expiryKeyPrefix, version.NewHeight(minBlkNum, 0).ToBytes()...)
 	endKey = append(expiryKeyPrefix, version.NewHeight(maxBlkNum+1, 0).ToBytes()...)
 	return
 }
 
+func getAllMissingDataKeysForRangeScan() (startKey, endKey []byte) {
+	startKey = missingDataKeyPrefix
+	endKey = []byte{missingDataKeyPrefix[0] + 1}
+	return
+}
+
+// getMissingDataKeysForRangeScanBelowBlockNum returns the range of the missing data keys of the blocks
+// that precede the block `blockNum`. As the block numbers are encoded in the reverse order, these keys
+// follow the missing data keys of the block `blockNum`
+func getMissingDataKeysForRangeScanBelowBlockNum(blockNum uint64) (startKey, endKey []byte) {
+	startKey = append(missingDataKeyPrefix, version.NewHeight(math.MaxUint64-blockNum+1, 0).ToBytes()...)
+	endKey = []byte{missingDataKeyPrefix[0] + 1}
+	return
+}
+
+// getMissingDataKeysForRangeScanAfterBlockNum returns the range of the missing data keys of the blocks
+// that follow the block `blockNum`. As the block numbers are encoded in the reverse order, these keys
+// precede the missing data keys of the block `blockNum`
+func getMissingDataKeysForRangeScanAfterBlockNum(blockNum uint64) (startKey, endKey []byte) {
+	startKey = missingDataKeyPrefix
+	endKey = append(missingDataKeyPrefix, version.NewHeight(math.MaxUint64-blockNum, 0).ToBytes()...)
+	return
+}
+
+func getMissingDataKeysForRangeScanByBlockNum(blockNum uint64) (startKey, endKey []byte) {
+	startKey = append(missingDataKeyPrefix, version.NewHeight(math.MaxUint64-blockNum, 0).ToBytes()...)
+	if blockNum == 0 {
+		endKey = []byte{missingDataKeyPrefix[0] + 1}
+		return
+	}
+	endKey = append(missingDataKeyPrefix, version.NewHeight(math.MaxUint64-blockNum+1, 0).ToBytes()...)
+	return
+}
+
 func encodeLastCommittedBlockVal(blockNum uint64) []byte {
 	return proto.EncodeVarint(blockNum)
 }
@@ -87,6 +135,24 @@ func decodeDatakey(datakeyBytes []byte) *dataKey {
 	return &dataKey{blkNum: blkNum, txNum: tranNum, ns: ns, coll: coll}
 }
 
+func encodeMissingDataKey(key *missingDataKey) []byte {
+	// the block number is encoded in the reverse order so that a range scan
+	// returns the missing data of the most recent blocks first
+	keyBytes := append(missingDataKeyPrefix, version.NewHeight(math.MaxUint64-key.blkNum, key.txNum).ToBytes()...)
+	keyBytes = append(keyBytes, []byte(key.ns)...)
+	keyBytes = append(keyBytes, nilByte)
+	return append(keyBytes, []byte(key.coll)...)
+}
+
+func decodeMissingDataKey(keyBytes []byte) *missingDataKey {
+	v, n := version.NewHeightFromBytes(keyBytes[1:])
+	remainingBytes := keyBytes[n+1:]
+	nilByteIndex := bytes.IndexByte(remainingBytes, nilByte)
+	ns := string(remainingBytes[:nilByteIndex])
+	coll := string(remainingBytes[nilByteIndex+1:])
+	return &missingDataKey{blkNum: math.MaxUint64 - v.BlockNum, txNum: v.TxNum, ns: ns, coll: coll}
+}
+
 func decodeDataValue(datavalueBytes []byte) (*rwset.CollectionPvtReadWriteSet, error) {
 	collPvtdata := &rwset.CollectionPvtReadWriteSet{}
 	err := proto.Unmarshal(datavalueBytes, collPvtdata)
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package pvtdatastorage
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -17,3 +18,13 @@ func TestDataKeyEncoding(t *testing.T) {
 	datakey2 := decodeDatakey(encodeDataKey(dataKey1))
 	assert.Equal(t, dataKey1, datakey2)
 }
+
+func TestMissingDataKeyEncoding(t *testing.T) {
+	missingDataKey1 := &missingDataKey{blkNum: 2, txNum: 5, ns: "ns1", coll: "coll1"}
+	missingDataKey2 := decodeMissingDataKey(encodeMissingDataKey(missingDataKey1))
+	assert.Equal(t, missingDataKey1, missingDataKey2)
+
+	// the keys of the most recent blocks sort first
+	missingDataKey3 := &missingDataKey{blkNum: 3, txNum: 0, ns: "ns1", coll: "coll1"}
+	assert.True(t, bytes.Compare(encodeMissingDataKey(missingDataKey3), encodeMissingDataKey(missingDataKey1)) < 0)
+}
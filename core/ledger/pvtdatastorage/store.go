@@ -15,6 +15,8 @@ import (
 // private write sets for a ledger
 type Provider interface {
 	OpenStore(id string) (Store, error)
+	// Drop drops all the data of the Store with the given id
+	Drop(id string) error
 	Close()
 }
 
@@ -34,7 +36,9 @@ type Store interface {
 	// InitLastCommittedBlockHeight sets the last commited block height into the pvt data store
 	// This function is used in a special case where the peer is started up with the blockchain
 	// from an earlier version of a peer when the pvt data feature (and hence this store) was not
-	// available. This function is expected to be called only this situation and hence is
+	// available. It is also used when a ledger is bootstrapped from a snapshot, in which case the
+	// blocks preceding the snapshot are not present on the peer.
+	// This function is expected to be called only in these situations and hence is
 	// expected to throw an error if the store is not empty. On a successful return from this
 	// fucntion the state of the store is expected to be same as of calling the prepare/commit
 	// function for block `0` through `blockNum` with no pvt data
@@ -48,11 +52,33 @@ type Store interface {
 	// Return from this should ensure that enough preparation is done such that `Commit` function invoked afterwards
 	// can commit the data and the store is capable of surviving a crash between this function call and the next
 	// invoke to the `Commit`
-	Prepare(blockNum uint64, pvtData []*ledger.TxPvtData) error
+	// The parameter `missingPvtData` contains the information about the private data that the peer is eligible
+	// for but is not available at the time of the commit. This information is persisted along with the block so
+	// that the missing private data can be retrieved later and committed via function `CommitPvtDataOfOldBlocks`
+	Prepare(blockNum uint64, pvtData []*ledger.TxPvtData, missingPvtData ledger.MissingBlockPvtdataInfo) error
 	// Commit commits the pvt data passed in the previous invoke to the `Prepare` function
 	Commit() error
 	// Rollback rolls back the pvt data passed in the previous invoke to the `Prepare` function
 	Rollback() error
+	// RollbackToBlock removes the pvt data committed with the blocks that follow the block `blockNum`
+	// and sets `blockNum` as the last committed block. This function is expected to be used for rolling
+	// back a ledger while the peer is not running and hence throws an error if a pending batch exists
+	RollbackToBlock(blockNum uint64) error
+	// GetMissingPvtDataInfoForMostRecentBlocks returns the information about the missing private data of at
+	// most `maxBlocks` most recent blocks that have missing private data. The missing private data that has
+	// already expired is not included
+	GetMissingPvtDataInfoForMostRecentBlocks(maxBlocks int) (ledger.MissingPvtDataInfo, error)
+	// GetMissingPvtDataInfoForMostRecentBlocksBelow returns the information about the missing private data of
+	// at most `maxBlocks` most recent blocks, among the blocks that precede the block `blockNum`, that have
+	// missing private data. The missing private data that has already expired is not included
+	GetMissingPvtDataInfoForMostRecentBlocksBelow(blockNum uint64, maxBlocks int) (ledger.MissingPvtDataInfo, error)
+	// CommitPvtDataOfOldBlocks commits the pvt data of already committed blocks. This is expected to be used for
+	// the pvt data that was missing when the blocks were committed. The missing data entries of the supplied pvt data
+	// are removed and the pvt data that has already expired is ignored
+	CommitPvtDataOfOldBlocks(blocksPvtData map[uint64][]*ledger.TxPvtData) error
+	// RemoveMissingPvtDataOfBlocks removes the missing data entries of the given blocks. This is expected to be used
+	// for the blocks whose missing pvt data cannot be committed anymore, e.g., because the blocks have been pruned
+	RemoveMissingPvtDataOfBlocks(blkNums []uint64) error
 	// IsEmpty returns true if the store does not have any block committed yet
 	IsEmpty() (bool, error)
 	// LastCommittedBlockHeight returns the height of the last committed block
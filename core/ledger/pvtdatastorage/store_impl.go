@@ -58,6 +58,12 @@ type dataKey struct {
 	ns, coll string
 }
 
+type missingDataKey struct {
+	blkNum   uint64
+	txNum    uint64
+	ns, coll string
+}
+
 //////// Provider functions  /////////////
 //////////////////////////////////////////
 
@@ -78,6 +84,11 @@ func (p *provider) OpenStore(ledgerid string) (Store, error) {
 	return s, nil
 }
 
+// Drop deletes all the data of the store with the given id
+func (p *provider) Drop(ledgerid string) error {
+	return p.dbProvider.GetDBHandle(ledgerid).DeleteAll()
+}
+
 // Close closes the store
 func (p *provider) Close() {
 	p.dbProvider.Close()
@@ -102,7 +113,7 @@ func (s *store) Init(btlPolicy pvtdatapolicy.BTLPolicy) {
 }
 
 // Prepare implements the function in the interface `Store`
-func (s *store) Prepare(blockNum uint64, pvtData []*ledger.TxPvtData) error {
+func (s *store) Prepare(blockNum uint64, pvtData []*ledger.TxPvtData, missingPvtData ledger.MissingBlockPvtdataInfo) error {
 	if s.batchPending {
 		return &ErrIllegalCall{`A pending batch exists as as result of last invoke to "Prepare" call.
 			 Invoke "Commit" or "Rollback" on the pending batch before invoking "Prepare" function`}
@@ -113,9 +124,17 @@ func (s *store) Prepare(blockNum uint64, pvtData []*ledger.TxPvtData) error {
 	}
 
 	batch := leveldbhelper.NewUpdateBatch()
+	// the missing data entries left over by an earlier attempt to commit this block are not relevant anymore
+	startKey, endKey := getMissingDataKeysForRangeScanByBlockNum(blockNum)
+	itr := s.db.GetIterator(startKey, endKey)
+	for itr.Next() {
+		batch.Delete(itr.Key())
+	}
+	itr.Release()
+
 	var err error
 	var keyBytes, valBytes []byte
-	dataEntries, expiryEntries, err := prepareStoreEntries(blockNum, pvtData, s.btlPolicy)
+	dataEntries, expiryEntries, missingDataKeys, err := prepareStoreEntries(blockNum, pvtData, s.btlPolicy, missingPvtData)
 	if err != nil {
 		return err
 	}
@@ -133,12 +152,16 @@ func (s *store) Prepare(blockNum uint64, pvtData []*ledger.TxPvtData) error {
 		}
 		batch.Put(keyBytes, valBytes)
 	}
+	for _, missingDataKey := range missingDataKeys {
+		batch.Put(encodeMissingDataKey(missingDataKey), emptyValue)
+	}
 	batch.Put(pendingCommitKey, emptyValue)
 	if err := s.db.WriteBatch(batch, true); err != nil {
 		return err
 	}
 	s.batchPending = true
-	logger.Debugf("Saved %d private data write sets for block [%d]", len(pvtData), blockNum)
+	logger.Debugf("Saved %d private data write sets and %d missing data entries for block [%d]",
+		len(pvtData), len(missingDataKeys), blockNum)
 	return nil
 }
 
@@ -174,6 +197,54 @@ func (s *store) Rollback() error {
 	return nil
 }
 
+// RollbackToBlock implements the function in the interface `Store`
+func (s *store) RollbackToBlock(blockNum uint64) error {
+	if s.batchPending {
+		return &ErrIllegalCall{`A pending batch exists as as result of last invoke to "Prepare" call.
+			 Invoke "Commit" or "Rollback" on the pending batch before invoking "RollbackToBlock" function`}
+	}
+	if s.isEmpty || s.lastCommittedBlock <= blockNum {
+		logger.Debugf("Nothing to rollback, the last committed block [%d] does not follow block [%d]", s.lastCommittedBlock, blockNum)
+		return nil
+	}
+	s.purgerLock.Lock()
+	defer s.purgerLock.Unlock()
+
+	batch := leveldbhelper.NewUpdateBatch()
+	startKey, endKey := getDataKeysForRangeScanFromBlockNum(blockNum + 1)
+	dataItr := s.db.GetIterator(startKey, endKey)
+	for dataItr.Next() {
+		batch.Delete(dataItr.Key())
+	}
+	dataItr.Release()
+
+	startKey, endKey = getAllExpiryKeysForRangeScan()
+	expiryItr := s.db.GetIterator(startKey, endKey)
+	for expiryItr.Next() {
+		if decodeExpiryKey(expiryItr.Key()).committingBlk > blockNum {
+			batch.Delete(expiryItr.Key())
+		}
+	}
+	expiryItr.Release()
+
+	startKey, endKey = getMissingDataKeysForRangeScanAfterBlockNum(blockNum)
+	missingDataItr := s.db.GetIterator(startKey, endKey)
+	for missingDataItr.Next() {
+		batch.Delete(missingDataItr.Key())
+	}
+	missingDataItr.Release()
+
+	// a pending commit marker left over by an earlier call to `Rollback` is not relevant anymore
+	batch.Delete(pendingCommitKey)
+	batch.Put(lastCommittedBlkkey, encodeLastCommittedBlockVal(blockNum))
+	if err := s.db.WriteBatch(batch, true); err != nil {
+		return err
+	}
+	logger.Infof("Rolled back private data from block [%d] to block [%d]", s.lastCommittedBlock, blockNum)
+	s.lastCommittedBlock = blockNum
+	return nil
+}
+
 // GetPvtDataByBlockNum implements the function in the interface `Store`.
 // If the store is empty or the last committed block number is smaller then the
 // requested block number, an 'ErrOutOfRange' is thrown
@@ -230,6 +301,158 @@ func (s *store) GetPvtDataByBlockNum(blockNum uint64, filter ledger.PvtNsCollFil
 	return blockPvtdata, nil
 }
 
+// GetMissingPvtDataInfoForMostRecentBlocks implements the function in the interface `Store`
+func (s *store) GetMissingPvtDataInfoForMostRecentBlocks(maxBlocks int) (ledger.MissingPvtDataInfo, error) {
+	startKey, endKey := getAllMissingDataKeysForRangeScan()
+	return s.getMissingPvtDataInfo(startKey, endKey, maxBlocks)
+}
+
+// GetMissingPvtDataInfoForMostRecentBlocksBelow implements the function in the interface `Store`
+func (s *store) GetMissingPvtDataInfoForMostRecentBlocksBelow(blockNum uint64, maxBlocks int) (ledger.MissingPvtDataInfo, error) {
+	if blockNum == 0 {
+		return nil, nil
+	}
+	startKey, endKey := getMissingDataKeysForRangeScanBelowBlockNum(blockNum)
+	return s.getMissingPvtDataInfo(startKey, endKey, maxBlocks)
+}
+
+// getMissingPvtDataInfo returns the missing private data of at most `maxBlocks` blocks
+// listed by the missing data keys in the range [startKey, endKey)
+func (s *store) getMissingPvtDataInfo(startKey, endKey []byte, maxBlocks int) (ledger.MissingPvtDataInfo, error) {
+	if s.isEmpty || maxBlocks <= 0 {
+		return nil, nil
+	}
+	missingPvtDataInfo := make(ledger.MissingPvtDataInfo)
+	itr := s.db.GetIterator(startKey, endKey)
+	defer itr.Release()
+
+	for itr.Next() {
+		missingDataKey := decodeMissingDataKey(itr.Key())
+		if missingDataKey.blkNum > s.lastCommittedBlock {
+			// the entries of a block that has been prepared but not committed
+			continue
+		}
+		if _, ok := missingPvtDataInfo[missingDataKey.blkNum]; !ok && len(missingPvtDataInfo) == maxBlocks {
+			break
+		}
+		expired, err := isExpiredCollData(missingDataKey.ns, missingDataKey.coll, missingDataKey.blkNum, s.btlPolicy, s.lastCommittedBlock)
+		if err != nil {
+			return nil, err
+		}
+		if expired {
+			continue
+		}
+		missingPvtDataInfo.Add(missingDataKey.blkNum, missingDataKey.txNum, missingDataKey.ns, missingDataKey.coll)
+	}
+	return missingPvtDataInfo, nil
+}
+
+// CommitPvtDataOfOldBlocks implements the function in the interface `Store`
+func (s *store) CommitPvtDataOfOldBlocks(blocksPvtData map[uint64][]*ledger.TxPvtData) error {
+	if s.batchPending {
+		return &ErrIllegalCall{`A pending batch exists as as result of last invoke to "Prepare" call.
+			 Invoke "Commit" or "Rollback" on the pending batch before invoking "CommitPvtDataOfOldBlocks" function`}
+	}
+	for blkNum := range blocksPvtData {
+		if s.isEmpty || blkNum > s.lastCommittedBlock {
+			return &ErrIllegalArgs{fmt.Sprintf("Last committed block=%d, block requested=%d", s.lastCommittedBlock, blkNum)}
+		}
+	}
+	s.purgerLock.Lock()
+	defer s.purgerLock.Unlock()
+
+	batch := leveldbhelper.NewUpdateBatch()
+	expiryEntries := make(map[expiryKey]*ExpiryData)
+	for blkNum, pvtData := range blocksPvtData {
+		for _, dataEntry := range prepareDataEntries(blkNum, pvtData) {
+			key := dataEntry.key
+			batch.Delete(encodeMissingDataKey(&missingDataKey{key.blkNum, key.txNum, key.ns, key.coll}))
+			expired, err := isExpired(key, s.btlPolicy, s.lastCommittedBlock)
+			if err != nil {
+				return err
+			}
+			if expired {
+				continue
+			}
+			valBytes, err := encodeDataValue(dataEntry.value)
+			if err != nil {
+				return err
+			}
+			batch.Put(encodeDataKey(key), valBytes)
+			if err := s.addToExpiryEntries(expiryEntries, key); err != nil {
+				return err
+			}
+		}
+	}
+	for key, expiryData := range expiryEntries {
+		key := key
+		valBytes, err := encodeExpiryValue(expiryData)
+		if err != nil {
+			return err
+		}
+		batch.Put(encodeExpiryKey(&key), valBytes)
+	}
+	if err := s.db.WriteBatch(batch, true); err != nil {
+		return err
+	}
+	logger.Debugf("Committed private data of %d old blocks", len(blocksPvtData))
+	return nil
+}
+
+// RemoveMissingPvtDataOfBlocks implements the function in the interface `Store`
+func (s *store) RemoveMissingPvtDataOfBlocks(blkNums []uint64) error {
+	if s.batchPending {
+		return &ErrIllegalCall{`A pending batch exists as as result of last invoke to "Prepare" call.
+			 Invoke "Commit" or "Rollback" on the pending batch before invoking "RemoveMissingPvtDataOfBlocks" function`}
+	}
+	s.purgerLock.Lock()
+	defer s.purgerLock.Unlock()
+
+	batch := leveldbhelper.NewUpdateBatch()
+	for _, blkNum := range blkNums {
+		startKey, endKey := getMissingDataKeysForRangeScanByBlockNum(blkNum)
+		itr := s.db.GetIterator(startKey, endKey)
+		for itr.Next() {
+			batch.Delete(itr.Key())
+		}
+		itr.Release()
+	}
+	if err := s.db.WriteBatch(batch, true); err != nil {
+		return err
+	}
+	logger.Debugf("Removed the missing private data entries of blocks %v", blkNums)
+	return nil
+}
+
+// addToExpiryEntries adds the given data key to the expiry data of the block in which the data key expires.
+// As the expiry entries of an old block may already exist, for instance for the missing data of the block,
+// the expiry data is loaded from the db when it is not present in the given map
+func (s *store) addToExpiryEntries(expiryEntries map[expiryKey]*ExpiryData, key *dataKey) error {
+	expiringBlk, err := s.btlPolicy.GetExpiringBlock(key.ns, key.coll, key.blkNum)
+	if err != nil || neverExpires(expiringBlk) {
+		return err
+	}
+	expKey := expiryKey{expiringBlk: expiringBlk, committingBlk: key.blkNum}
+	expiryData, ok := expiryEntries[expKey]
+	if !ok {
+		valBytes, err := s.db.Get(encodeExpiryKey(&expKey))
+		if err != nil {
+			return err
+		}
+		if valBytes == nil {
+			expiryData = newExpiryData()
+		} else if expiryData, err = decodeExpiryValue(valBytes); err != nil {
+			return err
+		}
+		if expiryData.Map == nil {
+			expiryData.Map = make(map[string]*Collections)
+		}
+		expiryEntries[expKey] = expiryData
+	}
+	expiryData.add(key.ns, key.coll, key.txNum)
+	return nil
+}
+
 // InitLastCommittedBlock implements the function in the interface `Store`
 func (s *store) InitLastCommittedBlock(blockNum uint64) error {
 	if !(s.isEmpty && !s.batchPending) {
@@ -275,6 +498,9 @@ func (s *store) purgeExpiredData(minBlkNum, maxBlkNum uint64) error {
 		for _, dataKey := range deriveDataKeys(expiryEntry) {
 			batch.Delete(encodeDataKey(dataKey))
 		}
+		for _, missingDataKey := range deriveMissingDataKeys(expiryEntry) {
+			batch.Delete(encodeMissingDataKey(missingDataKey))
+		}
 		s.db.WriteBatch(batch, false)
 	}
 	logger.Debugf("[%d] Entries purged from private data storage", len(expiryEntries))
@@ -56,15 +56,15 @@ func TestStoreBasicCommitAndRetrieval(t *testing.T) {
 	}
 
 	// no pvt data with block 0
-	assert.NoError(store.Prepare(0, nil))
+	assert.NoError(store.Prepare(0, nil, nil))
 	assert.NoError(store.Commit())
 
 	// pvt data with block 1 - commit
-	assert.NoError(store.Prepare(1, testData))
+	assert.NoError(store.Prepare(1, testData, nil))
 	assert.NoError(store.Commit())
 
 	// pvt data with block 2 - rollback
-	assert.NoError(store.Prepare(2, testData))
+	assert.NoError(store.Prepare(2, testData, nil))
 	assert.NoError(store.Rollback())
 
 	// pvt data retrieval for block 0 should return nil
@@ -111,7 +111,7 @@ func TestExpiryDataNotIncluded(t *testing.T) {
 	store := env.TestStore
 
 	// no pvt data with block 0
-	assert.NoError(store.Prepare(0, nil))
+	assert.NoError(store.Prepare(0, nil, nil))
 	assert.NoError(store.Commit())
 
 	// write pvt data for block 1
@@ -119,7 +119,7 @@ func TestExpiryDataNotIncluded(t *testing.T) {
 		produceSamplePvtdata(t, 2, []string{"ns-1:coll-1", "ns-1:coll-2", "ns-2:coll-1", "ns-2:coll-2"}),
 		produceSamplePvtdata(t, 4, []string{"ns-1:coll-1", "ns-1:coll-2", "ns-2:coll-1", "ns-2:coll-2"}),
 	}
-	assert.NoError(store.Prepare(1, testDataForBlk1))
+	assert.NoError(store.Prepare(1, testDataForBlk1, nil))
 	assert.NoError(store.Commit())
 
 	// write pvt data for block 2
@@ -127,7 +127,7 @@ func TestExpiryDataNotIncluded(t *testing.T) {
 		produceSamplePvtdata(t, 3, []string{"ns-1:coll-1", "ns-1:coll-2", "ns-2:coll-1", "ns-2:coll-2"}),
 		produceSamplePvtdata(t, 5, []string{"ns-1:coll-1", "ns-1:coll-2", "ns-2:coll-1", "ns-2:coll-2"}),
 	}
-	assert.NoError(store.Prepare(2, testDataForBlk2))
+	assert.NoError(store.Prepare(2, testDataForBlk2, nil))
 	assert.NoError(store.Commit())
 
 	retrievedData, _ := store.GetPvtDataByBlockNum(1, nil)
@@ -135,7 +135,7 @@ func TestExpiryDataNotIncluded(t *testing.T) {
 	testutil.AssertEquals(t, retrievedData, testDataForBlk1)
 
 	// Commit block 3 with no pvtdata
-	assert.NoError(store.Prepare(3, nil))
+	assert.NoError(store.Prepare(3, nil, nil))
 	assert.NoError(store.Commit())
 
 	// After committing block 3, the data for "ns-1:coll1" of block 1 should have expired and should not be returned by the store
@@ -147,7 +147,7 @@ func TestExpiryDataNotIncluded(t *testing.T) {
 	testutil.AssertEquals(t, retrievedData, expectedPvtdataFromBlock1)
 
 	// Commit block 4 with no pvtdata
-	assert.NoError(store.Prepare(4, nil))
+	assert.NoError(store.Prepare(4, nil, nil))
 	assert.NoError(store.Commit())
 
 	// After committing block 4, the data for "ns-2:coll2" of block 1 should also have expired and should not be returned by the store
@@ -183,7 +183,7 @@ func TestStorePurge(t *testing.T) {
 	s := env.TestStore
 
 	// no pvt data with block 0
-	assert.NoError(s.Prepare(0, nil))
+	assert.NoError(s.Prepare(0, nil, nil))
 	assert.NoError(s.Commit())
 
 	// write pvt data for block 1
@@ -191,11 +191,11 @@ func TestStorePurge(t *testing.T) {
 		produceSamplePvtdata(t, 2, []string{"ns-1:coll-1", "ns-1:coll-2", "ns-2:coll-1", "ns-2:coll-2"}),
 		produceSamplePvtdata(t, 4, []string{"ns-1:coll-1", "ns-1:coll-2", "ns-2:coll-1", "ns-2:coll-2"}),
 	}
-	assert.NoError(s.Prepare(1, testDataForBlk1))
+	assert.NoError(s.Prepare(1, testDataForBlk1, nil))
 	assert.NoError(s.Commit())
 
 	// write pvt data for block 2
-	assert.NoError(s.Prepare(2, nil))
+	assert.NoError(s.Prepare(2, nil, nil))
 	assert.NoError(s.Commit())
 	// data for ns-1:coll-1 and ns-2:coll-2 should exist in store
 	testWaitForPurgerRoutineToFinish(s)
@@ -203,7 +203,7 @@ func TestStorePurge(t *testing.T) {
 	assert.True(testDataKeyExists(t, s, &dataKey{blkNum: 1, txNum: 2, ns: "ns-2", coll: "coll-2"}))
 
 	// write pvt data for block 3
-	assert.NoError(s.Prepare(3, nil))
+	assert.NoError(s.Prepare(3, nil, nil))
 	assert.NoError(s.Commit())
 	// data for ns-1:coll-1 and ns-2:coll-2 should exist in store (because purger should not be launched at block 3)
 	testWaitForPurgerRoutineToFinish(s)
@@ -211,7 +211,7 @@ func TestStorePurge(t *testing.T) {
 	assert.True(testDataKeyExists(t, s, &dataKey{blkNum: 1, txNum: 2, ns: "ns-2", coll: "coll-2"}))
 
 	// write pvt data for block 4
-	assert.NoError(s.Prepare(4, nil))
+	assert.NoError(s.Prepare(4, nil, nil))
 	assert.NoError(s.Commit())
 	// data for ns-1:coll-1 should not exist in store (because purger should be launched at block 4) but ns-2:coll-2 should exist because it
 	// expires at block 5
@@ -220,7 +220,7 @@ func TestStorePurge(t *testing.T) {
 	assert.True(testDataKeyExists(t, s, &dataKey{blkNum: 1, txNum: 2, ns: "ns-2", coll: "coll-2"}))
 
 	// write pvt data for block 5
-	assert.NoError(s.Prepare(5, nil))
+	assert.NoError(s.Prepare(5, nil, nil))
 	assert.NoError(s.Commit())
 	// ns-2:coll-2 should exist because though the data expires at block 5 but purger is launched every second block
 	testWaitForPurgerRoutineToFinish(s)
@@ -228,7 +228,7 @@ func TestStorePurge(t *testing.T) {
 	assert.True(testDataKeyExists(t, s, &dataKey{blkNum: 1, txNum: 2, ns: "ns-2", coll: "coll-2"}))
 
 	// write pvt data for block 6
-	assert.NoError(s.Prepare(6, nil))
+	assert.NoError(s.Prepare(6, nil, nil))
 	assert.NoError(s.Commit())
 	// ns-2:coll-2 should not exists now (because purger should be launched at block 6)
 	testWaitForPurgerRoutineToFinish(s)
@@ -252,14 +252,14 @@ func TestStoreState(t *testing.T) {
 	testData := []*ledger.TxPvtData{
 		produceSamplePvtdata(t, 0, []string{"ns-1:coll-1", "ns-1:coll-2"}),
 	}
-	_, ok := store.Prepare(1, testData).(*ErrIllegalArgs)
+	_, ok := store.Prepare(1, testData, nil).(*ErrIllegalArgs)
 	assert.True(ok)
 
-	assert.Nil(store.Prepare(0, testData))
+	assert.Nil(store.Prepare(0, testData, nil))
 	assert.NoError(store.Commit())
 
-	assert.Nil(store.Prepare(1, testData))
-	_, ok = store.Prepare(2, testData).(*ErrIllegalCall)
+	assert.Nil(store.Prepare(1, testData, nil))
+	_, ok = store.Prepare(2, testData, nil).(*ErrIllegalCall)
 	assert.True(ok)
 }
 
@@ -285,6 +285,200 @@ func TestInitLastCommittedBlock(t *testing.T) {
 	assert.True(ok)
 }
 
+func TestProviderDrop(t *testing.T) {
+	env := NewTestStoreEnv(t, "TestProviderDrop", nil)
+	defer env.Cleanup()
+	assert := assert.New(t)
+	assert.NoError(env.TestStore.InitLastCommittedBlock(25))
+	testEmpty(false, assert, env.TestStore)
+
+	assert.NoError(env.TestStoreProvider.Drop("TestProviderDrop"))
+	env.CloseAndReopen()
+	testEmpty(true, assert, env.TestStore)
+	testLastCommittedBlockHeight(0, assert, env.TestStore)
+}
+
+func TestStoreRollbackToBlock(t *testing.T) {
+	cs := btltestutil.NewMockCollectionStore()
+	cs.SetBTL("ns-1", "coll-1", 5)
+	cs.SetBTL("ns-1", "coll-2", 0)
+	btlPolicy := pvtdatapolicy.ConstructBTLPolicy(cs)
+	env := NewTestStoreEnv(t, "TestStoreRollbackToBlock", btlPolicy)
+	defer env.Cleanup()
+	assert := assert.New(t)
+	s := env.TestStore
+
+	testData := []*ledger.TxPvtData{
+		produceSamplePvtdata(t, 2, []string{"ns-1:coll-1", "ns-1:coll-2"}),
+	}
+	for blkNum := uint64(0); blkNum < 4; blkNum++ {
+		var pvtData []*ledger.TxPvtData
+		if blkNum%2 == 1 {
+			pvtData = testData
+		}
+		assert.NoError(s.Prepare(blkNum, pvtData, nil))
+		assert.NoError(s.Commit())
+	}
+
+	// rolling back to the last committed block (or beyond) is a no-op
+	assert.NoError(s.RollbackToBlock(3))
+	testLastCommittedBlockHeight(4, assert, s)
+
+	assert.NoError(s.Prepare(4, nil, nil))
+	_, ok := s.RollbackToBlock(1).(*ErrIllegalCall)
+	assert.True(ok)
+	assert.NoError(s.Rollback())
+
+	assert.NoError(s.RollbackToBlock(1))
+	testLastCommittedBlockHeight(2, assert, s)
+	assert.True(testDataKeyExists(t, s, &dataKey{blkNum: 1, txNum: 2, ns: "ns-1", coll: "coll-1"}))
+	assert.False(testDataKeyExists(t, s, &dataKey{blkNum: 3, txNum: 2, ns: "ns-1", coll: "coll-1"}))
+	assert.False(testDataKeyExists(t, s, &dataKey{blkNum: 3, txNum: 2, ns: "ns-1", coll: "coll-2"}))
+	expiryEntries, err := s.(*store).retrieveExpiryEntries(0, 10)
+	assert.NoError(err)
+	assert.Len(expiryEntries, 1)
+	assert.Equal(uint64(1), expiryEntries[0].key.committingBlk)
+
+	// the rollback survives a reopen of the store and the blocks after the target block can be committed again
+	env.CloseAndReopen()
+	s = env.TestStore
+	testLastCommittedBlockHeight(2, assert, s)
+	assert.NoError(s.Prepare(2, nil, nil))
+	assert.NoError(s.Commit())
+	assert.NoError(s.Prepare(3, testData, nil))
+	assert.NoError(s.Commit())
+	retrievedData, err := s.GetPvtDataByBlockNum(3, nil)
+	assert.NoError(err)
+	assert.Equal(testData, retrievedData)
+}
+
+func TestStoreMissingPvtData(t *testing.T) {
+	cs := btltestutil.NewMockCollectionStore()
+	cs.SetBTL("ns-1", "coll-1", 2)
+	cs.SetBTL("ns-1", "coll-2", 0)
+	cs.SetBTL("ns-2", "coll-1", 2)
+	btlPolicy := pvtdatapolicy.ConstructBTLPolicy(cs)
+	env := NewTestStoreEnv(t, "TestStoreMissingPvtData", btlPolicy)
+	defer env.Cleanup()
+	assert := assert.New(t)
+	s := env.TestStore
+
+	// block 1 misses the data of tx 2 for coll-2 and block 2 misses the data of tx 4 for coll-1
+	// of both the namespaces; the data of coll-1 of block 2 expires when block 5 is committed
+	missingDataForBlk1 := make(ledger.MissingPvtDataInfo)
+	missingDataForBlk1.Add(1, 2, "ns-1", "coll-2")
+	missingDataForBlk2 := make(ledger.MissingPvtDataInfo)
+	missingDataForBlk2.Add(2, 4, "ns-1", "coll-1")
+	missingDataForBlk2.Add(2, 4, "ns-2", "coll-1")
+
+	assert.NoError(s.Prepare(0, nil, nil))
+	assert.NoError(s.Commit())
+	assert.NoError(s.Prepare(1, []*ledger.TxPvtData{produceSamplePvtdata(t, 2, []string{"ns-1:coll-1"})}, missingDataForBlk1[1]))
+	assert.NoError(s.Commit())
+	assert.NoError(s.Prepare(2, nil, missingDataForBlk2[2]))
+	assert.NoError(s.Commit())
+
+	// the missing data is listed starting from the most recent block
+	missingData, err := s.GetMissingPvtDataInfoForMostRecentBlocks(1)
+	assert.NoError(err)
+	assert.Equal(ledger.MissingPvtDataInfo{2: missingDataForBlk2[2]}, missingData)
+	missingData, err = s.GetMissingPvtDataInfoForMostRecentBlocks(10)
+	assert.NoError(err)
+	assert.Len(missingData, 2)
+	assert.Equal(missingDataForBlk1[1], missingData[1])
+	assert.Len(missingData[2][4], 2)
+
+	// the listing resumes below a given block
+	missingData, err = s.GetMissingPvtDataInfoForMostRecentBlocksBelow(2, 10)
+	assert.NoError(err)
+	assert.Equal(ledger.MissingPvtDataInfo{1: missingDataForBlk1[1]}, missingData)
+	missingData, err = s.GetMissingPvtDataInfoForMostRecentBlocksBelow(3, 1)
+	assert.NoError(err)
+	assert.Equal(ledger.MissingPvtDataInfo{2: missingDataForBlk2[2]}, missingData)
+	missingData, err = s.GetMissingPvtDataInfoForMostRecentBlocksBelow(1, 10)
+	assert.NoError(err)
+	assert.Empty(missingData)
+
+	// the missing data of a prepared block is not listed before the block is committed
+	assert.NoError(s.Prepare(3, nil, ledger.MissingBlockPvtdataInfo{0: {{Namespace: "ns-1", Collection: "coll-1"}}}))
+	missingData, err = s.GetMissingPvtDataInfoForMostRecentBlocks(1)
+	assert.NoError(err)
+	assert.Contains(missingData, uint64(2))
+	_, ok := s.CommitPvtDataOfOldBlocks(nil).(*ErrIllegalCall)
+	assert.True(ok)
+	assert.NoError(s.Rollback())
+
+	// the pvt data of the future blocks cannot be committed as old blocks
+	_, ok = s.CommitPvtDataOfOldBlocks(map[uint64][]*ledger.TxPvtData{3: nil}).(*ErrIllegalArgs)
+	assert.True(ok)
+
+	// commit the missing data of block 1 and a part of the missing data of block 2
+	oldBlocksPvtData := map[uint64][]*ledger.TxPvtData{
+		1: {produceSamplePvtdata(t, 2, []string{"ns-1:coll-2"})},
+		2: {produceSamplePvtdata(t, 4, []string{"ns-2:coll-1"})},
+	}
+	assert.NoError(s.CommitPvtDataOfOldBlocks(oldBlocksPvtData))
+	retrievedData, err := s.GetPvtDataByBlockNum(1, nil)
+	assert.NoError(err)
+	assert.Equal([]*ledger.TxPvtData{produceSamplePvtdata(t, 2, []string{"ns-1:coll-1", "ns-1:coll-2"})}, retrievedData)
+	retrievedData, err = s.GetPvtDataByBlockNum(2, nil)
+	assert.NoError(err)
+	assert.Equal(oldBlocksPvtData[2], retrievedData)
+	missingData, err = s.GetMissingPvtDataInfoForMostRecentBlocks(10)
+	assert.NoError(err)
+	expectedMissingData := make(ledger.MissingPvtDataInfo)
+	expectedMissingData.Add(2, 4, "ns-1", "coll-1")
+	assert.Equal(expectedMissingData, missingData)
+
+	// the committed data of the old block expires along with the data committed with the block
+	expiryEntries, err := s.(*store).retrieveExpiryEntries(5, 5)
+	assert.NoError(err)
+	assert.Len(expiryEntries, 1)
+	assert.Equal(uint64(2), expiryEntries[0].key.committingBlk)
+	assert.Equal([]uint64{4}, expiryEntries[0].value.Map["ns-2"].Map["coll-1"].List)
+	assert.Equal([]uint64{4}, expiryEntries[0].value.Map["ns-2"].MissingDataMap["coll-1"].List)
+	assert.Equal([]uint64{4}, expiryEntries[0].value.Map["ns-1"].MissingDataMap["coll-1"].List)
+
+	// the missing data is not listed anymore once it expires and is purged along with the expired data
+	viper.Set("ledger.pvtdataStore.purgeInterval", 5)
+	defer viper.Set("ledger.pvtdataStore.purgeInterval", 100)
+	for blkNum := uint64(3); blkNum <= 5; blkNum++ {
+		assert.NoError(s.Prepare(blkNum, nil, nil))
+		assert.NoError(s.Commit())
+	}
+	testWaitForPurgerRoutineToFinish(s)
+	missingData, err = s.GetMissingPvtDataInfoForMostRecentBlocks(10)
+	assert.NoError(err)
+	assert.Empty(missingData)
+	assert.False(testDataKeyExists(t, s, &dataKey{blkNum: 2, txNum: 4, ns: "ns-2", coll: "coll-1"}))
+
+	// the rollback removes the missing data entries of the rolled back blocks
+	missingDataForBlk6 := make(ledger.MissingPvtDataInfo)
+	missingDataForBlk6.Add(6, 0, "ns-1", "coll-2")
+	assert.NoError(s.Prepare(6, nil, missingDataForBlk6[6]))
+	assert.NoError(s.Commit())
+	missingData, err = s.GetMissingPvtDataInfoForMostRecentBlocks(10)
+	assert.NoError(err)
+	assert.Equal(missingDataForBlk6, missingData)
+	assert.NoError(s.RollbackToBlock(5))
+	missingData, err = s.GetMissingPvtDataInfoForMostRecentBlocks(10)
+	assert.NoError(err)
+	assert.Empty(missingData)
+	// the missing data entries of given blocks can be removed, e.g., when the blocks have been pruned
+	missingDataForBlks6And7 := make(ledger.MissingPvtDataInfo)
+	missingDataForBlks6And7.Add(6, 0, "ns-1", "coll-2")
+	missingDataForBlks6And7.Add(6, 1, "ns-2", "coll-1")
+	missingDataForBlks6And7.Add(7, 0, "ns-1", "coll-1")
+	for blkNum := uint64(6); blkNum <= 7; blkNum++ {
+		assert.NoError(s.Prepare(blkNum, nil, missingDataForBlks6And7[blkNum]))
+		assert.NoError(s.Commit())
+	}
+	assert.NoError(s.RemoveMissingPvtDataOfBlocks([]uint64{6}))
+	missingData, err = s.GetMissingPvtDataInfoForMostRecentBlocks(10)
+	assert.NoError(err)
+	assert.Equal(ledger.MissingPvtDataInfo{7: missingDataForBlks6And7[7]}, missingData)
+}
+
 // TODO Add tests for simulating a crash between calls `Prepare` and `Commit`/`Rollback`
 
 func testEmpty(expectedEmpty bool, assert *assert.Assertions, store Store) {
@@ -84,6 +84,8 @@ func ResetConfigToDefaultValues() {
 	viper.Set("ledger.history.enableHistoryDatabase", false)
 	viper.Set("ledger.state.couchDBConfig.autoWarmIndexes", true)
 	viper.Set("ledger.state.couchDBConfig.warmIndexesAfterNBlocks", 1)
+	viper.Set("ledger.state.couchDBConfig.valueCacheSize", 1000)
+	viper.Set("ledger.blockchain.maxBlockfileSize", 64*1024*1024)
 	viper.Set("peer.fileSystemPath", "/var/hyperledger/production")
 }
 
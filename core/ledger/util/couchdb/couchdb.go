@@ -19,6 +19,7 @@ package couchdb
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -32,6 +33,7 @@ import (
 	"net/textproto"
 	"net/url"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -101,8 +103,9 @@ type RangeQueryResponse struct {
 
 //QueryResponse is used for processing REST query responses from CouchDB
 type QueryResponse struct {
-	Warning string            `json:"warning"`
-	Docs    []json.RawMessage `json:"docs"`
+	Warning  string            `json:"warning"`
+	Docs     []json.RawMessage `json:"docs"`
+	Bookmark string            `json:"bookmark"`
 }
 
 // DocMetadata is used for capturing CouchDB document header info,
@@ -419,6 +422,56 @@ func (couchInstance *CouchInstance) VerifyCouchConfig() (*ConnectionInfo, *DBRet
 	return dbResponse, couchDBReturn, nil
 }
 
+// HealthCheck checks if the peer is able to communicate with CouchDB
+func (couchInstance *CouchInstance) HealthCheck(ctx context.Context) error {
+	connectURL, err := url.Parse(couchInstance.conf.URL)
+	if err != nil {
+		logger.Errorf("URL parse error: %s", err.Error())
+		return err
+	}
+	connectURL.Path = "/"
+
+	// the health check is attempted only once, the caller is expected to poll
+	resp, _, err := couchInstance.handleRequest(http.MethodGet, connectURL.String(), nil, "", "", 0, true)
+	if err != nil {
+		return fmt.Errorf("failed to connect to couch db [%s]", err)
+	}
+	defer closeResponseBody(resp)
+	return nil
+}
+
+//RetrieveDatabaseNames provides method to retrieve the names of all the databases in the CouchDB instance
+func (couchInstance *CouchInstance) RetrieveDatabaseNames() ([]string, error) {
+
+	logger.Debugf("Entering RetrieveDatabaseNames()")
+
+	connectURL, err := url.Parse(couchInstance.conf.URL)
+	if err != nil {
+		logger.Errorf("URL parse error: %s", err.Error())
+		return nil, err
+	}
+	connectURL.Path = "/_all_dbs"
+
+	//get the number of retries
+	maxRetries := couchInstance.conf.MaxRetries
+
+	resp, _, err := couchInstance.handleRequest(http.MethodGet, connectURL.String(), nil, "", "", maxRetries, true)
+	if err != nil {
+		return nil, err
+	}
+	defer closeResponseBody(resp)
+
+	var dbNames []string
+	decodeErr := json.NewDecoder(resp.Body).Decode(&dbNames)
+	if decodeErr != nil {
+		return nil, decodeErr
+	}
+
+	logger.Debugf("Exiting RetrieveDatabaseNames()")
+
+	return dbNames, nil
+}
+
 //DropDatabase provides method to drop an existing database
 func (dbclient *CouchDatabase) DropDatabase() (*DBOperationResponse, error) {
 
@@ -1003,8 +1056,9 @@ func (dbclient *CouchDatabase) DeleteDoc(id, rev string) error {
 
 }
 
-//QueryDocuments method provides function for processing a query
-func (dbclient *CouchDatabase) QueryDocuments(query string) (*[]QueryResult, error) {
+//QueryDocuments method provides function for processing a query. Along with the
+//results, it returns the bookmark that can be used for retrieving the next page of results
+func (dbclient *CouchDatabase) QueryDocuments(query string) (*[]QueryResult, string, error) {
 
 	logger.Debugf("Entering QueryDocuments()  query=%s", query)
 
@@ -1013,7 +1067,7 @@ func (dbclient *CouchDatabase) QueryDocuments(query string) (*[]QueryResult, err
 	queryURL, err := url.Parse(dbclient.CouchInstance.conf.URL)
 	if err != nil {
 		logger.Errorf("URL parse error: %s", err.Error())
-		return nil, err
+		return nil, "", err
 	}
 
 	queryURL.Path = dbclient.DBName + "/_find"
@@ -1023,7 +1077,7 @@ func (dbclient *CouchDatabase) QueryDocuments(query string) (*[]QueryResult, err
 
 	resp, _, err := dbclient.CouchInstance.handleRequest(http.MethodPost, queryURL.String(), []byte(query), "", "", maxRetries, true)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	defer closeResponseBody(resp)
 
@@ -1038,14 +1092,14 @@ func (dbclient *CouchDatabase) QueryDocuments(query string) (*[]QueryResult, err
 	//handle as JSON document
 	jsonResponseRaw, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	var jsonResponse = &QueryResponse{}
 
 	err2 := json.Unmarshal(jsonResponseRaw, &jsonResponse)
 	if err2 != nil {
-		return nil, err2
+		return nil, "", err2
 	}
 
 	for _, row := range jsonResponse.Docs {
@@ -1053,7 +1107,7 @@ func (dbclient *CouchDatabase) QueryDocuments(query string) (*[]QueryResult, err
 		var docMetadata = &DocMetadata{}
 		err3 := json.Unmarshal(row, &docMetadata)
 		if err3 != nil {
-			return nil, err3
+			return nil, "", err3
 		}
 
 		if docMetadata.AttachmentsInfo != nil {
@@ -1062,7 +1116,7 @@ func (dbclient *CouchDatabase) QueryDocuments(query string) (*[]QueryResult, err
 
 			couchDoc, _, err := dbclient.ReadDoc(docMetadata.ID)
 			if err != nil {
-				return nil, err
+				return nil, "", err
 			}
 			var addDocument = &QueryResult{ID: docMetadata.ID, Value: couchDoc.JSONValue, Attachments: couchDoc.Attachments}
 			results = append(results, *addDocument)
@@ -1077,7 +1131,7 @@ func (dbclient *CouchDatabase) QueryDocuments(query string) (*[]QueryResult, err
 	}
 	logger.Debugf("Exiting QueryDocuments()")
 
-	return &results, nil
+	return &results, jsonResponse.Bookmark, nil
 
 }
 
@@ -1370,6 +1424,138 @@ func (dbclient *CouchDatabase) BatchRetrieveDocumentMetadata(keys []string) ([]*
 
 }
 
+// BatchRetrieveDocuments - batch method to retrieve the documents for a set of keys,
+// along with their attachments, using a single bulk read of _all_docs. Keys that do
+// not exist or that were deleted are not included in the results. The order of the
+// results is not guaranteed to match the order of the keys.
+func (dbclient *CouchDatabase) BatchRetrieveDocuments(keys []string) ([]*QueryResult, error) {
+
+	logger.Debugf("Entering BatchRetrieveDocuments()  keys=%s", keys)
+
+	batchRetrieveURL, err := url.Parse(dbclient.CouchInstance.conf.URL)
+	if err != nil {
+		logger.Errorf("URL parse error: %s", err.Error())
+		return nil, err
+	}
+	batchRetrieveURL.Path = dbclient.DBName + "/_all_docs"
+
+	queryParms := batchRetrieveURL.Query()
+	queryParms.Add("include_docs", "true")
+	// the content of the attachments is inlined in the documents, base64 encoded
+	queryParms.Add("attachments", "true")
+	batchRetrieveURL.RawQuery = queryParms.Encode()
+
+	keymap := make(map[string]interface{})
+
+	keymap["keys"] = keys
+
+	jsonKeys, err := json.Marshal(keymap)
+	if err != nil {
+		return nil, err
+	}
+
+	//get the number of retries
+	maxRetries := dbclient.CouchInstance.conf.MaxRetries
+
+	resp, _, err := dbclient.CouchInstance.handleRequest(http.MethodPost, batchRetrieveURL.String(), jsonKeys, "", "", maxRetries, true)
+	if err != nil {
+		return nil, err
+	}
+	defer closeResponseBody(resp)
+
+	if logger.IsEnabledFor(logging.DEBUG) {
+		dump, _ := httputil.DumpResponse(resp, false)
+		// compact debug log by replacing carriage return / line feed with dashes to separate http headers
+		logger.Debugf("HTTP Response: %s", bytes.Replace(dump, []byte{0x0d, 0x0a}, []byte{0x20, 0x7c, 0x20}, -1))
+	}
+
+	//handle as JSON document
+	jsonResponseRaw, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var jsonResponse = &RangeQueryResponse{}
+	err2 := json.Unmarshal(jsonResponseRaw, &jsonResponse)
+	if err2 != nil {
+		return nil, err2
+	}
+
+	results := []*QueryResult{}
+
+	for _, row := range jsonResponse.Rows {
+
+		// rows of keys that are not found or that were deleted carry no document
+		if len(row.Doc) == 0 || bytes.Equal(row.Doc, []byte("null")) {
+			continue
+		}
+
+		var docMetadata = &DocMetadata{}
+		err3 := json.Unmarshal(row.Doc, &docMetadata)
+		if err3 != nil {
+			return nil, err3
+		}
+
+		if docMetadata.AttachmentsInfo != nil {
+
+			logger.Debugf("Adding JSON document and attachments for id: %s", docMetadata.ID)
+
+			attachments, err := decodeInlineAttachments(docMetadata.AttachmentsInfo)
+			if err != nil {
+				return nil, err
+			}
+
+			results = append(results, &QueryResult{docMetadata.ID, row.Doc, attachments})
+
+		} else {
+
+			logger.Debugf("Adding json docment for id: %s", docMetadata.ID)
+
+			results = append(results, &QueryResult{docMetadata.ID, row.Doc, nil})
+
+		}
+
+	}
+
+	logger.Debugf("Exiting BatchRetrieveDocuments()")
+
+	return results, nil
+
+}
+
+//inlineAttachment is an attachment of a document read with attachments=true
+type inlineAttachment struct {
+	ContentType string `json:"content_type"`
+	Data        []byte `json:"data"`
+}
+
+//decodeInlineAttachments decodes the _attachments field of a document read with
+//attachments=true, in which the content of each attachment is base64 encoded
+func decodeInlineAttachments(attachmentsInfo json.RawMessage) ([]*AttachmentInfo, error) {
+	inlineAttachments := make(map[string]*inlineAttachment)
+	if err := json.Unmarshal(attachmentsInfo, &inlineAttachments); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(inlineAttachments))
+	for name := range inlineAttachments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	attachments := []*AttachmentInfo{}
+	for _, name := range names {
+		attachment := inlineAttachments[name]
+		attachments = append(attachments, &AttachmentInfo{
+			Name:            name,
+			ContentType:     attachment.ContentType,
+			Length:          uint64(len(attachment.Data)),
+			AttachmentBytes: attachment.Data,
+		})
+	}
+	return attachments, nil
+}
+
 //BatchUpdateDocuments - batch method to batch update documents
 func (dbclient *CouchDatabase) BatchUpdateDocuments(documents []*CouchDoc) ([]*BatchUpdateResponse, error) {
 
@@ -7,9 +7,11 @@ SPDX-License-Identifier: Apache-2.0
 package couchdb
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
@@ -143,6 +145,31 @@ func TestEncodePathElement(t *testing.T) {
 
 }
 
+func TestHealthCheck(t *testing.T) {
+	client := &http.Client{}
+
+	// a server that behaves like a healthy CouchDB
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"couchdb":"Welcome","version":"2.1.1"}`)
+	}))
+	defer server.Close()
+	couchInstance := &CouchInstance{conf: CouchConnectionDef{URL: server.URL}, client: client}
+	err := couchInstance.HealthCheck(context.Background())
+	testutil.AssertNoError(t, err, "Health check should have succeeded")
+
+	// a server that is not reachable
+	unreachableURL := server.URL
+	server.Close()
+	couchInstance = &CouchInstance{conf: CouchConnectionDef{URL: unreachableURL}, client: client}
+	err = couchInstance.HealthCheck(context.Background())
+	testutil.AssertError(t, err, "Health check should have failed")
+	testutil.AssertEquals(t, strings.Contains(err.Error(), "failed to connect to couch db"), true)
+
+	couchInstance = &CouchInstance{conf: CouchConnectionDef{URL: badParseConnectURL}, client: client}
+	err = couchInstance.HealthCheck(context.Background())
+	testutil.AssertError(t, err, "Health check should have failed")
+}
+
 func TestBadCouchDBInstance(t *testing.T) {
 
 	//TODO continue changes to return and removal of sprintf in followon changes
@@ -207,13 +234,17 @@ func TestBadCouchDBInstance(t *testing.T) {
 	testutil.AssertError(t, err, "Error should have been thrown with ReadDocRange and invalid connection")
 
 	//Test QueryDocuments with bad connection
-	_, err = badDB.QueryDocuments("1")
+	_, _, err = badDB.QueryDocuments("1")
 	testutil.AssertError(t, err, "Error should have been thrown with QueryDocuments and invalid connection")
 
 	//Test BatchRetrieveDocumentMetadata with bad connection
 	_, err = badDB.BatchRetrieveDocumentMetadata(nil)
 	testutil.AssertError(t, err, "Error should have been thrown with BatchRetrieveDocumentMetadata and invalid connection")
 
+	//Test BatchRetrieveDocuments with bad connection
+	_, err = badDB.BatchRetrieveDocuments(nil)
+	testutil.AssertError(t, err, "Error should have been thrown with BatchRetrieveDocuments and invalid connection")
+
 	//Test BatchUpdateDocuments with bad connection
 	_, err = badDB.BatchUpdateDocuments(nil)
 	testutil.AssertError(t, err, "Error should have been thrown with BatchUpdateDocuments and invalid connection")
@@ -841,6 +872,75 @@ func TestDBSaveAttachment(t *testing.T) {
 	}
 }
 
+func TestBatchRetrieveDocumentsWithBinaryValues(t *testing.T) {
+
+	if ledgerconfig.IsCouchDBEnabled() {
+
+		database := "testbatchretrievebinary"
+		err := cleanup(database)
+		testutil.AssertNoError(t, err, fmt.Sprintf("Error when trying to cleanup  Error: %s", err))
+		defer cleanup(database)
+
+		//create a new instance and database object
+		couchInstance, err := CreateCouchInstance(couchDBDef.URL, couchDBDef.Username, couchDBDef.Password,
+			couchDBDef.MaxRetries, couchDBDef.MaxRetriesOnStartup, couchDBDef.RequestTimeout)
+		testutil.AssertNoError(t, err, fmt.Sprintf("Error when trying to create couch instance"))
+		db := CouchDatabase{CouchInstance: *couchInstance, DBName: database}
+
+		errdb := db.CreateDatabaseIfNotExist()
+		testutil.AssertNoError(t, errdb, fmt.Sprintf("Error when trying to create database"))
+
+		//binary values, which are not valid utf8 and contain every byte value
+		binaryValue1 := make([]byte, 512)
+		for i := range binaryValue1 {
+			binaryValue1[i] = byte(i)
+		}
+		binaryValue2 := []byte{0x00, 0xff, 0xfe, 0x80, 0x0a}
+
+		_, saveerr := db.SaveDoc("binary1", "", &CouchDoc{JSONValue: nil, Attachments: []*AttachmentInfo{
+			{Name: "valueBytes", ContentType: "application/octet-stream", AttachmentBytes: binaryValue1},
+		}})
+		testutil.AssertNoError(t, saveerr, fmt.Sprintf("Error when trying to save a document"))
+		_, saveerr = db.SaveDoc("binary2", "", &CouchDoc{JSONValue: nil, Attachments: []*AttachmentInfo{
+			{Name: "valueBytes", ContentType: "application/octet-stream", AttachmentBytes: binaryValue2},
+		}})
+		testutil.AssertNoError(t, saveerr, fmt.Sprintf("Error when trying to save a document"))
+		_, saveerr = db.SaveDoc("json1", "", &CouchDoc{JSONValue: []byte(`{"asset_name":"marble01"}`), Attachments: nil})
+		testutil.AssertNoError(t, saveerr, fmt.Sprintf("Error when trying to save a document"))
+
+		batchDocs, err := db.BatchRetrieveDocuments([]string{"binary1", "binary2", "json1", "missing"})
+		testutil.AssertNoError(t, err, fmt.Sprintf("Error when attempting retrieve documents"))
+		testutil.AssertEquals(t, len(batchDocs), 3)
+
+		for _, doc := range batchDocs {
+			switch doc.ID {
+			case "binary1", "binary2":
+				expectedValue := binaryValue1
+				if doc.ID == "binary2" {
+					expectedValue = binaryValue2
+				}
+				testutil.AssertEquals(t, len(doc.Attachments), 1)
+				testutil.AssertEquals(t, doc.Attachments[0].Name, "valueBytes")
+				testutil.AssertEquals(t, doc.Attachments[0].ContentType, "application/octet-stream")
+				testutil.AssertEquals(t, doc.Attachments[0].Length, uint64(len(expectedValue)))
+				testutil.AssertEquals(t, doc.Attachments[0].AttachmentBytes, expectedValue)
+
+				//the attachments are the same as the ones read with ReadDoc
+				couchDoc, _, geterr := db.ReadDoc(doc.ID)
+				testutil.AssertNoError(t, geterr, fmt.Sprintf("Error when trying to retrieve a document with attachment"))
+				testutil.AssertEquals(t, doc.Attachments[0].AttachmentBytes, couchDoc.Attachments[0].AttachmentBytes)
+			case "json1":
+				testutil.AssertNil(t, doc.Attachments)
+				asset := &Asset{}
+				testutil.AssertNoError(t, json.Unmarshal(doc.Value, asset), "Error when trying to unmarshal a document")
+				testutil.AssertEquals(t, asset.AssetName, "marble01")
+			default:
+				t.Fatalf("Unexpected document %s", doc.ID)
+			}
+		}
+	}
+}
+
 func TestDBDeleteDocument(t *testing.T) {
 
 	if ledgerconfig.IsCouchDBEnabled() {
@@ -1037,7 +1137,7 @@ func TestIndexOperations(t *testing.T) {
 	queryString := "{\"selector\":{\"size\": {\"$gt\": 0}},\"fields\": [\"_id\", \"_rev\", \"owner\", \"asset_name\", \"color\", \"size\"], \"sort\":[{\"size\":\"desc\"}], \"limit\": 10,\"skip\": 0}"
 
 	//Execute a query with a sort, this should throw the exception
-	_, err = db.QueryDocuments(queryString)
+	_, _, err = db.QueryDocuments(queryString)
 	testutil.AssertError(t, err, fmt.Sprintf("Error thrown while querying without a valid index"))
 
 	//Create the index
@@ -1048,7 +1148,7 @@ func TestIndexOperations(t *testing.T) {
 	time.Sleep(100 * time.Millisecond)
 
 	//Execute a query with an index,  this should succeed
-	_, err = db.QueryDocuments(queryString)
+	_, _, err = db.QueryDocuments(queryString)
 	testutil.AssertNoError(t, err, fmt.Sprintf("Error thrown while querying with an index"))
 
 	//Create another index definition
@@ -1264,13 +1364,13 @@ func TestRichQuery(t *testing.T) {
 			//Test query with invalid JSON -------------------------------------------------------------------
 			queryString := "{\"selector\":{\"owner\":}}"
 
-			_, err = db.QueryDocuments(queryString)
+			_, _, err = db.QueryDocuments(queryString)
 			testutil.AssertError(t, err, fmt.Sprintf("Error should have been thrown for bad json"))
 
 			//Test query with object  -------------------------------------------------------------------
 			queryString = "{\"selector\":{\"owner\":{\"$eq\":\"jerry\"}}}"
 
-			queryResult, err := db.QueryDocuments(queryString)
+			queryResult, _, err := db.QueryDocuments(queryString)
 			testutil.AssertNoError(t, err, fmt.Sprintf("Error when attempting to execute a query"))
 
 			//There should be 3 results for owner="jerry"
@@ -1279,7 +1379,7 @@ func TestRichQuery(t *testing.T) {
 			//Test query with implicit operator   --------------------------------------------------------------
 			queryString = "{\"selector\":{\"owner\":\"jerry\"}}"
 
-			queryResult, err = db.QueryDocuments(queryString)
+			queryResult, _, err = db.QueryDocuments(queryString)
 			testutil.AssertNoError(t, err, fmt.Sprintf("Error when attempting to execute a query"))
 
 			//There should be 3 results for owner="jerry"
@@ -1288,7 +1388,7 @@ func TestRichQuery(t *testing.T) {
 			//Test query with specified fields   -------------------------------------------------------------------
 			queryString = "{\"selector\":{\"owner\":{\"$eq\":\"jerry\"}},\"fields\": [\"owner\",\"asset_name\",\"color\",\"size\"]}"
 
-			queryResult, err = db.QueryDocuments(queryString)
+			queryResult, _, err = db.QueryDocuments(queryString)
 			testutil.AssertNoError(t, err, fmt.Sprintf("Error when attempting to execute a query"))
 
 			//There should be 3 results for owner="jerry"
@@ -1297,7 +1397,7 @@ func TestRichQuery(t *testing.T) {
 			//Test query with a leading operator   -------------------------------------------------------------------
 			queryString = "{\"selector\":{\"$or\":[{\"owner\":{\"$eq\":\"jerry\"}},{\"owner\": {\"$eq\": \"frank\"}}]}}"
 
-			queryResult, err = db.QueryDocuments(queryString)
+			queryResult, _, err = db.QueryDocuments(queryString)
 			testutil.AssertNoError(t, err, fmt.Sprintf("Error when attempting to execute a query"))
 
 			//There should be 4 results for owner="jerry" or owner="frank"
@@ -1306,7 +1406,7 @@ func TestRichQuery(t *testing.T) {
 			//Test query implicit and explicit operator   ------------------------------------------------------------------
 			queryString = "{\"selector\":{\"color\":\"green\",\"$or\":[{\"owner\":\"tom\"},{\"owner\":\"frank\"}]}}"
 
-			queryResult, err = db.QueryDocuments(queryString)
+			queryResult, _, err = db.QueryDocuments(queryString)
 			testutil.AssertNoError(t, err, fmt.Sprintf("Error when attempting to execute a query"))
 
 			//There should be 2 results for color="green" and (owner="jerry" or owner="frank")
@@ -1315,7 +1415,7 @@ func TestRichQuery(t *testing.T) {
 			//Test query with a leading operator  -------------------------------------------------------------------------
 			queryString = "{\"selector\":{\"$and\":[{\"size\":{\"$gte\":2}},{\"size\":{\"$lte\":5}}]}}"
 
-			queryResult, err = db.QueryDocuments(queryString)
+			queryResult, _, err = db.QueryDocuments(queryString)
 			testutil.AssertNoError(t, err, fmt.Sprintf("Error when attempting to execute a query"))
 
 			//There should be 4 results for size >= 2 and size <= 5
@@ -1324,7 +1424,7 @@ func TestRichQuery(t *testing.T) {
 			//Test query with leading and embedded operator  -------------------------------------------------------------
 			queryString = "{\"selector\":{\"$and\":[{\"size\":{\"$gte\":3}},{\"size\":{\"$lte\":10}},{\"$not\":{\"size\":7}}]}}"
 
-			queryResult, err = db.QueryDocuments(queryString)
+			queryResult, _, err = db.QueryDocuments(queryString)
 			testutil.AssertNoError(t, err, fmt.Sprintf("Error when attempting to execute a query"))
 
 			//There should be 7 results for size >= 3 and size <= 10 and not 7
@@ -1333,7 +1433,7 @@ func TestRichQuery(t *testing.T) {
 			//Test query with leading operator and array of objects ----------------------------------------------------------
 			queryString = "{\"selector\":{\"$and\":[{\"size\":{\"$gte\":2}},{\"size\":{\"$lte\":10}},{\"$nor\":[{\"size\":3},{\"size\":5},{\"size\":7}]}]}}"
 
-			queryResult, err = db.QueryDocuments(queryString)
+			queryResult, _, err = db.QueryDocuments(queryString)
 			testutil.AssertNoError(t, err, fmt.Sprintf("Error when attempting to execute a query"))
 
 			//There should be 6 results for size >= 2 and size <= 10 and not 3,5 or 7
@@ -1349,7 +1449,7 @@ func TestRichQuery(t *testing.T) {
 			//Test query with for tom  -------------------------------------------------------------------
 			queryString = "{\"selector\":{\"owner\":{\"$eq\":\"tom\"}}}"
 
-			queryResult, err = db.QueryDocuments(queryString)
+			queryResult, _, err = db.QueryDocuments(queryString)
 			testutil.AssertNoError(t, err, fmt.Sprintf("Error when attempting to execute a query"))
 
 			//There should be 8 results for owner="tom"
@@ -1358,7 +1458,7 @@ func TestRichQuery(t *testing.T) {
 			//Test query with for tom with limit  -------------------------------------------------------------------
 			queryString = "{\"selector\":{\"owner\":{\"$eq\":\"tom\"}},\"limit\":2}"
 
-			queryResult, err = db.QueryDocuments(queryString)
+			queryResult, _, err = db.QueryDocuments(queryString)
 			testutil.AssertNoError(t, err, fmt.Sprintf("Error when attempting to execute a query"))
 
 			//There should be 2 results for owner="tom" with a limit of 2
@@ -1367,7 +1467,7 @@ func TestRichQuery(t *testing.T) {
 			//Test query with invalid index  -------------------------------------------------------------------
 			queryString = "{\"selector\":{\"owner\":\"tom\"}, \"use_index\":[\"_design/indexOwnerDoc\",\"indexOwner\"]}"
 
-			_, err = db.QueryDocuments(queryString)
+			_, _, err = db.QueryDocuments(queryString)
 			testutil.AssertError(t, err, fmt.Sprintf("Error should have been thrown for an invalid index"))
 
 		}
@@ -1599,6 +1699,32 @@ func testBatchBatchOperations(t *testing.T, maxRetries int) {
 
 		//assert the value was deleted
 		testutil.AssertNil(t, dbGetResp)
+
+		//----------------------------------------------
+		//Test Batch Retrieve Documents
+
+		keys = []string{"marble01", "marble02", "marble03", "marble99"}
+
+		batchDocs, err := db.BatchRetrieveDocuments(keys)
+		testutil.AssertNoError(t, err, fmt.Sprintf("Error when attempting retrieve documents"))
+
+		//the deleted and the missing documents are not returned
+		testutil.AssertEquals(t, len(batchDocs), 2)
+		for _, doc := range batchDocs {
+			switch doc.ID {
+			case "marble01":
+				assetResp = &Asset{}
+				geterr = json.Unmarshal(doc.Value, &assetResp)
+				testutil.AssertNoError(t, geterr, fmt.Sprintf("Error when trying to unmarshal a document"))
+				testutil.AssertEquals(t, assetResp.Owner, "jerry")
+				testutil.AssertEquals(t, len(doc.Attachments), 1)
+			case "marble03":
+				testutil.AssertEquals(t, len(doc.Attachments), 1)
+				testutil.AssertEquals(t, doc.Attachments[0].AttachmentBytes, attachment3.AttachmentBytes)
+			default:
+				t.Fatalf("Unexpected document %s", doc.ID)
+			}
+		}
 	}
 }
 
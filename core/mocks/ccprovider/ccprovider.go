@@ -60,6 +60,14 @@ func (m *MockTxSim) ExecuteQuery(namespace, query string) (commonledger.ResultsI
 	return nil, nil
 }
 
+func (m *MockTxSim) GetStateRangeScanIteratorWithPagination(namespace, startKey, endKey string, pageSize int32, bookmark string) (ledger.QueryResultsIterator, error) {
+	return nil, nil
+}
+
+func (m *MockTxSim) ExecuteQueryWithPagination(namespace, query string, pageSize int32, bookmark string) (ledger.QueryResultsIterator, error) {
+	return nil, nil
+}
+
 func (m *MockTxSim) Done() {
 }
 
@@ -0,0 +1,207 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operations
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"time"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/flogging/httpadmin"
+	"github.com/hyperledger/fabric/common/healthz"
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("operations")
+
+const shutdownTimeout = 5 * time.Second
+
+// TLS holds the TLS configuration of the operations endpoint
+type TLS struct {
+	Enabled            bool
+	CertFile           string
+	KeyFile            string
+	ClientCertRequired bool
+	ClientCACertFiles  []string
+}
+
+// Options holds the configuration of the operations endpoint
+type Options struct {
+	// ListenAddress is the address the operations endpoint listens on
+	ListenAddress string
+	// TLS is the TLS configuration of the operations endpoint
+	TLS TLS
+	// Metrics are the options used to initialize the metrics root scope.
+	// The metrics are served at /metrics when the prometheus reporter is used
+	Metrics metrics.Opts
+	// Profiling enables the Go pprof endpoints under /debug/pprof/
+	Profiling bool
+}
+
+// System is the operations endpoint of a peer or an orderer. It serves the
+// following resources over HTTP(S):
+//
+//	/healthz      the health of the registered components
+//	/logspec      the active logging specification (GET) and its update (PUT)
+//	/metrics      the metrics in the prometheus exposition format
+//	/debug/pprof/ the Go pprof profiles, if profiling is enabled
+//
+// When TLS is enabled, /logspec can only be accessed by clients that present a
+// certificate issued by one of the configured client CAs
+type System struct {
+	*healthz.HealthHandler
+
+	options  Options
+	mux      *http.ServeMux
+	server   *http.Server
+	listener net.Listener
+}
+
+// NewSystem creates a new, not yet started, operations System
+func NewSystem(o Options) *System {
+	mux := http.NewServeMux()
+	return &System{
+		HealthHandler: healthz.NewHealthHandler(),
+		options:       o,
+		mux:           mux,
+		server:        &http.Server{Handler: mux},
+	}
+}
+
+// Start initializes the metrics and starts serving the operations endpoint
+// in the background
+func (s *System) Start() error {
+	if err := s.initializeMetrics(); err != nil {
+		return err
+	}
+	s.initializeHandlers()
+
+	listener, err := s.listen()
+	if err != nil {
+		return err
+	}
+	s.listener = listener
+
+	logger.Infof("Starting operations endpoint on %s", listener.Addr())
+	go func() {
+		if err := s.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			logger.Errorf("Operations endpoint stopped serving: %s", err)
+		}
+	}()
+	return nil
+}
+
+// Stop stops serving the operations endpoint and shuts down the metrics
+func (s *System) Stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	err := s.server.Shutdown(ctx)
+	if metricsErr := metrics.Shutdown(); metricsErr != nil && err == nil {
+		err = metricsErr
+	}
+	return err
+}
+
+// Addr returns the address the operations endpoint listens on, once started
+func (s *System) Addr() string {
+	if s.listener == nil {
+		return ""
+	}
+	return s.listener.Addr().String()
+}
+
+func (s *System) initializeMetrics() error {
+	if err := metrics.Init(s.options.Metrics); err != nil {
+		return errors.WithMessage(err, "failed initializing metrics")
+	}
+	go func() {
+		if err := metrics.Start(); err != nil {
+			logger.Errorf("Failed starting metrics: %s", err)
+		}
+	}()
+	return nil
+}
+
+func (s *System) initializeHandlers() {
+	s.mux.Handle("/healthz", s.HealthHandler)
+	s.mux.Handle("/logspec", s.requireClientCert(httpadmin.NewSpecHandler()))
+	if handler := metrics.PrometheusHandler(); handler != nil {
+		s.mux.Handle("/metrics", handler)
+	}
+	if s.options.Profiling {
+		s.mux.HandleFunc("/debug/pprof/", pprof.Index)
+		s.mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		s.mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		s.mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		s.mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	}
+}
+
+// requireClientCert rejects the requests that do not carry a verified client
+// certificate when TLS is enabled
+func (s *System) requireClientCert(next http.Handler) http.Handler {
+	if !s.options.TLS.Enabled {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *System) listen() (net.Listener, error) {
+	listener, err := net.Listen("tcp", s.options.ListenAddress)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed listening on %s", s.options.ListenAddress)
+	}
+	if !s.options.TLS.Enabled {
+		return listener, nil
+	}
+	tlsConfig, err := s.options.TLS.config()
+	if err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return tls.NewListener(listener, tlsConfig), nil
+}
+
+func (t TLS) config() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed loading the TLS certificate of the operations endpoint")
+	}
+	caCertPool := x509.NewCertPool()
+	for _, caPath := range t.ClientCACertFiles {
+		caPem, err := ioutil.ReadFile(caPath)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed reading client CA certificate %s", caPath)
+		}
+		if !caCertPool.AppendCertsFromPEM(caPem) {
+			return nil, errors.Errorf("failed parsing client CA certificate %s", caPath)
+		}
+	}
+	clientAuth := tls.VerifyClientCertIfGiven
+	if t.ClientCertRequired {
+		clientAuth = tls.RequireAndVerifyClientCert
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    caCertPool,
+		ClientAuth:   clientAuth,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
@@ -0,0 +1,170 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operations
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/crypto/tlsgen"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/metrics"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/assert"
+)
+
+type checkerFunc func(context.Context) error
+
+func (f checkerFunc) HealthCheck(ctx context.Context) error {
+	return f(ctx)
+}
+
+func TestSystem(t *testing.T) {
+	defer flogging.Reset()
+	system := NewSystem(Options{
+		ListenAddress: "127.0.0.1:0",
+		Metrics:       metrics.Opts{Enabled: true, Reporter: "prom", Interval: 100 * time.Millisecond},
+		Profiling:     true,
+	})
+	assert.Empty(t, system.Addr())
+	assert.NoError(t, system.Start())
+	defer system.Stop()
+	baseURL := fmt.Sprintf("http://%s", system.Addr())
+
+	resp, err := http.Get(baseURL + "/healthz")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+
+	assert.NoError(t, system.RegisterChecker("couchdb", checkerFunc(func(context.Context) error {
+		return errors.New("couchdb is unreachable")
+	})))
+	resp, err = http.Get(baseURL + "/healthz")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Contains(t, string(body), `{"component":"couchdb","reason":"couchdb is unreachable"}`)
+
+	req, _ := http.NewRequest(http.MethodPut, baseURL+"/logspec", strings.NewReader(`{"spec": "operations=debug:warning"}`))
+	resp, err = http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	resp.Body.Close()
+	assert.Equal(t, "DEBUG", flogging.GetModuleLevel("operations"))
+	resp, err = http.Get(baseURL + "/logspec")
+	assert.NoError(t, err)
+	body, _ = ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.JSONEq(t, `{"spec": "operations=debug:warning"}`, string(body))
+
+	metrics.RootScope.SubScope("operations").Counter("test_total").Inc(1)
+	scrape := func() string {
+		resp, err := http.Get(baseURL + "/metrics")
+		if err != nil {
+			return ""
+		}
+		defer resp.Body.Close()
+		body, _ := ioutil.ReadAll(resp.Body)
+		return string(body)
+	}
+	gt := NewGomegaWithT(t)
+	gt.Eventually(scrape, 5*time.Second).Should(ContainSubstring("hyperledger_fabric_operations_test_total 1"))
+
+	resp, err = http.Get(baseURL + "/debug/pprof/")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+}
+
+func TestSystemTLS(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "operations")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	ca, err := tlsgen.NewCA()
+	assert.NoError(t, err)
+	serverKeyPair, err := ca.NewServerCertKeyPair("127.0.0.1")
+	assert.NoError(t, err)
+	clientKeyPair, err := ca.NewClientCertKeyPair()
+	assert.NoError(t, err)
+	writeFile := func(name string, content []byte) string {
+		path := filepath.Join(tempDir, name)
+		assert.NoError(t, ioutil.WriteFile(path, content, 0600))
+		return path
+	}
+
+	system := NewSystem(Options{
+		ListenAddress: "127.0.0.1:0",
+		TLS: TLS{
+			Enabled:           true,
+			CertFile:          writeFile("server.crt", serverKeyPair.Cert),
+			KeyFile:           writeFile("server.key", serverKeyPair.Key),
+			ClientCACertFiles: []string{writeFile("ca.crt", ca.CertBytes())},
+		},
+	})
+	assert.NoError(t, system.Start())
+	defer system.Stop()
+	baseURL := fmt.Sprintf("https://%s", system.Addr())
+
+	caCertPool := x509.NewCertPool()
+	caCertPool.AppendCertsFromPEM(ca.CertBytes())
+	clientCert, err := tls.X509KeyPair(clientKeyPair.Cert, clientKeyPair.Key)
+	assert.NoError(t, err)
+	anonymousClient := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: caCertPool}}}
+	authenticatedClient := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+		RootCAs:      caCertPool,
+		Certificates: []tls.Certificate{clientCert},
+	}}}
+
+	// the health of the process is available to anyone
+	resp, err := anonymousClient.Get(baseURL + "/healthz")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+
+	// the logging specification requires a client certificate
+	resp, err = anonymousClient.Get(baseURL + "/logspec")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	resp.Body.Close()
+	resp, err = authenticatedClient.Get(baseURL + "/logspec")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+
+	// profiling is disabled
+	resp, err = authenticatedClient.Get(baseURL + "/debug/pprof/")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	resp.Body.Close()
+}
+
+func TestSystemStartFailures(t *testing.T) {
+	system := NewSystem(Options{ListenAddress: "bad-address"})
+	err := system.Start()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed listening on bad-address")
+
+	system = NewSystem(Options{
+		ListenAddress: "127.0.0.1:0",
+		TLS:           TLS{Enabled: true, CertFile: "missing.crt", KeyFile: "missing.key"},
+	})
+	err = system.Start()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed loading the TLS certificate of the operations endpoint")
+}
@@ -16,6 +16,7 @@ limitations under the License.
 package peer
 
 import (
+	"fmt"
 	"runtime/debug"
 	"time"
 
@@ -23,8 +24,11 @@ import (
 	"github.com/hyperledger/fabric/common/deliver"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/core/aclmgmt/resources"
+	"github.com/hyperledger/fabric/core/common/privdata"
+	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
 	"github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/op/go-logging"
@@ -44,10 +48,22 @@ func init() {
 // given resource name
 type PolicyCheckerProvider func(resourceName string) deliver.PolicyCheckerFunc
 
+// PrivateDataProvider gives access to the private data of committed blocks
+// and to the access policies of the collections it belongs to
+type PrivateDataProvider interface {
+	// GetPvtDataByNum returns the private data of the given block of a channel
+	GetPvtDataByNum(channelID string, blockNum uint64) ([]*ledger.TxPvtData, error)
+
+	// CollectionAccessPolicy returns the access policy of the given collection
+	// of a chaincode deployed on a channel
+	CollectionAccessPolicy(channelID, namespace, collection string) (privdata.CollectionAccessPolicy, error)
+}
+
 // server holds the dependencies necessary to create a deliver server
 type server struct {
 	dh                    *deliver.Handler
 	policyCheckerProvider PolicyCheckerProvider
+	pvtDataProvider       PrivateDataProvider
 }
 
 // blockResponseSender structure used to send block responses
@@ -87,7 +103,7 @@ func (fbrs *filteredBlockResponseSender) SendStatusResponse(status common.Status
 func (fbrs *filteredBlockResponseSender) SendBlockResponse(block *common.Block) error {
 	// Generates filtered block response
 	b := blockEvent(*block)
-	filteredBlock, err := b.toFilteredBlock()
+	filteredBlock, err := b.toFilteredBlock(false)
 	if err != nil {
 		logger.Warningf("Failed to generate filtered block due to: %s", err)
 		return fbrs.SendStatusResponse(common.Status_BAD_REQUEST)
@@ -98,6 +114,126 @@ func (fbrs *filteredBlockResponseSender) SendBlockResponse(block *common.Block)
 	return fbrs.Send(response)
 }
 
+// privateDataResponseSender structure used to send filtered blocks carrying
+// chaincode event payloads along with the private data the requestor is
+// entitled to read
+type privateDataResponseSender struct {
+	peer.Deliver_DeliverWithPrivateDataServer
+	pvtDataProvider PrivateDataProvider
+	// envelope is the last seek request received, it identifies the
+	// channel and the requestor that the private data is filtered for
+	envelope *common.Envelope
+}
+
+// Recv receives the next seek request and keeps track of it
+func (pdrs *privateDataResponseSender) Recv() (*common.Envelope, error) {
+	envelope, err := pdrs.Deliver_DeliverWithPrivateDataServer.Recv()
+	if err == nil {
+		pdrs.envelope = envelope
+	}
+	return envelope, err
+}
+
+func (pdrs *privateDataResponseSender) SendStatusResponse(status common.Status) error {
+	response := &peer.DeliverResponse{
+		Type: &peer.DeliverResponse_Status{Status: status},
+	}
+	return pdrs.Send(response)
+}
+
+// SendBlockResponse generates deliver response with a filtered block and the
+// private data of the collections the requestor is a member of
+func (pdrs *privateDataResponseSender) SendBlockResponse(block *common.Block) error {
+	b := blockEvent(*block)
+	filteredBlock, err := b.toFilteredBlock(true)
+	if err != nil {
+		logger.Warningf("Failed to generate filtered block due to: %s", err)
+		return pdrs.SendStatusResponse(common.Status_BAD_REQUEST)
+	}
+
+	privateDataMap, err := pdrs.privateData(block.Header.Number)
+	if err != nil {
+		logger.Warningf("Failed to retrieve private data for block [%d] due to: %s", block.Header.Number, err)
+		return pdrs.SendStatusResponse(common.Status_INTERNAL_SERVER_ERROR)
+	}
+
+	response := &peer.DeliverResponse{
+		Type: &peer.DeliverResponse_FilteredBlockAndPrivateData{
+			FilteredBlockAndPrivateData: &peer.FilteredBlockAndPrivateData{
+				FilteredBlock:  filteredBlock,
+				PrivateDataMap: privateDataMap,
+			},
+		},
+	}
+	return pdrs.Send(response)
+}
+
+// privateData returns the private write sets of the given block, keyed by
+// transaction sequence in the block, retaining only the collections whose
+// access policy is satisfied by the requestor
+func (pdrs *privateDataResponseSender) privateData(blockNum uint64) (map[uint64]*rwset.TxPvtReadWriteSet, error) {
+	if pdrs.envelope == nil {
+		return nil, errors.New("no seek request received")
+	}
+	channelID, err := utils.ChannelID(pdrs.envelope)
+	if err != nil {
+		return nil, err
+	}
+	signedData, err := pdrs.envelope.AsSignedData()
+	if err != nil {
+		return nil, err
+	}
+
+	txPvtData, err := pdrs.pvtDataProvider.GetPvtDataByNum(channelID, blockNum)
+	if err != nil {
+		return nil, err
+	}
+
+	// memberships caches the access decision per collection for this block
+	memberships := make(map[string]bool)
+	isMember := func(namespace, collection string) (bool, error) {
+		key := namespace + "~" + collection
+		if member, exists := memberships[key]; exists {
+			return member, nil
+		}
+		accessPolicy, err := pdrs.pvtDataProvider.CollectionAccessPolicy(channelID, namespace, collection)
+		if err != nil {
+			return false, err
+		}
+		member := accessPolicy != nil && accessPolicy.AccessFilter()(*signedData[0])
+		memberships[key] = member
+		return member, nil
+	}
+
+	privateDataMap := make(map[uint64]*rwset.TxPvtReadWriteSet)
+	for _, txPvt := range txPvtData {
+		if txPvt.WriteSet == nil {
+			continue
+		}
+		filteredWriteSet := &rwset.TxPvtReadWriteSet{DataModel: txPvt.WriteSet.DataModel}
+		for _, nsRWSet := range txPvt.WriteSet.NsPvtRwset {
+			filteredNsRWSet := &rwset.NsPvtReadWriteSet{Namespace: nsRWSet.Namespace}
+			for _, collRWSet := range nsRWSet.CollectionPvtRwset {
+				member, err := isMember(nsRWSet.Namespace, collRWSet.CollectionName)
+				if err != nil {
+					return nil, errors.WithMessage(err, fmt.Sprintf("failed retrieving access policy of collection %s:%s", nsRWSet.Namespace, collRWSet.CollectionName))
+				}
+				if !member {
+					continue
+				}
+				filteredNsRWSet.CollectionPvtRwset = append(filteredNsRWSet.CollectionPvtRwset, collRWSet)
+			}
+			if len(filteredNsRWSet.CollectionPvtRwset) != 0 {
+				filteredWriteSet.NsPvtRwset = append(filteredWriteSet.NsPvtRwset, filteredNsRWSet)
+			}
+		}
+		if len(filteredWriteSet.NsPvtRwset) != 0 {
+			privateDataMap[txPvt.SeqInBlock] = filteredWriteSet
+		}
+	}
+	return privateDataMap, nil
+}
+
 // transactionActions aliasing for peer.TransactionAction pointers slice
 type transactionActions []*peer.TransactionAction
 
@@ -135,9 +271,27 @@ func (s *server) Deliver(srv peer.Deliver_DeliverServer) (err error) {
 	return s.dh.Handle(srv.Context(), deliverServer)
 }
 
-// NewDeliverEventsServer creates a peer.Deliver server to deliver block and
-// filtered block events
-func NewDeliverEventsServer(mutualTLS bool, policyCheckerProvider PolicyCheckerProvider, chainManager deliver.ChainManager) peer.DeliverServer {
+// DeliverWithPrivateData sends a stream of filtered blocks, carrying chaincode
+// event payloads and the private data the client is entitled to, after commitment
+func (s *server) DeliverWithPrivateData(srv peer.Deliver_DeliverWithPrivateDataServer) error {
+	logger.Debugf("Starting new DeliverWithPrivateData handler")
+	defer dumpStacktraceOnPanic()
+	sender := &privateDataResponseSender{
+		Deliver_DeliverWithPrivateDataServer: srv,
+		pvtDataProvider:                      s.pvtDataProvider,
+	}
+	// getting policy checker based on resources.Event_FilteredBlockAndPrivateData resource name
+	deliverServer := &deliver.Server{
+		Receiver:       sender,
+		PolicyChecker:  s.policyCheckerProvider(resources.Event_FilteredBlockAndPrivateData),
+		ResponseSender: sender,
+	}
+	return s.dh.Handle(srv.Context(), deliverServer)
+}
+
+// NewDeliverEventsServer creates a peer.Deliver server to deliver block,
+// filtered block and filtered block with private data events
+func NewDeliverEventsServer(mutualTLS bool, policyCheckerProvider PolicyCheckerProvider, chainManager deliver.ChainManager, pvtDataProvider PrivateDataProvider) peer.DeliverServer {
 	timeWindow := viper.GetDuration("peer.authentication.timewindow")
 	if timeWindow == 0 {
 		defaultTimeWindow := 15 * time.Minute
@@ -145,8 +299,9 @@ func NewDeliverEventsServer(mutualTLS bool, policyCheckerProvider PolicyCheckerP
 		timeWindow = defaultTimeWindow
 	}
 	return &server{
-		dh: deliver.NewHandler(chainManager, timeWindow, mutualTLS),
+		dh:                    deliver.NewHandler(chainManager, timeWindow, mutualTLS),
 		policyCheckerProvider: policyCheckerProvider,
+		pvtDataProvider:       pvtDataProvider,
 	}
 }
 
@@ -161,7 +316,9 @@ func (s *server) sendProducer(srv peer.Deliver_DeliverFilteredServer) func(msg p
 	}
 }
 
-func (block *blockEvent) toFilteredBlock() (*peer.FilteredBlock, error) {
+// toFilteredBlock converts the block into a filtered block, the chaincode event
+// payloads are retained only if withPayload is set
+func (block *blockEvent) toFilteredBlock(withPayload bool) (*peer.FilteredBlock, error) {
 	filteredBlock := &peer.FilteredBlock{
 		Number: block.Header.Number,
 	}
@@ -213,7 +370,7 @@ func (block *blockEvent) toFilteredBlock() (*peer.FilteredBlock, error) {
 				return nil, errors.WithMessage(err, "error unmarshal transaction payload for block event")
 			}
 
-			filteredTransaction.Data, err = transactionActions(tx.Actions).toFilteredActions()
+			filteredTransaction.Data, err = transactionActions(tx.Actions).toFilteredActions(withPayload)
 			if err != nil {
 				logger.Errorf(err.Error())
 				return nil, err
@@ -226,7 +383,7 @@ func (block *blockEvent) toFilteredBlock() (*peer.FilteredBlock, error) {
 	return filteredBlock, nil
 }
 
-func (ta transactionActions) toFilteredActions() (*peer.FilteredTransaction_TransactionActions, error) {
+func (ta transactionActions) toFilteredActions(withPayload bool) (*peer.FilteredTransaction_TransactionActions, error) {
 	transactionActions := &peer.FilteredTransactionActions{}
 	for _, action := range ta {
 		chaincodeActionPayload, err := utils.GetChaincodeActionPayload(action.Payload)
@@ -261,6 +418,9 @@ func (ta transactionActions) toFilteredActions() (*peer.FilteredTransaction_Tran
 					EventName:   ccEvent.EventName,
 				},
 			}
+			if withPayload {
+				filteredAction.ChaincodeEvent.Payload = ccEvent.Payload
+			}
 			transactionActions.ChaincodeActions = append(transactionActions.ChaincodeActions, filteredAction)
 		}
 	}
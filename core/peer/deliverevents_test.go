@@ -16,10 +16,14 @@ import (
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
 	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/common/privdata"
+	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
 	"github.com/hyperledger/fabric/protos/orderer"
 	"github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -145,6 +149,53 @@ func (*mockDeliverServer) SetTrailer(metadata.MD) {
 	panic("implement me")
 }
 
+// mockPrivateDataProvider mock implementation of the PrivateDataProvider
+type mockPrivateDataProvider struct {
+	mock.Mock
+}
+
+func (m *mockPrivateDataProvider) GetPvtDataByNum(channelID string, blockNum uint64) ([]*ledger.TxPvtData, error) {
+	args := m.Called(channelID, blockNum)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*ledger.TxPvtData), args.Error(1)
+}
+
+func (m *mockPrivateDataProvider) CollectionAccessPolicy(channelID, namespace, collection string) (privdata.CollectionAccessPolicy, error) {
+	args := m.Called(channelID, namespace, collection)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(privdata.CollectionAccessPolicy), args.Error(1)
+}
+
+// mockAccessPolicy mock implementation of the privdata.CollectionAccessPolicy
+// that either admits or rejects every requestor
+type mockAccessPolicy struct {
+	member bool
+}
+
+func (m *mockAccessPolicy) AccessFilter() privdata.Filter {
+	return func(common.SignedData) bool {
+		return m.member
+	}
+}
+
+func (*mockAccessPolicy) RequiredPeerCount() int {
+	return 0
+}
+
+func (*mockAccessPolicy) MaximumPeerCount() int {
+	return 0
+}
+
+func (*mockAccessPolicy) MemberOrgs() []string {
+	return nil
+}
+
+const eventPayload = "testEventPayload"
+
 type testConfig struct {
 	channelID     string
 	eventName     string
@@ -208,6 +259,8 @@ func TestEventsServer_DeliverFiltered(t *testing.T) {
 								config.Equal(config.eventName, chaincodeActions[0].ChaincodeEvent.EventName)
 								config.Equal(config.txID, chaincodeActions[0].ChaincodeEvent.TxId)
 								config.Equal(config.chaincodeName, chaincodeActions[0].ChaincodeEvent.ChaincodeId)
+								// filtered blocks never carry the event payload
+								config.Nil(chaincodeActions[0].ChaincodeEvent.Payload)
 							default:
 								config.FailNow("Unexpected response type")
 							}
@@ -371,7 +424,7 @@ func TestEventsServer_DeliverFiltered(t *testing.T) {
 			wg := &sync.WaitGroup{}
 			chainManager, deliverServer := test.prepare(wg)
 
-			server := NewDeliverEventsServer(false, defaultPolicyCheckerProvider, chainManager)
+			server := NewDeliverEventsServer(false, defaultPolicyCheckerProvider, chainManager, &mockPrivateDataProvider{})
 			err := server.DeliverFiltered(deliverServer)
 			wg.Wait()
 			// no error expected
@@ -379,6 +432,141 @@ func TestEventsServer_DeliverFiltered(t *testing.T) {
 		})
 	}
 }
+func TestEventsServer_DeliverWithPrivateData(t *testing.T) {
+	viper.Set("peer.authentication.timewindow", "1s")
+	config := testConfig{
+		channelID:     "testChainID",
+		eventName:     "testEvent",
+		chaincodeName: "mycc",
+		txID:          "testID",
+		payload: &common.Payload{
+			Header: &common.Header{
+				ChannelHeader: utils.MarshalOrPanic(&common.ChannelHeader{
+					ChannelId: "testChainID",
+					Timestamp: util.CreateUtcTimestamp(),
+				}),
+				SignatureHeader: utils.MarshalOrPanic(&common.SignatureHeader{}),
+			},
+			Data: utils.MarshalOrPanic(&orderer.SeekInfo{
+				Start:    &orderer.SeekPosition{Type: &orderer.SeekPosition_Specified{Specified: &orderer.SeekSpecified{Number: 0}}},
+				Stop:     &orderer.SeekPosition{Type: &orderer.SeekPosition_Newest{Newest: &orderer.SeekNewest{}}},
+				Behavior: orderer.SeekInfo_BLOCK_UNTIL_READY,
+			}),
+		},
+		Assertions: assert.New(t),
+	}
+
+	collRWSet := func(name string) *rwset.CollectionPvtReadWriteSet {
+		return &rwset.CollectionPvtReadWriteSet{CollectionName: name, Rwset: []byte(name)}
+	}
+	pvtData := []*ledger.TxPvtData{
+		{
+			SeqInBlock: 0,
+			WriteSet: &rwset.TxPvtReadWriteSet{
+				NsPvtRwset: []*rwset.NsPvtReadWriteSet{
+					{
+						Namespace:          "mycc",
+						CollectionPvtRwset: []*rwset.CollectionPvtReadWriteSet{collRWSet("coll1"), collRWSet("coll2")},
+					},
+				},
+			},
+		},
+		{
+			SeqInBlock: 1,
+			WriteSet: &rwset.TxPvtReadWriteSet{
+				NsPvtRwset: []*rwset.NsPvtReadWriteSet{
+					{
+						Namespace:          "mycc",
+						CollectionPvtRwset: []*rwset.CollectionPvtReadWriteSet{collRWSet("coll2")},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		setup    func(*mockPrivateDataProvider)
+		validate func(*peer.DeliverResponse)
+	}{
+		{
+			name: "Testing deliver of the filtered block with payloads and the private data of member collections",
+			setup: func(provider *mockPrivateDataProvider) {
+				provider.On("GetPvtDataByNum", config.channelID, uint64(0)).Return(pvtData, nil)
+				provider.On("CollectionAccessPolicy", config.channelID, "mycc", "coll1").Return(&mockAccessPolicy{member: true}, nil)
+				provider.On("CollectionAccessPolicy", config.channelID, "mycc", "coll2").Return(&mockAccessPolicy{member: false}, nil)
+			},
+			validate: func(response *peer.DeliverResponse) {
+				blockAndPvtData := response.GetFilteredBlockAndPrivateData()
+				config.NotNil(blockAndPvtData)
+				block := blockAndPvtData.FilteredBlock
+				config.Equal(uint64(0), block.Number)
+				config.Equal(config.channelID, block.ChannelId)
+				config.Equal(1, len(block.FilteredTransactions))
+				chaincodeActions := block.FilteredTransactions[0].GetTransactionActions().ChaincodeActions
+				config.Equal(1, len(chaincodeActions))
+				config.Equal(config.eventName, chaincodeActions[0].ChaincodeEvent.EventName)
+				config.Equal([]byte(eventPayload), chaincodeActions[0].ChaincodeEvent.Payload)
+
+				// only coll1 is readable, hence the second transaction is omitted
+				config.Len(blockAndPvtData.PrivateDataMap, 1)
+				txPvtRWSet := blockAndPvtData.PrivateDataMap[0]
+				config.Len(txPvtRWSet.NsPvtRwset, 1)
+				config.Equal([]*rwset.CollectionPvtReadWriteSet{collRWSet("coll1")}, txPvtRWSet.NsPvtRwset[0].CollectionPvtRwset)
+			},
+		},
+		{
+			name: "Testing deliver with private data when the private data cannot be retrieved",
+			setup: func(provider *mockPrivateDataProvider) {
+				provider.On("GetPvtDataByNum", config.channelID, uint64(0)).Return(nil, errors.New("pvtdata store unavailable"))
+			},
+			validate: func(response *peer.DeliverResponse) {
+				config.Equal(common.Status_INTERNAL_SERVER_ERROR, response.GetStatus())
+			},
+		},
+		{
+			name: "Testing deliver with private data when the collection access policy cannot be retrieved",
+			setup: func(provider *mockPrivateDataProvider) {
+				provider.On("GetPvtDataByNum", config.channelID, uint64(0)).Return(pvtData, nil)
+				provider.On("CollectionAccessPolicy", config.channelID, "mycc", "coll1").Return(nil, errors.New("collection not found"))
+			},
+			validate: func(response *peer.DeliverResponse) {
+				config.Equal(common.Status_INTERNAL_SERVER_ERROR, response.GetStatus())
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chaincodeActionPayload, err := createChaincodeAction(config.chaincodeName, config.eventName, config.txID)
+			config.NoError(err)
+			chainManager := createDefaultSupportMamangerMock(config, chaincodeActionPayload)
+
+			pvtDataProvider := &mockPrivateDataProvider{}
+			test.setup(pvtDataProvider)
+
+			p := &peer2.Peer{}
+			deliverServer := &mockDeliverServer{}
+			deliverServer.On("Context").Return(peer2.NewContext(context.TODO(), p))
+			deliverServer.On("Recv").Return(&common.Envelope{
+				Payload: utils.MarshalOrPanic(config.payload),
+			}, nil).Once()
+			deliverServer.On("Recv").Return(&common.Envelope{}, io.EOF)
+
+			var responses []*peer.DeliverResponse
+			deliverServer.On("Send", mock.Anything).Run(func(args mock.Arguments) {
+				responses = append(responses, args.Get(0).(*peer.DeliverResponse))
+			}).Return(nil)
+
+			server := NewDeliverEventsServer(false, defaultPolicyCheckerProvider, chainManager, pvtDataProvider)
+			err = server.DeliverWithPrivateData(deliverServer)
+			assert.NoError(t, err)
+			assert.NotEmpty(t, responses)
+			test.validate(responses[0])
+		})
+	}
+}
+
 func createDefaultSupportMamangerMock(config testConfig, chaincodeActionPayload *peer.ChaincodeActionPayload) *mockChainManager {
 	chainManager := &mockChainManager{}
 	iter := &mockIterator{}
@@ -453,6 +641,7 @@ func createChaincodeAction(chaincodeName string, eventName string, txID string)
 		ChaincodeId: chaincodeName,
 		EventName:   eventName,
 		TxId:        txID,
+		Payload:     []byte(eventPayload),
 	})
 	if err != nil {
 		return nil, err
@@ -346,7 +346,7 @@ func createChain(cid string, ledger ledger.PeerLedger, cb *common.Block, sccp sy
 		*chainSupport
 		*semaphore.Weighted
 	}{cs, validationWorkersSemaphore}
-	validator := txvalidator.NewTxValidator(vcs, sccp)
+	validator := txvalidator.NewTxValidator(cid, vcs, sccp)
 	c := committer.NewLedgerCommitterReactive(ledger, func(block *common.Block) error {
 		chainID, err := utils.GetChainIDFromBlock(block)
 		if err != nil {
@@ -692,6 +692,37 @@ func (DeliverChainManager) GetChain(chainID string) (deliver.Chain, bool) {
 	return channel.cs, ok
 }
 
+// DeliverPrivateDataProvider provides access to the private data of a
+// channel's committed blocks for deliver
+type DeliverPrivateDataProvider struct {
+}
+
+// GetPvtDataByNum returns the private data of the given block of a channel
+func (DeliverPrivateDataProvider) GetPvtDataByNum(chainID string, blockNum uint64) ([]*ledger.TxPvtData, error) {
+	l := GetLedger(chainID)
+	if l == nil {
+		return nil, errors.Errorf("channel %s not found", chainID)
+	}
+	return l.GetPvtDataByNum(blockNum, nil)
+}
+
+// CollectionAccessPolicy returns the access policy of the given collection
+// of a chaincode deployed on a channel
+func (DeliverPrivateDataProvider) CollectionAccessPolicy(chainID, namespace, collection string) (privdata.CollectionAccessPolicy, error) {
+	l := GetLedger(chainID)
+	if l == nil {
+		return nil, errors.Errorf("channel %s not found", chainID)
+	}
+	collectionStore := privdata.NewSimpleCollectionStore(&collectionSupport{
+		PeerLedger: l,
+	})
+	return collectionStore.RetrieveCollectionAccessPolicy(common.CollectionCriteria{
+		Channel:    chainID,
+		Namespace:  namespace,
+		Collection: collection,
+	})
+}
+
 // fileLedgerBlockStore implements the interface expected by
 // common/ledger/blockledger/file to interact with a file ledger for deliver
 type fileLedgerBlockStore struct {
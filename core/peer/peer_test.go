@@ -124,7 +124,7 @@ func TestCreateChainFromBlock(t *testing.T) {
 	go grpcServer.Serve(socket)
 	defer grpcServer.Stop()
 
-	err = CreateChainFromBlock(block, nil)
+	err = CreateChainFromBlock(block, (&mscc.MocksccProviderFactory{}).NewSystemChaincodeProvider())
 	if err != nil {
 		t.Fatalf("failed to create chain %s", err)
 	}
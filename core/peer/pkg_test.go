@@ -24,6 +24,7 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	configtxtest "github.com/hyperledger/fabric/common/configtx/test"
+	mscc "github.com/hyperledger/fabric/common/mocks/scc"
 	"github.com/hyperledger/fabric/core/comm"
 	testpb "github.com/hyperledger/fabric/core/comm/testdata/grpc"
 	"github.com/hyperledger/fabric/core/ledger/util"
@@ -176,7 +177,7 @@ func TestUpdateRootsFromConfigBlock(t *testing.T) {
 			"Org1-cert.pem"))
 		viper.Set("peer.fileSystemPath", "/var/hyperledger/test/")
 		defer os.RemoveAll("/var/hyperledger/test/")
-		err := peer.Default.CreateChainFromBlock(block, nil)
+		err := peer.Default.CreateChainFromBlock(block, (&mscc.MocksccProviderFactory{}).NewSystemChaincodeProvider())
 		if err != nil {
 			t.Fatalf("Failed to create config block (%s)", err)
 		}
@@ -251,10 +251,10 @@ func TestDeploy(t *testing.T) {
 	// message is expected.
 	errMessage = "invalid collection configuration supplied for chaincode example02:1.0"
 	testDeploy(t, "example02", "1.0", path, false, false, true, errMessage, scc, stub, []byte("invalid collection"))
-	// Should contain an entry for the chaincodeData only
-	assert.Equal(t, 1, len(stub.State))
+	// The writes of the failed deploy are discarded
+	assert.Equal(t, 0, len(stub.State))
 	_, ok := stub.State["example02"]
-	assert.Equal(t, true, ok)
+	assert.Equal(t, false, ok)
 
 	collName1 := "mycollection1"
 	var signers = [][]byte{[]byte("signer0"), []byte("signer1")}
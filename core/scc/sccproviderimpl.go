@@ -18,9 +18,11 @@ package scc
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/core/common/validation/statebased"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/peer"
 )
@@ -29,6 +31,9 @@ import (
 type ProviderImpl struct {
 	Peer        peer.Operations
 	PeerSupport peer.Support
+
+	vpmgrOnce sync.Once
+	vpmgr     statebased.KeyLevelValidationParameterManager
 }
 
 // IsSysCC returns true if the supplied chaincode is a system chaincode
@@ -71,3 +76,12 @@ func (c *ProviderImpl) PolicyManager(channelID string) (policies.Manager, bool)
 	m := c.Peer.GetPolicyManager(channelID)
 	return m, (m != nil)
 }
+
+// GetValidationParameterManager returns the manager of key-level
+// validation parameters, which is created upon the first call
+func (c *ProviderImpl) GetValidationParameterManager() statebased.KeyLevelValidationParameterManager {
+	c.vpmgrOnce.Do(func() {
+		c.vpmgr = statebased.NewKeyLevelValidationParameterManager(c)
+	})
+	return c.vpmgr
+}
@@ -9,15 +9,18 @@ package vscc
 import (
 	"bytes"
 	"fmt"
+	"strconv"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/cauthdsl"
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/common/privdata"
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
+	"github.com/hyperledger/fabric/core/common/validation/statebased"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/scc/lscc"
 	m "github.com/hyperledger/fabric/msp"
@@ -92,6 +95,9 @@ func (vscc *ValidatorOneValidSignature) Invoke(stub shim.ChaincodeStubInterface)
 	// args[0] - function name (not used now)
 	// args[1] - serialized Envelope
 	// args[2] - serialized policy
+	// args[3] - namespace being validated (key-level endorsement only)
+	// args[4] - block number (key-level endorsement only)
+	// args[5] - position of the transaction in the block (key-level endorsement only)
 	args := stub.GetArgs()
 	if len(args) < 3 {
 		return shim.Error("Incorrect number of arguments")
@@ -155,6 +161,21 @@ func (vscc *ValidatorOneValidSignature) Invoke(stub shim.ChaincodeStubInterface)
 		return shim.Error(err.Error())
 	}
 
+	// key-level endorsement policies are honored if the channel supports
+	// them and the committer supplied the coordinates of the transaction
+	keyLevelEndorsement := ac.Capabilities().KeyLevelEndorsement() && len(args) >= 6
+	var ns string
+	var blockNum, txNum uint64
+	if keyLevelEndorsement {
+		ns = string(args[3])
+		if blockNum, err = strconv.ParseUint(string(args[4]), 10, 64); err != nil {
+			return shim.Error(fmt.Sprintf("invalid block number supplied: %s", err))
+		}
+		if txNum, err = strconv.ParseUint(string(args[5]), 10, 64); err != nil {
+			return shim.Error(fmt.Sprintf("invalid transaction number supplied: %s", err))
+		}
+	}
+
 	// loop through each of the actions within
 	for _, act := range tx.Actions {
 		cap, err := utils.GetChaincodeActionPayload(act.Payload)
@@ -168,8 +189,15 @@ func (vscc *ValidatorOneValidSignature) Invoke(stub shim.ChaincodeStubInterface)
 			return shim.Error(err.Error())
 		}
 
-		// evaluate the signature set against the policy
-		err = policy.Evaluate(signatureSet)
+		if keyLevelEndorsement {
+			// evaluate the signature set against the validation parameters
+			// of the keys written by the transaction and, if needed,
+			// against the chaincode-level policy
+			err = vscc.validateKeyLevelEndorsement(chdr.ChannelId, ns, blockNum, txNum, args[2], cap, signatureSet)
+		} else {
+			// evaluate the signature set against the policy
+			err = policy.Evaluate(signatureSet)
+		}
 		if err != nil {
 			logger.Warningf("Endorsement policy failure for transaction txid=%s, err: %s", chdr.GetTxId(), err.Error())
 			if len(signatureSet) < len(cap.Action.Endorsements) {
@@ -202,6 +230,48 @@ func (vscc *ValidatorOneValidSignature) Invoke(stub shim.ChaincodeStubInterface)
 	return shim.Success(nil)
 }
 
+// validateKeyLevelEndorsement evaluates the endorsements of a chaincode
+// action against the validation parameters of the keys of namespace ns
+// that are written by the action and against the chaincode-level policy
+func (vscc *ValidatorOneValidSignature) validateKeyLevelEndorsement(chid, ns string, blockNum, txNum uint64, policy []byte, cap *pb.ChaincodeActionPayload, signatureSet []*common.SignedData) error {
+	respPayload, err := utils.GetProposalResponsePayload(cap.Action.ProposalResponsePayload)
+	if err != nil {
+		return errors.WithMessage(err, "GetProposalResponsePayload failed")
+	}
+	ccAction, err := utils.GetChaincodeAction(respPayload.Extension)
+	if err != nil {
+		return errors.WithMessage(err, "GetChaincodeAction failed")
+	}
+
+	// only keep the endorsements whose identities were retained
+	// after deduplication
+	endorsements := make([]*pb.Endorsement, 0, len(signatureSet))
+	for _, sd := range signatureSet {
+		endorsements = append(endorsements, &pb.Endorsement{Endorser: sd.Identity, Signature: sd.Signature})
+	}
+
+	pe := &policyEvaluator{
+		pProvider: cauthdsl.NewPolicyProvider(mspmgmt.GetManagerForChain(chid)),
+	}
+	validator := statebased.NewKeyLevelValidator(pe, vscc.sccprovider.GetValidationParameterManager())
+	return validator.Validate(chid, ns, blockNum, txNum, ccAction.Results, cap.Action.ProposalResponsePayload, policy, endorsements)
+}
+
+// policyEvaluator implements statebased.PolicyEvaluator
+// on top of the policies of a channel's MSPs
+type policyEvaluator struct {
+	pProvider policies.Provider
+}
+
+// Evaluate evaluates the supplied signature set against the supplied policy
+func (p *policyEvaluator) Evaluate(policyBytes []byte, signatureSet []*common.SignedData) error {
+	policy, _, err := p.pProvider.NewPolicy(policyBytes)
+	if err != nil {
+		return errors.WithMessage(err, "could not create policy")
+	}
+	return policy.Evaluate(signatureSet)
+}
+
 // checkInstantiationPolicy evaluates an instantiation policy against a signed proposal
 func (vscc *ValidatorOneValidSignature) checkInstantiationPolicy(chainName string, env *common.Envelope, instantiationPolicy []byte, payl *common.Payload) error {
 	// create a policy object from the policy bytes
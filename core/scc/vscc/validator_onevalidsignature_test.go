@@ -41,6 +41,23 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+func createTxWithResults(res []byte) (*common.Envelope, error) {
+	ccid := &peer.ChaincodeID{Name: "foo", Version: "v1"}
+	cis := &peer.ChaincodeInvocationSpec{ChaincodeSpec: &peer.ChaincodeSpec{ChaincodeId: ccid}}
+
+	prop, _, err := utils.CreateProposalFromCIS(common.HeaderType_ENDORSER_TRANSACTION, util.GetTestChainID(), cis, sid)
+	if err != nil {
+		return nil, err
+	}
+
+	presp, err := utils.CreateProposalResponse(prop.Header, prop.Payload, &peer.Response{Status: 200}, res, nil, ccid, nil, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.CreateSignedTx(prop, id, presp)
+}
+
 func createTx(endorsedByDuplicatedIdentity bool) (*common.Envelope, error) {
 	ccid := &peer.ChaincodeID{Name: "foo", Version: "v1"}
 	cis := &peer.ChaincodeInvocationSpec{ChaincodeSpec: &peer.ChaincodeSpec{ChaincodeId: ccid}}
@@ -448,6 +465,79 @@ func TestInvoke(t *testing.T) {
 	}
 }
 
+// mockVPManager returns the validation parameters it has been configured with
+type mockVPManager struct {
+	vps map[string][]byte
+}
+
+func (m *mockVPManager) GetValidationParameterForKey(ch, cc, coll, key string, blockNum, txNum uint64) ([]byte, error) {
+	return m.vps[key], nil
+}
+
+func (m *mockVPManager) ExtractValidationParameterDependency(ch, cc string, blockNum, txNum uint64, rwset []byte) {
+}
+
+func (m *mockVPManager) SetTxValidationCode(ch, cc string, blockNum, txNum uint64, vc peer.TxValidationCode) {
+}
+
+func TestInvokeKeyLevelEndorsement(t *testing.T) {
+	vpmgr := &mockVPManager{vps: map[string][]byte{}}
+	mp := (&scc.MocksccProviderFactory{
+		ApplicationConfigBool: true,
+		ApplicationConfigRv: &mc.MockApplication{
+			CapabilitiesRv: &mc.MockApplicationCapabilities{KeyLevelEndorsementRv: true},
+		},
+	}).NewSystemChaincodeProvider().(*scc.MocksccProviderImpl)
+	mp.VPMgr = vpmgr
+
+	v := New(mp)
+	stub := shim.NewMockStub("validatoronevalidsignature", v)
+
+	rwsetBuilder := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder.AddToWriteSet("foo", "key", []byte("value"))
+	sr, err := rwsetBuilder.GetTxSimulationResults()
+	assert.NoError(t, err)
+	res, err := sr.GetPubSimulationBytes()
+	assert.NoError(t, err)
+
+	tx, err := createTxWithResults(res)
+	assert.NoError(t, err)
+	envBytes, err := utils.GetBytesEnvelope(tx)
+	assert.NoError(t, err)
+
+	goodPolicy, err := getSignedByMSPMemberPolicy(mspid)
+	assert.NoError(t, err)
+	badPolicy, err := getSignedByMSPMemberPolicy("barf")
+	assert.NoError(t, err)
+
+	// without key-level policies, the chaincode-level policy applies
+	args := [][]byte{[]byte("dv"), envBytes, goodPolicy, []byte("foo"), []byte("1"), []byte("0")}
+	res0 := stub.MockInvoke("1", args)
+	assert.Equal(t, int32(shim.OK), res0.Status, res0.Message)
+	args = [][]byte{[]byte("dv"), envBytes, badPolicy, []byte("foo"), []byte("1"), []byte("0")}
+	assert.NotEqual(t, int32(shim.OK), stub.MockInvoke("1", args).Status)
+
+	// the key-level policy overrides the chaincode-level policy
+	vpmgr.vps["key"] = goodPolicy
+	args = [][]byte{[]byte("dv"), envBytes, badPolicy, []byte("foo"), []byte("1"), []byte("0")}
+	res0 = stub.MockInvoke("1", args)
+	assert.Equal(t, int32(shim.OK), res0.Status, res0.Message)
+	vpmgr.vps["key"] = badPolicy
+	args = [][]byte{[]byte("dv"), envBytes, goodPolicy, []byte("foo"), []byte("1"), []byte("0")}
+	assert.NotEqual(t, int32(shim.OK), stub.MockInvoke("1", args).Status)
+
+	// without the coordinates of the transaction, the chaincode-level policy applies
+	args = [][]byte{[]byte("dv"), envBytes, goodPolicy}
+	res0 = stub.MockInvoke("1", args)
+	assert.Equal(t, int32(shim.OK), res0.Status, res0.Message)
+
+	// malformed coordinates
+	args = [][]byte{[]byte("dv"), envBytes, goodPolicy, []byte("foo"), []byte("barf"), []byte("0")}
+	assert.NotEqual(t, int32(shim.OK), stub.MockInvoke("1", args).Status)
+	args = [][]byte{[]byte("dv"), envBytes, goodPolicy, []byte("foo"), []byte("1"), []byte("barf")}
+	assert.NotEqual(t, int32(shim.OK), stub.MockInvoke("1", args).Status)
+}
+
 func TestInvalidFunction(t *testing.T) {
 	State := make(map[string]map[string][]byte)
 	mp := (&scc.MocksccProviderFactory{
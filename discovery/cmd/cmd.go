@@ -0,0 +1,87 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discovery
+
+import (
+	"os"
+
+	"github.com/hyperledger/fabric/cmd/common"
+	"github.com/hyperledger/fabric/protos/discovery"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+const (
+	// PeersCommand is the name of the command that queries the peers of a channel
+	PeersCommand = "peers"
+	// ConfigCommand is the name of the command that queries the configuration of a channel
+	ConfigCommand = "config"
+	// EndorsersCommand is the name of the command that queries the endorsers of chaincodes
+	EndorsersCommand = "endorsers"
+)
+
+const (
+	// JSONFormat prints the response as JSON
+	JSONFormat = "json"
+	// TableFormat prints the response as a human readable table
+	TableFormat = "table"
+)
+
+//go:generate mockery -dir . -name Stub -case underscore -output mocks/
+
+// Stub represents a remote discovery service
+type Stub interface {
+	// Send sends the request, and receives a response
+	Send(server string, conf common.Config, req *discovery.Request) (*discovery.Response, error)
+}
+
+//go:generate mockery -dir . -name ResponseParser -case underscore -output mocks/
+
+// ResponseParser parses responses sent from the server
+type ResponseParser interface {
+	// ParseResponse parses the response of the query on the given channel,
+	// and prints it in the given format
+	ParseResponse(channel string, response *discovery.Response, format string) error
+}
+
+// CommandRegistrar registers commands
+type CommandRegistrar interface {
+	// Command adds a new top-level command to the CLI
+	Command(name, help string, onCommand common.CLICommand) *kingpin.CmdClause
+}
+
+// AddCommands registers the discovery commands to the given CommandRegistrar
+func AddCommands(cli CommandRegistrar) {
+	stub := NewClientStub()
+
+	peerCmd := NewPeerCmd(stub, &PeerResponseParser{Writer: os.Stdout})
+	peers := cli.Command(PeersCommand, "Discover peers", peerCmd.Execute)
+	peerCmd.server = serverFlag(peers)
+	peerCmd.channel = peers.Flag("channel", "Sets the channel the query is intended to. If not specified, the local peers are queried").String()
+	peerCmd.output = outputFlag(peers)
+
+	configCmd := NewConfigCmd(stub, &ConfigResponseParser{Writer: os.Stdout})
+	config := cli.Command(ConfigCommand, "Discover channel config", configCmd.Execute)
+	configCmd.server = serverFlag(config)
+	configCmd.channel = config.Flag("channel", "Sets the channel the query is intended to").Required().String()
+	configCmd.output = outputFlag(config)
+
+	endorserCmd := NewEndorsersCmd(stub, &EndorserResponseParser{Writer: os.Stdout})
+	endorsers := cli.Command(EndorsersCommand, "Discover chaincode endorsers", endorserCmd.Execute)
+	endorserCmd.server = serverFlag(endorsers)
+	endorserCmd.channel = endorsers.Flag("channel", "Sets the channel the query is intended to").Required().String()
+	endorserCmd.chaincodes = endorsers.Flag("chaincode", "Specifies the chaincode name(s). Several chaincodes mean a chaincode-to-chaincode call chain, starting with the first").Required().Strings()
+	endorserCmd.collections = endorsers.Flag("collection", "Specifies the name(s) of the collection(s) the chaincodes access").Strings()
+	endorserCmd.output = outputFlag(endorsers)
+}
+
+func serverFlag(cmd *kingpin.CmdClause) *string {
+	return cmd.Flag("server", "Sets the endpoint of the server to connect").Required().String()
+}
+
+func outputFlag(cmd *kingpin.CmdClause) *string {
+	return cmd.Flag("output", "Sets the output format, either json or table").Default(JSONFormat).Enum(JSONFormat, TableFormat)
+}
@@ -0,0 +1,58 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discovery
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/cmd/common"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+func TestAddCommands(t *testing.T) {
+	registrar := &commandRegistrar{
+		app:      kingpin.New("test", "test"),
+		commands: make(map[string]common.CLICommand),
+	}
+	AddCommands(registrar)
+	assert.Len(t, registrar.commands, 3)
+	for _, cmd := range []string{PeersCommand, ConfigCommand, EndorsersCommand} {
+		assert.Contains(t, registrar.commands, cmd)
+	}
+
+	for _, tc := range []struct {
+		args   []string
+		errMsg string
+	}{
+		{args: []string{"peers", "--server", "p0:7051"}},
+		{args: []string{"peers", "--server", "p0:7051", "--channel", "mychannel", "--output", "table"}},
+		{args: []string{"config", "--server", "p0:7051", "--channel", "mychannel"}},
+		{args: []string{"endorsers", "--server", "p0:7051", "--channel", "mychannel", "--chaincode", "mycc", "--chaincode", "yourcc", "--collection", "col1"}},
+		{args: []string{"peers"}, errMsg: "required flag --server not provided"},
+		{args: []string{"config", "--server", "p0:7051"}, errMsg: "required flag --channel not provided"},
+		{args: []string{"endorsers", "--server", "p0:7051", "--channel", "mychannel"}, errMsg: "required flag --chaincode not provided"},
+		{args: []string{"peers", "--server", "p0:7051", "--output", "yaml"}, errMsg: "enum value must be one of json,table, got 'yaml'"},
+	} {
+		_, err := registrar.app.Parse(tc.args)
+		if tc.errMsg == "" {
+			assert.NoError(t, err)
+			continue
+		}
+		assert.EqualError(t, err, tc.errMsg)
+	}
+}
+
+type commandRegistrar struct {
+	app      *kingpin.Application
+	commands map[string]common.CLICommand
+}
+
+func (cr *commandRegistrar) Command(name, help string, onCommand common.CLICommand) *kingpin.CmdClause {
+	cr.commands[name] = onCommand
+	return cr.app.Command(name, help)
+}
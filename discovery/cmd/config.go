@@ -0,0 +1,109 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discovery
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/fabric/cmd/common"
+	"github.com/hyperledger/fabric/protos/discovery"
+	"github.com/pkg/errors"
+)
+
+// NewConfigCmd creates a new ConfigCmd
+func NewConfigCmd(stub Stub, parser ResponseParser) *ConfigCmd {
+	return &ConfigCmd{
+		stub:   stub,
+		parser: parser,
+	}
+}
+
+// ConfigCmd executes a command that retrieves config
+type ConfigCmd struct {
+	stub    Stub
+	server  *string
+	channel *string
+	output  *string
+	parser  ResponseParser
+}
+
+// Execute executes the command
+func (pc *ConfigCmd) Execute(conf common.Config) error {
+	server := stringValue(pc.server)
+	if server == "" {
+		return errors.New("no server specified")
+	}
+	channel := stringValue(pc.channel)
+	if channel == "" {
+		return errors.New("no channel specified")
+	}
+
+	req := &discovery.Request{
+		Queries: []*discovery.Query{
+			{
+				Channel: channel,
+				Query:   &discovery.Query_ConfigQuery{ConfigQuery: &discovery.ConfigQuery{}},
+			},
+		},
+	}
+	res, err := pc.stub.Send(server, conf, req)
+	if err != nil {
+		return err
+	}
+	return pc.parser.ParseResponse(channel, res, outputFormat(pc.output))
+}
+
+// ConfigResponseParser parses config responses
+type ConfigResponseParser struct {
+	io.Writer
+}
+
+// ParseResponse parses the given response for the given channel
+func (parser *ConfigResponseParser) ParseResponse(channel string, res *discovery.Response, format string) error {
+	config, errRes := res.ConfigAt(0)
+	if errRes != nil {
+		return errors.New(errRes.Content)
+	}
+	if config == nil {
+		return errors.Errorf("expected a config result but got %v instead", res.Results[0])
+	}
+
+	if format == TableFormat {
+		return writeTable(parser.Writer, configTable(config))
+	}
+	return writeJSON(parser.Writer, config)
+}
+
+func configTable(config *discovery.ConfigResult) [][]string {
+	mspIDs := make(map[string]struct{})
+	for mspID := range config.Msps {
+		mspIDs[mspID] = struct{}{}
+	}
+	for mspID := range config.Orderers {
+		mspIDs[mspID] = struct{}{}
+	}
+	var sortedMSPIDs []string
+	for mspID := range mspIDs {
+		sortedMSPIDs = append(sortedMSPIDs, mspID)
+	}
+	sort.Strings(sortedMSPIDs)
+
+	rows := [][]string{{"MSP ID", "ORDERERS"}}
+	for _, mspID := range sortedMSPIDs {
+		var orderers []string
+		if endpoints, exists := config.Orderers[mspID]; exists {
+			for _, endpoint := range endpoints.Endpoint {
+				orderers = append(orderers, fmt.Sprintf("%s:%d", endpoint.Host, endpoint.Port))
+			}
+		}
+		rows = append(rows, []string{mspID, strings.Join(orderers, ",")})
+	}
+	return rows
+}
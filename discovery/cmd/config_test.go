@@ -0,0 +1,138 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discovery
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hyperledger/fabric/cmd/common"
+	"github.com/hyperledger/fabric/discovery/cmd/mocks"
+	"github.com/hyperledger/fabric/protos/discovery"
+	"github.com/hyperledger/fabric/protos/msp"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestConfigCmd(t *testing.T) {
+	server := "peer0"
+	channel := "mychannel"
+	stub := &mocks.Stub{}
+	parser := &mocks.ResponseParser{}
+	cmd := NewConfigCmd(stub, parser)
+	response := &discovery.Response{}
+
+	t.Run("no server supplied", func(t *testing.T) {
+		assert.EqualError(t, cmd.Execute(common.Config{}), "no server specified")
+	})
+
+	t.Run("no channel supplied", func(t *testing.T) {
+		cmd.server = &server
+		assert.EqualError(t, cmd.Execute(common.Config{}), "no channel specified")
+	})
+
+	t.Run("config query", func(t *testing.T) {
+		cmd.channel = &channel
+		stub.On("Send", server, common.Config{}, mock.MatchedBy(func(req *discovery.Request) bool {
+			return req.Queries[0].GetConfigQuery() != nil && req.Queries[0].Channel == channel
+		})).Return(response, nil).Once()
+		parser.On("ParseResponse", channel, response, JSONFormat).Return(nil).Once()
+		assert.NoError(t, cmd.Execute(common.Config{}))
+	})
+
+	t.Run("server returns an error", func(t *testing.T) {
+		stub.On("Send", server, common.Config{}, mock.Anything).Return(nil, errors.New("deadline exceeded")).Once()
+		assert.EqualError(t, cmd.Execute(common.Config{}), "deadline exceeded")
+	})
+
+	stub.AssertExpectations(t)
+	parser.AssertExpectations(t)
+}
+
+func TestConfigResponseParser(t *testing.T) {
+	res := &discovery.Response{
+		Results: []*discovery.QueryResult{
+			{
+				Result: &discovery.QueryResult_ConfigResult{
+					ConfigResult: &discovery.ConfigResult{
+						Msps: map[string]*msp.FabricMSPConfig{
+							"Org1MSP":     {Name: "Org1MSP"},
+							"OrdererMSP":  {Name: "OrdererMSP"},
+							"Org2MSP":     {Name: "Org2MSP"},
+							"Orderer2MSP": {Name: "Orderer2MSP"},
+						},
+						Orderers: map[string]*discovery.Endpoints{
+							"OrdererMSP": {
+								Endpoint: []*discovery.Endpoint{
+									{Host: "orderer0", Port: 7050},
+									{Host: "orderer1", Port: 7050},
+								},
+							},
+							"Orderer2MSP": {
+								Endpoint: []*discovery.Endpoint{
+									{Host: "orderer2", Port: 7050},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	t.Run("json", func(t *testing.T) {
+		buff := &bytes.Buffer{}
+		parser := &ConfigResponseParser{Writer: buff}
+		assert.NoError(t, parser.ParseResponse("mychannel", res, JSONFormat))
+		assert.Contains(t, buff.String(), `"Orderer2MSP": {
+			"endpoint": [
+				{
+					"host": "orderer2",
+					"port": 7050
+				}
+			]
+		}`)
+		assert.Contains(t, buff.String(), `"Org1MSP": {
+			"name": "Org1MSP"
+		}`)
+	})
+
+	t.Run("table", func(t *testing.T) {
+		buff := &bytes.Buffer{}
+		parser := &ConfigResponseParser{Writer: buff}
+		assert.NoError(t, parser.ParseResponse("mychannel", res, TableFormat))
+		assert.Equal(t, ""+
+			"MSP ID       ORDERERS\n"+
+			"Orderer2MSP  orderer2:7050\n"+
+			"OrdererMSP   orderer0:7050,orderer1:7050\n"+
+			"Org1MSP      \n"+
+			"Org2MSP      \n", buff.String())
+	})
+
+	t.Run("error response", func(t *testing.T) {
+		errRes := &discovery.Response{
+			Results: []*discovery.QueryResult{
+				{Result: &discovery.QueryResult_Error{Error: &discovery.Error{Content: "access denied"}}},
+			},
+		}
+		parser := &ConfigResponseParser{Writer: &bytes.Buffer{}}
+		assert.EqualError(t, parser.ParseResponse("mychannel", errRes, JSONFormat), "access denied")
+	})
+
+	t.Run("unexpected response", func(t *testing.T) {
+		badRes := &discovery.Response{
+			Results: []*discovery.QueryResult{
+				{Result: &discovery.QueryResult_Members{Members: &discovery.PeerMembershipResult{}}},
+			},
+		}
+		parser := &ConfigResponseParser{Writer: &bytes.Buffer{}}
+		err := parser.ParseResponse("mychannel", badRes, JSONFormat)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "expected a config result but got")
+	})
+}
@@ -0,0 +1,183 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discovery
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/fabric/cmd/common"
+	"github.com/hyperledger/fabric/protos/discovery"
+	"github.com/pkg/errors"
+)
+
+// NewEndorsersCmd creates a new EndorsersCmd
+func NewEndorsersCmd(stub Stub, parser ResponseParser) *EndorsersCmd {
+	return &EndorsersCmd{
+		stub:   stub,
+		parser: parser,
+	}
+}
+
+// EndorsersCmd executes a command that retrieves endorsers for a chaincode invocation chain
+type EndorsersCmd struct {
+	stub        Stub
+	server      *string
+	channel     *string
+	chaincodes  *[]string
+	collections *[]string
+	output      *string
+	parser      ResponseParser
+}
+
+// Execute executes the command
+func (pc *EndorsersCmd) Execute(conf common.Config) error {
+	server := stringValue(pc.server)
+	if server == "" {
+		return errors.New("no server specified")
+	}
+	channel := stringValue(pc.channel)
+	if channel == "" {
+		return errors.New("no channel specified")
+	}
+	if pc.chaincodes == nil || len(*pc.chaincodes) == 0 {
+		return errors.New("no chaincode specified")
+	}
+
+	interest := &discovery.ChaincodeInterest{
+		ChaincodeNames: *pc.chaincodes,
+	}
+	if pc.collections != nil {
+		interest.CollectionNames = *pc.collections
+	}
+	req := &discovery.Request{
+		Queries: []*discovery.Query{
+			{
+				Channel: channel,
+				Query: &discovery.Query_CcQuery{
+					CcQuery: &discovery.ChaincodeQuery{
+						Interests: []*discovery.ChaincodeInterest{interest},
+					},
+				},
+			},
+		},
+	}
+	res, err := pc.stub.Send(server, conf, req)
+	if err != nil {
+		return err
+	}
+	return pc.parser.ParseResponse(channel, res, outputFormat(pc.output))
+}
+
+// EndorserResponseParser parses endorsement responses
+type EndorserResponseParser struct {
+	io.Writer
+}
+
+// ParseResponse parses the given response for the given channel
+func (parser *EndorserResponseParser) ParseResponse(channel string, res *discovery.Response, format string) error {
+	ccQueryRes, errRes := res.EndorsersAt(0)
+	if errRes != nil {
+		return errors.New(errRes.Content)
+	}
+	if ccQueryRes == nil {
+		return errors.Errorf("expected a chaincode query result but got %v instead", res.Results[0])
+	}
+
+	var descriptors []*endorsementDescriptor
+	for _, desc := range ccQueryRes.Content {
+		descriptor, err := parseEndorsementDescriptor(desc)
+		if err != nil {
+			return err
+		}
+		descriptors = append(descriptors, descriptor)
+	}
+
+	if format == TableFormat {
+		return writeTable(parser.Writer, endorsersTable(descriptors))
+	}
+	return writeJSON(parser.Writer, descriptors)
+}
+
+// endorsementDescriptor is the printable form of an endorsement descriptor
+// returned from the discovery service
+type endorsementDescriptor struct {
+	Chaincode         string
+	EndorsersByGroups map[string][]*peer
+	Layouts           []*layout
+}
+
+type layout struct {
+	QuantitiesByGroup map[string]uint32
+}
+
+func parseEndorsementDescriptor(desc *discovery.EndorsementDescriptor) (*endorsementDescriptor, error) {
+	res := &endorsementDescriptor{
+		Chaincode:         desc.Chaincode,
+		EndorsersByGroups: make(map[string][]*peer),
+	}
+	for grp, endorsers := range desc.EndorsersByGroups {
+		var peers []*peer
+		for _, p := range endorsers.Peers {
+			peer, err := parsePeer(p, true)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed parsing endorser of group %s", grp)
+			}
+			peers = append(peers, peer)
+		}
+		sortPeers(peers)
+		res.EndorsersByGroups[grp] = peers
+	}
+	for _, l := range desc.Layouts {
+		for grp := range l.QuantitiesByGroup {
+			if _, exists := desc.EndorsersByGroups[grp]; !exists {
+				return nil, errors.Errorf("group %s isn't mapped to endorsers, but exists in a layout", grp)
+			}
+		}
+		res.Layouts = append(res.Layouts, &layout{QuantitiesByGroup: l.QuantitiesByGroup})
+	}
+	return res, nil
+}
+
+func endorsersTable(descriptors []*endorsementDescriptor) [][]string {
+	rows := [][]string{{"CHAINCODE", "GROUP", "MSP ID", "ENDPOINT", "LEDGER HEIGHT"}}
+	for _, desc := range descriptors {
+		for _, grp := range sortedGroups(desc.EndorsersByGroups) {
+			for _, p := range desc.EndorsersByGroups[grp] {
+				rows = append(rows, []string{desc.Chaincode, grp, p.MSPID, p.Endpoint, fmt.Sprintf("%d", p.LedgerHeight)})
+			}
+		}
+	}
+	rows = append(rows, []string{})
+	rows = append(rows, []string{"CHAINCODE", "LAYOUT"})
+	for _, desc := range descriptors {
+		for _, l := range desc.Layouts {
+			var groups []string
+			for grp := range l.QuantitiesByGroup {
+				groups = append(groups, grp)
+			}
+			sort.Strings(groups)
+			var quantities []string
+			for _, grp := range groups {
+				quantities = append(quantities, fmt.Sprintf("%s:%d", grp, l.QuantitiesByGroup[grp]))
+			}
+			rows = append(rows, []string{desc.Chaincode, strings.Join(quantities, ",")})
+		}
+	}
+	return rows
+}
+
+func sortedGroups(endorsersByGroups map[string][]*peer) []string {
+	var groups []string
+	for grp := range endorsersByGroups {
+		groups = append(groups, grp)
+	}
+	sort.Strings(groups)
+	return groups
+}
@@ -0,0 +1,160 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discovery
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hyperledger/fabric/cmd/common"
+	"github.com/hyperledger/fabric/discovery/cmd/mocks"
+	"github.com/hyperledger/fabric/protos/discovery"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestEndorserCmd(t *testing.T) {
+	server := "peer0"
+	channel := "mychannel"
+	stub := &mocks.Stub{}
+	parser := &mocks.ResponseParser{}
+	cmd := NewEndorsersCmd(stub, parser)
+	response := &discovery.Response{}
+
+	t.Run("no server supplied", func(t *testing.T) {
+		assert.EqualError(t, cmd.Execute(common.Config{}), "no server specified")
+	})
+
+	t.Run("no channel supplied", func(t *testing.T) {
+		cmd.server = &server
+		assert.EqualError(t, cmd.Execute(common.Config{}), "no channel specified")
+	})
+
+	t.Run("no chaincode supplied", func(t *testing.T) {
+		cmd.channel = &channel
+		assert.EqualError(t, cmd.Execute(common.Config{}), "no chaincode specified")
+	})
+
+	t.Run("chaincode-to-chaincode call with collections", func(t *testing.T) {
+		chaincodes := []string{"mycc", "yourcc"}
+		collections := []string{"col1", "col2"}
+		output := TableFormat
+		cmd.chaincodes = &chaincodes
+		cmd.collections = &collections
+		cmd.output = &output
+		stub.On("Send", server, common.Config{}, mock.MatchedBy(func(req *discovery.Request) bool {
+			interests := req.Queries[0].GetCcQuery().Interests
+			return req.Queries[0].Channel == channel && len(interests) == 1 &&
+				assert.ObjectsAreEqual(chaincodes, interests[0].ChaincodeNames) &&
+				assert.ObjectsAreEqual(collections, interests[0].CollectionNames)
+		})).Return(response, nil).Once()
+		parser.On("ParseResponse", channel, response, TableFormat).Return(nil).Once()
+		assert.NoError(t, cmd.Execute(common.Config{}))
+	})
+
+	t.Run("server returns an error", func(t *testing.T) {
+		stub.On("Send", server, common.Config{}, mock.Anything).Return(nil, errors.New("deadline exceeded")).Once()
+		assert.EqualError(t, cmd.Execute(common.Config{}), "deadline exceeded")
+	})
+
+	stub.AssertExpectations(t)
+	parser.AssertExpectations(t)
+}
+
+func TestEndorserResponseParser(t *testing.T) {
+	descriptor := &discovery.EndorsementDescriptor{
+		Chaincode: "mycc",
+		EndorsersByGroups: map[string]*discovery.Peers{
+			"G0": {Peers: []*discovery.Peer{newPeer("Org1MSP", 1, true, "mycc"), newPeer("Org1MSP", 0, true, "mycc")}},
+			"G1": {Peers: []*discovery.Peer{newPeer("Org2MSP", 0, true, "mycc")}},
+		},
+		Layouts: []*discovery.Layout{
+			{QuantitiesByGroup: map[string]uint32{"G0": 2}},
+			{QuantitiesByGroup: map[string]uint32{"G1": 1, "G0": 1}},
+		},
+	}
+	res := &discovery.Response{
+		Results: []*discovery.QueryResult{
+			{
+				Result: &discovery.QueryResult_CcQueryRes{
+					CcQueryRes: &discovery.ChaincodeQueryResult{
+						Content: []*discovery.EndorsementDescriptor{descriptor},
+					},
+				},
+			},
+		},
+	}
+
+	t.Run("json", func(t *testing.T) {
+		buff := &bytes.Buffer{}
+		parser := &EndorserResponseParser{Writer: buff}
+		assert.NoError(t, parser.ParseResponse("mychannel", res, JSONFormat))
+		assert.Contains(t, buff.String(), `"Chaincode": "mycc"`)
+		assert.Contains(t, buff.String(), `"G1": [
+				{
+					"MSPID": "Org2MSP",
+					"LedgerHeight": 100,
+					"Endpoint": "p0.Org2MSP:7051",
+					"Identity": "identity of p0.Org2MSP",
+					"Chaincodes": [
+						"mycc"
+					]
+				}
+			]`)
+		assert.Contains(t, buff.String(), `"Layouts": [
+			{
+				"QuantitiesByGroup": {
+					"G0": 2
+				}
+			},
+			{
+				"QuantitiesByGroup": {
+					"G0": 1,
+					"G1": 1
+				}
+			}
+		]`)
+	})
+
+	t.Run("table", func(t *testing.T) {
+		buff := &bytes.Buffer{}
+		parser := &EndorserResponseParser{Writer: buff}
+		assert.NoError(t, parser.ParseResponse("mychannel", res, TableFormat))
+		assert.Equal(t, ""+
+			"CHAINCODE  GROUP  MSP ID   ENDPOINT         LEDGER HEIGHT\n"+
+			"mycc       G0     Org1MSP  p0.Org1MSP:7051  100\n"+
+			"mycc       G0     Org1MSP  p1.Org1MSP:7051  101\n"+
+			"mycc       G1     Org2MSP  p0.Org2MSP:7051  100\n"+
+			"\n"+
+			"CHAINCODE  LAYOUT\n"+
+			"mycc       G0:2\n"+
+			"mycc       G0:1,G1:1\n", buff.String())
+	})
+
+	t.Run("error response", func(t *testing.T) {
+		errRes := &discovery.Response{
+			Results: []*discovery.QueryResult{
+				{Result: &discovery.QueryResult_Error{Error: &discovery.Error{Content: "failed constructing descriptor for chaincodes:<name:\"mycc\" > "}}},
+			},
+		}
+		parser := &EndorserResponseParser{Writer: &bytes.Buffer{}}
+		assert.EqualError(t, parser.ParseResponse("mychannel", errRes, JSONFormat), "failed constructing descriptor for chaincodes:<name:\"mycc\" > ")
+	})
+
+	t.Run("layout with an unknown group", func(t *testing.T) {
+		descriptor.Layouts = append(descriptor.Layouts, &discovery.Layout{QuantitiesByGroup: map[string]uint32{"G2": 1}})
+		parser := &EndorserResponseParser{Writer: &bytes.Buffer{}}
+		assert.EqualError(t, parser.ParseResponse("mychannel", res, JSONFormat), "group G2 isn't mapped to endorsers, but exists in a layout")
+	})
+
+	t.Run("endorser without stateInfo", func(t *testing.T) {
+		descriptor.EndorsersByGroups["G1"] = &discovery.Peers{Peers: []*discovery.Peer{newPeer("Org2MSP", 0, false)}}
+		parser := &EndorserResponseParser{Writer: &bytes.Buffer{}}
+		assert.EqualError(t, parser.ParseResponse("mychannel", res, JSONFormat), "failed parsing endorser of group G1: received an empty stateInfo message")
+	})
+}
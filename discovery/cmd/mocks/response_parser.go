@@ -0,0 +1,30 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Code generated by mockery v1.0.0
+package mocks
+
+import discovery "github.com/hyperledger/fabric/protos/discovery"
+import mock "github.com/stretchr/testify/mock"
+
+// ResponseParser is an autogenerated mock type for the ResponseParser type
+type ResponseParser struct {
+	mock.Mock
+}
+
+// ParseResponse provides a mock function with given fields: channel, response, format
+func (_m *ResponseParser) ParseResponse(channel string, response *discovery.Response, format string) error {
+	ret := _m.Called(channel, response, format)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, *discovery.Response, string) error); ok {
+		r0 = rf(channel, response, format)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -0,0 +1,40 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Code generated by mockery v1.0.0
+package mocks
+
+import common "github.com/hyperledger/fabric/cmd/common"
+import discovery "github.com/hyperledger/fabric/protos/discovery"
+import mock "github.com/stretchr/testify/mock"
+
+// Stub is an autogenerated mock type for the Stub type
+type Stub struct {
+	mock.Mock
+}
+
+// Send provides a mock function with given fields: server, conf, req
+func (_m *Stub) Send(server string, conf common.Config, req *discovery.Request) (*discovery.Response, error) {
+	ret := _m.Called(server, conf, req)
+
+	var r0 *discovery.Response
+	if rf, ok := ret.Get(0).(func(string, common.Config, *discovery.Request) *discovery.Response); ok {
+		r0 = rf(server, conf, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*discovery.Response)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, common.Config, *discovery.Request) error); ok {
+		r1 = rf(server, conf, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,61 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discovery
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+)
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func outputFormat(format *string) string {
+	if format == nil || *format == "" {
+		return JSONFormat
+	}
+	return *format
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		return errors.Wrap(err, "failed marshaling response to JSON")
+	}
+	_, err = fmt.Fprintln(w, string(b))
+	return err
+}
+
+// writeTable writes the given rows as a table, the first row being the header
+func writeTable(w io.Writer, rows [][]string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// sortPeers sorts the peers by their MSP ID and then by their endpoints,
+// so the output is stable across invocations
+func sortPeers(peers []*peer) {
+	sort.Slice(peers, func(i, j int) bool {
+		if peers[i].MSPID != peers[j].MSPID {
+			return peers[i].MSPID < peers[j].MSPID
+		}
+		return peers[i].Endpoint < peers[j].Endpoint
+	})
+}
@@ -0,0 +1,150 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discovery
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/cmd/common"
+	"github.com/hyperledger/fabric/protos/discovery"
+	"github.com/hyperledger/fabric/protos/msp"
+	"github.com/pkg/errors"
+)
+
+// NewPeerCmd creates a new PeerCmd
+func NewPeerCmd(stub Stub, parser ResponseParser) *PeerCmd {
+	return &PeerCmd{
+		stub:   stub,
+		parser: parser,
+	}
+}
+
+// PeerCmd executes peer listing command
+type PeerCmd struct {
+	stub    Stub
+	server  *string
+	channel *string
+	output  *string
+	parser  ResponseParser
+}
+
+// Execute executes the command. If no channel was specified, the local
+// peers of the server are queried
+func (pc *PeerCmd) Execute(conf common.Config) error {
+	channel := stringValue(pc.channel)
+	server := stringValue(pc.server)
+	if server == "" {
+		return errors.New("no server specified")
+	}
+
+	query := &discovery.Query{Channel: channel}
+	if channel == "" {
+		query.Query = &discovery.Query_LocalPeers{LocalPeers: &discovery.LocalPeerQuery{}}
+	} else {
+		query.Query = &discovery.Query_PeerQuery{PeerQuery: &discovery.PeerMembershipQuery{}}
+	}
+	req := &discovery.Request{Queries: []*discovery.Query{query}}
+
+	res, err := pc.stub.Send(server, conf, req)
+	if err != nil {
+		return err
+	}
+	return pc.parser.ParseResponse(channel, res, outputFormat(pc.output))
+}
+
+// PeerResponseParser parses a peer response
+type PeerResponseParser struct {
+	io.Writer
+}
+
+// ParseResponse parses the given response about the given channel
+func (parser *PeerResponseParser) ParseResponse(channel string, res *discovery.Response, format string) error {
+	membership, errRes := res.MembershipAt(0)
+	if errRes != nil {
+		return errors.New(errRes.Content)
+	}
+	if membership == nil {
+		return errors.Errorf("expected a peer membership result but got %v instead", res.Results[0])
+	}
+
+	var peers []*peer
+	for _, peersOfOrg := range membership.PeersByOrg {
+		for _, p := range peersOfOrg.Peers {
+			peer, err := parsePeer(p, channel != "")
+			if err != nil {
+				return err
+			}
+			peers = append(peers, peer)
+		}
+	}
+	sortPeers(peers)
+
+	if format == TableFormat {
+		return writeTable(parser.Writer, peersTable(peers))
+	}
+	return writeJSON(parser.Writer, peers)
+}
+
+// peer is the printable form of a peer returned from the discovery service
+type peer struct {
+	MSPID        string
+	LedgerHeight uint64
+	Endpoint     string
+	Identity     string
+	Chaincodes   []string
+}
+
+func parsePeer(p *discovery.Peer, withStateInfo bool) (*peer, error) {
+	sID := &msp.SerializedIdentity{}
+	if err := proto.Unmarshal(p.Identity, sID); err != nil {
+		return nil, errors.Wrap(err, "failed unmarshaling peer's identity")
+	}
+	res := &peer{
+		MSPID:    sID.Mspid,
+		Identity: string(sID.IdBytes),
+	}
+
+	if p.MembershipInfo == nil {
+		return nil, errors.New("received an empty alive message")
+	}
+	aliveMsg, err := p.MembershipInfo.ToGossipMessage()
+	if err != nil || aliveMsg.GetAliveMsg() == nil {
+		return nil, errors.Errorf("failed parsing alive message: %v", err)
+	}
+	if membership := aliveMsg.GetAliveMsg().Membership; membership != nil {
+		res.Endpoint = membership.Endpoint
+	}
+
+	if !withStateInfo {
+		return res, nil
+	}
+	if p.StateInfo == nil {
+		return nil, errors.New("received an empty stateInfo message")
+	}
+	stateInfoMsg, err := p.StateInfo.ToGossipMessage()
+	if err != nil || stateInfoMsg.GetStateInfo() == nil {
+		return nil, errors.Errorf("failed parsing stateInfo message: %v", err)
+	}
+	if props := stateInfoMsg.GetStateInfo().Properties; props != nil {
+		res.LedgerHeight = props.LedgerHeight
+		for _, cc := range props.Chaincodes {
+			res.Chaincodes = append(res.Chaincodes, cc.Name)
+		}
+	}
+	return res, nil
+}
+
+func peersTable(peers []*peer) [][]string {
+	rows := [][]string{{"MSP ID", "ENDPOINT", "LEDGER HEIGHT", "CHAINCODES"}}
+	for _, p := range peers {
+		rows = append(rows, []string{p.MSPID, p.Endpoint, fmt.Sprintf("%d", p.LedgerHeight), strings.Join(p.Chaincodes, ",")})
+	}
+	return rows
+}
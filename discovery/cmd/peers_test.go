@@ -0,0 +1,215 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discovery
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/fabric/cmd/common"
+	"github.com/hyperledger/fabric/discovery/cmd/mocks"
+	"github.com/hyperledger/fabric/protos/discovery"
+	"github.com/hyperledger/fabric/protos/gossip"
+	"github.com/hyperledger/fabric/protos/msp"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPeerCmd(t *testing.T) {
+	server := "peer0"
+	channel := "mychannel"
+	stub := &mocks.Stub{}
+	parser := &mocks.ResponseParser{}
+	cmd := NewPeerCmd(stub, parser)
+	response := &discovery.Response{}
+
+	t.Run("no server supplied", func(t *testing.T) {
+		err := cmd.Execute(common.Config{})
+		assert.EqualError(t, err, "no server specified")
+	})
+
+	t.Run("local peers query", func(t *testing.T) {
+		cmd.server = &server
+		stub.On("Send", server, common.Config{}, mock.MatchedBy(func(req *discovery.Request) bool {
+			return req.Queries[0].GetLocalPeers() != nil && req.Queries[0].Channel == ""
+		})).Return(response, nil).Once()
+		parser.On("ParseResponse", "", response, JSONFormat).Return(nil).Once()
+		assert.NoError(t, cmd.Execute(common.Config{}))
+	})
+
+	t.Run("channel peers query", func(t *testing.T) {
+		output := TableFormat
+		cmd.channel = &channel
+		cmd.output = &output
+		stub.On("Send", server, common.Config{}, mock.MatchedBy(func(req *discovery.Request) bool {
+			return req.Queries[0].GetPeerQuery() != nil && req.Queries[0].Channel == channel
+		})).Return(response, nil).Once()
+		parser.On("ParseResponse", channel, response, TableFormat).Return(nil).Once()
+		assert.NoError(t, cmd.Execute(common.Config{}))
+	})
+
+	t.Run("server returns an error", func(t *testing.T) {
+		stub.On("Send", server, common.Config{}, mock.Anything).Return(nil, errors.New("deadline exceeded")).Once()
+		assert.EqualError(t, cmd.Execute(common.Config{}), "deadline exceeded")
+	})
+
+	stub.AssertExpectations(t)
+	parser.AssertExpectations(t)
+}
+
+func TestPeerResponseParser(t *testing.T) {
+	res := &discovery.Response{
+		Results: []*discovery.QueryResult{
+			{
+				Result: &discovery.QueryResult_Members{
+					Members: &discovery.PeerMembershipResult{
+						PeersByOrg: map[string]*discovery.Peers{
+							"Org2MSP": {Peers: []*discovery.Peer{newPeer("Org2MSP", 0, true, "mycc")}},
+							"Org1MSP": {Peers: []*discovery.Peer{newPeer("Org1MSP", 1, true, "mycc", "yourcc"), newPeer("Org1MSP", 0, true)}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	t.Run("json", func(t *testing.T) {
+		buff := &bytes.Buffer{}
+		parser := &PeerResponseParser{Writer: buff}
+		assert.NoError(t, parser.ParseResponse("mychannel", res, JSONFormat))
+		assert.Equal(t, `[
+	{
+		"MSPID": "Org1MSP",
+		"LedgerHeight": 100,
+		"Endpoint": "p0.Org1MSP:7051",
+		"Identity": "identity of p0.Org1MSP",
+		"Chaincodes": null
+	},
+	{
+		"MSPID": "Org1MSP",
+		"LedgerHeight": 101,
+		"Endpoint": "p1.Org1MSP:7051",
+		"Identity": "identity of p1.Org1MSP",
+		"Chaincodes": [
+			"mycc",
+			"yourcc"
+		]
+	},
+	{
+		"MSPID": "Org2MSP",
+		"LedgerHeight": 100,
+		"Endpoint": "p0.Org2MSP:7051",
+		"Identity": "identity of p0.Org2MSP",
+		"Chaincodes": [
+			"mycc"
+		]
+	}
+]
+`, buff.String())
+	})
+
+	t.Run("table", func(t *testing.T) {
+		buff := &bytes.Buffer{}
+		parser := &PeerResponseParser{Writer: buff}
+		assert.NoError(t, parser.ParseResponse("mychannel", res, TableFormat))
+		assert.Equal(t, ""+
+			"MSP ID   ENDPOINT         LEDGER HEIGHT  CHAINCODES\n"+
+			"Org1MSP  p0.Org1MSP:7051  100            \n"+
+			"Org1MSP  p1.Org1MSP:7051  101            mycc,yourcc\n"+
+			"Org2MSP  p0.Org2MSP:7051  100            mycc\n", buff.String())
+	})
+
+	t.Run("local peers", func(t *testing.T) {
+		localRes := &discovery.Response{
+			Results: []*discovery.QueryResult{
+				{
+					Result: &discovery.QueryResult_Members{
+						Members: &discovery.PeerMembershipResult{
+							PeersByOrg: map[string]*discovery.Peers{
+								"Org1MSP": {Peers: []*discovery.Peer{newPeer("Org1MSP", 0, false)}},
+							},
+						},
+					},
+				},
+			},
+		}
+		buff := &bytes.Buffer{}
+		parser := &PeerResponseParser{Writer: buff}
+		assert.NoError(t, parser.ParseResponse("", localRes, TableFormat))
+		assert.Equal(t, ""+
+			"MSP ID   ENDPOINT         LEDGER HEIGHT  CHAINCODES\n"+
+			"Org1MSP  p0.Org1MSP:7051  0              \n", buff.String())
+	})
+
+	t.Run("error response", func(t *testing.T) {
+		errRes := &discovery.Response{
+			Results: []*discovery.QueryResult{
+				{Result: &discovery.QueryResult_Error{Error: &discovery.Error{Content: "access denied"}}},
+			},
+		}
+		parser := &PeerResponseParser{Writer: &bytes.Buffer{}}
+		assert.EqualError(t, parser.ParseResponse("mychannel", errRes, JSONFormat), "access denied")
+	})
+
+	t.Run("missing stateInfo", func(t *testing.T) {
+		badRes := &discovery.Response{
+			Results: []*discovery.QueryResult{
+				{
+					Result: &discovery.QueryResult_Members{
+						Members: &discovery.PeerMembershipResult{
+							PeersByOrg: map[string]*discovery.Peers{
+								"Org1MSP": {Peers: []*discovery.Peer{newPeer("Org1MSP", 0, false)}},
+							},
+						},
+					},
+				},
+			},
+		}
+		parser := &PeerResponseParser{Writer: &bytes.Buffer{}}
+		assert.EqualError(t, parser.ParseResponse("mychannel", badRes, JSONFormat), "received an empty stateInfo message")
+	})
+}
+
+func newPeer(mspID string, index int, withStateInfo bool, chaincodes ...string) *discovery.Peer {
+	endpoint := fmt.Sprintf("p%d.%s:7051", index, mspID)
+	aliveMsg, _ := (&gossip.GossipMessage{
+		Content: &gossip.GossipMessage_AliveMsg{
+			AliveMsg: &gossip.AliveMessage{
+				Membership: &gossip.Member{Endpoint: endpoint},
+			},
+		},
+	}).NoopSign()
+	p := &discovery.Peer{
+		Identity: utils.MarshalOrPanic(&msp.SerializedIdentity{
+			Mspid:   mspID,
+			IdBytes: []byte(fmt.Sprintf("identity of p%d.%s", index, mspID)),
+		}),
+		MembershipInfo: aliveMsg.Envelope,
+	}
+	if !withStateInfo {
+		return p
+	}
+	var ccs []*gossip.Chaincode
+	for _, cc := range chaincodes {
+		ccs = append(ccs, &gossip.Chaincode{Name: cc, Version: "1.0"})
+	}
+	stateInfoMsg, _ := (&gossip.GossipMessage{
+		Content: &gossip.GossipMessage_StateInfo{
+			StateInfo: &gossip.StateInfo{
+				Properties: &gossip.Properties{
+					LedgerHeight: uint64(100 + index),
+					Chaincodes:   ccs,
+				},
+			},
+		},
+	}).NoopSign()
+	p.StateInfo = stateInfoMsg.Envelope
+	return p
+}
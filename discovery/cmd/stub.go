@@ -0,0 +1,77 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discovery
+
+import (
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/cmd/common"
+	"github.com/hyperledger/fabric/cmd/common/comm"
+	"github.com/hyperledger/fabric/cmd/common/signer"
+	"github.com/hyperledger/fabric/protos/discovery"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+var defaultTimeout = 10 * time.Second
+
+// ClientStub is a stub that communicates with the discovery service
+type ClientStub struct {
+	timeout time.Duration
+}
+
+// NewClientStub creates a new ClientStub
+func NewClientStub() *ClientStub {
+	return &ClientStub{timeout: defaultTimeout}
+}
+
+// Send signs the request with the identity of the user, sends it to the
+// discovery service running at the given server, and returns the response
+func (stub *ClientStub) Send(server string, conf common.Config, req *discovery.Request) (*discovery.Response, error) {
+	client, err := comm.NewClient(conf.TLSConfig)
+	if err != nil {
+		return nil, err
+	}
+	userSigner, err := signer.NewSigner(conf.SignerConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Authentication = &discovery.AuthInfo{
+		ClientIdentity:    userSigner.Creator,
+		ClientTlsCertHash: client.TLSCertHash,
+	}
+	payload, err := proto.Marshal(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed marshaling request")
+	}
+	sig, err := userSigner.Sign(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed signing request")
+	}
+
+	conn, err := client.NewDialer(server)()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed connecting to %s", server)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), stub.timeout)
+	defer cancel()
+	resp, err := discovery.NewDiscoveryClient(conn).Discover(ctx, &discovery.SignedRequest{
+		Payload:   payload,
+		Signature: sig,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "discovery service refused our request")
+	}
+	if n := len(resp.Results); n != len(req.Queries) {
+		return nil, errors.Errorf("sent %d queries but received %d responses back", len(req.Queries), n)
+	}
+	return resp, nil
+}
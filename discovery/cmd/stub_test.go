@@ -0,0 +1,111 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discovery
+
+import (
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/bccsp/utils"
+	"github.com/hyperledger/fabric/cmd/common"
+	"github.com/hyperledger/fabric/cmd/common/signer"
+	util2 "github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/comm"
+	"github.com/hyperledger/fabric/core/config/configtest"
+	"github.com/hyperledger/fabric/protos/discovery"
+	"github.com/hyperledger/fabric/protos/msp"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+func TestClientStub(t *testing.T) {
+	srv, err := comm.NewGRPCServer("127.0.0.1:", comm.ServerConfig{
+		SecOpts: &comm.SecureOptions{},
+	})
+	assert.NoError(t, err)
+	svc := &discoveryService{t: t}
+	discovery.RegisterDiscoveryServer(srv.Server(), svc)
+	go srv.Start()
+	defer srv.Stop()
+
+	mspDir, err := configtest.GetDevMspDir()
+	assert.NoError(t, err)
+	svc.mspDir = mspDir
+	conf := common.Config{
+		SignerConfig: signer.Config{
+			MSPID:  "SampleOrg",
+			MSPDir: mspDir,
+		},
+	}
+	req := &discovery.Request{
+		Queries: []*discovery.Query{
+			{
+				Channel: "mychannel",
+				Query:   &discovery.Query_ConfigQuery{ConfigQuery: &discovery.ConfigQuery{}},
+			},
+		},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		svc.response = &discovery.Response{
+			Results: []*discovery.QueryResult{
+				{Result: &discovery.QueryResult_ConfigResult{ConfigResult: &discovery.ConfigResult{}}},
+			},
+		}
+		res, err := NewClientStub().Send(srv.Address(), conf, req)
+		assert.NoError(t, err)
+		assert.True(t, proto.Equal(svc.response, res))
+	})
+
+	t.Run("wrong number of results", func(t *testing.T) {
+		svc.response = &discovery.Response{}
+		_, err := NewClientStub().Send(srv.Address(), conf, req)
+		assert.EqualError(t, err, "sent 1 queries but received 0 responses back")
+	})
+
+	t.Run("bad signer config", func(t *testing.T) {
+		badConf := conf
+		badConf.SignerConfig.MSPDir = filepath.Join("testdata", "non_existent_msp")
+		_, err := NewClientStub().Send(srv.Address(), badConf, req)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "could not load a valid signer certificate from directory")
+	})
+}
+
+type discoveryService struct {
+	t        *testing.T
+	mspDir   string
+	response *discovery.Response
+}
+
+// Discover verifies the request is signed by the identity in its
+// authentication info and returns the predefined response
+func (ds *discoveryService) Discover(_ context.Context, sr *discovery.SignedRequest) (*discovery.Response, error) {
+	req, err := sr.ToRequest()
+	assert.NoError(ds.t, err)
+	sID := &msp.SerializedIdentity{}
+	assert.NoError(ds.t, proto.Unmarshal(req.Authentication.ClientIdentity, sID))
+	assert.Equal(ds.t, "SampleOrg", sID.Mspid)
+
+	// the request is signed by the signing identity of the local MSP
+	signcert, err := ioutil.ReadFile(filepath.Join(ds.mspDir, "signcerts", "peer.pem"))
+	assert.NoError(ds.t, err)
+	expected, _ := pem.Decode(signcert)
+	bl, _ := pem.Decode(sID.IdBytes)
+	assert.Equal(ds.t, expected.Bytes, bl.Bytes)
+	cert, err := x509.ParseCertificate(bl.Bytes)
+	assert.NoError(ds.t, err)
+	r, s, err := utils.UnmarshalECDSASignature(sr.Signature)
+	assert.NoError(ds.t, err)
+	assert.True(ds.t, ecdsa.Verify(cert.PublicKey.(*ecdsa.PublicKey), util2.ComputeSHA256(sr.Payload), r, s))
+	return ds.response, nil
+}
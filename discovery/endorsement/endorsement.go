@@ -7,7 +7,11 @@ SPDX-License-Identifier: Apache-2.0
 package endorsement
 
 import (
+	"bytes"
 	"fmt"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
 
 	"github.com/hyperledger/fabric/common/chaincode"
 	"github.com/hyperledger/fabric/common/flogging"
@@ -16,6 +20,7 @@ import (
 	"github.com/hyperledger/fabric/gossip/api"
 	"github.com/hyperledger/fabric/gossip/common"
 	discovery2 "github.com/hyperledger/fabric/gossip/discovery"
+	common2 "github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/discovery"
 	"github.com/hyperledger/fabric/protos/msp"
 	"github.com/pkg/errors"
@@ -77,15 +82,27 @@ func NewEndorsementAnalyzer(gs gossipSupport, pf policyFetcher, pe principalEval
 
 type peerPrincipalEvaluator func(member discovery2.NetworkMember, principal *msp.MSPPrincipal) bool
 
-// PeersForEndorsement returns an EndorsementDescriptor for a given set of peers, channel, and chaincode
+// PeersForEndorsement returns an EndorsementDescriptor for a given set of peers, channel, and chaincode interest.
+// When the interest contains several chaincodes (a chaincode to chaincode invocation), the layouts satisfy
+// the endorsement policies of all of them. When the interest contains collections, only peers that are
+// members of all the collections are considered as endorsers
 func (ea *endorsementAnalyzer) PeersForEndorsement(chainID common.ChainID, interest *discovery.ChaincodeInterest) (*discovery.EndorsementDescriptor, error) {
-	chaincode := interest.ChaincodeNames[0]
-	ccMD := ea.Metadata(string(chainID), chaincode)
-	if ccMD == nil {
-		return nil, errors.Errorf("No metadata was found for chaincode %s in channel %s", chaincode, string(chainID))
+	if len(interest.ChaincodeNames) == 0 {
+		return nil, errors.New("no chaincode specified in the interest")
+	}
+	var metadata []*chaincode.Metadata
+	for _, cc := range interest.ChaincodeNames {
+		ccMD := ea.Metadata(string(chainID), cc)
+		if ccMD == nil {
+			return nil, errors.Errorf("No metadata was found for chaincode %s in channel %s", cc, string(chainID))
+		}
+		metadata = append(metadata, ccMD)
+	}
+	// Filter out peers that don't have the chaincodes installed on them
+	chanMembership := ea.PeersOfChannel(chainID)
+	for _, ccMD := range metadata {
+		chanMembership = chanMembership.Filter(peersWithChaincode(ccMD))
 	}
-	// Filter out peers that don't have the chaincode installed on them
-	chanMembership := ea.PeersOfChannel(chainID).Filter(peersWithChaincode(ccMD))
 	channelMembersById := chanMembership.ByID()
 	// Choose only the alive messages of those that have joined the channel
 	aliveMembership := ea.Peers().Intersect(chanMembership)
@@ -94,15 +111,21 @@ func (ea *endorsementAnalyzer) PeersForEndorsement(chainID common.ChainID, inter
 	identities := ea.IdentityInfo()
 	identitiesOfMembers := computeIdentitiesOfMembers(identities, membersById)
 
-	// Retrieve the policy for the chaincode
-	pol := ea.PolicyByChaincode(string(chainID), chaincode)
-	if pol == nil {
-		logger.Debug("Policy for chaincode '", chaincode, "'doesn't exist")
-		return nil, errors.New("policy not found")
+	// Compute the combinations of principals (principal sets) that satisfy the endorsement policies
+	principalsSets, err := ea.principalSetsOfChaincodes(string(chainID), interest.ChaincodeNames)
+	if err != nil {
+		return nil, err
 	}
 
-	// Compute the combinations of principals (principal sets) that satisfy the endorsement policy
-	principalsSets := policies.PrincipalSets(pol.SatisfiedBy())
+	// Filter out peers that aren't members of the collections
+	if len(interest.CollectionNames) > 0 {
+		collPrincipals, err := collectionsMemberPrincipals(metadata, interest.CollectionNames)
+		if err != nil {
+			return nil, err
+		}
+		aliveMembership = aliveMembership.Filter(ea.membersOfCollections(string(chainID), identitiesOfMembers, collPrincipals))
+		membersById = aliveMembership.ByID()
+	}
 
 	// Obtain the MSP IDs of the members of the channel that are alive
 	mspIDsOfChannelPeers := mspIDsOfMembers(membersById, identities.ByID())
@@ -137,12 +160,53 @@ func (ea *endorsementAnalyzer) PeersForEndorsement(chainID common.ChainID, inter
 	}
 
 	return &discovery.EndorsementDescriptor{
-		Chaincode:         chaincode,
+		Chaincode:         interest.ChaincodeNames[0],
 		Layouts:           layouts,
 		EndorsersByGroups: endorsersByGroup(criteria),
 	}, nil
 }
 
+// principalSetsOfChaincodes returns the principal sets that satisfy the endorsement
+// policies of all the given chaincodes
+func (ea *endorsementAnalyzer) principalSetsOfChaincodes(channel string, chaincodes []string) (policies.PrincipalSets, error) {
+	var principalsSets policies.PrincipalSets
+	for i, cc := range chaincodes {
+		pol := ea.PolicyByChaincode(channel, cc)
+		if pol == nil {
+			logger.Debug("Policy for chaincode '", cc, "'doesn't exist")
+			return nil, errors.New("policy not found")
+		}
+		ccPrincipalSets := policies.PrincipalSets(pol.SatisfiedBy())
+		if i == 0 {
+			principalsSets = ccPrincipalSets
+			continue
+		}
+		principalsSets = mergePrincipalSets(principalsSets, ccPrincipalSets)
+	}
+	return principalsSets, nil
+}
+
+// membersOfCollections returns a predicate that accepts the peers that satisfy
+// at least one principal of each of the given principal lists
+func (ea *endorsementAnalyzer) membersOfCollections(channel string, identitiesOfMembers memberIdentities, collPrincipals [][]*msp.MSPPrincipal) func(member discovery2.NetworkMember) bool {
+	satisfiesPrincipal := ea.satisfiesPrincipal(channel, identitiesOfMembers)
+	return func(member discovery2.NetworkMember) bool {
+		for _, principals := range collPrincipals {
+			isMember := false
+			for _, principal := range principals {
+				if satisfiesPrincipal(member, principal) {
+					isMember = true
+					break
+				}
+			}
+			if !isMember {
+				return false
+			}
+		}
+		return true
+	}
+}
+
 func (ea *endorsementAnalyzer) satisfiesPrincipal(channel string, identitiesOfMembers memberIdentities) peerPrincipalEvaluator {
 	return func(member discovery2.NetworkMember, principal *msp.MSPPrincipal) bool {
 		err := ea.SatisfiesPrincipal(channel, identitiesOfMembers.identityByPKIID(member.PKIid), principal)
@@ -382,3 +446,114 @@ func mspIDsOfMembers(membersById map[string]discovery2.NetworkMember, identities
 	}
 	return res
 }
+
+// mergePrincipalSets returns the principal sets that satisfy both the policy that
+// is satisfied by the principal sets of s1 and the policy that is satisfied by the
+// principal sets of s2. Each principal set of the result combines a principal set
+// of s1 with a principal set of s2, such that each principal appears as many
+// times as it appears at most in either of them
+func mergePrincipalSets(s1, s2 policies.PrincipalSets) policies.PrincipalSets {
+	var res policies.PrincipalSets
+	seen := make(map[string]struct{})
+	for _, set1 := range s1 {
+		for _, set2 := range s2 {
+			merged := mergePrincipalSet(set1, set2)
+			key := principalSetKey(merged)
+			if _, exists := seen[key]; exists {
+				continue
+			}
+			seen[key] = struct{}{}
+			res = append(res, merged)
+		}
+	}
+	return res
+}
+
+func mergePrincipalSet(set1, set2 policies.PrincipalSet) policies.PrincipalSet {
+	pluralities := make(map[principalKey]int)
+	var keys []principalKey
+	for _, set := range []policies.PrincipalSet{set1, set2} {
+		for principal, plurality := range set.UniqueSet() {
+			key := principalKey{
+				cls:       int32(principal.PrincipalClassification),
+				principal: string(principal.Principal),
+			}
+			if _, exists := pluralities[key]; !exists {
+				keys = append(keys, key)
+			}
+			if plurality > pluralities[key] {
+				pluralities[key] = plurality
+			}
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].cls != keys[j].cls {
+			return keys[i].cls < keys[j].cls
+		}
+		return keys[i].principal < keys[j].principal
+	})
+
+	var merged policies.PrincipalSet
+	for _, key := range keys {
+		for i := 0; i < pluralities[key]; i++ {
+			merged = append(merged, key.toPrincipal())
+		}
+	}
+	return merged
+}
+
+// principalSetKey returns a string that identifies the given principal set,
+// assuming the principals in it are sorted
+func principalSetKey(set policies.PrincipalSet) string {
+	buff := &bytes.Buffer{}
+	for _, principal := range set {
+		fmt.Fprintf(buff, "%d:%x,", principal.PrincipalClassification, principal.Principal)
+	}
+	return buff.String()
+}
+
+// collectionsMemberPrincipals returns, for each of the given collections, the principals
+// of the member orgs policy of the collection. A collection is looked up in the collection
+// configurations of all the given chaincodes, and each chaincode that defines it
+// contributes its member orgs principals
+func collectionsMemberPrincipals(metadata []*chaincode.Metadata, collections []string) ([][]*msp.MSPPrincipal, error) {
+	var res [][]*msp.MSPPrincipal
+	configsByChaincode := make(map[string]*common2.CollectionConfigPackage)
+	for _, collection := range collections {
+		found := false
+		for _, ccMD := range metadata {
+			ccp, exists := configsByChaincode[ccMD.Name]
+			if !exists {
+				ccp = &common2.CollectionConfigPackage{}
+				if err := proto.Unmarshal(ccMD.CollectionsConfig, ccp); err != nil {
+					return nil, errors.Wrapf(err, "failed unmarshaling collection configuration of chaincode %s", ccMD.Name)
+				}
+				configsByChaincode[ccMD.Name] = ccp
+			}
+			for _, conf := range ccp.Config {
+				staticConf := conf.GetStaticCollectionConfig()
+				if staticConf == nil || staticConf.Name != collection {
+					continue
+				}
+				found = true
+				policy := staticConf.MemberOrgsPolicy.GetSignaturePolicy()
+				if policy == nil || len(policy.Identities) == 0 {
+					return nil, errors.Errorf("collection %s of chaincode %s has no member orgs", collection, ccMD.Name)
+				}
+				res = append(res, policy.Identities)
+			}
+		}
+		if !found {
+			return nil, errors.Errorf("collection %s doesn't exist in the collection configurations of chaincodes %v", collection, chaincodeNames(metadata))
+		}
+	}
+	return res, nil
+}
+
+func chaincodeNames(metadata []*chaincode.Metadata) []string {
+	var names []string
+	for _, ccMD := range metadata {
+		names = append(names, ccMD.Name)
+	}
+	return names
+}
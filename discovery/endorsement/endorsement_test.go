@@ -16,9 +16,11 @@ import (
 	"github.com/hyperledger/fabric/gossip/api"
 	"github.com/hyperledger/fabric/gossip/common"
 	"github.com/hyperledger/fabric/gossip/discovery"
+	common2 "github.com/hyperledger/fabric/protos/common"
 	discovery2 "github.com/hyperledger/fabric/protos/discovery"
 	"github.com/hyperledger/fabric/protos/gossip"
 	"github.com/hyperledger/fabric/protos/msp"
+	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -196,6 +198,166 @@ func TestPeersForEndorsement(t *testing.T) {
 	assert.Equal(t, err.Error(), "No metadata was found for chaincode chaincode in channel test")
 }
 
+func TestPeersForEndorsementCC2CCAndCollections(t *testing.T) {
+	extractPeers := func(desc *discovery2.EndorsementDescriptor) map[string]struct{} {
+		res := make(map[string]struct{})
+		for _, endorsers := range desc.EndorsersByGroups {
+			for _, p := range endorsers.Peers {
+				res[string(p.Identity)] = struct{}{}
+			}
+		}
+		return res
+	}
+	principal := func(p string) *msp.MSPPrincipal {
+		return &msp.MSPPrincipal{Principal: []byte(p)}
+	}
+	collectionsConfig := func(name string, memberPrincipals ...*msp.MSPPrincipal) []byte {
+		return utils.MarshalOrPanic(&common2.CollectionConfigPackage{
+			Config: []*common2.CollectionConfig{
+				{
+					Payload: &common2.CollectionConfig_StaticCollectionConfig{
+						StaticCollectionConfig: &common2.StaticCollectionConfig{
+							Name: name,
+							MemberOrgsPolicy: &common2.CollectionPolicyConfig{
+								Payload: &common2.CollectionPolicyConfig_SignaturePolicy{
+									SignaturePolicy: &common2.SignaturePolicyEnvelope{Identities: memberPrincipals},
+								},
+							},
+						},
+					},
+				},
+			},
+		})
+	}
+
+	channel := common.ChainID("test")
+	pkiID2MSPID := map[string]string{
+		"p0": "Org1MSP",
+		"p1": "Org1MSP",
+		"p2": "Org2MSP",
+		"p3": "Org2MSP",
+		"p4": "Org3MSP",
+		"p5": "Org3MSP",
+	}
+	peers := peerSet{
+		newPeer(0).withChaincode("cc1", "1.0").withChaincode("cc2", "1.0"),
+		newPeer(1).withChaincode("cc1", "1.0"),
+		newPeer(2).withChaincode("cc1", "1.0").withChaincode("cc2", "1.0"),
+		newPeer(3).withChaincode("cc1", "1.0"),
+		newPeer(4).withChaincode("cc1", "1.0").withChaincode("cc2", "1.0"),
+		newPeer(5).withChaincode("cc1", "1.0").withChaincode("cc2", "1.0"),
+	}
+	g := &gossipMock{}
+	g.On("PeersOfChannel").Return(peers.toMembers())
+	g.On("Peers").Return(peers.toMembers())
+	g.On("IdentityInfo").Return(identitySet(pkiID2MSPID))
+
+	// cc1 requires p0 and p2, or p4.
+	// cc2 requires p2, or p4 and p5
+	pb := principalBuilder{}
+	cc1Policy := pb.newSet().addPrincipal(principal("p0")).addPrincipal(principal("p2")).
+		newSet().addPrincipal(principal("p4")).buildPolicy()
+	cc2Policy := pb.newSet().addPrincipal(principal("p2")).
+		newSet().addPrincipal(principal("p4")).addPrincipal(principal("p5")).buildPolicy()
+	pf := &policyFetcherMock{}
+	pf.On("PolicyByChaincode", "cc1").Return(cc1Policy)
+	pf.On("PolicyByChaincode", "cc2").Return(cc2Policy)
+
+	mf := metadataByName{
+		"cc1": {Name: "cc1", Version: "1.0", CollectionsConfig: collectionsConfig("col1", principal("p0"), principal("p2"), principal("p5"))},
+		"cc2": {Name: "cc2", Version: "1.0"},
+	}
+	analyzer := NewEndorsementAnalyzer(g, pf, cc1Policy.ToPrincipalEvaluator(pkiID2MSPID), mf)
+
+	// Scenario I: cc1 calls cc2, so the layouts must satisfy both policies:
+	// p0 and p2, or p0, p2, p4 and p5, or p2 and p4, or p4 and p5
+	desc, err := analyzer.PeersForEndorsement(channel, &discovery2.ChaincodeInterest{ChaincodeNames: []string{"cc1", "cc2"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "cc1", desc.Chaincode)
+	assert.Len(t, desc.Layouts, 4)
+	assert.Equal(t, map[string]struct{}{"p0": {}, "p2": {}, "p4": {}, "p5": {}}, extractPeers(desc))
+
+	// Scenario II: same as above, but the interest also involves col1 whose members are p0, p2 and p5.
+	// Only the layout of p0 and p2 can be satisfied by members of the collection
+	desc, err = analyzer.PeersForEndorsement(channel, &discovery2.ChaincodeInterest{
+		ChaincodeNames:  []string{"cc1", "cc2"},
+		CollectionNames: []string{"col1"},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, desc.Layouts, 1)
+	assert.Equal(t, map[string]struct{}{"p0": {}, "p2": {}}, extractPeers(desc))
+
+	// Scenario III: only cc1 with col1: p4 isn't a member of the collection,
+	// so the layout of p0 and p2 is the only one that can be satisfied
+	desc, err = analyzer.PeersForEndorsement(channel, &discovery2.ChaincodeInterest{
+		ChaincodeNames:  []string{"cc1"},
+		CollectionNames: []string{"col1"},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, desc.Layouts, 1)
+	assert.Equal(t, map[string]struct{}{"p0": {}, "p2": {}}, extractPeers(desc))
+
+	// Scenario IV: the collection doesn't exist
+	desc, err = analyzer.PeersForEndorsement(channel, &discovery2.ChaincodeInterest{
+		ChaincodeNames:  []string{"cc1", "cc2"},
+		CollectionNames: []string{"col2"},
+	})
+	assert.Nil(t, desc)
+	assert.EqualError(t, err, "collection col2 doesn't exist in the collection configurations of chaincodes [cc1 cc2]")
+
+	// Scenario V: cc2 isn't installed on p5, so only the layouts
+	// of p0 and p2, and of p2 and p4 can be satisfied
+	peers[5].Properties.Chaincodes = peers[5].Properties.Chaincodes[:1]
+	g = &gossipMock{}
+	g.On("PeersOfChannel").Return(peers.toMembers())
+	g.On("Peers").Return(peers.toMembers())
+	g.On("IdentityInfo").Return(identitySet(pkiID2MSPID))
+	analyzer = NewEndorsementAnalyzer(g, pf, cc1Policy.ToPrincipalEvaluator(pkiID2MSPID), mf)
+	desc, err = analyzer.PeersForEndorsement(channel, &discovery2.ChaincodeInterest{ChaincodeNames: []string{"cc1", "cc2"}})
+	assert.NoError(t, err)
+	assert.Len(t, desc.Layouts, 2)
+	assert.Equal(t, map[string]struct{}{"p0": {}, "p2": {}, "p4": {}}, extractPeers(desc))
+
+	// Scenario VI: the policy of the called chaincode isn't found
+	pf.On("PolicyByChaincode", "cc3").Return(nil)
+	mf["cc3"] = &chaincode.Metadata{Name: "cc3", Version: "1.0"}
+	desc, err = analyzer.PeersForEndorsement(channel, &discovery2.ChaincodeInterest{ChaincodeNames: []string{"cc1", "cc3"}})
+	assert.Nil(t, desc)
+	assert.EqualError(t, err, "policy not found")
+
+	// Scenario VII: the metadata of the called chaincode isn't found
+	desc, err = analyzer.PeersForEndorsement(channel, &discovery2.ChaincodeInterest{ChaincodeNames: []string{"cc1", "cc4"}})
+	assert.Nil(t, desc)
+	assert.EqualError(t, err, "No metadata was found for chaincode cc4 in channel test")
+}
+
+func TestMergePrincipalSets(t *testing.T) {
+	principal := func(p string) *msp.MSPPrincipal {
+		return &msp.MSPPrincipal{Principal: []byte(p)}
+	}
+	// (a, a, b) or (c) merged with (a, b, b) or (c)
+	s1 := policies.PrincipalSets{
+		{principal("a"), principal("a"), principal("b")},
+		{principal("c")},
+	}
+	s2 := policies.PrincipalSets{
+		{principal("a"), principal("b"), principal("b")},
+		{principal("c")},
+	}
+	assert.Equal(t, policies.PrincipalSets{
+		{principal("a"), principal("a"), principal("b"), principal("b")},
+		{principal("a"), principal("a"), principal("b"), principal("c")},
+		{principal("a"), principal("b"), principal("b"), principal("c")},
+		{principal("c")},
+	}, mergePrincipalSets(s1, s2))
+
+	// identical principal sets are merged into one
+	assert.Equal(t, policies.PrincipalSets{
+		{principal("a"), principal("b")},
+		{principal("a")},
+	}, mergePrincipalSets(policies.PrincipalSets{{principal("a")}, {principal("b"), principal("a")}}, policies.PrincipalSets{{principal("b")}, {principal("a")}}))
+}
+
 type peerSet []*peerInfo
 
 func (p peerSet) toMembers() discovery.Members {
@@ -339,3 +501,9 @@ func (mf *metadataFetcher) Metadata(channel string, cc string) *chaincode.Metada
 	}
 	return arg.(*chaincode.Metadata)
 }
+
+type metadataByName map[string]*chaincode.Metadata
+
+func (m metadataByName) Metadata(channel string, cc string) *chaincode.Metadata {
+	return m[cc]
+}
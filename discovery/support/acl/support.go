@@ -137,6 +137,14 @@ func (s *DiscoverySupport) MSPOfPrincipal(principal *msp.MSPPrincipal) string {
 			return ""
 		}
 		return ouRole.MspIdentifier
+	case msp.MSPPrincipal_IDEMIX_ATTRIBUTES:
+		attributes := &msp.IdemixAttributes{}
+		err := proto.Unmarshal(principal.Principal, attributes)
+		if err != nil {
+			logger.Warning("Failed unmarshaling principal:", err)
+			return ""
+		}
+		return attributes.MspIdentifier
 	}
 	logger.Warning("Received principal of unknown classification:", principal)
 	return ""
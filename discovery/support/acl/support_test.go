@@ -56,6 +56,14 @@ func TestMSPOfPrincipal(t *testing.T) {
 			classification: msp.MSPPrincipal_ORGANIZATION_UNIT,
 			expectedMSPID:  "Org3MSP",
 		},
+		{
+			name: "idemix attributes",
+			principal: &msp.IdemixAttributes{
+				MspIdentifier: "Org4MSP",
+			},
+			classification: msp.MSPPrincipal_IDEMIX_ATTRIBUTES,
+			expectedMSPID:  "Org4MSP",
+		},
 		{
 			name:           "unknown",
 			principal:      nil,
@@ -0,0 +1,103 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deliverclient
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Checkpointer records the number of the last block whose events were
+// dispatched, so that a client resumes from the following block after a
+// reconnect or a restart
+type Checkpointer interface {
+	// LastBlock returns the number of the last block checkpointed, and
+	// false if no block was checkpointed yet
+	LastBlock() (uint64, bool, error)
+
+	// Checkpoint records that the events of the given block were dispatched
+	Checkpoint(blockNum uint64) error
+}
+
+// MemoryCheckpointer is a Checkpointer that keeps the checkpoint in memory,
+// it resumes across reconnects but not across restarts
+type MemoryCheckpointer struct {
+	lock     sync.Mutex
+	blockNum uint64
+	set      bool
+}
+
+// LastBlock returns the number of the last block checkpointed
+func (mc *MemoryCheckpointer) LastBlock() (uint64, bool, error) {
+	mc.lock.Lock()
+	defer mc.lock.Unlock()
+	return mc.blockNum, mc.set, nil
+}
+
+// Checkpoint records the number of the given block
+func (mc *MemoryCheckpointer) Checkpoint(blockNum uint64) error {
+	mc.lock.Lock()
+	defer mc.lock.Unlock()
+	mc.blockNum = blockNum
+	mc.set = true
+	return nil
+}
+
+// FileCheckpointer is a Checkpointer that persists the checkpoint to a file
+type FileCheckpointer struct {
+	path string
+}
+
+// NewFileCheckpointer creates a FileCheckpointer that stores the checkpoint
+// at the given path
+func NewFileCheckpointer(path string) *FileCheckpointer {
+	return &FileCheckpointer{path: path}
+}
+
+// LastBlock returns the number of the last block checkpointed in the file
+func (fc *FileCheckpointer) LastBlock() (uint64, bool, error) {
+	b, err := ioutil.ReadFile(fc.path)
+	if os.IsNotExist(err) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, errors.Wrapf(err, "failed reading checkpoint file %s", fc.path)
+	}
+	blockNum, err := strconv.ParseUint(strings.TrimSpace(string(b)), 10, 64)
+	if err != nil {
+		return 0, false, errors.Wrapf(err, "checkpoint file %s is corrupted", fc.path)
+	}
+	return blockNum, true, nil
+}
+
+// Checkpoint atomically replaces the checkpoint file with the given block number
+func (fc *FileCheckpointer) Checkpoint(blockNum uint64) error {
+	tmpFile, err := ioutil.TempFile(filepath.Dir(fc.path), filepath.Base(fc.path))
+	if err != nil {
+		return errors.Wrap(err, "failed creating checkpoint file")
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.WriteString(strconv.FormatUint(blockNum, 10)); err != nil {
+		tmpFile.Close()
+		return errors.Wrap(err, "failed writing checkpoint file")
+	}
+	if err := tmpFile.Sync(); err != nil {
+		tmpFile.Close()
+		return errors.Wrap(err, "failed syncing checkpoint file")
+	}
+	if err := tmpFile.Close(); err != nil {
+		return errors.Wrap(err, "failed closing checkpoint file")
+	}
+	return errors.Wrap(os.Rename(tmpFile.Name(), fc.path), "failed replacing checkpoint file")
+}
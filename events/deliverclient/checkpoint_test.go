@@ -0,0 +1,57 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deliverclient
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryCheckpointer(t *testing.T) {
+	mc := &MemoryCheckpointer{}
+	_, exists, err := mc.LastBlock()
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	assert.NoError(t, mc.Checkpoint(0))
+	blockNum, exists, err := mc.LastBlock()
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, uint64(0), blockNum)
+}
+
+func TestFileCheckpointer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checkpoint")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "mychannel.checkpoint")
+	fc := NewFileCheckpointer(path)
+	_, exists, err := fc.LastBlock()
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	assert.NoError(t, fc.Checkpoint(41))
+	assert.NoError(t, fc.Checkpoint(42))
+
+	// a new checkpointer on the same file resumes from the last checkpoint
+	blockNum, exists, err := NewFileCheckpointer(path).LastBlock()
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, uint64(42), blockNum)
+
+	assert.NoError(t, ioutil.WriteFile(path, []byte("garbage"), 0644))
+	_, _, err = fc.LastBlock()
+	assert.Contains(t, err.Error(), "is corrupted")
+
+	fc = NewFileCheckpointer(filepath.Join(dir, "missing", "mychannel.checkpoint"))
+	assert.Error(t, fc.Checkpoint(1))
+}
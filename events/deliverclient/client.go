@@ -0,0 +1,501 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deliverclient
+
+import (
+	"math"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/common/crypto"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+var logger = flogging.MustGetLogger("events/deliverclient")
+
+const (
+	defaultReconnectInterval    = 500 * time.Millisecond
+	defaultMaxReconnectInterval = 30 * time.Second
+	defaultEventBufferSize      = 100
+)
+
+var (
+	newest  = &orderer.SeekPosition{Type: &orderer.SeekPosition_Newest{Newest: &orderer.SeekNewest{}}}
+	maxStop = &orderer.SeekPosition{Type: &orderer.SeekPosition_Specified{Specified: &orderer.SeekSpecified{Number: math.MaxUint64}}}
+)
+
+// Config holds the configuration of a Client
+type Config struct {
+	// ChannelID is the channel the events are received for
+	ChannelID string
+	// Endpoints are the peers the client receives events from. The client
+	// connects to the first one and moves on to the next on failure
+	Endpoints []string
+	// Connector opens the deliver streams to the peers
+	Connector Connector
+	// Signer signs the seek requests sent to the peers
+	Signer crypto.LocalSigner
+	// TLSCertHash is the hash of the client TLS certificate, required
+	// when the peers enforce mutual TLS
+	TLSCertHash []byte
+	// Checkpointer records the blocks processed so that the client resumes
+	// after them. Defaults to a MemoryCheckpointer
+	Checkpointer Checkpointer
+	// Start is the position to start receiving blocks from when nothing
+	// was checkpointed yet. Defaults to the newest block
+	Start *orderer.SeekPosition
+	// ReconnectInterval is the initial time to wait before reconnecting,
+	// doubled on every consecutive failure up to MaxReconnectInterval
+	ReconnectInterval    time.Duration
+	MaxReconnectInterval time.Duration
+	// EventBufferSize is the capacity of the registration channels
+	EventBufferSize int
+}
+
+// registration holds the state shared by the block and chaincode registrations.
+// Events are sent while holding lock, so that the channel of the registration
+// is not closed during a send, and done is closed on unregistration to
+// interrupt a send pending on a full channel
+type registration struct {
+	lock sync.Mutex
+	done chan struct{}
+}
+
+func newRegistration() registration {
+	return registration{done: make(chan struct{})}
+}
+
+// cancel interrupts the pending send if any, then closes the events
+// channel of the registration with closeEvents
+func (r *registration) cancel(closeEvents func()) {
+	close(r.done)
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	closeEvents()
+}
+
+// BlockRegistration is a registration for block events
+type BlockRegistration struct {
+	registration
+	events chan *BlockEvent
+}
+
+// ChaincodeRegistration is a registration for the events of a chaincode
+// whose name matches a filter
+type ChaincodeRegistration struct {
+	registration
+	chaincodeID string
+	eventFilter *regexp.Regexp
+	events      chan *ChaincodeEvent
+}
+
+// TxStatusRegistration is a registration for the status of a transaction
+type TxStatusRegistration struct {
+	txID   string
+	events chan *TxStatusEvent
+}
+
+// Client receives blocks of a channel from the Deliver or DeliverFiltered
+// services of a set of peers and dispatches block, chaincode and transaction
+// status events to the registrations
+type Client struct {
+	config Config
+
+	lock       sync.Mutex
+	blockRegs  map[*BlockRegistration]struct{}
+	ccRegs     map[*ChaincodeRegistration]struct{}
+	txRegs     map[string]*TxStatusRegistration
+	started    bool
+	ctx        context.Context
+	cancel     context.CancelFunc
+	done       chan struct{}
+	endpointID int
+}
+
+// NewClient creates a new Client with the given configuration
+func NewClient(config Config) (*Client, error) {
+	if config.ChannelID == "" {
+		return nil, errors.New("channel ID must be provided")
+	}
+	if len(config.Endpoints) == 0 {
+		return nil, errors.New("at least one endpoint must be provided")
+	}
+	if config.Connector == nil {
+		return nil, errors.New("connector must be provided")
+	}
+	if config.Signer == nil {
+		return nil, errors.New("signer must be provided")
+	}
+	if config.Checkpointer == nil {
+		config.Checkpointer = &MemoryCheckpointer{}
+	}
+	if config.Start == nil {
+		config.Start = newest
+	}
+	if config.ReconnectInterval == 0 {
+		config.ReconnectInterval = defaultReconnectInterval
+	}
+	if config.MaxReconnectInterval == 0 {
+		config.MaxReconnectInterval = defaultMaxReconnectInterval
+	}
+	if config.EventBufferSize == 0 {
+		config.EventBufferSize = defaultEventBufferSize
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Client{
+		config:    config,
+		blockRegs: make(map[*BlockRegistration]struct{}),
+		ccRegs:    make(map[*ChaincodeRegistration]struct{}),
+		txRegs:    make(map[string]*TxStatusRegistration),
+		ctx:       ctx,
+		cancel:    cancel,
+		done:      make(chan struct{}),
+	}, nil
+}
+
+// RegisterBlockEvent registers for the events of every block delivered
+func (c *Client) RegisterBlockEvent() (*BlockRegistration, <-chan *BlockEvent) {
+	reg := &BlockRegistration{
+		registration: newRegistration(),
+		events:       make(chan *BlockEvent, c.config.EventBufferSize),
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.blockRegs[reg] = struct{}{}
+	return reg, reg.events
+}
+
+// RegisterChaincodeEvent registers for the events set by the given chaincode
+// whose name matches the eventFilter regular expression
+func (c *Client) RegisterChaincodeEvent(chaincodeID, eventFilter string) (*ChaincodeRegistration, <-chan *ChaincodeEvent, error) {
+	if chaincodeID == "" {
+		return nil, nil, errors.New("chaincode ID must be provided")
+	}
+	filter, err := regexp.Compile(eventFilter)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "invalid event filter %s", eventFilter)
+	}
+
+	reg := &ChaincodeRegistration{
+		registration: newRegistration(),
+		chaincodeID:  chaincodeID,
+		eventFilter:  filter,
+		events:       make(chan *ChaincodeEvent, c.config.EventBufferSize),
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.ccRegs[reg] = struct{}{}
+	return reg, reg.events, nil
+}
+
+// RegisterTxStatus registers for the status of the given transaction. The
+// registration is removed once the status is sent
+func (c *Client) RegisterTxStatus(txID string) (*TxStatusRegistration, <-chan *TxStatusEvent, error) {
+	if txID == "" {
+		return nil, nil, errors.New("transaction ID must be provided")
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if _, exists := c.txRegs[txID]; exists {
+		return nil, nil, errors.Errorf("transaction %s is already registered", txID)
+	}
+	reg := &TxStatusRegistration{
+		txID:   txID,
+		events: make(chan *TxStatusEvent, 1),
+	}
+	c.txRegs[txID] = reg
+	return reg, reg.events, nil
+}
+
+// UnregisterBlockEvent removes the given block registration and closes its channel
+func (c *Client) UnregisterBlockEvent(reg *BlockRegistration) {
+	c.lock.Lock()
+	_, exists := c.blockRegs[reg]
+	delete(c.blockRegs, reg)
+	c.lock.Unlock()
+	if exists {
+		reg.cancel(func() { close(reg.events) })
+	}
+}
+
+// UnregisterChaincodeEvent removes the given chaincode registration and closes its channel
+func (c *Client) UnregisterChaincodeEvent(reg *ChaincodeRegistration) {
+	c.lock.Lock()
+	_, exists := c.ccRegs[reg]
+	delete(c.ccRegs, reg)
+	c.lock.Unlock()
+	if exists {
+		reg.cancel(func() { close(reg.events) })
+	}
+}
+
+// UnregisterTxStatus removes the given transaction registration and closes its channel
+func (c *Client) UnregisterTxStatus(reg *TxStatusRegistration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.txRegs[reg.txID] == reg {
+		delete(c.txRegs, reg.txID)
+		close(reg.events)
+	}
+}
+
+// Start starts receiving blocks in the background
+func (c *Client) Start() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.started {
+		return errors.New("client already started")
+	}
+	c.started = true
+	go c.run()
+	return nil
+}
+
+// Stop stops receiving blocks and closes the channels of all registrations
+func (c *Client) Stop() {
+	c.cancel()
+
+	c.lock.Lock()
+	started := c.started
+	c.lock.Unlock()
+	if started {
+		<-c.done
+	}
+
+	c.lock.Lock()
+	blockRegs, ccRegs := c.blockRegs, c.ccRegs
+	for _, reg := range c.txRegs {
+		close(reg.events)
+	}
+	c.blockRegs = make(map[*BlockRegistration]struct{})
+	c.ccRegs = make(map[*ChaincodeRegistration]struct{})
+	c.txRegs = make(map[string]*TxStatusRegistration)
+	c.lock.Unlock()
+
+	for reg := range blockRegs {
+		reg.cancel(func() { close(reg.events) })
+	}
+	for reg := range ccRegs {
+		reg.cancel(func() { close(reg.events) })
+	}
+}
+
+// run delivers blocks from the endpoints until the client is stopped, moving
+// on to the next endpoint whenever a stream fails
+func (c *Client) run() {
+	defer close(c.done)
+
+	interval := c.config.ReconnectInterval
+	for {
+		endpoint := c.config.Endpoints[c.endpointID]
+		progressed, err := c.deliver(endpoint)
+		if c.ctx.Err() != nil {
+			logger.Debugf("[channel: %s] Client stopped", c.config.ChannelID)
+			return
+		}
+		if progressed {
+			interval = c.config.ReconnectInterval
+		}
+		c.endpointID = (c.endpointID + 1) % len(c.config.Endpoints)
+		logger.Warningf("[channel: %s] Disconnected from %s: %s, reconnecting to %s in %s",
+			c.config.ChannelID, endpoint, err, c.config.Endpoints[c.endpointID], interval)
+
+		select {
+		case <-time.After(interval):
+		case <-c.ctx.Done():
+			return
+		}
+		interval *= 2
+		if interval > c.config.MaxReconnectInterval {
+			interval = c.config.MaxReconnectInterval
+		}
+	}
+}
+
+// deliver receives blocks from the given endpoint until the stream fails,
+// and returns whether any block was received
+func (c *Client) deliver(endpoint string) (bool, error) {
+	ctx, cancel := context.WithCancel(c.ctx)
+	defer cancel()
+
+	stream, err := c.config.Connector(ctx, endpoint)
+	if err != nil {
+		return false, err
+	}
+	defer stream.CloseSend()
+
+	start, err := c.seekStart()
+	if err != nil {
+		return false, err
+	}
+	env, err := utils.CreateSignedEnvelopeWithTLSBinding(common.HeaderType_DELIVER_SEEK_INFO, c.config.ChannelID, c.config.Signer, &orderer.SeekInfo{
+		Start:    start,
+		Stop:     maxStop,
+		Behavior: orderer.SeekInfo_BLOCK_UNTIL_READY,
+	}, 0, 0, c.config.TLSCertHash)
+	if err != nil {
+		return false, errors.WithMessage(err, "failed creating seek request")
+	}
+	if err := stream.Send(env); err != nil {
+		return false, errors.Wrap(err, "failed sending seek request")
+	}
+	logger.Debugf("[channel: %s] Receiving blocks from %s starting at %v", c.config.ChannelID, endpoint, start)
+
+	progressed := false
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			return progressed, errors.Wrap(err, "failed receiving from deliver stream")
+		}
+
+		var be *BlockEvent
+		switch t := resp.Type.(type) {
+		case *peer.DeliverResponse_Status:
+			return progressed, errors.Errorf("deliver stream ended with status %s", t.Status)
+		case *peer.DeliverResponse_Block:
+			be = &BlockEvent{Block: t.Block}
+		case *peer.DeliverResponse_FilteredBlock:
+			be = &BlockEvent{FilteredBlock: t.FilteredBlock}
+		default:
+			return progressed, errors.Errorf("unexpected deliver response type %T", t)
+		}
+
+		if err := c.dispatch(be); err != nil {
+			return progressed, err
+		}
+		if err := c.config.Checkpointer.Checkpoint(be.Number()); err != nil {
+			return progressed, errors.WithMessage(err, "failed checkpointing block")
+		}
+		progressed = true
+	}
+}
+
+// seekStart returns the position following the last block checkpointed, or
+// the configured start position if nothing was checkpointed yet
+func (c *Client) seekStart() (*orderer.SeekPosition, error) {
+	lastBlock, exists, err := c.config.Checkpointer.LastBlock()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed retrieving checkpoint")
+	}
+	if !exists {
+		return c.config.Start, nil
+	}
+	return &orderer.SeekPosition{Type: &orderer.SeekPosition_Specified{Specified: &orderer.SeekSpecified{Number: lastBlock + 1}}}, nil
+}
+
+// dispatch sends the events of the given block to the matching registrations.
+// Registrations that are not drained hold back the delivery of the following blocks.
+// The events are sent without holding the client lock, so that registrations
+// may be added or removed while a send is pending
+func (c *Client) dispatch(be *BlockEvent) error {
+	txs, err := transactionsOf(be)
+	if err != nil {
+		return errors.WithMessage(err, "failed parsing block")
+	}
+	blockNum := be.Number()
+
+	c.lock.Lock()
+	var blockRegs []*BlockRegistration
+	for reg := range c.blockRegs {
+		blockRegs = append(blockRegs, reg)
+	}
+	var ccRegs []*ChaincodeRegistration
+	for reg := range c.ccRegs {
+		ccRegs = append(ccRegs, reg)
+	}
+	c.lock.Unlock()
+
+	for _, reg := range blockRegs {
+		if err := c.sendBlockEvent(reg, be); err != nil {
+			return err
+		}
+	}
+
+	for _, tx := range txs {
+		for _, ccEvent := range tx.chaincodeEvents {
+			for _, reg := range ccRegs {
+				if reg.chaincodeID != ccEvent.ChaincodeId || !reg.eventFilter.MatchString(ccEvent.EventName) {
+					continue
+				}
+				event := &ChaincodeEvent{
+					TxID:             ccEvent.TxId,
+					ChaincodeID:      ccEvent.ChaincodeId,
+					EventName:        ccEvent.EventName,
+					Payload:          ccEvent.Payload,
+					BlockNumber:      blockNum,
+					TxValidationCode: tx.validationCode,
+				}
+				if err := c.sendChaincodeEvent(reg, event); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, tx := range txs {
+		if reg, exists := c.txRegs[tx.txID]; exists {
+			// the channel is buffered with room for the single status
+			reg.events <- &TxStatusEvent{
+				TxID:             tx.txID,
+				TxValidationCode: tx.validationCode,
+				BlockNumber:      blockNum,
+			}
+			delete(c.txRegs, tx.txID)
+			close(reg.events)
+		}
+	}
+	return nil
+}
+
+// sendBlockEvent sends the given event to the registration, unless it
+// is unregistered or the client is stopped while waiting for room
+func (c *Client) sendBlockEvent(reg *BlockRegistration, be *BlockEvent) error {
+	reg.lock.Lock()
+	defer reg.lock.Unlock()
+	select {
+	case <-reg.done:
+		return nil
+	default:
+	}
+	select {
+	case reg.events <- be:
+	case <-reg.done:
+	case <-c.ctx.Done():
+		return c.ctx.Err()
+	}
+	return nil
+}
+
+// sendChaincodeEvent sends the given event to the registration, unless it
+// is unregistered or the client is stopped while waiting for room
+func (c *Client) sendChaincodeEvent(reg *ChaincodeRegistration, event *ChaincodeEvent) error {
+	reg.lock.Lock()
+	defer reg.lock.Unlock()
+	select {
+	case <-reg.done:
+		return nil
+	default:
+	}
+	select {
+	case reg.events <- event:
+	case <-reg.done:
+	case <-c.ctx.Done():
+		return c.ctx.Err()
+	}
+	return nil
+}
@@ -0,0 +1,408 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deliverclient
+
+import (
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	mockcrypto "github.com/hyperledger/fabric/common/mocks/crypto"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+// mockStream is a DeliverStream that serves the responses pushed to it
+type mockStream struct {
+	ctx       context.Context
+	endpoint  string
+	seeks     chan *orderer.SeekInfo
+	responses chan *peer.DeliverResponse
+}
+
+func (ms *mockStream) Send(env *common.Envelope) error {
+	seekInfo := &orderer.SeekInfo{}
+	if _, err := utils.UnmarshalEnvelopeOfType(env, common.HeaderType_DELIVER_SEEK_INFO, seekInfo); err != nil {
+		return err
+	}
+	ms.seeks <- seekInfo
+	return nil
+}
+
+func (ms *mockStream) Recv() (*peer.DeliverResponse, error) {
+	select {
+	case resp, ok := <-ms.responses:
+		if !ok {
+			return nil, io.EOF
+		}
+		return resp, nil
+	case <-ms.ctx.Done():
+		return nil, ms.ctx.Err()
+	}
+}
+
+func (ms *mockStream) CloseSend() error {
+	return nil
+}
+
+// mockConnector hands out the streams it creates over a channel
+type mockConnector struct {
+	lock    sync.Mutex
+	failFor map[string]bool
+	streams chan *mockStream
+}
+
+func newMockConnector() *mockConnector {
+	return &mockConnector{
+		failFor: make(map[string]bool),
+		streams: make(chan *mockStream, 10),
+	}
+}
+
+func (mc *mockConnector) connect(ctx context.Context, endpoint string) (DeliverStream, error) {
+	mc.lock.Lock()
+	defer mc.lock.Unlock()
+	if mc.failFor[endpoint] {
+		return nil, errors.Errorf("%s is unreachable", endpoint)
+	}
+	stream := &mockStream{
+		ctx:       ctx,
+		endpoint:  endpoint,
+		seeks:     make(chan *orderer.SeekInfo, 1),
+		responses: make(chan *peer.DeliverResponse, 10),
+	}
+	mc.streams <- stream
+	return stream, nil
+}
+
+func (mc *mockConnector) nextStream(t *testing.T) *mockStream {
+	select {
+	case stream := <-mc.streams:
+		return stream
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a connection")
+	}
+	return nil
+}
+
+func filteredBlockResponse(number uint64, txs ...*peer.FilteredTransaction) *peer.DeliverResponse {
+	return &peer.DeliverResponse{
+		Type: &peer.DeliverResponse_FilteredBlock{
+			FilteredBlock: &peer.FilteredBlock{
+				ChannelId:            "mychannel",
+				Number:               number,
+				FilteredTransactions: txs,
+			},
+		},
+	}
+}
+
+func filteredTx(txID string, code peer.TxValidationCode, events ...*peer.ChaincodeEvent) *peer.FilteredTransaction {
+	actions := &peer.FilteredTransactionActions{}
+	for _, event := range events {
+		actions.ChaincodeActions = append(actions.ChaincodeActions, &peer.FilteredChaincodeAction{ChaincodeEvent: event})
+	}
+	return &peer.FilteredTransaction{
+		Txid:             txID,
+		Type:             common.HeaderType_ENDORSER_TRANSACTION,
+		TxValidationCode: code,
+		Data:             &peer.FilteredTransaction_TransactionActions{TransactionActions: actions},
+	}
+}
+
+func newTestClient(t *testing.T, connector *mockConnector, endpoints ...string) *Client {
+	client, err := NewClient(Config{
+		ChannelID:         "mychannel",
+		Endpoints:         endpoints,
+		Connector:         connector.connect,
+		Signer:            &mockcrypto.LocalSigner{},
+		ReconnectInterval: time.Millisecond,
+	})
+	assert.NoError(t, err)
+	return client
+}
+
+func TestNewClient(t *testing.T) {
+	connector := newMockConnector()
+	tests := []struct {
+		name        string
+		config      Config
+		expectedErr string
+	}{
+		{
+			name:        "missing channel",
+			config:      Config{Endpoints: []string{"peer0"}, Connector: connector.connect, Signer: &mockcrypto.LocalSigner{}},
+			expectedErr: "channel ID must be provided",
+		},
+		{
+			name:        "missing endpoints",
+			config:      Config{ChannelID: "mychannel", Connector: connector.connect, Signer: &mockcrypto.LocalSigner{}},
+			expectedErr: "at least one endpoint must be provided",
+		},
+		{
+			name:        "missing connector",
+			config:      Config{ChannelID: "mychannel", Endpoints: []string{"peer0"}, Signer: &mockcrypto.LocalSigner{}},
+			expectedErr: "connector must be provided",
+		},
+		{
+			name:        "missing signer",
+			config:      Config{ChannelID: "mychannel", Endpoints: []string{"peer0"}, Connector: connector.connect},
+			expectedErr: "signer must be provided",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewClient(test.config)
+			assert.EqualError(t, err, test.expectedErr)
+		})
+	}
+
+	client := newTestClient(t, connector, "peer0")
+	assert.IsType(t, &MemoryCheckpointer{}, client.config.Checkpointer)
+	assert.True(t, proto.Equal(newest, client.config.Start))
+	assert.Equal(t, defaultEventBufferSize, client.config.EventBufferSize)
+}
+
+func TestClientDispatch(t *testing.T) {
+	connector := newMockConnector()
+	client := newTestClient(t, connector, "peer0")
+	defer client.Stop()
+
+	_, blocks := client.RegisterBlockEvent()
+	_, ccEvents, err := client.RegisterChaincodeEvent("mycc", "^transfer.*")
+	assert.NoError(t, err)
+	_, txStatus, err := client.RegisterTxStatus("tx2")
+	assert.NoError(t, err)
+	_, _, err = client.RegisterTxStatus("tx2")
+	assert.EqualError(t, err, "transaction tx2 is already registered")
+	_, _, err = client.RegisterChaincodeEvent("mycc", "[")
+	assert.Error(t, err)
+
+	assert.NoError(t, client.Start())
+	assert.EqualError(t, client.Start(), "client already started")
+
+	stream := connector.nextStream(t)
+	seekInfo := <-stream.seeks
+	assert.True(t, proto.Equal(newest, seekInfo.Start))
+	assert.Equal(t, orderer.SeekInfo_BLOCK_UNTIL_READY, seekInfo.Behavior)
+
+	stream.responses <- filteredBlockResponse(5,
+		filteredTx("tx1", peer.TxValidationCode_VALID,
+			&peer.ChaincodeEvent{TxId: "tx1", ChaincodeId: "mycc", EventName: "transferred"},
+			&peer.ChaincodeEvent{TxId: "tx1", ChaincodeId: "mycc", EventName: "minted"},
+			&peer.ChaincodeEvent{TxId: "tx1", ChaincodeId: "othercc", EventName: "transferred"}),
+		filteredTx("tx2", peer.TxValidationCode_MVCC_READ_CONFLICT))
+
+	block := <-blocks
+	assert.Equal(t, uint64(5), block.Number())
+	assert.Len(t, block.FilteredBlock.FilteredTransactions, 2)
+
+	ccEvent := <-ccEvents
+	assert.Equal(t, &ChaincodeEvent{
+		TxID:             "tx1",
+		ChaincodeID:      "mycc",
+		EventName:        "transferred",
+		BlockNumber:      5,
+		TxValidationCode: peer.TxValidationCode_VALID,
+	}, ccEvent)
+	select {
+	case ccEvent = <-ccEvents:
+		t.Fatalf("unexpected chaincode event %v", ccEvent)
+	default:
+	}
+
+	status := <-txStatus
+	assert.Equal(t, &TxStatusEvent{TxID: "tx2", TxValidationCode: peer.TxValidationCode_MVCC_READ_CONFLICT, BlockNumber: 5}, status)
+	_, open := <-txStatus
+	assert.False(t, open, "transaction registration should be closed after its status was sent")
+
+	lastBlock, exists, err := client.config.Checkpointer.LastBlock()
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, uint64(5), lastBlock)
+}
+
+func TestClientFullBlock(t *testing.T) {
+	connector := newMockConnector()
+	client := newTestClient(t, connector, "peer0")
+	defer client.Stop()
+
+	_, ccEvents, err := client.RegisterChaincodeEvent("mycc", "")
+	assert.NoError(t, err)
+	assert.NoError(t, client.Start())
+
+	eventBytes := utils.MarshalOrPanic(&peer.ChaincodeEvent{TxId: "tx1", ChaincodeId: "mycc", EventName: "transferred", Payload: []byte("payload")})
+	actionPayload := &peer.ChaincodeActionPayload{
+		Action: &peer.ChaincodeEndorsedAction{
+			ProposalResponsePayload: utils.MarshalOrPanic(&peer.ProposalResponsePayload{
+				Extension: utils.MarshalOrPanic(&peer.ChaincodeAction{Events: eventBytes}),
+			}),
+		},
+	}
+	env := &common.Envelope{
+		Payload: utils.MarshalOrPanic(&common.Payload{
+			Header: &common.Header{
+				ChannelHeader: utils.MarshalOrPanic(&common.ChannelHeader{
+					Type:      int32(common.HeaderType_ENDORSER_TRANSACTION),
+					ChannelId: "mychannel",
+					TxId:      "tx1",
+				}),
+			},
+			Data: utils.MarshalOrPanic(&peer.Transaction{
+				Actions: []*peer.TransactionAction{{Payload: utils.MarshalOrPanic(actionPayload)}},
+			}),
+		}),
+	}
+	block := common.NewBlock(3, nil)
+	block.Data.Data = [][]byte{utils.MarshalOrPanic(env)}
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = []byte{byte(peer.TxValidationCode_VALID)}
+
+	stream := connector.nextStream(t)
+	<-stream.seeks
+	stream.responses <- &peer.DeliverResponse{Type: &peer.DeliverResponse_Block{Block: block}}
+
+	ccEvent := <-ccEvents
+	assert.Equal(t, "tx1", ccEvent.TxID)
+	assert.Equal(t, []byte("payload"), ccEvent.Payload)
+	assert.Equal(t, uint64(3), ccEvent.BlockNumber)
+}
+
+func TestClientReconnectResumesFromCheckpoint(t *testing.T) {
+	connector := newMockConnector()
+	connector.failFor["peer1"] = true
+	client := newTestClient(t, connector, "peer0", "peer1", "peer2")
+	defer client.Stop()
+
+	_, blocks := client.RegisterBlockEvent()
+	assert.NoError(t, client.Start())
+
+	stream := connector.nextStream(t)
+	assert.Equal(t, "peer0", stream.endpoint)
+	<-stream.seeks
+	stream.responses <- filteredBlockResponse(10)
+	assert.Equal(t, uint64(10), (<-blocks).Number())
+
+	// the stream of peer0 breaks, peer1 is unreachable hence the client
+	// moves on to peer2 and resumes after the last block checkpointed
+	close(stream.responses)
+	stream = connector.nextStream(t)
+	assert.Equal(t, "peer2", stream.endpoint)
+	seekInfo := <-stream.seeks
+	assert.Equal(t, uint64(11), seekInfo.Start.GetSpecified().Number)
+
+	stream.responses <- filteredBlockResponse(11)
+	assert.Equal(t, uint64(11), (<-blocks).Number())
+
+	// a status response ends the stream as well
+	stream.responses <- &peer.DeliverResponse{Type: &peer.DeliverResponse_Status{Status: common.Status_FORBIDDEN}}
+	stream = connector.nextStream(t)
+	assert.Equal(t, "peer0", stream.endpoint)
+	seekInfo = <-stream.seeks
+	assert.Equal(t, uint64(12), seekInfo.Start.GetSpecified().Number)
+}
+
+func TestClientStop(t *testing.T) {
+	connector := newMockConnector()
+	client := newTestClient(t, connector, "peer0")
+
+	blockReg, blocks := client.RegisterBlockEvent()
+	_, ccEvents, err := client.RegisterChaincodeEvent("mycc", "")
+	assert.NoError(t, err)
+	_, txStatus, err := client.RegisterTxStatus("tx1")
+	assert.NoError(t, err)
+
+	client.UnregisterBlockEvent(blockReg)
+	_, open := <-blocks
+	assert.False(t, open)
+
+	assert.NoError(t, client.Start())
+	<-connector.nextStream(t).seeks
+	client.Stop()
+
+	_, open = <-ccEvents
+	assert.False(t, open)
+	_, open = <-txStatus
+	assert.False(t, open)
+}
+
+func TestClientRegistrationFromHandler(t *testing.T) {
+	connector := newMockConnector()
+	client, err := NewClient(Config{
+		ChannelID:       "mychannel",
+		Endpoints:       []string{"peer0"},
+		Connector:       connector.connect,
+		Signer:          &mockcrypto.LocalSigner{},
+		EventBufferSize: 1,
+	})
+	assert.NoError(t, err)
+	defer client.Stop()
+
+	reg, blocks := client.RegisterBlockEvent()
+	assert.NoError(t, client.Start())
+	stream := connector.nextStream(t)
+	<-stream.seeks
+	for i := uint64(1); i <= 3; i++ {
+		stream.responses <- filteredBlockResponse(i)
+	}
+
+	// waitForFullBuffer waits until the dispatch of the next block waits for room in the channel
+	waitForFullBuffer := func() {
+		for len(blocks) < 1 || len(stream.responses) > 0 {
+			time.Sleep(time.Millisecond)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// the handler registers and unregisters from inside its event loop
+	// while the dispatch of the following block waits for room in its channel
+	others := make(chan (<-chan *BlockEvent), 1)
+	var received []uint64
+	handlerDone := make(chan struct{})
+	go func() {
+		defer close(handlerDone)
+		for be := range blocks {
+			received = append(received, be.Number())
+			switch be.Number() {
+			case 1:
+				waitForFullBuffer()
+				_, otherBlocks := client.RegisterBlockEvent()
+				others <- otherBlocks
+			case 2:
+				stream.responses <- filteredBlockResponse(4)
+				waitForFullBuffer()
+				client.UnregisterBlockEvent(reg)
+			}
+		}
+	}()
+
+	var otherBlocks <-chan *BlockEvent
+	select {
+	case otherBlocks = <-others:
+	case <-time.After(5 * time.Second):
+		t.Fatal("registering from the handler deadlocked")
+	}
+	// the registration was added while block 3 was being dispatched
+	select {
+	case be := <-otherBlocks:
+		assert.Equal(t, uint64(4), be.Number())
+	case <-time.After(5 * time.Second):
+		t.Fatal("block 4 wasn't delivered")
+	}
+	select {
+	case <-handlerDone:
+	case <-time.After(5 * time.Second):
+		t.Fatal("unregistering from the handler deadlocked")
+	}
+	assert.Equal(t, []uint64{1, 2, 3}, received)
+}
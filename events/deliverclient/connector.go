@@ -0,0 +1,69 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deliverclient
+
+import (
+	"github.com/hyperledger/fabric/core/comm"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// DeliverStream is the common interface of the Deliver and DeliverFiltered
+// client streams
+type DeliverStream interface {
+	Send(*common.Envelope) error
+	Recv() (*peer.DeliverResponse, error)
+	CloseSend() error
+}
+
+// Connector opens a deliver stream to the peer at the given endpoint. The
+// stream must be torn down once the given context is done
+type Connector func(ctx context.Context, endpoint string) (DeliverStream, error)
+
+// NewConnector returns a Connector that dials peers with the given gRPC
+// client and opens a DeliverFiltered stream if filtered is set, or a
+// Deliver stream otherwise
+func NewConnector(grpcClient *comm.GRPCClient, filtered bool) Connector {
+	return func(ctx context.Context, endpoint string) (DeliverStream, error) {
+		conn, err := grpcClient.NewConnection(endpoint, "")
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed connecting to "+endpoint)
+		}
+
+		dc := peer.NewDeliverClient(conn)
+		var stream DeliverStream
+		if filtered {
+			stream, err = dc.DeliverFiltered(ctx)
+		} else {
+			stream, err = dc.Deliver(ctx)
+		}
+		if err != nil {
+			conn.Close()
+			return nil, errors.Wrap(err, "failed opening deliver stream to "+endpoint)
+		}
+
+		go func() {
+			<-ctx.Done()
+			conn.Close()
+		}()
+		return &connStream{DeliverStream: stream, conn: conn}, nil
+	}
+}
+
+// connStream closes the underlying connection along with the stream
+type connStream struct {
+	DeliverStream
+	conn *grpc.ClientConn
+}
+
+func (cs *connStream) CloseSend() error {
+	defer cs.conn.Close()
+	return cs.DeliverStream.CloseSend()
+}
@@ -0,0 +1,158 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deliverclient
+
+import (
+	"github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// BlockEvent is sent to block registrations for every block delivered.
+// Block is set when the client uses the Deliver service, and FilteredBlock
+// when it uses the DeliverFiltered service
+type BlockEvent struct {
+	Block         *common.Block
+	FilteredBlock *peer.FilteredBlock
+}
+
+// Number returns the number of the block the event pertains to
+func (be *BlockEvent) Number() uint64 {
+	if be.Block != nil {
+		return be.Block.Header.Number
+	}
+	return be.FilteredBlock.Number
+}
+
+// ChaincodeEvent is sent to chaincode event registrations for every matching
+// event set by a chaincode. The payload is only available when the client
+// uses the Deliver service
+type ChaincodeEvent struct {
+	TxID             string
+	ChaincodeID      string
+	EventName        string
+	Payload          []byte
+	BlockNumber      uint64
+	TxValidationCode peer.TxValidationCode
+}
+
+// TxStatusEvent is sent to a transaction status registration once the
+// transaction is committed
+type TxStatusEvent struct {
+	TxID             string
+	TxValidationCode peer.TxValidationCode
+	BlockNumber      uint64
+}
+
+// blockTransaction holds the information of a transaction in a block that
+// the registrations are matched against
+type blockTransaction struct {
+	txID            string
+	validationCode  peer.TxValidationCode
+	chaincodeEvents []*peer.ChaincodeEvent
+}
+
+// transactionsOf returns the transactions of a delivered block
+func transactionsOf(be *BlockEvent) ([]*blockTransaction, error) {
+	if be.Block != nil {
+		return blockTransactions(be.Block)
+	}
+	return filteredBlockTransactions(be.FilteredBlock), nil
+}
+
+func filteredBlockTransactions(fb *peer.FilteredBlock) []*blockTransaction {
+	var txs []*blockTransaction
+	for _, ftx := range fb.FilteredTransactions {
+		tx := &blockTransaction{
+			txID:           ftx.Txid,
+			validationCode: ftx.TxValidationCode,
+		}
+		for _, action := range ftx.GetTransactionActions().GetChaincodeActions() {
+			if action.ChaincodeEvent != nil {
+				tx.chaincodeEvents = append(tx.chaincodeEvents, action.ChaincodeEvent)
+			}
+		}
+		txs = append(txs, tx)
+	}
+	return txs
+}
+
+func blockTransactions(block *common.Block) ([]*blockTransaction, error) {
+	if block.Data == nil || block.Metadata == nil || len(block.Metadata.Metadata) <= int(common.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		return nil, errors.Errorf("block [%d] is malformed", block.Header.Number)
+	}
+	txsFltr := util.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+
+	var txs []*blockTransaction
+	for txIndex, envBytes := range block.Data.Data {
+		env, err := utils.GetEnvelopeFromBlock(envBytes)
+		if err != nil {
+			return nil, errors.WithMessage(err, "error getting tx from block")
+		}
+		payload, err := utils.GetPayload(env)
+		if err != nil {
+			return nil, errors.WithMessage(err, "could not extract payload from envelope")
+		}
+		if payload.Header == nil {
+			logger.Debugf("Transaction payload header is nil, %d, block num %d", txIndex, block.Header.Number)
+			continue
+		}
+		chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+		if err != nil {
+			return nil, err
+		}
+
+		tx := &blockTransaction{
+			txID:           chdr.TxId,
+			validationCode: txsFltr.Flag(txIndex),
+		}
+		if common.HeaderType(chdr.Type) == common.HeaderType_ENDORSER_TRANSACTION {
+			tx.chaincodeEvents, err = chaincodeEvents(payload.Data)
+			if err != nil {
+				return nil, err
+			}
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
+
+func chaincodeEvents(payloadData []byte) ([]*peer.ChaincodeEvent, error) {
+	tx, err := utils.GetTransaction(payloadData)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error unmarshal transaction payload")
+	}
+
+	var events []*peer.ChaincodeEvent
+	for _, action := range tx.Actions {
+		ccActionPayload, err := utils.GetChaincodeActionPayload(action.Payload)
+		if err != nil {
+			return nil, errors.WithMessage(err, "error unmarshal transaction action payload")
+		}
+		if ccActionPayload.Action == nil {
+			continue
+		}
+		propRespPayload, err := utils.GetProposalResponsePayload(ccActionPayload.Action.ProposalResponsePayload)
+		if err != nil {
+			return nil, errors.WithMessage(err, "error unmarshal proposal response payload")
+		}
+		ccAction, err := utils.GetChaincodeAction(propRespPayload.Extension)
+		if err != nil {
+			return nil, errors.WithMessage(err, "error unmarshal chaincode action")
+		}
+		ccEvent, err := utils.GetChaincodeEvents(ccAction.Events)
+		if err != nil {
+			return nil, errors.WithMessage(err, "error unmarshal chaincode event")
+		}
+		if ccEvent.GetChaincodeId() != "" {
+			events = append(events, ccEvent)
+		}
+	}
+	return events, nil
+}
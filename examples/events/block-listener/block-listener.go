@@ -17,123 +17,53 @@
 package main
 
 import (
-	"errors"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"strings"
+	"time"
 
-	"github.com/hyperledger/fabric/core/ledger/util"
-	"github.com/hyperledger/fabric/events/consumer"
+	"github.com/hyperledger/fabric/common/localmsp"
+	"github.com/hyperledger/fabric/core/comm"
+	"github.com/hyperledger/fabric/events/deliverclient"
 	"github.com/hyperledger/fabric/msp/mgmt"
 	"github.com/hyperledger/fabric/msp/mgmt/testtools"
-	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/orderer"
 	pb "github.com/hyperledger/fabric/protos/peer"
-	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/spf13/viper"
 )
 
-type adapter struct {
-	notfy chan *pb.Event_Block
-}
-
-//GetInterestedEvents implements consumer.EventAdapter interface for registering interested events
-func (a *adapter) GetInterestedEvents() ([]*pb.Interest, error) {
-	return []*pb.Interest{{EventType: pb.EventType_BLOCK}}, nil
-}
-
-//Recv implements consumer.EventAdapter interface for receiving events
-func (a *adapter) Recv(msg *pb.Event) (bool, error) {
-	if o, e := msg.Event.(*pb.Event_Block); e {
-		a.notfy <- o
-		return true, nil
-	}
-	return false, fmt.Errorf("Receive unknown type event: %v", msg)
-}
-
-//Disconnected implements consumer.EventAdapter interface for disconnecting
-func (a *adapter) Disconnected(err error) {
-	fmt.Print("Disconnected...exiting\n")
-	os.Exit(1)
-}
-
-func createEventClient(eventAddress string, _ string) *adapter {
-	var obcEHClient *consumer.EventsClient
-
-	done := make(chan *pb.Event_Block)
-	adapter := &adapter{notfy: done}
-	obcEHClient, _ = consumer.NewEventsClient(eventAddress, 5, adapter)
-	if err := obcEHClient.Start(); err != nil {
-		fmt.Printf("could not start chat. err: %s\n", err)
-		obcEHClient.Stop()
-		return nil
+func createEventClient(peerAddresses []string, channelID string, filtered bool, checkpointFile string) (*deliverclient.Client, error) {
+	clientConfig := comm.ClientConfig{
+		KaOpts:  comm.DefaultKeepaliveOptions,
+		SecOpts: &comm.SecureOptions{},
+		Timeout: 5 * time.Second,
 	}
-
-	return adapter
-}
-func getTxPayload(tdata []byte) (*common.Payload, error) {
-	if tdata == nil {
-		return nil, errors.New("Cannot extract payload from nil transaction")
-	}
-
-	if env, err := utils.GetEnvelopeFromBlock(tdata); err != nil {
-		return nil, fmt.Errorf("Error getting tx from block(%s)", err)
-	} else if env != nil {
-		// get the payload from the envelope
-		payload, err := utils.GetPayload(env)
+	if viper.GetBool("peer.tls.enabled") {
+		rootCert, err := ioutil.ReadFile(viper.GetString("peer.tls.rootcert.file"))
 		if err != nil {
-			return nil, fmt.Errorf("Could not extract payload from envelope, err %s", err)
+			return nil, fmt.Errorf("error loading TLS root certificate: %s", err)
 		}
-		return payload, nil
+		clientConfig.SecOpts.UseTLS = true
+		clientConfig.SecOpts.ServerRootCAs = [][]byte{rootCert}
 	}
-	return nil, nil
-}
-
-// getChainCodeEvents parses block events for chaincode events associated with individual transactions
-func getChainCodeEvents(tdata []byte) (*pb.ChaincodeEvent, error) {
-	if tdata == nil {
-		return nil, errors.New("Cannot extract payload from nil transaction")
+	grpcClient, err := comm.NewGRPCClient(clientConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error creating grpc client: %s", err)
 	}
 
-	if env, err := utils.GetEnvelopeFromBlock(tdata); err != nil {
-		return nil, fmt.Errorf("Error getting tx from block(%s)", err)
-	} else if env != nil {
-		// get the payload from the envelope
-		payload, err := utils.GetPayload(env)
-		if err != nil {
-			return nil, fmt.Errorf("Could not extract payload from envelope, err %s", err)
-		}
-
-		chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
-		if err != nil {
-			return nil, fmt.Errorf("Could not extract channel header from envelope, err %s", err)
-		}
-
-		if common.HeaderType(chdr.Type) == common.HeaderType_ENDORSER_TRANSACTION {
-			tx, err := utils.GetTransaction(payload.Data)
-			if err != nil {
-				return nil, fmt.Errorf("Error unmarshalling transaction payload for block event: %s", err)
-			}
-			chaincodeActionPayload, err := utils.GetChaincodeActionPayload(tx.Actions[0].Payload)
-			if err != nil {
-				return nil, fmt.Errorf("Error unmarshalling transaction action payload for block event: %s", err)
-			}
-			propRespPayload, err := utils.GetProposalResponsePayload(chaincodeActionPayload.Action.ProposalResponsePayload)
-			if err != nil {
-				return nil, fmt.Errorf("Error unmarshalling proposal response payload for block event: %s", err)
-			}
-			caPayload, err := utils.GetChaincodeAction(propRespPayload.Extension)
-			if err != nil {
-				return nil, fmt.Errorf("Error unmarshalling chaincode action for block event: %s", err)
-			}
-			ccEvent, err := utils.GetChaincodeEvents(caPayload.Events)
-
-			if ccEvent != nil {
-				return ccEvent, nil
-			}
-		}
+	config := deliverclient.Config{
+		ChannelID: channelID,
+		Endpoints: peerAddresses,
+		Connector: deliverclient.NewConnector(grpcClient, filtered),
+		Signer:    localmsp.NewSigner(),
+		Start:     &orderer.SeekPosition{Type: &orderer.SeekPosition_Newest{Newest: &orderer.SeekNewest{}}},
+	}
+	if checkpointFile != "" {
+		config.Checkpointer = deliverclient.NewFileCheckpointer(checkpointFile)
 	}
-	return nil, errors.New("No events found")
+	return deliverclient.NewClient(config)
 }
 
 func main() {
@@ -143,14 +73,20 @@ func main() {
 	replacer := strings.NewReplacer(".", "_")
 	viper.SetEnvKeyReplacer(replacer)
 
-	var eventAddress string
+	var peerAddresses string
+	var channelID string
 	var chaincodeID string
 	var mspDir string
 	var mspId string
-	flag.StringVar(&eventAddress, "events-address", "0.0.0.0:7053", "address of events server")
+	var checkpointFile string
+	var filtered bool
+	flag.StringVar(&peerAddresses, "events-address", "0.0.0.0:7051", "comma separated addresses of the peers to receive events from")
+	flag.StringVar(&channelID, "channel", "mychannel", "channel to receive events for")
 	flag.StringVar(&chaincodeID, "events-from-chaincode", "", "listen to events from given chaincode")
 	flag.StringVar(&mspDir, "events-mspdir", "", "set up the msp direction")
 	flag.StringVar(&mspId, "events-mspid", "", "set up the mspid")
+	flag.StringVar(&checkpointFile, "checkpoint", "", "file recording the last block received, to resume from on restart")
+	flag.BoolVar(&filtered, "filtered", false, "receive filtered blocks, without chaincode event payloads")
 	flag.Parse()
 
 	//if no msp info provided, we use the default MSP under fabric/sampleconfig
@@ -169,50 +105,55 @@ func main() {
 		}
 	}
 
-	fmt.Printf("Event Address: %s\n", eventAddress)
+	fmt.Printf("Peer Addresses: %s\n", peerAddresses)
 
-	a := createEventClient(eventAddress, chaincodeID)
-	if a == nil {
-		fmt.Println("Error creating event client")
-		return
+	client, err := createEventClient(strings.Split(peerAddresses, ","), channelID, filtered, checkpointFile)
+	if err != nil {
+		fmt.Printf("Error creating event client: %s\n", err)
+		os.Exit(-1)
 	}
+	_, blocks := client.RegisterBlockEvent()
+	var ccEvents <-chan *deliverclient.ChaincodeEvent
+	if chaincodeID != "" {
+		_, ccEvents, err = client.RegisterChaincodeEvent(chaincodeID, "")
+		if err != nil {
+			fmt.Printf("Error registering for chaincode events: %s\n", err)
+			os.Exit(-1)
+		}
+	}
+	if err := client.Start(); err != nil {
+		fmt.Printf("Error starting event client: %s\n", err)
+		os.Exit(-1)
+	}
+	defer client.Stop()
 
 	for {
 		select {
-		case b := <-a.notfy:
+		case b := <-blocks:
 			fmt.Println("")
 			fmt.Println("")
-			fmt.Println("Received block")
+			fmt.Printf("Received block %d from channel '%s'\n", b.Number(), channelID)
 			fmt.Println("--------------")
-			txsFltr := util.TxValidationFlags(b.Block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
-			for i, r := range b.Block.Data.Data {
-				tx, _ := getTxPayload(r)
-				if tx != nil {
-					chdr, err := utils.UnmarshalChannelHeader(tx.Header.ChannelHeader)
-					if err != nil {
-						fmt.Print("Error extracting channel header\n")
-						return
-					}
-					if txsFltr.IsInvalid(i) {
-						fmt.Println("")
-						fmt.Println("")
-						fmt.Printf("Received invalid transaction from channel '%s'\n", chdr.ChannelId)
-						fmt.Println("--------------")
-						fmt.Printf("Transaction invalid: TxID: %s\n", chdr.TxId)
-					} else {
-						fmt.Printf("Received transaction from channel '%s': \n\t[%v]\n", chdr.ChannelId, tx)
-						if event, err := getChainCodeEvents(r); err == nil {
-							if len(chaincodeID) != 0 && event.ChaincodeId == chaincodeID {
-								fmt.Println("")
-								fmt.Println("")
-								fmt.Printf("Received chaincode event from channel '%s'\n", chdr.ChannelId)
-								fmt.Println("------------------------")
-								fmt.Printf("Chaincode Event:%+v\n", event)
-							}
-						}
+			if b.FilteredBlock != nil {
+				for _, tx := range b.FilteredBlock.FilteredTransactions {
+					if tx.TxValidationCode != pb.TxValidationCode_VALID {
+						fmt.Printf("Transaction invalid: TxID: %s, code: %s\n", tx.Txid, tx.TxValidationCode)
+						continue
 					}
+					fmt.Printf("Transaction valid: TxID: %s\n", tx.Txid)
 				}
+			} else {
+				fmt.Printf("Received %d transactions\n", len(b.Block.Data.Data))
+			}
+		case event := <-ccEvents:
+			if event.TxValidationCode != pb.TxValidationCode_VALID {
+				continue
 			}
+			fmt.Println("")
+			fmt.Println("")
+			fmt.Printf("Received chaincode event from channel '%s'\n", channelID)
+			fmt.Println("------------------------")
+			fmt.Printf("Chaincode Event:%+v\n", event)
 		}
 	}
 }
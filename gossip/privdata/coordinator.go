@@ -108,6 +108,7 @@ type Fetcher interface {
 // Support encapsulates set of interfaces to
 // aggregate required functionality by single struct
 type Support struct {
+	ChainID string
 	privdata.CollectionStore
 	txvalidator.Validator
 	committer.Committer
@@ -119,6 +120,7 @@ type coordinator struct {
 	selfSignedData common.SignedData
 	Support
 	transientBlockRetention uint64
+	metrics                 *coordinatorMetrics
 }
 
 // NewCoordinator creates a new instance of coordinator
@@ -128,7 +130,8 @@ func NewCoordinator(support Support, selfSignedData common.SignedData) Coordinat
 		logger.Warning("Configuration key", transientBlockRetentionConfigKey, "isn't set, defaulting to", transientBlockRetentionDefault)
 		transientBlockRetention = transientBlockRetentionDefault
 	}
-	return &coordinator{Support: support, selfSignedData: selfSignedData, transientBlockRetention: transientBlockRetention}
+	return &coordinator{Support: support, selfSignedData: selfSignedData, transientBlockRetention: transientBlockRetention,
+		metrics: newCoordinatorMetrics(support.ChainID)}
 }
 
 // StorePvtData used to persist private date into transient store
@@ -192,6 +195,7 @@ func (c *coordinator) StoreBlock(block *common.Block, privateDataSets util.PvtDa
 
 	// Only log results if we actually attempted to fetch
 	if bFetchFromPeers {
+		c.metrics.fetchDuration.RecordDuration(time.Since(start))
 		if len(privateInfo.missingKeys) == 0 {
 			logger.Debug("Fetched all missing collection private write sets from remote peers")
 		} else {
@@ -218,6 +222,7 @@ func (c *coordinator) StoreBlock(block *common.Block, privateDataSets util.PvtDa
 			SeqInBlock: int(missingRWS.seqInBlock),
 		})
 	}
+	c.metrics.missingPvtData.Inc(int64(len(blockAndPvtData.Missing)))
 
 	// commit block and private data
 	err = c.CommitWithPvtData(blockAndPvtData)
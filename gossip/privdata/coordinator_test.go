@@ -16,6 +16,7 @@ import (
 	"time"
 
 	pb "github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/metrics"
 	util2 "github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/common/privdata"
 	"github.com/hyperledger/fabric/core/ledger"
@@ -158,6 +159,22 @@ func (mock *committerMock) CommitWithPvtData(blockAndPvtData *ledger.BlockAndPvt
 	return args.Error(0)
 }
 
+func (mock *committerMock) CommitPvtDataOfOldBlocks(blockPvtData []*ledger.BlockPvtData) ([]*ledger.PvtdataHashMismatch, error) {
+	args := mock.Called(blockPvtData)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*ledger.PvtdataHashMismatch), args.Error(1)
+}
+
+func (mock *committerMock) GetMissingPvtDataTracker() (ledger.MissingPvtDataTracker, error) {
+	args := mock.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(ledger.MissingPvtDataTracker), args.Error(1)
+}
+
 func (mock *committerMock) GetPvtDataAndBlockByNum(seqNum uint64) (*ledger.BlockAndPvtData, error) {
 	args := mock.Called(seqNum)
 	if args.Get(0) == nil {
@@ -1033,6 +1050,9 @@ func TestCoordinatorStoreBlock(t *testing.T) {
 func TestProceedWithoutPrivateData(t *testing.T) {
 	// Scenario: we are missing private data (c2 in ns3) and it cannot be obtained from any peer.
 	// Block needs to be committed with missing private data.
+	ts, restore := metrics.UseTestScope()
+	defer restore()
+
 	peerSelfSignedData := common.SignedData{
 		Identity:  []byte{0, 1, 2},
 		Signature: []byte{3, 4, 5},
@@ -1102,6 +1122,7 @@ func TestProceedWithoutPrivateData(t *testing.T) {
 	block := bf.AddTxn("tx1", "ns3", hash, "c3", "c2").create()
 	pvtData := pdFactory.addRWSet().addNSRWSet("ns3", "c3").create()
 	coordinator := NewCoordinator(Support{
+		ChainID:         "test",
 		CollectionStore: cs,
 		Committer:       committer,
 		Fetcher:         fetcher,
@@ -1112,6 +1133,12 @@ func TestProceedWithoutPrivateData(t *testing.T) {
 	assert.NoError(t, err)
 	assertCommitHappened()
 	assertPurged("tx1")
+
+	// the fetch from remote peers has been timed, and the private data
+	// that could not be obtained has been counted as missing
+	tags := map[string]string{"channel": "test"}
+	assert.Len(t, ts.HistogramValues("privdata.fetch_duration", tags), 1)
+	assert.Equal(t, int64(1), ts.CounterValue("privdata.missing_pvtdata", tags))
 }
 
 func TestCoordinatorGetBlocks(t *testing.T) {
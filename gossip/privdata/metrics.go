@@ -0,0 +1,27 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privdata
+
+import (
+	"github.com/hyperledger/fabric/common/metrics"
+)
+
+// coordinatorMetrics holds the metrics of the private data handling of the blocks of a channel
+type coordinatorMetrics struct {
+	// fetchDuration is the time spent fetching missing private data from remote peers
+	fetchDuration metrics.Histogram
+	// missingPvtData counts the collection private write sets committed as missing
+	missingPvtData metrics.Counter
+}
+
+func newCoordinatorMetrics(chainID string) *coordinatorMetrics {
+	scope := metrics.GetRootScope().SubScope("privdata").Tagged(map[string]string{"channel": chainID})
+	return &coordinatorMetrics{
+		fetchDuration:  scope.Histogram("fetch_duration"),
+		missingPvtData: scope.Counter("missing_pvtdata"),
+	}
+}
@@ -0,0 +1,352 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privdata
+
+import (
+	"encoding/hex"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	util2 "github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/committer"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/protos/common"
+	gossip2 "github.com/hyperledger/fabric/protos/gossip"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+const (
+	reconcileSleepIntervalConfigKey = "peer.gossip.pvtData.reconcileSleepInterval"
+	reconcileSleepIntervalDefault   = time.Minute
+	reconcileBatchSizeConfigKey     = "peer.gossip.pvtData.reconcileBatchSize"
+	reconcileBatchSizeDefault       = 10
+	reconciliationEnabledConfigKey  = "peer.gossip.pvtData.reconciliationEnabled"
+)
+
+// Reconciler completes the private data that was missing when the corresponding blocks were committed.
+// This is done by periodically pulling the missing private data from other eligible peers and committing
+// it into the ledger
+type Reconciler interface {
+	// Start starts the reconciler in a background goroutine
+	Start()
+	// Stop stops the reconciler
+	Stop()
+}
+
+// ReconcilerConfig holds the configuration of the private data reconciliation
+type ReconcilerConfig struct {
+	SleepInterval time.Duration
+	BatchSize     int
+	IsEnabled     bool
+}
+
+// GetReconcilerConfig returns the configuration of the private data reconciliation from the peer's configuration
+func GetReconcilerConfig() *ReconcilerConfig {
+	sleepInterval := viper.GetDuration(reconcileSleepIntervalConfigKey)
+	if sleepInterval == 0 {
+		logger.Warning("Configuration key", reconcileSleepIntervalConfigKey, "isn't set, defaulting to", reconcileSleepIntervalDefault)
+		sleepInterval = reconcileSleepIntervalDefault
+	}
+	batchSize := viper.GetInt(reconcileBatchSizeConfigKey)
+	if batchSize == 0 {
+		logger.Warning("Configuration key", reconcileBatchSizeConfigKey, "isn't set, defaulting to", reconcileBatchSizeDefault)
+		batchSize = reconcileBatchSizeDefault
+	}
+	isEnabled := true
+	if viper.IsSet(reconciliationEnabledConfigKey) {
+		isEnabled = viper.GetBool(reconciliationEnabledConfigKey)
+	}
+	return &ReconcilerConfig{SleepInterval: sleepInterval, BatchSize: batchSize, IsEnabled: isEnabled}
+}
+
+type reconciler struct {
+	config *ReconcilerConfig
+	committer.Committer
+	Fetcher
+	stopChan  chan struct{}
+	startOnce sync.Once
+	stopOnce  sync.Once
+	// resumeBelow is the block below which the next reconciliation round looks for
+	// missing private data, or 0 if it starts from the most recent blocks
+	resumeBelow uint64
+}
+
+// NewReconciler creates a new instance of reconciler
+func NewReconciler(c committer.Committer, fetcher Fetcher, config *ReconcilerConfig) Reconciler {
+	return &reconciler{
+		config:    config,
+		Committer: c,
+		Fetcher:   fetcher,
+		stopChan:  make(chan struct{}),
+	}
+}
+
+// Start implements function in the interface 'Reconciler'
+func (r *reconciler) Start() {
+	if !r.config.IsEnabled {
+		logger.Info("Private data reconciliation is disabled")
+		return
+	}
+	r.startOnce.Do(func() {
+		go r.run()
+	})
+}
+
+// Stop implements function in the interface 'Reconciler'
+func (r *reconciler) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stopChan)
+	})
+}
+
+func (r *reconciler) run() {
+	for {
+		select {
+		case <-r.stopChan:
+			return
+		case <-time.After(r.config.SleepInterval):
+			logger.Debug("Start reconcile missing private data")
+			if err := r.reconcile(); err != nil {
+				logger.Error("Failed reconciling missing private data:", err)
+			}
+		}
+	}
+}
+
+// reconcile fetches the missing private data of the most recent blocks from the other peers,
+// and commits it into the ledger. If some of the missing private data of these blocks is not
+// reconciled, the next round moves on to the blocks below them, so that the private data that
+// cannot be fetched doesn't prevent the reconciliation of the older blocks. Once there are no
+// older blocks with missing private data, the reconciliation starts over from the most recent blocks
+func (r *reconciler) reconcile() error {
+	missingPvtDataTracker, err := r.GetMissingPvtDataTracker()
+	if err != nil {
+		return errors.WithMessage(err, "failed obtaining missing private data tracker")
+	}
+	missingPvtDataInfo, err := r.getMissingPvtDataInfo(missingPvtDataTracker)
+	if err != nil {
+		return errors.WithMessage(err, "failed obtaining missing private data from the ledger")
+	}
+	if len(missingPvtDataInfo) == 0 {
+		logger.Debug("No missing private data to reconcile")
+		return nil
+	}
+
+	reconciled, err := r.reconcileMissingPvtData(missingPvtDataInfo)
+	if reconciled {
+		r.resumeBelow = 0
+		return err
+	}
+	r.resumeBelow = lowestBlockNum(missingPvtDataInfo)
+	logger.Debugf("Not all the missing private data was reconciled, the next round resumes below block [%d]", r.resumeBelow)
+	return err
+}
+
+// getMissingPvtDataInfo returns the missing private data of the blocks of the current round, which
+// follow the blocks of the previous round or, when there are none left, are the most recent blocks
+func (r *reconciler) getMissingPvtDataInfo(tracker ledger.MissingPvtDataTracker) (ledger.MissingPvtDataInfo, error) {
+	if r.resumeBelow != 0 {
+		missingPvtDataInfo, err := tracker.GetMissingPvtDataInfoForMostRecentBlocksBelow(r.resumeBelow, r.config.BatchSize)
+		if err != nil || len(missingPvtDataInfo) != 0 {
+			return missingPvtDataInfo, err
+		}
+		r.resumeBelow = 0
+	}
+	return tracker.GetMissingPvtDataInfoForMostRecentBlocks(r.config.BatchSize)
+}
+
+// reconcileMissingPvtData fetches and commits the given missing private data, and returns
+// whether all of it was reconciled
+func (r *reconciler) reconcileMissingPvtData(missingPvtDataInfo ledger.MissingPvtDataInfo) (bool, error) {
+	dig2src, missingKeys := r.getDig2SrcAndMissingKeys(missingPvtDataInfo)
+	if len(dig2src) == 0 {
+		logger.Debug("No missing private data found in the blocks to reconcile")
+		return false, nil
+	}
+	fetchedData, err := r.fetch(dig2src)
+	if err != nil {
+		return false, errors.WithMessage(err, "failed fetching missing private data from peers")
+	}
+
+	blocksPvtData := r.preparePvtDataToCommit(fetchedData, missingKeys)
+	if len(blocksPvtData) == 0 {
+		logger.Debug("None of the missing private data was fetched from peers")
+		return false, nil
+	}
+	pvtdataHashMismatches, err := r.CommitPvtDataOfOldBlocks(blocksPvtData)
+	if err != nil {
+		return false, errors.WithMessage(err, "failed committing the reconciled private data")
+	}
+	for _, mismatch := range pvtdataHashMismatches {
+		logger.Warningf("Reconciled private data of collection [%s:%s] of tx [%d] in block [%d] does not match with the hash in the block",
+			mismatch.Namespace, mismatch.Collection, mismatch.TxNum, mismatch.BlockNum)
+	}
+	logger.Infof("Reconciled private data of %d block(s)", len(blocksPvtData))
+	// preparePvtDataToCommit removes the keys of the private data to commit from missingKeys
+	return len(missingKeys) == 0 && len(pvtdataHashMismatches) == 0 && len(dig2src) == missingCollectionsCount(missingPvtDataInfo), nil
+}
+
+// lowestBlockNum returns the lowest block number of the given missing private data
+func lowestBlockNum(missingPvtDataInfo ledger.MissingPvtDataInfo) uint64 {
+	lowest := uint64(math.MaxUint64)
+	for blockNum := range missingPvtDataInfo {
+		if blockNum < lowest {
+			lowest = blockNum
+		}
+	}
+	return lowest
+}
+
+// missingCollectionsCount returns the number of missing collection private write sets in the given missing private data
+func missingCollectionsCount(missingPvtDataInfo ledger.MissingPvtDataInfo) int {
+	count := 0
+	for _, missingBlockPvtDataInfo := range missingPvtDataInfo {
+		for _, missingColls := range missingBlockPvtDataInfo {
+			count += len(missingColls)
+		}
+	}
+	return count
+}
+
+type blockAndSeqInBlock struct {
+	blockNum   uint64
+	seqInBlock uint64
+}
+
+// reconcileKey identifies a missing collection private write set and its hash as present in the block
+type reconcileKey struct {
+	blockNum uint64
+	rwSetKey
+}
+
+// getDig2SrcAndMissingKeys parses the blocks that have missing private data and returns the digests to fetch
+// along with their sources (i.e., the endorsers), and the keys of the missing private write sets
+func (r *reconciler) getDig2SrcAndMissingKeys(missingPvtDataInfo ledger.MissingPvtDataInfo) (dig2sources, map[reconcileKey]struct{}) {
+	dig2src := make(dig2sources)
+	missingKeys := make(map[reconcileKey]struct{})
+
+	var blockNums []uint64
+	for blockNum := range missingPvtDataInfo {
+		blockNums = append(blockNums, blockNum)
+	}
+	sort.Slice(blockNums, func(i, j int) bool { return blockNums[i] < blockNums[j] })
+
+	for _, block := range r.GetBlocks(blockNums) {
+		if block.Header == nil || block.Metadata == nil ||
+			len(block.Metadata.Metadata) <= int(common.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+			logger.Warning("Skipping block without a header or a Tx filter bitmap")
+			continue
+		}
+		blockNum := block.Header.Number
+		missingBlockPvtDataInfo := missingPvtDataInfo[blockNum]
+		txsFilter := txValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+		if len(txsFilter) != len(block.Data.Data) {
+			logger.Warningf("Skipping block [%d] as its data size(%d) is different from Tx filter size(%d)",
+				blockNum, len(block.Data.Data), len(txsFilter))
+			continue
+		}
+		blockData(block.Data.Data).forEachTxn(txsFilter, func(seqInBlock uint64, chdr *common.ChannelHeader, txRWSet *rwsetutil.TxRwSet, endorsers []*peer.Endorsement) {
+			missingColls, exists := missingBlockPvtDataInfo[seqInBlock]
+			if !exists {
+				return
+			}
+			for _, missingColl := range missingColls {
+				hash := pvtRWSetHash(txRWSet, missingColl.Namespace, missingColl.Collection)
+				if hash == nil {
+					logger.Warningf("Collection [%s:%s] of tx [%d] in block [%d] does not exist in the block",
+						missingColl.Namespace, missingColl.Collection, seqInBlock, blockNum)
+					continue
+				}
+				dig := &gossip2.PvtDataDigest{
+					TxId:       chdr.TxId,
+					Namespace:  missingColl.Namespace,
+					Collection: missingColl.Collection,
+					BlockSeq:   blockNum,
+					SeqInBlock: seqInBlock,
+				}
+				dig2src[dig] = endorsers
+				missingKeys[reconcileKey{
+					blockNum: blockNum,
+					rwSetKey: rwSetKey{
+						txID:       chdr.TxId,
+						seqInBlock: seqInBlock,
+						namespace:  missingColl.Namespace,
+						collection: missingColl.Collection,
+						hash:       hex.EncodeToString(hash),
+					},
+				}] = struct{}{}
+			}
+		})
+	}
+	return dig2src, missingKeys
+}
+
+// preparePvtDataToCommit picks, out of the fetched private data, the private write sets whose hashes match
+// with the hashes present in the blocks, and groups them by the block and the transaction
+func (r *reconciler) preparePvtDataToCommit(fetchedData []*gossip2.PvtDataElement, missingKeys map[reconcileKey]struct{}) []*ledger.BlockPvtData {
+	rwSetsByTxs := make(map[blockAndSeqInBlock]readWriteSets)
+	for _, element := range fetchedData {
+		dig := element.Digest
+		for _, rws := range element.Payload {
+			key := reconcileKey{
+				blockNum: dig.BlockSeq,
+				rwSetKey: rwSetKey{
+					txID:       dig.TxId,
+					seqInBlock: dig.SeqInBlock,
+					namespace:  dig.Namespace,
+					collection: dig.Collection,
+					hash:       hex.EncodeToString(util2.ComputeSHA256(rws)),
+				},
+			}
+			if _, isMissing := missingKeys[key]; !isMissing {
+				logger.Debug("Ignoring", key.rwSetKey, "because it wasn't found in the block")
+				continue
+			}
+			delete(missingKeys, key)
+			tx := blockAndSeqInBlock{blockNum: dig.BlockSeq, seqInBlock: dig.SeqInBlock}
+			rwSetsByTxs[tx] = append(rwSetsByTxs[tx], readWriteSet{rwSetKey: key.rwSetKey, rws: rws})
+		}
+	}
+
+	blocksPvtData := make(map[uint64]*ledger.BlockPvtData)
+	for tx, rwSets := range rwSetsByTxs {
+		blockPvtData, exists := blocksPvtData[tx.blockNum]
+		if !exists {
+			blockPvtData = &ledger.BlockPvtData{BlockNum: tx.blockNum, WriteSets: make(map[uint64]*ledger.TxPvtData)}
+			blocksPvtData[tx.blockNum] = blockPvtData
+		}
+		blockPvtData.WriteSets[tx.seqInBlock] = &ledger.TxPvtData{SeqInBlock: tx.seqInBlock, WriteSet: rwSets.toRWSet()}
+	}
+
+	var res []*ledger.BlockPvtData
+	for _, blockPvtData := range blocksPvtData {
+		res = append(res, blockPvtData)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].BlockNum < res[j].BlockNum })
+	return res
+}
+
+// pvtRWSetHash returns the hash of the private write set of the given collection as present
+// in the transaction's read-write set, or nil if the collection is not present
+func pvtRWSetHash(txRWSet *rwsetutil.TxRwSet, ns, coll string) []byte {
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		if nsRWSet.NameSpace != ns {
+			continue
+		}
+		for _, collHashedRWSet := range nsRWSet.CollHashedRwSets {
+			if collHashedRWSet.CollectionName == coll {
+				return collHashedRWSet.PvtRwSetHash
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,256 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privdata
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	util2 "github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/protos/common"
+	proto "github.com/hyperledger/fabric/protos/gossip"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type missingPvtDataTrackerMock struct {
+	mock.Mock
+}
+
+func (m *missingPvtDataTrackerMock) GetMissingPvtDataInfoForMostRecentBlocks(maxBlocks int) (ledger.MissingPvtDataInfo, error) {
+	args := m.Called(maxBlocks)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(ledger.MissingPvtDataInfo), args.Error(1)
+}
+
+func (m *missingPvtDataTrackerMock) GetMissingPvtDataInfoForMostRecentBlocksBelow(blockNum uint64, maxBlocks int) (ledger.MissingPvtDataInfo, error) {
+	args := m.Called(blockNum, maxBlocks)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(ledger.MissingPvtDataInfo), args.Error(1)
+}
+
+func TestGetReconcilerConfig(t *testing.T) {
+	defer viper.Reset()
+	config := GetReconcilerConfig()
+	assert.Equal(t, &ReconcilerConfig{SleepInterval: time.Minute, BatchSize: 10, IsEnabled: true}, config)
+
+	viper.Set("peer.gossip.pvtData.reconcileSleepInterval", "5s")
+	viper.Set("peer.gossip.pvtData.reconcileBatchSize", 2)
+	viper.Set("peer.gossip.pvtData.reconciliationEnabled", false)
+	config = GetReconcilerConfig()
+	assert.Equal(t, &ReconcilerConfig{SleepInterval: 5 * time.Second, BatchSize: 2, IsEnabled: false}, config)
+}
+
+func TestNoItemsToReconcile(t *testing.T) {
+	tracker := &missingPvtDataTrackerMock{}
+	tracker.On("GetMissingPvtDataInfoForMostRecentBlocks", 10).Return(ledger.MissingPvtDataInfo{}, nil)
+	committer := &committerMock{}
+	committer.On("GetMissingPvtDataTracker").Return(tracker, nil)
+	fetcher := &fetcherMock{t: t}
+
+	r := &reconciler{config: &ReconcilerConfig{SleepInterval: time.Minute, BatchSize: 10, IsEnabled: true}, Committer: committer, Fetcher: fetcher}
+	assert.NoError(t, r.reconcile())
+	committer.AssertNotCalled(t, "GetBlocks", mock.Anything)
+	fetcher.AssertNotCalled(t, "fetch", mock.Anything)
+	committer.AssertNotCalled(t, "CommitPvtDataOfOldBlocks", mock.Anything)
+}
+
+func TestReconciliationHappyPath(t *testing.T) {
+	hash := util2.ComputeSHA256([]byte("rws-pre-image"))
+	bf := &blockFactory{channelID: "test"}
+	block := bf.AddTxnWithEndorsement("tx1", "ns1", hash, "org1", true, "c1", "c2").create()
+	block.Header.Number = 3
+
+	tracker := &missingPvtDataTrackerMock{}
+	missingPvtDataInfo := make(ledger.MissingPvtDataInfo)
+	missingPvtDataInfo.Add(3, 0, "ns1", "c1")
+	tracker.On("GetMissingPvtDataInfoForMostRecentBlocks", 10).Return(missingPvtDataInfo, nil)
+
+	committer := &committerMock{}
+	committer.On("GetMissingPvtDataTracker").Return(tracker, nil)
+	committer.On("GetBlocks", []uint64{3}).Return([]*common.Block{block})
+	var commitHappened bool
+	committer.On("CommitPvtDataOfOldBlocks", mock.Anything).Run(func(args mock.Arguments) {
+		blocksPvtData := args.Get(0).([]*ledger.BlockPvtData)
+		assert.Len(t, blocksPvtData, 1)
+		assert.Equal(t, uint64(3), blocksPvtData[0].BlockNum)
+		assert.Len(t, blocksPvtData[0].WriteSets, 1)
+		txPvtData := blocksPvtData[0].WriteSets[0]
+		assert.Equal(t, uint64(0), txPvtData.SeqInBlock)
+		assert.True(t, txPvtData.Has("ns1", "c1"))
+		assert.False(t, txPvtData.Has("ns1", "c2"))
+		assert.Equal(t, []byte("rws-pre-image"), txPvtData.WriteSet.NsPvtRwset[0].CollectionPvtRwset[0].Rwset)
+		commitHappened = true
+	}).Return(nil, nil)
+
+	fetcher := &fetcherMock{t: t}
+	fetcher.On("fetch", mock.Anything).expectingDigests([]*proto.PvtDataDigest{
+		{
+			TxId: "tx1", Namespace: "ns1", Collection: "c1", BlockSeq: 3, SeqInBlock: 0,
+		},
+	}).expectingEndorsers("org1").Return([]*proto.PvtDataElement{
+		{
+			Digest: &proto.PvtDataDigest{
+				TxId: "tx1", Namespace: "ns1", Collection: "c1", BlockSeq: 3, SeqInBlock: 0,
+			},
+			// the private write set whose hash doesn't match with the one in the block is ignored
+			Payload: [][]byte{[]byte("rws-tampered"), []byte("rws-pre-image")},
+		},
+	}, nil)
+
+	r := &reconciler{config: &ReconcilerConfig{SleepInterval: time.Minute, BatchSize: 10, IsEnabled: true}, Committer: committer, Fetcher: fetcher}
+	assert.NoError(t, r.reconcile())
+	assert.True(t, commitHappened)
+}
+
+func TestReconciliationFailures(t *testing.T) {
+	hash := util2.ComputeSHA256([]byte("rws-pre-image"))
+	bf := &blockFactory{channelID: "test"}
+	block := bf.AddTxnWithEndorsement("tx1", "ns1", hash, "org1", true, "c1").create()
+	missingPvtDataInfo := make(ledger.MissingPvtDataInfo)
+	missingPvtDataInfo.Add(1, 0, "ns1", "c1")
+	config := &ReconcilerConfig{SleepInterval: time.Minute, BatchSize: 10, IsEnabled: true}
+
+	// failure in obtaining the missing private data from the ledger
+	tracker := &missingPvtDataTrackerMock{}
+	tracker.On("GetMissingPvtDataInfoForMostRecentBlocks", 10).Return(nil, errors.New("ledger error"))
+	committer := &committerMock{}
+	committer.On("GetMissingPvtDataTracker").Return(tracker, nil)
+	r := &reconciler{config: config, Committer: committer, Fetcher: &fetcherMock{t: t}}
+	err := r.reconcile()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "ledger error")
+
+	// failure in fetching the missing private data from peers
+	tracker = &missingPvtDataTrackerMock{}
+	tracker.On("GetMissingPvtDataInfoForMostRecentBlocks", 10).Return(missingPvtDataInfo, nil)
+	committer = &committerMock{}
+	committer.On("GetMissingPvtDataTracker").Return(tracker, nil)
+	committer.On("GetBlocks", []uint64{1}).Return([]*common.Block{block})
+	fetcher := &fetcherMock{t: t}
+	fetcher.On("fetch", mock.Anything).expectingDigests([]*proto.PvtDataDigest{
+		{
+			TxId: "tx1", Namespace: "ns1", Collection: "c1", BlockSeq: 1, SeqInBlock: 0,
+		},
+	}).expectingEndorsers("org1").Return(nil, errors.New("fetch error"))
+	r = &reconciler{config: config, Committer: committer, Fetcher: fetcher}
+	err = r.reconcile()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "fetch error")
+	committer.AssertNotCalled(t, "CommitPvtDataOfOldBlocks", mock.Anything)
+}
+
+func TestReconciliationMovesPastFailures(t *testing.T) {
+	// Scenario: the missing private data of the most recent block 5 cannot be fetched.
+	// The next round reconciles the missing private data of the older block 3, and once
+	// there are no older blocks with missing private data, the reconciliation starts over
+	// from the most recent blocks
+	hash := util2.ComputeSHA256([]byte("rws-pre-image"))
+	bf := &blockFactory{channelID: "test"}
+	block5 := bf.AddTxnWithEndorsement("tx5", "ns1", hash, "org1", true, "c1").create()
+	block5.Header.Number = 5
+	bf = &blockFactory{channelID: "test"}
+	block3 := bf.AddTxnWithEndorsement("tx3", "ns1", hash, "org1", true, "c1").create()
+	block3.Header.Number = 3
+
+	missingOfBlock5 := make(ledger.MissingPvtDataInfo)
+	missingOfBlock5.Add(5, 0, "ns1", "c1")
+	missingOfBlock3 := make(ledger.MissingPvtDataInfo)
+	missingOfBlock3.Add(3, 0, "ns1", "c1")
+
+	tracker := &missingPvtDataTrackerMock{}
+	tracker.On("GetMissingPvtDataInfoForMostRecentBlocks", 1).Return(missingOfBlock5, nil)
+	tracker.On("GetMissingPvtDataInfoForMostRecentBlocksBelow", uint64(5), 1).Return(missingOfBlock3, nil).Once()
+	tracker.On("GetMissingPvtDataInfoForMostRecentBlocksBelow", uint64(5), 1).Return(ledger.MissingPvtDataInfo{}, nil)
+	committer := &committerMock{}
+	committer.On("GetMissingPvtDataTracker").Return(tracker, nil)
+	committer.On("GetBlocks", []uint64{5}).Return([]*common.Block{block5})
+	committer.On("GetBlocks", []uint64{3}).Return([]*common.Block{block3})
+	var committedBlocks []uint64
+	committer.On("CommitPvtDataOfOldBlocks", mock.Anything).Run(func(args mock.Arguments) {
+		for _, blockPvtData := range args.Get(0).([]*ledger.BlockPvtData) {
+			committedBlocks = append(committedBlocks, blockPvtData.BlockNum)
+		}
+	}).Return(nil, nil)
+
+	fetcherOf := func(block *common.Block, txID string, payload [][]byte) *fetcherMock {
+		dig := &proto.PvtDataDigest{
+			TxId: txID, Namespace: "ns1", Collection: "c1", BlockSeq: block.Header.Number, SeqInBlock: 0,
+		}
+		fetcher := &fetcherMock{t: t}
+		elements := []*proto.PvtDataElement{}
+		if payload != nil {
+			elements = append(elements, &proto.PvtDataElement{Digest: dig, Payload: payload})
+		}
+		fetcher.On("fetch", mock.Anything).expectingDigests([]*proto.PvtDataDigest{dig}).expectingEndorsers("org1").Return(elements, nil)
+		return fetcher
+	}
+
+	r := &reconciler{config: &ReconcilerConfig{SleepInterval: time.Minute, BatchSize: 1, IsEnabled: true}, Committer: committer}
+	// the missing private data of block 5 isn't fetched
+	r.Fetcher = fetcherOf(block5, "tx5", nil)
+	assert.NoError(t, r.reconcile())
+	assert.Empty(t, committedBlocks)
+	assert.Equal(t, uint64(5), r.resumeBelow)
+
+	// the next round moves on to block 3
+	r.Fetcher = fetcherOf(block3, "tx3", [][]byte{[]byte("rws-pre-image")})
+	assert.NoError(t, r.reconcile())
+	assert.Equal(t, []uint64{3}, committedBlocks)
+	assert.Equal(t, uint64(0), r.resumeBelow)
+	tracker.AssertNumberOfCalls(t, "GetMissingPvtDataInfoForMostRecentBlocksBelow", 1)
+
+	// having reconciled the older blocks, the next round starts over from block 5
+	r.Fetcher = fetcherOf(block5, "tx5", nil)
+	assert.NoError(t, r.reconcile())
+	assert.Equal(t, uint64(5), r.resumeBelow)
+	tracker.AssertNumberOfCalls(t, "GetMissingPvtDataInfoForMostRecentBlocks", 2)
+
+	// when there are no older blocks with missing private data, the round starts over from block 5 right away
+	r.Fetcher = fetcherOf(block5, "tx5", nil)
+	assert.NoError(t, r.reconcile())
+	tracker.AssertNumberOfCalls(t, "GetMissingPvtDataInfoForMostRecentBlocksBelow", 2)
+	tracker.AssertNumberOfCalls(t, "GetMissingPvtDataInfoForMostRecentBlocks", 3)
+	assert.Equal(t, uint64(5), r.resumeBelow)
+}
+
+func TestReconcilerStartAndStop(t *testing.T) {
+	reconcileAttempted := make(chan struct{}, 10)
+	tracker := &missingPvtDataTrackerMock{}
+	tracker.On("GetMissingPvtDataInfoForMostRecentBlocks", 10).Run(func(_ mock.Arguments) {
+		reconcileAttempted <- struct{}{}
+	}).Return(ledger.MissingPvtDataInfo{}, nil)
+	committer := &committerMock{}
+	committer.On("GetMissingPvtDataTracker").Return(tracker, nil)
+
+	// a disabled reconciler doesn't attempt reconciliation
+	r := NewReconciler(committer, &fetcherMock{t: t}, &ReconcilerConfig{SleepInterval: 10 * time.Millisecond, BatchSize: 10, IsEnabled: false})
+	r.Start()
+	select {
+	case <-reconcileAttempted:
+		t.Fatal("Reconciliation attempted although the reconciler is disabled")
+	case <-time.After(100 * time.Millisecond):
+	}
+	r.Stop()
+
+	r = NewReconciler(committer, &fetcherMock{t: t}, &ReconcilerConfig{SleepInterval: 10 * time.Millisecond, BatchSize: 10, IsEnabled: true})
+	r.Start()
+	select {
+	case <-reconcileAttempted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Reconciliation wasn't attempted")
+	}
+	r.Stop()
+	r.Stop()
+}
@@ -341,6 +341,22 @@ func (li *mockLedgerInfo) CommitWithPvtData(blockAndPvtData *ledger.BlockAndPvtD
 	panic("implement me")
 }
 
+func (li *mockLedgerInfo) CommitPvtDataOfOldBlocks(blockPvtData []*ledger.BlockPvtData) ([]*ledger.PvtdataHashMismatch, error) {
+	panic("implement me")
+}
+
+func (li *mockLedgerInfo) GetMissingPvtDataTracker() (ledger.MissingPvtDataTracker, error) {
+	return li, nil
+}
+
+func (li *mockLedgerInfo) GetMissingPvtDataInfoForMostRecentBlocks(maxBlocks int) (ledger.MissingPvtDataInfo, error) {
+	return nil, nil
+}
+
+func (li *mockLedgerInfo) GetMissingPvtDataInfoForMostRecentBlocksBelow(blockNum uint64, maxBlocks int) (ledger.MissingPvtDataInfo, error) {
+	return nil, nil
+}
+
 func (li *mockLedgerInfo) GetPvtDataAndBlockByNum(seqNum uint64) (*ledger.BlockAndPvtData, error) {
 	panic("implement me")
 }
@@ -40,12 +40,25 @@ const (
 	defAntiEntropyInterval             = 10 * time.Second
 	defAntiEntropyStateResponseTimeout = 3 * time.Second
 	defAntiEntropyBatchSize            = 10
+	defAntiEntropyParallelRequests     = 1
+	defAntiEntropyProgressInterval     = 10 * time.Second
 
 	defChannelBufferSize     = 100
 	defAntiEntropyMaxRetries = 3
 
 	defMaxBlockDistance = 100
 
+	// maxAntiEntropyBatchSize is the largest batch of blocks served in response
+	// to a single state request, whatever the batch size the serving peer requests
+	maxAntiEntropyBatchSize = defMaxBlockDistance
+
+	antiEntropyIntervalConfigKey         = "peer.gossip.state.checkInterval"
+	antiEntropyResponseTimeoutConfigKey  = "peer.gossip.state.responseTimeout"
+	antiEntropyBatchSizeConfigKey        = "peer.gossip.state.batchSize"
+	antiEntropyMaxRetriesConfigKey       = "peer.gossip.state.maxRetries"
+	antiEntropyParallelRequestsConfigKey = "peer.gossip.state.parallelRequests"
+	antiEntropyProgressIntervalConfigKey = "peer.gossip.state.progressReportInterval"
+
 	blocking    = true
 	nonBlocking = false
 
@@ -109,6 +122,54 @@ type ledgerResources interface {
 	Close()
 }
 
+// stateConfig holds the configuration of the anti-entropy state transfer
+type stateConfig struct {
+	// interval between two checks of the ledger heights of the other peers
+	antiEntropyInterval time.Duration
+	// time to wait for the response to a state request before resending it
+	antiEntropyStateResponseTimeout time.Duration
+	// maximum difference between the last and the first sequence number of a
+	// state request, both for the requests sent and the requests served
+	antiEntropyBatchSize uint64
+	// number of times a state request is resent before the state transfer is aborted
+	antiEntropyMaxRetries int
+	// number of state requests that are in flight at the same time, each to the
+	// least busy of the peers that have the blocks
+	antiEntropyParallelRequests int
+	// interval between two reports of the progress of a state transfer
+	antiEntropyProgressInterval time.Duration
+}
+
+// getStateConfig reads the configuration of the state transfer from the peer's configuration
+func getStateConfig() *stateConfig {
+	config := &stateConfig{
+		antiEntropyInterval:             util.GetDurationOrDefault(antiEntropyIntervalConfigKey, defAntiEntropyInterval),
+		antiEntropyStateResponseTimeout: util.GetDurationOrDefault(antiEntropyResponseTimeoutConfigKey, defAntiEntropyStateResponseTimeout),
+		antiEntropyBatchSize:            uint64(util.GetIntOrDefault(antiEntropyBatchSizeConfigKey, defAntiEntropyBatchSize)),
+		antiEntropyMaxRetries:           util.GetIntOrDefault(antiEntropyMaxRetriesConfigKey, defAntiEntropyMaxRetries),
+		antiEntropyParallelRequests:     util.GetIntOrDefault(antiEntropyParallelRequestsConfigKey, defAntiEntropyParallelRequests),
+		antiEntropyProgressInterval:     util.GetDurationOrDefault(antiEntropyProgressIntervalConfigKey, defAntiEntropyProgressInterval),
+	}
+	if config.antiEntropyBatchSize > maxAntiEntropyBatchSize {
+		logger.Warningf("%s is %d, but peers serve at most %d blocks per state request, using %d",
+			antiEntropyBatchSizeConfigKey, config.antiEntropyBatchSize, maxAntiEntropyBatchSize, maxAntiEntropyBatchSize)
+		config.antiEntropyBatchSize = maxAntiEntropyBatchSize
+	}
+	// The blocks received ahead of the ledger height wait in the payloads buffer,
+	// which blocks the state transfer once it holds more than defMaxBlockDistance*2
+	// payloads, so the requests in flight must not span more than defMaxBlockDistance blocks
+	if maxRequests := int(defMaxBlockDistance / (config.antiEntropyBatchSize + 1)); config.antiEntropyParallelRequests > maxRequests {
+		if maxRequests < 1 {
+			maxRequests = 1
+		}
+		logger.Warningf("%s is %d, but at most %d requests of %d blocks fit in the payloads buffer, using %d",
+			antiEntropyParallelRequestsConfigKey, config.antiEntropyParallelRequests, maxRequests,
+			config.antiEntropyBatchSize+1, maxRequests)
+		config.antiEntropyParallelRequests = maxRequests
+	}
+	return config
+}
+
 // ServicesMediator aggregated adapter to compound all mediator
 // required by state transfer into single struct
 type ServicesMediator struct {
@@ -123,6 +184,8 @@ type GossipStateProviderImpl struct {
 	// Chain id
 	chainID string
 
+	config *stateConfig
+
 	mediator *ServicesMediator
 
 	// Channel to read gossip messages from
@@ -203,6 +266,8 @@ func NewGossipStateProvider(chainID string, services *ServicesMediator, ledger l
 		// Chain ID
 		chainID: chainID,
 
+		config: getStateConfig(),
+
 		// Channel to read new messages from
 		gossipChan: gossipChan,
 
@@ -218,7 +283,7 @@ func NewGossipStateProvider(chainID string, services *ServicesMediator, ledger l
 
 		stateRequestCh: make(chan proto.ReceivedMessage, defChannelBufferSize),
 
-		stopCh: make(chan struct{}, 1),
+		stopCh: make(chan struct{}),
 
 		stateTransferActive: 0,
 
@@ -256,7 +321,6 @@ func (s *GossipStateProviderImpl) listen() {
 			logger.Debug("Dispatching a message", msg)
 			go s.dispatch(msg)
 		case <-s.stopCh:
-			s.stopCh <- struct{}{}
 			logger.Debug("Stop listening for new messages")
 			return
 		}
@@ -360,7 +424,6 @@ func (s *GossipStateProviderImpl) processStateRequests() {
 		case msg := <-s.stateRequestCh:
 			s.handleStateRequest(msg)
 		case <-s.stopCh:
-			s.stopCh <- struct{}{}
 			return
 		}
 	}
@@ -375,9 +438,9 @@ func (s *GossipStateProviderImpl) handleStateRequest(msg proto.ReceivedMessage)
 	request := msg.GetGossipMessage().GetStateRequest()
 
 	batchSize := request.EndSeqNum - request.StartSeqNum
-	if batchSize > defAntiEntropyBatchSize {
-		logger.Errorf("Requesting blocks batchSize size (%d) greater than configured allowed"+
-			" (%d) batching for anti-entropy. Ignoring request...", batchSize, defAntiEntropyBatchSize)
+	if batchSize > maxAntiEntropyBatchSize {
+		logger.Errorf("Requesting blocks batchSize size (%d) greater than allowed"+
+			" (%d) batching for anti-entropy. Ignoring request...", batchSize, maxAntiEntropyBatchSize)
 		return
 	}
 
@@ -486,14 +549,13 @@ func (s *GossipStateProviderImpl) Stop() {
 	// Make sure stop won't be executed twice
 	// and stop channel won't be used again
 	s.once.Do(func() {
-		s.stopCh <- struct{}{}
+		close(s.stopCh)
 		// Make sure all go-routines has finished
 		s.done.Wait()
 		// Close all resources
 		s.ledger.Close()
 		close(s.stateRequestCh)
 		close(s.stateResponseCh)
-		close(s.stopCh)
 	})
 }
 
@@ -557,7 +619,6 @@ func (s *GossipStateProviderImpl) deliverPayloads() {
 				}
 			}
 		case <-s.stopCh:
-			s.stopCh <- struct{}{}
 			logger.Debug("State provider has been stopped, finishing to push new blocks.")
 			return
 		}
@@ -571,9 +632,8 @@ func (s *GossipStateProviderImpl) antiEntropy() {
 	for {
 		select {
 		case <-s.stopCh:
-			s.stopCh <- struct{}{}
 			return
-		case <-time.After(defAntiEntropyInterval):
+		case <-time.After(s.config.antiEntropyInterval):
 			ourHeight, err := s.ledger.LedgerHeight()
 			if err != nil {
 				// Unable to read from ledger continue to the next round
@@ -611,64 +671,197 @@ func (s *GossipStateProviderImpl) maxAvailableLedgerHeight() uint64 {
 	return max
 }
 
-// GetBlocksInRange capable to acquire blocks with sequence
-// numbers in the range [start...end].
+// stateRequest is a request for the blocks in range [start...end] during a state transfer
+type stateRequest struct {
+	msg   *proto.GossipMessage
+	start uint64
+	end   uint64
+	// tries is the number of times the request has been sent
+	tries int
+	// peer is the endpoint of the peer the request is in flight to, empty
+	// if the request is waiting to be sent
+	peer     string
+	deadline time.Time
+}
+
+// requestBlocksInRange acquires the blocks with sequence numbers in the range [start...end].
+// The range is split in batches of antiEntropyBatchSize blocks and up to antiEntropyParallelRequests
+// batches, the lowest ones not received yet, are requested at the same time from different peers.
+// The received blocks are verified and pushed into the payloads buffer, from which they are
+// committed in order.
 func (s *GossipStateProviderImpl) requestBlocksInRange(start uint64, end uint64) {
 	atomic.StoreInt32(&s.stateTransferActive, 1)
 	defer atomic.StoreInt32(&s.stateTransferActive, 0)
 
-	for prev := start; prev <= end; {
-		next := min(end, prev+defAntiEntropyBatchSize)
+	progress := newStateTransferProgress(s.chainID, start, end, s.config.antiEntropyProgressInterval)
+	defer progress.finish()
 
-		gossipMsg := s.stateRequestMessage(prev, next)
+	// pending are the requests of the window, in the order of their sequence numbers
+	var pending []*stateRequest
+	// inFlight is the number of requests in flight to each peer
+	inFlight := make(map[string]int)
+	next := start
+	for {
+		for len(pending) < s.config.antiEntropyParallelRequests && next <= end {
+			last := min(end, next+s.config.antiEntropyBatchSize)
+			// The window must not run more than defMaxBlockDistance blocks ahead of its
+			// lowest request, or the payloads buffer fills up while that request is retried
+			if len(pending) > 0 && last-pending[0].start >= defMaxBlockDistance {
+				break
+			}
+			pending = append(pending, &stateRequest{msg: s.stateRequestMessage(next, last), start: next, end: last})
+			next = last + 1
+		}
+		if len(pending) == 0 {
+			return
+		}
 
-		responseReceived := false
-		tryCounts := 0
+		deadline := time.Time{}
+		for _, req := range pending {
+			if req.peer == "" {
+				if req.tries > s.config.antiEntropyMaxRetries {
+					logger.Warningf("Wasn't  able to get blocks in range [%d...%d], after %d retries",
+						req.start, req.end, req.tries)
+					return
+				}
+				// Select peers to ask for blocks
+				peer, err := s.selectPeerToRequestFrom(req.end, inFlight)
+				if err != nil {
+					logger.Warningf("Cannot send state request for blocks in range [%d...%d], due to %+v",
+						req.start, req.end, errors.WithStack(err))
+					return
+				}
 
-		for !responseReceived {
-			if tryCounts > defAntiEntropyMaxRetries {
-				logger.Warningf("Wasn't  able to get blocks in range [%d...%d], after %d retries",
-					prev, next, tryCounts)
-				return
+				logger.Debugf("State transfer, with peer %s, requesting blocks in range [%d...%d], "+
+					"for chainID %s", peer.Endpoint, req.start, req.end, s.chainID)
+
+				s.mediator.Send(req.msg, peer)
+				req.tries++
+				req.peer = peer.Endpoint
+				req.deadline = time.Now().Add(s.config.antiEntropyStateResponseTimeout)
+				inFlight[req.peer]++
 			}
-			// Select peers to ask for blocks
-			peer, err := s.selectPeerToRequestFrom(next)
-			if err != nil {
-				logger.Warningf("Cannot send state request for blocks in range [%d...%d], due to %+v",
-					prev, next, errors.WithStack(err))
-				return
+			if deadline.IsZero() || req.deadline.Before(deadline) {
+				deadline = req.deadline
 			}
+		}
 
-			logger.Debugf("State transfer, with peer %s, requesting blocks in range [%d...%d], "+
-				"for chainID %s", peer.Endpoint, prev, next, s.chainID)
-
-			s.mediator.Send(gossipMsg, peer)
-			tryCounts++
-
-			// Wait until timeout or response arrival
-			select {
-			case msg := <-s.stateResponseCh:
-				if msg.GetGossipMessage().Nonce != gossipMsg.Nonce {
-					continue
-				}
-				// Got corresponding response for state request, can continue
-				index, err := s.handleStateResponse(msg)
-				if err != nil {
-					logger.Warningf("Wasn't able to process state response for "+
-						"blocks [%d...%d], due to %+v", prev, next, errors.WithStack(err))
-					continue
+		// Wait until the first timeout or a response arrival
+		select {
+		case msg := <-s.stateResponseCh:
+			req := pendingRequest(pending, msg.GetGossipMessage().Nonce)
+			if req == nil {
+				continue
+			}
+			if req.peer != "" {
+				inFlight[req.peer]--
+				req.peer = ""
+			}
+			// Got corresponding response for state request
+			index, err := s.handleStateResponse(msg)
+			if err != nil {
+				logger.Warningf("Wasn't able to process state response for "+
+					"blocks [%d...%d], due to %+v", req.start, req.end, errors.WithStack(err))
+				continue
+			}
+			if index < req.start || index > req.end {
+				logger.Warningf("State response for blocks [%d...%d] ends with block %d, ignoring it",
+					req.start, req.end, index)
+				continue
+			}
+			progress.add(index - req.start + 1)
+			if index == req.end {
+				pending = removeRequest(pending, req)
+				continue
+			}
+			// The peer sent only part of the blocks, the rest is requested again
+			req.start = index + 1
+			req.msg = s.stateRequestMessage(req.start, req.end)
+			req.tries = 0
+		case <-time.After(time.Until(deadline)):
+			now := time.Now()
+			for _, req := range pending {
+				if req.peer != "" && !req.deadline.After(now) {
+					inFlight[req.peer]--
+					req.peer = ""
 				}
-				prev = index + 1
-				responseReceived = true
-			case <-time.After(defAntiEntropyStateResponseTimeout):
-			case <-s.stopCh:
-				s.stopCh <- struct{}{}
-				return
 			}
+		case <-s.stopCh:
+			return
 		}
 	}
 }
 
+// pendingRequest returns the request with the given nonce, or nil if there is none
+func pendingRequest(pending []*stateRequest, nonce uint64) *stateRequest {
+	for _, req := range pending {
+		if req.msg.Nonce == nonce {
+			return req
+		}
+	}
+	return nil
+}
+
+// removeRequest returns the requests without the given one
+func removeRequest(pending []*stateRequest, req *stateRequest) []*stateRequest {
+	for i := range pending {
+		if pending[i] == req {
+			return append(pending[:i], pending[i+1:]...)
+		}
+	}
+	return pending
+}
+
+// stateTransferProgress reports the progress of a state transfer
+type stateTransferProgress struct {
+	chainID    string
+	start      uint64
+	end        uint64
+	received   uint64
+	interval   time.Duration
+	began      time.Time
+	lastReport time.Time
+}
+
+func newStateTransferProgress(chainID string, start, end uint64, interval time.Duration) *stateTransferProgress {
+	now := time.Now()
+	return &stateTransferProgress{
+		chainID:    chainID,
+		start:      start,
+		end:        end,
+		interval:   interval,
+		began:      now,
+		lastReport: now,
+	}
+}
+
+// add records received blocks, and reports the progress if the
+// report interval has elapsed since the last report
+func (p *stateTransferProgress) add(blocks uint64) {
+	p.received += blocks
+	if time.Since(p.lastReport) < p.interval {
+		return
+	}
+	p.lastReport = time.Now()
+	elapsed := p.lastReport.Sub(p.began)
+	total := p.end - p.start + 1
+	logger.Infof("Channel [%s]: state transfer of blocks [%d...%d] received %d of %d blocks (%.1f%%) in %s, %.1f blocks/s",
+		p.chainID, p.start, p.end, p.received, total, 100*float64(p.received)/float64(total),
+		elapsed.Round(time.Second), float64(p.received)/elapsed.Seconds())
+}
+
+// finish reports the outcome of the state transfer, at info level if the
+// progress has been reported along the way
+func (p *stateTransferProgress) finish() {
+	elapsed := time.Since(p.began)
+	format := "Channel [%s]: state transfer of blocks [%d...%d] ended after receiving %d blocks in %s"
+	if p.lastReport.Equal(p.began) {
+		logger.Debugf(format, p.chainID, p.start, p.end, p.received, elapsed)
+		return
+	}
+	logger.Infof(format, p.chainID, p.start, p.end, p.received, elapsed.Round(time.Second))
+}
+
 // Generate state request message for given blocks in range [beginSeq...endSeq]
 func (s *GossipStateProviderImpl) stateRequestMessage(beginSeq uint64, endSeq uint64) *proto.GossipMessage {
 	return &proto.GossipMessage{
@@ -684,8 +877,9 @@ func (s *GossipStateProviderImpl) stateRequestMessage(beginSeq uint64, endSeq ui
 	}
 }
 
-// Select peer which has required blocks to ask missing blocks from
-func (s *GossipStateProviderImpl) selectPeerToRequestFrom(height uint64) (*comm.RemotePeer, error) {
+// Select peer which has required blocks to ask missing blocks from, among
+// the ones with the fewest state requests in flight
+func (s *GossipStateProviderImpl) selectPeerToRequestFrom(height uint64, inFlight map[string]int) (*comm.RemotePeer, error) {
 	// Filter peers which posses required range of missing blocks
 	peers := s.filterPeers(s.hasRequiredHeight(height))
 
@@ -694,8 +888,19 @@ func (s *GossipStateProviderImpl) selectPeerToRequestFrom(height uint64) (*comm.
 		return nil, errors.New("there are no peers to ask for missing blocks from")
 	}
 
+	var leastBusy []*comm.RemotePeer
+	for _, peer := range peers {
+		if len(leastBusy) > 0 && inFlight[peer.Endpoint] > inFlight[leastBusy[0].Endpoint] {
+			continue
+		}
+		if len(leastBusy) > 0 && inFlight[peer.Endpoint] < inFlight[leastBusy[0].Endpoint] {
+			leastBusy = leastBusy[:0]
+		}
+		leastBusy = append(leastBusy, peer)
+	}
+
 	// Select peer to ask for blocks
-	return peers[util.RandomInt(n)], nil
+	return leastBusy[util.RandomInt(len(leastBusy))], nil
 }
 
 // filterPeers return list of peers which aligns the predicate provided
@@ -751,7 +956,11 @@ func (s *GossipStateProviderImpl) addPayload(payload *proto.Payload, blockingMod
 	}
 
 	for blockingMode && s.payloads.Size() > defMaxBlockDistance*2 {
-		time.Sleep(enqueueRetryInterval)
+		select {
+		case <-s.stopCh:
+			return errors.Errorf("State provider has been stopped, cannot enqueue block with sequence of %d", payload.SeqNum)
+		case <-time.After(enqueueRetryInterval):
+		}
 	}
 
 	s.payloads.Push(payload)
@@ -39,6 +39,7 @@ import (
 	proto "github.com/hyperledger/fabric/protos/gossip"
 	"github.com/hyperledger/fabric/protos/ledger/rwset"
 	"github.com/op/go-logging"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -210,6 +211,16 @@ func (mc *mockCommitter) GetPvtDataAndBlockByNum(seqNum uint64) (*ledger.BlockAn
 	return args.Get(0).(*ledger.BlockAndPvtData), args.Error(1)
 }
 
+func (mc *mockCommitter) CommitPvtDataOfOldBlocks(blockPvtData []*ledger.BlockPvtData) ([]*ledger.PvtdataHashMismatch, error) {
+	args := mc.Called(blockPvtData)
+	return args.Get(0).([]*ledger.PvtdataHashMismatch), args.Error(1)
+}
+
+func (mc *mockCommitter) GetMissingPvtDataTracker() (ledger.MissingPvtDataTracker, error) {
+	args := mc.Called()
+	return args.Get(0).(ledger.MissingPvtDataTracker), args.Error(1)
+}
+
 func (mc *mockCommitter) LedgerHeight() (uint64, error) {
 	mc.Lock()
 	m := mc.Mock
@@ -262,6 +273,14 @@ func (mock *ramLedger) CommitWithPvtData(blockAndPvtdata *ledger.BlockAndPvtData
 	return errors.New("invalid input parameters for block and private data param")
 }
 
+func (mock *ramLedger) CommitPvtDataOfOldBlocks(blockPvtData []*ledger.BlockPvtData) ([]*ledger.PvtdataHashMismatch, error) {
+	panic("implement me")
+}
+
+func (mock *ramLedger) GetMissingPvtDataTracker() (ledger.MissingPvtDataTracker, error) {
+	panic("implement me")
+}
+
 func (mock *ramLedger) GetBlockchainInfo() (*pcomm.BlockchainInfo, error) {
 	mock.RLock()
 	defer mock.RUnlock()
@@ -506,6 +525,294 @@ func TestLargeBlockGap(t *testing.T) {
 	}
 }
 
+func TestParallelStateTransfer(t *testing.T) {
+	// Scenario: the peer knows of three peers whose ledger height is 300 blocks higher
+	// than its own. It requests several batches at the same time, spread over the peers,
+	// which respond out of order, and the blocks are committed in order.
+	// Not parallel, as the configuration is set through viper
+	gutil.SetDuration(antiEntropyIntervalConfigKey, 100*time.Millisecond)
+	viper.Set(antiEntropyParallelRequestsConfigKey, 4)
+	defer viper.Set(antiEntropyIntervalConfigKey, nil)
+	defer viper.Set(antiEntropyParallelRequestsConfigKey, nil)
+
+	mc := &mockCommitter{Mock: &mock.Mock{}}
+	blocksPassedToLedger := make(chan uint64, 300)
+	mc.On("CommitWithPvtData", mock.Anything).Run(func(arg mock.Arguments) {
+		blocksPassedToLedger <- arg.Get(0).(*pcomm.Block).Header.Number
+	})
+	msgsFromPeer := make(chan proto.ReceivedMessage)
+	mc.On("LedgerHeight", mock.Anything).Return(uint64(1), nil)
+	g := &mocks.GossipMock{}
+	var membership []discovery.NetworkMember
+	for _, endpoint := range []string{"a", "b", "c"} {
+		membership = append(membership, discovery.NetworkMember{
+			PKIid:      common.PKIidType(endpoint),
+			Endpoint:   endpoint,
+			Properties: &proto.Properties{LedgerHeight: 301},
+		})
+	}
+	g.On("PeersOfChannel", mock.Anything).Return(membership)
+	g.On("Accept", mock.Anything, false).Return(make(<-chan *proto.GossipMessage), nil)
+	g.On("Accept", mock.Anything, true).Return(nil, msgsFromPeer)
+
+	var lock sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	requestedPeers := make(map[string]struct{})
+	g.On("Send", mock.Anything, mock.Anything).Run(func(arguments mock.Arguments) {
+		msg := arguments.Get(0).(*proto.GossipMessage)
+		peers := arguments.Get(1).([]*comm.RemotePeer)
+		req := msg.GetStateRequest()
+		lock.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		requestedPeers[peers[0].Endpoint] = struct{}{}
+		lock.Unlock()
+		res := &proto.GossipMessage{
+			Nonce:   msg.Nonce,
+			Channel: []byte(util.GetTestChainID()),
+			Content: &proto.GossipMessage_StateResponse{
+				StateResponse: &proto.RemoteStateResponse{},
+			},
+		}
+		for seq := req.StartSeqNum; seq <= req.EndSeqNum; seq++ {
+			b, _ := pb.Marshal(pcomm.NewBlock(seq, []byte{}))
+			res.GetStateResponse().Payloads = append(res.GetStateResponse().Payloads, &proto.Payload{
+				SeqNum: seq,
+				Data:   b,
+			})
+		}
+		sMsg, _ := res.NoopSign()
+		// Respond after a random delay, so that the responses arrive out of order
+		go func() {
+			time.Sleep(time.Duration(rand.Intn(50)) * time.Millisecond)
+			lock.Lock()
+			inFlight--
+			lock.Unlock()
+			msgsFromPeer <- &comm.ReceivedMessageImpl{SignedGossipMessage: sMsg}
+		}()
+	})
+	portPrefix := portStartRange + 225
+	p := newPeerNodeWithGossip(newGossipConfig(portPrefix, 0), mc, noopPeerIdentityAcceptor, g)
+	defer p.shutdown()
+	assert.Equal(t, 4, p.s.config.antiEntropyParallelRequests)
+
+	for expectedSequence := 1; expectedSequence <= 300; expectedSequence++ {
+		select {
+		case blockSeq := <-blocksPassedToLedger:
+			assert.Equal(t, expectedSequence, int(blockSeq))
+		case <-time.After(30 * time.Second):
+			t.Fatalf("Block %d wasn't committed", expectedSequence)
+		}
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	assert.True(t, maxInFlight > 1, "at most %d requests were in flight", maxInFlight)
+	assert.True(t, maxInFlight <= 4, "%d requests were in flight", maxInFlight)
+	assert.True(t, len(requestedPeers) > 1, "blocks were requested from %d peers", len(requestedPeers))
+}
+
+func TestStateConfig(t *testing.T) {
+	// Not parallel, as the configuration is set through viper
+	config := getStateConfig()
+	assert.Equal(t, &stateConfig{
+		antiEntropyInterval:             defAntiEntropyInterval,
+		antiEntropyStateResponseTimeout: defAntiEntropyStateResponseTimeout,
+		antiEntropyBatchSize:            defAntiEntropyBatchSize,
+		antiEntropyMaxRetries:           defAntiEntropyMaxRetries,
+		antiEntropyParallelRequests:     defAntiEntropyParallelRequests,
+		antiEntropyProgressInterval:     defAntiEntropyProgressInterval,
+	}, config)
+
+	viper.Set(antiEntropyBatchSizeConfigKey, 20)
+	viper.Set(antiEntropyMaxRetriesConfigKey, 5)
+	defer viper.Set(antiEntropyBatchSizeConfigKey, nil)
+	defer viper.Set(antiEntropyMaxRetriesConfigKey, nil)
+	config = getStateConfig()
+	assert.Equal(t, uint64(20), config.antiEntropyBatchSize)
+	assert.Equal(t, 5, config.antiEntropyMaxRetries)
+
+	// The requests in flight must fit in the payloads buffer
+	viper.Set(antiEntropyParallelRequestsConfigKey, 10)
+	defer viper.Set(antiEntropyParallelRequestsConfigKey, nil)
+	config = getStateConfig()
+	assert.Equal(t, 4, config.antiEntropyParallelRequests)
+
+	// A peer serves at most maxAntiEntropyBatchSize blocks per request
+	viper.Set(antiEntropyBatchSizeConfigKey, 200)
+	config = getStateConfig()
+	assert.Equal(t, uint64(maxAntiEntropyBatchSize), config.antiEntropyBatchSize)
+	assert.Equal(t, 1, config.antiEntropyParallelRequests)
+}
+
+func TestStateTransferWindow(t *testing.T) {
+	// Scenario: the peer knows of a peer whose ledger height is 1000 blocks higher
+	// than its own, which never responds to the request of the lowest batch but
+	// responds to all the others. The requests in flight must not run more than
+	// defMaxBlockDistance blocks ahead of the lowest one, and the state provider
+	// must stop while the lowest batch is still missing.
+	// Not parallel, as the configuration is set through viper
+	gutil.SetDuration(antiEntropyIntervalConfigKey, 100*time.Millisecond)
+	gutil.SetDuration(antiEntropyResponseTimeoutConfigKey, 100*time.Millisecond)
+	viper.Set(antiEntropyParallelRequestsConfigKey, 4)
+	defer viper.Set(antiEntropyIntervalConfigKey, nil)
+	defer viper.Set(antiEntropyResponseTimeoutConfigKey, nil)
+	defer viper.Set(antiEntropyParallelRequestsConfigKey, nil)
+
+	mc := &mockCommitter{Mock: &mock.Mock{}}
+	mc.On("CommitWithPvtData", mock.Anything)
+	mc.On("LedgerHeight", mock.Anything).Return(uint64(1), nil)
+	msgsFromPeer := make(chan proto.ReceivedMessage, 100)
+	g := &mocks.GossipMock{}
+	membership := []discovery.NetworkMember{
+		{
+			PKIid:      common.PKIidType("a"),
+			Endpoint:   "a",
+			Properties: &proto.Properties{LedgerHeight: 1001},
+		}}
+	g.On("PeersOfChannel", mock.Anything).Return(membership)
+	g.On("Accept", mock.Anything, false).Return(make(<-chan *proto.GossipMessage), nil)
+	g.On("Accept", mock.Anything, true).Return(nil, msgsFromPeer)
+
+	var lock sync.Mutex
+	lowestRequests, highestRequested := 0, uint64(0)
+	g.On("Send", mock.Anything, mock.Anything).Run(func(arguments mock.Arguments) {
+		msg := arguments.Get(0).(*proto.GossipMessage)
+		req := msg.GetStateRequest()
+		lock.Lock()
+		defer lock.Unlock()
+		if req.EndSeqNum > highestRequested {
+			highestRequested = req.EndSeqNum
+		}
+		if req.StartSeqNum == 1 {
+			lowestRequests++
+			return
+		}
+		res := &proto.GossipMessage{
+			Nonce:   msg.Nonce,
+			Channel: []byte(util.GetTestChainID()),
+			Content: &proto.GossipMessage_StateResponse{
+				StateResponse: &proto.RemoteStateResponse{},
+			},
+		}
+		for seq := req.StartSeqNum; seq <= req.EndSeqNum; seq++ {
+			b, _ := pb.Marshal(pcomm.NewBlock(seq, []byte{}))
+			res.GetStateResponse().Payloads = append(res.GetStateResponse().Payloads, &proto.Payload{
+				SeqNum: seq,
+				Data:   b,
+			})
+		}
+		sMsg, _ := res.NoopSign()
+		msgsFromPeer <- &comm.ReceivedMessageImpl{SignedGossipMessage: sMsg}
+	})
+	portPrefix := portStartRange + 235
+	p := newPeerNodeWithGossip(newGossipConfig(portPrefix, 0), mc, noopPeerIdentityAcceptor, g)
+
+	// Wait for the state transfer to be abandoned and started over
+	waitUntilTrueOrTimeout(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return lowestRequests > defAntiEntropyMaxRetries+1
+	}, 30*time.Second)
+
+	lock.Lock()
+	assert.True(t, highestRequested <= defMaxBlockDistance, "blocks up to %d were requested", highestRequested)
+	lock.Unlock()
+	assert.True(t, p.s.payloads.Size() <= defMaxBlockDistance, "payload buffer size is %d", p.s.payloads.Size())
+
+	stopped := make(chan struct{})
+	go func() {
+		p.shutdown()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(10 * time.Second):
+		t.Fatal("State provider didn't stop")
+	}
+}
+
+func TestAddPayloadAfterStop(t *testing.T) {
+	// Scenario: the payloads buffer is full, and adding a payload in blocking
+	// mode waits until the state provider is stopped.
+	t.Parallel()
+	mc := &mockCommitter{Mock: &mock.Mock{}}
+	mc.On("LedgerHeight", mock.Anything).Return(uint64(1), nil)
+	g := &mocks.GossipMock{}
+	g.On("PeersOfChannel", mock.Anything).Return([]discovery.NetworkMember{})
+	g.On("Accept", mock.Anything, false).Return(make(<-chan *proto.GossipMessage), nil)
+	g.On("Accept", mock.Anything, true).Return(nil, make(chan proto.ReceivedMessage))
+	portPrefix := portStartRange + 240
+	p := newPeerNodeWithGossip(newGossipConfig(portPrefix, 0), mc, noopPeerIdentityAcceptor, g)
+	defer p.g.Stop()
+
+	// Fill the payloads buffer with blocks that cannot be committed, as block 1 is missing
+	for seq := uint64(2); seq <= defMaxBlockDistance*2+2; seq++ {
+		p.s.payloads.Push(&proto.Payload{SeqNum: seq})
+	}
+
+	errCh := make(chan error)
+	go func() {
+		errCh <- p.s.addPayload(&proto.Payload{SeqNum: defMaxBlockDistance*2 + 3}, blocking)
+	}()
+	select {
+	case err := <-errCh:
+		t.Fatalf("Payload was added to a full buffer, error: %v", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	p.s.Stop()
+	select {
+	case err := <-errCh:
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "has been stopped")
+	case <-time.After(10 * time.Second):
+		t.Fatal("Adding the payload didn't stop")
+	}
+}
+
+func TestHandleStateRequestBatchSize(t *testing.T) {
+	// Scenario: a peer serves requests for more blocks than it requests itself,
+	// up to maxAntiEntropyBatchSize blocks
+	t.Parallel()
+	coord := new(coordinatorMock)
+	coord.On("LedgerHeight", mock.Anything).Return(uint64(1000), nil)
+	coord.On("GetPvtDataAndBlockByNum", mock.Anything).Return(pcomm.NewBlock(1, []byte{}), gutil.PvtDataCollections{}, nil)
+	s := &GossipStateProviderImpl{
+		chainID: util.GetTestChainID(),
+		config:  &stateConfig{antiEntropyBatchSize: defAntiEntropyBatchSize},
+		ledger:  coord,
+	}
+
+	request := func(start, end uint64) *receivedMessageMock {
+		msg, _ := (&proto.GossipMessage{
+			Nonce:   1,
+			Channel: []byte(util.GetTestChainID()),
+			Content: &proto.GossipMessage_StateRequest{StateRequest: &proto.RemoteStateRequest{
+				StartSeqNum: start,
+				EndSeqNum:   end,
+			}},
+		}).NoopSign()
+		requestMsg := new(receivedMessageMock)
+		requestMsg.On("GetGossipMessage").Return(msg)
+		requestMsg.On("GetConnectionInfo").Return(&proto.ConnectionInfo{Auth: &proto.AuthInfo{}})
+		requestMsg.On("Respond", mock.Anything)
+		return requestMsg
+	}
+
+	requestMsg := request(1, 1+maxAntiEntropyBatchSize)
+	s.handleStateRequest(requestMsg)
+	requestMsg.AssertCalled(t, "Respond", mock.Anything)
+	response := requestMsg.Calls[len(requestMsg.Calls)-1].Arguments.Get(0).(*proto.GossipMessage)
+	assert.Len(t, response.GetStateResponse().Payloads, maxAntiEntropyBatchSize+1)
+
+	requestMsg = request(1, 2+maxAntiEntropyBatchSize)
+	s.handleStateRequest(requestMsg)
+	requestMsg.AssertNotCalled(t, "Respond", mock.Anything)
+}
+
 func TestOverPopulation(t *testing.T) {
 	// Scenario: Add to the state provider blocks
 	// with a gap in between, and ensure that the payload buffer
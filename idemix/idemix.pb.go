@@ -16,6 +16,9 @@ It has these top-level messages:
 	CredRequest
 	Signature
 	NymSignature
+	NonRevokedHandle
+	CredentialRevocationInformation
+	SetMembershipProof
 */
 package idemix
 
@@ -334,6 +337,11 @@ func (m *CredRequest) GetProofS2() []byte {
 // Nym - a fresh pseudonym (a commitment to to the user secert)
 // ProofSRNym - a zero-knowledge proof of knowledge of the
 // user secret inside Nym
+// Epoch, RevocationAlg, NonRevSigPrime, NonRevSigBar, ProofSNonRev - a
+// zero-knowledge proof that the revocation handle of the credential
+// is not revoked in the given epoch
+// MembershipProofs - zero-knowledge proofs that hidden attributes
+// take one of the values in a set
 type Signature struct {
 	APrime       *ECP     `protobuf:"bytes,1,opt,name=APrime" json:"APrime,omitempty"`
 	ABar         *ECP     `protobuf:"bytes,2,opt,name=ABar" json:"ABar,omitempty"`
@@ -348,6 +356,18 @@ type Signature struct {
 	Nonce        []byte   `protobuf:"bytes,11,opt,name=Nonce,proto3" json:"Nonce,omitempty"`
 	Nym          *ECP     `protobuf:"bytes,12,opt,name=Nym" json:"Nym,omitempty"`
 	ProofSRNym   []byte   `protobuf:"bytes,13,opt,name=ProofSRNym,proto3" json:"ProofSRNym,omitempty"`
+	// Epoch is the epoch of the revocation information used for the non-revocation proof
+	Epoch int64 `protobuf:"varint,14,opt,name=Epoch" json:"Epoch,omitempty"`
+	// RevocationAlg is the revocation algorithm of the non-revocation proof
+	RevocationAlg int32 `protobuf:"varint,15,opt,name=RevocationAlg" json:"RevocationAlg,omitempty"`
+	// NonRevSigPrime and NonRevSigBar are the randomized epoch signature on the revocation handle
+	NonRevSigPrime *ECP `protobuf:"bytes,16,opt,name=NonRevSigPrime" json:"NonRevSigPrime,omitempty"`
+	NonRevSigBar   *ECP `protobuf:"bytes,17,opt,name=NonRevSigBar" json:"NonRevSigBar,omitempty"`
+	// ProofSNonRev is the s-value proving knowledge of the randomness of NonRevSigPrime
+	ProofSNonRev []byte `protobuf:"bytes,18,opt,name=ProofSNonRev,proto3" json:"ProofSNonRev,omitempty"`
+	// MembershipProofs contains a set membership proof for each attribute that is
+	// hidden but proven to take one of the values in a set
+	MembershipProofs []*SetMembershipProof `protobuf:"bytes,19,rep,name=MembershipProofs" json:"MembershipProofs,omitempty"`
 }
 
 func (m *Signature) Reset()                    { *m = Signature{} }
@@ -446,6 +466,48 @@ func (m *Signature) GetProofSRNym() []byte {
 	return nil
 }
 
+func (m *Signature) GetEpoch() int64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *Signature) GetRevocationAlg() int32 {
+	if m != nil {
+		return m.RevocationAlg
+	}
+	return 0
+}
+
+func (m *Signature) GetNonRevSigPrime() *ECP {
+	if m != nil {
+		return m.NonRevSigPrime
+	}
+	return nil
+}
+
+func (m *Signature) GetNonRevSigBar() *ECP {
+	if m != nil {
+		return m.NonRevSigBar
+	}
+	return nil
+}
+
+func (m *Signature) GetProofSNonRev() []byte {
+	if m != nil {
+		return m.ProofSNonRev
+	}
+	return nil
+}
+
+func (m *Signature) GetMembershipProofs() []*SetMembershipProof {
+	if m != nil {
+		return m.MembershipProofs
+	}
+	return nil
+}
+
 // NymSignature specifies a signature object that signs a message
 // with respect to a pseudonym. It differs from the standard idemix.signature in the fact that
 // the  standard signature object also proves that the pseudonym is based on a secret certified by
@@ -495,6 +557,161 @@ func (m *NymSignature) GetNonce() []byte {
 	return nil
 }
 
+// NonRevokedHandle contains a weak Boneh-Boyen signature placed
+// by the revocation authority on a revocation handle that is
+// not revoked in a certain epoch
+type NonRevokedHandle struct {
+	// Handle is the revocation handle
+	Handle []byte `protobuf:"bytes,1,opt,name=Handle,proto3" json:"Handle,omitempty"`
+	// EpochSig is the signature on Handle under the epoch key
+	EpochSig *ECP `protobuf:"bytes,2,opt,name=EpochSig" json:"EpochSig,omitempty"`
+}
+
+func (m *NonRevokedHandle) Reset()                    { *m = NonRevokedHandle{} }
+func (m *NonRevokedHandle) String() string            { return proto.CompactTextString(m) }
+func (*NonRevokedHandle) ProtoMessage()               {}
+func (*NonRevokedHandle) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
+func (m *NonRevokedHandle) GetHandle() []byte {
+	if m != nil {
+		return m.Handle
+	}
+	return nil
+}
+
+func (m *NonRevokedHandle) GetEpochSig() *ECP {
+	if m != nil {
+		return m.EpochSig
+	}
+	return nil
+}
+
+// CredentialRevocationInformation contains the information necessary
+// for users to prove that their credential is not revoked in a certain
+// epoch, and for verifiers to check such proofs
+type CredentialRevocationInformation struct {
+	// Epoch is the epoch (time window) in which this CRI is valid
+	Epoch int64 `protobuf:"varint,1,opt,name=Epoch" json:"Epoch,omitempty"`
+	// EpochPk is the public key used by the revocation authority in this epoch
+	EpochPk *ECP2 `protobuf:"bytes,2,opt,name=EpochPk" json:"EpochPk,omitempty"`
+	// EpochPkSig is a signature on EpochPk valid under the revocation authority's long term key
+	EpochPkSig []byte `protobuf:"bytes,3,opt,name=EpochPkSig,proto3" json:"EpochPkSig,omitempty"`
+	// RevocationAlg denotes which revocation algorithm is used
+	RevocationAlg int32 `protobuf:"varint,4,opt,name=RevocationAlg" json:"RevocationAlg,omitempty"`
+	// NonRevokedHandles contains a signature under EpochPk on
+	// every revocation handle that is not revoked in this epoch
+	NonRevokedHandles []*NonRevokedHandle `protobuf:"bytes,5,rep,name=NonRevokedHandles" json:"NonRevokedHandles,omitempty"`
+}
+
+func (m *CredentialRevocationInformation) Reset()         { *m = CredentialRevocationInformation{} }
+func (m *CredentialRevocationInformation) String() string { return proto.CompactTextString(m) }
+func (*CredentialRevocationInformation) ProtoMessage()    {}
+func (*CredentialRevocationInformation) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{9}
+}
+
+func (m *CredentialRevocationInformation) GetEpoch() int64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *CredentialRevocationInformation) GetEpochPk() *ECP2 {
+	if m != nil {
+		return m.EpochPk
+	}
+	return nil
+}
+
+func (m *CredentialRevocationInformation) GetEpochPkSig() []byte {
+	if m != nil {
+		return m.EpochPkSig
+	}
+	return nil
+}
+
+func (m *CredentialRevocationInformation) GetRevocationAlg() int32 {
+	if m != nil {
+		return m.RevocationAlg
+	}
+	return 0
+}
+
+func (m *CredentialRevocationInformation) GetNonRevokedHandles() []*NonRevokedHandle {
+	if m != nil {
+		return m.NonRevokedHandles
+	}
+	return nil
+}
+
+// SetMembershipProof is a zero-knowledge proof that a hidden attribute
+// of a credential takes one of the values in a set, without revealing which.
+// The proof consists of a commitment to the attribute value, a proof that the
+// commitment opens to the attribute signed in the credential, and an
+// OR-proof that the commitment opens to one of the values
+type SetMembershipProof struct {
+	// AttributeIndex is the index of the attribute the proof is about
+	AttributeIndex int32 `protobuf:"varint,1,opt,name=AttributeIndex" json:"AttributeIndex,omitempty"`
+	// Values is the set of values, as []byte representations of amcl.BIGs
+	Values [][]byte `protobuf:"bytes,2,rep,name=Values,proto3" json:"Values,omitempty"`
+	// Commitment is a Pedersen commitment to the attribute value
+	Commitment *ECP `protobuf:"bytes,3,opt,name=Commitment" json:"Commitment,omitempty"`
+	// ProofSRand is the s-value proving knowledge of the randomness of Commitment
+	ProofSRand []byte `protobuf:"bytes,4,opt,name=ProofSRand,proto3" json:"ProofSRand,omitempty"`
+	// ProofC contains the challenge of the OR-proof for each value
+	ProofC [][]byte `protobuf:"bytes,5,rep,name=ProofC,proto3" json:"ProofC,omitempty"`
+	// ProofS contains the s-value of the OR-proof for each value
+	ProofS [][]byte `protobuf:"bytes,6,rep,name=ProofS,proto3" json:"ProofS,omitempty"`
+}
+
+func (m *SetMembershipProof) Reset()                    { *m = SetMembershipProof{} }
+func (m *SetMembershipProof) String() string            { return proto.CompactTextString(m) }
+func (*SetMembershipProof) ProtoMessage()               {}
+func (*SetMembershipProof) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+
+func (m *SetMembershipProof) GetAttributeIndex() int32 {
+	if m != nil {
+		return m.AttributeIndex
+	}
+	return 0
+}
+
+func (m *SetMembershipProof) GetValues() [][]byte {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+func (m *SetMembershipProof) GetCommitment() *ECP {
+	if m != nil {
+		return m.Commitment
+	}
+	return nil
+}
+
+func (m *SetMembershipProof) GetProofSRand() []byte {
+	if m != nil {
+		return m.ProofSRand
+	}
+	return nil
+}
+
+func (m *SetMembershipProof) GetProofC() [][]byte {
+	if m != nil {
+		return m.ProofC
+	}
+	return nil
+}
+
+func (m *SetMembershipProof) GetProofS() [][]byte {
+	if m != nil {
+		return m.ProofS
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*ECP)(nil), "ECP")
 	proto.RegisterType((*ECP2)(nil), "ECP2")
@@ -504,47 +721,67 @@ func init() {
 	proto.RegisterType((*CredRequest)(nil), "CredRequest")
 	proto.RegisterType((*Signature)(nil), "Signature")
 	proto.RegisterType((*NymSignature)(nil), "NymSignature")
+	proto.RegisterType((*NonRevokedHandle)(nil), "NonRevokedHandle")
+	proto.RegisterType((*CredentialRevocationInformation)(nil), "CredentialRevocationInformation")
+	proto.RegisterType((*SetMembershipProof)(nil), "SetMembershipProof")
 }
 
 func init() { proto.RegisterFile("idemix/idemix.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 591 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x54, 0x4d, 0x6b, 0xdb, 0x4c,
-	0x18, 0x64, 0xf5, 0xe1, 0xc4, 0x8f, 0xf5, 0xe6, 0x0d, 0x9b, 0x12, 0x96, 0x50, 0x8a, 0x2b, 0x4a,
-	0xc8, 0xc9, 0x21, 0xca, 0x2f, 0x90, 0x8c, 0x5a, 0x87, 0x82, 0x11, 0xd2, 0x21, 0x76, 0x6f, 0xb2,
-	0xbd, 0xb6, 0x85, 0x2d, 0x2b, 0x5d, 0x49, 0x10, 0xff, 0x8e, 0x5e, 0xfb, 0x4f, 0x7b, 0x29, 0xfb,
-	0xa1, 0x4f, 0xe8, 0xc9, 0x9a, 0x19, 0x3d, 0xfb, 0x8c, 0x67, 0x16, 0xc1, 0x4d, 0xb2, 0xa1, 0x69,
-	0xf2, 0xfe, 0x28, 0x7f, 0x26, 0x6f, 0x2c, 0x2b, 0x32, 0xfb, 0x33, 0xe8, 0xfe, 0x34, 0xc0, 0x16,
-	0xa0, 0x05, 0x41, 0x63, 0xf4, 0x60, 0x85, 0x68, 0xc1, 0xd1, 0x92, 0x68, 0x12, 0x2d, 0xed, 0xaf,
-	0x60, 0xf8, 0xd3, 0xc0, 0xc1, 0x57, 0xa0, 0x2d, 0x5c, 0xf5, 0x92, 0xb6, 0x70, 0x05, 0xf6, 0xd4,
-	0x6b, 0xda, 0xc2, 0xe3, 0x78, 0xe9, 0x12, 0x5d, 0xe2, 0xa5, 0xd0, 0x97, 0x1e, 0x31, 0x14, 0xf6,
-	0xec, 0xdf, 0x1a, 0xfc, 0xff, 0x92, 0xe7, 0x25, 0x65, 0x41, 0xb9, 0x3a, 0x26, 0xeb, 0xef, 0xf4,
-	0x8c, 0xef, 0xe1, 0xca, 0x2d, 0x0a, 0x96, 0xac, 0xca, 0x82, 0xce, 0xe3, 0x94, 0xe6, 0x04, 0x8d,
-	0xf5, 0x87, 0x61, 0xd8, 0x63, 0xf1, 0x2d, 0xe8, 0xb3, 0xe8, 0x20, 0x96, 0x8d, 0x1c, 0x63, 0xe2,
-	0x4f, 0x83, 0x90, 0x13, 0xf8, 0x0e, 0xcc, 0x59, 0x18, 0x9f, 0x36, 0x62, 0x6d, 0xa5, 0x48, 0x0a,
-	0x7f, 0x84, 0xc1, 0x8c, 0x1f, 0x93, 0x13, 0x63, 0xac, 0xd7, 0xa2, 0xe2, 0xf0, 0x0d, 0xa0, 0x57,
-	0x62, 0x8a, 0x29, 0x93, 0x0b, 0x4e, 0x88, 0x5e, 0xf9, 0x71, 0x5e, 0xcc, 0xbe, 0x3d, 0x91, 0x41,
-	0xfb, 0x38, 0x41, 0x55, 0x9a, 0x43, 0x2e, 0xfa, 0x9a, 0x83, 0x6f, 0x61, 0x10, 0xb0, 0x2c, 0xdb,
-	0x4e, 0xc9, 0xa5, 0xf8, 0xbb, 0x0a, 0xd5, 0x7c, 0x44, 0x86, 0x2d, 0x3e, 0xc2, 0x18, 0x8c, 0x59,
-	0x9c, 0xef, 0x09, 0x08, 0x56, 0x3c, 0xdb, 0x2e, 0x0c, 0x65, 0x3a, 0x3c, 0x97, 0x6b, 0xd0, 0x5f,
-	0xa2, 0x83, 0x0a, 0x9b, 0x3f, 0x62, 0x1b, 0xf4, 0x97, 0xa0, 0x4a, 0xe0, 0x7a, 0xd2, 0x0b, 0x32,
-	0xe4, 0xa2, 0xbd, 0x05, 0x98, 0x32, 0xba, 0xa1, 0xa7, 0x22, 0x89, 0x8f, 0x18, 0x03, 0x92, 0x75,
-	0x55, 0x66, 0x91, 0xcb, 0x39, 0xaf, 0x93, 0x22, 0xf2, 0x78, 0xdb, 0xbe, 0xaa, 0x0d, 0xf9, 0x1c,
-	0x45, 0xaa, 0x34, 0x14, 0xe1, 0x0f, 0x60, 0xca, 0x08, 0xcd, 0xb1, 0xfe, 0x60, 0x85, 0x12, 0xd8,
-	0xbf, 0x10, 0x8c, 0xf8, 0xa2, 0x90, 0xfe, 0x2c, 0x69, 0x5e, 0xf0, 0x76, 0xe6, 0xe7, 0xb4, 0xb3,
-	0x8b, 0x13, 0x78, 0x0c, 0x23, 0xe9, 0x73, 0x9e, 0x9d, 0xd6, 0x54, 0x5d, 0x95, 0x36, 0xd5, 0x0a,
-	0x4e, 0xef, 0x04, 0x47, 0xe0, 0x42, 0x46, 0xf5, 0xa4, 0xbc, 0x54, 0xb0, 0x51, 0x1c, 0xd1, 0x5e,
-	0xad, 0x38, 0xf6, 0x1f, 0x0d, 0x86, 0x51, 0xb2, 0x3b, 0xc5, 0x45, 0xc9, 0x28, 0x6f, 0xdf, 0x0d,
-	0x58, 0x92, 0xd2, 0x8e, 0x2d, 0xc5, 0x61, 0x02, 0x86, 0xeb, 0xc5, 0xac, 0x13, 0x85, 0x60, 0xf8,
-	0x9c, 0x27, 0xe7, 0xda, 0x57, 0x4a, 0x71, 0x2d, 0xbf, 0x46, 0xc7, 0xef, 0x1d, 0x5c, 0x4a, 0x1b,
-	0xd1, 0x41, 0xd9, 0xaa, 0x71, 0xe3, 0xd8, 0x17, 0xd7, 0xaa, 0x76, 0xec, 0x37, 0x53, 0xa1, 0xbc,
-	0x55, 0xf5, 0x54, 0xe8, 0xb4, 0xb4, 0x67, 0x75, 0xa9, 0x6a, 0x8c, 0x6d, 0xb0, 0xd4, 0xe9, 0xd2,
-	0xa9, 0xbc, 0x5c, 0x1d, 0x8e, 0x67, 0x2f, 0xb1, 0xec, 0x0f, 0x44, 0x7f, 0x6d, 0x8a, 0x77, 0x2b,
-	0x7b, 0x19, 0x89, 0x71, 0xb3, 0x6a, 0x44, 0x74, 0x69, 0xf5, 0xbb, 0xfc, 0x04, 0xa0, 0xf6, 0x73,
-	0xf9, 0x3f, 0x31, 0xd2, 0x62, 0xec, 0x77, 0xb0, 0xe6, 0xe7, 0xb4, 0xc9, 0xbf, 0x49, 0x0a, 0xfd,
-	0x33, 0x29, 0xad, 0x97, 0x54, 0x77, 0x87, 0xde, 0xdf, 0xd1, 0x38, 0x36, 0x5a, 0x8e, 0xbd, 0xfb,
-	0x1f, 0x5f, 0x76, 0x49, 0xb1, 0x2f, 0x57, 0x93, 0x75, 0x96, 0x3e, 0xee, 0xcf, 0x6f, 0x94, 0x1d,
-	0xe9, 0x66, 0x47, 0xd9, 0xe3, 0x36, 0x5e, 0xb1, 0x64, 0xad, 0x3e, 0x78, 0xab, 0x81, 0xf8, 0xe2,
-	0x3d, 0xff, 0x0d, 0x00, 0x00, 0xff, 0xff, 0x06, 0x88, 0xd9, 0x9e, 0x08, 0x05, 0x00, 0x00,
+	// 853 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x55, 0xdd, 0x8e, 0xe2, 0x36,
+	0x18, 0x95, 0x49, 0xc2, 0xcc, 0x7c, 0xb0, 0xb3, 0x8c, 0xa7, 0x5a, 0x59, 0xab, 0xaa, 0x9b, 0x46,
+	0xa3, 0x15, 0x17, 0x15, 0xa3, 0xcd, 0x3e, 0xc0, 0x2a, 0x41, 0x69, 0x41, 0x6d, 0x11, 0x72, 0xa4,
+	0x2e, 0xf4, 0x2e, 0x80, 0x07, 0xa2, 0x21, 0x09, 0x4d, 0xc2, 0x6a, 0x78, 0x8e, 0xde, 0xf6, 0x89,
+	0xfa, 0x12, 0x7d, 0x89, 0x3e, 0x40, 0xe5, 0x1f, 0x12, 0x27, 0x6c, 0xaf, 0xf0, 0x39, 0x27, 0xb6,
+	0x3f, 0x9f, 0xf3, 0x61, 0xc3, 0x7d, 0xbc, 0x61, 0x49, 0xfc, 0xf2, 0x28, 0x7f, 0x46, 0x87, 0x3c,
+	0x2b, 0x33, 0xe7, 0x7b, 0x30, 0x82, 0xf1, 0x1c, 0xf7, 0x01, 0x2d, 0x08, 0xb2, 0xd1, 0xb0, 0x4f,
+	0xd1, 0x82, 0xa3, 0x25, 0xe9, 0x48, 0xb4, 0x74, 0x7e, 0x04, 0x33, 0x18, 0xcf, 0x5d, 0x7c, 0x0b,
+	0x9d, 0x85, 0xa7, 0x3e, 0xea, 0x2c, 0x3c, 0x81, 0x7d, 0xf5, 0x59, 0x67, 0xe1, 0x73, 0xbc, 0xf4,
+	0x88, 0x21, 0xf1, 0x52, 0xe8, 0x4b, 0x9f, 0x98, 0x0a, 0xfb, 0xce, 0x5f, 0x1d, 0x78, 0x3d, 0x2d,
+	0x8a, 0x23, 0xcb, 0xe7, 0xc7, 0xd5, 0x3e, 0x5e, 0xff, 0xcc, 0x4e, 0xf8, 0x3d, 0xdc, 0x7a, 0x65,
+	0x99, 0xc7, 0xab, 0x63, 0xc9, 0x66, 0x51, 0xc2, 0x0a, 0x82, 0x6c, 0x63, 0x78, 0x43, 0x5b, 0x2c,
+	0x7e, 0x03, 0xc6, 0x24, 0x7c, 0x16, 0x9b, 0xf5, 0x5c, 0x73, 0x14, 0x8c, 0xe7, 0x94, 0x13, 0xf8,
+	0x2d, 0x58, 0x13, 0x1a, 0xa5, 0x1b, 0x62, 0x68, 0x8a, 0xa4, 0xf0, 0xb7, 0xd0, 0x9d, 0xf0, 0x65,
+	0x0a, 0x62, 0xda, 0x46, 0x25, 0x2a, 0x0e, 0xdf, 0x03, 0xfa, 0x4c, 0x2c, 0x31, 0xcb, 0xe2, 0x82,
+	0x4b, 0xd1, 0x67, 0xbe, 0x9c, 0x1f, 0xe5, 0x3f, 0x7d, 0x20, 0x5d, 0x7d, 0x39, 0x41, 0x9d, 0x35,
+	0x97, 0x5c, 0xb5, 0x35, 0x17, 0xbf, 0x81, 0xee, 0x3c, 0xcf, 0xb2, 0xa7, 0x31, 0xb9, 0x16, 0xc7,
+	0x55, 0xa8, 0xe2, 0x43, 0x72, 0xa3, 0xf1, 0x21, 0xc6, 0x60, 0x4e, 0xa2, 0x62, 0x47, 0x40, 0xb0,
+	0x62, 0xec, 0x78, 0x70, 0x23, 0xdd, 0xe1, 0xbe, 0x0c, 0xc0, 0x98, 0x86, 0xcf, 0xca, 0x6c, 0x3e,
+	0xc4, 0x0e, 0x18, 0xd3, 0xf9, 0xd9, 0x81, 0xc1, 0xa8, 0x65, 0x24, 0xe5, 0xa2, 0xf3, 0x04, 0x30,
+	0xce, 0xd9, 0x86, 0xa5, 0x65, 0x1c, 0xed, 0x31, 0x06, 0x24, 0xe3, 0x3a, 0x17, 0x8b, 0x3c, 0xce,
+	0xf9, 0x0d, 0x17, 0x91, 0xcf, 0xd3, 0x0e, 0x54, 0x6c, 0x28, 0xe0, 0x28, 0x54, 0xa1, 0xa1, 0x10,
+	0x7f, 0x03, 0x96, 0xb4, 0xd0, 0xb2, 0x8d, 0x61, 0x9f, 0x4a, 0xe0, 0xfc, 0x89, 0xa0, 0xc7, 0x37,
+	0xa2, 0xec, 0x8f, 0x23, 0x2b, 0x4a, 0x9e, 0xce, 0xec, 0x94, 0x34, 0xf6, 0xe2, 0x04, 0xb6, 0xa1,
+	0x27, 0xeb, 0x9c, 0x65, 0xe9, 0x9a, 0xa9, 0x56, 0xd1, 0x29, 0xcd, 0x38, 0xa3, 0x61, 0x1c, 0x81,
+	0x2b, 0x31, 0x0a, 0x3f, 0xa8, 0x5a, 0xce, 0xb0, 0x56, 0x5c, 0x62, 0xe9, 0x8a, 0xeb, 0xfc, 0x6b,
+	0xc2, 0x4d, 0x18, 0x6f, 0xd3, 0xa8, 0x3c, 0xe6, 0x8c, 0xa7, 0xef, 0xcd, 0xf3, 0x38, 0x61, 0x8d,
+	0xb2, 0x14, 0x87, 0x09, 0x98, 0x9e, 0x1f, 0xe5, 0x0d, 0x2b, 0x04, 0xc3, 0xe7, 0xf9, 0x72, 0x9e,
+	0xde, 0x52, 0x8a, 0xd3, 0xea, 0x35, 0x1b, 0xf5, 0xbe, 0x85, 0x6b, 0x59, 0x46, 0xf8, 0xac, 0xca,
+	0xaa, 0x70, 0x5d, 0x71, 0x40, 0xba, 0x7a, 0xc5, 0x41, 0x3d, 0x8b, 0xca, 0xae, 0xaa, 0x66, 0x51,
+	0x57, 0xd3, 0x3e, 0xaa, 0xa6, 0xaa, 0x30, 0x76, 0xa0, 0xaf, 0x56, 0x97, 0x95, 0xca, 0xe6, 0x6a,
+	0x70, 0xdc, 0x7b, 0x89, 0x65, 0x7e, 0x20, 0xf2, 0xd3, 0x29, 0x9e, 0xad, 0xcc, 0xa5, 0x27, 0xa6,
+	0x5b, 0xe7, 0x44, 0x44, 0x96, 0xfd, 0x76, 0x96, 0xdf, 0x01, 0xa8, 0xfd, 0xb9, 0xfc, 0x4a, 0x4c,
+	0xd1, 0x18, 0xbe, 0x5a, 0x70, 0xc8, 0xd6, 0x3b, 0x72, 0x6b, 0xa3, 0xa1, 0x41, 0x25, 0xc0, 0x0f,
+	0xf0, 0x8a, 0xb2, 0x2f, 0xd9, 0x3a, 0x2a, 0xe3, 0x2c, 0xf5, 0xf6, 0x5b, 0xf2, 0xda, 0x46, 0x43,
+	0x8b, 0x36, 0x49, 0xfc, 0x03, 0xdc, 0xce, 0xb2, 0x94, 0xb2, 0x2f, 0x61, 0xbc, 0x95, 0x27, 0x1a,
+	0x68, 0xdb, 0xb7, 0x34, 0x3c, 0x84, 0x7e, 0xc5, 0xf0, 0x0c, 0xef, 0xb4, 0x6f, 0x1b, 0x4a, 0xed,
+	0x93, 0x64, 0x09, 0xd6, 0x7d, 0x92, 0x1c, 0xfe, 0x04, 0x83, 0x5f, 0x59, 0xb2, 0x62, 0x79, 0xb1,
+	0x8b, 0x0f, 0x42, 0x29, 0xc8, 0xbd, 0xb8, 0x2f, 0xee, 0x47, 0x21, 0x2b, 0x5b, 0x1a, 0xbd, 0xf8,
+	0xd8, 0x79, 0x81, 0xfe, 0xec, 0x94, 0xd4, 0x8d, 0x57, 0xb7, 0x08, 0xfa, 0xdf, 0x16, 0xe9, 0xb4,
+	0x5a, 0xa4, 0x69, 0xae, 0xf1, 0x35, 0x73, 0x65, 0x54, 0xa6, 0x16, 0x95, 0xf3, 0x0b, 0x0c, 0xe4,
+	0x21, 0xb2, 0x67, 0xb6, 0x99, 0x44, 0xe9, 0x66, 0x2f, 0x76, 0x97, 0xa3, 0xf3, 0xee, 0x8a, 0xb7,
+	0xe1, 0x5a, 0x24, 0x12, 0xc6, 0xdb, 0x46, 0xd3, 0x57, 0xac, 0xf3, 0x0f, 0x82, 0x77, 0xf5, 0xed,
+	0x51, 0x07, 0x34, 0x4d, 0x9f, 0xb2, 0x3c, 0x11, 0xc3, 0x3a, 0x64, 0xa4, 0x87, 0xfc, 0x0e, 0xae,
+	0xc4, 0xa0, 0xba, 0x9e, 0xd4, 0x85, 0x7a, 0x66, 0xf9, 0xf1, 0xd4, 0x90, 0x6f, 0xaf, 0x8e, 0x57,
+	0x33, 0x97, 0x5d, 0x62, 0x7e, 0xad, 0x4b, 0x3e, 0xc1, 0x5d, 0xfb, 0xb8, 0xf2, 0x5e, 0xea, 0xb9,
+	0x77, 0xa3, 0xb6, 0x42, 0x2f, 0xbf, 0x75, 0xfe, 0x46, 0x80, 0x2f, 0x23, 0x6d, 0xbc, 0x41, 0xd3,
+	0x74, 0xc3, 0x5e, 0xc4, 0xe9, 0x2c, 0xda, 0x62, 0xb9, 0xb5, 0xbf, 0x45, 0xfb, 0x23, 0x2b, 0x48,
+	0x47, 0xfc, 0x99, 0x14, 0xc2, 0x0f, 0x00, 0xe3, 0x2c, 0x49, 0xe2, 0x32, 0x61, 0x69, 0xd9, 0xb8,
+	0x35, 0x34, 0x5e, 0x8b, 0x98, 0x3f, 0x57, 0x66, 0x23, 0x62, 0xfe, 0x5a, 0xd5, 0x6d, 0x23, 0xaf,
+	0xda, 0xcb, 0x27, 0xa4, 0xab, 0xf1, 0xa1, 0xff, 0xfe, 0xf7, 0x87, 0x6d, 0x5c, 0xee, 0x8e, 0xab,
+	0xd1, 0x3a, 0x4b, 0x1e, 0x77, 0xa7, 0x03, 0xcb, 0xf7, 0x6c, 0xb3, 0x65, 0xf9, 0xe3, 0x53, 0xb4,
+	0xca, 0xe3, 0xb5, 0x7a, 0xe6, 0x57, 0x5d, 0xf1, 0xce, 0x7f, 0xfc, 0x6f, 0x00, 0x32, 0xb5, 0xa1,
+	0xd7, 0xfe, 0x07, 0x00, 0x00,
 }
@@ -113,10 +113,10 @@ func TestIdemix(t *testing.T) {
 
 	disclosure := []byte{0, 0, 0, 0, 0}
 	msg := []byte{1, 2, 3, 4, 5}
-	sig, err := NewSignature(cred, sk, Nym, RandNym, key.IPk, disclosure, msg, rng)
+	sig, err := NewSignature(cred, sk, Nym, RandNym, key.IPk, disclosure, msg, 0, nil, nil, rng)
 	assert.NoError(t, err)
 
-	err = sig.Ver(disclosure, key.IPk, msg, nil)
+	err = sig.Ver(disclosure, key.IPk, msg, nil, 0, nil, nil)
 	if err != nil {
 		t.Fatalf("Signature should be valid but verification returned error: %s", err)
 		return
@@ -124,10 +124,10 @@ func TestIdemix(t *testing.T) {
 
 	// Test signing selective disclosure
 	disclosure = []byte{0, 1, 1, 1, 1}
-	sig, err = NewSignature(cred, sk, Nym, RandNym, key.IPk, disclosure, msg, rng)
+	sig, err = NewSignature(cred, sk, Nym, RandNym, key.IPk, disclosure, msg, 0, nil, nil, rng)
 	assert.NoError(t, err)
 
-	err = sig.Ver(disclosure, key.IPk, msg, attrs)
+	err = sig.Ver(disclosure, key.IPk, msg, attrs, 0, nil, nil)
 	if err != nil {
 		t.Fatalf("Signature should be valid but verification returned error: %s", err)
 		return
@@ -143,3 +143,144 @@ func TestIdemix(t *testing.T) {
 		return
 	}
 }
+
+func TestRevocation(t *testing.T) {
+	rng, err := GetRand()
+	assert.NoError(t, err)
+
+	AttributeNames := []string{"Attr1", "Attr2", "RevocationHandle"}
+	key, err := NewIssuerKey(AttributeNames, rng)
+	assert.NoError(t, err)
+
+	// Issue a credential with revocation handle 42 (attribute 2)
+	rhIndex := 2
+	attrs := []*FP256BN.BIG{FP256BN.NewBIGint(1), FP256BN.NewBIGint(2), FP256BN.NewBIGint(42)}
+	sk := RandModOrder(rng)
+	cred, err := NewCredential(key, NewCredRequest(sk, RandModOrder(rng), key.IPk, rng), attrs, rng)
+	assert.NoError(t, err)
+	Nym, RandNym := MakeNym(sk, key.IPk, rng)
+
+	revocationKey, err := GenerateLongTermRevocationKey()
+	assert.NoError(t, err)
+
+	// Handle 42 is not revoked in epoch 1
+	cri, err := CreateCRI(revocationKey, []*FP256BN.BIG{FP256BN.NewBIGint(7), FP256BN.NewBIGint(42)}, 1, ALG_WEAK_BB, rng)
+	assert.NoError(t, err)
+	assert.NoError(t, VerifyCRI(&revocationKey.PublicKey, cri))
+
+	disclosure := []byte{1, 0, 0}
+	msg := []byte("message")
+	sig, err := NewSignature(cred, sk, Nym, RandNym, key.IPk, disclosure, msg, rhIndex, cri, nil, rng)
+	assert.NoError(t, err)
+	assert.NoError(t, sig.Ver(disclosure, key.IPk, msg, attrs, rhIndex, cri, nil))
+
+	// The signature carries a non-revocation proof, so verifying without revocation must fail
+	assert.Error(t, sig.Ver(disclosure, key.IPk, msg, attrs, rhIndex, nil, nil))
+
+	// Tampering with the randomized epoch signature breaks the proof
+	nonRevSigBar := sig.NonRevSigBar
+	sig.NonRevSigBar = EcpToProto(GenG1.Mul(RandModOrder(rng)))
+	assert.Error(t, sig.Ver(disclosure, key.IPk, msg, attrs, rhIndex, cri, nil))
+	sig.NonRevSigBar = nonRevSigBar
+	proofSNonRev := sig.ProofSNonRev
+	sig.ProofSNonRev = BigToBytes(RandModOrder(rng))
+	assert.Error(t, sig.Ver(disclosure, key.IPk, msg, attrs, rhIndex, cri, nil))
+	sig.ProofSNonRev = proofSNonRev
+	sig.NonRevSigPrime = nil
+	assert.EqualError(t, sig.Ver(disclosure, key.IPk, msg, attrs, rhIndex, cri, nil), "signature invalid: non-revocation proof is missing")
+
+	// The revocation handle may not be disclosed
+	_, err = NewSignature(cred, sk, Nym, RandNym, key.IPk, []byte{1, 0, 1}, msg, rhIndex, cri, nil, rng)
+	assert.Error(t, err)
+	_, err = NewSignature(cred, sk, Nym, RandNym, key.IPk, disclosure, msg, 3, cri, nil, rng)
+	assert.Error(t, err)
+
+	// Handle 42 is revoked in epoch 2
+	cri2, err := CreateCRI(revocationKey, []*FP256BN.BIG{FP256BN.NewBIGint(7)}, 2, ALG_WEAK_BB, rng)
+	assert.NoError(t, err)
+	_, err = NewSignature(cred, sk, Nym, RandNym, key.IPk, disclosure, msg, rhIndex, cri2, nil, rng)
+	assert.EqualError(t, err, "cannot create idemix signature: the revocation handle is revoked in epoch 2")
+
+	// Signatures for epoch 1 are not valid in epoch 2
+	sig, err = NewSignature(cred, sk, Nym, RandNym, key.IPk, disclosure, msg, rhIndex, cri, nil, rng)
+	assert.NoError(t, err)
+	assert.EqualError(t, sig.Ver(disclosure, key.IPk, msg, attrs, rhIndex, cri2, nil), "signature invalid: signature is for epoch 1, but the current epoch is 2")
+
+	// A CRI with a forged epoch key does not verify
+	otherKey, err := GenerateLongTermRevocationKey()
+	assert.NoError(t, err)
+	assert.Error(t, VerifyCRI(&otherKey.PublicKey, cri))
+	cri.Epoch = 3
+	assert.Error(t, VerifyCRI(&revocationKey.PublicKey, cri))
+
+	// Without revocation, no handle signatures are created
+	cri, err = CreateCRI(revocationKey, []*FP256BN.BIG{FP256BN.NewBIGint(42)}, 1, ALG_NO_REVOCATION, rng)
+	assert.NoError(t, err)
+	assert.Empty(t, cri.NonRevokedHandles)
+	sig, err = NewSignature(cred, sk, Nym, RandNym, key.IPk, disclosure, msg, rhIndex, cri, nil, rng)
+	assert.NoError(t, err)
+	assert.Nil(t, sig.NonRevSigPrime)
+	assert.NoError(t, sig.Ver(disclosure, key.IPk, msg, attrs, rhIndex, cri, nil))
+
+	_, err = CreateCRI(revocationKey, nil, 1, RevocationAlgorithm(42), rng)
+	assert.Error(t, err)
+}
+
+func TestSetMembership(t *testing.T) {
+	rng, err := GetRand()
+	assert.NoError(t, err)
+
+	AttributeNames := []string{"Attr1", "Attr2", "Attr3", "RevocationHandle"}
+	key, err := NewIssuerKey(AttributeNames, rng)
+	assert.NoError(t, err)
+
+	rhIndex := 3
+	attrs := []*FP256BN.BIG{FP256BN.NewBIGint(1), FP256BN.NewBIGint(2), FP256BN.NewBIGint(3), FP256BN.NewBIGint(42)}
+	sk := RandModOrder(rng)
+	cred, err := NewCredential(key, NewCredRequest(sk, RandModOrder(rng), key.IPk, rng), attrs, rng)
+	assert.NoError(t, err)
+	Nym, RandNym := MakeNym(sk, key.IPk, rng)
+
+	revocationKey, err := GenerateLongTermRevocationKey()
+	assert.NoError(t, err)
+	cri, err := CreateCRI(revocationKey, []*FP256BN.BIG{FP256BN.NewBIGint(42)}, 1, ALG_WEAK_BB, rng)
+	assert.NoError(t, err)
+
+	// Prove that attribute 1 is in {5, 2, 9} and attribute 2 is in {3}, while disclosing attribute 0
+	disclosure := []byte{1, 0, 0, 0}
+	msg := []byte("message")
+	memberships := map[int][]*FP256BN.BIG{
+		2: {FP256BN.NewBIGint(3)},
+		1: {FP256BN.NewBIGint(5), FP256BN.NewBIGint(2), FP256BN.NewBIGint(9)},
+	}
+	sig, err := NewSignature(cred, sk, Nym, RandNym, key.IPk, disclosure, msg, rhIndex, cri, memberships, rng)
+	assert.NoError(t, err)
+	assert.Len(t, sig.MembershipProofs, 2)
+	assert.NoError(t, sig.Ver(disclosure, key.IPk, msg, attrs, rhIndex, cri, memberships))
+
+	// The verifier must expect exactly the sets that were proven
+	assert.Error(t, sig.Ver(disclosure, key.IPk, msg, attrs, rhIndex, cri, nil))
+	assert.Error(t, sig.Ver(disclosure, key.IPk, msg, attrs, rhIndex, cri, map[int][]*FP256BN.BIG{1: memberships[1]}))
+	assert.Error(t, sig.Ver(disclosure, key.IPk, msg, attrs, rhIndex, cri, map[int][]*FP256BN.BIG{
+		1: {FP256BN.NewBIGint(5), FP256BN.NewBIGint(6), FP256BN.NewBIGint(9)},
+		2: memberships[2],
+	}))
+
+	// Tampering with the proof makes it invalid
+	proofC := sig.MembershipProofs[0].ProofC[0]
+	sig.MembershipProofs[0].ProofC[0] = BigToBytes(RandModOrder(rng))
+	assert.Error(t, sig.Ver(disclosure, key.IPk, msg, attrs, rhIndex, cri, memberships))
+	sig.MembershipProofs[0].ProofC[0] = proofC
+	sig.MembershipProofs[1].Commitment = EcpToProto(GenG1.Mul(RandModOrder(rng)))
+	assert.Error(t, sig.Ver(disclosure, key.IPk, msg, attrs, rhIndex, cri, memberships))
+
+	// The attribute value must be in the set
+	_, err = NewSignature(cred, sk, Nym, RandNym, key.IPk, disclosure, msg, rhIndex, cri, map[int][]*FP256BN.BIG{1: {FP256BN.NewBIGint(5)}}, rng)
+	assert.EqualError(t, err, "cannot create idemix signature: the value of attribute 1 is not in the set")
+
+	// The attribute must remain hidden
+	_, err = NewSignature(cred, sk, Nym, RandNym, key.IPk, disclosure, msg, rhIndex, cri, map[int][]*FP256BN.BIG{0: {FP256BN.NewBIGint(1)}}, rng)
+	assert.Error(t, err)
+	_, err = NewSignature(cred, sk, Nym, RandNym, key.IPk, disclosure, msg, rhIndex, cri, map[int][]*FP256BN.BIG{1: {}}, rng)
+	assert.Error(t, err)
+}
@@ -0,0 +1,128 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package idemix
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/binary"
+	"math/big"
+
+	"github.com/hyperledger/fabric-amcl/amcl"
+	"github.com/hyperledger/fabric-amcl/amcl/FP256BN"
+	"github.com/pkg/errors"
+)
+
+// The revocation authority decides, for every epoch (time window), which
+// credentials are revoked. It holds a long term ECDSA key and, for every epoch,
+// generates a fresh epoch key that is certified by the long term key.
+// With the epoch key it places a weak Boneh-Boyen signature on every revocation
+// handle that is not revoked in this epoch. A user proves that their credential is
+// not revoked by proving, in zero-knowledge, knowledge of an epoch signature on
+// the (hidden) revocation handle attribute of the credential.
+// The epoch signatures are published in a CredentialRevocationInformation (CRI) object.
+
+// RevocationAlgorithm identifies the revocation algorithm used in a CRI
+type RevocationAlgorithm int32
+
+const (
+	// ALG_NO_REVOCATION means that credentials are never revoked,
+	// and signatures carry no non-revocation proof
+	ALG_NO_REVOCATION RevocationAlgorithm = iota
+
+	// ALG_WEAK_BB means that the revocation authority places a weak Boneh-Boyen
+	// signature on every unrevoked revocation handle in every epoch
+	ALG_WEAK_BB
+)
+
+// ecdsaSignature is the ASN.1 structure of an ECDSA signature
+type ecdsaSignature struct {
+	R, S *big.Int
+}
+
+// GenerateLongTermRevocationKey generates the long term signing key of the revocation authority
+func GenerateLongTermRevocationKey() (*ecdsa.PrivateKey, error) {
+	return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+}
+
+// CreateCRI creates the credential revocation information for the given epoch,
+// containing an epoch signature on each of the unrevoked handles.
+// Revocation handles that are not in unrevokedHandles are revoked in this epoch.
+func CreateCRI(key *ecdsa.PrivateKey, unrevokedHandles []*FP256BN.BIG, epoch int64, alg RevocationAlgorithm, rng *amcl.RAND) (*CredentialRevocationInformation, error) {
+	if key == nil || rng == nil {
+		return nil, errors.Errorf("CreateCRI received nil input")
+	}
+	if alg != ALG_NO_REVOCATION && alg != ALG_WEAK_BB {
+		return nil, errors.Errorf("CreateCRI received unknown revocation algorithm %d", alg)
+	}
+
+	cri := &CredentialRevocationInformation{
+		Epoch:         epoch,
+		RevocationAlg: int32(alg),
+	}
+
+	// generate and certify the epoch key
+	epochSk, epochPk := WBBKeyGen(rng)
+	cri.EpochPk = Ecp2ToProto(epochPk)
+	r, s, err := ecdsa.Sign(rand.Reader, key, epochPkDigest(cri.EpochPk, epoch, alg))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sign the epoch public key")
+	}
+	cri.EpochPkSig, err = asn1.Marshal(ecdsaSignature{r, s})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal the epoch public key signature")
+	}
+
+	if alg == ALG_NO_REVOCATION {
+		return cri, nil
+	}
+
+	cri.NonRevokedHandles = make([]*NonRevokedHandle, len(unrevokedHandles))
+	for i, rh := range unrevokedHandles {
+		cri.NonRevokedHandles[i] = &NonRevokedHandle{
+			Handle:   BigToBytes(rh),
+			EpochSig: EcpToProto(WBBSign(epochSk, rh)),
+		}
+	}
+	return cri, nil
+}
+
+// VerifyCRI checks that the epoch public key of a CRI was certified by the
+// long term key of the revocation authority
+func VerifyCRI(pk *ecdsa.PublicKey, cri *CredentialRevocationInformation) error {
+	if pk == nil || cri == nil || cri.EpochPk == nil {
+		return errors.Errorf("VerifyCRI received nil input")
+	}
+
+	sig := &ecdsaSignature{}
+	rest, err := asn1.Unmarshal(cri.EpochPkSig, sig)
+	if err != nil {
+		return errors.Wrap(err, "failed to unmarshal the epoch public key signature")
+	}
+	if len(rest) != 0 || sig.R == nil || sig.S == nil {
+		return errors.Errorf("malformed epoch public key signature")
+	}
+
+	if !ecdsa.Verify(pk, epochPkDigest(cri.EpochPk, cri.Epoch, RevocationAlgorithm(cri.RevocationAlg)), sig.R, sig.S) {
+		return errors.Errorf("EpochPKSig invalid")
+	}
+	return nil
+}
+
+// epochPkDigest computes the digest signed by the revocation authority
+// to certify an epoch public key
+func epochPkDigest(epochPk *ECP2, epoch int64, alg RevocationAlgorithm) []byte {
+	data := make([]byte, 4*FieldBytes+8+4)
+	index := appendBytesG2(data, 0, Ecp2FromProto(epochPk))
+	binary.BigEndian.PutUint64(data[index:], uint64(epoch))
+	binary.BigEndian.PutUint32(data[index+8:], uint32(alg))
+	digest := sha256.Sum256(data)
+	return digest[:]
+}
@@ -0,0 +1,277 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package idemix
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+
+	"github.com/hyperledger/fabric-amcl/amcl"
+	"github.com/hyperledger/fabric-amcl/amcl/FP256BN"
+	"github.com/pkg/errors"
+)
+
+// A set membership proof shows that a hidden attribute of a credential takes one of
+// the values of a public set, without revealing which one.
+// The signer commits to the attribute value m with a Pedersen commitment
+// C = h_j^{m} h_r^{rho}, where h_j is the base of the attribute in the issuer public key.
+// Knowledge of the opening of C is proven together with the rest of the signature,
+// using the same s-value of the hidden attribute, which binds C to the credential.
+// Then, for each value v_i of the set, D_i = C h_j^{-v_i} is a commitment to zero
+// with randomness rho if and only if m = v_i. An OR-proof of knowledge of the discrete
+// logarithm of one of the D_i in base h_r (for details see R.Cramer, I.Damgard, B.Schoenmakers
+// "Proofs of Partial Knowledge and Simplified Design of Witness Hiding Protocols")
+// shows that m is in the set. The challenges c_i of the OR-proof sum up to the challenge
+// of the signature.
+
+// membershipData contains the values of a set membership proof that are hashed into the challenge
+type membershipData struct {
+	attributeIndex int
+	values         []*FP256BN.BIG
+	commitment     *FP256BN.ECP
+	t              *FP256BN.ECP
+	u              []*FP256BN.ECP
+}
+
+// membershipWitness contains the secrets of the signer for a set membership proof
+type membershipWitness struct {
+	membershipData
+	// hiddenIndex is the position of the attribute among the hidden attributes
+	hiddenIndex int
+	// member is the position of the attribute value in the set
+	member int
+	rho    *FP256BN.BIG
+	rRho   *FP256BN.BIG
+	w      *FP256BN.BIG
+	c      []*FP256BN.BIG
+	z      []*FP256BN.BIG
+}
+
+// sortedMembershipIndices returns the attribute indices of memberships in increasing order
+func sortedMembershipIndices(memberships map[int][]*FP256BN.BIG) []int {
+	indices := make([]int, 0, len(memberships))
+	for index := range memberships {
+		indices = append(indices, index)
+	}
+	sort.Ints(indices)
+	return indices
+}
+
+// hiddenIndexOf returns the position of attribute index among the hidden attributes
+func hiddenIndexOf(Disclosure []byte, HiddenIndices []int, index int) (int, error) {
+	if index < 0 || index >= len(Disclosure) {
+		return -1, errors.Errorf("attribute index %d out of range", index)
+	}
+	if Disclosure[index] != 0 {
+		return -1, errors.Errorf("attribute %d is disclosed but is also proven to be in a set, which requires it to remain hidden", index)
+	}
+	for i, j := range HiddenIndices {
+		if j == index {
+			return i, nil
+		}
+	}
+	return -1, errors.Errorf("attribute index %d not found", index)
+}
+
+// newMembershipWitnesses computes the commitments of the set membership proofs
+// for the attributes of cred. rAttrs are the randomness used for the hidden attributes.
+func newMembershipWitnesses(cred *Credential, ipk *IssuerPublicKey, Disclosure []byte, HiddenIndices []int, rAttrs []*FP256BN.BIG, memberships map[int][]*FP256BN.BIG, rng *amcl.RAND) ([]*membershipWitness, error) {
+	HRand := EcpFromProto(ipk.HRand)
+
+	witnesses := make([]*membershipWitness, 0, len(memberships))
+	for _, attributeIndex := range sortedMembershipIndices(memberships) {
+		values := memberships[attributeIndex]
+		if len(values) == 0 {
+			return nil, errors.Errorf("the set of values for attribute %d is empty", attributeIndex)
+		}
+		hiddenIndex, err := hiddenIndexOf(Disclosure, HiddenIndices, attributeIndex)
+		if err != nil {
+			return nil, err
+		}
+		if attributeIndex >= len(cred.Attrs) || attributeIndex >= len(ipk.HAttrs) {
+			return nil, errors.Errorf("credential has no attribute %d", attributeIndex)
+		}
+
+		m := FP256BN.FromBytes(cred.Attrs[attributeIndex])
+		member := -1
+		for i, v := range values {
+			if bigEqual(m, v) {
+				member = i
+				break
+			}
+		}
+		if member < 0 {
+			return nil, errors.Errorf("the value of attribute %d is not in the set", attributeIndex)
+		}
+
+		HAttr := EcpFromProto(ipk.HAttrs[attributeIndex])
+		witness := &membershipWitness{
+			hiddenIndex: hiddenIndex,
+			member:      member,
+			rho:         RandModOrder(rng),
+			rRho:        RandModOrder(rng),
+			w:           RandModOrder(rng),
+			c:           make([]*FP256BN.BIG, len(values)),
+			z:           make([]*FP256BN.BIG, len(values)),
+		}
+		witness.attributeIndex = attributeIndex
+		witness.values = values
+		witness.commitment = HAttr.Mul2(m, HRand, witness.rho)
+		witness.t = HAttr.Mul2(rAttrs[hiddenIndex], HRand, witness.rRho)
+		witness.u = make([]*FP256BN.ECP, len(values))
+		for i, v := range values {
+			if i == member {
+				witness.u[i] = FP256BN.G1mul(HRand, witness.w)
+				continue
+			}
+			// simulate the proof for the values the attribute is not equal to
+			witness.c[i] = RandModOrder(rng)
+			witness.z[i] = RandModOrder(rng)
+			witness.u[i] = FP256BN.G1mul(HRand, witness.z[i])
+			witness.u[i].Sub(FP256BN.G1mul(membershipD(witness.commitment, HAttr, v), witness.c[i]))
+		}
+		witnesses = append(witnesses, witness)
+	}
+	return witnesses, nil
+}
+
+// proof completes the set membership proof given the challenge of the signature
+func (witness *membershipWitness) proof(ProofC *FP256BN.BIG) *SetMembershipProof {
+	c := FP256BN.NewBIGcopy(ProofC)
+	for i, ci := range witness.c {
+		if i != witness.member {
+			c = Modsub(c, ci, GroupOrder)
+		}
+	}
+	witness.c[witness.member] = c
+	witness.z[witness.member] = Modadd(witness.w, FP256BN.Modmul(c, witness.rho, GroupOrder), GroupOrder)
+
+	proof := &SetMembershipProof{
+		AttributeIndex: int32(witness.attributeIndex),
+		Values:         make([][]byte, len(witness.values)),
+		Commitment:     EcpToProto(witness.commitment),
+		ProofSRand:     BigToBytes(Modadd(witness.rRho, FP256BN.Modmul(ProofC, witness.rho, GroupOrder), GroupOrder)),
+		ProofC:         make([][]byte, len(witness.values)),
+		ProofS:         make([][]byte, len(witness.values)),
+	}
+	for i := range witness.values {
+		proof.Values[i] = BigToBytes(witness.values[i])
+		proof.ProofC[i] = BigToBytes(witness.c[i])
+		proof.ProofS[i] = BigToBytes(witness.z[i])
+	}
+	return proof
+}
+
+// verifyMembershipProofs checks that the set membership proofs of sig prove exactly the
+// expected memberships, and recomputes the values that are hashed into the challenge
+func verifyMembershipProofs(sig *Signature, ipk *IssuerPublicKey, Disclosure []byte, HiddenIndices []int, ProofSAttrs []*FP256BN.BIG, ProofC *FP256BN.BIG, memberships map[int][]*FP256BN.BIG) ([]*membershipData, error) {
+	indices := sortedMembershipIndices(memberships)
+	if len(sig.MembershipProofs) != len(indices) {
+		return nil, errors.Errorf("expected %d set membership proofs, got %d", len(indices), len(sig.MembershipProofs))
+	}
+
+	HRand := EcpFromProto(ipk.HRand)
+
+	data := make([]*membershipData, len(indices))
+	for n, attributeIndex := range indices {
+		proof := sig.MembershipProofs[n]
+		if proof == nil || int(proof.AttributeIndex) != attributeIndex {
+			return nil, errors.Errorf("expected a set membership proof for attribute %d", attributeIndex)
+		}
+		hiddenIndex, err := hiddenIndexOf(Disclosure, HiddenIndices, attributeIndex)
+		if err != nil {
+			return nil, err
+		}
+		if attributeIndex >= len(ipk.HAttrs) {
+			return nil, errors.Errorf("issuer public key has no attribute %d", attributeIndex)
+		}
+
+		values := memberships[attributeIndex]
+		if len(proof.Values) != len(values) || len(proof.ProofC) != len(values) || len(proof.ProofS) != len(values) {
+			return nil, errors.Errorf("set membership proof for attribute %d has an incorrect amount of values", attributeIndex)
+		}
+		for i, v := range values {
+			if !bigEqual(FP256BN.FromBytes(proof.Values[i]), v) {
+				return nil, errors.Errorf("set membership proof for attribute %d is for a different set", attributeIndex)
+			}
+		}
+		if proof.Commitment == nil {
+			return nil, errors.Errorf("set membership proof for attribute %d has no commitment", attributeIndex)
+		}
+
+		HAttr := EcpFromProto(ipk.HAttrs[attributeIndex])
+		commitment := EcpFromProto(proof.Commitment)
+
+		// the challenges of the OR-proof must sum up to the challenge of the signature
+		sum := FP256BN.NewBIGint(0)
+		for _, c := range proof.ProofC {
+			sum = Modadd(sum, FP256BN.FromBytes(c), GroupOrder)
+		}
+		if !bigEqual(sum, ProofC) {
+			return nil, errors.Errorf("set membership proof for attribute %d has invalid challenges", attributeIndex)
+		}
+
+		t := HAttr.Mul2(ProofSAttrs[hiddenIndex], HRand, FP256BN.FromBytes(proof.ProofSRand))
+		t.Sub(FP256BN.G1mul(commitment, ProofC))
+
+		u := make([]*FP256BN.ECP, len(values))
+		for i, v := range values {
+			u[i] = FP256BN.G1mul(HRand, FP256BN.FromBytes(proof.ProofS[i]))
+			u[i].Sub(FP256BN.G1mul(membershipD(commitment, HAttr, v), FP256BN.FromBytes(proof.ProofC[i])))
+		}
+
+		data[n] = &membershipData{
+			attributeIndex: attributeIndex,
+			values:         values,
+			commitment:     commitment,
+			t:              t,
+			u:              u,
+		}
+	}
+	return data, nil
+}
+
+// bigEqual returns true if a and b have the same byte representation
+func bigEqual(a, b *FP256BN.BIG) bool {
+	return bytes.Equal(BigToBytes(a), BigToBytes(b))
+}
+
+// membershipD returns C h_j^{-v}, which is a commitment to zero if C commits to v
+func membershipD(commitment, HAttr *FP256BN.ECP, v *FP256BN.BIG) *FP256BN.ECP {
+	D := FP256BN.NewECP()
+	D.Copy(commitment)
+	D.Sub(FP256BN.G1mul(HAttr, v))
+	return D
+}
+
+// membershipDataLen returns the number of bytes appendMembershipData appends
+func membershipDataLen(memberships map[int][]*FP256BN.BIG) int {
+	length := 0
+	for _, values := range memberships {
+		length = length + 4 + len(values)*FieldBytes + (2+len(values))*(2*FieldBytes+1)
+	}
+	return length
+}
+
+// appendMembershipData appends, for each set membership proof, the attribute index,
+// the set of values, the commitment and the commitments of the zero-knowledge proofs to data
+func appendMembershipData(data []byte, index int, memberships []*membershipData) int {
+	for _, m := range memberships {
+		binary.BigEndian.PutUint32(data[index:], uint32(m.attributeIndex))
+		index = index + 4
+		for _, v := range m.values {
+			index = appendBytesBig(data, index, v)
+		}
+		index = appendBytesG1(data, index, m.commitment)
+		index = appendBytesG1(data, index, m.t)
+		for _, u := range m.u {
+			index = appendBytesG1(data, index, u)
+		}
+	}
+	return index
+}
@@ -7,6 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package idemix
 
 import (
+	"bytes"
+	"encoding/binary"
+
 	"github.com/hyperledger/fabric-amcl/amcl"
 	"github.com/hyperledger/fabric-amcl/amcl/FP256BN"
 	"github.com/pkg/errors"
@@ -27,6 +30,9 @@ const signLabel = "sign"
 // the advanced privacy features provided by Identity Mixer (due to zero-knowledge proofs):
 //  - Unlinkability of the signatures produced with the same credential
 //  - Selective attribute disclosure and predicates over attributes
+// If the issuer works with a revocation authority, the signature also proves (in zero-knowledge)
+// that the revocation handle attribute of the credential is not revoked in the current epoch,
+// by proving knowledge of a weak Boneh-Boyen epoch signature on it (see revocation_authority.go)
 
 // Make a slice of all the attribute indices that will not be disclosed
 func hiddenIndices(Disclosure []byte) []int {
@@ -43,13 +49,26 @@ func hiddenIndices(Disclosure []byte) []int {
 // The []byte Disclosure steers which attributes are disclosed:
 // if Disclosure[i] == 0 then attribute i remains hidden and otherwise it is disclosed.
 // We use the zero-knowledge proof by http://eprint.iacr.org/2016/663.pdf to prove knowledge of a BBS+ signature
-func NewSignature(cred *Credential, sk *FP256BN.BIG, Nym *FP256BN.ECP, RNym *FP256BN.BIG, ipk *IssuerPublicKey, Disclosure []byte, msg []byte, rng *amcl.RAND) (*Signature, error) {
+// The credential revocation information cri steers the non-revocation proof: attribute rhIndex
+// is the revocation handle, which must remain hidden. If cri is nil, no non-revocation proof is made.
+// memberships maps the indices of hidden attributes to sets of values: the signature proves
+// that each of these attributes takes one of the values of its set (see setmembership.go).
+func NewSignature(cred *Credential, sk *FP256BN.BIG, Nym *FP256BN.ECP, RNym *FP256BN.BIG, ipk *IssuerPublicKey, Disclosure []byte, msg []byte, rhIndex int, cri *CredentialRevocationInformation, memberships map[int][]*FP256BN.BIG, rng *amcl.RAND) (*Signature, error) {
 	if cred == nil || sk == nil || Nym == nil || RNym == nil || ipk == nil || rng == nil {
 		return nil, errors.Errorf("cannot create idemix signature: received nil input")
 	}
 
 	HiddenIndices := hiddenIndices(Disclosure)
 
+	alg, epoch := revocationParameters(cri)
+	rhHiddenIndex, err := revocationHandleIndex(alg, Disclosure, HiddenIndices, rhIndex)
+	if err != nil {
+		return nil, errors.WithMessage(err, "cannot create idemix signature")
+	}
+	if alg != ALG_NO_REVOCATION && rhIndex >= len(cred.Attrs) {
+		return nil, errors.Errorf("cannot create idemix signature: credential has no revocation handle attribute %d", rhIndex)
+	}
+
 	// Start sig
 	r1 := RandModOrder(rng)
 	r2 := RandModOrder(rng)
@@ -100,13 +119,46 @@ func NewSignature(cred *Credential, sk *FP256BN.BIG, Nym *FP256BN.ECP, RNym *FP2
 
 	t3 := HSk.Mul2(rSk, HRand, rRNym)
 
+	// Prove knowledge of an epoch signature on the revocation handle:
+	// the epoch signature sigma is randomized to sigma' = sigma^{rho}, and
+	// sigmaBar = g1^{rho} sigma'^{-rh} = sigma'^{epochSk} can be checked with a pairing
+	var NonRevSigPrime, NonRevSigBar, t4 *FP256BN.ECP
+	var rho, rRho *FP256BN.BIG
+	if alg == ALG_WEAK_BB {
+		rh := FP256BN.FromBytes(cred.Attrs[rhIndex])
+		epochSig, err := epochSignature(cri, rh)
+		if err != nil {
+			return nil, errors.WithMessage(err, "cannot create idemix signature")
+		}
+
+		rho = RandModOrder(rng)
+		rRho = RandModOrder(rng)
+		NonRevSigPrime = FP256BN.G1mul(epochSig, rho)
+		NonRevSigBar = FP256BN.G1mul(GenG1, rho)
+		NonRevSigBar.Sub(FP256BN.G1mul(NonRevSigPrime, rh))
+
+		t4 = FP256BN.G1mul(GenG1, rRho)
+		t4.Sub(FP256BN.G1mul(NonRevSigPrime, rAttrs[rhHiddenIndex]))
+	}
+
+	witnesses, err := newMembershipWitnesses(cred, ipk, Disclosure, HiddenIndices, rAttrs, memberships, rng)
+	if err != nil {
+		return nil, errors.WithMessage(err, "cannot create idemix signature")
+	}
+	membershipProofData := make([]*membershipData, len(witnesses))
+	for i, witness := range witnesses {
+		membershipProofData[i] = &witness.membershipData
+	}
+
 	// proofData is the data being hashed, it consists of:
 	// the signature label
 	// 7 elements of G1 each taking 2*FieldBytes+1 bytes
 	// one bigint (hash of the issuer public key) of length FieldBytes
 	// disclosed attributes
 	// message being signed
-	proofData := make([]byte, len([]byte(signLabel))+7*(2*FieldBytes+1)+FieldBytes+len(Disclosure)+len(msg))
+	// the epoch and revocation algorithm, and 3 elements of G1 in case of a non-revocation proof
+	// the sets, commitments and their proofs in case of set membership proofs
+	proofData := make([]byte, len([]byte(signLabel))+7*(2*FieldBytes+1)+FieldBytes+len(Disclosure)+len(msg)+nonRevocationDataLen(alg)+membershipDataLen(memberships))
 	index := 0
 	index = appendBytesString(proofData, index, signLabel)
 	index = appendBytesG1(proofData, index, t1)
@@ -121,6 +173,9 @@ func NewSignature(cred *Credential, sk *FP256BN.BIG, Nym *FP256BN.ECP, RNym *FP2
 	copy(proofData[index:], Disclosure)
 	index = index + len(Disclosure)
 	copy(proofData[index:], msg)
+	index = index + len(msg)
+	index = appendNonRevocationData(proofData, index, alg, epoch, t4, NonRevSigPrime, NonRevSigBar)
+	appendMembershipData(proofData, index, membershipProofData)
 	c := HashModOrder(proofData)
 
 	// add the previous hash and the nonce and hash again to compute a second hash (C value)
@@ -141,29 +196,57 @@ func NewSignature(cred *Credential, sk *FP256BN.BIG, Nym *FP256BN.ECP, RNym *FP2
 		ProofSAttrs[i] = BigToBytes(Modadd(rAttrs[i], FP256BN.Modmul(ProofC, FP256BN.FromBytes(cred.Attrs[j]), GroupOrder), GroupOrder))
 	}
 
-	return &Signature{
-			EcpToProto(APrime),
-			EcpToProto(ABar),
-			EcpToProto(BPrime),
-			BigToBytes(ProofC),
-			BigToBytes(ProofSSk),
-			BigToBytes(ProofSE),
-			BigToBytes(ProofSR2),
-			BigToBytes(ProofSR3),
-			BigToBytes(ProofSSPrime),
-			ProofSAttrs,
-			BigToBytes(Nonce),
-			EcpToProto(Nym),
-			BigToBytes(ProofSRNym)},
-		nil
+	sig := &Signature{
+		APrime:        EcpToProto(APrime),
+		ABar:          EcpToProto(ABar),
+		BPrime:        EcpToProto(BPrime),
+		ProofC:        BigToBytes(ProofC),
+		ProofSSk:      BigToBytes(ProofSSk),
+		ProofSE:       BigToBytes(ProofSE),
+		ProofSR2:      BigToBytes(ProofSR2),
+		ProofSR3:      BigToBytes(ProofSR3),
+		ProofSSPrime:  BigToBytes(ProofSSPrime),
+		ProofSAttrs:   ProofSAttrs,
+		Nonce:         BigToBytes(Nonce),
+		Nym:           EcpToProto(Nym),
+		ProofSRNym:    BigToBytes(ProofSRNym),
+		Epoch:         epoch,
+		RevocationAlg: int32(alg),
+	}
+	if alg == ALG_WEAK_BB {
+		sig.NonRevSigPrime = EcpToProto(NonRevSigPrime)
+		sig.NonRevSigBar = EcpToProto(NonRevSigBar)
+		sig.ProofSNonRev = BigToBytes(Modadd(rRho, FP256BN.Modmul(ProofC, rho, GroupOrder), GroupOrder))
+	}
+	for _, witness := range witnesses {
+		sig.MembershipProofs = append(sig.MembershipProofs, witness.proof(ProofC))
+	}
+	return sig, nil
 }
 
 // Ver verifies an idemix signature
 // Disclosure steers which attributes it expects to be disclosed
 // attributeValues[i] contains the desired attribute value for the i-th undisclosed attribute in Disclosure
-func (sig *Signature) Ver(Disclosure []byte, ipk *IssuerPublicKey, msg []byte, attributeValues []*FP256BN.BIG) error {
+// cri is the credential revocation information of the current epoch, the signature must prove
+// that the revocation handle (attribute rhIndex) is not revoked in it. If cri is nil, the
+// signature must not carry a non-revocation proof.
+// memberships maps the indices of hidden attributes to the sets of values the signature
+// must prove them to be in; the signature must not carry any other set membership proof.
+func (sig *Signature) Ver(Disclosure []byte, ipk *IssuerPublicKey, msg []byte, attributeValues []*FP256BN.BIG, rhIndex int, cri *CredentialRevocationInformation, memberships map[int][]*FP256BN.BIG) error {
 	HiddenIndices := hiddenIndices(Disclosure)
 
+	alg, epoch := revocationParameters(cri)
+	if RevocationAlgorithm(sig.GetRevocationAlg()) != alg {
+		return errors.Errorf("signature invalid: revocation algorithm %d, expected %d", sig.GetRevocationAlg(), alg)
+	}
+	if sig.GetEpoch() != epoch {
+		return errors.Errorf("signature invalid: signature is for epoch %d, but the current epoch is %d", sig.GetEpoch(), epoch)
+	}
+	rhHiddenIndex, err := revocationHandleIndex(alg, Disclosure, HiddenIndices, rhIndex)
+	if err != nil {
+		return errors.WithMessage(err, "signature invalid")
+	}
+
 	APrime := EcpFromProto(sig.GetAPrime())
 	ABar := EcpFromProto(sig.GetABar())
 	BPrime := EcpFromProto(sig.GetBPrime())
@@ -230,13 +313,45 @@ func (sig *Signature) Ver(Disclosure []byte, ipk *IssuerPublicKey, msg []byte, a
 	t3 := HSk.Mul2(ProofSSk, HRand, ProofSRNym)
 	t3.Sub(Nym.Mul(ProofC))
 
+	var NonRevSigPrime, NonRevSigBar, t4 *FP256BN.ECP
+	if alg == ALG_WEAK_BB {
+		if sig.NonRevSigPrime == nil || sig.NonRevSigBar == nil || sig.ProofSNonRev == nil {
+			return errors.Errorf("signature invalid: non-revocation proof is missing")
+		}
+		NonRevSigPrime = EcpFromProto(sig.NonRevSigPrime)
+		NonRevSigBar = EcpFromProto(sig.NonRevSigBar)
+		if NonRevSigPrime.Is_infinity() {
+			return errors.Errorf("signature invalid: NonRevSigPrime = 1")
+		}
+
+		// check that NonRevSigBar = NonRevSigPrime^{epochSk}
+		temp1 := FP256BN.Ate(Ecp2FromProto(cri.EpochPk), NonRevSigPrime)
+		temp2 := FP256BN.Ate(GenG2, NonRevSigBar)
+		temp2.Inverse()
+		temp1.Mul(temp2)
+		if !FP256BN.Fexp(temp1).Isunity() {
+			return errors.Errorf("signature invalid: NonRevSigPrime and NonRevSigBar don't have the expected structure")
+		}
+
+		t4 = FP256BN.G1mul(GenG1, FP256BN.FromBytes(sig.ProofSNonRev))
+		t4.Sub(FP256BN.G1mul(NonRevSigPrime, ProofSAttrs[rhHiddenIndex]))
+		t4.Sub(FP256BN.G1mul(NonRevSigBar, ProofC))
+	}
+
+	membershipProofData, err := verifyMembershipProofs(sig, ipk, Disclosure, HiddenIndices, ProofSAttrs, ProofC, memberships)
+	if err != nil {
+		return errors.WithMessage(err, "signature invalid")
+	}
+
 	// proofData is the data being hashed, it consists of:
 	// the signature label
 	// 7 elements of G1 each taking 2*FieldBytes+1 bytes
 	// one bigint (hash of the issuer public key) of length FieldBytes
 	// disclosed attributes
 	// message that was signed
-	proofData := make([]byte, len([]byte(signLabel))+7*(2*FieldBytes+1)+FieldBytes+len(Disclosure)+len(msg))
+	// the epoch and revocation algorithm, and 3 elements of G1 in case of a non-revocation proof
+	// the sets, commitments and their proofs in case of set membership proofs
+	proofData := make([]byte, len([]byte(signLabel))+7*(2*FieldBytes+1)+FieldBytes+len(Disclosure)+len(msg)+nonRevocationDataLen(alg)+membershipDataLen(memberships))
 	index := 0
 	index = appendBytesString(proofData, index, signLabel)
 	index = appendBytesG1(proofData, index, t1)
@@ -251,6 +366,9 @@ func (sig *Signature) Ver(Disclosure []byte, ipk *IssuerPublicKey, msg []byte, a
 	copy(proofData[index:], Disclosure)
 	index = index + len(Disclosure)
 	copy(proofData[index:], msg)
+	index = index + len(msg)
+	index = appendNonRevocationData(proofData, index, alg, epoch, t4, NonRevSigPrime, NonRevSigBar)
+	appendMembershipData(proofData, index, membershipProofData)
 
 	c := HashModOrder(proofData)
 	index = 0
@@ -263,3 +381,74 @@ func (sig *Signature) Ver(Disclosure []byte, ipk *IssuerPublicKey, msg []byte, a
 
 	return nil
 }
+
+// revocationParameters returns the revocation algorithm and epoch of a credential revocation information
+func revocationParameters(cri *CredentialRevocationInformation) (RevocationAlgorithm, int64) {
+	if cri == nil {
+		return ALG_NO_REVOCATION, 0
+	}
+	return RevocationAlgorithm(cri.RevocationAlg), cri.Epoch
+}
+
+// revocationHandleIndex checks that the revocation handle attribute is hidden and
+// returns its position among the hidden attributes. It returns -1 if no
+// non-revocation proof is required.
+func revocationHandleIndex(alg RevocationAlgorithm, Disclosure []byte, HiddenIndices []int, rhIndex int) (int, error) {
+	switch alg {
+	case ALG_NO_REVOCATION:
+		return -1, nil
+	case ALG_WEAK_BB:
+		if rhIndex < 0 || rhIndex >= len(Disclosure) {
+			return -1, errors.Errorf("revocation handle index %d out of range", rhIndex)
+		}
+		if Disclosure[rhIndex] != 0 {
+			return -1, errors.Errorf("attribute %d is disclosed but is also the revocation handle, which should remain hidden", rhIndex)
+		}
+		for i, j := range HiddenIndices {
+			if j == rhIndex {
+				return i, nil
+			}
+		}
+		return -1, errors.Errorf("revocation handle index %d not found", rhIndex)
+	default:
+		return -1, errors.Errorf("unknown revocation algorithm %d", alg)
+	}
+}
+
+// epochSignature looks up and verifies the epoch signature on the revocation handle rh
+func epochSignature(cri *CredentialRevocationInformation, rh *FP256BN.BIG) (*FP256BN.ECP, error) {
+	rhBytes := BigToBytes(rh)
+	for _, handle := range cri.NonRevokedHandles {
+		if !bytes.Equal(BigToBytes(FP256BN.FromBytes(handle.Handle)), rhBytes) {
+			continue
+		}
+		epochSig := EcpFromProto(handle.EpochSig)
+		if err := WBBVerify(Ecp2FromProto(cri.EpochPk), epochSig, rh); err != nil {
+			return nil, errors.WithMessage(err, "invalid epoch signature on the revocation handle")
+		}
+		return epochSig, nil
+	}
+	return nil, errors.Errorf("the revocation handle is revoked in epoch %d", cri.Epoch)
+}
+
+// nonRevocationDataLen returns the number of bytes appendNonRevocationData appends
+func nonRevocationDataLen(alg RevocationAlgorithm) int {
+	if alg == ALG_WEAK_BB {
+		return 12 + 3*(2*FieldBytes+1)
+	}
+	return 12
+}
+
+// appendNonRevocationData appends the epoch, the revocation algorithm and, in case
+// of a non-revocation proof, its commitment and randomized epoch signature to data
+func appendNonRevocationData(data []byte, index int, alg RevocationAlgorithm, epoch int64, t4, NonRevSigPrime, NonRevSigBar *FP256BN.ECP) int {
+	binary.BigEndian.PutUint64(data[index:], uint64(epoch))
+	binary.BigEndian.PutUint32(data[index+8:], uint32(alg))
+	index = index + 12
+	if alg == ALG_WEAK_BB {
+		index = appendBytesG1(data, index, t4)
+		index = appendBytesG1(data, index, NonRevSigPrime)
+		index = appendBytesG1(data, index, NonRevSigBar)
+	}
+	return index
+}
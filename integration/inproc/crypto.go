@@ -0,0 +1,179 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package inproc
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/tools/cryptogen/ca"
+	cryptogenmsp "github.com/hyperledger/fabric/common/tools/cryptogen/msp"
+	"github.com/hyperledger/fabric/msp"
+	mspproto "github.com/hyperledger/fabric/protos/msp"
+	"github.com/pkg/errors"
+)
+
+const (
+	adminName = "Admin"
+	userName  = "User1"
+)
+
+// organization holds the crypto material generated for an organization
+type organization struct {
+	name   string
+	mspID  string
+	domain string
+	// mspDir is the directory of the verifying MSP of the organization,
+	// which is the MSP that goes in the channel configuration
+	mspDir string
+	// nodes are the names of the peers or orderers of the organization
+	nodes       []string
+	admin       msp.SigningIdentity
+	user        msp.SigningIdentity
+	nodeSigners map[string]msp.SigningIdentity
+}
+
+// generateOrganization generates in dir the CAs, the verifying MSP and the local MSPs
+// of the nodes and users of an organization, in the layout used by cryptogen, and
+// loads the signing identities of the nodes and users
+func generateOrganization(dir, name string, nodeCount int, nodeType int) (*organization, error) {
+	domain := strings.ToLower(name) + ".example.com"
+	orgDir := filepath.Join(dir, domain)
+	org := &organization{
+		name:        name,
+		mspID:       name + "MSP",
+		domain:      domain,
+		mspDir:      filepath.Join(orgDir, "msp"),
+		nodeSigners: make(map[string]msp.SigningIdentity),
+	}
+
+	signCA, err := ca.NewCA(filepath.Join(orgDir, "ca"), domain, "ca."+domain, "US", "California", "San Francisco", "", "", "")
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("error generating the CA of organization %s", name))
+	}
+	tlsCA, err := ca.NewCA(filepath.Join(orgDir, "tlsca"), domain, "tlsca."+domain, "US", "California", "San Francisco", "", "", "")
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("error generating the TLS CA of organization %s", name))
+	}
+	if err := cryptogenmsp.GenerateVerifyingMSP(org.mspDir, signCA, tlsCA, false); err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("error generating the MSP of organization %s", name))
+	}
+
+	for i := 0; i < nodeCount; i++ {
+		nodeName := fmt.Sprintf("peer%d.%s", i, domain)
+		if nodeType == cryptogenmsp.ORDERER {
+			nodeName = fmt.Sprintf("orderer.%s", domain)
+		}
+		signer, err := generateSigningIdentity(filepath.Join(orgDir, "peers", nodeName), nodeName, org.mspID, signCA, tlsCA, nodeType)
+		if err != nil {
+			return nil, err
+		}
+		org.nodes = append(org.nodes, nodeName)
+		org.nodeSigners[nodeName] = signer
+	}
+
+	adminCommonName := fmt.Sprintf("%s@%s", adminName, domain)
+	if org.admin, err = generateSigningIdentity(filepath.Join(orgDir, "users", adminCommonName), adminCommonName, org.mspID, signCA, tlsCA, cryptogenmsp.CLIENT); err != nil {
+		return nil, err
+	}
+	userCommonName := fmt.Sprintf("%s@%s", userName, domain)
+	if org.user, err = generateSigningIdentity(filepath.Join(orgDir, "users", userCommonName), userCommonName, org.mspID, signCA, tlsCA, cryptogenmsp.CLIENT); err != nil {
+		return nil, err
+	}
+
+	// the admin of the organization is the identity whose certificate is in the
+	// admincerts folder of the verifying MSP, replacing the throwaway certificate
+	// created by cryptogen
+	adminCertsDir := filepath.Join(org.mspDir, "admincerts")
+	if err := os.RemoveAll(adminCertsDir); err != nil {
+		return nil, errors.Wrapf(err, "error clearing %s", adminCertsDir)
+	}
+	if err := os.MkdirAll(adminCertsDir, 0755); err != nil {
+		return nil, errors.Wrapf(err, "error creating %s", adminCertsDir)
+	}
+	adminCert, err := readSingleFile(filepath.Join(orgDir, "users", adminCommonName, "msp", "signcerts"))
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(filepath.Join(adminCertsDir, adminCommonName+"-cert.pem"), adminCert, 0644); err != nil {
+		return nil, errors.Wrapf(err, "error writing the admin certificate of organization %s", name)
+	}
+
+	return org, nil
+}
+
+// generateSigningIdentity generates the local MSP of a node or user in dir and
+// loads its signing identity
+func generateSigningIdentity(dir, commonName, mspID string, signCA, tlsCA *ca.CA, nodeType int) (msp.SigningIdentity, error) {
+	if err := cryptogenmsp.GenerateLocalMSP(dir, commonName, nil, signCA, tlsCA, nodeType, false); err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("error generating the MSP of %s", commonName))
+	}
+	signer, err := loadSigningIdentity(filepath.Join(dir, "msp"), mspID)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("error loading the signing identity of %s", commonName))
+	}
+	return signer, nil
+}
+
+// loadSigningIdentity loads the signing identity of the local MSP in mspDir. The
+// private key is handed to the MSP as key material instead of being looked up in
+// the keystore, as the BCCSP factories are initialized only once per process and
+// therefore cannot point to the keystores of the several MSPs of a network.
+func loadSigningIdentity(mspDir, mspID string) (msp.SigningIdentity, error) {
+	conf, err := msp.GetVerifyingMspConfig(mspDir, mspID, msp.ProviderTypeToString(msp.FABRIC))
+	if err != nil {
+		return nil, err
+	}
+	fabricConf := &mspproto.FabricMSPConfig{}
+	if err := proto.Unmarshal(conf.Config, fabricConf); err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling the MSP config")
+	}
+	cert, err := readSingleFile(filepath.Join(mspDir, "signcerts"))
+	if err != nil {
+		return nil, err
+	}
+	key, err := readSingleFile(filepath.Join(mspDir, "keystore"))
+	if err != nil {
+		return nil, err
+	}
+	fabricConf.SigningIdentity = &mspproto.SigningIdentityInfo{
+		PublicSigner:  cert,
+		PrivateSigner: &mspproto.KeyInfo{KeyIdentifier: "key", KeyMaterial: key},
+	}
+	if conf.Config, err = proto.Marshal(fabricConf); err != nil {
+		return nil, errors.Wrap(err, "error marshaling the MSP config")
+	}
+
+	localMSP, err := msp.New(&msp.BCCSPNewOpts{NewBaseOpts: msp.NewBaseOpts{Version: msp.MSPv1_0}})
+	if err != nil {
+		return nil, err
+	}
+	if err := localMSP.Setup(conf); err != nil {
+		return nil, err
+	}
+	return localMSP.GetDefaultSigningIdentity()
+}
+
+// readSingleFile returns the content of the only file in dir
+func readSingleFile(dir string) ([]byte, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading %s", dir)
+	}
+	if len(files) != 1 {
+		return nil, errors.Errorf("expected a single file in %s, found %d", dir, len(files))
+	}
+	content, err := ioutil.ReadFile(filepath.Join(dir, files[0].Name()))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading %s", files[0].Name())
+	}
+	return content, nil
+}
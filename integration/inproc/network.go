@@ -0,0 +1,439 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package inproc provides a test network that runs in the test process: a solo
+// orderer and one or more peers joined to a single channel. The peers use the
+// real endorser, validator, VSCC and ledger (with goleveldb state in a temporary
+// directory), and run the chaincodes in process through the inproccontroller,
+// so that tests can observe MVCC conflicts and endorsement policy failures
+// without Docker.
+//
+// The network relies on process-wide state of the peer packages, like the MSP
+// managers of the channels, hence two networks with the same channel ID must not
+// be used at the same time. Private data, chaincode upgrades, config updates and
+// chaincode-to-chaincode invocations of application chaincodes are not supported.
+package inproc
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/policies"
+	cryptogenmsp "github.com/hyperledger/fabric/common/tools/cryptogen/msp"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/container/inproccontroller"
+	"github.com/hyperledger/fabric/msp"
+	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
+	cb "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("inproc")
+
+// Config is the configuration of a Network
+type Config struct {
+	// ChannelID is the ID of the channel joined by the peers,
+	// testchannel if empty
+	ChannelID string
+	// Organizations are the organizations of the peers,
+	// a single organization Org1 with one peer if empty
+	Organizations []OrganizationConfig
+	// BatchTimeout is the time the orderer waits before cutting
+	// a block, 100ms if zero
+	BatchTimeout time.Duration
+	// MaxMessageCount is the maximum number of transactions
+	// in a block, 10 if zero
+	MaxMessageCount uint32
+	// CommitTimeout is the time Submit waits for a transaction
+	// to be committed by every peer, 30s if zero
+	CommitTimeout time.Duration
+}
+
+// OrganizationConfig is the configuration of an organization of a Network
+type OrganizationConfig struct {
+	// Name is the name of the organization, e.g. Org1. The MSP ID of the
+	// organization is the name followed by MSP, e.g. Org1MSP
+	Name string
+	// Peers is the number of peers of the organization, 1 if zero
+	Peers int
+}
+
+// Network is a test network running in the test process
+type Network struct {
+	id            string
+	dir           string
+	channelID     string
+	commitTimeout time.Duration
+	orgs          []*organization
+	bundle        *channelconfig.Bundle
+	orderer       *orderer
+	peers         []*Peer
+	started       bool
+}
+
+// Transaction is a transaction assembled from the endorsements of a proposal
+type Transaction struct {
+	// TxID is the ID of the transaction
+	TxID string
+	// Envelope is the signed transaction
+	Envelope *cb.Envelope
+	// Response is the response of the chaincode
+	Response *pb.Response
+}
+
+// NewNetwork generates the crypto material of the organizations, starts the orderer
+// and the peers and joins the peers to the channel
+func NewNetwork(config Config) (*Network, error) {
+	if config.ChannelID == "" {
+		config.ChannelID = "testchannel"
+	}
+	if len(config.Organizations) == 0 {
+		config.Organizations = []OrganizationConfig{{Name: "Org1"}}
+	}
+	if config.BatchTimeout == 0 {
+		config.BatchTimeout = 100 * time.Millisecond
+	}
+	if config.MaxMessageCount == 0 {
+		config.MaxMessageCount = 10
+	}
+	if config.CommitTimeout == 0 {
+		config.CommitTimeout = 30 * time.Second
+	}
+
+	dir, err := ioutil.TempDir("", "inproc")
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the directory of the network")
+	}
+	n := &Network{
+		id:            util.GenerateUUID(),
+		dir:           dir,
+		channelID:     config.ChannelID,
+		commitTimeout: config.CommitTimeout,
+	}
+	if err := n.start(config); err != nil {
+		n.Close()
+		return nil, err
+	}
+	return n, nil
+}
+
+func (n *Network) start(config Config) error {
+	cryptoDir := filepath.Join(n.dir, "crypto")
+	ordererOrg, err := generateOrganization(cryptoDir, "Orderer", 1, cryptogenmsp.ORDERER)
+	if err != nil {
+		return err
+	}
+	for _, orgConfig := range config.Organizations {
+		peers := orgConfig.Peers
+		if peers == 0 {
+			peers = 1
+		}
+		org, err := generateOrganization(cryptoDir, orgConfig.Name, peers, cryptogenmsp.PEER)
+		if err != nil {
+			return err
+		}
+		n.orgs = append(n.orgs, org)
+	}
+
+	systemGenesis, channelGenesis, err := genesisBlocks(n.channelID, ordererOrg, n.orgs, config.BatchTimeout, config.MaxMessageCount)
+	if err != nil {
+		return err
+	}
+	configEnv, err := utils.ExtractEnvelope(channelGenesis, 0)
+	if err != nil {
+		return err
+	}
+	if n.bundle, err = channelconfig.NewBundleFromEnvelope(configEnv); err != nil {
+		return errors.WithMessage(err, "error loading the channel config")
+	}
+	// the signatures of the proposals and transactions are checked
+	// against the process-wide MSP manager of the channel
+	mspmgmt.XXXSetMSPManager(n.channelID, n.bundle.MSPManager())
+
+	if n.orderer, err = newOrderer(ordererOrg.nodeSigners[ordererOrg.nodes[0]], systemGenesis, channelGenesis); err != nil {
+		return err
+	}
+	for _, org := range n.orgs {
+		for _, name := range org.nodes {
+			p, err := newPeer(n, name, org.mspID, org.nodeSigners[name], channelGenesis)
+			if p != nil {
+				n.peers = append(n.peers, p)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	reader, err := n.orderer.reader(n.channelID)
+	if err != nil {
+		return err
+	}
+	for _, p := range n.peers {
+		p.start(reader)
+	}
+	n.started = true
+	return nil
+}
+
+// Close stops the peers and the orderer and removes the files of the network
+func (n *Network) Close() {
+	for _, p := range n.peers {
+		p.stop(n.started)
+	}
+	if n.orderer != nil {
+		n.orderer.stop(systemChannelID, n.channelID)
+	}
+	if err := os.RemoveAll(n.dir); err != nil {
+		logger.Warningf("Error removing %s: %s", n.dir, err)
+	}
+}
+
+// ChannelID returns the ID of the channel of the network
+func (n *Network) ChannelID() string {
+	return n.channelID
+}
+
+// Peers returns the peers of the given organizations, or all the peers
+// of the network if no organization is given
+func (n *Network) Peers(orgs ...string) []*Peer {
+	if len(orgs) == 0 {
+		return n.peers
+	}
+	var peers []*Peer
+	for _, org := range orgs {
+		for _, p := range n.peers {
+			if p.MSPID == org+"MSP" {
+				peers = append(peers, p)
+			}
+		}
+	}
+	return peers
+}
+
+// Admin returns the admin identity of an organization, or nil if
+// the organization does not exist
+func (n *Network) Admin(org string) msp.SigningIdentity {
+	for _, o := range n.orgs {
+		if o.name == org {
+			return o.admin
+		}
+	}
+	return nil
+}
+
+// User returns a non-admin identity of an organization, or nil if
+// the organization does not exist
+func (n *Network) User(org string) msp.SigningIdentity {
+	for _, o := range n.orgs {
+		if o.name == org {
+			return o.user
+		}
+	}
+	return nil
+}
+
+// DeployChaincode deploys a chaincode on the channel. The chaincode is launched on
+// every peer, then a deploy transaction is endorsed by a peer of the first organization,
+// on behalf of the admin of that organization, and committed. The endorsement policy is
+// expressed in the syntax of cauthdsl.FromString, e.g. AND('Org1MSP.member','Org2MSP.member')
+func (n *Network) DeployChaincode(name, version string, cc shim.Chaincode, policy string, initArgs [][]byte) error {
+	policyEnvelope, err := cauthdsl.FromString(policy)
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("invalid endorsement policy %s", policy))
+	}
+
+	path := fmt.Sprintf("%s/%s/%s", n.id, name, version)
+	if err := inproccontroller.Register(path, cc); err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("error registering chaincode %s", name))
+	}
+	cds := &pb.ChaincodeDeploymentSpec{
+		ExecEnv: pb.ChaincodeDeploymentSpec_SYSTEM,
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			Type:        pb.ChaincodeSpec_GOLANG,
+			ChaincodeId: &pb.ChaincodeID{Name: name, Path: path, Version: version},
+			Input:       &pb.ChaincodeInput{Args: initArgs},
+		},
+	}
+	for _, p := range n.peers {
+		if err := p.launchChaincode(cds); err != nil {
+			return err
+		}
+	}
+
+	admin := n.orgs[0].admin
+	creator, err := admin.Serialize()
+	if err != nil {
+		return err
+	}
+	prop, txID, err := utils.CreateDeployProposalFromCDS(n.channelID, cds, creator, utils.MarshalOrPanic(policyEnvelope), []byte("escc"), []byte("vscc"), nil)
+	if err != nil {
+		return errors.WithMessage(err, "error creating the deploy proposal")
+	}
+	tx, err := n.endorse(admin, n.Peers(n.orgs[0].name)[:1], prop, txID)
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("error endorsing the deployment of chaincode %s", name))
+	}
+	codes, err := n.Submit(tx)
+	if err != nil {
+		return err
+	}
+	if codes[0] != pb.TxValidationCode_VALID {
+		return errors.Errorf("deployment of chaincode %s invalidated with code %s", name, codes[0])
+	}
+	return nil
+}
+
+// Endorse submits to the given peers a proposal, created by the signer, that invokes
+// a chaincode with the given arguments and assembles the transaction from the
+// endorsements. It fails if a peer does not endorse the proposal.
+func (n *Network) Endorse(signer msp.SigningIdentity, peers []*Peer, chaincode string, args [][]byte) (*Transaction, error) {
+	creator, err := signer.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	cis := &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			Type:        pb.ChaincodeSpec_GOLANG,
+			ChaincodeId: &pb.ChaincodeID{Name: chaincode},
+			Input:       &pb.ChaincodeInput{Args: args},
+		},
+	}
+	prop, txID, err := utils.CreateChaincodeProposal(cb.HeaderType_ENDORSER_TRANSACTION, n.channelID, cis, creator)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error creating the proposal")
+	}
+	return n.endorse(signer, peers, prop, txID)
+}
+
+func (n *Network) endorse(signer msp.SigningIdentity, peers []*Peer, prop *pb.Proposal, txID string) (*Transaction, error) {
+	if len(peers) == 0 {
+		return nil, errors.New("no peers to endorse the proposal")
+	}
+	signedProp, err := utils.GetSignedProposal(prop, signer)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error signing the proposal")
+	}
+	var responses []*pb.ProposalResponse
+	for _, p := range peers {
+		resp, err := p.endorser.ProcessProposal(context.Background(), signedProp)
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("error endorsing on peer %s", p.Name))
+		}
+		if resp.Response == nil {
+			return nil, errors.Errorf("empty response from peer %s", p.Name)
+		}
+		if resp.Response.Status >= shim.ERRORTHRESHOLD {
+			return nil, errors.Errorf("proposal not endorsed by peer %s: status %d, message %s", p.Name, resp.Response.Status, resp.Response.Message)
+		}
+		responses = append(responses, resp)
+	}
+	env, err := utils.CreateSignedTx(prop, signer, responses...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error assembling the transaction")
+	}
+	return &Transaction{TxID: txID, Envelope: env, Response: responses[0].Response}, nil
+}
+
+// Submit sends the transactions to the orderer, in order, and returns their validation
+// codes once every peer committed them. It fails if the peers disagree on a code.
+func (n *Network) Submit(txs ...*Transaction) ([]pb.TxValidationCode, error) {
+	for _, tx := range txs {
+		if err := n.orderer.broadcast(tx.Envelope); err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("error submitting transaction %s", tx.TxID))
+		}
+	}
+	deadline := time.Now().Add(n.commitTimeout)
+	codes := make([]pb.TxValidationCode, len(txs))
+	for i, tx := range txs {
+		for j, p := range n.peers {
+			code, err := p.waitForTransaction(tx.TxID, deadline)
+			if err != nil {
+				return nil, err
+			}
+			if j == 0 {
+				codes[i] = code
+				continue
+			}
+			if code != codes[i] {
+				return nil, errors.Errorf("peers %s and %s disagree on the validation of transaction %s: %s and %s", n.peers[0].Name, p.Name, tx.TxID, codes[i], code)
+			}
+		}
+	}
+	return codes, nil
+}
+
+// Invoke endorses a proposal on the given peers and submits the transaction,
+// returning its validation code
+func (n *Network) Invoke(signer msp.SigningIdentity, peers []*Peer, chaincode string, args [][]byte) (pb.TxValidationCode, error) {
+	tx, err := n.Endorse(signer, peers, chaincode, args)
+	if err != nil {
+		return pb.TxValidationCode_NOT_VALIDATED, err
+	}
+	codes, err := n.Submit(tx)
+	if err != nil {
+		return pb.TxValidationCode_NOT_VALIDATED, err
+	}
+	return codes[0], nil
+}
+
+// Query endorses a proposal on a peer, without submitting the transaction,
+// and returns the payload of the chaincode response
+func (n *Network) Query(signer msp.SigningIdentity, peer *Peer, chaincode string, args [][]byte) ([]byte, error) {
+	tx, err := n.Endorse(signer, []*Peer{peer}, chaincode, args)
+	if err != nil {
+		return nil, err
+	}
+	return tx.Response.Payload, nil
+}
+
+// checkProposal checks that the creator of the proposal satisfies the Writers policy
+// of the application, which is the default policy of the proposals in a peer
+func (n *Network) checkProposal(channelID string, signedProp *pb.SignedProposal) error {
+	if channelID != n.channelID {
+		return errors.Errorf("channel %s does not exist", channelID)
+	}
+	prop, err := utils.GetProposal(signedProp.ProposalBytes)
+	if err != nil {
+		return err
+	}
+	hdr, err := utils.GetHeader(prop.Header)
+	if err != nil {
+		return err
+	}
+	shdr, err := utils.GetSignatureHeader(hdr.SignatureHeader)
+	if err != nil {
+		return err
+	}
+	policy, ok := n.bundle.PolicyManager().GetPolicy(policies.ChannelApplicationWriters)
+	if !ok {
+		return errors.Errorf("policy %s not found", policies.ChannelApplicationWriters)
+	}
+	return policy.Evaluate([]*cb.SignedData{{
+		Data:      signedProp.ProposalBytes,
+		Identity:  shdr.Creator,
+		Signature: signedProp.Signature,
+	}})
+}
+
+// mspIDs returns the IDs of the MSPs of the organizations of the peers
+func (n *Network) mspIDs() []string {
+	var mspIDs []string
+	for _, org := range n.orgs {
+		mspIDs = append(mspIDs, org.mspID)
+	}
+	return mspIDs
+}
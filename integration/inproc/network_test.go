@@ -0,0 +1,135 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package inproc
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// kvChaincode stores values under keys. put writes a value and inc reads
+// a value and writes it back with a suffix, so that two concurrent inc
+// transactions on the same key conflict.
+type kvChaincode struct{}
+
+func (kvChaincode) Init(stub shim.ChaincodeStubInterface) pb.Response {
+	return shim.Success(nil)
+}
+
+func (kvChaincode) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
+	fn, args := stub.GetFunctionAndParameters()
+	switch fn {
+	case "put":
+		if err := stub.PutState(args[0], []byte(args[1])); err != nil {
+			return shim.Error(err.Error())
+		}
+		return shim.Success(nil)
+	case "get":
+		value, err := stub.GetState(args[0])
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+		return shim.Success(value)
+	case "inc":
+		value, err := stub.GetState(args[0])
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+		if err := stub.PutState(args[0], append(value, '+')); err != nil {
+			return shim.Error(err.Error())
+		}
+		return shim.Success(nil)
+	default:
+		return shim.Error("unknown function " + fn)
+	}
+}
+
+func args(strs ...string) [][]byte {
+	var res [][]byte
+	for _, s := range strs {
+		res = append(res, []byte(s))
+	}
+	return res
+}
+
+func TestInvokeAndQuery(t *testing.T) {
+	n, err := NewNetwork(Config{ChannelID: "invokechannel"})
+	require.NoError(t, err)
+	defer n.Close()
+
+	err = n.DeployChaincode("kv", "1.0", kvChaincode{}, "OR('Org1MSP.member')", nil)
+	require.NoError(t, err)
+
+	code, err := n.Invoke(n.User("Org1"), n.Peers(), "kv", args("put", "a", "100"))
+	require.NoError(t, err)
+	assert.Equal(t, pb.TxValidationCode_VALID, code)
+
+	for _, p := range n.Peers() {
+		value, err := n.Query(n.User("Org1"), p, "kv", args("get", "a"))
+		require.NoError(t, err)
+		assert.Equal(t, []byte("100"), value)
+	}
+}
+
+func TestMVCCConflict(t *testing.T) {
+	n, err := NewNetwork(Config{ChannelID: "mvccchannel"})
+	require.NoError(t, err)
+	defer n.Close()
+
+	err = n.DeployChaincode("kv", "1.0", kvChaincode{}, "OR('Org1MSP.member')", nil)
+	require.NoError(t, err)
+	code, err := n.Invoke(n.User("Org1"), n.Peers(), "kv", args("put", "a", "0"))
+	require.NoError(t, err)
+	require.Equal(t, pb.TxValidationCode_VALID, code)
+
+	// both transactions read the same version of the key before either is committed
+	tx1, err := n.Endorse(n.User("Org1"), n.Peers(), "kv", args("inc", "a"))
+	require.NoError(t, err)
+	tx2, err := n.Endorse(n.User("Org1"), n.Peers(), "kv", args("inc", "a"))
+	require.NoError(t, err)
+
+	codes, err := n.Submit(tx1, tx2)
+	require.NoError(t, err)
+	assert.Equal(t, []pb.TxValidationCode{pb.TxValidationCode_VALID, pb.TxValidationCode_MVCC_READ_CONFLICT}, codes)
+
+	value, err := n.Query(n.User("Org1"), n.Peers()[0], "kv", args("get", "a"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("0+"), value)
+}
+
+func TestEndorsementPolicyFailure(t *testing.T) {
+	n, err := NewNetwork(Config{
+		ChannelID: "policychannel",
+		Organizations: []OrganizationConfig{
+			{Name: "Org1"},
+			{Name: "Org2"},
+		},
+	})
+	require.NoError(t, err)
+	defer n.Close()
+
+	err = n.DeployChaincode("kv", "1.0", kvChaincode{}, "AND('Org1MSP.member','Org2MSP.member')", nil)
+	require.NoError(t, err)
+
+	code, err := n.Invoke(n.User("Org1"), n.Peers("Org1"), "kv", args("put", "a", "100"))
+	require.NoError(t, err)
+	assert.Equal(t, pb.TxValidationCode_ENDORSEMENT_POLICY_FAILURE, code)
+
+	code, err = n.Invoke(n.User("Org2"), n.Peers("Org1", "Org2"), "kv", args("put", "a", "200"))
+	require.NoError(t, err)
+	assert.Equal(t, pb.TxValidationCode_VALID, code)
+
+	for _, p := range n.Peers() {
+		value, err := n.Query(n.User("Org1"), p, "kv", args("get", "a"))
+		require.NoError(t, err)
+		assert.Equal(t, []byte("200"), value)
+	}
+}
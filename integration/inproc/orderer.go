@@ -0,0 +1,194 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package inproc
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/crypto"
+	"github.com/hyperledger/fabric/common/genesis"
+	"github.com/hyperledger/fabric/common/ledger/blockledger"
+	ramledger "github.com/hyperledger/fabric/common/ledger/blockledger/ram"
+	"github.com/hyperledger/fabric/common/tools/configtxgen/encoder"
+	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/orderer/common/multichannel"
+	"github.com/hyperledger/fabric/orderer/consensus"
+	"github.com/hyperledger/fabric/orderer/consensus/solo"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+const (
+	systemChannelID = "systemchannel"
+	consortiumName  = "SampleConsortium"
+	// ramLedgerSize is the number of blocks that the orderer keeps per channel,
+	// which bounds the number of blocks of a test
+	ramLedgerSize = 100000
+)
+
+// orderer is a solo orderer backed by in-memory ledgers
+type orderer struct {
+	registrar *multichannel.Registrar
+}
+
+// localSigner implements crypto.LocalSigner with a signing identity
+type localSigner struct {
+	msp.SigningIdentity
+	*crypto.SignatureHeaderCreator
+}
+
+func newLocalSigner(id msp.SigningIdentity) crypto.LocalSigner {
+	return &localSigner{
+		SigningIdentity:        id,
+		SignatureHeaderCreator: crypto.NewSignatureHeaderCreator(id),
+	}
+}
+
+// newOrderer starts a solo orderer whose system channel and application channel
+// are bootstrapped with the given genesis blocks
+func newOrderer(signer msp.SigningIdentity, systemGenesis, channelGenesis *cb.Block) (*orderer, error) {
+	ledgerFactory := ramledger.New(ramLedgerSize)
+	for _, block := range []*cb.Block{systemGenesis, channelGenesis} {
+		channelID, err := utils.GetChainIDFromBlock(block)
+		if err != nil {
+			return nil, err
+		}
+		ledger, err := ledgerFactory.GetOrCreate(channelID)
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("error creating the orderer ledger of channel %s", channelID))
+		}
+		if err := ledger.Append(block); err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("error appending the genesis block of channel %s", channelID))
+		}
+	}
+	consenters := map[string]consensus.Consenter{encoder.ConsensusTypeSolo: solo.New()}
+	return &orderer{
+		registrar: multichannel.NewRegistrar(ledgerFactory, consenters, newLocalSigner(signer)),
+	}, nil
+}
+
+// broadcast submits a transaction for ordering
+func (o *orderer) broadcast(env *cb.Envelope) error {
+	_, isConfig, chain, err := o.registrar.BroadcastChannelSupport(env)
+	if err != nil {
+		return errors.WithMessage(err, "error looking up the channel of the transaction")
+	}
+	if isConfig {
+		return errors.New("config transactions are not supported")
+	}
+	configSeq, err := chain.ProcessNormalMsg(env)
+	if err != nil {
+		return errors.WithMessage(err, "transaction rejected by the orderer")
+	}
+	if err := chain.WaitReady(); err != nil {
+		return err
+	}
+	return chain.Order(env, configSeq)
+}
+
+// reader returns the reader of the blocks of a channel
+func (o *orderer) reader(channelID string) (blockledger.Reader, error) {
+	chain, ok := o.registrar.GetChain(channelID)
+	if !ok {
+		return nil, errors.Errorf("channel %s does not exist", channelID)
+	}
+	return chain.Reader(), nil
+}
+
+// stop halts the chains of the orderer
+func (o *orderer) stop(channelIDs ...string) {
+	for _, channelID := range channelIDs {
+		if chain, ok := o.registrar.GetChain(channelID); ok {
+			chain.Halt()
+		}
+	}
+}
+
+// genesisBlocks returns the genesis block of the system channel of the orderer and
+// the genesis block of the application channel joined by the peers
+func genesisBlocks(channelID string, ordererOrg *organization, peerOrgs []*organization, batchTimeout time.Duration, maxMessageCount uint32) (systemGenesis, channelGenesis *cb.Block, err error) {
+	var applicationOrgs []*genesisconfig.Organization
+	for _, org := range peerOrgs {
+		applicationOrgs = append(applicationOrgs, organizationProfile(org))
+	}
+	ordererProfile := &genesisconfig.Orderer{
+		OrdererType:  encoder.ConsensusTypeSolo,
+		Addresses:    []string{"orderer." + ordererOrg.domain + ":7050"},
+		BatchTimeout: batchTimeout,
+		BatchSize: genesisconfig.BatchSize{
+			MaxMessageCount:   maxMessageCount,
+			AbsoluteMaxBytes:  10 * 1024 * 1024,
+			PreferredMaxBytes: 512 * 1024,
+		},
+		Organizations: []*genesisconfig.Organization{organizationProfile(ordererOrg)},
+		Capabilities:  map[string]bool{"V1_1": true},
+		Policies:      implicitMetaPolicies(),
+	}
+
+	systemGenesis, err = genesisBlock(systemChannelID, &genesisconfig.Profile{
+		Orderer: ordererProfile,
+		Consortiums: map[string]*genesisconfig.Consortium{
+			consortiumName: {Organizations: applicationOrgs},
+		},
+		Capabilities: map[string]bool{"V1_1": true},
+		Policies:     implicitMetaPolicies(),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	channelGenesis, err = genesisBlock(channelID, &genesisconfig.Profile{
+		Consortium: consortiumName,
+		Orderer:    ordererProfile,
+		Application: &genesisconfig.Application{
+			Organizations: applicationOrgs,
+			Capabilities:  map[string]bool{"V1_2": true},
+			Policies:      implicitMetaPolicies(),
+		},
+		Capabilities: map[string]bool{"V1_1": true},
+		Policies:     implicitMetaPolicies(),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return systemGenesis, channelGenesis, nil
+}
+
+func genesisBlock(channelID string, profile *genesisconfig.Profile) (*cb.Block, error) {
+	channelGroup, err := encoder.NewChannelGroup(profile)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("error creating the config of channel %s", channelID))
+	}
+	return genesis.NewFactoryImpl(channelGroup).Block(channelID)
+}
+
+// organizationProfile returns the configtxgen profile of an organization, whose
+// admins are the identities in the admincerts folder of its MSP
+func organizationProfile(org *organization) *genesisconfig.Organization {
+	return &genesisconfig.Organization{
+		Name:    org.name,
+		ID:      org.mspID,
+		MSPDir:  org.mspDir,
+		MSPType: msp.ProviderTypeToString(msp.FABRIC),
+		Policies: map[string]*genesisconfig.Policy{
+			channelconfig.ReadersPolicyKey: {Type: encoder.SignaturePolicyType, Rule: fmt.Sprintf("OR('%s.member')", org.mspID)},
+			channelconfig.WritersPolicyKey: {Type: encoder.SignaturePolicyType, Rule: fmt.Sprintf("OR('%s.member')", org.mspID)},
+			channelconfig.AdminsPolicyKey:  {Type: encoder.SignaturePolicyType, Rule: fmt.Sprintf("OR('%s.admin')", org.mspID)},
+		},
+	}
+}
+
+func implicitMetaPolicies() map[string]*genesisconfig.Policy {
+	return map[string]*genesisconfig.Policy{
+		channelconfig.ReadersPolicyKey: {Type: encoder.ImplicitMetaPolicyType, Rule: "ANY " + channelconfig.ReadersPolicyKey},
+		channelconfig.WritersPolicyKey: {Type: encoder.ImplicitMetaPolicyType, Rule: "ANY " + channelconfig.WritersPolicyKey},
+		channelconfig.AdminsPolicyKey:  {Type: encoder.ImplicitMetaPolicyType, Rule: "MAJORITY " + channelconfig.AdminsPolicyKey},
+	}
+}
@@ -0,0 +1,337 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package inproc
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/blockledger"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/chaincode"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/committer"
+	"github.com/hyperledger/fabric/core/committer/txvalidator"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/container"
+	"github.com/hyperledger/fabric/core/container/inproccontroller"
+	"github.com/hyperledger/fabric/core/endorser"
+	"github.com/hyperledger/fabric/core/handlers/endorsement/builtin"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	ledgerutil "github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/core/scc/vscc"
+	"github.com/hyperledger/fabric/msp"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"golang.org/x/sync/semaphore"
+)
+
+// chaincodeTimeout is the timeout of the startup and of the execution of the chaincodes
+const chaincodeTimeout = 30 * time.Second
+
+// initLock serializes the parts of the initialization of the peers that go
+// through process-wide state: the file system path of the ledgers, which is
+// read from viper, and the chaincode provider captured by the validator
+var initLock sync.Mutex
+
+// Peer is an in-process peer joined to the channel of a Network
+type Peer struct {
+	// Name is the name of the peer, e.g. peer0.org1.example.com
+	Name string
+	// MSPID is the MSP ID of the organization of the peer
+	MSPID string
+
+	network          *Network
+	signer           msp.SigningIdentity
+	ledgerProvider   ledger.PeerLedgerProvider
+	ledger           ledger.PeerLedger
+	sccp             *sccProvider
+	chaincodeSupport *chaincode.ChaincodeSupport
+	endorser         pb.EndorserServer
+	validator        txvalidator.Validator
+	committer        *committer.LedgerCommitter
+	chaincodes       []launchedChaincode
+
+	mutex     sync.Mutex
+	codes     map[string]pb.TxValidationCode
+	committed chan struct{}
+	commitErr error
+	done      chan struct{}
+	stopped   chan struct{}
+}
+
+// launchedChaincode is a chaincode launched on the peer, which is stopped with the peer
+type launchedChaincode struct {
+	cccid *ccprovider.CCContext
+	cds   *pb.ChaincodeDeploymentSpec
+}
+
+// newPeer creates a peer whose ledger is bootstrapped with the genesis block of the
+// channel and launches its validation system chaincode. The peer is returned along
+// with the error, if any, so that the resources it acquired can be released.
+func newPeer(n *Network, name, mspID string, signer msp.SigningIdentity, channelGenesis *cb.Block) (*Peer, error) {
+	p := &Peer{
+		Name:      name,
+		MSPID:     mspID,
+		network:   n,
+		signer:    signer,
+		codes:     make(map[string]pb.TxValidationCode),
+		committed: make(chan struct{}),
+		done:      make(chan struct{}),
+		stopped:   make(chan struct{}),
+	}
+
+	if err := p.createLedger(filepath.Join(n.dir, "peers", name), channelGenesis); err != nil {
+		return p, err
+	}
+
+	p.sccp = &sccProvider{peer: p}
+	vmController := container.NewVMController()
+	vmController.RegisterVMProvider(container.SYSTEM, &peerVMProvider{inproccontroller.NewProvider()})
+	chaincodeConfig := &chaincode.Config{
+		PeerNetworkID:  n.id,
+		PeerID:         name,
+		ExecuteTimeout: chaincodeTimeout,
+		StartupTimeout: chaincodeTimeout,
+		LogFormat:      "%{message}",
+		LogLevel:       "info",
+		ShimLogLevel:   "warning",
+	}
+	p.chaincodeSupport = chaincode.NewChaincodeSupport(chaincodeConfig, "", false, nil, nil, nil, &aclProvider{network: n}, vmController)
+	p.chaincodeSupport.SetSysCCProvider(p.sccp)
+	if err := p.startSystemChaincode("vscc", vscc.New(p.sccp)); err != nil {
+		return p, err
+	}
+
+	initLock.Lock()
+	chaincode.SideEffectInitialize(p.chaincodeSupport)
+	p.validator = txvalidator.NewTxValidator(n.channelID, &validatorSupport{
+		peer:      p,
+		semaphore: semaphore.NewWeighted(int64(runtime.NumCPU())),
+	}, p.sccp)
+	initLock.Unlock()
+	p.committer = committer.NewLedgerCommitter(p.ledger)
+
+	support := &endorserSupport{peer: p}
+	support.PluginEndorser = endorser.NewPluginEndorser(support, support, endorser.MapBasedPluginMapper{
+		"escc": &builtin.DefaultEndorsementFactory{},
+	})
+	p.endorser = endorser.NewEndorserServer(distributePrivateData, support)
+
+	return p, nil
+}
+
+func (p *Peer) createLedger(dir string, channelGenesis *cb.Block) error {
+	initLock.Lock()
+	defer initLock.Unlock()
+	viper.Set("peer.fileSystemPath", dir)
+	provider, err := kvledger.NewProvider()
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("error creating the ledger provider of peer %s", p.Name))
+	}
+	provider.Initialize(nil)
+	p.ledgerProvider = provider
+
+	// like the join through CSCC, mark the transactions of the genesis block as valid,
+	// on a copy as the block is shared with the orderer and the other peers
+	block := proto.Clone(channelGenesis).(*cb.Block)
+	if len(block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER]) == 0 {
+		block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER] = ledgerutil.NewTxValidationFlagsSetValue(len(block.Data.Data), pb.TxValidationCode_VALID)
+	}
+	if p.ledger, err = provider.Create(block); err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("error creating the ledger of peer %s", p.Name))
+	}
+	return nil
+}
+
+// distributePrivateData is the private data distributor of the endorsers, which
+// fails as there is no gossip between the in-process peers
+func distributePrivateData(channel string, txID string, privateData *rwset.TxPvtReadWriteSet, blkHt uint64) error {
+	return errors.New("private data is not supported by the in-process network")
+}
+
+// Ledger returns the ledger of the channel on the peer
+func (p *Peer) Ledger() ledger.PeerLedger {
+	return p.ledger
+}
+
+// channelLedger returns the ledger of the channel on the peer
+func (p *Peer) channelLedger(channelID string) (ledger.PeerLedger, error) {
+	if channelID != p.network.channelID {
+		return nil, errors.Errorf("channel %s does not exist", channelID)
+	}
+	return p.ledger, nil
+}
+
+// startSystemChaincode registers and deploys a system chaincode of the peer. The path
+// of the chaincode is specific to the peer as system chaincodes are bound to the
+// system chaincode provider of their peer.
+func (p *Peer) startSystemChaincode(name string, cc shim.Chaincode) error {
+	path := fmt.Sprintf("%s/%s/%s", p.network.id, p.Name, name)
+	if err := inproccontroller.Register(path, cc); err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("error registering system chaincode %s", name))
+	}
+	cds := &pb.ChaincodeDeploymentSpec{
+		ExecEnv: pb.ChaincodeDeploymentSpec_SYSTEM,
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			Type:        pb.ChaincodeSpec_GOLANG,
+			ChaincodeId: &pb.ChaincodeID{Name: name, Path: path},
+			Input:       &pb.ChaincodeInput{},
+		},
+	}
+	cccid := ccprovider.NewCCContext("", name, util.GetSysCCVersion(), util.GenerateUUID(), true, nil, nil)
+	resp, _, err := p.chaincodeSupport.ExecuteSpec(context.Background(), cccid, cds)
+	if err == nil && resp.Status != shim.OK {
+		err = errors.New(resp.Message)
+	}
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("error deploying system chaincode %s on peer %s", name, p.Name))
+	}
+	p.chaincodes = append(p.chaincodes, launchedChaincode{cccid: cccid, cds: cds})
+	return nil
+}
+
+// launchChaincode launches an application chaincode on the peer
+func (p *Peer) launchChaincode(cds *pb.ChaincodeDeploymentSpec) error {
+	chaincodeID := cds.ChaincodeSpec.ChaincodeId
+	cccid := ccprovider.NewCCContext(p.network.channelID, chaincodeID.Name, chaincodeID.Version, util.GenerateUUID(), false, nil, nil)
+	if err := p.chaincodeSupport.Launch(context.Background(), cccid, cds); err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("error launching chaincode %s on peer %s", chaincodeID.Name, p.Name))
+	}
+	p.chaincodes = append(p.chaincodes, launchedChaincode{cccid: cccid, cds: cds})
+	return nil
+}
+
+// start starts the delivery of the blocks of the channel from the orderer to the peer
+func (p *Peer) start(reader blockledger.Reader) {
+	go p.deliverBlocks(reader)
+}
+
+// stop stops the delivery of the blocks and the chaincodes of the peer and closes its ledger
+func (p *Peer) stop(started bool) {
+	close(p.done)
+	if started {
+		<-p.stopped
+	}
+	for _, cc := range p.chaincodes {
+		if err := p.chaincodeSupport.Stop(context.Background(), cc.cccid, cc.cds); err != nil {
+			logger.Warningf("Error stopping chaincode %s on peer %s: %s", cc.cccid.Name, p.Name, err)
+		}
+	}
+	if p.ledger != nil {
+		p.ledger.Close()
+	}
+	if p.ledgerProvider != nil {
+		p.ledgerProvider.Close()
+	}
+}
+
+// deliverBlocks validates and commits the blocks of the channel, starting after the
+// genesis block, until the peer is stopped or a block fails to commit
+func (p *Peer) deliverBlocks(reader blockledger.Reader) {
+	defer close(p.stopped)
+	iterator, _ := reader.Iterator(&ab.SeekPosition{
+		Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: 1}},
+	})
+	defer iterator.Close()
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-iterator.ReadyChan():
+		}
+		block, status := iterator.Next()
+		if status != cb.Status_SUCCESS {
+			p.fail(errors.Errorf("error reading a block from the orderer: %s", status))
+			return
+		}
+		// the peers receive their own copy of the block, as they would through
+		// the deliver service, since the validation writes to its metadata
+		if err := p.commitBlock(proto.Clone(block).(*cb.Block)); err != nil {
+			p.fail(err)
+			return
+		}
+	}
+}
+
+// commitBlock validates and commits a block and records the validation codes of its transactions
+func (p *Peer) commitBlock(block *cb.Block) error {
+	if err := p.validator.Validate(block); err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("error validating block %d on peer %s", block.Header.Number, p.Name))
+	}
+	if err := p.committer.CommitWithPvtData(&ledger.BlockAndPvtData{Block: block}); err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("error committing block %d on peer %s", block.Header.Number, p.Name))
+	}
+
+	txsFilter := ledgerutil.TxValidationFlags(block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	codes := make(map[string]pb.TxValidationCode)
+	for i, envBytes := range block.Data.Data {
+		env, err := utils.GetEnvelopeFromBlock(envBytes)
+		if err != nil {
+			return err
+		}
+		chdr, err := utils.ChannelHeader(env)
+		if err != nil {
+			return err
+		}
+		codes[chdr.TxId] = txsFilter.Flag(i)
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for txID, code := range codes {
+		p.codes[txID] = code
+	}
+	close(p.committed)
+	p.committed = make(chan struct{})
+	return nil
+}
+
+func (p *Peer) fail(err error) {
+	logger.Errorf("Peer %s stopped committing blocks: %s", p.Name, err)
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.commitErr = err
+	close(p.committed)
+	p.committed = make(chan struct{})
+}
+
+// waitForTransaction waits until the peer commits the given transaction and returns
+// its validation code
+func (p *Peer) waitForTransaction(txID string, deadline time.Time) (pb.TxValidationCode, error) {
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+	for {
+		p.mutex.Lock()
+		code, ok := p.codes[txID]
+		commitErr := p.commitErr
+		committed := p.committed
+		p.mutex.Unlock()
+		switch {
+		case ok:
+			return code, nil
+		case commitErr != nil:
+			return pb.TxValidationCode_NOT_VALIDATED, errors.WithMessage(commitErr, fmt.Sprintf("peer %s stopped committing blocks", p.Name))
+		}
+		select {
+		case <-committed:
+		case <-timer.C:
+			return pb.TxValidationCode_NOT_VALIDATED, errors.Errorf("timeout waiting for transaction %s to be committed on peer %s", txID, p.Name)
+		}
+	}
+}
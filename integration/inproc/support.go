@@ -0,0 +1,399 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package inproc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/core/chaincode"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/common/validation/statebased"
+	"github.com/hyperledger/fabric/core/container"
+	"github.com/hyperledger/fabric/core/container/api"
+	"github.com/hyperledger/fabric/core/container/ccintf"
+	"github.com/hyperledger/fabric/core/endorser"
+	endorsement "github.com/hyperledger/fabric/core/handlers/endorsement/api/identities"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/scc"
+	"github.com/hyperledger/fabric/msp"
+	cb "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"golang.org/x/sync/semaphore"
+)
+
+// lifecycleNamespace is the namespace in which the definitions of the chaincodes are stored
+const lifecycleNamespace = "lscc"
+
+// endorserSupport implements endorser.Support for a peer. The lifecycle system chaincode
+// is emulated, see deployChaincode, and the other system chaincodes are not available.
+type endorserSupport struct {
+	*endorser.PluginEndorser
+	peer *Peer
+}
+
+// Sign signs the message with the identity of the peer
+func (s *endorserSupport) Sign(message []byte) ([]byte, error) {
+	return s.peer.signer.Sign(message)
+}
+
+// Serialize returns the serialized identity of the peer
+func (s *endorserSupport) Serialize() ([]byte, error) {
+	return s.peer.signer.Serialize()
+}
+
+// SigningIdentityForRequest returns the identity of the peer
+func (s *endorserSupport) SigningIdentityForRequest(*pb.SignedProposal) (endorsement.SigningIdentity, error) {
+	return s.peer.signer, nil
+}
+
+// NewQueryCreator returns the ledger of the channel
+func (s *endorserSupport) NewQueryCreator(channel string) (endorser.QueryCreator, error) {
+	return s.peer.channelLedger(channel)
+}
+
+// IsSysCCAndNotInvokableExternal returns true if the supplied chaincode is
+// a system chaincode and it is not invokable
+func (s *endorserSupport) IsSysCCAndNotInvokableExternal(name string) bool {
+	return scc.IsSysCCAndNotInvokableExternal(name)
+}
+
+// GetTxSimulator returns a transaction simulator on the ledger of the channel
+func (s *endorserSupport) GetTxSimulator(ledgername string, txid string) (ledger.TxSimulator, error) {
+	lgr, err := s.peer.channelLedger(ledgername)
+	if err != nil {
+		return nil, err
+	}
+	return lgr.NewTxSimulator(txid)
+}
+
+// GetHistoryQueryExecutor returns a history query executor on the ledger of the channel
+func (s *endorserSupport) GetHistoryQueryExecutor(ledgername string) (ledger.HistoryQueryExecutor, error) {
+	lgr, err := s.peer.channelLedger(ledgername)
+	if err != nil {
+		return nil, err
+	}
+	return lgr.NewHistoryQueryExecutor()
+}
+
+// GetTransactionByID retrieves a transaction by id
+func (s *endorserSupport) GetTransactionByID(chid, txID string) (*pb.ProcessedTransaction, error) {
+	lgr, err := s.peer.channelLedger(chid)
+	if err != nil {
+		return nil, err
+	}
+	return lgr.GetTransactionByID(txID)
+}
+
+// IsSysCC returns true if the name matches a system chaincode
+func (s *endorserSupport) IsSysCC(name string) bool {
+	return scc.IsSysCC(name)
+}
+
+// Execute executes a proposal on the chaincode support of the peer
+func (s *endorserSupport) Execute(ctxt context.Context, cid, name, version, txid string, syscc bool, signedProp *pb.SignedProposal, prop *pb.Proposal, spec ccprovider.ChaincodeSpecGetter) (*pb.Response, *pb.ChaincodeEvent, error) {
+	if name == lifecycleNamespace {
+		return s.peer.network.deployChaincode(ctxt, spec)
+	}
+
+	cccid := ccprovider.NewCCContext(cid, name, version, txid, syscc, signedProp, prop)
+	switch spec := spec.(type) {
+	case *pb.ChaincodeDeploymentSpec:
+		return s.peer.chaincodeSupport.ExecuteSpec(ctxt, cccid, spec)
+	case *pb.ChaincodeInvocationSpec:
+		return s.peer.chaincodeSupport.ExecuteChaincode(ctxt, cccid, spec.ChaincodeSpec.Input.Args)
+	default:
+		return nil, nil, errors.Errorf("unexpected chaincode spec type %T", spec)
+	}
+}
+
+// GetChaincodeDefinition returns the definition of the chaincode recorded in the
+// lifecycle namespace
+func (s *endorserSupport) GetChaincodeDefinition(ctx context.Context, chainID string, txid string, signedProp *pb.SignedProposal, prop *pb.Proposal, chaincodeID string, txsim ledger.TxSimulator) (ccprovider.ChaincodeDefinition, error) {
+	var qe ledger.QueryExecutor = txsim
+	if txsim == nil {
+		queryExecutor, err := s.peer.sccp.GetQueryExecutorForLedger(chainID)
+		if err != nil {
+			return nil, err
+		}
+		defer queryExecutor.Done()
+		qe = queryExecutor
+	}
+	cd, err := getChaincodeData(qe, chaincodeID)
+	if err != nil {
+		return nil, err
+	}
+	return cd, nil
+}
+
+// CheckACL checks that the creator of the proposal satisfies the Writers policy
+// of the application
+func (s *endorserSupport) CheckACL(signedProp *pb.SignedProposal, chdr *cb.ChannelHeader, shdr *cb.SignatureHeader, hdrext *pb.ChaincodeHeaderExtension) error {
+	return s.peer.network.checkProposal(chdr.ChannelId, signedProp)
+}
+
+// IsJavaCC returns true if the deployment spec describes a Java chaincode
+func (s *endorserSupport) IsJavaCC(buf []byte) (bool, error) {
+	cds, err := utils.GetChaincodeDeploymentSpec(buf)
+	if err != nil {
+		return false, err
+	}
+	return cds.ChaincodeSpec.Type == pb.ChaincodeSpec_JAVA, nil
+}
+
+// CheckInstantiationPolicy does nothing, as the chaincodes are not installed
+// as packages; the instantiation policy is enforced by VSCC
+func (s *endorserSupport) CheckInstantiationPolicy(name, version string, cd ccprovider.ChaincodeDefinition) error {
+	return nil
+}
+
+// GetApplicationConfig returns the application config of the channel
+func (s *endorserSupport) GetApplicationConfig(cid string) (channelconfig.Application, bool) {
+	return s.peer.sccp.GetApplicationConfig(cid)
+}
+
+// sccProvider implements sysccprovider.SystemChaincodeProvider for a peer
+type sccProvider struct {
+	peer      *Peer
+	vpmgrOnce sync.Once
+	vpmgr     statebased.KeyLevelValidationParameterManager
+}
+
+// IsSysCC returns true if the supplied chaincode is a system chaincode
+func (c *sccProvider) IsSysCC(name string) bool {
+	return scc.IsSysCC(name)
+}
+
+// IsSysCCAndNotInvokableCC2CC returns true if the supplied chaincode is
+// a system chaincode and it is not invokable through a cc2cc invocation
+func (c *sccProvider) IsSysCCAndNotInvokableCC2CC(name string) bool {
+	return scc.IsSysCCAndNotInvokableCC2CC(name)
+}
+
+// IsSysCCAndNotInvokableExternal returns true if the supplied chaincode is
+// a system chaincode and it is not invokable
+func (c *sccProvider) IsSysCCAndNotInvokableExternal(name string) bool {
+	return scc.IsSysCCAndNotInvokableExternal(name)
+}
+
+// GetQueryExecutorForLedger returns a query executor for the ledger of the channel
+func (c *sccProvider) GetQueryExecutorForLedger(cid string) (ledger.QueryExecutor, error) {
+	lgr, err := c.peer.channelLedger(cid)
+	if err != nil {
+		return nil, err
+	}
+	return lgr.NewQueryExecutor()
+}
+
+// GetApplicationConfig returns the application config of the channel
+func (c *sccProvider) GetApplicationConfig(cid string) (channelconfig.Application, bool) {
+	if cid != c.peer.network.channelID {
+		return nil, false
+	}
+	return c.peer.network.bundle.ApplicationConfig()
+}
+
+// PolicyManager returns the policy manager of the channel
+func (c *sccProvider) PolicyManager(channelID string) (policies.Manager, bool) {
+	if channelID != c.peer.network.channelID {
+		return nil, false
+	}
+	return c.peer.network.bundle.PolicyManager(), true
+}
+
+// GetValidationParameterManager returns the manager of key-level
+// validation parameters of the peer, which is created upon the first call
+func (c *sccProvider) GetValidationParameterManager() statebased.KeyLevelValidationParameterManager {
+	c.vpmgrOnce.Do(func() {
+		c.vpmgr = statebased.NewKeyLevelValidationParameterManager(c)
+	})
+	return c.vpmgr
+}
+
+// validatorSupport implements txvalidator.Support for a peer
+type validatorSupport struct {
+	peer      *Peer
+	semaphore *semaphore.Weighted
+}
+
+// Acquire implements semaphore-like acquire semantics
+func (v *validatorSupport) Acquire(ctx context.Context, n int64) error {
+	return v.semaphore.Acquire(ctx, n)
+}
+
+// Release implements semaphore-like release semantics
+func (v *validatorSupport) Release(n int64) {
+	v.semaphore.Release(n)
+}
+
+// Ledger returns the ledger of the channel on the peer
+func (v *validatorSupport) Ledger() ledger.PeerLedger {
+	return v.peer.ledger
+}
+
+// MSPManager returns the MSP manager of the channel
+func (v *validatorSupport) MSPManager() msp.MSPManager {
+	return v.peer.network.bundle.MSPManager()
+}
+
+// Apply fails, as the channel config cannot be updated
+func (v *validatorSupport) Apply(configtx *cb.ConfigEnvelope) error {
+	return errors.New("config updates are not supported by the in-process network")
+}
+
+// GetMSPIDs returns the IDs of the application MSPs of the channel
+func (v *validatorSupport) GetMSPIDs(cid string) []string {
+	return v.peer.network.mspIDs()
+}
+
+// Capabilities returns the application capabilities of the channel
+func (v *validatorSupport) Capabilities() channelconfig.ApplicationCapabilities {
+	ac, _ := v.peer.network.bundle.ApplicationConfig()
+	return ac.Capabilities()
+}
+
+// aclProvider implements chaincode.ACLProvider by requiring the Writers policy of
+// the application for all the resources
+type aclProvider struct {
+	network *Network
+}
+
+// CheckACL checks that the signed proposal satisfies the Writers policy of the application
+func (a *aclProvider) CheckACL(resName string, channelID string, idinfo interface{}) error {
+	signedProp, ok := idinfo.(*pb.SignedProposal)
+	if !ok {
+		return errors.Errorf("unexpected identity %T for resource %s, expected a signed proposal", idinfo, resName)
+	}
+	return a.network.checkProposal(channelID, signedProp)
+}
+
+// peerVMProvider provides the in-process VMs of a peer. The in-process controller names
+// the chaincode instances after the chaincode name and version only, hence the VMs qualify
+// the version with the network and the peer so that the peers can run the same chaincode.
+type peerVMProvider struct {
+	container.VMProvider
+}
+
+// NewVM returns a VM of the peer
+func (p *peerVMProvider) NewVM() api.VM {
+	return &peerVM{VM: p.VMProvider.NewVM()}
+}
+
+type peerVM struct {
+	api.VM
+}
+
+func (vm *peerVM) Deploy(ctxt context.Context, ccid ccintf.CCID, args []string, env []string, reader io.Reader) error {
+	return vm.VM.Deploy(ctxt, peerCCID(ccid), args, env, reader)
+}
+
+func (vm *peerVM) Start(ctxt context.Context, ccid ccintf.CCID, args []string, env []string, filesToUpload map[string][]byte, builder api.BuildSpecFactory, preLaunchFunc api.PrelaunchFunc) error {
+	return vm.VM.Start(ctxt, peerCCID(ccid), args, env, filesToUpload, builder, preLaunchFunc)
+}
+
+func (vm *peerVM) Stop(ctxt context.Context, ccid ccintf.CCID, timeout uint, dontkill bool, dontremove bool) error {
+	return vm.VM.Stop(ctxt, peerCCID(ccid), timeout, dontkill, dontremove)
+}
+
+func (vm *peerVM) Destroy(ctxt context.Context, ccid ccintf.CCID, force bool, noprune bool) error {
+	return vm.VM.Destroy(ctxt, peerCCID(ccid), force, noprune)
+}
+
+func (vm *peerVM) GetVMName(ccid ccintf.CCID, format func(string) (string, error)) (string, error) {
+	return vm.VM.GetVMName(peerCCID(ccid), format)
+}
+
+func peerCCID(ccid ccintf.CCID) ccintf.CCID {
+	ccid.Version = fmt.Sprintf("%s-%s-%s", ccid.NetworkID, ccid.PeerID, ccid.Version)
+	return ccid
+}
+
+// deployChaincode emulates the deploy function of the lifecycle system chaincode:
+// it records the definition of the chaincode in the lifecycle namespace, in the
+// format that VSCC and the endorsers expect. The endorser then executes the Init
+// function of the chaincode, which DeployChaincode launched beforehand.
+func (n *Network) deployChaincode(ctxt context.Context, spec ccprovider.ChaincodeSpecGetter) (*pb.Response, *pb.ChaincodeEvent, error) {
+	args := spec.GetChaincodeSpec().Input.Args
+	if len(args) < 3 || string(args[0]) != "deploy" {
+		return errorResponse("only the deploy function of the lifecycle is supported"), nil, nil
+	}
+	cds, err := utils.GetChaincodeDeploymentSpec(args[2])
+	if err != nil {
+		return errorResponse(err.Error()), nil, nil
+	}
+	txsim, ok := ctxt.Value(chaincode.TXSimulatorKey).(ledger.TxSimulator)
+	if !ok {
+		return nil, nil, errors.New("no transaction simulator in the context")
+	}
+	name := cds.ChaincodeSpec.ChaincodeId.Name
+	existing, err := txsim.GetState(lifecycleNamespace, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if existing != nil {
+		return errorResponse(fmt.Sprintf("chaincode %s already exists", name)), nil, nil
+	}
+
+	cd := &ccprovider.ChaincodeData{
+		Name:    name,
+		Version: cds.ChaincodeSpec.ChaincodeId.Version,
+		Escc:    "escc",
+		Vscc:    "vscc",
+	}
+	if len(args) > 3 && len(args[3]) > 0 {
+		cd.Policy = args[3]
+	} else {
+		cd.Policy = utils.MarshalOrPanic(cauthdsl.SignedByAnyMember(n.mspIDs()))
+	}
+	if len(args) > 4 && len(args[4]) > 0 {
+		cd.Escc = string(args[4])
+	}
+	if len(args) > 5 && len(args[5]) > 0 {
+		cd.Vscc = string(args[5])
+	}
+	cd.InstantiationPolicy = utils.MarshalOrPanic(cauthdsl.SignedByAnyAdmin(n.mspIDs()))
+
+	cdBytes, err := proto.Marshal(cd)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "error marshaling the chaincode definition")
+	}
+	if err := txsim.SetState(lifecycleNamespace, name, cdBytes); err != nil {
+		return nil, nil, err
+	}
+	resp := shim.Success(cdBytes)
+	return &resp, nil, nil
+}
+
+// getChaincodeData reads the definition of a chaincode from the lifecycle namespace
+func getChaincodeData(qe ledger.QueryExecutor, name string) (*ccprovider.ChaincodeData, error) {
+	cdBytes, err := qe.GetState(lifecycleNamespace, name)
+	if err != nil {
+		return nil, err
+	}
+	if cdBytes == nil {
+		return nil, errors.Errorf("chaincode %s not found", name)
+	}
+	cd := &ccprovider.ChaincodeData{}
+	if err := proto.Unmarshal(cdBytes, cd); err != nil {
+		return nil, errors.Wrapf(err, "error unmarshaling the definition of chaincode %s", name)
+	}
+	return cd, nil
+}
+
+func errorResponse(message string) *pb.Response {
+	resp := shim.Error(message)
+	return &resp
+}
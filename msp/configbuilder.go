@@ -362,6 +362,9 @@ const (
 	IdemixConfigDirUser             = "user"
 	IdemixConfigFileIssuerPublicKey = "IssuerPublicKey"
 	IdemixConfigFileSigner          = "SignerConfig"
+
+	IdemixConfigFileRevocationPublicKey   = "RevocationPublicKey"
+	IdemixConfigFileRevocationInformation = "RevocationInformation"
 )
 
 // GetIdemixMspConfig returns the configuration for the Idemix MSP
@@ -376,6 +379,17 @@ func GetIdemixMspConfig(dir string, ID string) (*msp.MSPConfig, error) {
 		IPk:  ipkBytes,
 	}
 
+	// the revocation public key and the credential revocation
+	// information of the current epoch are optional
+	revocationPkBytes, err := readFile(filepath.Join(dir, IdemixConfigDirMsp, IdemixConfigFileRevocationPublicKey))
+	if err == nil {
+		idemixConfig.RevocationPk = revocationPkBytes
+	}
+	criBytes, err := readFile(filepath.Join(dir, IdemixConfigDirMsp, IdemixConfigFileRevocationInformation))
+	if err == nil {
+		idemixConfig.CredentialRevocationInformation = criBytes
+	}
+
 	signerBytes, err := readFile(filepath.Join(dir, IdemixConfigDirUser, IdemixConfigFileSigner))
 	if err == nil {
 		signerConfig := &msp.IdemixMSPSignerConfig{}
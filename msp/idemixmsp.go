@@ -9,12 +9,13 @@ package msp
 import (
 	"bytes"
 	"encoding/hex"
+	"strings"
 	"time"
 
 	"github.com/golang/protobuf/proto"
-	"github.com/hyperledger/fabric-amcl/amcl"
-	"github.com/hyperledger/fabric-amcl/amcl/FP256BN"
-	"github.com/hyperledger/fabric/idemix"
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/factory"
+	idemixcrypto "github.com/hyperledger/fabric/idemix"
 	m "github.com/hyperledger/fabric/protos/msp"
 	logging "github.com/op/go-logging"
 	"github.com/pkg/errors"
@@ -48,23 +49,31 @@ const (
 	AttributeNameRevocationHandle = "RevocationHandle"
 )
 
-// discloseFlags will be passed to the idemix signing and verification routines.
-// It informs idemix to disclose both attributes (OU and Role) when signing,
-// while hiding attributes EnrollmentID and RevocationHandle.
-var discloseFlags = []byte{1, 1, 0, 0}
-
 type idemixmsp struct {
-	ipk    *idemix.IssuerPublicKey
-	rng    *amcl.RAND
+	csp    bccsp.BCCSP
+	ipk    bccsp.Key
 	signer *idemixSigningIdentity
 	name   string
+	// revocationPK is the long term public key of the revocation authority
+	revocationPK bccsp.Key
+	// cri is the (serialized) credential revocation information of the current epoch,
+	// nil if credentials of this msp are not subject to revocation
+	cri []byte
 }
 
 // newIdemixMsp creates a new instance of idemixmsp
 func newIdemixMsp() (MSP, error) {
 	mspLogger.Debugf("Creating Idemix-based MSP instance")
 
-	msp := idemixmsp{}
+	csp, err := factory.GetBCCSPFromOpts(&factory.FactoryOpts{
+		ProviderName: factory.IdemixFactoryName,
+		IdemixOpts:   &factory.IdemixOpts{Ephemeral: true},
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed initializing the idemix bccsp")
+	}
+
+	msp := idemixmsp{csp: csp}
 	return &msp, nil
 }
 
@@ -88,37 +97,27 @@ func (msp *idemixmsp) Setup(conf1 *m.MSPConfig) error {
 	msp.name = conf.Name
 	mspLogger.Debugf("Setting up Idemix MSP instance %s", msp.name)
 
-	ipk := new(idemix.IssuerPublicKey)
-	err = proto.Unmarshal(conf.IPk, ipk)
+	// Import the issuer public key, checking that it has
+	// attributes OU, Role, EnrollmentId, and RevocationHandle
+	ipk, err := msp.csp.KeyImport(conf.IPk, &bccsp.IdemixIssuerPublicKeyImportOpts{
+		Temporary: true,
+		AttributeNames: []string{
+			AttributeNameOU,
+			AttributeNameRole,
+			AttributeNameEnrollmentId,
+			AttributeNameRevocationHandle,
+		},
+	})
 	if err != nil {
-		return errors.Wrap(err, "failed to unmarshal ipk from idemix msp config")
-	}
-	err = ipk.SetHash()
-	if err != nil {
-		return errors.WithMessage(err, "setting the hash of the issuer public key failed")
-	}
-
-	if len(ipk.AttributeNames) < 4 ||
-		ipk.AttributeNames[AttributeIndexOU] != AttributeNameOU ||
-		ipk.AttributeNames[AttributeIndexRole] != AttributeNameRole ||
-		ipk.AttributeNames[AttributeIndexEnrollmentId] != AttributeNameEnrollmentId ||
-		ipk.AttributeNames[AttributeIndexRevocationHandle] != AttributeNameRevocationHandle {
-		return errors.Errorf("issuer public key must have have attributes OU, Role, EnrollmentId, and RevocationHandle")
-	}
-
-	err = ipk.Check()
-	if err != nil {
-		return errors.WithMessage(err, "cannot setup idemix msp with invalid public key")
+		return errors.WithMessage(err, "cannot setup idemix msp with invalid issuer public key")
 	}
 	msp.ipk = ipk
 
-	rng, err := idemix.GetRand()
+	err = msp.setupRevocation(&conf)
 	if err != nil {
-		return errors.Wrap(err, "error initializing PRNG for idemix msp")
+		return err
 	}
 
-	msp.rng = rng
-
 	if conf.Signer == nil {
 		// No credential in config, so we don't setup a default signer
 		mspLogger.Debug("idemix msp setup as verification only msp (no key material found)")
@@ -126,15 +125,11 @@ func (msp *idemixmsp) Setup(conf1 *m.MSPConfig) error {
 	}
 
 	// A credential is present in the config, so we setup a default signer
-	cred := new(idemix.Credential)
-	err = proto.Unmarshal(conf.Signer.Cred, cred)
+	userKey, err := msp.csp.KeyImport(conf.Signer.Sk, &bccsp.IdemixUserSecretKeyImportOpts{Temporary: true})
 	if err != nil {
-		return errors.Wrap(err, "Failed to unmarshal credential from config")
+		return errors.WithMessage(err, "failed importing the user secret key")
 	}
 
-	sk := FP256BN.FromBytes(conf.Signer.Sk)
-
-	Nym, RandNym := idemix.MakeNym(sk, msp.ipk, rng)
 	role := &m.MSPRole{
 		MspIdentifier: msp.name,
 		Role:          m.MSPRole_MEMBER,
@@ -146,47 +141,180 @@ func (msp *idemixmsp) Setup(conf1 *m.MSPConfig) error {
 	ou := &m.OrganizationUnit{
 		MspIdentifier:                msp.name,
 		OrganizationalUnitIdentifier: conf.Signer.OrganizationalUnitIdentifier,
-		CertifiersIdentifier:         ipk.Hash,
+		CertifiersIdentifier:         ipk.SKI(),
 	}
 
 	enrollmentId := conf.Signer.EnrollmentId
 
-	// Check if credential contains the right amount of attribute values (Role, OU, EnrollmentId, RevocationHandle)
-	if len(cred.Attrs) != 4 {
-		return errors.Errorf("Credential contains %d attribute values, but expected 4", len(cred.Attrs))
+	// The attributes the default signer proves to satisfy beyond its OU and role
+	var claims *m.IdemixAttributes
+	if len(conf.Signer.Attributes) != 0 {
+		claims = &m.IdemixAttributes{}
+		err = proto.Unmarshal(conf.Signer.Attributes, claims)
+		if err != nil {
+			return errors.Wrap(err, "failed unmarshalling the attributes of the default signer")
+		}
 	}
-
-	// Check if credential contains the correct OU attribute value
-	ouBytes := []byte(conf.Signer.OrganizationalUnitIdentifier)
-	if !bytes.Equal(idemix.BigToBytes(idemix.HashModOrder(ouBytes)), cred.Attrs[AttributeIndexOU]) {
-		return errors.New("Credential does not contain the correct OU attribute value")
+	attributes, err := proofAttributes(ou, role, claims)
+	if err != nil {
+		return errors.WithMessage(err, "invalid attributes of the default signer")
+	}
+
+	// Verify that the credential is cryptographically valid and that it
+	// contains the right attribute values (OU, Role, EnrollmentId, RevocationHandle)
+	_, err = msp.csp.Verify(userKey, conf.Signer.Cred, nil, &bccsp.IdemixCredentialSignerOpts{
+		IssuerPK: ipk,
+		Attributes: []bccsp.IdemixAttribute{
+			{Type: bccsp.IdemixBytesAttribute, Value: []byte(conf.Signer.OrganizationalUnitIdentifier)},
+			{Type: bccsp.IdemixIntAttribute, Value: int(role.Role)},
+			{Type: bccsp.IdemixBytesAttribute, Value: []byte(enrollmentId)},
+			{Type: bccsp.IdemixHiddenAttribute},
+		},
+	})
+	if err != nil {
+		return errors.WithMessage(err, "Credential is not cryptographically valid")
 	}
 
-	// Check if credential contains the correct Role attribute value
-	if !bytes.Equal(idemix.BigToBytes(FP256BN.NewBIGint(int(role.Role))), cred.Attrs[AttributeIndexRole]) {
-		return errors.New("Credential does not contain the correct Role attribute value")
+	// Create a fresh pseudonym for the default signer
+	nym, err := msp.csp.KeyDeriv(userKey, &bccsp.IdemixNymKeyDerivationOpts{Temporary: true, IssuerPK: ipk})
+	if err != nil {
+		return errors.WithMessage(err, "Failed deriving nym")
+	}
+	nymPublicKey, err := nym.PublicKey()
+	if err != nil {
+		return errors.Wrap(err, "Failed getting public nym key")
 	}
 
-	// Check if credential contains the correct Enrollment ID attribute value
-	if !bytes.Equal(idemix.BigToBytes(idemix.HashModOrder([]byte(enrollmentId))), cred.Attrs[AttributeIndexEnrollmentId]) {
-		return errors.New("Credential does not contain the correct enrollment id attribute value")
+	// Create the cryptographic evidence that this identity is valid
+	proof, err := msp.csp.Sign(userKey, nil, &bccsp.IdemixSignerOpts{
+		Credential: conf.Signer.Cred,
+		Nym:        nym,
+		IssuerPK:   ipk,
+		Attributes: attributes,
+		RhIndex:    AttributeIndexRevocationHandle,
+		CRI:        msp.cri,
+	})
+	if err != nil {
+		return errors.WithMessage(err, "Failed to setup cryptographic proof of identity")
 	}
 
-	// Verify that the credential is cryptographically valid
-	err = cred.Ver(sk, msp.ipk)
+	// Set up default signer
+	id, err := newIdemixIdentity(msp, nymPublicKey, role, ou, claims, proof)
 	if err != nil {
-		return errors.Wrap(err, "Credential is not cryptographically valid")
+		return err
 	}
 
-	// Create the cryptographic evidence that this identity is valid
-	proof, err := idemix.NewSignature(cred, sk, Nym, RandNym, ipk, discloseFlags, nil, rng)
+	// Disclosed attributes are not checked when creating the proof,
+	// so we make sure the claims of the default signer hold
+	if claims != nil {
+		err = id.verifyProof()
+		if err != nil {
+			return errors.WithMessage(err, "the attributes of the default signer do not match its credential")
+		}
+	}
+
+	msp.signer = &idemixSigningIdentity{
+		idemixidentity: id,
+		Cred:           conf.Signer.Cred,
+		UserKey:        userKey,
+		NymKey:         nym,
+		enrollmentId:   enrollmentId,
+	}
+
+	return nil
+}
+
+// setupRevocation sets up the revocation public key and the
+// credential revocation information of the current epoch
+func (msp *idemixmsp) setupRevocation(conf *m.IdemixMSPConfig) error {
+	if len(conf.RevocationPk) != 0 {
+		revocationPK, err := msp.csp.KeyImport(conf.RevocationPk, &bccsp.IdemixRevocationPublicKeyImportOpts{Temporary: true})
+		if err != nil {
+			return errors.WithMessage(err, "failed importing revocation public key")
+		}
+		msp.revocationPK = revocationPK
+	}
+
+	if len(conf.CredentialRevocationInformation) == 0 {
+		mspLogger.Debugf("idemix msp %s has no credential revocation information, credentials are not subject to revocation", msp.name)
+		return nil
+	}
+	if msp.revocationPK == nil {
+		return errors.Errorf("credential revocation information requires a revocation public key")
+	}
+
+	cri := &idemixcrypto.CredentialRevocationInformation{}
+	err := proto.Unmarshal(conf.CredentialRevocationInformation, cri)
 	if err != nil {
-		return errors.Wrap(err, "Failed to setup cryptographic proof of identity")
+		return errors.Wrap(err, "failed to unmarshal credential revocation information")
 	}
+	_, err = msp.csp.Verify(msp.revocationPK, conf.CredentialRevocationInformation, nil, &bccsp.IdemixCRISignerOpts{
+		Epoch:               cri.Epoch,
+		RevocationAlgorithm: bccsp.RevocationAlgorithm(cri.RevocationAlg),
+	})
+	if err != nil {
+		return errors.WithMessage(err, "credential revocation information is not valid")
+	}
+	mspLogger.Debugf("idemix msp %s is in revocation epoch %d", msp.name, cri.Epoch)
+	msp.cri = conf.CredentialRevocationInformation
+	return nil
+}
 
-	// Set up default signer
-	msp.signer = &idemixSigningIdentity{newIdemixIdentity(msp, Nym, role, ou, proof), rng, cred, sk, RandNym, enrollmentId}
+// proofAttributes returns the attributes of the proof of an identity:
+// the proof discloses both attributes OU and Role, and hides attribute
+// RevocationHandle. Attribute EnrollmentID is hidden unless claims contain
+// a predicate on it: it is disclosed for an EQUAL predicate, and proven to
+// be one of the values of a MEMBER predicate.
+func proofAttributes(ou *m.OrganizationUnit, role *m.MSPRole, claims *m.IdemixAttributes) ([]bccsp.IdemixAttribute, error) {
+	attributes := []bccsp.IdemixAttribute{
+		{Type: bccsp.IdemixBytesAttribute, Value: []byte(ou.OrganizationalUnitIdentifier)},
+		{Type: bccsp.IdemixIntAttribute, Value: int(role.Role)},
+		{Type: bccsp.IdemixHiddenAttribute},
+		{Type: bccsp.IdemixHiddenAttribute},
+	}
+
+	for _, predicate := range claims.GetPredicates() {
+		if predicate.AttributeName != AttributeNameEnrollmentId {
+			return nil, errors.Errorf("attribute %s cannot be claimed", predicate.AttributeName)
+		}
+		if attributes[AttributeIndexEnrollmentId].Type != bccsp.IdemixHiddenAttribute {
+			return nil, errors.Errorf("attribute %s is claimed more than once", predicate.AttributeName)
+		}
+		err := validatePredicate(predicate)
+		if err != nil {
+			return nil, err
+		}
 
+		switch predicate.Type {
+		case m.IdemixAttributePredicate_EQUAL:
+			attributes[AttributeIndexEnrollmentId] = bccsp.IdemixAttribute{Type: bccsp.IdemixBytesAttribute, Value: []byte(predicate.Values[0])}
+		case m.IdemixAttributePredicate_MEMBER:
+			values := make([]bccsp.IdemixAttribute, len(predicate.Values))
+			for i, value := range predicate.Values {
+				values[i] = bccsp.IdemixAttribute{Type: bccsp.IdemixBytesAttribute, Value: []byte(value)}
+			}
+			attributes[AttributeIndexEnrollmentId] = bccsp.IdemixAttribute{Type: bccsp.IdemixMembershipAttribute, Value: values}
+		}
+	}
+
+	return attributes, nil
+}
+
+// validatePredicate checks that an EQUAL predicate has exactly one value
+// and that a MEMBER predicate has at least one value
+func validatePredicate(predicate *m.IdemixAttributePredicate) error {
+	switch predicate.Type {
+	case m.IdemixAttributePredicate_EQUAL:
+		if len(predicate.Values) != 1 {
+			return errors.Errorf("predicate on attribute %s must have exactly one value, got %d", predicate.AttributeName, len(predicate.Values))
+		}
+	case m.IdemixAttributePredicate_MEMBER:
+		if len(predicate.Values) == 0 {
+			return errors.Errorf("predicate on attribute %s must have at least one value", predicate.AttributeName)
+		}
+	default:
+		return errors.Errorf("invalid predicate type %d", int32(predicate.Type))
+	}
 	return nil
 }
 
@@ -240,7 +368,11 @@ func (msp *idemixmsp) deserializeIdentityInternal(serializedID []byte) (Identity
 	if serialized.NymX == nil || serialized.NymY == nil {
 		return nil, errors.Errorf("unable to deserialize idemix identity: pseudonym is invalid")
 	}
-	Nym := FP256BN.NewECPbigs(FP256BN.FromBytes(serialized.NymX), FP256BN.FromBytes(serialized.NymY))
+	rawNymPublicKey := append(serialized.NymX, serialized.NymY...)
+	nymPublicKey, err := msp.csp.KeyImport(rawNymPublicKey, &bccsp.IdemixNymPublicKeyImportOpts{Temporary: true})
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to import nym public key")
+	}
 
 	ou := &m.OrganizationUnit{}
 	err = proto.Unmarshal(serialized.OU, ou)
@@ -253,27 +385,24 @@ func (msp *idemixmsp) deserializeIdentityInternal(serializedID []byte) (Identity
 		return nil, errors.Wrap(err, "cannot deserialize the role of the identity")
 	}
 
-	proof := &idemix.Signature{}
-	err = proto.Unmarshal(serialized.Proof, proof)
-	if err != nil {
-		return nil, errors.Wrap(err, "cannot deserialize the proof of the identity")
+	var claims *m.IdemixAttributes
+	if len(serialized.Attributes) != 0 {
+		claims = &m.IdemixAttributes{}
+		err = proto.Unmarshal(serialized.Attributes, claims)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot deserialize the attributes of the identity")
+		}
 	}
 
-	return newIdemixIdentity(msp, Nym, role, ou, proof), nil
-
+	return newIdemixIdentity(msp, nymPublicKey, role, ou, claims, serialized.Proof)
 }
 
 func (msp *idemixmsp) Validate(id Identity) error {
 	mspLogger.Infof("Validating identity %s", id)
 
-	var identity *idemixidentity
-	switch t := id.(type) {
-	case *idemixidentity:
-		identity = id.(*idemixidentity)
-	case *idemixSigningIdentity:
-		identity = id.(*idemixSigningIdentity).idemixidentity
-	default:
-		return errors.Errorf("identity type %T is not recognized", t)
+	identity, err := toIdemixIdentity(id)
+	if err != nil {
+		return err
 	}
 	if identity.GetMSPIdentifier() != msp.name {
 		return errors.Errorf("the supplied identity does not belong to this msp")
@@ -281,11 +410,33 @@ func (msp *idemixmsp) Validate(id Identity) error {
 	return identity.verifyProof()
 }
 
-func (id *idemixidentity) verifyProof() error {
-	ouBytes := []byte(id.OU.OrganizationalUnitIdentifier)
-	attributeValues := []*FP256BN.BIG{idemix.HashModOrder(ouBytes), FP256BN.NewBIGint(int(id.Role.Role))}
+// toIdemixIdentity returns the idemix identity underlying id
+func toIdemixIdentity(id Identity) (*idemixidentity, error) {
+	switch t := id.(type) {
+	case *idemixidentity:
+		return t, nil
+	case *idemixSigningIdentity:
+		return t.idemixidentity, nil
+	default:
+		return nil, errors.Errorf("identity type %T is not recognized", t)
+	}
+}
 
-	return id.associationProof.Ver(discloseFlags, id.msp.ipk, nil, attributeValues)
+func (id *idemixidentity) verifyProof() error {
+	attributes, err := proofAttributes(id.OU, id.Role, id.Attributes)
+	if err != nil {
+		return errors.WithMessage(err, "invalid attributes")
+	}
+	valid, err := id.msp.csp.Verify(id.msp.ipk, id.associationProof, nil, &bccsp.IdemixSignerOpts{
+		IssuerPK:   id.msp.ipk,
+		Attributes: attributes,
+		RhIndex:    AttributeIndexRevocationHandle,
+		CRI:        id.msp.cri,
+	})
+	if err == nil && !valid {
+		return errors.Errorf("unexpected condition, an error should be returned for an invalid proof")
+	}
+	return err
 }
 
 func (msp *idemixmsp) SatisfiesPrincipal(id Identity, principal *m.MSPPrincipal) error {
@@ -368,6 +519,39 @@ func (msp *idemixmsp) SatisfiesPrincipal(id Identity, principal *m.MSPPrincipal)
 			return errors.Errorf("user is not part of the desired organizational unit")
 		}
 
+		return nil
+	case m.MSPPrincipal_IDEMIX_ATTRIBUTES:
+		attributes := &m.IdemixAttributes{}
+		err := proto.Unmarshal(principal.Principal, attributes)
+		if err != nil {
+			return errors.Wrap(err, "could not unmarshal IdemixAttributes from principal")
+		}
+
+		mspLogger.Debugf("Checking if identity satisfies %d attribute predicates of mspid \"%s\"", len(attributes.Predicates), attributes.MspIdentifier)
+
+		// at first, we check whether the MSP
+		// identifier is the same as that of the identity
+		if attributes.MspIdentifier != msp.name {
+			return errors.Errorf("the identity is a member of a different MSP (expected %s, got %s)", attributes.MspIdentifier, id.GetMSPIdentifier())
+		}
+
+		// we then check if the identity is valid with this MSP,
+		// which also verifies the attributes it claims
+		err = msp.Validate(id)
+		if err != nil {
+			return err
+		}
+
+		identity, err := toIdemixIdentity(id)
+		if err != nil {
+			return err
+		}
+		for _, predicate := range attributes.Predicates {
+			err = identity.satisfiesPredicate(predicate)
+			if err != nil {
+				return err
+			}
+		}
 		return nil
 	default:
 		return errors.Errorf("invalid principal type %d", int32(principal.PrincipalClassification))
@@ -397,31 +581,95 @@ func (msp *idemixmsp) GetTLSIntermediateCerts() [][]byte {
 }
 
 type idemixidentity struct {
-	Nym  *FP256BN.ECP
-	msp  *idemixmsp
-	id   *IdentityIdentifier
-	Role *m.MSPRole
-	OU   *m.OrganizationUnit
+	NymPublicKey bccsp.Key
+	msp          *idemixmsp
+	id           *IdentityIdentifier
+	Role         *m.MSPRole
+	OU           *m.OrganizationUnit
+	// Attributes contains the predicates over further attributes
+	// this identity proves to satisfy, nil if there are none
+	Attributes *m.IdemixAttributes
 	// associationProof contains cryptographic proof that this identity
 	// belongs to the MSP id.msp, i.e., it proves that the pseudonym
 	// is constructed from a secret key on which the CA issued a credential.
-	associationProof *idemix.Signature
+	associationProof []byte
 }
 
-func newIdemixIdentity(msp *idemixmsp, nym *FP256BN.ECP, role *m.MSPRole, ou *m.OrganizationUnit, proof *idemix.Signature) *idemixidentity {
+func newIdemixIdentity(msp *idemixmsp, nymPublicKey bccsp.Key, role *m.MSPRole, ou *m.OrganizationUnit, attributes *m.IdemixAttributes, proof []byte) (*idemixidentity, error) {
+	x, y, err := nymCoordinates(nymPublicKey)
+	if err != nil {
+		return nil, err
+	}
+
 	id := &idemixidentity{}
-	id.Nym = nym
+	id.NymPublicKey = nymPublicKey
 	id.msp = msp
-	id.id = &IdentityIdentifier{Mspid: msp.name, Id: proto.MarshalTextString(idemix.EcpToProto(nym))}
+	id.id = &IdentityIdentifier{Mspid: msp.name, Id: proto.MarshalTextString(&idemixcrypto.ECP{X: x, Y: y})}
 	id.Role = role
 	id.OU = ou
+	id.Attributes = attributes
 	id.associationProof = proof
-	return id
+	return id, nil
+}
+
+// satisfiesPredicate checks that the attribute values this identity proves
+// to hold are all in the set of values allowed by predicate. Attributes OU and
+// Role are always disclosed, roles are compared with the names of MSPRole types.
+func (id *idemixidentity) satisfiesPredicate(predicate *m.IdemixAttributePredicate) error {
+	err := validatePredicate(predicate)
+	if err != nil {
+		return err
+	}
+
+	var proven []string
+	equal := func(a, b string) bool { return a == b }
+	switch predicate.AttributeName {
+	case AttributeNameOU:
+		proven = []string{id.OU.OrganizationalUnitIdentifier}
+	case AttributeNameRole:
+		proven = []string{id.Role.Role.String()}
+		equal = strings.EqualFold
+	case AttributeNameEnrollmentId:
+		for _, claim := range id.Attributes.GetPredicates() {
+			if claim.AttributeName == predicate.AttributeName {
+				proven = claim.Values
+			}
+		}
+		if len(proven) == 0 {
+			return errors.Errorf("the identity does not prove any predicate on attribute %s", predicate.AttributeName)
+		}
+	default:
+		return errors.Errorf("attribute %s cannot be used in a principal", predicate.AttributeName)
+	}
+
+	for _, value := range proven {
+		allowed := false
+		for _, v := range predicate.Values {
+			if equal(value, v) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return errors.Errorf("the identity does not satisfy the predicate on attribute %s", predicate.AttributeName)
+		}
+	}
+	return nil
+}
+
+// nymCoordinates returns the coordinates of the pseudonym
+// from the byte representation of its public key
+func nymCoordinates(nymPublicKey bccsp.Key) ([]byte, []byte, error) {
+	raw, err := nymPublicKey.Bytes()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not marshal nym public key")
+	}
+	return raw[:len(raw)/2], raw[len(raw)/2:], nil
 }
 
 func (id *idemixidentity) ExpiresAt() time.Time {
-	// Idemix MSP currently does not use expiration dates or revocation,
-	// so we return the zero time to indicate this.
+	// Idemix MSP currently does not use expiration dates (revocation
+	// is handled per epoch), so we return the zero time to indicate this.
 	return time.Time{}
 }
 
@@ -436,7 +684,7 @@ func (id *idemixidentity) GetMSPIdentifier() string {
 
 func (id *idemixidentity) GetOrganizationalUnits() []*OUIdentifier {
 	// we use the (serialized) public key of this MSP as the CertifiersIdentifier
-	certifiersIdentifier, err := proto.Marshal(id.msp.ipk)
+	certifiersIdentifier, err := id.msp.ipk.Bytes()
 	if err != nil {
 		mspIdentityLogger.Errorf("Failed to marshal ipk in GetOrganizationalUnits: %s", err)
 		return nil
@@ -454,13 +702,8 @@ func (id *idemixidentity) Verify(msg []byte, sig []byte) error {
 		mspIdentityLogger.Debugf("Verify Idemix sig: sig = %s", hex.Dump(sig))
 	}
 
-	signature := new(idemix.NymSignature)
-	err := proto.Unmarshal(sig, signature)
-	if err != nil {
-		return errors.Wrap(err, "error unmarshalling signature")
-	}
-
-	return signature.Ver(id.Nym, id.msp.ipk, msg)
+	_, err := id.msp.csp.Verify(id.NymPublicKey, sig, msg, &bccsp.IdemixNymSignerOpts{IssuerPK: id.msp.ipk})
+	return err
 }
 
 func (id *idemixidentity) SatisfiesPrincipal(principal *m.MSPPrincipal) error {
@@ -468,9 +711,12 @@ func (id *idemixidentity) SatisfiesPrincipal(principal *m.MSPPrincipal) error {
 }
 
 func (id *idemixidentity) Serialize() ([]byte, error) {
+	var err error
 	serialized := &m.SerializedIdemixIdentity{}
-	serialized.NymX = idemix.BigToBytes(id.Nym.GetX())
-	serialized.NymY = idemix.BigToBytes(id.Nym.GetY())
+	serialized.NymX, serialized.NymY, err = nymCoordinates(id.NymPublicKey)
+	if err != nil {
+		return nil, err
+	}
 	ouBytes, err := proto.Marshal(id.OU)
 	if err != nil {
 		return nil, errors.Wrapf(err, "could not marshal OU of identity %s", id.id)
@@ -484,9 +730,13 @@ func (id *idemixidentity) Serialize() ([]byte, error) {
 	serialized.OU = ouBytes
 	serialized.Role = roleBytes
 
-	serialized.Proof, err = proto.Marshal(id.associationProof)
-	if err != nil {
-		return nil, err
+	serialized.Proof = id.associationProof
+
+	if len(id.Attributes.GetPredicates()) != 0 {
+		serialized.Attributes, err = proto.Marshal(id.Attributes)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not marshal attributes of identity %s", id.id)
+		}
 	}
 
 	idemixIDBytes, err := proto.Marshal(serialized)
@@ -505,20 +755,15 @@ func (id *idemixidentity) Serialize() ([]byte, error) {
 
 type idemixSigningIdentity struct {
 	*idemixidentity
-	rng          *amcl.RAND
-	Cred         *idemix.Credential
-	Sk           *FP256BN.BIG
-	RandNym      *FP256BN.BIG
+	Cred         []byte
+	UserKey      bccsp.Key
+	NymKey       bccsp.Key
 	enrollmentId string
 }
 
 func (id *idemixSigningIdentity) Sign(msg []byte) ([]byte, error) {
 	mspLogger.Debugf("Idemix identity %s is signing", id.GetIdentifier())
-	sig, err := idemix.NewNymSignature(id.Sk, id.Nym, id.RandNym, id.msp.ipk, msg, id.rng)
-	if err != nil {
-		return nil, err
-	}
-	return proto.Marshal(sig)
+	return id.msp.csp.Sign(id.UserKey, msg, &bccsp.IdemixNymSignerOpts{Nym: id.NymKey, IssuerPK: id.msp.ipk})
 }
 
 func (id *idemixSigningIdentity) GetPublicVersion() Identity {
@@ -7,9 +7,13 @@ SPDX-License-Identifier: Apache-2.0
 package msp
 
 import (
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/pem"
 	"testing"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-amcl/amcl/FP256BN"
 	"github.com/hyperledger/fabric/idemix"
 	"github.com/hyperledger/fabric/protos/msp"
 	"github.com/pkg/errors"
@@ -34,6 +38,63 @@ func setup(configPath string, ID string) (MSP, error) {
 	return msp, nil
 }
 
+// setupWithRevocation sets up an idemix msp from the config in configPath,
+// extended with the revocation public key of key and the given revocation information
+func setupWithRevocation(configPath string, ID string, key *ecdsa.PrivateKey, cri *idemix.CredentialRevocationInformation) (MSP, error) {
+	conf, err := GetIdemixMspConfig(configPath, ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "Getting MSP config failed")
+	}
+	idemixConfig := &msp.IdemixMSPConfig{}
+	err = proto.Unmarshal(conf.Config, idemixConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	pkBytes, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	idemixConfig.RevocationPk = pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pkBytes})
+	idemixConfig.CredentialRevocationInformation, err = proto.Marshal(cri)
+	if err != nil {
+		return nil, err
+	}
+	conf.Config, err = proto.Marshal(idemixConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	msp, err := newIdemixMsp()
+	if err != nil {
+		return nil, err
+	}
+	err = msp.Setup(conf)
+	if err != nil {
+		return nil, errors.Wrap(err, "Setting up MSP failed")
+	}
+	return msp, nil
+}
+
+// getRevocationHandle returns the revocation handle of the default signer in configPath
+func getRevocationHandle(configPath string) (*FP256BN.BIG, error) {
+	conf, err := GetIdemixMspConfig(configPath, "MSPID")
+	if err != nil {
+		return nil, err
+	}
+	idemixConfig := &msp.IdemixMSPConfig{}
+	err = proto.Unmarshal(conf.Config, idemixConfig)
+	if err != nil {
+		return nil, err
+	}
+	cred := &idemix.Credential{}
+	err = proto.Unmarshal(idemixConfig.Signer.Cred, cred)
+	if err != nil {
+		return nil, err
+	}
+	return FP256BN.FromBytes(cred.Attrs[AttributeIndexRevocationHandle]), nil
+}
+
 func getDefaultSigner(msp MSP) (SigningIdentity, error) {
 	id, err := msp.GetDefaultSigningIdentity()
 	if err != nil {
@@ -139,6 +200,83 @@ func TestSigning(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestIdemixRevocation(t *testing.T) {
+	rng, err := idemix.GetRand()
+	assert.NoError(t, err)
+	key, err := idemix.GenerateLongTermRevocationKey()
+	assert.NoError(t, err)
+	rh, err := getRevocationHandle("testdata/idemix/MSP1OU1")
+	assert.NoError(t, err)
+
+	// The default signer is not revoked in epoch 1
+	cri, err := idemix.CreateCRI(key, []*FP256BN.BIG{rh}, 1, idemix.ALG_WEAK_BB, rng)
+	assert.NoError(t, err)
+	msp1, err := setupWithRevocation("testdata/idemix/MSP1OU1", "MSP1", key, cri)
+	assert.NoError(t, err)
+	id, err := getDefaultSigner(msp1)
+	assert.NoError(t, err)
+
+	idBytes, err := id.Serialize()
+	assert.NoError(t, err)
+
+	verMsp, err := setupWithRevocation("testdata/idemix/MSP1Verifier", "MSP1", key, cri)
+	assert.NoError(t, err)
+	verID, err := verMsp.DeserializeIdentity(idBytes)
+	assert.NoError(t, err)
+	assert.NoError(t, verMsp.Validate(verID))
+
+	// A verifier that is not aware of revocation rejects the identity
+	noRevMsp, err := setup("testdata/idemix/MSP1Verifier", "MSP1")
+	assert.NoError(t, err)
+	verID, err = noRevMsp.DeserializeIdentity(idBytes)
+	assert.NoError(t, err)
+	assert.Error(t, noRevMsp.Validate(verID))
+
+	// The default signer is revoked in epoch 2
+	cri2, err := idemix.CreateCRI(key, []*FP256BN.BIG{FP256BN.NewBIGint(1234)}, 2, idemix.ALG_WEAK_BB, rng)
+	assert.NoError(t, err)
+	verMsp, err = setupWithRevocation("testdata/idemix/MSP1Verifier", "MSP1", key, cri2)
+	assert.NoError(t, err)
+	verID, err = verMsp.DeserializeIdentity(idBytes)
+	assert.NoError(t, err)
+	err = verMsp.Validate(verID)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "signature is for epoch 1, but the current epoch is 2")
+
+	_, err = setupWithRevocation("testdata/idemix/MSP1OU1", "MSP1", key, cri2)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the revocation handle is revoked in epoch 2")
+
+	// Revocation information that is not certified by the revocation authority is rejected
+	otherKey, err := idemix.GenerateLongTermRevocationKey()
+	assert.NoError(t, err)
+	cri3, err := idemix.CreateCRI(otherKey, []*FP256BN.BIG{rh}, 3, idemix.ALG_WEAK_BB, rng)
+	assert.NoError(t, err)
+	cri3.EpochPkSig = cri.EpochPkSig
+	_, err = setupWithRevocation("testdata/idemix/MSP1Verifier", "MSP1", key, cri3)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "credential revocation information is not valid")
+
+	// Revocation information without a revocation public key is rejected
+	conf, err := GetIdemixMspConfig("testdata/idemix/MSP1Verifier", "MSP1")
+	assert.NoError(t, err)
+	idemixConfig := &msp.IdemixMSPConfig{}
+	assert.NoError(t, proto.Unmarshal(conf.Config, idemixConfig))
+	idemixConfig.CredentialRevocationInformation, err = proto.Marshal(cri)
+	assert.NoError(t, err)
+	conf.Config, err = proto.Marshal(idemixConfig)
+	assert.NoError(t, err)
+	msp2, err := newIdemixMsp()
+	assert.NoError(t, err)
+	assert.EqualError(t, msp2.Setup(conf), "credential revocation information requires a revocation public key")
+
+	// A malformed revocation public key is rejected
+	idemixConfig.RevocationPk = []byte("barf")
+	conf.Config, err = proto.Marshal(idemixConfig)
+	assert.NoError(t, err)
+	assert.Error(t, msp2.Setup(conf))
+}
+
 func TestSigningBad(t *testing.T) {
 	msp, err := setup("testdata/idemix/MSP1OU1", "MSP1OU1")
 	assert.NoError(t, err)
@@ -478,3 +616,189 @@ func TestPrincipalBad(t *testing.T) {
 	err = id1.SatisfiesPrincipal(principal)
 	assert.Error(t, err, "Principal with bad Classification should fail")
 }
+
+// setupWithAttributes sets up an idemix msp from the config in configPath,
+// whose default signer proves to satisfy the given attribute predicates
+func setupWithAttributes(configPath string, ID string, predicates ...*msp.IdemixAttributePredicate) (MSP, error) {
+	conf, err := GetIdemixMspConfig(configPath, ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "Getting MSP config failed")
+	}
+	idemixConfig := &msp.IdemixMSPConfig{}
+	err = proto.Unmarshal(conf.Config, idemixConfig)
+	if err != nil {
+		return nil, err
+	}
+	idemixConfig.Signer.Attributes, err = proto.Marshal(&msp.IdemixAttributes{Predicates: predicates})
+	if err != nil {
+		return nil, err
+	}
+	conf.Config, err = proto.Marshal(idemixConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	msp, err := newIdemixMsp()
+	if err != nil {
+		return nil, err
+	}
+	err = msp.Setup(conf)
+	if err != nil {
+		return nil, errors.Wrap(err, "Setting up MSP failed")
+	}
+	return msp, nil
+}
+
+func idemixAttributesPrincipal(t *testing.T, mspID string, predicates ...*msp.IdemixAttributePredicate) *msp.MSPPrincipal {
+	bytes, err := proto.Marshal(&msp.IdemixAttributes{MspIdentifier: mspID, Predicates: predicates})
+	assert.NoError(t, err)
+	return &msp.MSPPrincipal{
+		PrincipalClassification: msp.MSPPrincipal_IDEMIX_ATTRIBUTES,
+		Principal:               bytes}
+}
+
+func TestPrincipalIdemixAttributes(t *testing.T) {
+	msp1, err := setupWithAttributes("testdata/idemix/MSP1OU1", "MSP1OU1", &msp.IdemixAttributePredicate{
+		AttributeName: AttributeNameEnrollmentId,
+		Type:          msp.IdemixAttributePredicate_MEMBER,
+		Values:        []string{"otherid", "testid"},
+	})
+	assert.NoError(t, err)
+
+	id1, err := getDefaultSigner(msp1)
+	assert.NoError(t, err)
+
+	// the claimed attributes survive serialization
+	serialized, err := id1.Serialize()
+	assert.NoError(t, err)
+	id2, err := msp1.DeserializeIdentity(serialized)
+	assert.NoError(t, err)
+	assert.NoError(t, msp1.Validate(id2))
+
+	member := &msp.IdemixAttributePredicate{
+		AttributeName: AttributeNameEnrollmentId,
+		Type:          msp.IdemixAttributePredicate_MEMBER,
+		Values:        []string{"testid", "thirdid", "otherid"},
+	}
+	ouAndRole := []*msp.IdemixAttributePredicate{
+		{AttributeName: AttributeNameOU, Type: msp.IdemixAttributePredicate_EQUAL, Values: []string{"OU1"}},
+		{AttributeName: AttributeNameRole, Type: msp.IdemixAttributePredicate_MEMBER, Values: []string{"admin", "member"}},
+	}
+	for _, id := range []Identity{id1, id2} {
+		assert.NoError(t, id.SatisfiesPrincipal(idemixAttributesPrincipal(t, "MSP1OU1", member)))
+		assert.NoError(t, id.SatisfiesPrincipal(idemixAttributesPrincipal(t, "MSP1OU1", ouAndRole...)))
+		assert.NoError(t, id.SatisfiesPrincipal(idemixAttributesPrincipal(t, "MSP1OU1")))
+
+		// the identity only proves that its enrollment id is one of two values
+		err = id.SatisfiesPrincipal(idemixAttributesPrincipal(t, "MSP1OU1", &msp.IdemixAttributePredicate{
+			AttributeName: AttributeNameEnrollmentId,
+			Type:          msp.IdemixAttributePredicate_EQUAL,
+			Values:        []string{"testid"},
+		}))
+		assert.EqualError(t, err, "the identity does not satisfy the predicate on attribute EnrollmentID")
+
+		err = id.SatisfiesPrincipal(idemixAttributesPrincipal(t, "MSP1OU1", &msp.IdemixAttributePredicate{
+			AttributeName: AttributeNameRole,
+			Type:          msp.IdemixAttributePredicate_EQUAL,
+			Values:        []string{"admin"},
+		}))
+		assert.EqualError(t, err, "the identity does not satisfy the predicate on attribute Role")
+
+		err = id.SatisfiesPrincipal(idemixAttributesPrincipal(t, "MSP2OU1", member))
+		assert.Error(t, err)
+
+		err = id.SatisfiesPrincipal(idemixAttributesPrincipal(t, "MSP1OU1", &msp.IdemixAttributePredicate{
+			AttributeName: AttributeNameRevocationHandle,
+			Type:          msp.IdemixAttributePredicate_MEMBER,
+			Values:        []string{"1"},
+		}))
+		assert.EqualError(t, err, "attribute RevocationHandle cannot be used in a principal")
+	}
+
+	// an identity that does not claim anything about its enrollment id
+	msp2, err := setup("testdata/idemix/MSP1OU1", "MSP1OU1")
+	assert.NoError(t, err)
+	id3, err := getDefaultSigner(msp2)
+	assert.NoError(t, err)
+	assert.NoError(t, id3.SatisfiesPrincipal(idemixAttributesPrincipal(t, "MSP1OU1", ouAndRole...)))
+	err = id3.SatisfiesPrincipal(idemixAttributesPrincipal(t, "MSP1OU1", member))
+	assert.EqualError(t, err, "the identity does not prove any predicate on attribute EnrollmentID")
+
+	// an identity disclosing its enrollment id
+	msp3, err := setupWithAttributes("testdata/idemix/MSP1OU1", "MSP1OU1", &msp.IdemixAttributePredicate{
+		AttributeName: AttributeNameEnrollmentId,
+		Type:          msp.IdemixAttributePredicate_EQUAL,
+		Values:        []string{"testid"},
+	})
+	assert.NoError(t, err)
+	id4, err := getDefaultSigner(msp3)
+	assert.NoError(t, err)
+	assert.NoError(t, id4.SatisfiesPrincipal(idemixAttributesPrincipal(t, "MSP1OU1", member)))
+	assert.NoError(t, id4.SatisfiesPrincipal(idemixAttributesPrincipal(t, "MSP1OU1", &msp.IdemixAttributePredicate{
+		AttributeName: AttributeNameEnrollmentId,
+		Type:          msp.IdemixAttributePredicate_EQUAL,
+		Values:        []string{"testid"},
+	})))
+
+	// tampering with the claimed attributes invalidates the identity
+	id5 := *id4.(*idemixSigningIdentity).idemixidentity
+	id5.Attributes = &msp.IdemixAttributes{Predicates: []*msp.IdemixAttributePredicate{member}}
+	assert.Error(t, msp3.Validate(&id5))
+}
+
+func TestPrincipalIdemixAttributesBad(t *testing.T) {
+	msp1, err := setup("testdata/idemix/MSP1OU1", "MSP1OU1")
+	assert.NoError(t, err)
+	id1, err := getDefaultSigner(msp1)
+	assert.NoError(t, err)
+
+	err = id1.SatisfiesPrincipal(&msp.MSPPrincipal{
+		PrincipalClassification: msp.MSPPrincipal_IDEMIX_ATTRIBUTES,
+		Principal:               []byte{1, 2, 3}})
+	assert.Error(t, err)
+
+	err = id1.SatisfiesPrincipal(idemixAttributesPrincipal(t, "MSP1OU1", &msp.IdemixAttributePredicate{
+		AttributeName: AttributeNameOU,
+		Type:          msp.IdemixAttributePredicate_EQUAL,
+		Values:        []string{"OU1", "OU2"},
+	}))
+	assert.EqualError(t, err, "predicate on attribute OU must have exactly one value, got 2")
+
+	err = id1.SatisfiesPrincipal(idemixAttributesPrincipal(t, "MSP1OU1", &msp.IdemixAttributePredicate{
+		AttributeName: AttributeNameOU,
+		Type:          msp.IdemixAttributePredicate_MEMBER,
+	}))
+	assert.EqualError(t, err, "predicate on attribute OU must have at least one value")
+}
+
+func TestSetupWithAttributesBad(t *testing.T) {
+	// the enrollment id of the default signer is testid
+	_, err := setupWithAttributes("testdata/idemix/MSP1OU1", "MSP1OU1", &msp.IdemixAttributePredicate{
+		AttributeName: AttributeNameEnrollmentId,
+		Type:          msp.IdemixAttributePredicate_EQUAL,
+		Values:        []string{"otherid"},
+	})
+	assert.Contains(t, err.Error(), "the attributes of the default signer do not match its credential")
+
+	_, err = setupWithAttributes("testdata/idemix/MSP1OU1", "MSP1OU1", &msp.IdemixAttributePredicate{
+		AttributeName: AttributeNameEnrollmentId,
+		Type:          msp.IdemixAttributePredicate_MEMBER,
+		Values:        []string{"otherid"},
+	})
+	assert.Contains(t, err.Error(), "Failed to setup cryptographic proof of identity")
+
+	_, err = setupWithAttributes("testdata/idemix/MSP1OU1", "MSP1OU1", &msp.IdemixAttributePredicate{
+		AttributeName: AttributeNameRevocationHandle,
+		Type:          msp.IdemixAttributePredicate_MEMBER,
+		Values:        []string{"1"},
+	})
+	assert.Contains(t, err.Error(), "attribute RevocationHandle cannot be claimed")
+
+	predicate := &msp.IdemixAttributePredicate{
+		AttributeName: AttributeNameEnrollmentId,
+		Type:          msp.IdemixAttributePredicate_EQUAL,
+		Values:        []string{"testid"},
+	}
+	_, err = setupWithAttributes("testdata/idemix/MSP1OU1", "MSP1OU1", predicate, predicate)
+	assert.Contains(t, err.Error(), "attribute EnrollmentID is claimed more than once")
+}
@@ -30,20 +30,23 @@ type Receiver interface {
 	// is useful for Kafka orderer to determine the `LastOffsetPersisted` of block.
 	Ordered(msg *cb.Envelope) (messageBatches [][]*cb.Envelope, pending bool)
 
-	// Cut returns the current batch and starts a new one
-	Cut() []*cb.Envelope
+	// Cut returns the current batch and starts a new one. The reason
+	// it is cut for (e.g. CutBatchTimeout) is reported in the metrics
+	Cut(reason string) []*cb.Envelope
 }
 
 type receiver struct {
 	sharedConfigManager   channelconfig.Orderer
 	pendingBatch          []*cb.Envelope
 	pendingBatchSizeBytes uint32
+	metrics               *cutterMetrics
 }
 
-// NewReceiverImpl creates a Receiver implementation based on the given configtxorderer manager
-func NewReceiverImpl(sharedConfigManager channelconfig.Orderer) Receiver {
+// NewReceiverImpl creates a Receiver implementation for the given channel based on the given configtxorderer manager
+func NewReceiverImpl(channelID string, sharedConfigManager channelconfig.Orderer) Receiver {
 	return &receiver{
 		sharedConfigManager: sharedConfigManager,
+		metrics:             newCutterMetrics(channelID),
 	}
 }
 
@@ -70,12 +73,13 @@ func (r *receiver) Ordered(msg *cb.Envelope) (messageBatches [][]*cb.Envelope, p
 
 		// cut pending batch, if it has any messages
 		if len(r.pendingBatch) > 0 {
-			messageBatch := r.Cut()
+			messageBatch := r.cut(cutPreferredMaxBytes)
 			messageBatches = append(messageBatches, messageBatch)
 		}
 
 		// create new batch with single message
 		messageBatches = append(messageBatches, []*cb.Envelope{msg})
+		r.recordBatch(1, cutPreferredMaxBytes)
 
 		return
 	}
@@ -85,7 +89,7 @@ func (r *receiver) Ordered(msg *cb.Envelope) (messageBatches [][]*cb.Envelope, p
 	if messageWillOverflowBatchSizeBytes {
 		logger.Debugf("The current message, with %v bytes, will overflow the pending batch of %v bytes.", messageSizeBytes, r.pendingBatchSizeBytes)
 		logger.Debugf("Pending batch would overflow if current message is added, cutting batch now.")
-		messageBatch := r.Cut()
+		messageBatch := r.cut(cutPreferredMaxBytes)
 		messageBatches = append(messageBatches, messageBatch)
 	}
 
@@ -96,7 +100,7 @@ func (r *receiver) Ordered(msg *cb.Envelope) (messageBatches [][]*cb.Envelope, p
 
 	if uint32(len(r.pendingBatch)) >= r.sharedConfigManager.BatchSize().MaxMessageCount {
 		logger.Debugf("Batch size met, cutting batch")
-		messageBatch := r.Cut()
+		messageBatch := r.cut(cutMaxMessageCount)
 		messageBatches = append(messageBatches, messageBatch)
 		pending = false
 	}
@@ -105,13 +109,27 @@ func (r *receiver) Ordered(msg *cb.Envelope) (messageBatches [][]*cb.Envelope, p
 }
 
 // Cut returns the current batch and starts a new one
-func (r *receiver) Cut() []*cb.Envelope {
+func (r *receiver) Cut(reason string) []*cb.Envelope {
+	return r.cut(reason)
+}
+
+// cut returns the current batch and starts a new one, reporting the
+// batch in the metrics along with the reason it was cut for
+func (r *receiver) cut(reason string) []*cb.Envelope {
 	batch := r.pendingBatch
 	r.pendingBatch = nil
 	r.pendingBatchSizeBytes = 0
+	if len(batch) > 0 {
+		r.recordBatch(len(batch), reason)
+	}
 	return batch
 }
 
+func (r *receiver) recordBatch(size int, reason string) {
+	r.metrics.batchSize.Update(float64(size))
+	r.metrics.cutBatches(reason).Inc(1)
+}
+
 func messageSizeBytes(message *cb.Envelope) uint32 {
 	return uint32(len(message.Payload) + len(message.Signature))
 }
@@ -19,6 +19,7 @@ package blockcutter
 import (
 	"testing"
 
+	"github.com/hyperledger/fabric/common/metrics"
 	mockconfig "github.com/hyperledger/fabric/common/mocks/config"
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
@@ -38,7 +39,7 @@ func TestNormalBatch(t *testing.T) {
 	maxMessageCount := uint32(2)
 	absoluteMaxBytes := uint32(1000)
 	preferredMaxBytes := uint32(100)
-	r := NewReceiverImpl(&mockconfig.Orderer{BatchSizeVal: &ab.BatchSize{MaxMessageCount: maxMessageCount, AbsoluteMaxBytes: absoluteMaxBytes, PreferredMaxBytes: preferredMaxBytes}})
+	r := NewReceiverImpl("testchannel", &mockconfig.Orderer{BatchSizeVal: &ab.BatchSize{MaxMessageCount: maxMessageCount, AbsoluteMaxBytes: absoluteMaxBytes, PreferredMaxBytes: preferredMaxBytes}})
 
 	batches, pending := r.Ordered(tx)
 	assert.Nil(t, batches, "Should not have created batch")
@@ -58,7 +59,7 @@ func TestBatchSizePreferredMaxBytesOverflow(t *testing.T) {
 	// set message count > 9
 	maxMessageCount := uint32(20)
 
-	r := NewReceiverImpl(&mockconfig.Orderer{BatchSizeVal: &ab.BatchSize{MaxMessageCount: maxMessageCount, AbsoluteMaxBytes: preferredMaxBytes * 2, PreferredMaxBytes: preferredMaxBytes}})
+	r := NewReceiverImpl("testchannel", &mockconfig.Orderer{BatchSizeVal: &ab.BatchSize{MaxMessageCount: maxMessageCount, AbsoluteMaxBytes: preferredMaxBytes * 2, PreferredMaxBytes: preferredMaxBytes}})
 
 	// enqueue 9 messages
 	for i := 0; i < 9; i++ {
@@ -75,7 +76,7 @@ func TestBatchSizePreferredMaxBytesOverflow(t *testing.T) {
 	assert.Len(t, batches[0], 9, "Should have had nine normal tx in the batch")
 
 	// force a batch cut
-	messageBatch := r.Cut()
+	messageBatch := r.Cut(CutBatchTimeout)
 	assert.NotNil(t, batches, "Should have created batch")
 	assert.Len(t, messageBatch, 1, "Should have had one tx in the batch")
 }
@@ -89,7 +90,7 @@ func TestBatchSizePreferredMaxBytesOverflowNoPending(t *testing.T) {
 	// set message count > 1
 	maxMessageCount := uint32(20)
 
-	r := NewReceiverImpl(&mockconfig.Orderer{BatchSizeVal: &ab.BatchSize{MaxMessageCount: maxMessageCount, AbsoluteMaxBytes: preferredMaxBytes * 3, PreferredMaxBytes: preferredMaxBytes}})
+	r := NewReceiverImpl("testchannel", &mockconfig.Orderer{BatchSizeVal: &ab.BatchSize{MaxMessageCount: maxMessageCount, AbsoluteMaxBytes: preferredMaxBytes * 3, PreferredMaxBytes: preferredMaxBytes}})
 
 	// submit normal message
 	batches, pending := r.Ordered(tx)
@@ -105,3 +106,48 @@ func TestBatchSizePreferredMaxBytesOverflowNoPending(t *testing.T) {
 		assert.Len(t, batch, 1, "Should have had one normal tx in batch %d", i)
 	}
 }
+
+func TestCutMetrics(t *testing.T) {
+	testScope, restore := metrics.UseTestScope()
+	defer restore()
+
+	txBytes := messageSizeBytes(tx)
+	r := NewReceiverImpl("testchannel", &mockconfig.Orderer{BatchSizeVal: &ab.BatchSize{MaxMessageCount: 3, AbsoluteMaxBytes: 2000, PreferredMaxBytes: txBytes * 10}})
+
+	cutBatches := func(reason string) int64 {
+		return testScope.CounterValue("blockcutter.cut_batches", map[string]string{"channel": "testchannel", "reason": reason})
+	}
+	batchSize := func() float64 {
+		return testScope.GaugeValue("blockcutter.batch_size", map[string]string{"channel": "testchannel"})
+	}
+
+	// the message count is reached
+	for i := 0; i < 3; i++ {
+		r.Ordered(tx)
+	}
+	assert.Equal(t, int64(1), cutBatches(cutMaxMessageCount))
+	assert.Equal(t, float64(3), batchSize())
+
+	// the pending message is cut to isolate the large one
+	r.Ordered(tx)
+	r.Ordered(txLarge)
+	assert.Equal(t, int64(2), cutBatches(cutPreferredMaxBytes))
+	assert.Equal(t, float64(1), batchSize())
+
+	// the consenters cut the pending batch for different reasons
+	r.Ordered(tx)
+	r.Ordered(tx)
+	r.Cut(CutConfigIsolation)
+	assert.Equal(t, int64(1), cutBatches(CutConfigIsolation))
+	assert.Equal(t, float64(2), batchSize())
+
+	r.Ordered(tx)
+	r.Cut(CutBatchTimeout)
+	assert.Equal(t, int64(1), cutBatches(CutBatchTimeout))
+	assert.Equal(t, float64(1), batchSize())
+
+	// cutting an empty batch is not reported
+	r.Cut(CutBatchTimeout)
+	assert.Equal(t, int64(1), cutBatches(CutBatchTimeout))
+	assert.Equal(t, int64(1), cutBatches(cutMaxMessageCount))
+}
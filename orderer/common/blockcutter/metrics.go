@@ -0,0 +1,50 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blockcutter
+
+import (
+	"github.com/hyperledger/fabric/common/metrics"
+)
+
+const (
+	// cutMaxMessageCount is reported when a batch is cut because it reached BatchSize.MaxMessageCount
+	cutMaxMessageCount = "max_message_count"
+	// cutPreferredMaxBytes is reported when a batch is cut because a message would have made it exceed
+	// BatchSize.PreferredMaxBytes, or because the message itself exceeds it and is isolated in its own batch
+	cutPreferredMaxBytes = "preferred_max_bytes"
+)
+
+// The reasons the consenters pass to Cut
+const (
+	// CutBatchTimeout is reported when the pending batch is cut because the batch timeout expired
+	CutBatchTimeout = "batch_timeout"
+	// CutConfigIsolation is reported when the pending batch is cut so that a config message
+	// is isolated in its own block
+	CutConfigIsolation = "config_isolation"
+	// CutDiscarded is reported when the pending batch is cut to be discarded, as happens
+	// when an etcdraft node stops serving requests
+	CutDiscarded = "discarded"
+)
+
+// cutterMetrics holds the metrics of the batches cut for a channel
+type cutterMetrics struct {
+	scope     metrics.Scope
+	batchSize metrics.Gauge
+}
+
+func newCutterMetrics(channelID string) *cutterMetrics {
+	scope := metrics.GetRootScope().SubScope("blockcutter").Tagged(map[string]string{"channel": channelID})
+	return &cutterMetrics{
+		scope:     scope,
+		batchSize: scope.Gauge("batch_size"),
+	}
+}
+
+// cutBatches returns the counter of the batches cut for the given reason
+func (m *cutterMetrics) cutBatches(reason string) metrics.Counter {
+	return m.scope.Tagged(map[string]string{"reason": reason}).Counter("cut_batches")
+}
@@ -65,13 +65,15 @@ type Consenter interface {
 }
 
 type handlerImpl struct {
-	sm ChannelSupportRegistrar
+	sm      ChannelSupportRegistrar
+	metrics *broadcastMetrics
 }
 
 // NewHandlerImpl constructs a new implementation of the Handler interface
 func NewHandlerImpl(sm ChannelSupportRegistrar) Handler {
 	return &handlerImpl{
-		sm: sm,
+		sm:      sm,
+		metrics: newBroadcastMetrics(),
 	}
 }
 
@@ -97,12 +99,12 @@ func (bh *handlerImpl) Handle(srv ab.AtomicBroadcast_BroadcastServer) error {
 				channelID = chdr.ChannelId
 			}
 			logger.Warningf("[channel: %s] Could not get message processor for serving %s: %s", channelID, addr, err)
-			return srv.Send(&ab.BroadcastResponse{Status: cb.Status_BAD_REQUEST, Info: err.Error()})
+			return bh.send(srv, chdr, &ab.BroadcastResponse{Status: cb.Status_BAD_REQUEST, Info: err.Error()})
 		}
 
 		if err = processor.WaitReady(); err != nil {
 			logger.Warningf("[channel: %s] Rejecting broadcast of message from %s with SERVICE_UNAVAILABLE: rejected by Consenter: %s", chdr.ChannelId, addr, err)
-			return srv.Send(&ab.BroadcastResponse{Status: cb.Status_SERVICE_UNAVAILABLE, Info: err.Error()})
+			return bh.send(srv, chdr, &ab.BroadcastResponse{Status: cb.Status_SERVICE_UNAVAILABLE, Info: err.Error()})
 		}
 
 		if !isConfig {
@@ -111,13 +113,13 @@ func (bh *handlerImpl) Handle(srv ab.AtomicBroadcast_BroadcastServer) error {
 			configSeq, err := processor.ProcessNormalMsg(msg)
 			if err != nil {
 				logger.Warningf("[channel: %s] Rejecting broadcast of normal message from %s because of error: %s", chdr.ChannelId, addr, err)
-				return srv.Send(&ab.BroadcastResponse{Status: ClassifyError(err), Info: err.Error()})
+				return bh.send(srv, chdr, &ab.BroadcastResponse{Status: ClassifyError(err), Info: err.Error()})
 			}
 
 			err = processor.Order(msg, configSeq)
 			if err != nil {
 				logger.Warningf("[channel: %s] Rejecting broadcast of normal message from %s with SERVICE_UNAVAILABLE: rejected by Order: %s", chdr.ChannelId, addr, err)
-				return srv.Send(&ab.BroadcastResponse{Status: cb.Status_SERVICE_UNAVAILABLE, Info: err.Error()})
+				return bh.send(srv, chdr, &ab.BroadcastResponse{Status: cb.Status_SERVICE_UNAVAILABLE, Info: err.Error()})
 			}
 		} else { // isConfig
 			logger.Debugf("[channel: %s] Broadcast is processing config update message from %s", chdr.ChannelId, addr)
@@ -125,19 +127,19 @@ func (bh *handlerImpl) Handle(srv ab.AtomicBroadcast_BroadcastServer) error {
 			config, configSeq, err := processor.ProcessConfigUpdateMsg(msg)
 			if err != nil {
 				logger.Warningf("[channel: %s] Rejecting broadcast of config message from %s because of error: %s", chdr.ChannelId, addr, err)
-				return srv.Send(&ab.BroadcastResponse{Status: ClassifyError(err), Info: err.Error()})
+				return bh.send(srv, chdr, &ab.BroadcastResponse{Status: ClassifyError(err), Info: err.Error()})
 			}
 
 			err = processor.Configure(config, configSeq)
 			if err != nil {
 				logger.Warningf("[channel: %s] Rejecting broadcast of config message from %s with SERVICE_UNAVAILABLE: rejected by Configure: %s", chdr.ChannelId, addr, err)
-				return srv.Send(&ab.BroadcastResponse{Status: cb.Status_SERVICE_UNAVAILABLE, Info: err.Error()})
+				return bh.send(srv, chdr, &ab.BroadcastResponse{Status: cb.Status_SERVICE_UNAVAILABLE, Info: err.Error()})
 			}
 		}
 
 		logger.Debugf("[channel: %s] Broadcast has successfully enqueued message of type %s from %s", chdr.ChannelId, cb.HeaderType_name[chdr.Type], addr)
 
-		err = srv.Send(&ab.BroadcastResponse{Status: cb.Status_SUCCESS})
+		err = bh.send(srv, chdr, &ab.BroadcastResponse{Status: cb.Status_SUCCESS})
 		if err != nil {
 			logger.Warningf("[channel: %s] Error sending to %s: %s", chdr.ChannelId, addr, err)
 			return err
@@ -145,6 +147,17 @@ func (bh *handlerImpl) Handle(srv ab.AtomicBroadcast_BroadcastServer) error {
 	}
 }
 
+// send sends the response to the broadcast of a message with the given channel
+// header, and accounts for the processing of the message in the metrics
+func (bh *handlerImpl) send(srv ab.AtomicBroadcast_BroadcastServer, chdr *cb.ChannelHeader, resp *ab.BroadcastResponse) error {
+	channelID, headerType := "<malformed_header>", cb.HeaderType(-1)
+	if chdr != nil {
+		channelID, headerType = chdr.ChannelId, cb.HeaderType(chdr.Type)
+	}
+	bh.metrics.processedEnvelopes(channelID, headerType, resp.Status).Inc(1)
+	return srv.Send(resp)
+}
+
 // ClassifyError converts an error type into a status code.
 func ClassifyError(err error) cb.Status {
 	switch errors.Cause(err) {
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
@@ -312,3 +313,48 @@ func TestMalformedHeader(t *testing.T) {
 		t.Fatalf("Should have terminated the stream")
 	}
 }
+
+func TestProcessedEnvelopesMetrics(t *testing.T) {
+	testScope, restore := metrics.UseTestScope()
+	defer restore()
+
+	processedEnvelopes := func(channelID string, headerType cb.HeaderType, status cb.Status) int64 {
+		return testScope.CounterValue("broadcast.processed_envelopes", map[string]string{
+			"channel": channelID,
+			"type":    headerType.String(),
+			"status":  status.String(),
+		})
+	}
+
+	mm := getMockSupportManager()
+	mm.ChdrVal = &cb.ChannelHeader{ChannelId: "mychannel", Type: int32(cb.HeaderType_ENDORSER_TRANSACTION)}
+	bh := NewHandlerImpl(mm)
+	m := newMockB()
+	defer close(m.recvChan)
+	go bh.Handle(m)
+
+	for i := 0; i < 2; i++ {
+		m.recvChan <- nil
+		<-m.sendChan
+	}
+	assert.Equal(t, int64(2), processedEnvelopes("mychannel", cb.HeaderType_ENDORSER_TRANSACTION, cb.Status_SUCCESS))
+
+	mm.MsgProcessorVal.rejectEnqueue = true
+	m.recvChan <- nil
+	<-m.sendChan
+	assert.Equal(t, int64(1), processedEnvelopes("mychannel", cb.HeaderType_ENDORSER_TRANSACTION, cb.Status_SERVICE_UNAVAILABLE))
+	assert.Equal(t, int64(2), processedEnvelopes("mychannel", cb.HeaderType_ENDORSER_TRANSACTION, cb.Status_SUCCESS))
+
+	// a message with a malformed header is accounted for separately
+	mm = getMockSupportManager()
+	mm.ChdrVal = nil
+	mm.MsgProcessorErr = errors.New("Mocked Error")
+	bh = NewHandlerImpl(mm)
+	m2 := newMockB()
+	defer close(m2.recvChan)
+	go bh.Handle(m2)
+
+	m2.recvChan <- nil
+	<-m2.sendChan
+	assert.Equal(t, int64(1), processedEnvelopes("<malformed_header>", cb.HeaderType(-1), cb.Status_BAD_REQUEST))
+}
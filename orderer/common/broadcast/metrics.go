@@ -0,0 +1,33 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package broadcast
+
+import (
+	"github.com/hyperledger/fabric/common/metrics"
+	cb "github.com/hyperledger/fabric/protos/common"
+)
+
+// broadcastMetrics holds the metrics of the envelopes received through broadcast
+type broadcastMetrics struct {
+	scope metrics.Scope
+}
+
+func newBroadcastMetrics() *broadcastMetrics {
+	return &broadcastMetrics{
+		scope: metrics.GetRootScope().SubScope("broadcast"),
+	}
+}
+
+// processedEnvelopes returns the counter of the envelopes of the given channel and
+// header type which were processed with the given status
+func (m *broadcastMetrics) processedEnvelopes(channelID string, headerType cb.HeaderType, status cb.Status) metrics.Counter {
+	return m.scope.Tagged(map[string]string{
+		"channel": channelID,
+		"type":    headerType.String(),
+		"status":  status.String(),
+	}).Counter("processed_envelopes")
+}
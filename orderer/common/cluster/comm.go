@@ -0,0 +1,323 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/comm"
+	"github.com/hyperledger/fabric/protos/orderer"
+	"github.com/op/go-logging"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+const pkgLogID = "orderer/common/cluster"
+
+var logger *logging.Logger
+
+func init() {
+	logger = flogging.MustGetLogger(pkgLogID)
+}
+
+// Handler handles Step(), Submit() and Pull() requests that were sent
+// by an authenticated member of the cluster
+type Handler interface {
+	// OnStep handles a consensus specific message sent by the given sender
+	OnStep(channel string, sender uint64, req *orderer.StepRequest) (*orderer.StepResponse, error)
+
+	// OnSubmit handles a transaction forwarded by the given sender
+	OnSubmit(channel string, sender uint64, req *orderer.SubmitRequest) (*orderer.SubmitResponse, error)
+
+	// OnPull handles a request of the given sender for committed blocks
+	OnPull(channel string, sender uint64, req *orderer.PullRequest) (*orderer.PullResponse, error)
+}
+
+// RemoteNode represents a cluster member
+type RemoteNode struct {
+	// ID is unique among all members, and cannot be 0.
+	ID uint64
+	// Endpoint is the endpoint of the node, denoted in %s:%d format
+	Endpoint string
+	// ServerTLSCert is the DER encoded TLS server certificate of the node
+	ServerTLSCert []byte
+	// ClientTLSCert is the DER encoded TLS client certificate of the node
+	ClientTLSCert []byte
+}
+
+// Communicator defines communication for a consenter
+type Communicator interface {
+	// Remote returns a RemoteContext for the given RemoteNode ID in the context
+	// of the given channel, or error if connection cannot be established, or
+	// the channel wasn't configured
+	Remote(channel string, id uint64) (*RemoteContext, error)
+
+	// Configure configures the communication to connect to all
+	// given members, and disconnect from any members not among the given
+	// members.
+	Configure(channel string, members []RemoteNode)
+
+	// Shutdown shuts down the communicator
+	Shutdown()
+}
+
+// SecureDialer connects to a remote address
+type SecureDialer interface {
+	// Dial creates a gRPC connection to the given address, which is only
+	// established if the given verifier accepts the remote certificate
+	Dial(address string, verifyFunc RemoteVerifier) (*grpc.ClientConn, error)
+}
+
+// RemoteVerifier verifies the connection to the remote host.
+// This is called after normal certificate verification by the TLS stack.
+type RemoteVerifier func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error
+
+// Comm implements Communicator and dispatches the requests that are
+// received by the cluster Service to its Handler
+type Comm struct {
+	// H handles the requests that were authenticated as sent by a cluster member
+	H Handler
+	// Connect creates connections to remote cluster members
+	Connect SecureDialer
+	// RPCTimeout bounds each remote call performed by a RemoteContext
+	RPCTimeout time.Duration
+
+	lock         sync.RWMutex
+	shutdown     bool
+	chan2members map[string]memberMapping
+}
+
+type memberMapping map[uint64]*stub
+
+// stub holds the information about a remote cluster member,
+// and lazily maintains the connection to it
+type stub struct {
+	RemoteNode
+	clientCertHash []byte
+
+	lock   sync.Mutex
+	conn   *grpc.ClientConn
+	remote *RemoteContext
+}
+
+// RemoteContext performs RPCs to a remote cluster member
+type RemoteContext struct {
+	Client     orderer.ClusterClient
+	RPCTimeout time.Duration
+}
+
+// NewComm creates a new Comm which dials remote members with the given dialer
+func NewComm(dialer SecureDialer, rpcTimeout time.Duration) *Comm {
+	return &Comm{
+		Connect:      dialer,
+		RPCTimeout:   rpcTimeout,
+		chan2members: make(map[string]memberMapping),
+	}
+}
+
+// DispatchStep identifies the sender of the request and passes it to the Handler
+func (c *Comm) DispatchStep(ctx context.Context, request *orderer.StepRequest) (*orderer.StepResponse, error) {
+	sender, err := c.requestSender(ctx, request.Channel)
+	if err != nil {
+		return nil, err
+	}
+	return c.H.OnStep(request.Channel, sender, request)
+}
+
+// DispatchSubmit identifies the sender of the request and passes it to the Handler
+func (c *Comm) DispatchSubmit(ctx context.Context, request *orderer.SubmitRequest) (*orderer.SubmitResponse, error) {
+	sender, err := c.requestSender(ctx, request.Channel)
+	if err != nil {
+		return nil, err
+	}
+	return c.H.OnSubmit(request.Channel, sender, request)
+}
+
+// DispatchPull identifies the sender of the request and passes it to the Handler
+func (c *Comm) DispatchPull(ctx context.Context, request *orderer.PullRequest) (*orderer.PullResponse, error) {
+	sender, err := c.requestSender(ctx, request.Channel)
+	if err != nil {
+		return nil, err
+	}
+	return c.H.OnPull(request.Channel, sender, request)
+}
+
+// requestSender returns the ID of the cluster member that sent the request
+// over the connection of the given context, according to the TLS client
+// certificate it used
+func (c *Comm) requestSender(ctx context.Context, channel string) (uint64, error) {
+	certHash := comm.ExtractCertificateHashFromContext(ctx)
+	if len(certHash) == 0 {
+		return 0, errors.New("no TLS certificate sent")
+	}
+
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.shutdown {
+		return 0, errors.New("communication has been shut down")
+	}
+
+	mapping, exists := c.chan2members[channel]
+	if !exists {
+		return 0, errors.Errorf("channel %s doesn't exist", channel)
+	}
+	for id, member := range mapping {
+		if bytes.Equal(member.clientCertHash, certHash) {
+			return id, nil
+		}
+	}
+	return 0, errors.Errorf("certificate extracted from TLS connection isn't authorized for channel %s", channel)
+}
+
+// Configure configures the channel with the given RemoteNodes
+func (c *Comm) Configure(channel string, newNodes []RemoteNode) {
+	logger.Debugf("Configuring channel %s with %d remote nodes", channel, len(newNodes))
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.shutdown {
+		return
+	}
+
+	oldMapping := c.chan2members[channel]
+	newMapping := make(memberMapping)
+	for _, node := range newNodes {
+		if s, exists := oldMapping[node.ID]; exists && sameNode(s.RemoteNode, node) {
+			newMapping[node.ID] = s
+			continue
+		}
+		newMapping[node.ID] = &stub{
+			RemoteNode:     node,
+			clientCertHash: util.ComputeSHA256(node.ClientTLSCert),
+		}
+	}
+
+	// Close the connections to members that were removed or altered
+	for id, s := range oldMapping {
+		if newMapping[id] != s {
+			s.deactivate()
+		}
+	}
+
+	c.chan2members[channel] = newMapping
+}
+
+// Remote obtains a RemoteContext linked to the destination node on the context
+// of a given channel
+func (c *Comm) Remote(channel string, id uint64) (*RemoteContext, error) {
+	c.lock.RLock()
+	if c.shutdown {
+		c.lock.RUnlock()
+		return nil, errors.New("communication has been shut down")
+	}
+	mapping, exists := c.chan2members[channel]
+	if !exists {
+		c.lock.RUnlock()
+		return nil, errors.Errorf("channel %s doesn't exist", channel)
+	}
+	s, exists := mapping[id]
+	c.lock.RUnlock()
+	if !exists {
+		return nil, errors.Errorf("node %d doesn't exist in channel %s's membership", id, channel)
+	}
+
+	return s.activate(c.Connect, c.RPCTimeout)
+}
+
+// Shutdown shuts down the instance
+func (c *Comm) Shutdown() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.shutdown = true
+	for _, mapping := range c.chan2members {
+		for _, s := range mapping {
+			s.deactivate()
+		}
+	}
+}
+
+// activate creates a connection to the remote node if it doesn't exist yet,
+// and returns a RemoteContext that uses it
+func (s *stub) activate(dialer SecureDialer, rpcTimeout time.Duration) (*RemoteContext, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.remote != nil {
+		return s.remote, nil
+	}
+
+	conn, err := dialer.Dial(s.Endpoint, s.verifyServerCert)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed connecting to %s", s.Endpoint)
+	}
+	s.conn = conn
+	s.remote = &RemoteContext{
+		Client:     orderer.NewClusterClient(conn),
+		RPCTimeout: rpcTimeout,
+	}
+	return s.remote, nil
+}
+
+// deactivate closes the connection to the remote node, if it exists
+func (s *stub) deactivate() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.conn != nil {
+		s.conn.Close()
+	}
+	s.conn = nil
+	s.remote = nil
+}
+
+// verifyServerCert pins the TLS server certificate of the remote node
+func (s *stub) verifyServerCert(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	if len(rawCerts) == 0 {
+		return errors.New("no TLS certificate presented by the remote node")
+	}
+	if !bytes.Equal(rawCerts[0], s.ServerTLSCert) {
+		return errors.Errorf("TLS certificate presented by %s doesn't match the one in the channel configuration", s.Endpoint)
+	}
+	return nil
+}
+
+// Step passes an implementation-specific message to the remote node
+func (rc *RemoteContext) Step(req *orderer.StepRequest) (*orderer.StepResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), rc.RPCTimeout)
+	defer cancel()
+	return rc.Client.Step(ctx, req)
+}
+
+// Submit forwards a transaction to the remote node
+func (rc *RemoteContext) Submit(req *orderer.SubmitRequest) (*orderer.SubmitResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), rc.RPCTimeout)
+	defer cancel()
+	return rc.Client.Submit(ctx, req)
+}
+
+// Pull retrieves committed blocks from the remote node
+func (rc *RemoteContext) Pull(req *orderer.PullRequest) (*orderer.PullResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), rc.RPCTimeout)
+	defer cancel()
+	return rc.Client.Pull(ctx, req)
+}
+
+func sameNode(a, b RemoteNode) bool {
+	return a.ID == b.ID &&
+		a.Endpoint == b.Endpoint &&
+		bytes.Equal(a.ServerTLSCert, b.ServerTLSCert) &&
+		bytes.Equal(a.ClientTLSCert, b.ClientTLSCert)
+}
@@ -0,0 +1,320 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cluster_test
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/crypto/tlsgen"
+	"github.com/hyperledger/fabric/core/comm"
+	"github.com/hyperledger/fabric/orderer/common/cluster"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/orderer"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+const testChannel = "test"
+
+var ca = createCAOrPanic()
+
+func createCAOrPanic() tlsgen.CA {
+	ca, err := tlsgen.NewCA()
+	if err != nil {
+		panic(err)
+	}
+	return ca
+}
+
+type stepCall struct {
+	channel string
+	sender  uint64
+	req     *orderer.StepRequest
+}
+
+type mockHandler struct {
+	sync.Mutex
+	steps        []stepCall
+	submitStatus common.Status
+	pullErr      error
+}
+
+func (h *mockHandler) OnStep(channel string, sender uint64, req *orderer.StepRequest) (*orderer.StepResponse, error) {
+	h.Lock()
+	defer h.Unlock()
+	h.steps = append(h.steps, stepCall{channel: channel, sender: sender, req: req})
+	return &orderer.StepResponse{}, nil
+}
+
+func (h *mockHandler) OnSubmit(channel string, sender uint64, req *orderer.SubmitRequest) (*orderer.SubmitResponse, error) {
+	return &orderer.SubmitResponse{Status: h.submitStatus, Info: "mock"}, nil
+}
+
+func (h *mockHandler) OnPull(channel string, sender uint64, req *orderer.PullRequest) (*orderer.PullResponse, error) {
+	if h.pullErr != nil {
+		return nil, h.pullErr
+	}
+	return &orderer.PullResponse{Blocks: []*common.Block{common.NewBlock(req.Start, nil)}}, nil
+}
+
+func (h *mockHandler) stepCalls() []stepCall {
+	h.Lock()
+	defer h.Unlock()
+	return append([]stepCall(nil), h.steps...)
+}
+
+type clusterNode struct {
+	id         uint64
+	srv        *comm.GRPCServer
+	comm       *cluster.Comm
+	handler    *mockHandler
+	serverCert []byte
+	clientCert []byte
+}
+
+func (cn *clusterNode) remoteNode() cluster.RemoteNode {
+	return cluster.RemoteNode{
+		ID:            cn.id,
+		Endpoint:      cn.srv.Address(),
+		ServerTLSCert: cn.serverCert,
+		ClientTLSCert: cn.clientCert,
+	}
+}
+
+func (cn *clusterNode) stop() {
+	cn.srv.Stop()
+	cn.comm.Shutdown()
+}
+
+func derOf(t *testing.T, pemBytes []byte) []byte {
+	bl, _ := pem.Decode(pemBytes)
+	assert.NotNil(t, bl)
+	return bl.Bytes
+}
+
+func newClusterNode(t *testing.T, id uint64) *clusterNode {
+	serverKeyPair, err := ca.NewServerCertKeyPair("127.0.0.1")
+	assert.NoError(t, err)
+	clientKeyPair, err := ca.NewClientCertKeyPair()
+	assert.NoError(t, err)
+
+	srv, err := comm.NewGRPCServer("127.0.0.1:0", comm.ServerConfig{
+		SecOpts: &comm.SecureOptions{
+			UseTLS:            true,
+			RequireClientCert: true,
+			Certificate:       serverKeyPair.Cert,
+			Key:               serverKeyPair.Key,
+			ClientRootCAs:     [][]byte{ca.CertBytes()},
+		},
+	})
+	assert.NoError(t, err)
+
+	dialer := cluster.NewTLSPinningDialer(comm.ClientConfig{
+		Timeout: time.Second,
+		SecOpts: &comm.SecureOptions{
+			UseTLS:            true,
+			RequireClientCert: true,
+			Certificate:       clientKeyPair.Cert,
+			Key:               clientKeyPair.Key,
+			ServerRootCAs:     [][]byte{ca.CertBytes()},
+		},
+	})
+
+	handler := &mockHandler{submitStatus: common.Status_SUCCESS}
+	c := cluster.NewComm(dialer, time.Second)
+	c.H = handler
+
+	orderer.RegisterClusterServer(srv.Server(), &cluster.Service{Dispatcher: c})
+	go srv.Start()
+
+	return &clusterNode{
+		id:         id,
+		srv:        srv,
+		comm:       c,
+		handler:    handler,
+		serverCert: derOf(t, serverKeyPair.Cert),
+		clientCert: derOf(t, clientKeyPair.Cert),
+	}
+}
+
+func TestBasicStep(t *testing.T) {
+	t.Parallel()
+	node1 := newClusterNode(t, 1)
+	defer node1.stop()
+	node2 := newClusterNode(t, 2)
+	defer node2.stop()
+
+	node1.comm.Configure(testChannel, []cluster.RemoteNode{node2.remoteNode()})
+	node2.comm.Configure(testChannel, []cluster.RemoteNode{node1.remoteNode()})
+
+	rpc := &cluster.RPC{Channel: testChannel, Comm: node1.comm}
+	_, err := rpc.Step(2, &orderer.StepRequest{Channel: testChannel, Payload: []byte{1, 2, 3}})
+	assert.NoError(t, err)
+
+	calls := node2.handler.stepCalls()
+	assert.Len(t, calls, 1)
+	assert.Equal(t, testChannel, calls[0].channel)
+	assert.Equal(t, uint64(1), calls[0].sender)
+	assert.Equal(t, []byte{1, 2, 3}, calls[0].req.Payload)
+}
+
+func TestSubmitAndPull(t *testing.T) {
+	t.Parallel()
+	node1 := newClusterNode(t, 1)
+	defer node1.stop()
+	node2 := newClusterNode(t, 2)
+	defer node2.stop()
+
+	node1.comm.Configure(testChannel, []cluster.RemoteNode{node2.remoteNode()})
+	node2.comm.Configure(testChannel, []cluster.RemoteNode{node1.remoteNode()})
+
+	rpc := &cluster.RPC{Channel: testChannel, Comm: node1.comm}
+
+	err := rpc.SendSubmit(2, &orderer.SubmitRequest{Channel: testChannel})
+	assert.NoError(t, err)
+
+	node2.handler.submitStatus = common.Status_SERVICE_UNAVAILABLE
+	err = rpc.SendSubmit(2, &orderer.SubmitRequest{Channel: testChannel})
+	assert.EqualError(t, err, "node 2 rejected the request with status SERVICE_UNAVAILABLE: mock")
+
+	resp, err := rpc.Pull(2, &orderer.PullRequest{Channel: testChannel, Start: 5, End: 10})
+	assert.NoError(t, err)
+	assert.Len(t, resp.Blocks, 1)
+	assert.Equal(t, uint64(5), resp.Blocks[0].Header.Number)
+
+	node2.handler.pullErr = errors.New("no such block")
+	_, err = rpc.Pull(2, &orderer.PullRequest{Channel: testChannel, Start: 5, End: 10})
+	assert.Contains(t, err.Error(), "no such block")
+}
+
+func TestUnauthenticatedSender(t *testing.T) {
+	t.Parallel()
+	node1 := newClusterNode(t, 1)
+	defer node1.stop()
+	node2 := newClusterNode(t, 2)
+	defer node2.stop()
+
+	// node2 doesn't know node1, hence it should reject its requests
+	node1.comm.Configure(testChannel, []cluster.RemoteNode{node2.remoteNode()})
+	node2.comm.Configure(testChannel, nil)
+
+	rpc := &cluster.RPC{Channel: testChannel, Comm: node1.comm}
+	_, err := rpc.Step(2, &orderer.StepRequest{Channel: testChannel})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "certificate extracted from TLS connection isn't authorized for channel test")
+	assert.Empty(t, node2.handler.stepCalls())
+
+	// node2 doesn't serve the channel node1 is sending to
+	_, err = node1.comm.Remote(testChannel, 2)
+	assert.NoError(t, err)
+	node1.comm.Configure("foo", []cluster.RemoteNode{node2.remoteNode()})
+	rpc = &cluster.RPC{Channel: "foo", Comm: node1.comm}
+	_, err = rpc.Step(2, &orderer.StepRequest{Channel: "foo"})
+	assert.Contains(t, err.Error(), "channel foo doesn't exist")
+}
+
+func TestServerCertificatePinning(t *testing.T) {
+	t.Parallel()
+	node1 := newClusterNode(t, 1)
+	defer node1.stop()
+	node2 := newClusterNode(t, 2)
+	defer node2.stop()
+	node3 := newClusterNode(t, 3)
+	defer node3.stop()
+
+	// node1 expects node2 to present the server certificate of node3,
+	// so the connection should not be established even though both
+	// certificates are issued by the same CA
+	impostor := node2.remoteNode()
+	impostor.ServerTLSCert = node3.serverCert
+	node1.comm.Configure(testChannel, []cluster.RemoteNode{impostor})
+	node2.comm.Configure(testChannel, []cluster.RemoteNode{node1.remoteNode()})
+
+	rpc := &cluster.RPC{Channel: testChannel, Comm: node1.comm}
+	_, err := rpc.Step(2, &orderer.StepRequest{Channel: testChannel})
+	assert.Error(t, err)
+	assert.Empty(t, node2.handler.stepCalls())
+}
+
+func TestReconfigure(t *testing.T) {
+	t.Parallel()
+	node1 := newClusterNode(t, 1)
+	defer node1.stop()
+	node2 := newClusterNode(t, 2)
+	defer node2.stop()
+
+	node1.comm.Configure(testChannel, []cluster.RemoteNode{node2.remoteNode()})
+	node2.comm.Configure(testChannel, []cluster.RemoteNode{node1.remoteNode()})
+
+	rc1, err := node1.comm.Remote(testChannel, 2)
+	assert.NoError(t, err)
+
+	// Configuring the same membership again keeps the connection
+	node1.comm.Configure(testChannel, []cluster.RemoteNode{node2.remoteNode()})
+	rc2, err := node1.comm.Remote(testChannel, 2)
+	assert.NoError(t, err)
+	assert.True(t, rc1 == rc2)
+
+	// Removing node2 makes it unreachable
+	node1.comm.Configure(testChannel, nil)
+	_, err = node1.comm.Remote(testChannel, 2)
+	assert.EqualError(t, err, "node 2 doesn't exist in channel test's membership")
+
+	_, err = node1.comm.Remote("foo", 2)
+	assert.EqualError(t, err, "channel foo doesn't exist")
+
+	// After shutting down, no node is reachable
+	node1.comm.Configure(testChannel, []cluster.RemoteNode{node2.remoteNode()})
+	node1.comm.Shutdown()
+	_, err = node1.comm.Remote(testChannel, 2)
+	assert.EqualError(t, err, "communication has been shut down")
+}
+
+func TestNoTLSCertificate(t *testing.T) {
+	t.Parallel()
+	c := cluster.NewComm(cluster.NewTLSPinningDialer(comm.ClientConfig{}), time.Second)
+	_, err := c.DispatchStep(context.Background(), &orderer.StepRequest{Channel: testChannel})
+	assert.EqualError(t, err, "no TLS certificate sent")
+}
+
+func TestDialerVerifyFunc(t *testing.T) {
+	t.Parallel()
+	node1 := newClusterNode(t, 1)
+	defer node1.stop()
+
+	clientKeyPair, err := ca.NewClientCertKeyPair()
+	assert.NoError(t, err)
+	dialer := cluster.NewTLSPinningDialer(comm.ClientConfig{
+		Timeout: time.Second,
+		SecOpts: &comm.SecureOptions{
+			UseTLS:            true,
+			RequireClientCert: true,
+			Certificate:       clientKeyPair.Cert,
+			Key:               clientKeyPair.Key,
+			ServerRootCAs:     [][]byte{ca.CertBytes()},
+		},
+	})
+
+	var presented []byte
+	conn, err := dialer.Dial(node1.srv.Address(), func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		presented = rawCerts[0]
+		return nil
+	})
+	assert.NoError(t, err)
+	defer conn.Close()
+	assert.Equal(t, node1.serverCert, presented)
+
+	_, err = dialer.Dial(node1.srv.Address(), func(_ [][]byte, _ [][]*x509.Certificate) error {
+		return errors.New("rejected")
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,41 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cluster
+
+import (
+	"github.com/hyperledger/fabric/core/comm"
+	"google.golang.org/grpc"
+)
+
+// PredicateDialer creates gRPC connections
+// that are only established if the given predicate
+// is fulfilled
+type PredicateDialer struct {
+	Config comm.ClientConfig
+}
+
+// NewTLSPinningDialer creates a new PredicateDialer that uses
+// the given client configuration to connect to remote nodes
+func NewTLSPinningDialer(config comm.ClientConfig) *PredicateDialer {
+	return &PredicateDialer{Config: config}
+}
+
+// Dial creates a new gRPC connection that can only be established, if the remote node's
+// certificate chain satisfy verifyFunc
+func (dialer *PredicateDialer) Dial(address string, verifyFunc RemoteVerifier) (*grpc.ClientConn, error) {
+	cfg := dialer.Config
+	if cfg.SecOpts != nil {
+		secOpts := *cfg.SecOpts
+		secOpts.VerifyCertificate = verifyFunc
+		cfg.SecOpts = &secOpts
+	}
+	client, err := comm.NewGRPCClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return client.NewConnection(address, "")
+}
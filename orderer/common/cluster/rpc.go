@@ -0,0 +1,55 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cluster
+
+import (
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/orderer"
+	"github.com/pkg/errors"
+)
+
+// RPC performs remote procedure calls to remote cluster nodes
+// in the context of a channel.
+type RPC struct {
+	Channel string
+	Comm    Communicator
+}
+
+// Step sends a StepRequest to the given destination node and returns the response
+func (s *RPC) Step(destination uint64, msg *orderer.StepRequest) (*orderer.StepResponse, error) {
+	stub, err := s.Comm.Remote(s.Channel, destination)
+	if err != nil {
+		return nil, err
+	}
+	return stub.Step(msg)
+}
+
+// SendSubmit sends a SubmitRequest to the given destination node, and fails
+// if the destination node doesn't report success
+func (s *RPC) SendSubmit(destination uint64, request *orderer.SubmitRequest) error {
+	stub, err := s.Comm.Remote(s.Channel, destination)
+	if err != nil {
+		return err
+	}
+	resp, err := stub.Submit(request)
+	if err != nil {
+		return err
+	}
+	if resp.Status != common.Status_SUCCESS {
+		return errors.Errorf("node %d rejected the request with status %s: %s", destination, resp.Status, resp.Info)
+	}
+	return nil
+}
+
+// Pull sends a PullRequest to the given destination node and returns the blocks it sent back
+func (s *RPC) Pull(destination uint64, request *orderer.PullRequest) (*orderer.PullResponse, error) {
+	stub, err := s.Comm.Remote(s.Channel, destination)
+	if err != nil {
+		return nil, err
+	}
+	return stub.Pull(request)
+}
@@ -0,0 +1,43 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cluster
+
+import (
+	"context"
+
+	"github.com/hyperledger/fabric/protos/orderer"
+)
+
+// Dispatcher dispatches requests
+type Dispatcher interface {
+	DispatchStep(ctx context.Context, request *orderer.StepRequest) (*orderer.StepResponse, error)
+	DispatchSubmit(ctx context.Context, request *orderer.SubmitRequest) (*orderer.SubmitResponse, error)
+	DispatchPull(ctx context.Context, request *orderer.PullRequest) (*orderer.PullResponse, error)
+}
+
+// Service defines the raft Service
+type Service struct {
+	Dispatcher Dispatcher
+}
+
+// Step forwards a message to a raft FSM located in this server
+func (s *Service) Step(ctx context.Context, request *orderer.StepRequest) (*orderer.StepResponse, error) {
+	logger.Debugf("Received Step request for channel %s", request.Channel)
+	return s.Dispatcher.DispatchStep(ctx, request)
+}
+
+// Submit accepts transactions forwarded by other cluster members
+func (s *Service) Submit(ctx context.Context, request *orderer.SubmitRequest) (*orderer.SubmitResponse, error) {
+	logger.Debugf("Received Submit request for channel %s", request.Channel)
+	return s.Dispatcher.DispatchSubmit(ctx, request)
+}
+
+// Pull serves committed blocks to other cluster members
+func (s *Service) Pull(ctx context.Context, request *orderer.PullRequest) (*orderer.PullResponse, error) {
+	logger.Debugf("Received Pull request for channel %s, blocks [%d, %d]", request.Channel, request.Start, request.End)
+	return s.Dispatcher.DispatchPull(ctx, request)
+}
@@ -33,7 +33,10 @@ type TopLevel struct {
 	FileLedger FileLedger
 	RAMLedger  RAMLedger
 	Kafka      Kafka
+	EtcdRaft   EtcdRaft
 	Debug      Debug
+	Operations Operations
+	Metrics    Metrics
 }
 
 // General contains config which should be common among all orderer types.
@@ -42,6 +45,7 @@ type General struct {
 	ListenAddress  string
 	ListenPort     uint16
 	TLS            TLS
+	Cluster        Cluster
 	Keepalive      Keepalive
 	GenesisMethod  string
 	GenesisProfile string
@@ -56,6 +60,16 @@ type General struct {
 	Authentication Authentication
 }
 
+// Cluster contains configuration for the communication between the
+// members of a cluster-based consensus (e.g. etcd/raft).
+type Cluster struct {
+	ClientCertificate string
+	ClientPrivateKey  string
+	RootCAs           []string
+	DialTimeout       time.Duration
+	RPCTimeout        time.Duration
+}
+
 // Keepalive contains configuration for gRPC servers.
 type Keepalive struct {
 	ServerMinInterval time.Duration
@@ -79,9 +93,11 @@ type Authentication struct {
 	TimeWindow time.Duration
 }
 
-// Profile contains configuration for Go pprof profiling.
+// Profile contains configuration for Go pprof profiling. When enabled, the
+// profiles are served by the operations endpoint.
 type Profile struct {
 	Enabled bool
+	// Deprecated: the profiles are served on Operations.ListenAddress
 	Address string
 }
 
@@ -147,6 +163,34 @@ type Consumer struct {
 	RetryBackoff time.Duration
 }
 
+// EtcdRaft contains configuration for the etcd/raft-based orderer.
+type EtcdRaft struct {
+	WALDir  string
+	SnapDir string
+}
+
+// Operations configures the operations endpoint of the orderer, which serves
+// the health checks, the logging specification and the metrics over HTTP(S).
+type Operations struct {
+	ListenAddress string
+	TLS           TLS
+}
+
+// Metrics configures the metrics of the orderer.
+type Metrics struct {
+	Enabled  bool
+	Reporter string
+	Interval time.Duration
+	Statsd   Statsd
+}
+
+// Statsd contains configuration of the statsd metrics reporter.
+type Statsd struct {
+	Address       string
+	FlushInterval time.Duration
+	FlushBytes    int
+}
+
 // Debug contains configuration for the orderer's debug parameters.
 type Debug struct {
 	BroadcastTraceDir string
@@ -165,10 +209,13 @@ var Defaults = TopLevel{
 		GenesisFile:    "genesisblock",
 		Profile: Profile{
 			Enabled: false,
-			Address: "0.0.0.0:6060",
 		},
-		LogLevel:    "INFO",
-		LogFormat:   "%{color}%{time:2006-01-02 15:04:05.000 MST} [%{module}] %{shortfunc} -> %{level:.4s} %{id:03x}%{color:reset} %{message}",
+		LogLevel:  "INFO",
+		LogFormat: "%{color}%{time:2006-01-02 15:04:05.000 MST} [%{module}] %{shortfunc} -> %{level:.4s} %{id:03x}%{color:reset} %{message}",
+		Cluster: Cluster{
+			DialTimeout: 5 * time.Second,
+			RPCTimeout:  7 * time.Second,
+		},
 		LocalMSPDir: "msp",
 		LocalMSPID:  "SampleOrg",
 		BCCSP:       bccsp.GetDefaultOpts(),
@@ -212,10 +259,23 @@ var Defaults = TopLevel{
 			Enabled: false,
 		},
 	},
+	EtcdRaft: EtcdRaft{
+		WALDir:  "/var/hyperledger/production/orderer/etcdraft/wal",
+		SnapDir: "/var/hyperledger/production/orderer/etcdraft/snapshot",
+	},
 	Debug: Debug{
 		BroadcastTraceDir: "",
 		DeliverTraceDir:   "",
 	},
+	Metrics: Metrics{
+		Enabled:  false,
+		Reporter: "prom",
+		Interval: time.Second,
+		Statsd: Statsd{
+			FlushInterval: 2 * time.Second,
+			FlushBytes:    1432,
+		},
+	},
 }
 
 // Load parses the orderer YAML file and environment, producing
@@ -246,8 +306,14 @@ func (c *TopLevel) completeInitialization(configDir string) {
 		// Translate any paths
 		c.General.TLS.RootCAs = translateCAs(configDir, c.General.TLS.RootCAs)
 		c.General.TLS.ClientRootCAs = translateCAs(configDir, c.General.TLS.ClientRootCAs)
+		c.General.Cluster.RootCAs = translateCAs(configDir, c.General.Cluster.RootCAs)
+		c.Operations.TLS.ClientRootCAs = translateCAs(configDir, c.Operations.TLS.ClientRootCAs)
+		coreconfig.TranslatePathInPlace(configDir, &c.General.Cluster.ClientCertificate)
+		coreconfig.TranslatePathInPlace(configDir, &c.General.Cluster.ClientPrivateKey)
 		coreconfig.TranslatePathInPlace(configDir, &c.General.TLS.PrivateKey)
 		coreconfig.TranslatePathInPlace(configDir, &c.General.TLS.Certificate)
+		coreconfig.TranslatePathInPlace(configDir, &c.Operations.TLS.PrivateKey)
+		coreconfig.TranslatePathInPlace(configDir, &c.Operations.TLS.Certificate)
 		coreconfig.TranslatePathInPlace(configDir, &c.General.GenesisFile)
 		coreconfig.TranslatePathInPlace(configDir, &c.General.LocalMSPDir)
 	}()
@@ -288,9 +354,27 @@ func (c *TopLevel) completeInitialization(configDir string) {
 		case c.Kafka.TLS.Enabled && c.Kafka.TLS.RootCAs == nil:
 			logger.Panicf("General.Kafka.TLS.CertificatePool must be set if General.Kafka.TLS.Enabled is set to true.")
 
-		case c.General.Profile.Enabled && c.General.Profile.Address == "":
-			logger.Infof("Profiling enabled and General.Profile.Address unset, setting to %s", Defaults.General.Profile.Address)
-			c.General.Profile.Address = Defaults.General.Profile.Address
+		case c.General.Profile.Address != "":
+			logger.Warningf("General.Profile.Address is deprecated and ignored, the profiles are served on Operations.ListenAddress")
+			c.General.Profile.Address = ""
+
+		case c.Operations.TLS.Enabled && c.Operations.TLS.Certificate == "":
+			logger.Panicf("Operations.TLS.Certificate must be set if Operations.TLS.Enabled is set to true.")
+		case c.Operations.TLS.Enabled && c.Operations.TLS.PrivateKey == "":
+			logger.Panicf("Operations.TLS.PrivateKey must be set if Operations.TLS.Enabled is set to true.")
+
+		case c.Metrics.Reporter == "":
+			logger.Infof("Metrics.Reporter unset, setting to %s", Defaults.Metrics.Reporter)
+			c.Metrics.Reporter = Defaults.Metrics.Reporter
+		case c.Metrics.Interval == 0:
+			logger.Infof("Metrics.Interval unset, setting to %s", Defaults.Metrics.Interval)
+			c.Metrics.Interval = Defaults.Metrics.Interval
+		case c.Metrics.Statsd.FlushInterval == 0:
+			logger.Infof("Metrics.Statsd.FlushInterval unset, setting to %s", Defaults.Metrics.Statsd.FlushInterval)
+			c.Metrics.Statsd.FlushInterval = Defaults.Metrics.Statsd.FlushInterval
+		case c.Metrics.Statsd.FlushBytes == 0:
+			logger.Infof("Metrics.Statsd.FlushBytes unset, setting to %d", Defaults.Metrics.Statsd.FlushBytes)
+			c.Metrics.Statsd.FlushBytes = Defaults.Metrics.Statsd.FlushBytes
 
 		case c.General.LocalMSPDir == "":
 			logger.Infof("General.LocalMSPDir unset, setting to %s", Defaults.General.LocalMSPDir)
@@ -299,6 +383,13 @@ func (c *TopLevel) completeInitialization(configDir string) {
 			logger.Infof("General.LocalMSPID unset, setting to %s", Defaults.General.LocalMSPID)
 			c.General.LocalMSPID = Defaults.General.LocalMSPID
 
+		case c.General.Cluster.DialTimeout == 0:
+			logger.Infof("General.Cluster.DialTimeout unset, setting to %v", Defaults.General.Cluster.DialTimeout)
+			c.General.Cluster.DialTimeout = Defaults.General.Cluster.DialTimeout
+		case c.General.Cluster.RPCTimeout == 0:
+			logger.Infof("General.Cluster.RPCTimeout unset, setting to %v", Defaults.General.Cluster.RPCTimeout)
+			c.General.Cluster.RPCTimeout = Defaults.General.Cluster.RPCTimeout
+
 		case c.General.Authentication.TimeWindow == 0:
 			logger.Infof("General.Authentication.TimeWindow unset, setting to %s", Defaults.General.Authentication.TimeWindow)
 			c.General.Authentication.TimeWindow = Defaults.General.Authentication.TimeWindow
@@ -352,6 +443,13 @@ func (c *TopLevel) completeInitialization(configDir string) {
 			logger.Infof("Kafka.Version unset, setting to %v", Defaults.Kafka.Version)
 			c.Kafka.Version = Defaults.Kafka.Version
 
+		case c.EtcdRaft.WALDir == "":
+			logger.Infof("EtcdRaft.WALDir unset, setting to %v", Defaults.EtcdRaft.WALDir)
+			c.EtcdRaft.WALDir = Defaults.EtcdRaft.WALDir
+		case c.EtcdRaft.SnapDir == "":
+			logger.Infof("EtcdRaft.SnapDir unset, setting to %v", Defaults.EtcdRaft.SnapDir)
+			c.EtcdRaft.SnapDir = Defaults.EtcdRaft.SnapDir
+
 		default:
 			return
 		}
@@ -8,6 +8,7 @@ package msgprocessor
 
 import (
 	"errors"
+	"fmt"
 
 	ab "github.com/hyperledger/fabric/protos/common"
 )
@@ -56,11 +57,36 @@ func NewRuleSet(rules []Rule) *RuleSet {
 
 // Apply applies the rules given for this set in order, returning nil on valid or err on invalid
 func (rs *RuleSet) Apply(message *ab.Envelope) error {
+	_, err := rs.apply(message)
+	return err
+}
+
+// apply applies the rules given for this set in order, returning nil on valid,
+// or the name of the rejecting rule and its error on invalid
+func (rs *RuleSet) apply(message *ab.Envelope) (string, error) {
 	for _, rule := range rs.rules {
 		err := rule.Apply(message)
 		if err != nil {
-			return err
+			return ruleName(rule), err
 		}
 	}
-	return nil
+	return "", nil
+}
+
+// ruleName returns the name by which the given rule is reported in the metrics
+func ruleName(rule Rule) string {
+	switch rule.(type) {
+	case emptyRejectRule:
+		return "empty_reject"
+	case *expirationRejectRule:
+		return "expiration_reject"
+	case *MaxBytesRule:
+		return "max_bytes"
+	case *SigFilter:
+		return "signature"
+	case *SystemChainFilter:
+		return "system_channel"
+	default:
+		return fmt.Sprintf("%T", rule)
+	}
 }
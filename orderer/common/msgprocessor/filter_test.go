@@ -50,3 +50,17 @@ func TestRuleSet(t *testing.T) {
 		assert.Nil(t, NewRuleSet(nil).Apply(&cb.Envelope{}))
 	})
 }
+
+func TestRuleSetRejectingRule(t *testing.T) {
+	filter, err := NewRuleSet([]Rule{AcceptRule, EmptyRejectRule, RejectRule}).apply(&cb.Envelope{})
+	assert.EqualError(t, err, ErrEmptyMessage.Error())
+	assert.Equal(t, "empty_reject", filter)
+
+	filter, err = NewRuleSet([]Rule{AcceptRule, EmptyRejectRule, RejectRule}).apply(&cb.Envelope{Payload: []byte("fakedata")})
+	assert.EqualError(t, err, "Rejected")
+	assert.Equal(t, "msgprocessor.rejectRule", filter)
+
+	filter, err = NewRuleSet([]Rule{AcceptRule}).apply(&cb.Envelope{})
+	assert.NoError(t, err)
+	assert.Empty(t, filter)
+}
@@ -0,0 +1,27 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msgprocessor
+
+import (
+	"github.com/hyperledger/fabric/common/metrics"
+)
+
+// processorMetrics holds the metrics of the message processor of a channel
+type processorMetrics struct {
+	scope metrics.Scope
+}
+
+func newProcessorMetrics(chainID string) *processorMetrics {
+	return &processorMetrics{
+		scope: metrics.GetRootScope().SubScope("msgprocessor").Tagged(map[string]string{"channel": chainID}),
+	}
+}
+
+// rejectedEnvelopes returns the counter of the envelopes rejected by the given filter
+func (m *processorMetrics) rejectedEnvelopes(filter string) metrics.Counter {
+	return m.scope.Tagged(map[string]string{"filter": filter}).Counter("rejected_envelopes")
+}
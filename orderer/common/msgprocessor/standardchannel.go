@@ -34,6 +34,7 @@ type StandardChannelSupport interface {
 type StandardChannel struct {
 	support StandardChannelSupport
 	filters *RuleSet
+	metrics *processorMetrics
 }
 
 // NewStandardChannel creates a new standard message processor
@@ -41,6 +42,7 @@ func NewStandardChannel(support StandardChannelSupport, filters *RuleSet) *Stand
 	return &StandardChannel{
 		filters: filters,
 		support: support,
+		metrics: newProcessorMetrics(support.ChainID()),
 	}
 }
 
@@ -58,6 +60,16 @@ func CreateStandardChannelFilters(filterSupport channelconfig.Resources) *RuleSe
 	})
 }
 
+// applyFilters applies the filters of the channel to the message,
+// and accounts for the rejection of the message by one of the filters
+func (s *StandardChannel) applyFilters(env *cb.Envelope) error {
+	filter, err := s.filters.apply(env)
+	if err != nil {
+		s.metrics.rejectedEnvelopes(filter).Inc(1)
+	}
+	return err
+}
+
 // ClassifyMsg inspects the message to determine which type of processing is necessary
 func (s *StandardChannel) ClassifyMsg(chdr *cb.ChannelHeader) Classification {
 	switch chdr.Type {
@@ -78,7 +90,7 @@ func (s *StandardChannel) ClassifyMsg(chdr *cb.ChannelHeader) Classification {
 // configuration sequence number and nil on success, or an error if the message is not valid
 func (s *StandardChannel) ProcessNormalMsg(env *cb.Envelope) (configSeq uint64, err error) {
 	configSeq = s.support.Sequence()
-	err = s.filters.Apply(env)
+	err = s.applyFilters(env)
 	return
 }
 
@@ -91,7 +103,7 @@ func (s *StandardChannel) ProcessConfigUpdateMsg(env *cb.Envelope) (config *cb.E
 	// Call Sequence first.  If seq advances between proposal and acceptance, this is okay, and will cause reprocessing
 	// however, if Sequence is called last, then a success could be falsely attributed to a newer configSeq
 	seq := s.support.Sequence()
-	err = s.filters.Apply(env)
+	err = s.applyFilters(env)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -111,7 +123,7 @@ func (s *StandardChannel) ProcessConfigUpdateMsg(env *cb.Envelope) (config *cb.E
 	// check, which although not strictly necessary, is a good sanity check, in case the orderer
 	// has not been configured with the right cert material.  The additional overhead of the signature
 	// check is negligable, as this is the reconfig path and not the normal path.
-	err = s.filters.Apply(config)
+	err = s.applyFilters(config)
 	if err != nil {
 		return nil, 0, err
 	}
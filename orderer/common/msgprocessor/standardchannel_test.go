@@ -11,6 +11,7 @@ import (
 	"testing"
 
 	"github.com/hyperledger/fabric/common/crypto"
+	"github.com/hyperledger/fabric/common/metrics"
 	cb "github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/stretchr/testify/assert"
@@ -68,6 +69,28 @@ func TestProcessNormalMsg(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestRejectedEnvelopesMetrics(t *testing.T) {
+	testScope, restore := metrics.UseTestScope()
+	defer restore()
+
+	rejectedEnvelopes := func(filter string) int64 {
+		return testScope.CounterValue("msgprocessor.rejected_envelopes", map[string]string{"channel": testChannelID, "filter": filter})
+	}
+
+	sc := NewStandardChannel(&mockSystemChannelFilterSupport{}, NewRuleSet([]Rule{EmptyRejectRule, AcceptRule}))
+	_, err := sc.ProcessNormalMsg(&cb.Envelope{})
+	assert.Equal(t, ErrEmptyMessage, err)
+	_, err = sc.ProcessNormalMsg(&cb.Envelope{})
+	assert.Equal(t, ErrEmptyMessage, err)
+	_, err = sc.ProcessNormalMsg(&cb.Envelope{Payload: []byte("fakedata")})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), rejectedEnvelopes("empty_reject"))
+
+	_, _, err = sc.ProcessConfigUpdateMsg(&cb.Envelope{})
+	assert.Equal(t, ErrEmptyMessage, err)
+	assert.Equal(t, int64(3), rejectedEnvelopes("empty_reject"))
+}
+
 func TestConfigUpdateMsg(t *testing.T) {
 	t.Run("BadMsg", func(t *testing.T) {
 		ms := &mockSystemChannelFilterSupport{
@@ -39,6 +39,7 @@ type BlockWriter struct {
 	lastConfigSeq      uint64
 	lastBlock          *cb.Block
 	committingBlock    sync.Mutex
+	metrics            *chainMetrics
 }
 
 func newBlockWriter(lastBlock *cb.Block, r *Registrar, support blockWriterSupport) *BlockWriter {
@@ -47,7 +48,9 @@ func newBlockWriter(lastBlock *cb.Block, r *Registrar, support blockWriterSuppor
 		lastConfigSeq: support.Sequence(),
 		lastBlock:     lastBlock,
 		registrar:     r,
+		metrics:       newChainMetrics(support.ChainID()),
 	}
+	bw.metrics.blockHeight.Update(float64(lastBlock.Header.Number + 1))
 
 	// If this is the genesis block, the lastconfig field may be empty, and, the last config block is necessarily block 0
 	// so no need to initialize lastConfig
@@ -170,6 +173,7 @@ func (bw *BlockWriter) commitBlock(encodedMetadataValue []byte) {
 	if err != nil {
 		logger.Panicf("[channel: %s] Could not append block: %s", bw.support.ChainID(), err)
 	}
+	bw.metrics.blockHeight.Update(float64(bw.lastBlock.Header.Number + 1))
 	logger.Debugf("[channel: %s] Wrote block %d", bw.support.ChainID(), bw.lastBlock.GetHeader().Number)
 }
 
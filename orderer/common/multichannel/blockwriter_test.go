@@ -22,6 +22,7 @@ import (
 	newchannelconfig "github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/crypto"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
+	"github.com/hyperledger/fabric/common/metrics"
 	mockconfigtx "github.com/hyperledger/fabric/common/mocks/configtx"
 	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
 	cb "github.com/hyperledger/fabric/protos/common"
@@ -173,6 +174,7 @@ func TestGoodWriteConfig(t *testing.T) {
 			ReadWriter:  l,
 			Validator:   &mockconfigtx.Validator{},
 		},
+		metrics: newChainMetrics(genesisconfig.TestChainID),
 	}
 
 	ctx := makeConfigTx(genesisconfig.TestChainID, 1)
@@ -192,3 +194,31 @@ func TestGoodWriteConfig(t *testing.T) {
 	omd := utils.GetMetadataFromBlockOrPanic(block, cb.BlockMetadataIndex_ORDERER)
 	assert.Equal(t, consenterMetadata, omd.Value)
 }
+
+func TestBlockHeightMetrics(t *testing.T) {
+	testScope, restore := metrics.UseTestScope()
+	defer restore()
+
+	blockHeight := func() float64 {
+		return testScope.GaugeValue("ledger.block_height", map[string]string{"channel": genesisconfig.TestChainID})
+	}
+
+	l := NewRAMLedger(10)
+	bw := newBlockWriter(genesisBlock, nil, &mockBlockWriterSupport{
+		LocalSigner: mockCrypto(),
+		ReadWriter:  l,
+		Validator:   &mockconfigtx.Validator{ChainIDVal: genesisconfig.TestChainID},
+	})
+	assert.Equal(t, float64(1), blockHeight())
+
+	for i := 0; i < 2; i++ {
+		bw.WriteBlock(bw.CreateNextBlock([]*cb.Envelope{makeNormalTx(genesisconfig.TestChainID, i)}), nil)
+	}
+
+	// Wait for the commit to complete
+	bw.committingBlock.Lock()
+	bw.committingBlock.Unlock()
+
+	assert.Equal(t, l.Height(), uint64(3))
+	assert.Equal(t, float64(3), blockHeight())
+}
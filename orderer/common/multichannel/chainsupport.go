@@ -48,7 +48,7 @@ func newChainSupport(
 	cs := &ChainSupport{
 		ledgerResources: ledgerResources,
 		LocalSigner:     signer,
-		cutter:          blockcutter.NewReceiverImpl(ledgerResources.SharedConfig()),
+		cutter:          blockcutter.NewReceiverImpl(ledgerResources.ConfigtxValidator().ChainID(), ledgerResources.SharedConfig()),
 	}
 
 	// Set up the msgprocessor
@@ -130,3 +130,12 @@ func (cs *ChainSupport) ConfigProto() *cb.Config {
 func (cs *ChainSupport) Sequence() uint64 {
 	return cs.ConfigtxValidator().Sequence()
 }
+
+// Block returns a block with the given number,
+// or nil if such a block doesn't exist.
+func (cs *ChainSupport) Block(number uint64) *cb.Block {
+	if cs.Height() <= number {
+		return nil
+	}
+	return blockledger.GetBlock(cs.Reader(), number)
+}
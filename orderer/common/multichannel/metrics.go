@@ -0,0 +1,23 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package multichannel
+
+import (
+	"github.com/hyperledger/fabric/common/metrics"
+)
+
+// chainMetrics holds the metrics of the ledger of a channel
+type chainMetrics struct {
+	blockHeight metrics.Gauge
+}
+
+func newChainMetrics(chainID string) *chainMetrics {
+	scope := metrics.GetRootScope().SubScope("ledger").Tagged(map[string]string{"channel": chainID})
+	return &chainMetrics{
+		blockHeight: scope.Gauge("block_height"),
+	}
+}
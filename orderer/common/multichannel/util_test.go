@@ -75,7 +75,7 @@ func (mch *mockChain) Start() {
 			class := mch.support.ClassifyMsg(chdr)
 			switch class {
 			case msgprocessor.ConfigMsg:
-				batch := mch.support.BlockCutter().Cut()
+				batch := mch.support.BlockCutter().Cut(blockcutter.CutConfigIsolation)
 				if batch != nil {
 					block := mch.support.CreateNextBlock(batch)
 					mch.support.WriteBlock(block, nil)
@@ -8,24 +8,27 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net"
-	"net/http"
-	_ "net/http/pprof" // This is essentially the main package for the orderer
 	"os"
 	"time"
 
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/crypto"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/healthz"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
+	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/common/tools/configtxgen/encoder"
 	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
 	"github.com/hyperledger/fabric/core/comm"
+	"github.com/hyperledger/fabric/core/operations"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/orderer/common/bootstrap/file"
+	"github.com/hyperledger/fabric/orderer/common/cluster"
 	"github.com/hyperledger/fabric/orderer/common/localconfig"
 	"github.com/hyperledger/fabric/orderer/common/metadata"
 	"github.com/hyperledger/fabric/orderer/common/multichannel"
 	"github.com/hyperledger/fabric/orderer/consensus"
+	"github.com/hyperledger/fabric/orderer/consensus/etcdraft"
 	"github.com/hyperledger/fabric/orderer/consensus/kafka"
 	"github.com/hyperledger/fabric/orderer/consensus/solo"
 	cb "github.com/hyperledger/fabric/protos/common"
@@ -97,15 +100,28 @@ func Start(cmd string, conf *localconfig.TopLevel) {
 		}
 	}
 
-	manager := initializeMultichannelRegistrar(conf, signer, tlsCallback)
+	clusterClientConfig := initializeClusterClientConfig(conf)
+	clusterComm := cluster.NewComm(cluster.NewTLSPinningDialer(clusterClientConfig), conf.General.Cluster.RPCTimeout)
+	var clusterCert []byte
+	if clusterClientConfig.SecOpts != nil {
+		clusterCert = clusterClientConfig.SecOpts.Certificate
+	}
+
+	opsSystem := newOperationsSystem(conf)
+	if cmd == start.FullCommand() {
+		// the operations endpoint initializes the metrics,
+		// so it has to be started before the channels are created
+		startOperationsSystem(conf, opsSystem)
+	}
+	manager := initializeMultichannelRegistrar(conf, signer, clusterComm, clusterCert, opsSystem, tlsCallback)
 	mutualTLS := serverConfig.SecOpts.UseTLS && serverConfig.SecOpts.RequireClientCert
 	server := NewServer(manager, signer, &conf.Debug, conf.General.Authentication.TimeWindow, mutualTLS)
 
 	switch cmd {
 	case start.FullCommand(): // "start" command
 		logger.Infof("Starting %s", metadata.GetVersionInfo())
-		initializeProfilingService(conf)
 		ab.RegisterAtomicBroadcastServer(grpcServer.Server(), server)
+		ab.RegisterClusterServer(grpcServer.Server(), &cluster.Service{Dispatcher: clusterComm})
 		logger.Info("Beginning to serve requests")
 		grpcServer.Start()
 	case benchmark.FullCommand(): // "benchmark" command
@@ -122,14 +138,46 @@ func initializeLoggingLevel(conf *localconfig.TopLevel) {
 	flogging.InitFromSpec(conf.General.LogLevel)
 }
 
-// Start the profiling service if enabled.
-func initializeProfilingService(conf *localconfig.TopLevel) {
-	if conf.General.Profile.Enabled {
-		go func() {
-			logger.Info("Starting Go pprof profiling service on:", conf.General.Profile.Address)
-			// The ListenAndServe() call does not return unless an error occurs.
-			logger.Panic("Go pprof service failed:", http.ListenAndServe(conf.General.Profile.Address, nil))
-		}()
+// healthChecker registers the health checks of the orderer's components
+type healthChecker interface {
+	RegisterChecker(component string, checker healthz.HealthChecker) error
+}
+
+// newOperationsSystem creates the operations endpoint of the orderer, which
+// serves the health checks, the logging specification, the metrics and, if
+// enabled, the Go pprof profiles.
+func newOperationsSystem(conf *localconfig.TopLevel) *operations.System {
+	return operations.NewSystem(operations.Options{
+		ListenAddress: conf.Operations.ListenAddress,
+		TLS: operations.TLS{
+			Enabled:            conf.Operations.TLS.Enabled,
+			CertFile:           conf.Operations.TLS.Certificate,
+			KeyFile:            conf.Operations.TLS.PrivateKey,
+			ClientCertRequired: conf.Operations.TLS.ClientAuthRequired,
+			ClientCACertFiles:  conf.Operations.TLS.ClientRootCAs,
+		},
+		Metrics: metrics.Opts{
+			Enabled:  conf.Metrics.Enabled,
+			Reporter: conf.Metrics.Reporter,
+			Interval: conf.Metrics.Interval,
+			StatsdReporterOpts: metrics.StatsdReporterOpts{
+				Address:       conf.Metrics.Statsd.Address,
+				FlushInterval: conf.Metrics.Statsd.FlushInterval,
+				FlushBytes:    conf.Metrics.Statsd.FlushBytes,
+			},
+		},
+		Profiling: conf.General.Profile.Enabled,
+	})
+}
+
+// Start the operations endpoint if a listen address is configured.
+func startOperationsSystem(conf *localconfig.TopLevel, opsSystem *operations.System) {
+	if conf.Operations.ListenAddress == "" {
+		logger.Info("Operations.ListenAddress unset, the operations endpoint is disabled")
+		return
+	}
+	if err := opsSystem.Start(); err != nil {
+		logger.Panicf("Failed to start the operations endpoint: %s", err)
 	}
 }
 
@@ -191,6 +239,60 @@ func initializeServerConfig(conf *localconfig.TopLevel) comm.ServerConfig {
 	return comm.ServerConfig{SecOpts: secureOpts, KaOpts: kaOpts}
 }
 
+// initializeClusterClientConfig returns the configuration of the client side of the
+// mutual TLS connections between ordering service nodes of a cluster
+func initializeClusterClientConfig(conf *localconfig.TopLevel) comm.ClientConfig {
+	cc := comm.ClientConfig{
+		KaOpts:  comm.DefaultKeepaliveOptions,
+		Timeout: conf.General.Cluster.DialTimeout,
+		SecOpts: &comm.SecureOptions{},
+	}
+
+	if !conf.General.TLS.Enabled {
+		return cc
+	}
+
+	certFile := conf.General.Cluster.ClientCertificate
+	if certFile == "" {
+		certFile = conf.General.TLS.Certificate
+	}
+	keyFile := conf.General.Cluster.ClientPrivateKey
+	if keyFile == "" {
+		keyFile = conf.General.TLS.PrivateKey
+	}
+	rootCAFiles := conf.General.Cluster.RootCAs
+	if len(rootCAFiles) == 0 {
+		rootCAFiles = conf.General.TLS.RootCAs
+	}
+
+	certBytes, err := ioutil.ReadFile(certFile)
+	if err != nil {
+		logger.Fatalf("Failed to load cluster client certificate file '%s' (%s)", certFile, err)
+	}
+	keyBytes, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		logger.Fatalf("Failed to load cluster client private key file '%s' (%s)", keyFile, err)
+	}
+
+	var serverRootCAs [][]byte
+	for _, serverRoot := range rootCAFiles {
+		rootCACert, err := ioutil.ReadFile(serverRoot)
+		if err != nil {
+			logger.Fatalf("Failed to load cluster root CA file '%s' (%s)", serverRoot, err)
+		}
+		serverRootCAs = append(serverRootCAs, rootCACert)
+	}
+
+	cc.SecOpts = &comm.SecureOptions{
+		UseTLS:            true,
+		RequireClientCert: true,
+		Certificate:       certBytes,
+		Key:               keyBytes,
+		ServerRootCAs:     serverRootCAs,
+	}
+	return cc
+}
+
 func initializeBootstrapChannel(conf *localconfig.TopLevel, lf blockledger.Factory) {
 	var genesisBlock *cb.Block
 
@@ -243,6 +345,7 @@ func initializeLocalMsp(conf *localconfig.TopLevel) {
 }
 
 func initializeMultichannelRegistrar(conf *localconfig.TopLevel, signer crypto.LocalSigner,
+	clusterComm *cluster.Comm, clusterCert []byte, healthChecker healthChecker,
 	callbacks ...func(bundle *channelconfig.Bundle)) *multichannel.Registrar {
 	lf, _ := createLedgerFactory(conf)
 	// Are we bootstrapping?
@@ -254,7 +357,11 @@ func initializeMultichannelRegistrar(conf *localconfig.TopLevel, signer crypto.L
 
 	consenters := make(map[string]consensus.Consenter)
 	consenters["solo"] = solo.New()
-	consenters["kafka"] = kafka.New(conf.Kafka)
+	consenters["kafka"] = kafka.New(conf.Kafka, healthChecker)
+	consenters["etcdraft"] = etcdraft.New(clusterComm, clusterCert, etcdraft.Config{
+		WALDir:  conf.EtcdRaft.WALDir,
+		SnapDir: conf.EtcdRaft.SnapDir,
+	})
 
 	return multichannel.NewRegistrar(lf, consenters, signer, callbacks...)
 }
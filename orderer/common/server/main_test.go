@@ -22,6 +22,7 @@ import (
 	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
 	"github.com/hyperledger/fabric/core/comm"
 	"github.com/hyperledger/fabric/core/config/configtest"
+	"github.com/hyperledger/fabric/orderer/common/cluster"
 	"github.com/hyperledger/fabric/orderer/common/localconfig"
 	"github.com/op/go-logging"
 	"github.com/stretchr/testify/assert"
@@ -40,32 +41,36 @@ func TestInitializeLoggingLevel(t *testing.T) {
 	assert.Equal(t, flogging.GetModuleLevel("foo"), "DEBUG")
 }
 
-func TestInitializeProfilingService(t *testing.T) {
+func TestStartOperationsSystem(t *testing.T) {
 	// get a free random port
 	listenAddr := func() string {
 		l, _ := net.Listen("tcp", "localhost:0")
 		l.Close()
 		return l.Addr().String()
 	}()
-	initializeProfilingService(
-		&localconfig.TopLevel{
-			General: localconfig.General{
-				LogLevel: "debug",
-				Profile: localconfig.Profile{
-					Enabled: true,
-					Address: listenAddr,
-				}},
-			Kafka: localconfig.Kafka{Verbose: true},
+	conf := &localconfig.TopLevel{
+		General: localconfig.General{
+			LogLevel: "debug",
+			Profile:  localconfig.Profile{Enabled: true},
 		},
-	)
-	time.Sleep(500 * time.Millisecond)
-	if _, err := http.Get("http://" + listenAddr + "/" + "/debug/"); err != nil {
-		t.Logf("Expected pprof to be up (will retry again in 3 seconds): %s", err)
-		time.Sleep(3 * time.Second)
-		if _, err := http.Get("http://" + listenAddr + "/" + "/debug/"); err != nil {
-			t.Fatalf("Expected pprof to be up: %s", err)
-		}
+		Operations: localconfig.Operations{ListenAddress: listenAddr},
 	}
+	opsSystem := newOperationsSystem(conf)
+	startOperationsSystem(conf, opsSystem)
+	defer opsSystem.Stop()
+
+	for _, path := range []string{"/healthz", "/logspec", "/debug/pprof/"} {
+		resp, err := http.Get("http://" + listenAddr + path)
+		assert.NoError(t, err, "Expected %s to be served", path)
+		assert.Equal(t, http.StatusOK, resp.StatusCode, "Expected %s to be served", path)
+		resp.Body.Close()
+	}
+
+	// the operations endpoint is disabled without a listen address
+	conf.Operations.ListenAddress = ""
+	opsSystem = newOperationsSystem(conf)
+	startOperationsSystem(conf, opsSystem)
+	assert.Empty(t, opsSystem.Addr())
 }
 
 func TestInitializeServerConfig(t *testing.T) {
@@ -235,10 +240,40 @@ func TestInitializeMultiChainManager(t *testing.T) {
 	conf := genesisConfig(t)
 	assert.NotPanics(t, func() {
 		initializeLocalMsp(conf)
-		initializeMultichannelRegistrar(conf, localmsp.NewSigner())
+		initializeMultichannelRegistrar(conf, localmsp.NewSigner(), newClusterComm(), nil, nil)
 	})
 }
 
+func TestInitializeClusterClientConfig(t *testing.T) {
+	conf := &localconfig.TopLevel{
+		General: localconfig.General{
+			TLS: localconfig.TLS{
+				Enabled: false,
+			},
+			Cluster: localconfig.Cluster{
+				DialTimeout: time.Second,
+			},
+		},
+	}
+	cc := initializeClusterClientConfig(conf)
+	assert.Equal(t, time.Second, cc.Timeout)
+	assert.False(t, cc.SecOpts.UseTLS)
+
+	// The cluster falls back to the TLS settings of the server
+	conf.General.TLS = localconfig.TLS{
+		Enabled:     true,
+		PrivateKey:  filepath.Join(".", "testdata", "tls", "server.key"),
+		Certificate: filepath.Join(".", "testdata", "tls", "server.crt"),
+	}
+	cc = initializeClusterClientConfig(conf)
+	expectedCert, err := ioutil.ReadFile(conf.General.TLS.Certificate)
+	assert.NoError(t, err)
+	assert.True(t, cc.SecOpts.UseTLS)
+	assert.True(t, cc.SecOpts.RequireClientCert)
+	assert.Equal(t, expectedCert, cc.SecOpts.Certificate)
+	assert.NotEmpty(t, cc.SecOpts.Key)
+}
+
 func TestInitializeGrpcServer(t *testing.T) {
 	// get a free random port
 	listenAddr := func() string {
@@ -296,7 +331,7 @@ func TestUpdateTrustedRoots(t *testing.T) {
 			updateTrustedRoots(grpcServer, caSupport, bundle)
 		}
 	}
-	initializeMultichannelRegistrar(genesisConfig(t), localmsp.NewSigner(), callback)
+	initializeMultichannelRegistrar(genesisConfig(t), localmsp.NewSigner(), newClusterComm(), nil, nil, callback)
 	t.Logf("# app CAs: %d", len(caSupport.AppRootCAsByChain[genesisconfig.TestChainID]))
 	t.Logf("# orderer CAs: %d", len(caSupport.OrdererRootCAsByChain[genesisconfig.TestChainID]))
 	// mutual TLS not required so no updates should have occurred
@@ -327,7 +362,7 @@ func TestUpdateTrustedRoots(t *testing.T) {
 			updateTrustedRoots(grpcServer, caSupport, bundle)
 		}
 	}
-	initializeMultichannelRegistrar(genesisConfig(t), localmsp.NewSigner(), callback)
+	initializeMultichannelRegistrar(genesisConfig(t), localmsp.NewSigner(), newClusterComm(), nil, nil, callback)
 	t.Logf("# app CAs: %d", len(caSupport.AppRootCAsByChain[genesisconfig.TestChainID]))
 	t.Logf("# orderer CAs: %d", len(caSupport.OrdererRootCAsByChain[genesisconfig.TestChainID]))
 	// mutual TLS is required so updates should have occurred
@@ -337,6 +372,10 @@ func TestUpdateTrustedRoots(t *testing.T) {
 	grpcServer.Listener().Close()
 }
 
+func newClusterComm() *cluster.Comm {
+	return cluster.NewComm(cluster.NewTLSPinningDialer(comm.ClientConfig{}), time.Second)
+}
+
 func genesisConfig(t *testing.T) *localconfig.TopLevel {
 	t.Helper()
 	localMSPDir, _ := configtest.GetDevMspDir()
@@ -0,0 +1,59 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package etcdraft
+
+import (
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/op/go-logging"
+)
+
+// blockCreator optimistically creates blocks in a chain. The created
+// blocks may not be written out eventually. This enables us to pipeline
+// the creation of blocks with achieving consensus on them leading to
+// performance improvements. The created chain is discarded if a
+// diverging block is committed
+type blockCreator struct {
+	hash   []byte
+	number uint64
+
+	logger *logging.Logger
+}
+
+func newBlockCreator(lastBlock *cb.Block, logger *logging.Logger) *blockCreator {
+	logger.Debugf("Initializing block creator with the tip of the chain at block [%d]", lastBlock.Header.Number)
+	return &blockCreator{
+		hash:   lastBlock.Header.Hash(),
+		number: lastBlock.Header.Number,
+		logger: logger,
+	}
+}
+
+// createNextBlock creates a new block with the next block number, and the given contents,
+// chained to the block that was created most recently.
+func (bc *blockCreator) createNextBlock(envs []*cb.Envelope) *cb.Block {
+	data := &cb.BlockData{
+		Data: make([][]byte, len(envs)),
+	}
+
+	var err error
+	for i, env := range envs {
+		data.Data[i], err = proto.Marshal(env)
+		if err != nil {
+			bc.logger.Panicf("Could not marshal envelope: %s", err)
+		}
+	}
+
+	bc.number++
+
+	block := cb.NewBlock(bc.number, bc.hash)
+	block.Header.DataHash = data.Hash()
+	block.Data = data
+
+	bc.hash = block.Header.Hash()
+	return block
+}
@@ -0,0 +1,1010 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package etcdraft
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/coreos/etcd/raft"
+	"github.com/coreos/etcd/raft/raftpb"
+	"github.com/coreos/etcd/wal"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/orderer/common/blockcutter"
+	"github.com/hyperledger/fabric/orderer/common/cluster"
+	"github.com/hyperledger/fabric/orderer/consensus"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/orderer/etcdraft"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/op/go-logging"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultSnapshotCatchUpEntries is the default number of entries
+	// to preserve in memory when a snapshot is taken. This is for
+	// slow followers to catch up.
+	DefaultSnapshotCatchUpEntries = uint64(20)
+
+	// maxPullBatch is the maximum number of blocks served
+	// to a lagging node in a single Pull response.
+	maxPullBatch = 100
+
+	// egressBufferSize is the number of raft messages that are buffered
+	// for each remote node before messages to it start being dropped.
+	egressBufferSize = 256
+
+	// pullRetryInterval is the time to wait between attempts
+	// to pull missing blocks from the other consenters.
+	pullRetryInterval = time.Second
+)
+
+// Configurator is used to configure the communication layer
+// when the chain starts.
+type Configurator interface {
+	Configure(channel string, newNodes []cluster.RemoteNode)
+}
+
+// RPC is used to mock the transport layer in tests.
+type RPC interface {
+	Step(dest uint64, msg *orderer.StepRequest) (*orderer.StepResponse, error)
+	SendSubmit(dest uint64, request *orderer.SubmitRequest) error
+	Pull(dest uint64, request *orderer.PullRequest) (*orderer.PullResponse, error)
+}
+
+// Options contains all the configurations relevant to the chain.
+type Options struct {
+	RaftID uint64
+
+	WALDir  string
+	SnapDir string
+	// SnapInterval is the accumulated size in bytes of the blocks
+	// written since the last snapshot, which triggers a new one.
+	// Snapshots are disabled if it is 0.
+	SnapInterval uint64
+
+	// This is configurable mainly for testing purpose. Users are not
+	// expected to alter this. Instead, DefaultSnapshotCatchUpEntries is used.
+	SnapshotCatchUpEntries uint64
+
+	MemoryStorage MemoryStorage
+	Logger        *logging.Logger
+
+	TickInterval    time.Duration
+	ElectionTick    int
+	HeartbeatTick   int
+	MaxSizePerMsg   uint64
+	MaxInflightMsgs int
+
+	RaftMetadata *etcdraft.RaftMetadata
+}
+
+type submit struct {
+	req    *orderer.SubmitRequest
+	leader chan uint64
+}
+
+type apply struct {
+	entries  []raftpb.Entry
+	soft     *raft.SoftState
+	snapshot raftpb.Snapshot
+}
+
+// Chain implements consensus.Chain interface.
+type Chain struct {
+	configurator Configurator
+	rpc          RPC
+
+	raftID    uint64
+	channelID string
+
+	submitC chan *submit
+	applyC  chan apply
+	haltC   chan struct{} // Signals to goroutines that the chain is halting
+	doneC   chan struct{} // Closes when the chain halts
+	startC  chan struct{} // Closes when the node is started
+
+	haltOnce sync.Once
+
+	fresh bool // indicates if this is a fresh raft node
+
+	lead uint64 // the Raft leader as seen by this node, accessed atomically
+
+	// the following fields are only accessed by the serveRequest goroutine
+	// once the chain has started
+	appliedIndex uint64
+	confState    raftpb.ConfState
+	accDataSize  uint64 // accumulated size of the blocks written since the last snapshot
+	lastBlock    *cb.Block
+
+	support consensus.ConsenterSupport
+
+	node    raft.Node
+	storage *RaftStorage
+	egress  map[uint64]chan raftpb.Message
+	opts    Options
+
+	// consentersLock protects the consenters of opts.RaftMetadata, whose TLS certificates
+	// are replaced by the serveRequest goroutine when a config block rotates them
+	consentersLock sync.RWMutex
+
+	logger *logging.Logger
+}
+
+// NewChain constructs a chain object.
+func NewChain(
+	support consensus.ConsenterSupport,
+	opts Options,
+	conf Configurator,
+	rpc RPC,
+) (*Chain, error) {
+	lg := opts.Logger
+
+	fresh := !wal.Exist(opts.WALDir)
+	storage, err := CreateStorage(lg, opts.WALDir, opts.SnapDir, opts.MemoryStorage)
+	if err != nil {
+		return nil, errors.Errorf("failed to restore persisted raft data: %s", err)
+	}
+
+	if opts.SnapshotCatchUpEntries == 0 {
+		storage.SnapshotCatchUpEntries = DefaultSnapshotCatchUpEntries
+	} else {
+		storage.SnapshotCatchUpEntries = opts.SnapshotCatchUpEntries
+	}
+
+	lastBlock := support.Block(support.Height() - 1)
+	if lastBlock == nil {
+		storage.Close()
+		return nil, errors.Errorf("failed to retrieve block [%d] from the ledger", support.Height()-1)
+	}
+
+	// Entries that are covered by the snapshot are never delivered again by raft,
+	// whereas later entries might be delivered again upon restart. Blocks that
+	// are already in the ledger are skipped when they are applied again.
+	snap := storage.Snapshot()
+	lg.Debugf("Starting raft node %d of channel %s at block [%d] and snapshot index %d",
+		opts.RaftID, support.ChainID(), lastBlock.Header.Number, snap.Metadata.Index)
+
+	return &Chain{
+		configurator: conf,
+		rpc:          rpc,
+		raftID:       opts.RaftID,
+		channelID:    support.ChainID(),
+		submitC:      make(chan *submit),
+		applyC:       make(chan apply),
+		haltC:        make(chan struct{}),
+		doneC:        make(chan struct{}),
+		startC:       make(chan struct{}),
+		fresh:        fresh,
+		appliedIndex: snap.Metadata.Index,
+		confState:    snap.Metadata.ConfState,
+		lastBlock:    lastBlock,
+		support:      support,
+		storage:      storage,
+		egress:       make(map[uint64]chan raftpb.Message),
+		opts:         opts,
+		logger:       lg,
+	}, nil
+}
+
+// Start instructs the orderer to begin serving the chain and keep it current.
+func (c *Chain) Start() {
+	c.logger.Infof("Starting Raft node %d of channel %s", c.raftID, c.channelID)
+
+	nodes, err := remoteNodes(c.raftID, c.opts.RaftMetadata)
+	if err != nil {
+		c.logger.Errorf("Failed to start chain, aborting: %s", err)
+		close(c.doneC)
+		return
+	}
+	c.configurator.Configure(c.channelID, nodes)
+
+	config := &raft.Config{
+		ID:              c.raftID,
+		ElectionTick:    c.opts.ElectionTick,
+		HeartbeatTick:   c.opts.HeartbeatTick,
+		Storage:         c.opts.MemoryStorage,
+		MaxSizePerMsg:   c.opts.MaxSizePerMsg,
+		MaxInflightMsgs: c.opts.MaxInflightMsgs,
+		Logger:          c.logger,
+		// PreVote prevents reconnected node from disturbing network.
+		// See etcd/raft doc for more details.
+		PreVote:     true,
+		CheckQuorum: true,
+		// Leaders create the blocks, so proposals must not be
+		// forwarded by followers.
+		DisableProposalForwarding: true,
+	}
+
+	if c.fresh {
+		c.logger.Infof("Starting new raft node %d", c.raftID)
+		c.node = raft.StartNode(config, raftPeers(c.opts.RaftMetadata))
+	} else {
+		c.logger.Infof("Restarting raft node %d", c.raftID)
+		c.node = raft.RestartNode(config)
+	}
+
+	for _, node := range nodes {
+		msgC := make(chan raftpb.Message, egressBufferSize)
+		c.egress[node.ID] = msgC
+		go c.sendLoop(node.ID, msgC)
+	}
+
+	close(c.startC)
+
+	raftDoneC := make(chan struct{})
+	go c.serveRaft(raftDoneC)
+	go c.serveRequest(raftDoneC)
+}
+
+// Order submits normal type transactions for ordering.
+func (c *Chain) Order(env *cb.Envelope, configSeq uint64) error {
+	return c.Submit(&orderer.SubmitRequest{LastValidationSeq: configSeq, Content: env, Channel: c.channelID}, 0)
+}
+
+// Configure submits config type transactions for ordering.
+func (c *Chain) Configure(env *cb.Envelope, configSeq uint64) error {
+	if err := c.checkConfigUpdateValidity(env); err != nil {
+		return err
+	}
+	return c.Submit(&orderer.SubmitRequest{LastValidationSeq: configSeq, Content: env, Channel: c.channelID}, 0)
+}
+
+// WaitReady is currently a no-op.
+func (c *Chain) WaitReady() error {
+	return nil
+}
+
+// Errored returns a channel that closes when the chain stops.
+func (c *Chain) Errored() <-chan struct{} {
+	return c.doneC
+}
+
+// Halt stops the chain.
+func (c *Chain) Halt() {
+	select {
+	case <-c.startC:
+	default:
+		c.logger.Warningf("Attempted to halt a chain that has not started")
+		return
+	}
+
+	c.haltOnce.Do(func() {
+		close(c.haltC)
+	})
+	<-c.doneC
+}
+
+// Submit forwards the incoming request to:
+// - the local serveRequest goroutine if this is leader
+// - the actual leader via the transport mechanism
+// The call fails if there's no leader elected yet.
+func (c *Chain) Submit(req *orderer.SubmitRequest, sender uint64) error {
+	leadC := make(chan uint64, 1)
+	select {
+	case c.submitC <- &submit{req: req, leader: leadC}:
+	case <-c.doneC:
+		return errors.Errorf("chain is stopped")
+	}
+
+	lead := <-leadC
+	if lead == raft.None {
+		return errors.Errorf("no Raft leader")
+	}
+
+	if lead == c.raftID {
+		return nil
+	}
+
+	if sender != 0 {
+		// The request was forwarded to us by a node that believed we are the leader,
+		// forwarding it again could make it bounce between the nodes.
+		return errors.Errorf("node %d is not the Raft leader, leader is %d", c.raftID, lead)
+	}
+
+	c.logger.Debugf("Forwarding submit request to Raft leader %d", lead)
+	return c.rpc.SendSubmit(lead, req)
+}
+
+// Step passes the given raft message, which was sent by the given node, to the raft FSM.
+func (c *Chain) Step(req *orderer.StepRequest, sender uint64) error {
+	select {
+	case <-c.startC:
+	default:
+		return errors.Errorf("chain %s has not started yet", c.channelID)
+	}
+
+	stepMsg := &raftpb.Message{}
+	if err := stepMsg.Unmarshal(req.Payload); err != nil {
+		return errors.Errorf("failed to unmarshal StepRequest payload to Raft Message: %s", err)
+	}
+
+	if stepMsg.From != sender {
+		return errors.Errorf("message claims to be from node %d but was sent by node %d", stepMsg.From, sender)
+	}
+
+	if err := c.node.Step(context.TODO(), *stepMsg); err != nil {
+		return errors.Errorf("failed to process Raft Step message: %s", err)
+	}
+
+	return nil
+}
+
+// Pull returns the committed blocks in the range of the given request,
+// starting at its start block. At most maxPullBatch blocks are returned.
+func (c *Chain) Pull(req *orderer.PullRequest) (*orderer.PullResponse, error) {
+	if req.Start > req.End {
+		return nil, errors.Errorf("invalid block range [%d, %d]", req.Start, req.End)
+	}
+
+	end := req.End
+	if end-req.Start >= maxPullBatch {
+		end = req.Start + maxPullBatch - 1
+	}
+
+	resp := &orderer.PullResponse{}
+	for number := req.Start; number <= end; number++ {
+		block := c.support.Block(number)
+		if block == nil {
+			break
+		}
+		resp.Blocks = append(resp.Blocks, block)
+	}
+
+	if len(resp.Blocks) == 0 {
+		return nil, errors.Errorf("block [%d] not found", req.Start)
+	}
+
+	return resp, nil
+}
+
+// serveRaft drives the raft state machine: it ticks it, persists the data it produces,
+// sends its messages to the other nodes and hands over the committed entries to serveRequest.
+func (c *Chain) serveRaft(raftDoneC chan struct{}) {
+	defer close(raftDoneC)
+
+	ticker := time.NewTicker(c.opts.TickInterval)
+	defer ticker.Stop()
+
+	stop := func() {
+		c.node.Stop()
+		if err := c.storage.Close(); err != nil {
+			c.logger.Errorf("Failed to close storage: %s", err)
+		}
+	}
+
+	for {
+		select {
+		case <-ticker.C:
+			c.node.Tick()
+
+		case rd := <-c.node.Ready():
+			if err := c.storage.Store(rd.Entries, rd.HardState, rd.Snapshot); err != nil {
+				c.logger.Panicf("Failed to persist etcd/raft data: %s", err)
+			}
+
+			c.send(rd.Messages)
+
+			select {
+			case c.applyC <- apply{entries: rd.CommittedEntries, soft: rd.SoftState, snapshot: rd.Snapshot}:
+			case <-c.haltC:
+				stop()
+				return
+			}
+
+			c.node.Advance()
+
+		case <-c.haltC:
+			stop()
+			return
+		}
+	}
+}
+
+// serveRequest owns the block cutter and the ledger: it orders the requests submitted while
+// this node is the leader, and writes the blocks committed by raft.
+func (c *Chain) serveRequest(raftDoneC chan struct{}) {
+	var timer <-chan time.Time
+	startTimer := func() {
+		if timer == nil {
+			timer = time.After(c.support.SharedConfig().BatchTimeout())
+		}
+	}
+	stopTimer := func() {
+		timer = nil
+	}
+
+	var (
+		soft           raft.SoftState
+		submitC        = c.submitC
+		bc             *blockCreator
+		proposeC       chan<- *cb.Block
+		cancelProposer context.CancelFunc = func() {}
+
+		// justElected is true if this node is the leader but it may not
+		// serve requests before all entries of its log have been applied
+		justElected    bool
+		configInflight bool
+		blockInflight  int
+	)
+
+	maxInflightBlocks := c.opts.MaxInflightMsgs
+	if maxInflightBlocks < 1 {
+		maxInflightBlocks = 1
+	}
+
+	// stopServing discards the blocks that have not been proposed yet,
+	// as well as the pending requests of the block cutter.
+	stopServing := func() {
+		cancelProposer()
+		bc = nil
+		proposeC = nil
+		configInflight = false
+		blockInflight = 0
+		stopTimer()
+		if batch := c.support.BlockCutter().Cut(blockcutter.CutDiscarded); len(batch) != 0 {
+			c.logger.Warningf("Discarding %d pending requests as this node is no longer serving requests", len(batch))
+		}
+	}
+
+	propose := func(batches ...[]*cb.Envelope) {
+		for _, batch := range batches {
+			b := bc.createNextBlock(batch)
+			c.logger.Debugf("Created block [%d], there are %d blocks in flight", b.Header.Number, blockInflight)
+			if isConfigBlock(b) {
+				configInflight = true
+			}
+			blockInflight++
+			proposeC <- b
+		}
+	}
+
+	updateSubmitC := func() {
+		switch {
+		case soft.RaftState != raft.StateLeader:
+			submitC = c.submitC
+		case justElected || configInflight || blockInflight >= maxInflightBlocks:
+			submitC = nil
+		default:
+			submitC = c.submitC
+		}
+	}
+
+	defer func() {
+		cancelProposer()
+		<-raftDoneC
+		close(c.doneC)
+	}()
+
+	for {
+		select {
+		case s := <-submitC:
+			s.leader <- soft.Lead
+
+			if soft.RaftState != raft.StateLeader {
+				continue
+			}
+
+			batches, pending, err := c.ordered(s.req)
+			if err != nil {
+				c.logger.Errorf("Failed to order message: %s", err)
+				continue
+			}
+			if pending {
+				startTimer() // no-op if timer is already started
+			} else {
+				stopTimer()
+			}
+
+			propose(batches...)
+			updateSubmitC()
+
+		case app := <-c.applyC:
+			if app.soft != nil {
+				newLeader := app.soft.Lead
+				if newLeader != soft.Lead {
+					c.logger.Infof("Raft leader changed: %d -> %d", soft.Lead, newLeader)
+					atomic.StoreUint64(&c.lead, newLeader)
+				}
+
+				wasLeader := soft.RaftState == raft.StateLeader
+				isLeader := app.soft.RaftState == raft.StateLeader
+
+				if !wasLeader && isLeader {
+					c.logger.Infof("Node %d became the Raft leader of channel %s", c.raftID, c.channelID)
+					justElected = true
+				}
+
+				if wasLeader && !isLeader {
+					c.logger.Warningf("Node %d stepped down as Raft leader of channel %s", c.raftID, c.channelID)
+					justElected = false
+					stopServing()
+				}
+
+				soft = raft.SoftState{Lead: app.soft.Lead, RaftState: app.soft.RaftState}
+			}
+
+			if !raft.IsEmptySnap(app.snapshot) {
+				c.catchUp(app.snapshot)
+			}
+
+			appliedBlocks, appliedConfig, diverged := c.apply(app.entries)
+
+			if soft.RaftState == raft.StateLeader && !justElected {
+				if diverged {
+					c.logger.Warningf("Blocks created by this leader diverged from the chain, waiting for in flight blocks to be applied")
+					stopServing()
+					justElected = true
+				} else {
+					blockInflight -= appliedBlocks
+					if blockInflight < 0 {
+						blockInflight = 0
+					}
+					if appliedConfig {
+						configInflight = false
+					}
+				}
+			}
+
+			if justElected {
+				lastIndex, _ := c.opts.MemoryStorage.LastIndex() // MemoryStorage.LastIndex always returns nil error
+				if lastIndex > c.appliedIndex {
+					c.logger.Debugf("There are in flight blocks, new leader should not serve requests until they are applied")
+				} else {
+					c.logger.Infof("Start accepting requests as Raft leader at block [%d]", c.lastBlock.Header.Number)
+					justElected = false
+					bc = newBlockCreator(c.lastBlock, c.logger)
+					// Leave room for the blocks that are created by a single request and a batch timeout
+					// after the number of blocks in flight reached its maximum.
+					proposeC, cancelProposer = c.startProposer(maxInflightBlocks + 2)
+				}
+			}
+
+			updateSubmitC()
+
+		case <-timer:
+			stopTimer()
+
+			if bc == nil {
+				continue
+			}
+
+			batch := c.support.BlockCutter().Cut(blockcutter.CutBatchTimeout)
+			if len(batch) == 0 {
+				c.logger.Warningf("Batch timer expired with no pending requests, this might indicate a bug")
+				continue
+			}
+
+			c.logger.Debugf("Batch timer expired, creating block")
+			propose(batch)
+			updateSubmitC()
+
+		case <-c.haltC:
+			c.logger.Infof("Stop serving requests")
+			return
+		}
+	}
+}
+
+// startProposer starts a goroutine which proposes the blocks sent to the returned
+// channel, until the returned cancel function is called.
+func (c *Chain) startProposer(size int) (chan<- *cb.Block, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	blocks := make(chan *cb.Block, size)
+	go c.proposer(ctx, blocks)
+	return blocks, cancel
+}
+
+// proposer proposes the blocks it receives to raft, until the given context is cancelled.
+func (c *Chain) proposer(ctx context.Context, blocks <-chan *cb.Block) {
+	for {
+		select {
+		case b := <-blocks:
+			data := utils.MarshalOrPanic(b)
+			if err := c.node.Propose(ctx, data); err != nil {
+				c.logger.Errorf("Failed to propose block [%d] to raft: %s", b.Header.Number, err)
+				return
+			}
+			c.logger.Debugf("Proposed block [%d] to raft consensus", b.Header.Number)
+
+		case <-ctx.Done():
+			c.logger.Debugf("Quit proposing blocks, discarded %d blocks in the queue", len(blocks))
+			return
+		}
+	}
+}
+
+// ordered orders the given request with the block cutter, and returns the batches to be
+// proposed, and whether there are requests pending in the block cutter.
+func (c *Chain) ordered(msg *orderer.SubmitRequest) (batches [][]*cb.Envelope, pending bool, err error) {
+	seq := c.support.Sequence()
+
+	if isConfig(msg.Content) {
+		// ConfigMsg
+		if msg.LastValidationSeq < seq {
+			msg.Content, _, err = c.support.ProcessConfigMsg(msg.Content)
+			if err != nil {
+				return nil, true, errors.Errorf("bad config message: %s", err)
+			}
+		}
+
+		batch := c.support.BlockCutter().Cut(blockcutter.CutConfigIsolation)
+		if len(batch) != 0 {
+			batches = append(batches, batch)
+		}
+		batches = append(batches, []*cb.Envelope{msg.Content})
+		return batches, false, nil
+	}
+
+	// it is a normal message
+	if msg.LastValidationSeq < seq {
+		if _, err := c.support.ProcessNormalMsg(msg.Content); err != nil {
+			return nil, true, errors.Errorf("bad normal message: %s", err)
+		}
+	}
+
+	batches, pending = c.support.BlockCutter().Ordered(msg.Content)
+	return batches, pending, nil
+}
+
+// apply writes the blocks of the given committed entries to the ledger, and takes a snapshot
+// if enough data was written since the last one. It returns the number of blocks written, whether
+// one of them was a config block, and whether a block which doesn't extend the chain was committed.
+func (c *Chain) apply(ents []raftpb.Entry) (appliedBlocks int, appliedConfig bool, diverged bool) {
+	if len(ents) == 0 {
+		return
+	}
+
+	if ents[0].Index > c.appliedIndex+1 {
+		c.logger.Panicf("first index of committed entry[%d] should <= appliedIndex[%d]+1", ents[0].Index, c.appliedIndex)
+	}
+
+	for i := range ents {
+		switch ents[i].Type {
+		case raftpb.EntryNormal:
+			if len(ents[i].Data) == 0 || ents[i].Index <= c.appliedIndex {
+				break
+			}
+
+			block := utils.UnmarshalBlockOrPanic(ents[i].Data)
+			if block.Header.Number <= c.lastBlock.Header.Number {
+				c.logger.Debugf("Block [%d] has already been written, skipping it", block.Header.Number)
+				break
+			}
+
+			if block.Header.Number != c.lastBlock.Header.Number+1 || !bytes.Equal(block.Header.PreviousHash, c.lastBlock.Header.Hash()) {
+				c.logger.Warningf("Block [%d] committed at index %d doesn't extend the chain at block [%d], skipping it",
+					block.Header.Number, ents[i].Index, c.lastBlock.Header.Number)
+				diverged = true
+				break
+			}
+
+			if isConfigBlock(block) {
+				appliedConfig = true
+			}
+			c.writeBlock(block, ents[i].Index)
+			c.accDataSize += uint64(len(ents[i].Data))
+			appliedBlocks++
+
+		case raftpb.EntryConfChange:
+			var cc raftpb.ConfChange
+			if err := cc.Unmarshal(ents[i].Data); err != nil {
+				c.logger.Warningf("Failed to unmarshal ConfChange data: %s", err)
+				continue
+			}
+
+			c.confState = *c.node.ApplyConfChange(cc)
+		}
+
+		if ents[i].Index > c.appliedIndex {
+			c.appliedIndex = ents[i].Index
+		}
+	}
+
+	if c.opts.SnapInterval != 0 && c.accDataSize >= c.opts.SnapInterval {
+		c.logger.Infof("Accumulated %d bytes since last snapshot, exceeding size limit (%d bytes), taking snapshot at block [%d]",
+			c.accDataSize, c.opts.SnapInterval, c.lastBlock.Header.Number)
+
+		// The header of the last block is enough for a lagging node
+		// to pull and verify the blocks it misses.
+		if err := c.storage.TakeSnapshot(c.appliedIndex, c.confState, utils.MarshalOrPanic(c.lastBlock.Header)); err != nil {
+			c.logger.Fatalf("Failed to create snapshot at index %d: %s", c.appliedIndex, err)
+		}
+		c.accDataSize = 0
+	}
+
+	return
+}
+
+// writeBlock writes the given block, which was committed at the given raft index, to the ledger.
+func (c *Chain) writeBlock(block *cb.Block, index uint64) {
+	if isConfigBlock(block) {
+		c.rotateConsenterCerts(block)
+	}
+
+	m := &etcdraft.RaftMetadata{
+		Consenters: c.opts.RaftMetadata.Consenters,
+		RaftIndex:  index,
+	}
+	c.commitBlock(block, utils.MarshalOrPanic(m))
+}
+
+func (c *Chain) commitBlock(block *cb.Block, encodedMetadataValue []byte) {
+	c.lastBlock = block
+
+	if isConfigBlock(block) {
+		c.logger.Infof("Writing config block [%d] to ledger", block.Header.Number)
+		c.support.WriteConfigBlock(block, encodedMetadataValue)
+		return
+	}
+
+	c.logger.Debugf("Writing block [%d] to ledger", block.Header.Number)
+	c.support.WriteBlock(block, encodedMetadataValue)
+}
+
+// rotateConsenterCerts replaces the TLS certificates of the consenters with the ones of the
+// channel configuration carried by the given config block, and reconfigures the communication
+// with the other consenters if any of them changed.
+func (c *Chain) rotateConsenterCerts(block *cb.Block) {
+	updated, ok, err := configBlockConsenters(block)
+	if err != nil {
+		c.logger.Panicf("Failed to read the consenters of config block [%d]: %s", block.Header.Number, err)
+	}
+	if !ok {
+		return
+	}
+
+	consenters, changed := rotatedConsenters(c.opts.RaftMetadata.Consenters, updated)
+	if !changed {
+		return
+	}
+
+	c.logger.Infof("Config block [%d] rotates the TLS certificates of consenters", block.Header.Number)
+	c.consentersLock.Lock()
+	c.opts.RaftMetadata.Consenters = consenters
+	c.consentersLock.Unlock()
+
+	nodes, err := remoteNodes(c.raftID, c.opts.RaftMetadata)
+	if err != nil {
+		c.logger.Panicf("Failed to reconfigure the communication with the consenters: %s", err)
+	}
+	c.configurator.Configure(c.channelID, nodes)
+}
+
+// catchUp pulls the blocks up to the one referenced by the given snapshot from the
+// other consenters, if the ledger is behind it.
+func (c *Chain) catchUp(snap raftpb.Snapshot) {
+	target := &cb.BlockHeader{}
+	if err := proto.Unmarshal(snap.Data, target); err != nil {
+		c.logger.Panicf("Failed to unmarshal snapshot data to block header: %s", err)
+	}
+
+	c.appliedIndex = snap.Metadata.Index
+	c.confState = snap.Metadata.ConfState
+	c.accDataSize = 0
+
+	if c.lastBlock.Header.Number >= target.Number {
+		c.logger.Debugf("Snapshot at block [%d] is not ahead of the ledger at block [%d]", target.Number, c.lastBlock.Header.Number)
+		return
+	}
+
+	c.logger.Infof("Catching up with snapshot at block [%d] from block [%d]", target.Number, c.lastBlock.Header.Number)
+
+	for c.lastBlock.Header.Number < target.Number {
+		if c.pullFromConsenters(target) {
+			continue
+		}
+
+		c.logger.Warningf("Failed pulling blocks from all consenters, retrying in %s", pullRetryInterval)
+		select {
+		case <-time.After(pullRetryInterval):
+		case <-c.haltC:
+			return
+		}
+	}
+
+	c.logger.Infof("Finished catching up with snapshot at block [%d]", target.Number)
+}
+
+// pullFromConsenters attempts to pull the next blocks towards the given target
+// from any of the other consenters, and returns whether any progress was made.
+func (c *Chain) pullFromConsenters(target *cb.BlockHeader) bool {
+	for id := range c.opts.RaftMetadata.Consenters {
+		if id == c.raftID {
+			continue
+		}
+
+		resp, err := c.rpc.Pull(id, &orderer.PullRequest{
+			Channel: c.channelID,
+			Start:   c.lastBlock.Header.Number + 1,
+			End:     target.Number,
+		})
+		if err != nil {
+			c.logger.Warningf("Failed pulling blocks from node %d: %s", id, err)
+			continue
+		}
+
+		if err := c.verifyPulledBlocks(resp.Blocks, target); err != nil {
+			c.logger.Warningf("Node %d sent invalid blocks: %s", id, err)
+			continue
+		}
+
+		for _, block := range resp.Blocks {
+			md, err := utils.GetMetadataFromBlock(block, cb.BlockMetadataIndex_ORDERER)
+			if err != nil {
+				c.logger.Panicf("Failed to extract orderer metadata from verified block [%d]: %s", block.Header.Number, err)
+			}
+			if isConfigBlock(block) {
+				c.rotateConsenterCerts(block)
+			}
+			c.commitBlock(block, md.Value)
+		}
+		return true
+	}
+	return false
+}
+
+// verifyPulledBlocks checks that the given blocks extend the chain, and lead to the given target
+func (c *Chain) verifyPulledBlocks(blocks []*cb.Block, target *cb.BlockHeader) error {
+	if len(blocks) == 0 {
+		return errors.New("no blocks sent")
+	}
+
+	prev := c.lastBlock.Header
+	for _, block := range blocks {
+		if block.Header == nil || block.Data == nil || block.Metadata == nil {
+			return errors.New("malformed block")
+		}
+		if block.Header.Number != prev.Number+1 {
+			return errors.Errorf("expected block [%d] but got block [%d]", prev.Number+1, block.Header.Number)
+		}
+		if !bytes.Equal(block.Header.PreviousHash, prev.Hash()) {
+			return errors.Errorf("block [%d] doesn't reference the hash of block [%d]", block.Header.Number, prev.Number)
+		}
+		if !bytes.Equal(block.Header.DataHash, block.Data.Hash()) {
+			return errors.Errorf("data hash of block [%d] doesn't match its content", block.Header.Number)
+		}
+		if block.Header.Number > target.Number {
+			return errors.Errorf("block [%d] is beyond the snapshot at block [%d]", block.Header.Number, target.Number)
+		}
+		if block.Header.Number == target.Number && !bytes.Equal(block.Header.Hash(), target.Hash()) {
+			return errors.Errorf("block [%d] doesn't match the snapshot", block.Header.Number)
+		}
+		prev = block.Header
+	}
+	return nil
+}
+
+// send hands over the given messages to the goroutines that send them to their destinations
+func (c *Chain) send(msgs []raftpb.Message) {
+	for _, msg := range msgs {
+		if msg.To == 0 {
+			continue
+		}
+
+		msgC, exists := c.egress[msg.To]
+		if !exists {
+			c.logger.Warningf("Dropping message to unknown node %d", msg.To)
+			continue
+		}
+
+		select {
+		case msgC <- msg:
+		default:
+			c.logger.Warningf("Dropping message to node %d as its queue is full", msg.To)
+			c.node.ReportUnreachable(msg.To)
+			if msg.Type == raftpb.MsgSnap {
+				c.node.ReportSnapshot(msg.To, raft.SnapshotFailure)
+			}
+		}
+	}
+}
+
+// sendLoop sends the messages destined to the given node, until the chain halts
+func (c *Chain) sendLoop(dest uint64, msgC <-chan raftpb.Message) {
+	for {
+		select {
+		case msg := <-msgC:
+			c.sendMessage(dest, msg)
+		case <-c.haltC:
+			return
+		}
+	}
+}
+
+func (c *Chain) sendMessage(dest uint64, msg raftpb.Message) {
+	status := raft.SnapshotFinish
+
+	payload, err := msg.Marshal()
+	if err != nil {
+		c.logger.Panicf("Failed to marshal raft message: %s", err)
+	}
+
+	if _, err := c.rpc.Step(dest, &orderer.StepRequest{Channel: c.channelID, Payload: payload}); err != nil {
+		c.logger.Debugf("Failed to send StepRequest to %d: %s", dest, err)
+		c.node.ReportUnreachable(dest)
+		status = raft.SnapshotFailure
+	}
+
+	if msg.Type == raftpb.MsgSnap {
+		c.node.ReportSnapshot(dest, status)
+	}
+}
+
+// checkConfigUpdateValidity rejects config updates which are not supported by this
+// consenter, i.e. changes of the consensus type and of the consenter set. Only the TLS
+// certificates of the existing consenters may be changed.
+func (c *Chain) checkConfigUpdateValidity(env *cb.Envelope) error {
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return err
+	}
+	if payload.Header == nil {
+		return errors.New("config transaction is missing a header")
+	}
+
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return err
+	}
+
+	switch chdr.Type {
+	case int32(cb.HeaderType_ORDERER_TRANSACTION):
+		return nil
+	case int32(cb.HeaderType_CONFIG):
+		configEnv, err := configtx.UnmarshalConfigEnvelope(payload.Data)
+		if err != nil {
+			return err
+		}
+
+		bundle, err := channelconfig.NewBundle(chdr.ChannelId, configEnv.Config)
+		if err != nil {
+			return err
+		}
+
+		ordererConf, ok := bundle.OrdererConfig()
+		if !ok {
+			return errors.New("config transaction has no orderer config")
+		}
+
+		if ordererConf.ConsensusType() != c.support.SharedConfig().ConsensusType() {
+			return errors.Errorf("updating consensus type from %s to %s is not supported",
+				c.support.SharedConfig().ConsensusType(), ordererConf.ConsensusType())
+		}
+
+		m := &etcdraft.Metadata{}
+		if err := proto.Unmarshal(ordererConf.ConsensusMetadata(), m); err != nil {
+			return errors.Wrap(err, "failed to unmarshal updated etcdraft metadata")
+		}
+
+		c.consentersLock.RLock()
+		same := sameEndpoints(c.opts.RaftMetadata.Consenters, m.Consenters)
+		c.consentersLock.RUnlock()
+		if !same {
+			return errors.New("update of consenters set is not supported yet")
+		}
+
+		for _, consenter := range m.Consenters {
+			if _, err := pemToDER(consenter.ClientTlsCert); err != nil {
+				return errors.Wrapf(err, "invalid client TLS certificate of consenter %s", endpoint(consenter))
+			}
+			if _, err := pemToDER(consenter.ServerTlsCert); err != nil {
+				return errors.Wrapf(err, "invalid server TLS certificate of consenter %s", endpoint(consenter))
+			}
+		}
+
+		return nil
+	default:
+		return errors.Errorf("config transaction has unknown header type %d", chdr.Type)
+	}
+}
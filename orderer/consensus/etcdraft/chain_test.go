@@ -0,0 +1,567 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package etcdraft
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/coreos/etcd/raft"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/crypto/tlsgen"
+	"github.com/hyperledger/fabric/common/flogging"
+	mockconfig "github.com/hyperledger/fabric/common/mocks/config"
+	"github.com/hyperledger/fabric/common/tools/configtxgen/configtxgentest"
+	"github.com/hyperledger/fabric/common/tools/configtxgen/encoder"
+	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
+	"github.com/hyperledger/fabric/common/tools/configtxlator/workflow"
+	"github.com/hyperledger/fabric/core/comm"
+	"github.com/hyperledger/fabric/orderer/common/blockcutter"
+	"github.com/hyperledger/fabric/orderer/common/cluster"
+	mockmultichannel "github.com/hyperledger/fabric/orderer/mocks/common/multichannel"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/orderer/etcdraft"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testChannel = "foo"
+	testTimeout = 30 * time.Second
+)
+
+// ledgerSupport is a ConsenterSupport which keeps the blocks it is told
+// to write in memory, and cuts a block for every envelope it orders
+type ledgerSupport struct {
+	*mockmultichannel.ConsenterSupport
+	cutter blockcutter.Receiver
+
+	lock   sync.RWMutex
+	blocks []*cb.Block
+}
+
+func newLedgerSupport(metadata *etcdraft.Metadata) *ledgerSupport {
+	sharedConfig := &mockconfig.Orderer{
+		ConsensusTypeVal:     "etcdraft",
+		ConsensusMetadataVal: utils.MarshalOrPanic(metadata),
+		BatchTimeoutVal:      time.Second,
+		BatchSizeVal: &orderer.BatchSize{
+			MaxMessageCount:   1,
+			AbsoluteMaxBytes:  10 * 1024 * 1024,
+			PreferredMaxBytes: 10 * 1024 * 1024,
+		},
+	}
+
+	genesis := cb.NewBlock(0, nil)
+	genesis.Data.Data = [][]byte{[]byte("genesis")}
+	genesis.Header.DataHash = genesis.Data.Hash()
+
+	return &ledgerSupport{
+		ConsenterSupport: &mockmultichannel.ConsenterSupport{
+			SharedConfigVal: sharedConfig,
+			ChainIDVal:      testChannel,
+		},
+		cutter: blockcutter.NewReceiverImpl(testChannel, sharedConfig),
+		blocks: []*cb.Block{genesis},
+	}
+}
+
+func (ls *ledgerSupport) BlockCutter() blockcutter.Receiver {
+	return ls.cutter
+}
+
+func (ls *ledgerSupport) WriteBlock(block *cb.Block, encodedMetadataValue []byte) {
+	block.Metadata.Metadata[cb.BlockMetadataIndex_ORDERER] = utils.MarshalOrPanic(&cb.Metadata{Value: encodedMetadataValue})
+
+	ls.lock.Lock()
+	defer ls.lock.Unlock()
+	ls.blocks = append(ls.blocks, block)
+}
+
+func (ls *ledgerSupport) WriteConfigBlock(block *cb.Block, encodedMetadataValue []byte) {
+	ls.WriteBlock(block, encodedMetadataValue)
+}
+
+func (ls *ledgerSupport) Height() uint64 {
+	ls.lock.RLock()
+	defer ls.lock.RUnlock()
+	return uint64(len(ls.blocks))
+}
+
+func (ls *ledgerSupport) Block(number uint64) *cb.Block {
+	ls.lock.RLock()
+	defer ls.lock.RUnlock()
+	if number >= uint64(len(ls.blocks)) {
+		return nil
+	}
+	return ls.blocks[number]
+}
+
+// lastMetadata returns the orderer metadata of the last block,
+// as the multichannel registrar passes it to HandleChain
+func (ls *ledgerSupport) lastMetadata() *cb.Metadata {
+	lastBlock := ls.Block(ls.Height() - 1)
+	md, err := utils.GetMetadataFromBlock(lastBlock, cb.BlockMetadataIndex_ORDERER)
+	if err != nil {
+		panic(err)
+	}
+	return md
+}
+
+type node struct {
+	id        uint64
+	srv       *comm.GRPCServer
+	comm      *cluster.Comm
+	consenter *Consenter
+	support   *ledgerSupport
+	chain     *Chain
+	halted    bool
+}
+
+type network struct {
+	t     *testing.T
+	dir   string
+	nodes map[uint64]*node
+}
+
+func newNetwork(t *testing.T, size int, options *etcdraft.Options) *network {
+	ca, err := tlsgen.NewCA()
+	assert.NoError(t, err)
+
+	dir, err := ioutil.TempDir("", "etcdraft-")
+	assert.NoError(t, err)
+
+	n := &network{t: t, dir: dir, nodes: make(map[uint64]*node)}
+	metadata := &etcdraft.Metadata{Options: options}
+
+	var clientCerts [][]byte
+	for i := 1; i <= size; i++ {
+		serverKeyPair, err := ca.NewServerCertKeyPair("127.0.0.1")
+		assert.NoError(t, err)
+		clientKeyPair, err := ca.NewClientCertKeyPair()
+		assert.NoError(t, err)
+
+		srv, err := comm.NewGRPCServer("127.0.0.1:0", comm.ServerConfig{
+			SecOpts: &comm.SecureOptions{
+				UseTLS:            true,
+				RequireClientCert: true,
+				Certificate:       serverKeyPair.Cert,
+				Key:               serverKeyPair.Key,
+				ClientRootCAs:     [][]byte{ca.CertBytes()},
+			},
+		})
+		assert.NoError(t, err)
+
+		port, err := strconv.ParseUint(strings.Split(srv.Address(), ":")[1], 10, 32)
+		assert.NoError(t, err)
+		metadata.Consenters = append(metadata.Consenters, &etcdraft.Consenter{
+			Host:          "127.0.0.1",
+			Port:          uint32(port),
+			ClientTlsCert: clientKeyPair.Cert,
+			ServerTlsCert: serverKeyPair.Cert,
+		})
+		clientCerts = append(clientCerts, clientKeyPair.Cert)
+
+		dialer := cluster.NewTLSPinningDialer(comm.ClientConfig{
+			Timeout: time.Second,
+			SecOpts: &comm.SecureOptions{
+				UseTLS:            true,
+				RequireClientCert: true,
+				Certificate:       clientKeyPair.Cert,
+				Key:               clientKeyPair.Key,
+				ServerRootCAs:     [][]byte{ca.CertBytes()},
+			},
+		})
+		clusterComm := cluster.NewComm(dialer, time.Second)
+		orderer.RegisterClusterServer(srv.Server(), &cluster.Service{Dispatcher: clusterComm})
+		go srv.Start()
+
+		n.nodes[uint64(i)] = &node{id: uint64(i), srv: srv, comm: clusterComm}
+	}
+
+	for id, nd := range n.nodes {
+		nd.consenter = New(nd.comm, clientCerts[id-1], Config{
+			WALDir:  path.Join(dir, fmt.Sprintf("node%d", id), "wal"),
+			SnapDir: path.Join(dir, fmt.Sprintf("node%d", id), "snap"),
+		})
+		nd.support = newLedgerSupport(metadata)
+	}
+
+	return n
+}
+
+func (n *network) start(ids ...uint64) {
+	for _, id := range ids {
+		nd := n.nodes[id]
+		chain, err := nd.consenter.HandleChain(nd.support, nd.support.lastMetadata())
+		assert.NoError(n.t, err)
+		nd.chain = chain.(*Chain)
+		nd.chain.Start()
+		nd.halted = false
+	}
+}
+
+func (n *network) halt(ids ...uint64) {
+	for _, id := range ids {
+		nd := n.nodes[id]
+		nd.chain.Halt()
+		nd.halted = true
+	}
+}
+
+func (n *network) stop() {
+	for _, nd := range n.nodes {
+		if nd.chain != nil && !nd.halted {
+			nd.chain.Halt()
+		}
+		nd.srv.Stop()
+		nd.comm.Shutdown()
+	}
+	os.RemoveAll(n.dir)
+}
+
+// elected waits until all the given nodes agree on a leader among them, and returns it
+func (n *network) elected(ids ...uint64) uint64 {
+	deadline := time.Now().Add(testTimeout)
+	for time.Now().Before(deadline) {
+		lead := atomic.LoadUint64(&n.nodes[ids[0]].chain.lead)
+		agreed := false
+		for _, id := range ids {
+			if id == lead {
+				agreed = true
+			}
+		}
+		for _, id := range ids[1:] {
+			if atomic.LoadUint64(&n.nodes[id].chain.lead) != lead {
+				agreed = false
+			}
+		}
+		if agreed {
+			return lead
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	n.t.Fatalf("nodes %v didn't elect a leader within %s", ids, testTimeout)
+	return raft.None
+}
+
+// waitHeight waits until the ledgers of the given nodes reach the given height,
+// and checks that they contain the same blocks
+func (n *network) waitHeight(height uint64, ids ...uint64) {
+	deadline := time.Now().Add(testTimeout)
+	for _, id := range ids {
+		for n.nodes[id].support.Height() < height {
+			if time.Now().After(deadline) {
+				n.t.Fatalf("node %d didn't reach height %d within %s, its height is %d",
+					id, height, testTimeout, n.nodes[id].support.Height())
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+	}
+
+	reference := n.nodes[ids[0]].support
+	for _, id := range ids[1:] {
+		for number := uint64(0); number < height; number++ {
+			expected := reference.Block(number).Header.Hash()
+			actual := n.nodes[id].support.Block(number).Header.Hash()
+			assert.True(n.t, bytes.Equal(expected, actual), "block [%d] of node %d differs from node %d", number, id, ids[0])
+		}
+	}
+}
+
+func (n *network) order(id uint64, count int) {
+	for i := 0; i < count; i++ {
+		env := &cb.Envelope{
+			Payload: utils.MarshalOrPanic(&cb.Payload{
+				Header: &cb.Header{
+					ChannelHeader: utils.MarshalOrPanic(&cb.ChannelHeader{
+						Type:      int32(cb.HeaderType_MESSAGE),
+						ChannelId: testChannel,
+					}),
+				},
+				Data: []byte(fmt.Sprintf("tx %d of node %d at %s", i, id, time.Now())),
+			}),
+		}
+		assert.NoError(n.t, n.nodes[id].chain.Order(env, 0))
+	}
+}
+
+func testOptions() *etcdraft.Options {
+	return &etcdraft.Options{
+		TickInterval:    "50ms",
+		ElectionTick:    10,
+		HeartbeatTick:   1,
+		MaxInflightMsgs: 256,
+		MaxSizePerMsg:   1024 * 1024,
+	}
+}
+
+func TestSingleNode(t *testing.T) {
+	n := newNetwork(t, 1, testOptions())
+	defer n.stop()
+
+	n.start(1)
+	n.elected(1)
+	n.order(1, 3)
+	n.waitHeight(4, 1)
+
+	// The ORDERER metadata of the blocks denotes the consenters and the raft index
+	m := &etcdraft.RaftMetadata{}
+	assert.NoError(t, proto.Unmarshal(n.nodes[1].support.lastMetadata().Value, m))
+	assert.Len(t, m.Consenters, 1)
+	assert.NotZero(t, m.RaftIndex)
+
+	// A restarted node replays its WAL, and doesn't write blocks twice
+	n.halt(1)
+	n.start(1)
+	n.elected(1)
+	n.order(1, 1)
+	n.waitHeight(5, 1)
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, uint64(5), n.nodes[1].support.Height())
+}
+
+func TestMultiNode(t *testing.T) {
+	n := newNetwork(t, 3, testOptions())
+	defer n.stop()
+
+	n.start(1, 2, 3)
+	lead := n.elected(1, 2, 3)
+	follower := lead%3 + 1
+
+	// Both the leader and the followers accept transactions
+	n.order(lead, 2)
+	n.order(follower, 2)
+	n.waitHeight(5, 1, 2, 3)
+
+	// The remaining nodes elect a new leader once the leader is gone
+	n.halt(lead)
+	var remaining []uint64
+	for id := range n.nodes {
+		if id != lead {
+			remaining = append(remaining, id)
+		}
+	}
+	newLead := n.elected(remaining...)
+	assert.NotEqual(t, lead, newLead)
+
+	n.order(remaining[0], 2)
+	n.waitHeight(7, remaining...)
+
+	// The former leader catches up once it is restarted
+	n.start(lead)
+	n.order(remaining[1], 1)
+	n.waitHeight(8, 1, 2, 3)
+}
+
+func TestSnapshotCatchUp(t *testing.T) {
+	options := testOptions()
+	// Take a snapshot every time a block is written
+	options.SnapshotIntervalSize = 1
+	n := newNetwork(t, 3, options)
+	defer n.stop()
+
+	n.start(1, 2, 3)
+	lead := n.elected(1, 2, 3)
+	lagging := lead%3 + 1
+	var others []uint64
+	for id := range n.nodes {
+		if id != lagging {
+			others = append(others, id)
+		}
+	}
+
+	n.halt(lagging)
+	// Enough blocks are written to make the leader purge the raft entries
+	// that the lagging node misses, so it has to be sent a snapshot
+	n.order(lead, int(DefaultSnapshotCatchUpEntries)+10)
+	height := DefaultSnapshotCatchUpEntries + 11
+	n.waitHeight(height, others...)
+
+	snapshots, err := ioutil.ReadDir(n.nodes[lead].consenter.EtcdRaftConfig.SnapDir + "/" + testChannel)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, snapshots)
+
+	n.start(lagging)
+	n.waitHeight(height, lagging, lead)
+
+	// The lagging node keeps up with new blocks after catching up
+	n.order(lead, 1)
+	n.waitHeight(height+1, 1, 2, 3)
+}
+
+func TestPull(t *testing.T) {
+	support := newLedgerSupport(&etcdraft.Metadata{})
+	for i := 1; i <= maxPullBatch+10; i++ {
+		support.WriteBlock(cb.NewBlock(uint64(i), nil), nil)
+	}
+	c := &Chain{support: support}
+
+	resp, err := c.Pull(&orderer.PullRequest{Start: 1, End: 3})
+	assert.NoError(t, err)
+	assert.Len(t, resp.Blocks, 3)
+	assert.Equal(t, uint64(1), resp.Blocks[0].Header.Number)
+
+	resp, err = c.Pull(&orderer.PullRequest{Start: 5, End: maxPullBatch + 20})
+	assert.NoError(t, err)
+	assert.Len(t, resp.Blocks, maxPullBatch)
+
+	resp, err = c.Pull(&orderer.PullRequest{Start: maxPullBatch + 5, End: maxPullBatch + 20})
+	assert.NoError(t, err)
+	assert.Len(t, resp.Blocks, 6)
+
+	_, err = c.Pull(&orderer.PullRequest{Start: 3, End: 1})
+	assert.EqualError(t, err, "invalid block range [3, 1]")
+
+	_, err = c.Pull(&orderer.PullRequest{Start: maxPullBatch + 11, End: maxPullBatch + 20})
+	assert.EqualError(t, err, fmt.Sprintf("block [%d] not found", maxPullBatch+11))
+}
+
+func TestVerifyPulledBlocks(t *testing.T) {
+	genesis := cb.NewBlock(0, nil)
+	bc := newBlockCreator(genesis, flogging.MustGetLogger(pkgLogID))
+	var blocks []*cb.Block
+	for i := 0; i < 3; i++ {
+		blocks = append(blocks, bc.createNextBlock([]*cb.Envelope{{Payload: []byte{byte(i)}}}))
+	}
+	c := &Chain{lastBlock: genesis}
+	target := blocks[2].Header
+
+	assert.NoError(t, c.verifyPulledBlocks(blocks, target))
+	assert.NoError(t, c.verifyPulledBlocks(blocks[:2], target))
+
+	err := c.verifyPulledBlocks(nil, target)
+	assert.EqualError(t, err, "no blocks sent")
+
+	err = c.verifyPulledBlocks(blocks[1:], target)
+	assert.EqualError(t, err, "expected block [1] but got block [2]")
+
+	err = c.verifyPulledBlocks(blocks[:2], blocks[0].Header)
+	assert.EqualError(t, err, "block [2] is beyond the snapshot at block [1]")
+
+	tampered := proto.Clone(blocks[1]).(*cb.Block)
+	tampered.Data.Data = [][]byte{[]byte("tampered")}
+	err = c.verifyPulledBlocks([]*cb.Block{blocks[0], tampered}, target)
+	assert.EqualError(t, err, "data hash of block [2] doesn't match its content")
+
+	forged := cb.NewBlock(3, blocks[1].Header.Hash())
+	forged.Header.DataHash = forged.Data.Hash()
+	err = c.verifyPulledBlocks([]*cb.Block{blocks[0], blocks[1], forged}, target)
+	assert.EqualError(t, err, "block [3] doesn't match the snapshot")
+}
+
+// recordingConfigurator records the remote nodes the communication is configured with
+type recordingConfigurator struct {
+	nodes []cluster.RemoteNode
+}
+
+func (rc *recordingConfigurator) Configure(channel string, newNodes []cluster.RemoteNode) {
+	rc.nodes = newNodes
+}
+
+// raftChannelConfig returns the config of a channel ordered by the given etcd/raft consenters
+func raftChannelConfig(t *testing.T, consenters []*etcdraft.Consenter) *cb.Config {
+	block := encoder.New(configtxgentest.Load(genesisconfig.SampleDevModeSoloProfile)).GenesisBlockForChannel(testChannel)
+	_, config, err := workflow.ConfigFromBlock(block)
+	assert.NoError(t, err)
+	metadata := &etcdraft.Metadata{Consenters: consenters, Options: testOptions()}
+	ordererGroup := config.ChannelGroup.Groups[channelconfig.OrdererGroupKey]
+	ordererGroup.Values[channelconfig.ConsensusTypeKey].Value = utils.MarshalOrPanic(&orderer.ConsensusType{
+		Type:     "etcdraft",
+		Metadata: utils.MarshalOrPanic(metadata),
+	})
+	return config
+}
+
+func configEnvelope(t *testing.T, config *cb.Config) *cb.Envelope {
+	env, err := utils.CreateSignedEnvelope(cb.HeaderType_CONFIG, testChannel, nil, &cb.ConfigEnvelope{Config: config}, 0, 0)
+	assert.NoError(t, err)
+	return env
+}
+
+func TestConsenterCertRotation(t *testing.T) {
+	_, c1 := newTestConsenter(t)
+	c1.Host = "raft1.example.com"
+	_, c2 := newTestConsenter(t)
+	c2.Host = "raft2.example.com"
+	config := raftChannelConfig(t, []*etcdraft.Consenter{c1, c2})
+
+	configurator := &recordingConfigurator{}
+	c := &Chain{
+		configurator: configurator,
+		raftID:       1,
+		channelID:    testChannel,
+		support:      newLedgerSupport(&etcdraft.Metadata{Consenters: []*etcdraft.Consenter{c1, c2}}),
+		opts: Options{
+			RaftMetadata: &etcdraft.RaftMetadata{Consenters: map[uint64]*etcdraft.Consenter{1: c1, 2: c2}},
+		},
+		logger: flogging.MustGetLogger(pkgLogID),
+	}
+
+	dir, err := ioutil.TempDir("", "etcdraft-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	ca, err := tlsgen.NewCA()
+	assert.NoError(t, err)
+	clientKeyPair, err := ca.NewClientCertKeyPair()
+	assert.NoError(t, err)
+	serverKeyPair, err := ca.NewServerCertKeyPair("raft2.example.com")
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(path.Join(dir, "client.pem"), clientKeyPair.Cert, 0600))
+	assert.NoError(t, ioutil.WriteFile(path.Join(dir, "server.pem"), serverKeyPair.Cert, 0600))
+
+	// A config update which rotates the TLS certificates of a consenter is valid
+	patch := &workflow.Patch{
+		Orderer: &workflow.OrdererPatch{
+			Consenters: []*genesisconfig.Consenter{{
+				Host:          "raft2.example.com",
+				Port:          7050,
+				ClientTLSCert: path.Join(dir, "client.pem"),
+				ServerTLSCert: path.Join(dir, "server.pem"),
+			}},
+		},
+	}
+	updated, err := patch.Apply(config)
+	assert.NoError(t, err)
+	env := configEnvelope(t, updated)
+	assert.NoError(t, c.checkConfigUpdateValidity(env))
+
+	// Once the config block is written, the communication pins the new certificates
+	block := cb.NewBlock(1, nil)
+	block.Data.Data = [][]byte{utils.MarshalOrPanic(env)}
+	c.rotateConsenterCerts(block)
+	assert.Equal(t, clientKeyPair.Cert, c.opts.RaftMetadata.Consenters[2].ClientTlsCert)
+	assert.Equal(t, serverKeyPair.Cert, c.opts.RaftMetadata.Consenters[2].ServerTlsCert)
+	assert.True(t, proto.Equal(c1, c.opts.RaftMetadata.Consenters[1]))
+	assert.Len(t, configurator.nodes, 1)
+	assert.Equal(t, uint64(2), configurator.nodes[0].ID)
+	assert.Equal(t, clientKeyPair.TLSCert.Raw, configurator.nodes[0].ClientTLSCert)
+	assert.Equal(t, serverKeyPair.TLSCert.Raw, configurator.nodes[0].ServerTLSCert)
+
+	// The consenter set can't be changed
+	_, c3 := newTestConsenter(t)
+	c3.Host = "raft3.example.com"
+	err = c.checkConfigUpdateValidity(configEnvelope(t, raftChannelConfig(t, []*etcdraft.Consenter{c1, c2, c3})))
+	assert.EqualError(t, err, "update of consenters set is not supported yet")
+
+	// The certificates of the consenters must be valid
+	bad := proto.Clone(c2).(*etcdraft.Consenter)
+	bad.ClientTlsCert = []byte("not a PEM")
+	err = c.checkConfigUpdateValidity(configEnvelope(t, raftChannelConfig(t, []*etcdraft.Consenter{c1, bad})))
+	assert.EqualError(t, err, "invalid client TLS certificate of consenter raft2.example.com:7050: failed decoding PEM certificate")
+}
@@ -0,0 +1,183 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package etcdraft
+
+import (
+	"bytes"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/coreos/etcd/raft"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/orderer/common/cluster"
+	"github.com/hyperledger/fabric/orderer/consensus"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/orderer/etcdraft"
+	"github.com/op/go-logging"
+	"github.com/pkg/errors"
+)
+
+const pkgLogID = "orderer/consensus/etcdraft"
+
+// Config contains etcdraft configurations
+type Config struct {
+	WALDir  string // WAL data of <my-channel> is stored in WALDir/<my-channel>
+	SnapDir string // Snapshots of <my-channel> are stored in SnapDir/<my-channel>
+}
+
+// Consenter implements etddraft consenter
+type Consenter struct {
+	// Communication is used to communicate with the other consenters
+	Communication cluster.Communicator
+	// Cert is the PEM encoded TLS certificate this node uses to authenticate
+	// to the other consenters, and by which it is identified in the consenter set
+	Cert           []byte
+	EtcdRaftConfig Config
+	Logger         *logging.Logger
+
+	chainsLock sync.RWMutex
+	chains     map[string]*Chain
+}
+
+// New creates a etcdraft Consenter, which handles the requests the given
+// cluster communication receives from the other consenters.
+func New(comm *cluster.Comm, cert []byte, conf Config) *Consenter {
+	consenter := &Consenter{
+		Communication:  comm,
+		Cert:           cert,
+		EtcdRaftConfig: conf,
+		Logger:         flogging.MustGetLogger(pkgLogID),
+		chains:         make(map[string]*Chain),
+	}
+	comm.H = consenter
+	return consenter
+}
+
+// HandleChain returns a new Chain instance or an error upon failure
+func (c *Consenter) HandleChain(support consensus.ConsenterSupport, metadata *cb.Metadata) (consensus.Chain, error) {
+	m := &etcdraft.Metadata{}
+	if err := proto.Unmarshal(support.SharedConfig().ConsensusMetadata(), m); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal consensus metadata")
+	}
+
+	if m.Options == nil {
+		return nil, errors.New("etcdraft options have not been provided")
+	}
+
+	raftMetadata, err := readRaftMetadata(metadata, m)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read Raft metadata")
+	}
+
+	id, err := c.detectSelfID(raftMetadata.Consenters)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to detect own Raft ID")
+	}
+
+	tickInterval, err := time.ParseDuration(m.Options.TickInterval)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse TickInterval (%s) to time duration", m.Options.TickInterval)
+	}
+
+	opts := Options{
+		RaftID:        id,
+		MemoryStorage: raft.NewMemoryStorage(),
+		Logger:        c.Logger,
+
+		TickInterval:    tickInterval,
+		ElectionTick:    int(m.Options.ElectionTick),
+		HeartbeatTick:   int(m.Options.HeartbeatTick),
+		MaxInflightMsgs: int(m.Options.MaxInflightMsgs),
+		MaxSizePerMsg:   m.Options.MaxSizePerMsg,
+		SnapInterval:    m.Options.SnapshotIntervalSize,
+
+		RaftMetadata: raftMetadata,
+
+		WALDir:  path.Join(c.EtcdRaftConfig.WALDir, support.ChainID()),
+		SnapDir: path.Join(c.EtcdRaftConfig.SnapDir, support.ChainID()),
+	}
+
+	rpc := &cluster.RPC{Channel: support.ChainID(), Comm: c.Communication}
+	chain, err := NewChain(support, opts, c.Communication, rpc)
+	if err != nil {
+		return nil, err
+	}
+
+	c.chainsLock.Lock()
+	c.chains[support.ChainID()] = chain
+	c.chainsLock.Unlock()
+
+	return chain, nil
+}
+
+// OnStep passes the given raft message to the chain of the given channel
+func (c *Consenter) OnStep(channel string, sender uint64, request *orderer.StepRequest) (*orderer.StepResponse, error) {
+	chain, err := c.chain(channel)
+	if err != nil {
+		return nil, err
+	}
+
+	return &orderer.StepResponse{}, chain.Step(request, sender)
+}
+
+// OnSubmit passes the given transaction to the chain of the given channel
+func (c *Consenter) OnSubmit(channel string, sender uint64, request *orderer.SubmitRequest) (*orderer.SubmitResponse, error) {
+	chain, err := c.chain(channel)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := chain.Submit(request, sender); err != nil {
+		return &orderer.SubmitResponse{Status: cb.Status_SERVICE_UNAVAILABLE, Info: err.Error()}, nil
+	}
+
+	return &orderer.SubmitResponse{Status: cb.Status_SUCCESS}, nil
+}
+
+// OnPull returns blocks of the chain of the given channel
+func (c *Consenter) OnPull(channel string, sender uint64, request *orderer.PullRequest) (*orderer.PullResponse, error) {
+	chain, err := c.chain(channel)
+	if err != nil {
+		return nil, err
+	}
+
+	return chain.Pull(request)
+}
+
+func (c *Consenter) chain(channel string) (*Chain, error) {
+	c.chainsLock.RLock()
+	defer c.chainsLock.RUnlock()
+
+	chain, exists := c.chains[channel]
+	if !exists {
+		return nil, errors.Errorf("channel %s is not served by this consenter", channel)
+	}
+	return chain, nil
+}
+
+// detectSelfID returns the ID of the consenter whose client TLS certificate matches ours
+func (c *Consenter) detectSelfID(consenters map[uint64]*etcdraft.Consenter) (uint64, error) {
+	thisNodeCertAsDER, err := pemToDER(c.Cert)
+	if err != nil {
+		return 0, errors.Wrap(err, "invalid TLS certificate of this node")
+	}
+
+	for id, consenter := range consenters {
+		certAsDER, err := pemToDER(consenter.ClientTlsCert)
+		if err != nil {
+			return 0, errors.Wrapf(err, "invalid client TLS certificate of consenter %d", id)
+		}
+		if bytes.Equal(thisNodeCertAsDER, certAsDER) {
+			return id, nil
+		}
+	}
+
+	return 0, errors.New("could not find the certificate of this node in the consenter set")
+}
@@ -0,0 +1,164 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package etcdraft
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/crypto/tlsgen"
+	"github.com/hyperledger/fabric/core/comm"
+	"github.com/hyperledger/fabric/orderer/common/cluster"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/orderer/etcdraft"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestConsenter(t *testing.T) (*Consenter, *etcdraft.Consenter) {
+	ca, err := tlsgen.NewCA()
+	assert.NoError(t, err)
+	clientKeyPair, err := ca.NewClientCertKeyPair()
+	assert.NoError(t, err)
+	serverKeyPair, err := ca.NewServerCertKeyPair("127.0.0.1")
+	assert.NoError(t, err)
+
+	clusterComm := cluster.NewComm(cluster.NewTLSPinningDialer(comm.ClientConfig{}), time.Second)
+	c := New(clusterComm, clientKeyPair.Cert, Config{WALDir: "/tmp/wal", SnapDir: "/tmp/snap"})
+	assert.True(t, clusterComm.H == c)
+
+	return c, &etcdraft.Consenter{
+		Host:          "127.0.0.1",
+		Port:          7050,
+		ClientTlsCert: clientKeyPair.Cert,
+		ServerTlsCert: serverKeyPair.Cert,
+	}
+}
+
+func TestHandleChainFailures(t *testing.T) {
+	c, self := newTestConsenter(t)
+	_, other := newTestConsenter(t)
+
+	t.Run("Bad consensus metadata", func(t *testing.T) {
+		support := newLedgerSupport(&etcdraft.Metadata{})
+		support.SharedConfigVal.ConsensusMetadataVal = []byte{1, 2, 3}
+		_, err := c.HandleChain(support, nil)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to unmarshal consensus metadata")
+	})
+
+	t.Run("Missing options", func(t *testing.T) {
+		support := newLedgerSupport(&etcdraft.Metadata{Consenters: []*etcdraft.Consenter{self}})
+		_, err := c.HandleChain(support, nil)
+		assert.EqualError(t, err, "etcdraft options have not been provided")
+	})
+
+	t.Run("Bad block metadata", func(t *testing.T) {
+		support := newLedgerSupport(&etcdraft.Metadata{Consenters: []*etcdraft.Consenter{self}, Options: testOptions()})
+		_, err := c.HandleChain(support, &cb.Metadata{Value: []byte{1, 2, 3}})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to read Raft metadata")
+	})
+
+	t.Run("Not in the consenter set", func(t *testing.T) {
+		support := newLedgerSupport(&etcdraft.Metadata{Consenters: []*etcdraft.Consenter{other}, Options: testOptions()})
+		_, err := c.HandleChain(support, nil)
+		assert.EqualError(t, err, "failed to detect own Raft ID: could not find the certificate of this node in the consenter set")
+	})
+
+	t.Run("Bad tick interval", func(t *testing.T) {
+		options := testOptions()
+		options.TickInterval = "foo"
+		support := newLedgerSupport(&etcdraft.Metadata{Consenters: []*etcdraft.Consenter{self}, Options: options})
+		_, err := c.HandleChain(support, nil)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to parse TickInterval (foo) to time duration")
+	})
+}
+
+func TestDetectSelfID(t *testing.T) {
+	c, self := newTestConsenter(t)
+	_, other := newTestConsenter(t)
+
+	id, err := c.detectSelfID(map[uint64]*etcdraft.Consenter{3: other, 5: self})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(5), id)
+
+	_, err = c.detectSelfID(map[uint64]*etcdraft.Consenter{1: {ClientTlsCert: []byte("not a PEM")}})
+	assert.EqualError(t, err, "invalid client TLS certificate of consenter 1: failed decoding PEM certificate")
+}
+
+func TestReadRaftMetadata(t *testing.T) {
+	_, c1 := newTestConsenter(t)
+	_, c2 := newTestConsenter(t)
+	configMetadata := &etcdraft.Metadata{Consenters: []*etcdraft.Consenter{c1, c2}}
+
+	// IDs are assigned in the order of the configuration
+	m, err := readRaftMetadata(nil, configMetadata)
+	assert.NoError(t, err)
+	assert.Equal(t, map[uint64]*etcdraft.Consenter{1: c1, 2: c2}, m.Consenters)
+
+	// The block metadata takes precedence over the configuration
+	blockMetadata := &cb.Metadata{Value: utils.MarshalOrPanic(&etcdraft.RaftMetadata{
+		Consenters: map[uint64]*etcdraft.Consenter{7: c2},
+		RaftIndex:  42,
+	})}
+	m, err = readRaftMetadata(blockMetadata, configMetadata)
+	assert.NoError(t, err)
+	assert.Len(t, m.Consenters, 1)
+	assert.True(t, proto.Equal(c2, m.Consenters[7]))
+	assert.Equal(t, uint64(42), m.RaftIndex)
+}
+
+func TestSameEndpoints(t *testing.T) {
+	_, c1 := newTestConsenter(t)
+	_, c2 := newTestConsenter(t)
+	c2.Port = 7051
+	_, c3 := newTestConsenter(t)
+	c3.Port = 7052
+	current := map[uint64]*etcdraft.Consenter{1: c1, 2: c2}
+
+	assert.True(t, sameEndpoints(current, []*etcdraft.Consenter{c2, c1}))
+	assert.False(t, sameEndpoints(current, []*etcdraft.Consenter{c1}))
+	assert.False(t, sameEndpoints(current, []*etcdraft.Consenter{c1, c3}))
+
+	// the TLS certificates of the consenters may differ
+	_, rotated := newTestConsenter(t)
+	assert.True(t, sameEndpoints(current, []*etcdraft.Consenter{c2, rotated}))
+}
+
+func TestRotatedConsenters(t *testing.T) {
+	_, c1 := newTestConsenter(t)
+	_, c2 := newTestConsenter(t)
+	c2.Port = 7051
+	current := map[uint64]*etcdraft.Consenter{1: c1, 2: c2}
+
+	consenters, changed := rotatedConsenters(current, []*etcdraft.Consenter{c2, c1})
+	assert.False(t, changed)
+	assert.Equal(t, current, consenters)
+
+	_, rotated := newTestConsenter(t)
+	rotated.Port = 7051
+	consenters, changed = rotatedConsenters(current, []*etcdraft.Consenter{c1, rotated})
+	assert.True(t, changed)
+	assert.Equal(t, map[uint64]*etcdraft.Consenter{1: c1, 2: rotated}, consenters)
+	// the current mapping is left untouched
+	assert.Equal(t, map[uint64]*etcdraft.Consenter{1: c1, 2: c2}, current)
+}
+
+func TestUnknownChannel(t *testing.T) {
+	c, _ := newTestConsenter(t)
+
+	_, err := c.OnStep("foo", 1, &orderer.StepRequest{})
+	assert.EqualError(t, err, "channel foo is not served by this consenter")
+	_, err = c.OnSubmit("foo", 1, &orderer.SubmitRequest{})
+	assert.EqualError(t, err, "channel foo is not served by this consenter")
+	_, err = c.OnPull("foo", 1, &orderer.PullRequest{})
+	assert.EqualError(t, err, "channel foo is not served by this consenter")
+}
@@ -0,0 +1,236 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package etcdraft
+
+import (
+	"os"
+
+	"github.com/coreos/etcd/raft"
+	"github.com/coreos/etcd/raft/raftpb"
+	"github.com/coreos/etcd/snap"
+	"github.com/coreos/etcd/wal"
+	"github.com/coreos/etcd/wal/walpb"
+	"github.com/op/go-logging"
+	"github.com/pkg/errors"
+)
+
+// MemoryStorage is currently backed by etcd/raft.MemoryStorage. This interface is
+// defined to expose dependencies of etcdraft so that it may be swapped in the
+// future.
+type MemoryStorage interface {
+	raft.Storage
+	Append(entries []raftpb.Entry) error
+	SetHardState(st raftpb.HardState) error
+	CreateSnapshot(i uint64, cs *raftpb.ConfState, data []byte) (raftpb.Snapshot, error)
+	Compact(compactIndex uint64) error
+	ApplySnapshot(snap raftpb.Snapshot) error
+}
+
+// RaftStorage encapsulates storages needed for etcd/raft data, i.e. memory, wal, snapshot
+type RaftStorage struct {
+	// SnapshotCatchUpEntries is the number of entries that are retained in memory
+	// after a snapshot is taken, so that slow followers can catch up without
+	// requiring the whole snapshot to be sent to them
+	SnapshotCatchUpEntries uint64
+
+	lg *logging.Logger
+
+	ram  MemoryStorage
+	wal  *wal.WAL
+	snap *snap.Snapshotter
+}
+
+// CreateStorage attempts to create a storage to persist etcd/raft data.
+// If data presents in specified disk, they are loaded to reconstruct storage state.
+func CreateStorage(
+	lg *logging.Logger,
+	walDir string,
+	snapDir string,
+	ram MemoryStorage,
+) (*RaftStorage, error) {
+	sn, err := createSnapshotter(snapDir)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := sn.Load()
+	if err != nil {
+		if err != snap.ErrNoSnapshot {
+			return nil, errors.Wrapf(err, "failed to load snapshot")
+		}
+		lg.Debugf("No snapshot found at %s", snapDir)
+		snapshot = &raftpb.Snapshot{}
+	} else {
+		lg.Debugf("Loaded snapshot at Term %d and Index %d", snapshot.Metadata.Term, snapshot.Metadata.Index)
+	}
+
+	w, err := createOrReadWAL(lg, walDir, snapshot)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create or read WAL")
+	}
+
+	_, st, ents, err := w.ReadAll()
+	if err != nil {
+		w.Close()
+		return nil, errors.Wrapf(err, "failed to read WAL")
+	}
+
+	if !raft.IsEmptySnap(*snapshot) {
+		if err := ram.ApplySnapshot(*snapshot); err != nil {
+			w.Close()
+			return nil, errors.Wrapf(err, "failed to apply snapshot to memory")
+		}
+	}
+
+	lg.Debugf("Setting HardState to {Term: %d, Commit: %d}", st.Term, st.Commit)
+	ram.SetHardState(st) // MemoryStorage.SetHardState always returns nil
+
+	lg.Debugf("Appending %d entries to memory storage", len(ents))
+	if err := ram.Append(ents); err != nil {
+		w.Close()
+		return nil, errors.Wrapf(err, "failed to append entries to memory storage")
+	}
+
+	return &RaftStorage{lg: lg, ram: ram, wal: w, snap: sn}, nil
+}
+
+func createSnapshotter(snapDir string) (*snap.Snapshotter, error) {
+	if err := os.MkdirAll(snapDir, os.ModePerm); err != nil {
+		return nil, errors.Errorf("failed to mkdir '%s' for snapshot: %s", snapDir, err)
+	}
+
+	return snap.New(snapDir), nil
+}
+
+func createOrReadWAL(lg *logging.Logger, walDir string, snapshot *raftpb.Snapshot) (*wal.WAL, error) {
+	if !wal.Exist(walDir) {
+		lg.Infof("No WAL data found, creating new WAL at path '%s'", walDir)
+		w, err := wal.Create(walDir, nil)
+		if err == os.ErrExist {
+			lg.Fatalf("programming error, we've just checked that WAL does not exist")
+		}
+
+		if err != nil {
+			return nil, errors.Errorf("failed to initialize WAL: %s", err)
+		}
+
+		if err = w.Close(); err != nil {
+			return nil, errors.Errorf("failed to close the WAL just created: %s", err)
+		}
+	} else {
+		lg.Infof("Found WAL data at path '%s', replaying it", walDir)
+	}
+
+	walsnap := walpb.Snapshot{
+		Index: snapshot.Metadata.Index,
+		Term:  snapshot.Metadata.Term,
+	}
+
+	lg.Debugf("Loading WAL at Term %d and Index %d", walsnap.Term, walsnap.Index)
+
+	w, err := wal.Open(walDir, walsnap)
+	if err != nil {
+		return nil, errors.Errorf("failed to open existing WAL: %s", err)
+	}
+
+	return w, nil
+}
+
+// Snapshot returns the latest snapshot stored in memory
+func (rs *RaftStorage) Snapshot() raftpb.Snapshot {
+	sn, _ := rs.ram.Snapshot() // Snapshot always returns nil error
+	return sn
+}
+
+// Store persists etcd/raft data
+func (rs *RaftStorage) Store(entries []raftpb.Entry, hardstate raftpb.HardState, snapshot raftpb.Snapshot) error {
+	if err := rs.wal.Save(hardstate, entries); err != nil {
+		return err
+	}
+
+	if !raft.IsEmptySnap(snapshot) {
+		if err := rs.saveSnap(snapshot); err != nil {
+			return err
+		}
+
+		if err := rs.ram.ApplySnapshot(snapshot); err != nil {
+			if err == raft.ErrSnapOutOfDate {
+				rs.lg.Warningf("Attempted to apply out-of-date snapshot at Term %d and Index %d",
+					snapshot.Metadata.Term, snapshot.Metadata.Index)
+			} else {
+				rs.lg.Fatalf("Unexpected programming error: %s", err)
+			}
+		}
+	}
+
+	return rs.ram.Append(entries)
+}
+
+func (rs *RaftStorage) saveSnap(snap raftpb.Snapshot) error {
+	// must save the snapshot index to the WAL before saving the
+	// snapshot to maintain the invariant that we only Open the
+	// wal at previously-saved snapshot indexes.
+	walsnap := walpb.Snapshot{
+		Index: snap.Metadata.Index,
+		Term:  snap.Metadata.Term,
+	}
+
+	rs.lg.Debugf("Saving snapshot to WAL")
+	if err := rs.wal.SaveSnapshot(walsnap); err != nil {
+		return errors.Errorf("failed to save snapshot to WAL: %s", err)
+	}
+
+	rs.lg.Debugf("Saving snapshot to disk")
+	if err := rs.snap.SaveSnap(snap); err != nil {
+		return errors.Errorf("failed to save snapshot to disk: %s", err)
+	}
+
+	rs.lg.Debugf("Releasing lock to wal files prior to %d", snap.Metadata.Index)
+	if err := rs.wal.ReleaseLockTo(snap.Metadata.Index); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// TakeSnapshot takes a snapshot at index i from MemoryStorage, and persists it to wal and disk.
+func (rs *RaftStorage) TakeSnapshot(i uint64, cs raftpb.ConfState, data []byte) error {
+	rs.lg.Debugf("Creating snapshot at index %d from MemoryStorage", i)
+	snap, err := rs.ram.CreateSnapshot(i, &cs, data)
+	if err != nil {
+		return errors.Errorf("failed to create snapshot from MemoryStorage: %s", err)
+	}
+
+	if err = rs.saveSnap(snap); err != nil {
+		return err
+	}
+
+	// Keep some entries in memory for slow followers to catchup
+	if i > rs.SnapshotCatchUpEntries {
+		compacti := i - rs.SnapshotCatchUpEntries
+		rs.lg.Debugf("Purging in-memory raft entries prior to %d", compacti)
+		if err = rs.ram.Compact(compacti); err != nil {
+			if err == raft.ErrCompacted {
+				rs.lg.Warningf("Raft entries prior to %d are already purged", compacti)
+			} else {
+				rs.lg.Fatalf("Failed to purge raft entries: %s", err)
+			}
+		}
+	}
+
+	rs.lg.Infof("Snapshot is taken at index %d", i)
+	return nil
+}
+
+// Close closes storage
+func (rs *RaftStorage) Close() error {
+	if err := rs.wal.Close(); err != nil {
+		return err
+	}
+
+	return nil
+}
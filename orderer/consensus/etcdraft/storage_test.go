@@ -0,0 +1,95 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package etcdraft
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/coreos/etcd/raft"
+	"github.com/coreos/etcd/raft/raftpb"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateStorage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "etcdraft-storage-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	walDir, snapDir := path.Join(dir, "wal"), path.Join(dir, "snap")
+	lg := flogging.MustGetLogger(pkgLogID)
+
+	// Fresh storage
+	ram := raft.NewMemoryStorage()
+	rs, err := CreateStorage(lg, walDir, snapDir, ram)
+	assert.NoError(t, err)
+	rs.SnapshotCatchUpEntries = 1
+
+	entries := []raftpb.Entry{
+		{Term: 1, Index: 1, Data: []byte("foo")},
+		{Term: 1, Index: 2, Data: []byte("bar")},
+		{Term: 1, Index: 3, Data: []byte("baz")},
+	}
+	hardstate := raftpb.HardState{Term: 1, Vote: 1, Commit: 3}
+	err = rs.Store(entries, hardstate, raftpb.Snapshot{})
+	assert.NoError(t, err)
+
+	lastIndex, err := ram.LastIndex()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), lastIndex)
+
+	cs := raftpb.ConfState{Nodes: []uint64{1}}
+	err = rs.TakeSnapshot(2, cs, []byte("snapshot"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), rs.Snapshot().Metadata.Index)
+
+	// Entries prior to the snapshot index minus SnapshotCatchUpEntries are purged
+	firstIndex, err := ram.FirstIndex()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), firstIndex)
+
+	err = rs.TakeSnapshot(1, cs, []byte("stale"))
+	assert.Error(t, err)
+	assert.NoError(t, rs.Close())
+
+	// Storage restored from disk
+	ram = raft.NewMemoryStorage()
+	rs, err = CreateStorage(lg, walDir, snapDir, ram)
+	assert.NoError(t, err)
+	defer rs.Close()
+
+	snap := rs.Snapshot()
+	assert.Equal(t, uint64(2), snap.Metadata.Index)
+	assert.Equal(t, []byte("snapshot"), snap.Data)
+	assert.Equal(t, cs, snap.Metadata.ConfState)
+
+	st, _, err := ram.InitialState()
+	assert.NoError(t, err)
+	assert.Equal(t, hardstate, st)
+
+	ents, err := ram.Entries(3, 4, ^uint64(0))
+	assert.NoError(t, err)
+	assert.Len(t, ents, 1)
+	assert.Equal(t, []byte("baz"), ents[0].Data)
+}
+
+func TestCreateStorageFailure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "etcdraft-storage-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// The snapshot directory cannot be created under a file
+	file := path.Join(dir, "file")
+	assert.NoError(t, ioutil.WriteFile(file, []byte{}, 0600))
+
+	_, err = CreateStorage(flogging.MustGetLogger(pkgLogID), path.Join(dir, "wal"), path.Join(file, "snap"), raft.NewMemoryStorage())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to mkdir")
+}
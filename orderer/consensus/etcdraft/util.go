@@ -0,0 +1,219 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package etcdraft
+
+import (
+	"encoding/pem"
+	"fmt"
+
+	"github.com/coreos/etcd/raft"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/orderer/common/cluster"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/orderer/etcdraft"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// isConfig returns true if the given envelope carries a configuration
+// transaction, i.e. either a channel config update or a channel creation
+// request on the system channel
+func isConfig(env *cb.Envelope) bool {
+	chdr, err := utils.ChannelHeader(env)
+	if err != nil {
+		return false
+	}
+
+	return chdr.Type == int32(cb.HeaderType_CONFIG) || chdr.Type == int32(cb.HeaderType_ORDERER_TRANSACTION)
+}
+
+// isConfigBlock returns true if the given block has to be written
+// by means of WriteConfigBlock
+func isConfigBlock(block *cb.Block) bool {
+	if block.Data == nil || len(block.Data.Data) != 1 {
+		return false
+	}
+
+	env, err := utils.ExtractEnvelope(block, 0)
+	if err != nil {
+		return false
+	}
+
+	return isConfig(env)
+}
+
+// pemToDER decodes the first PEM block of the given bytes
+func pemToDER(pemBytes []byte) ([]byte, error) {
+	bl, _ := pem.Decode(pemBytes)
+	if bl == nil {
+		return nil, errors.New("failed decoding PEM certificate")
+	}
+	return bl.Bytes, nil
+}
+
+// readRaftMetadata returns the consenters mapping of the chain. It is read from the
+// metadata of the last block if present, otherwise the consenters of the
+// channel configuration are assigned the IDs 1 to n in their order of appearance.
+func readRaftMetadata(blockMetadata *cb.Metadata, configMetadata *etcdraft.Metadata) (*etcdraft.RaftMetadata, error) {
+	m := &etcdraft.RaftMetadata{
+		Consenters: map[uint64]*etcdraft.Consenter{},
+	}
+
+	if blockMetadata != nil && len(blockMetadata.Value) != 0 {
+		if err := proto.Unmarshal(blockMetadata.Value, m); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal block's metadata")
+		}
+		return m, nil
+	}
+
+	for i, consenter := range configMetadata.Consenters {
+		m.Consenters[uint64(i+1)] = consenter
+	}
+
+	return m, nil
+}
+
+// remoteNodes converts the consenters of the given metadata, except the one with the given ID,
+// into the cluster members the communication layer needs to connect to
+func remoteNodes(self uint64, m *etcdraft.RaftMetadata) ([]cluster.RemoteNode, error) {
+	var nodes []cluster.RemoteNode
+	for id, consenter := range m.Consenters {
+		if id == self {
+			continue
+		}
+
+		serverCert, err := pemToDER(consenter.ServerTlsCert)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid server TLS certificate of node %d", id)
+		}
+		clientCert, err := pemToDER(consenter.ClientTlsCert)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid client TLS certificate of node %d", id)
+		}
+
+		nodes = append(nodes, cluster.RemoteNode{
+			ID:            id,
+			Endpoint:      endpoint(consenter),
+			ServerTLSCert: serverCert,
+			ClientTLSCert: clientCert,
+		})
+	}
+	return nodes, nil
+}
+
+// raftPeers returns the raft peers of the given consenters mapping
+func raftPeers(m *etcdraft.RaftMetadata) []raft.Peer {
+	var peers []raft.Peer
+	for id := range m.Consenters {
+		peers = append(peers, raft.Peer{ID: id})
+	}
+	return peers
+}
+
+// sameEndpoints returns true if both sets contain consenters with the same
+// endpoints, regardless of their order and of their TLS certificates
+func sameEndpoints(current map[uint64]*etcdraft.Consenter, updated []*etcdraft.Consenter) bool {
+	if len(current) != len(updated) {
+		return false
+	}
+
+	for _, u := range updated {
+		found := false
+		for _, c := range current {
+			if endpoint(c) == endpoint(u) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// rotatedConsenters returns a copy of the current consenters mapping in which every
+// consenter has the TLS certificates of the updated consenter with the same endpoint,
+// and whether any certificate changed
+func rotatedConsenters(current map[uint64]*etcdraft.Consenter, updated []*etcdraft.Consenter) (map[uint64]*etcdraft.Consenter, bool) {
+	rotated := make(map[uint64]*etcdraft.Consenter, len(current))
+	changed := false
+	for id, c := range current {
+		rotated[id] = c
+		for _, u := range updated {
+			if endpoint(c) != endpoint(u) {
+				continue
+			}
+			if !proto.Equal(c, u) {
+				rotated[id] = &etcdraft.Consenter{
+					Host:          c.Host,
+					Port:          c.Port,
+					ClientTlsCert: u.ClientTlsCert,
+					ServerTlsCert: u.ServerTlsCert,
+				}
+				changed = true
+			}
+			break
+		}
+	}
+	return rotated, changed
+}
+
+// configBlockConsenters returns the consenters of the channel configuration carried by the
+// given config block, and false if the block doesn't update the configuration of this channel
+func configBlockConsenters(block *cb.Block) ([]*etcdraft.Consenter, bool, error) {
+	env, err := utils.ExtractEnvelope(block, 0)
+	if err != nil {
+		return nil, false, err
+	}
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return nil, false, err
+	}
+	if payload.Header == nil {
+		return nil, false, errors.New("config transaction is missing a header")
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return nil, false, err
+	}
+	if chdr.Type != int32(cb.HeaderType_CONFIG) {
+		return nil, false, nil
+	}
+
+	configEnv, err := configtx.UnmarshalConfigEnvelope(payload.Data)
+	if err != nil {
+		return nil, false, err
+	}
+	if configEnv.Config == nil || configEnv.Config.ChannelGroup == nil {
+		return nil, false, errors.New("config has no channel group")
+	}
+	ordererGroup, ok := configEnv.Config.ChannelGroup.Groups[channelconfig.OrdererGroupKey]
+	if !ok {
+		return nil, false, errors.New("config has no orderer group")
+	}
+	value, ok := ordererGroup.Values[channelconfig.ConsensusTypeKey]
+	if !ok {
+		return nil, false, errors.New("config has no consensus type")
+	}
+	consensusType := &orderer.ConsensusType{}
+	if err := proto.Unmarshal(value.Value, consensusType); err != nil {
+		return nil, false, errors.Wrap(err, "failed to unmarshal consensus type")
+	}
+	m := &etcdraft.Metadata{}
+	if err := proto.Unmarshal(consensusType.Metadata, m); err != nil {
+		return nil, false, errors.Wrap(err, "failed to unmarshal etcdraft metadata")
+	}
+	return m.Consenters, true, nil
+}
+
+func endpoint(consenter *etcdraft.Consenter) string {
+	return fmt.Sprintf("%s:%d", consenter.Host, consenter.Port)
+}
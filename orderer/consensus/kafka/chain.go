@@ -7,12 +7,14 @@ SPDX-License-Identifier: Apache-2.0
 package kafka
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"time"
 
 	"github.com/Shopify/sarama"
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/orderer/common/blockcutter"
 	localconfig "github.com/hyperledger/fabric/orderer/common/localconfig"
 	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
 	"github.com/hyperledger/fabric/orderer/consensus"
@@ -117,6 +119,41 @@ func (chain *chainImpl) Errored() <-chan struct{} {
 	return chain.errorChan
 }
 
+// HealthCheck checks whether the Kafka brokers of the channel are reachable.
+// An error listing the unreachable brokers is returned if any of them cannot be
+// connected to.
+func (chain *chainImpl) HealthCheck(ctx context.Context) error {
+	var unreachable []string
+	for _, address := range chain.SharedConfig().KafkaBrokers() {
+		if err := checkBrokerReachable(address, chain.consenter.brokerConfig()); err != nil {
+			logger.Debugf("[channel: %s] Kafka broker %s is unreachable: %s", chain.ChainID(), address, err)
+			unreachable = append(unreachable, address)
+		}
+	}
+	if len(unreachable) > 0 {
+		return fmt.Errorf("unable to reach Kafka brokers %v", unreachable)
+	}
+	return nil
+}
+
+// checkBrokerReachable opens, and then closes, a connection to the Kafka broker
+// at the given address
+func checkBrokerReachable(address string, brokerConfig *sarama.Config) error {
+	broker := sarama.NewBroker(address)
+	if err := broker.Open(brokerConfig); err != nil {
+		return err
+	}
+	defer broker.Close()
+	connected, err := broker.Connected()
+	if err != nil {
+		return err
+	}
+	if !connected {
+		return fmt.Errorf("not connected to %s", address)
+	}
+	return nil
+}
+
 // Start allocates the necessary resources for staying up to date with this
 // Chain. Implements the consensus.Chain interface. Called by
 // consensus.NewManagerImpl() which is invoked when the ordering process is
@@ -293,6 +330,7 @@ func (chain *chainImpl) processMessagesToBlocks() ([]uint64, error) {
 	}()
 
 	subscription := fmt.Sprintf("added subscription to %s/%d", chain.channel.topic(), chain.channel.partition())
+	consumerLag := newConsumerLagGauge(chain.ChainID(), chain.channel)
 	var topicPartitionSubscriptionResumed <-chan string
 	var deliverSessionTimer *time.Timer
 	var deliverSessionTimedOut <-chan time.Time
@@ -368,6 +406,7 @@ func (chain *chainImpl) processMessagesToBlocks() ([]uint64, error) {
 				logger.Criticalf("[channel: %s] Kafka consumer closed.", chain.ChainID())
 				return counts, nil
 			}
+			consumerLag.Update(float64(chain.channelConsumer.HighWaterMarkOffset() - in.Offset - 1))
 
 			// catch the possibility that we missed a topic subscription event before
 			// we registered the event listener
@@ -614,7 +653,7 @@ func (chain *chainImpl) processRegular(regularMessage *ab.KafkaMessageRegular, r
 	//   Kafka message, so that `lastOriginalOffsetProcessed` is advanced
 	commitConfigMsg := func(message *cb.Envelope, newOffset int64) {
 		logger.Debugf("[channel: %s] Received config message", chain.ChainID())
-		batch := chain.BlockCutter().Cut()
+		batch := chain.BlockCutter().Cut(blockcutter.CutConfigIsolation)
 
 		if batch != nil {
 			logger.Debugf("[channel: %s] Cut pending messages into block", chain.ChainID())
@@ -828,7 +867,7 @@ func (chain *chainImpl) processTimeToCut(ttcMessage *ab.KafkaMessageTimeToCut, r
 	if ttcNumber == chain.lastCutBlockNumber+1 {
 		chain.timer = nil
 		logger.Debugf("[channel: %s] Nil'd the timer", chain.ChainID())
-		batch := chain.BlockCutter().Cut()
+		batch := chain.BlockCutter().Cut(blockcutter.CutBatchTimeout)
 		if len(batch) == 0 {
 			return fmt.Errorf("got right time-to-cut message (for block %d),"+
 				" no pending requests though; this might indicate a bug", chain.lastCutBlockNumber+1)
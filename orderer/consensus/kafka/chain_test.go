@@ -7,7 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package kafka
 
 import (
+	"context"
 	"fmt"
+	"strconv"
 	"testing"
 	"time"
 
@@ -15,6 +17,7 @@ import (
 	"github.com/Shopify/sarama/mocks"
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/metrics"
 	mockconfig "github.com/hyperledger/fabric/common/mocks/config"
 	"github.com/hyperledger/fabric/orderer/common/blockcutter"
 	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
@@ -95,6 +98,18 @@ func TestChain(t *testing.T) {
 		}
 	})
 
+	t.Run("HealthCheck", func(t *testing.T) {
+		_, mockBroker, mockSupport := newMocks(t)
+		chain, _ := newChain(mockConsenter, mockSupport, newestOffset-1, lastOriginalOffsetProcessed, lastResubmittedConfigOffset)
+
+		assert.NoError(t, chain.HealthCheck(context.Background()), "Expected the broker to be reachable")
+
+		mockBroker.Close()
+		err := chain.HealthCheck(context.Background())
+		assert.Error(t, err, "Expected the broker to be unreachable")
+		assert.Contains(t, err.Error(), mockBroker.Addr())
+	})
+
 	t.Run("Start", func(t *testing.T) {
 		_, mockBroker, mockSupport := newMocks(t)
 		defer func() { mockBroker.Close() }()
@@ -2168,6 +2183,74 @@ func TestProcessMessagesToBlocks(t *testing.T) {
 }
 
 // This ensures message is re-validated if config seq has advanced
+func TestConsumerLagMetrics(t *testing.T) {
+	testScope, restore := metrics.UseTestScope()
+	defer restore()
+
+	mockChannel := newChannel("mockChannelFoo", defaultPartition)
+	consumerLag := func() []float64 {
+		return testScope.GaugeValues("kafka.consumer_lag", map[string]string{
+			"channel":   mockChannel.topic(),
+			"topic":     mockChannel.topic(),
+			"partition": strconv.Itoa(int(mockChannel.partition())),
+		})
+	}
+
+	mockParentConsumer := mocks.NewConsumer(t, mockBrokerConfig)
+	mpc := mockParentConsumer.ExpectConsumePartition(mockChannel.topic(), mockChannel.partition(), int64(0))
+	mockChannelConsumer, err := mockParentConsumer.ConsumePartition(mockChannel.topic(), mockChannel.partition(), int64(0))
+	assert.NoError(t, err, "Expected no error when setting up the mock partition consumer")
+
+	errorChan := make(chan struct{})
+	close(errorChan)
+	haltChan := make(chan struct{})
+
+	mockSupport := &mockmultichannel.ConsenterSupport{
+		Blocks:         make(chan *cb.Block), // WriteBlock will post here
+		BlockCutterVal: mockblockcutter.NewReceiver(),
+		ChainIDVal:     mockChannel.topic(),
+		HeightVal:      uint64(3),
+		SharedConfigVal: &mockconfig.Orderer{
+			BatchTimeoutVal: longTimeout,
+		},
+	}
+	defer close(mockSupport.BlockCutterVal.Block)
+
+	bareMinimumChain := &chainImpl{
+		parentConsumer:  mockParentConsumer,
+		channelConsumer: mockChannelConsumer,
+
+		channel:            mockChannel,
+		ConsenterSupport:   mockSupport,
+		lastCutBlockNumber: uint64(3),
+
+		errorChan:                      errorChan,
+		haltChan:                       haltChan,
+		doneProcessingMessagesToBlocks: make(chan struct{}),
+	}
+
+	// All three messages are in the partition before the chain starts consuming it
+	for i := 0; i < 3; i++ {
+		mpc.YieldMessage(newMockConsumerMessage(newRegularMessage(utils.MarshalOrPanic(newMockEnvelope("fooMessage")))))
+	}
+
+	done := make(chan struct{})
+	go func() {
+		_, err = bareMinimumChain.processMessagesToBlocks()
+		done <- struct{}{}
+	}()
+
+	for i := 0; i < 3; i++ {
+		mockSupport.BlockCutterVal.Block <- struct{}{} // Let the `mockblockcutter.Ordered` call return
+	}
+
+	close(haltChan) // Identical to chain.Halt()
+	<-done
+	assert.NoError(t, err, "Expected the processMessagesToBlocks call to return without errors")
+	// The lag decreases as the messages which were already in the partition are consumed
+	assert.Equal(t, []float64{2, 1, 0}, consumerLag())
+}
+
 func TestResubmission(t *testing.T) {
 	blockIngressMsg := func(t *testing.T, block bool, fn func() error) {
 		wait := make(chan struct{})
@@ -3152,7 +3235,7 @@ func TestDeliverSession(t *testing.T) {
 		defer env.broker2.Close()
 
 		// initialize consenter
-		consenter := New(mockLocalConfig.Kafka)
+		consenter := New(mockLocalConfig.Kafka, nil)
 
 		// initialize chain
 		metadata := &cb.Metadata{Value: utils.MarshalOrPanic(&ab.KafkaMetadata{LastOffsetPersisted: env.height})}
@@ -3241,7 +3324,7 @@ func TestDeliverSession(t *testing.T) {
 		defer env.broker0.Close()
 
 		// initialize consenter
-		consenter := New(mockLocalConfig.Kafka)
+		consenter := New(mockLocalConfig.Kafka, nil)
 
 		// initialize chain
 		metadata := &cb.Metadata{Value: utils.MarshalOrPanic(&ab.KafkaMetadata{LastOffsetPersisted: env.height})}
@@ -3303,7 +3386,7 @@ func TestDeliverSession(t *testing.T) {
 		defer env.broker0.Close()
 
 		// initialize consenter
-		consenter := New(mockLocalConfig.Kafka)
+		consenter := New(mockLocalConfig.Kafka, nil)
 
 		// initialize chain
 		metadata := &cb.Metadata{Value: utils.MarshalOrPanic(&ab.KafkaMetadata{LastOffsetPersisted: env.height})}
@@ -3375,8 +3458,8 @@ func (r *mockReceiver) Ordered(msg *cb.Envelope) (messageBatches [][]*cb.Envelop
 	return args.Get(0).([][]*cb.Envelope), args.Bool(1)
 }
 
-func (r *mockReceiver) Cut() []*cb.Envelope {
-	args := r.Called()
+func (r *mockReceiver) Cut(reason string) []*cb.Envelope {
+	args := r.Called(reason)
 	return args.Get(0).([]*cb.Envelope)
 }
 
@@ -3453,3 +3536,8 @@ func (c *mockConsenterSupport) Height() uint64 {
 	args := c.Called()
 	return args.Get(0).(uint64)
 }
+
+func (c *mockConsenterSupport) Block(number uint64) *cb.Block {
+	args := c.Called(number)
+	return args.Get(0).(*cb.Block)
+}
@@ -7,15 +7,26 @@ SPDX-License-Identifier: Apache-2.0
 package kafka
 
 import (
+	"fmt"
+
 	"github.com/Shopify/sarama"
+	"github.com/hyperledger/fabric/common/healthz"
 	localconfig "github.com/hyperledger/fabric/orderer/common/localconfig"
 	"github.com/hyperledger/fabric/orderer/consensus"
 	cb "github.com/hyperledger/fabric/protos/common"
 	logging "github.com/op/go-logging"
 )
 
-// New creates a Kafka-based consenter. Called by orderer's main.go.
-func New(config localconfig.Kafka) consensus.Consenter {
+// healthChecker is used to register the health checks of the chains with the
+// operations endpoint of the orderer
+type healthChecker interface {
+	RegisterChecker(component string, checker healthz.HealthChecker) error
+}
+
+// New creates a Kafka-based consenter. Called by orderer's main.go. If a
+// healthChecker is supplied, the reachability of the Kafka brokers of every
+// chain is registered with it.
+func New(config localconfig.Kafka, healthChecker healthChecker) consensus.Consenter {
 	if config.Verbose {
 		logging.SetLevel(logging.DEBUG, saramaLogID)
 	}
@@ -25,6 +36,7 @@ func New(config localconfig.Kafka) consensus.Consenter {
 		tlsConfigVal:    config.TLS,
 		retryOptionsVal: config.Retry,
 		kafkaVersionVal: config.Version,
+		healthChecker:   healthChecker,
 	}
 }
 
@@ -36,6 +48,7 @@ type consenterImpl struct {
 	tlsConfigVal    localconfig.TLS
 	retryOptionsVal localconfig.Retry
 	kafkaVersionVal sarama.KafkaVersion
+	healthChecker   healthChecker
 }
 
 // HandleChain creates/returns a reference to a consensus.Chain object for the
@@ -45,7 +58,17 @@ type consenterImpl struct {
 // existingChains.
 func (consenter *consenterImpl) HandleChain(support consensus.ConsenterSupport, metadata *cb.Metadata) (consensus.Chain, error) {
 	lastOffsetPersisted, lastOriginalOffsetProcessed, lastResubmittedConfigOffset := getOffsets(metadata.Value, support.ChainID())
-	return newChain(consenter, support, lastOffsetPersisted, lastOriginalOffsetProcessed, lastResubmittedConfigOffset)
+	chain, err := newChain(consenter, support, lastOffsetPersisted, lastOriginalOffsetProcessed, lastResubmittedConfigOffset)
+	if err != nil {
+		return nil, err
+	}
+	if consenter.healthChecker != nil {
+		component := fmt.Sprintf("kafka/%s", support.ChainID())
+		if err := consenter.healthChecker.RegisterChecker(component, chain); err != nil {
+			logger.Warningf("[channel: %s] Failed registering the health check of the Kafka brokers: %s", support.ChainID(), err)
+		}
+	}
+	return chain, nil
 }
 
 // commonConsenter allows us to retrieve the configuration options set on the
@@ -15,6 +15,7 @@ import (
 	"github.com/Shopify/sarama"
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/healthz"
 	mockconfig "github.com/hyperledger/fabric/common/mocks/config"
 	localconfig "github.com/hyperledger/fabric/orderer/common/localconfig"
 	"github.com/hyperledger/fabric/orderer/consensus"
@@ -56,11 +57,24 @@ func init() {
 }
 
 func TestNew(t *testing.T) {
-	_ = consensus.Consenter(New(mockLocalConfig.Kafka))
+	_ = consensus.Consenter(New(mockLocalConfig.Kafka, nil))
+}
+
+type mockHealthChecker struct {
+	checkers map[string]healthz.HealthChecker
+}
+
+func (m *mockHealthChecker) RegisterChecker(component string, checker healthz.HealthChecker) error {
+	if _, exists := m.checkers[component]; exists {
+		return fmt.Errorf("%s is already registered", component)
+	}
+	m.checkers[component] = checker
+	return nil
 }
 
 func TestHandleChain(t *testing.T) {
-	consenter := consensus.Consenter(New(mockLocalConfig.Kafka))
+	healthChecker := &mockHealthChecker{checkers: make(map[string]healthz.HealthChecker)}
+	consenter := consensus.Consenter(New(mockLocalConfig.Kafka, healthChecker))
 
 	oldestOffset := int64(0)
 	newestOffset := int64(5)
@@ -91,8 +105,9 @@ func TestHandleChain(t *testing.T) {
 
 	mockMetadata := &cb.Metadata{Value: utils.MarshalOrPanic(&ab.KafkaMetadata{LastOffsetPersisted: newestOffset - 1})}
 
-	_, err := consenter.HandleChain(mockSupport, mockMetadata)
+	chain, err := consenter.HandleChain(mockSupport, mockMetadata)
 	assert.NoError(t, err, "Expected the HandleChain call to return without errors")
+	assert.Equal(t, chain, healthChecker.checkers["kafka/"+mockChannel.topic()], "Expected the chain to be registered as health checker")
 }
 
 // Test helper functions and mock objects defined here
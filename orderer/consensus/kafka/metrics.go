@@ -0,0 +1,23 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kafka
+
+import (
+	"strconv"
+
+	"github.com/hyperledger/fabric/common/metrics"
+)
+
+// newConsumerLagGauge returns the gauge of the number of messages of the partition
+// of the given channel which are yet to be consumed
+func newConsumerLagGauge(chainID string, channel channel) metrics.Gauge {
+	return metrics.GetRootScope().SubScope("kafka").Tagged(map[string]string{
+		"channel":   chainID,
+		"topic":     channel.topic(),
+		"partition": strconv.Itoa(int(channel.partition())),
+	}).Gauge("consumer_lag")
+}
@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/orderer/common/blockcutter"
 	"github.com/hyperledger/fabric/orderer/consensus"
 	cb "github.com/hyperledger/fabric/protos/common"
 	"github.com/op/go-logging"
@@ -155,7 +156,7 @@ func (ch *chain) main() {
 						continue
 					}
 				}
-				batch := ch.support.BlockCutter().Cut()
+				batch := ch.support.BlockCutter().Cut(blockcutter.CutConfigIsolation)
 				if batch != nil {
 					block := ch.support.CreateNextBlock(batch)
 					ch.support.WriteBlock(block, nil)
@@ -169,7 +170,7 @@ func (ch *chain) main() {
 			//clear the timer
 			timer = nil
 
-			batch := ch.support.BlockCutter().Cut()
+			batch := ch.support.BlockCutter().Cut(blockcutter.CutBatchTimeout)
 			if len(batch) == 0 {
 				logger.Warningf("Batch timer expired with no pending requests, this might indicate a bug")
 				continue
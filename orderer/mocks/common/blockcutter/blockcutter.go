@@ -93,7 +93,7 @@ func (mbc *Receiver) Ordered(env *cb.Envelope) ([][]*cb.Envelope, bool) {
 }
 
 // Cut terminates the current batch, returning it
-func (mbc *Receiver) Cut() []*cb.Envelope {
+func (mbc *Receiver) Cut(reason string) []*cb.Envelope {
 	logger.Debugf("Cutting batch")
 	res := mbc.CurBatch
 	mbc.CurBatch = nil
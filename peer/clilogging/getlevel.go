@@ -25,9 +25,10 @@ import (
 
 func getLevelCmd(cf *LoggingCmdFactory) *cobra.Command {
 	var loggingGetLevelCmd = &cobra.Command{
-		Use:   "getlevel <module>",
-		Short: "Returns the logging level of the requested module logger.",
-		Long:  `Returns the logging level of the requested module logger. Note: the module name should exactly match the name that is displayed in the logs.`,
+		Use:        "getlevel <module>",
+		Short:      "Returns the logging level of the requested module logger.",
+		Long:       `Returns the logging level of the requested module logger. Note: the module name should exactly match the name that is displayed in the logs.`,
+		Deprecated: "use the /logspec resource of the operations endpoint instead",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return getLevel(cf, cmd, args)
 		},
@@ -14,9 +14,10 @@ import (
 
 func revertLevelsCmd(cf *LoggingCmdFactory) *cobra.Command {
 	var loggingRevertLevelsCmd = &cobra.Command{
-		Use:   "revertlevels",
-		Short: "Reverts the logging levels to the levels at the end of peer startup.",
-		Long:  `Reverts the logging levels to the levels at the end of peer startup`,
+		Use:        "revertlevels",
+		Short:      "Reverts the logging levels to the levels at the end of peer startup.",
+		Long:       `Reverts the logging levels to the levels at the end of peer startup`,
+		Deprecated: "use the /logspec resource of the operations endpoint instead",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return revertLevels(cf, cmd, args)
 		},
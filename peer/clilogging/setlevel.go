@@ -14,9 +14,10 @@ import (
 
 func setLevelCmd(cf *LoggingCmdFactory) *cobra.Command {
 	var loggingSetLevelCmd = &cobra.Command{
-		Use:   "setlevel <module regular expression> <log level>",
-		Short: "Sets the logging level for all modules that match the regular expression.",
-		Long:  `Sets the logging level for all modules that match the regular expression.`,
+		Use:        "setlevel <module regular expression> <log level>",
+		Short:      "Sets the logging level for all modules that match the regular expression.",
+		Long:       `Sets the logging level for all modules that match the regular expression.`,
+		Deprecated: "use the /logspec resource of the operations endpoint instead",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return setLevel(cf, cmd, args)
 		},
@@ -7,7 +7,6 @@ SPDX-License-Identifier: Apache-2.0
 package main
 
 import (
-	_ "net/http/pprof"
 	"os"
 	"runtime"
 	"strings"
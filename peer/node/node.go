@@ -25,7 +25,7 @@ import (
 
 const (
 	nodeFuncName = "node"
-	nodeCmdDes   = "Operate a peer node: start|status."
+	nodeCmdDes   = "Operate a peer node: start|status|reset|rollback|rebuild-dbs|snapshot|join-snapshot."
 )
 
 var logger = flogging.MustGetLogger("nodeCmd")
@@ -34,6 +34,11 @@ var logger = flogging.MustGetLogger("nodeCmd")
 func Cmd() *cobra.Command {
 	nodeCmd.AddCommand(startCmd())
 	nodeCmd.AddCommand(statusCmd())
+	nodeCmd.AddCommand(resetCmd())
+	nodeCmd.AddCommand(rollbackCmd())
+	nodeCmd.AddCommand(rebuildDBsCmd())
+	nodeCmd.AddCommand(snapshotCmd())
+	nodeCmd.AddCommand(joinSnapshotCmd())
 
 	return nodeCmd
 }
@@ -0,0 +1,40 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	"github.com/hyperledger/fabric/peer/common"
+	"github.com/spf13/cobra"
+)
+
+func rebuildDBsCmd() *cobra.Command {
+	flags := nodeRebuildDBsCmd.Flags()
+	flags.StringVarP(&channelID, "channelID", "c", common.UndefinedParamValue, "Channel whose databases are rebuilt. If not supplied, the databases of all channels are rebuilt.")
+	return nodeRebuildDBsCmd
+}
+
+var nodeRebuildDBsCmd = &cobra.Command{
+	Use:   "rebuild-dbs",
+	Short: "Rebuilds the databases.",
+	Long: `Drops the state, history and config history databases of a channel, or of all channels if no channel is ` +
+		`supplied. The databases are rebuilt on the next peer start by replaying the blocks and the private data of ` +
+		`the channels. When the command is executed, the peer must be offline.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 {
+			return fmt.Errorf("trailing args detected: %s", args)
+		}
+		// Parsing of the command line is done so silence cmd usage
+		cmd.SilenceUsage = true
+		if channelID == common.UndefinedParamValue {
+			return kvledger.RebuildAllDBs()
+		}
+		return kvledger.RebuildDBs(channelID)
+	},
+}
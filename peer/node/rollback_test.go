@@ -0,0 +1,76 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/hyperledger/fabric/peer/common"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRollbackCmd(t *testing.T) {
+	testPath, err := ioutil.TempDir("", "rollbackcmd")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testPath)
+	viper.Set("peer.fileSystemPath", testPath)
+	defer viper.Reset()
+
+	cmd := rollbackCmd()
+	defer func() { channelID, blockNumber = "", 0 }()
+
+	cmd.SetArgs([]string{})
+	assert.EqualError(t, cmd.Execute(), "must supply channel ID")
+
+	cmd.SetArgs([]string{"-c", "mychannel"})
+	assert.EqualError(t, cmd.Execute(), "must supply block number")
+
+	cmd.SetArgs([]string{"-c", "mychannel", "-b", "10", "extra"})
+	assert.EqualError(t, cmd.Execute(), "trailing args detected: [extra]")
+
+	cmd.SetArgs([]string{"-c", "mychannel", "-b", "10"})
+	assert.EqualError(t, cmd.Execute(), "LedgerID does not exist")
+}
+
+func TestResetCmd(t *testing.T) {
+	testPath, err := ioutil.TempDir("", "resetcmd")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testPath)
+	viper.Set("peer.fileSystemPath", testPath)
+	defer viper.Reset()
+
+	cmd := resetCmd()
+	cmd.SetArgs([]string{"extra"})
+	assert.EqualError(t, cmd.Execute(), "trailing args detected: [extra]")
+
+	cmd.SetArgs([]string{})
+	assert.NoError(t, cmd.Execute())
+}
+
+func TestRebuildDBsCmd(t *testing.T) {
+	testPath, err := ioutil.TempDir("", "rebuilddbscmd")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testPath)
+	viper.Set("peer.fileSystemPath", testPath)
+	defer viper.Reset()
+
+	cmd := rebuildDBsCmd()
+	defer func() { channelID = "" }()
+
+	cmd.SetArgs([]string{"extra"})
+	assert.EqualError(t, cmd.Execute(), "trailing args detected: [extra]")
+
+	cmd.SetArgs([]string{"-c", "mychannel"})
+	assert.EqualError(t, cmd.Execute(), "LedgerID does not exist")
+
+	channelID = common.UndefinedParamValue
+	cmd.SetArgs([]string{})
+	assert.NoError(t, cmd.Execute())
+}
@@ -0,0 +1,90 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	"github.com/hyperledger/fabric/peer/common"
+	"github.com/spf13/cobra"
+)
+
+var (
+	snapshotPath string
+	snapshotHash string
+)
+
+func snapshotCmd() *cobra.Command {
+	flags := nodeSnapshotCmd.Flags()
+	flags.StringVarP(&channelID, "channelID", "c", common.UndefinedParamValue, "Channel to snapshot.")
+	flags.StringVarP(&snapshotPath, "snapshotPath", "p", common.UndefinedParamValue, "Directory to which the snapshot is written. The directory must not exist.")
+	return nodeSnapshotCmd
+}
+
+var nodeSnapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Generates a snapshot of a channel.",
+	Long: `Generates a snapshot of the ledger of a channel at the current height of its block store. The snapshot ` +
+		`contains the state, the config history and the blocks that follow the last config block, and it can be ` +
+		`used by another peer to join the channel without replaying the blocks from the genesis block. ` +
+		`The hash of the snapshot is printed for verification by the joining peer. ` +
+		`When the command is executed, the peer must be offline.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 {
+			return fmt.Errorf("trailing args detected: %s", args)
+		}
+		if channelID == common.UndefinedParamValue {
+			return fmt.Errorf("must supply channel ID")
+		}
+		if snapshotPath == common.UndefinedParamValue {
+			return fmt.Errorf("must supply snapshot path")
+		}
+		// Parsing of the command line is done so silence cmd usage
+		cmd.SilenceUsage = true
+		hash, err := kvledger.GenerateSnapshot(channelID, snapshotPath)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Snapshot of channel [%s] written to [%s]\nSnapshot hash: %s\n", channelID, snapshotPath, hash)
+		return nil
+	},
+}
+
+func joinSnapshotCmd() *cobra.Command {
+	flags := nodeJoinSnapshotCmd.Flags()
+	flags.StringVarP(&snapshotPath, "snapshotPath", "p", common.UndefinedParamValue, "Directory that contains the snapshot.")
+	flags.StringVarP(&snapshotHash, "snapshotHash", "", common.UndefinedParamValue, "Expected hash of the snapshot, as printed by the snapshot command.")
+	return nodeJoinSnapshotCmd
+}
+
+var nodeJoinSnapshotCmd = &cobra.Command{
+	Use:   "join-snapshot",
+	Short: "Joins a channel from a snapshot.",
+	Long: `Creates the ledger of a channel from a snapshot generated by another peer, after verifying the snapshot ` +
+		`against the expected hash. The channel is joined on the next peer start and the peer continues from the ` +
+		`block that follows the last block of the snapshot. When the command is executed, the peer must be offline.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 {
+			return fmt.Errorf("trailing args detected: %s", args)
+		}
+		if snapshotPath == common.UndefinedParamValue {
+			return fmt.Errorf("must supply snapshot path")
+		}
+		if snapshotHash == common.UndefinedParamValue {
+			return fmt.Errorf("must supply snapshot hash")
+		}
+		// Parsing of the command line is done so silence cmd usage
+		cmd.SilenceUsage = true
+		ledgerID, err := kvledger.CreateLedgerFromSnapshot(snapshotPath, snapshotHash)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Ledger of channel [%s] created from the snapshot\n", ledgerID)
+		return nil
+	},
+}
@@ -0,0 +1,63 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotCmd(t *testing.T) {
+	testPath, err := ioutil.TempDir("", "snapshotcmd")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testPath)
+	viper.Set("peer.fileSystemPath", testPath)
+	defer viper.Reset()
+
+	cmd := snapshotCmd()
+	defer func() { channelID, snapshotPath = "", "" }()
+
+	cmd.SetArgs([]string{})
+	assert.EqualError(t, cmd.Execute(), "must supply channel ID")
+
+	cmd.SetArgs([]string{"-c", "mychannel"})
+	assert.EqualError(t, cmd.Execute(), "must supply snapshot path")
+
+	cmd.SetArgs([]string{"-c", "mychannel", "-p", filepath.Join(testPath, "snapshot"), "extra"})
+	assert.EqualError(t, cmd.Execute(), "trailing args detected: [extra]")
+
+	cmd.SetArgs([]string{"-c", "mychannel", "-p", filepath.Join(testPath, "snapshot")})
+	assert.EqualError(t, cmd.Execute(), "LedgerID does not exist")
+}
+
+func TestJoinSnapshotCmd(t *testing.T) {
+	testPath, err := ioutil.TempDir("", "joinsnapshotcmd")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testPath)
+	viper.Set("peer.fileSystemPath", testPath)
+	defer viper.Reset()
+
+	cmd := joinSnapshotCmd()
+	defer func() { snapshotPath, snapshotHash = "", "" }()
+
+	cmd.SetArgs([]string{})
+	assert.EqualError(t, cmd.Execute(), "must supply snapshot path")
+
+	cmd.SetArgs([]string{"-p", filepath.Join(testPath, "snapshot")})
+	assert.EqualError(t, cmd.Execute(), "must supply snapshot hash")
+
+	cmd.SetArgs([]string{"-p", filepath.Join(testPath, "snapshot"), "--snapshotHash", "hash", "extra"})
+	assert.EqualError(t, cmd.Execute(), "trailing args detected: [extra]")
+
+	cmd.SetArgs([]string{"-p", filepath.Join(testPath, "snapshot"), "--snapshotHash", "hash"})
+	assert.Error(t, cmd.Execute())
+}
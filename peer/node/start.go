@@ -10,9 +10,9 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net"
-	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 	"time"
 
@@ -22,6 +22,7 @@ import (
 	"github.com/hyperledger/fabric/common/deliver"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/localmsp"
+	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/common/viperutil"
 	"github.com/hyperledger/fabric/core/aclmgmt"
@@ -32,14 +33,20 @@ import (
 	"github.com/hyperledger/fabric/core/comm"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
+	coreconfig "github.com/hyperledger/fabric/core/config"
 	"github.com/hyperledger/fabric/core/container"
+	"github.com/hyperledger/fabric/core/container/dockercontroller"
+	"github.com/hyperledger/fabric/core/container/externalbuilder"
 	"github.com/hyperledger/fabric/core/endorser"
 	authHandler "github.com/hyperledger/fabric/core/handlers/auth"
 	endorsement2 "github.com/hyperledger/fabric/core/handlers/endorsement/api"
 	endorsement3 "github.com/hyperledger/fabric/core/handlers/endorsement/api/identities"
 	"github.com/hyperledger/fabric/core/handlers/library"
 	"github.com/hyperledger/fabric/core/ledger/cceventmgmt"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
+	"github.com/hyperledger/fabric/core/ledger/util/couchdb"
+	"github.com/hyperledger/fabric/core/operations"
 	"github.com/hyperledger/fabric/core/peer"
 	"github.com/hyperledger/fabric/core/scc"
 	"github.com/hyperledger/fabric/discovery"
@@ -142,12 +149,23 @@ func serve(args []string) error {
 
 	logger.Infof("Starting %s", version.GetInfo())
 
+	opsSystem := newOperationsSystem()
+	if err := startOperationsSystem(opsSystem); err != nil {
+		return err
+	}
+	defer opsSystem.Stop()
+
 	//startup aclmgmt with default ACL providers (resource based and default 1.0 policies based).
 	//Users can pass in their own ACLProvider to RegisterACLProvider (currently unit tests do this)
 	aclmgmt.RegisterACLProvider(nil)
 
 	//initialize resource management exit
 	ledgermgmt.Initialize(peer.ConfigTxProcessors)
+	if ledgerconfig.IsCouchDBEnabled() {
+		if err := registerCouchDBChecker(opsSystem); err != nil {
+			return err
+		}
+	}
 
 	// Parameter overrides must be processed before any parameters are
 	// cached. Failures to cache cause the server to terminate immediately.
@@ -212,7 +230,7 @@ func serve(args []string) error {
 		}
 	}
 
-	abServer := peer.NewDeliverEventsServer(mutualTLS, policyCheckerProvider, &peer.DeliverChainManager{})
+	abServer := peer.NewDeliverEventsServer(mutualTLS, policyCheckerProvider, &peer.DeliverChainManager{}, &peer.DeliverPrivateDataProvider{})
 	pb.RegisterDeliverServer(peerServer.Server(), abServer)
 
 	// Setup chaincode path
@@ -233,6 +251,13 @@ func serve(args []string) error {
 	chaincodeSupport, sccp := registerChaincodeSupport(ccSrv, ccEndpoint, ca)
 	go ccSrv.Start()
 
+	// the chaincodes are run in docker containers unless in development mode
+	if !chaincode.IsDevMode() {
+		if err := opsSystem.RegisterChecker("docker", dockercontroller.NewDockerVM()); err != nil {
+			return errors.WithMessage(err, "failed registering the docker health checker")
+		}
+	}
+
 	logger.Debugf("Running peer")
 
 	// Start the Admin server
@@ -388,17 +413,6 @@ func serve(args []string) error {
 		go ehubGrpcServer.Start()
 	}
 
-	// Start profiling http endpoint if enabled
-	if viper.GetBool("peer.profile.enabled") {
-		go func() {
-			profileListenAddress := viper.GetString("peer.profile.listenAddress")
-			logger.Infof("Starting profiling server with listenAddress = %s", profileListenAddress)
-			if profileErr := http.ListenAndServe(profileListenAddress, nil); profileErr != nil {
-				logger.Errorf("Error starting profiler: %s", profileErr)
-			}
-		}()
-	}
-
 	logger.Infof("Started peer with ID=[%s], network ID=[%s], address=[%s]",
 		peerEndpoint.Id, viper.GetString("peer.networkId"), peerEndpoint.Address)
 
@@ -406,6 +420,54 @@ func serve(args []string) error {
 	return <-serve
 }
 
+// newOperationsSystem creates the operations endpoint of the peer, which serves
+// the health checks, the logging specification, the metrics and, if enabled,
+// the Go pprof profiles.
+func newOperationsSystem() *operations.System {
+	var clientRootCAs []string
+	for _, file := range viper.GetStringSlice("operations.tls.clientRootCAs.files") {
+		clientRootCAs = append(clientRootCAs, coreconfig.TranslatePath(filepath.Dir(viper.ConfigFileUsed()), file))
+	}
+	return operations.NewSystem(operations.Options{
+		ListenAddress: viper.GetString("operations.listenAddress"),
+		TLS: operations.TLS{
+			Enabled:            viper.GetBool("operations.tls.enabled"),
+			CertFile:           coreconfig.GetPath("operations.tls.cert.file"),
+			KeyFile:            coreconfig.GetPath("operations.tls.key.file"),
+			ClientCertRequired: viper.GetBool("operations.tls.clientAuthRequired"),
+			ClientCACertFiles:  clientRootCAs,
+		},
+		Metrics:   metrics.NewOpts(),
+		Profiling: viper.GetBool("peer.profile.enabled"),
+	})
+}
+
+// startOperationsSystem starts the operations endpoint if a listen address is configured
+func startOperationsSystem(opsSystem *operations.System) error {
+	if viper.GetString("operations.listenAddress") == "" {
+		logger.Info("operations.listenAddress unset, the operations endpoint is disabled")
+		return nil
+	}
+	if err := opsSystem.Start(); err != nil {
+		return errors.WithMessage(err, "failed to start the operations endpoint")
+	}
+	return nil
+}
+
+// registerCouchDBChecker registers the health check of the CouchDB used as state database
+func registerCouchDBChecker(opsSystem *operations.System) error {
+	couchDBDef := couchdb.GetCouchDBDefinition()
+	couchInstance, err := couchdb.CreateCouchInstance(couchDBDef.URL, couchDBDef.Username, couchDBDef.Password,
+		couchDBDef.MaxRetries, couchDBDef.MaxRetriesOnStartup, couchDBDef.RequestTimeout)
+	if err != nil {
+		return errors.WithMessage(err, "failed connecting to CouchDB")
+	}
+	if err := opsSystem.RegisterChecker("couchdb", couchInstance); err != nil {
+		return errors.WithMessage(err, "failed registering the CouchDB health checker")
+	}
+	return nil
+}
+
 func localPolicy(policyObject proto.Message) policies.Policy {
 	localMSP := mgmt.GetLocalMSP()
 	pp := cauthdsl.NewPolicyProvider(localMSP)
@@ -589,16 +651,22 @@ func registerChaincodeSupport(grpcServer *comm.GRPCServer, ccEndpoint string, ca
 	userRunsCC := chaincode.IsDevMode()
 	tlsEnabled := viper.GetBool("peer.tls.enabled")
 
+	ccConfig := chaincode.GlobalConfig()
+	vmController := container.NewVMController()
+	if len(ccConfig.ExternalBuilders) > 0 {
+		vmController.RegisterVMProvider(container.EXTERNAL, externalbuilder.NewProvider(ccConfig.ExternalBuilders))
+	}
+
 	authenticator := accesscontrol.NewAuthenticator(ca)
 	chaincodeSupport := chaincode.NewChaincodeSupport(
-		chaincode.GlobalConfig(),
+		ccConfig,
 		ccEndpoint,
 		userRunsCC,
 		ca.CertBytes(),
 		authenticator,
 		&ccprovider.CCInfoFSImpl{},
 		aclmgmt.GetACLProvider(),
-		container.NewVMController(),
+		vmController,
 	)
 	chaincode.SideEffectInitialize(chaincodeSupport)
 
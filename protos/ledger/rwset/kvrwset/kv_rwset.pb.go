@@ -12,9 +12,9 @@ It has these top-level messages:
 	HashedRWSet
 	KVRead
 	KVWrite
+	KVMetadataWrite
 	KVReadHash
 	KVWriteHash
-	KVMetadataWrite
 	KVMetadataEntry
 	Version
 	RangeQueryInfo
@@ -41,9 +41,10 @@ const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 // KVRWSet encapsulates the read-write set for a chaincode that operates upon a KV or Document data model
 // This structure is used for both the public data and the private data
 type KVRWSet struct {
-	Reads            []*KVRead         `protobuf:"bytes,1,rep,name=reads" json:"reads,omitempty"`
-	RangeQueriesInfo []*RangeQueryInfo `protobuf:"bytes,2,rep,name=range_queries_info,json=rangeQueriesInfo" json:"range_queries_info,omitempty"`
-	Writes           []*KVWrite        `protobuf:"bytes,3,rep,name=writes" json:"writes,omitempty"`
+	Reads            []*KVRead          `protobuf:"bytes,1,rep,name=reads" json:"reads,omitempty"`
+	RangeQueriesInfo []*RangeQueryInfo  `protobuf:"bytes,2,rep,name=range_queries_info,json=rangeQueriesInfo" json:"range_queries_info,omitempty"`
+	Writes           []*KVWrite         `protobuf:"bytes,3,rep,name=writes" json:"writes,omitempty"`
+	MetadataWrites   []*KVMetadataWrite `protobuf:"bytes,4,rep,name=metadata_writes,json=metadataWrites" json:"metadata_writes,omitempty"`
 }
 
 func (m *KVRWSet) Reset()                    { *m = KVRWSet{} }
@@ -72,6 +73,13 @@ func (m *KVRWSet) GetWrites() []*KVWrite {
 	return nil
 }
 
+func (m *KVRWSet) GetMetadataWrites() []*KVMetadataWrite {
+	if m != nil {
+		return m.MetadataWrites
+	}
+	return nil
+}
+
 // HashedRWSet encapsulates hashed representation of a private read-write set for KV or Document data model
 type HashedRWSet struct {
 	HashedReads  []*KVReadHash  `protobuf:"bytes,1,rep,name=hashed_reads,json=hashedReads" json:"hashed_reads,omitempty"`
@@ -124,22 +132,10 @@ func (m *KVRead) GetVersion() *Version {
 }
 
 // KVWrite captures a write (update/delete) operation performed during transaction simulation
-// Four operations are supported:
-// 1) value update: if KeepExistingValue is false, the value is updated to Value
-// 2) metadata update: if Metadata is not nil, all entries in Metadata.Upserts are applied
-// 3) medatadata deletion: if Metadata is nil, all metadata is deleted
-// 4) deletion: if IsDelete is set, this key is removed from the ledger.
-// Note that 1 and 2 (or 3) may be combined.
 type KVWrite struct {
 	Key      string `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
 	IsDelete bool   `protobuf:"varint,2,opt,name=is_delete,json=isDelete" json:"is_delete,omitempty"`
 	Value    []byte `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
-	// The two values below have been added in v1.2. Note that their default values
-	// describe a behaviour which is consistent with that of of pre-1.2 code:
-	// - if KeepExistingValue is false, the value is updated to value (unless IsDelete is true).
-	// - if Metadata is nil, metadata is deleted for this key.
-	KeepExistingValue bool             `protobuf:"varint,4,opt,name=keep_existing_value,json=keepExistingValue" json:"keep_existing_value,omitempty"`
-	Metadata          *KVMetadataWrite `protobuf:"bytes,5,opt,name=metadata" json:"metadata,omitempty"`
 }
 
 func (m *KVWrite) Reset()                    { *m = KVWrite{} }
@@ -168,16 +164,29 @@ func (m *KVWrite) GetValue() []byte {
 	return nil
 }
 
-func (m *KVWrite) GetKeepExistingValue() bool {
+// KVMetadataWrite captures all the entries in the metadata associated with a key
+// The entries replace the existing metadata of the key; an empty list of entries
+// deletes the metadata. The value of the key is left unchanged
+type KVMetadataWrite struct {
+	Key     string             `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
+	Entries []*KVMetadataEntry `protobuf:"bytes,2,rep,name=entries" json:"entries,omitempty"`
+}
+
+func (m *KVMetadataWrite) Reset()                    { *m = KVMetadataWrite{} }
+func (m *KVMetadataWrite) String() string            { return proto.CompactTextString(m) }
+func (*KVMetadataWrite) ProtoMessage()               {}
+func (*KVMetadataWrite) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+func (m *KVMetadataWrite) GetKey() string {
 	if m != nil {
-		return m.KeepExistingValue
+		return m.Key
 	}
-	return false
+	return ""
 }
 
-func (m *KVWrite) GetMetadata() *KVMetadataWrite {
+func (m *KVMetadataWrite) GetEntries() []*KVMetadataEntry {
 	if m != nil {
-		return m.Metadata
+		return m.Entries
 	}
 	return nil
 }
@@ -193,7 +202,7 @@ type KVReadHash struct {
 func (m *KVReadHash) Reset()                    { *m = KVReadHash{} }
 func (m *KVReadHash) String() string            { return proto.CompactTextString(m) }
 func (*KVReadHash) ProtoMessage()               {}
-func (*KVReadHash) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+func (*KVReadHash) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
 
 func (m *KVReadHash) GetKeyHash() []byte {
 	if m != nil {
@@ -210,20 +219,16 @@ func (m *KVReadHash) GetVersion() *Version {
 }
 
 // KVWriteHash is similar to the KVWrite. It captures a write (update/delete) operation performed during transaction simulation
-// for a hashed key and to a hashed value. Note that metadata are stored in cleartext (no hash).
-// Similar considerations to KVWrite apply here.
 type KVWriteHash struct {
-	KeyHash           []byte           `protobuf:"bytes,1,opt,name=key_hash,json=keyHash,proto3" json:"key_hash,omitempty"`
-	IsDelete          bool             `protobuf:"varint,2,opt,name=is_delete,json=isDelete" json:"is_delete,omitempty"`
-	ValueHash         []byte           `protobuf:"bytes,3,opt,name=value_hash,json=valueHash,proto3" json:"value_hash,omitempty"`
-	KeepExistingValue bool             `protobuf:"varint,4,opt,name=keep_existing_value,json=keepExistingValue" json:"keep_existing_value,omitempty"`
-	Metadata          *KVMetadataWrite `protobuf:"bytes,5,opt,name=metadata" json:"metadata,omitempty"`
+	KeyHash   []byte `protobuf:"bytes,1,opt,name=key_hash,json=keyHash,proto3" json:"key_hash,omitempty"`
+	IsDelete  bool   `protobuf:"varint,2,opt,name=is_delete,json=isDelete" json:"is_delete,omitempty"`
+	ValueHash []byte `protobuf:"bytes,3,opt,name=value_hash,json=valueHash,proto3" json:"value_hash,omitempty"`
 }
 
 func (m *KVWriteHash) Reset()                    { *m = KVWriteHash{} }
 func (m *KVWriteHash) String() string            { return proto.CompactTextString(m) }
 func (*KVWriteHash) ProtoMessage()               {}
-func (*KVWriteHash) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+func (*KVWriteHash) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
 
 func (m *KVWriteHash) GetKeyHash() []byte {
 	if m != nil {
@@ -246,45 +251,6 @@ func (m *KVWriteHash) GetValueHash() []byte {
 	return nil
 }
 
-func (m *KVWriteHash) GetKeepExistingValue() bool {
-	if m != nil {
-		return m.KeepExistingValue
-	}
-	return false
-}
-
-func (m *KVWriteHash) GetMetadata() *KVMetadataWrite {
-	if m != nil {
-		return m.Metadata
-	}
-	return nil
-}
-
-// KVMetadata captures all the upserts to the metadata associated with a key
-type KVMetadataWrite struct {
-	Upserts []*KVMetadataEntry `protobuf:"bytes,1,rep,name=upserts" json:"upserts,omitempty"`
-	Deletes []string           `protobuf:"bytes,2,rep,name=deletes" json:"deletes,omitempty"`
-}
-
-func (m *KVMetadataWrite) Reset()                    { *m = KVMetadataWrite{} }
-func (m *KVMetadataWrite) String() string            { return proto.CompactTextString(m) }
-func (*KVMetadataWrite) ProtoMessage()               {}
-func (*KVMetadataWrite) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
-
-func (m *KVMetadataWrite) GetUpserts() []*KVMetadataEntry {
-	if m != nil {
-		return m.Upserts
-	}
-	return nil
-}
-
-func (m *KVMetadataWrite) GetDeletes() []string {
-	if m != nil {
-		return m.Deletes
-	}
-	return nil
-}
-
 // KVMetadataEntry captures a 'name'ed entry in the metadata of a key.
 type KVMetadataEntry struct {
 	Name  string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
@@ -546,9 +512,9 @@ func init() {
 	proto.RegisterType((*HashedRWSet)(nil), "kvrwset.HashedRWSet")
 	proto.RegisterType((*KVRead)(nil), "kvrwset.KVRead")
 	proto.RegisterType((*KVWrite)(nil), "kvrwset.KVWrite")
+	proto.RegisterType((*KVMetadataWrite)(nil), "kvrwset.KVMetadataWrite")
 	proto.RegisterType((*KVReadHash)(nil), "kvrwset.KVReadHash")
 	proto.RegisterType((*KVWriteHash)(nil), "kvrwset.KVWriteHash")
-	proto.RegisterType((*KVMetadataWrite)(nil), "kvrwset.KVMetadataWrite")
 	proto.RegisterType((*KVMetadataEntry)(nil), "kvrwset.KVMetadataEntry")
 	proto.RegisterType((*Version)(nil), "kvrwset.Version")
 	proto.RegisterType((*RangeQueryInfo)(nil), "kvrwset.RangeQueryInfo")
@@ -559,53 +525,49 @@ func init() {
 func init() { proto.RegisterFile("ledger/rwset/kvrwset/kv_rwset.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 754 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x55, 0xdd, 0x6a, 0xe3, 0x46,
-	0x14, 0x5e, 0x39, 0xb1, 0x25, 0x1f, 0xdb, 0xbb, 0xde, 0xc9, 0x96, 0xa8, 0x94, 0x82, 0xd1, 0x52,
-	0x30, 0x7b, 0x21, 0x83, 0x5b, 0x4a, 0x97, 0xd2, 0x9b, 0xb2, 0x2e, 0x29, 0xe9, 0x2e, 0x74, 0x02,
-	0x0e, 0xf4, 0x46, 0x8c, 0xa3, 0x13, 0x5b, 0xc8, 0x92, 0xdc, 0x99, 0x91, 0x6d, 0x5d, 0xb5, 0x7d,
-	0x94, 0xbe, 0x43, 0x9f, 0xa2, 0x4f, 0x55, 0xe6, 0x8c, 0x14, 0x7b, 0x83, 0x1b, 0xe8, 0x4d, 0xaf,
-	0xa4, 0x39, 0xdf, 0xf9, 0xce, 0xcf, 0x37, 0x67, 0x66, 0xe0, 0xf5, 0x1a, 0xe3, 0x25, 0xca, 0x89,
-	0xdc, 0x29, 0xd4, 0x93, 0x74, 0xdb, 0x7c, 0x23, 0xfa, 0x09, 0x37, 0xb2, 0xd0, 0x05, 0x73, 0x6b,
-	0x7b, 0xf0, 0xa7, 0x03, 0xee, 0xf5, 0x9c, 0xdf, 0xde, 0xa0, 0x66, 0x5f, 0x40, 0x5b, 0xa2, 0x88,
-	0x95, 0xef, 0x8c, 0xce, 0xc6, 0xbd, 0xe9, 0x8b, 0xb0, 0x76, 0x0a, 0xaf, 0xe7, 0x1c, 0x45, 0xcc,
-	0x2d, 0xca, 0x66, 0xc0, 0xa4, 0xc8, 0x97, 0x18, 0xfd, 0x5a, 0xa2, 0x4c, 0x50, 0x45, 0x49, 0x7e,
-	0x5f, 0xf8, 0x2d, 0xe2, 0x5c, 0x3e, 0x70, 0xb8, 0x71, 0xf9, 0xb9, 0x44, 0x59, 0xfd, 0x98, 0xdf,
-	0x17, 0x7c, 0x28, 0x9b, 0x75, 0x82, 0xca, 0x58, 0xd8, 0x18, 0x3a, 0x3b, 0x99, 0x68, 0x54, 0xfe,
-	0x19, 0x51, 0x87, 0x47, 0xe9, 0x6e, 0x0d, 0xc0, 0x6b, 0x3c, 0xf8, 0xdd, 0x81, 0xde, 0x95, 0x50,
-	0x2b, 0x8c, 0x6d, 0x9d, 0x5f, 0x43, 0x7f, 0x45, 0xcb, 0xe8, 0xb8, 0xdc, 0x8b, 0x47, 0xe5, 0x1a,
-	0x06, 0xef, 0x59, 0x47, 0x4e, 0x85, 0xbf, 0x85, 0x41, 0xcd, 0xab, 0x13, 0xdb, 0x9a, 0x5f, 0x3d,
-	0x4e, 0x4c, 0xcc, 0x3a, 0xc5, 0xad, 0x2d, 0xe1, 0x07, 0xe8, 0xd8, 0xa8, 0x6c, 0x08, 0x67, 0x29,
-	0x56, 0xbe, 0x33, 0x72, 0xc6, 0x5d, 0x6e, 0x7e, 0xd9, 0x1b, 0x70, 0xb7, 0x28, 0x55, 0x52, 0xe4,
-	0x7e, 0x6b, 0xe4, 0x7c, 0xd4, 0xc9, 0xdc, 0xda, 0x79, 0xe3, 0x10, 0xfc, 0x45, 0x72, 0x53, 0xd0,
-	0x13, 0x91, 0x3e, 0x83, 0x6e, 0xa2, 0xa2, 0x18, 0xd7, 0xa8, 0x91, 0x62, 0x79, 0xdc, 0x4b, 0xd4,
-	0x3b, 0x5a, 0xb3, 0x57, 0xd0, 0xde, 0x8a, 0x75, 0x89, 0xfe, 0xd9, 0xc8, 0x19, 0xf7, 0xb9, 0x5d,
-	0xb0, 0x10, 0x2e, 0x52, 0xc4, 0x4d, 0x84, 0xfb, 0x44, 0xe9, 0x24, 0x5f, 0x46, 0xd6, 0xe7, 0x9c,
-	0xc8, 0x2f, 0x0d, 0x34, 0xab, 0x91, 0x39, 0xf9, 0x7f, 0x05, 0x5e, 0x86, 0x5a, 0xc4, 0x42, 0x0b,
-	0xbf, 0x4d, 0xd5, 0xfa, 0x47, 0xed, 0xbf, 0xaf, 0x21, 0xab, 0xff, 0x83, 0x67, 0x70, 0x03, 0x70,
-	0x10, 0x95, 0x7d, 0x0a, 0x5e, 0x8a, 0x55, 0x64, 0x04, 0xa2, 0xea, 0xfb, 0xdc, 0x4d, 0xb1, 0x22,
-	0xe8, 0xbf, 0x68, 0xf1, 0xb7, 0x03, 0xbd, 0x23, 0xc5, 0x9f, 0x0a, 0xfb, 0xa4, 0x30, 0x9f, 0x03,
-	0x50, 0xd3, 0x96, 0x69, 0xd5, 0xe9, 0x92, 0x85, 0xb8, 0xff, 0x8f, 0x42, 0x11, 0xbc, 0x78, 0x04,
-	0xb2, 0x29, 0xb8, 0xe5, 0x46, 0xa1, 0xd4, 0xcd, 0x84, 0x9e, 0x8a, 0x33, 0xcb, 0xb5, 0xac, 0x78,
-	0xe3, 0xc8, 0x7c, 0x70, 0x6d, 0x97, 0x76, 0x38, 0xbb, 0xbc, 0x59, 0x06, 0xdf, 0x1e, 0x27, 0x20,
-	0x16, 0x63, 0x70, 0x9e, 0x8b, 0x0c, 0xeb, 0x09, 0xa2, 0xff, 0xc3, 0x94, 0xb4, 0x8e, 0xa6, 0x24,
-	0xf8, 0x0e, 0xdc, 0x5a, 0x7e, 0x23, 0xe5, 0x62, 0x5d, 0xdc, 0xa5, 0x51, 0x5e, 0x66, 0xc4, 0x3c,
-	0xe7, 0x1e, 0x19, 0x3e, 0x94, 0x19, 0xfb, 0x04, 0x3a, 0x7a, 0x4f, 0x48, 0x8b, 0x90, 0xb6, 0xde,
-	0x7f, 0x28, 0xb3, 0xe0, 0x8f, 0x16, 0x3c, 0xff, 0xf8, 0x3c, 0x9b, 0x30, 0x4a, 0x0b, 0xa9, 0xa3,
-	0xc3, 0x08, 0x7b, 0x64, 0xb8, 0xc6, 0x8a, 0x5d, 0x82, 0x8b, 0x79, 0x4c, 0x50, 0x8b, 0xa0, 0x0e,
-	0xe6, 0xb1, 0x01, 0x5e, 0xc3, 0x20, 0xd1, 0x32, 0xc2, 0xfd, 0x4a, 0x94, 0x4a, 0x63, 0x4c, 0xbb,
-	0xe5, 0xf1, 0x7e, 0xa2, 0xe5, 0xac, 0xb1, 0xb1, 0x29, 0x74, 0xa5, 0xd8, 0xd5, 0x67, 0xfb, 0x9c,
-	0x76, 0xe0, 0x70, 0xb6, 0xa9, 0x02, 0x3a, 0xce, 0x57, 0xcf, 0xb8, 0x27, 0xc5, 0xce, 0x1e, 0x6d,
-	0x0e, 0x17, 0xe4, 0x1f, 0x65, 0x28, 0xd3, 0xb5, 0x1d, 0x05, 0x54, 0xf5, 0xfe, 0x8d, 0x4e, 0xb0,
-	0xdf, 0x93, 0xdf, 0x4d, 0x99, 0x65, 0x42, 0x56, 0x57, 0xcf, 0xf8, 0x4b, 0x79, 0xb0, 0xd2, 0x5d,
-	0xa3, 0xbe, 0xef, 0x03, 0xd8, 0x98, 0xe6, 0x7e, 0x0b, 0xbe, 0x01, 0x38, 0xb0, 0xd9, 0x1b, 0xf0,
-	0xcc, 0x8d, 0xfa, 0xd4, 0x6d, 0xe9, 0xa6, 0x5b, 0xf2, 0x0d, 0x7e, 0x83, 0xcb, 0x7f, 0xc9, 0x6b,
-	0x46, 0x37, 0x13, 0xfb, 0x28, 0xc6, 0xa5, 0x44, 0xbb, 0x8f, 0x03, 0xde, 0xcd, 0xc4, 0xfe, 0x1d,
-	0x19, 0x8c, 0xc8, 0x06, 0x5e, 0xe3, 0x16, 0xd7, 0xa4, 0xe4, 0x80, 0x7b, 0x99, 0xd8, 0xff, 0x64,
-	0xd6, 0x6c, 0x0c, 0xc3, 0x07, 0xb0, 0xe9, 0xd7, 0xdc, 0xa4, 0x7d, 0xfe, 0xbc, 0xf1, 0xa9, 0x1b,
-	0x29, 0x60, 0x5a, 0xc8, 0x65, 0xb8, 0xaa, 0x36, 0x28, 0xed, 0xe3, 0x10, 0xde, 0x8b, 0x85, 0x4c,
-	0xee, 0xec, 0x63, 0xa0, 0xc2, 0xda, 0x68, 0xcb, 0xaf, 0xdb, 0xf8, 0xe5, 0xed, 0x32, 0xd1, 0xab,
-	0x72, 0x11, 0xde, 0x15, 0xd9, 0xe4, 0x88, 0x3a, 0xb1, 0xd4, 0x89, 0xa5, 0x4e, 0x4e, 0x3d, 0x36,
-	0x8b, 0x0e, 0x81, 0x5f, 0xfe, 0x13, 0x00, 0x00, 0xff, 0xff, 0x08, 0x42, 0x1a, 0xf5, 0x8b, 0x06,
-	0x00, 0x00,
+	// 704 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x54, 0xdf, 0x6b, 0x1a, 0x41,
+	0x10, 0xce, 0xf9, 0xf3, 0x1c, 0x35, 0xda, 0x4d, 0x4a, 0xae, 0x94, 0x82, 0x5c, 0x28, 0x48, 0x1e,
+	0x14, 0x2c, 0x94, 0x86, 0xd2, 0x87, 0x96, 0xa4, 0xa4, 0xa4, 0x09, 0x74, 0x03, 0x09, 0xf4, 0xe5,
+	0x58, 0x73, 0x13, 0x3d, 0xf4, 0xee, 0xd2, 0xbd, 0x3d, 0xf5, 0x9e, 0xda, 0xfe, 0xaf, 0xfd, 0x43,
+	0xca, 0xce, 0x9e, 0xd1, 0x88, 0x0d, 0xf4, 0xc9, 0x9d, 0xf9, 0xe6, 0x9b, 0x9b, 0xef, 0x5b, 0x77,
+	0xe0, 0x70, 0x8a, 0xfe, 0x08, 0x65, 0x5f, 0xce, 0x13, 0x54, 0xfd, 0xc9, 0x6c, 0xf9, 0xeb, 0xd1,
+	0xa1, 0x77, 0x2f, 0x63, 0x15, 0xb3, 0x6a, 0x9e, 0x77, 0xff, 0x58, 0x50, 0x3d, 0xbf, 0xe6, 0x37,
+	0x57, 0xa8, 0xd8, 0x6b, 0x28, 0x4b, 0x14, 0x7e, 0xe2, 0x58, 0x9d, 0x62, 0xb7, 0x3e, 0x68, 0xf5,
+	0xf2, 0xa2, 0xde, 0xf9, 0x35, 0x47, 0xe1, 0x73, 0x83, 0xb2, 0x53, 0x60, 0x52, 0x44, 0x23, 0xf4,
+	0x7e, 0xa4, 0x28, 0x03, 0x4c, 0xbc, 0x20, 0xba, 0x8b, 0x9d, 0x02, 0x71, 0x0e, 0x1e, 0x38, 0x5c,
+	0x97, 0x7c, 0x4b, 0x51, 0x66, 0x5f, 0xa2, 0xbb, 0x98, 0xb7, 0xe5, 0x32, 0x0e, 0x30, 0xd1, 0x19,
+	0xd6, 0x85, 0xca, 0x5c, 0x06, 0x0a, 0x13, 0xa7, 0x48, 0xd4, 0xf6, 0xda, 0xe7, 0x6e, 0x34, 0xc0,
+	0x73, 0x9c, 0x7d, 0x84, 0x56, 0x88, 0x4a, 0xf8, 0x42, 0x09, 0x2f, 0xa7, 0x94, 0x88, 0xe2, 0xac,
+	0x51, 0x2e, 0xf2, 0x0a, 0x43, 0xdd, 0x0d, 0xd7, 0xc3, 0xc4, 0xfd, 0x65, 0x41, 0xfd, 0x4c, 0x24,
+	0x63, 0xf4, 0x8d, 0xd4, 0xb7, 0xd0, 0x18, 0x53, 0xe8, 0xad, 0x2b, 0xde, 0xdb, 0x50, 0xac, 0x19,
+	0xbc, 0x6e, 0x0a, 0x39, 0x69, 0x3f, 0x86, 0x66, 0xce, 0xcb, 0x07, 0x31, 0xb2, 0xf7, 0x37, 0x67,
+	0x27, 0x66, 0xfe, 0x89, 0x7c, 0x84, 0xcf, 0x50, 0x31, 0x5d, 0x59, 0x1b, 0x8a, 0x13, 0xcc, 0x1c,
+	0xab, 0x63, 0x75, 0x6b, 0x5c, 0x1f, 0xd9, 0x11, 0x54, 0x67, 0x28, 0x93, 0x20, 0x8e, 0x9c, 0x42,
+	0xc7, 0x7a, 0x64, 0xc6, 0xb5, 0xc9, 0xf3, 0x65, 0x81, 0x7b, 0xa9, 0x2f, 0x8c, 0x7a, 0x6e, 0x69,
+	0xf4, 0x12, 0x6a, 0x41, 0xe2, 0xf9, 0x38, 0x45, 0x85, 0xd4, 0xca, 0xe6, 0x76, 0x90, 0x9c, 0x50,
+	0xcc, 0xf6, 0xa1, 0x3c, 0x13, 0xd3, 0x14, 0x9d, 0x62, 0xc7, 0xea, 0x36, 0xb8, 0x09, 0xdc, 0x1b,
+	0x68, 0x6d, 0xb8, 0xb7, 0xa5, 0xef, 0x00, 0xaa, 0x18, 0x29, 0x19, 0x3c, 0x28, 0xde, 0x66, 0xfd,
+	0x69, 0xa4, 0x64, 0xc6, 0x97, 0x85, 0xee, 0x15, 0xc0, 0xca, 0x46, 0xf6, 0x02, 0xec, 0x09, 0x66,
+	0x9e, 0xb6, 0x84, 0x1a, 0x37, 0x78, 0x75, 0x82, 0x19, 0x41, 0xff, 0xa3, 0xde, 0x87, 0xfa, 0x9a,
+	0xc5, 0x4f, 0x75, 0x7d, 0xd2, 0x8a, 0x57, 0x00, 0xa4, 0xde, 0x30, 0x8d, 0x1f, 0x35, 0xca, 0x68,
+	0xae, 0xfb, 0x1e, 0x5a, 0x1b, 0xb2, 0x18, 0x83, 0x52, 0x24, 0x42, 0xcc, 0x4d, 0xa1, 0xf3, 0xca,
+	0xd0, 0xc2, 0xba, 0xa1, 0x1f, 0xa0, 0x9a, 0x8f, 0xad, 0x67, 0x18, 0x4e, 0xe3, 0xdb, 0x89, 0x17,
+	0xa5, 0x21, 0x31, 0x4b, 0xdc, 0xa6, 0xc4, 0x65, 0x1a, 0xb2, 0xe7, 0x50, 0x51, 0x0b, 0x42, 0x0a,
+	0x84, 0x94, 0xd5, 0xe2, 0x32, 0x0d, 0xdd, 0xdf, 0x05, 0xd8, 0x7d, 0xfc, 0x78, 0x74, 0x9b, 0x44,
+	0x09, 0xa9, 0xbc, 0xd5, 0xad, 0xd8, 0x94, 0x38, 0xc7, 0x8c, 0x1d, 0xe8, 0xab, 0xf1, 0x09, 0x2a,
+	0x10, 0x54, 0xc1, 0xc8, 0xd7, 0xc0, 0x21, 0x34, 0x03, 0x25, 0x3d, 0x5c, 0x8c, 0x45, 0x9a, 0x28,
+	0xf4, 0x49, 0xa6, 0xcd, 0x1b, 0x81, 0x92, 0xa7, 0xcb, 0x1c, 0x1b, 0x40, 0x4d, 0x8a, 0x79, 0xfe,
+	0x0a, 0x4a, 0x1d, 0xeb, 0xd1, 0x2b, 0xa0, 0x09, 0xe8, 0x8f, 0x7f, 0xb6, 0xc3, 0x6d, 0x29, 0xe6,
+	0x74, 0x66, 0x1c, 0xf6, 0xa8, 0xde, 0x0b, 0x51, 0x4e, 0xa6, 0xc6, 0x43, 0x4c, 0x9c, 0x32, 0xb1,
+	0x3b, 0x5b, 0xd8, 0x17, 0x54, 0x77, 0x95, 0x86, 0xa1, 0x90, 0xd9, 0xd9, 0x0e, 0x7f, 0x26, 0x57,
+	0x59, 0x7a, 0x95, 0xc9, 0xa7, 0x06, 0x80, 0xe9, 0xa9, 0x97, 0x89, 0xfb, 0x0e, 0x60, 0xc5, 0x66,
+	0x47, 0x60, 0xeb, 0xf5, 0xf5, 0xd4, 0x6a, 0xaa, 0x4e, 0x66, 0x54, 0xeb, 0xfe, 0x84, 0x83, 0x7f,
+	0x7c, 0x57, 0xdf, 0x79, 0x28, 0x16, 0x9e, 0x8f, 0x23, 0x89, 0xe6, 0x1e, 0x9b, 0xbc, 0x16, 0x8a,
+	0xc5, 0x09, 0x25, 0xb4, 0xc9, 0x1a, 0x9e, 0xe2, 0x0c, 0xa7, 0xe4, 0x64, 0x93, 0xdb, 0xa1, 0x58,
+	0x7c, 0xd5, 0x31, 0xeb, 0x42, 0xfb, 0x01, 0x5c, 0xea, 0xd5, 0x6b, 0xab, 0xc1, 0x77, 0x97, 0x35,
+	0xb9, 0x90, 0x18, 0x06, 0xb1, 0x1c, 0xf5, 0xc6, 0xd9, 0x3d, 0x4a, 0xb3, 0x89, 0x7b, 0x77, 0x62,
+	0x28, 0x83, 0x5b, 0xb3, 0x79, 0x93, 0x5e, 0x9e, 0x34, 0xe3, 0xe7, 0x32, 0xbe, 0x1f, 0x8f, 0x02,
+	0x35, 0x4e, 0x87, 0xbd, 0xdb, 0x38, 0xec, 0xaf, 0x51, 0xfb, 0x86, 0xda, 0x37, 0xd4, 0xfe, 0xb6,
+	0xcd, 0x3e, 0xac, 0x10, 0xf8, 0xe6, 0xef, 0x00, 0xf9, 0x2e, 0x8e, 0xfb, 0xf8, 0x05, 0x00, 0x00,
 }
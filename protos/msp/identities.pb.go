@@ -24,6 +24,8 @@ It has these top-level messages:
 	MSPPrincipal
 	OrganizationUnit
 	MSPRole
+	IdemixAttributes
+	IdemixAttributePredicate
 */
 package msp
 
@@ -90,6 +92,9 @@ type SerializedIdemixIdentity struct {
 	Role []byte `protobuf:"bytes,4,opt,name=Role,proto3" json:"Role,omitempty"`
 	// Proof contains the cryptographic evidence that this identity is valid
 	Proof []byte `protobuf:"bytes,5,opt,name=Proof,proto3" json:"Proof,omitempty"`
+	// Attributes contains a marshaled common.IdemixAttributes with the
+	// predicates over further attributes this identity proves to satisfy
+	Attributes []byte `protobuf:"bytes,6,opt,name=Attributes,proto3" json:"Attributes,omitempty"`
 }
 
 func (m *SerializedIdemixIdentity) Reset()                    { *m = SerializedIdemixIdentity{} }
@@ -132,6 +137,13 @@ func (m *SerializedIdemixIdentity) GetProof() []byte {
 	return nil
 }
 
+func (m *SerializedIdemixIdentity) GetAttributes() []byte {
+	if m != nil {
+		return m.Attributes
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*SerializedIdentity)(nil), "msp.SerializedIdentity")
 	proto.RegisterType((*SerializedIdemixIdentity)(nil), "msp.SerializedIdemixIdentity")
@@ -140,20 +152,21 @@ func init() {
 func init() { proto.RegisterFile("msp/identities.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 236 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x54, 0x8f, 0x4f, 0x4b, 0x03, 0x31,
-	0x10, 0xc5, 0xd9, 0x6d, 0xeb, 0x9f, 0x50, 0x3c, 0x84, 0x1e, 0xe2, 0xad, 0xf6, 0xb4, 0xa7, 0xe4,
-	0xe0, 0x37, 0x28, 0x78, 0xf0, 0xa0, 0x95, 0x95, 0x82, 0x7a, 0x91, 0x6e, 0x33, 0xdd, 0x0e, 0xec,
-	0x98, 0x90, 0x89, 0xe0, 0x8a, 0x1f, 0x5e, 0x36, 0x59, 0xc4, 0xde, 0xde, 0x7b, 0xfc, 0xf8, 0x31,
-	0x23, 0x16, 0xc4, 0xde, 0xa0, 0x85, 0x8f, 0x88, 0x11, 0x81, 0xb5, 0x0f, 0x2e, 0x3a, 0x39, 0x21,
-	0xf6, 0xab, 0x3b, 0x21, 0x9f, 0x21, 0xe0, 0xae, 0xc3, 0x6f, 0xb0, 0xf7, 0x19, 0xe9, 0xe5, 0x42,
-	0xcc, 0x88, 0x3d, 0x5a, 0x55, 0x2c, 0x8b, 0xea, 0xb2, 0xce, 0x45, 0x5e, 0x8b, 0x0b, 0xb4, 0xef,
-	0x4d, 0x1f, 0x81, 0x55, 0xb9, 0x2c, 0xaa, 0x79, 0x7d, 0x8e, 0x76, 0x3d, 0xd4, 0xd5, 0x8f, 0x50,
-	0x27, 0x1a, 0xc2, 0xaf, 0x3f, 0x99, 0x14, 0xd3, 0xc7, 0x9e, 0x5e, 0x92, 0x6b, 0x5e, 0xa7, 0x3c,
-	0x6e, 0xaf, 0xa3, 0x26, 0x65, 0x79, 0x25, 0xca, 0xcd, 0x56, 0x4d, 0xd2, 0x52, 0x6e, 0xb6, 0x03,
-	0x53, 0xbb, 0x0e, 0xd4, 0x34, 0x33, 0x43, 0x1e, 0x0e, 0x7b, 0x0a, 0xce, 0x1d, 0xd4, 0x2c, 0x8d,
-	0xb9, 0xac, 0x1f, 0xc4, 0x8d, 0x0b, 0xad, 0x3e, 0xf6, 0x1e, 0x42, 0x07, 0xb6, 0x85, 0xa0, 0x0f,
-	0xbb, 0x26, 0xe0, 0x3e, 0x7f, 0xca, 0x9a, 0xd8, 0xbf, 0x55, 0x2d, 0xc6, 0xe3, 0x67, 0xa3, 0xf7,
-	0x8e, 0xcc, 0x3f, 0xd2, 0x64, 0xd2, 0x64, 0xd2, 0x10, 0xfb, 0xe6, 0x2c, 0xe5, 0xdb, 0xdf, 0x00,
-	0x00, 0x00, 0xff, 0xff, 0x97, 0xeb, 0x66, 0x12, 0x37, 0x01, 0x00, 0x00,
+	// 249 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x54, 0x90, 0xcd, 0x4a, 0x03, 0x31,
+	0x14, 0x85, 0x99, 0xfe, 0xa9, 0x97, 0xe2, 0x22, 0x74, 0x11, 0x37, 0x52, 0xbb, 0x9a, 0x55, 0xb2,
+	0xf0, 0x09, 0x2c, 0xb8, 0x70, 0xa1, 0x95, 0x91, 0x82, 0xba, 0x91, 0xa6, 0xb9, 0x9d, 0x5e, 0x98,
+	0x98, 0x90, 0xa4, 0xe0, 0xf8, 0x2c, 0x3e, 0xac, 0x24, 0x29, 0x52, 0x77, 0xe7, 0x9c, 0x7c, 0x7c,
+	0x5c, 0x02, 0x33, 0x13, 0x9c, 0x24, 0x8d, 0x9f, 0x91, 0x22, 0x61, 0x10, 0xce, 0xdb, 0x68, 0xd9,
+	0xd0, 0x04, 0xb7, 0xb8, 0x07, 0xf6, 0x82, 0x9e, 0x36, 0x1d, 0x7d, 0xa3, 0x7e, 0x28, 0x48, 0xcf,
+	0x66, 0x30, 0x36, 0xc1, 0x91, 0xe6, 0xd5, 0xbc, 0xaa, 0x2f, 0x9a, 0x52, 0xd8, 0x15, 0x9c, 0x93,
+	0xfe, 0x50, 0x7d, 0xc4, 0xc0, 0x07, 0xf3, 0xaa, 0x9e, 0x36, 0x67, 0xa4, 0x97, 0xa9, 0x2e, 0x7e,
+	0x2a, 0xe0, 0xff, 0x3c, 0x86, 0xbe, 0xfe, 0x6c, 0x0c, 0x46, 0x4f, 0xbd, 0x79, 0xcd, 0xb2, 0x69,
+	0x93, 0xf3, 0x71, 0x7b, 0x3b, 0x7a, 0x72, 0x66, 0x97, 0x30, 0x58, 0xad, 0xf9, 0x30, 0x2f, 0x83,
+	0xd5, 0x3a, 0x31, 0x8d, 0xed, 0x90, 0x8f, 0x0a, 0x93, 0x72, 0xba, 0xec, 0xd9, 0x5b, 0xbb, 0xe3,
+	0xe3, 0x3c, 0x96, 0xc2, 0xae, 0x01, 0xee, 0x62, 0xf4, 0xa4, 0x0e, 0xe9, 0xb6, 0x49, 0x7e, 0x3a,
+	0x59, 0x96, 0x8f, 0x70, 0x63, 0x7d, 0x2b, 0xf6, 0xbd, 0x43, 0xdf, 0xa1, 0x6e, 0xd1, 0x8b, 0xdd,
+	0x46, 0x79, 0xda, 0x96, 0xaf, 0x08, 0xc2, 0x04, 0xf7, 0x5e, 0xb7, 0x14, 0xf7, 0x07, 0x25, 0xb6,
+	0xd6, 0xc8, 0x13, 0x52, 0x16, 0x52, 0x16, 0x52, 0x9a, 0xe0, 0xd4, 0x24, 0xe7, 0xdb, 0xdf, 0x01,
+	0x00, 0x28, 0x5d, 0x68, 0xe3, 0x58, 0x01, 0x00, 0x00,
 }
@@ -220,6 +220,11 @@ type IdemixMSPConfig struct {
 	IPk []byte `protobuf:"bytes,2,opt,name=IPk,proto3" json:"IPk,omitempty"`
 	// signer may contain crypto material to configure a default signer
 	Signer *IdemixMSPSignerConfig `protobuf:"bytes,3,opt,name=signer" json:"signer,omitempty"`
+	// revocation_pk is the (PEM encoded) long term public key of the revocation authority
+	RevocationPk []byte `protobuf:"bytes,4,opt,name=revocation_pk,json=revocationPk,proto3" json:"revocation_pk,omitempty"`
+	// credential_revocation_information is the (serialized) credential
+	// revocation information of the current epoch
+	CredentialRevocationInformation []byte `protobuf:"bytes,5,opt,name=credential_revocation_information,json=credentialRevocationInformation,proto3" json:"credential_revocation_information,omitempty"`
 }
 
 func (m *IdemixMSPConfig) Reset()                    { *m = IdemixMSPConfig{} }
@@ -248,6 +253,20 @@ func (m *IdemixMSPConfig) GetSigner() *IdemixMSPSignerConfig {
 	return nil
 }
 
+func (m *IdemixMSPConfig) GetRevocationPk() []byte {
+	if m != nil {
+		return m.RevocationPk
+	}
+	return nil
+}
+
+func (m *IdemixMSPConfig) GetCredentialRevocationInformation() []byte {
+	if m != nil {
+		return m.CredentialRevocationInformation
+	}
+	return nil
+}
+
 // IdemixMSPSIgnerConfig contains the crypto material to set up an idemix signing identity
 type IdemixMSPSignerConfig struct {
 	// Cred represents the serialized idemix credential of the default signer
@@ -260,6 +279,9 @@ type IdemixMSPSignerConfig struct {
 	IsAdmin bool `protobuf:"varint,4,opt,name=is_admin,json=isAdmin" json:"is_admin,omitempty"`
 	// enrollment_id contains the enrollment id of this signer
 	EnrollmentId string `protobuf:"bytes,5,opt,name=enrollment_id,json=enrollmentId" json:"enrollment_id,omitempty"`
+	// attributes contains a marshaled common.IdemixAttributes with the
+	// predicates over further attributes the default signer proves to satisfy
+	Attributes []byte `protobuf:"bytes,6,opt,name=attributes,proto3" json:"attributes,omitempty"`
 }
 
 func (m *IdemixMSPSignerConfig) Reset()                    { *m = IdemixMSPSignerConfig{} }
@@ -302,6 +324,13 @@ func (m *IdemixMSPSignerConfig) GetEnrollmentId() string {
 	return ""
 }
 
+func (m *IdemixMSPSignerConfig) GetAttributes() []byte {
+	if m != nil {
+		return m.Attributes
+	}
+	return nil
+}
+
 // SigningIdentityInfo represents the configuration information
 // related to the signing identity the peer is to use for generating
 // endorsements
@@ -455,54 +484,58 @@ func init() {
 func init() { proto.RegisterFile("msp/msp_config.proto", fileDescriptor1) }
 
 var fileDescriptor1 = []byte{
-	// 781 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x55, 0xdd, 0x6e, 0xeb, 0x44,
-	0x10, 0x96, 0x93, 0x34, 0x4d, 0x26, 0x4e, 0x52, 0xf6, 0x9c, 0x53, 0x0c, 0xe2, 0x94, 0xd4, 0x80,
-	0xc8, 0x0d, 0x89, 0x94, 0x22, 0xc1, 0x05, 0x37, 0x34, 0x50, 0xb0, 0xa0, 0xb4, 0x72, 0xd4, 0x1b,
-	0x6e, 0xac, 0x8d, 0xbd, 0x71, 0x56, 0xb1, 0xd7, 0xd6, 0xee, 0xa6, 0x22, 0x88, 0xb7, 0xe0, 0x3d,
-	0xb8, 0xe3, 0x01, 0x78, 0x33, 0xb4, 0x3f, 0x49, 0x1c, 0x1a, 0x02, 0x77, 0xbb, 0x33, 0xdf, 0x37,
-	0x33, 0xfe, 0x66, 0x66, 0x0d, 0xaf, 0x73, 0x51, 0x8e, 0x73, 0x51, 0x46, 0x71, 0xc1, 0x16, 0x34,
-	0x1d, 0x95, 0xbc, 0x90, 0x05, 0xaa, 0xe7, 0xa2, 0xf4, 0xbf, 0x80, 0xf6, 0xfd, 0xec, 0x71, 0xaa,
-	0xed, 0x08, 0x41, 0x43, 0x6e, 0x4a, 0xe2, 0x39, 0x03, 0x67, 0x78, 0x16, 0xea, 0x33, 0xba, 0x84,
-	0xa6, 0x61, 0x79, 0xb5, 0x81, 0x33, 0x74, 0x43, 0x7b, 0xf3, 0xff, 0x68, 0x40, 0xff, 0x0e, 0xcf,
-	0x39, 0x8d, 0x0f, 0xf8, 0x0c, 0xe7, 0x86, 0xdf, 0x0e, 0xf5, 0x19, 0xbd, 0x05, 0xe0, 0x45, 0x21,
-	0xa3, 0x98, 0x70, 0x29, 0xbc, 0xda, 0xa0, 0x3e, 0x74, 0xc3, 0xb6, 0xb2, 0x4c, 0x95, 0x01, 0x7d,
-	0x06, 0x88, 0x32, 0x49, 0x78, 0x4e, 0x12, 0x8a, 0x25, 0xb1, 0xb0, 0xba, 0x86, 0xbd, 0x53, 0xf5,
-	0x18, 0xf8, 0x25, 0x34, 0x71, 0x92, 0x53, 0x26, 0xbc, 0x86, 0x86, 0xd8, 0x1b, 0xfa, 0x14, 0xfa,
-	0x9c, 0x3c, 0x17, 0x31, 0x96, 0xb4, 0x60, 0x51, 0x46, 0x85, 0xf4, 0xce, 0x34, 0xa0, 0xb7, 0x37,
-	0xff, 0x48, 0x85, 0x44, 0x53, 0xb8, 0x10, 0x34, 0x65, 0x94, 0xa5, 0x11, 0x4d, 0x08, 0x93, 0x54,
-	0x6e, 0xbc, 0xe6, 0xc0, 0x19, 0x76, 0x26, 0xde, 0x28, 0x17, 0xe5, 0x68, 0x66, 0x9c, 0x81, 0xf5,
-	0x05, 0x6c, 0x51, 0x84, 0x7d, 0x71, 0x68, 0x44, 0x11, 0x7c, 0x58, 0xf0, 0x14, 0x33, 0xfa, 0xab,
-	0x0e, 0x8c, 0xb3, 0x68, 0xcd, 0xa8, 0xb4, 0x01, 0x17, 0x94, 0x70, 0xe1, 0x9d, 0x0f, 0xea, 0xc3,
-	0xce, 0xe4, 0x5d, 0x1d, 0xd3, 0xc8, 0xf4, 0xf0, 0x14, 0xec, 0xfc, 0xe1, 0xdb, 0x43, 0xfe, 0x13,
-	0xa3, 0x72, 0xef, 0x15, 0xe8, 0x2b, 0xe8, 0xc6, 0x7c, 0x53, 0xca, 0xc2, 0x76, 0xcc, 0x6b, 0xe9,
-	0x12, 0xab, 0xe1, 0xa6, 0xda, 0x6f, 0x84, 0x0f, 0xdd, 0xb8, 0x72, 0x43, 0x1f, 0x43, 0x4f, 0x66,
-	0x22, 0xaa, 0xc8, 0xde, 0xd6, 0x5a, 0xb8, 0x32, 0x13, 0xe1, 0x4e, 0xf9, 0xcf, 0xe1, 0x52, 0xa1,
-	0x8e, 0xa8, 0x0f, 0x1a, 0xfd, 0x5a, 0x66, 0x22, 0x78, 0xd1, 0x80, 0x2f, 0xa1, 0x6b, 0xf2, 0xff,
-	0x54, 0x24, 0xe4, 0xe1, 0x49, 0x78, 0x1d, 0x5d, 0x19, 0xaa, 0x54, 0x66, 0x3d, 0xe1, 0x21, 0xd0,
-	0xff, 0xdd, 0x01, 0xf4, 0xb2, 0x74, 0x34, 0x81, 0x37, 0x4a, 0x5e, 0x2c, 0xd7, 0x9c, 0x44, 0x4b,
-	0x2c, 0x96, 0xd1, 0x02, 0xe7, 0x34, 0xdb, 0xd8, 0x21, 0x7a, 0xb5, 0x73, 0x7e, 0x8f, 0xc5, 0xf2,
-	0x4e, 0xbb, 0x50, 0x00, 0xd7, 0xdb, 0xe6, 0x55, 0x44, 0xb7, 0xec, 0x35, 0x8b, 0x95, 0xa8, 0x7a,
-	0x5c, 0xdb, 0xe1, 0xd5, 0x16, 0xb8, 0x97, 0x57, 0x07, 0xb2, 0x28, 0x7f, 0x05, 0xfd, 0x20, 0x21,
-	0x39, 0xfd, 0xe5, 0xf4, 0x14, 0x5f, 0x40, 0x3d, 0x78, 0x5c, 0xd9, 0x15, 0x50, 0x47, 0x34, 0x81,
-	0xa6, 0x2a, 0x8d, 0x70, 0xaf, 0xae, 0x15, 0x78, 0x5f, 0x2b, 0xb0, 0x8b, 0x35, 0xd3, 0x3e, 0xdb,
-	0x1e, 0x8b, 0xf4, 0xff, 0x72, 0xe0, 0xcd, 0x51, 0x84, 0xca, 0x39, 0xe5, 0x24, 0xd1, 0x39, 0xdd,
-	0x50, 0x9f, 0x51, 0x0f, 0x6a, 0xb3, 0x6d, 0xca, 0xda, 0x6c, 0x85, 0xbe, 0x81, 0xab, 0xd3, 0x53,
-	0xa7, 0x2b, 0x69, 0x87, 0x1f, 0x9c, 0x9a, 0x2d, 0xf4, 0x1e, 0xb4, 0xa8, 0x88, 0xf4, 0xda, 0x78,
-	0x8d, 0x81, 0x33, 0x6c, 0x85, 0xe7, 0x54, 0x7c, 0xad, 0xae, 0xe8, 0x23, 0xe8, 0x12, 0xc6, 0x8b,
-	0x2c, 0xcb, 0x09, 0x53, 0x71, 0xbd, 0x33, 0x1d, 0xcf, 0xdd, 0x1b, 0x83, 0xc4, 0x2f, 0xe0, 0xd5,
-	0x91, 0x1d, 0x51, 0xdc, 0x72, 0x3d, 0xcf, 0x68, 0x1c, 0x59, 0x55, 0xcc, 0x97, 0xb8, 0xc6, 0x68,
-	0xbe, 0x15, 0xdd, 0x40, 0xaf, 0xe4, 0xf4, 0x59, 0x4d, 0x9a, 0x45, 0xd5, 0xb4, 0x76, 0xae, 0xd6,
-	0xee, 0x07, 0x62, 0xd6, 0xad, 0x6b, 0x31, 0x86, 0xe4, 0xcf, 0xe0, 0xdc, 0x7a, 0xd0, 0x27, 0xd0,
-	0x5b, 0x91, 0x6a, 0xcb, 0x6d, 0x8f, 0xba, 0x2b, 0x52, 0xe9, 0x2f, 0xba, 0x06, 0x57, 0xc1, 0x72,
-	0x2c, 0x09, 0xa7, 0x38, 0xb3, 0x12, 0x76, 0x56, 0x64, 0x73, 0x6f, 0x4d, 0xfe, 0x6f, 0xdb, 0x59,
-	0xac, 0x6e, 0x25, 0x1a, 0x40, 0x47, 0x6d, 0x00, 0x5d, 0xd0, 0x18, 0x4b, 0x62, 0x3f, 0xa1, 0x6a,
-	0xfa, 0x1f, 0x3d, 0xa8, 0xfd, 0x77, 0x0f, 0xfc, 0x3f, 0x9d, 0x7f, 0x6c, 0x91, 0x7a, 0xd7, 0xbe,
-	0x65, 0x78, 0x9e, 0x99, 0xa4, 0xad, 0xd0, 0xde, 0xd0, 0x77, 0x80, 0xe2, 0x8c, 0x12, 0x26, 0xab,
-	0x75, 0x5a, 0xd5, 0xfe, 0xf5, 0x71, 0x39, 0x42, 0x51, 0xef, 0x5e, 0x49, 0x08, 0x3f, 0x08, 0x53,
-	0x3f, 0x1d, 0xe6, 0x05, 0xe1, 0x36, 0x82, 0xeb, 0x82, 0xa7, 0xa3, 0xe5, 0xa6, 0x24, 0x3c, 0x23,
-	0x49, 0x4a, 0xf8, 0x68, 0xa1, 0x79, 0xe6, 0x8f, 0x22, 0x54, 0xa4, 0xdb, 0x8b, 0x7b, 0x51, 0x9a,
-	0xa9, 0x7e, 0xc4, 0xf1, 0x0a, 0xa7, 0xe4, 0xe7, 0x61, 0x4a, 0xe5, 0x72, 0x3d, 0x1f, 0xc5, 0x45,
-	0x3e, 0xae, 0x70, 0xc7, 0x86, 0x3b, 0x36, 0x5c, 0xf5, 0x7f, 0x9a, 0x37, 0xf5, 0xf9, 0xe6, 0xef,
-	0x00, 0x00, 0x00, 0xff, 0xff, 0x38, 0x91, 0xde, 0x9b, 0xb1, 0x06, 0x00, 0x00,
+	// 838 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x55, 0x51, 0x6f, 0x23, 0x35,
+	0x10, 0xd6, 0x26, 0x6d, 0xda, 0x4c, 0x36, 0x69, 0xf1, 0xdd, 0x95, 0x05, 0x71, 0xbd, 0x74, 0x01,
+	0x91, 0x17, 0x52, 0xa9, 0x87, 0x04, 0x0f, 0xbc, 0x70, 0x81, 0x83, 0x05, 0xca, 0x55, 0x8e, 0xfa,
+	0xc2, 0xcb, 0xca, 0xd9, 0x38, 0x89, 0x95, 0x5d, 0xef, 0xca, 0x76, 0x4e, 0x04, 0xf1, 0x2f, 0xf8,
+	0x1f, 0xbc, 0xf1, 0x6f, 0x78, 0xe1, 0x9f, 0x20, 0x8f, 0xdd, 0x64, 0xd3, 0x54, 0xe5, 0xde, 0xec,
+	0x99, 0xef, 0x9b, 0x9d, 0xf9, 0x66, 0xc6, 0x0b, 0x4f, 0x0b, 0x5d, 0x5d, 0x16, 0xba, 0x4a, 0xb3,
+	0x52, 0xce, 0xc4, 0x7c, 0x58, 0xa9, 0xd2, 0x94, 0xa4, 0x59, 0xe8, 0x2a, 0xfe, 0x12, 0xda, 0xd7,
+	0xe3, 0x9b, 0x11, 0xda, 0x09, 0x81, 0x03, 0xb3, 0xae, 0x78, 0x14, 0xf4, 0x83, 0xc1, 0x21, 0xc5,
+	0x33, 0x39, 0x83, 0x96, 0x63, 0x45, 0x8d, 0x7e, 0x30, 0x08, 0xa9, 0xbf, 0xc5, 0x7f, 0x1d, 0xc0,
+	0xc9, 0x6b, 0x36, 0x51, 0x22, 0xdb, 0xe1, 0x4b, 0x56, 0x38, 0x7e, 0x9b, 0xe2, 0x99, 0x3c, 0x07,
+	0x50, 0x65, 0x69, 0xd2, 0x8c, 0x2b, 0xa3, 0xa3, 0x46, 0xbf, 0x39, 0x08, 0x69, 0xdb, 0x5a, 0x46,
+	0xd6, 0x40, 0x3e, 0x07, 0x22, 0xa4, 0xe1, 0xaa, 0xe0, 0x53, 0xc1, 0x0c, 0xf7, 0xb0, 0x26, 0xc2,
+	0xde, 0xab, 0x7b, 0x1c, 0xfc, 0x0c, 0x5a, 0x6c, 0x5a, 0x08, 0xa9, 0xa3, 0x03, 0x84, 0xf8, 0x1b,
+	0xf9, 0x0c, 0x4e, 0x14, 0x7f, 0x5b, 0x66, 0xcc, 0x88, 0x52, 0xa6, 0xb9, 0xd0, 0x26, 0x3a, 0x44,
+	0x40, 0x6f, 0x6b, 0xfe, 0x59, 0x68, 0x43, 0x46, 0x70, 0xaa, 0xc5, 0x5c, 0x0a, 0x39, 0x4f, 0xc5,
+	0x94, 0x4b, 0x23, 0xcc, 0x3a, 0x6a, 0xf5, 0x83, 0x41, 0xe7, 0x2a, 0x1a, 0x16, 0xba, 0x1a, 0x8e,
+	0x9d, 0x33, 0xf1, 0xbe, 0x44, 0xce, 0x4a, 0x7a, 0xa2, 0x77, 0x8d, 0x24, 0x85, 0x17, 0xa5, 0x9a,
+	0x33, 0x29, 0x7e, 0xc7, 0xc0, 0x2c, 0x4f, 0x57, 0x52, 0x18, 0x1f, 0x70, 0x26, 0xb8, 0xd2, 0xd1,
+	0x51, 0xbf, 0x39, 0xe8, 0x5c, 0xbd, 0x8f, 0x31, 0x9d, 0x4c, 0x6f, 0x6e, 0x93, 0x8d, 0x9f, 0x3e,
+	0xdf, 0xe5, 0xdf, 0x4a, 0x61, 0xb6, 0x5e, 0x4d, 0xbe, 0x86, 0x6e, 0xa6, 0xd6, 0x95, 0x29, 0x7d,
+	0xc7, 0xa2, 0xe3, 0x7e, 0x70, 0x2f, 0xdc, 0x08, 0xfd, 0x4e, 0x78, 0x1a, 0x66, 0xb5, 0x1b, 0xf9,
+	0x04, 0x7a, 0x26, 0xd7, 0x69, 0x4d, 0xf6, 0x36, 0x6a, 0x11, 0x9a, 0x5c, 0xd3, 0x8d, 0xf2, 0x5f,
+	0xc0, 0x99, 0x45, 0x3d, 0xa0, 0x3e, 0x20, 0xfa, 0xa9, 0xc9, 0x75, 0xb2, 0xd7, 0x80, 0xaf, 0xa0,
+	0xeb, 0xbe, 0xff, 0x4b, 0x39, 0xe5, 0x6f, 0x6e, 0x75, 0xd4, 0xc1, 0xcc, 0x48, 0x2d, 0x33, 0xef,
+	0xa1, 0xbb, 0xc0, 0xf8, 0xcf, 0x00, 0xc8, 0x7e, 0xea, 0xe4, 0x0a, 0x9e, 0x59, 0x79, 0x99, 0x59,
+	0x29, 0x9e, 0x2e, 0x98, 0x5e, 0xa4, 0x33, 0x56, 0x88, 0x7c, 0xed, 0x87, 0xe8, 0xc9, 0xc6, 0xf9,
+	0x03, 0xd3, 0x8b, 0xd7, 0xe8, 0x22, 0x09, 0x5c, 0xdc, 0x35, 0xaf, 0x26, 0xba, 0x67, 0xaf, 0x64,
+	0x66, 0x45, 0xc5, 0x71, 0x6d, 0xd3, 0xf3, 0x3b, 0xe0, 0x56, 0x5e, 0x0c, 0xe4, 0x51, 0xf1, 0x3f,
+	0x01, 0x9c, 0x24, 0x53, 0x5e, 0x88, 0xdf, 0x1e, 0x1f, 0xe3, 0x53, 0x68, 0x26, 0x37, 0x4b, 0xbf,
+	0x03, 0xf6, 0x48, 0xae, 0xa0, 0x65, 0x73, 0xe3, 0x2a, 0x6a, 0xa2, 0x04, 0x1f, 0xa2, 0x04, 0x9b,
+	0x58, 0x63, 0xf4, 0xf9, 0xfe, 0x78, 0x24, 0xf9, 0x18, 0xba, 0xb5, 0x31, 0xad, 0x96, 0xd1, 0x01,
+	0xc6, 0x0b, 0xb7, 0xc6, 0x9b, 0x25, 0xf9, 0x11, 0x2e, 0x32, 0xc5, 0x31, 0x5d, 0x96, 0xa7, 0x35,
+	0xbc, 0x90, 0xb3, 0x52, 0x15, 0x78, 0x8e, 0x0e, 0x91, 0xf8, 0x62, 0x0b, 0xa4, 0x1b, 0x5c, 0xb2,
+	0x85, 0xc5, 0xff, 0x06, 0xf0, 0xec, 0xc1, 0x94, 0x6c, 0x91, 0x23, 0xc5, 0xa7, 0x58, 0x64, 0x48,
+	0xf1, 0x4c, 0x7a, 0xd0, 0x18, 0xdf, 0xd5, 0xd8, 0x18, 0x2f, 0xc9, 0xb7, 0x70, 0xfe, 0xf8, 0x9c,
+	0x63, 0xe9, 0x6d, 0xfa, 0xd1, 0x63, 0xd3, 0x4c, 0x3e, 0x80, 0x63, 0xa1, 0x53, 0x5c, 0x54, 0xac,
+	0xf7, 0x98, 0x1e, 0x09, 0xfd, 0x8d, 0xbd, 0x5a, 0x3d, 0xb8, 0x54, 0x65, 0x9e, 0x17, 0x5c, 0xda,
+	0xb8, 0x58, 0x56, 0x9b, 0x86, 0x5b, 0x63, 0x32, 0x25, 0xe7, 0x00, 0xcc, 0x18, 0x25, 0x26, 0x2b,
+	0xc3, 0x35, 0x2e, 0x6b, 0x48, 0x6b, 0x96, 0xb8, 0x84, 0x27, 0x0f, 0x6c, 0xad, 0x8d, 0x5d, 0xad,
+	0x26, 0xb9, 0xc8, 0x52, 0xdf, 0x26, 0x57, 0x69, 0xe8, 0x8c, 0x4e, 0x0b, 0xf2, 0x12, 0x7a, 0x95,
+	0x12, 0x6f, 0xed, 0xec, 0x7b, 0x54, 0x03, 0x9b, 0x19, 0x62, 0x33, 0x7f, 0xe2, 0xee, 0x01, 0xe8,
+	0x7a, 0x8c, 0x23, 0xc5, 0x63, 0x38, 0xf2, 0x1e, 0xf2, 0x29, 0xf4, 0x96, 0xbc, 0x3e, 0x84, 0x7e,
+	0x68, 0xba, 0x4b, 0x5e, 0x9b, 0x38, 0x72, 0x01, 0xa1, 0x85, 0x15, 0xcc, 0x70, 0x25, 0x58, 0xee,
+	0x25, 0xee, 0x2c, 0xf9, 0xfa, 0xda, 0x9b, 0xe2, 0x3f, 0x80, 0xec, 0xbf, 0x13, 0xa4, 0x0f, 0x1d,
+	0xbb, 0x93, 0x62, 0x26, 0x32, 0x66, 0xb8, 0x2f, 0xa1, 0x6e, 0x7a, 0x87, 0x1e, 0x35, 0xfe, 0xbf,
+	0x47, 0xf1, 0xdf, 0xc1, 0xbd, 0xbd, 0xb6, 0x2f, 0xed, 0x77, 0x92, 0x4d, 0x72, 0xf7, 0xd1, 0x63,
+	0xea, 0x6f, 0xe4, 0x7b, 0x20, 0x59, 0x2e, 0xb8, 0x34, 0xf5, 0x3c, 0xa3, 0xc6, 0xde, 0xfb, 0x54,
+	0x77, 0xd3, 0x07, 0x28, 0xf6, 0x25, 0xae, 0x38, 0x57, 0x3b, 0x61, 0x9a, 0x8f, 0x87, 0xd9, 0x23,
+	0xbc, 0x4a, 0xe1, 0xa2, 0x54, 0xf3, 0xe1, 0x62, 0x5d, 0x71, 0x95, 0xf3, 0xe9, 0x9c, 0xab, 0xe1,
+	0x0c, 0x79, 0xee, 0x1f, 0xa7, 0x6d, 0xa4, 0x57, 0xa7, 0xd7, 0xba, 0x72, 0x53, 0x7f, 0xc3, 0xb2,
+	0x25, 0x9b, 0xf3, 0x5f, 0x07, 0x73, 0x61, 0x16, 0xab, 0xc9, 0x30, 0x2b, 0x8b, 0xcb, 0x1a, 0xf7,
+	0xd2, 0x71, 0x2f, 0x1d, 0xd7, 0xfe, 0x31, 0x27, 0x2d, 0x3c, 0xbf, 0xfc, 0x6f, 0x00, 0xbe, 0x08,
+	0x3c, 0xc4, 0x43, 0x07, 0x00, 0x00,
 }
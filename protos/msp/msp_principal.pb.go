@@ -23,17 +23,21 @@ const (
 	// E.g., this can well be represented by an MSP's
 	// Organization unit
 	MSPPrincipal_IDENTITY MSPPrincipal_Classification = 2
+	// identity
+	MSPPrincipal_IDEMIX_ATTRIBUTES MSPPrincipal_Classification = 3
 )
 
 var MSPPrincipal_Classification_name = map[int32]string{
 	0: "ROLE",
 	1: "ORGANIZATION_UNIT",
 	2: "IDENTITY",
+	3: "IDEMIX_ATTRIBUTES",
 }
 var MSPPrincipal_Classification_value = map[string]int32{
 	"ROLE":              0,
 	"ORGANIZATION_UNIT": 1,
 	"IDENTITY":          2,
+	"IDEMIX_ATTRIBUTES": 3,
 }
 
 func (x MSPPrincipal_Classification) String() string {
@@ -70,6 +74,29 @@ func (x MSPRole_MSPRoleType) String() string {
 }
 func (MSPRole_MSPRoleType) EnumDescriptor() ([]byte, []int) { return fileDescriptor2, []int{2, 0} }
 
+type IdemixAttributePredicate_Type int32
+
+const (
+	IdemixAttributePredicate_EQUAL  IdemixAttributePredicate_Type = 0
+	IdemixAttributePredicate_MEMBER IdemixAttributePredicate_Type = 1
+)
+
+var IdemixAttributePredicate_Type_name = map[int32]string{
+	0: "EQUAL",
+	1: "MEMBER",
+}
+var IdemixAttributePredicate_Type_value = map[string]int32{
+	"EQUAL":  0,
+	"MEMBER": 1,
+}
+
+func (x IdemixAttributePredicate_Type) String() string {
+	return proto.EnumName(IdemixAttributePredicate_Type_name, int32(x))
+}
+func (IdemixAttributePredicate_Type) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor2, []int{4, 0}
+}
+
 // MSPPrincipal aims to represent an MSP-centric set of identities.
 // In particular, this structure allows for definition of
 //  - a group of identities that are member of the same MSP
@@ -196,42 +223,122 @@ func (m *MSPRole) GetRole() MSPRole_MSPRoleType {
 	return MSPRole_MEMBER
 }
 
+// IdemixAttributes governs the organization of the Principal
+// field of an MSPPrincipal when it aims to define the holders of
+// Idemix credentials whose attributes satisfy a set of predicates.
+type IdemixAttributes struct {
+	// MSPIdentifier represents the identifier of the Idemix MSP this
+	// principal refers to
+	MspIdentifier string `protobuf:"bytes,1,opt,name=msp_identifier,json=mspIdentifier" json:"msp_identifier,omitempty"`
+	// Predicates that must all be satisfied by the attributes an
+	// identity proves to hold
+	Predicates []*IdemixAttributePredicate `protobuf:"bytes,2,rep,name=predicates" json:"predicates,omitempty"`
+}
+
+func (m *IdemixAttributes) Reset()                    { *m = IdemixAttributes{} }
+func (m *IdemixAttributes) String() string            { return proto.CompactTextString(m) }
+func (*IdemixAttributes) ProtoMessage()               {}
+func (*IdemixAttributes) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{3} }
+
+func (m *IdemixAttributes) GetMspIdentifier() string {
+	if m != nil {
+		return m.MspIdentifier
+	}
+	return ""
+}
+
+func (m *IdemixAttributes) GetPredicates() []*IdemixAttributePredicate {
+	if m != nil {
+		return m.Predicates
+	}
+	return nil
+}
+
+// IdemixAttributePredicate is a predicate over a single attribute
+// of an Idemix credential.
+type IdemixAttributePredicate struct {
+	// AttributeName is the name of the attribute as defined by the
+	// issuer public key, e.g. "OU", "Role" or "EnrollmentID"
+	AttributeName string `protobuf:"bytes,1,opt,name=attribute_name,json=attributeName" json:"attribute_name,omitempty"`
+	// Type defines how Values are compared against the attribute
+	Type IdemixAttributePredicate_Type `protobuf:"varint,2,opt,name=type,enum=common.IdemixAttributePredicate_Type" json:"type,omitempty"`
+	// Values the attribute is compared against
+	Values []string `protobuf:"bytes,3,rep,name=values" json:"values,omitempty"`
+}
+
+func (m *IdemixAttributePredicate) Reset()                    { *m = IdemixAttributePredicate{} }
+func (m *IdemixAttributePredicate) String() string            { return proto.CompactTextString(m) }
+func (*IdemixAttributePredicate) ProtoMessage()               {}
+func (*IdemixAttributePredicate) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{4} }
+
+func (m *IdemixAttributePredicate) GetAttributeName() string {
+	if m != nil {
+		return m.AttributeName
+	}
+	return ""
+}
+
+func (m *IdemixAttributePredicate) GetType() IdemixAttributePredicate_Type {
+	if m != nil {
+		return m.Type
+	}
+	return IdemixAttributePredicate_EQUAL
+}
+
+func (m *IdemixAttributePredicate) GetValues() []string {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*MSPPrincipal)(nil), "common.MSPPrincipal")
 	proto.RegisterType((*OrganizationUnit)(nil), "common.OrganizationUnit")
 	proto.RegisterType((*MSPRole)(nil), "common.MSPRole")
+	proto.RegisterType((*IdemixAttributes)(nil), "common.IdemixAttributes")
+	proto.RegisterType((*IdemixAttributePredicate)(nil), "common.IdemixAttributePredicate")
 	proto.RegisterEnum("common.MSPPrincipal_Classification", MSPPrincipal_Classification_name, MSPPrincipal_Classification_value)
 	proto.RegisterEnum("common.MSPRole_MSPRoleType", MSPRole_MSPRoleType_name, MSPRole_MSPRoleType_value)
+	proto.RegisterEnum("common.IdemixAttributePredicate_Type", IdemixAttributePredicate_Type_name, IdemixAttributePredicate_Type_value)
 }
 
 func init() { proto.RegisterFile("msp/msp_principal.proto", fileDescriptor2) }
 
 var fileDescriptor2 = []byte{
-	// 402 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x92, 0x4d, 0x6f, 0xd3, 0x30,
-	0x18, 0xc7, 0xe7, 0x6c, 0x94, 0xf5, 0xa1, 0x44, 0xc6, 0x62, 0x5a, 0x25, 0x26, 0x34, 0x05, 0x90,
-	0x7a, 0x4a, 0xa4, 0xed, 0xc6, 0x05, 0x75, 0x6b, 0x84, 0x2c, 0x2d, 0x2f, 0xf2, 0xb2, 0x03, 0x3b,
-	0x10, 0xa5, 0x99, 0x9b, 0x59, 0x4a, 0x62, 0xcb, 0xc9, 0x0e, 0xe3, 0xbb, 0xf0, 0x2d, 0xf8, 0x1a,
-	0x7c, 0x27, 0x94, 0x84, 0xa6, 0x2e, 0x27, 0x4e, 0x89, 0x9f, 0xff, 0xef, 0xf7, 0xf8, 0x15, 0x4e,
-	0xab, 0x46, 0x79, 0x55, 0xa3, 0x52, 0xa5, 0x45, 0x9d, 0x0b, 0x95, 0x95, 0xae, 0xd2, 0xb2, 0x95,
-	0x64, 0x92, 0xcb, 0xaa, 0x92, 0xb5, 0xf3, 0x1b, 0xc1, 0x2c, 0xb8, 0x8d, 0xe3, 0x6d, 0x4c, 0xbe,
-	0xc3, 0x7c, 0x64, 0xd3, 0xbc, 0xcc, 0x9a, 0x46, 0x6c, 0x44, 0x9e, 0xb5, 0x42, 0xd6, 0x73, 0x74,
-	0x8e, 0x16, 0xf6, 0xc5, 0x07, 0x77, 0x70, 0x5d, 0xd3, 0x73, 0xaf, 0xf7, 0x50, 0x76, 0x3a, 0x36,
-	0xd9, 0x0f, 0xc8, 0x19, 0x4c, 0xc7, 0x68, 0x6e, 0x9d, 0xa3, 0xc5, 0x8c, 0xed, 0x0a, 0xce, 0x17,
-	0xb0, 0xff, 0xe1, 0x8f, 0xe1, 0x88, 0x45, 0x37, 0x3e, 0x3e, 0x20, 0x27, 0xf0, 0x26, 0x62, 0x5f,
-	0x97, 0x21, 0xbd, 0x5f, 0x26, 0x34, 0x0a, 0xd3, 0xbb, 0x90, 0x26, 0x18, 0x91, 0x19, 0x1c, 0xd3,
-	0x95, 0x1f, 0x26, 0x34, 0xf9, 0x86, 0x2d, 0xe7, 0x17, 0x02, 0x1c, 0xe9, 0x22, 0xab, 0xc5, 0x8f,
-	0xde, 0xbf, 0xab, 0x45, 0x4b, 0x3e, 0x81, 0xdd, 0x9d, 0x81, 0x78, 0xe0, 0x75, 0x2b, 0x36, 0x82,
-	0xeb, 0x7e, 0x27, 0x53, 0xf6, 0xba, 0x6a, 0x14, 0x1d, 0x8b, 0x64, 0x05, 0xef, 0xa5, 0xa1, 0x66,
-	0x65, 0xfa, 0x54, 0x8b, 0xd6, 0xd4, 0xac, 0x5e, 0x3b, 0xdb, 0xa7, 0xba, 0x29, 0x8c, 0x2e, 0x97,
-	0x70, 0x92, 0x73, 0x3d, 0x0c, 0x1a, 0x53, 0x3e, 0xec, 0x37, 0xfb, 0x76, 0x17, 0xee, 0x24, 0xe7,
-	0x27, 0x82, 0x97, 0xc1, 0x6d, 0xcc, 0x64, 0xc9, 0xff, 0x77, 0xb5, 0x1e, 0x1c, 0x69, 0x59, 0xf2,
-	0x7e, 0x4d, 0xf6, 0xc5, 0x3b, 0xe3, 0x52, 0xba, 0x2e, 0xdb, 0x6f, 0xf2, 0xac, 0x38, 0xeb, 0x41,
-	0xe7, 0x33, 0xbc, 0x32, 0x8a, 0x04, 0x60, 0x12, 0xf8, 0xc1, 0x95, 0xcf, 0xf0, 0x01, 0x99, 0xc2,
-	0x8b, 0xe5, 0x2a, 0xa0, 0x21, 0x46, 0x5d, 0xf9, 0xfa, 0x86, 0xfa, 0x61, 0x82, 0xad, 0xee, 0xec,
-	0x63, 0xdf, 0x67, 0xf8, 0xf0, 0x2a, 0x86, 0x8f, 0x52, 0x17, 0xee, 0xe3, 0xb3, 0xe2, 0xba, 0xe4,
-	0x0f, 0x05, 0xd7, 0xee, 0x26, 0x5b, 0x6b, 0x91, 0x0f, 0xcf, 0xa9, 0xf9, 0x3b, 0xfb, 0xfd, 0xa2,
-	0x10, 0xed, 0xe3, 0xd3, 0xba, 0x1b, 0x7a, 0x06, 0xec, 0x0d, 0xb0, 0x37, 0xc0, 0xdd, 0x83, 0x5c,
-	0x4f, 0xfa, 0xff, 0xcb, 0x3f, 0x01, 0x00, 0x00, 0xff, 0xff, 0x0e, 0xfd, 0x10, 0x47, 0xa2, 0x02,
-	0x00, 0x00,
+	// 529 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x53, 0x5d, 0x8f, 0xd2, 0x40,
+	0x14, 0xdd, 0x52, 0x16, 0x97, 0xbb, 0x48, 0xea, 0xc4, 0x75, 0x9b, 0xb8, 0x1a, 0x52, 0xdd, 0x84,
+	0xa7, 0x92, 0xb0, 0x4f, 0xfa, 0x64, 0x59, 0x1a, 0x33, 0x09, 0x2d, 0x75, 0x28, 0x46, 0xf7, 0xc1,
+	0xa6, 0x94, 0x81, 0x9d, 0xa4, 0x5f, 0x99, 0x16, 0x23, 0xfa, 0x5b, 0xfc, 0x15, 0xfa, 0x93, 0xfc,
+	0x21, 0x66, 0xda, 0x2d, 0x94, 0x4d, 0x8c, 0xfb, 0xd4, 0xde, 0x73, 0xcf, 0xb9, 0x73, 0xe6, 0xdc,
+	0x0c, 0x9c, 0x47, 0x59, 0x3a, 0x88, 0xb2, 0xd4, 0x4b, 0x39, 0x8b, 0x03, 0x96, 0xfa, 0xa1, 0x9e,
+	0xf2, 0x24, 0x4f, 0x50, 0x2b, 0x48, 0xa2, 0x28, 0x89, 0xb5, 0x3f, 0x12, 0x74, 0xac, 0x99, 0xe3,
+	0x54, 0x6d, 0xf4, 0x05, 0xd4, 0x1d, 0xd7, 0x0b, 0x42, 0x3f, 0xcb, 0xd8, 0x8a, 0x05, 0x7e, 0xce,
+	0x92, 0x58, 0x95, 0x7a, 0x52, 0xbf, 0x3b, 0x7c, 0xa5, 0x97, 0x5a, 0xbd, 0xae, 0xd3, 0xaf, 0x0f,
+	0xa8, 0xe4, 0x7c, 0x37, 0xe4, 0xb0, 0x81, 0x2e, 0xa0, 0xbd, 0x6b, 0xa9, 0x8d, 0x9e, 0xd4, 0xef,
+	0x90, 0x3d, 0xa0, 0x7d, 0x84, 0xee, 0x3d, 0xfe, 0x09, 0x34, 0xc9, 0x74, 0x62, 0x2a, 0x47, 0xe8,
+	0x0c, 0x9e, 0x4c, 0xc9, 0x7b, 0xc3, 0xc6, 0x37, 0x86, 0x8b, 0xa7, 0xb6, 0x37, 0xb7, 0xb1, 0xab,
+	0x48, 0xa8, 0x03, 0x27, 0x78, 0x6c, 0xda, 0x2e, 0x76, 0x3f, 0x2b, 0x0d, 0x41, 0xc2, 0x63, 0xd3,
+	0xc2, 0x9f, 0x3c, 0xc3, 0x75, 0x09, 0x1e, 0xcd, 0x5d, 0x73, 0xa6, 0xc8, 0xda, 0x6f, 0x09, 0x94,
+	0x29, 0x5f, 0xfb, 0x31, 0xfb, 0x5e, 0x8c, 0x9d, 0xc7, 0x2c, 0x47, 0x97, 0xd0, 0x15, 0xd1, 0xb0,
+	0x25, 0x8d, 0x73, 0xb6, 0x62, 0x94, 0x17, 0x17, 0x6c, 0x93, 0xc7, 0x51, 0x96, 0xe2, 0x1d, 0x88,
+	0xc6, 0xf0, 0x32, 0xa9, 0x49, 0xfd, 0xd0, 0xdb, 0xc4, 0x2c, 0xaf, 0xcb, 0x1a, 0x85, 0xec, 0xe2,
+	0x90, 0x25, 0x8e, 0xa8, 0x4d, 0xb9, 0x82, 0xb3, 0x80, 0xf2, 0xb2, 0xc8, 0xea, 0x62, 0xb9, 0xc8,
+	0xe0, 0xe9, 0xbe, 0xb9, 0x17, 0x69, 0x3f, 0x25, 0x78, 0x64, 0xcd, 0x1c, 0x92, 0x84, 0xf4, 0xa1,
+	0x6e, 0x07, 0xd0, 0xe4, 0x49, 0x48, 0x0b, 0x4f, 0xdd, 0xe1, 0xf3, 0xda, 0xae, 0xc4, 0x94, 0xea,
+	0xeb, 0x6e, 0x53, 0x4a, 0x0a, 0xa2, 0xf6, 0x16, 0x4e, 0x6b, 0x20, 0x02, 0x68, 0x59, 0xa6, 0x35,
+	0x32, 0x89, 0x72, 0x84, 0xda, 0x70, 0x6c, 0x8c, 0x2d, 0x6c, 0x2b, 0x92, 0x80, 0xaf, 0x27, 0xd8,
+	0xb4, 0x5d, 0xa5, 0x21, 0x56, 0xe2, 0x98, 0x26, 0x51, 0x64, 0xed, 0x07, 0x28, 0x78, 0x49, 0x23,
+	0xf6, 0xcd, 0xc8, 0x73, 0xce, 0x16, 0x9b, 0x9c, 0x66, 0x0f, 0xf5, 0xf9, 0x0e, 0x20, 0xe5, 0x74,
+	0x29, 0xd6, 0x4c, 0x33, 0xb5, 0xd1, 0x93, 0xfb, 0xa7, 0xc3, 0x5e, 0xe5, 0xf6, 0xde, 0x50, 0xa7,
+	0x22, 0x92, 0x9a, 0x46, 0xfb, 0x25, 0x81, 0xfa, 0x2f, 0xa2, 0x70, 0xe1, 0x57, 0xa8, 0x17, 0xfb,
+	0x11, 0xad, 0x5c, 0xec, 0x50, 0xdb, 0x8f, 0x28, 0x7a, 0x03, 0xcd, 0x7c, 0x9b, 0x56, 0x69, 0x5d,
+	0xfe, 0xef, 0x7c, 0xbd, 0xcc, 0x4d, 0x48, 0xd0, 0x33, 0x68, 0x7d, 0xf5, 0xc3, 0x0d, 0xcd, 0x54,
+	0xb9, 0x27, 0xf7, 0xdb, 0xe4, 0xae, 0xd2, 0x5e, 0x40, 0xb3, 0x08, 0xb2, 0x0d, 0xc7, 0xe6, 0x87,
+	0xb9, 0x31, 0x51, 0x8e, 0x6a, 0x99, 0x4a, 0x23, 0x07, 0x5e, 0x27, 0x7c, 0xad, 0xdf, 0x6e, 0x53,
+	0xca, 0x43, 0xba, 0x5c, 0x53, 0xae, 0xaf, 0xfc, 0x05, 0x67, 0x41, 0xf9, 0x30, 0xb3, 0x3b, 0x0b,
+	0x37, 0xfd, 0x35, 0xcb, 0x6f, 0x37, 0x0b, 0x51, 0x0e, 0x6a, 0xe4, 0x41, 0x49, 0x1e, 0x94, 0x64,
+	0xf1, 0xb4, 0x17, 0xad, 0xe2, 0xff, 0xea, 0xef, 0x00, 0xdb, 0x30, 0xd2, 0x27, 0xec, 0x03, 0x00,
+	0x00,
 }
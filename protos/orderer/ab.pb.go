@@ -5,11 +5,14 @@
 Package orderer is a generated protocol buffer package.
 
 It is generated from these files:
+
 	orderer/ab.proto
+	orderer/cluster.proto
 	orderer/configuration.proto
 	orderer/kafka.proto
 
 It has these top-level messages:
+
 	BroadcastResponse
 	SeekNewest
 	SeekOldest
@@ -17,6 +20,12 @@ It has these top-level messages:
 	SeekPosition
 	SeekInfo
 	DeliverResponse
+	StepRequest
+	StepResponse
+	SubmitRequest
+	SubmitResponse
+	PullRequest
+	PullResponse
 	ConsensusType
 	BatchSize
 	BatchTimeout
@@ -621,37 +630,37 @@ var _AtomicBroadcast_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("orderer/ab.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 505 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x93, 0xdf, 0x6e, 0x12, 0x51,
-	0x10, 0xc6, 0x59, 0xa5, 0xb4, 0x8c, 0x2d, 0xa5, 0xa7, 0x69, 0x43, 0xb8, 0x30, 0xcd, 0x26, 0x55,
-	0x8c, 0xba, 0x6b, 0x30, 0xf1, 0x42, 0x4d, 0x0c, 0x6b, 0xdb, 0x40, 0x24, 0x60, 0x16, 0x7a, 0xa1,
-	0x37, 0x64, 0x77, 0x19, 0x60, 0xed, 0xb2, 0x67, 0x73, 0xce, 0x01, 0xd3, 0xa7, 0xf0, 0x45, 0x7c,
-	0x24, 0x1f, 0xc6, 0x9c, 0x3f, 0xbb, 0x48, 0xd5, 0x5e, 0xb1, 0x33, 0xf3, 0xfb, 0xce, 0x7c, 0x33,
-	0x19, 0xa0, 0x4e, 0xd9, 0x14, 0x19, 0x32, 0x37, 0x08, 0x9d, 0x8c, 0x51, 0x41, 0xc9, 0xae, 0xc9,
-	0x34, 0x8f, 0x23, 0xba, 0x5c, 0xd2, 0xd4, 0xd5, 0x3f, 0xba, 0x6a, 0x0f, 0xe1, 0xc8, 0x63, 0x34,
-	0x98, 0x46, 0x01, 0x17, 0x3e, 0xf2, 0x8c, 0xa6, 0x1c, 0xc9, 0x13, 0xa8, 0x70, 0x11, 0x88, 0x15,
-	0x6f, 0x58, 0x67, 0x56, 0xab, 0xd6, 0xae, 0x39, 0x46, 0x33, 0x52, 0x59, 0xdf, 0x54, 0x09, 0x81,
-	0x72, 0x9c, 0xce, 0x68, 0xe3, 0xc1, 0x99, 0xd5, 0xaa, 0xfa, 0xea, 0xdb, 0xde, 0x07, 0x18, 0x21,
-	0xde, 0x0c, 0xf0, 0x3b, 0x72, 0x91, 0x47, 0xc3, 0x64, 0x2a, 0xa3, 0xa7, 0x70, 0x20, 0xa3, 0x51,
-	0x86, 0x51, 0x3c, 0x8b, 0x71, 0x4a, 0x4e, 0xa1, 0x92, 0xae, 0x96, 0x21, 0x32, 0xd5, 0xa8, 0xec,
-	0x9b, 0xc8, 0xfe, 0x69, 0xc1, 0xbe, 0x24, 0x3f, 0x53, 0x1e, 0x8b, 0x98, 0xa6, 0xe4, 0x25, 0x54,
-	0x52, 0xf5, 0xa2, 0x02, 0x1f, 0xb5, 0x8f, 0x1d, 0x33, 0x95, 0xb3, 0x69, 0xd6, 0x2d, 0xf9, 0x06,
-	0x92, 0x38, 0x55, 0x2d, 0x95, 0xb5, 0xbb, 0xb8, 0x76, 0x23, 0x71, 0x0d, 0x91, 0x37, 0x50, 0xe5,
-	0xb9, 0xa7, 0xc6, 0x43, 0xa5, 0x38, 0xdd, 0x52, 0x14, 0x8e, 0xbb, 0x25, 0x7f, 0x83, 0x7a, 0x15,
-	0x28, 0x8f, 0x6f, 0x33, 0xb4, 0x7f, 0x59, 0xb0, 0x27, 0xb1, 0x5e, 0x3a, 0xa3, 0xe4, 0x39, 0xec,
-	0x70, 0x11, 0xb0, 0xdc, 0xe9, 0xc9, 0xd6, 0x43, 0xf9, 0x40, 0xbe, 0x66, 0xc8, 0x33, 0x28, 0x73,
-	0x41, 0x33, 0x63, 0xf3, 0x3f, 0xac, 0x42, 0xc8, 0x5b, 0xd8, 0x0b, 0x71, 0x11, 0xac, 0x63, 0xca,
-	0x94, 0xc7, 0x5a, 0xfb, 0xf1, 0x16, 0x2e, 0x9b, 0xab, 0x0f, 0xcf, 0x50, 0x7e, 0xc1, 0xdb, 0xef,
-	0xf5, 0x3a, 0xf3, 0x0a, 0x39, 0x81, 0x23, 0xaf, 0x3f, 0xfc, 0xf8, 0x69, 0x72, 0x3d, 0x18, 0xf7,
-	0xfa, 0x13, 0xff, 0xb2, 0x73, 0xf1, 0xa5, 0x5e, 0x92, 0xe9, 0xab, 0x4e, 0xaf, 0x3f, 0xe9, 0x5d,
-	0x4d, 0x06, 0xc3, 0xb1, 0x49, 0x5b, 0xf6, 0x37, 0x38, 0xbc, 0xc0, 0x24, 0x5e, 0x23, 0x2b, 0x2e,
-	0xa4, 0x75, 0xff, 0x85, 0xc8, 0xdd, 0x9a, 0x1b, 0x39, 0x87, 0x9d, 0x30, 0xa1, 0xd1, 0x8d, 0x19,
-	0xf1, 0x20, 0x07, 0x3d, 0x99, 0xec, 0x96, 0x7c, 0x5d, 0xcd, 0x57, 0xd9, 0xfe, 0x61, 0xc1, 0x61,
-	0x47, 0xd0, 0x65, 0x1c, 0x15, 0x67, 0x49, 0x3e, 0x40, 0x75, 0x13, 0xd4, 0xf3, 0x07, 0x2e, 0xd3,
-	0x35, 0x26, 0x34, 0xc3, 0x66, 0xb3, 0x58, 0xc3, 0x5f, 0x97, 0x6c, 0x97, 0x5a, 0xd6, 0x2b, 0x8b,
-	0xbc, 0x83, 0x5d, 0x33, 0xc0, 0x3f, 0xe4, 0x8d, 0x42, 0x7e, 0x67, 0x48, 0x2d, 0xf6, 0xae, 0xe1,
-	0x9c, 0xb2, 0xb9, 0xb3, 0xb8, 0xcd, 0x90, 0x25, 0x38, 0x9d, 0x23, 0x73, 0x66, 0x41, 0xc8, 0xe2,
-	0x48, 0xff, 0x83, 0x78, 0x2e, 0xff, 0xfa, 0x62, 0x1e, 0x8b, 0xc5, 0x2a, 0x94, 0x0d, 0xdc, 0x3f,
-	0x68, 0x57, 0xd3, 0xae, 0xa6, 0x5d, 0x43, 0x87, 0x15, 0x15, 0xbf, 0xfe, 0x1d, 0x00, 0x00, 0xff,
-	0xff, 0x4b, 0x88, 0xa4, 0x39, 0xb1, 0x03, 0x00, 0x00,
+	// 504 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x93, 0xdf, 0x6e, 0xda, 0x4a,
+	0x10, 0xc6, 0x31, 0x87, 0x90, 0x30, 0x87, 0x10, 0xb2, 0x51, 0x22, 0x8b, 0x8b, 0x2a, 0xb2, 0x94,
+	0x96, 0xaa, 0xad, 0x5d, 0x51, 0xa9, 0x17, 0x6d, 0xa5, 0x0a, 0x37, 0x89, 0x40, 0x45, 0x50, 0x19,
+	0x72, 0xd1, 0xde, 0x20, 0xdb, 0x0c, 0xe0, 0xc6, 0x78, 0xad, 0x5d, 0x43, 0x95, 0xa7, 0xe8, 0x8b,
+	0xf4, 0x91, 0xfa, 0x30, 0xd5, 0xfe, 0xb1, 0x09, 0x6d, 0x94, 0x2b, 0xef, 0x37, 0xf3, 0xfb, 0x76,
+	0x66, 0x56, 0x63, 0x68, 0x52, 0x36, 0x43, 0x86, 0xcc, 0xf1, 0x03, 0x3b, 0x65, 0x34, 0xa3, 0x64,
+	0x5f, 0x47, 0x5a, 0x27, 0x21, 0x5d, 0xad, 0x68, 0xe2, 0xa8, 0x8f, 0xca, 0x5a, 0x23, 0x38, 0x76,
+	0x19, 0xf5, 0x67, 0xa1, 0xcf, 0x33, 0x0f, 0x79, 0x4a, 0x13, 0x8e, 0xe4, 0x29, 0x54, 0x79, 0xe6,
+	0x67, 0x6b, 0x6e, 0x1a, 0xe7, 0x46, 0xbb, 0xd1, 0x69, 0xd8, 0xda, 0x33, 0x96, 0x51, 0x4f, 0x67,
+	0x09, 0x81, 0x4a, 0x94, 0xcc, 0xa9, 0x59, 0x3e, 0x37, 0xda, 0x35, 0x4f, 0x9e, 0xad, 0x3a, 0xc0,
+	0x18, 0xf1, 0x76, 0x88, 0x3f, 0x90, 0x67, 0xb9, 0x1a, 0xc5, 0x33, 0xa1, 0x9e, 0xc1, 0xa1, 0x50,
+	0xe3, 0x14, 0xc3, 0x68, 0x1e, 0xe1, 0x8c, 0x9c, 0x41, 0x35, 0x59, 0xaf, 0x02, 0x64, 0xb2, 0x50,
+	0xc5, 0xd3, 0xca, 0xfa, 0x65, 0x40, 0x5d, 0x90, 0x5f, 0x28, 0x8f, 0xb2, 0x88, 0x26, 0xe4, 0x15,
+	0x54, 0x13, 0x79, 0xa3, 0x04, 0xff, 0xef, 0x9c, 0xd8, 0x7a, 0x2a, 0x7b, 0x5b, 0xac, 0x57, 0xf2,
+	0x34, 0x24, 0x70, 0x2a, 0x4b, 0x9a, 0xe5, 0x07, 0x70, 0xd5, 0x8d, 0xc0, 0x15, 0x44, 0xde, 0x42,
+	0x8d, 0xe7, 0x3d, 0x99, 0xff, 0x49, 0xc7, 0xd9, 0x8e, 0xa3, 0xe8, 0xb8, 0x57, 0xf2, 0xb6, 0xa8,
+	0x5b, 0x85, 0xca, 0xe4, 0x2e, 0x45, 0xeb, 0xb7, 0x01, 0x07, 0x02, 0xeb, 0x27, 0x73, 0x4a, 0x5e,
+	0xc0, 0x1e, 0xcf, 0x7c, 0x96, 0x77, 0x7a, 0xba, 0x73, 0x51, 0x3e, 0x90, 0xa7, 0x18, 0xf2, 0x1c,
+	0x2a, 0x3c, 0xa3, 0xa9, 0x59, 0x7e, 0x8c, 0x95, 0x08, 0x79, 0x07, 0x07, 0x01, 0x2e, 0xfd, 0x4d,
+	0x44, 0x99, 0xec, 0xb1, 0xd1, 0x79, 0xb2, 0x83, 0x8b, 0xe2, 0xf2, 0xe0, 0x6a, 0xca, 0x2b, 0x78,
+	0xeb, 0x03, 0xd4, 0xef, 0x67, 0xc8, 0x29, 0x1c, 0xbb, 0x83, 0xd1, 0xa7, 0xcf, 0xd3, 0x9b, 0xe1,
+	0xa4, 0x3f, 0x98, 0x7a, 0x57, 0xdd, 0xcb, 0xaf, 0xcd, 0x92, 0x08, 0x5f, 0x77, 0xfb, 0x83, 0x69,
+	0xff, 0x7a, 0x3a, 0x1c, 0x4d, 0x74, 0xd8, 0xb0, 0xbe, 0xc3, 0xd1, 0x25, 0xc6, 0xd1, 0x06, 0x59,
+	0xb1, 0x21, 0xed, 0xc7, 0x37, 0x44, 0xbc, 0xad, 0xde, 0x91, 0x0b, 0xd8, 0x0b, 0x62, 0x1a, 0xde,
+	0xea, 0x11, 0x0f, 0x73, 0xd0, 0x15, 0xc1, 0x5e, 0xc9, 0x53, 0xd9, 0xfc, 0x29, 0x3b, 0x3f, 0x0d,
+	0x38, 0xea, 0x66, 0x74, 0x15, 0x85, 0xc5, 0x5a, 0x92, 0x8f, 0x50, 0xdb, 0x8a, 0x66, 0x7e, 0xc1,
+	0x55, 0xb2, 0xc1, 0x98, 0xa6, 0xd8, 0x6a, 0x15, 0xcf, 0xf0, 0xcf, 0x26, 0x5b, 0xa5, 0xb6, 0xf1,
+	0xda, 0x20, 0xef, 0x61, 0x5f, 0x0f, 0xf0, 0x80, 0xdd, 0x2c, 0xec, 0x7f, 0x0d, 0xa9, 0xcc, 0xee,
+	0x0d, 0x5c, 0x50, 0xb6, 0xb0, 0x97, 0x77, 0x29, 0xb2, 0x18, 0x67, 0x0b, 0x64, 0xf6, 0xdc, 0x0f,
+	0x58, 0x14, 0xaa, 0x3f, 0x88, 0xe7, 0xf6, 0x6f, 0x2f, 0x17, 0x51, 0xb6, 0x5c, 0x07, 0xa2, 0x80,
+	0x73, 0x8f, 0x76, 0x14, 0xed, 0x28, 0xda, 0xd1, 0x74, 0x50, 0x95, 0xfa, 0xcd, 0x9f, 0x01, 0x00,
+	0x4b, 0x88, 0xa4, 0x39, 0xb1, 0x03, 0x00, 0x00,
 }
@@ -21,8 +21,10 @@ import (
 
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/msp"
+	"github.com/hyperledger/fabric/protos/orderer/etcdraft"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/empty"
 )
 
 func init() {
@@ -132,3 +134,19 @@ func (doocv *DynamicOrdererOrgConfigValue) VariablyOpaqueFieldProto(name string)
 		return nil, fmt.Errorf("unknown Orderer Org ConfigValue name: %s", doocv.name)
 	}
 }
+
+func (ct *ConsensusType) VariablyOpaqueFields() []string {
+	return []string{"metadata"}
+}
+
+func (ct *ConsensusType) VariablyOpaqueFieldProto(name string) (proto.Message, error) {
+	if name != ct.VariablyOpaqueFields()[0] {
+		return nil, fmt.Errorf("not a marshaled field: %s", name)
+	}
+	switch ct.Type {
+	case "etcdraft":
+		return &etcdraft.Metadata{}, nil
+	default:
+		return &empty.Empty{}, nil
+	}
+}
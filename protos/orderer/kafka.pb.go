@@ -34,7 +34,9 @@ var KafkaMessageRegular_Class_value = map[string]int32{
 func (x KafkaMessageRegular_Class) String() string {
 	return proto.EnumName(KafkaMessageRegular_Class_name, int32(x))
 }
-func (KafkaMessageRegular_Class) EnumDescriptor() ([]byte, []int) { return fileDescriptor2, []int{1, 0} }
+func (KafkaMessageRegular_Class) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor3, []int{1, 0}
+}
 
 // KafkaMessage is a wrapper type for the messages
 // that the Kafka-based orderer deals with.
@@ -49,7 +51,7 @@ type KafkaMessage struct {
 func (m *KafkaMessage) Reset()                    { *m = KafkaMessage{} }
 func (m *KafkaMessage) String() string            { return proto.CompactTextString(m) }
 func (*KafkaMessage) ProtoMessage()               {}
-func (*KafkaMessage) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{0} }
+func (*KafkaMessage) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{0} }
 
 type isKafkaMessage_Type interface{ isKafkaMessage_Type() }
 
@@ -199,7 +201,7 @@ type KafkaMessageRegular struct {
 func (m *KafkaMessageRegular) Reset()                    { *m = KafkaMessageRegular{} }
 func (m *KafkaMessageRegular) String() string            { return proto.CompactTextString(m) }
 func (*KafkaMessageRegular) ProtoMessage()               {}
-func (*KafkaMessageRegular) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{1} }
+func (*KafkaMessageRegular) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{1} }
 
 func (m *KafkaMessageRegular) GetPayload() []byte {
 	if m != nil {
@@ -238,7 +240,7 @@ type KafkaMessageTimeToCut struct {
 func (m *KafkaMessageTimeToCut) Reset()                    { *m = KafkaMessageTimeToCut{} }
 func (m *KafkaMessageTimeToCut) String() string            { return proto.CompactTextString(m) }
 func (*KafkaMessageTimeToCut) ProtoMessage()               {}
-func (*KafkaMessageTimeToCut) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{2} }
+func (*KafkaMessageTimeToCut) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{2} }
 
 func (m *KafkaMessageTimeToCut) GetBlockNumber() uint64 {
 	if m != nil {
@@ -258,7 +260,7 @@ type KafkaMessageConnect struct {
 func (m *KafkaMessageConnect) Reset()                    { *m = KafkaMessageConnect{} }
 func (m *KafkaMessageConnect) String() string            { return proto.CompactTextString(m) }
 func (*KafkaMessageConnect) ProtoMessage()               {}
-func (*KafkaMessageConnect) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{3} }
+func (*KafkaMessageConnect) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{3} }
 
 func (m *KafkaMessageConnect) GetPayload() []byte {
 	if m != nil {
@@ -292,7 +294,7 @@ type KafkaMetadata struct {
 func (m *KafkaMetadata) Reset()                    { *m = KafkaMetadata{} }
 func (m *KafkaMetadata) String() string            { return proto.CompactTextString(m) }
 func (*KafkaMetadata) ProtoMessage()               {}
-func (*KafkaMetadata) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{4} }
+func (*KafkaMetadata) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{4} }
 
 func (m *KafkaMetadata) GetLastOffsetPersisted() int64 {
 	if m != nil {
@@ -324,38 +326,38 @@ func init() {
 	proto.RegisterEnum("orderer.KafkaMessageRegular_Class", KafkaMessageRegular_Class_name, KafkaMessageRegular_Class_value)
 }
 
-func init() { proto.RegisterFile("orderer/kafka.proto", fileDescriptor2) }
-
-var fileDescriptor2 = []byte{
-	// 476 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x93, 0xd1, 0x6a, 0xdb, 0x30,
-	0x14, 0x86, 0xe3, 0x26, 0x4d, 0xe8, 0x49, 0xd6, 0x05, 0x85, 0x42, 0x60, 0x5b, 0xe9, 0x0c, 0x63,
-	0xbd, 0x28, 0x36, 0x64, 0x37, 0x65, 0x57, 0x5b, 0x0d, 0x5b, 0x47, 0x57, 0xa7, 0x68, 0x29, 0x83,
-	0xdd, 0x18, 0xd9, 0x3e, 0x76, 0x4d, 0x6c, 0xcb, 0x95, 0xe4, 0x8b, 0xbc, 0xe3, 0xf6, 0x0c, 0x7b,
-	0x95, 0x61, 0xc9, 0x5e, 0x1b, 0x48, 0x7b, 0x67, 0xfd, 0xfa, 0x7e, 0x9d, 0x73, 0xfe, 0x83, 0x61,
-	0xc6, 0x45, 0x8c, 0x02, 0x85, 0xbb, 0x66, 0xc9, 0x9a, 0x39, 0x95, 0xe0, 0x8a, 0x93, 0x51, 0x2b,
-	0xda, 0xbf, 0x2d, 0x98, 0x5c, 0x35, 0x17, 0xd7, 0x28, 0x25, 0x4b, 0x91, 0x9c, 0xc3, 0x48, 0x60,
-	0x5a, 0xe7, 0x4c, 0xcc, 0xad, 0x13, 0xeb, 0x74, 0xbc, 0x78, 0xed, 0xb4, 0xac, 0xf3, 0x98, 0xa3,
-	0x86, 0xb9, 0xec, 0xd1, 0x0e, 0x27, 0x9f, 0x60, 0xac, 0xb2, 0x02, 0x03, 0xc5, 0x83, 0xa8, 0x56,
-	0xf3, 0x3d, 0xed, 0x3e, 0xde, 0xe9, 0x5e, 0x65, 0x05, 0xae, 0xb8, 0x57, 0xab, 0xcb, 0x1e, 0x3d,
-	0x50, 0xdd, 0xa1, 0xa9, 0x1d, 0xf1, 0xb2, 0xc4, 0x48, 0xcd, 0xfb, 0xcf, 0xd4, 0xf6, 0x0c, 0xd3,
-	0xd4, 0x6e, 0xf1, 0x8b, 0x21, 0x0c, 0x56, 0x9b, 0x0a, 0xed, 0xbf, 0x16, 0xcc, 0x76, 0xb4, 0x49,
-	0xe6, 0x30, 0xaa, 0xd8, 0x26, 0xe7, 0x2c, 0xd6, 0x53, 0x4d, 0x68, 0x77, 0x24, 0x6f, 0x00, 0x22,
-	0x5e, 0x26, 0x59, 0x1a, 0x48, 0xbc, 0xd7, 0x4d, 0x0f, 0xe8, 0x81, 0x51, 0x7e, 0xe0, 0x3d, 0x39,
-	0x87, 0xfd, 0x28, 0x67, 0x52, 0xea, 0x86, 0x0e, 0x17, 0xf6, 0x73, 0x61, 0x38, 0x5e, 0x43, 0x52,
-	0x63, 0x20, 0xef, 0xe1, 0x25, 0x17, 0x59, 0x9a, 0x95, 0x2c, 0x0f, 0x78, 0x92, 0x48, 0x54, 0xf3,
-	0xc1, 0x89, 0x75, 0xda, 0xa7, 0x87, 0x9d, 0xbc, 0xd4, 0xaa, 0x7d, 0x06, 0xfb, 0xda, 0x48, 0xc6,
-	0x30, 0xba, 0xf5, 0xaf, 0xfc, 0xe5, 0x4f, 0x7f, 0xda, 0x23, 0x00, 0x43, 0x7f, 0x49, 0xaf, 0x3f,
-	0x7f, 0x9f, 0x5a, 0xcd, 0xb7, 0xb7, 0xf4, 0xbf, 0x7c, 0xfb, 0x3a, 0xdd, 0xb3, 0x3f, 0xc2, 0xd1,
-	0xce, 0x24, 0xc9, 0x5b, 0x98, 0x84, 0x39, 0x8f, 0xd6, 0x41, 0x59, 0x17, 0x21, 0x9a, 0xed, 0x0d,
-	0xe8, 0x58, 0x6b, 0xbe, 0x96, 0x6c, 0x77, 0x3b, 0x9c, 0x36, 0xc7, 0xa7, 0xc3, 0xb1, 0xff, 0x58,
-	0xf0, 0xa2, 0x75, 0x28, 0x16, 0x33, 0xc5, 0xc8, 0x02, 0x8e, 0x72, 0x26, 0x55, 0x3b, 0x51, 0x50,
-	0xa1, 0x90, 0x99, 0x54, 0x68, 0x9c, 0x7d, 0x3a, 0x6b, 0x2e, 0xcd, 0x5c, 0x37, 0xdd, 0x15, 0xf1,
-	0xe0, 0xd8, 0x78, 0xb6, 0xe3, 0x08, 0x2a, 0xc1, 0x23, 0x94, 0x12, 0x63, 0x1d, 0x7b, 0x9f, 0xbe,
-	0xd2, 0xe6, 0xad, 0x70, 0x6e, 0x3a, 0xe4, 0xff, 0x23, 0x02, 0x65, 0x1d, 0x16, 0x99, 0x52, 0x18,
-	0x07, 0xed, 0xe2, 0xda, 0x74, 0xfb, 0x0f, 0x8f, 0xd0, 0x07, 0xc8, 0xd3, 0x8c, 0x79, 0xed, 0xe2,
-	0x16, 0xde, 0x71, 0x91, 0x3a, 0x77, 0x9b, 0x0a, 0x45, 0x8e, 0x71, 0x8a, 0xc2, 0x49, 0x58, 0x28,
-	0xb2, 0xc8, 0xfc, 0x16, 0xb2, 0xdb, 0xee, 0xaf, 0xb3, 0x34, 0x53, 0x77, 0x75, 0xe8, 0x44, 0xbc,
-	0x70, 0x1f, 0xd1, 0xae, 0xa1, 0x5d, 0x43, 0xbb, 0x2d, 0x1d, 0x0e, 0xf5, 0xf9, 0xc3, 0xbf, 0x00,
-	0x00, 0x00, 0xff, 0xff, 0x41, 0xa5, 0x96, 0xb1, 0x6b, 0x03, 0x00, 0x00,
+func init() { proto.RegisterFile("orderer/kafka.proto", fileDescriptor3) }
+
+var fileDescriptor3 = []byte{
+	// 473 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x93, 0xd1, 0x6a, 0xdb, 0x3e,
+	0x14, 0xc6, 0xe3, 0x26, 0x4d, 0xe8, 0x49, 0xfe, 0xfd, 0x07, 0x85, 0x82, 0x61, 0x5b, 0xe9, 0x0c,
+	0x63, 0xbd, 0x28, 0x36, 0x64, 0x37, 0x65, 0x57, 0x5b, 0x0d, 0x5b, 0x47, 0x57, 0xa7, 0x68, 0x29,
+	0x83, 0xdd, 0x18, 0xd9, 0x3e, 0x76, 0x4d, 0x6c, 0xcb, 0x95, 0xe4, 0x8b, 0xbc, 0xe3, 0xf6, 0x0c,
+	0x7b, 0x95, 0x61, 0xc9, 0x5e, 0x5b, 0xc8, 0x7a, 0x67, 0x7d, 0xfa, 0x7d, 0x3a, 0xe7, 0x7c, 0x07,
+	0xc3, 0x82, 0x8b, 0x04, 0x05, 0x0a, 0x6f, 0xc3, 0xd2, 0x0d, 0x73, 0x6b, 0xc1, 0x15, 0x27, 0x93,
+	0x4e, 0x74, 0x7e, 0x5a, 0x30, 0xbb, 0x6a, 0x2f, 0xae, 0x51, 0x4a, 0x96, 0x21, 0x39, 0x87, 0x89,
+	0xc0, 0xac, 0x29, 0x98, 0xb0, 0xad, 0x13, 0xeb, 0x74, 0xba, 0x7c, 0xe9, 0x76, 0xac, 0xfb, 0x98,
+	0xa3, 0x86, 0xb9, 0x1c, 0xd0, 0x1e, 0x27, 0x1f, 0x60, 0xaa, 0xf2, 0x12, 0x43, 0xc5, 0xc3, 0xb8,
+	0x51, 0xf6, 0x9e, 0x76, 0x1f, 0xef, 0x74, 0xaf, 0xf3, 0x12, 0xd7, 0xdc, 0x6f, 0xd4, 0xe5, 0x80,
+	0x1e, 0xa8, 0xfe, 0xd0, 0xd6, 0x8e, 0x79, 0x55, 0x61, 0xac, 0xec, 0xe1, 0x33, 0xb5, 0x7d, 0xc3,
+	0xb4, 0xb5, 0x3b, 0xfc, 0x62, 0x0c, 0xa3, 0xf5, 0xb6, 0x46, 0xe7, 0xb7, 0x05, 0x8b, 0x1d, 0x6d,
+	0x12, 0x1b, 0x26, 0x35, 0xdb, 0x16, 0x9c, 0x25, 0x7a, 0xaa, 0x19, 0xed, 0x8f, 0xe4, 0x15, 0x40,
+	0xcc, 0xab, 0x34, 0xcf, 0x42, 0x89, 0xf7, 0xba, 0xe9, 0x11, 0x3d, 0x30, 0xca, 0x37, 0xbc, 0x27,
+	0xe7, 0xb0, 0x1f, 0x17, 0x4c, 0x4a, 0xdd, 0xd0, 0xe1, 0xd2, 0x79, 0x2e, 0x0c, 0xd7, 0x6f, 0x49,
+	0x6a, 0x0c, 0xe4, 0x2d, 0xfc, 0xcf, 0x45, 0x9e, 0xe5, 0x15, 0x2b, 0x42, 0x9e, 0xa6, 0x12, 0x95,
+	0x3d, 0x3a, 0xb1, 0x4e, 0x87, 0xf4, 0xb0, 0x97, 0x57, 0x5a, 0x75, 0xce, 0x60, 0x5f, 0x1b, 0xc9,
+	0x14, 0x26, 0xb7, 0xc1, 0x55, 0xb0, 0xfa, 0x1e, 0xcc, 0x07, 0x04, 0x60, 0x1c, 0xac, 0xe8, 0xf5,
+	0xc7, 0xaf, 0x73, 0xab, 0xfd, 0xf6, 0x57, 0xc1, 0xa7, 0x2f, 0x9f, 0xe7, 0x7b, 0xce, 0x7b, 0x38,
+	0xda, 0x99, 0x24, 0x79, 0x0d, 0xb3, 0xa8, 0xe0, 0xf1, 0x26, 0xac, 0x9a, 0x32, 0x42, 0xb3, 0xbd,
+	0x11, 0x9d, 0x6a, 0x2d, 0xd0, 0x92, 0xe3, 0xc1, 0x62, 0x47, 0x8e, 0xff, 0x0e, 0xc7, 0xf9, 0x65,
+	0xc1, 0x7f, 0x9d, 0x43, 0xb1, 0x84, 0x29, 0x46, 0x96, 0x70, 0x54, 0x30, 0xa9, 0xba, 0x89, 0xc2,
+	0x1a, 0x85, 0xcc, 0xa5, 0x42, 0xe3, 0x1c, 0xd2, 0x45, 0x7b, 0x69, 0xe6, 0xba, 0xe9, 0xaf, 0x88,
+	0x0f, 0xc7, 0xc6, 0xf3, 0x34, 0x8e, 0xb0, 0x16, 0x3c, 0x46, 0x29, 0x31, 0xd1, 0xb1, 0x0f, 0xe9,
+	0x0b, 0x6d, 0x7e, 0x12, 0xce, 0x4d, 0x8f, 0xfc, 0x7d, 0x44, 0xa0, 0x6c, 0xa2, 0x32, 0x57, 0x0a,
+	0x93, 0xb0, 0x5b, 0x5c, 0x97, 0xee, 0xf0, 0xe1, 0x11, 0xfa, 0x00, 0xf9, 0x9a, 0x31, 0xaf, 0x5d,
+	0xdc, 0xc2, 0x1b, 0x2e, 0x32, 0xf7, 0x6e, 0x5b, 0xa3, 0x28, 0x30, 0xc9, 0x50, 0xb8, 0x29, 0x8b,
+	0x44, 0x1e, 0x9b, 0xdf, 0x42, 0xf6, 0xdb, 0xfd, 0x71, 0x96, 0xe5, 0xea, 0xae, 0x89, 0xdc, 0x98,
+	0x97, 0xde, 0x23, 0xda, 0x33, 0xb4, 0x67, 0x68, 0xaf, 0xa3, 0xa3, 0xb1, 0x3e, 0xbf, 0xfb, 0x33,
+	0x00, 0x41, 0xa5, 0x96, 0xb1, 0x6b, 0x03, 0x00, 0x00,
 }
@@ -35,13 +35,19 @@ It has these top-level messages:
 	GetState
 	PutState
 	DelState
+	GetStateMetadata
+	PutStateMetadata
 	GetStateByRange
 	GetQueryResult
+	QueryMetadata
 	GetHistoryForKey
 	QueryStateNext
 	QueryStateClose
 	QueryResultBytes
 	QueryResponse
+	QueryResponseMetadata
+	StateMetadata
+	StateMetadataResult
 	AnchorPeers
 	AnchorPeer
 	APIResource
@@ -57,6 +63,7 @@ It has these top-level messages:
 	FilteredChaincodeAction
 	SignedEvent
 	Event
+	FilteredBlockAndPrivateData
 	DeliverResponse
 	PeerID
 	PeerEndpoint
@@ -510,7 +517,7 @@ var _Admin_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("peer/admin.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 466 bytes of a gzipped FileDescriptorProto
+	// 463 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x53, 0xdd, 0x6e, 0xd3, 0x30,
 	0x14, 0x6e, 0x0b, 0xed, 0xc8, 0xe9, 0xd8, 0x82, 0x41, 0x50, 0x75, 0x42, 0xa0, 0x5c, 0xc1, 0x8d,
 	0x23, 0x8a, 0xd0, 0xae, 0xb8, 0x68, 0x49, 0x18, 0x88, 0x2d, 0xad, 0x9c, 0x55, 0x08, 0x24, 0x34,
@@ -539,6 +546,5 @@ var fileDescriptor0 = []byte{
 	0x71, 0x7a, 0x75, 0x93, 0xa3, 0x12, 0xb8, 0xe2, 0xa8, 0xe8, 0x65, 0xb2, 0x50, 0xeb, 0x65, 0xf3,
 	0x64, 0xb5, 0x3e, 0x93, 0x7d, 0x53, 0xe1, 0x59, 0xb2, 0xfc, 0x99, 0x70, 0xfc, 0xfe, 0x9a, 0xaf,
 	0xf5, 0x55, 0xb9, 0xa8, 0x5e, 0xf1, 0xef, 0x10, 0x7d, 0x4b, 0xb4, 0xfb, 0x54, 0xf8, 0x15, 0x71,
-	0x61, 0x77, 0xed, 0xed, 0x9f, 0x00, 0x00, 0x00, 0xff, 0xff, 0xaf, 0x20, 0x26, 0x0b, 0x86, 0x03,
-	0x00, 0x00,
+	0x61, 0x77, 0xed, 0xed, 0x9f, 0x01, 0x00, 0xaf, 0x20, 0x26, 0x0b, 0x86, 0x03, 0x00, 0x00,
 }
@@ -282,45 +282,45 @@ func init() {
 func init() { proto.RegisterFile("peer/chaincode.proto", fileDescriptor1) }
 
 var fileDescriptor1 = []byte{
-	// 632 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x54, 0x6d, 0x6f, 0xda, 0x3c,
-	0x14, 0x6d, 0x80, 0xbe, 0xdd, 0x50, 0x94, 0xc7, 0x0f, 0xcf, 0x33, 0xd4, 0x4f, 0x2c, 0xd2, 0x34,
-	0x36, 0x4d, 0x41, 0x62, 0xd5, 0x36, 0x4d, 0x53, 0x25, 0x4a, 0xd2, 0x2a, 0x1d, 0x83, 0xca, 0x6d,
-	0x27, 0x6d, 0x5f, 0x50, 0xea, 0x5c, 0xc0, 0x6a, 0x70, 0xa2, 0x60, 0xa2, 0xe6, 0x67, 0xec, 0x97,
-	0xec, 0x27, 0x6e, 0xb2, 0x53, 0x5e, 0xba, 0xf6, 0xdb, 0x3e, 0x71, 0x7d, 0x7c, 0x7c, 0xee, 0x3d,
-	0x07, 0xc7, 0x50, 0x4f, 0x10, 0xd3, 0x36, 0x9b, 0x06, 0x5c, 0xb0, 0x38, 0x44, 0x27, 0x49, 0x63,
-	0x19, 0x93, 0x1d, 0xfd, 0x33, 0xb7, 0x87, 0x60, 0xf6, 0x96, 0x5b, 0xbe, 0x4b, 0x08, 0x54, 0x92,
-	0x40, 0x4e, 0x1b, 0x46, 0xd3, 0x68, 0xed, 0x53, 0x5d, 0x2b, 0x4c, 0x04, 0x33, 0x6c, 0x94, 0x0a,
-	0x4c, 0xd5, 0xa4, 0x01, 0xbb, 0x19, 0xa6, 0x73, 0x1e, 0x8b, 0x46, 0x59, 0xc3, 0xcb, 0xa5, 0xfd,
-	0xd3, 0x80, 0xda, 0x5a, 0x51, 0x24, 0x0b, 0xa9, 0x04, 0x82, 0x74, 0x32, 0x6f, 0x18, 0xcd, 0x72,
-	0xab, 0x4a, 0x75, 0x4d, 0x7c, 0x30, 0x43, 0x64, 0x71, 0x1a, 0x48, 0x1e, 0x8b, 0x79, 0xa3, 0xd4,
-	0x2c, 0xb7, 0xcc, 0xce, 0xcb, 0x62, 0xb8, 0xb9, 0xf3, 0x50, 0xc0, 0x71, 0xd7, 0x4c, 0x4f, 0xc8,
-	0x34, 0xa7, 0x9b, 0x67, 0x0f, 0x8f, 0xc1, 0xfa, 0x93, 0x40, 0x2c, 0x28, 0xdf, 0x62, 0x7e, 0x6f,
-	0x43, 0x95, 0xa4, 0x0e, 0xdb, 0x59, 0x10, 0x2d, 0x0a, 0x1b, 0x55, 0x5a, 0x2c, 0x3e, 0x96, 0x3e,
+	// 629 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x54, 0x5d, 0x6f, 0xda, 0x4a,
+	0x10, 0x8d, 0x81, 0x7c, 0x8d, 0x09, 0xf2, 0xdd, 0xcb, 0xbd, 0x45, 0x79, 0xa2, 0x96, 0xaa, 0xd2,
+	0xaa, 0x32, 0x12, 0x8d, 0xda, 0xaa, 0xaa, 0x22, 0x11, 0xec, 0x44, 0x4e, 0x29, 0x44, 0x9b, 0xa4,
+	0x52, 0xfb, 0x82, 0x9c, 0xf5, 0x00, 0xab, 0x98, 0xb5, 0x65, 0x16, 0x2b, 0xfe, 0x19, 0xfd, 0x25,
+	0xfd, 0x89, 0xad, 0x76, 0x1d, 0x3e, 0xd2, 0xe4, 0xad, 0x4f, 0xcc, 0xcc, 0x9e, 0x3d, 0x33, 0xe7,
+	0x30, 0x5e, 0xa8, 0x27, 0x88, 0x69, 0x9b, 0x4d, 0x03, 0x2e, 0x58, 0x1c, 0xa2, 0x93, 0xa4, 0xb1,
+	0x8c, 0xc9, 0x8e, 0xfe, 0x99, 0xdb, 0x43, 0x30, 0x7b, 0xcb, 0x23, 0xdf, 0x25, 0x04, 0x2a, 0x49,
+	0x20, 0xa7, 0x0d, 0xa3, 0x69, 0xb4, 0xf6, 0xa9, 0x8e, 0x55, 0x4d, 0x04, 0x33, 0x6c, 0x94, 0x8a,
+	0x9a, 0x8a, 0x49, 0x03, 0x76, 0x33, 0x4c, 0xe7, 0x3c, 0x16, 0x8d, 0xb2, 0x2e, 0x2f, 0x53, 0xfb,
+	0xa7, 0x01, 0xb5, 0x35, 0xa3, 0x48, 0x16, 0x52, 0x11, 0x04, 0xe9, 0x64, 0xde, 0x30, 0x9a, 0xe5,
+	0x56, 0x95, 0xea, 0x98, 0xf8, 0x60, 0x86, 0xc8, 0xe2, 0x34, 0x90, 0x3c, 0x16, 0xf3, 0x46, 0xa9,
+	0x59, 0x6e, 0x99, 0x9d, 0x97, 0xc5, 0x70, 0x73, 0xe7, 0x21, 0x81, 0xe3, 0xae, 0x91, 0x9e, 0x90,
+	0x69, 0x4e, 0x37, 0xef, 0x1e, 0x1e, 0x83, 0xf5, 0x27, 0x80, 0x58, 0x50, 0xbe, 0xc5, 0xfc, 0x5e,
+	0x86, 0x0a, 0x49, 0x1d, 0xb6, 0xb3, 0x20, 0x5a, 0x14, 0x32, 0xaa, 0xb4, 0x48, 0x3e, 0x96, 0x3e,
 	0x18, 0xf6, 0x2f, 0x03, 0x0e, 0x56, 0x0d, 0x2f, 0x13, 0x64, 0xc4, 0x81, 0x8a, 0xcc, 0x13, 0xd4,
-	0xc7, 0x6b, 0x9d, 0xc3, 0x47, 0x53, 0x29, 0x92, 0x73, 0x95, 0x27, 0x48, 0x35, 0x8f, 0xbc, 0x83,
-	0xea, 0x2a, 0xdf, 0x11, 0x0f, 0x75, 0x0b, 0xb3, 0xf3, 0xef, 0x63, 0x37, 0x2e, 0x35, 0x57, 0x44,
-	0x3f, 0x24, 0x6f, 0x60, 0x9b, 0x2b, 0x83, 0x3a, 0x43, 0xb3, 0xf3, 0xff, 0xd3, 0xf6, 0x69, 0x41,
-	0x52, 0x99, 0x4b, 0x3e, 0xc3, 0x78, 0x21, 0x1b, 0x95, 0xa6, 0xd1, 0xda, 0xa6, 0xcb, 0xa5, 0x7d,
-	0x0c, 0x15, 0x35, 0x0d, 0x39, 0x80, 0xfd, 0xeb, 0x81, 0xeb, 0x9d, 0xfa, 0x03, 0xcf, 0xb5, 0xb6,
-	0x08, 0xc0, 0xce, 0xd9, 0xb0, 0xdf, 0x1d, 0x9c, 0x59, 0x06, 0xd9, 0x83, 0xca, 0x60, 0xe8, 0x7a,
-	0x56, 0x89, 0xec, 0x42, 0xb9, 0xd7, 0xa5, 0x56, 0x59, 0x41, 0xe7, 0xdd, 0xaf, 0x5d, 0xab, 0x62,
-	0xff, 0x28, 0xc1, 0xb3, 0x55, 0x4f, 0x17, 0x93, 0x28, 0xce, 0x67, 0x28, 0xa4, 0xce, 0xe2, 0x13,
-	0xd4, 0xd6, 0xde, 0xe6, 0x09, 0x32, 0x9d, 0x8a, 0xd9, 0xf9, 0xef, 0xc9, 0x54, 0xe8, 0x01, 0x7b,
-	0x90, 0xe4, 0x73, 0xa8, 0xea, 0x83, 0x49, 0xc0, 0x6e, 0x83, 0x09, 0x6a, 0xa3, 0x55, 0x6a, 0x2a,
-	0xec, 0xa2, 0x80, 0xc8, 0x10, 0xf6, 0xf0, 0x0e, 0xd9, 0x08, 0x45, 0xa6, 0x7d, 0xd5, 0x3a, 0x47,
-	0x8f, 0xa4, 0x1f, 0xce, 0xe4, 0x78, 0x77, 0xc8, 0x16, 0xea, 0xdf, 0xf6, 0x44, 0xc6, 0xd3, 0x58,
-	0xa8, 0x0d, 0xba, 0xab, 0x54, 0x3c, 0x91, 0xd9, 0x0e, 0xd4, 0x9f, 0x22, 0xa8, 0x38, 0xdc, 0x61,
-	0xef, 0xb3, 0x47, 0x8b, 0x68, 0x2e, 0xbf, 0x5d, 0x5e, 0x79, 0x5f, 0x2c, 0xe3, 0xbc, 0xb2, 0x57,
-	0xb2, 0xca, 0xb4, 0x86, 0xe3, 0x31, 0x32, 0xc9, 0x33, 0x1c, 0x85, 0x81, 0x44, 0x3b, 0xd9, 0x88,
-	0xc4, 0x17, 0x59, 0xcc, 0xf4, 0xf5, 0xfa, 0xfb, 0x48, 0xee, 0xdb, 0xfd, 0xc3, 0xc3, 0xd1, 0x04,
-	0x05, 0x16, 0xb7, 0x76, 0x14, 0x44, 0x13, 0xfb, 0x3d, 0xd4, 0xfa, 0x7c, 0x8c, 0x2c, 0x67, 0x11,
-	0x7a, 0x99, 0x9a, 0xf8, 0xc5, 0x66, 0x23, 0xfd, 0x0d, 0x16, 0x17, 0x7a, 0xad, 0x38, 0x08, 0x66,
-	0xf8, 0xfa, 0x08, 0xea, 0xbd, 0x58, 0x8c, 0x79, 0x88, 0x42, 0xf2, 0x20, 0xe2, 0x32, 0xef, 0x63,
-	0x86, 0x91, 0x32, 0x79, 0x71, 0x7d, 0xd2, 0xf7, 0x7b, 0xd6, 0x16, 0xb1, 0xa0, 0xda, 0x1b, 0x0e,
-	0x4e, 0x7d, 0xd7, 0x1b, 0x5c, 0xf9, 0xdd, 0xbe, 0x65, 0x9c, 0x0c, 0xc1, 0x8e, 0xd3, 0x89, 0x33,
-	0xcd, 0x13, 0x4c, 0x23, 0x0c, 0x27, 0x98, 0x3a, 0xe3, 0xe0, 0x26, 0xe5, 0x6c, 0xe9, 0x42, 0xbd,
-	0x1b, 0xdf, 0x5f, 0x4d, 0xb8, 0x9c, 0x2e, 0x6e, 0x1c, 0x16, 0xcf, 0xda, 0x1b, 0xd4, 0x76, 0x41,
-	0x6d, 0x17, 0xd4, 0xb6, 0xa2, 0xde, 0x14, 0x4f, 0xca, 0xdb, 0xdf, 0x01, 0x00, 0x00, 0xff, 0xff,
-	0xea, 0x1f, 0x3b, 0xe4, 0x71, 0x04, 0x00, 0x00,
+	0xd7, 0x6b, 0x9d, 0xc3, 0x47, 0x53, 0x29, 0x90, 0x73, 0x95, 0x27, 0x48, 0x35, 0x8e, 0xbc, 0x83,
+	0xea, 0xca, 0xdf, 0x11, 0x0f, 0x75, 0x0b, 0xb3, 0xf3, 0xef, 0x63, 0x35, 0x2e, 0x35, 0x57, 0x40,
+	0x3f, 0x24, 0x6f, 0x60, 0x9b, 0x2b, 0x81, 0xda, 0x43, 0xb3, 0xf3, 0xff, 0xd3, 0xf2, 0x69, 0x01,
+	0x52, 0x9e, 0x4b, 0x3e, 0xc3, 0x78, 0x21, 0x1b, 0x95, 0xa6, 0xd1, 0xda, 0xa6, 0xcb, 0xd4, 0x3e,
+	0x86, 0x8a, 0x9a, 0x86, 0x1c, 0xc0, 0xfe, 0xf5, 0xc0, 0xf5, 0x4e, 0xfd, 0x81, 0xe7, 0x5a, 0x5b,
+	0x04, 0x60, 0xe7, 0x6c, 0xd8, 0xef, 0x0e, 0xce, 0x2c, 0x83, 0xec, 0x41, 0x65, 0x30, 0x74, 0x3d,
+	0xab, 0x44, 0x76, 0xa1, 0xdc, 0xeb, 0x52, 0xab, 0xac, 0x4a, 0xe7, 0xdd, 0xaf, 0x5d, 0xab, 0x62,
+	0xff, 0x28, 0xc1, 0xb3, 0x55, 0x4f, 0x17, 0x93, 0x28, 0xce, 0x67, 0x28, 0xa4, 0xf6, 0xe2, 0x13,
+	0xd4, 0xd6, 0xda, 0xe6, 0x09, 0x32, 0xed, 0x8a, 0xd9, 0xf9, 0xef, 0x49, 0x57, 0xe8, 0x01, 0xdb,
+	0x4c, 0xc9, 0x73, 0xa8, 0xea, 0x8b, 0x49, 0xc0, 0x6e, 0x83, 0x09, 0x6a, 0xa1, 0x55, 0x6a, 0xaa,
+	0xda, 0x45, 0x51, 0x22, 0x43, 0xd8, 0xc3, 0x3b, 0x64, 0x23, 0x14, 0x99, 0xd6, 0x55, 0xeb, 0x1c,
+	0x3d, 0xa2, 0x7e, 0x38, 0x93, 0xe3, 0xdd, 0x21, 0x5b, 0xa8, 0x7f, 0xdb, 0x13, 0x19, 0x4f, 0x63,
+	0xa1, 0x0e, 0xe8, 0xae, 0x62, 0xf1, 0x44, 0x66, 0x3b, 0x50, 0x7f, 0x0a, 0xa0, 0xec, 0x70, 0x87,
+	0xbd, 0xcf, 0x1e, 0x2d, 0xac, 0xb9, 0xfc, 0x76, 0x79, 0xe5, 0x7d, 0xb1, 0x8c, 0xf3, 0xca, 0x5e,
+	0xc9, 0x2a, 0xd3, 0x1a, 0x8e, 0xc7, 0xc8, 0x24, 0xcf, 0x70, 0x14, 0x06, 0x12, 0xed, 0x64, 0xc3,
+	0x12, 0x5f, 0x64, 0x31, 0xd3, 0xeb, 0xf5, 0xf7, 0x96, 0xdc, 0xb7, 0xfb, 0x87, 0x87, 0xa3, 0x09,
+	0x0a, 0x2c, 0xb6, 0x76, 0x14, 0x44, 0x13, 0xfb, 0x3d, 0xd4, 0xfa, 0x7c, 0x8c, 0x2c, 0x67, 0x11,
+	0x7a, 0x99, 0x9a, 0xf8, 0xc5, 0x66, 0x23, 0xfd, 0x0d, 0x16, 0x0b, 0xbd, 0x66, 0x1c, 0x04, 0x33,
+	0x7c, 0x7d, 0x04, 0xf5, 0x5e, 0x2c, 0xc6, 0x3c, 0x44, 0x21, 0x79, 0x10, 0x71, 0x99, 0xf7, 0x31,
+	0xc3, 0x48, 0x89, 0xbc, 0xb8, 0x3e, 0xe9, 0xfb, 0x3d, 0x6b, 0x8b, 0x58, 0x50, 0xed, 0x0d, 0x07,
+	0xa7, 0xbe, 0xeb, 0x0d, 0xae, 0xfc, 0x6e, 0xdf, 0x32, 0x4e, 0x86, 0x60, 0xc7, 0xe9, 0xc4, 0x99,
+	0xe6, 0x09, 0xa6, 0x11, 0x86, 0x13, 0x4c, 0x9d, 0x71, 0x70, 0x93, 0x72, 0xb6, 0x54, 0xa1, 0xde,
+	0x8d, 0xef, 0xaf, 0x26, 0x5c, 0x4e, 0x17, 0x37, 0x0e, 0x8b, 0x67, 0xed, 0x0d, 0x68, 0xbb, 0x80,
+	0xb6, 0x0b, 0x68, 0x5b, 0x41, 0x6f, 0x8a, 0x27, 0xe5, 0xed, 0xef, 0x01, 0x00, 0xea, 0x1f, 0x3b,
+	0xe4, 0x71, 0x04, 0x00, 0x00,
 }
@@ -18,6 +18,24 @@ var _ = proto.Marshal
 var _ = fmt.Errorf
 var _ = math.Inf
 
+type MetaDataKeys int32
+
+const (
+	MetaDataKeys_VALIDATION_PARAMETER MetaDataKeys = 0
+)
+
+var MetaDataKeys_name = map[int32]string{
+	0: "VALIDATION_PARAMETER",
+}
+var MetaDataKeys_value = map[string]int32{
+	"VALIDATION_PARAMETER": 0,
+}
+
+func (x MetaDataKeys) String() string {
+	return proto.EnumName(MetaDataKeys_name, int32(x))
+}
+func (MetaDataKeys) EnumDescriptor() ([]byte, []int) { return fileDescriptor3, []int{0} }
+
 type ChaincodeMessage_Type int32
 
 const (
@@ -40,6 +58,8 @@ const (
 	ChaincodeMessage_QUERY_STATE_CLOSE   ChaincodeMessage_Type = 17
 	ChaincodeMessage_KEEPALIVE           ChaincodeMessage_Type = 18
 	ChaincodeMessage_GET_HISTORY_FOR_KEY ChaincodeMessage_Type = 19
+	ChaincodeMessage_GET_STATE_METADATA  ChaincodeMessage_Type = 20
+	ChaincodeMessage_PUT_STATE_METADATA  ChaincodeMessage_Type = 21
 )
 
 var ChaincodeMessage_Type_name = map[int32]string{
@@ -62,6 +82,8 @@ var ChaincodeMessage_Type_name = map[int32]string{
 	17: "QUERY_STATE_CLOSE",
 	18: "KEEPALIVE",
 	19: "GET_HISTORY_FOR_KEY",
+	20: "GET_STATE_METADATA",
+	21: "PUT_STATE_METADATA",
 }
 var ChaincodeMessage_Type_value = map[string]int32{
 	"UNDEFINED":           0,
@@ -83,6 +105,8 @@ var ChaincodeMessage_Type_value = map[string]int32{
 	"QUERY_STATE_CLOSE":   17,
 	"KEEPALIVE":           18,
 	"GET_HISTORY_FOR_KEY": 19,
+	"GET_STATE_METADATA":  20,
+	"PUT_STATE_METADATA":  21,
 }
 
 func (x ChaincodeMessage_Type) String() string {
@@ -238,16 +262,73 @@ func (m *DelState) GetCollection() string {
 	return ""
 }
 
+type GetStateMetadata struct {
+	Key        string `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
+	Collection string `protobuf:"bytes,2,opt,name=collection" json:"collection,omitempty"`
+}
+
+func (m *GetStateMetadata) Reset()                    { *m = GetStateMetadata{} }
+func (m *GetStateMetadata) String() string            { return proto.CompactTextString(m) }
+func (*GetStateMetadata) ProtoMessage()               {}
+func (*GetStateMetadata) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{4} }
+
+func (m *GetStateMetadata) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *GetStateMetadata) GetCollection() string {
+	if m != nil {
+		return m.Collection
+	}
+	return ""
+}
+
+type PutStateMetadata struct {
+	Key        string         `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
+	Collection string         `protobuf:"bytes,2,opt,name=collection" json:"collection,omitempty"`
+	Metadata   *StateMetadata `protobuf:"bytes,3,opt,name=metadata" json:"metadata,omitempty"`
+}
+
+func (m *PutStateMetadata) Reset()                    { *m = PutStateMetadata{} }
+func (m *PutStateMetadata) String() string            { return proto.CompactTextString(m) }
+func (*PutStateMetadata) ProtoMessage()               {}
+func (*PutStateMetadata) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{5} }
+
+func (m *PutStateMetadata) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *PutStateMetadata) GetCollection() string {
+	if m != nil {
+		return m.Collection
+	}
+	return ""
+}
+
+func (m *PutStateMetadata) GetMetadata() *StateMetadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
 type GetStateByRange struct {
 	StartKey   string `protobuf:"bytes,1,opt,name=startKey" json:"startKey,omitempty"`
 	EndKey     string `protobuf:"bytes,2,opt,name=endKey" json:"endKey,omitempty"`
 	Collection string `protobuf:"bytes,3,opt,name=collection" json:"collection,omitempty"`
+	Metadata   []byte `protobuf:"bytes,4,opt,name=metadata,proto3" json:"metadata,omitempty"`
 }
 
 func (m *GetStateByRange) Reset()                    { *m = GetStateByRange{} }
 func (m *GetStateByRange) String() string            { return proto.CompactTextString(m) }
 func (*GetStateByRange) ProtoMessage()               {}
-func (*GetStateByRange) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{4} }
+func (*GetStateByRange) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{6} }
 
 func (m *GetStateByRange) GetStartKey() string {
 	if m != nil {
@@ -270,15 +351,23 @@ func (m *GetStateByRange) GetCollection() string {
 	return ""
 }
 
+func (m *GetStateByRange) GetMetadata() []byte {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
 type GetQueryResult struct {
 	Query      string `protobuf:"bytes,1,opt,name=query" json:"query,omitempty"`
 	Collection string `protobuf:"bytes,2,opt,name=collection" json:"collection,omitempty"`
+	Metadata   []byte `protobuf:"bytes,3,opt,name=metadata,proto3" json:"metadata,omitempty"`
 }
 
 func (m *GetQueryResult) Reset()                    { *m = GetQueryResult{} }
 func (m *GetQueryResult) String() string            { return proto.CompactTextString(m) }
 func (*GetQueryResult) ProtoMessage()               {}
-func (*GetQueryResult) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{5} }
+func (*GetQueryResult) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{7} }
 
 func (m *GetQueryResult) GetQuery() string {
 	if m != nil {
@@ -294,6 +383,40 @@ func (m *GetQueryResult) GetCollection() string {
 	return ""
 }
 
+func (m *GetQueryResult) GetMetadata() []byte {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+// QueryMetadata is the metadata of a GetStateByRange or a GetQueryResult.
+// It allows the results of a query to be retrieved in pages of up to
+// pageSize records, resuming from the bookmark returned with the previous page
+type QueryMetadata struct {
+	PageSize int32  `protobuf:"varint,1,opt,name=pageSize" json:"pageSize,omitempty"`
+	Bookmark string `protobuf:"bytes,2,opt,name=bookmark" json:"bookmark,omitempty"`
+}
+
+func (m *QueryMetadata) Reset()                    { *m = QueryMetadata{} }
+func (m *QueryMetadata) String() string            { return proto.CompactTextString(m) }
+func (*QueryMetadata) ProtoMessage()               {}
+func (*QueryMetadata) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{8} }
+
+func (m *QueryMetadata) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *QueryMetadata) GetBookmark() string {
+	if m != nil {
+		return m.Bookmark
+	}
+	return ""
+}
+
 type GetHistoryForKey struct {
 	Key string `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
 }
@@ -301,7 +424,7 @@ type GetHistoryForKey struct {
 func (m *GetHistoryForKey) Reset()                    { *m = GetHistoryForKey{} }
 func (m *GetHistoryForKey) String() string            { return proto.CompactTextString(m) }
 func (*GetHistoryForKey) ProtoMessage()               {}
-func (*GetHistoryForKey) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{6} }
+func (*GetHistoryForKey) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{9} }
 
 func (m *GetHistoryForKey) GetKey() string {
 	if m != nil {
@@ -317,7 +440,7 @@ type QueryStateNext struct {
 func (m *QueryStateNext) Reset()                    { *m = QueryStateNext{} }
 func (m *QueryStateNext) String() string            { return proto.CompactTextString(m) }
 func (*QueryStateNext) ProtoMessage()               {}
-func (*QueryStateNext) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{7} }
+func (*QueryStateNext) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{10} }
 
 func (m *QueryStateNext) GetId() string {
 	if m != nil {
@@ -333,7 +456,7 @@ type QueryStateClose struct {
 func (m *QueryStateClose) Reset()                    { *m = QueryStateClose{} }
 func (m *QueryStateClose) String() string            { return proto.CompactTextString(m) }
 func (*QueryStateClose) ProtoMessage()               {}
-func (*QueryStateClose) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{8} }
+func (*QueryStateClose) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{11} }
 
 func (m *QueryStateClose) GetId() string {
 	if m != nil {
@@ -349,7 +472,7 @@ type QueryResultBytes struct {
 func (m *QueryResultBytes) Reset()                    { *m = QueryResultBytes{} }
 func (m *QueryResultBytes) String() string            { return proto.CompactTextString(m) }
 func (*QueryResultBytes) ProtoMessage()               {}
-func (*QueryResultBytes) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{9} }
+func (*QueryResultBytes) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{12} }
 
 func (m *QueryResultBytes) GetResultBytes() []byte {
 	if m != nil {
@@ -359,15 +482,16 @@ func (m *QueryResultBytes) GetResultBytes() []byte {
 }
 
 type QueryResponse struct {
-	Results []*QueryResultBytes `protobuf:"bytes,1,rep,name=results" json:"results,omitempty"`
-	HasMore bool                `protobuf:"varint,2,opt,name=has_more,json=hasMore" json:"has_more,omitempty"`
-	Id      string              `protobuf:"bytes,3,opt,name=id" json:"id,omitempty"`
+	Results  []*QueryResultBytes `protobuf:"bytes,1,rep,name=results" json:"results,omitempty"`
+	HasMore  bool                `protobuf:"varint,2,opt,name=has_more,json=hasMore" json:"has_more,omitempty"`
+	Id       string              `protobuf:"bytes,3,opt,name=id" json:"id,omitempty"`
+	Metadata []byte              `protobuf:"bytes,4,opt,name=metadata,proto3" json:"metadata,omitempty"`
 }
 
 func (m *QueryResponse) Reset()                    { *m = QueryResponse{} }
 func (m *QueryResponse) String() string            { return proto.CompactTextString(m) }
 func (*QueryResponse) ProtoMessage()               {}
-func (*QueryResponse) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{10} }
+func (*QueryResponse) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{13} }
 
 func (m *QueryResponse) GetResults() []*QueryResultBytes {
 	if m != nil {
@@ -390,18 +514,98 @@ func (m *QueryResponse) GetId() string {
 	return ""
 }
 
+func (m *QueryResponse) GetMetadata() []byte {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+// QueryResponseMetadata is the metadata of a QueryResponse. It contains the count
+// of the records that were fetched and the bookmark to use for fetching the next page
+type QueryResponseMetadata struct {
+	FetchedRecordsCount int32  `protobuf:"varint,1,opt,name=fetched_records_count,json=fetchedRecordsCount" json:"fetched_records_count,omitempty"`
+	Bookmark            string `protobuf:"bytes,2,opt,name=bookmark" json:"bookmark,omitempty"`
+}
+
+func (m *QueryResponseMetadata) Reset()                    { *m = QueryResponseMetadata{} }
+func (m *QueryResponseMetadata) String() string            { return proto.CompactTextString(m) }
+func (*QueryResponseMetadata) ProtoMessage()               {}
+func (*QueryResponseMetadata) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{14} }
+
+func (m *QueryResponseMetadata) GetFetchedRecordsCount() int32 {
+	if m != nil {
+		return m.FetchedRecordsCount
+	}
+	return 0
+}
+
+func (m *QueryResponseMetadata) GetBookmark() string {
+	if m != nil {
+		return m.Bookmark
+	}
+	return ""
+}
+
+type StateMetadata struct {
+	Metakey string `protobuf:"bytes,1,opt,name=metakey" json:"metakey,omitempty"`
+	Value   []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (m *StateMetadata) Reset()                    { *m = StateMetadata{} }
+func (m *StateMetadata) String() string            { return proto.CompactTextString(m) }
+func (*StateMetadata) ProtoMessage()               {}
+func (*StateMetadata) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{15} }
+
+func (m *StateMetadata) GetMetakey() string {
+	if m != nil {
+		return m.Metakey
+	}
+	return ""
+}
+
+func (m *StateMetadata) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+type StateMetadataResult struct {
+	Entries []*StateMetadata `protobuf:"bytes,1,rep,name=entries" json:"entries,omitempty"`
+}
+
+func (m *StateMetadataResult) Reset()                    { *m = StateMetadataResult{} }
+func (m *StateMetadataResult) String() string            { return proto.CompactTextString(m) }
+func (*StateMetadataResult) ProtoMessage()               {}
+func (*StateMetadataResult) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{16} }
+
+func (m *StateMetadataResult) GetEntries() []*StateMetadata {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*ChaincodeMessage)(nil), "protos.ChaincodeMessage")
 	proto.RegisterType((*GetState)(nil), "protos.GetState")
 	proto.RegisterType((*PutState)(nil), "protos.PutState")
 	proto.RegisterType((*DelState)(nil), "protos.DelState")
+	proto.RegisterType((*GetStateMetadata)(nil), "protos.GetStateMetadata")
+	proto.RegisterType((*PutStateMetadata)(nil), "protos.PutStateMetadata")
 	proto.RegisterType((*GetStateByRange)(nil), "protos.GetStateByRange")
 	proto.RegisterType((*GetQueryResult)(nil), "protos.GetQueryResult")
+	proto.RegisterType((*QueryMetadata)(nil), "protos.QueryMetadata")
 	proto.RegisterType((*GetHistoryForKey)(nil), "protos.GetHistoryForKey")
 	proto.RegisterType((*QueryStateNext)(nil), "protos.QueryStateNext")
 	proto.RegisterType((*QueryStateClose)(nil), "protos.QueryStateClose")
 	proto.RegisterType((*QueryResultBytes)(nil), "protos.QueryResultBytes")
 	proto.RegisterType((*QueryResponse)(nil), "protos.QueryResponse")
+	proto.RegisterType((*QueryResponseMetadata)(nil), "protos.QueryResponseMetadata")
+	proto.RegisterType((*StateMetadata)(nil), "protos.StateMetadata")
+	proto.RegisterType((*StateMetadataResult)(nil), "protos.StateMetadataResult")
+	proto.RegisterEnum("protos.MetaDataKeys", MetaDataKeys_name, MetaDataKeys_value)
 	proto.RegisterEnum("protos.ChaincodeMessage_Type", ChaincodeMessage_Type_name, ChaincodeMessage_Type_value)
 }
 
@@ -512,57 +716,71 @@ var _ChaincodeSupport_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("peer/chaincode_shim.proto", fileDescriptor3) }
 
 var fileDescriptor3 = []byte{
-	// 825 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x95, 0x51, 0x6f, 0xe2, 0x46,
-	0x10, 0xc7, 0x8f, 0x00, 0xc1, 0x0c, 0x09, 0xec, 0x6d, 0xae, 0xa9, 0x0f, 0xe9, 0x5a, 0x8a, 0xfa,
-	0x40, 0x5f, 0xa0, 0xa5, 0x7d, 0xe8, 0xc3, 0x49, 0x15, 0xc1, 0x1b, 0x62, 0x85, 0xd8, 0xdc, 0xda,
-	0x39, 0x1d, 0x7d, 0xb1, 0x1c, 0xbc, 0x67, 0xac, 0x1a, 0xaf, 0x6b, 0x2f, 0xa7, 0xf3, 0x67, 0xe8,
-	0x07, 0xeb, 0xd7, 0xaa, 0xd6, 0xc6, 0x84, 0x23, 0x8a, 0x4e, 0xea, 0x13, 0xfe, 0xcf, 0xfc, 0x66,
-	0xe6, 0x3f, 0xd6, 0xb2, 0x86, 0xd7, 0x31, 0x63, 0xc9, 0x68, 0xb5, 0x76, 0x83, 0x68, 0xc5, 0x3d,
-	0xe6, 0xa4, 0xeb, 0x60, 0x33, 0x8c, 0x13, 0x2e, 0x38, 0x3e, 0xcd, 0x7f, 0xd2, 0x6e, 0xf7, 0x08,
-	0x61, 0x9f, 0x58, 0x24, 0x0a, 0xa6, 0x7b, 0x91, 0xe7, 0xe2, 0x84, 0xc7, 0x3c, 0x75, 0xc3, 0x5d,
-	0xf0, 0x7b, 0x9f, 0x73, 0x3f, 0x64, 0xa3, 0x5c, 0x3d, 0x6c, 0x3f, 0x8e, 0x44, 0xb0, 0x61, 0xa9,
-	0x70, 0x37, 0x71, 0x01, 0xf4, 0xff, 0xa9, 0x03, 0x9a, 0x96, 0xfd, 0xee, 0x58, 0x9a, 0xba, 0x3e,
-	0xc3, 0xbf, 0x40, 0x4d, 0x64, 0x31, 0x53, 0x2b, 0xbd, 0xca, 0xa0, 0x3d, 0x7e, 0x53, 0xa0, 0xe9,
-	0xf0, 0x98, 0x1b, 0xda, 0x59, 0xcc, 0x68, 0x8e, 0xe2, 0xdf, 0xa1, 0xb9, 0x6f, 0xad, 0x9e, 0xf4,
-	0x2a, 0x83, 0xd6, 0xb8, 0x3b, 0x2c, 0x86, 0x0f, 0xcb, 0xe1, 0x43, 0xbb, 0x24, 0xe8, 0x23, 0x8c,
-	0x55, 0x68, 0xc4, 0x6e, 0x16, 0x72, 0xd7, 0x53, 0xab, 0xbd, 0xca, 0xe0, 0x8c, 0x96, 0x12, 0x63,
-	0xa8, 0x89, 0xcf, 0x81, 0xa7, 0xd6, 0x7a, 0x95, 0x41, 0x93, 0xe6, 0xcf, 0x78, 0x0c, 0x4a, 0xb9,
-	0xa2, 0x5a, 0xcf, 0xc7, 0x5c, 0x96, 0xf6, 0xac, 0xc0, 0x8f, 0x98, 0xb7, 0xd8, 0x65, 0xe9, 0x9e,
-	0xc3, 0x7f, 0x40, 0xe7, 0xe8, 0x95, 0xa9, 0xa7, 0x5f, 0x96, 0xee, 0x37, 0x23, 0x32, 0x4b, 0xdb,
-	0xab, 0x2f, 0x34, 0x7e, 0x03, 0xb0, 0x5a, 0xbb, 0x51, 0xc4, 0x42, 0x27, 0xf0, 0xd4, 0x46, 0x6e,
-	0xa7, 0xb9, 0x8b, 0xe8, 0x5e, 0xff, 0xdf, 0x13, 0xa8, 0xc9, 0x57, 0x81, 0xcf, 0xa1, 0x79, 0x6f,
-	0x68, 0xe4, 0x5a, 0x37, 0x88, 0x86, 0x5e, 0xe0, 0x33, 0x50, 0x28, 0x99, 0xe9, 0x96, 0x4d, 0x28,
-	0xaa, 0xe0, 0x36, 0x40, 0xa9, 0x88, 0x86, 0x4e, 0xb0, 0x02, 0x35, 0xdd, 0xd0, 0x6d, 0x54, 0xc5,
-	0x4d, 0xa8, 0x53, 0x32, 0xd1, 0x96, 0xa8, 0x86, 0x3b, 0xd0, 0xb2, 0xe9, 0xc4, 0xb0, 0x26, 0x53,
-	0x5b, 0x37, 0x0d, 0x54, 0x97, 0x2d, 0xa7, 0xe6, 0xdd, 0x62, 0x4e, 0x6c, 0xa2, 0xa1, 0x53, 0x89,
-	0x12, 0x4a, 0x4d, 0x8a, 0x1a, 0x32, 0x33, 0x23, 0xb6, 0x63, 0xd9, 0x13, 0x9b, 0x20, 0x45, 0xca,
-	0xc5, 0x7d, 0x29, 0x9b, 0x52, 0x6a, 0x64, 0xbe, 0x93, 0x80, 0x5f, 0x01, 0xd2, 0x8d, 0xf7, 0xe6,
-	0x2d, 0x71, 0xa6, 0x37, 0x13, 0xdd, 0x98, 0x9a, 0x1a, 0x41, 0xad, 0xc2, 0xa0, 0xb5, 0x30, 0x0d,
-	0x8b, 0xa0, 0x73, 0x7c, 0x09, 0x78, 0xdf, 0xd0, 0xb9, 0x5a, 0x3a, 0x74, 0x62, 0xcc, 0x08, 0x6a,
-	0xcb, 0x5a, 0x19, 0x7f, 0x77, 0x4f, 0xe8, 0xd2, 0xa1, 0xc4, 0xba, 0x9f, 0xdb, 0xa8, 0x23, 0xa3,
-	0x45, 0xa4, 0xe0, 0x0d, 0xf2, 0xc1, 0x46, 0x08, 0x7f, 0x03, 0x2f, 0x0f, 0xa3, 0xd3, 0xb9, 0x69,
-	0x11, 0xf4, 0x52, 0xba, 0xb9, 0x25, 0x64, 0x31, 0x99, 0xeb, 0xef, 0x09, 0xc2, 0xf8, 0x5b, 0xb8,
-	0x90, 0x1d, 0x6f, 0x74, 0xcb, 0x36, 0xe9, 0xd2, 0xb9, 0x36, 0xa9, 0x73, 0x4b, 0x96, 0xe8, 0xa2,
-	0xff, 0x16, 0x94, 0x19, 0x13, 0x96, 0x70, 0x05, 0xc3, 0x08, 0xaa, 0x7f, 0xb1, 0x2c, 0x3f, 0x83,
-	0x4d, 0x2a, 0x1f, 0xf1, 0x77, 0x00, 0x2b, 0x1e, 0x86, 0x6c, 0x25, 0x02, 0x1e, 0xe5, 0x87, 0xac,
-	0x49, 0x0f, 0x22, 0x7d, 0x0a, 0xca, 0x62, 0xfb, 0x6c, 0xf5, 0x2b, 0xa8, 0x7f, 0x72, 0xc3, 0x2d,
-	0xcb, 0x0b, 0xcf, 0x68, 0x21, 0x8e, 0x7a, 0x56, 0x9f, 0xf4, 0x7c, 0x0b, 0x8a, 0xc6, 0xc2, 0xff,
-	0xeb, 0x88, 0x41, 0xa7, 0xdc, 0xe7, 0x2a, 0xa3, 0x6e, 0xe4, 0x33, 0xdc, 0x05, 0x25, 0x15, 0x6e,
-	0x22, 0x6e, 0xf7, 0x9d, 0xf6, 0x1a, 0x5f, 0xc2, 0x29, 0x8b, 0x3c, 0x99, 0x29, 0x5a, 0xed, 0xd4,
-	0x57, 0x4d, 0x5e, 0x43, 0x7b, 0xc6, 0xc4, 0xbb, 0x2d, 0x4b, 0x32, 0xca, 0xd2, 0x6d, 0x28, 0xe4,
-	0xb2, 0x7f, 0x4b, 0xb9, 0x1b, 0x51, 0x88, 0xaf, 0xda, 0xfd, 0x11, 0xd0, 0x8c, 0x89, 0x9b, 0x20,
-	0x15, 0x3c, 0xc9, 0xae, 0x79, 0x22, 0x67, 0x3f, 0x59, 0xba, 0xdf, 0x83, 0x76, 0x3e, 0x2a, 0x5f,
-	0xcb, 0x60, 0x9f, 0x05, 0x6e, 0xc3, 0x49, 0xe0, 0xed, 0x90, 0x93, 0xc0, 0xeb, 0xff, 0x00, 0x9d,
-	0x47, 0x62, 0x1a, 0xf2, 0x94, 0x3d, 0x41, 0x7e, 0x03, 0x74, 0xe0, 0xf7, 0x2a, 0x13, 0x2c, 0xc5,
-	0x3d, 0x68, 0x25, 0x8f, 0x32, 0x87, 0xcf, 0xe8, 0x61, 0xa8, 0x1f, 0xc1, 0x79, 0x59, 0x15, 0xf3,
-	0x28, 0x65, 0x78, 0x0c, 0x8d, 0x22, 0x2f, 0xf1, 0xea, 0xa0, 0x35, 0x56, 0xcb, 0xbf, 0xf4, 0x71,
-	0x77, 0x5a, 0x82, 0xf8, 0x35, 0x28, 0x6b, 0x37, 0x75, 0x36, 0x3c, 0x29, 0xce, 0x82, 0x42, 0x1b,
-	0x6b, 0x37, 0xbd, 0xe3, 0x49, 0xe9, 0xb2, 0x5a, 0xba, 0x1c, 0x7f, 0x38, 0xb8, 0x1c, 0xad, 0x6d,
-	0x1c, 0xf3, 0x44, 0x60, 0x0d, 0x14, 0xca, 0xfc, 0x20, 0x15, 0x2c, 0xc1, 0xea, 0x73, 0x57, 0x63,
-	0xf7, 0xd9, 0x4c, 0xff, 0xc5, 0xa0, 0xf2, 0x73, 0xe5, 0xca, 0x84, 0x3e, 0x4f, 0xfc, 0xe1, 0x3a,
-	0x8b, 0x59, 0x12, 0x32, 0xcf, 0x67, 0xc9, 0xf0, 0xa3, 0xfb, 0x90, 0x04, 0xab, 0xb2, 0x4e, 0xde,
-	0xe6, 0x7f, 0xfe, 0xe4, 0x07, 0x62, 0xbd, 0x7d, 0x18, 0xae, 0xf8, 0x66, 0x74, 0x80, 0x8e, 0x0a,
-	0xb4, 0xb8, 0xd5, 0xd3, 0x91, 0x44, 0x1f, 0x8a, 0x4f, 0xc4, 0xaf, 0xff, 0x05, 0x00, 0x00, 0xff,
-	0xff, 0x18, 0x6a, 0x79, 0xe7, 0x46, 0x06, 0x00, 0x00,
+	// 1041 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x56, 0xcf, 0x73, 0xda, 0x46,
+	0x14, 0x0e, 0x06, 0x8c, 0x78, 0xd8, 0x78, 0xb3, 0xb6, 0x53, 0x85, 0x99, 0xb4, 0x54, 0xd3, 0x03,
+	0xed, 0x01, 0x1a, 0xda, 0x43, 0x0f, 0x99, 0xc9, 0xc8, 0x68, 0x4d, 0x18, 0xf3, 0x2b, 0x2b, 0x39,
+	0x13, 0xf7, 0xa2, 0x11, 0x68, 0x0d, 0x1a, 0x03, 0xab, 0x4a, 0x4b, 0x1a, 0x7a, 0xeb, 0xb5, 0xff,
+	0x52, 0xff, 0xb0, 0x5e, 0x3b, 0xab, 0x5f, 0x06, 0x5c, 0x27, 0x33, 0xe9, 0x09, 0xbe, 0xf7, 0xbe,
+	0x7d, 0xef, 0x7b, 0xef, 0xed, 0x6a, 0x17, 0x9e, 0xfb, 0x8c, 0x05, 0xad, 0xe9, 0xdc, 0xf1, 0x56,
+	0x53, 0xee, 0x32, 0x3b, 0x9c, 0x7b, 0xcb, 0xa6, 0x1f, 0x70, 0xc1, 0xf1, 0x61, 0xf4, 0x13, 0xd6,
+	0x6a, 0x7b, 0x14, 0xf6, 0x81, 0xad, 0x44, 0xcc, 0xa9, 0x9d, 0x46, 0x3e, 0x3f, 0xe0, 0x3e, 0x0f,
+	0x9d, 0x45, 0x62, 0xfc, 0x66, 0xc6, 0xf9, 0x6c, 0xc1, 0x5a, 0x11, 0x9a, 0xac, 0x6f, 0x5b, 0xc2,
+	0x5b, 0xb2, 0x50, 0x38, 0x4b, 0x3f, 0x26, 0x68, 0x7f, 0x17, 0x01, 0x75, 0xd2, 0x78, 0x03, 0x16,
+	0x86, 0xce, 0x8c, 0xe1, 0x97, 0x50, 0x10, 0x1b, 0x9f, 0xa9, 0xb9, 0x7a, 0xae, 0x51, 0x6d, 0xbf,
+	0x88, 0xa9, 0x61, 0x73, 0x9f, 0xd7, 0xb4, 0x36, 0x3e, 0xa3, 0x11, 0x15, 0xff, 0x02, 0xe5, 0x2c,
+	0xb4, 0x7a, 0x50, 0xcf, 0x35, 0x2a, 0xed, 0x5a, 0x33, 0x4e, 0xde, 0x4c, 0x93, 0x37, 0xad, 0x94,
+	0x41, 0xef, 0xc9, 0x58, 0x85, 0x92, 0xef, 0x6c, 0x16, 0xdc, 0x71, 0xd5, 0x7c, 0x3d, 0xd7, 0x38,
+	0xa2, 0x29, 0xc4, 0x18, 0x0a, 0xe2, 0xa3, 0xe7, 0xaa, 0x85, 0x7a, 0xae, 0x51, 0xa6, 0xd1, 0x7f,
+	0xdc, 0x06, 0x25, 0x2d, 0x51, 0x2d, 0x46, 0x69, 0x9e, 0xa5, 0xf2, 0x4c, 0x6f, 0xb6, 0x62, 0xee,
+	0x38, 0xf1, 0xd2, 0x8c, 0x87, 0x5f, 0xc3, 0xc9, 0x5e, 0xcb, 0xd4, 0xc3, 0xdd, 0xa5, 0x59, 0x65,
+	0x44, 0x7a, 0x69, 0x75, 0xba, 0x83, 0xf1, 0x0b, 0x80, 0xe9, 0xdc, 0x59, 0xad, 0xd8, 0xc2, 0xf6,
+	0x5c, 0xb5, 0x14, 0xc9, 0x29, 0x27, 0x96, 0x9e, 0xab, 0xfd, 0x73, 0x00, 0x05, 0xd9, 0x0a, 0x7c,
+	0x0c, 0xe5, 0xeb, 0xa1, 0x41, 0x2e, 0x7b, 0x43, 0x62, 0xa0, 0x27, 0xf8, 0x08, 0x14, 0x4a, 0xba,
+	0x3d, 0xd3, 0x22, 0x14, 0xe5, 0x70, 0x15, 0x20, 0x45, 0xc4, 0x40, 0x07, 0x58, 0x81, 0x42, 0x6f,
+	0xd8, 0xb3, 0x50, 0x1e, 0x97, 0xa1, 0x48, 0x89, 0x6e, 0xdc, 0xa0, 0x02, 0x3e, 0x81, 0x8a, 0x45,
+	0xf5, 0xa1, 0xa9, 0x77, 0xac, 0xde, 0x68, 0x88, 0x8a, 0x32, 0x64, 0x67, 0x34, 0x18, 0xf7, 0x89,
+	0x45, 0x0c, 0x74, 0x28, 0xa9, 0x84, 0xd2, 0x11, 0x45, 0x25, 0xe9, 0xe9, 0x12, 0xcb, 0x36, 0x2d,
+	0xdd, 0x22, 0x48, 0x91, 0x70, 0x7c, 0x9d, 0xc2, 0xb2, 0x84, 0x06, 0xe9, 0x27, 0x10, 0xf0, 0x19,
+	0xa0, 0xde, 0xf0, 0xdd, 0xe8, 0x8a, 0xd8, 0x9d, 0x37, 0x7a, 0x6f, 0xd8, 0x19, 0x19, 0x04, 0x55,
+	0x62, 0x81, 0xe6, 0x78, 0x34, 0x34, 0x09, 0x3a, 0xc6, 0xcf, 0x00, 0x67, 0x01, 0xed, 0x8b, 0x1b,
+	0x9b, 0xea, 0xc3, 0x2e, 0x41, 0x55, 0xb9, 0x56, 0xda, 0xdf, 0x5e, 0x13, 0x7a, 0x63, 0x53, 0x62,
+	0x5e, 0xf7, 0x2d, 0x74, 0x22, 0xad, 0xb1, 0x25, 0xe6, 0x0f, 0xc9, 0x7b, 0x0b, 0x21, 0x7c, 0x0e,
+	0x4f, 0xb7, 0xad, 0x9d, 0xfe, 0xc8, 0x24, 0xe8, 0xa9, 0x54, 0x73, 0x45, 0xc8, 0x58, 0xef, 0xf7,
+	0xde, 0x11, 0x84, 0xf1, 0x57, 0x70, 0x2a, 0x23, 0xbe, 0xe9, 0x99, 0xd6, 0x88, 0xde, 0xd8, 0x97,
+	0x23, 0x6a, 0x5f, 0x91, 0x1b, 0x74, 0xba, 0x2b, 0x61, 0x40, 0x2c, 0xdd, 0xd0, 0x2d, 0x1d, 0x9d,
+	0x49, 0xfb, 0xf8, 0xfa, 0x81, 0xfd, 0x5c, 0x7b, 0x05, 0x4a, 0x97, 0x09, 0x53, 0x38, 0x82, 0x61,
+	0x04, 0xf9, 0x3b, 0xb6, 0x89, 0xf6, 0x6c, 0x99, 0xca, 0xbf, 0xf8, 0x6b, 0x80, 0x29, 0x5f, 0x2c,
+	0xd8, 0x54, 0x78, 0x7c, 0x15, 0x6d, 0xca, 0x32, 0xdd, 0xb2, 0x68, 0x14, 0x94, 0xf1, 0xfa, 0xd1,
+	0xd5, 0x67, 0x50, 0xfc, 0xe0, 0x2c, 0xd6, 0x2c, 0x5a, 0x78, 0x44, 0x63, 0xb0, 0x17, 0x33, 0xff,
+	0x20, 0xe6, 0x2b, 0x50, 0x0c, 0xb6, 0xf8, 0x52, 0x45, 0x06, 0xa0, 0xb4, 0x9e, 0x01, 0x13, 0x8e,
+	0xeb, 0x08, 0xe7, 0x0b, 0xa2, 0xfc, 0x0e, 0x68, 0xbc, 0xfe, 0xbf, 0x51, 0xf0, 0x4b, 0x50, 0x96,
+	0xc9, 0xea, 0xa8, 0xce, 0x4a, 0xfb, 0x3c, 0x3b, 0x69, 0xdb, 0xa1, 0x69, 0x46, 0xd3, 0xfe, 0xcc,
+	0xc1, 0x49, 0xaa, 0xff, 0x62, 0x43, 0x9d, 0xd5, 0x8c, 0xe1, 0x1a, 0x28, 0xa1, 0x70, 0x02, 0x71,
+	0x95, 0x65, 0xcf, 0x30, 0x7e, 0x06, 0x87, 0x6c, 0xe5, 0x4a, 0x4f, 0x9c, 0x3e, 0x41, 0x9f, 0x6b,
+	0xb2, 0x8c, 0x99, 0x49, 0x2b, 0x44, 0xd3, 0xb9, 0xd7, 0x30, 0x81, 0x6a, 0x97, 0x89, 0xb7, 0x6b,
+	0x16, 0x6c, 0x28, 0x0b, 0xd7, 0x0b, 0x21, 0x07, 0xf9, 0x9b, 0x84, 0x49, 0xfa, 0x18, 0x7c, 0xb6,
+	0xfc, 0xda, 0x5e, 0xf9, 0xdb, 0x39, 0xba, 0x70, 0x1c, 0x25, 0xc8, 0xba, 0x5b, 0x03, 0xc5, 0x77,
+	0x66, 0xcc, 0xf4, 0xfe, 0x88, 0x3f, 0x9a, 0x45, 0x9a, 0x61, 0xe9, 0x9b, 0x70, 0x7e, 0xb7, 0x74,
+	0x82, 0xbb, 0x24, 0x4d, 0x86, 0xb5, 0xef, 0xa2, 0x79, 0xbf, 0xf1, 0x42, 0xc1, 0x83, 0xcd, 0x25,
+	0x0f, 0x64, 0xf1, 0x0f, 0x26, 0xa5, 0xd5, 0xa1, 0x1a, 0xa5, 0x8b, 0xfa, 0x3a, 0x64, 0x1f, 0x05,
+	0xae, 0xc2, 0x81, 0xe7, 0x26, 0x94, 0x03, 0xcf, 0xd5, 0xbe, 0x85, 0x93, 0x7b, 0x46, 0x67, 0xc1,
+	0x43, 0xf6, 0x80, 0xf2, 0x33, 0xa0, 0xad, 0xa6, 0x5c, 0x6c, 0x04, 0x0b, 0x71, 0x1d, 0x2a, 0xc1,
+	0x3d, 0x8c, 0xc8, 0x47, 0x74, 0xdb, 0xa4, 0xfd, 0x95, 0x4b, 0x4a, 0xa5, 0x2c, 0xf4, 0xf9, 0x2a,
+	0x64, 0xb8, 0x0d, 0xa5, 0x98, 0x20, 0xf9, 0xf9, 0x46, 0xa5, 0xad, 0xa6, 0xbb, 0x62, 0x3f, 0x3c,
+	0x4d, 0x89, 0xf8, 0x39, 0x28, 0x73, 0x27, 0xb4, 0x97, 0x3c, 0x88, 0x4f, 0x93, 0x42, 0x4b, 0x73,
+	0x27, 0x1c, 0xf0, 0x20, 0x95, 0x99, 0x4f, 0x65, 0x7e, 0x72, 0xb4, 0x33, 0x38, 0xdf, 0xd1, 0x92,
+	0xb5, 0xbf, 0x0d, 0xe7, 0xb7, 0x4c, 0x4c, 0xe7, 0xcc, 0xb5, 0x03, 0x36, 0xe5, 0x81, 0x1b, 0xda,
+	0x53, 0xbe, 0x5e, 0x89, 0x64, 0x16, 0xa7, 0x89, 0x93, 0xc6, 0xbe, 0x8e, 0x74, 0x7d, 0x72, 0x2c,
+	0xaf, 0xe1, 0x78, 0xf7, 0xf4, 0xa8, 0x50, 0x92, 0x2a, 0xee, 0xe7, 0x92, 0xc2, 0xff, 0xfe, 0x4a,
+	0x68, 0x97, 0x70, 0xba, 0x7b, 0x46, 0xe2, 0x9d, 0xd8, 0x82, 0x12, 0x5b, 0x89, 0xc0, 0x63, 0x69,
+	0xef, 0x1e, 0x39, 0x51, 0x29, 0xeb, 0x87, 0x06, 0x1c, 0x49, 0xa3, 0xe1, 0x08, 0xe7, 0x8a, 0x6d,
+	0x42, 0xac, 0xc2, 0xd9, 0x3b, 0xbd, 0xdf, 0x33, 0x74, 0x79, 0x3b, 0xd8, 0x63, 0x9d, 0xea, 0x03,
+	0x22, 0x6f, 0x97, 0x27, 0xed, 0xf7, 0x5b, 0xd7, 0xb8, 0xb9, 0xf6, 0x7d, 0x1e, 0x08, 0x6c, 0x80,
+	0x42, 0xd9, 0xcc, 0x0b, 0x05, 0x0b, 0xb0, 0xfa, 0xd8, 0x25, 0x5e, 0x7b, 0xd4, 0xa3, 0x3d, 0x69,
+	0xe4, 0x7e, 0xcc, 0x5d, 0x8c, 0x40, 0xe3, 0xc1, 0xac, 0x39, 0xdf, 0xf8, 0x2c, 0x58, 0x30, 0x77,
+	0xc6, 0x82, 0xe6, 0xad, 0x33, 0x09, 0xbc, 0x69, 0xba, 0x4e, 0xbe, 0x3b, 0x7e, 0xfd, 0x7e, 0xe6,
+	0x89, 0xf9, 0x7a, 0xd2, 0x9c, 0xf2, 0x65, 0x6b, 0x8b, 0xda, 0x8a, 0xa9, 0xf1, 0xfb, 0x23, 0x6c,
+	0x49, 0xea, 0x24, 0x7e, 0xcc, 0xfc, 0xf4, 0xef, 0x00, 0x50, 0x71, 0xe6, 0x83, 0xf0, 0x08, 0x00,
+	0x00,
 }
@@ -8,6 +8,7 @@ import fmt "fmt"
 import math "math"
 import common "github.com/hyperledger/fabric/protos/common"
 import google_protobuf1 "github.com/golang/protobuf/ptypes/timestamp"
+import rwset "github.com/hyperledger/fabric/protos/ledger/rwset"
 
 import (
 	context "golang.org/x/net/context"
@@ -458,8 +459,8 @@ func (m *SignedEvent) GetEventBytes() []byte {
 }
 
 // Event is used by
-//  - consumers (adapters) to send Register
-//  - producer to advertise supported types and events
+//   - consumers (adapters) to send Register
+//   - producer to advertise supported types and events
 type Event struct {
 	// Types that are valid to be assigned to Event:
 	//	*Event_Register
@@ -731,19 +732,48 @@ func _Event_OneofSizer(msg proto.Message) (n int) {
 	return n
 }
 
+// FilteredBlockAndPrivateData is sent by DeliverWithPrivateData and contains
+// a filtered block whose chaincode events carry their payloads, along with the
+// private write sets of the collections the requestor is a member of, keyed by
+// the sequence of the transaction within the block
+type FilteredBlockAndPrivateData struct {
+	FilteredBlock  *FilteredBlock                      `protobuf:"bytes,1,opt,name=filtered_block,json=filteredBlock" json:"filtered_block,omitempty"`
+	PrivateDataMap map[uint64]*rwset.TxPvtReadWriteSet `protobuf:"bytes,2,rep,name=private_data_map,json=privateDataMap" json:"private_data_map,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+}
+
+func (m *FilteredBlockAndPrivateData) Reset()                    { *m = FilteredBlockAndPrivateData{} }
+func (m *FilteredBlockAndPrivateData) String() string            { return proto.CompactTextString(m) }
+func (*FilteredBlockAndPrivateData) ProtoMessage()               {}
+func (*FilteredBlockAndPrivateData) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{11} }
+
+func (m *FilteredBlockAndPrivateData) GetFilteredBlock() *FilteredBlock {
+	if m != nil {
+		return m.FilteredBlock
+	}
+	return nil
+}
+
+func (m *FilteredBlockAndPrivateData) GetPrivateDataMap() map[uint64]*rwset.TxPvtReadWriteSet {
+	if m != nil {
+		return m.PrivateDataMap
+	}
+	return nil
+}
+
 // DeliverResponse
 type DeliverResponse struct {
 	// Types that are valid to be assigned to Type:
 	//	*DeliverResponse_Status
 	//	*DeliverResponse_Block
 	//	*DeliverResponse_FilteredBlock
+	//	*DeliverResponse_FilteredBlockAndPrivateData
 	Type isDeliverResponse_Type `protobuf_oneof:"Type"`
 }
 
 func (m *DeliverResponse) Reset()                    { *m = DeliverResponse{} }
 func (m *DeliverResponse) String() string            { return proto.CompactTextString(m) }
 func (*DeliverResponse) ProtoMessage()               {}
-func (*DeliverResponse) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{11} }
+func (*DeliverResponse) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{12} }
 
 type isDeliverResponse_Type interface{ isDeliverResponse_Type() }
 
@@ -756,10 +786,14 @@ type DeliverResponse_Block struct {
 type DeliverResponse_FilteredBlock struct {
 	FilteredBlock *FilteredBlock `protobuf:"bytes,3,opt,name=filtered_block,json=filteredBlock,oneof"`
 }
+type DeliverResponse_FilteredBlockAndPrivateData struct {
+	FilteredBlockAndPrivateData *FilteredBlockAndPrivateData `protobuf:"bytes,4,opt,name=filtered_block_and_private_data,json=filteredBlockAndPrivateData,oneof"`
+}
 
-func (*DeliverResponse_Status) isDeliverResponse_Type()        {}
-func (*DeliverResponse_Block) isDeliverResponse_Type()         {}
-func (*DeliverResponse_FilteredBlock) isDeliverResponse_Type() {}
+func (*DeliverResponse_Status) isDeliverResponse_Type()                      {}
+func (*DeliverResponse_Block) isDeliverResponse_Type()                       {}
+func (*DeliverResponse_FilteredBlock) isDeliverResponse_Type()               {}
+func (*DeliverResponse_FilteredBlockAndPrivateData) isDeliverResponse_Type() {}
 
 func (m *DeliverResponse) GetType() isDeliverResponse_Type {
 	if m != nil {
@@ -789,12 +823,20 @@ func (m *DeliverResponse) GetFilteredBlock() *FilteredBlock {
 	return nil
 }
 
+func (m *DeliverResponse) GetFilteredBlockAndPrivateData() *FilteredBlockAndPrivateData {
+	if x, ok := m.GetType().(*DeliverResponse_FilteredBlockAndPrivateData); ok {
+		return x.FilteredBlockAndPrivateData
+	}
+	return nil
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*DeliverResponse) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _DeliverResponse_OneofMarshaler, _DeliverResponse_OneofUnmarshaler, _DeliverResponse_OneofSizer, []interface{}{
 		(*DeliverResponse_Status)(nil),
 		(*DeliverResponse_Block)(nil),
 		(*DeliverResponse_FilteredBlock)(nil),
+		(*DeliverResponse_FilteredBlockAndPrivateData)(nil),
 	}
 }
 
@@ -815,6 +857,11 @@ func _DeliverResponse_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
 		if err := b.EncodeMessage(x.FilteredBlock); err != nil {
 			return err
 		}
+	case *DeliverResponse_FilteredBlockAndPrivateData:
+		b.EncodeVarint(4<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.FilteredBlockAndPrivateData); err != nil {
+			return err
+		}
 	case nil:
 	default:
 		return fmt.Errorf("DeliverResponse.Type has unexpected type %T", x)
@@ -848,6 +895,14 @@ func _DeliverResponse_OneofUnmarshaler(msg proto.Message, tag, wire int, b *prot
 		err := b.DecodeMessage(msg)
 		m.Type = &DeliverResponse_FilteredBlock{msg}
 		return true, err
+	case 4: // Type.filtered_block_and_private_data
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(FilteredBlockAndPrivateData)
+		err := b.DecodeMessage(msg)
+		m.Type = &DeliverResponse_FilteredBlockAndPrivateData{msg}
+		return true, err
 	default:
 		return false, nil
 	}
@@ -870,6 +925,11 @@ func _DeliverResponse_OneofSizer(msg proto.Message) (n int) {
 		n += proto.SizeVarint(3<<3 | proto.WireBytes)
 		n += proto.SizeVarint(uint64(s))
 		n += s
+	case *DeliverResponse_FilteredBlockAndPrivateData:
+		s := proto.Size(x.FilteredBlockAndPrivateData)
+		n += proto.SizeVarint(4<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
 	case nil:
 	default:
 		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
@@ -889,6 +949,7 @@ func init() {
 	proto.RegisterType((*FilteredChaincodeAction)(nil), "protos.FilteredChaincodeAction")
 	proto.RegisterType((*SignedEvent)(nil), "protos.SignedEvent")
 	proto.RegisterType((*Event)(nil), "protos.Event")
+	proto.RegisterType((*FilteredBlockAndPrivateData)(nil), "protos.FilteredBlockAndPrivateData")
 	proto.RegisterType((*DeliverResponse)(nil), "protos.DeliverResponse")
 	proto.RegisterEnum("protos.EventType", EventType_name, EventType_value)
 }
@@ -1008,6 +1069,10 @@ type DeliverClient interface {
 	// deliver first requires an Envelope of type ab.DELIVER_SEEK_INFO with Payload data as a marshaled orderer.SeekInfo message,
 	// then a stream of **filtered** block replies is received.
 	DeliverFiltered(ctx context.Context, opts ...grpc.CallOption) (Deliver_DeliverFilteredClient, error)
+	// deliver first requires an Envelope of type ab.DELIVER_SEEK_INFO with Payload data as a marshaled orderer.SeekInfo message,
+	// then a stream of **filtered** blocks carrying chaincode event payloads and the private data
+	// the requestor is entitled to is received.
+	DeliverWithPrivateData(ctx context.Context, opts ...grpc.CallOption) (Deliver_DeliverWithPrivateDataClient, error)
 }
 
 type deliverClient struct {
@@ -1080,6 +1145,37 @@ func (x *deliverDeliverFilteredClient) Recv() (*DeliverResponse, error) {
 	return m, nil
 }
 
+func (c *deliverClient) DeliverWithPrivateData(ctx context.Context, opts ...grpc.CallOption) (Deliver_DeliverWithPrivateDataClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Deliver_serviceDesc.Streams[2], c.cc, "/protos.Deliver/DeliverWithPrivateData", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &deliverDeliverWithPrivateDataClient{stream}
+	return x, nil
+}
+
+type Deliver_DeliverWithPrivateDataClient interface {
+	Send(*common.Envelope) error
+	Recv() (*DeliverResponse, error)
+	grpc.ClientStream
+}
+
+type deliverDeliverWithPrivateDataClient struct {
+	grpc.ClientStream
+}
+
+func (x *deliverDeliverWithPrivateDataClient) Send(m *common.Envelope) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *deliverDeliverWithPrivateDataClient) Recv() (*DeliverResponse, error) {
+	m := new(DeliverResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // Server API for Deliver service
 
 type DeliverServer interface {
@@ -1089,6 +1185,10 @@ type DeliverServer interface {
 	// deliver first requires an Envelope of type ab.DELIVER_SEEK_INFO with Payload data as a marshaled orderer.SeekInfo message,
 	// then a stream of **filtered** block replies is received.
 	DeliverFiltered(Deliver_DeliverFilteredServer) error
+	// deliver first requires an Envelope of type ab.DELIVER_SEEK_INFO with Payload data as a marshaled orderer.SeekInfo message,
+	// then a stream of **filtered** blocks carrying chaincode event payloads and the private data
+	// the requestor is entitled to is received.
+	DeliverWithPrivateData(Deliver_DeliverWithPrivateDataServer) error
 }
 
 func RegisterDeliverServer(s *grpc.Server, srv DeliverServer) {
@@ -1147,6 +1247,32 @@ func (x *deliverDeliverFilteredServer) Recv() (*common.Envelope, error) {
 	return m, nil
 }
 
+func _Deliver_DeliverWithPrivateData_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DeliverServer).DeliverWithPrivateData(&deliverDeliverWithPrivateDataServer{stream})
+}
+
+type Deliver_DeliverWithPrivateDataServer interface {
+	Send(*DeliverResponse) error
+	Recv() (*common.Envelope, error)
+	grpc.ServerStream
+}
+
+type deliverDeliverWithPrivateDataServer struct {
+	grpc.ServerStream
+}
+
+func (x *deliverDeliverWithPrivateDataServer) Send(m *DeliverResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *deliverDeliverWithPrivateDataServer) Recv() (*common.Envelope, error) {
+	m := new(common.Envelope)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 var _Deliver_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "protos.Deliver",
 	HandlerType: (*DeliverServer)(nil),
@@ -1164,6 +1290,12 @@ var _Deliver_serviceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "DeliverWithPrivateData",
+			Handler:       _Deliver_DeliverWithPrivateData_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "peer/events.proto",
 }
@@ -1171,69 +1303,78 @@ var _Deliver_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("peer/events.proto", fileDescriptor5) }
 
 var fileDescriptor5 = []byte{
-	// 1009 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x56, 0xdd, 0x72, 0xdb, 0x44,
-	0x14, 0xb6, 0x62, 0xc7, 0xb1, 0x8e, 0xe3, 0xd4, 0xd9, 0xb4, 0xa9, 0xc6, 0x05, 0x5a, 0xc4, 0xc0,
-	0x04, 0x2e, 0xec, 0x60, 0x3a, 0x0c, 0xd3, 0x0b, 0x98, 0xf8, 0x27, 0xc8, 0x34, 0x4d, 0x32, 0x1b,
-	0x87, 0x8b, 0x5e, 0xa0, 0x59, 0xcb, 0x6b, 0x59, 0xad, 0x2c, 0x79, 0x76, 0xd7, 0x99, 0xe4, 0x11,
-	0x78, 0x03, 0xde, 0x80, 0x19, 0x5e, 0x85, 0x17, 0xe2, 0x92, 0xd1, 0x6a, 0x57, 0x52, 0x6c, 0xda,
-	0x21, 0x57, 0xf6, 0xf9, 0xfb, 0xf6, 0xfc, 0x7c, 0x67, 0x57, 0xb0, 0xbf, 0xa4, 0x94, 0x75, 0xe8,
-	0x0d, 0x8d, 0x04, 0x6f, 0x2f, 0x59, 0x2c, 0x62, 0x54, 0x95, 0x3f, 0xbc, 0x75, 0xe0, 0xc5, 0x8b,
-	0x45, 0x1c, 0x75, 0xd2, 0x9f, 0xd4, 0xd8, 0x7a, 0xee, 0xc7, 0xb1, 0x1f, 0xd2, 0x8e, 0x94, 0x26,
-	0xab, 0x59, 0x47, 0x04, 0x0b, 0xca, 0x05, 0x59, 0x2c, 0x95, 0x43, 0x4b, 0x02, 0x7a, 0x73, 0x12,
-	0x44, 0x5e, 0x3c, 0xa5, 0xae, 0x84, 0x56, 0xb6, 0x43, 0x69, 0x13, 0x8c, 0x44, 0x9c, 0x78, 0x22,
-	0xd0, 0xa0, 0xf6, 0x25, 0xec, 0xf6, 0x75, 0x00, 0xa6, 0x3e, 0xfa, 0x1c, 0x76, 0x73, 0x80, 0x60,
-	0x6a, 0x19, 0x2f, 0x8c, 0x23, 0x13, 0xd7, 0x33, 0xdd, 0x68, 0x8a, 0x3e, 0x05, 0x90, 0xc8, 0x6e,
-	0x44, 0x16, 0xd4, 0xda, 0x92, 0x0e, 0xa6, 0xd4, 0x9c, 0x93, 0x05, 0xb5, 0xff, 0x34, 0xa0, 0x36,
-	0x8a, 0x04, 0x65, 0x94, 0x0b, 0x74, 0xac, 0x7d, 0xc5, 0xdd, 0x92, 0x4a, 0xb0, 0xbd, 0xee, 0x7e,
-	0x7a, 0x34, 0x6f, 0x0f, 0x13, 0xcb, 0xf8, 0x6e, 0x49, 0x55, 0x78, 0xf2, 0x17, 0x0d, 0x00, 0xe5,
-	0x09, 0x30, 0xea, 0xbb, 0x41, 0x34, 0x8b, 0xe5, 0x29, 0xf5, 0xee, 0x63, 0x1d, 0x59, 0x4c, 0xd9,
-	0x29, 0xe1, 0xa6, 0x57, 0x90, 0x47, 0xd1, 0x2c, 0x46, 0x16, 0xec, 0x48, 0xdd, 0x68, 0x60, 0x95,
-	0x65, 0x82, 0x5a, 0xec, 0x99, 0xb0, 0xa3, 0x9c, 0xec, 0x97, 0x50, 0xc3, 0xd4, 0x0f, 0xb8, 0xa0,
-	0x0c, 0x1d, 0x41, 0x35, 0x9d, 0x84, 0x65, 0xbc, 0x28, 0x1f, 0xd5, 0xbb, 0x4d, 0x7d, 0x94, 0x2e,
-	0x05, 0x2b, 0xbb, 0xfd, 0x06, 0x4c, 0x4c, 0xdf, 0x51, 0xd9, 0x44, 0xf4, 0x05, 0x6c, 0x89, 0x5b,
-	0x59, 0x57, 0xbd, 0x7b, 0xa0, 0x43, 0xc6, 0x79, 0x97, 0xf1, 0x96, 0xb8, 0x45, 0xcf, 0xc0, 0xa4,
-	0x8c, 0xc5, 0xcc, 0x5d, 0x70, 0x5f, 0xf5, 0xab, 0x26, 0x15, 0x6f, 0xb8, 0x6f, 0x7f, 0x0f, 0x70,
-	0x1d, 0xb1, 0x87, 0xa7, 0xf1, 0x87, 0x01, 0x8d, 0xd3, 0x20, 0x4c, 0xb4, 0xd3, 0x5e, 0x18, 0x7b,
-	0xef, 0x93, 0xb9, 0x78, 0x73, 0x12, 0x45, 0x34, 0xcc, 0x07, 0x67, 0x2a, 0xcd, 0x68, 0x8a, 0x0e,
-	0xa1, 0x1a, 0xad, 0x16, 0x13, 0xca, 0x64, 0x0a, 0x15, 0xac, 0x24, 0x74, 0x09, 0x4f, 0x66, 0x0a,
-	0xc7, 0x2d, 0xf0, 0x83, 0x5b, 0x15, 0x99, 0xc1, 0x33, 0x9d, 0x81, 0x3e, 0xac, 0x58, 0xdd, 0xe3,
-	0xd9, 0xa6, 0x92, 0xdb, 0xff, 0x18, 0x70, 0xf0, 0x1f, 0xde, 0x08, 0x41, 0x45, 0xdc, 0x66, 0xa9,
-	0xc9, 0xff, 0xe8, 0x2b, 0xa8, 0x48, 0x6a, 0x6c, 0x49, 0x6a, 0xa0, 0xb6, 0x62, 0xbc, 0x43, 0xc9,
-	0x94, 0x32, 0xc9, 0x0d, 0x69, 0x47, 0xa7, 0x80, 0xc4, 0xad, 0x7b, 0x43, 0xc2, 0x60, 0x4a, 0x12,
-	0x30, 0x37, 0x99, 0xb6, 0x9c, 0xed, 0x5e, 0xd7, 0xca, 0x1a, 0x7f, 0xfb, 0x6b, 0xe6, 0xd0, 0x4f,
-	0xd8, 0xd0, 0x14, 0x6b, 0x1a, 0x74, 0x0d, 0x07, 0x85, 0x22, 0xdd, 0xbc, 0xd6, 0x64, 0x82, 0xf6,
-	0x47, 0x6a, 0x3d, 0x49, 0x3d, 0x9d, 0x12, 0x46, 0x62, 0x43, 0xdb, 0xab, 0x42, 0x65, 0x40, 0x04,
-	0xb1, 0xdf, 0x41, 0xeb, 0xc3, 0xb1, 0xe8, 0x0c, 0xf6, 0x73, 0x6e, 0xeb, 0xa3, 0xd3, 0x41, 0x3f,
-	0x5f, 0x3f, 0x3a, 0xa3, 0x78, 0x1a, 0x5c, 0xe0, 0xb8, 0x42, 0xb3, 0xdf, 0xc2, 0xd3, 0x0f, 0x38,
-	0xa3, 0x9f, 0xe0, 0xd1, 0xda, 0x35, 0xa0, 0x38, 0x7a, 0xb8, 0xb1, 0x41, 0x72, 0x09, 0xf1, 0x9e,
-	0x77, 0x4f, 0xb6, 0x5f, 0x43, 0xfd, 0x2a, 0xf0, 0x23, 0x3a, 0x95, 0x22, 0xfa, 0x04, 0x4c, 0x1e,
-	0xf8, 0x11, 0x11, 0x2b, 0x96, 0x6e, 0xf1, 0x2e, 0xce, 0x15, 0xe8, 0x33, 0xb5, 0xe4, 0xbd, 0x3b,
-	0x41, 0xb9, 0x9c, 0xe4, 0x2e, 0x2e, 0x68, 0xec, 0xbf, 0xcb, 0xb0, 0x9d, 0xe2, 0xb4, 0xa1, 0xa6,
-	0xa9, 0xae, 0x12, 0xca, 0x08, 0xae, 0x37, 0xd1, 0x29, 0xe1, 0xcc, 0x07, 0x7d, 0x09, 0xdb, 0x93,
-	0x84, 0xdb, 0x6a, 0xff, 0x1b, 0x9a, 0x1e, 0x92, 0xf0, 0x4e, 0x09, 0xa7, 0x56, 0x74, 0xb2, 0x59,
-	0x6e, 0xf9, 0x63, 0xe5, 0x3a, 0xa5, 0xf5, 0x82, 0xd1, 0xb7, 0x60, 0x32, 0xbd, 0xd5, 0x8a, 0x0d,
-	0xfb, 0x79, 0x6a, 0xca, 0xe0, 0x94, 0x70, 0xee, 0x85, 0x5e, 0x02, 0xac, 0xb2, 0xcd, 0xb5, 0xb6,
-	0x65, 0x0c, 0xd2, 0x31, 0xf9, 0x4e, 0x3b, 0x25, 0x5c, 0xf0, 0x43, 0x3f, 0xc2, 0x5e, 0xb6, 0x6e,
-	0x69, 0x6d, 0x3b, 0x32, 0xf2, 0xc9, 0x3a, 0x01, 0x74, 0x8d, 0x8d, 0xd9, 0xbd, 0x2d, 0x4f, 0x6e,
-	0x36, 0x46, 0x89, 0x88, 0x99, 0x55, 0x95, 0x9d, 0xd6, 0x22, 0xfa, 0x01, 0xcc, 0xec, 0x45, 0xb0,
-	0x6a, 0x12, 0xb4, 0xd5, 0x4e, 0xdf, 0x8c, 0xb6, 0x7e, 0x33, 0xda, 0x63, 0xed, 0x81, 0x73, 0x67,
-	0x64, 0x43, 0x43, 0x84, 0xdc, 0xf5, 0x28, 0x13, 0xee, 0x9c, 0xf0, 0xb9, 0x65, 0x4a, 0xe4, 0xba,
-	0x08, 0x79, 0x9f, 0x32, 0xe1, 0x10, 0x3e, 0xef, 0xed, 0xa8, 0x19, 0xda, 0x7f, 0x19, 0xf0, 0x68,
-	0x40, 0xc3, 0xe0, 0x86, 0x32, 0x4c, 0xf9, 0x32, 0x8e, 0x38, 0x4d, 0xae, 0x2d, 0x2e, 0x88, 0x58,
-	0x71, 0x75, 0xc5, 0xef, 0xe9, 0x41, 0x5d, 0x49, 0xad, 0x53, 0xc2, 0xca, 0xfe, 0x7f, 0x27, 0xba,
-	0xd9, 0xa5, 0xf2, 0x43, 0xba, 0x94, 0xec, 0x63, 0x72, 0x79, 0x7c, 0x73, 0x0d, 0x66, 0xf6, 0xca,
-	0xa0, 0x5d, 0xa8, 0xe1, 0xe1, 0xcf, 0xa3, 0xab, 0xf1, 0x10, 0x37, 0x4b, 0xc8, 0x84, 0xed, 0xde,
-	0xd9, 0x45, 0xff, 0x75, 0xd3, 0x40, 0x0d, 0x30, 0xfb, 0xce, 0xc9, 0xe8, 0xbc, 0x7f, 0x31, 0x18,
-	0x36, 0xb7, 0x12, 0x11, 0x0f, 0x7f, 0x19, 0xf6, 0xc7, 0xa3, 0x8b, 0xf3, 0x66, 0x19, 0xed, 0x43,
-	0xe3, 0x74, 0x74, 0x36, 0x1e, 0xe2, 0xe1, 0x20, 0x0d, 0xa8, 0x74, 0x5f, 0x41, 0x55, 0xc2, 0x72,
-	0x74, 0x0c, 0x95, 0xfe, 0x9c, 0x08, 0x94, 0x5d, 0xfe, 0x85, 0xb5, 0x69, 0x35, 0xee, 0xbd, 0x74,
-	0x76, 0xe9, 0xc8, 0x38, 0x36, 0xba, 0xbf, 0x1b, 0xb0, 0xa3, 0xfa, 0x87, 0x5e, 0xe5, 0x7f, 0x9b,
-	0xba, 0x13, 0xc3, 0xe8, 0x86, 0x86, 0xf1, 0x92, 0xb6, 0x9e, 0xea, 0xe8, 0xb5, 0x6e, 0xa7, 0x38,
-	0xa8, 0x97, 0x8d, 0x41, 0xf7, 0xe2, 0xc1, 0x18, 0xbd, 0xdf, 0xc0, 0x8e, 0x99, 0xdf, 0x9e, 0xdf,
-	0x2d, 0x29, 0x0b, 0xe9, 0xd4, 0xa7, 0xac, 0x3d, 0x23, 0x13, 0x16, 0x78, 0x3a, 0x2c, 0xf9, 0x6a,
-	0xe8, 0x35, 0xd2, 0x5a, 0x2f, 0x89, 0xf7, 0x9e, 0xf8, 0xf4, 0xed, 0xd7, 0x7e, 0x20, 0xe6, 0xab,
-	0x49, 0x72, 0x56, 0xa7, 0x10, 0xd9, 0x49, 0x23, 0xd3, 0xcf, 0x13, 0xde, 0x49, 0x22, 0x27, 0xe9,
-	0xf7, 0xcc, 0x77, 0xff, 0x06, 0x00, 0x00, 0xff, 0xff, 0x7f, 0xfc, 0x9e, 0x06, 0xeb, 0x08, 0x00,
-	0x00,
+	// 1167 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x56, 0xd9, 0x6e, 0xdb, 0x46,
+	0x17, 0xd6, 0xe6, 0x85, 0xc7, 0x96, 0x23, 0x8f, 0x13, 0x87, 0x90, 0xff, 0xbf, 0x49, 0x19, 0xb4,
+	0x70, 0x7b, 0x21, 0xa5, 0x6a, 0xd0, 0x06, 0x41, 0xd1, 0xc2, 0x92, 0x95, 0x4a, 0xcd, 0x66, 0x8c,
+	0x95, 0x06, 0x48, 0x81, 0x12, 0x63, 0xf1, 0x88, 0x62, 0x2c, 0x91, 0xc4, 0x70, 0xa4, 0xda, 0x4f,
+	0xd2, 0x02, 0x7d, 0x80, 0x3e, 0x4c, 0x6f, 0xfb, 0x30, 0xbd, 0x2c, 0x38, 0x0b, 0x45, 0xc9, 0x89,
+	0x11, 0xdf, 0x48, 0x33, 0x67, 0xf9, 0xe6, 0x2c, 0xdf, 0x99, 0x21, 0xec, 0xc6, 0x88, 0xbc, 0x89,
+	0x73, 0x0c, 0x45, 0xd2, 0x88, 0x79, 0x24, 0x22, 0xb2, 0x2e, 0xff, 0x92, 0xfa, 0xde, 0x30, 0x9a,
+	0x4e, 0xa3, 0xb0, 0xa9, 0xfe, 0x94, 0xb2, 0x7e, 0xcf, 0x8f, 0x22, 0x7f, 0x82, 0x4d, 0xb9, 0x3b,
+	0x9b, 0x8d, 0x9a, 0x22, 0x98, 0x62, 0x22, 0xd8, 0x34, 0xd6, 0x06, 0x75, 0x09, 0x38, 0x1c, 0xb3,
+	0x20, 0x1c, 0x46, 0x1e, 0xba, 0x12, 0x5a, 0xeb, 0xf6, 0xa5, 0x4e, 0x70, 0x16, 0x26, 0x6c, 0x28,
+	0x82, 0x0c, 0xd4, 0x9e, 0xa0, 0xe7, 0x23, 0x6f, 0xf2, 0xdf, 0x12, 0x14, 0xea, 0x57, 0x69, 0x9c,
+	0x13, 0xd8, 0xee, 0x18, 0x28, 0x8a, 0x3e, 0xf9, 0x14, 0xb6, 0x17, 0xd0, 0x81, 0x67, 0x17, 0xef,
+	0x17, 0x0f, 0x2d, 0xba, 0x95, 0xc9, 0xfa, 0x1e, 0xf9, 0x3f, 0x80, 0x3c, 0xd3, 0x0d, 0xd9, 0x14,
+	0xed, 0x92, 0x34, 0xb0, 0xa4, 0xe4, 0x25, 0x9b, 0xa2, 0xf3, 0x57, 0x11, 0x36, 0xfb, 0xa1, 0x40,
+	0x8e, 0x89, 0x20, 0x0f, 0x8d, 0xad, 0xb8, 0x8c, 0x51, 0x82, 0xed, 0xb4, 0x76, 0xd5, 0xd1, 0x49,
+	0xa3, 0x9b, 0x6a, 0x06, 0x97, 0x31, 0x6a, 0xf7, 0x74, 0x49, 0x8e, 0x81, 0x2c, 0x02, 0xe0, 0xe8,
+	0xbb, 0x41, 0x38, 0x8a, 0xe4, 0x29, 0x5b, 0xad, 0xdb, 0xc6, 0x33, 0x1f, 0x72, 0xaf, 0x40, 0x6b,
+	0xc3, 0xdc, 0xbe, 0x1f, 0x8e, 0x22, 0x62, 0xc3, 0x86, 0x94, 0xf5, 0x8f, 0xed, 0xb2, 0x0c, 0xd0,
+	0x6c, 0xdb, 0x16, 0x6c, 0x68, 0x23, 0xe7, 0x11, 0x6c, 0x52, 0xf4, 0x83, 0x44, 0x20, 0x27, 0x87,
+	0xb0, 0xae, 0x7a, 0x64, 0x17, 0xef, 0x97, 0x0f, 0xb7, 0x5a, 0x35, 0x73, 0x94, 0x49, 0x85, 0x6a,
+	0xbd, 0xf3, 0x02, 0x2c, 0x8a, 0xef, 0x50, 0x96, 0x97, 0x3c, 0x80, 0x92, 0xb8, 0x90, 0x79, 0x6d,
+	0xb5, 0xf6, 0x8c, 0xcb, 0x60, 0x51, 0x7f, 0x5a, 0x12, 0x17, 0xe4, 0x00, 0x2c, 0xe4, 0x3c, 0xe2,
+	0xee, 0x34, 0xf1, 0x75, 0xbd, 0x36, 0xa5, 0xe0, 0x45, 0xe2, 0x3b, 0xdf, 0x00, 0xbc, 0x0e, 0xf9,
+	0xcd, 0xc3, 0xf8, 0xa3, 0x08, 0xd5, 0xa7, 0xc1, 0x24, 0x95, 0x7a, 0xed, 0x49, 0x34, 0x3c, 0x4f,
+	0xfb, 0x32, 0x1c, 0xb3, 0x30, 0xc4, 0xc9, 0xa2, 0x71, 0x96, 0x96, 0xf4, 0x3d, 0xb2, 0x0f, 0xeb,
+	0xe1, 0x6c, 0x7a, 0x86, 0x5c, 0x86, 0x50, 0xa1, 0x7a, 0x47, 0x4e, 0xe0, 0xce, 0x48, 0xe3, 0xb8,
+	0x39, 0xe6, 0x24, 0x76, 0x45, 0x46, 0x70, 0x60, 0x22, 0x30, 0x87, 0xe5, 0xb3, 0xbb, 0x3d, 0xba,
+	0x2a, 0x4c, 0x9c, 0x7f, 0x8b, 0xb0, 0xf7, 0x1e, 0x6b, 0x42, 0xa0, 0x22, 0x2e, 0xb2, 0xd0, 0xe4,
+	0x9a, 0x7c, 0x0e, 0x15, 0x49, 0x8d, 0x92, 0xa4, 0x06, 0x69, 0xe8, 0x59, 0xe8, 0x21, 0xf3, 0x90,
+	0x4b, 0x6e, 0x48, 0x3d, 0x79, 0x0a, 0x44, 0x5c, 0xb8, 0x73, 0x36, 0x09, 0x3c, 0x96, 0x82, 0xb9,
+	0x69, 0xb7, 0x65, 0x6f, 0x77, 0x5a, 0x76, 0x56, 0xf8, 0x8b, 0x9f, 0x33, 0x83, 0x4e, 0xca, 0x86,
+	0x9a, 0x58, 0x91, 0x90, 0xd7, 0xb0, 0x97, 0x4b, 0xd2, 0x5d, 0xe4, 0x9a, 0x76, 0xd0, 0xb9, 0x26,
+	0xd7, 0x23, 0x65, 0xd9, 0x2b, 0x50, 0x22, 0xae, 0x48, 0xdb, 0xeb, 0x50, 0x39, 0x66, 0x82, 0x39,
+	0xef, 0xa0, 0xfe, 0x61, 0x5f, 0xf2, 0x1c, 0x76, 0x17, 0xdc, 0x36, 0x47, 0xab, 0x46, 0xdf, 0x5b,
+	0x3d, 0x3a, 0xa3, 0xb8, 0x72, 0xce, 0x71, 0x5c, 0xa3, 0x39, 0x6f, 0xe1, 0xee, 0x07, 0x8c, 0xc9,
+	0x0f, 0x70, 0x6b, 0xe5, 0x82, 0xd0, 0x1c, 0xdd, 0xbf, 0x32, 0x41, 0x72, 0x08, 0xe9, 0xce, 0x70,
+	0x69, 0xef, 0x3c, 0x83, 0xad, 0xd3, 0xc0, 0x0f, 0xd1, 0x93, 0x5b, 0xf2, 0x3f, 0xb0, 0x92, 0xc0,
+	0x0f, 0x99, 0x98, 0x71, 0x35, 0xc5, 0xdb, 0x74, 0x21, 0x20, 0x9f, 0xe8, 0x21, 0x6f, 0x5f, 0x0a,
+	0x4c, 0x64, 0x27, 0xb7, 0x69, 0x4e, 0xe2, 0xfc, 0x5d, 0x86, 0x35, 0x85, 0xd3, 0x80, 0x4d, 0x43,
+	0x75, 0x1d, 0x50, 0x46, 0x70, 0x33, 0x89, 0xbd, 0x02, 0xcd, 0x6c, 0xc8, 0x67, 0xb0, 0x76, 0x96,
+	0x72, 0x5b, 0xcf, 0x7f, 0xd5, 0xd0, 0x43, 0x12, 0xbe, 0x57, 0xa0, 0x4a, 0x4b, 0x8e, 0xae, 0xa6,
+	0x5b, 0xbe, 0x2e, 0xdd, 0x5e, 0x61, 0x35, 0x61, 0xf2, 0x15, 0x58, 0xdc, 0x4c, 0xb5, 0x66, 0xc3,
+	0xee, 0x22, 0x34, 0xad, 0xe8, 0x15, 0xe8, 0xc2, 0x8a, 0x3c, 0x02, 0x98, 0x65, 0x93, 0x6b, 0xaf,
+	0x49, 0x1f, 0x62, 0x7c, 0x16, 0x33, 0xdd, 0x2b, 0xd0, 0x9c, 0x1d, 0xf9, 0x1e, 0x76, 0xb2, 0x71,
+	0x53, 0xb9, 0x6d, 0x48, 0xcf, 0x3b, 0xab, 0x04, 0x30, 0x39, 0x56, 0x47, 0x4b, 0x53, 0x9e, 0xde,
+	0x6c, 0x1c, 0x99, 0x88, 0xb8, 0xbd, 0x2e, 0x2b, 0x6d, 0xb6, 0xe4, 0x31, 0x58, 0xd9, 0x5b, 0x61,
+	0x6f, 0x4a, 0xd0, 0x7a, 0x43, 0xbd, 0x26, 0x0d, 0xf3, 0x9a, 0x34, 0x06, 0xc6, 0x82, 0x2e, 0x8c,
+	0x89, 0x03, 0x55, 0x31, 0x49, 0xdc, 0x21, 0x72, 0xe1, 0x8e, 0x59, 0x32, 0xb6, 0x2d, 0x89, 0xbc,
+	0x25, 0x26, 0x49, 0x07, 0xb9, 0xe8, 0xb1, 0x64, 0xdc, 0xde, 0xd0, 0x3d, 0x74, 0xfe, 0x2c, 0xc1,
+	0xc1, 0x52, 0x8c, 0x47, 0xa1, 0x77, 0xc2, 0x83, 0x39, 0x13, 0x98, 0x8e, 0x00, 0xf9, 0xee, 0x4a,
+	0x82, 0xc5, 0x6b, 0x12, 0x5c, 0x4d, 0x8f, 0x41, 0x2d, 0x56, 0x60, 0xae, 0xc7, 0x04, 0x73, 0xa7,
+	0x2c, 0xb6, 0x4b, 0x72, 0x42, 0xbe, 0x7d, 0xaf, 0xff, 0xf2, 0xe1, 0x8d, 0xdc, 0xfa, 0x05, 0x8b,
+	0xbb, 0xa1, 0xe0, 0x97, 0x74, 0x27, 0x5e, 0x12, 0xd6, 0x7f, 0x81, 0xbd, 0xf7, 0x98, 0x91, 0x1a,
+	0x94, 0xcf, 0xf1, 0x52, 0x06, 0x5b, 0xa1, 0xe9, 0x92, 0x34, 0x60, 0x6d, 0xce, 0x26, 0x33, 0xd4,
+	0xec, 0xb3, 0x1b, 0xea, 0xe1, 0x1c, 0x5c, 0x9c, 0xcc, 0x05, 0x45, 0xe6, 0xbd, 0xe1, 0x81, 0xc0,
+	0x53, 0x14, 0x54, 0x99, 0x3d, 0x29, 0x3d, 0x2e, 0x3a, 0xbf, 0x97, 0xe0, 0xd6, 0x31, 0x4e, 0x82,
+	0x39, 0x72, 0x8a, 0x49, 0x1c, 0x85, 0x09, 0xa6, 0x97, 0x7a, 0x22, 0x98, 0x98, 0x25, 0xfa, 0x01,
+	0xdc, 0x31, 0x34, 0x3e, 0x95, 0xd2, 0x5e, 0x81, 0x6a, 0xfd, 0xc7, 0xf2, 0xfd, 0x2a, 0x87, 0xca,
+	0x37, 0xe2, 0xd0, 0x39, 0xdc, 0x5b, 0xf6, 0x77, 0x59, 0xe8, 0xb9, 0xf9, 0xba, 0xeb, 0x11, 0x78,
+	0xf0, 0x11, 0x35, 0xef, 0x15, 0xe8, 0xc1, 0xe8, 0xc3, 0xea, 0xf4, 0x6a, 0x4c, 0xef, 0xf1, 0x2f,
+	0x5f, 0x83, 0x95, 0x3d, 0xf8, 0x64, 0x1b, 0x36, 0x69, 0xf7, 0xc7, 0xfe, 0xe9, 0xa0, 0x4b, 0x6b,
+	0x05, 0x62, 0xc1, 0x5a, 0xfb, 0xf9, 0xab, 0xce, 0xb3, 0x5a, 0x91, 0x54, 0xc1, 0xea, 0xf4, 0x8e,
+	0xfa, 0x2f, 0x3b, 0xaf, 0x8e, 0xbb, 0xb5, 0x52, 0xba, 0xa5, 0xdd, 0x9f, 0xba, 0x9d, 0x41, 0xff,
+	0xd5, 0xcb, 0x5a, 0x99, 0xec, 0x42, 0xf5, 0x69, 0xff, 0xf9, 0xa0, 0x4b, 0xbb, 0xc7, 0xca, 0xa1,
+	0xd2, 0x7a, 0x02, 0xeb, 0x12, 0x36, 0x21, 0x0f, 0xa1, 0xd2, 0x19, 0x33, 0x41, 0xb2, 0x77, 0x38,
+	0x77, 0x83, 0xd5, 0xab, 0x4b, 0x1f, 0x1d, 0x4e, 0xe1, 0xb0, 0xf8, 0xb0, 0xd8, 0xfa, 0xa7, 0x08,
+	0x1b, 0xba, 0x59, 0xe4, 0xc9, 0x62, 0x59, 0x33, 0x65, 0xef, 0x86, 0x73, 0x9c, 0x44, 0x31, 0xd6,
+	0xef, 0x1a, 0xef, 0x95, 0xd6, 0x2a, 0x1c, 0xd2, 0xce, 0x7a, 0x6e, 0xea, 0x74, 0x73, 0x8c, 0x3e,
+	0xec, 0x6b, 0xc5, 0x9b, 0x40, 0x8c, 0xf3, 0x03, 0x75, 0x53, 0xa8, 0xf6, 0xaf, 0xe0, 0x44, 0xdc,
+	0x6f, 0x8c, 0x2f, 0x63, 0xe4, 0xea, 0xc3, 0xaf, 0x31, 0x62, 0x67, 0x3c, 0x18, 0x1a, 0xb7, 0x18,
+	0x91, 0xb7, 0xab, 0xaa, 0x6c, 0x27, 0x6c, 0x78, 0xce, 0x7c, 0x7c, 0xfb, 0x85, 0x1f, 0x88, 0xf1,
+	0xec, 0x2c, 0x3d, 0xab, 0x99, 0xf3, 0x6c, 0x2a, 0x4f, 0xf5, 0x39, 0x9a, 0x34, 0x53, 0xcf, 0x33,
+	0xf5, 0xfd, 0xfa, 0xf5, 0x7f, 0x03, 0x00, 0x28, 0x6f, 0xa0, 0xd6, 0xdb, 0x0a, 0x00, 0x00,
 }
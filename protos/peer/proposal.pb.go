@@ -20,22 +20,22 @@ var _ = math.Inf
 // When an endorser receives a SignedProposal message, it should verify the
 // signature over the proposal bytes. This verification requires the following
 // steps:
-// 1. Verification of the validity of the certificate that was used to produce
-//    the signature.  The certificate will be available once proposalBytes has
-//    been unmarshalled to a Proposal message, and Proposal.header has been
-//    unmarshalled to a Header message. While this unmarshalling-before-verifying
-//    might not be ideal, it is unavoidable because i) the signature needs to also
-//    protect the signing certificate; ii) it is desirable that Header is created
-//    once by the client and never changed (for the sake of accountability and
-//    non-repudiation). Note also that it is actually impossible to conclusively
-//    verify the validity of the certificate included in a Proposal, because the
-//    proposal needs to first be endorsed and ordered with respect to certificate
-//    expiration transactions. Still, it is useful to pre-filter expired
-//    certificates at this stage.
-// 2. Verification that the certificate is trusted (signed by a trusted CA) and
-//    that it is allowed to transact with us (with respect to some ACLs);
-// 3. Verification that the signature on proposalBytes is valid;
-// 4. Detect replay attacks;
+//  1. Verification of the validity of the certificate that was used to produce
+//     the signature.  The certificate will be available once proposalBytes has
+//     been unmarshalled to a Proposal message, and Proposal.header has been
+//     unmarshalled to a Header message. While this unmarshalling-before-verifying
+//     might not be ideal, it is unavoidable because i) the signature needs to also
+//     protect the signing certificate; ii) it is desirable that Header is created
+//     once by the client and never changed (for the sake of accountability and
+//     non-repudiation). Note also that it is actually impossible to conclusively
+//     verify the validity of the certificate included in a Proposal, because the
+//     proposal needs to first be endorsed and ordered with respect to certificate
+//     expiration transactions. Still, it is useful to pre-filter expired
+//     certificates at this stage.
+//  2. Verification that the certificate is trusted (signed by a trusted CA) and
+//     that it is allowed to transact with us (with respect to some ACLs);
+//  3. Verification that the signature on proposalBytes is valid;
+//  4. Detect replay attacks;
 type SignedProposal struct {
 	// The bytes of Proposal
 	ProposalBytes []byte `protobuf:"bytes,1,opt,name=proposal_bytes,json=proposalBytes,proto3" json:"proposal_bytes,omitempty"`
@@ -65,24 +65,24 @@ func (m *SignedProposal) GetSignature() []byte {
 }
 
 // A Proposal is sent to an endorser for endorsement.  The proposal contains:
-// 1. A header which should be unmarshaled to a Header message.  Note that
-//    Header is both the header of a Proposal and of a Transaction, in that i)
-//    both headers should be unmarshaled to this message; and ii) it is used to
-//    compute cryptographic hashes and signatures.  The header has fields common
-//    to all proposals/transactions.  In addition it has a type field for
-//    additional customization. An example of this is the ChaincodeHeaderExtension
-//    message used to extend the Header for type CHAINCODE.
-// 2. A payload whose type depends on the header's type field.
-// 3. An extension whose type depends on the header's type field.
+//  1. A header which should be unmarshaled to a Header message.  Note that
+//     Header is both the header of a Proposal and of a Transaction, in that i)
+//     both headers should be unmarshaled to this message; and ii) it is used to
+//     compute cryptographic hashes and signatures.  The header has fields common
+//     to all proposals/transactions.  In addition it has a type field for
+//     additional customization. An example of this is the ChaincodeHeaderExtension
+//     message used to extend the Header for type CHAINCODE.
+//  2. A payload whose type depends on the header's type field.
+//  3. An extension whose type depends on the header's type field.
 //
 // Let us see an example. For type CHAINCODE (see the Header message),
 // we have the following:
-// 1. The header is a Header message whose extensions field is a
-//    ChaincodeHeaderExtension message.
-// 2. The payload is a ChaincodeProposalPayload message.
-// 3. The extension is a ChaincodeAction that might be used to ask the
-//    endorsers to endorse a specific ChaincodeAction, thus emulating the
-//    submitting peer model.
+//  1. The header is a Header message whose extensions field is a
+//     ChaincodeHeaderExtension message.
+//  2. The payload is a ChaincodeProposalPayload message.
+//  3. The extension is a ChaincodeAction that might be used to ask the
+//     endorsers to endorse a specific ChaincodeAction, thus emulating the
+//     submitting peer model.
 type Proposal struct {
 	// The header of the proposal. It is the bytes of the Header
 	Header []byte `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
@@ -257,7 +257,7 @@ func init() {
 func init() { proto.RegisterFile("peer/proposal.proto", fileDescriptor7) }
 
 var fileDescriptor7 = []byte{
-	// 452 bytes of a gzipped FileDescriptorProto
+	// 449 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x53, 0x4d, 0x6f, 0xd3, 0x4c,
 	0x10, 0x96, 0x93, 0xf7, 0xed, 0xc7, 0x24, 0xf4, 0x63, 0x5b, 0x21, 0x2b, 0xea, 0xa1, 0xb2, 0x84,
 	0x54, 0x24, 0xb0, 0xa5, 0x20, 0x21, 0xc4, 0x05, 0x11, 0xa8, 0x44, 0x0f, 0x48, 0x95, 0x81, 0x1e,
@@ -285,6 +285,6 @@ var fileDescriptor7 = []byte{
 	0xfb, 0xe2, 0x2b, 0x24, 0x42, 0xd5, 0xe9, 0xaa, 0x93, 0xa8, 0x1a, 0xac, 0x6a, 0x54, 0xe9, 0x37,
 	0x5a, 0x28, 0x56, 0x7a, 0x66, 0xff, 0xd8, 0x17, 0x87, 0xf7, 0x1e, 0x96, 0x77, 0xb4, 0xc6, 0xdb,
 	0xa7, 0x35, 0x33, 0xab, 0xb6, 0x48, 0x4b, 0xf1, 0x3d, 0xdb, 0xe0, 0x66, 0x96, 0x9b, 0x59, 0x6e,
-	0xd6, 0x73, 0x0b, 0xfb, 0x31, 0xbd, 0xf8, 0x13, 0x00, 0x00, 0xff, 0xff, 0x12, 0x75, 0xb6, 0xaf,
-	0x6a, 0x03, 0x00, 0x00,
+	0xd6, 0x73, 0x0b, 0xfb, 0x31, 0xbd, 0xf8, 0x33, 0x00, 0x12, 0x75, 0xb6, 0xaf, 0x6a, 0x03, 0x00,
+	0x00,
 }
@@ -209,28 +209,28 @@ func init() {
 func init() { proto.RegisterFile("peer/proposal_response.proto", fileDescriptor8) }
 
 var fileDescriptor8 = []byte{
-	// 367 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x92, 0x51, 0x4b, 0xfb, 0x30,
-	0x14, 0xc5, 0xe9, 0xfe, 0xff, 0xcd, 0x2d, 0x9b, 0x30, 0x2a, 0x68, 0x19, 0x03, 0x47, 0x7d, 0x99,
-	0x20, 0x29, 0x28, 0x82, 0xcf, 0x03, 0xd1, 0xc7, 0x11, 0xc4, 0x07, 0x11, 0x24, 0xdd, 0xee, 0xd2,
-	0x62, 0xdb, 0x84, 0xdc, 0x54, 0xdc, 0x07, 0xf6, 0x7b, 0x48, 0xd3, 0xa6, 0xab, 0xe2, 0xd3, 0x38,
-	0x77, 0x27, 0xbf, 0x7b, 0xcf, 0xed, 0x25, 0x73, 0x05, 0xa0, 0x23, 0xa5, 0xa5, 0x92, 0xc8, 0xb3,
-	0x37, 0x0d, 0xa8, 0x64, 0x81, 0x40, 0x95, 0x96, 0x46, 0xfa, 0x03, 0xfb, 0x83, 0xb3, 0x73, 0x21,
-	0xa5, 0xc8, 0x20, 0xb2, 0x32, 0x2e, 0x77, 0x91, 0x49, 0x73, 0x40, 0xc3, 0x73, 0x55, 0x1b, 0xc3,
-	0x2f, 0x8f, 0x4c, 0xd7, 0x0d, 0x84, 0x35, 0x0c, 0x3f, 0x20, 0x47, 0x1f, 0xa0, 0x31, 0x95, 0x45,
-	0xe0, 0x2d, 0xbc, 0x65, 0x9f, 0x39, 0xe9, 0xdf, 0x91, 0x51, 0x4b, 0x08, 0x7a, 0x0b, 0x6f, 0x39,
-	0xbe, 0x9e, 0xd1, 0xba, 0x07, 0x75, 0x3d, 0xe8, 0x93, 0x73, 0xb0, 0x83, 0xd9, 0xbf, 0x22, 0x43,
-	0x37, 0x63, 0xf0, 0xdf, 0x3e, 0x9c, 0xd6, 0x2f, 0x90, 0xba, 0xbe, 0xac, 0x75, 0x54, 0x13, 0x28,
-	0xbe, 0xcf, 0x24, 0xdf, 0x06, 0xfd, 0x85, 0xb7, 0x9c, 0x30, 0x27, 0xfd, 0x5b, 0x32, 0x86, 0x62,
-	0x2b, 0x35, 0x42, 0x0e, 0x85, 0x09, 0x06, 0x16, 0x75, 0xe2, 0x50, 0xf7, 0x87, 0xbf, 0x58, 0xd7,
-	0x17, 0x3e, 0x93, 0x61, 0x1b, 0xef, 0x94, 0x0c, 0xd0, 0x70, 0x53, 0x62, 0x93, 0xae, 0x51, 0x55,
-	0xd3, 0x1c, 0x10, 0xb9, 0x00, 0x1b, 0x6d, 0xc4, 0x9c, 0xec, 0x8e, 0xf3, 0xef, 0xc7, 0x38, 0xe1,
-	0x2b, 0x39, 0xfb, 0xbd, 0xbe, 0x75, 0x33, 0xe9, 0x05, 0x39, 0x6e, 0x3f, 0x4f, 0xc2, 0x31, 0xb1,
-	0xdd, 0x26, 0x6c, 0xe2, 0x8a, 0x8f, 0x1c, 0x13, 0x7f, 0x4e, 0x46, 0xf0, 0x69, 0xa0, 0xb0, 0xcb,
-	0xee, 0x59, 0xc3, 0xa1, 0x10, 0x3e, 0x90, 0x71, 0x27, 0x91, 0x3f, 0x23, 0xc3, 0x26, 0x93, 0x6e,
-	0x60, 0xad, 0xae, 0x40, 0x98, 0x8a, 0x82, 0x9b, 0x52, 0x83, 0x03, 0xb5, 0x85, 0x55, 0x42, 0x42,
-	0xa9, 0x05, 0x4d, 0xf6, 0x0a, 0x74, 0x06, 0x5b, 0x01, 0x9a, 0xee, 0x78, 0xac, 0xd3, 0x8d, 0x5b,
-	0x5c, 0x75, 0x4d, 0xab, 0x3f, 0xa2, 0x6c, 0xde, 0xb9, 0x80, 0x97, 0x4b, 0x91, 0x9a, 0xa4, 0x8c,
-	0xe9, 0x46, 0xe6, 0x51, 0x87, 0x11, 0xd5, 0x8c, 0xfa, 0xba, 0x30, 0xaa, 0x18, 0x71, 0x7d, 0x79,
-	0x37, 0xdf, 0x01, 0x00, 0x00, 0xff, 0xff, 0x0e, 0x52, 0x0b, 0x35, 0xa0, 0x02, 0x00, 0x00,
+	// 365 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x92, 0xd1, 0x4b, 0xe3, 0x40,
+	0x10, 0xc6, 0x49, 0xef, 0xda, 0x4b, 0xb7, 0x3d, 0x28, 0x39, 0x38, 0x43, 0x29, 0x58, 0xe2, 0x4b,
+	0x05, 0xd9, 0x80, 0x22, 0xf8, 0x5c, 0x10, 0x7d, 0x2c, 0x8b, 0xf8, 0x20, 0x82, 0x6c, 0xda, 0xe9,
+	0x26, 0x98, 0x64, 0x97, 0x9d, 0x8d, 0xd8, 0x3f, 0xd8, 0xff, 0x43, 0xb2, 0xc9, 0xa6, 0x51, 0x7c,
+	0x2a, 0xdf, 0x74, 0xf6, 0x37, 0xdf, 0x37, 0x19, 0xb2, 0x50, 0x00, 0x3a, 0x56, 0x5a, 0x2a, 0x89,
+	0x3c, 0x7f, 0xd1, 0x80, 0x4a, 0x96, 0x08, 0x54, 0x69, 0x69, 0x64, 0x30, 0xb2, 0x3f, 0x38, 0x3f,
+	0x15, 0x52, 0x8a, 0x1c, 0x62, 0x2b, 0x93, 0x6a, 0x1f, 0x9b, 0xac, 0x00, 0x34, 0xbc, 0x50, 0x4d,
+	0x63, 0xf4, 0xe1, 0x91, 0xd9, 0xa6, 0x85, 0xb0, 0x96, 0x11, 0x84, 0xe4, 0xcf, 0x1b, 0x68, 0xcc,
+	0x64, 0x19, 0x7a, 0x4b, 0x6f, 0x35, 0x64, 0x4e, 0x06, 0x37, 0x64, 0xdc, 0x11, 0xc2, 0xc1, 0xd2,
+	0x5b, 0x4d, 0x2e, 0xe7, 0xb4, 0x99, 0x41, 0xdd, 0x0c, 0xfa, 0xe0, 0x3a, 0xd8, 0xb1, 0x39, 0xb8,
+	0x20, 0xbe, 0xf3, 0x18, 0xfe, 0xb6, 0x0f, 0x67, 0xcd, 0x0b, 0xa4, 0x6e, 0x2e, 0xf3, 0x75, 0xcf,
+	0x81, 0xe2, 0x87, 0x5c, 0xf2, 0x5d, 0x38, 0x5c, 0x7a, 0xab, 0x29, 0x73, 0x32, 0xb8, 0x26, 0x13,
+	0x28, 0x77, 0x52, 0x23, 0x14, 0x50, 0x9a, 0x70, 0x64, 0x51, 0xff, 0x1c, 0xea, 0xf6, 0xf8, 0x17,
+	0xeb, 0xf7, 0x45, 0x8f, 0xc4, 0xef, 0xe2, 0xfd, 0x27, 0x23, 0x34, 0xdc, 0x54, 0xd8, 0xa6, 0x6b,
+	0x55, 0x3d, 0xb4, 0x00, 0x44, 0x2e, 0xc0, 0x46, 0x1b, 0x33, 0x27, 0xfb, 0x76, 0x7e, 0x7d, 0xb1,
+	0x13, 0x3d, 0x93, 0x93, 0xef, 0xeb, 0xdb, 0xb4, 0x4e, 0xcf, 0xc8, 0xdf, 0xee, 0xf3, 0xa4, 0x1c,
+	0x53, 0x3b, 0x6d, 0xca, 0xa6, 0xae, 0x78, 0xcf, 0x31, 0x0d, 0x16, 0x64, 0x0c, 0xef, 0x06, 0x4a,
+	0xbb, 0xec, 0x81, 0x6d, 0x38, 0x16, 0xa2, 0x3b, 0x32, 0xe9, 0x25, 0x0a, 0xe6, 0xc4, 0x6f, 0x33,
+	0xe9, 0x16, 0xd6, 0xe9, 0x1a, 0x84, 0x99, 0x28, 0xb9, 0xa9, 0x34, 0x38, 0x50, 0x57, 0x58, 0xa7,
+	0x24, 0x92, 0x5a, 0xd0, 0xf4, 0xa0, 0x40, 0xe7, 0xb0, 0x13, 0xa0, 0xe9, 0x9e, 0x27, 0x3a, 0xdb,
+	0xba, 0xc5, 0xd5, 0xd7, 0xb4, 0xfe, 0x21, 0xca, 0xf6, 0x95, 0x0b, 0x78, 0x3a, 0x17, 0x99, 0x49,
+	0xab, 0x84, 0x6e, 0x65, 0x11, 0xf7, 0x18, 0x71, 0xc3, 0x68, 0xae, 0x0b, 0xe3, 0x9a, 0x91, 0x34,
+	0x97, 0x77, 0xf5, 0x39, 0x00, 0x0e, 0x52, 0x0b, 0x35, 0xa0, 0x02, 0x00, 0x00,
 }
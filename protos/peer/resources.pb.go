@@ -144,7 +144,7 @@ func init() {
 func init() { proto.RegisterFile("peer/resources.proto", fileDescriptor10) }
 
 var fileDescriptor10 = []byte{
-	// 326 bytes of a gzipped FileDescriptorProto
+	// 323 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x91, 0xbf, 0x4f, 0xfb, 0x30,
 	0x10, 0xc5, 0xd5, 0xea, 0xab, 0x2f, 0xed, 0x51, 0x0a, 0x32, 0x05, 0x55, 0x9d, 0xaa, 0x4c, 0x85,
 	0x21, 0x91, 0xf8, 0x31, 0xb0, 0x01, 0x51, 0x07, 0x26, 0x50, 0x40, 0x1d, 0x58, 0x2a, 0xd7, 0xb9,
@@ -164,6 +164,6 @@ var fileDescriptor10 = []byte{
 	0xac, 0x0d, 0x7c, 0x37, 0xd8, 0x3d, 0x38, 0x78, 0xda, 0x72, 0xfe, 0xe3, 0xf9, 0x15, 0x02, 0x4d,
 	0x79, 0x58, 0x6c, 0x6a, 0xa4, 0x12, 0xd3, 0x1c, 0x29, 0xcc, 0xf8, 0x8a, 0xa4, 0xf0, 0x97, 0x31,
 	0x61, 0x73, 0xce, 0xcf, 0xab, 0x5c, 0xda, 0x62, 0xbd, 0x6a, 0xcc, 0xa2, 0x3d, 0x34, 0xf2, 0x68,
-	0xe4, 0xd1, 0xa8, 0x41, 0x57, 0xfe, 0xd2, 0xb7, 0xbf, 0x01, 0x00, 0x00, 0xff, 0xff, 0x5d, 0x4a,
-	0x54, 0x47, 0x08, 0x02, 0x00, 0x00,
+	0xe4, 0xd1, 0xa8, 0x41, 0x57, 0xfe, 0xd2, 0xb7, 0xbf, 0x03, 0x00, 0x5d, 0x4a, 0x54, 0x47, 0x08,
+	0x02, 0x00, 0x00,
 }
@@ -311,7 +311,7 @@ func init() {
 func init() { proto.RegisterFile("peer/transaction.proto", fileDescriptor12) }
 
 var fileDescriptor12 = []byte{
-	// 843 bytes of a gzipped FileDescriptorProto
+	// 840 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x54, 0x5d, 0x6f, 0x22, 0x37,
 	0x14, 0x2d, 0xd9, 0x4d, 0xd2, 0x5c, 0xf2, 0x61, 0x0c, 0x21, 0x04, 0x45, 0xdd, 0x15, 0x0f, 0xd5,
 	0xb6, 0x95, 0x40, 0xca, 0x3e, 0x54, 0xaa, 0xfa, 0x62, 0x66, 0x9c, 0x30, 0xea, 0x60, 0x8f, 0x3c,
@@ -363,6 +363,6 @@ var fileDescriptor12 = []byte{
 	0x04, 0x5a, 0x51, 0x3c, 0x6b, 0x3f, 0x3c, 0xad, 0x74, 0xbc, 0xd0, 0xd3, 0x99, 0x8e, 0xdb, 0x9f,
 	0x82, 0x71, 0x3c, 0x9f, 0x14, 0x6f, 0xd7, 0xac, 0xd9, 0x2e, 0xde, 0x59, 0x07, 0x5e, 0x30, 0xf9,
 	0x3d, 0x98, 0xe9, 0x5f, 0xbf, 0x9b, 0xcd, 0xd7, 0x0f, 0x8f, 0x63, 0xb3, 0xbd, 0x3a, 0x3b, 0xd7,
-	0x3b, 0xd9, 0xf5, 0x6c, 0x71, 0x27, 0x1d, 0x73, 0x7d, 0x9c, 0x2d, 0xf5, 0x8f, 0xff, 0x06, 0x00,
-	0x00, 0xff, 0xff, 0xa7, 0x7b, 0xe0, 0x48, 0xf5, 0x05, 0x00, 0x00,
+	0x3b, 0xd9, 0xf5, 0x6c, 0x71, 0x27, 0x1d, 0x73, 0x7d, 0x9c, 0x2d, 0xf5, 0x8f, 0xff, 0x0e, 0x00,
+	0xa7, 0x7b, 0xe0, 0x48, 0xf5, 0x05, 0x00, 0x00,
 }
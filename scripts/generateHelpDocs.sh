@@ -60,7 +60,7 @@ cat docs/wrappers/peer_logging_postscript.md >> $DOC
 DOC=docs/source/commands/peernode.md
 cat docs/wrappers/peer_node_preamble.md > $DOC
 
-for x in "peer node start" "peer node status"; do
+for x in "peer node reset" "peer node rollback" "peer node start" "peer node status"; do
   echo "" >> $DOC
   echo "##" $x >> $DOC
   echo "\`\`\`" >> $DOC
@@ -104,7 +104,7 @@ DOC=docs/source/commands/configtxlator.md
 
 cat docs/wrappers/configtxlator_preamble.md > $DOC
 
-for x in "configtxlator start" "configtxlator proto_encode" "configtxlator proto_decode" "configtxlator compute_update" "configtxlator version"; do
+for x in "configtxlator start" "configtxlator proto_encode" "configtxlator proto_decode" "configtxlator compute_update" "configtxlator patch_update" "configtxlator evaluate_update" "configtxlator merge_signatures" "configtxlator version"; do
   echo "" >> $DOC
   echo "##" $x >> $DOC
   echo "\`\`\`" >> $DOC